@@ -30,6 +30,8 @@ func CLI(c component.Factories) {
 	switch {
 	case componentType == "all":
 		createAllSchemaFiles(c, e)
+	case componentType == "jsonschema":
+		createJSONSchemaFile(c, e)
 	case componentType != "" && componentName != "":
 		createSingleSchemaFile(
 			c,
@@ -45,6 +47,7 @@ func CLI(c component.Factories) {
 func prepUsage() {
 	const usage = `cfgschema all
 cfgschema <componentType> <componentName>
+cfgschema jsonschema
 
 options
 `
@@ -58,6 +61,7 @@ func parseArgs() (env, string, string) {
 	e := env{}
 	flag.StringVar(&e.srcRoot, "s", defaultSrcRoot, "collector source root")
 	flag.StringVar(&e.moduleName, "m", defaultModule, "module name")
+	flag.StringVar(&e.jsonSchemaFilename, "o", jsonSchemaFilename, "JSON Schema file created by jsonschema, - for the standard output")
 	flag.Parse()
 	componentType := flag.Arg(0)
 	componentName := flag.Arg(1)
@@ -24,9 +24,10 @@ const defaultSrcRoot = "."
 const defaultModule = "go.opentelemetry.io/collector"
 
 type env struct {
-	srcRoot      string
-	moduleName   string
-	yamlFilename func(reflect.Type, env) string
+	srcRoot            string
+	moduleName         string
+	yamlFilename       func(reflect.Type, env) string
+	jsonSchemaFilename string
 }
 
 const schemaFilename = "cfg-schema.yaml"
@@ -0,0 +1,269 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schemagen
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configmodels"
+)
+
+const jsonSchemaFilename = "otelcol-schema.json"
+
+// jsonSchema is the subset of JSON Schema (draft-07) describing the collector configuration.
+type jsonSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Ref                  string                 `json:"$ref,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Type                 interface{}            `json:"type,omitempty"`
+	Default              interface{}            `json:"default,omitempty"`
+	Minimum              *int                   `json:"minimum,omitempty"`
+	Properties           map[string]*jsonSchema `json:"properties,omitempty"`
+	PatternProperties    map[string]*jsonSchema `json:"patternProperties,omitempty"`
+	AdditionalProperties interface{}            `json:"additionalProperties,omitempty"`
+	Items                *jsonSchema            `json:"items,omitempty"`
+	Definitions          map[string]interface{} `json:"definitions,omitempty"`
+}
+
+// createJSONSchemaFile creates a JSON Schema file describing the configuration of the
+// collector with the given components, to be used by the IDEs and the validation tools.
+func createJSONSchemaFile(components component.Factories, env env) {
+	marshaled, err := json.MarshalIndent(collectorJSONSchema(components, env), "", "  ")
+	if err != nil {
+		panic(err)
+	}
+	filename := env.jsonSchemaFilename
+	if filename == "" {
+		filename = jsonSchemaFilename
+	}
+	if filename == "-" {
+		_, err = os.Stdout.Write(append(marshaled, '\n'))
+	} else {
+		err = ioutil.WriteFile(filename, append(marshaled, '\n'), 0600)
+	}
+	if err != nil {
+		panic(err)
+	}
+}
+
+// collectorJSONSchema returns the JSON Schema of the collector configuration. The schemas of
+// the component configs are in the definitions, referenced by the component sections for the
+// keys made of the component type and an optional name, e.g. `otlp` or `otlp/2`.
+func collectorJSONSchema(components component.Factories, env env) *jsonSchema {
+	definitions := map[string]interface{}{}
+	section := func(kind string, cfgs map[configmodels.Type]configmodels.NamedEntity) *jsonSchema {
+		defs := map[string]*jsonSchema{}
+		patterns := map[string]*jsonSchema{}
+		for typ, cfg := range cfgs {
+			s := configJSONSchema(reflect.ValueOf(cfg), env, map[reflect.Type]bool{})
+			// A component without settings can be declared without value.
+			s.Type = []string{"object", "null"}
+			defs[string(typ)] = s
+			patterns["^"+regexp.QuoteMeta(string(typ))+"(/.+)?$"] = &jsonSchema{Ref: "#/definitions/" + kind + "/" + string(typ)}
+		}
+		definitions[kind] = defs
+		return &jsonSchema{
+			Type:                 "object",
+			PatternProperties:    patterns,
+			AdditionalProperties: false,
+		}
+	}
+
+	receivers := map[configmodels.Type]configmodels.NamedEntity{}
+	for typ, f := range components.Receivers {
+		receivers[typ] = f.CreateDefaultConfig()
+	}
+	processors := map[configmodels.Type]configmodels.NamedEntity{}
+	for typ, f := range components.Processors {
+		processors[typ] = f.CreateDefaultConfig()
+	}
+	exporters := map[configmodels.Type]configmodels.NamedEntity{}
+	for typ, f := range components.Exporters {
+		exporters[typ] = f.CreateDefaultConfig()
+	}
+	extensions := map[configmodels.Type]configmodels.NamedEntity{}
+	for typ, f := range components.Extensions {
+		extensions[typ] = f.CreateDefaultConfig()
+	}
+
+	names := &jsonSchema{Type: "array", Items: &jsonSchema{Type: "string"}}
+	return &jsonSchema{
+		Schema: "http://json-schema.org/draft-07/schema#",
+		Title:  "OpenTelemetry Collector configuration",
+		Type:   "object",
+		Properties: map[string]*jsonSchema{
+			"receivers":  section("receivers", receivers),
+			"processors": section("processors", processors),
+			"exporters":  section("exporters", exporters),
+			"extensions": section("extensions", extensions),
+			"include": {
+				Description: "Files merged before this file, relative to its directory.",
+				Type:        "array",
+				Items:       &jsonSchema{Type: "string"},
+			},
+			"service": {
+				Type: "object",
+				Properties: map[string]*jsonSchema{
+					"extensions": names,
+					"pipelines": {
+						Type: "object",
+						PatternProperties: map[string]*jsonSchema{
+							"^(traces|metrics|logs)(/.+)?$": {
+								Type: "object",
+								Properties: map[string]*jsonSchema{
+									"receivers":  names,
+									"processors": names,
+									"exporters":  names,
+								},
+								AdditionalProperties: false,
+							},
+						},
+						AdditionalProperties: false,
+					},
+				},
+				AdditionalProperties: false,
+			},
+		},
+		AdditionalProperties: false,
+		Definitions:          definitions,
+	}
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// configJSONSchema returns the schema of a config value, the defaults being the values of v.
+// The types being visited are tracked to stop on the recursive types.
+func configJSONSchema(v reflect.Value, env env, visiting map[reflect.Type]bool) *jsonSchema {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v = reflect.New(v.Type().Elem())
+		}
+		v = v.Elem()
+	}
+	s := &jsonSchema{}
+	switch {
+	case v.Type() == durationType:
+		s.Type = "string"
+		if v.Int() != 0 {
+			s.Default = time.Duration(v.Int()).String()
+		}
+	case v.Kind() == reflect.Struct:
+		s.Type = "object"
+		s.Properties = map[string]*jsonSchema{}
+		if visiting[v.Type()] {
+			return s
+		}
+		visiting[v.Type()] = true
+		addStructProperties(s, v, env, visiting)
+		delete(visiting, v.Type())
+	case v.Kind() == reflect.Map:
+		s.Type = "object"
+		s.AdditionalProperties = configJSONSchema(reflect.New(v.Type().Elem()).Elem(), env, visiting)
+	case v.Kind() == reflect.Slice || v.Kind() == reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			s.Type = "string"
+			break
+		}
+		s.Type = "array"
+		s.Items = configJSONSchema(reflect.New(v.Type().Elem()).Elem(), env, visiting)
+	case v.Kind() == reflect.String:
+		s.Type = "string"
+		if v.String() != "" {
+			s.Default = v.String()
+		}
+	case v.Kind() == reflect.Bool:
+		s.Type = "boolean"
+		if v.Bool() {
+			s.Default = true
+		}
+	case v.Kind() >= reflect.Int && v.Kind() <= reflect.Int64:
+		s.Type = "integer"
+		if v.Int() != 0 {
+			s.Default = v.Int()
+		}
+	case v.Kind() >= reflect.Uint && v.Kind() <= reflect.Uint64:
+		s.Type = "integer"
+		s.Minimum = new(int)
+		if v.Uint() != 0 {
+			s.Default = v.Uint()
+		}
+	case v.Kind() == reflect.Float32 || v.Kind() == reflect.Float64:
+		s.Type = "number"
+		if v.Float() != 0 {
+			s.Default = v.Float()
+		}
+	}
+	// The interfaces, e.g. the placeholders of the settings decoded by the components, accept
+	// any value.
+	return s
+}
+
+// addStructProperties adds the fields of the struct v to the properties of s, the squashed
+// fields being added as if they were fields of v.
+func addStructProperties(s *jsonSchema, v reflect.Value, env env, visiting map[reflect.Type]bool) {
+	comments := moduleCommentsForStruct(v, env)
+	for i := 0; i < v.NumField(); i++ {
+		structField := v.Type().Field(i)
+		if structField.PkgPath != "" && !structField.Anonymous {
+			// Unexported field.
+			continue
+		}
+		tagName, options, _ := mapstructure(structField.Tag)
+		if tagName == "-" {
+			continue
+		}
+		fv := v.Field(i)
+		if containsSquash(options) {
+			if fv.Kind() == reflect.Ptr {
+				if fv.IsNil() {
+					fv = reflect.New(fv.Type().Elem())
+				}
+				fv = fv.Elem()
+			}
+			if fv.Kind() == reflect.Struct {
+				addStructProperties(s, fv, env, visiting)
+			}
+			continue
+		}
+		name := tagName
+		if name == "" {
+			name = strings.ToLower(structField.Name)
+		}
+		p := configJSONSchema(fv, env, visiting)
+		p.Description = strings.TrimSpace(comments[structField.Name])
+		s.Properties[name] = p
+	}
+}
+
+// moduleCommentsForStruct returns the comments of the fields of the struct v when its package is
+// part of the module, the sources of the other packages are not available.
+func moduleCommentsForStruct(v reflect.Value, env env) map[string]string {
+	pkg := v.Type().PkgPath()
+	if pkg != env.moduleName && !strings.HasPrefix(pkg, env.moduleName+"/") {
+		return nil
+	}
+	if _, err := os.Stat(packageDir(v.Type(), env)); err != nil {
+		return nil
+	}
+	return commentsForStruct(v, env)
+}
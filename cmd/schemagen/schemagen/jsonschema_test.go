@@ -0,0 +1,94 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schemagen
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testRecursive struct {
+	Name     string            `mapstructure:"name"`
+	Children []*testRecursive  `mapstructure:"children"`
+	Labels   map[string]string `mapstructure:"labels"`
+	Ratio    float64           `mapstructure:"ratio"`
+	Any      interface{}       `mapstructure:"any"`
+}
+
+func TestConfigJSONSchema(t *testing.T) {
+	s := configJSONSchema(reflect.ValueOf(&testStruct{
+		One:      "1",
+		Two:      2,
+		Four:     true,
+		Duration: 42,
+	}), testEnv(), map[reflect.Type]bool{})
+
+	assert.Equal(t, "object", s.Type)
+	assert.Len(t, s.Properties, 10)
+	assert.Equal(t, &jsonSchema{Type: "string", Default: "1"}, s.Properties["one"])
+	assert.Equal(t, &jsonSchema{Type: "integer", Default: int64(2)}, s.Properties["two"])
+	assert.Equal(t, &jsonSchema{Type: "integer", Minimum: new(int)}, s.Properties["three"])
+	assert.Equal(t, &jsonSchema{Type: "boolean", Default: true}, s.Properties["four"])
+	assert.Equal(t, &jsonSchema{Type: "string", Default: "42ns", Description: "embedded, package qualified"}, s.Properties["duration"])
+	// The fields of the squashed structs are properties of the struct.
+	assert.Equal(t, &jsonSchema{Type: "string"}, s.Properties["name"])
+	assert.NotContains(t, s.Properties, "ignored")
+
+	persons := s.Properties["person_ptrs"]
+	assert.Equal(t, "array", persons.Type)
+	assert.Equal(t, &jsonSchema{Type: "object", Properties: map[string]*jsonSchema{"name": {Type: "string"}}}, persons.Items)
+}
+
+func TestConfigJSONSchemaRecursive(t *testing.T) {
+	s := configJSONSchema(reflect.ValueOf(testRecursive{}), testEnv(), map[reflect.Type]bool{})
+
+	assert.Len(t, s.Properties, 5)
+	assert.Equal(t, &jsonSchema{Type: "object", Properties: map[string]*jsonSchema{}}, s.Properties["children"].Items)
+	assert.Equal(t, &jsonSchema{Type: "object", AdditionalProperties: &jsonSchema{Type: "string"}}, s.Properties["labels"])
+	assert.Equal(t, &jsonSchema{Type: "number"}, s.Properties["ratio"])
+	assert.Equal(t, &jsonSchema{}, s.Properties["any"])
+}
+
+func TestCreateJSONSchemaFile(t *testing.T) {
+	e := testEnv()
+	e.jsonSchemaFilename = path.Join(t.TempDir(), jsonSchemaFilename)
+	createJSONSchemaFile(testComponents(), e)
+
+	content, err := ioutil.ReadFile(e.jsonSchemaFilename)
+	require.NoError(t, err)
+	var schema map[string]interface{}
+	require.NoError(t, json.Unmarshal(content, &schema))
+
+	exporters := schema["properties"].(map[string]interface{})["exporters"].(map[string]interface{})
+	assert.Equal(t,
+		map[string]interface{}{"$ref": "#/definitions/exporters/otlp"},
+		exporters["patternProperties"].(map[string]interface{})["^otlp(/.+)?$"])
+
+	otlp := schema["definitions"].(map[string]interface{})["exporters"].(map[string]interface{})["otlp"].(map[string]interface{})
+	assert.Equal(t, []interface{}{"object", "null"}, otlp["type"])
+	endpoint := otlp["properties"].(map[string]interface{})["endpoint"].(map[string]interface{})
+	assert.Equal(t, "string", endpoint["type"])
+	assert.NotEmpty(t, endpoint["description"])
+
+	for _, kind := range []string{"receivers", "processors", "exporters", "extensions"} {
+		assert.NotEmpty(t, schema["definitions"].(map[string]interface{})[kind], kind)
+	}
+}
@@ -47,6 +47,23 @@ type Component interface {
 	Shutdown(ctx context.Context) error
 }
 
+// DrainStats are the numbers of items, i.e. the spans, the metric data points or the log records,
+// drained by a component at shutdown.
+type DrainStats struct {
+	// Flushed is the number of items sent to the next component or to the destination.
+	Flushed int64
+	// Dropped is the number of items which failed to be sent or were abandoned when the
+	// context given to Shutdown was done.
+	Dropped int64
+}
+
+// Drainer is an optional interface implemented by the components holding data, e.g. in
+// batches or queues, reporting how the data was drained by Shutdown.
+type Drainer interface {
+	// DrainStats returns the items drained since Shutdown was invoked.
+	DrainStats() DrainStats
+}
+
 // Kind specified one of the 4 components kinds, see consts below.
 type Kind int
 
@@ -75,6 +92,15 @@ type ConfigUnmarshaler interface {
 	Unmarshal(componentViperSection *viper.Viper, intoCfg interface{}) error
 }
 
+// DataTypeSupporter interface is an optional interface that if implemented by a Factory of
+// receivers, processors or exporters, reports the data types of the pipelines its components
+// can be used in without creating them, e.g. to validate the configuration.
+type DataTypeSupporter interface {
+	// SupportsDataType returns true if the components created by the factory can be used
+	// in the pipelines of the data type.
+	SupportsDataType(dataType configmodels.DataType) bool
+}
+
 // CustomUnmarshaler is a function that un-marshals a viper data into a config struct
 // in a custom way.
 // componentViperSection *viper.Viper
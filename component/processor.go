@@ -68,6 +68,11 @@ type ProcessorCreateParams struct {
 
 	// ApplicationStartInfo can be used by components for informational purposes
 	ApplicationStartInfo ApplicationStartInfo
+
+	// Backpressure is true when the pipeline of the processor propagates backpressure, the
+	// processor must then return the errors wrapped with consumererror.Backpressure by the next
+	// consumer to its callers instead of dropping the data.
+	Backpressure bool
 }
 
 // ProcessorFactory is factory interface for processors. This is the
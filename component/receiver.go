@@ -61,6 +61,11 @@ type ReceiverCreateParams struct {
 
 	// ApplicationStartInfo can be used by components for informational purposes
 	ApplicationStartInfo ApplicationStartInfo
+
+	// Backpressure is true when all the pipelines the receiver is attached to propagate
+	// backpressure, the receiver can then refuse the data with a retryable status when the next
+	// consumer returns an error wrapped with consumererror.Backpressure.
+	Backpressure bool
 }
 
 // ReceiverFactory can create TracesReceiver and MetricsReceiver. This is the
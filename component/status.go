@@ -0,0 +1,66 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package component
+
+// Status is the health status of a component.
+type Status int
+
+const (
+	// StatusStarting means that the component is being started.
+	StatusStarting Status = iota
+	// StatusHealthy means that the component was started and works as expected.
+	StatusHealthy
+	// StatusDegraded means that the component works but not as expected, e.g. it falls behind.
+	StatusDegraded
+	// StatusFailed means that the component failed, e.g. its start returned an error.
+	StatusFailed
+)
+
+func (s Status) String() string {
+	switch s {
+	case StatusStarting:
+		return "starting"
+	case StatusHealthy:
+		return "healthy"
+	case StatusDegraded:
+		return "degraded"
+	case StatusFailed:
+		return "failed"
+	}
+	return "unknown"
+}
+
+// StatusEvent is a change of the status of a component.
+type StatusEvent struct {
+	Kind Kind
+	// Name is the full name of the component in the configuration, e.g. "otlp/2".
+	Name string
+	// Pipeline is the name of the pipeline of a processor, each pipeline having its own
+	// instance of the processor. It is empty for the other kinds of components.
+	Pipeline string
+	Status   Status
+	// Err is the error that made the component fail, if any.
+	Err error
+}
+
+// StatusWatcher is an extra interface for Extension hosted by the OpenTelemetry
+// Service that is to be implemented by extensions interested in the status of the
+// components of the service.
+type StatusWatcher interface {
+	// ComponentStatusChanged notifies the Extension that the status of a component
+	// changed. It can be called before the Extension is started, for the components
+	// started before it.
+	ComponentStatusChanged(event StatusEvent)
+}
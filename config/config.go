@@ -42,6 +42,7 @@ const (
 	errUnknownType
 	errDuplicateName
 	errUnmarshalTopLevelStructureError
+	errExpandEnv
 )
 
 const (
@@ -74,6 +75,9 @@ const (
 
 	// pipelinesKeyName is the configuration key name for pipelines section.
 	pipelinesKeyName = "pipelines"
+
+	// serviceKeyName is the configuration key name for service section.
+	serviceKeyName = "service"
 )
 
 type configSettings struct {
@@ -90,9 +94,10 @@ type serviceSettings struct {
 }
 
 type pipelineSettings struct {
-	Receivers  []string `mapstructure:"receivers"`
-	Processors []string `mapstructure:"processors"`
-	Exporters  []string `mapstructure:"exporters"`
+	Receivers    []string `mapstructure:"receivers"`
+	Processors   []string `mapstructure:"processors"`
+	Exporters    []string `mapstructure:"exporters"`
+	Backpressure bool     `mapstructure:"backpressure"`
 }
 
 // typeAndNameSeparator is the separator that is used between type and name in type/name composite keys.
@@ -157,6 +162,9 @@ func Load(
 	config.Processors = processors
 
 	// Load the service and its data pipelines.
+	if err := expandEnvService(&rawCfg.Service); err != nil {
+		return nil, err
+	}
 	service, err := loadService(rawCfg.Service)
 	if err != nil {
 		return nil, err
@@ -226,6 +234,13 @@ func errorUnmarshalError(component string, fullName string, err error) error {
 	}
 }
 
+func errorExpandEnv(path string, err error) error {
+	return &configError{
+		code: errExpandEnv,
+		msg:  fmt.Sprintf("error expanding environment variables in %s: %v", path, err),
+	}
+}
+
 func errorDuplicateName(component string, fullName string) error {
 	return &configError{
 		code: errDuplicateName,
@@ -240,7 +255,9 @@ func loadExtensions(exts map[string]interface{}, factories map[configmodels.Type
 	// Iterate over extensions and create a config for each.
 	for key, value := range exts {
 		componentConfig := viperFromStringMap(cast.ToStringMap(value))
-		expandEnvConfig(componentConfig)
+		if err := expandEnvConfig(componentConfig, componentPath(extensionsKeyName, key)); err != nil {
+			return nil, err
+		}
 
 		// Decode the key into type and fullName components.
 		typeStr, fullName, err := DecodeTypeAndName(key)
@@ -257,7 +274,9 @@ func loadExtensions(exts map[string]interface{}, factories map[configmodels.Type
 		// Create the default config for this extension
 		extensionCfg := factory.CreateDefaultConfig()
 		extensionCfg.SetName(fullName)
-		expandEnvLoadedConfig(extensionCfg)
+		if err := expandEnvLoadedConfig(extensionCfg); err != nil {
+			return nil, errorExpandEnv(componentPath(extensionsKeyName, fullName), err)
+		}
 
 		// Now that the default config struct is created we can Unmarshal into it
 		// and it will apply user-defined config on top of the default.
@@ -293,7 +312,9 @@ func LoadReceiver(componentConfig *viper.Viper, typeStr configmodels.Type, fullN
 	// Create the default config for this receiver.
 	receiverCfg := factory.CreateDefaultConfig()
 	receiverCfg.SetName(fullName)
-	expandEnvLoadedConfig(receiverCfg)
+	if err := expandEnvLoadedConfig(receiverCfg); err != nil {
+		return nil, errorExpandEnv(componentPath(receiversKeyName, fullName), err)
+	}
 
 	// Now that the default config struct is created we can Unmarshal into it
 	// and it will apply user-defined config on top of the default.
@@ -312,7 +333,9 @@ func loadReceivers(recvs map[string]interface{}, factories map[configmodels.Type
 	// Iterate over input map and create a config for each.
 	for key, value := range recvs {
 		componentConfig := viperFromStringMap(cast.ToStringMap(value))
-		expandEnvConfig(componentConfig)
+		if err := expandEnvConfig(componentConfig, componentPath(receiversKeyName, key)); err != nil {
+			return nil, err
+		}
 
 		// Decode the key into type and fullName components.
 		typeStr, fullName, err := DecodeTypeAndName(key)
@@ -349,7 +372,9 @@ func loadExporters(exps map[string]interface{}, factories map[configmodels.Type]
 	// Iterate over Exporters and create a config for each.
 	for key, value := range exps {
 		componentConfig := viperFromStringMap(cast.ToStringMap(value))
-		expandEnvConfig(componentConfig)
+		if err := expandEnvConfig(componentConfig, componentPath(exportersKeyName, key)); err != nil {
+			return nil, err
+		}
 
 		// Decode the key into type and fullName components.
 		typeStr, fullName, err := DecodeTypeAndName(key)
@@ -366,7 +391,9 @@ func loadExporters(exps map[string]interface{}, factories map[configmodels.Type]
 		// Create the default config for this exporter
 		exporterCfg := factory.CreateDefaultConfig()
 		exporterCfg.SetName(fullName)
-		expandEnvLoadedConfig(exporterCfg)
+		if err := expandEnvLoadedConfig(exporterCfg); err != nil {
+			return nil, errorExpandEnv(componentPath(exportersKeyName, fullName), err)
+		}
 
 		// Now that the default config struct is created we can Unmarshal into it
 		// and it will apply user-defined config on top of the default.
@@ -392,7 +419,9 @@ func loadProcessors(procs map[string]interface{}, factories map[configmodels.Typ
 	// Iterate over processors and create a config for each.
 	for key, value := range procs {
 		componentConfig := viperFromStringMap(cast.ToStringMap(value))
-		expandEnvConfig(componentConfig)
+		if err := expandEnvConfig(componentConfig, componentPath(processorsKeyName, key)); err != nil {
+			return nil, err
+		}
 
 		// Decode the key into type and fullName components.
 		typeStr, fullName, err := DecodeTypeAndName(key)
@@ -409,7 +438,9 @@ func loadProcessors(procs map[string]interface{}, factories map[configmodels.Typ
 		// Create the default config for this processor.
 		processorCfg := factory.CreateDefaultConfig()
 		processorCfg.SetName(fullName)
-		expandEnvLoadedConfig(processorCfg)
+		if err := expandEnvLoadedConfig(processorCfg); err != nil {
+			return nil, errorExpandEnv(componentPath(processorsKeyName, fullName), err)
+		}
 
 		// Now that the default config struct is created we can Unmarshal into it
 		// and it will apply user-defined config on top of the default.
@@ -457,6 +488,7 @@ func loadPipelines(pipelinesConfig map[string]pipelineSettings) (configmodels.Pi
 		pipelineCfg.Receivers = rawPipeline.Receivers
 		pipelineCfg.Processors = rawPipeline.Processors
 		pipelineCfg.Exporters = rawPipeline.Exporters
+		pipelineCfg.Backpressure = rawPipeline.Backpressure
 
 		if pipelines[fullName] != nil {
 			return nil, errorDuplicateName(pipelinesKeyName, fullName)
@@ -468,76 +500,145 @@ func loadPipelines(pipelinesConfig map[string]pipelineSettings) (configmodels.Pi
 	return pipelines, nil
 }
 
+// componentPath returns the path of a component in the configuration, e.g. "receivers::otlp".
+func componentPath(section, key string) string {
+	return section + ViperDelimiter + key
+}
+
 // expandEnvConfig creates a new viper config with expanded values for all the values (simple, list or map value).
-// It does not expand the keys.
-func expandEnvConfig(v *viper.Viper) {
+// It does not expand the keys. The returned error reports the path of the value under the given path.
+func expandEnvConfig(v *viper.Viper, path string) error {
 	for _, k := range v.AllKeys() {
-		v.Set(k, expandStringValues(v.Get(k)))
+		value, err := expandStringValues(v.Get(k), path+ViperDelimiter+k)
+		if err != nil {
+			return err
+		}
+		v.Set(k, value)
 	}
+	return nil
 }
 
-func expandStringValues(value interface{}) interface{} {
+func expandStringValues(value interface{}, path string) (interface{}, error) {
 	switch v := value.(type) {
 	default:
-		return v
+		return v, nil
 	case string:
-		return expandEnv(v)
+		expanded, err := expandEnv(v)
+		if err != nil {
+			return nil, errorExpandEnv(path, err)
+		}
+		return expanded, nil
 	case []interface{}:
 		nslice := make([]interface{}, 0, len(v))
-		for _, vint := range v {
-			nslice = append(nslice, expandStringValues(vint))
+		for i, vint := range v {
+			nvalue, err := expandStringValues(vint, fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return nil, err
+			}
+			nslice = append(nslice, nvalue)
 		}
-		return nslice
+		return nslice, nil
 	case map[interface{}]interface{}:
 		nmap := make(map[interface{}]interface{}, len(v))
 		for k, vint := range v {
-			nmap[k] = expandStringValues(vint)
+			nvalue, err := expandStringValues(vint, fmt.Sprintf("%s%s%v", path, ViperDelimiter, k))
+			if err != nil {
+				return nil, err
+			}
+			nmap[k] = nvalue
 		}
-		return nmap
+		return nmap, nil
 	}
 }
 
+// expandEnvService expands the environment variables in the extensions and the
+// components of the pipelines of the service.
+func expandEnvService(service *serviceSettings) error {
+	if err := expandEnvStrings(service.Extensions, serviceKeyName+ViperDelimiter+extensionsKeyName); err != nil {
+		return err
+	}
+	for name, pipeline := range service.Pipelines {
+		path := serviceKeyName + ViperDelimiter + pipelinesKeyName + ViperDelimiter + name + ViperDelimiter
+		if err := expandEnvStrings(pipeline.Receivers, path+receiversKeyName); err != nil {
+			return err
+		}
+		if err := expandEnvStrings(pipeline.Processors, path+processorsKeyName); err != nil {
+			return err
+		}
+		if err := expandEnvStrings(pipeline.Exporters, path+exportersKeyName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func expandEnvStrings(values []string, path string) error {
+	for i, value := range values {
+		expanded, err := expandEnv(value)
+		if err != nil {
+			return errorExpandEnv(fmt.Sprintf("%s[%d]", path, i), err)
+		}
+		values[i] = expanded
+	}
+	return nil
+}
+
 // expandEnvLoadedConfig is a utility function that goes recursively through a config object
 // and tries to expand environment variables in its string fields.
-func expandEnvLoadedConfig(s interface{}) {
-	expandEnvLoadedConfigPointer(s)
+func expandEnvLoadedConfig(s interface{}) error {
+	return expandEnvLoadedConfigPointer(s)
 }
 
-func expandEnvLoadedConfigPointer(s interface{}) {
+func expandEnvLoadedConfigPointer(s interface{}) error {
 	// Check that the value given is indeed a pointer, otherwise safely stop the search here
 	value := reflect.ValueOf(s)
 	if value.Kind() != reflect.Ptr {
-		return
+		return nil
 	}
 	// Run expandLoadedConfigValue on the value behind the pointer
-	expandEnvLoadedConfigValue(value.Elem())
+	return expandEnvLoadedConfigValue(value.Elem())
 }
 
-func expandEnvLoadedConfigValue(value reflect.Value) {
+func expandEnvLoadedConfigValue(value reflect.Value) error {
 	// The value given is a string, we expand it (if allowed)
 	if value.Kind() == reflect.String && value.CanSet() {
-		value.SetString(expandEnv(value.String()))
+		expanded, err := expandEnv(value.String())
+		if err != nil {
+			return err
+		}
+		value.SetString(expanded)
 	}
 	// The value given is a struct, we go through its fields
 	if value.Kind() == reflect.Struct {
 		for i := 0; i < value.NumField(); i++ {
 			field := value.Field(i) // Returns the content of the field
 			if field.CanSet() {     // Only try to modify a field if it can be modified (eg. skip unexported private fields)
+				var err error
 				switch field.Kind() {
 				case reflect.String: // The current field is a string, we want to expand it
-					field.SetString(expandEnv(field.String())) // Expand env variables in the string
+					err = expandEnvLoadedConfigValue(field) // Expand env variables in the string
 				case reflect.Ptr: // The current field is a pointer
-					expandEnvLoadedConfigPointer(field.Interface()) // Run the expansion function on the pointer
+					err = expandEnvLoadedConfigPointer(field.Interface()) // Run the expansion function on the pointer
 				case reflect.Struct: // The current field is a nested struct
-					expandEnvLoadedConfigValue(field) // Go through the nested struct
+					err = expandEnvLoadedConfigValue(field) // Go through the nested struct
+				}
+				if err != nil {
+					return err
 				}
 			}
 		}
 	}
+	return nil
 }
 
-func expandEnv(s string) string {
-	return os.Expand(s, func(str string) string {
+// expandEnv substitutes the environment variables in s:
+//   - $FOO and ${FOO} are substituted with env var FOO, or the empty string if not set.
+//   - ${FOO:default} is substituted with env var FOO, or "default" if FOO is not set or empty.
+//   - ${FOO:?message} is substituted with env var FOO, an error with the message is
+//     returned if FOO is not set or empty.
+func expandEnv(s string) (string, error) {
+	var err error
+	expanded := os.Expand(s, func(str string) string {
 		// This allows escaping environment variable substitution via $$, e.g.
 		// - $FOO will be substituted with env var FOO
 		// - $$FOO will be replaced with $FOO
@@ -545,8 +646,30 @@ func expandEnv(s string) string {
 		if str == "$" {
 			return "$"
 		}
-		return os.Getenv(str)
+		idx := strings.IndexByte(str, ':')
+		if idx < 0 {
+			return os.Getenv(str)
+		}
+		name, modifier := str[:idx], str[idx+1:]
+		if value := os.Getenv(name); value != "" {
+			return value
+		}
+		if !strings.HasPrefix(modifier, "?") {
+			return modifier
+		}
+		if err == nil {
+			if msg := modifier[1:]; msg != "" {
+				err = fmt.Errorf("environment variable %q is required: %s", name, msg)
+			} else {
+				err = fmt.Errorf("environment variable %q is required", name)
+			}
+		}
+		return ""
 	})
+	if err != nil {
+		return "", err
+	}
+	return expanded, nil
 }
 
 func unmarshaler(factory component.Factory) component.CustomUnmarshaler {
@@ -120,11 +120,12 @@ func TestDecodeConfig(t *testing.T) {
 
 	assert.Equal(t,
 		&configmodels.Pipeline{
-			Name:       "traces",
-			InputType:  configmodels.TracesDataType,
-			Receivers:  []string{"examplereceiver"},
-			Processors: []string{"exampleprocessor"},
-			Exporters:  []string{"exampleexporter"},
+			Name:         "traces",
+			InputType:    configmodels.TracesDataType,
+			Receivers:    []string{"examplereceiver"},
+			Processors:   []string{"exampleprocessor"},
+			Exporters:    []string{"exampleexporter"},
+			Backpressure: true,
 		},
 		config.Service.Pipelines["traces"],
 		"Did not load pipeline config correctly")
@@ -452,6 +453,9 @@ func TestDecodeConfig_Invalid(t *testing.T) {
 		{name: "invalid-processor-sub-config", expected: errUnmarshalTopLevelStructureError},
 		{name: "invalid-receiver-sub-config", expected: errUnmarshalTopLevelStructureError},
 		{name: "invalid-pipeline-sub-config", expected: errUnmarshalTopLevelStructureError},
+
+		{name: "missing-required-env-receiver", expected: errExpandEnv, expectedMessage: "receivers::examplereceiver::extra_list[1]: environment variable \"RECEIVERS_EXAMPLERECEIVER_MISSING_REQUIRED\" is required: the receiver list value is required"},
+		{name: "missing-required-env-pipeline", expected: errExpandEnv, expectedMessage: "service::pipelines::traces::exporters[0]"},
 	}
 
 	factories, err := testcomponents.ExampleComponents()
@@ -493,6 +497,85 @@ func TestLoadEmptyAllSections(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestDefaultEnvVars(t *testing.T) {
+	assert.NoError(t, os.Setenv("RECEIVERS_EXAMPLERECEIVER_EXTRA", "some receiver string"))
+	defer func() {
+		assert.NoError(t, os.Unsetenv("RECEIVERS_EXAMPLERECEIVER_EXTRA"))
+	}()
+
+	factories, err := testcomponents.ExampleComponents()
+	assert.NoError(t, err)
+
+	config, err := loadConfigFile(t, path.Join(".", "testdata", "simple-config-with-default-env.yaml"), factories)
+	require.NoError(t, err, "Unable to load config")
+
+	assert.Equal(t,
+		&testcomponents.ExampleReceiver{
+			ReceiverSettings: configmodels.ReceiverSettings{
+				TypeVal: "examplereceiver",
+				NameVal: "examplereceiver",
+			},
+			TCPAddr: confignet.TCPAddr{
+				Endpoint: "localhost:1234",
+			},
+			ExtraSetting:     "some receiver string",
+			ExtraMapSetting:  map[string]string{"recv.1": "some receiver map value_1"},
+			ExtraListSetting: []string{"", "${RECEIVERS_EXAMPLERECEIVER_EXTRA_LIST_VALUE_2:escaped}"},
+		},
+		config.Receivers["examplereceiver"],
+		"Did not load receiver config correctly")
+
+	assert.Equal(t, []string{"exampleextension"}, config.Service.Extensions)
+	assert.Equal(t, []string{"exampleexporter"}, config.Service.Pipelines["traces"].Exporters)
+}
+
+func TestExpandEnv(t *testing.T) {
+	assert.NoError(t, os.Setenv("VALUE", "replaced_value"))
+	assert.NoError(t, os.Setenv("EMPTY_VALUE", ""))
+	defer func() {
+		assert.NoError(t, os.Unsetenv("VALUE"))
+		assert.NoError(t, os.Unsetenv("EMPTY_VALUE"))
+	}()
+
+	tests := []struct {
+		input    string
+		expected string
+		errMsg   string
+	}{
+		{input: "$VALUE", expected: "replaced_value"},
+		{input: "${VALUE}:${MISSING_VALUE}", expected: "replaced_value:"},
+		{input: "${VALUE:default}", expected: "replaced_value"},
+		{input: "${MISSING_VALUE:default}", expected: "default"},
+		{input: "${EMPTY_VALUE:default}", expected: "default"},
+		{input: "${MISSING_VALUE:localhost:4317}", expected: "localhost:4317"},
+		{input: "${MISSING_VALUE:}", expected: ""},
+		{input: "$${MISSING_VALUE:?}", expected: "${MISSING_VALUE:?}"},
+		{input: "${VALUE:?the value is required}", expected: "replaced_value"},
+		{input: "${MISSING_VALUE:?the value is required}", errMsg: `environment variable "MISSING_VALUE" is required: the value is required`},
+		{input: "${EMPTY_VALUE:?}", errMsg: `environment variable "EMPTY_VALUE" is required`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			actual, err := expandEnv(tt.input)
+			if tt.errMsg != "" {
+				assert.EqualError(t, err, tt.errMsg)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestExpandEnvLoadedConfigMissingRequiredEnv(t *testing.T) {
+	config := &testConfig{
+		NestedConfigValue: nestedConfig{
+			NestedStringValue: "${NESTED_VALUE:?}",
+		},
+	}
+	assert.EqualError(t, expandEnvLoadedConfig(config), `environment variable "NESTED_VALUE" is required`)
+}
+
 func loadConfigFile(t *testing.T, fileName string, factories component.Factories) (*configmodels.Config, error) {
 	// Read yaml config from file
 	v := NewViper()
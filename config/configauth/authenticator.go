@@ -21,10 +21,13 @@ import (
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
+
+	"go.opentelemetry.io/collector/consumer/consumererror"
 )
 
 var (
-	errNoOIDCProvided   = errors.New("no OIDC information provided")
+	errNoOIDCProvided   = errors.New("no OIDC, basic or client certificate authentication provided")
+	errOIDCAndBasic     = errors.New("only one of OIDC and basic authentication can be provided")
 	errMetadataNotFound = errors.New("no request metadata found")
 	defaultAttribute    = "authorization"
 )
@@ -50,17 +53,98 @@ type authenticateFunc func(context.Context, map[string][]string) (context.Contex
 type unaryInterceptorFunc func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler, authenticate authenticateFunc) (interface{}, error)
 type streamInterceptorFunc func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler, authenticate authenticateFunc) error
 
-// NewAuthenticator creates an authenticator based on the given configuration
+// NewAuthenticator creates an authenticator based on the given configuration, the client
+// certificate being checked before the OIDC token or the basic credentials when both are given.
 func NewAuthenticator(cfg Authentication) (Authenticator, error) {
-	if cfg.OIDC == nil {
+	if cfg.OIDC == nil && cfg.Basic == nil && cfg.ClientCertificate == nil {
 		return nil, errNoOIDCProvided
 	}
+	if cfg.OIDC != nil && cfg.Basic != nil {
+		return nil, errOIDCAndBasic
+	}
 
 	if len(cfg.Attribute) == 0 {
 		cfg.Attribute = defaultAttribute
 	}
 
-	return newOIDCAuthenticator(cfg)
+	var auths []Authenticator
+	if cfg.ClientCertificate != nil {
+		auth, err := newClientCertificateAuthenticator(cfg)
+		if err != nil {
+			return nil, err
+		}
+		auths = append(auths, auth)
+	}
+	if cfg.OIDC != nil {
+		auth, err := newOIDCAuthenticator(cfg)
+		if err != nil {
+			return nil, err
+		}
+		auths = append(auths, auth)
+	}
+	if cfg.Basic != nil {
+		auth, err := newBasicAuthenticator(cfg)
+		if err != nil {
+			return nil, err
+		}
+		auths = append(auths, auth)
+	}
+
+	if len(auths) == 1 {
+		return auths[0], nil
+	}
+	return &chainAuthenticator{
+		authenticators:    auths,
+		unaryInterceptor:  defaultUnaryInterceptor,
+		streamInterceptor: defaultStreamInterceptor,
+	}, nil
+}
+
+// chainAuthenticator authenticates the requests authenticated by all its authenticators.
+type chainAuthenticator struct {
+	authenticators []Authenticator
+
+	unaryInterceptor  unaryInterceptorFunc
+	streamInterceptor streamInterceptorFunc
+}
+
+var _ Authenticator = (*chainAuthenticator)(nil)
+
+func (c *chainAuthenticator) Authenticate(ctx context.Context, headers map[string][]string) (context.Context, error) {
+	var err error
+	for _, auth := range c.authenticators {
+		if ctx, err = auth.Authenticate(ctx, headers); err != nil {
+			return ctx, err
+		}
+	}
+	return ctx, nil
+}
+
+func (c *chainAuthenticator) Start(ctx context.Context) error {
+	for _, auth := range c.authenticators {
+		if err := auth.Start(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *chainAuthenticator) Close() error {
+	var errs []error
+	for _, auth := range c.authenticators {
+		if err := auth.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return consumererror.CombineErrors(errs)
+}
+
+func (c *chainAuthenticator) UnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	return c.unaryInterceptor(ctx, req, info, handler, c.Authenticate)
+}
+
+func (c *chainAuthenticator) StreamInterceptor(srv interface{}, str grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return c.streamInterceptor(srv, str, info, handler, c.Authenticate)
 }
 
 func defaultUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler, authenticate authenticateFunc) (interface{}, error) {
@@ -16,6 +16,7 @@ package configauth
 
 import (
 	"context"
+	"crypto/x509"
 	"fmt"
 	"testing"
 
@@ -38,6 +39,43 @@ func TestNewAuthenticator(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestNewChainAuthenticator(t *testing.T) {
+	// prepare
+	p, err := NewAuthenticator(Authentication{
+		Basic:             &BasicAuth{Users: map[string]string{"collector": "secret"}},
+		ClientCertificate: &ClientCertificateAuth{AllowedSANs: []string{"agent.example.com"}},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, p.Start(context.Background()))
+	headers := map[string][]string{"authorization": basicHeader("collector", "secret")}
+	ctx := context.WithValue(context.Background(), tlsStateKey, verifiedState(&x509.Certificate{DNSNames: []string{"agent.example.com"}}))
+
+	// test
+	authenticated, err := p.Authenticate(ctx, headers)
+	_, noCertErr := p.Authenticate(context.Background(), headers)
+	_, noBasicErr := p.Authenticate(ctx, nil)
+
+	// verify
+	assert.NoError(t, err)
+	subject, _ := SubjectFromContext(authenticated)
+	assert.Equal(t, "collector", subject)
+	assert.Equal(t, errNoClientCertificate, noCertErr)
+	assert.Equal(t, errNotAuthenticated, noBasicErr)
+	assert.NoError(t, p.Close())
+}
+
+func TestOIDCAndBasic(t *testing.T) {
+	// test
+	p, err := NewAuthenticator(Authentication{
+		OIDC:  &OIDC{Audience: "some-audience", IssuerURL: "http://example.com"},
+		Basic: &BasicAuth{Users: map[string]string{"collector": "secret"}},
+	})
+
+	// verify
+	assert.Nil(t, p)
+	assert.Equal(t, errOIDCAndBasic, err)
+}
+
 func TestMissingOIDC(t *testing.T) {
 	// test
 	p, err := NewAuthenticator(Authentication{})
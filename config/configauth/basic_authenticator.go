@@ -0,0 +1,102 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configauth
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"strings"
+
+	"google.golang.org/grpc"
+)
+
+type basicAuthenticator struct {
+	attribute string
+	users     map[string]string
+
+	unaryInterceptor  unaryInterceptorFunc
+	streamInterceptor streamInterceptorFunc
+}
+
+var (
+	_ Authenticator = (*basicAuthenticator)(nil)
+
+	errNoUsersProvided     = errors.New("no users provided for the basic authentication")
+	errInvalidCredentials  = errors.New("invalid username or password")
+	errInvalidBasicAuthFmt = errors.New("invalid basic authentication header format")
+)
+
+func newBasicAuthenticator(cfg Authentication) (*basicAuthenticator, error) {
+	if len(cfg.Basic.Users) == 0 {
+		return nil, errNoUsersProvided
+	}
+	if cfg.Attribute == "" {
+		cfg.Attribute = defaultAttribute
+	}
+
+	return &basicAuthenticator{
+		attribute:         cfg.Attribute,
+		users:             cfg.Basic.Users,
+		unaryInterceptor:  defaultUnaryInterceptor,
+		streamInterceptor: defaultStreamInterceptor,
+	}, nil
+}
+
+func (b *basicAuthenticator) Authenticate(ctx context.Context, headers map[string][]string) (context.Context, error) {
+	authHeaders := headers[b.attribute]
+	if len(authHeaders) == 0 {
+		return ctx, errNotAuthenticated
+	}
+
+	// we only use the first header, if multiple values exist
+	parts := strings.SplitN(authHeaders[0], " ", 2)
+	if len(parts) != 2 || !strings.EqualFold(parts[0], "basic") {
+		return ctx, errInvalidBasicAuthFmt
+	}
+	raw, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return ctx, errInvalidBasicAuthFmt
+	}
+	credentials := strings.SplitN(string(raw), ":", 2)
+	if len(credentials) != 2 {
+		return ctx, errInvalidBasicAuthFmt
+	}
+
+	password, ok := b.users[credentials[0]]
+	// the comparison is done even for unknown users, not to tell them apart by the response time
+	if subtle.ConstantTimeCompare([]byte(password), []byte(credentials[1])) != 1 || !ok {
+		return ctx, errInvalidCredentials
+	}
+
+	return context.WithValue(ctx, subjectKey, credentials[0]), nil
+}
+
+func (b *basicAuthenticator) Start(context.Context) error {
+	return nil
+}
+
+func (b *basicAuthenticator) Close() error {
+	return nil
+}
+
+func (b *basicAuthenticator) UnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	return b.unaryInterceptor(ctx, req, info, handler, b.Authenticate)
+}
+
+func (b *basicAuthenticator) StreamInterceptor(srv interface{}, str grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return b.streamInterceptor(srv, str, info, handler, b.Authenticate)
+}
@@ -0,0 +1,112 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configauth
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func basicHeader(username, password string) []string {
+	return []string{"Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password))}
+}
+
+func TestBasicAuthenticationSucceeded(t *testing.T) {
+	// prepare
+	p, err := newBasicAuthenticator(Authentication{
+		Basic: &BasicAuth{Users: map[string]string{"collector": "secret"}},
+	})
+	require.NoError(t, err)
+	require.NoError(t, p.Start(context.Background()))
+
+	// test
+	ctx, err := p.Authenticate(context.Background(), map[string][]string{"authorization": basicHeader("collector", "secret")})
+
+	// verify
+	assert.NoError(t, err)
+	subject, ok := SubjectFromContext(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, "collector", subject)
+	assert.NoError(t, p.Close())
+}
+
+func TestBasicAuthenticationFailed(t *testing.T) {
+	// prepare
+	p, err := newBasicAuthenticator(Authentication{
+		Attribute: "x-auth",
+		Basic:     &BasicAuth{Users: map[string]string{"collector": "secret"}},
+	})
+	require.NoError(t, err)
+
+	for _, tt := range []struct {
+		name     string
+		headers  map[string][]string
+		expected error
+	}{
+		{
+			name:     "missing",
+			headers:  map[string][]string{"authorization": basicHeader("collector", "secret")},
+			expected: errNotAuthenticated,
+		},
+		{
+			name:     "wrong password",
+			headers:  map[string][]string{"x-auth": basicHeader("collector", "other")},
+			expected: errInvalidCredentials,
+		},
+		{
+			name:     "unknown user",
+			headers:  map[string][]string{"x-auth": basicHeader("other", "")},
+			expected: errInvalidCredentials,
+		},
+		{
+			name:     "bearer",
+			headers:  map[string][]string{"x-auth": {"Bearer token"}},
+			expected: errInvalidBasicAuthFmt,
+		},
+		{
+			name:     "not base64",
+			headers:  map[string][]string{"x-auth": {"Basic !"}},
+			expected: errInvalidBasicAuthFmt,
+		},
+		{
+			name:     "no password",
+			headers:  map[string][]string{"x-auth": {"Basic " + base64.StdEncoding.EncodeToString([]byte("collector"))}},
+			expected: errInvalidBasicAuthFmt,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			// test
+			ctx, err := p.Authenticate(context.Background(), tt.headers)
+
+			// verify
+			assert.Equal(t, tt.expected, err)
+			_, ok := SubjectFromContext(ctx)
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestMissingBasicUsers(t *testing.T) {
+	// test
+	p, err := newBasicAuthenticator(Authentication{Basic: &BasicAuth{}})
+
+	// verify
+	assert.Nil(t, p)
+	assert.Equal(t, errNoUsersProvided, err)
+}
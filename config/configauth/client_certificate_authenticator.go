@@ -0,0 +1,125 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configauth
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+type clientCertificateAuthenticator struct {
+	allowedSANs map[string]struct{}
+
+	unaryInterceptor  unaryInterceptorFunc
+	streamInterceptor streamInterceptorFunc
+}
+
+type tlsStateType struct{}
+
+var (
+	_ Authenticator = (*clientCertificateAuthenticator)(nil)
+
+	tlsStateKey = tlsStateType{}
+
+	errNoAllowedSANsProvided       = errors.New("no allowed subject alternative names provided for the client certificate authentication")
+	errNoClientCertificate         = errors.New("no verified client certificate")
+	errClientCertificateNotAllowed = errors.New("the client certificate has none of the allowed subject alternative names")
+)
+
+func newClientCertificateAuthenticator(cfg Authentication) (*clientCertificateAuthenticator, error) {
+	if len(cfg.ClientCertificate.AllowedSANs) == 0 {
+		return nil, errNoAllowedSANsProvided
+	}
+
+	allowed := make(map[string]struct{}, len(cfg.ClientCertificate.AllowedSANs))
+	for _, san := range cfg.ClientCertificate.AllowedSANs {
+		allowed[san] = struct{}{}
+	}
+
+	return &clientCertificateAuthenticator{
+		allowedSANs:       allowed,
+		unaryInterceptor:  defaultUnaryInterceptor,
+		streamInterceptor: defaultStreamInterceptor,
+	}, nil
+}
+
+// Authenticate checks the client certificate verified during the TLS handshake, the headers
+// aren't used.
+func (c *clientCertificateAuthenticator) Authenticate(ctx context.Context, _ map[string][]string) (context.Context, error) {
+	state := tlsStateFromContext(ctx)
+	if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return ctx, errNoClientCertificate
+	}
+
+	cert := state.VerifiedChains[0][0]
+	for _, san := range certificateSANs(cert) {
+		if _, ok := c.allowedSANs[san]; ok {
+			if _, found := SubjectFromContext(ctx); !found {
+				ctx = context.WithValue(ctx, subjectKey, san)
+			}
+			return ctx, nil
+		}
+	}
+	return ctx, errClientCertificateNotAllowed
+}
+
+func (c *clientCertificateAuthenticator) Start(context.Context) error {
+	return nil
+}
+
+func (c *clientCertificateAuthenticator) Close() error {
+	return nil
+}
+
+func (c *clientCertificateAuthenticator) UnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	return c.unaryInterceptor(ctx, req, info, handler, c.Authenticate)
+}
+
+func (c *clientCertificateAuthenticator) StreamInterceptor(srv interface{}, str grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return c.streamInterceptor(srv, str, info, handler, c.Authenticate)
+}
+
+// tlsStateFromContext returns the TLS connection state of the HTTP request or of the gRPC peer,
+// if any.
+func tlsStateFromContext(ctx context.Context) *tls.ConnectionState {
+	if state, ok := ctx.Value(tlsStateKey).(*tls.ConnectionState); ok {
+		return state
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		if info, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+			return &info.State
+		}
+	}
+	return nil
+}
+
+func certificateSANs(cert *x509.Certificate) []string {
+	sans := make([]string, 0, len(cert.DNSNames)+len(cert.EmailAddresses)+len(cert.IPAddresses)+len(cert.URIs))
+	sans = append(sans, cert.DNSNames...)
+	sans = append(sans, cert.EmailAddresses...)
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	for _, uri := range cert.URIs {
+		sans = append(sans, uri.String())
+	}
+	return sans
+}
@@ -0,0 +1,153 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configauth
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+func verifiedState(cert *x509.Certificate) *tls.ConnectionState {
+	return &tls.ConnectionState{
+		PeerCertificates: []*x509.Certificate{cert},
+		VerifiedChains:   [][]*x509.Certificate{{cert}},
+	}
+}
+
+func TestClientCertificateAuthentication(t *testing.T) {
+	// prepare
+	p, err := newClientCertificateAuthenticator(Authentication{
+		ClientCertificate: &ClientCertificateAuth{
+			AllowedSANs: []string{"agent.example.com", "spiffe://example.com/agent", "10.0.0.1", "agent@example.com"},
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, p.Start(context.Background()))
+	spiffe, err := url.Parse("spiffe://example.com/agent")
+	require.NoError(t, err)
+
+	for _, tt := range []struct {
+		name     string
+		cert     *x509.Certificate
+		expected string
+	}{
+		{
+			name:     "dns",
+			cert:     &x509.Certificate{DNSNames: []string{"other.example.com", "agent.example.com"}},
+			expected: "agent.example.com",
+		},
+		{
+			name:     "uri",
+			cert:     &x509.Certificate{URIs: []*url.URL{spiffe}},
+			expected: "spiffe://example.com/agent",
+		},
+		{
+			name:     "ip",
+			cert:     &x509.Certificate{IPAddresses: []net.IP{net.ParseIP("10.0.0.1")}},
+			expected: "10.0.0.1",
+		},
+		{
+			name:     "email",
+			cert:     &x509.Certificate{EmailAddresses: []string{"agent@example.com"}},
+			expected: "agent@example.com",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			// test
+			ctx, err := p.Authenticate(context.WithValue(context.Background(), tlsStateKey, verifiedState(tt.cert)), nil)
+
+			// verify
+			assert.NoError(t, err)
+			subject, ok := SubjectFromContext(ctx)
+			assert.True(t, ok)
+			assert.Equal(t, tt.expected, subject)
+		})
+	}
+	assert.NoError(t, p.Close())
+}
+
+func TestClientCertificateAuthenticationFromPeer(t *testing.T) {
+	// prepare
+	p, err := newClientCertificateAuthenticator(Authentication{
+		ClientCertificate: &ClientCertificateAuth{AllowedSANs: []string{"agent.example.com"}},
+	})
+	require.NoError(t, err)
+	ctx := peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: credentials.TLSInfo{State: *verifiedState(&x509.Certificate{DNSNames: []string{"agent.example.com"}})},
+	})
+
+	// test
+	_, err = p.Authenticate(ctx, nil)
+
+	// verify
+	assert.NoError(t, err)
+}
+
+func TestClientCertificateAuthenticationFailed(t *testing.T) {
+	// prepare
+	p, err := newClientCertificateAuthenticator(Authentication{
+		ClientCertificate: &ClientCertificateAuth{AllowedSANs: []string{"agent.example.com"}},
+	})
+	require.NoError(t, err)
+	cert := &x509.Certificate{DNSNames: []string{"other.example.com"}}
+
+	for _, tt := range []struct {
+		name     string
+		ctx      context.Context
+		expected error
+	}{
+		{
+			name:     "no tls",
+			ctx:      context.Background(),
+			expected: errNoClientCertificate,
+		},
+		{
+			name:     "not verified",
+			ctx:      context.WithValue(context.Background(), tlsStateKey, &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}),
+			expected: errNoClientCertificate,
+		},
+		{
+			name:     "not allowed",
+			ctx:      context.WithValue(context.Background(), tlsStateKey, verifiedState(cert)),
+			expected: errClientCertificateNotAllowed,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			// test
+			_, err := p.Authenticate(tt.ctx, nil)
+
+			// verify
+			assert.Equal(t, tt.expected, err)
+		})
+	}
+}
+
+func TestMissingAllowedSANs(t *testing.T) {
+	// test
+	p, err := newClientCertificateAuthenticator(Authentication{ClientCertificate: &ClientCertificateAuth{}})
+
+	// verify
+	assert.Nil(t, p)
+	assert.Equal(t, errNoAllowedSANsProvided, err)
+}
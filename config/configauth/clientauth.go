@@ -0,0 +1,134 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configauth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"google.golang.org/grpc/credentials"
+)
+
+// ClientAuthentication defines the auth settings for the clients, e.g. the exporters.
+type ClientAuthentication struct {
+	// AuthenticatorName is the full name of the extension authenticating the requests,
+	// e.g. "oauth2". Required.
+	AuthenticatorName string `mapstructure:"authenticator"`
+}
+
+// ClientAuthenticator adds the credentials to the requests of the clients.
+type ClientAuthenticator interface {
+	// RequestHeaders returns the headers to add to a request, e.g. the authorization header.
+	// The names of the headers are lower case to be used as gRPC metadata.
+	RequestHeaders(ctx context.Context) (map[string]string, error)
+}
+
+var (
+	clientAuthenticatorsMu sync.RWMutex
+	clientAuthenticators   = make(map[string]ClientAuthenticator)
+)
+
+// RegisterClientAuthenticator makes the authenticator available to the clients under the given
+// name, usually the full name of the extension. It returns the function unregistering it.
+func RegisterClientAuthenticator(name string, authenticator ClientAuthenticator) (func(), error) {
+	clientAuthenticatorsMu.Lock()
+	defer clientAuthenticatorsMu.Unlock()
+	if _, ok := clientAuthenticators[name]; ok {
+		return nil, fmt.Errorf("the authenticator %q is already registered", name)
+	}
+	clientAuthenticators[name] = authenticator
+	return func() {
+		clientAuthenticatorsMu.Lock()
+		defer clientAuthenticatorsMu.Unlock()
+		if clientAuthenticators[name] == authenticator {
+			delete(clientAuthenticators, name)
+		}
+	}, nil
+}
+
+func getClientAuthenticator(name string) (ClientAuthenticator, error) {
+	clientAuthenticatorsMu.RLock()
+	defer clientAuthenticatorsMu.RUnlock()
+	authenticator, ok := clientAuthenticators[name]
+	if !ok {
+		return nil, fmt.Errorf("the authenticator %q is not found, it must be an extension of the service", name)
+	}
+	return authenticator, nil
+}
+
+// Validate checks that the authenticator is registered, the extensions being started before the
+// clients are created.
+func (ca *ClientAuthentication) Validate() error {
+	if ca.AuthenticatorName == "" {
+		return fmt.Errorf("\"authenticator\" is required")
+	}
+	_, err := getClientAuthenticator(ca.AuthenticatorName)
+	return err
+}
+
+// ToPerRPCCredentials returns the gRPC credentials adding the headers of the authenticator to
+// every RPC. The authenticator is looked up on each RPC, and the credentials require a secure
+// transport.
+func (ca *ClientAuthentication) ToPerRPCCredentials() credentials.PerRPCCredentials {
+	return &clientAuthCredentials{name: ca.AuthenticatorName}
+}
+
+// ToRoundTripper returns an HTTP transport adding the headers of the authenticator to every
+// request sent with the base transport.
+func (ca *ClientAuthentication) ToRoundTripper(base http.RoundTripper) http.RoundTripper {
+	return &clientAuthRoundTripper{name: ca.AuthenticatorName, base: base}
+}
+
+type clientAuthCredentials struct {
+	name string
+}
+
+var _ credentials.PerRPCCredentials = (*clientAuthCredentials)(nil)
+
+func (c *clientAuthCredentials) GetRequestMetadata(ctx context.Context, _ ...string) (map[string]string, error) {
+	authenticator, err := getClientAuthenticator(c.name)
+	if err != nil {
+		return nil, err
+	}
+	return authenticator.RequestHeaders(ctx)
+}
+
+func (c *clientAuthCredentials) RequireTransportSecurity() bool {
+	return true
+}
+
+type clientAuthRoundTripper struct {
+	name string
+	base http.RoundTripper
+}
+
+func (rt *clientAuthRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	authenticator, err := getClientAuthenticator(rt.name)
+	if err != nil {
+		return nil, err
+	}
+	headers, err := authenticator.RequestHeaders(req.Context())
+	if err != nil {
+		return nil, fmt.Errorf("failed to authenticate the request: %w", err)
+	}
+	// The request must not be modified by a RoundTripper.
+	req = req.Clone(req.Context())
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	return rt.base.RoundTrip(req)
+}
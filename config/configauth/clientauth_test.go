@@ -0,0 +1,104 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configauth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClientAuthenticator struct {
+	headers map[string]string
+	err     error
+}
+
+func (a *fakeClientAuthenticator) RequestHeaders(context.Context) (map[string]string, error) {
+	return a.headers, a.err
+}
+
+func TestRegisterClientAuthenticator(t *testing.T) {
+	// prepare
+	ca := &ClientAuthentication{AuthenticatorName: "fake"}
+	assert.Error(t, ca.Validate())
+
+	// test
+	unregister, err := RegisterClientAuthenticator("fake", &fakeClientAuthenticator{})
+	require.NoError(t, err)
+	_, err = RegisterClientAuthenticator("fake", &fakeClientAuthenticator{})
+
+	// verify
+	assert.Error(t, err)
+	assert.NoError(t, ca.Validate())
+	unregister()
+	assert.Error(t, ca.Validate())
+	assert.Error(t, (&ClientAuthentication{}).Validate())
+}
+
+func TestClientAuthenticationPerRPCCredentials(t *testing.T) {
+	// prepare
+	ca := &ClientAuthentication{AuthenticatorName: "fake/grpc"}
+	creds := ca.ToPerRPCCredentials()
+	_, err := creds.GetRequestMetadata(context.Background())
+	assert.Error(t, err)
+
+	unregister, err := RegisterClientAuthenticator("fake/grpc", &fakeClientAuthenticator{headers: map[string]string{"authorization": "Bearer token"}})
+	require.NoError(t, err)
+	defer unregister()
+
+	// test
+	md, err := creds.GetRequestMetadata(context.Background())
+
+	// verify
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"authorization": "Bearer token"}, md)
+	assert.True(t, creds.RequireTransportSecurity())
+}
+
+func TestClientAuthenticationRoundTripper(t *testing.T) {
+	// prepare
+	var received string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Get("Authorization")
+	}))
+	defer server.Close()
+
+	authenticator := &fakeClientAuthenticator{headers: map[string]string{"authorization": "Bearer token"}}
+	unregister, err := RegisterClientAuthenticator("fake/http", authenticator)
+	require.NoError(t, err)
+	defer unregister()
+	ca := &ClientAuthentication{AuthenticatorName: "fake/http"}
+	client := &http.Client{Transport: ca.ToRoundTripper(http.DefaultTransport)}
+
+	// test
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	resp, err := client.Do(req)
+
+	// verify
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, "Bearer token", received)
+	assert.Empty(t, req.Header.Get("Authorization"))
+
+	authenticator.err = errors.New("no token")
+	_, err = client.Get(server.URL)
+	assert.Error(t, err)
+}
@@ -16,8 +16,13 @@ package configauth
 
 import (
 	"context"
+	"errors"
+	"net/http"
+	"strings"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // Authentication defines the auth settings for the receiver
@@ -26,8 +31,32 @@ type Authentication struct {
 	Attribute string `mapstructure:"attribute"`
 
 	// OIDC configures this receiver to use the given OIDC provider as the backend for the authentication mechanism.
-	// Required.
+	// Optional, one of OIDC, Basic and ClientCertificate is required.
 	OIDC *OIDC `mapstructure:"oidc"`
+
+	// Basic configures this receiver to require the HTTP basic authentication of one of the given users.
+	// It can't be used with OIDC. Optional.
+	Basic *BasicAuth `mapstructure:"basic"`
+
+	// ClientCertificate configures this receiver to require a client certificate with one of the given
+	// subject alternative names, in addition to the OIDC or basic authentication if any. The server must
+	// verify the client certificates, with the client_ca_file of its TLS settings. Optional.
+	ClientCertificate *ClientCertificateAuth `mapstructure:"client_certificate"`
+}
+
+// BasicAuth defines the users allowed by the HTTP basic authentication
+type BasicAuth struct {
+	// Users are the passwords of the allowed users, by username.
+	// Required.
+	Users map[string]string `mapstructure:"users" sensitive:"true"`
+}
+
+// ClientCertificateAuth defines the client certificates allowed to connect to this receiver
+type ClientCertificateAuth struct {
+	// AllowedSANs are the subject alternative names, DNS names, email addresses, IP addresses or URIs,
+	// one of which the client certificate must have.
+	// Required.
+	AllowedSANs []string `mapstructure:"allowed_sans"`
 }
 
 // OIDC defines the OpenID Connect properties for this processor
@@ -68,7 +97,96 @@ func (a *Authentication) ToServerOptions() ([]grpc.ServerOption, error) {
 	}
 
 	return []grpc.ServerOption{
-		grpc.UnaryInterceptor(auth.UnaryInterceptor),
-		grpc.StreamInterceptor(auth.StreamInterceptor),
+		grpc.UnaryInterceptor(unaryServerInterceptor(auth)),
+		grpc.StreamInterceptor(streamServerInterceptor(auth)),
 	}, nil
 }
+
+// unaryServerInterceptor turns the errors of the authenticator into gRPC status codes, this is
+// only done here not to mistake the errors of the handlers for authentication failures.
+func unaryServerInterceptor(auth Authenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		handled := false
+		res, err := auth.UnaryInterceptor(ctx, req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			handled = true
+			return handler(ctx, req)
+		})
+		if err != nil && !handled {
+			return nil, authFailure(ctx, transportGRPC, err)
+		}
+		return res, err
+	}
+}
+
+// streamServerInterceptor is the equivalent of unaryServerInterceptor for the streams.
+func streamServerInterceptor(auth Authenticator) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		handled := false
+		err := auth.StreamInterceptor(srv, ss, info, func(srv interface{}, ss grpc.ServerStream) error {
+			handled = true
+			return handler(srv, ss)
+		})
+		if err != nil && !handled {
+			return authFailure(ss.Context(), transportGRPC, err)
+		}
+		return err
+	}
+}
+
+// ToHTTPHandler wraps the handler of an HTTP server to authenticate the requests, the requests
+// not authenticated are refused with 401 Unauthorized and the client certificates without any of
+// the allowed subject alternative names with 403 Forbidden.
+func (a *Authentication) ToHTTPHandler(next http.Handler) (http.Handler, error) {
+	auth, err := NewAuthenticator(*a)
+	if err != nil {
+		return nil, err
+	}
+
+	// TODO: we need a hook to call auth.Close()
+	if err := auth.Start(context.Background()); err != nil {
+		return nil, err
+	}
+
+	challenge := ""
+	switch {
+	case a.OIDC != nil:
+		challenge = "Bearer"
+	case a.Basic != nil:
+		challenge = `Basic realm="OpenTelemetry Collector"`
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the headers are looked for in lower case, as in the gRPC metadata
+		headers := make(map[string][]string, len(r.Header))
+		for k, v := range r.Header {
+			headers[strings.ToLower(k)] = v
+		}
+		ctx := r.Context()
+		if r.TLS != nil {
+			ctx = context.WithValue(ctx, tlsStateKey, r.TLS)
+		}
+
+		ctx, err := auth.Authenticate(ctx, headers)
+		if err != nil {
+			code := http.StatusUnauthorized
+			if status.Code(authFailure(ctx, transportHTTP, err)) == codes.PermissionDenied {
+				code = http.StatusForbidden
+			} else if challenge != "" {
+				w.Header().Set("WWW-Authenticate", challenge)
+			}
+			http.Error(w, http.StatusText(code), code)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	}), nil
+}
+
+// authFailure records the authentication failure and returns the gRPC status of the error.
+func authFailure(ctx context.Context, transport string, err error) error {
+	code, reason := codes.Unauthenticated, reasonUnauthenticated
+	if errors.Is(err, errClientCertificateNotAllowed) {
+		code, reason = codes.PermissionDenied, reasonPermissionDenied
+	}
+	recordAuthFailure(ctx, transport, reason)
+	return status.Error(code, err.Error())
+}
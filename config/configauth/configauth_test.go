@@ -15,10 +15,20 @@
 package configauth
 
 import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 func TestToServerOptions(t *testing.T) {
@@ -72,3 +82,148 @@ func TestInvalidConfigurationFailsOnToServerOptions(t *testing.T) {
 	}
 
 }
+
+func TestServerInterceptorsStatus(t *testing.T) {
+	// prepare
+	auth, err := NewAuthenticator(Authentication{
+		Basic:             &BasicAuth{Users: map[string]string{"collector": "secret"}},
+		ClientCertificate: &ClientCertificateAuth{AllowedSANs: []string{"agent.example.com"}},
+	})
+	require.NoError(t, err)
+	unary := unaryServerInterceptor(auth)
+	stream := streamServerInterceptor(auth)
+	handlerErr := errors.New("handler failed")
+	md := metadata.Pairs("authorization", basicHeader("collector", "secret")[0])
+
+	for _, tt := range []struct {
+		name     string
+		ctx      context.Context
+		expected error
+	}{
+		{
+			name:     "authenticated",
+			ctx:      context.WithValue(metadata.NewIncomingContext(context.Background(), md), tlsStateKey, verifiedState(&x509.Certificate{DNSNames: []string{"agent.example.com"}})),
+			expected: handlerErr,
+		},
+		{
+			name:     "no metadata",
+			ctx:      context.Background(),
+			expected: status.Error(codes.Unauthenticated, errMetadataNotFound.Error()),
+		},
+		{
+			name:     "no certificate",
+			ctx:      metadata.NewIncomingContext(context.Background(), md),
+			expected: status.Error(codes.Unauthenticated, errNoClientCertificate.Error()),
+		},
+		{
+			name:     "not allowed",
+			ctx:      context.WithValue(metadata.NewIncomingContext(context.Background(), md), tlsStateKey, verifiedState(&x509.Certificate{DNSNames: []string{"other.example.com"}})),
+			expected: status.Error(codes.PermissionDenied, errClientCertificateNotAllowed.Error()),
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			// test
+			_, unaryErr := unary(tt.ctx, nil, &grpc.UnaryServerInfo{}, func(context.Context, interface{}) (interface{}, error) {
+				return nil, handlerErr
+			})
+			streamErr := stream(nil, &mockServerStream{ctx: tt.ctx}, &grpc.StreamServerInfo{}, func(interface{}, grpc.ServerStream) error {
+				return handlerErr
+			})
+
+			// verify
+			assert.Equal(t, tt.expected, unaryErr)
+			assert.Equal(t, tt.expected, streamErr)
+		})
+	}
+}
+
+func TestToHTTPHandler(t *testing.T) {
+	// prepare
+	views := MetricViews()
+	require.NoError(t, view.Register(views...))
+	defer view.Unregister(views...)
+
+	config := Authentication{
+		Basic:             &BasicAuth{Users: map[string]string{"collector": "secret"}},
+		ClientCertificate: &ClientCertificateAuth{AllowedSANs: []string{"agent.example.com"}},
+	}
+	handler, err := config.ToHTTPHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		subject, _ := SubjectFromContext(r.Context())
+		_, _ = w.Write([]byte(subject))
+	}))
+	require.NoError(t, err)
+
+	for _, tt := range []struct {
+		name      string
+		cert      *x509.Certificate
+		password  string
+		expected  int
+		challenge string
+	}{
+		{
+			name:     "authenticated",
+			cert:     &x509.Certificate{DNSNames: []string{"agent.example.com"}},
+			password: "secret",
+			expected: http.StatusOK,
+		},
+		{
+			name:      "no certificate",
+			password:  "secret",
+			expected:  http.StatusUnauthorized,
+			challenge: `Basic realm="OpenTelemetry Collector"`,
+		},
+		{
+			name:      "wrong password",
+			cert:      &x509.Certificate{DNSNames: []string{"agent.example.com"}},
+			password:  "other",
+			expected:  http.StatusUnauthorized,
+			challenge: `Basic realm="OpenTelemetry Collector"`,
+		},
+		{
+			name:     "not allowed",
+			cert:     &x509.Certificate{DNSNames: []string{"other.example.com"}},
+			password: "secret",
+			expected: http.StatusForbidden,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/v1/traces", nil)
+			req.SetBasicAuth("collector", tt.password)
+			if tt.cert != nil {
+				req.TLS = verifiedState(tt.cert)
+			}
+			rec := httptest.NewRecorder()
+
+			// test
+			handler.ServeHTTP(rec, req)
+
+			// verify
+			assert.Equal(t, tt.expected, rec.Code)
+			assert.Equal(t, tt.challenge, rec.Header().Get("WWW-Authenticate"))
+			if tt.expected == http.StatusOK {
+				assert.Equal(t, "collector", rec.Body.String())
+			}
+		})
+	}
+
+	rows, err := view.RetrieveData(statAuthFailures.Name())
+	require.NoError(t, err)
+	failures := map[string]float64{}
+	for _, row := range rows {
+		for _, tag := range row.Tags {
+			if tag.Key == tagReasonKey {
+				failures[tag.Value] = row.Data.(*view.SumData).Value
+			}
+		}
+	}
+	assert.Equal(t, map[string]float64{reasonUnauthenticated: 2, reasonPermissionDenied: 1}, failures)
+}
+
+func TestInvalidConfigurationFailsOnToHTTPHandler(t *testing.T) {
+	// test
+	handler, err := (&Authentication{}).ToHTTPHandler(http.NotFoundHandler())
+
+	// verify
+	assert.Equal(t, errNoOIDCProvided, err)
+	assert.Nil(t, handler)
+}
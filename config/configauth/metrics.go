@@ -0,0 +1,58 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configauth
+
+import (
+	"context"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+)
+
+const (
+	transportGRPC = "grpc"
+	transportHTTP = "http"
+
+	reasonUnauthenticated  = "unauthenticated"
+	reasonPermissionDenied = "permission_denied"
+)
+
+var (
+	tagTransportKey, _ = tag.NewKey("transport")
+	tagReasonKey, _    = tag.NewKey("reason")
+
+	statAuthFailures = stats.Int64("receiver/auth_failures", "Number of requests refused by the authentication of the receivers", stats.UnitDimensionless)
+)
+
+// MetricViews returns the metrics views related to the authentication of the receivers.
+func MetricViews() []*view.View {
+	return []*view.View{
+		{
+			Name:        statAuthFailures.Name(),
+			Measure:     statAuthFailures,
+			Description: statAuthFailures.Description(),
+			TagKeys:     []tag.Key{tagTransportKey, tagReasonKey},
+			Aggregation: view.Sum(),
+		},
+	}
+}
+
+func recordAuthFailure(ctx context.Context, transport string, reason string) {
+	_ = stats.RecordWithTags(
+		ctx,
+		[]tag.Mutator{tag.Upsert(tagTransportKey, transport), tag.Upsert(tagReasonKey, reason)},
+		statAuthFailures.M(1))
+}
@@ -16,6 +16,7 @@ package configgrpc
 
 import (
 	"context"
+	"encoding/base64"
 	"path"
 	"runtime"
 	"testing"
@@ -24,6 +25,9 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 
 	"go.opentelemetry.io/collector/config/configauth"
 	"go.opentelemetry.io/collector/config/confignet"
@@ -58,6 +62,14 @@ func TestAllGrpcClientSettings(t *testing.T) {
 			Timeout:             time.Second,
 			PermitWithoutStream: true,
 		},
+		ConnectParams: &ConnectParamsConfig{
+			BaseDelay:         time.Second,
+			Multiplier:        2,
+			Jitter:            0.1,
+			MaxDelay:          time.Minute,
+			MinConnectTimeout: 10 * time.Second,
+		},
+		ProxyURL:        "http://proxy:3128",
 		ReadBufferSize:  1024,
 		WriteBufferSize: 1024,
 		WaitForReady:    true,
@@ -66,7 +78,7 @@ func TestAllGrpcClientSettings(t *testing.T) {
 	}
 	opts, err := gcs.ToDialOptions()
 	assert.NoError(t, err)
-	assert.Len(t, opts, 6)
+	assert.Len(t, opts, 8)
 }
 
 func TestDefaultGrpcServerSettings(t *testing.T) {
@@ -187,6 +199,27 @@ func TestGRPCClientSettingsError(t *testing.T) {
 				BalancerName:    "test",
 			},
 		},
+		{
+			err: "unsupported proxy_url scheme \"ftp\", must be http, https or socks5",
+			settings: GRPCClientSettings{
+				Endpoint: "localhost:1234",
+				ProxyURL: "ftp://proxy:21",
+			},
+		},
+		{
+			err: "invalid proxy_url: the host is missing",
+			settings: GRPCClientSettings{
+				Endpoint: "localhost:1234",
+				ProxyURL: "http://",
+			},
+		},
+		{
+			err: "the dial options provider \"missing\" is not found",
+			settings: GRPCClientSettings{
+				Endpoint:    "localhost:1234",
+				DialOptions: []string{"missing"},
+			},
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.err, func(t *testing.T) {
@@ -486,6 +519,95 @@ func TestReceiveOnUnixDomainSocket(t *testing.T) {
 	s.Stop()
 }
 
+func TestReceiveOnAdditionalEndpoints(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping test on windows")
+	}
+	socketName := testutil.TempSocketName(t)
+	gss := &GRPCServerSettings{
+		NetAddr: confignet.NetAddr{
+			Endpoint:  "127.0.0.1:0",
+			Transport: "tcp",
+			AdditionalEndpoints: []confignet.AdditionalEndpoint{
+				{Endpoint: socketName, Transport: "unix"},
+			},
+		},
+	}
+	ln, err := gss.ToListener()
+	require.NoError(t, err)
+	opts, err := gss.ToServerOption()
+	require.NoError(t, err)
+	s := grpc.NewServer(opts...)
+	otelcol.RegisterTraceServiceServer(s, &grpcTraceServer{})
+	go func() {
+		_ = s.Serve(ln)
+	}()
+	defer s.Stop()
+
+	for _, endpoint := range []string{ln.Addr().String(), "unix://" + socketName} {
+		gcs := &GRPCClientSettings{
+			Endpoint: endpoint,
+			TLSSetting: configtls.TLSClientSetting{
+				Insecure: true,
+			},
+		}
+		clientOpts, err := gcs.ToDialOptions()
+		require.NoError(t, err)
+		grpcClientConn, err := grpc.Dial(gcs.Endpoint, clientOpts...)
+		require.NoError(t, err)
+		ctx, cancelFunc := context.WithTimeout(context.Background(), 2*time.Second)
+		_, err = otelcol.NewTraceServiceClient(grpcClientConn).Export(ctx, &otelcol.ExportTraceServiceRequest{}, grpc.WaitForReady(true))
+		cancelFunc()
+		assert.NoError(t, err, endpoint)
+		grpcClientConn.Close()
+	}
+}
+
+func TestReceiveWithBasicAuth(t *testing.T) {
+	gss := &GRPCServerSettings{
+		NetAddr: confignet.NetAddr{
+			Endpoint:  "localhost:0",
+			Transport: "tcp",
+		},
+		Auth: &configauth.Authentication{
+			Basic: &configauth.BasicAuth{Users: map[string]string{"collector": "secret"}},
+		},
+	}
+	ln, err := gss.ToListener()
+	require.NoError(t, err)
+	opts, err := gss.ToServerOption()
+	require.NoError(t, err)
+	s := grpc.NewServer(opts...)
+	otelcol.RegisterTraceServiceServer(s, &grpcTraceServer{})
+	go func() {
+		_ = s.Serve(ln)
+	}()
+	defer s.Stop()
+
+	gcs := &GRPCClientSettings{
+		Endpoint: ln.Addr().String(),
+		TLSSetting: configtls.TLSClientSetting{
+			Insecure: true,
+		},
+	}
+	clientOpts, err := gcs.ToDialOptions()
+	require.NoError(t, err)
+	grpcClientConn, err := grpc.Dial(gcs.Endpoint, clientOpts...)
+	require.NoError(t, err)
+	defer grpcClientConn.Close()
+	client := otelcol.NewTraceServiceClient(grpcClientConn)
+	ctx, cancelFunc := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancelFunc()
+
+	_, err = client.Export(ctx, &otelcol.ExportTraceServiceRequest{}, grpc.WaitForReady(true))
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	authCtx := metadata.AppendToOutgoingContext(ctx, "authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte("collector:secret")))
+	resp, err := client.Export(authCtx, &otelcol.ExportTraceServiceRequest{}, grpc.WaitForReady(true))
+	assert.NoError(t, err)
+	assert.NotNil(t, resp)
+}
+
 type grpcTraceServer struct{}
 
 func (gts *grpcTraceServer) Export(context.Context, *otelcol.ExportTraceServiceRequest) (*otelcol.ExportTraceServiceResponse, error) {
@@ -521,3 +643,76 @@ func TestWithPerRPCAuthInvalidAuthType(t *testing.T) {
 	assert.Error(t, err)
 	assert.Nil(t, dialOpts)
 }
+
+type fakeClientAuthenticator struct{}
+
+func (fakeClientAuthenticator) RequestHeaders(context.Context) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer token"}, nil
+}
+
+func TestWithClientAuth(t *testing.T) {
+	// prepare
+	gcs := &GRPCClientSettings{
+		Auth: &configauth.ClientAuthentication{AuthenticatorName: "fake"},
+	}
+	_, err := gcs.ToDialOptions()
+	assert.Error(t, err)
+	unregister, err := configauth.RegisterClientAuthenticator("fake", fakeClientAuthenticator{})
+	require.NoError(t, err)
+	defer unregister()
+
+	// test
+	dialOpts, err := gcs.ToDialOptions()
+
+	// verify
+	assert.NoError(t, err)
+	assert.Len(t, dialOpts, 2) // WithInsecure and WithPerRPCCredentials
+}
+
+type fakeDialOptionsProvider struct {
+	opts []grpc.DialOption
+}
+
+func (p fakeDialOptionsProvider) DialOptions() []grpc.DialOption {
+	return p.opts
+}
+
+func TestWithDialOptions(t *testing.T) {
+	var intercepted []string
+	interceptor := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		intercepted = append(intercepted, method)
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+	unregister, err := RegisterDialOptionsProvider("interceptor", fakeDialOptionsProvider{
+		opts: []grpc.DialOption{grpc.WithUnaryInterceptor(interceptor)},
+	})
+	require.NoError(t, err)
+	defer unregister()
+	_, err = RegisterDialOptionsProvider("interceptor", fakeDialOptionsProvider{})
+	assert.EqualError(t, err, `the dial options provider "interceptor" is already registered`)
+
+	endpoint := startTraceServer(t)
+	gcs := &GRPCClientSettings{
+		Endpoint: endpoint,
+		TLSSetting: configtls.TLSClientSetting{
+			Insecure: true,
+		},
+		DialOptions: []string{"interceptor"},
+	}
+	opts, err := gcs.ToDialOptions()
+	require.NoError(t, err)
+	assert.Len(t, opts, 2) // WithInsecure and WithUnaryInterceptor
+
+	conn, err := grpc.Dial(gcs.Endpoint, opts...)
+	require.NoError(t, err)
+	defer conn.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	_, err = otelcol.NewTraceServiceClient(conn).Export(ctx, &otelcol.ExportTraceServiceRequest{}, grpc.WaitForReady(true))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/opentelemetry.proto.collector.trace.v1.TraceService/Export"}, intercepted)
+
+	unregister()
+	_, err = gcs.ToDialOptions()
+	assert.Error(t, err)
+}
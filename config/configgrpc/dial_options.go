@@ -0,0 +1,74 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configgrpc
+
+import (
+	"fmt"
+	"sync"
+
+	"google.golang.org/grpc"
+)
+
+// DialOptionsProvider contributes dial options to the gRPC clients, e.g. the
+// interceptors of an extension.
+type DialOptionsProvider interface {
+	// DialOptions returns the options to add to the options of the client.
+	DialOptions() []grpc.DialOption
+}
+
+var (
+	dialOptionsProvidersMu sync.RWMutex
+	dialOptionsProviders   = make(map[string]*registeredProvider)
+)
+
+// registeredProvider identifies a registration, the providers may not be comparable.
+type registeredProvider struct {
+	provider DialOptionsProvider
+}
+
+// RegisterDialOptionsProvider makes the provider available to the clients under the given
+// name, usually the full name of the extension. It returns the function unregistering it.
+func RegisterDialOptionsProvider(name string, provider DialOptionsProvider) (func(), error) {
+	dialOptionsProvidersMu.Lock()
+	defer dialOptionsProvidersMu.Unlock()
+	if _, ok := dialOptionsProviders[name]; ok {
+		return nil, fmt.Errorf("the dial options provider %q is already registered", name)
+	}
+	registered := &registeredProvider{provider: provider}
+	dialOptionsProviders[name] = registered
+	return func() {
+		dialOptionsProvidersMu.Lock()
+		defer dialOptionsProvidersMu.Unlock()
+		if dialOptionsProviders[name] == registered {
+			delete(dialOptionsProviders, name)
+		}
+	}, nil
+}
+
+// providedDialOptions returns the options of the providers in the given order, the extensions
+// being started before the clients are created.
+func providedDialOptions(names []string) ([]grpc.DialOption, error) {
+	dialOptionsProvidersMu.RLock()
+	defer dialOptionsProvidersMu.RUnlock()
+	var opts []grpc.DialOption
+	for _, name := range names {
+		registered, ok := dialOptionsProviders[name]
+		if !ok {
+			return nil, fmt.Errorf("the dial options provider %q is not found, it must be an extension of the service", name)
+		}
+		opts = append(opts, registered.provider.DialOptions()...)
+	}
+	return opts, nil
+}
@@ -0,0 +1,253 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configgrpc
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"go.opentelemetry.io/collector/config/configtls"
+	otelcol "go.opentelemetry.io/collector/internal/data/protogen/collector/trace/v1"
+)
+
+func startTraceServer(t *testing.T) string {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := grpc.NewServer()
+	otelcol.RegisterTraceServiceServer(s, &grpcTraceServer{})
+	go func() {
+		_ = s.Serve(ln)
+	}()
+	t.Cleanup(s.Stop)
+	return ln.Addr().String()
+}
+
+func exportThrough(t *testing.T, proxyURL string, endpoint string) error {
+	gcs := &GRPCClientSettings{
+		Endpoint: endpoint,
+		TLSSetting: configtls.TLSClientSetting{
+			Insecure: true,
+		},
+		ProxyURL: proxyURL,
+	}
+	opts, err := gcs.ToDialOptions()
+	require.NoError(t, err)
+	conn, err := grpc.Dial(gcs.Endpoint, opts...)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	_, err = otelcol.NewTraceServiceClient(conn).Export(ctx, &otelcol.ExportTraceServiceRequest{}, grpc.WaitForReady(true))
+	return err
+}
+
+func pipe(a, b net.Conn) {
+	go func() {
+		_, _ = io.Copy(a, b)
+		a.Close()
+	}()
+	_, _ = io.Copy(b, a)
+	b.Close()
+}
+
+// newConnectProxy returns an HTTP proxy tunneling the CONNECT requests, the
+// credentials are required when the authorization is not empty.
+func newConnectProxy(t *testing.T, authorization string, tunnels *int32) *httptest.Server {
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodConnect {
+			http.Error(w, "only CONNECT is supported", http.StatusMethodNotAllowed)
+			return
+		}
+		if authorization != "" && r.Header.Get("Proxy-Authorization") != authorization {
+			w.WriteHeader(http.StatusProxyAuthRequired)
+			return
+		}
+		target, err := net.Dial("tcp", r.Host)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			target.Close()
+			return
+		}
+		atomic.AddInt32(tunnels, 1)
+		_, _ = conn.Write([]byte("HTTP/1.1 200 Connection established\r\n\r\n"))
+		pipe(conn, target)
+	}))
+	t.Cleanup(proxy.Close)
+	return proxy
+}
+
+func TestHTTPProxy(t *testing.T) {
+	endpoint := startTraceServer(t)
+	var tunnels int32
+	proxy := newConnectProxy(t, "", &tunnels)
+
+	require.NoError(t, exportThrough(t, proxy.URL, endpoint))
+	assert.EqualValues(t, 1, atomic.LoadInt32(&tunnels))
+}
+
+func TestHTTPProxyAuthentication(t *testing.T) {
+	endpoint := startTraceServer(t)
+	var tunnels int32
+	authorization := "Basic " + base64.StdEncoding.EncodeToString([]byte("collector:secret"))
+	proxy := newConnectProxy(t, authorization, &tunnels)
+	host := proxy.Listener.Addr().String()
+
+	require.NoError(t, exportThrough(t, "http://collector:secret@"+host, endpoint))
+	assert.EqualValues(t, 1, atomic.LoadInt32(&tunnels))
+
+	err := exportThrough(t, "http://collector:wrong@"+host, endpoint)
+	assert.Error(t, err)
+	assert.EqualValues(t, 1, atomic.LoadInt32(&tunnels))
+}
+
+// newSOCKS5Proxy returns the address of a SOCKS5 proxy supporting the CONNECT
+// command, requiring the user and password when not empty.
+func newSOCKS5Proxy(t *testing.T, user, password string, tunnels *int32) string {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	handle := func(conn net.Conn) {
+		r := bufio.NewReader(conn)
+		read := func(n int) []byte {
+			b := make([]byte, n)
+			if _, err := io.ReadFull(r, b); err != nil {
+				return nil
+			}
+			return b
+		}
+
+		greeting := read(2)
+		if greeting == nil || greeting[0] != 5 || read(int(greeting[1])) == nil {
+			conn.Close()
+			return
+		}
+		if user == "" {
+			_, _ = conn.Write([]byte{5, 0})
+		} else {
+			_, _ = conn.Write([]byte{5, 2})
+			version := read(2)
+			if version == nil {
+				conn.Close()
+				return
+			}
+			u := string(read(int(version[1])))
+			p := string(read(int(read(1)[0])))
+			if u != user || p != password {
+				_, _ = conn.Write([]byte{1, 1})
+				conn.Close()
+				return
+			}
+			_, _ = conn.Write([]byte{1, 0})
+		}
+
+		request := read(4)
+		if request == nil || request[1] != 1 {
+			conn.Close()
+			return
+		}
+		var host string
+		switch request[3] {
+		case 1:
+			host = net.IP(read(4)).String()
+		case 3:
+			host = string(read(int(read(1)[0])))
+		case 4:
+			host = net.IP(read(16)).String()
+		}
+		port := binary.BigEndian.Uint16(read(2))
+		target, err := net.Dial("tcp", net.JoinHostPort(host, strconv.Itoa(int(port))))
+		if err != nil {
+			_, _ = conn.Write([]byte{5, 5, 0, 1, 0, 0, 0, 0, 0, 0})
+			conn.Close()
+			return
+		}
+		atomic.AddInt32(tunnels, 1)
+		_, _ = conn.Write([]byte{5, 0, 0, 1, 0, 0, 0, 0, 0, 0})
+		pipe(&bufferedConn{Conn: conn, r: r}, target)
+	}
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go handle(conn)
+		}
+	}()
+	return ln.Addr().String()
+}
+
+func TestSOCKS5Proxy(t *testing.T) {
+	endpoint := startTraceServer(t)
+	var tunnels int32
+	proxy := newSOCKS5Proxy(t, "", "", &tunnels)
+
+	require.NoError(t, exportThrough(t, "socks5://"+proxy, endpoint))
+	assert.EqualValues(t, 1, atomic.LoadInt32(&tunnels))
+}
+
+func TestSOCKS5ProxyAuthentication(t *testing.T) {
+	endpoint := startTraceServer(t)
+	var tunnels int32
+	proxy := newSOCKS5Proxy(t, "collector", "secret", &tunnels)
+
+	require.NoError(t, exportThrough(t, "socks5://collector:secret@"+proxy, endpoint))
+	assert.EqualValues(t, 1, atomic.LoadInt32(&tunnels))
+
+	err := exportThrough(t, "socks5://collector:wrong@"+proxy, endpoint)
+	assert.Error(t, err)
+	assert.EqualValues(t, 1, atomic.LoadInt32(&tunnels))
+}
+
+func TestProxyHostPort(t *testing.T) {
+	tests := []struct {
+		url      string
+		expected string
+	}{
+		{url: "http://proxy", expected: "proxy:80"},
+		{url: "https://proxy", expected: "proxy:443"},
+		{url: "socks5://proxy", expected: "proxy:1080"},
+		{url: "http://proxy:3128", expected: "proxy:3128"},
+	}
+	for _, test := range tests {
+		t.Run(test.url, func(t *testing.T) {
+			u, err := url.Parse(test.url)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, proxyHostPort(u))
+		})
+	}
+}
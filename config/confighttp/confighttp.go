@@ -22,6 +22,8 @@ import (
 
 	"github.com/rs/cors"
 
+	"go.opentelemetry.io/collector/config/configauth"
+	"go.opentelemetry.io/collector/config/confignet"
 	"go.opentelemetry.io/collector/config/configtls"
 	"go.opentelemetry.io/collector/internal/middleware"
 )
@@ -44,7 +46,10 @@ type HTTPClientSettings struct {
 
 	// Additional headers attached to each HTTP request sent by the client.
 	// Existing header values are overwritten if collision happens.
-	Headers map[string]string `mapstructure:"headers,omitempty"`
+	Headers map[string]string `mapstructure:"headers,omitempty" sensitive:"true"`
+
+	// Auth configures the extension adding the credentials to every request, e.g. OAuth2 tokens.
+	Auth *configauth.ClientAuthentication `mapstructure:"auth,omitempty"`
 
 	// Custom Round Tripper to allow for individual components to intercept HTTP requests
 	CustomRoundTripper func(next http.RoundTripper) (http.RoundTripper, error)
@@ -74,6 +79,13 @@ func (hcs *HTTPClientSettings) ToClient() (*http.Client, error) {
 		}
 	}
 
+	if hcs.Auth != nil {
+		if err = hcs.Auth.Validate(); err != nil {
+			return nil, err
+		}
+		clientTransport = hcs.Auth.ToRoundTripper(clientTransport)
+	}
+
 	if hcs.CustomRoundTripper != nil {
 		clientTransport, err = hcs.CustomRoundTripper(clientTransport)
 		if err != nil {
@@ -106,6 +118,18 @@ type HTTPServerSettings struct {
 	// Endpoint configures the listening address for the server.
 	Endpoint string `mapstructure:"endpoint"`
 
+	// Transport to listen on, "tcp" (the default) or "unix" for a unix domain socket whose
+	// path is Endpoint.
+	Transport string `mapstructure:"transport"`
+
+	// AdditionalEndpoints are the other addresses to listen on, e.g. an IPv6 address next to
+	// an IPv4 one or a unix socket next to a TCP address.
+	AdditionalEndpoints []confignet.AdditionalEndpoint `mapstructure:"additional_endpoints,omitempty"`
+
+	// SocketPermissions sets the octal permissions, e.g. "0660", of the socket file created
+	// for the "unix" transport. The default permissions are kept when empty.
+	SocketPermissions string `mapstructure:"socket_permissions"`
+
 	// TLSSetting struct exposes TLS client configuration.
 	TLSSetting *configtls.TLSServerSetting `mapstructure:"tls_settings, omitempty"`
 
@@ -120,10 +144,47 @@ type HTTPServerSettings struct {
 	// CORS needs to be enabled first by providing a non-empty list in CorsOrigins
 	// A wildcard (*) can be used to match any header.
 	CorsHeaders []string `mapstructure:"cors_allowed_headers"`
+
+	// CorsMaxAge is the number of seconds the browsers cache the results of the preflight
+	// requests for, see the Access-Control-Max-Age header. The header is not sent when zero.
+	CorsMaxAge int `mapstructure:"cors_max_age"`
+
+	// ReadTimeout is the maximum duration for reading the entire request, including the body.
+	// See http.Server.ReadTimeout, there is no timeout when zero.
+	ReadTimeout time.Duration `mapstructure:"read_timeout,omitempty"`
+
+	// ReadHeaderTimeout is the maximum duration for reading the request headers. See
+	// http.Server.ReadHeaderTimeout, the ReadTimeout is used when zero.
+	ReadHeaderTimeout time.Duration `mapstructure:"read_header_timeout,omitempty"`
+
+	// WriteTimeout is the maximum duration before timing out the writes of the response.
+	// See http.Server.WriteTimeout, there is no timeout when zero.
+	WriteTimeout time.Duration `mapstructure:"write_timeout,omitempty"`
+
+	// IdleTimeout is the maximum duration to wait for the next request on keep-alive
+	// connections. See http.Server.IdleTimeout, the ReadTimeout is used when zero.
+	IdleTimeout time.Duration `mapstructure:"idle_timeout,omitempty"`
+
+	// MaxRequestBodySize is the maximum size in bytes of the request bodies, before and after
+	// their decompression. The larger requests are refused with 413 Request Entity Too Large.
+	// There is no limit when zero.
+	MaxRequestBodySize int64 `mapstructure:"max_request_body_size,omitempty"`
+
+	// Middlewares are the full names of the extensions wrapping the handler of the server,
+	// e.g. to rate limit or log the requests, see RegisterServerMiddleware. The first one
+	// serves the requests first, after the CORS handling.
+	Middlewares []string `mapstructure:"middlewares,omitempty"`
+
+	// Auth for this receiver, the requests not authenticated are refused with 401 Unauthorized.
+	Auth *configauth.Authentication `mapstructure:"auth,omitempty"`
 }
 
 func (hss *HTTPServerSettings) ToListener() (net.Listener, error) {
-	listener, err := net.Listen("tcp", hss.Endpoint)
+	addr := confignet.NetAddr{Endpoint: hss.Endpoint, Transport: hss.Transport, AdditionalEndpoints: hss.AdditionalEndpoints}
+	if addr.Transport == "" {
+		addr.Transport = "tcp"
+	}
+	listener, err := addr.ListenWithPermissions(hss.SocketPermissions)
 	if err != nil {
 		return nil, err
 	}
@@ -155,22 +216,53 @@ func WithErrorHandler(e middleware.ErrorHandler) ToServerOption {
 	}
 }
 
-func (hss *HTTPServerSettings) ToServer(handler http.Handler, opts ...ToServerOption) *http.Server {
+func (hss *HTTPServerSettings) ToServer(handler http.Handler, opts ...ToServerOption) (*http.Server, error) {
 	serverOpts := &toServerOptions{}
 	for _, o := range opts {
 		o(serverOpts)
 	}
+	errorHandler := serverOpts.errorHandler
+	if errorHandler == nil {
+		errorHandler = func(w http.ResponseWriter, _ *http.Request, errorMsg string, statusCode int) {
+			http.Error(w, errorMsg, statusCode)
+		}
+	}
+
+	if hss.Auth != nil {
+		// the authentication comes after CORS, the preflight requests not having any credentials
+		var err error
+		if handler, err = hss.Auth.ToHTTPHandler(handler); err != nil {
+			return nil, err
+		}
+	}
+	if len(hss.Middlewares) > 0 {
+		var err error
+		if handler, err = wrapMiddlewares(handler, hss.Middlewares); err != nil {
+			return nil, err
+		}
+	}
 	if len(hss.CorsOrigins) > 0 {
-		co := cors.Options{AllowedOrigins: hss.CorsOrigins, AllowedHeaders: hss.CorsHeaders}
+		co := cors.Options{AllowedOrigins: hss.CorsOrigins, AllowedHeaders: hss.CorsHeaders, MaxAge: hss.CorsMaxAge}
 		handler = cors.New(co).Handler(handler)
 	}
 	// TODO: emit a warning when non-empty CorsHeaders and empty CorsOrigins.
 
+	if hss.MaxRequestBodySize > 0 {
+		// the decompressed body is limited too, compressed bodies can be much larger once decompressed
+		handler = maxBodySizeHandler(handler, hss.MaxRequestBodySize, errorHandler)
+	}
 	handler = middleware.HTTPContentDecompressor(
 		handler,
 		middleware.WithErrorHandler(serverOpts.errorHandler),
 	)
-	return &http.Server{
-		Handler: handler,
+	if hss.MaxRequestBodySize > 0 {
+		handler = maxBodySizeHandler(handler, hss.MaxRequestBodySize, errorHandler)
 	}
+	return &http.Server{
+		Handler:           handler,
+		ReadTimeout:       hss.ReadTimeout,
+		ReadHeaderTimeout: hss.ReadHeaderTimeout,
+		WriteTimeout:      hss.WriteTimeout,
+		IdleTimeout:       hss.IdleTimeout,
+	}, nil
 }
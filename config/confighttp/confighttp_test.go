@@ -15,20 +15,29 @@
 package confighttp
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"path"
+	"runtime"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"go.opentelemetry.io/collector/config/configauth"
+	"go.opentelemetry.io/collector/config/confignet"
 	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/testutil"
 )
 
 func TestAllHTTPClientSettings(t *testing.T) {
@@ -278,10 +287,11 @@ func TestHttpReception(t *testing.T) {
 			}
 			ln, err := hss.ToListener()
 			assert.NoError(t, err)
-			s := hss.ToServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			s, err := hss.ToServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 				_, errWrite := fmt.Fprint(w, "test")
 				assert.NoError(t, errWrite)
 			}))
+			require.NoError(t, err)
 
 			go func() {
 				_ = s.Serve(ln)
@@ -315,6 +325,87 @@ func TestHttpReception(t *testing.T) {
 	}
 }
 
+func TestHttpReceptionOnUnixDomainSocket(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping test on windows")
+	}
+	hss := &HTTPServerSettings{
+		Endpoint:          testutil.TempSocketName(t),
+		Transport:         "unix",
+		SocketPermissions: "0600",
+	}
+	ln, err := hss.ToListener()
+	require.NoError(t, err)
+	s, err := hss.ToServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, errWrite := fmt.Fprint(w, "test")
+		assert.NoError(t, errWrite)
+	}))
+	require.NoError(t, err)
+	go func() {
+		_ = s.Serve(ln)
+	}()
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return (&net.Dialer{}).DialContext(ctx, "unix", hss.Endpoint)
+			},
+		},
+	}
+	resp, err := client.Get("http://unix/")
+	require.NoError(t, err)
+	body, err := ioutil.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+	assert.Equal(t, "test", string(body))
+	require.NoError(t, s.Close())
+}
+
+func TestHttpReceptionOnAdditionalEndpoints(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping test on windows")
+	}
+	socketName := testutil.TempSocketName(t)
+	hss := &HTTPServerSettings{
+		Endpoint:  "127.0.0.1:0",
+		Transport: "tcp",
+		AdditionalEndpoints: []confignet.AdditionalEndpoint{
+			{Endpoint: socketName, Transport: "unix"},
+		},
+	}
+	ln, err := hss.ToListener()
+	require.NoError(t, err)
+	s, err := hss.ToServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, errWrite := fmt.Fprint(w, "test")
+		assert.NoError(t, errWrite)
+	}))
+	require.NoError(t, err)
+	go func() {
+		_ = s.Serve(ln)
+	}()
+
+	for _, addr := range []struct{ network, address string }{
+		{network: "tcp", address: ln.Addr().String()},
+		{network: "unix", address: socketName},
+	} {
+		addr := addr
+		client := &http.Client{
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+					return (&net.Dialer{}).DialContext(ctx, addr.network, addr.address)
+				},
+			},
+		}
+		resp, err := client.Get("http://collector/")
+		require.NoError(t, err)
+		body, err := ioutil.ReadAll(resp.Body)
+		assert.NoError(t, err)
+		assert.NoError(t, resp.Body.Close())
+		assert.Equal(t, "test", string(body))
+	}
+	require.NoError(t, s.Close())
+}
+
 func TestHttpCors(t *testing.T) {
 	tests := []struct {
 		name             string
@@ -358,7 +449,8 @@ func TestHttpCors(t *testing.T) {
 
 			ln, err := hss.ToListener()
 			assert.NoError(t, err)
-			s := hss.ToServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+			s, err := hss.ToServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+			require.NoError(t, err)
 			go func() {
 				_ = s.Serve(ln)
 			}()
@@ -390,7 +482,8 @@ func TestHttpCorsInvalidSettings(t *testing.T) {
 	}
 
 	// This effectively does not enable CORS but should also not cause an error
-	s := hss.ToServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	s, err := hss.ToServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	require.NoError(t, err)
 	require.NotNil(t, s)
 	require.NoError(t, s.Close())
 }
@@ -432,7 +525,10 @@ func ExampleHTTPServerSettings() {
 	settings := HTTPServerSettings{
 		Endpoint: ":443",
 	}
-	s := settings.ToServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	s, err := settings.ToServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	if err != nil {
+		panic(err)
+	}
 	l, err := settings.ToListener()
 	if err != nil {
 		panic(err)
@@ -481,3 +577,224 @@ func TestHttpHeaders(t *testing.T) {
 		})
 	}
 }
+
+type fakeClientAuthenticator struct{}
+
+func (fakeClientAuthenticator) RequestHeaders(context.Context) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer token"}, nil
+}
+
+func TestHttpClientAuth(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		w.WriteHeader(200)
+	}))
+	defer server.Close()
+
+	setting := HTTPClientSettings{
+		Endpoint: server.URL,
+		Auth:     &configauth.ClientAuthentication{AuthenticatorName: "fake"},
+	}
+	_, err := setting.ToClient()
+	assert.Error(t, err)
+
+	unregister, err := configauth.RegisterClientAuthenticator("fake", fakeClientAuthenticator{})
+	require.NoError(t, err)
+	defer unregister()
+	client, err := setting.ToClient()
+	require.NoError(t, err)
+	resp, err := client.Get(setting.Endpoint)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, 200, resp.StatusCode)
+}
+
+func TestHttpServerAuth(t *testing.T) {
+	hss := &HTTPServerSettings{
+		Endpoint: "localhost:0",
+		Auth: &configauth.Authentication{
+			Basic: &configauth.BasicAuth{Users: map[string]string{"collector": "secret"}},
+		},
+	}
+	ln, err := hss.ToListener()
+	require.NoError(t, err)
+	s, err := hss.ToServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		subject, _ := configauth.SubjectFromContext(r.Context())
+		_, errWrite := fmt.Fprint(w, subject)
+		assert.NoError(t, errWrite)
+	}))
+	require.NoError(t, err)
+	go func() {
+		_ = s.Serve(ln)
+	}()
+	defer s.Close()
+
+	url := fmt.Sprintf("http://%s", ln.Addr().String())
+	resp, err := http.Post(url, "application/json", nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	req, err := http.NewRequest(http.MethodPost, url, nil)
+	require.NoError(t, err)
+	req.SetBasicAuth("collector", "secret")
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "collector", string(body))
+}
+
+func TestHttpServerInvalidAuth(t *testing.T) {
+	hss := &HTTPServerSettings{
+		Endpoint: "localhost:0",
+		Auth:     &configauth.Authentication{},
+	}
+	s, err := hss.ToServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	assert.Error(t, err)
+	assert.Nil(t, s)
+}
+
+func TestHttpServerTimeouts(t *testing.T) {
+	hss := &HTTPServerSettings{
+		Endpoint:          "localhost:0",
+		ReadTimeout:       time.Second,
+		ReadHeaderTimeout: 2 * time.Second,
+		WriteTimeout:      3 * time.Second,
+		IdleTimeout:       4 * time.Second,
+	}
+	s, err := hss.ToServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	require.NoError(t, err)
+	assert.Equal(t, time.Second, s.ReadTimeout)
+	assert.Equal(t, 2*time.Second, s.ReadHeaderTimeout)
+	assert.Equal(t, 3*time.Second, s.WriteTimeout)
+	assert.Equal(t, 4*time.Second, s.IdleTimeout)
+}
+
+func TestHttpServerMaxRequestBodySize(t *testing.T) {
+	hss := &HTTPServerSettings{
+		Endpoint:           "localhost:0",
+		MaxRequestBodySize: 10,
+	}
+	s, err := hss.ToServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := ioutil.ReadAll(r.Body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+	}))
+	require.NoError(t, err)
+
+	var compressed bytes.Buffer
+	gw := gzip.NewWriter(&compressed)
+	_, err = gw.Write(bytes.Repeat([]byte("a"), 100))
+	require.NoError(t, err)
+	require.NoError(t, gw.Close())
+	require.Less(t, compressed.Len(), 100)
+
+	tests := []struct {
+		name     string
+		request  func() *http.Request
+		expected int
+	}{
+		{
+			name: "within the limit",
+			request: func() *http.Request {
+				return httptest.NewRequest(http.MethodPost, "/", strings.NewReader("0123456789"))
+			},
+			expected: http.StatusOK,
+		},
+		{
+			name: "content length above the limit",
+			request: func() *http.Request {
+				return httptest.NewRequest(http.MethodPost, "/", strings.NewReader("0123456789a"))
+			},
+			expected: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name: "unknown length above the limit",
+			request: func() *http.Request {
+				req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("0123456789a"))
+				req.ContentLength = -1
+				return req
+			},
+			expected: http.StatusBadRequest,
+		},
+		{
+			name: "decompressed body above the limit",
+			request: func() *http.Request {
+				req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(compressed.Bytes()))
+				req.ContentLength = -1
+				req.Header.Set("Content-Encoding", "gzip")
+				return req
+			},
+			expected: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			s.Handler.ServeHTTP(rec, tt.request())
+			assert.Equal(t, tt.expected, rec.Code)
+		})
+	}
+}
+
+func TestHttpCorsMaxAge(t *testing.T) {
+	hss := &HTTPServerSettings{
+		Endpoint:    "localhost:0",
+		CorsOrigins: []string{"allowed-*.com"},
+		CorsMaxAge:  7200,
+	}
+	s, err := hss.ToServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodOptions, "/", nil)
+	req.Header.Set("Origin", "allowed-origin.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	rec := httptest.NewRecorder()
+	s.Handler.ServeHTTP(rec, req)
+	assert.Equal(t, "7200", rec.Header().Get("Access-Control-Max-Age"))
+}
+
+type headerMiddleware struct {
+	value string
+}
+
+func (m headerMiddleware) WrapHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("X-Middleware", m.value)
+		next.ServeHTTP(w, r)
+	})
+}
+
+func TestHttpServerMiddlewares(t *testing.T) {
+	hss := &HTTPServerSettings{
+		Endpoint:    "localhost:0",
+		Middlewares: []string{"first", "second"},
+	}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("X-Middleware", "handler")
+	})
+	_, err := hss.ToServer(handler)
+	assert.EqualError(t, err, `the middleware "first" is not found, it must be an extension of the service`)
+
+	unregisterFirst, err := RegisterServerMiddleware("first", headerMiddleware{value: "first"})
+	require.NoError(t, err)
+	defer unregisterFirst()
+	unregisterSecond, err := RegisterServerMiddleware("second", headerMiddleware{value: "second"})
+	require.NoError(t, err)
+	defer unregisterSecond()
+	_, err = RegisterServerMiddleware("second", headerMiddleware{})
+	assert.EqualError(t, err, `the middleware "second" is already registered`)
+
+	s, err := hss.ToServer(handler)
+	require.NoError(t, err)
+	rec := httptest.NewRecorder()
+	s.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", nil))
+	assert.Equal(t, []string{"first", "second", "handler"}, rec.Header().Values("X-Middleware"))
+
+	unregisterSecond()
+	_, err = hss.ToServer(handler)
+	assert.Error(t, err)
+}
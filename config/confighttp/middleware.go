@@ -0,0 +1,91 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package confighttp
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+
+	"go.opentelemetry.io/collector/internal/middleware"
+)
+
+// ServerMiddleware wraps the handlers of the HTTP servers, e.g. the rate
+// limiting or the logging of the requests of an extension.
+type ServerMiddleware interface {
+	// WrapHandler returns the handler serving the requests before, or instead of, the next one.
+	WrapHandler(next http.Handler) http.Handler
+}
+
+var (
+	serverMiddlewaresMu sync.RWMutex
+	serverMiddlewares   = make(map[string]*registeredMiddleware)
+)
+
+// registeredMiddleware identifies a registration, the middlewares may not be comparable.
+type registeredMiddleware struct {
+	middleware ServerMiddleware
+}
+
+// RegisterServerMiddleware makes the middleware available to the servers under the given
+// name, usually the full name of the extension. It returns the function unregistering it.
+func RegisterServerMiddleware(name string, serverMiddleware ServerMiddleware) (func(), error) {
+	serverMiddlewaresMu.Lock()
+	defer serverMiddlewaresMu.Unlock()
+	if _, ok := serverMiddlewares[name]; ok {
+		return nil, fmt.Errorf("the middleware %q is already registered", name)
+	}
+	registered := &registeredMiddleware{middleware: serverMiddleware}
+	serverMiddlewares[name] = registered
+	return func() {
+		serverMiddlewaresMu.Lock()
+		defer serverMiddlewaresMu.Unlock()
+		if serverMiddlewares[name] == registered {
+			delete(serverMiddlewares, name)
+		}
+	}, nil
+}
+
+// wrapMiddlewares wraps the handler with the middlewares, the first one serving the requests
+// first. The extensions are started before the servers are created.
+func wrapMiddlewares(handler http.Handler, names []string) (http.Handler, error) {
+	serverMiddlewaresMu.RLock()
+	defer serverMiddlewaresMu.RUnlock()
+	middlewares := make([]ServerMiddleware, len(names))
+	for i, name := range names {
+		registered, ok := serverMiddlewares[name]
+		if !ok {
+			return nil, fmt.Errorf("the middleware %q is not found, it must be an extension of the service", name)
+		}
+		middlewares[i] = registered.middleware
+	}
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i].WrapHandler(handler)
+	}
+	return handler, nil
+}
+
+// maxBodySizeHandler refuses the requests whose body is larger than the limit with 413
+// Request Entity Too Large, the reads beyond the limit failing for the bodies of unknown size.
+func maxBodySizeHandler(next http.Handler, limit int64, errorHandler middleware.ErrorHandler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ContentLength > limit {
+			errorHandler(w, r, fmt.Sprintf("the request body is larger than %d bytes", limit), http.StatusRequestEntityTooLarge)
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, limit)
+		next.ServeHTTP(w, r)
+	})
+}
@@ -74,7 +74,12 @@ func (cfg *Config) Validate() error {
 
 	// Check that all pipelines have at least one receiver and one exporter, and they reference
 	// only configured components.
-	return cfg.validateServicePipelines()
+	if err := cfg.validateServicePipelines(); err != nil {
+		return err
+	}
+
+	// Check the settings of the components implementing ConfigValidator.
+	return cfg.validateComponents()
 }
 
 func (cfg *Config) validateServiceExtensions() error {
@@ -134,6 +139,47 @@ func (cfg *Config) validateServicePipelines() error {
 	return nil
 }
 
+// validateComponents validates the components used by the service, the other ones are not created.
+func (cfg *Config) validateComponents() error {
+	for _, ref := range cfg.Service.Extensions {
+		if err := validateComponent(cfg.Extensions[ref]); err != nil {
+			return fmt.Errorf("extension %q has invalid configuration: %w", ref, err)
+		}
+	}
+	for _, pipeline := range cfg.Service.Pipelines {
+		for _, ref := range pipeline.Receivers {
+			if err := validateComponent(cfg.Receivers[ref]); err != nil {
+				return fmt.Errorf("receiver %q has invalid configuration: %w", ref, err)
+			}
+		}
+		for _, ref := range pipeline.Processors {
+			if err := validateComponent(cfg.Processors[ref]); err != nil {
+				return fmt.Errorf("processor %q has invalid configuration: %w", ref, err)
+			}
+		}
+		for _, ref := range pipeline.Exporters {
+			if err := validateComponent(cfg.Exporters[ref]); err != nil {
+				return fmt.Errorf("exporter %q has invalid configuration: %w", ref, err)
+			}
+		}
+	}
+	return nil
+}
+
+func validateComponent(cfg interface{}) error {
+	if v, ok := cfg.(ConfigValidator); ok {
+		return v.Validate()
+	}
+	return nil
+}
+
+// ConfigValidator is implemented by the configurations of the components validating their
+// settings, Validate is called when the configuration of the collector is validated.
+type ConfigValidator interface {
+	// Validate returns an error if the settings are invalid.
+	Validate() error
+}
+
 // Service defines the configurable components of the service.
 type Service struct {
 	// Extensions is the ordered list of extensions configured for the service.
@@ -176,6 +222,10 @@ type Pipeline struct {
 	Receivers  []string
 	Processors []string
 	Exporters  []string
+	// Backpressure propagates the errors of the exporters refusing the data, e.g. because their
+	// queue is full, back through the processors to the receivers, so that the receivers refuse
+	// the data with a retryable status instead of the data being dropped in the pipeline.
+	Backpressure bool
 }
 
 // Pipelines is a map of names to Pipelines.
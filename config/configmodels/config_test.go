@@ -128,6 +128,29 @@ func TestConfigValidate(t *testing.T) {
 	}
 }
 
+type validatedReceiver struct {
+	ReceiverSettings
+	err error
+}
+
+func (r *validatedReceiver) Validate() error {
+	return r.err
+}
+
+func TestConfigValidateComponents(t *testing.T) {
+	cfg := generateConfig()
+	cfg.Receivers["nop/2"] = &validatedReceiver{
+		ReceiverSettings: ReceiverSettings{TypeVal: "nop", NameVal: "nop/2"},
+		err:              errors.New("invalid endpoint"),
+	}
+	// The components not used by the pipelines are not validated.
+	assert.NoError(t, cfg.Validate())
+
+	pipe := cfg.Service.Pipelines["traces"]
+	pipe.Receivers = append(pipe.Receivers, "nop/2")
+	assert.EqualError(t, cfg.Validate(), `receiver "nop/2" has invalid configuration: invalid endpoint`)
+}
+
 func generateConfig() *Config {
 	return &Config{
 		Receivers: map[string]Receiver{
@@ -15,7 +15,10 @@
 package confignet
 
 import (
+	"fmt"
 	"net"
+	"os"
+	"strconv"
 )
 
 // NetAddr represents a network endpoint address.
@@ -30,14 +33,97 @@ type NetAddr struct {
 	// Transport to use. Known protocols are "tcp", "tcp4" (IPv4-only), "tcp6" (IPv6-only), "udp", "udp4" (IPv4-only),
 	// "udp6" (IPv6-only), "ip", "ip4" (IPv4-only), "ip6" (IPv6-only), "unix", "unixgram" and "unixpacket".
 	Transport string `mapstructure:"transport"`
+
+	// AdditionalEndpoints are the other addresses to listen on, e.g. an IPv6 address next to an
+	// IPv4 one or a unix socket next to a TCP address. Only used by Listen and
+	// ListenWithPermissions for the stream transports.
+	AdditionalEndpoints []AdditionalEndpoint `mapstructure:"additional_endpoints,omitempty"`
+}
+
+// AdditionalEndpoint is an address to listen on in addition to the Endpoint of a NetAddr.
+type AdditionalEndpoint struct {
+	// Endpoint configures the address, with the same syntax as NetAddr.Endpoint.
+	Endpoint string `mapstructure:"endpoint"`
+
+	// Transport to use, the Transport of the NetAddr when empty.
+	Transport string `mapstructure:"transport"`
 }
 
 func (na *NetAddr) Dial() (net.Conn, error) {
 	return net.Dial(na.Transport, na.Endpoint)
 }
 
+// Listen listens on the address, and on the AdditionalEndpoints if any, the returned listener
+// accepting the connections of all of them.
 func (na *NetAddr) Listen() (net.Listener, error) {
-	return net.Listen(na.Transport, na.Endpoint)
+	return na.listen(nil)
+}
+
+// ListenWithPermissions is like Listen, and additionally sets the permissions of the socket
+// files of the "unix" and "unixpacket" transports to the given octal permissions, e.g. "0660".
+// The default permissions are kept when permissions is empty.
+func (na *NetAddr) ListenWithPermissions(permissions string) (net.Listener, error) {
+	if permissions == "" {
+		return na.Listen()
+	}
+	hasSocketFile := false
+	for _, addr := range na.addrs() {
+		hasSocketFile = hasSocketFile || isSocketFileTransport(addr.Transport)
+	}
+	if !hasSocketFile {
+		return nil, fmt.Errorf("socket permissions require a unix transport, got %q", na.Transport)
+	}
+	mode, err := strconv.ParseUint(permissions, 8, 32)
+	if err != nil || mode > 0777 {
+		return nil, fmt.Errorf("invalid socket permissions %q, expecting octal permissions such as \"0660\"", permissions)
+	}
+	fileMode := os.FileMode(mode)
+	return na.listen(&fileMode)
+}
+
+// addrs returns this address followed by the additional ones.
+func (na *NetAddr) addrs() []NetAddr {
+	addrs := []NetAddr{{Endpoint: na.Endpoint, Transport: na.Transport}}
+	for _, addr := range na.AdditionalEndpoints {
+		if addr.Transport == "" {
+			addr.Transport = na.Transport
+		}
+		addrs = append(addrs, NetAddr{Endpoint: addr.Endpoint, Transport: addr.Transport})
+	}
+	return addrs
+}
+
+func isSocketFileTransport(transport string) bool {
+	return transport == "unix" || transport == "unixpacket"
+}
+
+// listen listens on all the addresses, setting the permissions of the socket files when mode
+// is not nil.
+func (na *NetAddr) listen(mode *os.FileMode) (net.Listener, error) {
+	var listeners []net.Listener
+	closeAll := func() {
+		for _, ln := range listeners {
+			ln.Close()
+		}
+	}
+	for _, addr := range na.addrs() {
+		ln, err := net.Listen(addr.Transport, addr.Endpoint)
+		if err != nil {
+			closeAll()
+			return nil, err
+		}
+		listeners = append(listeners, ln)
+		if mode != nil && isSocketFileTransport(addr.Transport) {
+			if err := os.Chmod(addr.Endpoint, *mode); err != nil {
+				closeAll()
+				return nil, fmt.Errorf("failed to set the permissions of socket %q: %w", addr.Endpoint, err)
+			}
+		}
+	}
+	if len(listeners) == 1 {
+		return listeners[0], nil
+	}
+	return newMultiListener(listeners), nil
 }
 
 // TCPAddr represents a tcp endpoint address.
@@ -15,10 +15,16 @@
 package confignet
 
 import (
+	"io"
 	"net"
+	"os"
+	"runtime"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/testutil"
 )
 
 func TestNetAddr(t *testing.T) {
@@ -88,3 +94,135 @@ func TestTcpAddr(t *testing.T) {
 	<-done
 	assert.NoError(t, ln.Close())
 }
+
+func TestListenWithPermissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping test on windows")
+	}
+	nas := &NetAddr{
+		Endpoint:  testutil.TempSocketName(t),
+		Transport: "unix",
+	}
+	ln, err := nas.ListenWithPermissions("0600")
+	require.NoError(t, err)
+	fi, err := os.Stat(nas.Endpoint)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), fi.Mode().Perm())
+	assert.NoError(t, ln.Close())
+}
+
+func TestListenWithPermissionsError(t *testing.T) {
+	tests := []struct {
+		name        string
+		addr        NetAddr
+		permissions string
+	}{
+		{
+			name:        "tcp",
+			addr:        NetAddr{Endpoint: "localhost:0", Transport: "tcp"},
+			permissions: "0660",
+		},
+		{
+			name:        "not_octal",
+			addr:        NetAddr{Endpoint: "unused", Transport: "unix"},
+			permissions: "rw-rw----",
+		},
+		{
+			name:        "too_large",
+			addr:        NetAddr{Endpoint: "unused", Transport: "unix"},
+			permissions: "01777",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ln, err := tt.addr.ListenWithPermissions(tt.permissions)
+			assert.Error(t, err)
+			assert.Nil(t, ln)
+		})
+	}
+}
+
+func TestListenAdditionalEndpoints(t *testing.T) {
+	nas := &NetAddr{
+		Endpoint:  "127.0.0.1:0",
+		Transport: "tcp",
+		AdditionalEndpoints: []AdditionalEndpoint{
+			{Endpoint: "127.0.0.1:0"},
+		},
+	}
+	if runtime.GOOS != "windows" {
+		nas.AdditionalEndpoints = append(nas.AdditionalEndpoints, AdditionalEndpoint{Endpoint: testutil.TempSocketName(t), Transport: "unix"})
+	}
+	ln, err := nas.Listen()
+	require.NoError(t, err)
+	addrs := ln.(*multiListener).Addrs()
+	require.Len(t, addrs, len(nas.AdditionalEndpoints)+1)
+	assert.Equal(t, addrs[0], ln.Addr())
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			_, _ = conn.Write([]byte("ok"))
+			conn.Close()
+		}
+	}()
+
+	for _, addr := range addrs {
+		conn, err := net.Dial(addr.Network(), addr.String())
+		require.NoError(t, err)
+		buf := make([]byte, 2)
+		_, err = io.ReadFull(conn, buf)
+		require.NoError(t, err)
+		assert.Equal(t, "ok", string(buf))
+		conn.Close()
+	}
+
+	assert.NoError(t, ln.Close())
+	<-done
+	for _, addr := range addrs {
+		_, err := net.Dial(addr.Network(), addr.String())
+		assert.Error(t, err)
+	}
+}
+
+func TestListenAdditionalEndpointsError(t *testing.T) {
+	taken, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer taken.Close()
+
+	nas := &NetAddr{
+		Endpoint:  "127.0.0.1:0",
+		Transport: "tcp",
+		AdditionalEndpoints: []AdditionalEndpoint{
+			{Endpoint: taken.Addr().String()},
+		},
+	}
+	ln, err := nas.Listen()
+	assert.Error(t, err)
+	assert.Nil(t, ln)
+}
+
+func TestListenWithPermissionsAdditionalEndpoints(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping test on windows")
+	}
+	socket := testutil.TempSocketName(t)
+	nas := &NetAddr{
+		Endpoint:  "127.0.0.1:0",
+		Transport: "tcp",
+		AdditionalEndpoints: []AdditionalEndpoint{
+			{Endpoint: socket, Transport: "unix"},
+		},
+	}
+	ln, err := nas.ListenWithPermissions("0600")
+	require.NoError(t, err)
+	fi, err := os.Stat(socket)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), fi.Mode().Perm())
+	assert.NoError(t, ln.Close())
+}
@@ -0,0 +1,110 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package confignet
+
+import (
+	"errors"
+	"net"
+	"sync"
+)
+
+var errListenerClosed = errors.New("use of closed network connection")
+
+type acceptResult struct {
+	conn net.Conn
+	err  error
+}
+
+// multiListener accepts the connections of several listeners, e.g. to serve
+// both an IPv4 and an IPv6 address with a single server. Its address is the
+// one of the first listener.
+type multiListener struct {
+	listeners []net.Listener
+	accepted  chan acceptResult
+	closed    chan struct{}
+	closeOnce sync.Once
+	closeErr  error
+}
+
+func newMultiListener(listeners []net.Listener) *multiListener {
+	ml := &multiListener{
+		listeners: listeners,
+		accepted:  make(chan acceptResult),
+		closed:    make(chan struct{}),
+	}
+	for _, ln := range listeners {
+		go ml.acceptLoop(ln)
+	}
+	return ml
+}
+
+func (ml *multiListener) acceptLoop(ln net.Listener) {
+	for {
+		conn, err := ln.Accept()
+		select {
+		case ml.accepted <- acceptResult{conn: conn, err: err}:
+		case <-ml.closed:
+			if conn != nil {
+				conn.Close()
+			}
+			return
+		}
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Temporary() {
+				// the servers retry the temporary errors
+				continue
+			}
+			return
+		}
+	}
+}
+
+// Accept returns the next connection accepted by any of the listeners, or
+// their first error other than a temporary one.
+func (ml *multiListener) Accept() (net.Conn, error) {
+	select {
+	case res := <-ml.accepted:
+		return res.conn, res.err
+	case <-ml.closed:
+		return nil, errListenerClosed
+	}
+}
+
+// Close closes all the listeners.
+func (ml *multiListener) Close() error {
+	ml.closeOnce.Do(func() {
+		close(ml.closed)
+		for _, ln := range ml.listeners {
+			if err := ln.Close(); err != nil && ml.closeErr == nil {
+				ml.closeErr = err
+			}
+		}
+	})
+	return ml.closeErr
+}
+
+func (ml *multiListener) Addr() net.Addr {
+	return ml.listeners[0].Addr()
+}
+
+// Addrs returns the addresses of all the listeners.
+func (ml *multiListener) Addrs() []net.Addr {
+	addrs := make([]net.Addr, len(ml.listeners))
+	for i, ln := range ml.listeners {
+		addrs[i] = ln.Addr()
+	}
+	return addrs
+}
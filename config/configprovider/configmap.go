@@ -0,0 +1,94 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configprovider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+var errInvalidConfigMapURI = errors.New("ConfigMap config URI must be configmap://namespace/name[/key]")
+
+// configMapProvider retrieves the configuration from a key of a Kubernetes ConfigMap.
+type configMapProvider struct {
+	namespace string
+	name      string
+	key       string
+	client    kubernetes.Interface
+}
+
+func newConfigMapProvider(u *url.URL) (*configMapProvider, error) {
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if u.Host == "" || parts[0] == "" || len(parts) > 2 {
+		return nil, errInvalidConfigMapURI
+	}
+	client, err := newClientset()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the Kubernetes client: %w", err)
+	}
+	p := &configMapProvider{
+		namespace: u.Host,
+		name:      parts[0],
+		client:    client,
+	}
+	if len(parts) == 2 {
+		p.key = parts[1]
+	}
+	return p, nil
+}
+
+// newClientset returns a clientset authenticated with the service account of the pod
+// of the collector, or with the kubeconfig file of the user when running outside of
+// a cluster.
+func newClientset() (kubernetes.Interface, error) {
+	cfg, err := rest.InClusterConfig()
+	if errors.Is(err, rest.ErrNotInCluster) {
+		cfg, err = clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+			clientcmd.NewDefaultClientConfigLoadingRules(), &clientcmd.ConfigOverrides{}).ClientConfig()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return kubernetes.NewForConfig(cfg)
+}
+
+func (p *configMapProvider) Retrieve(ctx context.Context) ([]byte, error) {
+	cm, err := p.client.CoreV1().ConfigMaps(p.namespace).Get(ctx, p.name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	key := p.key
+	if key == "" {
+		if len(cm.Data) != 1 {
+			return nil, fmt.Errorf("the key must be set as ConfigMap %s/%s has %d keys", p.namespace, p.name, len(cm.Data))
+		}
+		for k := range cm.Data {
+			key = k
+		}
+	}
+	content, ok := cm.Data[key]
+	if !ok {
+		return nil, fmt.Errorf("ConfigMap %s/%s has no key %q", p.namespace, p.name, key)
+	}
+	return []byte(content), nil
+}
@@ -0,0 +1,64 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configprovider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestConfigMapProvider(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "observability", Name: "single"},
+			Data:       map[string]string{"config.yaml": testConfig},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "observability", Name: "multiple"},
+			Data:       map[string]string{"agent.yaml": "", "collector.yaml": testConfig},
+		},
+	)
+
+	tests := []struct {
+		name      string
+		configMap string
+		key       string
+		errMsg    string
+	}{
+		{name: "key", configMap: "multiple", key: "collector.yaml"},
+		{name: "single key", configMap: "single"},
+		{name: "missing key", configMap: "single", key: "other.yaml", errMsg: `ConfigMap observability/single has no key "other.yaml"`},
+		{name: "several keys", configMap: "multiple", errMsg: "the key must be set as ConfigMap observability/multiple has 2 keys"},
+		{name: "missing ConfigMap", configMap: "missing", errMsg: `configmaps "missing" not found`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &configMapProvider{namespace: "observability", name: tt.configMap, key: tt.key, client: client}
+			content, err := p.Retrieve(context.Background())
+			if tt.errMsg != "" {
+				assert.EqualError(t, err, tt.errMsg)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, testConfig, string(content))
+		})
+	}
+}
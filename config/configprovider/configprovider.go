@@ -0,0 +1,90 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package configprovider implements the retrieval of the configuration of the collector
+// from remote sources: HTTP(S) servers, S3 buckets and Kubernetes ConfigMaps.
+package configprovider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"go.opentelemetry.io/collector/config/configtls"
+)
+
+const (
+	schemeHTTP      = "http"
+	schemeHTTPS     = "https"
+	schemeS3        = "s3"
+	schemeConfigMap = "configmap"
+
+	defaultTimeout = 30 * time.Second
+)
+
+// Provider retrieves the configuration of the collector from a source.
+type Provider interface {
+	// Retrieve returns the configuration, a YAML document.
+	Retrieve(ctx context.Context) ([]byte, error)
+}
+
+// Settings configures the retrieval of the remote configurations.
+type Settings struct {
+	// TLSSetting configures the TLS connections to the HTTPS servers.
+	TLSSetting configtls.TLSClientSetting
+
+	// Headers are added to the requests to the HTTP(S) servers, e.g. to authenticate
+	// with the "Authorization" header.
+	Headers map[string]string
+
+	// Timeout is the timeout of the requests to the HTTP(S) servers, 30s by default.
+	Timeout time.Duration
+}
+
+// IsRemote returns true if the configuration at uri is retrieved by a Provider, false
+// if it is a local file or directory.
+func IsRemote(uri string) bool {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return false
+	}
+	switch u.Scheme {
+	case schemeHTTP, schemeHTTPS, schemeS3, schemeConfigMap:
+		return true
+	}
+	return false
+}
+
+// New returns the Provider retrieving the configuration at uri:
+//   - "http://host/path" and "https://host/path" are retrieved with a GET request.
+//   - "s3://bucket/key" is retrieved from S3, the region being the one of the AWS
+//     configuration of the environment unless set with the "region" query parameter.
+//   - "configmap://namespace/name/key" is retrieved from the key of a Kubernetes ConfigMap,
+//     the key can be omitted if the ConfigMap has a single key.
+func New(uri string, settings Settings) (Provider, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config URI %q: %w", uri, err)
+	}
+	switch u.Scheme {
+	case schemeHTTP, schemeHTTPS:
+		return newHTTPProvider(u, settings)
+	case schemeS3:
+		return newS3Provider(u)
+	case schemeConfigMap:
+		return newConfigMapProvider(u)
+	}
+	return nil, fmt.Errorf("unsupported config URI scheme %q", u.Scheme)
+}
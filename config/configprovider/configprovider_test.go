@@ -0,0 +1,63 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configprovider
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsRemote(t *testing.T) {
+	assert.True(t, IsRemote("http://localhost:8080/config.yaml"))
+	assert.True(t, IsRemote("https://localhost:8080/config.yaml"))
+	assert.True(t, IsRemote("s3://bucket/config.yaml"))
+	assert.True(t, IsRemote("configmap://namespace/name/config.yaml"))
+	assert.False(t, IsRemote("config.yaml"))
+	assert.False(t, IsRemote("/etc/otelcol/config.yaml"))
+	assert.False(t, IsRemote(`C:\otelcol\config.yaml`))
+	assert.False(t, IsRemote("ftp://localhost/config.yaml"))
+}
+
+func TestNew(t *testing.T) {
+	p, err := New("https://localhost:8080/config.yaml", Settings{})
+	require.NoError(t, err)
+	assert.IsType(t, &httpProvider{}, p)
+
+	p, err = New("s3://bucket/path/config.yaml?region=us-west-2", Settings{})
+	require.NoError(t, err)
+	require.IsType(t, &s3Provider{}, p)
+	assert.Equal(t, "bucket", p.(*s3Provider).bucket)
+	assert.Equal(t, "path/config.yaml", p.(*s3Provider).key)
+}
+
+func TestNewInvalid(t *testing.T) {
+	tests := []struct {
+		uri    string
+		errMsg string
+	}{
+		{uri: "ftp://localhost/config.yaml", errMsg: `unsupported config URI scheme "ftp"`},
+		{uri: "s3://bucket", errMsg: errInvalidS3URI.Error()},
+		{uri: "configmap://namespace", errMsg: errInvalidConfigMapURI.Error()},
+		{uri: "configmap://namespace/name/key/other", errMsg: errInvalidConfigMapURI.Error()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.uri, func(t *testing.T) {
+			_, err := New(tt.uri, Settings{})
+			assert.EqualError(t, err, tt.errMsg)
+		})
+	}
+}
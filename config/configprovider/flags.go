@@ -0,0 +1,88 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configprovider
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	caFileCfg          = "config-ca-file"
+	certFileCfg        = "config-cert-file"
+	keyFileCfg         = "config-key-file"
+	headerCfg          = "config-header"
+	refreshIntervalCfg = "config-refresh-interval"
+)
+
+var (
+	caFile          = new(string)
+	certFile        = new(string)
+	keyFile         = new(string)
+	headers         = new(headersFlag)
+	refreshInterval = new(time.Duration)
+)
+
+// Flags is a helper func, to add the flags configuring the retrieval of the remote
+// configurations to the service that exposes the application flags.
+func Flags(flags *flag.FlagSet) {
+	flags.StringVar(caFile, caFileCfg, "", "Path to the CA cert verifying the servers of the remote configurations")
+	flags.StringVar(certFile, certFileCfg, "", "Path to the TLS cert authenticating the collector to the servers of the remote configurations")
+	flags.StringVar(keyFile, keyFileCfg, "", "Path to the TLS key authenticating the collector to the servers of the remote configurations")
+	*headers = nil
+	flags.Var(headers, headerCfg, "Header added to the requests of the remote configurations over HTTP(S), in the name=value format, can be repeated")
+	flags.DurationVar(refreshInterval, refreshIntervalCfg, 0, "Interval between two retrievals of the configuration, the changes being applied without restarting the collector. The configuration is only loaded at startup when not set")
+}
+
+// GetFlagSettings returns the Settings from the flags.
+func GetFlagSettings() Settings {
+	var s Settings
+	s.TLSSetting.CAFile = *caFile
+	s.TLSSetting.CertFile = *certFile
+	s.TLSSetting.KeyFile = *keyFile
+	s.Headers = *headers
+	return s
+}
+
+// GetRefreshInterval returns the interval between two retrievals of the configuration
+// from the flags, zero if the configuration is only loaded at startup.
+func GetRefreshInterval() time.Duration {
+	return *refreshInterval
+}
+
+// headersFlag is a flag that can be repeated, each flag adding a header.
+type headersFlag map[string]string
+
+func (h *headersFlag) String() string {
+	var headers []string
+	for k, v := range *h {
+		headers = append(headers, k+"="+v)
+	}
+	return strings.Join(headers, ",")
+}
+
+func (h *headersFlag) Set(value string) error {
+	idx := strings.Index(value, "=")
+	if idx <= 0 {
+		return fmt.Errorf("invalid header %q, the format is name=value", value)
+	}
+	if *h == nil {
+		*h = map[string]string{}
+	}
+	(*h)[value[:idx]] = value[idx+1:]
+	return nil
+}
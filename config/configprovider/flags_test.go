@@ -0,0 +1,44 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configprovider
+
+import (
+	"flag"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlags(t *testing.T) {
+	flags := new(flag.FlagSet)
+	Flags(flags)
+	require.NoError(t, flags.Parse([]string{
+		"--config-ca-file=ca.pem",
+		"--config-header=Authorization=Bearer a=b",
+		"--config-header=X-Scope=collector",
+		"--config-refresh-interval=1m",
+	}))
+
+	settings := GetFlagSettings()
+	assert.Equal(t, "ca.pem", settings.TLSSetting.CAFile)
+	assert.Equal(t, map[string]string{"Authorization": "Bearer a=b", "X-Scope": "collector"}, settings.Headers)
+	assert.Equal(t, time.Minute, GetRefreshInterval())
+
+	flags = new(flag.FlagSet)
+	Flags(flags)
+	assert.Error(t, flags.Parse([]string{"--config-header=Authorization"}))
+}
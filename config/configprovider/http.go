@@ -0,0 +1,71 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configprovider
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+)
+
+// httpProvider retrieves the configuration from an HTTP(S) server.
+type httpProvider struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+func newHTTPProvider(u *url.URL, settings Settings) (*httpProvider, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	tlsCfg, err := settings.TLSSetting.LoadTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	transport.TLSClientConfig = tlsCfg
+
+	timeout := settings.Timeout
+	if timeout == 0 {
+		timeout = defaultTimeout
+	}
+	return &httpProvider{
+		url:     u.String(),
+		headers: settings.Headers,
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   timeout,
+		},
+	}, nil
+}
+
+func (p *httpProvider) Retrieve(ctx context.Context) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range p.headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to retrieve the configuration, status: %s", resp.Status)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
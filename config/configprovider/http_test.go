@@ -0,0 +1,87 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configprovider
+
+import (
+	"context"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/config/configtls"
+)
+
+const testConfig = "receivers:\n  otlp:\n"
+
+func TestHTTPProvider(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(testConfig))
+	}))
+	defer server.Close()
+
+	caFile, err := ioutil.TempFile("", "ca")
+	require.NoError(t, err)
+	defer os.Remove(caFile.Name())
+	require.NoError(t, pem.Encode(caFile, &pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+	require.NoError(t, caFile.Close())
+
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	settings := Settings{
+		TLSSetting: configtls.TLSClientSetting{
+			TLSSetting: configtls.TLSSetting{CAFile: caFile.Name()},
+		},
+		Headers: map[string]string{"Authorization": "Bearer token"},
+	}
+	p, err := newHTTPProvider(u, settings)
+	require.NoError(t, err)
+	content, err := p.Retrieve(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, testConfig, string(content))
+
+	settings.Headers = nil
+	p, err = newHTTPProvider(u, settings)
+	require.NoError(t, err)
+	_, err = p.Retrieve(context.Background())
+	assert.EqualError(t, err, "failed to retrieve the configuration, status: 401 Unauthorized")
+
+	// The server is not trusted without the CA.
+	p, err = newHTTPProvider(u, Settings{})
+	require.NoError(t, err)
+	_, err = p.Retrieve(context.Background())
+	assert.Error(t, err)
+}
+
+func TestHTTPProviderInvalidTLS(t *testing.T) {
+	u, err := url.Parse("https://localhost/config.yaml")
+	require.NoError(t, err)
+	_, err = newHTTPProvider(u, Settings{
+		TLSSetting: configtls.TLSClientSetting{
+			TLSSetting: configtls.TLSSetting{CAFile: "missing.pem"},
+		},
+	})
+	assert.Error(t, err)
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"path/filepath"
+	"time"
 )
 
 // TLSSetting exposes the common client and server TLS configurations.
@@ -34,6 +35,13 @@ type TLSSetting struct {
 	CertFile string `mapstructure:"cert_file"`
 	// Path to the TLS key to use for TLS required connections. (optional)
 	KeyFile string `mapstructure:"key_file"`
+	// ReloadInterval is the minimum interval between two checks of the cert, key and CA files,
+	// the files changed are reloaded without restarting the collector. The files are only
+	// loaded at startup when not set. (optional)
+	ReloadInterval time.Duration `mapstructure:"reload_interval"`
+	// SPIFFE obtains the certificate and the CAs from a SPIFFE Workload API instead of the
+	// files, the peers are then authenticated by their SPIFFE ID. (optional)
+	SPIFFE *SPIFFESetting `mapstructure:"spiffe"`
 }
 
 // TLSClientSetting contains TLS configurations that are specific to client
@@ -120,11 +128,41 @@ func (c TLSSetting) loadCert(caPath string) (*x509.CertPool, error) {
 	return certPool, nil
 }
 
+// dynamic returns whether the certificate and the CAs can change while the TLS configuration is
+// used.
+func (c TLSSetting) dynamic() bool {
+	return c.ReloadInterval > 0 || c.SPIFFE != nil
+}
+
+// materialSource returns the source of the certificate and the CAs of a dynamic TLS
+// configuration, caFile being the CA file verifying the peers.
+func (c TLSSetting) materialSource(caFile string) (materialSource, []string, error) {
+	if c.SPIFFE == nil {
+		source, err := newFileSource(c.CertFile, c.KeyFile, caFile, c.ReloadInterval)
+		return source, nil, err
+	}
+	if c.CAFile != "" || c.CertFile != "" || c.KeyFile != "" {
+		return nil, nil, fmt.Errorf("spiffe cannot be used with the ca_file, cert_file and key_file settings")
+	}
+	source, err := getSPIFFESource(c.SPIFFE)
+	return source, c.SPIFFE.AllowedIDs, err
+}
+
 func (c TLSClientSetting) LoadTLSConfig() (*tls.Config, error) {
-	if c.Insecure && c.CAFile == "" {
+	if c.Insecure && c.CAFile == "" && c.SPIFFE == nil {
 		return nil, nil
 	}
 
+	if c.dynamic() {
+		source, allowedIDs, err := c.materialSource(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load TLS config: %w", err)
+		}
+		tlsCfg := dynamicClientConfig(source, allowedIDs, c.InsecureSkipVerify)
+		tlsCfg.ServerName = c.ServerName
+		return tlsCfg, nil
+	}
+
 	tlsCfg, err := c.TLSSetting.loadTLSConfig()
 	if err != nil {
 		return nil, fmt.Errorf("failed to load TLS config: %w", err)
@@ -135,6 +173,14 @@ func (c TLSClientSetting) LoadTLSConfig() (*tls.Config, error) {
 }
 
 func (c TLSServerSetting) LoadTLSConfig() (*tls.Config, error) {
+	if c.dynamic() {
+		source, allowedIDs, err := c.materialSource(c.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load TLS config: %w", err)
+		}
+		return dynamicServerConfig(source, allowedIDs, c.ClientCAFile != "" || c.SPIFFE != nil), nil
+	}
+
 	tlsCfg, err := c.loadTLSConfig()
 	if err != nil {
 		return nil, fmt.Errorf("failed to load TLS config: %w", err)
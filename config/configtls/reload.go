@@ -0,0 +1,268 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configtls
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// tlsMaterial is the certificate of a TLS configuration and the CAs verifying the peers, at a
+// given time.
+type tlsMaterial struct {
+	// certificate is nil when the TLS configuration has no certificate.
+	certificate *tls.Certificate
+	// roots is nil to verify the peers with the system CAs.
+	roots *x509.CertPool
+	// spiffe is true when the certificates are SPIFFE identities, the peers are then
+	// authenticated by their SPIFFE ID rather than by their host name.
+	spiffe bool
+}
+
+// materialSource returns the current certificate and CAs of a TLS configuration.
+type materialSource interface {
+	material() (*tlsMaterial, error)
+}
+
+// fileSource reads the certificate and the CAs from files, and reads them again when they
+// change, at most once every interval.
+type fileSource struct {
+	certFile string
+	keyFile  string
+	caFile   string
+	interval time.Duration
+
+	mu       sync.Mutex
+	checked  time.Time
+	contents [][]byte
+	current  *tlsMaterial
+}
+
+func newFileSource(certFile, keyFile, caFile string, interval time.Duration) (*fileSource, error) {
+	if (certFile == "" && keyFile != "") || (certFile != "" && keyFile == "") {
+		return nil, fmt.Errorf("for auth via TLS, either both certificate and key must be supplied, or neither")
+	}
+	s := &fileSource{certFile: certFile, keyFile: keyFile, caFile: caFile, interval: interval}
+	// The files are read before the configuration is used to report the invalid files at
+	// startup.
+	if _, err := s.material(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *fileSource) material() (*tlsMaterial, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	if s.current != nil && now.Sub(s.checked) < s.interval {
+		return s.current, nil
+	}
+	s.checked = now
+
+	contents, err := s.readFiles()
+	if err == nil && s.current != nil && equalContents(contents, s.contents) {
+		return s.current, nil
+	}
+	var m *tlsMaterial
+	if err == nil {
+		m, err = s.parse(contents)
+	}
+	if err != nil {
+		if s.current == nil {
+			return nil, err
+		}
+		// The files may be read while they are written, the previous certificate is used
+		// until they are valid again.
+		return s.current, nil
+	}
+	s.contents, s.current = contents, m
+	return m, nil
+}
+
+func (s *fileSource) readFiles() ([][]byte, error) {
+	contents := make([][]byte, 3)
+	for i, file := range []string{s.certFile, s.keyFile, s.caFile} {
+		if file == "" {
+			continue
+		}
+		content, err := ioutil.ReadFile(filepath.Clean(file))
+		if err != nil {
+			if file == s.caFile {
+				return nil, fmt.Errorf("failed to load CA %s: %w", file, err)
+			}
+			return nil, fmt.Errorf("failed to load TLS cert and key: %w", err)
+		}
+		contents[i] = content
+	}
+	return contents, nil
+}
+
+func (s *fileSource) parse(contents [][]byte) (*tlsMaterial, error) {
+	m := &tlsMaterial{}
+	if s.caFile != "" {
+		m.roots = x509.NewCertPool()
+		if !m.roots.AppendCertsFromPEM(contents[2]) {
+			return nil, fmt.Errorf("failed to parse CA %s", s.caFile)
+		}
+	}
+	if s.certFile != "" {
+		certificate, err := tls.X509KeyPair(contents[0], contents[1])
+		if err != nil {
+			return nil, fmt.Errorf("failed to load TLS cert and key: %w", err)
+		}
+		m.certificate = &certificate
+	}
+	return m, nil
+}
+
+func equalContents(a, b [][]byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !bytes.Equal(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// dynamicClientConfig returns the client TLS configuration using the current certificate and
+// CAs of the source for every connection. The server certificate is verified by
+// verifyPeer rather than by crypto/tls, the CAs being able to change.
+func dynamicClientConfig(source materialSource, allowedIDs []string, insecureSkipVerify bool) *tls.Config {
+	return &tls.Config{
+		// The server certificate is verified by VerifyConnection.
+		InsecureSkipVerify: true, // #nosec G402
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			m, err := source.material()
+			if err != nil {
+				return nil, err
+			}
+			if m.certificate == nil {
+				// No certificate is sent.
+				return &tls.Certificate{}, nil
+			}
+			return m.certificate, nil
+		},
+		VerifyConnection: func(cs tls.ConnectionState) error {
+			if insecureSkipVerify {
+				return nil
+			}
+			m, err := source.material()
+			if err != nil {
+				return err
+			}
+			return verifyPeer(cs, m, cs.ServerName, allowedIDs)
+		},
+	}
+}
+
+// dynamicServerConfig returns the server TLS configuration using the current certificate and
+// CAs of the source for every connection. The client certificates are required and verified
+// with the current CAs when verifyClients is true.
+func dynamicServerConfig(source materialSource, allowedIDs []string, verifyClients bool) *tls.Config {
+	getCertificate := func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+		m, err := source.material()
+		if err != nil {
+			return nil, err
+		}
+		if m.certificate == nil {
+			return nil, errors.New("no TLS certificate configured")
+		}
+		return m.certificate, nil
+	}
+	if !verifyClients {
+		return &tls.Config{GetCertificate: getCertificate}
+	}
+	return &tls.Config{
+		// The client certificates are verified by crypto/tls with the CAs of the connection,
+		// so that the verified chains are available to the authenticators.
+		GetConfigForClient: func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+			m, err := source.material()
+			if err != nil {
+				return nil, err
+			}
+			cfg := &tls.Config{
+				GetCertificate: getCertificate,
+				ClientCAs:      m.roots,
+				ClientAuth:     tls.RequireAndVerifyClientCert,
+				// The protocols of the server, e.g. h2 added by gRPC, are set on a copy of
+				// this configuration that is not available here, the protocols are the ones
+				// of the client instead, the first one being selected as the server would.
+				NextProtos: hello.SupportedProtos,
+			}
+			if m.spiffe {
+				cfg.VerifyConnection = func(cs tls.ConnectionState) error {
+					return verifySPIFFEID(cs.PeerCertificates[0], allowedIDs)
+				}
+			}
+			return cfg, nil
+		},
+	}
+}
+
+// verifyPeer verifies the certificate chain of the server with the current CAs. The host name
+// is verified unless the servers are authenticated by their SPIFFE ID, see verifySPIFFEID.
+func verifyPeer(cs tls.ConnectionState, m *tlsMaterial, dnsName string, allowedIDs []string) error {
+	if len(cs.PeerCertificates) == 0 {
+		return errors.New("tls: the server did not provide a certificate")
+	}
+	opts := x509.VerifyOptions{
+		Roots:         m.roots,
+		Intermediates: x509.NewCertPool(),
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	if !m.spiffe {
+		opts.DNSName = dnsName
+	}
+	for _, cert := range cs.PeerCertificates[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+	leaf := cs.PeerCertificates[0]
+	if _, err := leaf.Verify(opts); err != nil {
+		return err
+	}
+	if !m.spiffe {
+		return nil
+	}
+	return verifySPIFFEID(leaf, allowedIDs)
+}
+
+// verifySPIFFEID verifies that the SPIFFE ID of the peer is one of allowedIDs, any ID being
+// allowed when allowedIDs is empty.
+func verifySPIFFEID(leaf *x509.Certificate, allowedIDs []string) error {
+	id, err := spiffeID(leaf)
+	if err != nil {
+		return err
+	}
+	if len(allowedIDs) == 0 {
+		return nil
+	}
+	for _, allowed := range allowedIDs {
+		if id == allowed {
+			return nil
+		}
+	}
+	return fmt.Errorf("tls: the SPIFFE ID %q of the peer is not allowed", id)
+}
@@ -0,0 +1,233 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configtls
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/url"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testCA issues the certificates of the tests.
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newTestCA(t *testing.T) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &testCA{cert: cert, key: key}
+}
+
+// issue returns a certificate for localhost, with the SPIFFE ID as URI SAN if not empty.
+func (ca *testCA) issue(t *testing.T, spiffeID string, lifetime time.Duration) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(lifetime),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+	}
+	if spiffeID != "" {
+		u, err := url.Parse(spiffeID)
+		require.NoError(t, err)
+		template.URIs = []*url.URL{u}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert, key
+}
+
+func writeCert(t *testing.T, path string, cert *x509.Certificate) {
+	require.NoError(t, ioutil.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}), 0600))
+}
+
+func writeKey(t *testing.T, path string, key *ecdsa.PrivateKey) {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600))
+}
+
+// handshake connects a client to a server with the given TLS configurations, and returns the
+// certificate of the server and the errors of the client and the server.
+func handshake(t *testing.T, clientCfg, serverCfg *tls.Config) (*x509.Certificate, error, error) {
+	// The connections are buffered by the kernel, unlike net.Pipe, the server writing the
+	// session tickets after the client handshake completed.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+	clientConn, err := net.Dial("tcp", ln.Addr().String())
+	require.NoError(t, err)
+	defer clientConn.Close()
+	serverConn, err := ln.Accept()
+	require.NoError(t, err)
+	defer serverConn.Close()
+	if clientCfg.ServerName == "" {
+		clientCfg = clientCfg.Clone()
+		clientCfg.ServerName = "localhost"
+	}
+	client := tls.Client(clientConn, clientCfg)
+	server := tls.Server(serverConn, serverCfg)
+
+	serverErr := make(chan error, 1)
+	go func() {
+		err := server.Handshake()
+		if err != nil {
+			// Unblock the client waiting for the server.
+			serverConn.Close()
+		}
+		serverErr <- err
+	}()
+	clientErr := client.Handshake()
+	if clientErr != nil {
+		clientConn.Close()
+	}
+	sErr := <-serverErr
+	var serverCert *x509.Certificate
+	if clientErr == nil {
+		serverCert = client.ConnectionState().PeerCertificates[0]
+	}
+	return serverCert, clientErr, sErr
+}
+
+func TestReloadCertificateFiles(t *testing.T) {
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+	clientCAFile := filepath.Join(dir, "client-ca.pem")
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	clientCertFile := filepath.Join(dir, "client-cert.pem")
+	clientKeyFile := filepath.Join(dir, "client-key.pem")
+
+	ca := newTestCA(t)
+	writeCert(t, caFile, ca.cert)
+	writeCert(t, clientCAFile, ca.cert)
+	cert, key := ca.issue(t, "", time.Hour)
+	writeCert(t, certFile, cert)
+	writeKey(t, keyFile, key)
+	clientCert, clientKey := ca.issue(t, "", time.Hour)
+	writeCert(t, clientCertFile, clientCert)
+	writeKey(t, clientKeyFile, clientKey)
+
+	serverCfg, err := TLSServerSetting{
+		TLSSetting:   TLSSetting{CertFile: certFile, KeyFile: keyFile, ReloadInterval: time.Nanosecond},
+		ClientCAFile: clientCAFile,
+	}.LoadTLSConfig()
+	require.NoError(t, err)
+	clientCfg, err := TLSClientSetting{
+		TLSSetting: TLSSetting{CAFile: caFile, CertFile: clientCertFile, KeyFile: clientKeyFile, ReloadInterval: time.Nanosecond},
+	}.LoadTLSConfig()
+	require.NoError(t, err)
+
+	serverCert, clientErr, serverErr := handshake(t, clientCfg, serverCfg)
+	require.NoError(t, clientErr)
+	require.NoError(t, serverErr)
+	assert.Equal(t, cert.Raw, serverCert.Raw)
+
+	// The server certificate is renewed.
+	renewed, renewedKey := ca.issue(t, "", time.Hour)
+	writeCert(t, certFile, renewed)
+	writeKey(t, keyFile, renewedKey)
+	serverCert, clientErr, serverErr = handshake(t, clientCfg, serverCfg)
+	require.NoError(t, clientErr)
+	require.NoError(t, serverErr)
+	assert.Equal(t, renewed.Raw, serverCert.Raw)
+
+	// The CA is replaced, the certificates issued by the previous CA are refused.
+	writeCert(t, caFile, newTestCA(t).cert)
+	_, clientErr, _ = handshake(t, clientCfg, serverCfg)
+	require.Error(t, clientErr)
+	assert.Contains(t, clientErr.Error(), "certificate signed by unknown authority")
+
+	// An invalid file is ignored, the previous certificate being used.
+	writeCert(t, caFile, ca.cert)
+	require.NoError(t, ioutil.WriteFile(keyFile, []byte("invalid"), 0600))
+	serverCert, clientErr, serverErr = handshake(t, clientCfg, serverCfg)
+	require.NoError(t, clientErr)
+	require.NoError(t, serverErr)
+	assert.Equal(t, renewed.Raw, serverCert.Raw)
+}
+
+func TestReloadCertificateFilesInterval(t *testing.T) {
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	ca := newTestCA(t)
+	cert, key := ca.issue(t, "", time.Hour)
+	writeCert(t, certFile, cert)
+	writeKey(t, keyFile, key)
+
+	source, err := newFileSource(certFile, keyFile, "", time.Hour)
+	require.NoError(t, err)
+	renewed, renewedKey := ca.issue(t, "", time.Hour)
+	writeCert(t, certFile, renewed)
+	writeKey(t, keyFile, renewedKey)
+
+	// The files are not read again before the interval elapsed.
+	m, err := source.material()
+	require.NoError(t, err)
+	assert.Equal(t, cert.Raw, m.certificate.Certificate[0])
+	source.checked = time.Now().Add(-time.Hour)
+	m, err = source.material()
+	require.NoError(t, err)
+	assert.Equal(t, renewed.Raw, m.certificate.Certificate[0])
+}
+
+func TestReloadCertificateFilesErrors(t *testing.T) {
+	_, err := TLSServerSetting{TLSSetting: TLSSetting{CertFile: "testdata/test-cert.pem", ReloadInterval: time.Second}}.LoadTLSConfig()
+	assert.EqualError(t, err, "failed to load TLS config: for auth via TLS, either both certificate and key must be supplied, or neither")
+
+	_, err = TLSServerSetting{TLSSetting: TLSSetting{CertFile: "testdata/test-cert.pem", KeyFile: "testdata/missing.pem", ReloadInterval: time.Second}}.LoadTLSConfig()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to load TLS cert and key")
+
+	_, err = TLSClientSetting{TLSSetting: TLSSetting{CAFile: "testdata/testCA-bad.txt", ReloadInterval: time.Second}}.LoadTLSConfig()
+	assert.EqualError(t, err, "failed to load TLS config: failed to parse CA testdata/testCA-bad.txt")
+}
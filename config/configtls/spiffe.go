@@ -0,0 +1,301 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configtls
+
+import (
+	"context"
+	"crypto"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+const (
+	// spiffeEndpointSocketEnv is the environment variable with the address of the SPIFFE
+	// Workload API, used when the endpoint_socket setting is not set.
+	spiffeEndpointSocketEnv = "SPIFFE_ENDPOINT_SOCKET"
+
+	spiffeFetchX509SVIDMethod = "/SpiffeWorkloadAPI/FetchX509SVID"
+	spiffeFetchTimeout        = 10 * time.Second
+)
+
+// SPIFFESetting configures the identities obtained from a SPIFFE Workload API, e.g. the SPIRE
+// agent, instead of the certificate and CA files.
+type SPIFFESetting struct {
+	// EndpointSocket is the address of the Workload API, e.g.
+	// "unix:///run/spire/sockets/agent.sock" or "tcp://127.0.0.1:8081". Defaults to the
+	// SPIFFE_ENDPOINT_SOCKET environment variable.
+	EndpointSocket string `mapstructure:"endpoint_socket"`
+	// AllowedIDs are the SPIFFE IDs of the peers allowed to connect, e.g.
+	// "spiffe://example.org/collector". Any peer with a certificate issued by the trust
+	// bundle is allowed when empty. (optional)
+	AllowedIDs []string `mapstructure:"allowed_ids"`
+}
+
+// spiffeSource returns the X.509 SVID, the SPIFFE identity, of the workload and the trust
+// bundle verifying the peers. The SVID is fetched again from the Workload API once half of its
+// lifetime elapsed, the short-lived SVIDs being renewed before they expire.
+type spiffeSource struct {
+	address string
+
+	mu        sync.Mutex
+	current   *tlsMaterial
+	refreshAt time.Time
+	expiresAt time.Time
+}
+
+var (
+	spiffeSourcesMu sync.Mutex
+	// spiffeSources are shared by the TLS configurations using the same Workload API.
+	spiffeSources = map[string]*spiffeSource{}
+)
+
+func getSPIFFESource(s *SPIFFESetting) (*spiffeSource, error) {
+	address := s.EndpointSocket
+	if address == "" {
+		address = os.Getenv(spiffeEndpointSocketEnv)
+	}
+	if address == "" {
+		return nil, fmt.Errorf("the SPIFFE Workload API endpoint_socket is not set and %s is not defined", spiffeEndpointSocketEnv)
+	}
+	if _, _, err := parseSPIFFEAddress(address); err != nil {
+		return nil, err
+	}
+	spiffeSourcesMu.Lock()
+	defer spiffeSourcesMu.Unlock()
+	source, ok := spiffeSources[address]
+	if !ok {
+		source = &spiffeSource{address: address}
+		spiffeSources[address] = source
+	}
+	return source, nil
+}
+
+// parseSPIFFEAddress returns the network and the address to dial for a Workload API address.
+func parseSPIFFEAddress(address string) (string, string, error) {
+	u, err := url.Parse(address)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid SPIFFE Workload API address %q: %w", address, err)
+	}
+	switch {
+	case u.Scheme == "unix" && u.Path != "":
+		return "unix", u.Path, nil
+	case u.Scheme == "unix" && u.Opaque != "":
+		return "unix", u.Opaque, nil
+	case u.Scheme == "tcp" && u.Host != "":
+		return "tcp", u.Host, nil
+	}
+	return "", "", fmt.Errorf("invalid SPIFFE Workload API address %q: the address must be unix:///path or tcp://host:port", address)
+}
+
+func (s *spiffeSource) material() (*tlsMaterial, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	if s.current != nil && now.Before(s.refreshAt) {
+		return s.current, nil
+	}
+
+	m, leaf, err := s.fetch()
+	if err != nil {
+		if s.current != nil && now.Before(s.expiresAt) {
+			// The current SVID is still valid, the fetch is retried at the next connection.
+			return s.current, nil
+		}
+		return nil, fmt.Errorf("failed to fetch the X.509 SVID from the SPIFFE Workload API %s: %w", s.address, err)
+	}
+	s.current = m
+	s.refreshAt = leaf.NotBefore.Add(leaf.NotAfter.Sub(leaf.NotBefore) / 2)
+	s.expiresAt = leaf.NotAfter
+	return m, nil
+}
+
+// fetch returns the first X.509 SVID sent by the Workload API, the default identity of the
+// workload.
+func (s *spiffeSource) fetch() (*tlsMaterial, *x509.Certificate, error) {
+	network, address, _ := parseSPIFFEAddress(s.address)
+	ctx, cancel := context.WithTimeout(context.Background(), spiffeFetchTimeout)
+	defer cancel()
+
+	conn, err := grpc.DialContext(ctx, address,
+		grpc.WithInsecure(),
+		grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, network, addr)
+		}),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer conn.Close()
+
+	// The Workload API refuses the requests without this header.
+	ctx = metadata.AppendToOutgoingContext(ctx, "workload.spiffe.io", "true")
+	stream, err := conn.NewStream(ctx, &grpc.StreamDesc{ServerStreams: true}, spiffeFetchX509SVIDMethod, grpc.ForceCodec(rawCodec{}))
+	if err != nil {
+		return nil, nil, err
+	}
+	// X509SVIDRequest has no field.
+	if err = stream.SendMsg(&rawMessage{}); err != nil {
+		return nil, nil, err
+	}
+	if err = stream.CloseSend(); err != nil {
+		return nil, nil, err
+	}
+	resp := &rawMessage{}
+	if err = stream.RecvMsg(resp); err != nil {
+		return nil, nil, err
+	}
+	return parseX509SVIDResponse(resp.data)
+}
+
+// parseX509SVIDResponse parses the X509SVIDResponse message of the Workload API:
+//
+//	message X509SVIDResponse {
+//	  repeated X509SVID svids = 1;
+//	  ...
+//	}
+//	message X509SVID {
+//	  string spiffe_id = 1;
+//	  bytes x509_svid = 2;     // ASN.1 DER certificates, the leaf first.
+//	  bytes x509_svid_key = 3; // ASN.1 DER PKCS#8 private key.
+//	  bytes bundle = 4;        // ASN.1 DER CA certificates.
+//	}
+func parseX509SVIDResponse(data []byte) (*tlsMaterial, *x509.Certificate, error) {
+	var svid []byte
+	err := forEachField(data, func(num protowire.Number, value []byte) {
+		if num == 1 && svid == nil {
+			svid = value
+		}
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if svid == nil {
+		return nil, nil, errors.New("the response has no X.509 SVID")
+	}
+
+	var certsDER, keyDER, bundleDER []byte
+	err = forEachField(svid, func(num protowire.Number, value []byte) {
+		switch num {
+		case 2:
+			certsDER = value
+		case 3:
+			keyDER = value
+		case 4:
+			bundleDER = value
+		}
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	certs, err := x509.ParseCertificates(certsDER)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid X.509 SVID: %w", err)
+	}
+	if len(certs) == 0 {
+		return nil, nil, errors.New("invalid X.509 SVID: no certificate")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(keyDER)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid X.509 SVID key: %w", err)
+	}
+	if _, ok := key.(crypto.Signer); !ok {
+		return nil, nil, errors.New("invalid X.509 SVID key: the key cannot sign")
+	}
+	bundle, err := x509.ParseCertificates(bundleDER)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid X.509 bundle: %w", err)
+	}
+	if len(bundle) == 0 {
+		return nil, nil, errors.New("invalid X.509 bundle: no certificate")
+	}
+
+	certificate := &tls.Certificate{PrivateKey: key, Leaf: certs[0]}
+	for _, cert := range certs {
+		certificate.Certificate = append(certificate.Certificate, cert.Raw)
+	}
+	roots := x509.NewCertPool()
+	for _, cert := range bundle {
+		roots.AddCert(cert)
+	}
+	return &tlsMaterial{certificate: certificate, roots: roots, spiffe: true}, certs[0], nil
+}
+
+// forEachField calls fn with the number and the value of the length-delimited fields of the
+// protobuf message data, the other fields are skipped.
+func forEachField(data []byte, fn func(protowire.Number, []byte)) error {
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		data = data[n:]
+		if typ != protowire.BytesType {
+			n = protowire.ConsumeFieldValue(num, typ, data)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			data = data[n:]
+			continue
+		}
+		value, n := protowire.ConsumeBytes(data)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		fn(num, value)
+		data = data[n:]
+	}
+	return nil
+}
+
+// spiffeID returns the SPIFFE ID of an X.509 SVID, its only URI SAN.
+func spiffeID(cert *x509.Certificate) (string, error) {
+	if len(cert.URIs) != 1 || cert.URIs[0].Scheme != "spiffe" {
+		return "", errors.New("tls: the peer certificate is not an X.509 SVID")
+	}
+	return cert.URIs[0].String(), nil
+}
+
+// rawMessage is a protobuf message encoded or decoded by the caller.
+type rawMessage struct {
+	data []byte
+}
+
+// rawCodec sends and receives the rawMessages as they are.
+type rawCodec struct{}
+
+func (rawCodec) Marshal(v interface{}) ([]byte, error) {
+	return v.(*rawMessage).data, nil
+}
+
+func (rawCodec) Unmarshal(data []byte, v interface{}) error {
+	v.(*rawMessage).data = append([]byte(nil), data...)
+	return nil
+}
+
+func (rawCodec) Name() string {
+	return "proto"
+}
@@ -0,0 +1,231 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configtls
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/x509"
+	"errors"
+	"net"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// fakeWorkloadAPI serves the X.509 SVIDs of the tests with the SPIFFE Workload API.
+type fakeWorkloadAPI struct {
+	mu       sync.Mutex
+	response []byte
+	fetches  int
+}
+
+func (f *fakeWorkloadAPI) setSVID(t *testing.T, ca *testCA, cert *x509.Certificate, key *ecdsa.PrivateKey) {
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	var svid []byte
+	svid = protowire.AppendTag(svid, 1, protowire.BytesType)
+	svid = protowire.AppendString(svid, cert.URIs[0].String())
+	svid = protowire.AppendTag(svid, 2, protowire.BytesType)
+	svid = protowire.AppendBytes(svid, cert.Raw)
+	svid = protowire.AppendTag(svid, 3, protowire.BytesType)
+	svid = protowire.AppendBytes(svid, keyDER)
+	svid = protowire.AppendTag(svid, 4, protowire.BytesType)
+	svid = protowire.AppendBytes(svid, ca.cert.Raw)
+	var resp []byte
+	resp = protowire.AppendTag(resp, 1, protowire.BytesType)
+	resp = protowire.AppendBytes(resp, svid)
+
+	f.mu.Lock()
+	f.response = resp
+	f.mu.Unlock()
+}
+
+func (f *fakeWorkloadAPI) handle(_ interface{}, stream grpc.ServerStream) error {
+	method, _ := grpc.MethodFromServerStream(stream)
+	if method != spiffeFetchX509SVIDMethod {
+		return errors.New("unknown method")
+	}
+	md, _ := metadata.FromIncomingContext(stream.Context())
+	if len(md.Get("workload.spiffe.io")) == 0 {
+		return errors.New("missing security header")
+	}
+	if err := stream.RecvMsg(&rawMessage{}); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	resp := f.response
+	f.fetches++
+	f.mu.Unlock()
+	return stream.SendMsg(&rawMessage{data: resp})
+}
+
+// serverRawCodec is the rawCodec of the gRPC server, grpc.ForceServerCodec is not available
+// in this version of gRPC.
+type serverRawCodec struct {
+	rawCodec
+}
+
+func (serverRawCodec) String() string {
+	return "raw"
+}
+
+func startFakeWorkloadAPI(t *testing.T) (*fakeWorkloadAPI, string) {
+	socket := filepath.Join(t.TempDir(), "agent.sock")
+	ln, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+	api := &fakeWorkloadAPI{}
+	server := grpc.NewServer(grpc.UnknownServiceHandler(api.handle), grpc.CustomCodec(serverRawCodec{}))
+	go server.Serve(ln)
+	t.Cleanup(server.Stop)
+	return api, "unix://" + socket
+}
+
+func TestSPIFFE(t *testing.T) {
+	api, address := startFakeWorkloadAPI(t)
+	ca := newTestCA(t)
+	cert, key := ca.issue(t, "spiffe://example.org/server", time.Hour)
+	api.setSVID(t, ca, cert, key)
+
+	serverCfg, err := TLSServerSetting{
+		TLSSetting: TLSSetting{SPIFFE: &SPIFFESetting{EndpointSocket: address}},
+	}.LoadTLSConfig()
+	require.NoError(t, err)
+	clientCfg, err := TLSClientSetting{
+		TLSSetting: TLSSetting{SPIFFE: &SPIFFESetting{EndpointSocket: address, AllowedIDs: []string{"spiffe://example.org/server"}}},
+	}.LoadTLSConfig()
+	require.NoError(t, err)
+
+	// The client and the server present the SVID of the workload, the host name is not
+	// verified.
+	clientCfg.ServerName = "otelcol.example.org"
+	serverCert, clientErr, serverErr := handshake(t, clientCfg, serverCfg)
+	require.NoError(t, clientErr)
+	require.NoError(t, serverErr)
+	assert.Equal(t, cert.Raw, serverCert.Raw)
+
+	// The SVID is only fetched again once half of its lifetime elapsed.
+	api.mu.Lock()
+	assert.Equal(t, 1, api.fetches)
+	api.mu.Unlock()
+
+	// The peers with an ID that is not allowed are refused.
+	deniedCfg, err := TLSClientSetting{
+		TLSSetting: TLSSetting{SPIFFE: &SPIFFESetting{EndpointSocket: address, AllowedIDs: []string{"spiffe://example.org/other"}}},
+	}.LoadTLSConfig()
+	require.NoError(t, err)
+	_, clientErr, _ = handshake(t, deniedCfg, serverCfg)
+	require.Error(t, clientErr)
+	assert.Contains(t, clientErr.Error(), `the SPIFFE ID "spiffe://example.org/server" of the peer is not allowed`)
+}
+
+func TestSPIFFEGRPC(t *testing.T) {
+	api, address := startFakeWorkloadAPI(t)
+	ca := newTestCA(t)
+	cert, key := ca.issue(t, "spiffe://example.org/collector", time.Hour)
+	api.setSVID(t, ca, cert, key)
+
+	serverCfg, err := TLSServerSetting{TLSSetting: TLSSetting{SPIFFE: &SPIFFESetting{EndpointSocket: address}}}.LoadTLSConfig()
+	require.NoError(t, err)
+	clientCfg, err := TLSClientSetting{TLSSetting: TLSSetting{SPIFFE: &SPIFFESetting{EndpointSocket: address}}}.LoadTLSConfig()
+	require.NoError(t, err)
+
+	// The fake Workload API is served with mTLS, authenticated by the SVIDs.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer(grpc.Creds(credentials.NewTLS(serverCfg)), grpc.UnknownServiceHandler(api.handle), grpc.CustomCodec(serverRawCodec{}))
+	go server.Serve(ln)
+	defer server.Stop()
+
+	conn, err := grpc.Dial(ln.Addr().String(), grpc.WithTransportCredentials(credentials.NewTLS(clientCfg)))
+	require.NoError(t, err)
+	defer conn.Close()
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "workload.spiffe.io", "true")
+	stream, err := conn.NewStream(ctx, &grpc.StreamDesc{ServerStreams: true}, spiffeFetchX509SVIDMethod, grpc.ForceCodec(rawCodec{}))
+	require.NoError(t, err)
+	require.NoError(t, stream.SendMsg(&rawMessage{}))
+	require.NoError(t, stream.CloseSend())
+	resp := &rawMessage{}
+	require.NoError(t, stream.RecvMsg(resp))
+	assert.NotEmpty(t, resp.data)
+}
+
+func TestSPIFFERenewal(t *testing.T) {
+	api, address := startFakeWorkloadAPI(t)
+	ca := newTestCA(t)
+	cert, key := ca.issue(t, "spiffe://example.org/collector", time.Hour)
+	api.setSVID(t, ca, cert, key)
+
+	source, err := getSPIFFESource(&SPIFFESetting{EndpointSocket: address})
+	require.NoError(t, err)
+	m, err := source.material()
+	require.NoError(t, err)
+	assert.Equal(t, cert.Raw, m.certificate.Certificate[0])
+
+	renewed, renewedKey := ca.issue(t, "spiffe://example.org/collector", time.Hour)
+	api.setSVID(t, ca, renewed, renewedKey)
+	m, err = source.material()
+	require.NoError(t, err)
+	assert.Equal(t, cert.Raw, m.certificate.Certificate[0])
+
+	source.mu.Lock()
+	source.refreshAt = time.Now()
+	source.mu.Unlock()
+	m, err = source.material()
+	require.NoError(t, err)
+	assert.Equal(t, renewed.Raw, m.certificate.Certificate[0])
+}
+
+func TestSPIFFEErrors(t *testing.T) {
+	_, err := TLSClientSetting{
+		TLSSetting: TLSSetting{CAFile: "testdata/testCA.pem", SPIFFE: &SPIFFESetting{EndpointSocket: "unix:///tmp/agent.sock"}},
+	}.LoadTLSConfig()
+	assert.EqualError(t, err, "failed to load TLS config: spiffe cannot be used with the ca_file, cert_file and key_file settings")
+
+	_, err = TLSClientSetting{
+		TLSSetting: TLSSetting{SPIFFE: &SPIFFESetting{EndpointSocket: "http://localhost"}},
+	}.LoadTLSConfig()
+	assert.EqualError(t, err, `failed to load TLS config: invalid SPIFFE Workload API address "http://localhost": the address must be unix:///path or tcp://host:port`)
+
+	t.Setenv(spiffeEndpointSocketEnv, "")
+	_, err = TLSServerSetting{TLSSetting: TLSSetting{SPIFFE: &SPIFFESetting{}}}.LoadTLSConfig()
+	assert.EqualError(t, err, "failed to load TLS config: the SPIFFE Workload API endpoint_socket is not set and SPIFFE_ENDPOINT_SOCKET is not defined")
+
+	source, err := getSPIFFESource(&SPIFFESetting{EndpointSocket: "unix://" + filepath.Join(t.TempDir(), "missing.sock")})
+	require.NoError(t, err)
+	_, err = source.material()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to fetch the X.509 SVID from the SPIFFE Workload API")
+}
+
+func TestParseSPIFFEAddress(t *testing.T) {
+	network, address, err := parseSPIFFEAddress("unix:///run/spire/sockets/agent.sock")
+	require.NoError(t, err)
+	assert.Equal(t, "unix", network)
+	assert.Equal(t, "/run/spire/sockets/agent.sock", address)
+
+	network, address, err = parseSPIFFEAddress("tcp://127.0.0.1:8081")
+	require.NoError(t, err)
+	assert.Equal(t, "tcp", network)
+	assert.Equal(t, "127.0.0.1:8081", address)
+}
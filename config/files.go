@@ -0,0 +1,200 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v2"
+)
+
+// includeKeyName is the top-level configuration key listing the files included by a file.
+const includeKeyName = "include"
+
+// ReadFiles reads the YAML configuration files, or the directories of files, at the given
+// paths and merges them into v. The files of a directory are the ones with a ".yaml" or
+// ".yml" extension, read in lexical order. The documents are deep-merged: the maps are
+// merged key by key, all the other values, including the lists, of a later document
+// replace the ones of the earlier documents, except for the empty values.
+//
+// A file can list other files or directories to read with the top-level "include" key,
+// the relative paths being relative to the directory of the file. The included files
+// are merged before the file including them, so that its values take precedence.
+func ReadFiles(v *viper.Viper, paths ...string) error {
+	merged := map[string]interface{}{}
+	for _, path := range paths {
+		if err := MergeFile(merged, path); err != nil {
+			return err
+		}
+	}
+	return v.MergeConfigMap(merged)
+}
+
+// MergeFile deep-merges the configuration file, or the files of the directory, at path
+// into merged, as ReadFiles does.
+func MergeFile(merged map[string]interface{}, path string) error {
+	return readPath(merged, path, nil)
+}
+
+// MergeYAML deep-merges the configuration document into merged, as ReadFiles does, name
+// identifying the document in the errors. The document cannot include files.
+func MergeYAML(merged map[string]interface{}, content []byte, name string) error {
+	doc, err := parseDocument(content, name)
+	if err != nil {
+		return err
+	}
+	if _, ok := doc[includeKeyName]; ok {
+		return fmt.Errorf("%q is only supported in local config files, found in %q", includeKeyName, name)
+	}
+	mergeMaps(merged, doc)
+	return nil
+}
+
+// readPath merges the file or the files of the directory at path into merged, includes
+// lists the files being read, to detect the include cycles.
+func readPath(merged map[string]interface{}, path string, includes []string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return readFile(merged, path, includes)
+	}
+	files, err := ioutil.ReadDir(path)
+	if err != nil {
+		return err
+	}
+	var names []string
+	for _, file := range files {
+		if ext := filepath.Ext(file.Name()); !file.IsDir() && (ext == ".yaml" || ext == ".yml") {
+			names = append(names, file.Name())
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := readFile(merged, filepath.Join(path, name), includes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readFile(merged map[string]interface{}, path string, includes []string) error {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	for _, include := range includes {
+		if include == absPath {
+			return fmt.Errorf("config file %q includes itself", path)
+		}
+	}
+	includes = append(includes, absPath)
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	doc, err := parseDocument(content, path)
+	if err != nil {
+		return err
+	}
+
+	if rawIncludes, ok := doc[includeKeyName]; ok {
+		delete(doc, includeKeyName)
+		includePaths, ok := rawIncludes.([]interface{})
+		if !ok && rawIncludes != nil {
+			return fmt.Errorf("%q of config file %q must be a list of paths", includeKeyName, path)
+		}
+		for _, rawPath := range includePaths {
+			includePath, ok := rawPath.(string)
+			if !ok || includePath == "" {
+				return fmt.Errorf("%q of config file %q must be a list of paths", includeKeyName, path)
+			}
+			if !filepath.IsAbs(includePath) {
+				includePath = filepath.Join(filepath.Dir(path), includePath)
+			}
+			if err := readPath(merged, includePath, includes); err != nil {
+				return err
+			}
+		}
+	}
+
+	mergeMaps(merged, doc)
+	return nil
+}
+
+func parseDocument(content []byte, name string) (map[string]interface{}, error) {
+	var doc map[string]interface{}
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, fmt.Errorf("cannot read config file %q: %w", name, err)
+	}
+	return toStringMap(doc).(map[string]interface{}), nil
+}
+
+// toStringMap converts the maps decoded from YAML to maps with lowercase string keys,
+// as the keys of viper are case insensitive.
+func toStringMap(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, mv := range v {
+			m[strings.ToLower(fmt.Sprint(k))] = toStringMap(mv)
+		}
+		return m
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, mv := range v {
+			m[strings.ToLower(k)] = toStringMap(mv)
+		}
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(v))
+		for i, sv := range v {
+			s[i] = toStringMap(sv)
+		}
+		return s
+	default:
+		return v
+	}
+}
+
+// mergeMaps deep-merges src into dst, the values of src taking precedence.
+func mergeMaps(dst, src map[string]interface{}) {
+	for k, sv := range src {
+		dv, ok := dst[k]
+		if !ok {
+			dst[k] = sv
+			continue
+		}
+		if sv == nil {
+			// An empty value, e.g. a component without settings, keeps the merged settings.
+			continue
+		}
+		dm, dok := dv.(map[string]interface{})
+		sm, sok := sv.(map[string]interface{})
+		if dok && sok {
+			mergeMaps(dm, sm)
+			continue
+		}
+		dst[k] = sv
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/config/confignet"
+	"go.opentelemetry.io/collector/internal/testcomponents"
+)
+
+func loadConfigFiles(t *testing.T, paths ...string) (*configmodels.Config, error) {
+	factories, err := testcomponents.ExampleComponents()
+	require.NoError(t, err)
+
+	v := NewViper()
+	if err = ReadFiles(v, paths...); err != nil {
+		return nil, err
+	}
+	return Load(v, factories)
+}
+
+func TestReadFilesMerge(t *testing.T) {
+	config, err := loadConfigFiles(t,
+		path.Join("testdata", "files", "base.yaml"),
+		path.Join("testdata", "files", "override.yaml"))
+	require.NoError(t, err)
+
+	assert.Equal(t,
+		&testcomponents.ExampleReceiver{
+			ReceiverSettings: configmodels.ReceiverSettings{
+				TypeVal: "examplereceiver",
+				NameVal: "examplereceiver",
+			},
+			TCPAddr: confignet.TCPAddr{
+				Endpoint: "localhost:1000",
+			},
+			ExtraSetting:     "some overridden string",
+			ExtraListSetting: []string{"some overridden list value"},
+		},
+		config.Receivers["examplereceiver"])
+	assert.Contains(t, config.Receivers, "examplereceiver/2")
+	assert.Equal(t, "some exporter string", config.Exporters["exampleexporter"].(*testcomponents.ExampleExporter).ExtraSetting)
+
+	pipeline := config.Service.Pipelines["traces"]
+	assert.Equal(t, []string{"examplereceiver", "examplereceiver/2"}, pipeline.Receivers)
+	assert.Equal(t, []string{"exampleprocessor"}, pipeline.Processors)
+	assert.Equal(t, []string{"exampleexporter"}, pipeline.Exporters)
+}
+
+func TestReadFilesInclude(t *testing.T) {
+	config, err := loadConfigFiles(t, path.Join("testdata", "files", "include", "config.yaml"))
+	require.NoError(t, err)
+
+	receiver := config.Receivers["examplereceiver"].(*testcomponents.ExampleReceiver)
+	assert.Equal(t, "localhost:2000", receiver.Endpoint)
+	assert.Equal(t, "some including string", receiver.ExtraSetting)
+
+	exporter := config.Exporters["exampleexporter"].(*testcomponents.ExampleExporter)
+	assert.Equal(t, int32(1), exporter.ExtraInt)
+	assert.Equal(t, "some second string", exporter.ExtraSetting)
+}
+
+func TestReadFilesDirectory(t *testing.T) {
+	v := NewViper()
+	require.NoError(t, ReadFiles(v, path.Join("testdata", "files", "include", "exporters")))
+	assert.Equal(t, map[string]interface{}{
+		"exporters": map[string]interface{}{
+			"exampleexporter": map[string]interface{}{
+				"extra":     "some second string",
+				"extra_int": 1,
+			},
+		},
+	}, v.AllSettings())
+}
+
+func TestReadFilesErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		path   string
+		errMsg string
+	}{
+		{
+			name:   "missing file",
+			path:   path.Join("testdata", "files", "missing.yaml"),
+			errMsg: "no such file or directory",
+		},
+		{
+			name:   "include cycle",
+			path:   path.Join("testdata", "files", "cycle", "a.yaml"),
+			errMsg: "includes itself",
+		},
+		{
+			name:   "invalid include",
+			path:   path.Join("testdata", "files", "invalid-include.yaml"),
+			errMsg: `"include" of config file "testdata/files/invalid-include.yaml" must be a list of paths`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ReadFiles(NewViper(), tt.path)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+}
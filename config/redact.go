@@ -0,0 +1,201 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/config/configmodels"
+)
+
+const (
+	// RedactedValue replaces the values of the sensitive settings in ToRedactedMap.
+	RedactedValue = "[REDACTED]"
+
+	// sensitiveTagName is the struct tag marking the sensitive settings, e.g.
+	// `sensitive:"true"`, their values are never shown.
+	sensitiveTagName = "sensitive"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// ToRedactedMap returns the settings of the loaded configuration, with the default values of the
+// components and the environment variables expanded, as the map they would be loaded from.
+// The values of the fields tagged with `sensitive:"true"` are replaced by RedactedValue, the
+// values of all the entries for the maps, unless they are empty.
+func ToRedactedMap(cfg *configmodels.Config) map[string]interface{} {
+	pipelines := map[string]interface{}{}
+	for name, pipeline := range cfg.Service.Pipelines {
+		p := map[string]interface{}{
+			"receivers": pipeline.Receivers,
+			"exporters": pipeline.Exporters,
+		}
+		if len(pipeline.Processors) > 0 {
+			p["processors"] = pipeline.Processors
+		}
+		if pipeline.Backpressure {
+			p["backpressure"] = true
+		}
+		pipelines[name] = p
+	}
+	service := map[string]interface{}{pipelinesKeyName: pipelines}
+	if len(cfg.Service.Extensions) > 0 {
+		service[extensionsKeyName] = cfg.Service.Extensions
+	}
+
+	out := map[string]interface{}{
+		receiversKeyName:  map[string]interface{}{},
+		processorsKeyName: map[string]interface{}{},
+		exportersKeyName:  map[string]interface{}{},
+		serviceKeyName:    service,
+	}
+	for name, c := range cfg.Receivers {
+		out[receiversKeyName].(map[string]interface{})[name] = encodeRedacted(reflect.ValueOf(c))
+	}
+	for name, c := range cfg.Processors {
+		out[processorsKeyName].(map[string]interface{})[name] = encodeRedacted(reflect.ValueOf(c))
+	}
+	for name, c := range cfg.Exporters {
+		out[exportersKeyName].(map[string]interface{})[name] = encodeRedacted(reflect.ValueOf(c))
+	}
+	if len(cfg.Extensions) > 0 {
+		extensions := map[string]interface{}{}
+		for name, c := range cfg.Extensions {
+			extensions[name] = encodeRedacted(reflect.ValueOf(c))
+		}
+		out[extensionsKeyName] = extensions
+	}
+	return out
+}
+
+// encodeRedacted returns the value v as it would be decoded from, following the mapstructure
+// tags of the structs.
+func encodeRedacted(v reflect.Value) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+	if v.Type() == durationType {
+		return time.Duration(v.Int()).String()
+	}
+	if m, ok := v.Interface().(encoding.TextMarshaler); ok && v.Kind() != reflect.Ptr && v.Kind() != reflect.Interface {
+		if text, err := m.MarshalText(); err == nil {
+			return string(text)
+		}
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return encodeRedacted(v.Elem())
+	case reflect.Struct:
+		out := map[string]interface{}{}
+		encodeStruct(out, v)
+		return out
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		out := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			out[fmt.Sprint(iter.Key().Interface())] = encodeRedacted(iter.Value())
+		}
+		return out
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return string(v.Bytes())
+		}
+		out := make([]interface{}, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			out[i] = encodeRedacted(v.Index(i))
+		}
+		return out
+	case reflect.String:
+		return v.String()
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		return nil
+	}
+	return v.Interface()
+}
+
+// encodeStruct adds the fields of the struct v to out, the fields of the squashed structs being
+// added as fields of v.
+func encodeStruct(out map[string]interface{}, v reflect.Value) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			// The unexported fields are not decoded.
+			continue
+		}
+		tag := strings.Split(field.Tag.Get("mapstructure"), ",")
+		name := tag[0]
+		if name == "-" {
+			continue
+		}
+		fv := v.Field(i)
+		if containsOption(tag[1:], "squash") {
+			for fv.Kind() == reflect.Ptr && !fv.IsNil() {
+				fv = fv.Elem()
+			}
+			if fv.Kind() == reflect.Struct {
+				encodeStruct(out, fv)
+			}
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		if field.Tag.Get(sensitiveTagName) == "true" {
+			out[name] = redact(fv)
+			continue
+		}
+		out[name] = encodeRedacted(fv)
+	}
+}
+
+// redact returns the value of a sensitive field, the empty values are kept to show that they
+// are not set.
+func redact(v reflect.Value) interface{} {
+	if v.IsZero() {
+		return encodeRedacted(v)
+	}
+	if v.Kind() == reflect.Map {
+		out := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			out[fmt.Sprint(iter.Key().Interface())] = RedactedValue
+		}
+		return out
+	}
+	return RedactedValue
+}
+
+func containsOption(options []string, option string) bool {
+	for _, o := range options {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.opentelemetry.io/collector/config/configmodels"
+)
+
+type redactTestAuth struct {
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password" sensitive:"true"`
+}
+
+type redactTestExporter struct {
+	configmodels.ExporterSettings `mapstructure:",squash"`
+	Endpoint                      string            `mapstructure:"endpoint"`
+	Timeout                       time.Duration     `mapstructure:"timeout"`
+	Headers                       map[string]string `mapstructure:"headers" sensitive:"true"`
+	Token                         string            `mapstructure:"token" sensitive:"true"`
+	Auth                          *redactTestAuth   `mapstructure:"auth"`
+	Backup                        *redactTestAuth   `mapstructure:"backup"`
+	Tags                          []string          `mapstructure:"tags"`
+	Internal                      string            `mapstructure:"-"`
+	Enabled                       bool
+}
+
+func TestToRedactedMap(t *testing.T) {
+	cfg := &configmodels.Config{
+		Receivers: configmodels.Receivers{
+			"examplereceiver": &configmodels.ReceiverSettings{TypeVal: "examplereceiver", NameVal: "examplereceiver"},
+		},
+		Exporters: configmodels.Exporters{
+			"redact/1": &redactTestExporter{
+				ExporterSettings: configmodels.ExporterSettings{TypeVal: "redact", NameVal: "redact/1"},
+				Endpoint:         "localhost:4317",
+				Timeout:          5 * time.Second,
+				Headers:          map[string]string{"Authorization": "Bearer secret"},
+				Auth:             &redactTestAuth{Username: "user", Password: "secret"},
+				Tags:             []string{"a", "b"},
+				Internal:         "internal",
+				Enabled:          true,
+			},
+		},
+		Service: configmodels.Service{
+			Pipelines: configmodels.Pipelines{
+				"traces": &configmodels.Pipeline{
+					Name:      "traces",
+					InputType: configmodels.TracesDataType,
+					Receivers: []string{"examplereceiver"},
+					Exporters: []string{"redact/1"},
+				},
+			},
+		},
+	}
+
+	assert.Equal(t, map[string]interface{}{
+		"receivers": map[string]interface{}{
+			"examplereceiver": map[string]interface{}{},
+		},
+		"processors": map[string]interface{}{},
+		"exporters": map[string]interface{}{
+			"redact/1": map[string]interface{}{
+				"endpoint": "localhost:4317",
+				"timeout":  "5s",
+				"headers":  map[string]interface{}{"Authorization": RedactedValue},
+				// The empty sensitive values are kept.
+				"token":   "",
+				"auth":    map[string]interface{}{"username": "user", "password": RedactedValue},
+				"backup":  nil,
+				"tags":    []interface{}{"a", "b"},
+				"enabled": true,
+			},
+		},
+		"service": map[string]interface{}{
+			"pipelines": map[string]interface{}{
+				"traces": map[string]interface{}{
+					"receivers": []string{"examplereceiver"},
+					"exporters": []string{"redact/1"},
+				},
+			},
+		},
+	}, ToRedactedMap(cfg))
+}
@@ -0,0 +1,47 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package consumererror
+
+import "errors"
+
+// backpressure is an error returned when the data was refused because a component
+// cannot accept more data for now, e.g. because its queue is full.
+type backpressure struct {
+	err error
+}
+
+// Backpressure wraps an error to indicate that the data was refused because a
+// component cannot accept more data for now. The data was not processed and can
+// be retried later, the receivers return a retryable status to their clients.
+func Backpressure(err error) error {
+	return backpressure{err: err}
+}
+
+func (b backpressure) Error() string {
+	return b.err.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (b backpressure) Unwrap() error {
+	return b.err
+}
+
+// IsBackpressure checks if an error was wrapped with the Backpressure function.
+func IsBackpressure(err error) bool {
+	if err != nil {
+		return errors.As(err, &backpressure{})
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package consumererror
+
+import "errors"
+
+// RejectedError can be used to signalize that the destination accepted the data except a
+// number of rejected items, e.g. spans, metric data points or log records.
+type RejectedError struct {
+	error
+	rejected int
+}
+
+// NewRejectedError creates a RejectedError for the given number of rejected items.
+// Use this error type only when the other items were accepted by the destination.
+func NewRejectedError(err error, rejected int) error {
+	return RejectedError{
+		error:    err,
+		rejected: rejected,
+	}
+}
+
+// Rejected returns the number of rejected items.
+func (err RejectedError) Rejected() int {
+	return err.rejected
+}
+
+// Unwrap returns the wrapped error.
+func (err RejectedError) Unwrap() error {
+	return err.error
+}
+
+// RejectedItems returns the number of rejected items of the RejectedError in the chain of err,
+// and whether there is one.
+func RejectedItems(err error) (int, bool) {
+	var rejectedErr RejectedError
+	if errors.As(err, &rejectedErr) {
+		return rejectedErr.rejected, true
+	}
+	return 0, false
+}
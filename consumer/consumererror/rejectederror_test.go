@@ -0,0 +1,46 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package consumererror
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRejectedError(t *testing.T) {
+	err := errors.New("some error")
+	rejectedErr := NewRejectedError(err, 3)
+	assert.Equal(t, err.Error(), rejectedErr.Error())
+	assert.Equal(t, 3, rejectedErr.(RejectedError).Rejected())
+	assert.True(t, errors.Is(rejectedErr, err))
+}
+
+func TestRejectedItems(t *testing.T) {
+	rejected, ok := RejectedItems(NewRejectedError(errors.New("some error"), 3))
+	assert.True(t, ok)
+	assert.Equal(t, 3, rejected)
+
+	rejected, ok = RejectedItems(Permanent(fmt.Errorf("wrapped: %w", NewRejectedError(errors.New("some error"), 2))))
+	assert.True(t, ok)
+	assert.Equal(t, 2, rejected)
+
+	_, ok = RejectedItems(errors.New("some error"))
+	assert.False(t, ok)
+	_, ok = RejectedItems(nil)
+	assert.False(t, ok)
+}
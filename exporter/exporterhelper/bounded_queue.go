@@ -0,0 +1,138 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exporterhelper
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// boundedQueue is a FIFO queue of a bounded capacity consumed by a pool of goroutines, its
+// oldest items can be removed to make room for new items.
+type boundedQueue struct {
+	items   chan interface{}
+	stopped int32
+	stopCh  chan struct{}
+	stopWG  sync.WaitGroup
+}
+
+func newBoundedQueue(capacity int) *boundedQueue {
+	return &boundedQueue{
+		items:  make(chan interface{}, capacity),
+		stopCh: make(chan struct{}),
+	}
+}
+
+// StartConsumers starts num goroutines passing the items of the queue to the callback.
+func (q *boundedQueue) StartConsumers(num int, callback func(item interface{})) {
+	var startWG sync.WaitGroup
+	for i := 0; i < num; i++ {
+		q.stopWG.Add(1)
+		startWG.Add(1)
+		go func() {
+			startWG.Done()
+			defer q.stopWG.Done()
+			for {
+				select {
+				case item := <-q.items:
+					callback(item)
+				case <-q.stopCh:
+					// Produce refuses the items once stopped, drain the items left in the queue.
+					for {
+						select {
+						case item := <-q.items:
+							callback(item)
+						default:
+							return
+						}
+					}
+				}
+			}
+		}()
+	}
+	startWG.Wait()
+}
+
+// Produce adds the item to the queue, it returns false when the queue is full or stopped.
+func (q *boundedQueue) Produce(item interface{}) bool {
+	// A consumer waiting for an item would receive it from a queue of capacity 0.
+	if atomic.LoadInt32(&q.stopped) != 0 || cap(q.items) == 0 {
+		return false
+	}
+	select {
+	case q.items <- item:
+		return true
+	default:
+		return false
+	}
+}
+
+// ProduceDroppingOldest adds the item to the queue, removing the oldest items when the queue is
+// full. It returns the removed items, and false when the item could not be added because the
+// queue is stopped or its capacity is 0.
+func (q *boundedQueue) ProduceDroppingOldest(item interface{}) ([]interface{}, bool) {
+	if atomic.LoadInt32(&q.stopped) != 0 || cap(q.items) == 0 {
+		return nil, false
+	}
+	var dropped []interface{}
+	for {
+		select {
+		case q.items <- item:
+			return dropped, true
+		default:
+		}
+		if oldest, ok := q.DropOldest(); ok {
+			dropped = append(dropped, oldest)
+		}
+	}
+}
+
+// DropOldest removes the oldest item of the queue, ok is false when the queue is empty.
+func (q *boundedQueue) DropOldest() (item interface{}, ok bool) {
+	select {
+	case item = <-q.items:
+		return item, true
+	default:
+		return nil, false
+	}
+}
+
+// Stop stops the consumers once they have consumed the items left in the queue and waits for
+// them.
+func (q *boundedQueue) Stop() {
+	atomic.StoreInt32(&q.stopped, 1)
+	close(q.stopCh)
+	q.stopWG.Wait()
+}
+
+// Size returns the number of items in the queue.
+func (q *boundedQueue) Size() int {
+	return len(q.items)
+}
+
+// Capacity returns the maximum number of items in the queue.
+func (q *boundedQueue) Capacity() int {
+	return cap(q.items)
+}
+
+// Resize changes the capacity of the queue keeping its items, it must be called before the
+// consumers are started.
+func (q *boundedQueue) Resize(capacity int) {
+	items := make(chan interface{}, capacity)
+	for len(q.items) > 0 && len(items) < capacity {
+		items <- <-q.items
+	}
+	q.items = items
+}
@@ -0,0 +1,99 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exporterhelper
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBoundedQueue(t *testing.T) {
+	q := newBoundedQueue(2)
+	assert.True(t, q.Produce(1))
+	assert.True(t, q.Produce(2))
+	assert.False(t, q.Produce(3))
+	assert.Equal(t, 2, q.Size())
+	assert.Equal(t, 2, q.Capacity())
+
+	dropped, ok := q.ProduceDroppingOldest(3)
+	assert.True(t, ok)
+	assert.Equal(t, []interface{}{1}, dropped)
+
+	item, ok := q.DropOldest()
+	assert.True(t, ok)
+	assert.Equal(t, 2, item)
+
+	q.Resize(3)
+	assert.True(t, q.Produce(4))
+	assert.True(t, q.Produce(5))
+	assert.Equal(t, 3, q.Size())
+
+	var wg sync.WaitGroup
+	wg.Add(3)
+	var consumed []interface{}
+	q.StartConsumers(1, func(item interface{}) {
+		consumed = append(consumed, item)
+		wg.Done()
+	})
+	wg.Wait()
+	q.Stop()
+	assert.Equal(t, []interface{}{3, 4, 5}, consumed)
+	assert.False(t, q.Produce(6))
+	_, ok = q.ProduceDroppingOldest(6)
+	assert.False(t, ok)
+	_, ok = q.DropOldest()
+	assert.False(t, ok)
+}
+
+func TestBoundedQueue_StopDrains(t *testing.T) {
+	q := newBoundedQueue(3)
+	release := make(chan struct{})
+	var consumed []interface{}
+	q.StartConsumers(1, func(item interface{}) {
+		<-release
+		consumed = append(consumed, item)
+	})
+	assert.True(t, q.Produce(1))
+	// The first item is being consumed.
+	assert.Eventually(t, func() bool { return q.Size() == 0 }, time.Second, time.Millisecond)
+	assert.True(t, q.Produce(2))
+	assert.True(t, q.Produce(3))
+
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		q.Stop()
+	}()
+	assert.Eventually(t, func() bool { return !q.Produce(4) }, time.Second, time.Millisecond)
+	close(release)
+	<-stopped
+	assert.Equal(t, []interface{}{1, 2, 3}, consumed)
+	assert.Equal(t, 0, q.Size())
+}
+
+func TestBoundedQueue_ZeroCapacity(t *testing.T) {
+	q := newBoundedQueue(0)
+	q.StartConsumers(1, func(item interface{}) {
+		assert.Fail(t, "unexpected item")
+	})
+	assert.False(t, q.Produce(1))
+	_, ok := q.ProduceDroppingOldest(1)
+	require.False(t, ok)
+	q.Stop()
+}
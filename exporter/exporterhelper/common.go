@@ -77,6 +77,7 @@ type baseSettings struct {
 	QueueSettings
 	RetrySettings
 	ResourceToTelemetrySettings
+	errorClassifier ErrorClassifier
 }
 
 // fromOptions returns the internal options starting from the default and applying all configured options.
@@ -142,6 +143,15 @@ func WithQueue(queueSettings QueueSettings) Option {
 	}
 }
 
+// WithErrorClassifier sets the ErrorClassifier deciding which errors of the exporter are retried.
+// The default is to drop the errors wrapped with consumererror.Permanent, to retry the errors
+// returned by NewThrottleRetry after their delay and to retry the other errors with the backoff.
+func WithErrorClassifier(classifier ErrorClassifier) Option {
+	return func(o *baseSettings) {
+		o.errorClassifier = classifier
+	}
+}
+
 // WithResourceToTelemetryConversion overrides the default ResourceToTelemetrySettings for an exporter.
 // The default ResourceToTelemetrySettings is to disable resource attributes to metric labels conversion.
 func WithResourceToTelemetryConversion(resourceToTelemetrySettings ResourceToTelemetrySettings) Option {
@@ -167,7 +177,7 @@ func newBaseExporter(cfg configmodels.Exporter, logger *zap.Logger, options ...O
 		convertResourceToTelemetry: bs.ResourceToTelemetrySettings.Enabled,
 	}
 
-	be.qrSender = newQueuedRetrySender(cfg.Name(), bs.QueueSettings, bs.RetrySettings, &timeoutSender{cfg: bs.TimeoutSettings}, logger)
+	be.qrSender = newQueuedRetrySender(cfg.Name(), bs.QueueSettings, bs.RetrySettings, bs.errorClassifier, &timeoutSender{cfg: bs.TimeoutSettings}, logger)
 	be.sender = be.qrSender
 
 	return be
@@ -179,6 +189,19 @@ func (be *baseExporter) wrapConsumerSender(f func(consumer requestSender) reques
 	be.qrSender.consumerSender = f(be.qrSender.consumerSender)
 }
 
+// setRequestCodec sets the functions converting the requests to bytes and back, used to
+// persist the requests when the queue is persistent.
+func (be *baseExporter) setRequestCodec(marshal func(request) ([]byte, error), unmarshal func([]byte) (request, error)) {
+	be.qrSender.marshal = marshal
+	be.qrSender.unmarshal = unmarshal
+}
+
+// setSignalPriority sets the priority of the requests of the exporter, used to choose the requests
+// to drop with DropPolicyBySignalPriority.
+func (be *baseExporter) setSignalPriority(priority signalPriority) {
+	be.qrSender.priority = priority
+}
+
 // Start all senders and exporter and is invoked during service start.
 func (be *baseExporter) Start(ctx context.Context, host component.Host) error {
 	// First start the wrapped exporter.
@@ -187,16 +210,27 @@ func (be *baseExporter) Start(ctx context.Context, host component.Host) error {
 	}
 
 	// If no error then start the queuedRetrySender.
-	be.qrSender.start()
-	return nil
+	return be.qrSender.start(ctx, host)
 }
 
 // Shutdown all senders and exporter and is invoked during service shutdown.
 func (be *baseExporter) Shutdown(ctx context.Context) error {
-	// First shutdown the queued retry sender
-	be.qrSender.shutdown()
+	// First shutdown the queued retry sender, draining the queue until ctx is done.
+	var errs []error
+	if err := be.qrSender.shutdown(ctx); err != nil {
+		errs = append(errs, err)
+	}
 	// Last shutdown the wrapped exporter itself.
-	return be.Component.Shutdown(ctx)
+	if err := be.Component.Shutdown(ctx); err != nil {
+		errs = append(errs, err)
+	}
+	return consumererror.CombineErrors(errs)
+}
+
+// DrainStats implements component.Drainer, the items being sent when the context of Shutdown
+// is done are not counted.
+func (be *baseExporter) DrainStats() component.DrainStats {
+	return be.qrSender.drainStats()
 }
 
 // timeoutSender is a request sender that adds a `timeout` to every request that passes this sender.
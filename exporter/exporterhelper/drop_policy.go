@@ -0,0 +1,123 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exporterhelper
+
+import (
+	"sync"
+)
+
+// DropPolicy decides which requests are dropped when the sending_queue is full.
+type DropPolicy string
+
+const (
+	// DropPolicyNewest drops the new requests, this is the default.
+	DropPolicyNewest DropPolicy = "newest"
+	// DropPolicyOldest drops the oldest queued requests to make room for the new requests.
+	DropPolicyOldest DropPolicy = "oldest"
+	// DropPolicyBySignalPriority shares the queue_size between the signals of the exporter and
+	// drops the oldest queued requests of the lowest priority signal to make room for the new
+	// requests of a higher priority signal. The logs are dropped first, then the metrics, the
+	// traces having the highest priority.
+	DropPolicyBySignalPriority DropPolicy = "by_signal_priority"
+)
+
+// signalPriority is the priority of the requests of a signal with DropPolicyBySignalPriority.
+type signalPriority int
+
+const (
+	priorityLogs signalPriority = iota
+	priorityMetrics
+	priorityTraces
+)
+
+// priorityGroup is the queues of the signals of an exporter sharing the queue_size with
+// DropPolicyBySignalPriority.
+type priorityGroup struct {
+	name     string
+	capacity int
+
+	mu      sync.Mutex
+	members []*queuedRetrySender
+}
+
+var (
+	priorityGroupsMu sync.Mutex
+	priorityGroups   = map[string]*priorityGroup{}
+)
+
+// joinPriorityGroup adds the sender to the group of its exporter.
+func joinPriorityGroup(qrs *queuedRetrySender) *priorityGroup {
+	priorityGroupsMu.Lock()
+	defer priorityGroupsMu.Unlock()
+	g, ok := priorityGroups[qrs.fullName]
+	if !ok {
+		g = &priorityGroup{name: qrs.fullName, capacity: qrs.cfg.QueueSize}
+		priorityGroups[qrs.fullName] = g
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.members = append(g.members, qrs)
+	return g
+}
+
+// leave removes the sender from the group, the group is deleted when it has no members.
+func (g *priorityGroup) leave(qrs *queuedRetrySender) {
+	priorityGroupsMu.Lock()
+	defer priorityGroupsMu.Unlock()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for i, m := range g.members {
+		if m == qrs {
+			g.members = append(g.members[:i], g.members[i+1:]...)
+			break
+		}
+	}
+	if len(g.members) == 0 && priorityGroups[g.name] == g {
+		delete(priorityGroups, g.name)
+	}
+}
+
+// produce adds the item to the queue of the sender, the oldest request of a lower priority queue
+// is dropped when the queues of the group are full. It returns false when the item was not added.
+func (g *priorityGroup) produce(qrs *queuedRetrySender, item interface{}) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	size := 0
+	for _, m := range g.members {
+		size += m.queue.Size()
+	}
+	if size >= g.capacity {
+		victim := g.lowestPriorityLocked(qrs.priority)
+		if victim == nil || !victim.dropOldest() {
+			return false
+		}
+	}
+	return qrs.metrics.produce(func() (bool, int) { return qrs.queue.Produce(item), 0 }, qrs.queue.Size)
+}
+
+// lowestPriorityLocked returns the member with the lowest priority below the priority having
+// queued requests, or nil when there is none.
+func (g *priorityGroup) lowestPriorityLocked(priority signalPriority) *queuedRetrySender {
+	var victim *queuedRetrySender
+	for _, m := range g.members {
+		if m.priority >= priority || m.queue.Size() == 0 {
+			continue
+		}
+		if victim == nil || m.priority < victim.priority {
+			victim = m
+		}
+	}
+	return victim
+}
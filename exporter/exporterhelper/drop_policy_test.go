@@ -0,0 +1,139 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exporterhelper
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal/testdata"
+)
+
+func dropPolicyQueueSettings(policy DropPolicy) QueueSettings {
+	qCfg := DefaultQueueSettings()
+	qCfg.NumConsumers = 0
+	qCfg.QueueSize = 2
+	qCfg.DropPolicy = policy
+	return qCfg
+}
+
+// queuedItems removes the items of the queue and returns them.
+func queuedItems(q *boundedQueue) []interface{} {
+	var items []interface{}
+	for {
+		item, ok := q.DropOldest()
+		if !ok {
+			return items
+		}
+		items = append(items, item)
+	}
+}
+
+func TestQueuedRetry_DropPolicyNewest(t *testing.T) {
+	be := newBaseExporter(defaultExporterCfg, zap.NewNop(), WithQueue(dropPolicyQueueSettings(DropPolicyNewest)))
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, be.Shutdown(context.Background()))
+	})
+
+	reqs := []*mockRequest{
+		newMockRequest(context.Background(), 1, nil),
+		newMockRequest(context.Background(), 2, nil),
+		newMockRequest(context.Background(), 3, nil),
+	}
+	for _, req := range reqs[:2] {
+		_, err := be.sender.send(req)
+		require.NoError(t, err)
+	}
+	droppedItems, err := be.sender.send(reqs[2])
+	require.Error(t, err)
+	assert.Equal(t, 3, droppedItems)
+	assert.Equal(t, []interface{}{reqs[0], reqs[1]}, queuedItems(be.qrSender.queue))
+}
+
+func TestQueuedRetry_DropPolicyOldest(t *testing.T) {
+	require.NoError(t, view.Register(MetricViews()...))
+	defer view.Unregister(MetricViews()...)
+
+	be := newBaseExporter(defaultExporterCfg, zap.NewNop(), WithQueue(dropPolicyQueueSettings(DropPolicyOldest)))
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, be.Shutdown(context.Background()))
+	})
+
+	reqs := []*mockRequest{
+		newMockRequest(context.Background(), 1, nil),
+		newMockRequest(context.Background(), 2, nil),
+		newMockRequest(context.Background(), 3, nil),
+	}
+	for _, req := range reqs {
+		droppedItems, err := be.sender.send(req)
+		require.NoError(t, err)
+		assert.Equal(t, 0, droppedItems)
+	}
+	assert.Equal(t, []interface{}{reqs[1], reqs[2]}, queuedItems(be.qrSender.queue))
+	assert.Len(t, be.qrSender.metrics.enqueueTimes, 2)
+
+	value, ok := exporterMetric(t, vQueueDroppedRequests)
+	require.True(t, ok)
+	assert.Equal(t, float64(1), value)
+}
+
+func TestQueuedRetry_DropPolicyBySignalPriority(t *testing.T) {
+	qCfg := dropPolicyQueueSettings(DropPolicyBySignalPriority)
+	te, err := NewTraceExporter(defaultExporterCfg, zap.NewNop(), func(context.Context, pdata.Traces) (int, error) { return 0, nil }, WithQueue(qCfg))
+	require.NoError(t, err)
+	me, err := NewMetricsExporter(defaultExporterCfg, zap.NewNop(), func(context.Context, pdata.Metrics) (int, error) { return 0, nil }, WithQueue(qCfg))
+	require.NoError(t, err)
+	require.NoError(t, te.Start(context.Background(), componenttest.NewNopHost()))
+	require.NoError(t, me.Start(context.Background(), componenttest.NewNopHost()))
+	tq := te.(*traceExporter).qrSender.queue
+	mq := me.(*metricsExporter).qrSender.queue
+
+	require.NoError(t, me.ConsumeMetrics(context.Background(), testdata.GenerateMetricsOneMetric()))
+	require.NoError(t, te.ConsumeTraces(context.Background(), testdata.GenerateTraceDataOneSpan()))
+	assert.Equal(t, 1, mq.Size())
+	assert.Equal(t, 1, tq.Size())
+
+	// The queues are full, the metrics cannot drop the traces.
+	assert.Error(t, me.ConsumeMetrics(context.Background(), testdata.GenerateMetricsOneMetric()))
+	assert.Equal(t, 1, mq.Size())
+
+	// The traces drop the metrics.
+	require.NoError(t, te.ConsumeTraces(context.Background(), testdata.GenerateTraceDataOneSpan()))
+	assert.Equal(t, 0, mq.Size())
+	assert.Equal(t, 2, tq.Size())
+
+	// No lower priority queued requests are left.
+	assert.Error(t, te.ConsumeTraces(context.Background(), testdata.GenerateTraceDataOneSpan()))
+	assert.Equal(t, 2, tq.Size())
+
+	assert.Len(t, priorityGroups[defaultExporterCfg.Name()].members, 2)
+	assert.NoError(t, te.Shutdown(context.Background()))
+	assert.NoError(t, me.Shutdown(context.Background()))
+	assert.NotContains(t, priorityGroups, defaultExporterCfg.Name())
+}
+
+func TestQueuedRetry_InvalidDropPolicy(t *testing.T) {
+	be := newBaseExporter(defaultExporterCfg, zap.NewNop(), WithQueue(dropPolicyQueueSettings("random")))
+	assert.EqualError(t, be.Start(context.Background(), componenttest.NewNopHost()), `unknown sending_queue.drop_policy "random"`)
+}
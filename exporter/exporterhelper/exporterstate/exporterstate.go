@@ -0,0 +1,100 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package exporterstate shares the state of the exporters with the other components, e.g. the
+// health check extension reporting the exporters falling behind or failing to export.
+package exporterstate
+
+import (
+	"sync"
+	"time"
+)
+
+// Queue is a sending queue of an exporter.
+type Queue interface {
+	// Size returns the number of requests in the queue.
+	Size() int
+	// Capacity returns the maximum number of requests in the queue.
+	Capacity() int
+}
+
+var (
+	mu     sync.RWMutex
+	queues = map[Queue]string{}
+)
+
+// Register registers the queue of the exporter with the given full name, and returns the
+// function unregistering it. An exporter has a queue per signal.
+func Register(exporter string, q Queue) func() {
+	mu.Lock()
+	queues[q] = exporter
+	mu.Unlock()
+	return func() {
+		mu.Lock()
+		delete(queues, q)
+		mu.Unlock()
+	}
+}
+
+// Saturation returns the ratio of the capacity of the sending queues used by each exporter,
+// between 0 and 1, the queues of the signals of an exporter being summed.
+func Saturation() map[string]float64 {
+	mu.RLock()
+	defer mu.RUnlock()
+	sizes := map[string]int{}
+	capacities := map[string]int{}
+	for q, exporter := range queues {
+		sizes[exporter] += q.Size()
+		capacities[exporter] += q.Capacity()
+	}
+	saturation := make(map[string]float64, len(sizes))
+	for exporter, size := range sizes {
+		if capacities[exporter] == 0 {
+			continue
+		}
+		s := float64(size) / float64(capacities[exporter])
+		if s > 1 {
+			s = 1
+		}
+		saturation[exporter] = s
+	}
+	return saturation
+}
+
+var (
+	failuresMu   sync.Mutex
+	failingSince = map[string]time.Time{}
+)
+
+// RecordExport records the result of an export attempt of the exporter with the given full name.
+func RecordExport(exporter string, err error) {
+	failuresMu.Lock()
+	defer failuresMu.Unlock()
+	if err == nil {
+		delete(failingSince, exporter)
+		return
+	}
+	if _, ok := failingSince[exporter]; !ok {
+		failingSince[exporter] = time.Now()
+	}
+}
+
+// FailingSince returns the time of the first of the failed exports of the exporter following its
+// last successful export, and false when its last export succeeded or it did not export yet.
+func FailingSince(exporter string) (time.Time, bool) {
+	failuresMu.Lock()
+	defer failuresMu.Unlock()
+	since, ok := failingSince[exporter]
+	return since, ok
+}
@@ -0,0 +1,80 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exporterstate
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fixedQueue struct {
+	size     int
+	capacity int
+}
+
+func (q *fixedQueue) Size() int {
+	return q.size
+}
+
+func (q *fixedQueue) Capacity() int {
+	return q.capacity
+}
+
+func TestSaturation(t *testing.T) {
+	assert.Empty(t, Saturation())
+
+	unregisterTraces := Register("otlp", &fixedQueue{size: 9, capacity: 10})
+	unregisterMetrics := Register("otlp", &fixedQueue{size: 1, capacity: 10})
+	unregisterOther := Register("otlp/2", &fixedQueue{size: 0, capacity: 10})
+	unregisterEmpty := Register("otlp/3", &fixedQueue{})
+	assert.Equal(t, map[string]float64{"otlp": 0.5, "otlp/2": 0}, Saturation())
+
+	unregisterMetrics()
+	assert.Equal(t, map[string]float64{"otlp": 0.9, "otlp/2": 0}, Saturation())
+
+	unregisterTraces()
+	unregisterOther()
+	unregisterEmpty()
+	assert.Empty(t, Saturation())
+}
+
+func TestFailingSince(t *testing.T) {
+	_, ok := FailingSince("otlp")
+	assert.False(t, ok)
+
+	RecordExport("otlp", nil)
+	_, ok = FailingSince("otlp")
+	assert.False(t, ok)
+
+	before := time.Now()
+	RecordExport("otlp", errors.New("export failed"))
+	since, ok := FailingSince("otlp")
+	assert.True(t, ok)
+	assert.False(t, since.Before(before))
+
+	RecordExport("otlp", errors.New("export failed"))
+	again, ok := FailingSince("otlp")
+	assert.True(t, ok)
+	assert.Equal(t, since, again)
+	_, ok = FailingSince("otlp/2")
+	assert.False(t, ok)
+
+	RecordExport("otlp", nil)
+	_, ok = FailingSince("otlp")
+	assert.False(t, ok)
+}
@@ -102,6 +102,19 @@ func (f *factory) Type() configmodels.Type {
 	return f.cfgType
 }
 
+// SupportsDataType returns true if the factory creates the exporters of the data type.
+func (f *factory) SupportsDataType(dataType configmodels.DataType) bool {
+	switch dataType {
+	case configmodels.TracesDataType:
+		return f.createTraceExporter != nil
+	case configmodels.MetricsDataType:
+		return f.createMetricsExporter != nil
+	case configmodels.LogsDataType:
+		return f.createLogsExporter != nil
+	}
+	return false
+}
+
 // CreateDefaultConfig creates the default configuration for processor.
 func (f *factory) CreateDefaultConfig() configmodels.Exporter {
 	return f.createDefaultConfig()
@@ -55,6 +55,10 @@ func TestNewFactory(t *testing.T) {
 	assert.EqualValues(t, defaultCfg, factory.CreateDefaultConfig())
 	_, ok := factory.(component.ConfigUnmarshaler)
 	assert.False(t, ok)
+	ds := factory.(component.DataTypeSupporter)
+	assert.False(t, ds.SupportsDataType(configmodels.TracesDataType))
+	assert.False(t, ds.SupportsDataType(configmodels.MetricsDataType))
+	assert.False(t, ds.SupportsDataType(configmodels.LogsDataType))
 	_, err := factory.CreateTracesExporter(context.Background(), component.ExporterCreateParams{Logger: zap.NewNop()}, defaultCfg)
 	assert.Equal(t, configerror.ErrDataTypeIsNotSupported, err)
 	_, err = factory.CreateMetricsExporter(context.Background(), component.ExporterCreateParams{Logger: zap.NewNop()}, defaultCfg)
@@ -78,6 +82,11 @@ func TestNewFactory_WithConstructors(t *testing.T) {
 	assert.True(t, ok)
 	assert.Equal(t, errors.New("my error"), fu.Unmarshal(nil, nil))
 
+	ds := factory.(component.DataTypeSupporter)
+	assert.True(t, ds.SupportsDataType(configmodels.TracesDataType))
+	assert.True(t, ds.SupportsDataType(configmodels.MetricsDataType))
+	assert.True(t, ds.SupportsDataType(configmodels.LogsDataType))
+
 	te, err := factory.CreateTracesExporter(context.Background(), component.ExporterCreateParams{Logger: zap.NewNop()}, defaultCfg)
 	assert.NoError(t, err)
 	assert.Same(t, nopTracesExporter, te)
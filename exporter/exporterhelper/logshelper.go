@@ -57,6 +57,18 @@ func (req *logsRequest) count() int {
 	return req.ld.LogRecordCount()
 }
 
+func (req *logsRequest) marshal() ([]byte, error) {
+	return req.ld.ToOtlpProtoBytes()
+}
+
+func (req *logsRequest) split() (request, request, bool) {
+	first, second, ok := splitLogs(req.ld)
+	if !ok {
+		return nil, nil, false
+	}
+	return newLogsRequest(req.ctx, first, req.pusher), newLogsRequest(req.ctx, second, req.pusher), true
+}
+
 type logsExporter struct {
 	*baseExporter
 	pusher PushLogs
@@ -88,6 +100,18 @@ func NewLogsExporter(
 	}
 
 	be := newBaseExporter(cfg, logger, options...)
+	be.setSignalPriority(priorityLogs)
+	be.setRequestCodec(
+		func(req request) ([]byte, error) {
+			return req.(*logsRequest).marshal()
+		},
+		func(data []byte) (request, error) {
+			ld := pdata.NewLogs()
+			if err := ld.FromOtlpProtoBytes(data); err != nil {
+				return nil, err
+			}
+			return newLogsRequest(obsreport.ExporterContext(context.Background(), cfg.Name()), ld, pusher), nil
+		})
 	be.wrapConsumerSender(func(nextSender requestSender) requestSender {
 		return &logsExporterWithObservability{
 			obsrep:     obsreport.NewExporter(configtelemetry.GetMetricsLevelFlagValue(), cfg.Name()),
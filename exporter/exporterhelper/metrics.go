@@ -0,0 +1,161 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exporterhelper
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+
+	"go.opentelemetry.io/collector/obsreport"
+)
+
+// defaultQueueMetricsInterval is the interval between the reports of the sending_queue metrics.
+const defaultQueueMetricsInterval = 10 * time.Second
+
+var (
+	mSplitRequests          = stats.Int64("exporter/split_requests", "Number of requests split in two because they were too large", stats.UnitDimensionless)
+	mRetries                = stats.Int64("exporter/retries", "Number of retries of the requests that failed to be exported", stats.UnitDimensionless)
+	mQueueSize              = stats.Int64("exporter/queue_size", "Current number of requests in the sending_queue", stats.UnitDimensionless)
+	mQueueCapacity          = stats.Int64("exporter/queue_capacity", "Maximum number of requests in the sending_queue", stats.UnitDimensionless)
+	mQueueSizeHighWatermark = stats.Int64("exporter/queue_size_high_watermark", "Highest number of requests in the sending_queue since the exporter started", stats.UnitDimensionless)
+	mQueueOldestItemAge     = stats.Int64("exporter/queue_oldest_item_age", "Time spent in the sending_queue by its oldest request", stats.UnitMilliseconds)
+	mEnqueueFailedRequests  = stats.Int64("exporter/enqueue_failed_requests", "Number of requests dropped because they could not be added to the sending_queue", stats.UnitDimensionless)
+	mQueueDroppedRequests   = stats.Int64("exporter/queue_dropped_requests", "Number of queued requests dropped by the drop_policy to make room for other requests", stats.UnitDimensionless)
+
+	vSplitRequests          = newExporterView(mSplitRequests, view.Sum())
+	vRetries                = newExporterView(mRetries, view.Sum())
+	vQueueSize              = newExporterView(mQueueSize, view.LastValue())
+	vQueueCapacity          = newExporterView(mQueueCapacity, view.LastValue())
+	vQueueSizeHighWatermark = newExporterView(mQueueSizeHighWatermark, view.LastValue())
+	vQueueOldestItemAge     = newExporterView(mQueueOldestItemAge, view.LastValue())
+	vEnqueueFailedRequests  = newExporterView(mEnqueueFailedRequests, view.Sum())
+	vQueueDroppedRequests   = newExporterView(mQueueDroppedRequests, view.Sum())
+)
+
+// newExporterView returns the view of the measure tagged with the exporter name.
+func newExporterView(measure *stats.Int64Measure, aggregation *view.Aggregation) *view.View {
+	return &view.View{
+		Name:        measure.Name(),
+		Measure:     measure,
+		Description: measure.Description(),
+		Aggregation: aggregation,
+		TagKeys: []tag.Key{
+			tag.MustNewKey(obsreport.ExporterKey),
+		},
+	}
+}
+
+// MetricViews return the metrics views of the exporter helpers.
+func MetricViews() []*view.View {
+	return []*view.View{
+		vSplitRequests,
+		vRetries,
+		vQueueSize,
+		vQueueCapacity,
+		vQueueSizeHighWatermark,
+		vQueueOldestItemAge,
+		vEnqueueFailedRequests,
+		vQueueDroppedRequests,
+	}
+}
+
+// queueMetrics tracks the requests of a sending_queue to report its metrics. The queue being
+// FIFO, the enqueue time of its oldest request is the first of the enqueue times.
+type queueMetrics struct {
+	ctx           context.Context
+	mu            sync.Mutex
+	enqueueTimes  []time.Time
+	highWatermark int
+}
+
+func newQueueMetrics(fullName string) *queueMetrics {
+	return &queueMetrics{ctx: obsreport.ExporterContext(context.Background(), fullName)}
+}
+
+// produce adds an item to the queue with the produce function, returning whether the item was
+// added and the number of oldest items it dropped from the queue, and tracks the added item.
+func (qm *queueMetrics) produce(produce func() (added bool, dropped int), size func() int) bool {
+	qm.mu.Lock()
+	defer qm.mu.Unlock()
+	added, dropped := produce()
+	qm.removeOldestLocked(dropped)
+	if !added {
+		return false
+	}
+	qm.enqueueTimes = append(qm.enqueueTimes, time.Now())
+	if s := size(); s > qm.highWatermark {
+		qm.highWatermark = s
+	}
+	return true
+}
+
+// consumed stops tracking the oldest request, which was taken from the queue.
+func (qm *queueMetrics) consumed() {
+	qm.mu.Lock()
+	defer qm.mu.Unlock()
+	qm.removeOldestLocked(1)
+}
+
+// dropOldest drops the oldest item of the queue with the drop function, returning whether an
+// item was dropped, and stops tracking it.
+func (qm *queueMetrics) dropOldest(drop func() bool) bool {
+	qm.mu.Lock()
+	defer qm.mu.Unlock()
+	if !drop() {
+		return false
+	}
+	qm.removeOldestLocked(1)
+	return true
+}
+
+func (qm *queueMetrics) removeOldestLocked(n int) {
+	if n > len(qm.enqueueTimes) {
+		n = len(qm.enqueueTimes)
+	}
+	qm.enqueueTimes = qm.enqueueTimes[n:]
+}
+
+// enqueueFailed records a request that could not be added to the queue.
+func (qm *queueMetrics) enqueueFailed() {
+	stats.Record(qm.ctx, mEnqueueFailedRequests.M(1))
+}
+
+// queuedDropped records a queued request dropped by the drop_policy.
+func (qm *queueMetrics) queuedDropped() {
+	stats.Record(qm.ctx, mQueueDroppedRequests.M(1))
+}
+
+// report records the current state of the queue.
+func (qm *queueMetrics) report(size int, capacity int) {
+	qm.mu.Lock()
+	var age time.Duration
+	if len(qm.enqueueTimes) > 0 {
+		age = time.Since(qm.enqueueTimes[0])
+	}
+	highWatermark := qm.highWatermark
+	qm.mu.Unlock()
+	stats.Record(
+		qm.ctx,
+		mQueueSize.M(int64(size)),
+		mQueueCapacity.M(int64(capacity)),
+		mQueueSizeHighWatermark.M(int64(highWatermark)),
+		mQueueOldestItemAge.M(age.Milliseconds()),
+	)
+}
@@ -0,0 +1,134 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exporterhelper
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/obsreport"
+)
+
+// exporterMetric returns the value of the metric of the test exporter, ok is false when it was
+// not recorded.
+func exporterMetric(t *testing.T, v *view.View) (value float64, ok bool) {
+	rows, err := view.RetrieveData(v.Name)
+	require.NoError(t, err)
+	for _, row := range rows {
+		if len(row.Tags) != 1 || row.Tags[0] != (tag.Tag{Key: tag.MustNewKey(obsreport.ExporterKey), Value: defaultExporterCfg.Name()}) {
+			continue
+		}
+		switch data := row.Data.(type) {
+		case *view.SumData:
+			return data.Value, true
+		case *view.LastValueData:
+			return data.Value, true
+		}
+	}
+	return 0, false
+}
+
+func TestQueuedRetry_QueueMetrics(t *testing.T) {
+	require.NoError(t, view.Register(MetricViews()...))
+	defer view.Unregister(MetricViews()...)
+
+	qCfg := DefaultQueueSettings()
+	qCfg.NumConsumers = 0
+	qCfg.QueueSize = 2
+	be := newBaseExporter(defaultExporterCfg, zap.NewNop(), WithQueue(qCfg))
+	be.qrSender.metricsInterval = time.Millisecond
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, be.Shutdown(context.Background()))
+	})
+
+	for i := 0; i < 2; i++ {
+		droppedItems, err := be.sender.send(newMockRequest(context.Background(), 2, nil))
+		require.NoError(t, err)
+		assert.Equal(t, 0, droppedItems)
+	}
+	_, err := be.sender.send(newMockRequest(context.Background(), 2, nil))
+	require.Error(t, err)
+
+	value, ok := exporterMetric(t, vEnqueueFailedRequests)
+	require.True(t, ok)
+	assert.Equal(t, float64(1), value)
+
+	time.Sleep(5 * time.Millisecond)
+	assert.Eventually(t, func() bool {
+		age, ok := exporterMetric(t, vQueueOldestItemAge)
+		return ok && age >= 5
+	}, time.Second, time.Millisecond)
+	for _, v := range []*view.View{vQueueSize, vQueueCapacity, vQueueSizeHighWatermark} {
+		value, ok := exporterMetric(t, v)
+		require.True(t, ok, v.Name)
+		assert.Equal(t, float64(2), value, v.Name)
+	}
+}
+
+func TestQueueMetrics_Consumed(t *testing.T) {
+	qm := newQueueMetrics("test")
+	produced := 0
+	size := func() int { return produced }
+	for i := 0; i < 3; i++ {
+		assert.True(t, qm.produce(func() (bool, int) { produced++; return true, 0 }, size))
+	}
+	assert.False(t, qm.produce(func() (bool, int) { return false, 0 }, size))
+	first := qm.enqueueTimes[1]
+	qm.consumed()
+	produced--
+	assert.Equal(t, first, qm.enqueueTimes[0])
+	assert.Len(t, qm.enqueueTimes, 2)
+	assert.Equal(t, 3, qm.highWatermark)
+	qm.consumed()
+	qm.consumed()
+	qm.consumed()
+	assert.Empty(t, qm.enqueueTimes)
+}
+
+func TestQueuedRetry_RetryMetric(t *testing.T) {
+	require.NoError(t, view.Register(MetricViews()...))
+	defer view.Unregister(MetricViews()...)
+
+	qCfg := DefaultQueueSettings()
+	qCfg.Enabled = false
+	rCfg := DefaultRetrySettings()
+	rCfg.InitialInterval = 0
+	be := newBaseExporter(defaultExporterCfg, zap.NewNop(), WithRetry(rCfg), WithQueue(qCfg))
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, be.Shutdown(context.Background()))
+	})
+
+	ctx := obsreport.ExporterContext(context.Background(), defaultExporterCfg.Name())
+	mockR := newMockRequest(ctx, 2, errors.New("transient error"))
+	droppedItems, err := be.sender.send(mockR)
+	require.NoError(t, err)
+	assert.Equal(t, 0, droppedItems)
+	mockR.checkNumRequests(t, 2)
+
+	value, ok := exporterMetric(t, vRetries)
+	require.True(t, ok)
+	assert.Equal(t, float64(1), value)
+}
@@ -58,6 +58,18 @@ func (req *metricsRequest) count() int {
 	return numPoints
 }
 
+func (req *metricsRequest) marshal() ([]byte, error) {
+	return req.md.ToOtlpProtoBytes()
+}
+
+func (req *metricsRequest) split() (request, request, bool) {
+	first, second, ok := splitMetrics(req.md)
+	if !ok {
+		return nil, nil, false
+	}
+	return newMetricsRequest(req.ctx, first, req.pusher), newMetricsRequest(req.ctx, second, req.pusher), true
+}
+
 type metricsExporter struct {
 	*baseExporter
 	pusher PushMetrics
@@ -93,6 +105,18 @@ func NewMetricsExporter(
 	}
 
 	be := newBaseExporter(cfg, logger, options...)
+	be.setSignalPriority(priorityMetrics)
+	be.setRequestCodec(
+		func(req request) ([]byte, error) {
+			return req.(*metricsRequest).marshal()
+		},
+		func(data []byte) (request, error) {
+			md := pdata.NewMetrics()
+			if err := md.FromOtlpProtoBytes(data); err != nil {
+				return nil, err
+			}
+			return newMetricsRequest(obsreport.ExporterContext(context.Background(), cfg.Name()), md, pusher), nil
+		})
 	be.wrapConsumerSender(func(nextSender requestSender) requestSender {
 		return &metricsSenderWithObservability{
 			obsrep:     obsreport.NewExporter(configtelemetry.GetMetricsLevelFlagValue(), cfg.Name()),
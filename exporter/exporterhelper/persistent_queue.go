@@ -0,0 +1,172 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exporterhelper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+
+	"go.opentelemetry.io/collector/extension/storage"
+)
+
+var (
+	errNoPersistentDirectory      = errors.New("sending_queue.persistent.directory must be set")
+	errPersistentDirectoryStorage = errors.New("sending_queue.persistent.directory and storage cannot be both set")
+	errPersistentQueueFull        = errors.New("persistent sending_queue is full")
+)
+
+// PersistentQueueSettings defines configuration for persisting the queued batches on disk, so
+// that they are sent after a restart of the collector instead of being lost.
+type PersistentQueueSettings struct {
+	// Directory is the directory where the queued batches are persisted, it must not be
+	// shared with other exporters.
+	Directory string `mapstructure:"directory"`
+	// Storage is the full name of the storage extension persisting the queued batches,
+	// instead of Directory.
+	Storage string `mapstructure:"storage"`
+	// MaxSizeMiB is the maximum size on disk of the persisted batches, 0 meaning unlimited.
+	// The batches are dropped once this size is reached.
+	MaxSizeMiB int `mapstructure:"max_size_mib"`
+}
+
+// persistedRequest is a request queued in the persistent queue, of which the key is deleted
+// once it is sent or dropped.
+type persistedRequest struct {
+	request
+	path string
+}
+
+// persistentStore keeps the queued requests in a storage client, one key per request named
+// after the sequence number of the request.
+type persistentStore struct {
+	client  storage.Client
+	maxSize int64
+
+	mu     sync.Mutex
+	size   int64
+	sizes  map[string]int64
+	nextID uint64
+}
+
+// openPersistentStore opens the store of the directory, creating it if needed, and returns the
+// keys of the requests persisted by a previous run, in the order they were queued.
+func openPersistentStore(cfg *PersistentQueueSettings) (*persistentStore, []string, error) {
+	if cfg.Directory == "" {
+		return nil, nil, errNoPersistentDirectory
+	}
+	client, err := storage.NewFileClient(cfg.Directory)
+	if err != nil {
+		return nil, nil, err
+	}
+	return newPersistentStore(client, cfg.MaxSizeMiB)
+}
+
+// newPersistentStore returns the store of the client and the keys of the requests persisted
+// by a previous run, in the order they were queued. The other keys of the client are ignored.
+func newPersistentStore(client storage.Client, maxSizeMiB int) (*persistentStore, []string, error) {
+	keys, err := client.Keys(context.Background())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s := &persistentStore{client: client, maxSize: int64(maxSizeMiB) * 1024 * 1024, sizes: map[string]int64{}}
+	var ids []uint64
+	for _, key := range keys {
+		id, err := strconv.ParseUint(key, 10, 64)
+		if err != nil || key != s.path(id) {
+			continue
+		}
+		// The values are read to know their size, the store has no other use of them.
+		data, err := client.Get(context.Background(), key)
+		if err != nil {
+			return nil, nil, err
+		}
+		ids = append(ids, id)
+		s.sizes[key] = int64(len(data))
+		s.size += int64(len(data))
+		if id >= s.nextID {
+			s.nextID = id + 1
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	paths := make([]string, len(ids))
+	for i, id := range ids {
+		paths[i] = s.path(id)
+	}
+	return s, paths, nil
+}
+
+func (s *persistentStore) path(id uint64) string {
+	return fmt.Sprintf("%020d", id)
+}
+
+// put persists a request, the request is persisted when the key is returned.
+func (s *persistentStore) put(data []byte) (string, error) {
+	s.mu.Lock()
+	if s.maxSize > 0 && s.size+int64(len(data)) > s.maxSize {
+		s.mu.Unlock()
+		return "", errPersistentQueueFull
+	}
+	id := s.nextID
+	s.nextID++
+	s.size += int64(len(data))
+	s.mu.Unlock()
+
+	path := s.path(id)
+	if err := s.client.Set(context.Background(), path, data); err != nil {
+		s.release(int64(len(data)))
+		return "", err
+	}
+	s.mu.Lock()
+	s.sizes[path] = int64(len(data))
+	s.mu.Unlock()
+	return path, nil
+}
+
+// get returns a persisted request.
+func (s *persistentStore) get(path string) ([]byte, error) {
+	data, err := s.client.Get(context.Background(), path)
+	if err == nil && data == nil {
+		err = fmt.Errorf("request %q not found", path)
+	}
+	return data, err
+}
+
+// remove removes a persisted request.
+func (s *persistentStore) remove(path string) error {
+	if err := s.client.Delete(context.Background(), path); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.size -= s.sizes[path]
+	delete(s.sizes, path)
+	s.mu.Unlock()
+	return nil
+}
+
+// close closes the client of the store.
+func (s *persistentStore) close() error {
+	return s.client.Close(context.Background())
+}
+
+func (s *persistentStore) release(size int64) {
+	s.mu.Lock()
+	s.size -= size
+	s.mu.Unlock()
+}
@@ -0,0 +1,359 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exporterhelper
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/extension/storage"
+	"go.opentelemetry.io/collector/internal/testdata"
+	"go.opentelemetry.io/collector/testutil"
+)
+
+func newTempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "persistent_queue")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return dir
+}
+
+func persistedFiles(t *testing.T, dir string) []string {
+	infos, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	var names []string
+	for _, info := range infos {
+		names = append(names, info.Name())
+	}
+	return names
+}
+
+func persistentQueueSettings(dir string) QueueSettings {
+	qCfg := DefaultQueueSettings()
+	qCfg.NumConsumers = 1
+	qCfg.Persistent = &PersistentQueueSettings{Directory: dir}
+	return qCfg
+}
+
+func TestPersistentStore(t *testing.T) {
+	dir := newTempDir(t)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "00000000000000000007.tmp"), []byte("partial"), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "other"), []byte("other"), 0600))
+
+	store, paths, err := openPersistentStore(&PersistentQueueSettings{Directory: dir})
+	require.NoError(t, err)
+	assert.Empty(t, paths)
+	assert.Equal(t, []string{"other"}, persistedFiles(t, dir))
+
+	first, err := store.put([]byte("first"))
+	require.NoError(t, err)
+	second, err := store.put([]byte("second"))
+	require.NoError(t, err)
+	third, err := store.put([]byte("third"))
+	require.NoError(t, err)
+	assert.EqualValues(t, 16, store.size)
+	require.NoError(t, store.remove(second))
+	assert.EqualValues(t, 10, store.size)
+
+	store, paths, err = openPersistentStore(&PersistentQueueSettings{Directory: dir})
+	require.NoError(t, err)
+	assert.Equal(t, []string{first, third}, paths)
+	assert.EqualValues(t, 10, store.size)
+	fourth, err := store.put([]byte("fourth"))
+	require.NoError(t, err)
+	assert.Equal(t, store.path(3), fourth)
+
+	store.maxSize = 20
+	_, err = store.put([]byte("fifth"))
+	assert.Equal(t, errPersistentQueueFull, err)
+	assert.EqualValues(t, 16, store.size)
+
+	_, _, err = openPersistentStore(&PersistentQueueSettings{})
+	assert.Equal(t, errNoPersistentDirectory, err)
+}
+
+func TestPersistentQueue_SendAfterRestart(t *testing.T) {
+	dir := newTempDir(t)
+	rCfg := DefaultRetrySettings()
+	rCfg.InitialInterval = 10 * time.Millisecond
+	rCfg.MaxElapsedTime = 0
+	var attempts int32
+	te, err := NewTraceExporter(defaultExporterCfg, zap.NewNop(), func(context.Context, pdata.Traces) (int, error) {
+		atomic.AddInt32(&attempts, 1)
+		return 0, errors.New("backend unavailable")
+	}, WithQueue(persistentQueueSettings(dir)), WithRetry(rCfg))
+	require.NoError(t, err)
+	require.NoError(t, te.Start(context.Background(), componenttest.NewNopHost()))
+
+	require.NoError(t, te.ConsumeTraces(context.Background(), testdata.GenerateTraceDataOneSpan()))
+	require.NoError(t, te.ConsumeTraces(context.Background(), testdata.GenerateTraceDataTwoSpansSameResource()))
+	assert.Len(t, persistedFiles(t, dir), 2)
+	testutil.WaitFor(t, func() bool {
+		return atomic.LoadInt32(&attempts) > 1
+	}, "retry a request")
+	require.NoError(t, te.Shutdown(context.Background()))
+	// The requests of which the retries were interrupted are still persisted.
+	assert.Len(t, persistedFiles(t, dir), 2)
+
+	var mu sync.Mutex
+	var received []pdata.Traces
+	te, err = NewTraceExporter(defaultExporterCfg, zap.NewNop(), func(_ context.Context, td pdata.Traces) (int, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		received = append(received, td)
+		return 0, nil
+	}, WithQueue(persistentQueueSettings(dir)), WithRetry(rCfg))
+	require.NoError(t, err)
+	require.NoError(t, te.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() {
+		assert.NoError(t, te.Shutdown(context.Background()))
+	}()
+
+	testutil.WaitFor(t, func() bool {
+		return len(persistedFiles(t, dir)) == 0
+	}, "send the persisted requests")
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []pdata.Traces{testdata.GenerateTraceDataOneSpan(), testdata.GenerateTraceDataTwoSpansSameResource()}, received)
+}
+
+func TestPersistentQueue_AllSignals(t *testing.T) {
+	dir := newTempDir(t)
+	var mu sync.Mutex
+	var received []interface{}
+	record := func(data interface{}) (int, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		received = append(received, data)
+		return 0, nil
+	}
+
+	qCfg := persistentQueueSettings(filepath.Join(dir, "metrics"))
+	me, err := NewMetricsExporter(defaultExporterCfg, zap.NewNop(), func(_ context.Context, md pdata.Metrics) (int, error) {
+		return record(md)
+	}, WithQueue(qCfg))
+	require.NoError(t, err)
+	qCfg = persistentQueueSettings(filepath.Join(dir, "logs"))
+	le, err := NewLogsExporter(defaultExporterCfg, zap.NewNop(), func(_ context.Context, ld pdata.Logs) (int, error) {
+		return record(ld)
+	}, WithQueue(qCfg))
+	require.NoError(t, err)
+
+	// The requests persisted before the exporter is started are sent when it starts.
+	metricsStore, _, err := openPersistentStore(persistentQueueSettings(filepath.Join(dir, "metrics")).Persistent)
+	require.NoError(t, err)
+	data, err := testdata.GenerateMetricsOneMetric().ToOtlpProtoBytes()
+	require.NoError(t, err)
+	_, err = metricsStore.put(data)
+	require.NoError(t, err)
+	logsStore, _, err := openPersistentStore(persistentQueueSettings(filepath.Join(dir, "logs")).Persistent)
+	require.NoError(t, err)
+	data, err = testdata.GenerateLogDataOneLog().ToOtlpProtoBytes()
+	require.NoError(t, err)
+	_, err = logsStore.put(data)
+	require.NoError(t, err)
+
+	for _, exp := range []component.Exporter{me, le} {
+		require.NoError(t, exp.Start(context.Background(), componenttest.NewNopHost()))
+		testutil.WaitFor(t, func() bool {
+			mu.Lock()
+			defer mu.Unlock()
+			return len(received) > 0
+		}, "send the persisted request")
+		require.NoError(t, exp.Shutdown(context.Background()))
+		mu.Lock()
+		received = nil
+		mu.Unlock()
+	}
+	assert.Empty(t, persistedFiles(t, filepath.Join(dir, "metrics")))
+	assert.Empty(t, persistedFiles(t, filepath.Join(dir, "logs")))
+}
+
+func TestPersistentQueue_DropNotQueued(t *testing.T) {
+	dir := newTempDir(t)
+	rCfg := DefaultRetrySettings()
+	rCfg.InitialInterval = 10 * time.Millisecond
+	rCfg.MaxElapsedTime = 0
+	te, err := NewTraceExporter(defaultExporterCfg, zap.NewNop(), func(context.Context, pdata.Traces) (int, error) {
+		return 0, errors.New("backend unavailable")
+	}, WithQueue(persistentQueueSettings(dir)), WithRetry(rCfg))
+	require.NoError(t, err)
+	require.NoError(t, te.Start(context.Background(), componenttest.NewNopHost()))
+	require.NoError(t, te.ConsumeTraces(context.Background(), testdata.GenerateTraceDataOneSpan()))
+	require.NoError(t, te.ConsumeTraces(context.Background(), testdata.GenerateTraceDataTwoSpansSameResource()))
+	require.NoError(t, te.Shutdown(context.Background()))
+	require.Len(t, persistedFiles(t, dir), 2)
+
+	// The queues of the priority group only hold one request, the other one is dropped.
+	qCfg := persistentQueueSettings(dir)
+	qCfg.QueueSize = 1
+	qCfg.DropPolicy = DropPolicyBySignalPriority
+	var pushed int32
+	te, err = NewTraceExporter(defaultExporterCfg, zap.NewNop(), func(context.Context, pdata.Traces) (int, error) {
+		atomic.AddInt32(&pushed, 1)
+		return 0, nil
+	}, WithQueue(qCfg), WithRetry(rCfg))
+	require.NoError(t, err)
+	require.NoError(t, te.Start(context.Background(), componenttest.NewNopHost()))
+	testutil.WaitFor(t, func() bool {
+		return len(persistedFiles(t, dir)) == 0
+	}, "send or drop the persisted requests")
+	assert.NoError(t, te.Shutdown(context.Background()))
+	assert.EqualValues(t, 1, atomic.LoadInt32(&pushed))
+}
+
+func TestPersistentQueue_DropUndecodable(t *testing.T) {
+	dir := newTempDir(t)
+	store, _, err := openPersistentStore(&PersistentQueueSettings{Directory: dir})
+	require.NoError(t, err)
+	_, err = store.put([]byte{0xff, 0xff})
+	require.NoError(t, err)
+
+	var pushed int32
+	te, err := NewTraceExporter(defaultExporterCfg, zap.NewNop(), func(context.Context, pdata.Traces) (int, error) {
+		atomic.AddInt32(&pushed, 1)
+		return 0, nil
+	}, WithQueue(persistentQueueSettings(dir)))
+	require.NoError(t, err)
+	require.NoError(t, te.Start(context.Background(), componenttest.NewNopHost()))
+	assert.NoError(t, te.Shutdown(context.Background()))
+	assert.Empty(t, persistedFiles(t, dir))
+	assert.EqualValues(t, 0, atomic.LoadInt32(&pushed))
+}
+
+func TestPersistentQueue_Full(t *testing.T) {
+	dir := newTempDir(t)
+	te, err := NewTraceExporter(defaultExporterCfg, zap.NewNop(), func(context.Context, pdata.Traces) (int, error) {
+		return 0, nil
+	}, WithQueue(persistentQueueSettings(dir)))
+	require.NoError(t, err)
+	require.NoError(t, te.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() {
+		assert.NoError(t, te.Shutdown(context.Background()))
+	}()
+
+	te.(*traceExporter).qrSender.store.maxSize = 1
+	err = te.ConsumeTraces(context.Background(), testdata.GenerateTraceDataOneSpan())
+	assert.True(t, errors.Is(err, errPersistentQueueFull))
+	assert.True(t, consumererror.IsBackpressure(err))
+	assert.Empty(t, persistedFiles(t, dir))
+}
+
+func TestPersistentQueue_InvalidSettings(t *testing.T) {
+	te, err := NewTraceExporter(defaultExporterCfg, zap.NewNop(), func(context.Context, pdata.Traces) (int, error) {
+		return 0, nil
+	}, WithQueue(persistentQueueSettings("")))
+	require.NoError(t, err)
+	assert.EqualError(t, te.Start(context.Background(), componenttest.NewNopHost()),
+		"failed to open the persistent sending_queue: sending_queue.persistent.directory must be set")
+
+	// Requests of the base exporter alone cannot be persisted.
+	be := newBaseExporter(defaultExporterCfg, zap.NewNop(), WithQueue(persistentQueueSettings(newTempDir(t))))
+	assert.Error(t, be.Start(context.Background(), componenttest.NewNopHost()))
+}
+
+type fakeStorage struct {
+	component.Extension
+	dir string
+}
+
+func (s *fakeStorage) GetClient(_ context.Context, kind component.Kind, name string) (storage.Client, error) {
+	return storage.NewFileClient(filepath.Join(s.dir, storage.EscapeName(name)))
+}
+
+type storageHost struct {
+	component.Host
+	extensions map[configmodels.NamedEntity]component.Extension
+}
+
+func (h *storageHost) GetExtensions() map[configmodels.NamedEntity]component.Extension {
+	return h.extensions
+}
+
+func TestPersistentQueue_Storage(t *testing.T) {
+	dir := newTempDir(t)
+	host := &storageHost{
+		Host: componenttest.NewNopHost(),
+		extensions: map[configmodels.NamedEntity]component.Extension{
+			&configmodels.ExtensionSettings{TypeVal: "file_storage", NameVal: "file_storage"}: &fakeStorage{dir: dir},
+		},
+	}
+	qCfg := DefaultQueueSettings()
+	qCfg.NumConsumers = 1
+	qCfg.Persistent = &PersistentQueueSettings{Storage: "file_storage"}
+
+	// The requests persisted in the storage before the exporter is started are sent when it starts.
+	client, err := storage.NewFileClient(filepath.Join(dir, storage.EscapeName(defaultExporterCfg.Name())))
+	require.NoError(t, err)
+	store, _, err := newPersistentStore(client, 0)
+	require.NoError(t, err)
+	data, err := testdata.GenerateTraceDataOneSpan().ToOtlpProtoBytes()
+	require.NoError(t, err)
+	_, err = store.put(data)
+	require.NoError(t, err)
+	require.NoError(t, client.Set(context.Background(), "other", []byte("other")))
+
+	var received int32
+	te, err := NewTraceExporter(defaultExporterCfg, zap.NewNop(), func(context.Context, pdata.Traces) (int, error) {
+		atomic.AddInt32(&received, 1)
+		return 0, nil
+	}, WithQueue(qCfg))
+	require.NoError(t, err)
+	require.NoError(t, te.Start(context.Background(), host))
+	require.NoError(t, te.ConsumeTraces(context.Background(), testdata.GenerateTraceDataOneSpan()))
+	testutil.WaitFor(t, func() bool {
+		return atomic.LoadInt32(&received) == 2
+	}, "send the requests")
+	require.NoError(t, te.Shutdown(context.Background()))
+
+	testutil.WaitFor(t, func() bool {
+		keys, err := client.Keys(context.Background())
+		require.NoError(t, err)
+		return len(keys) == 1
+	}, "remove the sent requests")
+	keys, err := client.Keys(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"other"}, keys)
+
+	qCfg.Persistent.Directory = dir
+	te, err = NewTraceExporter(defaultExporterCfg, zap.NewNop(), func(context.Context, pdata.Traces) (int, error) {
+		return 0, nil
+	}, WithQueue(qCfg))
+	require.NoError(t, err)
+	assert.Equal(t, errPersistentDirectoryStorage, te.Start(context.Background(), host))
+
+	qCfg.Persistent = &PersistentQueueSettings{Storage: "missing"}
+	te, err = NewTraceExporter(defaultExporterCfg, zap.NewNop(), func(context.Context, pdata.Traces) (int, error) {
+		return 0, nil
+	}, WithQueue(qCfg))
+	require.NoError(t, err)
+	assert.Error(t, te.Start(context.Background(), host))
+}
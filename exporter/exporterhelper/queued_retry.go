@@ -18,15 +18,21 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
-	"github.com/jaegertracing/jaeger/pkg/queue"
+	"go.opencensus.io/stats"
 	"go.opencensus.io/trace"
+	"go.uber.org/atomic"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 
+	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/exporter/exporterhelper/exporterstate"
+	"go.opentelemetry.io/collector/extension/storage"
+	"go.opentelemetry.io/collector/internal/recenterrors"
 	"go.opentelemetry.io/collector/obsreport"
 )
 
@@ -38,6 +44,10 @@ type QueueSettings struct {
 	NumConsumers int `mapstructure:"num_consumers"`
 	// QueueSize is the maximum number of batches allowed in queue at a given time.
 	QueueSize int `mapstructure:"queue_size"`
+	// Persistent persists the queued batches on disk when set, so that they survive restarts.
+	Persistent *PersistentQueueSettings `mapstructure:"persistent"`
+	// DropPolicy decides which batches are dropped when the queue is full, DropPolicyNewest when empty.
+	DropPolicy DropPolicy `mapstructure:"drop_policy"`
 }
 
 // DefaultQueueSettings returns the default settings for QueueSettings.
@@ -79,12 +89,38 @@ func DefaultRetrySettings() RetrySettings {
 }
 
 type queuedRetrySender struct {
+	fullName        string
 	cfg             QueueSettings
 	consumerSender  requestSender
-	queue           *queue.BoundedQueue
+	queue           *boundedQueue
 	retryStopCh     chan struct{}
 	traceAttributes []trace.Attribute
 	logger          *zap.Logger
+	metrics         *queueMetrics
+	metricsInterval time.Duration
+
+	// The queues of the signals of the exporter share their capacity with DropPolicyBySignalPriority.
+	priority signalPriority
+	group    *priorityGroup
+
+	// unregisterQueue removes the queue from the queues reported by the exporterstate package.
+	unregisterQueue func()
+
+	// The requests are converted to bytes and back to be persisted, when the queue is persistent.
+	marshal   func(request) ([]byte, error)
+	unmarshal func([]byte) (request, error)
+	store     *persistentStore
+
+	// queuedItems is the number of items in the queue.
+	queuedItems atomic.Int64
+	// drainFlushed and drainDropped count the items sent and dropped by the queue consumers
+	// during the shutdown.
+	drainFlushed atomic.Int64
+	drainDropped atomic.Int64
+	// drainStatsMu protects abandonedStats, the stats frozen once the queue is stopped or the
+	// context of the shutdown is done before.
+	drainStatsMu   sync.Mutex
+	abandonedStats *component.DrainStats
 }
 
 func createSampledLogger(logger *zap.Logger) *zap.Logger {
@@ -106,34 +142,239 @@ func createSampledLogger(logger *zap.Logger) *zap.Logger {
 	return logger.WithOptions(opts)
 }
 
-func newQueuedRetrySender(fullName string, qCfg QueueSettings, rCfg RetrySettings, nextSender requestSender, logger *zap.Logger) *queuedRetrySender {
+func newQueuedRetrySender(fullName string, qCfg QueueSettings, rCfg RetrySettings, classifier ErrorClassifier, nextSender requestSender, logger *zap.Logger) *queuedRetrySender {
 	retryStopCh := make(chan struct{})
 	sampledLogger := createSampledLogger(logger)
 	traceAttr := trace.StringAttribute(obsreport.ExporterKey, fullName)
 	return &queuedRetrySender{
-		cfg: qCfg,
+		fullName: fullName,
+		cfg:      qCfg,
 		consumerSender: &retrySender{
+			fullName:       fullName,
 			traceAttribute: traceAttr,
 			cfg:            rCfg,
+			classifier:     classifier,
 			nextSender:     nextSender,
 			stopCh:         retryStopCh,
 			logger:         sampledLogger,
 		},
-		queue:           queue.NewBoundedQueue(qCfg.QueueSize, func(item interface{}) {}),
+		queue:           newBoundedQueue(qCfg.QueueSize),
 		retryStopCh:     retryStopCh,
 		traceAttributes: []trace.Attribute{traceAttr},
 		logger:          sampledLogger,
+		metrics:         newQueueMetrics(fullName),
+		metricsInterval: defaultQueueMetricsInterval,
 	}
 }
 
 // start is invoked during service startup.
-func (qrs *queuedRetrySender) start() {
+func (qrs *queuedRetrySender) start(ctx context.Context, host component.Host) error {
+	switch qrs.cfg.DropPolicy {
+	case "", DropPolicyNewest, DropPolicyOldest:
+	case DropPolicyBySignalPriority:
+		if qrs.cfg.Enabled {
+			qrs.group = joinPriorityGroup(qrs)
+		}
+	default:
+		return fmt.Errorf("unknown sending_queue.drop_policy %q", qrs.cfg.DropPolicy)
+	}
+	if qrs.cfg.Enabled && qrs.cfg.Persistent != nil {
+		if err := qrs.openStore(ctx, host); err != nil {
+			return err
+		}
+	}
 	qrs.queue.StartConsumers(qrs.cfg.NumConsumers, func(item interface{}) {
-		req := item.(request)
-		_, _ = qrs.consumerSender.send(req)
+		qrs.metrics.consumed()
+		count := queuedRequest(item).count()
+		qrs.queuedItems.Sub(int64(count))
+		pr, ok := item.(*persistedRequest)
+		if !ok {
+			dropped, _ := qrs.consumerSender.send(item.(request))
+			qrs.recordDrain(count, dropped)
+			return
+		}
+		dropped, err := qrs.consumerSender.send(pr.request)
+		if err != nil && qrs.stopping() {
+			// The retries were interrupted by the shutdown, the request is sent after the restart.
+			return
+		}
+		qrs.recordDrain(count, dropped)
+		qrs.removePersisted(pr.path)
+	})
+	if qrs.cfg.Enabled {
+		qrs.unregisterQueue = exporterstate.Register(qrs.fullName, qrs.queue)
+		go qrs.reportMetrics()
+	}
+	return nil
+}
+
+// reportMetrics reports the metrics of the queue periodically until the shutdown.
+func (qrs *queuedRetrySender) reportMetrics() {
+	ticker := time.NewTicker(qrs.metricsInterval)
+	defer ticker.Stop()
+	for {
+		qrs.metrics.report(qrs.queue.Size(), qrs.queue.Capacity())
+		select {
+		case <-ticker.C:
+		case <-qrs.retryStopCh:
+			return
+		}
+	}
+}
+
+// queuedRequest returns the request of an item of the queue.
+func queuedRequest(item interface{}) request {
+	if pr, ok := item.(*persistedRequest); ok {
+		return pr.request
+	}
+	return item.(request)
+}
+
+// produce adds the item to the queue according to the drop_policy, it returns false when the
+// item was dropped.
+func (qrs *queuedRetrySender) produce(item interface{}) bool {
+	if !qrs.produceItem(item) {
+		return false
+	}
+	qrs.queuedItems.Add(int64(queuedRequest(item).count()))
+	return true
+}
+
+func (qrs *queuedRetrySender) produceItem(item interface{}) bool {
+	switch {
+	case qrs.group != nil:
+		return qrs.group.produce(qrs, item)
+	case qrs.cfg.DropPolicy == DropPolicyOldest:
+		return qrs.metrics.produce(func() (bool, int) {
+			dropped, added := qrs.queue.ProduceDroppingOldest(item)
+			for _, d := range dropped {
+				qrs.dropQueued(d)
+			}
+			return added, len(dropped)
+		}, qrs.queue.Size)
+	}
+	return qrs.metrics.produce(func() (bool, int) { return qrs.queue.Produce(item), 0 }, qrs.queue.Size)
+}
+
+// dropOldest drops the oldest item of the queue to make room for another one, it returns false
+// when the queue is empty.
+func (qrs *queuedRetrySender) dropOldest() bool {
+	return qrs.metrics.dropOldest(func() bool {
+		item, ok := qrs.queue.DropOldest()
+		if ok {
+			qrs.dropQueued(item)
+		}
+		return ok
 	})
 }
 
+// dropQueued drops an item removed from the queue by the drop_policy.
+func (qrs *queuedRetrySender) dropQueued(item interface{}) {
+	req, ok := item.(request)
+	if pr, isPersisted := item.(*persistedRequest); isPersisted {
+		req, ok = pr.request, true
+		qrs.removePersisted(pr.path)
+	}
+	if !ok {
+		return
+	}
+	qrs.queuedItems.Sub(int64(req.count()))
+	qrs.logger.Error(
+		"Dropping queued data to make room for newer data. Try increasing queue_size.",
+		zap.String("drop_policy", string(qrs.cfg.DropPolicy)),
+		zap.Int("dropped_items", req.count()),
+	)
+	trace.FromContext(req.context()).Annotate(qrs.traceAttributes, "Dropped item, sending_queue is full.")
+	qrs.metrics.queuedDropped()
+	recenterrors.Record(obsreport.ExporterKey, qrs.fullName, recenterrors.TypeDropped, req.count(), errors.New("sending_queue is full"))
+}
+
+// openStore opens the persistent store and queues the requests persisted before the restart,
+// the queue being enlarged if they do not fit in it.
+func (qrs *queuedRetrySender) openStore(ctx context.Context, host component.Host) error {
+	if qrs.marshal == nil || qrs.unmarshal == nil {
+		return errors.New("the requests of this exporter cannot be persisted")
+	}
+	var store *persistentStore
+	var paths []string
+	var err error
+	if qrs.cfg.Persistent.Storage != "" {
+		if qrs.cfg.Persistent.Directory != "" {
+			return errPersistentDirectoryStorage
+		}
+		var client storage.Client
+		client, err = storage.GetClient(ctx, host, qrs.cfg.Persistent.Storage, component.KindExporter, qrs.fullName)
+		if err != nil {
+			return fmt.Errorf("failed to open the persistent sending_queue: %w", err)
+		}
+		store, paths, err = newPersistentStore(client, qrs.cfg.Persistent.MaxSizeMiB)
+	} else {
+		store, paths, err = openPersistentStore(qrs.cfg.Persistent)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open the persistent sending_queue: %w", err)
+	}
+	qrs.store = store
+	if len(paths) > qrs.queue.Capacity() {
+		qrs.queue.Resize(len(paths))
+	}
+	for _, path := range paths {
+		data, err := store.get(path)
+		if err != nil {
+			return fmt.Errorf("failed to read the persistent sending_queue: %w", err)
+		}
+		req, err := qrs.unmarshal(data)
+		if err != nil {
+			qrs.logger.Error("Dropping persisted data that cannot be decoded.", zap.String("path", path), zap.Error(err))
+			qrs.removePersisted(path)
+			continue
+		}
+		if !qrs.produce(&persistedRequest{request: req, path: path}) {
+			qrs.removePersisted(path)
+			qrs.logger.Error(
+				"Dropping persisted data because sending_queue is full. Try increasing queue_size.",
+				zap.String("path", path),
+				zap.Int("dropped_items", req.count()),
+			)
+			qrs.metrics.enqueueFailed()
+			recenterrors.Record(obsreport.ExporterKey, qrs.fullName, recenterrors.TypeDropped, req.count(), errors.New("sending_queue is full"))
+		}
+	}
+	if len(paths) > 0 {
+		qrs.logger.Info("Sending the data persisted in the sending_queue.", zap.Int("requests", qrs.queue.Size()))
+	}
+	return nil
+}
+
+func (qrs *queuedRetrySender) removePersisted(path string) {
+	if err := qrs.store.remove(path); err != nil {
+		qrs.logger.Error("Failed to remove persisted data from the sending_queue.", zap.String("path", path), zap.Error(err))
+	}
+}
+
+// stopping returns whether the sender is shutting down.
+func (qrs *queuedRetrySender) stopping() bool {
+	select {
+	case <-qrs.retryStopCh:
+		return true
+	default:
+		return false
+	}
+}
+
+// persist persists the request in the persistent store.
+func (qrs *queuedRetrySender) persist(req request) (*persistedRequest, error) {
+	data, err := qrs.marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	path, err := qrs.store.put(data)
+	if err != nil {
+		return nil, err
+	}
+	return &persistedRequest{request: req, path: path}, nil
+}
+
 // send implements the requestSender interface
 func (qrs *queuedRetrySender) send(req request) (int, error) {
 	if !qrs.cfg.Enabled {
@@ -152,27 +393,122 @@ func (qrs *queuedRetrySender) send(req request) (int, error) {
 	req.setContext(noCancellationContext{Context: req.context()})
 
 	span := trace.FromContext(req.context())
-	if !qrs.queue.Produce(req) {
+	var item interface{} = req
+	if qrs.store != nil {
+		pr, err := qrs.persist(req)
+		if err != nil {
+			qrs.logger.Error(
+				"Dropping data because it cannot be persisted in the sending_queue. Try increasing max_size_mib.",
+				zap.Error(err),
+				zap.Int("dropped_items", req.count()),
+			)
+			span.Annotate(qrs.traceAttributes, "Dropped item, it cannot be persisted.")
+			qrs.metrics.enqueueFailed()
+			recenterrors.Record(obsreport.ExporterKey, qrs.fullName, recenterrors.TypeDropped, req.count(), err)
+			if errors.Is(err, errPersistentQueueFull) {
+				err = consumererror.Backpressure(err)
+			}
+			return req.count(), err
+		}
+		item = pr
+	}
+	if !qrs.produce(item) {
+		if pr, ok := item.(*persistedRequest); ok {
+			qrs.removePersisted(pr.path)
+		}
 		qrs.logger.Error(
 			"Dropping data because sending_queue is full. Try increasing queue_size.",
 			zap.Int("dropped_items", req.count()),
 		)
 		span.Annotate(qrs.traceAttributes, "Dropped item, sending_queue is full.")
-		return req.count(), errors.New("sending_queue is full")
+		qrs.metrics.enqueueFailed()
+		err := errors.New("sending_queue is full")
+		recenterrors.Record(obsreport.ExporterKey, qrs.fullName, recenterrors.TypeDropped, req.count(), err)
+		return req.count(), consumererror.Backpressure(err)
 	}
 
 	span.Annotate(qrs.traceAttributes, "Enqueued item.")
 	return 0, nil
 }
 
-// shutdown is invoked during service shutdown.
-func (qrs *queuedRetrySender) shutdown() {
+// shutdown is invoked during service shutdown, the queue is drained until ctx is done.
+func (qrs *queuedRetrySender) shutdown(ctx context.Context) error {
 	// First stop the retry goroutines, so that unblocks the queue workers.
 	close(qrs.retryStopCh)
 
-	// Stop the queued sender, this will drain the queue and will call the retry (which is stopped) that will only
-	// try once every request.
-	qrs.queue.Stop()
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		// Stop the queued sender, its consumers drain the queue calling the retry (which is stopped) that only
+		// tries once every request.
+		qrs.queue.Stop()
+
+		if qrs.group != nil {
+			qrs.group.leave(qrs)
+		}
+		if qrs.unregisterQueue != nil {
+			qrs.unregisterQueue()
+		}
+		if qrs.store != nil {
+			if err := qrs.store.close(); err != nil {
+				qrs.logger.Error("Failed to close the persistent sending_queue.", zap.Error(err))
+			}
+		}
+	}()
+
+	select {
+	case <-stopped:
+		// The items enqueued while the consumers were draining the queue are never sent.
+		qrs.abandonItems()
+		return nil
+	case <-ctx.Done():
+	}
+
+	abandoned := qrs.abandonItems()
+	qrs.logger.Warn("The sending_queue was not drained before the shutdown deadline.",
+		zap.Int64("dropped_items", abandoned))
+	return ctx.Err()
+}
+
+// recordDrain records the items sent by a queue consumer during the shutdown.
+func (qrs *queuedRetrySender) recordDrain(count int, dropped int) {
+	if !qrs.stopping() {
+		return
+	}
+	qrs.drainFlushed.Add(int64(count - dropped))
+	qrs.drainDropped.Add(int64(dropped))
+}
+
+// abandonItems freezes the drain stats, counting the items left in the queue as dropped unless
+// they are persisted to be sent after the restart, and returns the dropped items.
+func (qrs *queuedRetrySender) abandonItems() int64 {
+	stats := qrs.drainStats()
+	abandoned := qrs.queuedItems.Load()
+	if qrs.store != nil {
+		// The persisted items are sent after the restart.
+		abandoned = 0
+	}
+	if abandoned > 0 {
+		stats.Dropped += abandoned
+	}
+	qrs.drainStatsMu.Lock()
+	qrs.abandonedStats = &stats
+	qrs.drainStatsMu.Unlock()
+	return abandoned
+}
+
+// drainStats returns the items drained by the shutdown, as they were at the shutdown deadline
+// if the queue could not be drained before it.
+func (qrs *queuedRetrySender) drainStats() component.DrainStats {
+	qrs.drainStatsMu.Lock()
+	defer qrs.drainStatsMu.Unlock()
+	if qrs.abandonedStats != nil {
+		return *qrs.abandonedStats
+	}
+	return component.DrainStats{
+		Flushed: qrs.drainFlushed.Load(),
+		Dropped: qrs.drainDropped.Load(),
+	}
 }
 
 // TODO: Clean this by forcing all exporters to return an internal error type that always include the information about retries.
@@ -188,18 +524,77 @@ func NewThrottleRetry(err error, delay time.Duration) error {
 	}
 }
 
+type requestTooLarge struct {
+	error
+}
+
+// NewRequestTooLargeError returns an error telling that the request was refused because it is too
+// large, the request is split in two and the parts are retried when retry_on_failure is enabled.
+func NewRequestTooLargeError(err error) error {
+	return &requestTooLarge{error: err}
+}
+
+// ErrorClass is the class of an export error, deciding whether and when the request is retried.
+type ErrorClass int
+
+const (
+	// ErrorClassDefault classifies the error with the default rules: the errors wrapped with
+	// consumererror.Permanent are dropped, the errors returned by NewThrottleRetry are retried
+	// after their delay, the requests failing with the errors returned by
+	// NewRequestTooLargeError are split and the other errors are retried with the backoff.
+	ErrorClassDefault ErrorClass = iota
+	// ErrorClassTransient retries the request with the backoff.
+	ErrorClassTransient
+	// ErrorClassPermanent drops the request without retrying it.
+	ErrorClassPermanent
+	// ErrorClassThrottled retries the request after the delay requested by the server, or after
+	// the backoff when it is longer.
+	ErrorClassThrottled
+	// ErrorClassTooLarge splits the request in two and sends the parts, the request is dropped
+	// when it cannot be split.
+	ErrorClassTooLarge
+)
+
+// ErrorClassifier returns the class of an error returned by the exporter, and the delay
+// requested by the server before retrying for ErrorClassThrottled. It is called for every
+// failed attempt when retry_on_failure is enabled.
+type ErrorClassifier func(err error) (class ErrorClass, delay time.Duration)
+
 type retrySender struct {
+	fullName       string
 	traceAttribute trace.Attribute
 	cfg            RetrySettings
+	classifier     ErrorClassifier
 	nextSender     requestSender
 	stopCh         chan struct{}
 	logger         *zap.Logger
 }
 
+// classify returns the class of the error and the delay before retrying, from the classifier of
+// the exporter when set, and with the default rules otherwise.
+func (rs *retrySender) classify(err error) (ErrorClass, time.Duration) {
+	if rs.classifier != nil {
+		if class, delay := rs.classifier(err); class != ErrorClassDefault {
+			return class, delay
+		}
+	}
+	if consumererror.IsPermanent(err) {
+		return ErrorClassPermanent, 0
+	}
+	if throttleErr, isThrottle := err.(*throttleRetry); isThrottle {
+		return ErrorClassThrottled, throttleErr.delay
+	}
+	if _, isTooLarge := err.(*requestTooLarge); isTooLarge {
+		return ErrorClassTooLarge, 0
+	}
+	return ErrorClassTransient, 0
+}
+
 // send implements the requestSender interface
 func (rs *retrySender) send(req request) (int, error) {
 	if !rs.cfg.Enabled {
 		n, err := rs.nextSender.send(req)
+		exporterstate.RecordExport(rs.fullName, err)
 		if err != nil {
 			rs.logger.Error(
 				"Exporting failed. Try enabling retry_on_failure config option.",
@@ -230,18 +625,30 @@ func (rs *retrySender) send(req request) (int, error) {
 				trace.Int64Attribute("retry_num", retryNum)},
 			"Sending request.")
 		droppedItems, err := rs.nextSender.send(req)
+		exporterstate.RecordExport(rs.fullName, err)
 
 		if err == nil {
 			return droppedItems, nil
 		}
 
+		class, throttleDelay := rs.classify(err)
+		if class == ErrorClassTooLarge {
+			if first, second, ok := splitRequest(req); ok {
+				return rs.sendSplit(req, first, second, err)
+			}
+			class = ErrorClassPermanent
+		}
+
 		// Immediately drop data on permanent errors.
-		if consumererror.IsPermanent(err) {
+		if class == ErrorClassPermanent {
 			rs.logger.Error(
 				"Exporting failed. The error is not retryable. Dropping data.",
 				zap.Error(err),
 				zap.Int("dropped_items", droppedItems),
 			)
+			if !consumererror.IsPermanent(err) {
+				err = consumererror.Permanent(err)
+			}
 			return droppedItems, err
 		}
 
@@ -262,8 +669,8 @@ func (rs *retrySender) send(req request) (int, error) {
 			return req.count(), err
 		}
 
-		if throttleErr, isThrottle := err.(*throttleRetry); isThrottle {
-			backoffDelay = max(backoffDelay, throttleErr.delay)
+		if class == ErrorClassThrottled {
+			backoffDelay = max(backoffDelay, throttleDelay)
 		}
 
 		backoffDelayStr := backoffDelay.String()
@@ -279,6 +686,7 @@ func (rs *retrySender) send(req request) (int, error) {
 			zap.String("interval", backoffDelayStr),
 		)
 		retryNum++
+		stats.Record(req.context(), mRetries.M(1))
 
 		// back-off, but get interrupted when shutting down or request is cancelled or timed out.
 		select {
@@ -291,6 +699,36 @@ func (rs *retrySender) send(req request) (int, error) {
 	}
 }
 
+// splitRequest splits the request in two, ok is false when the request cannot be split.
+func splitRequest(req request) (first request, second request, ok bool) {
+	sr, isSplittable := req.(splittableRequest)
+	if !isSplittable {
+		return nil, nil, false
+	}
+	return sr.split()
+}
+
+// sendSplit sends the two parts of a request that was too large, each part is split again when
+// it is still too large.
+func (rs *retrySender) sendSplit(req request, first request, second request, err error) (int, error) {
+	stats.Record(req.context(), mSplitRequests.M(1))
+	rs.logger.Warn(
+		"Exporting failed. The request is too large, splitting it.",
+		zap.Error(err),
+		zap.Int("items", req.count()),
+	)
+	var errs []error
+	droppedItems := 0
+	for _, part := range []request{first, second} {
+		dropped, partErr := rs.send(part)
+		if partErr != nil {
+			errs = append(errs, partErr)
+		}
+		droppedItems += dropped
+	}
+	return droppedItems, consumererror.CombineErrors(errs)
+}
+
 // max returns the larger of x or y.
 func max(x, y time.Duration) time.Duration {
 	if x < y {
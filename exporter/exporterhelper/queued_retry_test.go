@@ -17,6 +17,8 @@ package exporterhelper
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net/http"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -24,10 +26,16 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
 	"go.uber.org/zap"
 
+	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/exporter/exporterhelper/exporterstate"
+	"go.opentelemetry.io/collector/internal/recenterrors"
 	"go.opentelemetry.io/collector/internal/testdata"
 	"go.opentelemetry.io/collector/obsreport/obsreporttest"
 )
@@ -149,6 +157,72 @@ func TestQueuedRetry_StopWhileWaiting(t *testing.T) {
 	// require.Zero(t, be.qrSender.queue.Size())
 }
 
+// blockingSender sends the requests once released, the mockRequest with a consumeError failing.
+type blockingSender struct {
+	release chan struct{}
+}
+
+func (bs *blockingSender) send(req request) (int, error) {
+	<-bs.release
+	if mr, ok := req.(*mockRequest); ok && mr.consumeError != nil {
+		return mr.cnt, mr.consumeError
+	}
+	return 0, nil
+}
+
+func TestQueuedRetry_DrainStats(t *testing.T) {
+	qCfg := DefaultQueueSettings()
+	qCfg.NumConsumers = 1
+	be := newBaseExporter(defaultExporterCfg, zap.NewNop(), WithRetry(DefaultRetrySettings()), WithQueue(qCfg))
+	bs := &blockingSender{release: make(chan struct{})}
+	be.qrSender.consumerSender = bs
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+
+	for _, req := range []*mockRequest{
+		newMockRequest(context.Background(), 2, nil),
+		newMockRequest(context.Background(), 3, consumererror.Permanent(errors.New("permanent error"))),
+		newMockRequest(context.Background(), 4, nil),
+	} {
+		_, err := be.sender.send(req)
+		require.NoError(t, err)
+	}
+	// The first request is being sent by the consumer, the others are drained by the shutdown.
+	assert.Eventually(t, func() bool { return be.qrSender.queue.Size() == 2 }, time.Second, time.Millisecond)
+
+	shutdownErr := make(chan error, 1)
+	go func() {
+		shutdownErr <- be.Shutdown(context.Background())
+	}()
+	assert.Eventually(t, be.qrSender.stopping, time.Second, time.Millisecond)
+	close(bs.release)
+
+	assert.NoError(t, <-shutdownErr)
+	assert.Equal(t, component.DrainStats{Flushed: 6, Dropped: 3}, be.DrainStats())
+}
+
+func TestQueuedRetry_ShutdownDeadline(t *testing.T) {
+	qCfg := DefaultQueueSettings()
+	qCfg.NumConsumers = 1
+	be := newBaseExporter(defaultExporterCfg, zap.NewNop(), WithRetry(DefaultRetrySettings()), WithQueue(qCfg))
+	bs := &blockingSender{release: make(chan struct{})}
+	be.qrSender.consumerSender = bs
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+	defer close(bs.release)
+
+	for i := 0; i < 3; i++ {
+		_, err := be.sender.send(newMockRequest(context.Background(), 2, nil))
+		require.NoError(t, err)
+	}
+	// The first request is being sent by the consumer.
+	assert.Eventually(t, func() bool { return be.qrSender.queue.Size() == 2 }, time.Second, time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, be.Shutdown(ctx))
+	// The items left in the queue are dropped, the ones being sent are not counted.
+	assert.Equal(t, component.DrainStats{Dropped: 4}, be.DrainStats())
+}
+
 func TestQueuedRetry_DoNotPreserveCancellation(t *testing.T) {
 	qCfg := DefaultQueueSettings()
 	qCfg.NumConsumers = 1
@@ -283,7 +357,129 @@ func TestQueuedRetry_RetryOnError(t *testing.T) {
 	require.Zero(t, be.qrSender.queue.Size())
 }
 
+// statusError is an error of a backend returning HTTP status codes.
+type statusError struct {
+	code       int
+	retryAfter time.Duration
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("status code %d", e.code)
+}
+
+func TestQueuedRetry_ErrorClassifier(t *testing.T) {
+	classifier := func(err error) (ErrorClass, time.Duration) {
+		var se *statusError
+		if !errors.As(err, &se) {
+			return ErrorClassDefault, 0
+		}
+		switch se.code {
+		case http.StatusRequestEntityTooLarge:
+			return ErrorClassPermanent, 0
+		case http.StatusTooManyRequests:
+			return ErrorClassThrottled, se.retryAfter
+		case http.StatusInternalServerError:
+			return ErrorClassTransient, 0
+		}
+		return ErrorClassDefault, 0
+	}
+	tests := []struct {
+		name         string
+		err          error
+		wantRequests int
+		wantDropped  int
+		wantDelay    time.Duration
+	}{
+		{name: "permanent", err: &statusError{code: http.StatusRequestEntityTooLarge}, wantRequests: 1, wantDropped: 2},
+		{name: "throttled", err: &statusError{code: http.StatusTooManyRequests, retryAfter: 100 * time.Millisecond}, wantRequests: 2, wantDelay: 100 * time.Millisecond},
+		{name: "transient_overrides_permanent", err: consumererror.Permanent(&statusError{code: http.StatusInternalServerError}), wantRequests: 2},
+		{name: "default_permanent", err: consumererror.Permanent(errors.New("bad data")), wantRequests: 1, wantDropped: 2},
+		{name: "default_transient", err: &statusError{code: http.StatusServiceUnavailable}, wantRequests: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			qCfg := DefaultQueueSettings()
+			qCfg.Enabled = false
+			rCfg := DefaultRetrySettings()
+			rCfg.InitialInterval = time.Millisecond
+			be := newBaseExporter(defaultExporterCfg, zap.NewNop(), WithRetry(rCfg), WithQueue(qCfg), WithErrorClassifier(classifier))
+			require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+			t.Cleanup(func() {
+				assert.NoError(t, be.Shutdown(context.Background()))
+			})
+
+			mockR := newMockRequest(context.Background(), 2, tt.err)
+			start := time.Now()
+			droppedItems, err := be.sender.send(mockR)
+			assert.Equal(t, tt.wantDropped, droppedItems)
+			if tt.wantDropped > 0 {
+				assert.True(t, consumererror.IsPermanent(err))
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.True(t, tt.wantDelay <= time.Since(start))
+			mockR.checkNumRequests(t, tt.wantRequests)
+		})
+	}
+}
+
+func TestQueuedRetry_SplitTooLarge(t *testing.T) {
+	require.NoError(t, view.Register(MetricViews()...))
+	defer view.Unregister(MetricViews()...)
+
+	var mu sync.Mutex
+	var sent []int
+	pusher := func(_ context.Context, td pdata.Traces) (int, error) {
+		if td.SpanCount() > 2 {
+			return td.SpanCount(), NewRequestTooLargeError(errors.New("too large"))
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		sent = append(sent, td.SpanCount())
+		return 0, nil
+	}
+	rCfg := DefaultRetrySettings()
+	rCfg.InitialInterval = time.Millisecond
+	be := newBaseExporter(defaultExporterCfg, zap.NewNop(), WithRetry(rCfg))
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, be.Shutdown(context.Background()))
+	})
+
+	droppedItems, err := be.qrSender.consumerSender.send(newTracesRequest(context.Background(), testdata.GenerateTraceDataManySpansSameResource(5), pusher))
+	require.NoError(t, err)
+	assert.Equal(t, 0, droppedItems)
+	assert.Equal(t, []int{2, 1, 2}, sent)
+
+	rows, err := view.RetrieveData(vSplitRequests.Name)
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, float64(2), rows[0].Data.(*view.SumData).Value)
+}
+
+func TestQueuedRetry_SplitTooLargeUnsplittable(t *testing.T) {
+	pusher := func(_ context.Context, td pdata.Traces) (int, error) {
+		return td.SpanCount(), NewRequestTooLargeError(errors.New("too large"))
+	}
+	rCfg := DefaultRetrySettings()
+	be := newBaseExporter(defaultExporterCfg, zap.NewNop(), WithRetry(rCfg))
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, be.Shutdown(context.Background()))
+	})
+
+	droppedItems, err := be.qrSender.consumerSender.send(newTracesRequest(context.Background(), testdata.GenerateTraceDataManySpansSameResource(2), pusher))
+	assert.True(t, consumererror.IsPermanent(err))
+	assert.Equal(t, 2, droppedItems)
+
+	droppedItems, err = be.qrSender.consumerSender.send(newMockRequest(context.Background(), 3, NewRequestTooLargeError(errors.New("too large"))))
+	assert.True(t, consumererror.IsPermanent(err))
+	assert.Equal(t, 3, droppedItems)
+}
+
 func TestQueuedRetry_DropOnFull(t *testing.T) {
+	recenterrors.Reset()
+	defer recenterrors.Reset()
 	qCfg := DefaultQueueSettings()
 	qCfg.QueueSize = 0
 	rCfg := DefaultRetrySettings()
@@ -296,7 +492,53 @@ func TestQueuedRetry_DropOnFull(t *testing.T) {
 	})
 	droppedItems, err := be.sender.send(newMockRequest(context.Background(), 2, errors.New("transient error")))
 	require.Error(t, err)
+	assert.True(t, consumererror.IsBackpressure(err))
 	assert.Equal(t, 2, droppedItems)
+
+	events := recenterrors.Events()
+	require.Len(t, events, 1)
+	assert.Equal(t, defaultExporterCfg.Name(), events[0].Name)
+	assert.Equal(t, recenterrors.TypeDropped, events[0].Type)
+	assert.Equal(t, int64(2), events[0].Items)
+	assert.Equal(t, "sending_queue is full", events[0].LastError)
+}
+
+func TestQueuedRetry_QueueState(t *testing.T) {
+	qCfg := DefaultQueueSettings()
+	qCfg.NumConsumers = 0
+	qCfg.QueueSize = 2
+	be := newBaseExporter(defaultExporterCfg, zap.NewNop(), WithQueue(qCfg))
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+	assert.Equal(t, 0.0, exporterstate.Saturation()[defaultExporterCfg.Name()])
+
+	_, err := be.sender.send(newMockRequest(context.Background(), 2, nil))
+	require.NoError(t, err)
+	assert.Equal(t, 0.5, exporterstate.Saturation()[defaultExporterCfg.Name()])
+
+	require.NoError(t, be.Shutdown(context.Background()))
+	_, ok := exporterstate.Saturation()[defaultExporterCfg.Name()]
+	assert.False(t, ok)
+}
+
+func TestQueuedRetry_RecordsExportFailures(t *testing.T) {
+	cfg := &configmodels.ExporterSettings{TypeVal: "test", NameVal: "test/failing"}
+	rCfg := DefaultRetrySettings()
+	rCfg.Enabled = false
+	be := newBaseExporter(cfg, zap.NewNop(), WithRetry(rCfg), WithQueue(QueueSettings{Enabled: false}))
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, be.Shutdown(context.Background()))
+	})
+
+	_, err := be.sender.send(newMockRequest(context.Background(), 2, errors.New("transient error")))
+	require.Error(t, err)
+	_, failing := exporterstate.FailingSince(cfg.Name())
+	assert.True(t, failing)
+
+	_, err = be.sender.send(newMockRequest(context.Background(), 2, nil))
+	require.NoError(t, err)
+	_, failing = exporterstate.FailingSince(cfg.Name())
+	assert.False(t, failing)
 }
 
 func TestQueuedRetryHappyPath(t *testing.T) {
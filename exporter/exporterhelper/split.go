@@ -0,0 +1,253 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exporterhelper
+
+import (
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// splittableRequest is a request that can be split in two when it is too large to be exported.
+type splittableRequest interface {
+	request
+	// split returns two requests with half of the items each, in order, ok is false when the
+	// request cannot be split.
+	split() (first request, second request, ok bool)
+}
+
+// splitTraces splits the traces in two parts with half of the spans each, ok is false when
+// there are fewer than two spans. The traces are not modified.
+func splitTraces(td pdata.Traces) (pdata.Traces, pdata.Traces, bool) {
+	count := td.SpanCount()
+	if count < 2 {
+		return td, pdata.Traces{}, false
+	}
+	first, second := newTracesPart(), newTracesPart()
+	left := count / 2
+	part := func() *tracesPart {
+		if left > 0 {
+			return first
+		}
+		return second
+	}
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		ilss := rs.InstrumentationLibrarySpans()
+		if ilss.Len() == 0 {
+			part().resource(i, rs)
+		}
+		for j := 0; j < ilss.Len(); j++ {
+			ils := ilss.At(j)
+			spans := ils.Spans()
+			if spans.Len() == 0 {
+				part().spans(i, rs, j, ils)
+			}
+			for k := 0; k < spans.Len(); k++ {
+				span := pdata.NewSpan()
+				spans.At(k).CopyTo(span)
+				part().spans(i, rs, j, ils).Append(span)
+				left--
+			}
+		}
+	}
+	return first.td, second.td, true
+}
+
+// tracesPart builds a part of split traces, keeping the resources and libraries of the spans.
+type tracesPart struct {
+	td       pdata.Traces
+	rs       pdata.ResourceSpans
+	ils      pdata.InstrumentationLibrarySpans
+	rsIndex  int
+	ilsIndex int
+}
+
+func newTracesPart() *tracesPart {
+	return &tracesPart{td: pdata.NewTraces(), rsIndex: -1, ilsIndex: -1}
+}
+
+// resource returns the resource spans of the part for the resource spans i of the traces.
+func (p *tracesPart) resource(i int, rs pdata.ResourceSpans) pdata.ResourceSpans {
+	if p.rsIndex != i {
+		p.rs = pdata.NewResourceSpans()
+		rs.Resource().CopyTo(p.rs.Resource())
+		p.td.ResourceSpans().Append(p.rs)
+		p.rsIndex, p.ilsIndex = i, -1
+	}
+	return p.rs
+}
+
+// spans returns the spans of the part for the library spans j of the resource spans i.
+func (p *tracesPart) spans(i int, rs pdata.ResourceSpans, j int, ils pdata.InstrumentationLibrarySpans) pdata.SpanSlice {
+	dest := p.resource(i, rs)
+	if p.ilsIndex != j {
+		p.ils = pdata.NewInstrumentationLibrarySpans()
+		ils.InstrumentationLibrary().CopyTo(p.ils.InstrumentationLibrary())
+		dest.InstrumentationLibrarySpans().Append(p.ils)
+		p.ilsIndex = j
+	}
+	return p.ils.Spans()
+}
+
+// splitMetrics splits the metrics in two parts with half of the metrics each, ok is false when
+// there are fewer than two metrics. The data points of a metric are not split. The metrics
+// are not modified.
+func splitMetrics(md pdata.Metrics) (pdata.Metrics, pdata.Metrics, bool) {
+	count := md.MetricCount()
+	if count < 2 {
+		return md, pdata.Metrics{}, false
+	}
+	first, second := newMetricsPart(), newMetricsPart()
+	left := count / 2
+	part := func() *metricsPart {
+		if left > 0 {
+			return first
+		}
+		return second
+	}
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		ilms := rm.InstrumentationLibraryMetrics()
+		if ilms.Len() == 0 {
+			part().resource(i, rm)
+		}
+		for j := 0; j < ilms.Len(); j++ {
+			ilm := ilms.At(j)
+			metrics := ilm.Metrics()
+			if metrics.Len() == 0 {
+				part().metrics(i, rm, j, ilm)
+			}
+			for k := 0; k < metrics.Len(); k++ {
+				metric := pdata.NewMetric()
+				metrics.At(k).CopyTo(metric)
+				part().metrics(i, rm, j, ilm).Append(metric)
+				left--
+			}
+		}
+	}
+	return first.md, second.md, true
+}
+
+// metricsPart builds a part of split metrics, keeping the resources and libraries of the metrics.
+type metricsPart struct {
+	md       pdata.Metrics
+	rm       pdata.ResourceMetrics
+	ilm      pdata.InstrumentationLibraryMetrics
+	rmIndex  int
+	ilmIndex int
+}
+
+func newMetricsPart() *metricsPart {
+	return &metricsPart{md: pdata.NewMetrics(), rmIndex: -1, ilmIndex: -1}
+}
+
+// resource returns the resource metrics of the part for the resource metrics i of the metrics.
+func (p *metricsPart) resource(i int, rm pdata.ResourceMetrics) pdata.ResourceMetrics {
+	if p.rmIndex != i {
+		p.rm = pdata.NewResourceMetrics()
+		rm.Resource().CopyTo(p.rm.Resource())
+		p.md.ResourceMetrics().Append(p.rm)
+		p.rmIndex, p.ilmIndex = i, -1
+	}
+	return p.rm
+}
+
+// metrics returns the metrics of the part for the library metrics j of the resource metrics i.
+func (p *metricsPart) metrics(i int, rm pdata.ResourceMetrics, j int, ilm pdata.InstrumentationLibraryMetrics) pdata.MetricSlice {
+	dest := p.resource(i, rm)
+	if p.ilmIndex != j {
+		p.ilm = pdata.NewInstrumentationLibraryMetrics()
+		ilm.InstrumentationLibrary().CopyTo(p.ilm.InstrumentationLibrary())
+		dest.InstrumentationLibraryMetrics().Append(p.ilm)
+		p.ilmIndex = j
+	}
+	return p.ilm.Metrics()
+}
+
+// splitLogs splits the logs in two parts with half of the log records each, ok is false when
+// there are fewer than two log records. The logs are not modified.
+func splitLogs(ld pdata.Logs) (pdata.Logs, pdata.Logs, bool) {
+	count := ld.LogRecordCount()
+	if count < 2 {
+		return ld, pdata.Logs{}, false
+	}
+	first, second := newLogsPart(), newLogsPart()
+	left := count / 2
+	part := func() *logsPart {
+		if left > 0 {
+			return first
+		}
+		return second
+	}
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		ills := rl.InstrumentationLibraryLogs()
+		if ills.Len() == 0 {
+			part().resource(i, rl)
+		}
+		for j := 0; j < ills.Len(); j++ {
+			ill := ills.At(j)
+			logs := ill.Logs()
+			if logs.Len() == 0 {
+				part().logs(i, rl, j, ill)
+			}
+			for k := 0; k < logs.Len(); k++ {
+				log := pdata.NewLogRecord()
+				logs.At(k).CopyTo(log)
+				part().logs(i, rl, j, ill).Append(log)
+				left--
+			}
+		}
+	}
+	return first.ld, second.ld, true
+}
+
+// logsPart builds a part of split logs, keeping the resources and libraries of the log records.
+type logsPart struct {
+	ld       pdata.Logs
+	rl       pdata.ResourceLogs
+	ill      pdata.InstrumentationLibraryLogs
+	rlIndex  int
+	illIndex int
+}
+
+func newLogsPart() *logsPart {
+	return &logsPart{ld: pdata.NewLogs(), rlIndex: -1, illIndex: -1}
+}
+
+// resource returns the resource logs of the part for the resource logs i of the logs.
+func (p *logsPart) resource(i int, rl pdata.ResourceLogs) pdata.ResourceLogs {
+	if p.rlIndex != i {
+		p.rl = pdata.NewResourceLogs()
+		rl.Resource().CopyTo(p.rl.Resource())
+		p.ld.ResourceLogs().Append(p.rl)
+		p.rlIndex, p.illIndex = i, -1
+	}
+	return p.rl
+}
+
+// logs returns the log records of the part for the library logs j of the resource logs i.
+func (p *logsPart) logs(i int, rl pdata.ResourceLogs, j int, ill pdata.InstrumentationLibraryLogs) pdata.LogSlice {
+	dest := p.resource(i, rl)
+	if p.illIndex != j {
+		p.ill = pdata.NewInstrumentationLibraryLogs()
+		ill.InstrumentationLibrary().CopyTo(p.ill.InstrumentationLibrary())
+		dest.InstrumentationLibraryLogs().Append(p.ill)
+		p.illIndex = j
+	}
+	return p.ill.Logs()
+}
@@ -0,0 +1,83 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exporterhelper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/internal/testdata"
+)
+
+func TestSplitTraces(t *testing.T) {
+	td := testdata.GenerateTraceDataTwoSpansSameResourceOneDifferent()
+	first, second, ok := splitTraces(td)
+	require.True(t, ok)
+	assert.Equal(t, testdata.GenerateTraceDataTwoSpansSameResourceOneDifferent(), td)
+
+	require.Equal(t, 1, first.ResourceSpans().Len())
+	assert.Equal(t, td.ResourceSpans().At(0).Resource(), first.ResourceSpans().At(0).Resource())
+	assert.Equal(t, td.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0),
+		first.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0))
+	assert.Equal(t, 1, first.SpanCount())
+
+	require.Equal(t, 2, second.ResourceSpans().Len())
+	assert.Equal(t, td.ResourceSpans().At(0).Resource(), second.ResourceSpans().At(0).Resource())
+	assert.Equal(t, td.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(1),
+		second.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0))
+	assert.Equal(t, td.ResourceSpans().At(1), second.ResourceSpans().At(1))
+	assert.Equal(t, 2, second.SpanCount())
+
+	_, _, ok = splitTraces(testdata.GenerateTraceDataOneSpan())
+	assert.False(t, ok)
+	_, _, ok = splitTraces(testdata.GenerateTraceDataEmpty())
+	assert.False(t, ok)
+}
+
+func TestSplitMetrics(t *testing.T) {
+	md := testdata.GenerateMetricsManyMetricsSameResource(5)
+	first, second, ok := splitMetrics(md)
+	require.True(t, ok)
+	assert.Equal(t, testdata.GenerateMetricsManyMetricsSameResource(5), md)
+
+	assert.Equal(t, 2, first.MetricCount())
+	assert.Equal(t, 3, second.MetricCount())
+	metrics := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	assert.Equal(t, metrics.At(1), first.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().At(1))
+	assert.Equal(t, metrics.At(2), second.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().At(0))
+	assert.Equal(t, md.ResourceMetrics().At(0).Resource(), second.ResourceMetrics().At(0).Resource())
+
+	_, _, ok = splitMetrics(testdata.GenerateMetricsOneMetric())
+	assert.False(t, ok)
+}
+
+func TestSplitLogs(t *testing.T) {
+	ld := testdata.GenerateLogDataTwoLogsSameResourceOneDifferent()
+	first, second, ok := splitLogs(ld)
+	require.True(t, ok)
+	assert.Equal(t, testdata.GenerateLogDataTwoLogsSameResourceOneDifferent(), ld)
+
+	assert.Equal(t, 1, first.LogRecordCount())
+	assert.Equal(t, 2, second.LogRecordCount())
+	require.Equal(t, 2, second.ResourceLogs().Len())
+	assert.Equal(t, ld.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(1),
+		second.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(0))
+	assert.Equal(t, ld.ResourceLogs().At(1), second.ResourceLogs().At(1))
+
+	_, _, ok = splitLogs(testdata.GenerateLogDataOneLog())
+	assert.False(t, ok)
+}
@@ -57,6 +57,18 @@ func (req *tracesRequest) count() int {
 	return req.td.SpanCount()
 }
 
+func (req *tracesRequest) marshal() ([]byte, error) {
+	return req.td.ToOtlpProtoBytes()
+}
+
+func (req *tracesRequest) split() (request, request, bool) {
+	first, second, ok := splitTraces(req.td)
+	if !ok {
+		return nil, nil, false
+	}
+	return newTracesRequest(req.ctx, first, req.pusher), newTracesRequest(req.ctx, second, req.pusher), true
+}
+
 type traceExporter struct {
 	*baseExporter
 	pusher PushTraces
@@ -90,6 +102,18 @@ func NewTraceExporter(
 	}
 
 	be := newBaseExporter(cfg, logger, options...)
+	be.setSignalPriority(priorityTraces)
+	be.setRequestCodec(
+		func(req request) ([]byte, error) {
+			return req.(*tracesRequest).marshal()
+		},
+		func(data []byte) (request, error) {
+			td := pdata.NewTraces()
+			if err := td.FromOtlpProtoBytes(data); err != nil {
+				return nil, err
+			}
+			return newTracesRequest(obsreport.ExporterContext(context.Background(), cfg.Name()), td, pusher), nil
+		})
 	be.wrapConsumerSender(func(nextSender requestSender) requestSender {
 		return &tracesExporterWithObservability{
 			obsrep:     obsreport.NewExporter(configtelemetry.GetMetricsLevelFlagValue(), cfg.Name()),
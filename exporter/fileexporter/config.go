@@ -15,6 +15,8 @@
 package fileexporter
 
 import (
+	"time"
+
 	"go.opentelemetry.io/collector/config/configmodels"
 )
 
@@ -24,4 +26,39 @@ type Config struct {
 
 	// Path of the file to write to. Path is relative to current directory.
 	Path string `mapstructure:"path"`
+
+	// Format of the data written to the file; options are json and proto. Defaults to json,
+	// one OTLP JSON export request per line. The proto format writes each OTLP protobuf
+	// export request prefixed with its length as a 4 bytes big-endian unsigned integer.
+	Format string `mapstructure:"format"`
+
+	// Compression of the file; the only option is gzip. Defaults to no compression.
+	Compression string `mapstructure:"compression"`
+
+	// Rotation defines the rotation of the file. Defaults to no rotation, the file is
+	// truncated when the collector starts.
+	Rotation *Rotation `mapstructure:"rotation"`
+}
+
+// Rotation defines when the file is rotated, i.e. renamed with a timestamp suffix
+// while a new file is started at Path.
+type Rotation struct {
+	// MaxMegabytes is the size in megabytes after which the file is rotated. Defaults
+	// to zero, i.e. no size based rotation.
+	MaxMegabytes int `mapstructure:"max_megabytes"`
+
+	// Interval is the duration after which the file is rotated. Defaults to zero, i.e.
+	// no time based rotation.
+	Interval time.Duration `mapstructure:"interval"`
+
+	// MaxBackups is the number of rotated files kept, the older ones are removed.
+	// Defaults to zero, i.e. all the rotated files are kept.
+	MaxBackups int `mapstructure:"max_backups"`
+}
+
+var _ configmodels.ConfigValidator = (*Config)(nil)
+
+// Validate checks the exporter configuration is valid.
+func (cfg *Config) Validate() error {
+	return validateConfig(cfg)
 }
@@ -17,6 +17,7 @@ package fileexporter
 import (
 	"path"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -49,4 +50,21 @@ func TestLoadConfig(t *testing.T) {
 			},
 			Path: "./filename.json",
 		})
+
+	e2 := cfg.Exporters["file/archive"]
+	assert.Equal(t, e2,
+		&Config{
+			ExporterSettings: configmodels.ExporterSettings{
+				NameVal: "file/archive",
+				TypeVal: "file",
+			},
+			Path:        "./archive.pb.gz",
+			Format:      "proto",
+			Compression: "gzip",
+			Rotation: &Rotation{
+				MaxMegabytes: 100,
+				Interval:     24 * time.Hour,
+				MaxBackups:   7,
+			},
+		})
 }
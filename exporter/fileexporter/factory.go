@@ -16,7 +16,8 @@ package fileexporter
 
 import (
 	"context"
-	"os"
+	"errors"
+	"fmt"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configmodels"
@@ -26,6 +27,11 @@ import (
 const (
 	// The value of "type" key in configuration.
 	typeStr = "file"
+
+	formatJSON  = "json"
+	formatProto = "proto"
+
+	compressionGzip = "gzip"
 )
 
 // NewFactory creates a factory for OTLP exporter.
@@ -81,11 +87,14 @@ func createExporter(config configmodels.Exporter) (*fileExporter, error) {
 	exporter, ok := exporters[cfg]
 
 	if !ok {
-		file, err := os.OpenFile(cfg.Path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+		if err := validateConfig(cfg); err != nil {
+			return nil, err
+		}
+		file, err := newFileWriter(cfg)
 		if err != nil {
 			return nil, err
 		}
-		exporter = &fileExporter{file: file}
+		exporter = &fileExporter{file: file, format: cfg.Format}
 
 		// Remember the receiver in the map
 		exporters[cfg] = exporter
@@ -93,6 +102,25 @@ func createExporter(config configmodels.Exporter) (*fileExporter, error) {
 	return exporter, nil
 }
 
+func validateConfig(cfg *Config) error {
+	switch cfg.Format {
+	case "", formatJSON, formatProto:
+	default:
+		return fmt.Errorf("unsupported format %q, valid formats are {%s, %s}", cfg.Format, formatJSON, formatProto)
+	}
+	switch cfg.Compression {
+	case "", compressionGzip:
+	default:
+		return fmt.Errorf("unsupported compression %q, the only valid compression is %s", cfg.Compression, compressionGzip)
+	}
+	if r := cfg.Rotation; r != nil {
+		if r.MaxMegabytes < 0 || r.Interval < 0 || r.MaxBackups < 0 {
+			return errors.New("rotation settings must not be negative")
+		}
+	}
+	return nil
+}
+
 // This is the map of already created File exporters for particular configurations.
 // We maintain this map because the Factory is asked trace and metric receivers separately
 // when it gets CreateTracesReceiver() and CreateMetricsReceiver() but they must not
@@ -15,7 +15,9 @@
 package fileexporter
 
 import (
+	"bytes"
 	"context"
+	"encoding/binary"
 	"io"
 	"sync"
 
@@ -34,44 +36,74 @@ import (
 var marshaler = &jsonpb.Marshaler{}
 
 // fileExporter is the implementation of file exporter that writes telemetry data to a file
-// in Protobuf-JSON format.
+// in Protobuf-JSON or length-prefixed Protobuf format.
 type fileExporter struct {
-	file  io.WriteCloser
-	mutex sync.Mutex
+	file   io.WriteCloser
+	format string
+	mutex  sync.Mutex
 }
 
 func (e *fileExporter) ConsumeTraces(_ context.Context, td pdata.Traces) error {
 	request := otlptrace.ExportTraceServiceRequest{
 		ResourceSpans: pdata.TracesToOtlp(td),
 	}
-	return exportMessageAsLine(e, &request)
+	return e.exportMessage(&request)
 }
 
 func (e *fileExporter) ConsumeMetrics(_ context.Context, md pdata.Metrics) error {
 	request := otlpmetrics.ExportMetricsServiceRequest{
 		ResourceMetrics: pdata.MetricsToOtlp(md),
 	}
-	return exportMessageAsLine(e, &request)
+	return e.exportMessage(&request)
 }
 
 func (e *fileExporter) ConsumeLogs(_ context.Context, ld pdata.Logs) error {
 	request := otlplogs.ExportLogsServiceRequest{
 		ResourceLogs: internal.LogsToOtlp(ld.InternalRep()),
 	}
-	return exportMessageAsLine(e, &request)
+	return e.exportMessage(&request)
 }
 
-func exportMessageAsLine(e *fileExporter, message proto.Message) error {
-	// Ensure only one write operation happens at a time.
+func (e *fileExporter) exportMessage(message proto.Message) error {
+	var buf []byte
+	var err error
+	if e.format == formatProto {
+		buf, err = marshalWithLength(message)
+	} else {
+		buf, err = marshalAsLine(message)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Ensure only one write operation happens at a time. The message is written
+	// at once so that a rotation of the file never splits it.
 	e.mutex.Lock()
 	defer e.mutex.Unlock()
-	if err := marshaler.Marshal(e.file, message); err != nil {
-		return err
+	_, err = e.file.Write(buf)
+	return err
+}
+
+func marshalAsLine(message proto.Message) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := marshaler.Marshal(&buf, message); err != nil {
+		return nil, err
 	}
-	if _, err := io.WriteString(e.file, "\n"); err != nil {
-		return err
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+// marshalWithLength marshals the message to Protobuf prefixed with its length as a
+// 4 bytes big-endian unsigned integer.
+func marshalWithLength(message proto.Message) ([]byte, error) {
+	data, err := proto.Marshal(message)
+	if err != nil {
+		return nil, err
 	}
-	return nil
+	buf := make([]byte, 4+len(data))
+	binary.BigEndian.PutUint32(buf, uint32(len(data)))
+	copy(buf[4:], data)
+	return buf, nil
 }
 
 func (e *fileExporter) Start(ctx context.Context, host component.Host) error {
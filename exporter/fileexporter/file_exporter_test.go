@@ -15,10 +15,12 @@ package fileexporter
 
 import (
 	"context"
+	"encoding/binary"
 	"testing"
 	"time"
 
 	"github.com/gogo/protobuf/jsonpb"
+	"github.com/gogo/protobuf/proto"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -51,6 +53,30 @@ func TestFileTraceExporterNoErrors(t *testing.T) {
 	assert.EqualValues(t, pdata.TracesToOtlp(td), j.ResourceSpans)
 }
 
+func TestFileTraceExporterProtoFormat(t *testing.T) {
+	mf := &testutil.LimitedWriter{}
+	lte := &fileExporter{file: mf, format: formatProto}
+	require.NotNil(t, lte)
+
+	td := testdata.GenerateTraceDataTwoSpansSameResource()
+	assert.NoError(t, lte.ConsumeTraces(context.Background(), td))
+	assert.NoError(t, lte.ConsumeTraces(context.Background(), td))
+	assert.NoError(t, lte.Shutdown(context.Background()))
+
+	buf := mf.Bytes()
+	for i := 0; i < 2; i++ {
+		require.True(t, len(buf) >= 4)
+		size := binary.BigEndian.Uint32(buf)
+		require.True(t, len(buf) >= 4+int(size))
+
+		var j collectortrace.ExportTraceServiceRequest
+		require.NoError(t, proto.Unmarshal(buf[4:4+size], &j))
+		assert.EqualValues(t, pdata.TracesToOtlp(td), j.ResourceSpans)
+		buf = buf[4+size:]
+	}
+	assert.Len(t, buf, 0)
+}
+
 func TestFileMetricsExporterNoErrors(t *testing.T) {
 	mf := &testutil.LimitedWriter{}
 	lme := &fileExporter{file: mf}
@@ -0,0 +1,192 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fileexporter
+
+import (
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// backupTimeFormat is the format of the timestamp of the rotated files, it
+// sorts lexicographically.
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// fileWriter writes the batches to the file at path, compressing it and
+// rotating it as configured. Each Write is one batch, a rotation never splits
+// a batch. It is not safe for concurrent use.
+type fileWriter struct {
+	path     string
+	gzip     bool
+	rotation *Rotation
+
+	// now returns the current time, replaced in tests.
+	now func() time.Time
+
+	file   *os.File
+	gz     *gzip.Writer
+	size   int64
+	opened time.Time
+}
+
+func newFileWriter(cfg *Config) (*fileWriter, error) {
+	w := &fileWriter{
+		path:     cfg.Path,
+		gzip:     cfg.Compression == compressionGzip,
+		rotation: cfg.Rotation,
+		now:      time.Now,
+	}
+
+	flags := os.O_RDWR | os.O_CREATE | os.O_TRUNC
+	if w.rotation != nil {
+		// Keep the data of the previous run as a rotated file rather than
+		// truncating it.
+		if info, err := os.Stat(w.path); err == nil && info.Size() > 0 {
+			if err := w.backup(); err != nil {
+				return nil, err
+			}
+		}
+		flags = os.O_RDWR | os.O_CREATE | os.O_EXCL
+	}
+	if err := w.open(flags); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *fileWriter) open(flags int) error {
+	file, err := os.OpenFile(w.path, flags, 0600)
+	if err != nil {
+		return err
+	}
+	w.file = file
+	w.size = 0
+	w.opened = w.now()
+	if w.gzip {
+		w.gz = gzip.NewWriter(file)
+	}
+	return nil
+}
+
+func (w *fileWriter) Write(p []byte) (int, error) {
+	if w.shouldRotate(len(p)) {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	if w.gz == nil {
+		n, err := w.file.Write(p)
+		w.size += int64(n)
+		return n, err
+	}
+
+	n, err := w.gz.Write(p)
+	if err != nil {
+		return n, err
+	}
+	// Flush each batch so that the file can be read up to the last batch even
+	// if the collector is killed.
+	if err = w.gz.Flush(); err != nil {
+		return n, err
+	}
+	info, err := w.file.Stat()
+	if err != nil {
+		return n, err
+	}
+	w.size = info.Size()
+	return n, nil
+}
+
+func (w *fileWriter) shouldRotate(n int) bool {
+	if w.rotation == nil || w.size == 0 {
+		return false
+	}
+	if w.rotation.MaxMegabytes > 0 && w.size+int64(n) > int64(w.rotation.MaxMegabytes)*1024*1024 {
+		return true
+	}
+	return w.rotation.Interval > 0 && w.now().Sub(w.opened) >= w.rotation.Interval
+}
+
+func (w *fileWriter) rotate() error {
+	if err := w.closeFile(); err != nil {
+		return err
+	}
+	if err := w.backup(); err != nil {
+		return err
+	}
+	if err := w.open(os.O_RDWR | os.O_CREATE | os.O_EXCL); err != nil {
+		return err
+	}
+	return w.removeOldBackups()
+}
+
+// backup renames the file at path with the timestamp suffix of now.
+func (w *fileWriter) backup() error {
+	return os.Rename(w.path, w.backupPath(w.now()))
+}
+
+// backupPath returns the path of the file rotated at t, the timestamp is
+// inserted before the extensions, e.g. "traces.json.gz" is rotated as
+// "traces-2006-01-02T15-04-05.000.json.gz".
+func (w *fileWriter) backupPath(t time.Time) string {
+	dir, name := filepath.Split(w.path)
+	base, ext := splitExt(name)
+	return filepath.Join(dir, base+"-"+t.UTC().Format(backupTimeFormat)+ext)
+}
+
+func splitExt(name string) (string, string) {
+	if i := strings.Index(name, "."); i > 0 {
+		return name[:i], name[i:]
+	}
+	return name, ""
+}
+
+func (w *fileWriter) removeOldBackups() error {
+	if w.rotation.MaxBackups <= 0 {
+		return nil
+	}
+	dir, name := filepath.Split(w.path)
+	base, ext := splitExt(name)
+	backups, err := filepath.Glob(filepath.Join(dir, base+"-*"+ext))
+	if err != nil {
+		return err
+	}
+	sort.Strings(backups)
+	for len(backups) > w.rotation.MaxBackups {
+		if err := os.Remove(backups[0]); err != nil {
+			return err
+		}
+		backups = backups[1:]
+	}
+	return nil
+}
+
+func (w *fileWriter) closeFile() error {
+	if w.gz != nil {
+		if err := w.gz.Close(); err != nil {
+			_ = w.file.Close()
+			return err
+		}
+	}
+	return w.file.Close()
+}
+
+func (w *fileWriter) Close() error {
+	return w.closeFile()
+}
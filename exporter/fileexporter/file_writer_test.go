@@ -0,0 +1,196 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fileexporter
+
+import (
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestFileWriter(t *testing.T, cfg *Config, now *time.Time) *fileWriter {
+	w, err := newFileWriter(cfg)
+	require.NoError(t, err)
+	w.now = func() time.Time { return *now }
+	w.opened = *now
+	return w
+}
+
+func listDir(t *testing.T, dir string) []string {
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	var names []string
+	for _, f := range files {
+		names = append(names, f.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+func readFile(t *testing.T, path string) string {
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestFileWriterTruncates(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fileexporter")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "data.json")
+	require.NoError(t, ioutil.WriteFile(path, []byte("previous\n"), 0600))
+
+	w, err := newFileWriter(&Config{Path: path})
+	require.NoError(t, err)
+	_, err = w.Write([]byte("batch\n"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	assert.Equal(t, []string{"data.json"}, listDir(t, dir))
+	assert.Equal(t, "batch\n", readFile(t, path))
+}
+
+func TestFileWriterRotatesBySize(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fileexporter")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "data.json")
+
+	now := time.Date(2020, 11, 5, 10, 30, 0, 0, time.UTC)
+	w := newTestFileWriter(t, &Config{Path: path, Rotation: &Rotation{MaxMegabytes: 1}}, &now)
+
+	batch := make([]byte, 600*1024)
+	_, err = w.Write(batch)
+	require.NoError(t, err)
+	now = now.Add(time.Second)
+	// The second batch does not fit in the file, the file is rotated before it
+	// is written.
+	_, err = w.Write(batch)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	assert.Equal(t, []string{"data-2020-11-05T10-30-01.000.json", "data.json"}, listDir(t, dir))
+	assert.Len(t, readFile(t, filepath.Join(dir, "data-2020-11-05T10-30-01.000.json")), len(batch))
+	assert.Len(t, readFile(t, path), len(batch))
+}
+
+func TestFileWriterRotatesByInterval(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fileexporter")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "data.json")
+
+	now := time.Date(2020, 11, 5, 10, 30, 0, 0, time.UTC)
+	w := newTestFileWriter(t, &Config{Path: path, Rotation: &Rotation{Interval: time.Hour, MaxBackups: 2}}, &now)
+
+	for i := 0; i < 4; i++ {
+		_, err = w.Write([]byte("batch\n"))
+		require.NoError(t, err)
+		now = now.Add(30 * time.Minute)
+	}
+	// The file is only rotated when a batch is written.
+	assert.Equal(t, []string{"data-2020-11-05T11-30-00.000.json", "data.json"}, listDir(t, dir))
+
+	for i := 0; i < 4; i++ {
+		_, err = w.Write([]byte("batch\n"))
+		require.NoError(t, err)
+		now = now.Add(30 * time.Minute)
+	}
+	require.NoError(t, w.Close())
+
+	// Only the two most recent rotated files are kept.
+	assert.Equal(t, []string{
+		"data-2020-11-05T12-30-00.000.json",
+		"data-2020-11-05T13-30-00.000.json",
+		"data.json",
+	}, listDir(t, dir))
+	assert.Equal(t, "batch\nbatch\n", readFile(t, filepath.Join(dir, "data-2020-11-05T13-30-00.000.json")))
+	assert.Equal(t, "batch\nbatch\n", readFile(t, path))
+}
+
+func TestFileWriterRotatesPreviousFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fileexporter")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "data.json")
+	require.NoError(t, ioutil.WriteFile(path, []byte("previous\n"), 0600))
+
+	w, err := newFileWriter(&Config{Path: path, Rotation: &Rotation{MaxMegabytes: 1}})
+	require.NoError(t, err)
+	_, err = w.Write([]byte("batch\n"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	names := listDir(t, dir)
+	require.Len(t, names, 2)
+	assert.Equal(t, "previous\n", readFile(t, filepath.Join(dir, names[0])))
+	assert.Equal(t, "batch\n", readFile(t, path))
+}
+
+func TestFileWriterGzip(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fileexporter")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "data.json.gz")
+
+	now := time.Date(2020, 11, 5, 10, 30, 0, 0, time.UTC)
+	w := newTestFileWriter(t, &Config{Path: path, Compression: compressionGzip, Rotation: &Rotation{Interval: time.Minute}}, &now)
+
+	_, err = w.Write([]byte("first\n"))
+	require.NoError(t, err)
+	// Each batch is flushed, the file can be read before it is closed.
+	r, err := gzip.NewReader(openFile(t, path))
+	require.NoError(t, err)
+	data, _ := ioutil.ReadAll(r)
+	assert.Equal(t, "first\n", string(data))
+
+	now = now.Add(time.Minute)
+	_, err = w.Write([]byte("second\n"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	assert.Equal(t, []string{"data-2020-11-05T10-31-00.000.json.gz", "data.json.gz"}, listDir(t, dir))
+	for name, expected := range map[string]string{
+		"data-2020-11-05T10-31-00.000.json.gz": "first\n",
+		"data.json.gz":                         "second\n",
+	} {
+		r, err := gzip.NewReader(openFile(t, filepath.Join(dir, name)))
+		require.NoError(t, err)
+		data, err := ioutil.ReadAll(r)
+		require.NoError(t, err)
+		assert.Equal(t, expected, string(data))
+	}
+}
+
+func openFile(t *testing.T, path string) *os.File {
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	t.Cleanup(func() { f.Close() })
+	return f
+}
+
+func TestValidateConfig(t *testing.T) {
+	assert.NoError(t, validateConfig(&Config{Format: formatProto, Compression: compressionGzip, Rotation: &Rotation{MaxMegabytes: 10}}))
+	assert.EqualError(t, validateConfig(&Config{Format: "xml"}), `unsupported format "xml", valid formats are {json, proto}`)
+	assert.EqualError(t, validateConfig(&Config{Compression: "zstd"}), `unsupported compression "zstd", the only valid compression is gzip`)
+	assert.EqualError(t, validateConfig(&Config{Rotation: &Rotation{MaxBackups: -1}}), "rotation settings must not be negative")
+}
@@ -35,7 +35,7 @@ type Authentication struct {
 // PlainTextConfig defines plaintext authentication.
 type PlainTextConfig struct {
 	Username string `mapstructure:"username"`
-	Password string `mapstructure:"password"`
+	Password string `mapstructure:"password" sensitive:"true"`
 }
 
 // SASLConfig defines the configuration for the SASL authentication.
@@ -43,9 +43,31 @@ type SASLConfig struct {
 	// Username to be used on authentication
 	Username string `mapstructure:"username"`
 	// Password to be used on authentication
-	Password string `mapstructure:"password"`
-	// SASL Mechanism to be used, possible values are: (PLAIN, SCRAM-SHA-256 or SCRAM-SHA-512).
+	Password string `mapstructure:"password" sensitive:"true"`
+	// SASL Mechanism to be used, possible values are: (PLAIN, SCRAM-SHA-256, SCRAM-SHA-512, OAUTHBEARER or AWS_MSK_IAM).
 	Mechanism string `mapstructure:"mechanism"`
+	// OAuthBearer configures the OAUTHBEARER mechanism, the username and password being
+	// the client ID and secret of the OAuth2 client credentials flow.
+	OAuthBearer OAuthBearerConfig `mapstructure:"oauthbearer"`
+	// AWSMSK configures the AWS_MSK_IAM mechanism, the username and password being unused.
+	AWSMSK AWSMSKConfig `mapstructure:"aws_msk"`
+}
+
+// OAuthBearerConfig defines the configuration of the OAuth2 client credentials flow
+// providing the OAUTHBEARER tokens.
+type OAuthBearerConfig struct {
+	// TokenURL is the URL of the token endpoint of the authorization server.
+	TokenURL string `mapstructure:"token_url"`
+	// Scopes are the optional scopes of the requested tokens.
+	Scopes []string `mapstructure:"scopes"`
+}
+
+// AWSMSKConfig defines the configuration of the AWS MSK IAM authentication.
+type AWSMSKConfig struct {
+	// Region of the MSK cluster, defaults to the region of the AWS SDK configuration.
+	Region string `mapstructure:"region"`
+	// RoleARN is the optional ARN of the role assumed to sign the tokens.
+	RoleARN string `mapstructure:"role_arn"`
 }
 
 // KerberosConfig defines kereros configuration.
@@ -54,7 +76,7 @@ type KerberosConfig struct {
 	Realm       string `mapstructure:"realm"`
 	UseKeyTab   bool   `mapstructure:"use_keytab"`
 	Username    string `mapstructure:"username"`
-	Password    string `mapstructure:"password" json:"-"`
+	Password    string `mapstructure:"password" json:"-" sensitive:"true"`
 	ConfigPath  string `mapstructure:"config_file"`
 	KeyTabPath  string `mapstructure:"keytab_file"`
 }
@@ -89,6 +111,17 @@ func configurePlaintext(config PlainTextConfig, saramaConfig *sarama.Config) {
 
 func configureSASL(config SASLConfig, saramaConfig *sarama.Config) error {
 
+	if config.Mechanism == "AWS_MSK_IAM" {
+		tokenProvider, err := newAWSMSKTokenProvider(config.AWSMSK)
+		if err != nil {
+			return err
+		}
+		saramaConfig.Net.SASL.Enable = true
+		saramaConfig.Net.SASL.Mechanism = sarama.SASLTypeOAuth
+		saramaConfig.Net.SASL.TokenProvider = tokenProvider
+		return nil
+	}
+
 	if config.Username == "" {
 		return fmt.Errorf("username have to be provided")
 	}
@@ -110,8 +143,15 @@ func configureSASL(config SASLConfig, saramaConfig *sarama.Config) error {
 		saramaConfig.Net.SASL.Mechanism = sarama.SASLTypeSCRAMSHA256
 	case "PLAIN":
 		saramaConfig.Net.SASL.Mechanism = sarama.SASLTypePlaintext
+	case "OAUTHBEARER":
+		tokenProvider, err := newOAuthTokenProvider(config.Username, config.Password, config.OAuthBearer)
+		if err != nil {
+			return err
+		}
+		saramaConfig.Net.SASL.Mechanism = sarama.SASLTypeOAuth
+		saramaConfig.Net.SASL.TokenProvider = tokenProvider
 	default:
-		return fmt.Errorf("invalid SASL Mechanism %q: can be either \"PLAIN\" , \"SCRAM-SHA-256\" or \"SCRAM-SHA-512\"", config.Mechanism)
+		return fmt.Errorf("invalid SASL Mechanism %q: can be either \"PLAIN\" , \"SCRAM-SHA-256\", \"SCRAM-SHA-512\", \"OAUTHBEARER\" or \"AWS_MSK_IAM\"", config.Mechanism)
 	}
 
 	return nil
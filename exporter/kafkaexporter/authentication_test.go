@@ -49,6 +49,16 @@ func TestAuthentication(t *testing.T) {
 
 	saramaSASLPLAINConfig.Net.SASL.Mechanism = sarama.SASLTypePlaintext
 
+	saramaSASLOAuthBearerConfig := &sarama.Config{}
+	saramaSASLOAuthBearerConfig.Net.SASL.Enable = true
+	saramaSASLOAuthBearerConfig.Net.SASL.User = "jdoe"
+	saramaSASLOAuthBearerConfig.Net.SASL.Password = "pass"
+	saramaSASLOAuthBearerConfig.Net.SASL.Mechanism = sarama.SASLTypeOAuth
+
+	saramaSASLAWSMSKConfig := &sarama.Config{}
+	saramaSASLAWSMSKConfig.Net.SASL.Enable = true
+	saramaSASLAWSMSKConfig.Net.SASL.Mechanism = sarama.SASLTypeOAuth
+
 	saramaTLSCfg := &sarama.Config{}
 	saramaTLSCfg.Net.TLS.Enable = true
 	tlsClient := configtls.TLSClientSetting{}
@@ -109,6 +119,20 @@ func TestAuthentication(t *testing.T) {
 			auth:         Authentication{SASL: &SASLConfig{Username: "jdoe", Password: "pass", Mechanism: "PLAIN"}},
 			saramaConfig: saramaSASLPLAINConfig,
 		},
+		{
+			auth: Authentication{SASL: &SASLConfig{Username: "jdoe", Password: "pass", Mechanism: "OAUTHBEARER",
+				OAuthBearer: OAuthBearerConfig{TokenURL: "http://localhost/token"}}},
+			saramaConfig: saramaSASLOAuthBearerConfig,
+		},
+		{
+			auth:         Authentication{SASL: &SASLConfig{Username: "jdoe", Password: "pass", Mechanism: "OAUTHBEARER"}},
+			saramaConfig: saramaSASLOAuthBearerConfig,
+			err:          "token_url have to be provided",
+		},
+		{
+			auth:         Authentication{SASL: &SASLConfig{Mechanism: "AWS_MSK_IAM", AWSMSK: AWSMSKConfig{Region: "us-east-1"}}},
+			saramaConfig: saramaSASLAWSMSKConfig,
+		},
 		{
 			auth:         Authentication{SASL: &SASLConfig{Username: "jdoe", Password: "pass", Mechanism: "SCRAM-SHA-222"}},
 			saramaConfig: saramaSASLSCRAM512Config,
@@ -135,6 +159,10 @@ func TestAuthentication(t *testing.T) {
 			} else {
 				// equalizes SCRAMClientGeneratorFunc to do assertion with the same reference.
 				config.Net.SASL.SCRAMClientGeneratorFunc = test.saramaConfig.Net.SASL.SCRAMClientGeneratorFunc
+				if test.saramaConfig.Net.SASL.Mechanism == sarama.SASLTypeOAuth {
+					assert.NotNil(t, config.Net.SASL.TokenProvider)
+					config.Net.SASL.TokenProvider = nil
+				}
 				assert.Equal(t, test.saramaConfig, config)
 			}
 		})
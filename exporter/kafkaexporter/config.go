@@ -38,6 +38,10 @@ type Config struct {
 	// Encoding of messages (default "otlp_proto")
 	Encoding string `mapstructure:"encoding"`
 
+	// PartitionBy defines how the traces are assigned to partitions: "trace_id", "resource"
+	// or "random" (default "random"). Ignored by the metrics exporter.
+	PartitionBy string `mapstructure:"partition_by"`
+
 	// Metadata is the namespace for metadata management properties used by the
 	// Client, and shared by the Producer/Consumer.
 	Metadata Metadata `mapstructure:"metadata"`
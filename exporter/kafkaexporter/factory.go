@@ -28,6 +28,7 @@ const (
 	defaultTracesTopic  = "otlp_spans"
 	defaultMetricsTopic = "otlp_metrics"
 	defaultEncoding     = "otlp_proto"
+	defaultPartitionBy  = partitionByRandom
 	defaultBroker       = "localhost:9092"
 	// default from sarama.NewConfig()
 	defaultMetadataRetryMax = 3
@@ -76,8 +77,9 @@ func createDefaultConfig() configmodels.Exporter {
 		QueueSettings:   exporterhelper.DefaultQueueSettings(),
 		Brokers:         []string{defaultBroker},
 		// using an empty topic to track when it has not been set by user, default is based on traces or metrics.
-		Topic:    "",
-		Encoding: defaultEncoding,
+		Topic:       "",
+		Encoding:    defaultEncoding,
+		PartitionBy: defaultPartitionBy,
 		Metadata: Metadata{
 			Full: defaultMetadataFull,
 			Retry: MetadataRetry{
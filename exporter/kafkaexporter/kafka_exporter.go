@@ -30,14 +30,15 @@ var errUnrecognizedEncoding = fmt.Errorf("unrecognized encoding")
 
 // kafkaTracesProducer uses sarama to produce trace messages to Kafka.
 type kafkaTracesProducer struct {
-	producer   sarama.SyncProducer
-	topic      string
-	marshaller TracesMarshaller
-	logger     *zap.Logger
+	producer    sarama.SyncProducer
+	topic       string
+	marshaller  TracesMarshaller
+	partitioner tracesPartitioner
+	logger      *zap.Logger
 }
 
 func (e *kafkaTracesProducer) traceDataPusher(_ context.Context, td pdata.Traces) (int, error) {
-	messages, err := e.marshaller.Marshal(td)
+	messages, err := e.marshal(td)
 	if err != nil {
 		return td.SpanCount(), consumererror.Permanent(err)
 	}
@@ -48,6 +49,26 @@ func (e *kafkaTracesProducer) traceDataPusher(_ context.Context, td pdata.Traces
 	return 0, nil
 }
 
+// marshal serializes the traces, every message being keyed by its partition when the
+// traces are partitioned.
+func (e *kafkaTracesProducer) marshal(td pdata.Traces) ([]Message, error) {
+	if e.partitioner == nil {
+		return e.marshaller.Marshal(td)
+	}
+	var messages []Message
+	for _, partition := range e.partitioner(td) {
+		partitionMessages, err := e.marshaller.Marshal(partition.traces)
+		if err != nil {
+			return nil, err
+		}
+		for i := range partitionMessages {
+			partitionMessages[i].Key = partition.key
+		}
+		messages = append(messages, partitionMessages...)
+	}
+	return messages, nil
+}
+
 func (e *kafkaTracesProducer) Close(context.Context) error {
 	return e.producer.Close()
 }
@@ -130,15 +151,20 @@ func newTracesExporter(config Config, params component.ExporterCreateParams, mar
 	if marshaller == nil {
 		return nil, errUnrecognizedEncoding
 	}
+	partitioner, err := newTracesPartitioner(config.PartitionBy)
+	if err != nil {
+		return nil, err
+	}
 	producer, err := newSaramaProducer(config)
 	if err != nil {
 		return nil, err
 	}
 	return &kafkaTracesProducer{
-		producer:   producer,
-		topic:      config.Topic,
-		marshaller: marshaller,
-		logger:     params.Logger,
+		producer:    producer,
+		topic:       config.Topic,
+		marshaller:  marshaller,
+		partitioner: partitioner,
+		logger:      params.Logger,
 	}, nil
 }
 
@@ -149,6 +175,10 @@ func producerMessages(messages []Message, topic string) []*sarama.ProducerMessag
 			Topic: topic,
 			Value: sarama.ByteEncoder(messages[i].Value),
 		}
+		// sarama's default partitioner sends the messages without key to random partitions.
+		if messages[i].Key != nil {
+			producerMessages[i].Key = sarama.ByteEncoder(messages[i].Key)
+		}
 	}
 	return producerMessages
 }
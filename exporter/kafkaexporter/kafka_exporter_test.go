@@ -108,6 +108,68 @@ func TestTraceDataPusher(t *testing.T) {
 	assert.Equal(t, 0, droppedSpans)
 }
 
+func TestTraceDataPusher_partition_by(t *testing.T) {
+	c := sarama.NewConfig()
+	producer := mocks.NewSyncProducer(t, c)
+	producer.ExpectSendMessageAndSucceed()
+	producer.ExpectSendMessageAndSucceed()
+
+	p := kafkaTracesProducer{
+		producer:    producer,
+		marshaller:  &otlpTracesPbMarshaller{},
+		partitioner: partitionTracesByTraceID,
+	}
+	t.Cleanup(func() {
+		require.NoError(t, p.Close(context.Background()))
+	})
+	droppedSpans, err := p.traceDataPusher(context.Background(), generateTraces())
+	require.NoError(t, err)
+	assert.Equal(t, 0, droppedSpans)
+}
+
+func TestTracesProducerMarshal(t *testing.T) {
+	p := kafkaTracesProducer{
+		marshaller: jaegerMarshaller{marshaller: jaegerProtoSpanMarshaller{}},
+	}
+	messages, err := p.marshal(generateTraces())
+	require.NoError(t, err)
+	require.Len(t, messages, 4)
+	for _, message := range messages {
+		assert.Nil(t, message.Key)
+	}
+
+	p.partitioner = partitionTracesByTraceID
+	messages, err = p.marshal(generateTraces())
+	require.NoError(t, err)
+	require.Len(t, messages, 4)
+	id1, id2 := traceID1.Bytes(), traceID2.Bytes()
+	keys := [][]byte{id1[:], id1[:], id1[:], id2[:]}
+	for i, message := range messages {
+		assert.Equal(t, keys[i], message.Key)
+	}
+
+	p.marshaller = &tracesErrorMarshaller{err: fmt.Errorf("failed to marshall")}
+	_, err = p.marshal(generateTraces())
+	assert.EqualError(t, err, "failed to marshall")
+}
+
+func TestProducerMessages(t *testing.T) {
+	messages := producerMessages([]Message{{Value: []byte("foo")}, {Key: []byte("key"), Value: []byte("bar")}}, "spans")
+	require.Len(t, messages, 2)
+	assert.Equal(t, "spans", messages[0].Topic)
+	assert.Equal(t, sarama.ByteEncoder("foo"), messages[0].Value)
+	assert.Nil(t, messages[0].Key)
+	assert.Equal(t, sarama.ByteEncoder("key"), messages[1].Key)
+	assert.Equal(t, sarama.ByteEncoder("bar"), messages[1].Value)
+}
+
+func TestNewExporter_err_partition_by(t *testing.T) {
+	c := Config{Encoding: defaultEncoding, PartitionBy: "span_id"}
+	texp, err := newTracesExporter(c, component.ExporterCreateParams{Logger: zap.NewNop()}, tracesMarshallers())
+	assert.EqualError(t, err, errUnrecognizedPartitionBy.Error())
+	assert.Nil(t, texp)
+}
+
 func TestTraceDataPusher_err(t *testing.T) {
 	c := sarama.NewConfig()
 	producer := mocks.NewSyncProducer(t, c)
@@ -38,6 +38,8 @@ type MetricsMarshaller interface {
 
 // Message encapsulates Kafka's message payload.
 type Message struct {
+	// Key is the message key, the messages with the same key are sent to the same partition.
+	Key   []byte
 	Value []byte
 }
 
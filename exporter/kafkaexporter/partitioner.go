@@ -0,0 +1,145 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkaexporter
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+)
+
+const (
+	partitionByRandom   = "random"
+	partitionByTraceID  = "trace_id"
+	partitionByResource = "resource"
+)
+
+var errUnrecognizedPartitionBy = fmt.Errorf("unrecognized partition_by")
+
+// keyedTraces are traces sent to Kafka with the same message key.
+type keyedTraces struct {
+	key    []byte
+	traces pdata.Traces
+}
+
+// tracesPartitioner splits traces into keyedTraces, the messages with the same key
+// being sent to the same partition.
+type tracesPartitioner func(td pdata.Traces) []keyedTraces
+
+// newTracesPartitioner returns the tracesPartitioner for the given partition_by value,
+// or nil when the messages are sent to random partitions.
+func newTracesPartitioner(partitionBy string) (tracesPartitioner, error) {
+	switch partitionBy {
+	case "", partitionByRandom:
+		return nil, nil
+	case partitionByTraceID:
+		return partitionTracesByTraceID, nil
+	case partitionByResource:
+		return partitionTracesByResource, nil
+	}
+	return nil, errUnrecognizedPartitionBy
+}
+
+// partitionTracesByTraceID splits the traces by trace ID, keeping the resource and the
+// instrumentation library of every span.
+func partitionTracesByTraceID(td pdata.Traces) []keyedTraces {
+	var partitions []keyedTraces
+	indexes := make(map[pdata.TraceID]int)
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		destRss := make(map[int]pdata.ResourceSpans)
+		ilss := rs.InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			ils := ilss.At(j)
+			destIlss := make(map[int]pdata.InstrumentationLibrarySpans)
+			spans := ils.Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				idx, ok := indexes[span.TraceID()]
+				if !ok {
+					idx = len(partitions)
+					indexes[span.TraceID()] = idx
+					traceID := span.TraceID().Bytes()
+					partitions = append(partitions, keyedTraces{key: traceID[:], traces: pdata.NewTraces()})
+				}
+				destIls, ok := destIlss[idx]
+				if !ok {
+					destRs, ok := destRss[idx]
+					if !ok {
+						partitionRss := partitions[idx].traces.ResourceSpans()
+						partitionRss.Resize(partitionRss.Len() + 1)
+						destRs = partitionRss.At(partitionRss.Len() - 1)
+						rs.Resource().CopyTo(destRs.Resource())
+						destRss[idx] = destRs
+					}
+					destIls = pdata.NewInstrumentationLibrarySpans()
+					destRs.InstrumentationLibrarySpans().Append(destIls)
+					ils.InstrumentationLibrary().CopyTo(destIls.InstrumentationLibrary())
+					destIlss[idx] = destIls
+				}
+				destSpans := destIls.Spans()
+				destSpans.Resize(destSpans.Len() + 1)
+				span.CopyTo(destSpans.At(destSpans.Len() - 1))
+			}
+		}
+	}
+	return partitions
+}
+
+// partitionTracesByResource splits the traces by resource, the key being a hash of the
+// resource attributes.
+func partitionTracesByResource(td pdata.Traces) []keyedTraces {
+	var partitions []keyedTraces
+	indexes := make(map[string]int)
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		key := resourceKey(rs.Resource())
+		idx, ok := indexes[string(key)]
+		if !ok {
+			idx = len(partitions)
+			indexes[string(key)] = idx
+			partitions = append(partitions, keyedTraces{key: key, traces: pdata.NewTraces()})
+		}
+		destRss := partitions[idx].traces.ResourceSpans()
+		destRss.Resize(destRss.Len() + 1)
+		rs.CopyTo(destRss.At(destRss.Len() - 1))
+	}
+	return partitions
+}
+
+// resourceKey returns the FNV-1a hash of the resource attributes, independent of their order.
+func resourceKey(resource pdata.Resource) []byte {
+	attrs := resource.Attributes()
+	keys := make([]string, 0, attrs.Len())
+	values := make(map[string]string, attrs.Len())
+	attrs.ForEach(func(k string, v pdata.AttributeValue) {
+		keys = append(keys, k)
+		values[k] = tracetranslator.AttributeValueToString(v, false)
+	})
+	sort.Strings(keys)
+	h := fnv.New64a()
+	for _, k := range keys {
+		h.Write([]byte(k))
+		h.Write([]byte{0})
+		h.Write([]byte(values[k]))
+		h.Write([]byte{0})
+	}
+	return h.Sum(nil)
+}
@@ -0,0 +1,129 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkaexporter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+var (
+	traceID1 = pdata.NewTraceID([16]byte{1})
+	traceID2 = pdata.NewTraceID([16]byte{2})
+)
+
+// generateTraces returns two resources, "foo" with spans of both traces in two
+// instrumentation libraries and "bar" with a span of the first trace.
+func generateTraces() pdata.Traces {
+	td := pdata.NewTraces()
+	td.ResourceSpans().Resize(2)
+	foo := td.ResourceSpans().At(0)
+	foo.Resource().Attributes().InsertString("service.name", "foo")
+	foo.Resource().Attributes().InsertString("host.name", "h1")
+	foo.InstrumentationLibrarySpans().Resize(2)
+	foo.InstrumentationLibrarySpans().At(0).InstrumentationLibrary().SetName("lib1")
+	foo.InstrumentationLibrarySpans().At(0).Spans().Resize(2)
+	foo.InstrumentationLibrarySpans().At(0).Spans().At(0).SetName("foo1")
+	foo.InstrumentationLibrarySpans().At(0).Spans().At(0).SetTraceID(traceID1)
+	foo.InstrumentationLibrarySpans().At(0).Spans().At(0).SetSpanID(pdata.NewSpanID([8]byte{1}))
+	foo.InstrumentationLibrarySpans().At(0).Spans().At(1).SetName("foo2")
+	foo.InstrumentationLibrarySpans().At(0).Spans().At(1).SetTraceID(traceID2)
+	foo.InstrumentationLibrarySpans().At(0).Spans().At(1).SetSpanID(pdata.NewSpanID([8]byte{2}))
+	foo.InstrumentationLibrarySpans().At(1).InstrumentationLibrary().SetName("lib2")
+	foo.InstrumentationLibrarySpans().At(1).Spans().Resize(1)
+	foo.InstrumentationLibrarySpans().At(1).Spans().At(0).SetName("foo3")
+	foo.InstrumentationLibrarySpans().At(1).Spans().At(0).SetTraceID(traceID1)
+	foo.InstrumentationLibrarySpans().At(1).Spans().At(0).SetSpanID(pdata.NewSpanID([8]byte{3}))
+	bar := td.ResourceSpans().At(1)
+	bar.Resource().Attributes().InsertString("service.name", "bar")
+	bar.InstrumentationLibrarySpans().Resize(1)
+	bar.InstrumentationLibrarySpans().At(0).Spans().Resize(1)
+	bar.InstrumentationLibrarySpans().At(0).Spans().At(0).SetName("bar1")
+	bar.InstrumentationLibrarySpans().At(0).Spans().At(0).SetTraceID(traceID1)
+	bar.InstrumentationLibrarySpans().At(0).Spans().At(0).SetSpanID(pdata.NewSpanID([8]byte{4}))
+	return td
+}
+
+func TestNewTracesPartitioner(t *testing.T) {
+	for _, partitionBy := range []string{"", partitionByRandom} {
+		partitioner, err := newTracesPartitioner(partitionBy)
+		require.NoError(t, err)
+		assert.Nil(t, partitioner)
+	}
+	for _, partitionBy := range []string{partitionByTraceID, partitionByResource} {
+		partitioner, err := newTracesPartitioner(partitionBy)
+		require.NoError(t, err)
+		assert.NotNil(t, partitioner)
+	}
+	partitioner, err := newTracesPartitioner("span_id")
+	assert.Equal(t, errUnrecognizedPartitionBy, err)
+	assert.Nil(t, partitioner)
+}
+
+func TestPartitionTracesByTraceID(t *testing.T) {
+	partitions := partitionTracesByTraceID(generateTraces())
+	require.Len(t, partitions, 2)
+
+	id1 := traceID1.Bytes()
+	assert.Equal(t, id1[:], partitions[0].key)
+	trace1 := partitions[0].traces
+	assert.Equal(t, 3, trace1.SpanCount())
+	require.Equal(t, 2, trace1.ResourceSpans().Len())
+	foo := trace1.ResourceSpans().At(0)
+	serviceName, _ := foo.Resource().Attributes().Get("service.name")
+	assert.Equal(t, "foo", serviceName.StringVal())
+	require.Equal(t, 2, foo.InstrumentationLibrarySpans().Len())
+	assert.Equal(t, "lib1", foo.InstrumentationLibrarySpans().At(0).InstrumentationLibrary().Name())
+	assert.Equal(t, "foo1", foo.InstrumentationLibrarySpans().At(0).Spans().At(0).Name())
+	assert.Equal(t, "lib2", foo.InstrumentationLibrarySpans().At(1).InstrumentationLibrary().Name())
+	assert.Equal(t, "foo3", foo.InstrumentationLibrarySpans().At(1).Spans().At(0).Name())
+	bar := trace1.ResourceSpans().At(1)
+	serviceName, _ = bar.Resource().Attributes().Get("service.name")
+	assert.Equal(t, "bar", serviceName.StringVal())
+	assert.Equal(t, "bar1", bar.InstrumentationLibrarySpans().At(0).Spans().At(0).Name())
+
+	id2 := traceID2.Bytes()
+	assert.Equal(t, id2[:], partitions[1].key)
+	trace2 := partitions[1].traces
+	require.Equal(t, 1, trace2.SpanCount())
+	assert.Equal(t, "lib1", trace2.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).InstrumentationLibrary().Name())
+	assert.Equal(t, "foo2", trace2.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0).Name())
+
+	assert.Len(t, partitionTracesByTraceID(pdata.NewTraces()), 0)
+}
+
+func TestPartitionTracesByResource(t *testing.T) {
+	td := generateTraces()
+	// Same resource as the first one, with the attributes in another order.
+	td.ResourceSpans().Resize(3)
+	foo := td.ResourceSpans().At(2)
+	foo.Resource().Attributes().InsertString("host.name", "h1")
+	foo.Resource().Attributes().InsertString("service.name", "foo")
+	foo.InstrumentationLibrarySpans().Resize(1)
+	foo.InstrumentationLibrarySpans().At(0).Spans().Resize(1)
+
+	partitions := partitionTracesByResource(td)
+	require.Len(t, partitions, 2)
+	assert.NotEqual(t, partitions[0].key, partitions[1].key)
+	assert.Equal(t, resourceKey(td.ResourceSpans().At(0).Resource()), partitions[0].key)
+	require.Equal(t, 2, partitions[0].traces.ResourceSpans().Len())
+	assert.Equal(t, 4, partitions[0].traces.SpanCount())
+	assert.Equal(t, resourceKey(td.ResourceSpans().At(1).Resource()), partitions[1].key)
+	assert.Equal(t, 1, partitions[1].traces.SpanCount())
+}
@@ -0,0 +1,117 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkaexporter
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/session"
+	v4 "github.com/aws/aws-sdk-go/aws/signer/v4"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+const (
+	awsMSKService     = "kafka-cluster"
+	awsMSKAction      = "kafka-cluster:Connect"
+	awsMSKTokenExpiry = 15 * time.Minute
+	awsMSKUserAgent   = "opentelemetry-collector"
+)
+
+var _ sarama.AccessTokenProvider = (*oauthTokenProvider)(nil)
+var _ sarama.AccessTokenProvider = (*awsMSKTokenProvider)(nil)
+
+// oauthTokenProvider provides the OAUTHBEARER tokens obtained with the OAuth2 client
+// credentials flow, the tokens being cached until they expire.
+type oauthTokenProvider struct {
+	tokenSource oauth2.TokenSource
+}
+
+func newOAuthTokenProvider(clientID, clientSecret string, config OAuthBearerConfig) (*oauthTokenProvider, error) {
+	if config.TokenURL == "" {
+		return nil, fmt.Errorf("token_url have to be provided")
+	}
+	cc := clientcredentials.Config{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		TokenURL:     config.TokenURL,
+		Scopes:       config.Scopes,
+	}
+	return &oauthTokenProvider{tokenSource: cc.TokenSource(context.Background())}, nil
+}
+
+func (p *oauthTokenProvider) Token() (*sarama.AccessToken, error) {
+	token, err := p.tokenSource.Token()
+	if err != nil {
+		return nil, err
+	}
+	return &sarama.AccessToken{Token: token.AccessToken}, nil
+}
+
+// awsMSKTokenProvider provides the OAUTHBEARER tokens of the AWS MSK IAM authentication,
+// a token being the base64 encoded URL of a SigV4 presigned kafka-cluster:Connect request.
+type awsMSKTokenProvider struct {
+	region string
+	signer *v4.Signer
+	now    func() time.Time
+}
+
+func newAWSMSKTokenProvider(config AWSMSKConfig) (*awsMSKTokenProvider, error) {
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Config:            aws.Config{Region: aws.String(config.Region)},
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create AWS session: %w", err)
+	}
+	region := aws.StringValue(sess.Config.Region)
+	if region == "" {
+		return nil, fmt.Errorf("region have to be provided")
+	}
+	creds := sess.Config.Credentials
+	if config.RoleARN != "" {
+		creds = stscreds.NewCredentials(sess, config.RoleARN)
+	}
+	return &awsMSKTokenProvider{
+		region: region,
+		signer: v4.NewSigner(creds),
+		now:    time.Now,
+	}, nil
+}
+
+func (p *awsMSKTokenProvider) Token() (*sarama.AccessToken, error) {
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("https://kafka.%s.amazonaws.com/", p.region), nil)
+	if err != nil {
+		return nil, err
+	}
+	query := req.URL.Query()
+	query.Set("Action", awsMSKAction)
+	req.URL.RawQuery = query.Encode()
+	if _, err = p.signer.Presign(req, nil, awsMSKService, p.region, awsMSKTokenExpiry, p.now()); err != nil {
+		return nil, fmt.Errorf("failed to sign AWS MSK IAM token: %w", err)
+	}
+	// The user agent is not part of the signature.
+	query = req.URL.Query()
+	query.Set("User-Agent", awsMSKUserAgent)
+	req.URL.RawQuery = query.Encode()
+	return &sarama.AccessToken{Token: base64.RawURLEncoding.EncodeToString([]byte(req.URL.String()))}, nil
+}
@@ -0,0 +1,104 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkaexporter
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	v4 "github.com/aws/aws-sdk-go/aws/signer/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOAuthTokenProvider(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "client_credentials", r.PostForm.Get("grant_type"))
+		assert.Equal(t, "kafka", r.PostForm.Get("scope"))
+		clientID, clientSecret, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "jdoe", clientID)
+		assert.Equal(t, "pass", clientSecret)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"token","token_type":"bearer","expires_in":3600}`))
+	}))
+	defer server.Close()
+
+	p, err := newOAuthTokenProvider("jdoe", "pass", OAuthBearerConfig{TokenURL: server.URL, Scopes: []string{"kafka"}})
+	require.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		token, err := p.Token()
+		require.NoError(t, err)
+		assert.Equal(t, "token", token.Token)
+	}
+	// The token is cached until it expires.
+	assert.Equal(t, 1, requests)
+}
+
+func TestOAuthTokenProvider_error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	p, err := newOAuthTokenProvider("jdoe", "pass", OAuthBearerConfig{TokenURL: server.URL})
+	require.NoError(t, err)
+	_, err = p.Token()
+	assert.Error(t, err)
+}
+
+func TestAWSMSKTokenProvider(t *testing.T) {
+	signTime := time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+	p := &awsMSKTokenProvider{
+		region: "us-east-1",
+		signer: v4.NewSigner(credentials.NewStaticCredentials("AKID", "SECRET", "")),
+		now:    func() time.Time { return signTime },
+	}
+	token, err := p.Token()
+	require.NoError(t, err)
+
+	rawURL, err := base64.RawURLEncoding.DecodeString(token.Token)
+	require.NoError(t, err)
+	u, err := url.Parse(string(rawURL))
+	require.NoError(t, err)
+	assert.Equal(t, "https", u.Scheme)
+	assert.Equal(t, "kafka.us-east-1.amazonaws.com", u.Host)
+	query := u.Query()
+	assert.Equal(t, "kafka-cluster:Connect", query.Get("Action"))
+	assert.Equal(t, "AWS4-HMAC-SHA256", query.Get("X-Amz-Algorithm"))
+	assert.Equal(t, "AKID/20210301/us-east-1/kafka-cluster/aws4_request", query.Get("X-Amz-Credential"))
+	assert.Equal(t, "20210301T100000Z", query.Get("X-Amz-Date"))
+	assert.Equal(t, "900", query.Get("X-Amz-Expires"))
+	assert.NotEmpty(t, query.Get("X-Amz-Signature"))
+	assert.Equal(t, awsMSKUserAgent, query.Get("User-Agent"))
+}
+
+func TestAWSMSKTokenProvider_error(t *testing.T) {
+	p := &awsMSKTokenProvider{
+		region: "us-east-1",
+		signer: v4.NewSigner(credentials.NewStaticCredentials("", "", "")),
+		now:    time.Now,
+	}
+	_, err := p.Token()
+	assert.Error(t, err)
+}
@@ -0,0 +1,87 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/exporter/otlpexporter"
+)
+
+// RoutingKey is the key the backend of the data is selected on.
+type RoutingKey string
+
+const (
+	// TraceIDRoutingKey routes the spans and the log records by their trace
+	// ID, all the spans of a trace are sent to the same backend.
+	TraceIDRoutingKey RoutingKey = "traceID"
+	// ServiceRoutingKey routes the data by the service.name attribute of its
+	// resource, all the data of a service is sent to the same backend.
+	ServiceRoutingKey RoutingKey = "service"
+)
+
+// Config has the configuration of the load-balancing exporter.
+type Config struct {
+	configmodels.ExporterSettings `mapstructure:",squash"`
+	// Protocol is the protocol the data is sent to the backends with.
+	Protocol Protocol `mapstructure:"protocol"`
+	// Resolver resolves the endpoints of the backends.
+	Resolver ResolverSettings `mapstructure:"resolver"`
+	// RoutingKey is what the backend of the data is selected on, traceID (the
+	// default) or service.
+	RoutingKey RoutingKey `mapstructure:"routing_key"`
+}
+
+// Protocol has the settings of the exporters created for each backend.
+type Protocol struct {
+	// OTLP are the settings of the OTLP exporters, the endpoint is replaced by
+	// the endpoint of the backend.
+	OTLP otlpexporter.Config `mapstructure:"otlp"`
+}
+
+// ResolverSettings defines how the endpoints of the backends are resolved,
+// exactly one resolver must be configured.
+type ResolverSettings struct {
+	Static *StaticResolver `mapstructure:"static"`
+	DNS    *DNSResolver    `mapstructure:"dns"`
+}
+
+// StaticResolver is a fixed list of backends.
+type StaticResolver struct {
+	// Hostnames are the endpoints of the backends, "host[:port]". The port
+	// defaults to the OTLP gRPC port, 4317.
+	Hostnames []string `mapstructure:"hostnames"`
+}
+
+// DNSResolver resolves the backends as the IP addresses of a hostname, e.g.
+// a headless Kubernetes service.
+type DNSResolver struct {
+	// Hostname is resolved to the IP addresses of the backends.
+	Hostname string `mapstructure:"hostname"`
+	// Port is the port of the backends, defaults to 4317.
+	Port string `mapstructure:"port"`
+	// Interval is how often the hostname is resolved again, defaults to 5s.
+	Interval time.Duration `mapstructure:"interval"`
+	// Timeout is the timeout of each resolution, defaults to 1s.
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+var _ configmodels.ConfigValidator = (*Config)(nil)
+
+// Validate checks the exporter configuration is valid.
+func (cfg *Config) Validate() error {
+	return validateConfig(cfg)
+}
@@ -0,0 +1,63 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configtest"
+)
+
+func TestLoadConfig(t *testing.T) {
+	factories, err := componenttest.NopFactories()
+	assert.NoError(t, err)
+
+	factory := NewFactory()
+	factories.Exporters[typeStr] = factory
+	cfg, err := configtest.LoadConfigFile(t, path.Join(".", "testdata", "config.yaml"), factories)
+
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+
+	e0 := cfg.Exporters["loadbalancing"].(*Config)
+	expected0 := factory.CreateDefaultConfig().(*Config)
+	expected0.Protocol.OTLP.Timeout = time.Second
+	expected0.Protocol.OTLP.TLSSetting.Insecure = true
+	expected0.Resolver = ResolverSettings{
+		Static: &StaticResolver{Hostnames: []string{"backend-1:4317", "backend-2"}},
+	}
+	assert.Equal(t, expected0, e0)
+
+	e1 := cfg.Exporters["loadbalancing/dns"].(*Config)
+	expected1 := factory.CreateDefaultConfig().(*Config)
+	expected1.NameVal = "loadbalancing/dns"
+	expected1.RoutingKey = ServiceRoutingKey
+	expected1.Protocol.OTLP.TLSSetting.Insecure = true
+	expected1.Resolver = ResolverSettings{
+		DNS: &DNSResolver{
+			Hostname: "sampling-collectors.observability.svc.cluster.local",
+			Port:     "55680",
+			Interval: 30 * time.Second,
+			Timeout:  2 * time.Second,
+		},
+	}
+	assert.Equal(t, expected1, e1)
+}
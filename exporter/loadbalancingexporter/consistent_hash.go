@@ -0,0 +1,72 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter
+
+import (
+	"hash/crc32"
+	"sort"
+	"strconv"
+)
+
+// ringPointsPerEndpoint is the number of points of each endpoint on the ring,
+// more points spread the keys more evenly across the endpoints.
+const ringPointsPerEndpoint = 100
+
+type ringItem struct {
+	position uint32
+	endpoint string
+}
+
+// hashRing is a consistent hashing ring: a key is assigned the endpoint of the
+// first point at or after the hash of the key. Adding or removing an endpoint
+// only moves the keys of the points of that endpoint. It is immutable.
+type hashRing struct {
+	items []ringItem
+}
+
+func newHashRing(endpoints []string) *hashRing {
+	items := make([]ringItem, 0, len(endpoints)*ringPointsPerEndpoint)
+	for _, endpoint := range endpoints {
+		for i := 0; i < ringPointsPerEndpoint; i++ {
+			items = append(items, ringItem{
+				position: crc32.ChecksumIEEE([]byte(endpoint + "-" + strconv.Itoa(i))),
+				endpoint: endpoint,
+			})
+		}
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].position == items[j].position {
+			return items[i].endpoint < items[j].endpoint
+		}
+		return items[i].position < items[j].position
+	})
+	return &hashRing{items: items}
+}
+
+// endpointFor returns the endpoint of the key, or an empty string when the ring
+// has no endpoint.
+func (r *hashRing) endpointFor(key []byte) string {
+	if len(r.items) == 0 {
+		return ""
+	}
+	position := crc32.ChecksumIEEE(key)
+	i := sort.Search(len(r.items), func(i int) bool {
+		return r.items[i].position >= position
+	})
+	if i == len(r.items) {
+		i = 0
+	}
+	return r.items[i].endpoint
+}
@@ -0,0 +1,56 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashRingEmpty(t *testing.T) {
+	assert.Equal(t, "", newHashRing(nil).endpointFor([]byte("key")))
+}
+
+func TestHashRingDistribution(t *testing.T) {
+	endpoints := []string{"backend-1:4317", "backend-2:4317", "backend-3:4317"}
+	ring := newHashRing(endpoints)
+
+	counts := map[string]int{}
+	for i := 0; i < 3000; i++ {
+		key := []byte(strconv.Itoa(i))
+		endpoint := ring.endpointFor(key)
+		// The same key always has the same endpoint.
+		assert.Equal(t, endpoint, ring.endpointFor(key))
+		counts[endpoint]++
+	}
+	for _, endpoint := range endpoints {
+		assert.InDelta(t, 1000, counts[endpoint], 300, endpoint)
+	}
+}
+
+func TestHashRingConsistency(t *testing.T) {
+	before := newHashRing([]string{"backend-1:4317", "backend-2:4317", "backend-3:4317"})
+	after := newHashRing([]string{"backend-1:4317", "backend-3:4317"})
+
+	for i := 0; i < 1000; i++ {
+		key := []byte(strconv.Itoa(i))
+		if endpoint := before.endpointFor(key); endpoint != "backend-2:4317" {
+			// Only the keys of the removed endpoint move.
+			assert.Equal(t, endpoint, after.endpointFor(key))
+		}
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+	"go.opentelemetry.io/collector/exporter/otlpexporter"
+)
+
+const (
+	// The value of "type" key in configuration.
+	typeStr = "loadbalancing"
+)
+
+var (
+	errNoResolver        = errors.New("a resolver must be configured, either static or dns")
+	errMultipleResolvers = errors.New("only one resolver can be configured, either static or dns")
+	errNoHostnames       = errors.New("the static resolver must have at least one hostname")
+	errNoDNSHostname     = errors.New("the dns resolver must have a hostname")
+)
+
+// NewFactory creates a factory for the load-balancing exporter.
+func NewFactory() component.ExporterFactory {
+	return exporterhelper.NewFactory(
+		typeStr,
+		createDefaultConfig,
+		exporterhelper.WithTraces(createTraceExporter),
+		exporterhelper.WithLogs(createLogsExporter))
+}
+
+func createDefaultConfig() configmodels.Exporter {
+	otlpDefaultCfg := otlpexporter.NewFactory().CreateDefaultConfig().(*otlpexporter.Config)
+	return &Config{
+		ExporterSettings: configmodels.ExporterSettings{
+			TypeVal: typeStr,
+			NameVal: typeStr,
+		},
+		Protocol: Protocol{
+			OTLP: *otlpDefaultCfg,
+		},
+		RoutingKey: TraceIDRoutingKey,
+	}
+}
+
+func createTraceExporter(
+	_ context.Context,
+	params component.ExporterCreateParams,
+	cfg configmodels.Exporter,
+) (component.TracesExporter, error) {
+	lbCfg := cfg.(*Config)
+	if err := validateConfig(lbCfg); err != nil {
+		return nil, err
+	}
+	lb := newLoadBalancer(params.Logger, lbCfg, otlpExporterFactory(params, lbCfg, configmodels.TracesDataType))
+	return &traceExporterImp{routingKey: lbCfg.RoutingKey, loadBalancer: lb}, nil
+}
+
+func createLogsExporter(
+	_ context.Context,
+	params component.ExporterCreateParams,
+	cfg configmodels.Exporter,
+) (component.LogsExporter, error) {
+	lbCfg := cfg.(*Config)
+	if err := validateConfig(lbCfg); err != nil {
+		return nil, err
+	}
+	lb := newLoadBalancer(params.Logger, lbCfg, otlpExporterFactory(params, lbCfg, configmodels.LogsDataType))
+	return &logExporterImp{routingKey: lbCfg.RoutingKey, loadBalancer: lb}, nil
+}
+
+func validateConfig(cfg *Config) error {
+	switch {
+	case cfg.Resolver.Static != nil && cfg.Resolver.DNS != nil:
+		return errMultipleResolvers
+	case cfg.Resolver.Static != nil:
+		if len(cfg.Resolver.Static.Hostnames) == 0 {
+			return errNoHostnames
+		}
+	case cfg.Resolver.DNS != nil:
+		if cfg.Resolver.DNS.Hostname == "" {
+			return errNoDNSHostname
+		}
+	default:
+		return errNoResolver
+	}
+	if cfg.RoutingKey != TraceIDRoutingKey && cfg.RoutingKey != ServiceRoutingKey {
+		return fmt.Errorf("unsupported routing_key %q, valid keys are {%s, %s}", cfg.RoutingKey, TraceIDRoutingKey, ServiceRoutingKey)
+	}
+	return nil
+}
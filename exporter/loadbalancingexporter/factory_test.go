@@ -0,0 +1,106 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configcheck"
+)
+
+func TestCreateDefaultConfig(t *testing.T) {
+	cfg := createDefaultConfig()
+	assert.NotNil(t, cfg, "failed to create default config")
+	assert.NoError(t, configcheck.ValidateConfig(cfg))
+}
+
+func TestCreateExporters(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Resolver.Static = &StaticResolver{Hostnames: []string{"localhost"}}
+	params := component.ExporterCreateParams{Logger: zap.NewNop()}
+
+	te, err := createTraceExporter(context.Background(), params, cfg)
+	require.NoError(t, err)
+	assert.NotNil(t, te)
+
+	le, err := createLogsExporter(context.Background(), params, cfg)
+	require.NoError(t, err)
+	assert.NotNil(t, le)
+}
+
+func TestCreateExporterInvalidConfig(t *testing.T) {
+	params := component.ExporterCreateParams{Logger: zap.NewNop()}
+	tests := []struct {
+		name   string
+		modify func(cfg *Config)
+		err    string
+	}{
+		{
+			name:   "no resolver",
+			modify: func(cfg *Config) {},
+			err:    errNoResolver.Error(),
+		},
+		{
+			name: "multiple resolvers",
+			modify: func(cfg *Config) {
+				cfg.Resolver.Static = &StaticResolver{Hostnames: []string{"localhost"}}
+				cfg.Resolver.DNS = &DNSResolver{Hostname: "localhost"}
+			},
+			err: errMultipleResolvers.Error(),
+		},
+		{
+			name: "no hostnames",
+			modify: func(cfg *Config) {
+				cfg.Resolver.Static = &StaticResolver{}
+			},
+			err: errNoHostnames.Error(),
+		},
+		{
+			name: "no dns hostname",
+			modify: func(cfg *Config) {
+				cfg.Resolver.DNS = &DNSResolver{Port: "4317"}
+			},
+			err: errNoDNSHostname.Error(),
+		},
+		{
+			name: "unsupported routing key",
+			modify: func(cfg *Config) {
+				cfg.Resolver.Static = &StaticResolver{Hostnames: []string{"localhost"}}
+				cfg.RoutingKey = "spanID"
+			},
+			err: `unsupported routing_key "spanID", valid keys are {traceID, service}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			tt.modify(cfg)
+
+			te, err := createTraceExporter(context.Background(), params, cfg)
+			assert.EqualError(t, err, tt.err)
+			assert.Nil(t, te)
+
+			le, err := createLogsExporter(context.Background(), params, cfg)
+			assert.EqualError(t, err, tt.err)
+			assert.Nil(t, le)
+		})
+	}
+}
@@ -0,0 +1,193 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/exporter/otlpexporter"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+var errNoBackends = errors.New("no backend is available")
+
+// exporterFactory creates the exporter of a backend.
+type exporterFactory func(ctx context.Context, endpoint string) (component.Exporter, error)
+
+// loadBalancer keeps an exporter per endpoint resolved by the resolver, and
+// the hash ring of these endpoints.
+type loadBalancer struct {
+	logger      *zap.Logger
+	res         resolver
+	newExporter exporterFactory
+
+	host component.Host
+
+	// The backends are replaced, never modified, when the endpoints change.
+	mutex    sync.RWMutex
+	backends *backends
+}
+
+// backends holds the ring and the exporters of its endpoints.
+type backends struct {
+	ring      *hashRing
+	exporters map[string]component.Exporter
+	// inflight counts the sends using the exporters, the exporters of the
+	// removed endpoints are only shut down once it is zero.
+	inflight sync.WaitGroup
+}
+
+func newBackends(ring *hashRing, exporters map[string]component.Exporter) *backends {
+	return &backends{ring: ring, exporters: exporters}
+}
+
+// newLoadBalancer creates the load balancer of the validated config.
+func newLoadBalancer(logger *zap.Logger, cfg *Config, newExporter exporterFactory) *loadBalancer {
+	var res resolver
+	if cfg.Resolver.Static != nil {
+		res = newStaticResolver(cfg.Resolver.Static.Hostnames)
+	} else {
+		res = newDNSResolver(logger, cfg.Resolver.DNS)
+	}
+
+	return &loadBalancer{
+		logger:      logger,
+		res:         res,
+		newExporter: newExporter,
+		backends:    newBackends(newHashRing(nil), map[string]component.Exporter{}),
+	}
+}
+
+func (lb *loadBalancer) start(ctx context.Context, host component.Host) error {
+	lb.host = host
+	lb.res.onChange(lb.onBackendChanges)
+	return lb.res.start(ctx)
+}
+
+// onBackendChanges starts the exporters of the new endpoints, and shuts the
+// exporters of the removed endpoints down once they are out of the ring and
+// the sends still using them are done.
+func (lb *loadBalancer) onBackendChanges(endpoints []string) {
+	lb.mutex.RLock()
+	previousBackends := lb.backends
+	lb.mutex.RUnlock()
+	previous := previousBackends.exporters
+
+	exporters := make(map[string]component.Exporter, len(endpoints))
+	for _, endpoint := range endpoints {
+		if exporter, ok := previous[endpoint]; ok {
+			exporters[endpoint] = exporter
+			continue
+		}
+		exporter, err := lb.newExporter(context.Background(), endpoint)
+		if err == nil {
+			err = exporter.Start(context.Background(), lb.host)
+		}
+		if err != nil {
+			lb.logger.Error("failed to create the exporter of the backend", zap.String("endpoint", endpoint), zap.Error(err))
+			continue
+		}
+		exporters[endpoint] = exporter
+	}
+
+	available := make([]string, 0, len(exporters))
+	for endpoint := range exporters {
+		available = append(available, endpoint)
+	}
+	ring := newHashRing(available)
+
+	lb.mutex.Lock()
+	lb.backends = newBackends(ring, exporters)
+	lb.mutex.Unlock()
+
+	previousBackends.inflight.Wait()
+	for endpoint, exporter := range previous {
+		if _, ok := exporters[endpoint]; ok {
+			continue
+		}
+		if err := exporter.Shutdown(context.Background()); err != nil {
+			lb.logger.Warn("failed to shut the exporter of the removed backend down", zap.String("endpoint", endpoint), zap.Error(err))
+		}
+	}
+	lb.logger.Info("backends changed", zap.Strings("endpoints", available))
+}
+
+// current returns the ring and the exporters of its endpoints, consistent
+// with each other, and the function to call once the exporters are not used
+// anymore.
+func (lb *loadBalancer) current() (*hashRing, map[string]component.Exporter, func()) {
+	lb.mutex.RLock()
+	defer lb.mutex.RUnlock()
+	b := lb.backends
+	b.inflight.Add(1)
+	return b.ring, b.exporters, b.inflight.Done
+}
+
+func (lb *loadBalancer) shutdown(ctx context.Context) error {
+	errs := []error{}
+	if err := lb.res.shutdown(ctx); err != nil {
+		errs = append(errs, err)
+	}
+
+	lb.mutex.Lock()
+	previous := lb.backends
+	lb.backends = newBackends(newHashRing(nil), map[string]component.Exporter{})
+	lb.mutex.Unlock()
+
+	previous.inflight.Wait()
+	for _, exporter := range previous.exporters {
+		if err := exporter.Shutdown(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return consumererror.CombineErrors(errs)
+}
+
+// otlpExporterFactory returns the factory of the OTLP exporters of the
+// backends for the data type.
+func otlpExporterFactory(params component.ExporterCreateParams, cfg *Config, dataType configmodels.DataType) exporterFactory {
+	factory := otlpexporter.NewFactory()
+	return func(ctx context.Context, endpoint string) (component.Exporter, error) {
+		oCfg := cfg.Protocol.OTLP
+		oCfg.TypeVal = factory.Type()
+		oCfg.NameVal = cfg.Name()
+		oCfg.Endpoint = endpoint
+		oCfg.TracesEndpoint = ""
+		oCfg.LogsEndpoint = ""
+		switch dataType {
+		case configmodels.TracesDataType:
+			return factory.CreateTracesExporter(ctx, params, &oCfg)
+		default:
+			return factory.CreateLogsExporter(ctx, params, &oCfg)
+		}
+	}
+}
+
+// serviceKey returns the routing key of the service of the resource, empty
+// when it has no service.name attribute.
+func serviceKey(resource pdata.Resource) []byte {
+	if service, ok := resource.Attributes().Get(conventions.AttributeServiceName); ok {
+		return []byte(service.StringVal())
+	}
+	return nil
+}
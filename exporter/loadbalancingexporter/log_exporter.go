@@ -0,0 +1,114 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+type logExporterImp struct {
+	routingKey   RoutingKey
+	loadBalancer *loadBalancer
+}
+
+var _ component.LogsExporter = (*logExporterImp)(nil)
+
+func (e *logExporterImp) Start(ctx context.Context, host component.Host) error {
+	return e.loadBalancer.start(ctx, host)
+}
+
+func (e *logExporterImp) Shutdown(ctx context.Context) error {
+	return e.loadBalancer.shutdown(ctx)
+}
+
+// ConsumeLogs splits the logs by the backend of their routing key and sends
+// each batch to its backend. The log records without a trace ID are routed by
+// their service.
+func (e *logExporterImp) ConsumeLogs(ctx context.Context, ld pdata.Logs) error {
+	ring, exporters, done := e.loadBalancer.current()
+	defer done()
+	if len(exporters) == 0 {
+		return errNoBackends
+	}
+
+	batches := e.splitByEndpoint(ring, ld)
+	var errs []error
+	for endpoint, batch := range batches {
+		if err := exporters[endpoint].(component.LogsExporter).ConsumeLogs(ctx, batch); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return consumererror.CombineErrors(errs)
+}
+
+func (e *logExporterImp) splitByEndpoint(ring *hashRing, ld pdata.Logs) map[string]pdata.Logs {
+	batches := map[string]pdata.Logs{}
+	batchOf := func(endpoint string) pdata.Logs {
+		batch, ok := batches[endpoint]
+		if !ok {
+			batch = pdata.NewLogs()
+			batches[endpoint] = batch
+		}
+		return batch
+	}
+
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		service := serviceKey(rl.Resource())
+		if e.routingKey == ServiceRoutingKey {
+			batchOf(ring.endpointFor(service)).ResourceLogs().Append(rl)
+			continue
+		}
+
+		rlOuts := map[string]pdata.ResourceLogs{}
+		ills := rl.InstrumentationLibraryLogs()
+		for j := 0; j < ills.Len(); j++ {
+			ill := ills.At(j)
+			illOuts := map[string]pdata.InstrumentationLibraryLogs{}
+			logs := ill.Logs()
+			for k := 0; k < logs.Len(); k++ {
+				log := logs.At(k)
+				key := service
+				if !log.TraceID().IsEmpty() {
+					traceID := log.TraceID().Bytes()
+					key = traceID[:]
+				}
+				endpoint := ring.endpointFor(key)
+
+				illOut, ok := illOuts[endpoint]
+				if !ok {
+					rlOut, ok := rlOuts[endpoint]
+					if !ok {
+						rlOut = pdata.NewResourceLogs()
+						rl.Resource().CopyTo(rlOut.Resource())
+						batchOf(endpoint).ResourceLogs().Append(rlOut)
+						rlOuts[endpoint] = rlOut
+					}
+					illOut = pdata.NewInstrumentationLibraryLogs()
+					ill.InstrumentationLibrary().CopyTo(illOut.InstrumentationLibrary())
+					rlOut.InstrumentationLibraryLogs().Append(illOut)
+					illOuts[endpoint] = illOut
+				}
+				illOut.Logs().Append(log)
+			}
+		}
+	}
+	return batches
+}
@@ -0,0 +1,86 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+func newLogsWithTraceIDs(service string, traceIDs ...byte) pdata.Logs {
+	ld := pdata.NewLogs()
+	ld.ResourceLogs().Resize(1)
+	rl := ld.ResourceLogs().At(0)
+	rl.Resource().Attributes().InsertString(conventions.AttributeServiceName, service)
+	rl.InstrumentationLibraryLogs().Resize(1)
+	logs := rl.InstrumentationLibraryLogs().At(0).Logs()
+	logs.Resize(len(traceIDs))
+	for i, id := range traceIDs {
+		if id != 0 {
+			logs.At(i).SetTraceID(pdata.NewTraceID([16]byte{id}))
+		}
+	}
+	return ld
+}
+
+func TestConsumeLogsByTraceID(t *testing.T) {
+	lb, backends := newTestLoadBalancer(t, "backend-1", "backend-2", "backend-3")
+	exp := &logExporterImp{routingKey: TraceIDRoutingKey, loadBalancer: lb}
+	require.NoError(t, exp.Start(context.Background(), componenttest.NewNopHost()))
+
+	require.NoError(t, exp.ConsumeLogs(context.Background(), newLogsWithTraceIDs("svc", 1, 2, 3, 0, 4, 5, 0, 1)))
+
+	ring, _, done := lb.current()
+	done()
+	records := 0
+	for endpoint, backend := range backends {
+		for _, ld := range backend.AllLogs() {
+			rl := ld.ResourceLogs().At(0)
+			logs := rl.InstrumentationLibraryLogs().At(0).Logs()
+			for k := 0; k < logs.Len(); k++ {
+				traceID := logs.At(k).TraceID()
+				expected := ring.endpointFor(serviceKey(rl.Resource()))
+				if !traceID.IsEmpty() {
+					id := traceID.Bytes()
+					expected = ring.endpointFor(id[:])
+				}
+				assert.Equal(t, expected, endpoint)
+				records++
+			}
+		}
+	}
+	assert.Equal(t, 8, records)
+	require.NoError(t, exp.Shutdown(context.Background()))
+}
+
+func TestConsumeLogsByService(t *testing.T) {
+	lb, backends := newTestLoadBalancer(t, "backend-1", "backend-2")
+	exp := &logExporterImp{routingKey: ServiceRoutingKey, loadBalancer: lb}
+	require.NoError(t, exp.Start(context.Background(), componenttest.NewNopHost()))
+
+	require.NoError(t, exp.ConsumeLogs(context.Background(), newLogsWithTraceIDs("svc", 1, 2, 3)))
+
+	ring, _, done := lb.current()
+	done()
+	backend := backends[ring.endpointFor([]byte("svc"))]
+	assert.Equal(t, 3, backend.LogRecordsCount())
+}
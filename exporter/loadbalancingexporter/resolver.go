@@ -0,0 +1,201 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter
+
+import (
+	"context"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	defaultPort        = "4317"
+	defaultDNSInterval = 5 * time.Second
+	defaultDNSTimeout  = time.Second
+)
+
+// resolver resolves the endpoints of the backends and calls the callback
+// registered with onChange whenever they change.
+type resolver interface {
+	start(ctx context.Context) error
+	shutdown(ctx context.Context) error
+	onChange(func(endpoints []string))
+}
+
+// staticResolver resolves a fixed list of endpoints, once when it starts.
+type staticResolver struct {
+	endpoints []string
+	callback  func(endpoints []string)
+}
+
+var _ resolver = (*staticResolver)(nil)
+
+func newStaticResolver(hostnames []string) *staticResolver {
+	endpoints := make([]string, 0, len(hostnames))
+	for _, hostname := range hostnames {
+		endpoints = append(endpoints, withDefaultPort(hostname))
+	}
+	sort.Strings(endpoints)
+	return &staticResolver{endpoints: endpoints}
+}
+
+func (r *staticResolver) start(context.Context) error {
+	if r.callback != nil {
+		r.callback(r.endpoints)
+	}
+	return nil
+}
+
+func (r *staticResolver) shutdown(context.Context) error {
+	return nil
+}
+
+func (r *staticResolver) onChange(callback func(endpoints []string)) {
+	r.callback = callback
+}
+
+// withDefaultPort appends the default port to the hostname if it has none.
+func withDefaultPort(hostname string) string {
+	if _, _, err := net.SplitHostPort(hostname); err == nil {
+		return hostname
+	}
+	host := strings.TrimSuffix(strings.TrimPrefix(hostname, "["), "]")
+	return net.JoinHostPort(host, defaultPort)
+}
+
+// netResolver is the subset of net.Resolver used by the DNS resolver,
+// replaced in tests.
+type netResolver interface {
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+// dnsResolver resolves the endpoints as the IP addresses of a hostname, when
+// it starts and then periodically. When a resolution fails, the endpoints of
+// the previous one are kept.
+type dnsResolver struct {
+	logger   *zap.Logger
+	resolver netResolver
+	hostname string
+	port     string
+	interval time.Duration
+	timeout  time.Duration
+
+	callback  func(endpoints []string)
+	endpoints []string
+	stopCh    chan struct{}
+	stopped   sync.WaitGroup
+}
+
+var _ resolver = (*dnsResolver)(nil)
+
+func newDNSResolver(logger *zap.Logger, cfg *DNSResolver) *dnsResolver {
+	r := &dnsResolver{
+		logger:   logger,
+		resolver: net.DefaultResolver,
+		hostname: cfg.Hostname,
+		port:     cfg.Port,
+		interval: cfg.Interval,
+		timeout:  cfg.Timeout,
+		stopCh:   make(chan struct{}),
+	}
+	if r.port == "" {
+		r.port = defaultPort
+	}
+	if r.interval <= 0 {
+		r.interval = defaultDNSInterval
+	}
+	if r.timeout <= 0 {
+		r.timeout = defaultDNSTimeout
+	}
+	return r
+}
+
+func (r *dnsResolver) start(ctx context.Context) error {
+	if err := r.resolve(ctx); err != nil {
+		// The backends may not be ready yet, they are resolved again on the
+		// next interval.
+		r.logger.Warn("failed to resolve the backends", zap.String("hostname", r.hostname), zap.Error(err))
+	}
+
+	r.stopped.Add(1)
+	go r.periodicallyResolve()
+	return nil
+}
+
+func (r *dnsResolver) periodicallyResolve() {
+	defer r.stopped.Done()
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := r.resolve(context.Background()); err != nil {
+				r.logger.Warn("failed to resolve the backends", zap.String("hostname", r.hostname), zap.Error(err))
+			}
+		case <-r.stopCh:
+			return
+		}
+	}
+}
+
+func (r *dnsResolver) resolve(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	addrs, err := r.resolver.LookupIPAddr(ctx, r.hostname)
+	if err != nil {
+		return err
+	}
+
+	endpoints := make([]string, 0, len(addrs))
+	for _, addr := range addrs {
+		endpoints = append(endpoints, net.JoinHostPort(addr.String(), r.port))
+	}
+	sort.Strings(endpoints)
+	if equalEndpoints(endpoints, r.endpoints) {
+		return nil
+	}
+	r.endpoints = endpoints
+	if r.callback != nil {
+		r.callback(endpoints)
+	}
+	return nil
+}
+
+func (r *dnsResolver) shutdown(context.Context) error {
+	close(r.stopCh)
+	r.stopped.Wait()
+	return nil
+}
+
+func (r *dnsResolver) onChange(callback func(endpoints []string)) {
+	r.callback = callback
+}
+
+func equalEndpoints(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,93 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestStaticResolver(t *testing.T) {
+	res := newStaticResolver([]string{"backend-2", "backend-1:55680", "[::1]"})
+
+	var resolved []string
+	res.onChange(func(endpoints []string) {
+		resolved = endpoints
+	})
+	require.NoError(t, res.start(context.Background()))
+	assert.Equal(t, []string{"[::1]:4317", "backend-1:55680", "backend-2:4317"}, resolved)
+	assert.NoError(t, res.shutdown(context.Background()))
+}
+
+type fakeNetResolver struct {
+	mutex sync.Mutex
+	addrs []net.IPAddr
+	err   error
+}
+
+func (r *fakeNetResolver) LookupIPAddr(context.Context, string) ([]net.IPAddr, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.addrs, r.err
+}
+
+func (r *fakeNetResolver) set(err error, ips ...string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.err = err
+	r.addrs = nil
+	for _, ip := range ips {
+		r.addrs = append(r.addrs, net.IPAddr{IP: net.ParseIP(ip)})
+	}
+}
+
+func TestDNSResolver(t *testing.T) {
+	netRes := &fakeNetResolver{}
+	netRes.set(nil, "10.0.0.2", "10.0.0.1")
+	res := newDNSResolver(zap.NewNop(), &DNSResolver{Hostname: "backends", Interval: time.Millisecond})
+	res.resolver = netRes
+
+	changes := make(chan []string, 10)
+	res.onChange(func(endpoints []string) {
+		changes <- endpoints
+	})
+	require.NoError(t, res.start(context.Background()))
+	assert.Equal(t, []string{"10.0.0.1:4317", "10.0.0.2:4317"}, <-changes)
+
+	// A failed resolution keeps the previous endpoints.
+	netRes.set(errors.New("no such host"))
+	time.Sleep(5 * time.Millisecond)
+	assert.Len(t, changes, 0)
+
+	netRes.set(nil, "10.0.0.1", "10.0.0.3", "::1")
+	assert.Equal(t, []string{"10.0.0.1:4317", "10.0.0.3:4317", "[::1]:4317"}, <-changes)
+
+	require.NoError(t, res.shutdown(context.Background()))
+}
+
+func TestDNSResolverDefaults(t *testing.T) {
+	res := newDNSResolver(zap.NewNop(), &DNSResolver{Hostname: "backends"})
+	assert.Equal(t, defaultPort, res.port)
+	assert.Equal(t, defaultDNSInterval, res.interval)
+	assert.Equal(t, defaultDNSTimeout, res.timeout)
+}
@@ -0,0 +1,108 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+type traceExporterImp struct {
+	routingKey   RoutingKey
+	loadBalancer *loadBalancer
+}
+
+var _ component.TracesExporter = (*traceExporterImp)(nil)
+
+func (e *traceExporterImp) Start(ctx context.Context, host component.Host) error {
+	return e.loadBalancer.start(ctx, host)
+}
+
+func (e *traceExporterImp) Shutdown(ctx context.Context) error {
+	return e.loadBalancer.shutdown(ctx)
+}
+
+// ConsumeTraces splits the traces by the backend of their routing key and
+// sends each batch to its backend.
+func (e *traceExporterImp) ConsumeTraces(ctx context.Context, td pdata.Traces) error {
+	ring, exporters, done := e.loadBalancer.current()
+	defer done()
+	if len(exporters) == 0 {
+		return errNoBackends
+	}
+
+	batches := e.splitByEndpoint(ring, td)
+	var errs []error
+	for endpoint, batch := range batches {
+		if err := exporters[endpoint].(component.TracesExporter).ConsumeTraces(ctx, batch); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return consumererror.CombineErrors(errs)
+}
+
+func (e *traceExporterImp) splitByEndpoint(ring *hashRing, td pdata.Traces) map[string]pdata.Traces {
+	batches := map[string]pdata.Traces{}
+	batchOf := func(endpoint string) pdata.Traces {
+		batch, ok := batches[endpoint]
+		if !ok {
+			batch = pdata.NewTraces()
+			batches[endpoint] = batch
+		}
+		return batch
+	}
+
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		if e.routingKey == ServiceRoutingKey {
+			batchOf(ring.endpointFor(serviceKey(rs.Resource()))).ResourceSpans().Append(rs)
+			continue
+		}
+
+		rsOuts := map[string]pdata.ResourceSpans{}
+		ilss := rs.InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			ils := ilss.At(j)
+			ilsOuts := map[string]pdata.InstrumentationLibrarySpans{}
+			spans := ils.Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				traceID := span.TraceID().Bytes()
+				endpoint := ring.endpointFor(traceID[:])
+
+				ilsOut, ok := ilsOuts[endpoint]
+				if !ok {
+					rsOut, ok := rsOuts[endpoint]
+					if !ok {
+						rsOut = pdata.NewResourceSpans()
+						rs.Resource().CopyTo(rsOut.Resource())
+						batchOf(endpoint).ResourceSpans().Append(rsOut)
+						rsOuts[endpoint] = rsOut
+					}
+					ilsOut = pdata.NewInstrumentationLibrarySpans()
+					ils.InstrumentationLibrary().CopyTo(ilsOut.InstrumentationLibrary())
+					rsOut.InstrumentationLibrarySpans().Append(ilsOut)
+					ilsOuts[endpoint] = ilsOut
+				}
+				ilsOut.Spans().Append(span)
+			}
+		}
+	}
+	return batches
+}
@@ -0,0 +1,243 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+// backendExporter is the exporter of a backend in the tests.
+type backendExporter struct {
+	consumertest.TracesSink
+	consumertest.LogsSink
+	err      error
+	started  bool
+	shutdown bool
+	// entered and release, when set, block ConsumeTraces until release is
+	// closed, entered receiving a value once it is called.
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (e *backendExporter) Start(context.Context, component.Host) error {
+	e.started = true
+	return nil
+}
+
+func (e *backendExporter) Shutdown(context.Context) error {
+	e.shutdown = true
+	return nil
+}
+
+func (e *backendExporter) ConsumeTraces(ctx context.Context, td pdata.Traces) error {
+	if e.release != nil {
+		e.entered <- struct{}{}
+		<-e.release
+	}
+	if e.err != nil {
+		return e.err
+	}
+	return e.TracesSink.ConsumeTraces(ctx, td)
+}
+
+func (e *backendExporter) ConsumeLogs(ctx context.Context, ld pdata.Logs) error {
+	if e.err != nil {
+		return e.err
+	}
+	return e.LogsSink.ConsumeLogs(ctx, ld)
+}
+
+func newTestLoadBalancer(t *testing.T, hostnames ...string) (*loadBalancer, map[string]*backendExporter) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Resolver.Static = &StaticResolver{Hostnames: hostnames}
+	require.NoError(t, validateConfig(cfg))
+
+	backends := map[string]*backendExporter{}
+	lb := newLoadBalancer(zap.NewNop(), cfg, func(_ context.Context, endpoint string) (component.Exporter, error) {
+		backend := &backendExporter{}
+		backends[endpoint] = backend
+		return backend, nil
+	})
+	return lb, backends
+}
+
+func newTracesWithTraceIDs(service string, traceIDs ...byte) pdata.Traces {
+	td := pdata.NewTraces()
+	td.ResourceSpans().Resize(1)
+	rs := td.ResourceSpans().At(0)
+	rs.Resource().Attributes().InsertString(conventions.AttributeServiceName, service)
+	rs.InstrumentationLibrarySpans().Resize(1)
+	ils := rs.InstrumentationLibrarySpans().At(0)
+	ils.InstrumentationLibrary().SetName("library")
+	ils.Spans().Resize(len(traceIDs))
+	for i, id := range traceIDs {
+		ils.Spans().At(i).SetTraceID(pdata.NewTraceID([16]byte{id}))
+	}
+	return td
+}
+
+func TestConsumeTracesByTraceID(t *testing.T) {
+	lb, backends := newTestLoadBalancer(t, "backend-1", "backend-2", "backend-3")
+	exp := &traceExporterImp{routingKey: TraceIDRoutingKey, loadBalancer: lb}
+	require.NoError(t, exp.Start(context.Background(), componenttest.NewNopHost()))
+	require.Len(t, backends, 3)
+
+	for i := 0; i < 10; i++ {
+		require.NoError(t, exp.ConsumeTraces(context.Background(), newTracesWithTraceIDs("svc", 1, 2, 3, 4, 5, 6, 7, 8, 1, 2)))
+	}
+
+	// All the spans of a trace are sent to the same backend, with their
+	// resource and instrumentation library.
+	backendOf := map[pdata.TraceID]string{}
+	spans := 0
+	for endpoint, backend := range backends {
+		assert.True(t, backend.started)
+		for _, td := range backend.AllTraces() {
+			rs := td.ResourceSpans().At(0)
+			service, _ := rs.Resource().Attributes().Get(conventions.AttributeServiceName)
+			assert.Equal(t, "svc", service.StringVal())
+			ils := rs.InstrumentationLibrarySpans().At(0)
+			assert.Equal(t, "library", ils.InstrumentationLibrary().Name())
+			for k := 0; k < ils.Spans().Len(); k++ {
+				traceID := ils.Spans().At(k).TraceID()
+				if previous, ok := backendOf[traceID]; ok {
+					assert.Equal(t, previous, endpoint)
+				}
+				backendOf[traceID] = endpoint
+				spans++
+			}
+		}
+	}
+	assert.Equal(t, 100, spans)
+	assert.Len(t, backendOf, 8)
+
+	require.NoError(t, exp.Shutdown(context.Background()))
+	for _, backend := range backends {
+		assert.True(t, backend.shutdown)
+	}
+}
+
+func TestConsumeTracesByService(t *testing.T) {
+	lb, backends := newTestLoadBalancer(t, "backend-1", "backend-2", "backend-3")
+	exp := &traceExporterImp{routingKey: ServiceRoutingKey, loadBalancer: lb}
+	require.NoError(t, exp.Start(context.Background(), componenttest.NewNopHost()))
+
+	td := newTracesWithTraceIDs("svc-a", 1, 2, 3)
+	newTracesWithTraceIDs("svc-b", 4, 5).ResourceSpans().MoveAndAppendTo(td.ResourceSpans())
+	newTracesWithTraceIDs("svc-a", 6).ResourceSpans().MoveAndAppendTo(td.ResourceSpans())
+	require.NoError(t, exp.ConsumeTraces(context.Background(), td))
+
+	ring, _, done := lb.current()
+	done()
+	for endpoint, backend := range backends {
+		for _, td := range backend.AllTraces() {
+			rss := td.ResourceSpans()
+			for i := 0; i < rss.Len(); i++ {
+				// The resources are sent as they are to the backend of their
+				// service.
+				assert.Equal(t, ring.endpointFor(serviceKey(rss.At(i).Resource())), endpoint)
+			}
+		}
+	}
+	spans := 0
+	for _, backend := range backends {
+		spans += backend.SpansCount()
+	}
+	assert.Equal(t, 6, spans)
+}
+
+func TestConsumeTracesErrors(t *testing.T) {
+	lb, backends := newTestLoadBalancer(t, "backend-1")
+	exp := &traceExporterImp{routingKey: TraceIDRoutingKey, loadBalancer: lb}
+	assert.Equal(t, errNoBackends, exp.ConsumeTraces(context.Background(), newTracesWithTraceIDs("svc", 1)))
+
+	require.NoError(t, exp.Start(context.Background(), componenttest.NewNopHost()))
+	backends["backend-1:4317"].err = errors.New("unavailable")
+	assert.EqualError(t, exp.ConsumeTraces(context.Background(), newTracesWithTraceIDs("svc", 1)), "unavailable")
+}
+
+func TestBackendChanges(t *testing.T) {
+	lb, backends := newTestLoadBalancer(t, "backend-1", "backend-2")
+	require.NoError(t, lb.start(context.Background(), componenttest.NewNopHost()))
+	backend1 := backends["backend-1:4317"]
+	backend2 := backends["backend-2:4317"]
+
+	lb.onBackendChanges([]string{"backend-1:4317", "backend-3:4317"})
+
+	// The exporter of a remaining backend is kept, the removed one is shut
+	// down and the new one is started.
+	ring, exporters, done := lb.current()
+	done()
+	assert.Len(t, exporters, 2)
+	assert.Equal(t, backend1, exporters["backend-1:4317"])
+	assert.False(t, backend1.shutdown)
+	assert.True(t, backend2.shutdown)
+	assert.True(t, backends["backend-3:4317"].started)
+	assert.NotEqual(t, "backend-2:4317", ring.endpointFor([]byte("key")))
+
+	require.NoError(t, lb.shutdown(context.Background()))
+	assert.True(t, backend1.shutdown)
+	ring, exporters, done = lb.current()
+	done()
+	assert.Len(t, exporters, 0)
+	assert.Equal(t, "", ring.endpointFor([]byte("key")))
+}
+
+func TestBackendChangesWaitForInflightSends(t *testing.T) {
+	lb, backends := newTestLoadBalancer(t, "backend-1")
+	exp := &traceExporterImp{routingKey: TraceIDRoutingKey, loadBalancer: lb}
+	require.NoError(t, exp.Start(context.Background(), componenttest.NewNopHost()))
+	backend1 := backends["backend-1:4317"]
+	backend1.entered = make(chan struct{}, 1)
+	backend1.release = make(chan struct{})
+
+	consumed := make(chan error, 1)
+	go func() { consumed <- exp.ConsumeTraces(context.Background(), newTracesWithTraceIDs("svc", 1)) }()
+	<-backend1.entered
+
+	changed := make(chan struct{})
+	go func() {
+		lb.onBackendChanges([]string{"backend-2:4317"})
+		close(changed)
+	}()
+
+	// The removed backend is not shut down while a send is using it.
+	select {
+	case <-changed:
+		t.Fatal("the backends changed while a send was in flight")
+	case <-time.After(100 * time.Millisecond):
+	}
+	assert.False(t, backend1.shutdown)
+
+	close(backend1.release)
+	require.NoError(t, <-consumed)
+	<-changed
+	assert.True(t, backend1.shutdown)
+	assert.Equal(t, 1, backend1.SpansCount())
+	require.NoError(t, exp.Shutdown(context.Background()))
+}
@@ -25,9 +25,38 @@ type Config struct {
 	// LogLevel defines log level of the logging exporter; options are debug, info, warn, error.
 	LogLevel string `mapstructure:"loglevel"`
 
+	// Traces, Metrics and Logs define the settings specific to each signal.
+	Traces  SignalSettings `mapstructure:"traces"`
+	Metrics SignalSettings `mapstructure:"metrics"`
+	Logs    SignalSettings `mapstructure:"logs"`
+
 	// SamplingInitial defines how many samples are initially logged during each second.
+	// The sampling applies to the log messages and to the batches written in the json
+	// and otlp_json formats.
 	SamplingInitial int `mapstructure:"sampling_initial"`
 
 	// SamplingThereafter defines the sampling rate after the initial samples are logged.
 	SamplingThereafter int `mapstructure:"sampling_thereafter"`
+
+	// Format defines the output format of the data; options are text, json, otlp_json.
+	// The text format logs the data when LogLevel is debug. The json and otlp_json
+	// formats always write the data to the standard output, as one JSON object per
+	// span, data point or log record, or as one OTLP JSON export request per batch.
+	Format string `mapstructure:"format"`
+}
+
+// SignalSettings defines the settings of the logging exporter specific to a signal.
+type SignalSettings struct {
+	// LogLevel overrides the log level of the logging exporter for the signal, e.g. to
+	// log the spans in detail with debug while only counting the metrics with info, or
+	// to silence a signal with error. Defaults to the LogLevel of the exporter.
+	LogLevel string `mapstructure:"loglevel"`
+}
+
+// logLevel returns the log level of the signal of the settings.
+func (cfg *Config) logLevel(signal SignalSettings) string {
+	if signal.LogLevel != "" {
+		return signal.LogLevel
+	}
+	return cfg.LogLevel
 }
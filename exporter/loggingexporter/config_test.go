@@ -50,5 +50,39 @@ func TestLoadConfig(t *testing.T) {
 			LogLevel:           "debug",
 			SamplingInitial:    10,
 			SamplingThereafter: 50,
+			Format:             formatText,
 		})
+
+	e2 := cfg.Exporters["logging/json"]
+	assert.Equal(t, e2,
+		&Config{
+			ExporterSettings: configmodels.ExporterSettings{
+				NameVal: "logging/json",
+				TypeVal: "logging",
+			},
+			LogLevel:           "info",
+			SamplingInitial:    defaultSamplingInitial,
+			SamplingThereafter: defaultSamplingThereafter,
+			Format:             formatJSON,
+		})
+
+	e3 := cfg.Exporters["logging/signals"]
+	assert.Equal(t, e3,
+		&Config{
+			ExporterSettings: configmodels.ExporterSettings{
+				NameVal: "logging/signals",
+				TypeVal: "logging",
+			},
+			LogLevel:           "warn",
+			Traces:             SignalSettings{LogLevel: "debug"},
+			Logs:               SignalSettings{LogLevel: "info"},
+			SamplingInitial:    defaultSamplingInitial,
+			SamplingThereafter: defaultSamplingThereafter,
+			Format:             formatText,
+		})
+
+	signals := e3.(*Config)
+	assert.Equal(t, "debug", signals.logLevel(signals.Traces))
+	assert.Equal(t, "warn", signals.logLevel(signals.Metrics))
+	assert.Equal(t, "info", signals.logLevel(signals.Logs))
 }
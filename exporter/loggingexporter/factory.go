@@ -16,6 +16,7 @@ package loggingexporter
 
 import (
 	"context"
+	"fmt"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -30,6 +31,10 @@ const (
 	typeStr                   = "logging"
 	defaultSamplingInitial    = 2
 	defaultSamplingThereafter = 500
+
+	formatText     = "text"
+	formatJSON     = "json"
+	formatOTLPJSON = "otlp_json"
 )
 
 // NewFactory creates a factory for Logging exporter
@@ -51,45 +56,55 @@ func createDefaultConfig() configmodels.Exporter {
 		LogLevel:           "info",
 		SamplingInitial:    defaultSamplingInitial,
 		SamplingThereafter: defaultSamplingThereafter,
+		Format:             formatText,
 	}
 }
 
 func createTraceExporter(_ context.Context, _ component.ExporterCreateParams, config configmodels.Exporter) (component.TracesExporter, error) {
 	cfg := config.(*Config)
+	level := cfg.logLevel(cfg.Traces)
 
-	exporterLogger, err := createLogger(cfg)
+	exporterLogger, err := createLogger(cfg, level)
 	if err != nil {
 		return nil, err
 	}
 
-	return newTraceExporter(config, cfg.LogLevel, exporterLogger)
+	return newTraceExporter(config, level, cfg.Format, newBatchSampler(cfg.SamplingInitial, cfg.SamplingThereafter), exporterLogger)
 }
 
 func createMetricsExporter(_ context.Context, _ component.ExporterCreateParams, config configmodels.Exporter) (component.MetricsExporter, error) {
 	cfg := config.(*Config)
+	level := cfg.logLevel(cfg.Metrics)
 
-	exporterLogger, err := createLogger(cfg)
+	exporterLogger, err := createLogger(cfg, level)
 	if err != nil {
 		return nil, err
 	}
 
-	return newMetricsExporter(config, cfg.LogLevel, exporterLogger)
+	return newMetricsExporter(config, level, cfg.Format, newBatchSampler(cfg.SamplingInitial, cfg.SamplingThereafter), exporterLogger)
 }
 
 func createLogsExporter(_ context.Context, _ component.ExporterCreateParams, config configmodels.Exporter) (component.LogsExporter, error) {
 	cfg := config.(*Config)
+	level := cfg.logLevel(cfg.Logs)
 
-	exporterLogger, err := createLogger(cfg)
+	exporterLogger, err := createLogger(cfg, level)
 	if err != nil {
 		return nil, err
 	}
 
-	return newLogsExporter(config, cfg.LogLevel, exporterLogger)
+	return newLogsExporter(config, level, cfg.Format, newBatchSampler(cfg.SamplingInitial, cfg.SamplingThereafter), exporterLogger)
 }
 
-func createLogger(cfg *Config) (*zap.Logger, error) {
+func createLogger(cfg *Config, logLevel string) (*zap.Logger, error) {
+	switch cfg.Format {
+	case formatText, formatJSON, formatOTLPJSON:
+	default:
+		return nil, fmt.Errorf("unsupported format %q, valid formats are {%s, %s, %s}", cfg.Format, formatText, formatJSON, formatOTLPJSON)
+	}
+
 	var level zapcore.Level
-	err := (&level).UnmarshalText([]byte(cfg.LogLevel))
+	err := (&level).UnmarshalText([]byte(logLevel))
 	if err != nil {
 		return nil, err
 	}
@@ -58,3 +58,27 @@ func TestCreateLogsExporter(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, te)
 }
+
+func TestCreateExporterInvalidFormat(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.Format = "yaml"
+
+	te, err := factory.CreateTracesExporter(context.Background(), component.ExporterCreateParams{Logger: zap.NewNop()}, cfg)
+	assert.EqualError(t, err, `unsupported format "yaml", valid formats are {text, json, otlp_json}`)
+	assert.Nil(t, te)
+}
+
+func TestCreateExporterInvalidSignalLogLevel(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.Metrics.LogLevel = "verbose"
+
+	te, err := factory.CreateTracesExporter(context.Background(), component.ExporterCreateParams{Logger: zap.NewNop()}, cfg)
+	assert.NoError(t, err)
+	assert.NotNil(t, te)
+
+	me, err := factory.CreateMetricsExporter(context.Background(), component.ExporterCreateParams{Logger: zap.NewNop()}, cfg)
+	assert.Error(t, err)
+	assert.Nil(t, me)
+}
@@ -0,0 +1,374 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loggingexporter
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/gogo/protobuf/proto"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal"
+	otlplogs "go.opentelemetry.io/collector/internal/data/protogen/collector/logs/v1"
+	otlpmetrics "go.opentelemetry.io/collector/internal/data/protogen/collector/metrics/v1"
+	otlptrace "go.opentelemetry.io/collector/internal/data/protogen/collector/trace/v1"
+)
+
+// otlpMarshaler marshals the OTLP export requests for the otlp_json format.
+var otlpMarshaler = &jsonpb.Marshaler{}
+
+// jsonObject is a JSON object of the json format, its keys are sorted.
+type jsonObject map[string]interface{}
+
+// jsonLinesBuffer accumulates the JSON objects of the json format, one per line.
+type jsonLinesBuffer struct {
+	buf bytes.Buffer
+	err error
+}
+
+func (b *jsonLinesBuffer) writeObject(obj jsonObject) {
+	if b.err != nil {
+		return
+	}
+	var line []byte
+	if line, b.err = json.Marshal(obj); b.err != nil {
+		return
+	}
+	b.buf.Write(line)
+	b.buf.WriteByte('\n')
+}
+
+func tracesToJSONLines(td pdata.Traces) ([]byte, error) {
+	b := jsonLinesBuffer{}
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		resource := attributeMapToJSON(rs.Resource().Attributes())
+		ilss := rs.InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			ils := ilss.At(j)
+			library := instrumentationLibraryToJSON(ils.InstrumentationLibrary())
+			spans := ils.Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				b.writeObject(jsonObject{
+					"resource":                resource,
+					"instrumentation_library": library,
+					"trace_id":                span.TraceID().HexString(),
+					"span_id":                 span.SpanID().HexString(),
+					"parent_span_id":          span.ParentSpanID().HexString(),
+					"trace_state":             string(span.TraceState()),
+					"name":                    span.Name(),
+					"kind":                    span.Kind().String(),
+					"start_time_unix_nano":    uint64(span.StartTime()),
+					"end_time_unix_nano":      uint64(span.EndTime()),
+					"status": jsonObject{
+						"code":    span.Status().Code().String(),
+						"message": span.Status().Message(),
+					},
+					"attributes": attributeMapToJSON(span.Attributes()),
+					"events":     spanEventsToJSON(span.Events()),
+					"links":      spanLinksToJSON(span.Links()),
+				})
+			}
+		}
+	}
+	return b.buf.Bytes(), b.err
+}
+
+func spanEventsToJSON(se pdata.SpanEventSlice) []jsonObject {
+	events := make([]jsonObject, 0, se.Len())
+	for i := 0; i < se.Len(); i++ {
+		e := se.At(i)
+		events = append(events, jsonObject{
+			"name":           e.Name(),
+			"time_unix_nano": uint64(e.Timestamp()),
+			"attributes":     attributeMapToJSON(e.Attributes()),
+		})
+	}
+	return events
+}
+
+func spanLinksToJSON(sl pdata.SpanLinkSlice) []jsonObject {
+	links := make([]jsonObject, 0, sl.Len())
+	for i := 0; i < sl.Len(); i++ {
+		l := sl.At(i)
+		links = append(links, jsonObject{
+			"trace_id":    l.TraceID().HexString(),
+			"span_id":     l.SpanID().HexString(),
+			"trace_state": string(l.TraceState()),
+			"attributes":  attributeMapToJSON(l.Attributes()),
+		})
+	}
+	return links
+}
+
+func metricsToJSONLines(md pdata.Metrics) ([]byte, error) {
+	b := jsonLinesBuffer{}
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		resource := attributeMapToJSON(rm.Resource().Attributes())
+		ilms := rm.InstrumentationLibraryMetrics()
+		for j := 0; j < ilms.Len(); j++ {
+			ilm := ilms.At(j)
+			library := instrumentationLibraryToJSON(ilm.InstrumentationLibrary())
+			metrics := ilm.Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				metric := metrics.At(k)
+				descriptor := metricDescriptorToJSON(metric)
+				forEachDataPointJSON(metric, func(dp jsonObject) {
+					dp["resource"] = resource
+					dp["instrumentation_library"] = library
+					dp["metric"] = descriptor
+					b.writeObject(dp)
+				})
+			}
+		}
+	}
+	return b.buf.Bytes(), b.err
+}
+
+func metricDescriptorToJSON(m pdata.Metric) jsonObject {
+	descriptor := jsonObject{
+		"name":        m.Name(),
+		"description": m.Description(),
+		"unit":        m.Unit(),
+		"data_type":   m.DataType().String(),
+	}
+	switch m.DataType() {
+	case pdata.MetricDataTypeIntSum:
+		descriptor["is_monotonic"] = m.IntSum().IsMonotonic()
+		descriptor["aggregation_temporality"] = m.IntSum().AggregationTemporality().String()
+	case pdata.MetricDataTypeDoubleSum:
+		descriptor["is_monotonic"] = m.DoubleSum().IsMonotonic()
+		descriptor["aggregation_temporality"] = m.DoubleSum().AggregationTemporality().String()
+	case pdata.MetricDataTypeIntHistogram:
+		descriptor["aggregation_temporality"] = m.IntHistogram().AggregationTemporality().String()
+	case pdata.MetricDataTypeDoubleHistogram:
+		descriptor["aggregation_temporality"] = m.DoubleHistogram().AggregationTemporality().String()
+	}
+	return descriptor
+}
+
+// forEachDataPointJSON calls fn with the JSON object of each data point of the metric.
+func forEachDataPointJSON(m pdata.Metric, fn func(dp jsonObject)) {
+	switch m.DataType() {
+	case pdata.MetricDataTypeIntGauge:
+		intDataPointsToJSON(m.IntGauge().DataPoints(), fn)
+	case pdata.MetricDataTypeDoubleGauge:
+		doubleDataPointsToJSON(m.DoubleGauge().DataPoints(), fn)
+	case pdata.MetricDataTypeIntSum:
+		intDataPointsToJSON(m.IntSum().DataPoints(), fn)
+	case pdata.MetricDataTypeDoubleSum:
+		doubleDataPointsToJSON(m.DoubleSum().DataPoints(), fn)
+	case pdata.MetricDataTypeIntHistogram:
+		ps := m.IntHistogram().DataPoints()
+		for i := 0; i < ps.Len(); i++ {
+			p := ps.At(i)
+			fn(jsonObject{
+				"labels":               stringMapToJSON(p.LabelsMap()),
+				"start_time_unix_nano": uint64(p.StartTime()),
+				"time_unix_nano":       uint64(p.Timestamp()),
+				"count":                p.Count(),
+				"sum":                  p.Sum(),
+				"bucket_counts":        nonNilUint64s(p.BucketCounts()),
+				"explicit_bounds":      nonNilFloat64s(p.ExplicitBounds()),
+			})
+		}
+	case pdata.MetricDataTypeDoubleHistogram:
+		ps := m.DoubleHistogram().DataPoints()
+		for i := 0; i < ps.Len(); i++ {
+			p := ps.At(i)
+			fn(jsonObject{
+				"labels":               stringMapToJSON(p.LabelsMap()),
+				"start_time_unix_nano": uint64(p.StartTime()),
+				"time_unix_nano":       uint64(p.Timestamp()),
+				"count":                p.Count(),
+				"sum":                  p.Sum(),
+				"bucket_counts":        nonNilUint64s(p.BucketCounts()),
+				"explicit_bounds":      nonNilFloat64s(p.ExplicitBounds()),
+			})
+		}
+	case pdata.MetricDataTypeDoubleSummary:
+		ps := m.DoubleSummary().DataPoints()
+		for i := 0; i < ps.Len(); i++ {
+			p := ps.At(i)
+			quantiles := make([]jsonObject, 0, p.QuantileValues().Len())
+			for j := 0; j < p.QuantileValues().Len(); j++ {
+				q := p.QuantileValues().At(j)
+				quantiles = append(quantiles, jsonObject{
+					"quantile": q.Quantile(),
+					"value":    q.Value(),
+				})
+			}
+			fn(jsonObject{
+				"labels":               stringMapToJSON(p.LabelsMap()),
+				"start_time_unix_nano": uint64(p.StartTime()),
+				"time_unix_nano":       uint64(p.Timestamp()),
+				"count":                p.Count(),
+				"sum":                  p.Sum(),
+				"quantile_values":      quantiles,
+			})
+		}
+	}
+}
+
+func intDataPointsToJSON(ps pdata.IntDataPointSlice, fn func(dp jsonObject)) {
+	for i := 0; i < ps.Len(); i++ {
+		p := ps.At(i)
+		fn(jsonObject{
+			"labels":               stringMapToJSON(p.LabelsMap()),
+			"start_time_unix_nano": uint64(p.StartTime()),
+			"time_unix_nano":       uint64(p.Timestamp()),
+			"value":                p.Value(),
+		})
+	}
+}
+
+func doubleDataPointsToJSON(ps pdata.DoubleDataPointSlice, fn func(dp jsonObject)) {
+	for i := 0; i < ps.Len(); i++ {
+		p := ps.At(i)
+		fn(jsonObject{
+			"labels":               stringMapToJSON(p.LabelsMap()),
+			"start_time_unix_nano": uint64(p.StartTime()),
+			"time_unix_nano":       uint64(p.Timestamp()),
+			"value":                p.Value(),
+		})
+	}
+}
+
+func logsToJSONLines(ld pdata.Logs) ([]byte, error) {
+	b := jsonLinesBuffer{}
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		resource := attributeMapToJSON(rl.Resource().Attributes())
+		ills := rl.InstrumentationLibraryLogs()
+		for j := 0; j < ills.Len(); j++ {
+			ill := ills.At(j)
+			library := instrumentationLibraryToJSON(ill.InstrumentationLibrary())
+			logs := ill.Logs()
+			for k := 0; k < logs.Len(); k++ {
+				lr := logs.At(k)
+				b.writeObject(jsonObject{
+					"resource":                resource,
+					"instrumentation_library": library,
+					"time_unix_nano":          uint64(lr.Timestamp()),
+					"severity_number":         int32(lr.SeverityNumber()),
+					"severity_text":           lr.SeverityText(),
+					"name":                    lr.Name(),
+					"body":                    attributeValueToJSON(lr.Body()),
+					"attributes":              attributeMapToJSON(lr.Attributes()),
+					"trace_id":                lr.TraceID().HexString(),
+					"span_id":                 lr.SpanID().HexString(),
+					"flags":                   lr.Flags(),
+				})
+			}
+		}
+	}
+	return b.buf.Bytes(), b.err
+}
+
+func instrumentationLibraryToJSON(il pdata.InstrumentationLibrary) jsonObject {
+	return jsonObject{
+		"name":    il.Name(),
+		"version": il.Version(),
+	}
+}
+
+func attributeMapToJSON(am pdata.AttributeMap) jsonObject {
+	obj := make(jsonObject, am.Len())
+	am.ForEach(func(k string, v pdata.AttributeValue) {
+		obj[k] = attributeValueToJSON(v)
+	})
+	return obj
+}
+
+func attributeValueToJSON(av pdata.AttributeValue) interface{} {
+	switch av.Type() {
+	case pdata.AttributeValueSTRING:
+		return av.StringVal()
+	case pdata.AttributeValueBOOL:
+		return av.BoolVal()
+	case pdata.AttributeValueDOUBLE:
+		return av.DoubleVal()
+	case pdata.AttributeValueINT:
+		return av.IntVal()
+	case pdata.AttributeValueARRAY:
+		arr := av.ArrayVal()
+		values := make([]interface{}, 0, arr.Len())
+		for i := 0; i < arr.Len(); i++ {
+			values = append(values, attributeValueToJSON(arr.At(i)))
+		}
+		return values
+	case pdata.AttributeValueMAP:
+		return attributeMapToJSON(av.MapVal())
+	default:
+		return nil
+	}
+}
+
+func stringMapToJSON(sm pdata.StringMap) map[string]string {
+	obj := make(map[string]string, sm.Len())
+	sm.ForEach(func(k string, v string) {
+		obj[k] = v
+	})
+	return obj
+}
+
+// nonNilUint64s and nonNilFloat64s make the empty slices marshal to [] rather than null.
+func nonNilUint64s(s []uint64) []uint64 {
+	if s == nil {
+		return []uint64{}
+	}
+	return s
+}
+
+func nonNilFloat64s(s []float64) []float64 {
+	if s == nil {
+		return []float64{}
+	}
+	return s
+}
+
+func tracesToOTLPJSON(td pdata.Traces) ([]byte, error) {
+	return otlpToJSONLine(&otlptrace.ExportTraceServiceRequest{
+		ResourceSpans: pdata.TracesToOtlp(td),
+	})
+}
+
+func metricsToOTLPJSON(md pdata.Metrics) ([]byte, error) {
+	return otlpToJSONLine(&otlpmetrics.ExportMetricsServiceRequest{
+		ResourceMetrics: pdata.MetricsToOtlp(md),
+	})
+}
+
+func logsToOTLPJSON(ld pdata.Logs) ([]byte, error) {
+	return otlpToJSONLine(&otlplogs.ExportLogsServiceRequest{
+		ResourceLogs: internal.LogsToOtlp(ld.InternalRep()),
+	})
+}
+
+func otlpToJSONLine(request proto.Message) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := otlpMarshaler.Marshal(&buf, request); err != nil {
+		return nil, err
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,224 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loggingexporter
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal"
+	otlplogs "go.opentelemetry.io/collector/internal/data/protogen/collector/logs/v1"
+	otlpmetrics "go.opentelemetry.io/collector/internal/data/protogen/collector/metrics/v1"
+	otlptrace "go.opentelemetry.io/collector/internal/data/protogen/collector/trace/v1"
+	"go.opentelemetry.io/collector/internal/testdata"
+)
+
+// withStdout replaces the standard output of the json formats for the duration of the test.
+func withStdout(t *testing.T) *bytes.Buffer {
+	buf := &bytes.Buffer{}
+	prev := stdout
+	stdout = buf
+	t.Cleanup(func() {
+		stdout = prev
+	})
+	return buf
+}
+
+func readLines(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	var lines []map[string]interface{}
+	scanner := bufio.NewScanner(buf)
+	for scanner.Scan() {
+		var line map[string]interface{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &line), scanner.Text())
+		lines = append(lines, line)
+	}
+	require.NoError(t, scanner.Err())
+	return lines
+}
+
+func TestLoggingTraceExporterJSON(t *testing.T) {
+	buf := withStdout(t)
+	lte, err := newTraceExporter(&configmodels.ExporterSettings{}, "info", formatJSON, nil, zap.NewNop())
+	require.NoError(t, err)
+
+	td := pdata.NewTraces()
+	td.ResourceSpans().Resize(1)
+	rs := td.ResourceSpans().At(0)
+	rs.Resource().Attributes().InsertString("service.name", "svc")
+	rs.InstrumentationLibrarySpans().Resize(1)
+	ils := rs.InstrumentationLibrarySpans().At(0)
+	ils.InstrumentationLibrary().SetName("lib")
+	ils.Spans().Resize(2)
+	span := ils.Spans().At(0)
+	span.SetTraceID(pdata.NewTraceID([16]byte{1}))
+	span.SetSpanID(pdata.NewSpanID([8]byte{2}))
+	span.SetName("operation")
+	span.SetKind(pdata.SpanKindSERVER)
+	span.SetStartTime(1000)
+	span.SetEndTime(2000)
+	span.Status().SetCode(pdata.StatusCodeError)
+	span.Attributes().InsertInt("http.status_code", 500)
+	span.Events().Resize(1)
+	span.Events().At(0).SetName("exception")
+	ils.Spans().At(1).SetName("other")
+
+	require.NoError(t, lte.ConsumeTraces(context.Background(), td))
+
+	lines := readLines(t, buf)
+	require.Len(t, lines, 2)
+	assert.Equal(t, map[string]interface{}{
+		"resource":                map[string]interface{}{"service.name": "svc"},
+		"instrumentation_library": map[string]interface{}{"name": "lib", "version": ""},
+		"trace_id":                "01000000000000000000000000000000",
+		"span_id":                 "0200000000000000",
+		"parent_span_id":          "",
+		"trace_state":             "",
+		"name":                    "operation",
+		"kind":                    "SPAN_KIND_SERVER",
+		"start_time_unix_nano":    float64(1000),
+		"end_time_unix_nano":      float64(2000),
+		"status":                  map[string]interface{}{"code": "STATUS_CODE_ERROR", "message": ""},
+		"attributes":              map[string]interface{}{"http.status_code": float64(500)},
+		"events": []interface{}{
+			map[string]interface{}{"name": "exception", "time_unix_nano": float64(0), "attributes": map[string]interface{}{}},
+		},
+		"links": []interface{}{},
+	}, lines[0])
+	assert.Equal(t, "other", lines[1]["name"])
+	assert.Equal(t, map[string]interface{}{"service.name": "svc"}, lines[1]["resource"])
+}
+
+func TestLoggingMetricsExporterJSON(t *testing.T) {
+	buf := withStdout(t)
+	lme, err := newMetricsExporter(&configmodels.ExporterSettings{}, "info", formatJSON, nil, zap.NewNop())
+	require.NoError(t, err)
+
+	md := testdata.GeneratMetricsAllTypesWithSampleDatapoints()
+	require.NoError(t, lme.ConsumeMetrics(context.Background(), md))
+
+	lines := readLines(t, buf)
+	_, dataPoints := md.MetricAndDataPointCount()
+	require.Len(t, lines, dataPoints)
+	for _, line := range lines {
+		assert.Contains(t, line, "resource")
+		assert.Contains(t, line, "instrumentation_library")
+		assert.Contains(t, line, "labels")
+		assert.Contains(t, line, "time_unix_nano")
+		assert.Contains(t, line["metric"], "name")
+	}
+
+	first := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().At(0)
+	assert.Equal(t, first.Name(), lines[0]["metric"].(map[string]interface{})["name"])
+	assert.Equal(t, "IntSum", lines[0]["metric"].(map[string]interface{})["data_type"])
+	assert.Equal(t, float64(first.IntSum().DataPoints().At(0).Value()), lines[0]["value"])
+}
+
+func TestLoggingLogsExporterJSON(t *testing.T) {
+	buf := withStdout(t)
+	lle, err := newLogsExporter(&configmodels.ExporterSettings{}, "info", formatJSON, nil, zap.NewNop())
+	require.NoError(t, err)
+
+	ld := pdata.NewLogs()
+	ld.ResourceLogs().Resize(1)
+	rl := ld.ResourceLogs().At(0)
+	rl.Resource().Attributes().InsertString("host.name", "host")
+	rl.InstrumentationLibraryLogs().Resize(1)
+	rl.InstrumentationLibraryLogs().At(0).Logs().Resize(1)
+	lr := rl.InstrumentationLibraryLogs().At(0).Logs().At(0)
+	lr.SetTimestamp(3000)
+	lr.SetSeverityNumber(pdata.SeverityNumberWARN)
+	lr.SetSeverityText("WARN")
+	lr.Body().SetStringVal("disk full")
+	lr.Attributes().InsertBool("fatal", false)
+
+	require.NoError(t, lle.ConsumeLogs(context.Background(), ld))
+
+	lines := readLines(t, buf)
+	require.Len(t, lines, 1)
+	assert.Equal(t, map[string]interface{}{
+		"resource":                map[string]interface{}{"host.name": "host"},
+		"instrumentation_library": map[string]interface{}{"name": "", "version": ""},
+		"time_unix_nano":          float64(3000),
+		"severity_number":         float64(pdata.SeverityNumberWARN),
+		"severity_text":           "WARN",
+		"name":                    "",
+		"body":                    "disk full",
+		"attributes":              map[string]interface{}{"fatal": false},
+		"trace_id":                "",
+		"span_id":                 "",
+		"flags":                   float64(0),
+	}, lines[0])
+}
+
+func TestLoggingExporterOTLPJSON(t *testing.T) {
+	buf := withStdout(t)
+
+	lte, err := newTraceExporter(&configmodels.ExporterSettings{}, "info", formatOTLPJSON, nil, zap.NewNop())
+	require.NoError(t, err)
+	td := testdata.GenerateTraceDataTwoSpansSameResourceOneDifferent()
+	require.NoError(t, lte.ConsumeTraces(context.Background(), td))
+	line, err := buf.ReadBytes('\n')
+	require.NoError(t, err)
+	var traces otlptrace.ExportTraceServiceRequest
+	require.NoError(t, jsonpb.Unmarshal(bytes.NewReader(line), &traces))
+	assert.Equal(t, td, pdata.TracesFromOtlp(traces.ResourceSpans))
+
+	lme, err := newMetricsExporter(&configmodels.ExporterSettings{}, "info", formatOTLPJSON, nil, zap.NewNop())
+	require.NoError(t, err)
+	md := testdata.GeneratMetricsAllTypesWithSampleDatapoints()
+	require.NoError(t, lme.ConsumeMetrics(context.Background(), md))
+	line, err = buf.ReadBytes('\n')
+	require.NoError(t, err)
+	var metrics otlpmetrics.ExportMetricsServiceRequest
+	require.NoError(t, jsonpb.Unmarshal(bytes.NewReader(line), &metrics))
+	assert.Equal(t, md, pdata.MetricsFromOtlp(metrics.ResourceMetrics))
+
+	lle, err := newLogsExporter(&configmodels.ExporterSettings{}, "info", formatOTLPJSON, nil, zap.NewNop())
+	require.NoError(t, err)
+	ld := testdata.GenerateLogDataTwoLogsSameResourceOneDifferent()
+	require.NoError(t, lle.ConsumeLogs(context.Background(), ld))
+	line, err = buf.ReadBytes('\n')
+	require.NoError(t, err)
+	var logs otlplogs.ExportLogsServiceRequest
+	require.NoError(t, jsonpb.Unmarshal(bytes.NewReader(line), &logs))
+	assert.Equal(t, ld, pdata.LogsFromInternalRep(internal.LogsFromOtlp(logs.ResourceLogs)))
+
+	assert.Zero(t, buf.Len())
+}
+
+func TestLoggingExporterJSONSampling(t *testing.T) {
+	buf := withStdout(t)
+	sampler := newBatchSampler(1, 2)
+	sampler.now = func() time.Time { return time.Unix(100, 0) }
+	lle, err := newLogsExporter(&configmodels.ExporterSettings{}, "info", formatJSON, sampler, zap.NewNop())
+	require.NoError(t, err)
+
+	for i := 0; i < 5; i++ {
+		require.NoError(t, lle.ConsumeLogs(context.Background(), testdata.GenerateLogDataOneLog()))
+	}
+	// The first batch, then every second one.
+	assert.Len(t, readLines(t, buf), 3)
+}
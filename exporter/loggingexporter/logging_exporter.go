@@ -17,9 +17,11 @@ package loggingexporter
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"strconv"
 	"strings"
+	"sync"
 	"syscall"
 
 	"go.uber.org/zap"
@@ -314,9 +316,37 @@ func attributeMapToString(av pdata.AttributeMap) string {
 	return b.String()
 }
 
+// stdout is where the json and otlp_json formats write the data, replaced in tests.
+var stdout io.Writer = os.Stdout
+
 type loggingExporter struct {
 	logger *zap.Logger
 	debug  bool
+	format string
+
+	// mu serializes the writes of the data in the json and otlp_json formats
+	// so that the lines of concurrent batches are not interleaved, and guards
+	// the sampler.
+	mu sync.Mutex
+	// sampler samples the batches written in the json and otlp_json formats,
+	// nil to write them all.
+	sampler *batchSampler
+}
+
+// writeData writes the data marshaled in the json or otlp_json format, if the
+// batch is sampled.
+func (s *loggingExporter) writeData(marshal func() ([]byte, error)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.sampler != nil && !s.sampler.sample() {
+		return nil
+	}
+	data, err := marshal()
+	if err != nil {
+		return err
+	}
+	_, err = stdout.Write(data)
+	return err
 }
 
 func (s *loggingExporter) pushTraceData(
@@ -326,6 +356,13 @@ func (s *loggingExporter) pushTraceData(
 
 	s.logger.Info("TracesExporter", zap.Int("#spans", td.SpanCount()))
 
+	switch s.format {
+	case formatJSON:
+		return 0, s.writeData(func() ([]byte, error) { return tracesToJSONLines(td) })
+	case formatOTLPJSON:
+		return 0, s.writeData(func() ([]byte, error) { return tracesToOTLPJSON(td) })
+	}
+
 	if !s.debug {
 		return 0, nil
 	}
@@ -374,6 +411,13 @@ func (s *loggingExporter) pushMetricsData(
 ) (int, error) {
 	s.logger.Info("MetricsExporter", zap.Int("#metrics", md.MetricCount()))
 
+	switch s.format {
+	case formatJSON:
+		return 0, s.writeData(func() ([]byte, error) { return metricsToJSONLines(md) })
+	case formatOTLPJSON:
+		return 0, s.writeData(func() ([]byte, error) { return metricsToOTLPJSON(md) })
+	}
+
 	if !s.debug {
 		return 0, nil
 	}
@@ -406,10 +450,12 @@ func (s *loggingExporter) pushMetricsData(
 
 // newTraceExporter creates an exporter.TracesExporter that just drops the
 // received data and logs debugging messages.
-func newTraceExporter(config configmodels.Exporter, level string, logger *zap.Logger) (component.TracesExporter, error) {
+func newTraceExporter(config configmodels.Exporter, level string, format string, sampler *batchSampler, logger *zap.Logger) (component.TracesExporter, error) {
 	s := &loggingExporter{
-		debug:  strings.ToLower(level) == "debug",
-		logger: logger,
+		debug:   strings.ToLower(level) == "debug",
+		format:  format,
+		sampler: sampler,
+		logger:  logger,
 	}
 
 	return exporterhelper.NewTraceExporter(
@@ -426,10 +472,12 @@ func newTraceExporter(config configmodels.Exporter, level string, logger *zap.Lo
 
 // newMetricsExporter creates an exporter.MetricsExporter that just drops the
 // received data and logs debugging messages.
-func newMetricsExporter(config configmodels.Exporter, level string, logger *zap.Logger) (component.MetricsExporter, error) {
+func newMetricsExporter(config configmodels.Exporter, level string, format string, sampler *batchSampler, logger *zap.Logger) (component.MetricsExporter, error) {
 	s := &loggingExporter{
-		debug:  strings.ToLower(level) == "debug",
-		logger: logger,
+		debug:   strings.ToLower(level) == "debug",
+		format:  format,
+		sampler: sampler,
+		logger:  logger,
 	}
 
 	return exporterhelper.NewMetricsExporter(
@@ -446,10 +494,12 @@ func newMetricsExporter(config configmodels.Exporter, level string, logger *zap.
 
 // newLogsExporter creates an exporter.LogsExporter that just drops the
 // received data and logs debugging messages.
-func newLogsExporter(config configmodels.Exporter, level string, logger *zap.Logger) (component.LogsExporter, error) {
+func newLogsExporter(config configmodels.Exporter, level string, format string, sampler *batchSampler, logger *zap.Logger) (component.LogsExporter, error) {
 	s := &loggingExporter{
-		debug:  strings.ToLower(level) == "debug",
-		logger: logger,
+		debug:   strings.ToLower(level) == "debug",
+		format:  format,
+		sampler: sampler,
+		logger:  logger,
 	}
 
 	return exporterhelper.NewLogsExporter(
@@ -470,6 +520,13 @@ func (s *loggingExporter) pushLogData(
 ) (int, error) {
 	s.logger.Info("LogsExporter", zap.Int("#logs", ld.LogRecordCount()))
 
+	switch s.format {
+	case formatJSON:
+		return 0, s.writeData(func() ([]byte, error) { return logsToJSONLines(ld) })
+	case formatOTLPJSON:
+		return 0, s.writeData(func() ([]byte, error) { return logsToOTLPJSON(ld) })
+	}
+
 	if !s.debug {
 		return 0, nil
 	}
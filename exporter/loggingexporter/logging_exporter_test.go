@@ -27,7 +27,7 @@ import (
 )
 
 func TestLoggingTraceExporterNoErrors(t *testing.T) {
-	lte, err := newTraceExporter(&configmodels.ExporterSettings{}, "Debug", zap.NewNop())
+	lte, err := newTraceExporter(&configmodels.ExporterSettings{}, "Debug", formatText, nil, zap.NewNop())
 	require.NotNil(t, lte)
 	assert.NoError(t, err)
 
@@ -38,7 +38,7 @@ func TestLoggingTraceExporterNoErrors(t *testing.T) {
 }
 
 func TestLoggingMetricsExporterNoErrors(t *testing.T) {
-	lme, err := newMetricsExporter(&configmodels.ExporterSettings{}, "DEBUG", zap.NewNop())
+	lme, err := newMetricsExporter(&configmodels.ExporterSettings{}, "DEBUG", formatText, nil, zap.NewNop())
 	require.NotNil(t, lme)
 	assert.NoError(t, err)
 
@@ -51,7 +51,7 @@ func TestLoggingMetricsExporterNoErrors(t *testing.T) {
 }
 
 func TestLoggingLogsExporterNoErrors(t *testing.T) {
-	lle, err := newLogsExporter(&configmodels.ExporterSettings{}, "debug", zap.NewNop())
+	lle, err := newLogsExporter(&configmodels.ExporterSettings{}, "debug", formatText, nil, zap.NewNop())
 	require.NotNil(t, lle)
 	assert.NoError(t, err)
 
@@ -0,0 +1,54 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loggingexporter
+
+import (
+	"time"
+)
+
+// batchSampler samples the batches written in the json and otlp_json formats
+// as the zap sampler samples the log messages: the first initial batches of
+// each second are written, then every thereafter-th batch of that second.
+type batchSampler struct {
+	initial    int
+	thereafter int
+
+	// now returns the current time, replaced in tests.
+	now   func() time.Time
+	tick  time.Time
+	count int
+}
+
+func newBatchSampler(initial, thereafter int) *batchSampler {
+	return &batchSampler{
+		initial:    initial,
+		thereafter: thereafter,
+		now:        time.Now,
+	}
+}
+
+// sample returns whether the next batch is written, it is not safe for
+// concurrent use.
+func (bs *batchSampler) sample() bool {
+	if now := bs.now(); now.Sub(bs.tick) >= time.Second {
+		bs.tick = now.Truncate(time.Second)
+		bs.count = 0
+	}
+	bs.count++
+	if bs.count <= bs.initial {
+		return true
+	}
+	return bs.thereafter > 0 && (bs.count-bs.initial)%bs.thereafter == 0
+}
@@ -0,0 +1,51 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loggingexporter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchSampler(t *testing.T) {
+	now := time.Unix(100, 0)
+	bs := newBatchSampler(2, 3)
+	bs.now = func() time.Time { return now }
+
+	var sampled []bool
+	for i := 0; i < 8; i++ {
+		sampled = append(sampled, bs.sample())
+	}
+	assert.Equal(t, []bool{true, true, false, false, true, false, false, true}, sampled)
+
+	// The count restarts each second.
+	now = now.Add(1500 * time.Millisecond)
+	assert.True(t, bs.sample())
+	assert.True(t, bs.sample())
+	assert.False(t, bs.sample())
+	now = now.Add(500 * time.Millisecond)
+	assert.True(t, bs.sample())
+}
+
+func TestBatchSamplerNoThereafter(t *testing.T) {
+	bs := newBatchSampler(1, 0)
+	bs.now = func() time.Time { return time.Unix(100, 0) }
+
+	assert.True(t, bs.sample())
+	assert.False(t, bs.sample())
+	assert.False(t, bs.sample())
+}
@@ -0,0 +1,163 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build arrow
+
+package otlpexporter
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configgrpc"
+	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	otlpmetrics "go.opentelemetry.io/collector/internal/data/protogen/collector/metrics/v1"
+	otlptraces "go.opentelemetry.io/collector/internal/data/protogen/collector/trace/v1"
+	"go.opentelemetry.io/collector/internal/otlparrow"
+	"go.opentelemetry.io/collector/internal/testdata"
+)
+
+// arrowServer serves the Arrow requests, the decoded data being exported to the OTLP
+// receivers.
+func arrowServer(srv *grpc.Server, traces *mockTraceReceiver, metrics *mockMetricsReceiver) *int32 {
+	var requestCount int32
+	srv.RegisterService(&grpc.ServiceDesc{
+		ServiceName: otlparrow.TracesServiceName,
+		HandlerType: (*interface{})(nil),
+		Methods: []grpc.MethodDesc{{
+			MethodName: "Export",
+			Handler: func(_ interface{}, ctx context.Context, dec func(interface{}) error, _ grpc.UnaryServerInterceptor) (interface{}, error) {
+				req := &otlparrow.Request{}
+				if err := dec(req); err != nil {
+					return nil, err
+				}
+				atomic.AddInt32(&requestCount, 1)
+				rss, err := otlparrow.DecodeTraces(req)
+				if err != nil {
+					return nil, err
+				}
+				return traces.Export(ctx, &otlptraces.ExportTraceServiceRequest{ResourceSpans: rss})
+			},
+		}},
+	}, struct{}{})
+	srv.RegisterService(&grpc.ServiceDesc{
+		ServiceName: otlparrow.MetricsServiceName,
+		HandlerType: (*interface{})(nil),
+		Methods: []grpc.MethodDesc{{
+			MethodName: "Export",
+			Handler: func(_ interface{}, ctx context.Context, dec func(interface{}) error, _ grpc.UnaryServerInterceptor) (interface{}, error) {
+				req := &otlparrow.Request{}
+				if err := dec(req); err != nil {
+					return nil, err
+				}
+				atomic.AddInt32(&requestCount, 1)
+				rms, err := otlparrow.DecodeMetrics(req)
+				if err != nil {
+					return nil, err
+				}
+				return metrics.Export(ctx, &otlpmetrics.ExportMetricsServiceRequest{ResourceMetrics: rms})
+			},
+		}},
+	}, struct{}{})
+	return &requestCount
+}
+
+func arrowConfig(endpoint string) *Config {
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	cfg.QueueSettings.Enabled = false
+	cfg.Arrow.Enabled = true
+	cfg.GRPCClientSettings = configgrpc.GRPCClientSettings{
+		Endpoint: endpoint,
+		TLSSetting: configtls.TLSClientSetting{
+			Insecure: true,
+		},
+	}
+	return cfg
+}
+
+func TestSendArrow(t *testing.T) {
+	ln, err := net.Listen("tcp", "localhost:")
+	require.NoError(t, err, "Failed to find an available address to run the gRPC server: %v", err)
+	traces := &mockTraceReceiver{mockReceiver: mockReceiver{srv: grpc.NewServer()}}
+	metrics := &mockMetricsReceiver{mockReceiver: mockReceiver{srv: traces.srv}}
+	arrowRequests := arrowServer(traces.srv, traces, metrics)
+	go func() {
+		_ = traces.srv.Serve(ln)
+	}()
+	defer traces.srv.GracefulStop()
+
+	factory := NewFactory()
+	cfg := arrowConfig(ln.Addr().String())
+	creationParams := component.ExporterCreateParams{Logger: zap.NewNop()}
+	texp, err := factory.CreateTracesExporter(context.Background(), creationParams, cfg)
+	require.NoError(t, err)
+	require.NoError(t, texp.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() {
+		assert.NoError(t, texp.Shutdown(context.Background()))
+	}()
+	mexp, err := factory.CreateMetricsExporter(context.Background(), creationParams, cfg)
+	require.NoError(t, err)
+	require.NoError(t, mexp.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() {
+		assert.NoError(t, mexp.Shutdown(context.Background()))
+	}()
+
+	td := testdata.GenerateTraceDataTwoSpansSameResource()
+	require.NoError(t, texp.ConsumeTraces(context.Background(), td))
+	assert.EqualValues(t, 1, atomic.LoadInt32(arrowRequests))
+	assert.EqualValues(t, 2, atomic.LoadInt32(&traces.totalItems))
+	assert.Equal(t, td, pdata.TracesFromOtlp(traces.GetLastRequest().ResourceSpans))
+
+	md := testdata.GenerateMetricsOneCounterOneSummaryMetrics()
+	require.NoError(t, mexp.ConsumeMetrics(context.Background(), md))
+	assert.EqualValues(t, 2, atomic.LoadInt32(arrowRequests))
+	assert.Equal(t, md, pdata.MetricsFromOtlp(metrics.GetLastRequest().ResourceMetrics))
+}
+
+func TestSendArrowUnsupported(t *testing.T) {
+	ln, err := net.Listen("tcp", "localhost:")
+	require.NoError(t, err, "Failed to find an available address to run the gRPC server: %v", err)
+	rcv := otlpTraceReceiverOnGRPCServer(ln)
+	defer rcv.srv.GracefulStop()
+
+	cfg := arrowConfig(ln.Addr().String())
+	exp, err := newExporter(cfg, &cfg.GRPCClientSettings, zap.NewNop())
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, exp.shutdown(context.Background()))
+	}()
+
+	// The server does not implement the Arrow service, the spans are sent with OTLP.
+	td := testdata.GenerateTraceDataTwoSpansSameResource()
+	dropped, err := exp.pushTraceData(context.Background(), td)
+	require.NoError(t, err)
+	assert.Zero(t, dropped)
+	assert.EqualValues(t, 1, atomic.LoadInt32(&exp.arrowUnsupported))
+	assert.EqualValues(t, 1, atomic.LoadInt32(&rcv.requestCount))
+	assert.EqualValues(t, 2, atomic.LoadInt32(&rcv.totalItems))
+
+	_, err = exp.pushTraceData(context.Background(), td)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, atomic.LoadInt32(&rcv.requestCount))
+}
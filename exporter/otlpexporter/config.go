@@ -28,4 +28,56 @@ type Config struct {
 	exporterhelper.RetrySettings   `mapstructure:"retry_on_failure"`
 
 	configgrpc.GRPCClientSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct.
+
+	// The endpoint to send traces to. If omitted the Endpoint will be used.
+	TracesEndpoint string `mapstructure:"traces_endpoint"`
+
+	// The endpoint to send metrics to. If omitted the Endpoint will be used.
+	MetricsEndpoint string `mapstructure:"metrics_endpoint"`
+
+	// The endpoint to send logs to. If omitted the Endpoint will be used.
+	LogsEndpoint string `mapstructure:"logs_endpoint"`
+
+	// The headers sent with the trace requests, overriding the Headers with the same name.
+	TracesHeaders map[string]string `mapstructure:"traces_headers" sensitive:"true"`
+
+	// The headers sent with the metrics requests, overriding the Headers with the same name.
+	MetricsHeaders map[string]string `mapstructure:"metrics_headers" sensitive:"true"`
+
+	// The headers sent with the logs requests, overriding the Headers with the same name.
+	LogsHeaders map[string]string `mapstructure:"logs_headers" sensitive:"true"`
+
+	// RetryOnPartialSuccess retries the requests of which the server rejected some items,
+	// instead of dropping the rejected items. The accepted items are sent again.
+	RetryOnPartialSuccess bool `mapstructure:"retry_on_partial_success"`
+
+	// Arrow configures the experimental columnar encoding of the traces and metrics.
+	Arrow ArrowSettings `mapstructure:"arrow"`
+}
+
+// ArrowSettings configures the experimental Arrow mode, sending the traces and metrics as
+// Apache Arrow tables to the servers supporting it.
+type ArrowSettings struct {
+	// Enabled sends the traces and metrics with the Arrow services, the requests are sent
+	// with OTLP once the server responded that it does not implement them.
+	Enabled bool `mapstructure:"enabled"`
+}
+
+// signalSettings returns the gRPC client settings of a signal, with the given endpoint, when
+// not empty, and headers overriding the ones of the exporter.
+func (cfg *Config) signalSettings(endpoint string, headers map[string]string) *configgrpc.GRPCClientSettings {
+	settings := cfg.GRPCClientSettings
+	if endpoint != "" {
+		settings.Endpoint = endpoint
+	}
+	if len(headers) > 0 {
+		settings.Headers = make(map[string]string, len(cfg.Headers)+len(headers))
+		for k, v := range cfg.Headers {
+			settings.Headers[k] = v
+		}
+		for k, v := range headers {
+			settings.Headers[k] = v
+		}
+	}
+	return &settings
 }
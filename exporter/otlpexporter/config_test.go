@@ -92,4 +92,31 @@ func TestLoadConfig(t *testing.T) {
 				BalancerName: "round_robin",
 			},
 		})
+
+	e2 := cfg.Exporters["otlp/signals"]
+	assert.Equal(t, "1.2.3.4:1234", e2.(*Config).Endpoint)
+	assert.Equal(t, "traces.example.com:4317", e2.(*Config).TracesEndpoint)
+	assert.Equal(t, "metrics.example.com:4317", e2.(*Config).MetricsEndpoint)
+	assert.Equal(t, "", e2.(*Config).LogsEndpoint)
+	assert.Equal(t, map[string]string{"tenant": "logs"}, e2.(*Config).LogsHeaders)
+
+	e3 := cfg.Exporters["otlp/arrow"]
+	assert.Equal(t, ArrowSettings{Enabled: true}, e3.(*Config).Arrow)
+	assert.Equal(t, "gzip", e3.(*Config).Compression)
+}
+
+func TestSignalSettings(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = "1.2.3.4:1234"
+	cfg.Headers = map[string]string{"header": "value", "tenant": "default"}
+
+	settings := cfg.signalSettings("", nil)
+	assert.Equal(t, "1.2.3.4:1234", settings.Endpoint)
+	assert.Equal(t, cfg.Headers, settings.Headers)
+
+	settings = cfg.signalSettings("traces.example.com:4317", map[string]string{"tenant": "traces"})
+	assert.Equal(t, "traces.example.com:4317", settings.Endpoint)
+	assert.Equal(t, map[string]string{"header": "value", "tenant": "traces"}, settings.Headers)
+	assert.Equal(t, map[string]string{"header": "value", "tenant": "default"}, cfg.Headers)
+	assert.Equal(t, "1.2.3.4:1234", cfg.Endpoint)
 }
@@ -60,11 +60,11 @@ func createTraceExporter(
 	params component.ExporterCreateParams,
 	cfg configmodels.Exporter,
 ) (component.TracesExporter, error) {
-	oce, err := newExporter(cfg)
+	oCfg := cfg.(*Config)
+	oce, err := newExporter(cfg, oCfg.signalSettings(oCfg.TracesEndpoint, oCfg.TracesHeaders), params.Logger)
 	if err != nil {
 		return nil, err
 	}
-	oCfg := cfg.(*Config)
 	oexp, err := exporterhelper.NewTraceExporter(
 		cfg,
 		params.Logger,
@@ -85,11 +85,11 @@ func createMetricsExporter(
 	params component.ExporterCreateParams,
 	cfg configmodels.Exporter,
 ) (component.MetricsExporter, error) {
-	oce, err := newExporter(cfg)
+	oCfg := cfg.(*Config)
+	oce, err := newExporter(cfg, oCfg.signalSettings(oCfg.MetricsEndpoint, oCfg.MetricsHeaders), params.Logger)
 	if err != nil {
 		return nil, err
 	}
-	oCfg := cfg.(*Config)
 	oexp, err := exporterhelper.NewMetricsExporter(
 		cfg,
 		params.Logger,
@@ -111,11 +111,11 @@ func createLogsExporter(
 	params component.ExporterCreateParams,
 	cfg configmodels.Exporter,
 ) (component.LogsExporter, error) {
-	oce, err := newExporter(cfg)
+	oCfg := cfg.(*Config)
+	oce, err := newExporter(cfg, oCfg.signalSettings(oCfg.LogsEndpoint, oCfg.LogsHeaders), params.Logger)
 	if err != nil {
 		return nil, err
 	}
-	oCfg := cfg.(*Config)
 	oexp, err := exporterhelper.NewLogsExporter(
 		cfg,
 		params.Logger,
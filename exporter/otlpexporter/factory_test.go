@@ -71,6 +71,12 @@ func TestCreateTraceExporter(t *testing.T) {
 			},
 			mustFail: true,
 		},
+		{
+			name: "TracesEndpoint",
+			config: Config{
+				TracesEndpoint: endpoint,
+			},
+		},
 		{
 			name: "UseSecure",
 			config: Config{
@@ -18,14 +18,18 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"sync/atomic"
 	"time"
 
+	"go.uber.org/zap"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 
+	"go.opentelemetry.io/collector/config/configgrpc"
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/consumer/pdata"
@@ -34,26 +38,46 @@ import (
 	otlplogs "go.opentelemetry.io/collector/internal/data/protogen/collector/logs/v1"
 	otlpmetrics "go.opentelemetry.io/collector/internal/data/protogen/collector/metrics/v1"
 	otlptrace "go.opentelemetry.io/collector/internal/data/protogen/collector/trace/v1"
+	"go.opentelemetry.io/collector/internal/otlparrow"
+	"go.opentelemetry.io/collector/internal/partialsuccess"
 )
 
 type exporterImp struct {
 	// Input configuration.
 	config *Config
 	w      *grpcSender
+	logger *zap.Logger
+
+	// arrowUnsupported is set to 1 once the server responded that it does not implement
+	// the Arrow services.
+	arrowUnsupported int32
 }
 
+// errArrowUnsupported is returned when the server does not implement an Arrow service.
+var errArrowUnsupported = errors.New("the Arrow service is not implemented by the server")
+
+const (
+	traceExportMethod   = "/opentelemetry.proto.collector.trace.v1.TraceService/Export"
+	metricsExportMethod = "/opentelemetry.proto.collector.metrics.v1.MetricsService/Export"
+	logsExportMethod    = "/opentelemetry.proto.collector.logs.v1.LogsService/Export"
+)
+
 // Crete new exporter and start it. The exporter will begin connecting but
 // this function may return before the connection is established.
-func newExporter(cfg configmodels.Exporter) (*exporterImp, error) {
+func newExporter(cfg configmodels.Exporter, settings *configgrpc.GRPCClientSettings, logger *zap.Logger) (*exporterImp, error) {
 	oCfg := cfg.(*Config)
 
-	if oCfg.Endpoint == "" {
+	if settings.Endpoint == "" {
 		return nil, errors.New("OTLP exporter config requires an Endpoint")
 	}
+	if oCfg.Arrow.Enabled && !otlparrow.Enabled {
+		return nil, otlparrow.ErrDisabled
+	}
 
 	e := &exporterImp{}
 	e.config = oCfg
-	w, err := newGrpcSender(oCfg)
+	e.logger = logger
+	w, err := newGrpcSender(settings)
 	if err != nil {
 		return nil, err
 	}
@@ -66,69 +90,118 @@ func (e *exporterImp) shutdown(context.Context) error {
 }
 
 func (e *exporterImp) pushTraceData(ctx context.Context, td pdata.Traces) (int, error) {
-	request := &otlptrace.ExportTraceServiceRequest{
-		ResourceSpans: pdata.TracesToOtlp(td),
+	rss := pdata.TracesToOtlp(td)
+	ps, sent, err := e.exportArrow(ctx, otlparrow.TracesExportMethod, func() (*otlparrow.Request, error) {
+		return otlparrow.EncodeTraces(rss)
+	})
+	if !sent {
+		request := &otlptrace.ExportTraceServiceRequest{
+			ResourceSpans: rss,
+		}
+		ps, err = e.w.exportTrace(ctx, request)
 	}
-	err := e.w.exportTrace(ctx, request)
 
 	if err != nil {
 		return td.SpanCount(), fmt.Errorf("failed to push trace data via OTLP exporter: %w", err)
 	}
-	return 0, nil
+	return e.processPartialSuccess(ps, td.SpanCount())
 }
 
 func (e *exporterImp) pushMetricsData(ctx context.Context, md pdata.Metrics) (int, error) {
-	request := &otlpmetrics.ExportMetricsServiceRequest{
-		ResourceMetrics: pdata.MetricsToOtlp(md),
+	rms := pdata.MetricsToOtlp(md)
+	ps, sent, err := e.exportArrow(ctx, otlparrow.MetricsExportMethod, func() (*otlparrow.Request, error) {
+		return otlparrow.EncodeMetrics(rms)
+	})
+	if !sent {
+		request := &otlpmetrics.ExportMetricsServiceRequest{
+			ResourceMetrics: rms,
+		}
+		ps, err = e.w.exportMetrics(ctx, request)
 	}
-	err := e.w.exportMetrics(ctx, request)
 
 	if err != nil {
 		return md.MetricCount(), fmt.Errorf("failed to push metrics data via OTLP exporter: %w", err)
 	}
-	return 0, nil
+	return e.processPartialSuccess(ps, md.MetricCount())
 }
 
 func (e *exporterImp) pushLogData(ctx context.Context, logs pdata.Logs) (int, error) {
 	request := &otlplogs.ExportLogsServiceRequest{
 		ResourceLogs: internal.LogsToOtlp(logs.InternalRep()),
 	}
-	err := e.w.exportLogs(ctx, request)
+	ps, err := e.w.exportLogs(ctx, request)
 
 	if err != nil {
 		return logs.LogRecordCount(), fmt.Errorf("failed to push log data via OTLP exporter: %w", err)
 	}
+	return e.processPartialSuccess(ps, logs.LogRecordCount())
+}
+
+// exportArrow sends the Arrow request built by encode when the Arrow mode is enabled and the
+// server did not respond yet that it does not implement the Arrow services. sent is false
+// when the data has to be sent with OTLP instead.
+func (e *exporterImp) exportArrow(
+	ctx context.Context,
+	method string,
+	encode func() (*otlparrow.Request, error),
+) (ps partialsuccess.PartialSuccess, sent bool, err error) {
+	if !e.config.Arrow.Enabled || atomic.LoadInt32(&e.arrowUnsupported) != 0 {
+		return ps, false, nil
+	}
+	request, err := encode()
+	if err != nil {
+		e.logger.Warn("Failed to encode the data as Arrow tables, sending it with OTLP", zap.Error(err))
+		return ps, false, nil
+	}
+	ps, err = e.w.exportArrow(ctx, method, request)
+	if err == errArrowUnsupported {
+		if atomic.CompareAndSwapInt32(&e.arrowUnsupported, 0, 1) {
+			e.logger.Info("The server does not support the Arrow mode, sending the data with OTLP")
+		}
+		return ps, false, nil
+	}
+	return ps, true, err
+}
+
+// processPartialSuccess returns the number of dropped items and the error of an export request
+// of numItems items, from the partial success of its response.
+func (e *exporterImp) processPartialSuccess(ps partialsuccess.PartialSuccess, numItems int) (int, error) {
+	err := ps.Error(e.config.RetryOnPartialSuccess)
+	if err != nil {
+		err = fmt.Errorf("partial success of the export via OTLP exporter: %w", err)
+		if e.config.RetryOnPartialSuccess {
+			return numItems, err
+		}
+		return int(ps.Rejected), err
+	}
+	if ps.ErrorMessage != "" {
+		e.logger.Warn("The export via OTLP exporter succeeded with a warning", zap.String("message", ps.ErrorMessage))
+	}
 	return 0, nil
 }
 
 type grpcSender struct {
-	// gRPC clients and connection.
-	traceExporter  otlptrace.TraceServiceClient
-	metricExporter otlpmetrics.MetricsServiceClient
-	logExporter    otlplogs.LogsServiceClient
+	// gRPC connection, the requests are invoked with partialsuccess.Response responses.
 	grpcClientConn *grpc.ClientConn
 	metadata       metadata.MD
 	waitForReady   bool
 }
 
-func newGrpcSender(config *Config) (*grpcSender, error) {
-	dialOpts, err := config.GRPCClientSettings.ToDialOptions()
+func newGrpcSender(settings *configgrpc.GRPCClientSettings) (*grpcSender, error) {
+	dialOpts, err := settings.ToDialOptions()
 	if err != nil {
 		return nil, err
 	}
 
 	var clientConn *grpc.ClientConn
-	if clientConn, err = grpc.Dial(config.GRPCClientSettings.Endpoint, dialOpts...); err != nil {
+	if clientConn, err = grpc.Dial(settings.Endpoint, dialOpts...); err != nil {
 		return nil, err
 	}
 
 	gs := &grpcSender{
-		traceExporter:  otlptrace.NewTraceServiceClient(clientConn),
-		metricExporter: otlpmetrics.NewMetricsServiceClient(clientConn),
-		logExporter:    otlplogs.NewLogsServiceClient(clientConn),
 		grpcClientConn: clientConn,
-		metadata:       metadata.New(config.GRPCClientSettings.Headers),
-		waitForReady:   config.GRPCClientSettings.WaitForReady,
+		metadata:       metadata.New(settings.Headers),
+		waitForReady:   settings.WaitForReady,
 	}
 	return gs, nil
 }
@@ -137,19 +210,37 @@ func (gs *grpcSender) stop() error {
 	return gs.grpcClientConn.Close()
 }
 
-func (gs *grpcSender) exportTrace(ctx context.Context, request *otlptrace.ExportTraceServiceRequest) error {
-	_, err := gs.traceExporter.Export(gs.enhanceContext(ctx), request, grpc.WaitForReady(gs.waitForReady))
-	return processError(err)
+func (gs *grpcSender) exportTrace(ctx context.Context, request *otlptrace.ExportTraceServiceRequest) (partialsuccess.PartialSuccess, error) {
+	return gs.export(ctx, traceExportMethod, request)
+}
+
+func (gs *grpcSender) exportMetrics(ctx context.Context, request *otlpmetrics.ExportMetricsServiceRequest) (partialsuccess.PartialSuccess, error) {
+	return gs.export(ctx, metricsExportMethod, request)
+}
+
+func (gs *grpcSender) exportLogs(ctx context.Context, request *otlplogs.ExportLogsServiceRequest) (partialsuccess.PartialSuccess, error) {
+	return gs.export(ctx, logsExportMethod, request)
+}
+
+// exportArrow sends an Arrow request, errArrowUnsupported is returned when the server does
+// not implement the method.
+func (gs *grpcSender) exportArrow(ctx context.Context, method string, request *otlparrow.Request) (partialsuccess.PartialSuccess, error) {
+	ps, err := gs.invoke(ctx, method, request)
+	if status.Code(err) == codes.Unimplemented {
+		return ps, errArrowUnsupported
+	}
+	return ps, processError(err)
 }
 
-func (gs *grpcSender) exportMetrics(ctx context.Context, request *otlpmetrics.ExportMetricsServiceRequest) error {
-	_, err := gs.metricExporter.Export(gs.enhanceContext(ctx), request, grpc.WaitForReady(gs.waitForReady))
-	return processError(err)
+func (gs *grpcSender) export(ctx context.Context, method string, request interface{}) (partialsuccess.PartialSuccess, error) {
+	ps, err := gs.invoke(ctx, method, request)
+	return ps, processError(err)
 }
 
-func (gs *grpcSender) exportLogs(ctx context.Context, request *otlplogs.ExportLogsServiceRequest) error {
-	_, err := gs.logExporter.Export(gs.enhanceContext(ctx), request, grpc.WaitForReady(gs.waitForReady))
-	return processError(err)
+func (gs *grpcSender) invoke(ctx context.Context, method string, request interface{}) (partialsuccess.PartialSuccess, error) {
+	resp := &partialsuccess.Response{}
+	err := gs.grpcClientConn.Invoke(gs.enhanceContext(ctx), method, request, resp, grpc.WaitForReady(gs.waitForReady))
+	return resp.PartialSuccess, err
 }
 
 func (gs *grpcSender) enhanceContext(ctx context.Context) context.Context {
@@ -178,6 +269,11 @@ func processError(err error) error {
 
 	// Now, this is this a real error.
 
+	if isMessageTooLarge(st) {
+		// The message exceeded the maximum size of the client or of the server, split it.
+		return exporterhelper.NewRequestTooLargeError(err)
+	}
+
 	if !shouldRetry(st.Code()) {
 		// It is not a retryable error, we should not retry.
 		return consumererror.Permanent(err)
@@ -194,6 +290,12 @@ func processError(err error) error {
 	return err
 }
 
+// isMessageTooLarge returns whether the status is the error returned by gRPC when a message exceeds
+// the maximum message size, when sending it or when receiving it on the server.
+func isMessageTooLarge(st *status.Status) bool {
+	return st.Code() == codes.ResourceExhausted && strings.Contains(st.Message(), "message larger than max")
+}
+
 func shouldRetry(code codes.Code) bool {
 	switch code {
 	case codes.OK:
@@ -26,18 +26,24 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protowire"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config/configgrpc"
 	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
 	otlplogs "go.opentelemetry.io/collector/internal/data/protogen/collector/logs/v1"
 	otlpmetrics "go.opentelemetry.io/collector/internal/data/protogen/collector/metrics/v1"
 	otlptraces "go.opentelemetry.io/collector/internal/data/protogen/collector/trace/v1"
 	"go.opentelemetry.io/collector/internal/testdata"
 	"go.opentelemetry.io/collector/obsreport"
+	"go.opentelemetry.io/collector/obsreport/obsreporttest"
 	"go.opentelemetry.io/collector/testutil"
 )
 
@@ -533,3 +539,191 @@ func TestSendLogData(t *testing.T) {
 	assert.EqualValues(t, 2, atomic.LoadInt32(&rcv.totalItems))
 	assert.EqualValues(t, expectedOTLPReq, rcv.GetLastRequest())
 }
+
+func TestSendToSignalEndpoints(t *testing.T) {
+	// Start one OTLP-compatible receiver per signal.
+	tracesLn, err := net.Listen("tcp", "localhost:")
+	require.NoError(t, err, "Failed to find an available address to run the gRPC server: %v", err)
+	tracesRcv := otlpTraceReceiverOnGRPCServer(tracesLn)
+	defer tracesRcv.srv.GracefulStop()
+	metricsLn, err := net.Listen("tcp", "localhost:")
+	require.NoError(t, err, "Failed to find an available address to run the gRPC server: %v", err)
+	metricsRcv := otlpMetricsReceiverOnGRPCServer(metricsLn)
+	defer metricsRcv.srv.GracefulStop()
+
+	// Start the OTLP exporters of both signals from the same config.
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.GRPCClientSettings = configgrpc.GRPCClientSettings{
+		Endpoint: "localhost:1",
+		TLSSetting: configtls.TLSClientSetting{
+			Insecure: true,
+		},
+		Headers: map[string]string{
+			"header": "header-value",
+			"tenant": "default",
+		},
+	}
+	cfg.TracesEndpoint = tracesLn.Addr().String()
+	cfg.MetricsEndpoint = metricsLn.Addr().String()
+	cfg.MetricsHeaders = map[string]string{"tenant": "metrics"}
+	creationParams := component.ExporterCreateParams{Logger: zap.NewNop()}
+	tracesExp, err := factory.CreateTracesExporter(context.Background(), creationParams, cfg)
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, tracesExp.Shutdown(context.Background()))
+	}()
+	metricsExp, err := factory.CreateMetricsExporter(context.Background(), creationParams, cfg)
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, metricsExp.Shutdown(context.Background()))
+	}()
+
+	host := componenttest.NewNopHost()
+	assert.NoError(t, tracesExp.Start(context.Background(), host))
+	assert.NoError(t, metricsExp.Start(context.Background(), host))
+
+	assert.NoError(t, tracesExp.ConsumeTraces(context.Background(), testdata.GenerateTraceDataTwoSpansSameResource()))
+	assert.NoError(t, metricsExp.ConsumeMetrics(context.Background(), testdata.GenerateMetricsTwoMetrics()))
+
+	// Wait until both are received.
+	testutil.WaitFor(t, func() bool {
+		return atomic.LoadInt32(&tracesRcv.requestCount) > 0 && atomic.LoadInt32(&metricsRcv.requestCount) > 0
+	}, "receive a request per signal")
+
+	assert.EqualValues(t, 2, atomic.LoadInt32(&tracesRcv.totalItems))
+	assert.EqualValues(t, []string{"header-value"}, tracesRcv.GetMetadata().Get("header"))
+	assert.EqualValues(t, []string{"default"}, tracesRcv.GetMetadata().Get("tenant"))
+	assert.EqualValues(t, []string{"header-value"}, metricsRcv.GetMetadata().Get("header"))
+	assert.EqualValues(t, []string{"metrics"}, metricsRcv.GetMetadata().Get("tenant"))
+	// The headers of the exporter are unchanged.
+	assert.Equal(t, "default", cfg.Headers["tenant"])
+}
+
+// partialSuccessResponse is a serialized OTLP export response with a partial success.
+type partialSuccessResponse struct {
+	buf []byte
+}
+
+func (r *partialSuccessResponse) Reset()                   {}
+func (r *partialSuccessResponse) String() string           { return "partialSuccessResponse" }
+func (r *partialSuccessResponse) ProtoMessage()            {}
+func (r *partialSuccessResponse) Marshal() ([]byte, error) { return r.buf, nil }
+
+func newPartialSuccessResponse(rejected int64, errorMessage string) *partialSuccessResponse {
+	var ps []byte
+	ps = protowire.AppendTag(ps, 1, protowire.VarintType)
+	ps = protowire.AppendVarint(ps, uint64(rejected))
+	ps = protowire.AppendTag(ps, 2, protowire.BytesType)
+	ps = protowire.AppendString(ps, errorMessage)
+	var buf []byte
+	buf = protowire.AppendTag(buf, 1, protowire.BytesType)
+	return &partialSuccessResponse{buf: protowire.AppendBytes(buf, ps)}
+}
+
+// partialSuccessTraceServer serves the trace requests with the responses returned by respond
+// for the request number, starting at 1.
+func partialSuccessTraceServer(ln net.Listener, respond func(int32) *partialSuccessResponse) (*grpc.Server, *int32) {
+	srv := grpc.NewServer()
+	var requestCount int32
+	srv.RegisterService(&grpc.ServiceDesc{
+		ServiceName: "opentelemetry.proto.collector.trace.v1.TraceService",
+		HandlerType: (*interface{})(nil),
+		Methods: []grpc.MethodDesc{{
+			MethodName: "Export",
+			Handler: func(_ interface{}, _ context.Context, dec func(interface{}) error, _ grpc.UnaryServerInterceptor) (interface{}, error) {
+				if err := dec(&otlptraces.ExportTraceServiceRequest{}); err != nil {
+					return nil, err
+				}
+				return respond(atomic.AddInt32(&requestCount, 1)), nil
+			},
+		}},
+	}, struct{}{})
+	go func() {
+		_ = srv.Serve(ln)
+	}()
+	return srv, &requestCount
+}
+
+func TestSendTracesPartialSuccess(t *testing.T) {
+	doneFn, err := obsreporttest.SetupRecordedMetricsTest()
+	require.NoError(t, err)
+	defer doneFn()
+
+	ln, err := net.Listen("tcp", "localhost:")
+	require.NoError(t, err, "Failed to find an available address to run the gRPC server: %v", err)
+	srv, requestCount := partialSuccessTraceServer(ln, func(int32) *partialSuccessResponse {
+		return newPartialSuccessResponse(1, "span is too old")
+	})
+	defer srv.GracefulStop()
+
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.QueueSettings.Enabled = false
+	cfg.GRPCClientSettings = configgrpc.GRPCClientSettings{
+		Endpoint: ln.Addr().String(),
+		TLSSetting: configtls.TLSClientSetting{
+			Insecure: true,
+		},
+	}
+	exp, err := factory.CreateTracesExporter(context.Background(), component.ExporterCreateParams{Logger: zap.NewNop()}, cfg)
+	require.NoError(t, err)
+	require.NoError(t, exp.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() {
+		assert.NoError(t, exp.Shutdown(context.Background()))
+	}()
+
+	err = exp.ConsumeTraces(context.Background(), testdata.GenerateTraceDataTwoSpansSameResource())
+	require.Error(t, err)
+	assert.True(t, consumererror.IsPermanent(err))
+	assert.Contains(t, err.Error(), "span is too old")
+	rejected, ok := consumererror.RejectedItems(err)
+	assert.True(t, ok)
+	assert.Equal(t, 1, rejected)
+	assert.EqualValues(t, 1, atomic.LoadInt32(requestCount))
+
+	// The accepted span is sent, the rejected one failed to be sent.
+	obsreporttest.CheckExporterTracesViews(t, cfg.Name(), 1, 1)
+}
+
+func TestSendTracesPartialSuccessRetry(t *testing.T) {
+	ln, err := net.Listen("tcp", "localhost:")
+	require.NoError(t, err, "Failed to find an available address to run the gRPC server: %v", err)
+	srv, requestCount := partialSuccessTraceServer(ln, func(request int32) *partialSuccessResponse {
+		if request == 1 {
+			return newPartialSuccessResponse(1, "try again")
+		}
+		// A warning only.
+		return newPartialSuccessResponse(0, "almost too old")
+	})
+	defer srv.GracefulStop()
+
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.QueueSettings.Enabled = false
+	cfg.RetrySettings.InitialInterval = 10 * time.Millisecond
+	cfg.RetryOnPartialSuccess = true
+	cfg.GRPCClientSettings = configgrpc.GRPCClientSettings{
+		Endpoint: ln.Addr().String(),
+		TLSSetting: configtls.TLSClientSetting{
+			Insecure: true,
+		},
+	}
+	exp, err := factory.CreateTracesExporter(context.Background(), component.ExporterCreateParams{Logger: zap.NewNop()}, cfg)
+	require.NoError(t, err)
+	require.NoError(t, exp.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() {
+		assert.NoError(t, exp.Shutdown(context.Background()))
+	}()
+
+	assert.NoError(t, exp.ConsumeTraces(context.Background(), testdata.GenerateTraceDataTwoSpansSameResource()))
+	assert.EqualValues(t, 2, atomic.LoadInt32(requestCount))
+}
+
+func TestProcessErrorMessageTooLarge(t *testing.T) {
+	err := status.Error(codes.ResourceExhausted, "grpc: received message larger than max (8 vs. 4)")
+	assert.EqualValues(t, exporterhelper.NewRequestTooLargeError(err), processError(err))
+
+	err = status.Error(codes.ResourceExhausted, "quota exceeded")
+	assert.EqualValues(t, err, processError(err))
+}
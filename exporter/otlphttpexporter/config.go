@@ -36,7 +36,11 @@ type Config struct {
 	// The URL to send logs to. If omitted the Endpoint + "/v1/logs" will be used.
 	LogsEndpoint string `mapstructure:"logs_endpoint"`
 
-	// The compression key for supported compression types within
-	// collector. Currently the only supported mode is `gzip`.
+	// The compression of the request bodies, `gzip`, `zstd` or `none`. The requests are sent
+	// uncompressed once the server refused a compressed request with 415 Unsupported Media Type.
 	Compression string `mapstructure:"compression"`
+
+	// RetryOnPartialSuccess retries the requests of which the server rejected some items,
+	// instead of dropping the rejected items. The accepted items are sent again.
+	RetryOnPartialSuccess bool `mapstructure:"retry_on_partial_success"`
 }
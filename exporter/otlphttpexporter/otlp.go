@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"mime"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -37,6 +38,7 @@ import (
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
 	"go.opentelemetry.io/collector/internal/middleware"
+	"go.opentelemetry.io/collector/internal/partialsuccess"
 )
 
 type exporterImp struct {
@@ -52,6 +54,9 @@ type exporterImp struct {
 const (
 	headerRetryAfter         = "Retry-After"
 	maxHTTPResponseReadBytes = 64 * 1024
+
+	compressionNone = "none"
+	compressionZstd = "zstd"
 )
 
 // Crete new exporter.
@@ -70,12 +75,15 @@ func newExporter(cfg configmodels.Exporter, logger *zap.Logger) (*exporterImp, e
 		return nil, err
 	}
 
-	if oCfg.Compression != "" {
-		if strings.ToLower(oCfg.Compression) == configgrpc.CompressionGzip {
-			client.Transport = middleware.NewCompressRoundTripper(client.Transport)
-		} else {
-			return nil, fmt.Errorf("unsupported compression type %q", oCfg.Compression)
+	switch compression := strings.ToLower(oCfg.Compression); compression {
+	case "", compressionNone:
+	case configgrpc.CompressionGzip, compressionZstd:
+		client.Transport, err = middleware.NewCompressRoundTripperWithEncoding(client.Transport, compression)
+		if err != nil {
+			return nil, err
 		}
+	default:
+		return nil, fmt.Errorf("unsupported compression type %q", oCfg.Compression)
 	}
 
 	return &exporterImp{
@@ -91,12 +99,12 @@ func (e *exporterImp) pushTraceData(ctx context.Context, traces pdata.Traces) (i
 		return traces.SpanCount(), consumererror.Permanent(err)
 	}
 
-	err = e.export(ctx, e.tracesURL, request)
+	ps, err := e.export(ctx, e.tracesURL, request)
 	if err != nil {
 		return traces.SpanCount(), err
 	}
 
-	return 0, nil
+	return e.processPartialSuccess(ps, traces.SpanCount())
 }
 
 func (e *exporterImp) pushMetricsData(ctx context.Context, metrics pdata.Metrics) (int, error) {
@@ -105,12 +113,12 @@ func (e *exporterImp) pushMetricsData(ctx context.Context, metrics pdata.Metrics
 		return metrics.MetricCount(), consumererror.Permanent(err)
 	}
 
-	err = e.export(ctx, e.metricsURL, request)
+	ps, err := e.export(ctx, e.metricsURL, request)
 	if err != nil {
 		return metrics.MetricCount(), err
 	}
 
-	return 0, nil
+	return e.processPartialSuccess(ps, metrics.MetricCount())
 }
 
 func (e *exporterImp) pushLogData(ctx context.Context, logs pdata.Logs) (int, error) {
@@ -119,25 +127,42 @@ func (e *exporterImp) pushLogData(ctx context.Context, logs pdata.Logs) (int, er
 		return logs.LogRecordCount(), consumererror.Permanent(err)
 	}
 
-	err = e.export(ctx, e.logsURL, request)
+	ps, err := e.export(ctx, e.logsURL, request)
 	if err != nil {
 		return logs.LogRecordCount(), err
 	}
 
+	return e.processPartialSuccess(ps, logs.LogRecordCount())
+}
+
+// processPartialSuccess returns the number of dropped items and the error of an export request
+// of numItems items, from the partial success of its response.
+func (e *exporterImp) processPartialSuccess(ps partialsuccess.PartialSuccess, numItems int) (int, error) {
+	err := ps.Error(e.config.RetryOnPartialSuccess)
+	if err != nil {
+		err = fmt.Errorf("partial success of the export via OTLP/HTTP exporter: %w", err)
+		if e.config.RetryOnPartialSuccess {
+			return numItems, err
+		}
+		return int(ps.Rejected), err
+	}
+	if ps.ErrorMessage != "" {
+		e.logger.Warn("The export via OTLP/HTTP exporter succeeded with a warning", zap.String("message", ps.ErrorMessage))
+	}
 	return 0, nil
 }
 
-func (e *exporterImp) export(ctx context.Context, url string, request []byte) error {
+func (e *exporterImp) export(ctx context.Context, url string, request []byte) (partialsuccess.PartialSuccess, error) {
 	e.logger.Debug("Preparing to make HTTP request", zap.String("url", url))
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(request))
 	if err != nil {
-		return consumererror.Permanent(err)
+		return partialsuccess.PartialSuccess{}, consumererror.Permanent(err)
 	}
 	req.Header.Set("Content-Type", "application/x-protobuf")
 
 	resp, err := e.client.Do(req)
 	if err != nil {
-		return fmt.Errorf("failed to make an HTTP request: %w", err)
+		return partialsuccess.PartialSuccess{}, fmt.Errorf("failed to make an HTTP request: %w", err)
 	}
 
 	defer func() {
@@ -147,8 +172,8 @@ func (e *exporterImp) export(ctx context.Context, url string, request []byte) er
 	}()
 
 	if resp.StatusCode >= 200 && resp.StatusCode <= 299 {
-		// Request is successful.
-		return nil
+		// Request is successful, apart from the items rejected in the partial success.
+		return e.readPartialSuccess(resp), nil
 	}
 
 	respStatus := readResponse(resp)
@@ -177,16 +202,39 @@ func (e *exporterImp) export(ctx context.Context, url string, request []byte) er
 			}
 		}
 		// Indicate to our caller to pause for the specified number of seconds.
-		return exporterhelper.NewThrottleRetry(formattedErr, time.Duration(retryAfter)*time.Second)
+		return partialsuccess.PartialSuccess{}, exporterhelper.NewThrottleRetry(formattedErr, time.Duration(retryAfter)*time.Second)
 	}
 
 	if resp.StatusCode == http.StatusBadRequest {
 		// Report the failure as permanent if the server thinks the request is malformed.
-		return consumererror.Permanent(formattedErr)
+		return partialsuccess.PartialSuccess{}, consumererror.Permanent(formattedErr)
+	}
+
+	if resp.StatusCode == http.StatusRequestEntityTooLarge {
+		// Split the request if the server thinks it is too large.
+		return partialsuccess.PartialSuccess{}, exporterhelper.NewRequestTooLargeError(formattedErr)
 	}
 
 	// All other errors are retryable, so don't wrap them in consumererror.Permanent().
-	return formattedErr
+	return partialsuccess.PartialSuccess{}, formattedErr
+}
+
+// readPartialSuccess decodes the partial success of a successful Protobuf-encoded response.
+// The response is considered a full success when it cannot be decoded.
+func (e *exporterImp) readPartialSuccess(resp *http.Response) partialsuccess.PartialSuccess {
+	if mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type")); err != nil || mediaType != "application/x-protobuf" {
+		return partialsuccess.PartialSuccess{}
+	}
+	respBytes, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxHTTPResponseReadBytes))
+	if err != nil {
+		return partialsuccess.PartialSuccess{}
+	}
+	ps, err := partialsuccess.Unmarshal(respBytes)
+	if err != nil {
+		e.logger.Debug("Failed to decode the partial success of the response", zap.Error(err))
+		return partialsuccess.PartialSuccess{}
+	}
+	return ps
 }
 
 // Read the response and decode the status.Status from the body.
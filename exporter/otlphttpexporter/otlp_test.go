@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -28,6 +29,7 @@ import (
 	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protowire"
 	"google.golang.org/protobuf/proto"
 
 	"go.opentelemetry.io/collector/component"
@@ -149,6 +151,16 @@ func TestCompressionOptions(t *testing.T) {
 			baseURL:     fmt.Sprintf("http://%s", addr),
 			compression: "gzip",
 		},
+		{
+			name:        "zstd",
+			baseURL:     fmt.Sprintf("http://%s", addr),
+			compression: "zstd",
+		},
+		{
+			name:        "none",
+			baseURL:     fmt.Sprintf("http://%s", addr),
+			compression: "none",
+		},
 		{
 			name:        "incorrect compression",
 			baseURL:     fmt.Sprintf("http://%s", addr),
@@ -390,6 +402,13 @@ func TestErrorResponses(t *testing.T) {
 			responseStatus: http.StatusNotFound,
 			err:            fmt.Errorf(errMsgPrefix + "404"),
 		},
+		{
+			name:           "413",
+			responseStatus: http.StatusRequestEntityTooLarge,
+			responseBody:   status.New(codes.InvalidArgument, "Request too large"),
+			err: exporterhelper.NewRequestTooLargeError(
+				fmt.Errorf(errMsgPrefix + "413, Message=Request too large, Details=[]")),
+		},
 		{
 			name:           "419",
 			responseStatus: http.StatusTooManyRequests,
@@ -463,3 +482,71 @@ func TestErrorResponses(t *testing.T) {
 		})
 	}
 }
+
+func TestPartialSuccessResponses(t *testing.T) {
+	var ps []byte
+	ps = protowire.AppendTag(ps, 1, protowire.VarintType)
+	ps = protowire.AppendVarint(ps, 1)
+	ps = protowire.AppendTag(ps, 2, protowire.BytesType)
+	ps = protowire.AppendString(ps, "span is too old")
+	var partialSuccess []byte
+	partialSuccess = protowire.AppendTag(partialSuccess, 1, protowire.BytesType)
+	partialSuccess = protowire.AppendBytes(partialSuccess, ps)
+
+	tests := []struct {
+		name        string
+		contentType string
+		retry       bool
+		wantErr     bool
+		isPermErr   bool
+	}{
+		{
+			name:        "drop",
+			contentType: "application/x-protobuf",
+			wantErr:     true,
+			isPermErr:   true,
+		},
+		{
+			name:        "retry",
+			contentType: "application/x-protobuf",
+			retry:       true,
+			wantErr:     true,
+		},
+		{
+			name:        "json",
+			contentType: "application/json",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+				writer.Header().Set("Content-Type", test.contentType)
+				writer.WriteHeader(http.StatusOK)
+				writer.Write(partialSuccess)
+			}))
+			defer srv.Close()
+
+			cfg := &Config{
+				TracesEndpoint:        srv.URL + "/v1/traces",
+				RetryOnPartialSuccess: test.retry,
+				// Create without QueueSettings and RetrySettings so that ConsumeTraces
+				// returns the errors that we want to check immediately.
+			}
+			exp, err := createTraceExporter(context.Background(), component.ExporterCreateParams{Logger: zap.NewNop()}, cfg)
+			require.NoError(t, err)
+
+			err = exp.ConsumeTraces(context.Background(), testdata.GenerateTraceDataTwoSpansSameResource())
+			if !test.wantErr {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "span is too old")
+			assert.Equal(t, test.isPermErr, consumererror.IsPermanent(err))
+			rejected, ok := consumererror.RejectedItems(err)
+			assert.True(t, ok)
+			assert.Equal(t, 1, rejected)
+		})
+	}
+}
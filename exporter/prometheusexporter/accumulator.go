@@ -20,9 +20,11 @@ import (
 	"sync"
 	"time"
 
+	"github.com/prometheus/prometheus/pkg/value"
 	"go.uber.org/zap"
 
 	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal/processor/filterset"
 )
 
 type accumulatedValue struct {
@@ -31,6 +33,7 @@ type accumulatedValue struct {
 	// stored indicates when metric was stored
 	stored time.Time
 
+	resource               pdata.Resource
 	instrumentationLibrary pdata.InstrumentationLibrary
 }
 
@@ -38,8 +41,9 @@ type accumulatedValue struct {
 type accumulator interface {
 	// Accumulate stores aggragated metric values
 	Accumulate(resourceMetrics pdata.ResourceMetrics) (processed int)
-	// Collect returns a slice with relevant aggregated metrics
-	Collect() (metrics []pdata.Metric)
+	// Collect returns a slice with relevant aggregated metrics and a slice with
+	// the resources of these metrics, at the same indexes
+	Collect() (metrics []pdata.Metric, resources []pdata.Resource)
 }
 
 // LastValueAccumulator keeps last value for accumulated metrics
@@ -51,66 +55,115 @@ type lastValueAccumulator struct {
 	// metricExpiration contains duration for which metric
 	// should be served after it was stored
 	metricExpiration time.Duration
+	// metricExpirations override metricExpiration for the
+	// metrics matching their names
+	metricExpirations []metricExpiration
+	// expirations caches the index of the override of each metric
+	// name, -1 if none
+	expirations sync.Map
+}
+
+// metricExpiration is the expiration of the metrics matching names
+type metricExpiration struct {
+	names      filterset.FilterSet
+	expiration time.Duration
+}
+
+func newMetricExpirations(cfgs []MetricExpiration) ([]metricExpiration, error) {
+	expirations := make([]metricExpiration, 0, len(cfgs))
+	for _, cfg := range cfgs {
+		names, err := filterset.CreateFilterSet(cfg.MetricNames, &cfg.Config)
+		if err != nil {
+			return nil, err
+		}
+		expirations = append(expirations, metricExpiration{names: names, expiration: cfg.Expiration})
+	}
+	return expirations, nil
 }
 
 // NewAccumulator returns LastValueAccumulator
-func newAccumulator(logger *zap.Logger, metricExpiration time.Duration) accumulator {
+func newAccumulator(logger *zap.Logger, metricExpiration time.Duration, metricExpirations []metricExpiration) accumulator {
 	return &lastValueAccumulator{
-		logger:           logger,
-		metricExpiration: metricExpiration,
+		logger:            logger,
+		metricExpiration:  metricExpiration,
+		metricExpirations: metricExpirations,
+	}
+}
+
+// expirationOf returns the expiration of the metric name, the expiration
+// of the first matching override if any
+func (a *lastValueAccumulator) expirationOf(name string) time.Duration {
+	if len(a.metricExpirations) == 0 {
+		return a.metricExpiration
+	}
+	i, ok := a.expirations.Load(name)
+	if !ok {
+		i = -1
+		for j, e := range a.metricExpirations {
+			if e.names.Matches(name) {
+				i = j
+				break
+			}
+		}
+		a.expirations.Store(name, i)
+	}
+	if i.(int) < 0 {
+		return a.metricExpiration
 	}
+	return a.metricExpirations[i.(int)].expiration
 }
 
 // Accumulate stores one datapoint per metric
 func (a *lastValueAccumulator) Accumulate(rm pdata.ResourceMetrics) (n int) {
 	ilms := rm.InstrumentationLibraryMetrics()
+	resource := rm.Resource()
 
 	for i := 0; i < ilms.Len(); i++ {
 		ilm := ilms.At(i)
 
 		metrics := ilm.Metrics()
 		for j := 0; j < metrics.Len(); j++ {
-			n += a.addMetric(metrics.At(j), ilm.InstrumentationLibrary())
+			n += a.addMetric(metrics.At(j), ilm.InstrumentationLibrary(), resource)
 		}
 	}
 
 	return
 }
 
-func (a *lastValueAccumulator) addMetric(metric pdata.Metric, il pdata.InstrumentationLibrary) int {
+func (a *lastValueAccumulator) addMetric(metric pdata.Metric, il pdata.InstrumentationLibrary, resource pdata.Resource) int {
 	a.logger.Debug(fmt.Sprintf("accumulating metric: %s", metric.Name()))
 
 	switch metric.DataType() {
 	case pdata.MetricDataTypeIntGauge:
-		return a.accumulateIntGauge(metric, il)
+		return a.accumulateIntGauge(metric, il, resource)
 	case pdata.MetricDataTypeIntSum:
-		return a.accumulateIntSum(metric, il)
+		return a.accumulateIntSum(metric, il, resource)
 	case pdata.MetricDataTypeDoubleGauge:
-		return a.accumulateDoubleGauge(metric, il)
+		return a.accumulateDoubleGauge(metric, il, resource)
 	case pdata.MetricDataTypeDoubleSum:
-		return a.accumulateDoubleSum(metric, il)
+		return a.accumulateDoubleSum(metric, il, resource)
 	case pdata.MetricDataTypeIntHistogram:
-		return a.accumulateIntHistogram(metric, il)
+		return a.accumulateIntHistogram(metric, il, resource)
 	case pdata.MetricDataTypeDoubleHistogram:
-		return a.accumulateDoubleHistogram(metric, il)
+		return a.accumulateDoubleHistogram(metric, il, resource)
 	}
 
 	return 0
 }
 
-func (a *lastValueAccumulator) accumulateIntGauge(metric pdata.Metric, il pdata.InstrumentationLibrary) (n int) {
+func (a *lastValueAccumulator) accumulateIntGauge(metric pdata.Metric, il pdata.InstrumentationLibrary, resource pdata.Resource) (n int) {
 	dps := metric.IntGauge().DataPoints()
 	for i := 0; i < dps.Len(); i++ {
 		ip := dps.At(i)
 
 		ts := ip.Timestamp().AsTime()
-		signature := timeseriesSignature(il.Name(), metric, ip.LabelsMap())
+		signature := resourceSignature(resource) + timeseriesSignature(il.Name(), metric, ip.LabelsMap())
 
 		v, ok := a.registeredMetrics.Load(signature)
 		if !ok {
 			m := createMetric(metric)
 			m.IntGauge().DataPoints().Append(ip)
-			a.registeredMetrics.Store(signature, &accumulatedValue{value: m, instrumentationLibrary: il, stored: time.Now()})
+			a.registeredMetrics.Store(signature, &accumulatedValue{value: m, resource: resource, instrumentationLibrary: il, stored: time.Now()})
 			n++
 			continue
 		}
@@ -123,25 +176,33 @@ func (a *lastValueAccumulator) accumulateIntGauge(metric pdata.Metric, il pdata.
 
 		m := createMetric(metric)
 		m.IntGauge().DataPoints().Append(ip)
-		a.registeredMetrics.Store(signature, &accumulatedValue{value: m, instrumentationLibrary: il, stored: time.Now()})
+		a.registeredMetrics.Store(signature, &accumulatedValue{value: m, resource: resource, instrumentationLibrary: il, stored: time.Now()})
 		n++
 	}
 	return
 }
 
-func (a *lastValueAccumulator) accumulateDoubleGauge(metric pdata.Metric, il pdata.InstrumentationLibrary) (n int) {
+func (a *lastValueAccumulator) accumulateDoubleGauge(metric pdata.Metric, il pdata.InstrumentationLibrary, resource pdata.Resource) (n int) {
 	dps := metric.DoubleGauge().DataPoints()
 	for i := 0; i < dps.Len(); i++ {
 		ip := dps.At(i)
 
 		ts := ip.Timestamp().AsTime()
-		signature := timeseriesSignature(il.Name(), metric, ip.LabelsMap())
+		signature := resourceSignature(resource) + timeseriesSignature(il.Name(), metric, ip.LabelsMap())
 
 		v, ok := a.registeredMetrics.Load(signature)
+		if value.IsStaleNaN(ip.Value()) {
+			// The series is gone, stop serving it so that Prometheus marks it
+			// stale rather than keeping its last value.
+			if ok && !ts.Before(v.(*accumulatedValue).value.DoubleGauge().DataPoints().At(0).Timestamp().AsTime()) {
+				a.registeredMetrics.Delete(signature)
+			}
+			continue
+		}
 		if !ok {
 			m := createMetric(metric)
 			m.DoubleGauge().DataPoints().Append(ip)
-			a.registeredMetrics.Store(signature, &accumulatedValue{value: m, instrumentationLibrary: il, stored: time.Now()})
+			a.registeredMetrics.Store(signature, &accumulatedValue{value: m, resource: resource, instrumentationLibrary: il, stored: time.Now()})
 			n++
 			continue
 		}
@@ -154,13 +215,13 @@ func (a *lastValueAccumulator) accumulateDoubleGauge(metric pdata.Metric, il pda
 
 		m := createMetric(metric)
 		m.DoubleGauge().DataPoints().Append(ip)
-		a.registeredMetrics.Store(signature, &accumulatedValue{value: m, instrumentationLibrary: il, stored: time.Now()})
+		a.registeredMetrics.Store(signature, &accumulatedValue{value: m, resource: resource, instrumentationLibrary: il, stored: time.Now()})
 		n++
 	}
 	return
 }
 
-func (a *lastValueAccumulator) accumulateIntSum(metric pdata.Metric, il pdata.InstrumentationLibrary) (n int) {
+func (a *lastValueAccumulator) accumulateIntSum(metric pdata.Metric, il pdata.InstrumentationLibrary, resource pdata.Resource) (n int) {
 	intSum := metric.IntSum()
 
 	// Drop metrics with non-cumulative aggregations
@@ -173,7 +234,7 @@ func (a *lastValueAccumulator) accumulateIntSum(metric pdata.Metric, il pdata.In
 		ip := dps.At(i)
 
 		ts := ip.Timestamp().AsTime()
-		signature := timeseriesSignature(il.Name(), metric, ip.LabelsMap())
+		signature := resourceSignature(resource) + timeseriesSignature(il.Name(), metric, ip.LabelsMap())
 
 		v, ok := a.registeredMetrics.Load(signature)
 		if !ok {
@@ -181,7 +242,7 @@ func (a *lastValueAccumulator) accumulateIntSum(metric pdata.Metric, il pdata.In
 			m.IntSum().SetIsMonotonic(metric.IntSum().IsMonotonic())
 			m.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
 			m.IntSum().DataPoints().Append(ip)
-			a.registeredMetrics.Store(signature, &accumulatedValue{value: m, instrumentationLibrary: il, stored: time.Now()})
+			a.registeredMetrics.Store(signature, &accumulatedValue{value: m, resource: resource, instrumentationLibrary: il, stored: time.Now()})
 			n++
 			continue
 		}
@@ -196,13 +257,13 @@ func (a *lastValueAccumulator) accumulateIntSum(metric pdata.Metric, il pdata.In
 		m.IntSum().SetIsMonotonic(metric.IntSum().IsMonotonic())
 		m.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
 		m.IntSum().DataPoints().Append(ip)
-		a.registeredMetrics.Store(signature, &accumulatedValue{value: m, instrumentationLibrary: il, stored: time.Now()})
+		a.registeredMetrics.Store(signature, &accumulatedValue{value: m, resource: resource, instrumentationLibrary: il, stored: time.Now()})
 		n++
 	}
 	return
 }
 
-func (a *lastValueAccumulator) accumulateDoubleSum(metric pdata.Metric, il pdata.InstrumentationLibrary) (n int) {
+func (a *lastValueAccumulator) accumulateDoubleSum(metric pdata.Metric, il pdata.InstrumentationLibrary, resource pdata.Resource) (n int) {
 	doubleSum := metric.DoubleSum()
 
 	// Drop metrics with non-cumulative aggregations
@@ -215,15 +276,23 @@ func (a *lastValueAccumulator) accumulateDoubleSum(metric pdata.Metric, il pdata
 		ip := dps.At(i)
 
 		ts := ip.Timestamp().AsTime()
-		signature := timeseriesSignature(il.Name(), metric, ip.LabelsMap())
+		signature := resourceSignature(resource) + timeseriesSignature(il.Name(), metric, ip.LabelsMap())
 
 		v, ok := a.registeredMetrics.Load(signature)
+		if value.IsStaleNaN(ip.Value()) {
+			// The series is gone, stop serving it so that Prometheus marks it
+			// stale rather than keeping its last value.
+			if ok && !ts.Before(v.(*accumulatedValue).value.DoubleSum().DataPoints().At(0).Timestamp().AsTime()) {
+				a.registeredMetrics.Delete(signature)
+			}
+			continue
+		}
 		if !ok {
 			m := createMetric(metric)
 			m.DoubleSum().SetIsMonotonic(metric.DoubleSum().IsMonotonic())
 			m.DoubleSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
 			m.DoubleSum().DataPoints().Append(ip)
-			a.registeredMetrics.Store(signature, &accumulatedValue{value: m, instrumentationLibrary: il, stored: time.Now()})
+			a.registeredMetrics.Store(signature, &accumulatedValue{value: m, resource: resource, instrumentationLibrary: il, stored: time.Now()})
 			n++
 			continue
 		}
@@ -238,13 +307,13 @@ func (a *lastValueAccumulator) accumulateDoubleSum(metric pdata.Metric, il pdata
 		m.DoubleSum().SetIsMonotonic(metric.DoubleSum().IsMonotonic())
 		m.DoubleSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
 		m.DoubleSum().DataPoints().Append(ip)
-		a.registeredMetrics.Store(signature, &accumulatedValue{value: m, instrumentationLibrary: il, stored: time.Now()})
+		a.registeredMetrics.Store(signature, &accumulatedValue{value: m, resource: resource, instrumentationLibrary: il, stored: time.Now()})
 		n++
 	}
 	return
 }
 
-func (a *lastValueAccumulator) accumulateIntHistogram(metric pdata.Metric, il pdata.InstrumentationLibrary) (n int) {
+func (a *lastValueAccumulator) accumulateIntHistogram(metric pdata.Metric, il pdata.InstrumentationLibrary, resource pdata.Resource) (n int) {
 	intHistogram := metric.IntHistogram()
 
 	// Drop metrics with non-cumulative aggregations
@@ -257,13 +326,13 @@ func (a *lastValueAccumulator) accumulateIntHistogram(metric pdata.Metric, il pd
 		ip := dps.At(i)
 
 		ts := ip.Timestamp().AsTime()
-		signature := timeseriesSignature(il.Name(), metric, ip.LabelsMap())
+		signature := resourceSignature(resource) + timeseriesSignature(il.Name(), metric, ip.LabelsMap())
 
 		v, ok := a.registeredMetrics.Load(signature)
 		if !ok {
 			m := createMetric(metric)
 			m.IntHistogram().DataPoints().Append(ip)
-			a.registeredMetrics.Store(signature, &accumulatedValue{value: m, instrumentationLibrary: il, stored: time.Now()})
+			a.registeredMetrics.Store(signature, &accumulatedValue{value: m, resource: resource, instrumentationLibrary: il, stored: time.Now()})
 			n++
 			continue
 		}
@@ -277,13 +346,13 @@ func (a *lastValueAccumulator) accumulateIntHistogram(metric pdata.Metric, il pd
 		m := createMetric(metric)
 		m.IntHistogram().DataPoints().Append(ip)
 		m.IntHistogram().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
-		a.registeredMetrics.Store(signature, &accumulatedValue{value: m, instrumentationLibrary: il, stored: time.Now()})
+		a.registeredMetrics.Store(signature, &accumulatedValue{value: m, resource: resource, instrumentationLibrary: il, stored: time.Now()})
 		n++
 	}
 	return
 }
 
-func (a *lastValueAccumulator) accumulateDoubleHistogram(metric pdata.Metric, il pdata.InstrumentationLibrary) (n int) {
+func (a *lastValueAccumulator) accumulateDoubleHistogram(metric pdata.Metric, il pdata.InstrumentationLibrary, resource pdata.Resource) (n int) {
 	doubleHistogram := metric.DoubleHistogram()
 
 	// Drop metrics with non-cumulative aggregations
@@ -296,13 +365,13 @@ func (a *lastValueAccumulator) accumulateDoubleHistogram(metric pdata.Metric, il
 		ip := dps.At(i)
 
 		ts := ip.Timestamp().AsTime()
-		signature := timeseriesSignature(il.Name(), metric, ip.LabelsMap())
+		signature := resourceSignature(resource) + timeseriesSignature(il.Name(), metric, ip.LabelsMap())
 
 		v, ok := a.registeredMetrics.Load(signature)
 		if !ok {
 			m := createMetric(metric)
 			m.DoubleHistogram().DataPoints().Append(ip)
-			a.registeredMetrics.Store(signature, &accumulatedValue{value: m, instrumentationLibrary: il, stored: time.Now()})
+			a.registeredMetrics.Store(signature, &accumulatedValue{value: m, resource: resource, instrumentationLibrary: il, stored: time.Now()})
 			n++
 			continue
 		}
@@ -316,31 +385,33 @@ func (a *lastValueAccumulator) accumulateDoubleHistogram(metric pdata.Metric, il
 		m := createMetric(metric)
 		m.DoubleHistogram().DataPoints().Append(ip)
 		m.DoubleHistogram().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
-		a.registeredMetrics.Store(signature, &accumulatedValue{value: m, instrumentationLibrary: il, stored: time.Now()})
+		a.registeredMetrics.Store(signature, &accumulatedValue{value: m, resource: resource, instrumentationLibrary: il, stored: time.Now()})
 		n++
 	}
 	return
 }
 
-// Collect returns a slice with relevant aggregated metrics
-func (a *lastValueAccumulator) Collect() []pdata.Metric {
+// Collect returns a slice with relevant aggregated metrics and their resources
+func (a *lastValueAccumulator) Collect() ([]pdata.Metric, []pdata.Resource) {
 	a.logger.Debug("Accumulator collect called")
 
 	res := make([]pdata.Metric, 0)
+	resources := make([]pdata.Resource, 0)
 
 	a.registeredMetrics.Range(func(key, value interface{}) bool {
 		v := value.(*accumulatedValue)
-		if time.Now().After(v.stored.Add(a.metricExpiration)) {
+		if time.Now().After(v.stored.Add(a.expirationOf(v.value.Name()))) {
 			a.logger.Debug(fmt.Sprintf("metric expired: %s", v.value.Name()))
 			a.registeredMetrics.Delete(key)
 			return true
 		}
 
 		res = append(res, v.value)
+		resources = append(resources, v.resource)
 		return true
 	})
 
-	return res
+	return res, resources
 }
 
 func timeseriesSignature(ilmName string, metric pdata.Metric, labels pdata.StringMap) string {
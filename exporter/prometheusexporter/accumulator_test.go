@@ -16,20 +16,23 @@ package prometheusexporter
 
 import (
 	"log"
+	"math"
 	"testing"
 	"time"
 
+	"github.com/prometheus/prometheus/pkg/value"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 
 	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal/processor/filterset"
 )
 
 func TestInvalidDataType(t *testing.T) {
-	a := newAccumulator(zap.NewNop(), 1*time.Hour).(*lastValueAccumulator)
+	a := newAccumulator(zap.NewNop(), 1*time.Hour, nil).(*lastValueAccumulator)
 	metric := pdata.NewMetric()
 	metric.SetDataType(-100)
-	n := a.addMetric(metric, pdata.NewInstrumentationLibrary())
+	n := a.addMetric(metric, pdata.NewInstrumentationLibrary(), pdata.NewResource())
 	require.Zero(t, n)
 }
 
@@ -130,7 +133,7 @@ func TestAccumulateDeltaAggregation(t *testing.T) {
 			resourceMetrics.InstrumentationLibraryMetrics().Append(ilm)
 			ilm.Metrics().Append(m)
 
-			a := newAccumulator(zap.NewNop(), 1*time.Hour).(*lastValueAccumulator)
+			a := newAccumulator(zap.NewNop(), 1*time.Hour, nil).(*lastValueAccumulator)
 			n := a.Accumulate(resourceMetrics)
 			require.Equal(t, 0, n)
 
@@ -327,7 +330,7 @@ func TestAccumulateMetrics(t *testing.T) {
 			ilm.Metrics().Append(m2)
 			ilm.Metrics().Append(m1)
 
-			a := newAccumulator(zap.NewNop(), 1*time.Hour).(*lastValueAccumulator)
+			a := newAccumulator(zap.NewNop(), 1*time.Hour, nil).(*lastValueAccumulator)
 
 			// 2 metric arrived
 			n := a.Accumulate(resourceMetrics)
@@ -427,3 +430,99 @@ func getMerticProperties(metric pdata.Metric) (
 
 	return
 }
+
+func TestAccumulateStaleMarker(t *testing.T) {
+	for _, dataType := range []pdata.MetricDataType{pdata.MetricDataTypeDoubleGauge, pdata.MetricDataTypeDoubleSum} {
+		t.Run(dataType.String(), func(t *testing.T) {
+			newMetric := func(ts time.Time, v float64) pdata.Metric {
+				dp := pdata.NewDoubleDataPoint()
+				dp.SetValue(v)
+				dp.LabelsMap().Insert("label_1", "1")
+				dp.SetTimestamp(pdata.TimestampFromTime(ts))
+
+				metric := pdata.NewMetric()
+				metric.SetName("test_metric")
+				metric.SetDataType(dataType)
+				if dataType == pdata.MetricDataTypeDoubleGauge {
+					metric.DoubleGauge().DataPoints().Append(dp)
+				} else {
+					metric.DoubleSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+					metric.DoubleSum().DataPoints().Append(dp)
+				}
+				return metric
+			}
+			accumulate := func(a *lastValueAccumulator, metric pdata.Metric) int {
+				resourceMetrics := pdata.NewResourceMetrics()
+				resourceMetrics.InstrumentationLibraryMetrics().Resize(1)
+				resourceMetrics.InstrumentationLibraryMetrics().At(0).Metrics().Append(metric)
+				return a.Accumulate(resourceMetrics)
+			}
+
+			a := newAccumulator(zap.NewNop(), 1*time.Hour, nil).(*lastValueAccumulator)
+			ts := time.Now()
+			require.Equal(t, 1, accumulate(a, newMetric(ts, 42)))
+
+			// A stale marker older than the stored value is ignored.
+			require.Equal(t, 0, accumulate(a, newMetric(ts.Add(-time.Second), math.Float64frombits(value.StaleNaN))))
+			metrics, _ := a.Collect()
+			require.Len(t, metrics, 1)
+
+			// A newer stale marker removes the series.
+			require.Equal(t, 0, accumulate(a, newMetric(ts.Add(time.Second), math.Float64frombits(value.StaleNaN))))
+			metrics, _ = a.Collect()
+			require.Len(t, metrics, 0)
+
+			// The series is served again once it has a new value.
+			require.Equal(t, 1, accumulate(a, newMetric(ts.Add(2*time.Second), 43)))
+			metrics, _ = a.Collect()
+			require.Len(t, metrics, 1)
+		})
+	}
+}
+
+func TestMetricExpirations(t *testing.T) {
+	expirations, err := newMetricExpirations([]MetricExpiration{
+		{
+			Config:      filterset.Config{MatchType: filterset.Regexp},
+			MetricNames: []string{"^k8s_pod_.*"},
+			Expiration:  time.Minute,
+		},
+		{
+			Config:      filterset.Config{MatchType: filterset.Strict},
+			MetricNames: []string{"k8s_pod_phase", "build_info"},
+			Expiration:  24 * time.Hour,
+		},
+	})
+	require.NoError(t, err)
+
+	a := newAccumulator(zap.NewNop(), 5*time.Minute, expirations).(*lastValueAccumulator)
+	// The first matching override applies.
+	require.Equal(t, time.Minute, a.expirationOf("k8s_pod_phase"))
+	require.Equal(t, time.Minute, a.expirationOf("k8s_pod_restarts"))
+	require.Equal(t, 24*time.Hour, a.expirationOf("build_info"))
+	require.Equal(t, 5*time.Minute, a.expirationOf("http_requests"))
+	// The cached results are the same.
+	require.Equal(t, time.Minute, a.expirationOf("k8s_pod_restarts"))
+	require.Equal(t, 5*time.Minute, a.expirationOf("http_requests"))
+
+	for _, name := range []string{"k8s_pod_restarts", "http_requests"} {
+		dp := pdata.NewIntDataPoint()
+		dp.SetTimestamp(pdata.TimestampFromTime(time.Now()))
+		metric := pdata.NewMetric()
+		metric.SetName(name)
+		metric.SetDataType(pdata.MetricDataTypeIntGauge)
+		metric.IntGauge().DataPoints().Append(dp)
+		require.Equal(t, 1, a.addMetric(metric, pdata.NewInstrumentationLibrary(), pdata.NewResource()))
+	}
+	// Age the stored values by two minutes.
+	a.registeredMetrics.Range(func(_, v interface{}) bool {
+		v.(*accumulatedValue).stored = v.(*accumulatedValue).stored.Add(-2 * time.Minute)
+		return true
+	})
+	metrics, _ := a.Collect()
+	require.Len(t, metrics, 1)
+	require.Equal(t, "http_requests", metrics[0].Name())
+
+	_, err = newMetricExpirations([]MetricExpiration{{MetricNames: []string{"a"}}})
+	require.Error(t, err)
+}
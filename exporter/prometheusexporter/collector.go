@@ -29,18 +29,24 @@ type collector struct {
 	logger      *zap.Logger
 
 	sendTimestamps bool
+	targetInfo     bool
 	namespace      string
 	constLabels    prometheus.Labels
 }
 
-func newCollector(config *Config, logger *zap.Logger) *collector {
+func newCollector(config *Config, logger *zap.Logger) (*collector, error) {
+	metricExpirations, err := newMetricExpirations(config.MetricExpirations)
+	if err != nil {
+		return nil, err
+	}
 	return &collector{
-		accumulator:    newAccumulator(logger, config.MetricExpiration),
+		accumulator:    newAccumulator(logger, config.MetricExpiration, metricExpirations),
 		logger:         logger,
 		namespace:      sanitize(config.Namespace),
 		sendTimestamps: config.SendTimestamps,
+		targetInfo:     config.TargetInfo,
 		constLabels:    config.ConstLabels,
-	}
+	}, nil
 }
 
 // Collector dynamically allocates metrics, describe shoud be noop
@@ -56,20 +62,20 @@ func (c *collector) processMetrics(rm pdata.ResourceMetrics) (n int) {
 
 var errUnknownMetricType = fmt.Errorf("unknown metric type")
 
-func (c *collector) convertMetric(metric pdata.Metric) (prometheus.Metric, error) {
+func (c *collector) convertMetric(metric pdata.Metric, resource pdata.Resource) (prometheus.Metric, error) {
 	switch metric.DataType() {
 	case pdata.MetricDataTypeIntGauge:
-		return c.convertIntGauge(metric)
+		return c.convertIntGauge(metric, resource)
 	case pdata.MetricDataTypeIntSum:
-		return c.convertIntSum(metric)
+		return c.convertIntSum(metric, resource)
 	case pdata.MetricDataTypeDoubleGauge:
-		return c.convertDoubleGauge(metric)
+		return c.convertDoubleGauge(metric, resource)
 	case pdata.MetricDataTypeDoubleSum:
-		return c.convertDoubleSum(metric)
+		return c.convertDoubleSum(metric, resource)
 	case pdata.MetricDataTypeIntHistogram:
-		return c.convertIntHistogram(metric)
+		return c.convertIntHistogram(metric, resource)
 	case pdata.MetricDataTypeDoubleHistogram:
-		return c.convertDoubleHistogram(metric)
+		return c.convertDoubleHistogram(metric, resource)
 	}
 
 	return nil, errUnknownMetricType
@@ -82,15 +88,34 @@ func metricName(namespace string, metric pdata.Metric) string {
 	return sanitize(metric.Name())
 }
 
-func (c *collector) getMetricMetadata(metric pdata.Metric, labels pdata.StringMap) (*prometheus.Desc, []string) {
-	keys := make([]string, 0, labels.Len())
-	values := make([]string, 0, labels.Len())
+func (c *collector) getMetricMetadata(metric pdata.Metric, labels pdata.StringMap, resource pdata.Resource) (*prometheus.Desc, []string) {
+	keys := make([]string, 0, labels.Len()+2)
+	values := make([]string, 0, labels.Len()+2)
 
 	labels.ForEach(func(k string, v string) {
 		keys = append(keys, k)
 		values = append(values, v)
 	})
 
+	if c.targetInfo {
+		// The labels of the data point and the constant labels take precedence
+		// over the job and instance of the resource.
+		job, instance := jobAndInstance(resource)
+		for _, l := range []struct{ key, value string }{{jobLabel, job}, {instanceLabel, instance}} {
+			if l.value == "" {
+				continue
+			}
+			if _, ok := labels.Get(l.key); ok {
+				continue
+			}
+			if _, ok := c.constLabels[l.key]; ok {
+				continue
+			}
+			keys = append(keys, l.key)
+			values = append(values, l.value)
+		}
+	}
+
 	return prometheus.NewDesc(
 		metricName(c.namespace, metric),
 		metric.Description(),
@@ -99,10 +124,10 @@ func (c *collector) getMetricMetadata(metric pdata.Metric, labels pdata.StringMa
 	), values
 }
 
-func (c *collector) convertIntGauge(metric pdata.Metric) (prometheus.Metric, error) {
+func (c *collector) convertIntGauge(metric pdata.Metric, resource pdata.Resource) (prometheus.Metric, error) {
 	ip := metric.IntGauge().DataPoints().At(0)
 
-	desc, labels := c.getMetricMetadata(metric, ip.LabelsMap())
+	desc, labels := c.getMetricMetadata(metric, ip.LabelsMap(), resource)
 	m, err := prometheus.NewConstMetric(desc, prometheus.GaugeValue, float64(ip.Value()), labels...)
 	if err != nil {
 		return nil, err
@@ -114,10 +139,10 @@ func (c *collector) convertIntGauge(metric pdata.Metric) (prometheus.Metric, err
 	return m, nil
 }
 
-func (c *collector) convertDoubleGauge(metric pdata.Metric) (prometheus.Metric, error) {
+func (c *collector) convertDoubleGauge(metric pdata.Metric, resource pdata.Resource) (prometheus.Metric, error) {
 	ip := metric.DoubleGauge().DataPoints().At(0)
 
-	desc, labels := c.getMetricMetadata(metric, ip.LabelsMap())
+	desc, labels := c.getMetricMetadata(metric, ip.LabelsMap(), resource)
 	m, err := prometheus.NewConstMetric(desc, prometheus.GaugeValue, ip.Value(), labels...)
 	if err != nil {
 		return nil, err
@@ -129,7 +154,7 @@ func (c *collector) convertDoubleGauge(metric pdata.Metric) (prometheus.Metric,
 	return m, nil
 }
 
-func (c *collector) convertIntSum(metric pdata.Metric) (prometheus.Metric, error) {
+func (c *collector) convertIntSum(metric pdata.Metric, resource pdata.Resource) (prometheus.Metric, error) {
 	ip := metric.IntSum().DataPoints().At(0)
 
 	metricType := prometheus.GaugeValue
@@ -137,7 +162,7 @@ func (c *collector) convertIntSum(metric pdata.Metric) (prometheus.Metric, error
 		metricType = prometheus.CounterValue
 	}
 
-	desc, labels := c.getMetricMetadata(metric, ip.LabelsMap())
+	desc, labels := c.getMetricMetadata(metric, ip.LabelsMap(), resource)
 	m, err := prometheus.NewConstMetric(desc, metricType, float64(ip.Value()), labels...)
 	if err != nil {
 		return nil, err
@@ -149,7 +174,7 @@ func (c *collector) convertIntSum(metric pdata.Metric) (prometheus.Metric, error
 	return m, nil
 }
 
-func (c *collector) convertDoubleSum(metric pdata.Metric) (prometheus.Metric, error) {
+func (c *collector) convertDoubleSum(metric pdata.Metric, resource pdata.Resource) (prometheus.Metric, error) {
 	ip := metric.DoubleSum().DataPoints().At(0)
 
 	metricType := prometheus.GaugeValue
@@ -157,7 +182,7 @@ func (c *collector) convertDoubleSum(metric pdata.Metric) (prometheus.Metric, er
 		metricType = prometheus.CounterValue
 	}
 
-	desc, labels := c.getMetricMetadata(metric, ip.LabelsMap())
+	desc, labels := c.getMetricMetadata(metric, ip.LabelsMap(), resource)
 	m, err := prometheus.NewConstMetric(desc, metricType, ip.Value(), labels...)
 	if err != nil {
 		return nil, err
@@ -169,9 +194,9 @@ func (c *collector) convertDoubleSum(metric pdata.Metric) (prometheus.Metric, er
 	return m, nil
 }
 
-func (c *collector) convertIntHistogram(metric pdata.Metric) (prometheus.Metric, error) {
+func (c *collector) convertIntHistogram(metric pdata.Metric, resource pdata.Resource) (prometheus.Metric, error) {
 	ip := metric.IntHistogram().DataPoints().At(0)
-	desc, labels := c.getMetricMetadata(metric, ip.LabelsMap())
+	desc, labels := c.getMetricMetadata(metric, ip.LabelsMap(), resource)
 
 	indicesMap := make(map[float64]int)
 	buckets := make([]float64, 0, len(ip.BucketCounts()))
@@ -207,9 +232,9 @@ func (c *collector) convertIntHistogram(metric pdata.Metric) (prometheus.Metric,
 	return m, nil
 }
 
-func (c *collector) convertDoubleHistogram(metric pdata.Metric) (prometheus.Metric, error) {
+func (c *collector) convertDoubleHistogram(metric pdata.Metric, resource pdata.Resource) (prometheus.Metric, error) {
 	ip := metric.DoubleHistogram().DataPoints().At(0)
-	desc, labels := c.getMetricMetadata(metric, ip.LabelsMap())
+	desc, labels := c.getMetricMetadata(metric, ip.LabelsMap(), resource)
 
 	indicesMap := make(map[float64]int)
 	buckets := make([]float64, 0, len(ip.BucketCounts()))
@@ -251,10 +276,11 @@ func (c *collector) convertDoubleHistogram(metric pdata.Metric) (prometheus.Metr
 func (c *collector) Collect(ch chan<- prometheus.Metric) {
 	c.logger.Debug("collect called")
 
-	inMetrics := c.accumulator.Collect()
+	inMetrics, resources := c.accumulator.Collect()
 
-	for _, pMetric := range inMetrics {
-		m, err := c.convertMetric(pMetric)
+	targets := map[string]bool{}
+	for i, pMetric := range inMetrics {
+		m, err := c.convertMetric(pMetric, resources[i])
 		if err != nil {
 			c.logger.Error(fmt.Sprintf("failed to convert metric %s: %s", pMetric.Name(), err.Error()))
 			continue
@@ -262,5 +288,21 @@ func (c *collector) Collect(ch chan<- prometheus.Metric) {
 
 		ch <- m
 		c.logger.Debug(fmt.Sprintf("metric served: %s", m.Desc().String()))
+
+		if !c.targetInfo {
+			continue
+		}
+		// Serve the target_info metric once per resource.
+		target := resourceSignature(resources[i])
+		if target == "" || targets[target] {
+			continue
+		}
+		targets[target] = true
+		info, err := c.convertTargetInfo(resources[i])
+		if err != nil {
+			c.logger.Error(fmt.Sprintf("failed to convert the target_info metric: %s", err.Error()))
+			continue
+		}
+		ch <- info
 	}
 }
@@ -33,9 +33,12 @@ type mockAccumulator struct {
 func (a *mockAccumulator) Accumulate(rm pdata.ResourceMetrics) (n int) {
 	return 0
 }
-func (a *mockAccumulator) Collect() []pdata.Metric {
-
-	return a.metrics
+func (a *mockAccumulator) Collect() ([]pdata.Metric, []pdata.Resource) {
+	resources := make([]pdata.Resource, len(a.metrics))
+	for i := range resources {
+		resources[i] = pdata.NewResource()
+	}
+	return a.metrics, resources
 }
 
 func TestConvertInvalidDataType(t *testing.T) {
@@ -48,7 +51,7 @@ func TestConvertInvalidDataType(t *testing.T) {
 		logger: zap.NewNop(),
 	}
 
-	_, err := c.convertMetric(metric)
+	_, err := c.convertMetric(metric, pdata.NewResource())
 	require.Equal(t, errUnknownMetricType, err)
 
 	ch := make(chan prometheus.Metric, 1)
@@ -91,7 +94,7 @@ func TestConvertInvalidMetric(t *testing.T) {
 		}
 		c := collector{}
 
-		_, err := c.convertMetric(metric)
+		_, err := c.convertMetric(metric, pdata.NewResource())
 		require.Error(t, err)
 	}
 }
@@ -20,6 +20,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 
 	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/internal/processor/filterset"
 )
 
 // Config defines configuration for Prometheus exporter.
@@ -40,4 +41,24 @@ type Config struct {
 
 	// MetricExpiration defines how long metrics are kept without updates
 	MetricExpiration time.Duration `mapstructure:"metric_expiration"`
+
+	// MetricExpirations overrides MetricExpiration for the metrics matching their names,
+	// the first matching override applies.
+	MetricExpirations []MetricExpiration `mapstructure:"metric_expirations"`
+
+	// TargetInfo, if true, exports the job and instance labels of the metrics from the
+	// service.namespace, service.name and service.instance.id resource attributes, and the
+	// other resource attributes as the labels of a target_info metric per resource.
+	TargetInfo bool `mapstructure:"target_info"`
+}
+
+// MetricExpiration defines how long the metrics matching MetricNames are kept without updates.
+type MetricExpiration struct {
+	filterset.Config `mapstructure:",squash"`
+
+	// MetricNames are the patterns the metric names are matched against.
+	MetricNames []string `mapstructure:"metric_names"`
+
+	// Expiration defines how long the matching metrics are kept without updates.
+	Expiration time.Duration `mapstructure:"expiration"`
 }
@@ -25,6 +25,7 @@ import (
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/config/configtest"
+	"go.opentelemetry.io/collector/internal/processor/filterset"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -56,5 +57,12 @@ func TestLoadConfig(t *testing.T) {
 			},
 			SendTimestamps:   true,
 			MetricExpiration: 60 * time.Minute,
+			MetricExpirations: []MetricExpiration{
+				{
+					Config:      filterset.Config{MatchType: filterset.Regexp},
+					MetricNames: []string{"^k8s_pod_.*"},
+					Expiration:  time.Minute,
+				},
+			},
 		})
 }
@@ -44,6 +44,7 @@ func createDefaultConfig() configmodels.Exporter {
 		ConstLabels:      map[string]string{},
 		SendTimestamps:   false,
 		MetricExpiration: time.Minute * 5,
+		TargetInfo:       true,
 	}
 }
 
@@ -51,7 +51,10 @@ func newPrometheusExporter(config *Config, logger *zap.Logger) (*prometheusExpor
 
 	obsrep := obsreport.NewExporter(configtelemetry.GetMetricsLevelFlagValue(), config.Name())
 
-	collector := newCollector(config, logger)
+	collector, err := newCollector(config, logger)
+	if err != nil {
+		return nil, err
+	}
 	registry := prometheus.NewRegistry()
 	_ = registry.Register(collector)
 
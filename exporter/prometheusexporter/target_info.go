@@ -0,0 +1,110 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheusexporter
+
+import (
+	"sort"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+)
+
+const (
+	targetInfoName        = "target_info"
+	targetInfoDescription = "Target metadata"
+
+	jobLabel      = "job"
+	instanceLabel = "instance"
+)
+
+// jobAndInstance returns the job and instance labels of the resource, as
+// defined by the Prometheus compatibility of the specification: the job is
+// the service.name, prefixed with the service.namespace and a slash if any,
+// and the instance is the service.instance.id.
+func jobAndInstance(resource pdata.Resource) (job string, instance string) {
+	attrs := resource.Attributes()
+	if name, ok := attrs.Get(conventions.AttributeServiceName); ok {
+		job = name.StringVal()
+		if namespace, ok := attrs.Get(conventions.AttributeServiceNamespace); ok && namespace.StringVal() != "" {
+			job = namespace.StringVal() + "/" + job
+		}
+	}
+	if id, ok := attrs.Get(conventions.AttributeServiceInstance); ok {
+		instance = id.StringVal()
+	}
+	return job, instance
+}
+
+// resourceSignature returns the part of the signature of the time series
+// identifying its resource, empty when the resource has no job and instance.
+func resourceSignature(resource pdata.Resource) string {
+	job, instance := jobAndInstance(resource)
+	if job == "" && instance == "" {
+		return ""
+	}
+	return job + "*" + instance + "*"
+}
+
+// isIdentifyingAttribute returns whether the attribute is already exported as
+// the job or instance label.
+func isIdentifyingAttribute(key string) bool {
+	return key == conventions.AttributeServiceName ||
+		key == conventions.AttributeServiceNamespace ||
+		key == conventions.AttributeServiceInstance
+}
+
+// convertTargetInfo returns the target_info metric of the resource: a gauge
+// of value 1 with the job and instance labels, and a label for each of the
+// other resource attributes, so that they can be joined with the metrics of
+// the resource. It returns nil when the resource has no job and instance.
+func (c *collector) convertTargetInfo(resource pdata.Resource) (prometheus.Metric, error) {
+	job, instance := jobAndInstance(resource)
+	if job == "" && instance == "" {
+		return nil, nil
+	}
+
+	labels := map[string]string{}
+	resource.Attributes().ForEach(func(k string, v pdata.AttributeValue) {
+		if isIdentifyingAttribute(k) {
+			return
+		}
+		labels[sanitize(k)] = tracetranslator.AttributeValueToString(v, false)
+	})
+	if job != "" {
+		labels[jobLabel] = job
+	}
+	if instance != "" {
+		labels[instanceLabel] = instance
+	}
+	for k := range c.constLabels {
+		delete(labels, k)
+	}
+
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	values := make([]string, 0, len(keys))
+	for _, k := range keys {
+		values = append(values, labels[k])
+	}
+
+	desc := prometheus.NewDesc(targetInfoName, targetInfoDescription, keys, c.constLabels)
+	return prometheus.NewConstMetric(desc, prometheus.GaugeValue, 1, values...)
+}
@@ -0,0 +1,159 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheusexporter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	io_prometheus_client "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+func newResourceMetrics(attrs map[string]string, labels map[string]string) pdata.ResourceMetrics {
+	rm := pdata.NewResourceMetrics()
+	for k, v := range attrs {
+		rm.Resource().Attributes().InsertString(k, v)
+	}
+	rm.InstrumentationLibraryMetrics().Resize(1)
+	metrics := rm.InstrumentationLibraryMetrics().At(0).Metrics()
+	metrics.Resize(1)
+	metric := metrics.At(0)
+	metric.SetName("requests")
+	metric.SetDataType(pdata.MetricDataTypeIntGauge)
+	metric.IntGauge().DataPoints().Resize(1)
+	dp := metric.IntGauge().DataPoints().At(0)
+	dp.SetValue(7)
+	dp.SetTimestamp(pdata.TimestampFromTime(time.Now()))
+	dp.LabelsMap().InitFromMap(labels)
+	return rm
+}
+
+func gather(t *testing.T, c *collector) map[string][]map[string]string {
+	registry := prometheus.NewRegistry()
+	require.NoError(t, registry.Register(c))
+	families, err := registry.Gather()
+	require.NoError(t, err)
+
+	gathered := map[string][]map[string]string{}
+	for _, family := range families {
+		for _, m := range family.Metric {
+			gathered[family.GetName()] = append(gathered[family.GetName()], labelsOf(m))
+		}
+	}
+	return gathered
+}
+
+func labelsOf(m *io_prometheus_client.Metric) map[string]string {
+	labels := map[string]string{}
+	for _, l := range m.Label {
+		labels[l.GetName()] = l.GetValue()
+	}
+	return labels
+}
+
+func TestJobAndInstance(t *testing.T) {
+	tests := []struct {
+		name     string
+		attrs    map[string]string
+		job      string
+		instance string
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name:     "name and instance",
+			attrs:    map[string]string{conventions.AttributeServiceName: "checkout", conventions.AttributeServiceInstance: "pod-1"},
+			job:      "checkout",
+			instance: "pod-1",
+		},
+		{
+			name:  "namespace",
+			attrs: map[string]string{conventions.AttributeServiceName: "checkout", conventions.AttributeServiceNamespace: "shop"},
+			job:   "shop/checkout",
+		},
+		{
+			name:  "namespace without name",
+			attrs: map[string]string{conventions.AttributeServiceNamespace: "shop"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resource := pdata.NewResource()
+			for k, v := range tt.attrs {
+				resource.Attributes().InsertString(k, v)
+			}
+			job, instance := jobAndInstance(resource)
+			assert.Equal(t, tt.job, job)
+			assert.Equal(t, tt.instance, instance)
+		})
+	}
+}
+
+func TestCollectTargetInfo(t *testing.T) {
+	c, err := newCollector(&Config{TargetInfo: true, MetricExpiration: time.Hour, ConstLabels: prometheus.Labels{"region": "eu"}}, zap.NewNop())
+	require.NoError(t, err)
+
+	c.processMetrics(newResourceMetrics(map[string]string{
+		conventions.AttributeServiceName:     "checkout",
+		conventions.AttributeServiceInstance: "pod-1",
+		"k8s.pod.name":                       "checkout-1",
+		"region":                             "us",
+	}, map[string]string{"path": "/"}))
+	// The same metric of another instance is a separate time series.
+	c.processMetrics(newResourceMetrics(map[string]string{
+		conventions.AttributeServiceName:     "checkout",
+		conventions.AttributeServiceInstance: "pod-2",
+	}, map[string]string{"path": "/"}))
+	// The labels of the data point take precedence.
+	c.processMetrics(newResourceMetrics(map[string]string{
+		conventions.AttributeServiceName: "cart",
+	}, map[string]string{"job": "batch"}))
+	// A resource without service has no job, no instance and no target_info.
+	c.processMetrics(newResourceMetrics(map[string]string{"host.name": "node-1"}, map[string]string{"path": "/health"}))
+
+	gathered := gather(t, c)
+	assert.ElementsMatch(t, []map[string]string{
+		{"path": "/", "job": "checkout", "instance": "pod-1", "region": "eu"},
+		{"path": "/", "job": "checkout", "instance": "pod-2", "region": "eu"},
+		{"job": "batch", "region": "eu"},
+		{"path": "/health", "region": "eu"},
+	}, gathered["requests"])
+	assert.ElementsMatch(t, []map[string]string{
+		{"job": "checkout", "instance": "pod-1", "k8s_pod_name": "checkout-1", "region": "eu"},
+		{"job": "checkout", "instance": "pod-2", "region": "eu"},
+		{"job": "cart", "region": "eu"},
+	}, gathered[targetInfoName])
+}
+
+func TestCollectWithoutTargetInfo(t *testing.T) {
+	c, err := newCollector(&Config{MetricExpiration: time.Hour}, zap.NewNop())
+	require.NoError(t, err)
+	c.processMetrics(newResourceMetrics(map[string]string{
+		conventions.AttributeServiceName:     "checkout",
+		conventions.AttributeServiceInstance: "pod-1",
+	}, map[string]string{"path": "/"}))
+
+	gathered := gather(t, c)
+	assert.Equal(t, []map[string]string{{"path": "/"}}, gathered["requests"])
+	assert.NotContains(t, gathered, targetInfoName)
+}
@@ -36,4 +36,19 @@ type Config struct {
 	ExternalLabels map[string]string `mapstructure:"external_labels"`
 
 	HTTPClientSettings confighttp.HTTPClientSettings `mapstructure:",squash"`
+
+	// WAL, if set, persists the requests in a write-ahead log before they are sent, one at a
+	// time and in order, retrying them until they are accepted or permanently rejected.
+	WAL *WALConfig `mapstructure:"wal"`
+}
+
+// WALConfig defines the write-ahead log of the requests.
+type WALConfig struct {
+	// Directory is where the write-ahead log is kept.
+	Directory string `mapstructure:"directory"`
+
+	// MaxSegmentMegabytes is the size in megabytes after which a new segment file of the
+	// write-ahead log is started, the segments are removed once all their requests are sent.
+	// Defaults to 64.
+	MaxSegmentMegabytes int `mapstructure:"max_segment_megabytes"`
 }
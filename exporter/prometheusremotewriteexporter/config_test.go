@@ -84,4 +84,10 @@ func Test_loadConfig(t *testing.T) {
 					"x-scope-orgid":                   "234"},
 			},
 		})
+
+	e2 := cfg.Exporters["prometheusremotewrite/wal"].(*Config)
+	assert.Equal(t, &WALConfig{
+		Directory:           "/var/lib/otelcol/prw",
+		MaxSegmentMegabytes: 16,
+	}, e2.WAL)
 }
@@ -50,6 +50,7 @@ type PrwExporter struct {
 	client         *http.Client
 	wg             *sync.WaitGroup
 	closeChan      chan struct{}
+	walSender      *walSender
 }
 
 // NewPrwExporter initializes a new PrwExporter instance and sets fields accordingly.
@@ -84,6 +85,9 @@ func NewPrwExporter(namespace string, endpoint string, client *http.Client, exte
 func (prwe *PrwExporter) Shutdown(context.Context) error {
 	close(prwe.closeChan)
 	prwe.wg.Wait()
+	if prwe.walSender != nil {
+		return prwe.walSender.shutdown()
+	}
 	return nil
 }
 
@@ -148,7 +152,12 @@ func (prwe *PrwExporter) PushMetrics(ctx context.Context, md pdata.Metrics) (int
 			}
 		}
 
-		if exportErrors := prwe.export(ctx, tsMap); len(exportErrors) != 0 {
+		if prwe.walSender != nil {
+			if err := prwe.walSender.append(tsMap); err != nil {
+				dropped = md.MetricCount()
+				errs = append(errs, err)
+			}
+		} else if exportErrors := prwe.export(ctx, tsMap); len(exportErrors) != 0 {
 			dropped = md.MetricCount()
 			errs = append(errs, exportErrors...)
 		}
@@ -323,6 +332,7 @@ func (prwe *PrwExporter) execute(ctx context.Context, writeReq *prompb.WriteRequ
 	if err != nil {
 		return consumererror.Permanent(err)
 	}
+	defer resp.Body.Close()
 
 	// 2xx status code is considered a success
 	// 5xx errors are recoverable and the exporter should retry
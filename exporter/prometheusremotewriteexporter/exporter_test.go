@@ -31,6 +31,7 @@ import (
 
 	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
 	otlp "go.opentelemetry.io/collector/internal/data/protogen/metrics/v1"
@@ -229,6 +230,10 @@ func Test_export(t *testing.T) {
 			errs := runExportPipeline(ts1, serverURL)
 			if tt.returnError {
 				assert.Error(t, errs[0])
+				if !tt.serverUp {
+					// The connection errors are not retried by the sending queue.
+					assert.True(t, consumererror.IsPermanent(errs[0]))
+				}
 				return
 			}
 			assert.Len(t, errs, 0)
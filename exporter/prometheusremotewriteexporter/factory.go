@@ -54,6 +54,12 @@ func createMetricsExporter(_ context.Context, params component.ExporterCreatePar
 		return nil, err
 	}
 
+	if prwCfg.WAL != nil {
+		if err = prwe.enableWAL(prwCfg.WAL, prwCfg.RetrySettings, prwCfg.TimeoutSettings, params.Logger); err != nil {
+			return nil, err
+		}
+	}
+
 	prwexp, err := exporterhelper.NewMetricsExporter(
 		cfg,
 		params.Logger,
@@ -61,6 +67,7 @@ func createMetricsExporter(_ context.Context, params component.ExporterCreatePar
 		exporterhelper.WithTimeout(prwCfg.TimeoutSettings),
 		exporterhelper.WithQueue(prwCfg.QueueSettings),
 		exporterhelper.WithRetry(prwCfg.RetrySettings),
+		exporterhelper.WithStart(prwe.Start),
 		exporterhelper.WithShutdown(prwe.Shutdown),
 	)
 
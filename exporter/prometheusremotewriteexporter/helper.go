@@ -357,31 +357,8 @@ func addSingleIntHistogramDataPoint(pt *otlp.IntHistogramDataPoint, metric *otlp
 	countlabels := createLabelSet(pt.GetLabels(), externalLabels, nameStr, baseName+countStr)
 	addSample(tsMap, count, countlabels, metric)
 
-	// cumulative count for conversion to cumulative histogram
-	var cumulativeCount uint64
-
-	// process each bound, ignore extra bucket values
-	for index, bound := range pt.GetExplicitBounds() {
-		if index >= len(pt.GetBucketCounts()) {
-			break
-		}
-		cumulativeCount += pt.GetBucketCounts()[index]
-		bucket := &prompb.Sample{
-			Value:     float64(cumulativeCount),
-			Timestamp: time,
-		}
-		boundStr := strconv.FormatFloat(bound, 'f', -1, 64)
-		labels := createLabelSet(pt.GetLabels(), externalLabels, nameStr, baseName+bucketStr, leStr, boundStr)
-		addSample(tsMap, bucket, labels, metric)
-	}
-	// add le=+Inf bucket
-	cumulativeCount += pt.GetBucketCounts()[len(pt.GetBucketCounts())-1]
-	infBucket := &prompb.Sample{
-		Value:     float64(cumulativeCount),
-		Timestamp: time,
-	}
-	infLabels := createLabelSet(pt.GetLabels(), externalLabels, nameStr, baseName+bucketStr, leStr, pInfStr)
-	addSample(tsMap, infBucket, infLabels, metric)
+	addBucketSamples(pt.GetLabels(), pt.GetExplicitBounds(), pt.GetBucketCounts(), pt.GetCount(), time, metric,
+		baseName, tsMap, externalLabels)
 }
 
 // addSingleDoubleHistogramDataPoint converts pt to 2 + min(len(ExplicitBounds), len(BucketCount)) + 1 samples. It
@@ -411,30 +388,39 @@ func addSingleDoubleHistogramDataPoint(pt *otlp.DoubleHistogramDataPoint, metric
 	countlabels := createLabelSet(pt.GetLabels(), externalLabels, nameStr, baseName+countStr)
 	addSample(tsMap, count, countlabels, metric)
 
+	addBucketSamples(pt.GetLabels(), pt.GetExplicitBounds(), pt.GetBucketCounts(), pt.GetCount(), time, metric,
+		baseName, tsMap, externalLabels)
+}
+
+// addBucketSamples converts the bucket counts of a histogram data point to the cumulative _bucket series of
+// Prometheus, one per explicit bound plus the le="+Inf" one. The bounds without a bucket count are ignored, and the
+// le="+Inf" bucket is the count of the data point, as Prometheus expects it to be equal to the _count series, so that
+// it is neither counted twice nor missing when len(BucketCounts) is not len(ExplicitBounds) + 1.
+func addBucketSamples(ptLabels []common.StringKeyValue, bounds []float64, bucketCounts []uint64, count uint64,
+	time int64, metric *otlp.Metric, baseName string, tsMap map[string]*prompb.TimeSeries,
+	externalLabels map[string]string) {
 	// cumulative count for conversion to cumulative histogram
 	var cumulativeCount uint64
 
-	// process each bound, based on histograms proto definition, # of buckets = # of explicit bounds + 1
-	for index, bound := range pt.GetExplicitBounds() {
-		if index >= len(pt.GetBucketCounts()) {
+	for index, bound := range bounds {
+		if index >= len(bucketCounts) {
 			break
 		}
-		cumulativeCount += pt.GetBucketCounts()[index]
+		cumulativeCount += bucketCounts[index]
 		bucket := &prompb.Sample{
 			Value:     float64(cumulativeCount),
 			Timestamp: time,
 		}
 		boundStr := strconv.FormatFloat(bound, 'f', -1, 64)
-		labels := createLabelSet(pt.GetLabels(), externalLabels, nameStr, baseName+bucketStr, leStr, boundStr)
+		labels := createLabelSet(ptLabels, externalLabels, nameStr, baseName+bucketStr, leStr, boundStr)
 		addSample(tsMap, bucket, labels, metric)
 	}
 	// add le=+Inf bucket
-	cumulativeCount += pt.GetBucketCounts()[len(pt.GetBucketCounts())-1]
 	infBucket := &prompb.Sample{
-		Value:     float64(cumulativeCount),
+		Value:     float64(count),
 		Timestamp: time,
 	}
-	infLabels := createLabelSet(pt.GetLabels(), externalLabels, nameStr, baseName+bucketStr, leStr, pInfStr)
+	infLabels := createLabelSet(ptLabels, externalLabels, nameStr, baseName+bucketStr, leStr, pInfStr)
 	addSample(tsMap, infBucket, infLabels, metric)
 }
 
@@ -367,3 +367,94 @@ func Test_batchTimeSeries(t *testing.T) {
 		})
 	}
 }
+
+// seriesValues returns the value of the last sample of every series, by __name__ and le or quantile label.
+func seriesValues(tsMap map[string]*prompb.TimeSeries) map[string]float64 {
+	values := map[string]float64{}
+	for _, ts := range tsMap {
+		var name, bucket string
+		for _, l := range ts.Labels {
+			switch l.Name {
+			case nameStr:
+				name = l.Value
+			case leStr, quantileStr:
+				bucket = "{" + l.Name + "=" + l.Value + "}"
+			}
+		}
+		values[name+bucket] = ts.Samples[len(ts.Samples)-1].Value
+	}
+	return values
+}
+
+// Test_addSingleHistogramDataPoint checks that the buckets of the histograms are cumulative and that the le="+Inf"
+// bucket is the count of the data point.
+func Test_addSingleHistogramDataPoint(t *testing.T) {
+	tests := []struct {
+		name    string
+		bounds  []float64
+		buckets []uint64
+		want    map[string]float64
+	}{
+		{
+			name:    "bounds_and_overflow_bucket",
+			bounds:  []float64{0.1, 0.5},
+			buckets: []uint64{1, 2, 3},
+			want: map[string]float64{
+				"hist_sum":             10,
+				"hist_count":           6,
+				"hist_bucket{le=0.1}":  1,
+				"hist_bucket{le=0.5}":  3,
+				"hist_bucket{le=+Inf}": 6,
+			},
+		},
+		{
+			name:    "extra_bounds",
+			bounds:  []float64{0.1, 0.5, 0.99},
+			buckets: []uint64{1, 2},
+			want: map[string]float64{
+				"hist_sum":             10,
+				"hist_count":           6,
+				"hist_bucket{le=0.1}":  1,
+				"hist_bucket{le=0.5}":  3,
+				"hist_bucket{le=+Inf}": 6,
+			},
+		},
+		{
+			name: "no_buckets",
+			want: map[string]float64{
+				"hist_sum":             10,
+				"hist_count":           6,
+				"hist_bucket{le=+Inf}": 6,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metric := &otlp.Metric{Name: "hist"}
+
+			tsMap := map[string]*prompb.TimeSeries{}
+			addSingleIntHistogramDataPoint(getIntHistogramDataPoint(lbs1, time1, 10, 6, tt.bounds, tt.buckets),
+				metric, "", tsMap, nil)
+			assert.Equal(t, tt.want, seriesValues(tsMap))
+
+			tsMap = map[string]*prompb.TimeSeries{}
+			addSingleDoubleHistogramDataPoint(getDoubleHistogramDataPoint(lbs1, time1, 10, 6, tt.bounds, tt.buckets),
+				metric, "", tsMap, nil)
+			assert.Equal(t, tt.want, seriesValues(tsMap))
+		})
+	}
+}
+
+// Test_addSingleDoubleSummaryDataPoint checks that a summary is converted to its _sum, _count and quantile series.
+func Test_addSingleDoubleSummaryDataPoint(t *testing.T) {
+	tsMap := map[string]*prompb.TimeSeries{}
+	addSingleDoubleSummaryDataPoint(getDoubleSummaryDataPoint(lbs1, time1, 10, 6, quantiles),
+		&otlp.Metric{Name: "summary"}, "", tsMap, nil)
+	assert.Equal(t, map[string]float64{
+		"summary_sum":            10,
+		"summary_count":          6,
+		"summary{quantile=0.15}": 7,
+		"summary{quantile=0.9}":  8,
+		"summary{quantile=0.99}": 9,
+	}, seriesValues(tsMap))
+}
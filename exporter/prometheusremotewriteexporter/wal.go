@@ -0,0 +1,376 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheusremotewriteexporter
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// walRecordHeaderSize is the size of the header of a record: the length and
+	// the CRC32 of its data, as 4 bytes big-endian unsigned integers.
+	walRecordHeaderSize = 8
+	// walCheckpointFile is the name of the file holding the position of the
+	// first record not sent yet.
+	walCheckpointFile = "checkpoint"
+	walSegmentSuffix  = ".wal"
+)
+
+var (
+	errWALClosed    = errors.New("the write-ahead log is closed")
+	errWALCorrupted = errors.New("corrupted write-ahead log record")
+)
+
+// walPosition is the position of a record in the write-ahead log.
+type walPosition struct {
+	segment int
+	offset  int64
+}
+
+// wal is a write-ahead log of records, kept in segment files of the
+// directory. The records are appended by the writers and read in order by a
+// single reader, the segments of which all the records are committed are
+// removed. The position of the reader is persisted in a checkpoint file
+// when it commits, so that the records not committed are read again after a
+// restart.
+type wal struct {
+	dir         string
+	segmentSize int64
+
+	mutex    sync.Mutex
+	closed   bool
+	notifyCh chan struct{}
+
+	// The segment appended to and its size.
+	writeFile *os.File
+	write     walPosition
+
+	// The segment read from, the position of the next record and the
+	// position of the first record not committed.
+	readFile  *os.File
+	reader    *bufio.Reader
+	read      walPosition
+	committed walPosition
+}
+
+// openWAL opens the write-ahead log of the directory, creating it if
+// needed. A partially written record at the end of the last segment, e.g.
+// when the collector crashed while appending it, is truncated.
+func openWAL(dir string, segmentSize int64) (*wal, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	w := &wal{
+		dir:         dir,
+		segmentSize: segmentSize,
+		notifyCh:    make(chan struct{}, 1),
+	}
+
+	segments, err := w.segments()
+	if err != nil {
+		return nil, err
+	}
+	if err = w.loadCheckpoint(); err != nil {
+		return nil, err
+	}
+	if len(segments) == 0 {
+		segments = []int{w.committed.segment}
+	}
+	if w.committed.segment < segments[0] {
+		w.committed = walPosition{segment: segments[0]}
+	}
+
+	last := segments[len(segments)-1]
+	size, err := validSize(w.segmentPath(last))
+	if err != nil {
+		return nil, err
+	}
+	if w.writeFile, err = os.OpenFile(w.segmentPath(last), os.O_CREATE|os.O_WRONLY, 0600); err != nil {
+		return nil, err
+	}
+	if err = w.writeFile.Truncate(size); err != nil {
+		return nil, err
+	}
+	if _, err = w.writeFile.Seek(size, io.SeekStart); err != nil {
+		return nil, err
+	}
+	w.write = walPosition{segment: last, offset: size}
+
+	if err = w.openReader(w.committed); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *wal) segmentPath(segment int) string {
+	return filepath.Join(w.dir, fmt.Sprintf("%020d%s", segment, walSegmentSuffix))
+}
+
+// segments returns the indexes of the segments, in order.
+func (w *wal) segments() ([]int, error) {
+	files, err := ioutil.ReadDir(w.dir)
+	if err != nil {
+		return nil, err
+	}
+	var segments []int
+	for _, file := range files {
+		name := file.Name()
+		if !strings.HasSuffix(name, walSegmentSuffix) {
+			continue
+		}
+		segment, err := strconv.Atoi(strings.TrimSuffix(name, walSegmentSuffix))
+		if err != nil {
+			continue
+		}
+		segments = append(segments, segment)
+	}
+	sort.Ints(segments)
+	return segments, nil
+}
+
+func (w *wal) loadCheckpoint() error {
+	data, err := ioutil.ReadFile(filepath.Join(w.dir, walCheckpointFile))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if _, err = fmt.Sscanf(string(data), "%d %d", &w.committed.segment, &w.committed.offset); err != nil {
+		return fmt.Errorf("invalid write-ahead log checkpoint: %v", err)
+	}
+	return nil
+}
+
+// validSize returns the size of the complete and valid records at the start
+// of the segment.
+func validSize(path string) (int64, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	var size int64
+	for {
+		data, err := readRecord(reader)
+		if err != nil {
+			return size, nil
+		}
+		size += walRecordHeaderSize + int64(len(data))
+	}
+}
+
+func readRecord(reader *bufio.Reader) ([]byte, error) {
+	header := make([]byte, walRecordHeaderSize)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		return nil, err
+	}
+	data := make([]byte, binary.BigEndian.Uint32(header))
+	if _, err := io.ReadFull(reader, data); err != nil {
+		return nil, err
+	}
+	if crc32.ChecksumIEEE(data) != binary.BigEndian.Uint32(header[4:]) {
+		return nil, errWALCorrupted
+	}
+	return data, nil
+}
+
+func (w *wal) openReader(position walPosition) error {
+	if w.readFile != nil {
+		_ = w.readFile.Close()
+	}
+	file, err := os.OpenFile(w.segmentPath(position.segment), os.O_CREATE|os.O_RDONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err = file.Seek(position.offset, io.SeekStart); err != nil {
+		_ = file.Close()
+		return err
+	}
+	w.readFile = file
+	w.reader = bufio.NewReader(file)
+	w.read = position
+	return nil
+}
+
+// append appends the records and syncs the segment to the disk.
+func (w *wal) append(records [][]byte) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.closed {
+		return errWALClosed
+	}
+
+	for _, data := range records {
+		if w.write.offset > 0 && w.write.offset+walRecordHeaderSize+int64(len(data)) > w.segmentSize {
+			if err := w.rotate(); err != nil {
+				return err
+			}
+		}
+		record := make([]byte, walRecordHeaderSize+len(data))
+		binary.BigEndian.PutUint32(record, uint32(len(data)))
+		binary.BigEndian.PutUint32(record[4:], crc32.ChecksumIEEE(data))
+		copy(record[walRecordHeaderSize:], data)
+		if _, err := w.writeFile.Write(record); err != nil {
+			return err
+		}
+		w.write.offset += int64(len(record))
+	}
+	if err := w.writeFile.Sync(); err != nil {
+		return err
+	}
+
+	select {
+	case w.notifyCh <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func (w *wal) rotate() error {
+	if err := w.writeFile.Sync(); err != nil {
+		return err
+	}
+	if err := w.writeFile.Close(); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(w.segmentPath(w.write.segment+1), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	w.writeFile = file
+	w.write = walPosition{segment: w.write.segment + 1}
+	return nil
+}
+
+// next returns the next record, waiting for it to be appended if needed.
+func (w *wal) next(ctx context.Context) ([]byte, error) {
+	for {
+		data, ok, err := w.tryNext()
+		if err != nil || ok {
+			return data, err
+		}
+		select {
+		case <-w.notifyCh:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func (w *wal) tryNext() ([]byte, bool, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.closed {
+		return nil, false, errWALClosed
+	}
+
+	for {
+		if w.read.segment == w.write.segment && w.read.offset >= w.write.offset {
+			return nil, false, nil
+		}
+		data, err := readRecord(w.reader)
+		if err == io.EOF && w.read.segment < w.write.segment {
+			// The segment is complete, continue with the next one.
+			if err = w.openReader(walPosition{segment: w.read.segment + 1}); err != nil {
+				return nil, false, err
+			}
+			continue
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF || err == errWALCorrupted {
+			// The record is cut short or its CRC does not match.
+			return nil, false, fmt.Errorf("%w in segment %d at offset %d", errWALCorrupted, w.read.segment, w.read.offset)
+		}
+		if err != nil {
+			return nil, false, err
+		}
+		w.read.offset += walRecordHeaderSize + int64(len(data))
+		return data, true, nil
+	}
+}
+
+// skipSegment skips the records left in the segment read from, the records following a
+// corrupted one cannot be found. The segment written to is rotated first if it is the one read
+// from, so that the records appended next are read.
+func (w *wal) skipSegment() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.closed {
+		return errWALClosed
+	}
+
+	if w.read.segment == w.write.segment {
+		if err := w.rotate(); err != nil {
+			return err
+		}
+	}
+	return w.openReader(walPosition{segment: w.read.segment + 1})
+}
+
+// commit persists the position after the records returned by next, and
+// removes the segments of which all the records are committed.
+func (w *wal) commit() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.closed {
+		return errWALClosed
+	}
+
+	checkpoint := filepath.Join(w.dir, walCheckpointFile)
+	data := fmt.Sprintf("%d %d", w.read.segment, w.read.offset)
+	if err := ioutil.WriteFile(checkpoint+".tmp", []byte(data), 0600); err != nil {
+		return err
+	}
+	if err := os.Rename(checkpoint+".tmp", checkpoint); err != nil {
+		return err
+	}
+
+	for segment := w.committed.segment; segment < w.read.segment; segment++ {
+		if err := os.Remove(w.segmentPath(segment)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	w.committed = w.read
+	return nil
+}
+
+func (w *wal) close() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	_ = w.readFile.Close()
+	return w.writeFile.Close()
+}
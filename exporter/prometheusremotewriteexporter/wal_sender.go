@@ -0,0 +1,220 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheusremotewriteexporter
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/gogo/protobuf/proto"
+	"github.com/prometheus/prometheus/prompb"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+)
+
+const defaultMaxSegmentMegabytes = 64
+
+// walSender sends the requests of the write-ahead log one at a time, in the
+// order they were appended, retrying each of them until it is accepted or
+// permanently rejected, so that the samples of a series are never rejected
+// as out of order.
+type walSender struct {
+	cfg     *WALConfig
+	retry   exporterhelper.RetrySettings
+	timeout time.Duration
+	logger  *zap.Logger
+
+	wal    *wal
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// enableWAL makes the exporter persist the requests in the write-ahead log
+// defined by cfg, and send them in the background with the retry and timeout
+// settings. It must be called before the exporter starts.
+func (prwe *PrwExporter) enableWAL(cfg *WALConfig, retry exporterhelper.RetrySettings, timeout exporterhelper.TimeoutSettings, logger *zap.Logger) error {
+	if cfg.Directory == "" {
+		return errors.New("prometheus remote write: the wal directory must be configured")
+	}
+	if cfg.MaxSegmentMegabytes < 0 {
+		return errors.New("prometheus remote write: the wal max_segment_megabytes must not be negative")
+	}
+	prwe.walSender = &walSender{
+		cfg:     cfg,
+		retry:   retry,
+		timeout: timeout.Timeout,
+		logger:  logger,
+	}
+	return nil
+}
+
+// Start opens the write-ahead log, if enabled, and starts sending its
+// requests.
+func (prwe *PrwExporter) Start(context.Context, component.Host) error {
+	if prwe.walSender == nil {
+		return nil
+	}
+	return prwe.walSender.start(prwe.execute)
+}
+
+func (s *walSender) start(execute func(context.Context, *prompb.WriteRequest) error) error {
+	segmentMegabytes := s.cfg.MaxSegmentMegabytes
+	if segmentMegabytes == 0 {
+		segmentMegabytes = defaultMaxSegmentMegabytes
+	}
+	w, err := openWAL(s.cfg.Directory, int64(segmentMegabytes)*1024*1024)
+	if err != nil {
+		return err
+	}
+	s.wal = w
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.done = make(chan struct{})
+	go s.run(ctx, execute)
+	return nil
+}
+
+// append persists the requests of the time series, with the samples of each
+// series sorted by timestamp.
+func (s *walSender) append(tsMap map[string]*prompb.TimeSeries) error {
+	for _, ts := range tsMap {
+		sort.SliceStable(ts.Samples, func(i, j int) bool {
+			return ts.Samples[i].Timestamp < ts.Samples[j].Timestamp
+		})
+	}
+	requests, err := batchTimeSeries(tsMap, maxBatchByteSize)
+	if err != nil {
+		return consumererror.Permanent(err)
+	}
+	records := make([][]byte, 0, len(requests))
+	for _, request := range requests {
+		data, err := proto.Marshal(request)
+		if err != nil {
+			return consumererror.Permanent(err)
+		}
+		records = append(records, data)
+	}
+	return s.wal.append(records)
+}
+
+func (s *walSender) run(ctx context.Context, execute func(context.Context, *prompb.WriteRequest) error) {
+	defer close(s.done)
+	readBackoff := s.newBackOff()
+	for {
+		data, err := s.wal.next(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			if errors.Is(err, errWALCorrupted) {
+				s.logger.Error("skipping the rest of a corrupted segment of the write-ahead log", zap.Error(err))
+				err = s.wal.skipSegment()
+			}
+			if err != nil {
+				backoffDelay := readBackoff.NextBackOff()
+				s.logger.Error("failed to read the write-ahead log, will retry", zap.Error(err), zap.Duration("interval", backoffDelay))
+				select {
+				case <-time.After(backoffDelay):
+				case <-ctx.Done():
+					return
+				}
+			}
+			continue
+		}
+		readBackoff.Reset()
+
+		request := &prompb.WriteRequest{}
+		if err = proto.Unmarshal(data, request); err != nil {
+			s.logger.Error("dropping a corrupted request of the write-ahead log", zap.Error(err))
+		} else if !s.send(ctx, execute, request) {
+			// Shutting down, the request is sent again after the restart.
+			return
+		}
+
+		if err = s.wal.commit(); err != nil {
+			s.logger.Error("failed to commit the write-ahead log", zap.Error(err))
+		}
+	}
+}
+
+// send sends the request until it is accepted or permanently rejected, it
+// returns false if the sender is stopped before.
+func (s *walSender) send(ctx context.Context, execute func(context.Context, *prompb.WriteRequest) error, request *prompb.WriteRequest) bool {
+	// Never give up, the following requests must not be sent before this one.
+	expBackoff := s.newBackOff()
+	for {
+		err := s.execute(ctx, execute, request)
+		if err == nil {
+			return true
+		}
+		if consumererror.IsPermanent(err) && !isConnectionError(err) {
+			s.logger.Error("dropping a request of the write-ahead log permanently rejected", zap.Int("timeseries", len(request.Timeseries)), zap.Error(err))
+			return true
+		}
+
+		backoffDelay := expBackoff.NextBackOff()
+		s.logger.Info("failed to send a request of the write-ahead log, will retry", zap.Error(err), zap.Duration("interval", backoffDelay))
+		select {
+		case <-time.After(backoffDelay):
+		case <-ctx.Done():
+			return false
+		}
+	}
+}
+
+// isConnectionError returns true if the HTTP client failed to send the request, e.g. when the
+// endpoint has an outage. These errors are permanent for the exporter but the write-ahead log
+// retries them.
+func isConnectionError(err error) bool {
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
+
+// newBackOff returns a backoff of the retry settings that never gives up.
+func (s *walSender) newBackOff() *backoff.ExponentialBackOff {
+	expBackoff := backoff.NewExponentialBackOff()
+	expBackoff.InitialInterval = s.retry.InitialInterval
+	expBackoff.MaxInterval = s.retry.MaxInterval
+	expBackoff.MaxElapsedTime = 0
+	return expBackoff
+}
+
+func (s *walSender) execute(ctx context.Context, execute func(context.Context, *prompb.WriteRequest) error, request *prompb.WriteRequest) error {
+	if s.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.timeout)
+		defer cancel()
+	}
+	return execute(ctx, request)
+}
+
+// shutdown stops sending the requests and closes the write-ahead log, the
+// requests not sent yet are sent after the restart.
+func (s *walSender) shutdown() error {
+	if s.wal == nil {
+		return nil
+	}
+	s.cancel()
+	<-s.done
+	return s.wal.close()
+}
@@ -0,0 +1,331 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheusremotewriteexporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+)
+
+func nextRecord(t *testing.T, w *wal) string {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	data, err := w.next(ctx)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestWALAppendNextCommit(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wal")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	w, err := openWAL(dir, 1024)
+	require.NoError(t, err)
+	require.NoError(t, w.append([][]byte{[]byte("a"), []byte("b")}))
+
+	assert.Equal(t, "a", nextRecord(t, w))
+	require.NoError(t, w.commit())
+
+	// Nothing is available after the last record.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, "b", nextRecord(t, w))
+	_, err = w.next(ctx)
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	// next waits for the records to be appended.
+	go func() {
+		assert.NoError(t, w.append([][]byte{[]byte("c")}))
+	}()
+	assert.Equal(t, "c", nextRecord(t, w))
+
+	require.NoError(t, w.close())
+	_, err = w.next(context.Background())
+	assert.Equal(t, errWALClosed, err)
+	assert.Equal(t, errWALClosed, w.append([][]byte{[]byte("d")}))
+}
+
+func TestWALResumeFromCheckpoint(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wal")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	w, err := openWAL(dir, 1024)
+	require.NoError(t, err)
+	require.NoError(t, w.append([][]byte{[]byte("a"), []byte("b"), []byte("c")}))
+	assert.Equal(t, "a", nextRecord(t, w))
+	require.NoError(t, w.commit())
+	// b is read but not committed, it has to be read again after the restart.
+	assert.Equal(t, "b", nextRecord(t, w))
+	require.NoError(t, w.close())
+
+	w, err = openWAL(dir, 1024)
+	require.NoError(t, err)
+	defer w.close()
+	assert.Equal(t, "b", nextRecord(t, w))
+	assert.Equal(t, "c", nextRecord(t, w))
+}
+
+func TestWALRotation(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wal")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	// Every record is in its own segment.
+	w, err := openWAL(dir, walRecordHeaderSize+1)
+	require.NoError(t, err)
+	defer w.close()
+	require.NoError(t, w.append([][]byte{[]byte("a"), []byte("b"), []byte("c")}))
+
+	segments, err := w.segments()
+	require.NoError(t, err)
+	assert.Equal(t, []int{0, 1, 2}, segments)
+
+	assert.Equal(t, "a", nextRecord(t, w))
+	assert.Equal(t, "b", nextRecord(t, w))
+	require.NoError(t, w.commit())
+
+	// The segments of which all the records are committed are removed.
+	segments, err = w.segments()
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, segments)
+
+	assert.Equal(t, "c", nextRecord(t, w))
+}
+
+func TestWALTruncatesCorruptedTail(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wal")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	w, err := openWAL(dir, 1024)
+	require.NoError(t, err)
+	require.NoError(t, w.append([][]byte{[]byte("a")}))
+	require.NoError(t, w.close())
+
+	// Simulates a crash in the middle of an append.
+	file, err := os.OpenFile(filepath.Join(dir, "00000000000000000000.wal"), os.O_APPEND|os.O_WRONLY, 0600)
+	require.NoError(t, err)
+	_, err = file.Write([]byte{0, 0, 0, 5, 1, 2})
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	w, err = openWAL(dir, 1024)
+	require.NoError(t, err)
+	defer w.close()
+	require.NoError(t, w.append([][]byte{[]byte("b")}))
+	assert.Equal(t, "a", nextRecord(t, w))
+	assert.Equal(t, "b", nextRecord(t, w))
+}
+
+// corruptRecord flips a byte of the data of the first record of the segment.
+func corruptRecord(t *testing.T, dir string, segment int) {
+	path := filepath.Join(dir, fmt.Sprintf("%020d%s", segment, walSegmentSuffix))
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	data[walRecordHeaderSize] ^= 0xff
+	require.NoError(t, ioutil.WriteFile(path, data, 0600))
+}
+
+func TestWALSkipSegment(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wal")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	// Every record is in its own segment.
+	w, err := openWAL(dir, walRecordHeaderSize+1)
+	require.NoError(t, err)
+	defer w.close()
+	require.NoError(t, w.append([][]byte{[]byte("a"), []byte("b")}))
+	corruptRecord(t, dir, 0)
+
+	_, err = w.next(context.Background())
+	assert.True(t, errors.Is(err, errWALCorrupted))
+	require.NoError(t, w.skipSegment())
+	assert.Equal(t, "b", nextRecord(t, w))
+
+	// The segment written to is rotated when it is skipped.
+	corruptRecord(t, dir, 1)
+	require.NoError(t, w.openReader(walPosition{segment: 1}))
+	_, err = w.next(context.Background())
+	assert.True(t, errors.Is(err, errWALCorrupted))
+	require.NoError(t, w.skipSegment())
+	require.NoError(t, w.append([][]byte{[]byte("c")}))
+	assert.Equal(t, "c", nextRecord(t, w))
+}
+
+func TestWALSenderSkipsCorruptedSegment(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wal")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	// The corrupted record is in a segment of its own, before the one the sender appends to.
+	w, err := openWAL(dir, walRecordHeaderSize+1)
+	require.NoError(t, err)
+	require.NoError(t, w.append([][]byte{[]byte("a"), []byte("b")}))
+	require.NoError(t, w.close())
+	corruptRecord(t, dir, 0)
+
+	sender := &walSender{
+		cfg:    &WALConfig{Directory: dir, MaxSegmentMegabytes: 1},
+		retry:  exporterhelper.RetrySettings{InitialInterval: time.Millisecond, MaxInterval: 10 * time.Millisecond},
+		logger: zap.NewNop(),
+	}
+	sent := make(chan string, 1)
+	require.NoError(t, sender.start(func(_ context.Context, request *prompb.WriteRequest) error {
+		sent <- request.Timeseries[0].Labels[0].Value
+		return nil
+	}))
+	defer sender.shutdown()
+
+	// The requests appended after the corrupted record are still sent.
+	ts := getTimeSeries(getPromLabels("__name__", "after"), getSample(1, 1))
+	require.NoError(t, sender.append(map[string]*prompb.TimeSeries{"after": ts}))
+	select {
+	case name := <-sent:
+		assert.Equal(t, "after", name)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the request appended after the corrupted record was not sent")
+	}
+}
+
+func TestWALSenderRetriesInOrder(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wal")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	var mu sync.Mutex
+	var received []string
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts++
+		// The first request is rejected with a recoverable error.
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		data, err := snappy.Decode(nil, body)
+		require.NoError(t, err)
+		request := &prompb.WriteRequest{}
+		require.NoError(t, proto.Unmarshal(data, request))
+		for _, ts := range request.Timeseries {
+			received = append(received, ts.Labels[0].Value)
+		}
+	}))
+	defer server.Close()
+
+	prwe, err := NewPrwExporter("", server.URL, http.DefaultClient, map[string]string{})
+	require.NoError(t, err)
+	retry := exporterhelper.RetrySettings{InitialInterval: time.Millisecond, MaxInterval: 10 * time.Millisecond}
+	require.NoError(t, prwe.enableWAL(&WALConfig{Directory: dir}, retry, exporterhelper.TimeoutSettings{Timeout: time.Second}, zap.NewNop()))
+	require.NoError(t, prwe.Start(context.Background(), componenttest.NewNopHost()))
+
+	for _, name := range []string{"first", "second"} {
+		ts := getTimeSeries(getPromLabels("__name__", name), getSample(1, 2), getSample(2, 1))
+		require.NoError(t, prwe.walSender.append(map[string]*prompb.TimeSeries{name: ts}))
+		// The samples are sorted by timestamp.
+		assert.Equal(t, int64(1), ts.Samples[0].Timestamp)
+	}
+
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(received) == 2
+	}, time.Second, 5*time.Millisecond)
+	require.NoError(t, prwe.Shutdown(context.Background()))
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"first", "second"}, received)
+	assert.Equal(t, 3, attempts)
+}
+
+func TestWALSenderRetriesConnectionErrors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wal")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	serverURL := server.URL
+	// The endpoint is down when the first request is sent.
+	server.Close()
+
+	prwe, err := NewPrwExporter("", serverURL, http.DefaultClient, map[string]string{})
+	require.NoError(t, err)
+	var mu sync.Mutex
+	var errs []error
+	sender := &walSender{
+		cfg:    &WALConfig{Directory: dir},
+		retry:  exporterhelper.RetrySettings{InitialInterval: time.Millisecond, MaxInterval: 10 * time.Millisecond},
+		logger: zap.NewNop(),
+	}
+	require.NoError(t, sender.start(func(ctx context.Context, request *prompb.WriteRequest) error {
+		err := prwe.execute(ctx, request)
+		mu.Lock()
+		defer mu.Unlock()
+		errs = append(errs, err)
+		if len(errs) == 2 {
+			return nil
+		}
+		return err
+	}))
+	defer sender.shutdown()
+
+	ts := getTimeSeries(getPromLabels("__name__", "test"), getSample(1, 1))
+	require.NoError(t, sender.append(map[string]*prompb.TimeSeries{"test": ts}))
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(errs) == 2
+	}, time.Second, time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	// The connection error is retried although it is permanent for the exporter.
+	assert.True(t, consumererror.IsPermanent(errs[0]))
+	assert.True(t, isConnectionError(errs[0]))
+}
+
+func TestEnableWALInvalidConfig(t *testing.T) {
+	prwe, err := NewPrwExporter("", "http://localhost:9009", http.DefaultClient, map[string]string{})
+	require.NoError(t, err)
+	assert.Error(t, prwe.enableWAL(&WALConfig{}, exporterhelper.DefaultRetrySettings(), exporterhelper.DefaultTimeoutSettings(), zap.NewNop()))
+	assert.Error(t, prwe.enableWAL(&WALConfig{Directory: "wal", MaxSegmentMegabytes: -1}, exporterhelper.DefaultRetrySettings(), exporterhelper.DefaultTimeoutSettings(), zap.NewNop()))
+}
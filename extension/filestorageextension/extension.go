@@ -0,0 +1,80 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filestorageextension
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/extension/storage"
+)
+
+type fileStorage struct {
+	directory string
+	logger    *zap.Logger
+}
+
+var _ storage.Extension = (*fileStorage)(nil)
+
+func newFileStorage(config Config, logger *zap.Logger) *fileStorage {
+	return &fileStorage{
+		directory: config.Directory,
+		logger:    logger,
+	}
+}
+
+// Start creates the directory, so that a directory that cannot be created fails the start
+// of the collector instead of that of the components.
+func (fs *fileStorage) Start(context.Context, component.Host) error {
+	if err := os.MkdirAll(fs.directory, 0700); err != nil {
+		return fmt.Errorf("failed to create the storage directory: %w", err)
+	}
+	return nil
+}
+
+func (fs *fileStorage) Shutdown(context.Context) error {
+	return nil
+}
+
+// GetClient returns a client persisting the values in the subdirectory of the component,
+// named after its kind and escaped full name, e.g. "receiver_filelog%2F1".
+func (fs *fileStorage) GetClient(_ context.Context, kind component.Kind, name string) (storage.Client, error) {
+	dir := filepath.Join(fs.directory, kindString(kind)+"_"+storage.EscapeName(name))
+	client, err := storage.NewFileClient(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the storage of %s %q: %w", kindString(kind), name, err)
+	}
+	fs.logger.Debug("Opened the storage of a component", zap.String("directory", dir))
+	return client, nil
+}
+
+func kindString(kind component.Kind) string {
+	switch kind {
+	case component.KindReceiver:
+		return "receiver"
+	case component.KindProcessor:
+		return "processor"
+	case component.KindExporter:
+		return "exporter"
+	case component.KindExtension:
+		return "extension"
+	}
+	return "unknown"
+}
@@ -0,0 +1,79 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filestorageextension
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/extension/storage"
+)
+
+func TestFileStorage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "file_storage")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	cfg := createDefaultConfig().(*Config)
+	cfg.Directory = filepath.Join(dir, "storage")
+	ext, err := createExtension(context.Background(), component.ExtensionCreateParams{Logger: zap.NewNop()}, cfg)
+	require.NoError(t, err)
+	require.NoError(t, ext.Start(context.Background(), componenttest.NewNopHost()))
+	ctx := context.Background()
+
+	receiverClient, err := ext.(storage.Extension).GetClient(ctx, component.KindReceiver, "filelog/1")
+	require.NoError(t, err)
+	exporterClient, err := ext.(storage.Extension).GetClient(ctx, component.KindExporter, "filelog/1")
+	require.NoError(t, err)
+	require.NoError(t, receiverClient.Set(ctx, "checkpoints", []byte("receiver")))
+	require.NoError(t, exporterClient.Set(ctx, "checkpoints", []byte("exporter")))
+
+	// The keys of the components are not shared.
+	value, err := receiverClient.Get(ctx, "checkpoints")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("receiver"), value)
+	value, err = exporterClient.Get(ctx, "checkpoints")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("exporter"), value)
+	_, err = os.Stat(filepath.Join(cfg.Directory, "receiver_filelog%2F1", "checkpoints"))
+	assert.NoError(t, err)
+
+	require.NoError(t, receiverClient.Close(ctx))
+	require.NoError(t, exporterClient.Close(ctx))
+	assert.NoError(t, ext.Shutdown(ctx))
+}
+
+func TestFileStorage_InvalidDirectory(t *testing.T) {
+	file, err := ioutil.TempFile("", "file_storage")
+	require.NoError(t, err)
+	file.Close()
+	defer os.Remove(file.Name())
+	cfg := createDefaultConfig().(*Config)
+	cfg.Directory = filepath.Join(file.Name(), "storage")
+	ext, err := createExtension(context.Background(), component.ExtensionCreateParams{Logger: zap.NewNop()}, cfg)
+	require.NoError(t, err)
+
+	assert.Error(t, ext.Start(context.Background(), componenttest.NewNopHost()))
+	_, err = ext.(storage.Extension).GetClient(context.Background(), component.KindReceiver, "filelog")
+	assert.Error(t, err)
+}
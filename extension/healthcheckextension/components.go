@@ -0,0 +1,216 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package healthcheckextension
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/jaegertracing/jaeger/pkg/healthcheck"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/exporter/exporterhelper/exporterstate"
+)
+
+const (
+	livenessPath  = "/live"
+	readinessPath = "/ready"
+
+	// statusUnavailable is the status of the service while the pipelines are not ready.
+	statusUnavailable = "unavailable"
+
+	defaultExporterFailureDuration = 5 * time.Minute
+)
+
+type componentKey struct {
+	kind     component.Kind
+	pipeline string
+	name     string
+}
+
+// componentHealth is the health of a component reported by the liveness and readiness endpoints.
+type componentHealth struct {
+	Kind     string `json:"kind"`
+	Name     string `json:"name"`
+	Pipeline string `json:"pipeline,omitempty"`
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+	// QueueSaturation is the ratio of the capacity of the sending queue used by an exporter.
+	QueueSaturation *float64 `json:"queue_saturation,omitempty"`
+	// FailingSince is the time since which all the exports of an exporter failed, when this
+	// makes the service not ready.
+	FailingSince *time.Time `json:"failing_since,omitempty"`
+
+	kind component.Kind
+}
+
+// health is the body of the responses of the liveness and readiness endpoints.
+type health struct {
+	Status     string            `json:"status"`
+	Components []componentHealth `json:"components"`
+
+	live  bool
+	ready bool
+}
+
+func kindString(kind component.Kind) string {
+	switch kind {
+	case component.KindReceiver:
+		return "receiver"
+	case component.KindProcessor:
+		return "processor"
+	case component.KindExporter:
+		return "exporter"
+	case component.KindExtension:
+		return "extension"
+	}
+	return "unknown"
+}
+
+func (hc *healthCheckExtension) ComponentStatusChanged(event component.StatusEvent) {
+	hc.mu.Lock()
+	defer hc.mu.Unlock()
+	hc.statuses[componentKey{kind: event.Kind, pipeline: event.Pipeline, name: event.Name}] = event
+}
+
+// exportsFailing returns the time since which all the exports of the exporter failed, and
+// whether this exceeds the exporter failure threshold.
+func (hc *healthCheckExtension) exportsFailing(exporter string) (time.Time, bool) {
+	threshold := hc.config.ExporterFailureThreshold
+	if threshold == nil {
+		return time.Time{}, false
+	}
+	if len(threshold.Exporters) > 0 {
+		checked := false
+		for _, name := range threshold.Exporters {
+			checked = checked || name == exporter
+		}
+		if !checked {
+			return time.Time{}, false
+		}
+	}
+	since, ok := exporterstate.FailingSince(exporter)
+	if !ok {
+		return time.Time{}, false
+	}
+	duration := threshold.Duration
+	if duration == 0 {
+		duration = defaultExporterFailureDuration
+	}
+	return since, time.Since(since) >= duration
+}
+
+// health returns the health of the service and of its components. The service is live while
+// none of its components failed, and ready when its pipelines are also ready, all its
+// components are started and none of the exporters exceeds the exporter failure threshold.
+func (hc *healthCheckExtension) health() health {
+	saturation := exporterstate.Saturation()
+	pipelinesReady := hc.state.Get() == healthcheck.Ready
+
+	hc.mu.Lock()
+	components := make([]componentHealth, 0, len(hc.statuses))
+	for _, event := range hc.statuses {
+		ch := componentHealth{
+			Kind:     kindString(event.Kind),
+			Name:     event.Name,
+			Pipeline: event.Pipeline,
+			kind:     event.Kind,
+		}
+		status := event.Status
+		if event.Err != nil {
+			ch.Error = event.Err.Error()
+		}
+		if s, ok := saturation[event.Name]; ok && event.Kind == component.KindExporter {
+			ch.QueueSaturation = &s
+			if status == component.StatusHealthy && hc.config.QueueSaturationThreshold > 0 && s >= hc.config.QueueSaturationThreshold {
+				status = component.StatusDegraded
+			}
+		}
+		if event.Kind == component.KindExporter {
+			if since, failing := hc.exportsFailing(event.Name); failing {
+				ch.FailingSince = &since
+				if status == component.StatusHealthy {
+					status = component.StatusDegraded
+				}
+			}
+		}
+		ch.Status = status.String()
+		components = append(components, ch)
+	}
+	hc.mu.Unlock()
+
+	sort.Slice(components, func(i, j int) bool {
+		if components[i].kind != components[j].kind {
+			return components[i].kind < components[j].kind
+		}
+		if components[i].Pipeline != components[j].Pipeline {
+			return components[i].Pipeline < components[j].Pipeline
+		}
+		return components[i].Name < components[j].Name
+	})
+
+	h := health{Components: components, live: true, ready: pipelinesReady}
+	starting, degraded := false, false
+	for _, c := range components {
+		switch c.Status {
+		case component.StatusFailed.String():
+			h.live = false
+			h.ready = false
+		case component.StatusStarting.String():
+			h.ready = false
+			starting = true
+		case component.StatusDegraded.String():
+			degraded = true
+		}
+		if c.FailingSince != nil {
+			h.ready = false
+		}
+	}
+	switch {
+	case !h.live:
+		h.Status = component.StatusFailed.String()
+	case !pipelinesReady:
+		h.Status = statusUnavailable
+	case starting:
+		h.Status = component.StatusStarting.String()
+	case degraded:
+		h.Status = component.StatusDegraded.String()
+	default:
+		h.Status = component.StatusHealthy.String()
+	}
+	return h
+}
+
+// componentsHandler returns the handler responding with the health of the components, with
+// 200 OK when ok returns true for the health of the service, 503 Service Unavailable otherwise.
+func (hc *healthCheckExtension) componentsHandler(ok func(h health) bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		h := hc.health()
+		body, err := json.Marshal(h)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if ok(h) {
+			w.WriteHeader(http.StatusOK)
+		} else {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		_, _ = w.Write(body)
+	})
+}
@@ -0,0 +1,177 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package healthcheckextension
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/exporter/exporterhelper/exporterstate"
+	"go.opentelemetry.io/collector/testutil"
+)
+
+type fixedQueue struct {
+	size     int
+	capacity int
+}
+
+func (q *fixedQueue) Size() int {
+	return q.size
+}
+
+func (q *fixedQueue) Capacity() int {
+	return q.capacity
+}
+
+func TestHealth(t *testing.T) {
+	hc := newServer(Config{QueueSaturationThreshold: 0.8}, zap.NewNop())
+
+	h := hc.health()
+	assert.Equal(t, statusUnavailable, h.Status)
+	assert.True(t, h.live)
+	assert.False(t, h.ready)
+
+	hc.ComponentStatusChanged(component.StatusEvent{Kind: component.KindExporter, Name: "otlp", Status: component.StatusHealthy})
+	hc.ComponentStatusChanged(component.StatusEvent{Kind: component.KindProcessor, Name: "batch", Pipeline: "traces", Status: component.StatusHealthy})
+	hc.ComponentStatusChanged(component.StatusEvent{Kind: component.KindReceiver, Name: "otlp", Status: component.StatusStarting})
+	require.NoError(t, hc.Ready())
+	h = hc.health()
+	assert.Equal(t, component.StatusStarting.String(), h.Status)
+	assert.True(t, h.live)
+	assert.False(t, h.ready)
+
+	hc.ComponentStatusChanged(component.StatusEvent{Kind: component.KindReceiver, Name: "otlp", Status: component.StatusHealthy})
+	h = hc.health()
+	assert.Equal(t, component.StatusHealthy.String(), h.Status)
+	assert.True(t, h.live)
+	assert.True(t, h.ready)
+	assert.Equal(t, []componentHealth{
+		{Kind: "receiver", Name: "otlp", Status: "healthy", kind: component.KindReceiver},
+		{Kind: "processor", Name: "batch", Pipeline: "traces", Status: "healthy", kind: component.KindProcessor},
+		{Kind: "exporter", Name: "otlp", Status: "healthy", kind: component.KindExporter},
+	}, h.Components)
+
+	q := &fixedQueue{size: 9, capacity: 10}
+	unregister := exporterstate.Register("otlp", q)
+	defer unregister()
+	h = hc.health()
+	assert.Equal(t, component.StatusDegraded.String(), h.Status)
+	assert.True(t, h.ready)
+	require.NotNil(t, h.Components[2].QueueSaturation)
+	assert.Equal(t, 0.9, *h.Components[2].QueueSaturation)
+	assert.Equal(t, "degraded", h.Components[2].Status)
+
+	q.size = 1
+	assert.Equal(t, component.StatusHealthy.String(), hc.health().Status)
+
+	hc.ComponentStatusChanged(component.StatusEvent{Kind: component.KindReceiver, Name: "otlp", Status: component.StatusFailed, Err: errors.New("bind failed")})
+	h = hc.health()
+	assert.Equal(t, component.StatusFailed.String(), h.Status)
+	assert.False(t, h.live)
+	assert.False(t, h.ready)
+	assert.Equal(t, "bind failed", h.Components[0].Error)
+}
+
+func TestHealthExporterFailureThreshold(t *testing.T) {
+	hc := newServer(Config{
+		ExporterFailureThreshold: &ExporterFailureThresholdSettings{
+			Exporters: []string{"otlp/failing"},
+			Duration:  time.Millisecond,
+		},
+	}, zap.NewNop())
+	hc.ComponentStatusChanged(component.StatusEvent{Kind: component.KindExporter, Name: "otlp/failing", Status: component.StatusHealthy})
+	hc.ComponentStatusChanged(component.StatusEvent{Kind: component.KindExporter, Name: "otlp/unchecked", Status: component.StatusHealthy})
+	require.NoError(t, hc.Ready())
+
+	exporterstate.RecordExport("otlp/unchecked", errors.New("export failed"))
+	defer exporterstate.RecordExport("otlp/unchecked", nil)
+	exporterstate.RecordExport("otlp/failing", errors.New("export failed"))
+	defer exporterstate.RecordExport("otlp/failing", nil)
+	since, ok := exporterstate.FailingSince("otlp/failing")
+	require.True(t, ok)
+	time.Sleep(2 * time.Millisecond)
+
+	h := hc.health()
+	assert.Equal(t, component.StatusDegraded.String(), h.Status)
+	assert.True(t, h.live)
+	assert.False(t, h.ready)
+	require.NotNil(t, h.Components[0].FailingSince)
+	assert.Equal(t, since, *h.Components[0].FailingSince)
+	assert.Equal(t, "degraded", h.Components[0].Status)
+	assert.Nil(t, h.Components[1].FailingSince)
+	assert.Equal(t, "healthy", h.Components[1].Status)
+
+	exporterstate.RecordExport("otlp/failing", nil)
+	h = hc.health()
+	assert.Equal(t, component.StatusHealthy.String(), h.Status)
+	assert.True(t, h.ready)
+}
+
+func TestHealthCheckExtensionLivenessReadiness(t *testing.T) {
+	config := Config{
+		Port: testutil.GetAvailablePort(t),
+	}
+	hcExt := newServer(config, zap.NewNop())
+	require.NoError(t, hcExt.Start(context.Background(), componenttest.NewNopHost()))
+	defer hcExt.Shutdown(context.Background())
+
+	url := "http://localhost:" + strconv.Itoa(int(config.Port))
+	get := func(path string) (int, health) {
+		resp, err := http.Get(url + path)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+		var h health
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&h))
+		return resp.StatusCode, h
+	}
+
+	code, h := get(livenessPath)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, statusUnavailable, h.Status)
+	code, _ = get(readinessPath)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+
+	hcExt.ComponentStatusChanged(component.StatusEvent{Kind: component.KindReceiver, Name: "otlp", Status: component.StatusHealthy})
+	require.NoError(t, hcExt.Ready())
+	code, h = get(readinessPath)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "healthy", h.Status)
+	assert.Equal(t, []componentHealth{{Kind: "receiver", Name: "otlp", Status: "healthy"}}, h.Components)
+
+	hcExt.ComponentStatusChanged(component.StatusEvent{Kind: component.KindReceiver, Name: "otlp", Status: component.StatusFailed, Err: errors.New("bind failed")})
+	code, h = get(livenessPath)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "failed", h.Status)
+	code, _ = get(readinessPath)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+
+	// The root path keeps reporting only whether the pipelines are ready.
+	resp, err := http.Get(url)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
@@ -15,6 +15,8 @@
 package healthcheckextension
 
 import (
+	"time"
+
 	"go.opentelemetry.io/collector/config/configmodels"
 )
 
@@ -26,4 +28,24 @@ type Config struct {
 	// Port is the port used to publish the health check status.
 	// The default value is 13133.
 	Port uint16 `mapstructure:"port"`
+
+	// QueueSaturationThreshold is the ratio of the capacity of the sending queue of an exporter
+	// above which the exporter is reported as degraded, 0 disables the check.
+	// The default value is 0.8.
+	QueueSaturationThreshold float64 `mapstructure:"queue_saturation_threshold"`
+
+	// ExporterFailureThreshold makes the service not ready when an exporter had only failed
+	// exports during a duration, disabled when not set.
+	ExporterFailureThreshold *ExporterFailureThresholdSettings `mapstructure:"exporter_failure_threshold"`
+}
+
+// ExporterFailureThresholdSettings defines the exporters making the service not ready when
+// they fail to export.
+type ExporterFailureThresholdSettings struct {
+	// Exporters are the full names of the exporters checked, all the exporters when empty.
+	Exporters []string `mapstructure:"exporters"`
+
+	// Duration is the duration after which an exporter having only failed exports makes the
+	// service not ready. The default value is 5m.
+	Duration time.Duration `mapstructure:"duration"`
 }
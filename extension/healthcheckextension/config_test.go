@@ -17,6 +17,7 @@ package healthcheckextension
 import (
 	"path"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -47,7 +48,12 @@ func TestLoadConfig(t *testing.T) {
 				TypeVal: "health_check",
 				NameVal: "health_check/1",
 			},
-			Port: 13,
+			Port:                     13,
+			QueueSaturationThreshold: 0.5,
+			ExporterFailureThreshold: &ExporterFailureThresholdSettings{
+				Exporters: []string{"otlp"},
+				Duration:  2 * time.Minute,
+			},
 		},
 		ext1)
 
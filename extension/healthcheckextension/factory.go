@@ -43,7 +43,8 @@ func createDefaultConfig() configmodels.Extension {
 			TypeVal: typeStr,
 			NameVal: typeStr,
 		},
-		Port: 13133,
+		Port:                     13133,
+		QueueSaturationThreshold: 0.8,
 	}
 }
 
@@ -36,7 +36,8 @@ func TestFactory_CreateDefaultConfig(t *testing.T) {
 			NameVal: typeStr,
 			TypeVal: typeStr,
 		},
-		Port: 13133,
+		Port:                     13133,
+		QueueSaturationThreshold: 0.8,
 	},
 		cfg)
 
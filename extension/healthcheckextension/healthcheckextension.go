@@ -19,6 +19,7 @@ import (
 	"net"
 	"net/http"
 	"strconv"
+	"sync"
 
 	"github.com/jaegertracing/jaeger/pkg/healthcheck"
 	"go.uber.org/zap"
@@ -31,9 +32,13 @@ type healthCheckExtension struct {
 	logger *zap.Logger
 	state  *healthcheck.HealthCheck
 	server http.Server
+
+	mu       sync.Mutex
+	statuses map[componentKey]component.StatusEvent
 }
 
 var _ component.PipelineWatcher = (*healthCheckExtension)(nil)
+var _ component.StatusWatcher = (*healthCheckExtension)(nil)
 
 func (hc *healthCheckExtension) Start(_ context.Context, host component.Host) error {
 
@@ -47,8 +52,12 @@ func (hc *healthCheckExtension) Start(_ context.Context, host component.Host) er
 		return nil
 	}
 
-	// Mount HC handler
-	hc.server.Handler = hc.state.Handler()
+	// Mount HC handlers
+	mux := http.NewServeMux()
+	mux.Handle("/", hc.state.Handler())
+	mux.Handle(livenessPath, hc.componentsHandler(func(h health) bool { return h.live }))
+	mux.Handle(readinessPath, hc.componentsHandler(func(h health) bool { return h.ready }))
+	hc.server.Handler = mux
 
 	go func() {
 		// The listener ownership goes to the server.
@@ -76,10 +85,11 @@ func (hc *healthCheckExtension) NotReady() error {
 
 func newServer(config Config, logger *zap.Logger) *healthCheckExtension {
 	hc := &healthCheckExtension{
-		config: config,
-		logger: logger,
-		state:  healthcheck.New(),
-		server: http.Server{},
+		config:   config,
+		logger:   logger,
+		state:    healthcheck.New(),
+		server:   http.Server{},
+		statuses: map[componentKey]component.StatusEvent{},
 	}
 
 	hc.state.SetLogger(logger)
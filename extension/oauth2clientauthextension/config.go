@@ -0,0 +1,54 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oauth2clientauthextension
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/config/configtls"
+)
+
+// Config has the configuration for the extension authenticating the requests of the
+// exporters. Either the client credentials or the token file are required.
+type Config struct {
+	configmodels.ExtensionSettings `mapstructure:",squash"`
+
+	// ClientID is the identifier of the collector for the authorization server.
+	ClientID string `mapstructure:"client_id"`
+
+	// ClientSecret is the secret of the collector for the authorization server.
+	ClientSecret string `mapstructure:"client_secret" sensitive:"true"`
+
+	// TokenURL is the endpoint of the authorization server issuing the tokens.
+	TokenURL string `mapstructure:"token_url"`
+
+	// Scopes are the scopes of the requested tokens, optional.
+	Scopes []string `mapstructure:"scopes"`
+
+	// EndpointParams are the additional parameters of the token requests, e.g. an audience.
+	EndpointParams map[string]string `mapstructure:"endpoint_params"`
+
+	// TLSSetting configures the client connecting to the token endpoint.
+	TLSSetting configtls.TLSClientSetting `mapstructure:"tls,omitempty"`
+
+	// Timeout is the timeout of the token requests.
+	Timeout time.Duration `mapstructure:"timeout"`
+
+	// TokenFile is the file the bearer token is read from, instead of requesting tokens
+	// with the client credentials, e.g. a Kubernetes service account token. The file is
+	// read again when it is modified.
+	TokenFile string `mapstructure:"token_file"`
+}
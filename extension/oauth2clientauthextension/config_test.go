@@ -0,0 +1,81 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oauth2clientauthextension
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/config/configtest"
+	"go.opentelemetry.io/collector/config/configtls"
+)
+
+func TestLoadConfig(t *testing.T) {
+	factories, err := componenttest.NopFactories()
+	assert.NoError(t, err)
+
+	factory := NewFactory()
+	factories.Extensions[typeStr] = factory
+	cfg, err := configtest.LoadConfigFile(t, path.Join(".", "testdata", "config.yaml"), factories)
+
+	require.Nil(t, err)
+	require.NotNil(t, cfg)
+
+	ext0 := cfg.Extensions["oauth2"]
+	defaultCfg := factory.CreateDefaultConfig().(*Config)
+	defaultCfg.ClientID = "collector"
+	defaultCfg.ClientSecret = "secret"
+	defaultCfg.TokenURL = "https://auth.example.com/oauth2/token"
+	assert.Equal(t, defaultCfg, ext0)
+
+	ext1 := cfg.Extensions["oauth2/1"]
+	assert.Equal(t,
+		&Config{
+			ExtensionSettings: configmodels.ExtensionSettings{
+				TypeVal: "oauth2",
+				NameVal: "oauth2/1",
+			},
+			ClientID:       "collector",
+			ClientSecret:   "secret",
+			TokenURL:       "https://auth.example.com/oauth2/token",
+			Scopes:         []string{"api.metrics", "api.traces"},
+			EndpointParams: map[string]string{"audience": "telemetry"},
+			TLSSetting: configtls.TLSClientSetting{
+				TLSSetting: configtls.TLSSetting{CAFile: "/etc/pki/ca.pem"},
+			},
+			Timeout: 2 * time.Second,
+		},
+		ext1)
+
+	ext2 := cfg.Extensions["oauth2/k8s"]
+	assert.Equal(t,
+		&Config{
+			ExtensionSettings: configmodels.ExtensionSettings{
+				TypeVal: "oauth2",
+				NameVal: "oauth2/k8s",
+			},
+			Timeout:   10 * time.Second,
+			TokenFile: "/var/run/secrets/kubernetes.io/serviceaccount/token",
+		},
+		ext2)
+
+	assert.Equal(t, []string{"oauth2/1", "oauth2/k8s"}, cfg.Service.Extensions)
+}
@@ -0,0 +1,146 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oauth2clientauthextension
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configauth"
+)
+
+// clientAuthExtension adds the authorization header to the requests of the exporters
+// referring to it with auth::authenticator.
+type clientAuthExtension struct {
+	config      Config
+	logger      *zap.Logger
+	tokenSource oauth2.TokenSource
+	unregister  func()
+}
+
+var _ configauth.ClientAuthenticator = (*clientAuthExtension)(nil)
+
+func newClientAuthExtension(config Config, logger *zap.Logger) *clientAuthExtension {
+	return &clientAuthExtension{
+		config: config,
+		logger: logger,
+	}
+}
+
+func (e *clientAuthExtension) Start(context.Context, component.Host) error {
+	if e.config.TokenFile != "" {
+		e.tokenSource = &fileTokenSource{path: e.config.TokenFile}
+	} else {
+		ts, err := e.clientCredentialsTokenSource()
+		if err != nil {
+			return err
+		}
+		e.tokenSource = ts
+	}
+
+	unregister, err := configauth.RegisterClientAuthenticator(e.config.Name(), e)
+	if err != nil {
+		return err
+	}
+	e.unregister = unregister
+	e.logger.Info("Starting OAuth2 client authentication extension")
+	return nil
+}
+
+func (e *clientAuthExtension) Shutdown(context.Context) error {
+	if e.unregister != nil {
+		e.unregister()
+	}
+	return nil
+}
+
+// clientCredentialsTokenSource returns the source of the tokens obtained with the OAuth2
+// client credentials flow, the tokens being cached until they expire.
+func (e *clientAuthExtension) clientCredentialsTokenSource() (oauth2.TokenSource, error) {
+	tlsCfg, err := e.config.TLSSetting.LoadTLSConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load TLS config: %w", err)
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsCfg
+	client := &http.Client{Transport: transport, Timeout: e.config.Timeout}
+
+	params := url.Values{}
+	for k, v := range e.config.EndpointParams {
+		params.Set(k, v)
+	}
+	cc := clientcredentials.Config{
+		ClientID:       e.config.ClientID,
+		ClientSecret:   e.config.ClientSecret,
+		TokenURL:       e.config.TokenURL,
+		Scopes:         e.config.Scopes,
+		EndpointParams: params,
+	}
+	return cc.TokenSource(context.WithValue(context.Background(), oauth2.HTTPClient, client)), nil
+}
+
+// RequestHeaders returns the authorization header with the current token.
+func (e *clientAuthExtension) RequestHeaders(context.Context) (map[string]string, error) {
+	token, err := e.tokenSource.Token()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the OAuth2 token: %w", err)
+	}
+	return map[string]string{"authorization": token.Type() + " " + token.AccessToken}, nil
+}
+
+// fileTokenSource reads the bearer token from a file, the file being read again when
+// its modification time changes so that the rotated tokens are used.
+type fileTokenSource struct {
+	path string
+
+	mu      sync.Mutex
+	modTime time.Time
+	token   *oauth2.Token
+}
+
+func (s *fileTokenSource) Token() (*oauth2.Token, error) {
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token != nil && info.ModTime().Equal(s.modTime) {
+		return s.token, nil
+	}
+	b, err := ioutil.ReadFile(s.path)
+	if err != nil {
+		return nil, err
+	}
+	value := strings.TrimSpace(string(b))
+	if value == "" {
+		return nil, fmt.Errorf("the token file %q is empty", s.path)
+	}
+	s.token = &oauth2.Token{AccessToken: value, TokenType: "Bearer"}
+	s.modTime = info.ModTime()
+	return s.token, nil
+}
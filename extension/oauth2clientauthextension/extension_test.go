@@ -0,0 +1,148 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oauth2clientauthextension
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configauth"
+	"go.opentelemetry.io/collector/config/confighttp"
+)
+
+func TestClientCredentials(t *testing.T) {
+	var requests int32
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "client_credentials", r.Form.Get("grant_type"))
+		assert.Equal(t, "api.traces", r.Form.Get("scope"))
+		assert.Equal(t, "telemetry", r.Form.Get("audience"))
+		user, password, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "collector", user)
+		assert.Equal(t, "secret", password)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"token-1","token_type":"Bearer","expires_in":3600}`))
+	}))
+	defer tokenServer.Close()
+
+	var authorization string
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+	}))
+	defer backend.Close()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.NameVal = "oauth2/test"
+	cfg.ClientID = "collector"
+	cfg.ClientSecret = "secret"
+	cfg.TokenURL = tokenServer.URL
+	cfg.Scopes = []string{"api.traces"}
+	cfg.EndpointParams = map[string]string{"audience": "telemetry"}
+	ext := newClientAuthExtension(*cfg, zap.NewNop())
+	require.NoError(t, ext.Start(context.Background(), componenttest.NewNopHost()))
+
+	clientSettings := confighttp.HTTPClientSettings{
+		Endpoint: backend.URL,
+		Auth:     &configauth.ClientAuthentication{AuthenticatorName: "oauth2/test"},
+	}
+	client, err := clientSettings.ToClient()
+	require.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(backend.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+	assert.Equal(t, "Bearer token-1", authorization)
+	// The token is cached until it expires.
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+
+	require.NoError(t, ext.Shutdown(context.Background()))
+	_, err = clientSettings.ToClient()
+	assert.Error(t, err)
+}
+
+func TestClientCredentialsError(t *testing.T) {
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer tokenServer.Close()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.ClientID = "collector"
+	cfg.ClientSecret = "wrong"
+	cfg.TokenURL = tokenServer.URL
+	ext := newClientAuthExtension(*cfg, zap.NewNop())
+	require.NoError(t, ext.Start(context.Background(), componenttest.NewNopHost()))
+	defer ext.Shutdown(context.Background())
+
+	_, err := ext.RequestHeaders(context.Background())
+	assert.Error(t, err)
+}
+
+func TestTokenFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "oauth2clientauth")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	tokenFile := filepath.Join(dir, "token")
+	require.NoError(t, ioutil.WriteFile(tokenFile, []byte("token-1\n"), 0600))
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.NameVal = "oauth2/file"
+	cfg.TokenFile = tokenFile
+	ext := newClientAuthExtension(*cfg, zap.NewNop())
+	require.NoError(t, ext.Start(context.Background(), componenttest.NewNopHost()))
+	defer ext.Shutdown(context.Background())
+
+	headers, err := ext.RequestHeaders(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"authorization": "Bearer token-1"}, headers)
+
+	// The rotated token is read again.
+	require.NoError(t, ioutil.WriteFile(tokenFile, []byte("token-2"), 0600))
+	modTime := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(tokenFile, modTime, modTime))
+	headers, err = ext.RequestHeaders(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"authorization": "Bearer token-2"}, headers)
+
+	require.NoError(t, os.Remove(tokenFile))
+	_, err = ext.RequestHeaders(context.Background())
+	assert.Error(t, err)
+}
+
+func TestStartDuplicateName(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.NameVal = "oauth2/duplicate"
+	cfg.TokenFile = "token"
+	ext := newClientAuthExtension(*cfg, zap.NewNop())
+	require.NoError(t, ext.Start(context.Background(), componenttest.NewNopHost()))
+	defer ext.Shutdown(context.Background())
+
+	assert.Error(t, newClientAuthExtension(*cfg, zap.NewNop()).Start(context.Background(), componenttest.NewNopHost()))
+}
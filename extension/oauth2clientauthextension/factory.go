@@ -0,0 +1,67 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oauth2clientauthextension
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/extension/extensionhelper"
+)
+
+const (
+	// The value of extension "type" in configuration.
+	typeStr = "oauth2"
+)
+
+var (
+	errNoCredentials   = errors.New("either \"token_file\" or \"client_id\", \"client_secret\" and \"token_url\" are required")
+	errBothCredentials = errors.New("\"token_file\" cannot be set with \"client_id\", \"client_secret\" and \"token_url\"")
+)
+
+// NewFactory creates a factory for the OAuth2 client authentication extension.
+func NewFactory() component.ExtensionFactory {
+	return extensionhelper.NewFactory(
+		typeStr,
+		createDefaultConfig,
+		createExtension)
+}
+
+func createDefaultConfig() configmodels.Extension {
+	return &Config{
+		ExtensionSettings: configmodels.ExtensionSettings{
+			TypeVal: typeStr,
+			NameVal: typeStr,
+		},
+		Timeout: 10 * time.Second,
+	}
+}
+
+// createExtension creates the extension based on this config.
+func createExtension(_ context.Context, params component.ExtensionCreateParams, cfg configmodels.Extension) (component.Extension, error) {
+	config := cfg.(*Config)
+	clientCredentials := config.ClientID != "" || config.ClientSecret != "" || config.TokenURL != ""
+	switch {
+	case config.TokenFile != "" && clientCredentials:
+		return nil, errBothCredentials
+	case config.TokenFile != "":
+	case config.ClientID == "" || config.ClientSecret == "" || config.TokenURL == "":
+		return nil, errNoCredentials
+	}
+	return newClientAuthExtension(*config, params.Logger), nil
+}
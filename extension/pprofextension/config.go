@@ -15,6 +15,8 @@
 package pprofextension
 
 import (
+	"time"
+
 	"go.opentelemetry.io/collector/config/configmodels"
 )
 
@@ -40,4 +42,33 @@ type Config struct {
 	// Optional file name to save the CPU profile to. The profiling starts when the
 	// Collector starts and is saved to the file when the Collector is terminated.
 	SaveToFile string `mapstructure:"save_to_file"`
+
+	// MemoryProfiles writes heap and goroutine profiles to a directory when the memory
+	// usage is high, so that they exist after the collector is killed for running out
+	// of memory. Disabled if nil.
+	MemoryProfiles *MemoryProfilesConfig `mapstructure:"memory_profiles"`
+}
+
+// MemoryProfilesConfig configures when the heap and goroutine profiles are written.
+type MemoryProfilesConfig struct {
+	// Directory is the directory where the profiles are written. Required.
+	Directory string `mapstructure:"directory"`
+
+	// MemoryLimitMiB is the heap size, in MiB, above which the profiles are written.
+	// Only the memory limiter triggers the profiles if 0.
+	MemoryLimitMiB uint64 `mapstructure:"memory_limit_mib"`
+
+	// OnMemoryLimiter writes the profiles when a memory limiter processor refuses data.
+	OnMemoryLimiter bool `mapstructure:"on_memory_limiter"`
+
+	// CheckInterval is the interval at which the memory usage is checked, 5s if 0.
+	CheckInterval time.Duration `mapstructure:"check_interval"`
+
+	// MinInterval is the minimum interval between two writes of the profiles, so that
+	// they are not written continuously while the memory usage stays high, 5m if 0.
+	MinInterval time.Duration `mapstructure:"min_interval"`
+
+	// MaxProfiles is the number of profiles of each kind kept in the directory, the
+	// oldest being removed, 10 if 0.
+	MaxProfiles int `mapstructure:"max_profiles"`
 }
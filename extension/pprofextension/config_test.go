@@ -17,6 +17,7 @@ package pprofextension
 import (
 	"path"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -53,6 +54,25 @@ func TestLoadConfig(t *testing.T) {
 		},
 		ext1)
 
+	ext2 := cfg.Extensions["pprof/2"]
+	assert.Equal(t,
+		&Config{
+			ExtensionSettings: configmodels.ExtensionSettings{
+				TypeVal: "pprof",
+				NameVal: "pprof/2",
+			},
+			Endpoint: "localhost:1777",
+			MemoryProfiles: &MemoryProfilesConfig{
+				Directory:       "/var/lib/otelcol/profiles",
+				MemoryLimitMiB:  1800,
+				OnMemoryLimiter: true,
+				CheckInterval:   10 * time.Second,
+				MinInterval:     10 * time.Minute,
+				MaxProfiles:     3,
+			},
+		},
+		ext2)
+
 	assert.Equal(t, 1, len(cfg.Service.Extensions))
 	assert.Equal(t, "pprof/1", cfg.Service.Extensions[0])
 }
@@ -52,6 +52,17 @@ func createExtension(_ context.Context, params component.ExtensionCreateParams,
 	if config.Endpoint == "" {
 		return nil, errors.New("\"endpoint\" is required when using the \"pprof\" extension")
 	}
+	if mp := config.MemoryProfiles; mp != nil {
+		if mp.Directory == "" {
+			return nil, errors.New("\"memory_profiles::directory\" is required")
+		}
+		if mp.MemoryLimitMiB == 0 && !mp.OnMemoryLimiter {
+			return nil, errors.New("\"memory_profiles\" requires \"memory_limit_mib\" or \"on_memory_limiter\"")
+		}
+		if mp.CheckInterval < 0 || mp.MinInterval < 0 || mp.MaxProfiles < 0 {
+			return nil, errors.New("\"memory_profiles\" intervals and \"max_profiles\" cannot be negative")
+		}
+	}
 
 	// The runtime settings are global to the application, so while in principle it
 	// is possible to have more than one instance, running multiple will mean that
@@ -18,6 +18,7 @@ import (
 	"context"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -76,3 +77,33 @@ func TestFactory_CreateExtensionOnlyOnce(t *testing.T) {
 	// Restore instance tracking from factory, for other tests.
 	atomic.StoreInt32(&instanceState, instanceNotCreated)
 }
+
+func TestFactory_CreateExtensionInvalidMemoryProfiles(t *testing.T) {
+	tests := []struct {
+		name   string
+		config MemoryProfilesConfig
+	}{
+		{
+			name:   "no directory",
+			config: MemoryProfilesConfig{MemoryLimitMiB: 100},
+		},
+		{
+			name:   "no trigger",
+			config: MemoryProfilesConfig{Directory: "profiles"},
+		},
+		{
+			name:   "negative interval",
+			config: MemoryProfilesConfig{Directory: "profiles", OnMemoryLimiter: true, MinInterval: -time.Second},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.MemoryProfiles = &tt.config
+
+			ext, err := createExtension(context.Background(), component.ExtensionCreateParams{Logger: zap.NewNop()}, cfg)
+			assert.Error(t, err)
+			assert.Nil(t, ext)
+		})
+	}
+}
@@ -0,0 +1,189 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pprofextension
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/processor/memorylimiter/memorystate"
+)
+
+const (
+	defaultCheckInterval = 5 * time.Second
+	defaultMinInterval   = 5 * time.Minute
+	defaultMaxProfiles   = 10
+
+	profileTimeFormat = "20060102T150405.000Z"
+)
+
+// memoryProfileKinds are the profiles written when the memory usage is high.
+var memoryProfileKinds = []string{"heap", "goroutine"}
+
+// memoryProfiler writes the heap and goroutine profiles to a directory when the heap size
+// crosses the limit or when a memory limiter refuses data.
+type memoryProfiler struct {
+	config MemoryProfilesConfig
+	logger *zap.Logger
+
+	// heapSize and memoryLevel are replaced by the tests.
+	heapSize    func() uint64
+	memoryLevel func() memorystate.Level
+
+	lastWrite time.Time
+	stopCh    chan struct{}
+	stopOnce  sync.Once
+	wg        sync.WaitGroup
+}
+
+func newMemoryProfiler(config MemoryProfilesConfig, logger *zap.Logger) *memoryProfiler {
+	if config.CheckInterval == 0 {
+		config.CheckInterval = defaultCheckInterval
+	}
+	if config.MinInterval == 0 {
+		config.MinInterval = defaultMinInterval
+	}
+	if config.MaxProfiles == 0 {
+		config.MaxProfiles = defaultMaxProfiles
+	}
+	return &memoryProfiler{
+		config:      config,
+		logger:      logger,
+		heapSize:    readHeapSize,
+		memoryLevel: memorystate.Current,
+		stopCh:      make(chan struct{}),
+	}
+}
+
+func readHeapSize() uint64 {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	return ms.HeapAlloc
+}
+
+func (mp *memoryProfiler) start() error {
+	if err := os.MkdirAll(mp.config.Directory, 0700); err != nil {
+		return fmt.Errorf("failed to create the memory profiles directory: %w", err)
+	}
+	mp.wg.Add(1)
+	go func() {
+		defer mp.wg.Done()
+		ticker := time.NewTicker(mp.config.CheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case now := <-ticker.C:
+				mp.check(now)
+			case <-mp.stopCh:
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+func (mp *memoryProfiler) stop() {
+	mp.stopOnce.Do(func() {
+		close(mp.stopCh)
+	})
+	mp.wg.Wait()
+}
+
+// check writes the profiles if the memory usage is high, at most once per MinInterval.
+func (mp *memoryProfiler) check(now time.Time) {
+	if !mp.lastWrite.IsZero() && now.Sub(mp.lastWrite) < mp.config.MinInterval {
+		return
+	}
+	reason := ""
+	if mp.config.OnMemoryLimiter {
+		if level := mp.memoryLevel(); level != memorystate.Normal {
+			reason = "memory limiter " + level.String()
+		}
+	}
+	if heapSize := mp.heapSize(); reason == "" && mp.config.MemoryLimitMiB > 0 && heapSize > mp.config.MemoryLimitMiB*1024*1024 {
+		reason = fmt.Sprintf("heap size %d MiB above the limit", heapSize/1024/1024)
+	}
+	if reason == "" {
+		return
+	}
+
+	mp.lastWrite = now
+	paths, err := mp.write(now)
+	if err != nil {
+		mp.logger.Error("Failed to write the memory profiles", zap.String("reason", reason), zap.Error(err))
+		return
+	}
+	mp.logger.Warn("Wrote the memory profiles", zap.String("reason", reason), zap.Strings("files", paths))
+	mp.prune()
+}
+
+// write writes the profiles, the files being named after the kind and the time, e.g.
+// "heap-20210301T101112.123Z.pprof".
+func (mp *memoryProfiler) write(now time.Time) ([]string, error) {
+	var paths []string
+	for _, kind := range memoryProfileKinds {
+		path := filepath.Join(mp.config.Directory, kind+"-"+now.UTC().Format(profileTimeFormat)+".pprof")
+		f, err := os.Create(path)
+		if err != nil {
+			return paths, err
+		}
+		err = pprof.Lookup(kind).WriteTo(f, 0)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return paths, err
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// prune removes the oldest profiles of each kind beyond MaxProfiles.
+func (mp *memoryProfiler) prune() {
+	infos, err := ioutil.ReadDir(mp.config.Directory)
+	if err != nil {
+		mp.logger.Error("Failed to list the memory profiles", zap.Error(err))
+		return
+	}
+	for _, kind := range memoryProfileKinds {
+		var names []string
+		for _, info := range infos {
+			if strings.HasPrefix(info.Name(), kind+"-") && strings.HasSuffix(info.Name(), ".pprof") {
+				names = append(names, info.Name())
+			}
+		}
+		if len(names) <= mp.config.MaxProfiles {
+			continue
+		}
+		// The names sort by time.
+		sort.Strings(names)
+		for _, name := range names[:len(names)-mp.config.MaxProfiles] {
+			if err := os.Remove(filepath.Join(mp.config.Directory, name)); err != nil {
+				mp.logger.Error("Failed to remove a memory profile", zap.Error(err))
+			}
+		}
+	}
+}
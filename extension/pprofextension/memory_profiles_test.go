@@ -0,0 +1,138 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pprofextension
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/processor/memorylimiter/memorystate"
+	"go.opentelemetry.io/collector/testutil"
+)
+
+func profileFiles(t *testing.T, dir string) []string {
+	infos, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	var names []string
+	for _, info := range infos {
+		names = append(names, info.Name())
+	}
+	return names
+}
+
+type hardLimitedProvider struct{}
+
+func (hardLimitedProvider) MemoryLevel() memorystate.Level {
+	return memorystate.HardLimited
+}
+
+func TestMemoryProfiler(t *testing.T) {
+	dir, err := ioutil.TempDir("", "memory_profiles")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	mp := newMemoryProfiler(MemoryProfilesConfig{
+		Directory:      dir,
+		MemoryLimitMiB: 100,
+		MinInterval:    time.Minute,
+		MaxProfiles:    2,
+	}, zap.NewNop())
+	heapSize := uint64(50 * 1024 * 1024)
+	mp.heapSize = func() uint64 { return heapSize }
+	mp.memoryLevel = func() memorystate.Level { return memorystate.HardLimited }
+	now := time.Date(2021, 3, 1, 10, 11, 12, 0, time.UTC)
+
+	// The memory limiter is ignored if not enabled.
+	mp.check(now)
+	assert.Empty(t, profileFiles(t, dir))
+
+	heapSize = 150 * 1024 * 1024
+	mp.check(now)
+	assert.Equal(t, []string{"goroutine-20210301T101112.000Z.pprof", "heap-20210301T101112.000Z.pprof"}, profileFiles(t, dir))
+	info, err := os.Stat(filepath.Join(dir, "heap-20210301T101112.000Z.pprof"))
+	require.NoError(t, err)
+	assert.NotZero(t, info.Size())
+
+	// The profiles are written at most once per min_interval.
+	mp.check(now.Add(30 * time.Second))
+	assert.Len(t, profileFiles(t, dir), 2)
+
+	mp.check(now.Add(time.Minute))
+	mp.check(now.Add(2 * time.Minute))
+	assert.Equal(t, []string{
+		"goroutine-20210301T101212.000Z.pprof",
+		"goroutine-20210301T101312.000Z.pprof",
+		"heap-20210301T101212.000Z.pprof",
+		"heap-20210301T101312.000Z.pprof",
+	}, profileFiles(t, dir))
+}
+
+func TestMemoryProfilerOnMemoryLimiter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "memory_profiles")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	mp := newMemoryProfiler(MemoryProfilesConfig{
+		Directory:       dir,
+		OnMemoryLimiter: true,
+	}, zap.NewNop())
+	assert.Equal(t, defaultCheckInterval, mp.config.CheckInterval)
+	assert.Equal(t, defaultMinInterval, mp.config.MinInterval)
+	assert.Equal(t, defaultMaxProfiles, mp.config.MaxProfiles)
+	level := memorystate.Normal
+	mp.memoryLevel = func() memorystate.Level { return level }
+	now := time.Now()
+
+	mp.check(now)
+	assert.Empty(t, profileFiles(t, dir))
+
+	level = memorystate.SoftLimited
+	mp.check(now)
+	assert.Len(t, profileFiles(t, dir), 2)
+}
+
+func TestPerformanceProfilerMemoryProfiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "memory_profiles")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	config := Config{
+		Endpoint: testutil.GetAvailableLocalAddress(t),
+		MemoryProfiles: &MemoryProfilesConfig{
+			Directory:       filepath.Join(dir, "profiles"),
+			OnMemoryLimiter: true,
+			CheckInterval:   time.Millisecond,
+		},
+	}
+	unregister := memorystate.Register(hardLimitedProvider{})
+	defer unregister()
+	pprofExt := newServer(config, zap.NewNop())
+	require.NoError(t, pprofExt.Start(context.Background(), componenttest.NewNopHost()))
+
+	testutil.WaitFor(t, func() bool {
+		infos, err := ioutil.ReadDir(config.MemoryProfiles.Directory)
+		return err == nil && len(infos) == 2
+	}, "write the memory profiles")
+	require.NoError(t, pprofExt.Shutdown(context.Background()))
+}
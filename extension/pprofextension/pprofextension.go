@@ -33,6 +33,7 @@ type pprofExtension struct {
 	logger *zap.Logger
 	file   *os.File
 	server http.Server
+	memory *memoryProfiler
 }
 
 func (p *pprofExtension) Start(_ context.Context, host component.Host) error {
@@ -54,6 +55,13 @@ func (p *pprofExtension) Start(_ context.Context, host component.Host) error {
 		}
 	}()
 
+	if p.config.MemoryProfiles != nil {
+		p.memory = newMemoryProfiler(*p.config.MemoryProfiles, p.logger)
+		if err := p.memory.start(); err != nil {
+			return err
+		}
+	}
+
 	if p.config.SaveToFile != "" {
 		f, err := os.Create(p.config.SaveToFile)
 		if err != nil {
@@ -67,6 +75,9 @@ func (p *pprofExtension) Start(_ context.Context, host component.Host) error {
 }
 
 func (p *pprofExtension) Shutdown(context.Context) error {
+	if p.memory != nil {
+		p.memory.stop()
+	}
 	if p.file != nil {
 		pprof.StopCPUProfile()
 		p.file.Close() // ignore the error
@@ -0,0 +1,44 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remoteconfigextension
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/config/configmodels"
+)
+
+// Config has the configuration for the extension connecting the collector to a
+// management server.
+type Config struct {
+	configmodels.ExtensionSettings `mapstructure:",squash"`
+
+	// HTTPClientSettings configures the client connecting to the management server,
+	// the status reports are posted to Endpoint.
+	confighttp.HTTPClientSettings `mapstructure:",squash"`
+
+	// InstanceID identifies the collector for the management server, the host name
+	// is used when empty.
+	InstanceID string `mapstructure:"instance_id"`
+
+	// PollInterval is the interval between two status reports, the management server
+	// answering them with the configuration to apply.
+	PollInterval time.Duration `mapstructure:"poll_interval"`
+
+	// AcceptRemoteConfig applies the configurations sent by the management server
+	// when true, otherwise only the status of the collector is reported.
+	AcceptRemoteConfig bool `mapstructure:"accept_remote_config"`
+}
@@ -0,0 +1,80 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remoteconfigextension
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// The messages exchanged with the management server, encoded in JSON. The collector
+// posts a statusReport to the server every poll interval and the server answers with
+// a serverResponse holding the configuration the collector should run with, if any.
+
+const (
+	remoteConfigApplied = "applied"
+	remoteConfigFailed  = "failed"
+)
+
+// statusReport describes the collector and its status.
+type statusReport struct {
+	InstanceID string           `json:"instance_id"`
+	Agent      agentDescription `json:"agent"`
+	// Health is the overall status of the collector, the worst status of its components.
+	Health     string            `json:"health"`
+	Components []componentStatus `json:"components,omitempty"`
+	// EffectiveConfig is only reported when it changed since the last report that
+	// reached the server.
+	EffectiveConfig *configBody `json:"effective_config,omitempty"`
+	// RemoteConfigStatus is the result of applying the last configuration sent by the server.
+	RemoteConfigStatus *remoteConfigStatus `json:"remote_config_status,omitempty"`
+}
+
+type agentDescription struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	GitHash string `json:"git_hash,omitempty"`
+}
+
+type componentStatus struct {
+	Kind     string `json:"kind"`
+	Name     string `json:"name"`
+	Pipeline string `json:"pipeline,omitempty"`
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+}
+
+// configBody is a YAML configuration of the collector.
+type configBody struct {
+	Body string `json:"body,omitempty"`
+	// Hash identifies the configuration, the SHA-256 of the body when not set by the server.
+	Hash string `json:"hash"`
+}
+
+type remoteConfigStatus struct {
+	Hash   string `json:"hash"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// serverResponse is the answer of the management server to a statusReport.
+type serverResponse struct {
+	RemoteConfig *configBody `json:"remote_config,omitempty"`
+}
+
+func hashConfig(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,351 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remoteconfigextension
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+)
+
+// configHost is implemented by the hosts whose configuration can be replaced while they run.
+type configHost interface {
+	GetEffectiveConfig() ([]byte, error)
+	ApplyConfig(ctx context.Context, config []byte) error
+}
+
+type componentKey struct {
+	kind     component.Kind
+	name     string
+	pipeline string
+}
+
+type remoteConfigExtension struct {
+	config     Config
+	logger     *zap.Logger
+	agent      agentDescription
+	instanceID string
+	client     *http.Client
+	host       configHost
+
+	// ready is set while the pipelines run, the configurations are only applied then.
+	ready int32
+
+	mu                 sync.Mutex
+	components         map[componentKey]component.StatusEvent
+	remoteStatus       *remoteConfigStatus
+	reportedConfigHash string
+
+	// readyCh triggers a report when the pipelines are ready, without waiting for the
+	// next poll.
+	readyCh chan struct{}
+	stopCh  chan struct{}
+	done    chan struct{}
+}
+
+var _ component.PipelineWatcher = (*remoteConfigExtension)(nil)
+var _ component.StatusWatcher = (*remoteConfigExtension)(nil)
+
+func newRemoteConfigExtension(config Config, params component.ExtensionCreateParams) (*remoteConfigExtension, error) {
+	instanceID := config.InstanceID
+	if instanceID == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return nil, fmt.Errorf("cannot get the host name to identify the collector, set \"instance_id\": %w", err)
+		}
+		instanceID = hostname
+	}
+	return &remoteConfigExtension{
+		config: config,
+		logger: params.Logger,
+		agent: agentDescription{
+			Name:    params.ApplicationStartInfo.ExeName,
+			Version: params.ApplicationStartInfo.Version,
+			GitHash: params.ApplicationStartInfo.GitHash,
+		},
+		instanceID: instanceID,
+		components: make(map[componentKey]component.StatusEvent),
+		readyCh:    make(chan struct{}, 1),
+	}, nil
+}
+
+func (e *remoteConfigExtension) Start(_ context.Context, host component.Host) error {
+	client, err := e.config.HTTPClientSettings.ToClient()
+	if err != nil {
+		return err
+	}
+	e.client = client
+
+	if ch, ok := host.(configHost); ok {
+		e.host = ch
+	} else if e.config.AcceptRemoteConfig {
+		e.logger.Warn("The configuration of the host cannot be replaced, only the status is reported")
+	}
+
+	e.logger.Info("Starting remote configuration extension",
+		zap.String("endpoint", e.config.Endpoint), zap.String("instance_id", e.instanceID))
+	e.stopCh = make(chan struct{})
+	e.done = make(chan struct{})
+	go e.run()
+	return nil
+}
+
+func (e *remoteConfigExtension) Shutdown(context.Context) error {
+	if e.stopCh == nil {
+		return nil
+	}
+	close(e.stopCh)
+	<-e.done
+	return nil
+}
+
+func (e *remoteConfigExtension) Ready() error {
+	atomic.StoreInt32(&e.ready, 1)
+	select {
+	case e.readyCh <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func (e *remoteConfigExtension) NotReady() error {
+	atomic.StoreInt32(&e.ready, 0)
+	// The pipelines are stopped, the status of their components is reported again when
+	// they are started.
+	e.mu.Lock()
+	for k := range e.components {
+		if k.kind != component.KindExtension {
+			delete(e.components, k)
+		}
+	}
+	e.mu.Unlock()
+	return nil
+}
+
+func (e *remoteConfigExtension) ComponentStatusChanged(event component.StatusEvent) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.components[componentKey{kind: event.Kind, name: event.Name, pipeline: event.Pipeline}] = event
+}
+
+// run reports the status every poll interval until the extension is shut down.
+func (e *remoteConfigExtension) run() {
+	defer close(e.done)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-e.stopCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	ticker := time.NewTicker(e.config.PollInterval)
+	defer ticker.Stop()
+	for {
+		// Report the result of a configuration right after applying it.
+		for e.poll(ctx) {
+		}
+		select {
+		case <-ticker.C:
+		case <-e.readyCh:
+		case <-e.stopCh:
+			return
+		}
+	}
+}
+
+// poll reports the status to the server and applies the configuration it answers with,
+// it returns true when a configuration was applied.
+func (e *remoteConfigExtension) poll(ctx context.Context) bool {
+	report, configHash := e.buildReport()
+	resp, err := e.send(ctx, report)
+	if err != nil {
+		e.logger.Warn("Failed to report the status to the management server", zap.Error(err))
+		return false
+	}
+
+	e.mu.Lock()
+	e.reportedConfigHash = configHash
+	e.mu.Unlock()
+
+	if resp.RemoteConfig == nil || !e.config.AcceptRemoteConfig || e.host == nil || atomic.LoadInt32(&e.ready) == 0 {
+		return false
+	}
+	body := []byte(resp.RemoteConfig.Body)
+	hash := resp.RemoteConfig.Hash
+	if hash == "" {
+		hash = hashConfig(body)
+	}
+	e.mu.Lock()
+	attempted := e.remoteStatus != nil && e.remoteStatus.Hash == hash
+	e.mu.Unlock()
+	// A configuration is applied once, even when it failed to be applied.
+	if attempted {
+		return false
+	}
+
+	e.logger.Info("Applying the configuration received from the management server", zap.String("hash", hash))
+	status := &remoteConfigStatus{Hash: hash, Status: remoteConfigApplied}
+	if err := e.host.ApplyConfig(ctx, body); err != nil {
+		e.logger.Error("Failed to apply the configuration received from the management server", zap.Error(err))
+		status.Status = remoteConfigFailed
+		status.Error = err.Error()
+	}
+	e.mu.Lock()
+	e.remoteStatus = status
+	e.mu.Unlock()
+	return true
+}
+
+// buildReport returns the status report and the hash of the effective configuration.
+func (e *remoteConfigExtension) buildReport() (*statusReport, string) {
+	report := &statusReport{
+		InstanceID: e.instanceID,
+		Agent:      e.agent,
+	}
+
+	var configHash string
+	if e.host != nil {
+		cfg, err := e.host.GetEffectiveConfig()
+		if err != nil {
+			e.logger.Warn("Failed to get the effective configuration", zap.Error(err))
+		} else {
+			configHash = hashConfig(cfg)
+			report.EffectiveConfig = &configBody{Body: string(cfg), Hash: configHash}
+		}
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if report.EffectiveConfig != nil && configHash == e.reportedConfigHash {
+		// The server already has the effective configuration, only send its hash.
+		report.EffectiveConfig.Body = ""
+	}
+	if e.remoteStatus != nil {
+		rs := *e.remoteStatus
+		report.RemoteConfigStatus = &rs
+	}
+	health := component.StatusHealthy
+	if atomic.LoadInt32(&e.ready) == 0 {
+		health = component.StatusStarting
+	}
+	for _, event := range e.components {
+		cs := componentStatus{
+			Kind:     kindString(event.Kind),
+			Name:     event.Name,
+			Pipeline: event.Pipeline,
+			Status:   event.Status.String(),
+		}
+		if event.Err != nil {
+			cs.Error = event.Err.Error()
+		}
+		report.Components = append(report.Components, cs)
+		health = worseStatus(health, event.Status)
+	}
+	report.Health = health.String()
+	sort.Slice(report.Components, func(i, j int) bool {
+		a, b := report.Components[i], report.Components[j]
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Pipeline < b.Pipeline
+	})
+	return report, configHash
+}
+
+func (e *remoteConfigExtension) send(ctx context.Context, report *statusReport) (*serverResponse, error) {
+	body, err := json.Marshal(report)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.config.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		// Drain the body so that the connection is reused.
+		_, _ = io.Copy(ioutil.Discard, resp.Body)
+		resp.Body.Close()
+	}()
+
+	if resp.StatusCode == http.StatusNoContent {
+		return &serverResponse{}, nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("the management server answered with status %q", resp.Status)
+	}
+	sr := &serverResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(sr); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("cannot decode the answer of the management server: %w", err)
+	}
+	return sr, nil
+}
+
+// worseStatus returns the worst of two statuses, failed being worse than degraded, itself
+// worse than starting.
+func worseStatus(a, b component.Status) component.Status {
+	rank := func(s component.Status) int {
+		switch s {
+		case component.StatusFailed:
+			return 3
+		case component.StatusDegraded:
+			return 2
+		case component.StatusStarting:
+			return 1
+		}
+		return 0
+	}
+	if rank(b) > rank(a) {
+		return b
+	}
+	return a
+}
+
+func kindString(kind component.Kind) string {
+	switch kind {
+	case component.KindReceiver:
+		return "receiver"
+	case component.KindProcessor:
+		return "processor"
+	case component.KindExporter:
+		return "exporter"
+	case component.KindExtension:
+		return "extension"
+	}
+	return "unknown"
+}
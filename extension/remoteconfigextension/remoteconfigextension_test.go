@@ -0,0 +1,243 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remoteconfigextension
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+)
+
+// fakeHost is a host whose configuration can be replaced, the configurations
+// containing "invalid" failing to be applied.
+type fakeHost struct {
+	component.Host
+	mu      sync.Mutex
+	config  string
+	applied []string
+}
+
+func (h *fakeHost) GetEffectiveConfig() ([]byte, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return []byte(h.config), nil
+}
+
+func (h *fakeHost) ApplyConfig(_ context.Context, config []byte) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.applied = append(h.applied, string(config))
+	if string(config) == "invalid" {
+		return errors.New("invalid configuration")
+	}
+	h.config = string(config)
+	return nil
+}
+
+func (h *fakeHost) appliedConfigs() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]string(nil), h.applied...)
+}
+
+// fakeServer records the status reports and answers them with remoteConfig.
+type fakeServer struct {
+	mu           sync.Mutex
+	reports      []statusReport
+	remoteConfig *configBody
+}
+
+func (s *fakeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var report statusReport
+	if err := json.NewDecoder(r.Body).Decode(&report); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reports = append(s.reports, report)
+	if s.remoteConfig == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(serverResponse{RemoteConfig: s.remoteConfig})
+}
+
+func (s *fakeServer) setRemoteConfig(body, hash string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.remoteConfig = &configBody{Body: body, Hash: hash}
+}
+
+func (s *fakeServer) lastReport() (statusReport, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.reports) == 0 {
+		return statusReport{}, 0
+	}
+	return s.reports[len(s.reports)-1], len(s.reports)
+}
+
+func newTestExtension(t *testing.T, endpoint string) *remoteConfigExtension {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = endpoint
+	cfg.InstanceID = "test"
+	cfg.PollInterval = 10 * time.Millisecond
+	ext, err := newRemoteConfigExtension(*cfg, component.ExtensionCreateParams{
+		Logger:               zap.NewNop(),
+		ApplicationStartInfo: component.DefaultApplicationStartInfo(),
+	})
+	require.NoError(t, err)
+	return ext
+}
+
+func TestRemoteConfigExtension_ReportStatus(t *testing.T) {
+	server := &fakeServer{}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	ext := newTestExtension(t, ts.URL)
+	host := &fakeHost{Host: componenttest.NewNopHost(), config: "receivers:\n"}
+	ext.ComponentStatusChanged(component.StatusEvent{Kind: component.KindReceiver, Name: "otlp", Status: component.StatusHealthy})
+	ext.ComponentStatusChanged(component.StatusEvent{Kind: component.KindExporter, Name: "otlp", Status: component.StatusFailed, Err: errors.New("cannot connect")})
+	require.NoError(t, ext.Start(context.Background(), host))
+	defer func() { assert.NoError(t, ext.Shutdown(context.Background())) }()
+
+	require.Eventually(t, func() bool {
+		_, n := server.lastReport()
+		return n >= 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	server.mu.Lock()
+	first := server.reports[0]
+	second := server.reports[1]
+	server.mu.Unlock()
+	assert.Equal(t, "test", first.InstanceID)
+	assert.Equal(t, component.DefaultApplicationStartInfo().Version, first.Agent.Version)
+	assert.Equal(t, "failed", first.Health)
+	assert.Equal(t, []componentStatus{
+		{Kind: "exporter", Name: "otlp", Status: "failed", Error: "cannot connect"},
+		{Kind: "receiver", Name: "otlp", Status: "healthy"},
+	}, first.Components)
+	require.NotNil(t, first.EffectiveConfig)
+	assert.Equal(t, "receivers:\n", first.EffectiveConfig.Body)
+	assert.Equal(t, hashConfig([]byte("receivers:\n")), first.EffectiveConfig.Hash)
+
+	// The configuration was received by the server, only its hash is sent again.
+	require.NotNil(t, second.EffectiveConfig)
+	assert.Equal(t, "", second.EffectiveConfig.Body)
+	assert.Equal(t, first.EffectiveConfig.Hash, second.EffectiveConfig.Hash)
+}
+
+func TestRemoteConfigExtension_ApplyConfig(t *testing.T) {
+	server := &fakeServer{}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	ext := newTestExtension(t, ts.URL)
+	host := &fakeHost{Host: componenttest.NewNopHost(), config: "initial"}
+	server.setRemoteConfig("invalid", "1")
+	require.NoError(t, ext.Start(context.Background(), host))
+	defer func() { assert.NoError(t, ext.Shutdown(context.Background())) }()
+
+	// The configuration is not applied until the pipelines are ready.
+	time.Sleep(50 * time.Millisecond)
+	assert.Empty(t, host.appliedConfigs())
+	require.NoError(t, ext.Ready())
+
+	require.Eventually(t, func() bool {
+		report, _ := server.lastReport()
+		return report.RemoteConfigStatus != nil
+	}, 5*time.Second, 10*time.Millisecond)
+	report, _ := server.lastReport()
+	assert.Equal(t, &remoteConfigStatus{Hash: "1", Status: remoteConfigFailed, Error: "invalid configuration"}, report.RemoteConfigStatus)
+	assert.Equal(t, hashConfig([]byte("initial")), report.EffectiveConfig.Hash)
+
+	server.setRemoteConfig("updated", "2")
+	require.Eventually(t, func() bool {
+		report, _ := server.lastReport()
+		return report.RemoteConfigStatus.Hash == "2"
+	}, 5*time.Second, 10*time.Millisecond)
+	report, _ = server.lastReport()
+	assert.Equal(t, remoteConfigApplied, report.RemoteConfigStatus.Status)
+	assert.Equal(t, hashConfig([]byte("updated")), report.EffectiveConfig.Hash)
+
+	// Each configuration is applied once.
+	_, n := server.lastReport()
+	require.Eventually(t, func() bool {
+		_, m := server.lastReport()
+		return m > n+2
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"invalid", "updated"}, host.appliedConfigs())
+}
+
+func TestRemoteConfigExtension_DoNotAcceptRemoteConfig(t *testing.T) {
+	server := &fakeServer{}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	ext := newTestExtension(t, ts.URL)
+	ext.config.AcceptRemoteConfig = false
+	host := &fakeHost{Host: componenttest.NewNopHost(), config: "initial"}
+	server.setRemoteConfig("updated", "")
+	require.NoError(t, ext.Start(context.Background(), host))
+	require.NoError(t, ext.Ready())
+
+	require.Eventually(t, func() bool {
+		_, n := server.lastReport()
+		return n >= 3
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.NoError(t, ext.Shutdown(context.Background()))
+	assert.Empty(t, host.appliedConfigs())
+}
+
+func TestRemoteConfigExtension_NotReadyForgetsPipelineComponents(t *testing.T) {
+	ext := newTestExtension(t, "http://localhost:4320")
+	ext.ComponentStatusChanged(component.StatusEvent{Kind: component.KindExtension, Name: "zpages", Status: component.StatusHealthy})
+	ext.ComponentStatusChanged(component.StatusEvent{Kind: component.KindProcessor, Name: "batch", Pipeline: "traces", Status: component.StatusDegraded})
+	require.NoError(t, ext.Ready())
+	report, _ := ext.buildReport()
+	assert.Equal(t, "degraded", report.Health)
+	assert.Len(t, report.Components, 2)
+
+	require.NoError(t, ext.NotReady())
+	report, _ = ext.buildReport()
+	assert.Equal(t, "starting", report.Health)
+	assert.Equal(t, []componentStatus{{Kind: "extension", Name: "zpages", Status: "healthy"}}, report.Components)
+}
+
+func TestRemoteConfigExtension_ServerError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	ext := newTestExtension(t, ts.URL)
+	ext.client = ts.Client()
+	_, err := ext.send(context.Background(), &statusReport{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,153 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const tmpSuffix = ".tmp"
+
+var errEmptyKey = errors.New("the key cannot be empty")
+
+// fileClient persists the values in the files of a directory, one file per key
+// named after the escaped key.
+type fileClient struct {
+	dir string
+}
+
+var _ Client = (*fileClient)(nil)
+
+// NewFileClient returns a client persisting the values in the files of the
+// directory, one file per key, the directory being created if needed. The
+// values are synced to the disk and replaced atomically, the files of which the
+// write did not complete are removed. The files of the directory that are not
+// named after an escaped key are ignored.
+func NewFileClient(dir string) (Client, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, info := range infos {
+		if strings.HasSuffix(info.Name(), tmpSuffix) {
+			// A value of which the write did not complete.
+			if err = os.Remove(filepath.Join(dir, info.Name())); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return &fileClient{dir: dir}, nil
+}
+
+// EscapeName escapes the name for it to be used as a file name, the escaped
+// names do not contain any "/" or ".".
+func EscapeName(name string) string {
+	return strings.ReplaceAll(url.QueryEscape(name), ".", "%2E")
+}
+
+func (c *fileClient) path(key string) (string, error) {
+	if key == "" {
+		return "", errEmptyKey
+	}
+	return filepath.Join(c.dir, EscapeName(key)), nil
+}
+
+func (c *fileClient) Get(_ context.Context, key string) ([]byte, error) {
+	path, err := c.path(key)
+	if err != nil {
+		return nil, err
+	}
+	value, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return value, err
+}
+
+func (c *fileClient) Set(_ context.Context, key string, value []byte) error {
+	path, err := c.path(key)
+	if err != nil {
+		return err
+	}
+	if err = writeFileSync(path+tmpSuffix, value); err != nil {
+		_ = os.Remove(path + tmpSuffix)
+		return err
+	}
+	if err = os.Rename(path+tmpSuffix, path); err != nil {
+		_ = os.Remove(path + tmpSuffix)
+		return err
+	}
+	return nil
+}
+
+func (c *fileClient) Delete(_ context.Context, key string) error {
+	path, err := c.path(key)
+	if err != nil {
+		return err
+	}
+	if err = os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (c *fileClient) Keys(context.Context) ([]string, error) {
+	infos, err := ioutil.ReadDir(c.dir)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(infos))
+	for _, info := range infos {
+		name := info.Name()
+		if info.IsDir() || strings.HasSuffix(name, tmpSuffix) {
+			continue
+		}
+		key, err := url.QueryUnescape(name)
+		if err != nil || EscapeName(key) != name {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+func (c *fileClient) Close(context.Context) error {
+	return nil
+}
+
+func writeFileSync(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err = f.Write(data); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err = f.Sync(); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,85 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileClient(t *testing.T) {
+	dir, err := ioutil.TempDir("", "file_client")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "partial.tmp"), []byte("partial"), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "not.escaped"), []byte("other"), 0600))
+	ctx := context.Background()
+
+	client, err := NewFileClient(dir)
+	require.NoError(t, err)
+	_, err = os.Stat(filepath.Join(dir, "partial.tmp"))
+	assert.True(t, os.IsNotExist(err))
+
+	value, err := client.Get(ctx, "checkpoints")
+	require.NoError(t, err)
+	assert.Nil(t, value)
+	keys, err := client.Keys(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+
+	for _, key := range []string{"checkpoints", "a/b", "..", "first.tmp"} {
+		require.NoError(t, client.Set(ctx, key, []byte("value of "+key)))
+	}
+	require.NoError(t, client.Set(ctx, "checkpoints", []byte("replaced")))
+	value, err = client.Get(ctx, "checkpoints")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("replaced"), value)
+	value, err = client.Get(ctx, "..")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value of .."), value)
+
+	require.NoError(t, client.Delete(ctx, "a/b"))
+	require.NoError(t, client.Delete(ctx, "a/b"))
+	require.NoError(t, client.Close(ctx))
+
+	// The values are persisted.
+	client, err = NewFileClient(dir)
+	require.NoError(t, err)
+	keys, err = client.Keys(ctx)
+	require.NoError(t, err)
+	sort.Strings(keys)
+	assert.Equal(t, []string{"..", "checkpoints", "first.tmp"}, keys)
+	value, err = client.Get(ctx, "first.tmp")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value of first.tmp"), value)
+
+	assert.Equal(t, errEmptyKey, client.Set(ctx, "", nil))
+	_, err = client.Get(ctx, "")
+	assert.Equal(t, errEmptyKey, err)
+	assert.Equal(t, errEmptyKey, client.Delete(ctx, ""))
+}
+
+func TestEscapeName(t *testing.T) {
+	assert.Equal(t, "otlp%2F2", EscapeName("otlp/2"))
+	assert.Equal(t, "%2E%2E", EscapeName(".."))
+	assert.Equal(t, "00000000000000000001", EscapeName("00000000000000000001"))
+}
@@ -0,0 +1,71 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package storage defines the interface of the storage extensions, persisting
+// the state of the components, e.g. the checkpoints of the receivers or the
+// queues of the exporters, so that it survives a restart of the collector.
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/collector/component"
+)
+
+// Extension is the interface of the storage extensions.
+type Extension interface {
+	component.Extension
+
+	// GetClient returns the client persisting the state of the component of the
+	// given kind and full name. The keys of the clients of different components
+	// are not shared.
+	GetClient(ctx context.Context, kind component.Kind, name string) (Client, error)
+}
+
+// Client persists the values of the keys of a component. The values are
+// persisted when Set or Delete returns, a client can be used concurrently.
+type Client interface {
+	// Get returns the value of the key, nil if the key is not set.
+	Get(ctx context.Context, key string) ([]byte, error)
+
+	// Set sets the value of the key.
+	Set(ctx context.Context, key string, value []byte) error
+
+	// Delete deletes the key, it is not an error if the key is not set.
+	Delete(ctx context.Context, key string) error
+
+	// Keys returns the keys set, in no particular order.
+	Keys(ctx context.Context) ([]string, error)
+
+	// Close closes the client, it is not used after.
+	Close(ctx context.Context) error
+}
+
+// GetClient returns the client persisting the state of the component of the
+// given kind and full name, from the storage extension of the host with the
+// given full name.
+func GetClient(ctx context.Context, host component.Host, extension string, kind component.Kind, name string) (Client, error) {
+	for cfg, ext := range host.GetExtensions() {
+		if cfg.Name() != extension {
+			continue
+		}
+		storageExt, ok := ext.(Extension)
+		if !ok {
+			return nil, fmt.Errorf("extension %q is not a storage extension", extension)
+		}
+		return storageExt.GetClient(ctx, kind, name)
+	}
+	return nil, fmt.Errorf("storage extension %q not found, it must be enabled in the service", extension)
+}
@@ -0,0 +1,73 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configmodels"
+)
+
+type fakeStorage struct {
+	component.Extension
+	client Client
+	kind   component.Kind
+	name   string
+}
+
+func (s *fakeStorage) GetClient(_ context.Context, kind component.Kind, name string) (Client, error) {
+	s.kind = kind
+	s.name = name
+	return s.client, nil
+}
+
+type extensionsHost struct {
+	component.Host
+	extensions map[configmodels.NamedEntity]component.Extension
+}
+
+func (h *extensionsHost) GetExtensions() map[configmodels.NamedEntity]component.Extension {
+	return h.extensions
+}
+
+func TestGetClient(t *testing.T) {
+	storage := &fakeStorage{client: &fileClient{dir: "test"}}
+	other, err := componenttest.NewNopExtensionFactory().CreateExtension(context.Background(), component.ExtensionCreateParams{}, nil)
+	require.NoError(t, err)
+	host := &extensionsHost{
+		Host: componenttest.NewNopHost(),
+		extensions: map[configmodels.NamedEntity]component.Extension{
+			&configmodels.ExtensionSettings{TypeVal: "file_storage", NameVal: "file_storage"}: storage,
+			&configmodels.ExtensionSettings{TypeVal: "other", NameVal: "other"}:               other,
+		},
+	}
+
+	client, err := GetClient(context.Background(), host, "file_storage", component.KindReceiver, "filelog/1")
+	require.NoError(t, err)
+	assert.Equal(t, storage.client, client)
+	assert.Equal(t, component.KindReceiver, storage.kind)
+	assert.Equal(t, "filelog/1", storage.name)
+
+	_, err = GetClient(context.Background(), host, "other", component.KindReceiver, "filelog/1")
+	assert.EqualError(t, err, `extension "other" is not a storage extension`)
+	_, err = GetClient(context.Background(), host, "missing", component.KindReceiver, "filelog/1")
+	assert.EqualError(t, err, `storage extension "missing" not found, it must be enabled in the service`)
+}
@@ -0,0 +1,85 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package batchpertrace splits the spans of a pdata.Traces per trace ID.
+package batchpertrace
+
+import (
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// Split returns a pdata.Traces per trace ID found in td, holding copies of the
+// spans of the trace with their resources and instrumentation libraries.
+func Split(td pdata.Traces) map[pdata.TraceID]pdata.Traces {
+	batches := make(map[pdata.TraceID]*batch)
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		ilss := rs.InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			ils := ilss.At(j)
+			for id, spans := range spansByTraceID(ils.Spans()) {
+				b, ok := batches[id]
+				if !ok {
+					b = &batch{traces: pdata.NewTraces(), rsIndex: -1}
+					batches[id] = b
+				}
+				b.add(i, rs, ils, spans)
+			}
+		}
+	}
+
+	result := make(map[pdata.TraceID]pdata.Traces, len(batches))
+	for id, b := range batches {
+		result[id] = b.traces
+	}
+	return result
+}
+
+// batch holds the spans of a trace being split.
+type batch struct {
+	traces pdata.Traces
+	// rsIndex is the index, in the input td, of the last resource copied to
+	// traces.
+	rsIndex int
+}
+
+func spansByTraceID(spans pdata.SpanSlice) map[pdata.TraceID][]pdata.Span {
+	byID := make(map[pdata.TraceID][]pdata.Span)
+	for k := 0; k < spans.Len(); k++ {
+		span := spans.At(k)
+		byID[span.TraceID()] = append(byID[span.TraceID()], span)
+	}
+	return byID
+}
+
+// add copies the spans of an instrumentation library of the resource at
+// rsIndex in the input td.
+func (b *batch) add(rsIndex int, rs pdata.ResourceSpans, ils pdata.InstrumentationLibrarySpans, spans []pdata.Span) {
+	destRSS := b.traces.ResourceSpans()
+	if b.rsIndex != rsIndex {
+		destRSS.Resize(destRSS.Len() + 1)
+		rs.Resource().CopyTo(destRSS.At(destRSS.Len() - 1).Resource())
+		b.rsIndex = rsIndex
+	}
+	destILSS := destRSS.At(destRSS.Len() - 1).InstrumentationLibrarySpans()
+	destILSS.Resize(destILSS.Len() + 1)
+	destILS := destILSS.At(destILSS.Len() - 1)
+	ils.InstrumentationLibrary().CopyTo(destILS.InstrumentationLibrary())
+	destSpans := destILS.Spans()
+	destSpans.Resize(len(spans))
+	for k, span := range spans {
+		span.CopyTo(destSpans.At(k))
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package batchpertrace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func traceID(b byte) pdata.TraceID {
+	return pdata.NewTraceID([16]byte{b})
+}
+
+func newResourceSpans(rss pdata.ResourceSpansSlice, service string, ids ...byte) {
+	rss.Resize(rss.Len() + 1)
+	rs := rss.At(rss.Len() - 1)
+	rs.Resource().Attributes().InsertString("service.name", service)
+	rs.InstrumentationLibrarySpans().Resize(1)
+	ils := rs.InstrumentationLibrarySpans().At(0)
+	ils.InstrumentationLibrary().SetName("lib")
+	ils.Spans().Resize(len(ids))
+	for i, id := range ids {
+		ils.Spans().At(i).SetTraceID(traceID(id))
+	}
+}
+
+func TestSplit(t *testing.T) {
+	td := pdata.NewTraces()
+	newResourceSpans(td.ResourceSpans(), "checkout", 1, 2, 1)
+	newResourceSpans(td.ResourceSpans(), "cart", 1)
+
+	batches := Split(td)
+	require.Len(t, batches, 2)
+
+	rss := batches[traceID(1)].ResourceSpans()
+	require.Equal(t, 2, rss.Len())
+	for i, expected := range []struct {
+		service string
+		spans   int
+	}{{service: "checkout", spans: 2}, {service: "cart", spans: 1}} {
+		service, _ := rss.At(i).Resource().Attributes().Get("service.name")
+		assert.Equal(t, expected.service, service.StringVal())
+		ils := rss.At(i).InstrumentationLibrarySpans()
+		require.Equal(t, 1, ils.Len())
+		assert.Equal(t, "lib", ils.At(0).InstrumentationLibrary().Name())
+		assert.Equal(t, expected.spans, ils.At(0).Spans().Len())
+	}
+
+	assert.Equal(t, 1, batches[traceID(2)].SpanCount())
+	// The split traces are copies.
+	assert.Equal(t, 4, td.SpanCount())
+}
+
+func TestSplit_Empty(t *testing.T) {
+	assert.Empty(t, Split(pdata.NewTraces()))
+}
@@ -15,6 +15,7 @@
 package data
 
 import (
+	"encoding/base64"
 	"encoding/hex"
 	"errors"
 	"fmt"
@@ -37,7 +38,17 @@ func marshalJSON(id []byte) ([]byte, error) {
 	return b, nil
 }
 
+// base64Encodings are the encodings of bytes fields accepted by the Protobuf JSON mapping.
+var base64Encodings = []*base64.Encoding{
+	base64.StdEncoding,
+	base64.URLEncoding,
+	base64.RawStdEncoding,
+	base64.RawURLEncoding,
+}
+
 // unmarshalJSON inflates trace id from hex string, possibly enclosed in quotes.
+// The base64 encoding of the Protobuf JSON mapping for bytes fields is accepted too,
+// the length of the string telling one encoding from the other.
 // Called by Protobuf JSON deserialization.
 func unmarshalJSON(dst []byte, src []byte) error {
 	if l := len(src); l >= 2 && src[0] == '"' && src[l-1] == '"' {
@@ -49,6 +60,18 @@ func unmarshalJSON(dst []byte, src []byte) error {
 	}
 
 	if len(dst) != hex.DecodedLen(nLen) {
+		for _, enc := range base64Encodings {
+			if enc.EncodedLen(len(dst)) != nLen {
+				continue
+			}
+			b := make([]byte, enc.DecodedLen(nLen))
+			n, err := enc.Decode(b, src)
+			if err != nil || n != len(dst) {
+				continue
+			}
+			copy(dst, b)
+			return nil
+		}
 		return errors.New("invalid length for ID")
 	}
 
@@ -118,6 +118,11 @@ func TestSpanIDUnmarshalJSON(t *testing.T) {
 	err = sid.UnmarshalJSON([]byte(`"nothex"`))
 	assert.Error(t, err)
 
+	// base64, as bytes fields are encoded by the Protobuf JSON mapping.
+	err = sid.UnmarshalJSON([]byte(`"EjRWeBI0Vng="`))
+	assert.NoError(t, err)
+	assert.EqualValues(t, [8]byte{0x12, 0x34, 0x56, 0x78, 0x12, 0x34, 0x56, 0x78}, sid.id)
+
 	err = sid.UnmarshalJSON([]byte(`"1"`))
 	assert.Error(t, err)
 
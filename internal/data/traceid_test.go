@@ -119,6 +119,17 @@ func TestTraceIDUnmarshalJSON(t *testing.T) {
 	err = tid.UnmarshalJSON([]byte(`"nothex"`))
 	assert.Error(t, err)
 
+	// base64, as bytes fields are encoded by the Protobuf JSON mapping.
+	for _, encoded := range []string{`"EjRWeBI0VngSNFZ4EjRWeA=="`, `"EjRWeBI0VngSNFZ4EjRWeA"`} {
+		tid = NewTraceID([16]byte{})
+		err = tid.UnmarshalJSON([]byte(encoded))
+		assert.NoError(t, err)
+		assert.EqualValues(t, tidBytes, tid.id)
+	}
+
+	err = tid.UnmarshalJSON([]byte(`"EjRWeBI0VngSNFZ4EjRW"`))
+	assert.Error(t, err)
+
 	err = tid.UnmarshalJSON([]byte(`"1"`))
 	assert.Error(t, err)
 
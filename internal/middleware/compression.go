@@ -18,28 +18,67 @@ import (
 	"bytes"
 	"compress/gzip"
 	"compress/zlib"
+	"fmt"
 	"io"
+	"io/ioutil"
 	"net/http"
+	"sync/atomic"
+
+	"github.com/klauspost/compress/zstd"
 )
 
 const (
 	headerContentEncoding = "Content-Encoding"
 	headerValueGZIP       = "gzip"
+	headerValueZstd       = "zstd"
+
+	// zstdMaxDecoderMemory bounds the window of the decoded zstd streams, so that a request
+	// cannot make the decoder allocate up to the window of 512 MiB allowed by zstd.
+	zstdMaxDecoderMemory = 64 << 20
 )
 
+// zstdEncoder compresses the zstd request bodies, EncodeAll is safe for concurrent use.
+var zstdEncoder, _ = zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1))
+
+// CompressRoundTripper compresses the bodies of the HTTP requests. Once the server refused
+// a compressed request with 415 Unsupported Media Type, the request is sent again
+// uncompressed, and so are all the following requests.
 type CompressRoundTripper struct {
 	http.RoundTripper
+	encoding string
+	compress func([]byte) ([]byte, error)
+	// uncompressed is set to 1 once the server refused a compressed request.
+	uncompressed int32
 }
 
+// NewCompressRoundTripper returns a CompressRoundTripper compressing with gzip.
 func NewCompressRoundTripper(rt http.RoundTripper) *CompressRoundTripper {
 	return &CompressRoundTripper{
 		RoundTripper: rt,
+		encoding:     headerValueGZIP,
+		compress:     gzipBody,
+	}
+}
+
+// NewCompressRoundTripperWithEncoding returns a CompressRoundTripper compressing with the
+// given encoding, "gzip" or "zstd".
+func NewCompressRoundTripperWithEncoding(rt http.RoundTripper, encoding string) (*CompressRoundTripper, error) {
+	switch encoding {
+	case headerValueGZIP:
+		return NewCompressRoundTripper(rt), nil
+	case headerValueZstd:
+		return &CompressRoundTripper{
+			RoundTripper: rt,
+			encoding:     headerValueZstd,
+			compress:     zstdBody,
+		}, nil
 	}
+	return nil, fmt.Errorf("unsupported compression type %q", encoding)
 }
 
 func (r *CompressRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
 
-	if req.Header.Get(headerContentEncoding) != "" {
+	if req.Header.Get(headerContentEncoding) != "" || req.Body == nil || atomic.LoadInt32(&r.uncompressed) == 1 {
 		// If the header already specifies a content encoding then skip compression
 		// since we don't want to compress it again. This is a safeguard that normally
 		// should not happen since CompressRoundTripper is not intended to be used
@@ -47,35 +86,76 @@ func (r *CompressRoundTripper) RoundTrip(req *http.Request) (*http.Response, err
 		return r.RoundTripper.RoundTrip(req)
 	}
 
-	// Gzip the body.
-	buf := bytes.NewBuffer([]byte{})
-	gzipWriter := gzip.NewWriter(buf)
-	_, copyErr := io.Copy(gzipWriter, req.Body)
+	// Compress the body, the uncompressed body is kept to be sent again if the server
+	// does not support the encoding.
+	body, readErr := ioutil.ReadAll(req.Body)
 	closeErr := req.Body.Close()
+	if readErr != nil {
+		return nil, readErr
+	}
+	if closeErr != nil {
+		return nil, closeErr
+	}
+	compressed, err := r.compress(body)
+	if err != nil {
+		return nil, err
+	}
 
-	if err := gzipWriter.Close(); err != nil {
+	// Create a new request since the docs say that we cannot modify the "req"
+	// (see https://golang.org/pkg/net/http/#RoundTripper).
+	cReq, err := cloneRequest(req, compressed)
+	if err != nil {
 		return nil, err
 	}
+	// Add the encoding header.
+	cReq.Header.Add(headerContentEncoding, r.encoding)
+
+	resp, err := r.RoundTripper.RoundTrip(cReq)
+	if err != nil || resp.StatusCode != http.StatusUnsupportedMediaType {
+		return resp, err
+	}
 
+	// The server does not support the encoding, fallback to uncompressed requests.
+	atomic.StoreInt32(&r.uncompressed, 1)
+	_, copyErr := io.Copy(ioutil.Discard, resp.Body)
+	closeErr = resp.Body.Close()
 	if copyErr != nil {
 		return nil, copyErr
 	}
 	if closeErr != nil {
 		return nil, closeErr
 	}
-
-	// Create a new request since the docs say that we cannot modify the "req"
-	// (see https://golang.org/pkg/net/http/#RoundTripper).
-	cReq, err := http.NewRequestWithContext(req.Context(), req.Method, req.URL.String(), buf)
+	uReq, err := cloneRequest(req, body)
 	if err != nil {
 		return nil, err
 	}
+	return r.RoundTripper.RoundTrip(uReq)
+}
 
-	// Clone the headers and add gzip encoding header.
+// cloneRequest returns a copy of req, with its headers, sending the given body.
+func cloneRequest(req *http.Request, body []byte) (*http.Request, error) {
+	cReq, err := http.NewRequestWithContext(req.Context(), req.Method, req.URL.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
 	cReq.Header = req.Header.Clone()
-	cReq.Header.Add(headerContentEncoding, headerValueGZIP)
+	return cReq, nil
+}
+
+func gzipBody(body []byte) ([]byte, error) {
+	buf := bytes.NewBuffer([]byte{})
+	gzipWriter := gzip.NewWriter(buf)
+	if _, err := gzipWriter.Write(body); err != nil {
+		return nil, err
+	}
+	if err := gzipWriter.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
 
-	return r.RoundTripper.RoundTrip(cReq)
+func zstdBody(body []byte) ([]byte, error) {
+	return zstdEncoder.EncodeAll(body, make([]byte, 0, len(body)/2)), nil
 }
 
 type ErrorHandler func(w http.ResponseWriter, r *http.Request, errorMsg string, statusCode int)
@@ -95,7 +175,7 @@ func WithErrorHandler(e ErrorHandler) DecompressorOption {
 // HTTPContentDecompressor is a middleware that offloads the task of handling compressed
 // HTTP requests by identifying the compression format in the "Content-Encoding" header and re-writing
 // request body so that the handlers further in the chain can work on decompressed data.
-// It supports gzip and deflate/zlib compression.
+// It supports gzip, zstd and deflate/zlib compression.
 func HTTPContentDecompressor(h http.Handler, opts ...DecompressorOption) http.Handler {
 	d := &decompressor{}
 	for _, o := range opts {
@@ -136,6 +216,12 @@ func newBodyReader(r *http.Request) (io.ReadCloser, error) {
 			return nil, err
 		}
 		return gr, nil
+	case "zstd":
+		zr, err := zstd.NewReader(r.Body, zstd.WithDecoderConcurrency(1), zstd.WithDecoderMaxMemory(zstdMaxDecoderMemory))
+		if err != nil {
+			return nil, err
+		}
+		return zr.IOReadCloser(), nil
 	case "deflate", "zlib":
 		zr, err := zlib.NewReader(r.Body)
 		if err != nil {
@@ -22,9 +22,11 @@ import (
 	"io/ioutil"
 	"net"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
+	"github.com/klauspost/compress/zstd"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -34,6 +36,7 @@ import (
 func TestHTTPClientCompression(t *testing.T) {
 	testBody := []byte("uncompressed_text")
 	compressedBody, _ := compressGzip(testBody)
+	compressedZstdBody, _ := compressZstd(testBody)
 
 	tests := []struct {
 		name     string
@@ -50,6 +53,11 @@ func TestHTTPClientCompression(t *testing.T) {
 			encoding: "gzip",
 			reqBody:  compressedBody.Bytes(),
 		},
+		{
+			name:     "ValidZstd",
+			encoding: "zstd",
+			reqBody:  compressedZstdBody.Bytes(),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -79,8 +87,9 @@ func TestHTTPClientCompression(t *testing.T) {
 			require.NoError(t, err, "failed to create request to test handler")
 
 			client := http.Client{}
-			if tt.encoding == "gzip" {
-				client.Transport = NewCompressRoundTripper(http.DefaultTransport)
+			if tt.encoding != "" {
+				client.Transport, err = NewCompressRoundTripperWithEncoding(http.DefaultTransport, tt.encoding)
+				require.NoError(t, err)
 			}
 			res, err := client.Do(req)
 			require.NoError(t, err)
@@ -92,6 +101,41 @@ func TestHTTPClientCompression(t *testing.T) {
 	}
 }
 
+func TestNewCompressRoundTripperWithEncodingError(t *testing.T) {
+	_, err := NewCompressRoundTripperWithEncoding(http.DefaultTransport, "gzip2")
+	assert.EqualError(t, err, `unsupported compression type "gzip2"`)
+}
+
+func TestHTTPClientCompressionUnsupportedMediaType(t *testing.T) {
+	testBody := []byte("uncompressed_text")
+	var encodings []string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encodings = append(encodings, r.Header.Get("Content-Encoding"))
+		if r.Header.Get("Content-Encoding") != "" {
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+			return
+		}
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err, "failed to read request body: %v", err)
+		assert.EqualValues(t, testBody, body)
+		w.WriteHeader(200)
+	})
+	srv := httptest.NewServer(handler)
+	defer srv.Close()
+
+	rt, err := NewCompressRoundTripperWithEncoding(http.DefaultTransport, "zstd")
+	require.NoError(t, err)
+	client := http.Client{Transport: rt}
+	for i := 0; i < 2; i++ {
+		res, err := client.Post(srv.URL, "text/plain", bytes.NewReader(testBody))
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+		assert.Equal(t, 200, res.StatusCode)
+	}
+	// Only the first request is sent compressed.
+	assert.Equal(t, []string{"zstd", "", ""}, encodings)
+}
+
 func TestHTTPContentDecompressionHandler(t *testing.T) {
 	testBody := []byte("uncompressed_text")
 	tests := []struct {
@@ -125,6 +169,14 @@ func TestHTTPContentDecompressionHandler(t *testing.T) {
 			},
 			respCode: 200,
 		},
+		{
+			name:     "ValidZstd",
+			encoding: "zstd",
+			reqBodyFunc: func() (*bytes.Buffer, error) {
+				return compressZstd(testBody)
+			},
+			respCode: 200,
+		},
 		{
 			name:     "InvalidGzip",
 			encoding: "gzip",
@@ -189,6 +241,19 @@ func TestHTTPContentDecompressionHandler(t *testing.T) {
 	}
 }
 
+func TestHTTPContentDecompressionZstdWindowTooLarge(t *testing.T) {
+	// a frame declaring a window of 128 MiB, made of a single empty raw block.
+	frame := []byte{0x28, 0xb5, 0x2f, 0xfd, 0x00, (27 - 10) << 3, 0x01, 0x00, 0x00}
+	req, err := http.NewRequest("POST", "http://localhost", bytes.NewReader(frame))
+	require.NoError(t, err)
+	req.Header.Set("Content-Encoding", "zstd")
+	body, err := newBodyReader(req)
+	require.NoError(t, err)
+	defer body.Close()
+	_, err = ioutil.ReadAll(body)
+	assert.Equal(t, zstd.ErrWindowSizeExceeded, err)
+}
+
 func compressGzip(body []byte) (*bytes.Buffer, error) {
 	var buf bytes.Buffer
 
@@ -216,3 +281,20 @@ func compressZlib(body []byte) (*bytes.Buffer, error) {
 
 	return &buf, nil
 }
+
+func compressZstd(body []byte) (*bytes.Buffer, error) {
+	var buf bytes.Buffer
+
+	zw, err := zstd.NewWriter(&buf)
+	if err != nil {
+		return nil, err
+	}
+	defer zw.Close()
+
+	_, err = zw.Write(body)
+	if err != nil {
+		return nil, err
+	}
+
+	return &buf, nil
+}
@@ -0,0 +1,222 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build arrow
+
+package otlparrow
+
+import (
+	"fmt"
+
+	otlpcommon "go.opentelemetry.io/collector/internal/data/protogen/common/v1"
+	otlpresource "go.opentelemetry.io/collector/internal/data/protogen/resource/v1"
+)
+
+// The types of the attribute values, the arrays and key-value lists are encoded with their
+// AnyValue proto in the bytes column.
+const (
+	valueTypeEmpty uint8 = iota
+	valueTypeString
+	valueTypeBool
+	valueTypeInt
+	valueTypeDouble
+	valueTypeArray
+	valueTypeKvlist
+)
+
+const (
+	attributeParent = iota
+	attributeKey
+	attributeType
+	attributeString
+	attributeInt
+	attributeDouble
+	attributeBool
+	attributeBytes
+)
+
+var attributesSchema = newSchema(
+	arrowField("parent", uint32Type),
+	arrowField("key", stringType),
+	arrowField("type", uint8Type),
+	arrowField("string", stringType),
+	arrowField("int", int64Type),
+	arrowField("double", float64Type),
+	arrowField("bool", boolType),
+	arrowField("bytes", binaryType),
+)
+
+// appendAttributes appends the attributes of the parent row to an attributes table.
+func appendAttributes(b tableBuilder, parent int, attrs []otlpcommon.KeyValue) error {
+	for i := range attrs {
+		attr := &attrs[i]
+		var (
+			typ     = valueTypeEmpty
+			str     string
+			intVal  int64
+			double  float64
+			boolVal bool
+			bytes   []byte
+			err     error
+		)
+		switch v := attr.Value.Value.(type) {
+		case *otlpcommon.AnyValue_StringValue:
+			typ, str = valueTypeString, v.StringValue
+		case *otlpcommon.AnyValue_BoolValue:
+			typ, boolVal = valueTypeBool, v.BoolValue
+		case *otlpcommon.AnyValue_IntValue:
+			typ, intVal = valueTypeInt, v.IntValue
+		case *otlpcommon.AnyValue_DoubleValue:
+			typ, double = valueTypeDouble, v.DoubleValue
+		case *otlpcommon.AnyValue_ArrayValue:
+			typ = valueTypeArray
+			bytes, err = attr.Value.Marshal()
+		case *otlpcommon.AnyValue_KvlistValue:
+			typ = valueTypeKvlist
+			bytes, err = attr.Value.Marshal()
+		}
+		if err != nil {
+			return err
+		}
+		b.uint32s(attributeParent).Append(uint32(parent))
+		b.strings(attributeKey).Append(attr.Key)
+		b.uint8s(attributeType).Append(typ)
+		b.strings(attributeString).Append(str)
+		b.int64s(attributeInt).Append(intVal)
+		b.float64s(attributeDouble).Append(double)
+		b.bools(attributeBool).Append(boolVal)
+		b.binaries(attributeBytes).Append(bytes)
+	}
+	return nil
+}
+
+// decodeAttributes returns the attributes of each of the numParents rows of the parent table.
+func decodeAttributes(t table, numParents int) ([][]otlpcommon.KeyValue, error) {
+	parents, err := t.parents(numParents)
+	if err != nil {
+		return nil, err
+	}
+	attrs := make([][]otlpcommon.KeyValue, numParents)
+	for row, parent := range parents {
+		attr := otlpcommon.KeyValue{Key: t.strings(attributeKey).Value(row)}
+		switch typ := t.uint8s(attributeType).Value(row); typ {
+		case valueTypeEmpty:
+		case valueTypeString:
+			attr.Value.Value = &otlpcommon.AnyValue_StringValue{StringValue: t.strings(attributeString).Value(row)}
+		case valueTypeBool:
+			attr.Value.Value = &otlpcommon.AnyValue_BoolValue{BoolValue: t.bools(attributeBool).Value(row)}
+		case valueTypeInt:
+			attr.Value.Value = &otlpcommon.AnyValue_IntValue{IntValue: t.int64s(attributeInt).Value(row)}
+		case valueTypeDouble:
+			attr.Value.Value = &otlpcommon.AnyValue_DoubleValue{DoubleValue: t.float64s(attributeDouble).Value(row)}
+		case valueTypeArray, valueTypeKvlist:
+			if err = attr.Value.Unmarshal(t.binaries(attributeBytes).Value(row)); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("invalid attribute type %d", typ)
+		}
+		attrs[parent] = append(attrs[parent], attr)
+	}
+	return attrs, nil
+}
+
+const (
+	labelParent = iota
+	labelKey
+	labelValue
+)
+
+var labelsSchema = newSchema(
+	arrowField("parent", uint32Type),
+	arrowField("key", stringType),
+	arrowField("value", stringType),
+)
+
+// appendLabels appends the labels of the parent row to a labels table.
+func appendLabels(b tableBuilder, parent int, labels []otlpcommon.StringKeyValue) {
+	for _, label := range labels {
+		b.uint32s(labelParent).Append(uint32(parent))
+		b.strings(labelKey).Append(label.Key)
+		b.strings(labelValue).Append(label.Value)
+	}
+}
+
+// decodeLabels returns the labels of each of the numParents rows of the parent table.
+func decodeLabels(t table, numParents int) ([][]otlpcommon.StringKeyValue, error) {
+	parents, err := t.parents(numParents)
+	if err != nil {
+		return nil, err
+	}
+	labels := make([][]otlpcommon.StringKeyValue, numParents)
+	for row, parent := range parents {
+		labels[parent] = append(labels[parent], otlpcommon.StringKeyValue{
+			Key:   t.strings(labelKey).Value(row),
+			Value: t.strings(labelValue).Value(row),
+		})
+	}
+	return labels, nil
+}
+
+// The resources table has no parent, its attributes are in a separate attributes table.
+var resourcesSchema = newSchema(
+	arrowField("dropped_attributes_count", uint32Type),
+)
+
+func appendResource(resources, attributes tableBuilder, resource *otlpresource.Resource) error {
+	parent := resources.uint32s(0).Len()
+	resources.uint32s(0).Append(resource.DroppedAttributesCount)
+	return appendAttributes(attributes, parent, resource.Attributes)
+}
+
+func decodeResources(resources, attributes table) ([]otlpresource.Resource, error) {
+	attrs, err := decodeAttributes(attributes, resources.rows())
+	if err != nil {
+		return nil, err
+	}
+	result := make([]otlpresource.Resource, resources.rows())
+	for row := range result {
+		result[row] = otlpresource.Resource{
+			Attributes:             attrs[row],
+			DroppedAttributesCount: resources.uint32s(0).Value(row),
+		}
+	}
+	return result, nil
+}
+
+// The parent of the instrumentation libraries is their resource.
+const (
+	libraryParent = iota
+	libraryName
+	libraryVersion
+)
+
+var librariesSchema = newSchema(
+	arrowField("parent", uint32Type),
+	arrowField("name", stringType),
+	arrowField("version", stringType),
+)
+
+func appendLibrary(b tableBuilder, parent int, library otlpcommon.InstrumentationLibrary) {
+	b.uint32s(libraryParent).Append(uint32(parent))
+	b.strings(libraryName).Append(library.Name)
+	b.strings(libraryVersion).Append(library.Version)
+}
+
+func decodeLibrary(t table, row int) otlpcommon.InstrumentationLibrary {
+	return otlpcommon.InstrumentationLibrary{
+		Name:    t.strings(libraryName).Value(row),
+		Version: t.strings(libraryVersion).Value(row),
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !arrow
+
+package otlparrow
+
+import (
+	otlpmetrics "go.opentelemetry.io/collector/internal/data/protogen/metrics/v1"
+	otlptrace "go.opentelemetry.io/collector/internal/data/protogen/trace/v1"
+)
+
+// Enabled is true when the collector is built with the arrow build tag, the Arrow mode being
+// left out of the default build.
+const Enabled = false
+
+// EncodeTraces returns ErrDisabled.
+func EncodeTraces([]*otlptrace.ResourceSpans) (*Request, error) {
+	return nil, ErrDisabled
+}
+
+// DecodeTraces returns ErrDisabled.
+func DecodeTraces(*Request) ([]*otlptrace.ResourceSpans, error) {
+	return nil, ErrDisabled
+}
+
+// EncodeMetrics returns ErrDisabled.
+func EncodeMetrics([]*otlpmetrics.ResourceMetrics) (*Request, error) {
+	return nil, ErrDisabled
+}
+
+// DecodeMetrics returns ErrDisabled.
+func DecodeMetrics(*Request) ([]*otlpmetrics.ResourceMetrics, error) {
+	return nil, ErrDisabled
+}
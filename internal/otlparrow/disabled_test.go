@@ -0,0 +1,35 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !arrow
+
+package otlparrow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDisabled(t *testing.T) {
+	assert.False(t, Enabled)
+	_, err := EncodeTraces(nil)
+	assert.Equal(t, ErrDisabled, err)
+	_, err = DecodeTraces(&Request{})
+	assert.Equal(t, ErrDisabled, err)
+	_, err = EncodeMetrics(nil)
+	assert.Equal(t, ErrDisabled, err)
+	_, err = DecodeMetrics(&Request{})
+	assert.Equal(t, ErrDisabled, err)
+}
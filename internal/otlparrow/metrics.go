@@ -0,0 +1,663 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build arrow
+
+package otlparrow
+
+import (
+	"fmt"
+
+	"github.com/apache/arrow/go/arrow"
+
+	otlpcommon "go.opentelemetry.io/collector/internal/data/protogen/common/v1"
+	otlpmetrics "go.opentelemetry.io/collector/internal/data/protogen/metrics/v1"
+)
+
+// The tables of the metrics requests. The data points are in a table per kind of point, the
+// type of a point, e.g. int or double, being the one of its metric.
+const (
+	metricsResources = iota
+	metricsResourceAttributes
+	metricsLibraries
+	metricsMetrics
+	metricsNumberPoints
+	metricsNumberLabels
+	metricsHistogramPoints
+	metricsHistogramLabels
+	metricsSummaryPoints
+	metricsSummaryLabels
+)
+
+// The types of the metrics.
+const (
+	metricTypeNone uint8 = iota
+	metricTypeIntGauge
+	metricTypeDoubleGauge
+	metricTypeIntSum
+	metricTypeDoubleSum
+	metricTypeIntHistogram
+	metricTypeDoubleHistogram
+	metricTypeDoubleSummary
+)
+
+// The parent of the metrics is their instrumentation library.
+const (
+	metricParent = iota
+	metricName
+	metricDescription
+	metricUnit
+	metricType
+	metricTemporality
+	metricMonotonic
+)
+
+var metricsSchema = newSchema(
+	arrowField("parent", uint32Type),
+	arrowField("name", stringType),
+	arrowField("description", stringType),
+	arrowField("unit", stringType),
+	arrowField("type", uint8Type),
+	arrowField("aggregation_temporality", int32Type),
+	arrowField("is_monotonic", boolType),
+)
+
+// The parent of the points is their metric. The exemplars, which are rare, are encoded with
+// their IntExemplar or DoubleExemplar proto.
+const (
+	pointParent = iota
+	pointStartTime
+	pointTime
+	pointExemplars
+	numberPointInt
+	numberPointDouble
+)
+
+var numberPointsSchema = newSchema(
+	arrowField("parent", uint32Type),
+	arrowField("start_time_unix_nano", uint64Type),
+	arrowField("time_unix_nano", uint64Type),
+	arrowField("exemplars", arrow.ListOf(binaryType)),
+	arrowField("int", int64Type),
+	arrowField("double", float64Type),
+)
+
+const (
+	histogramPointCount = iota + pointExemplars + 1
+	histogramPointIntSum
+	histogramPointDoubleSum
+	histogramPointBucketCounts
+	histogramPointExplicitBounds
+)
+
+var histogramPointsSchema = newSchema(
+	arrowField("parent", uint32Type),
+	arrowField("start_time_unix_nano", uint64Type),
+	arrowField("time_unix_nano", uint64Type),
+	arrowField("exemplars", arrow.ListOf(binaryType)),
+	arrowField("count", uint64Type),
+	arrowField("int_sum", int64Type),
+	arrowField("double_sum", float64Type),
+	arrowField("bucket_counts", arrow.ListOf(uint64Type)),
+	arrowField("explicit_bounds", arrow.ListOf(float64Type)),
+)
+
+// The summary points have no exemplars, the column is kept so that all the points share the
+// first columns.
+const (
+	summaryPointCount = iota + pointExemplars + 1
+	summaryPointSum
+	summaryPointQuantiles
+	summaryPointValues
+)
+
+var summaryPointsSchema = newSchema(
+	arrowField("parent", uint32Type),
+	arrowField("start_time_unix_nano", uint64Type),
+	arrowField("time_unix_nano", uint64Type),
+	arrowField("exemplars", arrow.ListOf(binaryType)),
+	arrowField("count", uint64Type),
+	arrowField("sum", float64Type),
+	arrowField("quantiles", arrow.ListOf(float64Type)),
+	arrowField("values", arrow.ListOf(float64Type)),
+)
+
+var metricsSchemas = []*arrow.Schema{
+	metricsResources:          resourcesSchema,
+	metricsResourceAttributes: attributesSchema,
+	metricsLibraries:          librariesSchema,
+	metricsMetrics:            metricsSchema,
+	metricsNumberPoints:       numberPointsSchema,
+	metricsNumberLabels:       labelsSchema,
+	metricsHistogramPoints:    histogramPointsSchema,
+	metricsHistogramLabels:    labelsSchema,
+	metricsSummaryPoints:      summaryPointsSchema,
+	metricsSummaryLabels:      labelsSchema,
+}
+
+// metricsEncoder appends the metrics to the tables of a request.
+type metricsEncoder struct {
+	builders []tableBuilder
+}
+
+// EncodeMetrics encodes the resource metrics of an OTLP metrics request in the tables of an
+// Arrow request.
+func EncodeMetrics(rms []*otlpmetrics.ResourceMetrics) (*Request, error) {
+	enc := &metricsEncoder{builders: make([]tableBuilder, len(metricsSchemas))}
+	for i, schema := range metricsSchemas {
+		enc.builders[i] = newTableBuilder(schema)
+		defer enc.builders[i].Release()
+	}
+
+	resources := enc.builders[metricsResources]
+	libraries := enc.builders[metricsLibraries]
+	for _, rm := range rms {
+		if rm == nil {
+			rm = &otlpmetrics.ResourceMetrics{}
+		}
+		resource := resources.uint32s(0).Len()
+		if err := appendResource(resources, enc.builders[metricsResourceAttributes], &rm.Resource); err != nil {
+			return nil, err
+		}
+		for _, ilm := range rm.InstrumentationLibraryMetrics {
+			if ilm == nil {
+				ilm = &otlpmetrics.InstrumentationLibraryMetrics{}
+			}
+			library := libraries.uint32s(libraryParent).Len()
+			appendLibrary(libraries, resource, ilm.InstrumentationLibrary)
+			for _, metric := range ilm.Metrics {
+				if metric == nil {
+					metric = &otlpmetrics.Metric{}
+				}
+				if err := enc.appendMetric(library, metric); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	return encodeTables(enc.builders)
+}
+
+func (enc *metricsEncoder) appendMetric(parent int, metric *otlpmetrics.Metric) error {
+	metrics := enc.builders[metricsMetrics]
+	row := metrics.uint32s(metricParent).Len()
+	typ := metricTypeNone
+	var temporality otlpmetrics.AggregationTemporality
+	var monotonic bool
+	switch data := metric.Data.(type) {
+	case *otlpmetrics.Metric_IntGauge:
+		typ = metricTypeIntGauge
+		for _, pt := range data.IntGauge.GetDataPoints() {
+			if err := enc.appendIntPoint(row, pt); err != nil {
+				return err
+			}
+		}
+	case *otlpmetrics.Metric_DoubleGauge:
+		typ = metricTypeDoubleGauge
+		for _, pt := range data.DoubleGauge.GetDataPoints() {
+			if err := enc.appendDoublePoint(row, pt); err != nil {
+				return err
+			}
+		}
+	case *otlpmetrics.Metric_IntSum:
+		typ = metricTypeIntSum
+		temporality, monotonic = data.IntSum.GetAggregationTemporality(), data.IntSum.GetIsMonotonic()
+		for _, pt := range data.IntSum.GetDataPoints() {
+			if err := enc.appendIntPoint(row, pt); err != nil {
+				return err
+			}
+		}
+	case *otlpmetrics.Metric_DoubleSum:
+		typ = metricTypeDoubleSum
+		temporality, monotonic = data.DoubleSum.GetAggregationTemporality(), data.DoubleSum.GetIsMonotonic()
+		for _, pt := range data.DoubleSum.GetDataPoints() {
+			if err := enc.appendDoublePoint(row, pt); err != nil {
+				return err
+			}
+		}
+	case *otlpmetrics.Metric_IntHistogram:
+		typ = metricTypeIntHistogram
+		temporality = data.IntHistogram.GetAggregationTemporality()
+		for _, pt := range data.IntHistogram.GetDataPoints() {
+			if err := enc.appendIntHistogramPoint(row, pt); err != nil {
+				return err
+			}
+		}
+	case *otlpmetrics.Metric_DoubleHistogram:
+		typ = metricTypeDoubleHistogram
+		temporality = data.DoubleHistogram.GetAggregationTemporality()
+		for _, pt := range data.DoubleHistogram.GetDataPoints() {
+			if err := enc.appendDoubleHistogramPoint(row, pt); err != nil {
+				return err
+			}
+		}
+	case *otlpmetrics.Metric_DoubleSummary:
+		typ = metricTypeDoubleSummary
+		for _, pt := range data.DoubleSummary.GetDataPoints() {
+			enc.appendSummaryPoint(row, pt)
+		}
+	}
+	metrics.uint32s(metricParent).Append(uint32(parent))
+	metrics.strings(metricName).Append(metric.Name)
+	metrics.strings(metricDescription).Append(metric.Description)
+	metrics.strings(metricUnit).Append(metric.Unit)
+	metrics.uint8s(metricType).Append(typ)
+	metrics.int32s(metricTemporality).Append(int32(temporality))
+	metrics.bools(metricMonotonic).Append(monotonic)
+	return nil
+}
+
+// appendPoint appends the columns shared by all the points, and returns the row of the point.
+func appendPoint(b tableBuilder, parent int, startTime, time uint64, exemplars [][]byte) int {
+	row := b.uint32s(pointParent).Len()
+	b.uint32s(pointParent).Append(uint32(parent))
+	b.uint64s(pointStartTime).Append(startTime)
+	b.uint64s(pointTime).Append(time)
+	b.appendBinaries(pointExemplars, exemplars)
+	return row
+}
+
+func (enc *metricsEncoder) appendIntPoint(parent int, pt *otlpmetrics.IntDataPoint) error {
+	if pt == nil {
+		pt = &otlpmetrics.IntDataPoint{}
+	}
+	exemplars, err := marshalIntExemplars(pt.Exemplars)
+	if err != nil {
+		return err
+	}
+	points := enc.builders[metricsNumberPoints]
+	row := appendPoint(points, parent, pt.StartTimeUnixNano, pt.TimeUnixNano, exemplars)
+	points.int64s(numberPointInt).Append(pt.Value)
+	points.float64s(numberPointDouble).Append(0)
+	appendLabels(enc.builders[metricsNumberLabels], row, pt.Labels)
+	return nil
+}
+
+func (enc *metricsEncoder) appendDoublePoint(parent int, pt *otlpmetrics.DoubleDataPoint) error {
+	if pt == nil {
+		pt = &otlpmetrics.DoubleDataPoint{}
+	}
+	exemplars, err := marshalDoubleExemplars(pt.Exemplars)
+	if err != nil {
+		return err
+	}
+	points := enc.builders[metricsNumberPoints]
+	row := appendPoint(points, parent, pt.StartTimeUnixNano, pt.TimeUnixNano, exemplars)
+	points.int64s(numberPointInt).Append(0)
+	points.float64s(numberPointDouble).Append(pt.Value)
+	appendLabels(enc.builders[metricsNumberLabels], row, pt.Labels)
+	return nil
+}
+
+func (enc *metricsEncoder) appendIntHistogramPoint(parent int, pt *otlpmetrics.IntHistogramDataPoint) error {
+	if pt == nil {
+		pt = &otlpmetrics.IntHistogramDataPoint{}
+	}
+	exemplars, err := marshalIntExemplars(pt.Exemplars)
+	if err != nil {
+		return err
+	}
+	points := enc.builders[metricsHistogramPoints]
+	row := appendPoint(points, parent, pt.StartTimeUnixNano, pt.TimeUnixNano, exemplars)
+	points.uint64s(histogramPointCount).Append(pt.Count)
+	points.int64s(histogramPointIntSum).Append(pt.Sum)
+	points.float64s(histogramPointDoubleSum).Append(0)
+	points.appendUint64s(histogramPointBucketCounts, pt.BucketCounts)
+	points.appendFloat64s(histogramPointExplicitBounds, pt.ExplicitBounds)
+	appendLabels(enc.builders[metricsHistogramLabels], row, pt.Labels)
+	return nil
+}
+
+func (enc *metricsEncoder) appendDoubleHistogramPoint(parent int, pt *otlpmetrics.DoubleHistogramDataPoint) error {
+	if pt == nil {
+		pt = &otlpmetrics.DoubleHistogramDataPoint{}
+	}
+	exemplars, err := marshalDoubleExemplars(pt.Exemplars)
+	if err != nil {
+		return err
+	}
+	points := enc.builders[metricsHistogramPoints]
+	row := appendPoint(points, parent, pt.StartTimeUnixNano, pt.TimeUnixNano, exemplars)
+	points.uint64s(histogramPointCount).Append(pt.Count)
+	points.int64s(histogramPointIntSum).Append(0)
+	points.float64s(histogramPointDoubleSum).Append(pt.Sum)
+	points.appendUint64s(histogramPointBucketCounts, pt.BucketCounts)
+	points.appendFloat64s(histogramPointExplicitBounds, pt.ExplicitBounds)
+	appendLabels(enc.builders[metricsHistogramLabels], row, pt.Labels)
+	return nil
+}
+
+func (enc *metricsEncoder) appendSummaryPoint(parent int, pt *otlpmetrics.DoubleSummaryDataPoint) {
+	if pt == nil {
+		pt = &otlpmetrics.DoubleSummaryDataPoint{}
+	}
+	quantiles := make([]float64, len(pt.QuantileValues))
+	values := make([]float64, len(pt.QuantileValues))
+	for i, qv := range pt.QuantileValues {
+		quantiles[i], values[i] = qv.GetQuantile(), qv.GetValue()
+	}
+	points := enc.builders[metricsSummaryPoints]
+	row := appendPoint(points, parent, pt.StartTimeUnixNano, pt.TimeUnixNano, nil)
+	points.uint64s(summaryPointCount).Append(pt.Count)
+	points.float64s(summaryPointSum).Append(pt.Sum)
+	points.appendFloat64s(summaryPointQuantiles, quantiles)
+	points.appendFloat64s(summaryPointValues, values)
+	appendLabels(enc.builders[metricsSummaryLabels], row, pt.Labels)
+}
+
+func marshalIntExemplars(exemplars []otlpmetrics.IntExemplar) ([][]byte, error) {
+	var result [][]byte
+	for i := range exemplars {
+		b, err := exemplars[i].Marshal()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, b)
+	}
+	return result, nil
+}
+
+func marshalDoubleExemplars(exemplars []otlpmetrics.DoubleExemplar) ([][]byte, error) {
+	var result [][]byte
+	for i := range exemplars {
+		b, err := exemplars[i].Marshal()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, b)
+	}
+	return result, nil
+}
+
+// DecodeMetrics decodes the resource metrics of an OTLP metrics request from the tables of an
+// Arrow request.
+func DecodeMetrics(req *Request) (rms []*otlpmetrics.ResourceMetrics, err error) {
+	tables, err := decodeTables(req, metricsSchemas)
+	if err != nil {
+		return nil, err
+	}
+	defer releaseTables(tables)
+	defer recoverMalformed(&err)
+
+	resources, err := decodeResources(tables[metricsResources], tables[metricsResourceAttributes])
+	if err != nil {
+		return nil, err
+	}
+	rms = make([]*otlpmetrics.ResourceMetrics, len(resources))
+	for i := range resources {
+		rms[i] = &otlpmetrics.ResourceMetrics{Resource: resources[i]}
+	}
+
+	libraries := tables[metricsLibraries]
+	libraryParents, err := libraries.parents(len(rms))
+	if err != nil {
+		return nil, err
+	}
+	ilms := make([]*otlpmetrics.InstrumentationLibraryMetrics, libraries.rows())
+	for row, parent := range libraryParents {
+		ilms[row] = &otlpmetrics.InstrumentationLibraryMetrics{InstrumentationLibrary: decodeLibrary(libraries, row)}
+		rms[parent].InstrumentationLibraryMetrics = append(rms[parent].InstrumentationLibraryMetrics, ilms[row])
+	}
+
+	metricsTable := tables[metricsMetrics]
+	metricParents, err := metricsTable.parents(len(ilms))
+	if err != nil {
+		return nil, err
+	}
+	metrics := make([]*otlpmetrics.Metric, metricsTable.rows())
+	for row, parent := range metricParents {
+		if metrics[row], err = decodeMetric(metricsTable, row); err != nil {
+			return nil, err
+		}
+		ilms[parent].Metrics = append(ilms[parent].Metrics, metrics[row])
+	}
+
+	if err = decodeNumberPoints(tables[metricsNumberPoints], tables[metricsNumberLabels], metrics); err != nil {
+		return nil, err
+	}
+	if err = decodeHistogramPoints(tables[metricsHistogramPoints], tables[metricsHistogramLabels], metrics); err != nil {
+		return nil, err
+	}
+	if err = decodeSummaryPoints(tables[metricsSummaryPoints], tables[metricsSummaryLabels], metrics); err != nil {
+		return nil, err
+	}
+	return rms, nil
+}
+
+func decodeMetric(t table, row int) (*otlpmetrics.Metric, error) {
+	metric := &otlpmetrics.Metric{
+		Name:        t.strings(metricName).Value(row),
+		Description: t.strings(metricDescription).Value(row),
+		Unit:        t.strings(metricUnit).Value(row),
+	}
+	temporality := otlpmetrics.AggregationTemporality(t.int32s(metricTemporality).Value(row))
+	monotonic := t.bools(metricMonotonic).Value(row)
+	switch typ := t.uint8s(metricType).Value(row); typ {
+	case metricTypeNone:
+	case metricTypeIntGauge:
+		metric.Data = &otlpmetrics.Metric_IntGauge{IntGauge: &otlpmetrics.IntGauge{}}
+	case metricTypeDoubleGauge:
+		metric.Data = &otlpmetrics.Metric_DoubleGauge{DoubleGauge: &otlpmetrics.DoubleGauge{}}
+	case metricTypeIntSum:
+		metric.Data = &otlpmetrics.Metric_IntSum{IntSum: &otlpmetrics.IntSum{
+			AggregationTemporality: temporality,
+			IsMonotonic:            monotonic,
+		}}
+	case metricTypeDoubleSum:
+		metric.Data = &otlpmetrics.Metric_DoubleSum{DoubleSum: &otlpmetrics.DoubleSum{
+			AggregationTemporality: temporality,
+			IsMonotonic:            monotonic,
+		}}
+	case metricTypeIntHistogram:
+		metric.Data = &otlpmetrics.Metric_IntHistogram{IntHistogram: &otlpmetrics.IntHistogram{
+			AggregationTemporality: temporality,
+		}}
+	case metricTypeDoubleHistogram:
+		metric.Data = &otlpmetrics.Metric_DoubleHistogram{DoubleHistogram: &otlpmetrics.DoubleHistogram{
+			AggregationTemporality: temporality,
+		}}
+	case metricTypeDoubleSummary:
+		metric.Data = &otlpmetrics.Metric_DoubleSummary{DoubleSummary: &otlpmetrics.DoubleSummary{}}
+	default:
+		return nil, fmt.Errorf("invalid metric type %d", typ)
+	}
+	return metric, nil
+}
+
+func decodeNumberPoints(points, labelsTable table, metrics []*otlpmetrics.Metric) error {
+	parents, err := points.parents(len(metrics))
+	if err != nil {
+		return err
+	}
+	labels, err := decodeLabels(labelsTable, points.rows())
+	if err != nil {
+		return err
+	}
+	for row, parent := range parents {
+		startTime := points.uint64s(pointStartTime).Value(row)
+		time := points.uint64s(pointTime).Value(row)
+		switch data := metrics[parent].Data.(type) {
+		case *otlpmetrics.Metric_IntGauge:
+			pt, err := decodeIntPoint(points, row, labels[row], startTime, time)
+			if err != nil {
+				return err
+			}
+			data.IntGauge.DataPoints = append(data.IntGauge.DataPoints, pt)
+		case *otlpmetrics.Metric_IntSum:
+			pt, err := decodeIntPoint(points, row, labels[row], startTime, time)
+			if err != nil {
+				return err
+			}
+			data.IntSum.DataPoints = append(data.IntSum.DataPoints, pt)
+		case *otlpmetrics.Metric_DoubleGauge:
+			pt, err := decodeDoublePoint(points, row, labels[row], startTime, time)
+			if err != nil {
+				return err
+			}
+			data.DoubleGauge.DataPoints = append(data.DoubleGauge.DataPoints, pt)
+		case *otlpmetrics.Metric_DoubleSum:
+			pt, err := decodeDoublePoint(points, row, labels[row], startTime, time)
+			if err != nil {
+				return err
+			}
+			data.DoubleSum.DataPoints = append(data.DoubleSum.DataPoints, pt)
+		default:
+			return fmt.Errorf("the metric of the number point %d is not a gauge or a sum", row)
+		}
+	}
+	return nil
+}
+
+func decodeIntPoint(points table, row int, labels []otlpcommon.StringKeyValue, startTime, time uint64) (*otlpmetrics.IntDataPoint, error) {
+	exemplars, err := unmarshalIntExemplars(points.binaryList(pointExemplars, row))
+	if err != nil {
+		return nil, err
+	}
+	return &otlpmetrics.IntDataPoint{
+		Labels:            labels,
+		StartTimeUnixNano: startTime,
+		TimeUnixNano:      time,
+		Value:             points.int64s(numberPointInt).Value(row),
+		Exemplars:         exemplars,
+	}, nil
+}
+
+func decodeDoublePoint(points table, row int, labels []otlpcommon.StringKeyValue, startTime, time uint64) (*otlpmetrics.DoubleDataPoint, error) {
+	exemplars, err := unmarshalDoubleExemplars(points.binaryList(pointExemplars, row))
+	if err != nil {
+		return nil, err
+	}
+	return &otlpmetrics.DoubleDataPoint{
+		Labels:            labels,
+		StartTimeUnixNano: startTime,
+		TimeUnixNano:      time,
+		Value:             points.float64s(numberPointDouble).Value(row),
+		Exemplars:         exemplars,
+	}, nil
+}
+
+func decodeHistogramPoints(points, labelsTable table, metrics []*otlpmetrics.Metric) error {
+	parents, err := points.parents(len(metrics))
+	if err != nil {
+		return err
+	}
+	labels, err := decodeLabels(labelsTable, points.rows())
+	if err != nil {
+		return err
+	}
+	for row, parent := range parents {
+		startTime := points.uint64s(pointStartTime).Value(row)
+		time := points.uint64s(pointTime).Value(row)
+		count := points.uint64s(histogramPointCount).Value(row)
+		bucketCounts := points.uint64List(histogramPointBucketCounts, row)
+		explicitBounds := points.float64List(histogramPointExplicitBounds, row)
+		switch data := metrics[parent].Data.(type) {
+		case *otlpmetrics.Metric_IntHistogram:
+			exemplars, err := unmarshalIntExemplars(points.binaryList(pointExemplars, row))
+			if err != nil {
+				return err
+			}
+			data.IntHistogram.DataPoints = append(data.IntHistogram.DataPoints, &otlpmetrics.IntHistogramDataPoint{
+				Labels:            labels[row],
+				StartTimeUnixNano: startTime,
+				TimeUnixNano:      time,
+				Count:             count,
+				Sum:               points.int64s(histogramPointIntSum).Value(row),
+				BucketCounts:      bucketCounts,
+				ExplicitBounds:    explicitBounds,
+				Exemplars:         exemplars,
+			})
+		case *otlpmetrics.Metric_DoubleHistogram:
+			exemplars, err := unmarshalDoubleExemplars(points.binaryList(pointExemplars, row))
+			if err != nil {
+				return err
+			}
+			data.DoubleHistogram.DataPoints = append(data.DoubleHistogram.DataPoints, &otlpmetrics.DoubleHistogramDataPoint{
+				Labels:            labels[row],
+				StartTimeUnixNano: startTime,
+				TimeUnixNano:      time,
+				Count:             count,
+				Sum:               points.float64s(histogramPointDoubleSum).Value(row),
+				BucketCounts:      bucketCounts,
+				ExplicitBounds:    explicitBounds,
+				Exemplars:         exemplars,
+			})
+		default:
+			return fmt.Errorf("the metric of the histogram point %d is not a histogram", row)
+		}
+	}
+	return nil
+}
+
+func decodeSummaryPoints(points, labelsTable table, metrics []*otlpmetrics.Metric) error {
+	parents, err := points.parents(len(metrics))
+	if err != nil {
+		return err
+	}
+	labels, err := decodeLabels(labelsTable, points.rows())
+	if err != nil {
+		return err
+	}
+	for row, parent := range parents {
+		data, ok := metrics[parent].Data.(*otlpmetrics.Metric_DoubleSummary)
+		if !ok {
+			return fmt.Errorf("the metric of the summary point %d is not a summary", row)
+		}
+		quantiles := points.float64List(summaryPointQuantiles, row)
+		values := points.float64List(summaryPointValues, row)
+		if len(quantiles) != len(values) {
+			return fmt.Errorf("the summary point %d has %d quantiles and %d values", row, len(quantiles), len(values))
+		}
+		var quantileValues []*otlpmetrics.DoubleSummaryDataPoint_ValueAtQuantile
+		for i := range quantiles {
+			quantileValues = append(quantileValues, &otlpmetrics.DoubleSummaryDataPoint_ValueAtQuantile{
+				Quantile: quantiles[i],
+				Value:    values[i],
+			})
+		}
+		data.DoubleSummary.DataPoints = append(data.DoubleSummary.DataPoints, &otlpmetrics.DoubleSummaryDataPoint{
+			Labels:            labels[row],
+			StartTimeUnixNano: points.uint64s(pointStartTime).Value(row),
+			TimeUnixNano:      points.uint64s(pointTime).Value(row),
+			Count:             points.uint64s(summaryPointCount).Value(row),
+			Sum:               points.float64s(summaryPointSum).Value(row),
+			QuantileValues:    quantileValues,
+		})
+	}
+	return nil
+}
+
+func unmarshalIntExemplars(data [][]byte) ([]otlpmetrics.IntExemplar, error) {
+	var exemplars []otlpmetrics.IntExemplar
+	for _, b := range data {
+		var exemplar otlpmetrics.IntExemplar
+		if err := exemplar.Unmarshal(b); err != nil {
+			return nil, err
+		}
+		exemplars = append(exemplars, exemplar)
+	}
+	return exemplars, nil
+}
+
+func unmarshalDoubleExemplars(data [][]byte) ([]otlpmetrics.DoubleExemplar, error) {
+	var exemplars []otlpmetrics.DoubleExemplar
+	for _, b := range data {
+		var exemplar otlpmetrics.DoubleExemplar
+		if err := exemplar.Unmarshal(b); err != nil {
+			return nil, err
+		}
+		exemplars = append(exemplars, exemplar)
+	}
+	return exemplars, nil
+}
@@ -0,0 +1,265 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build arrow
+
+package otlparrow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal/data"
+	otlpcollectormetrics "go.opentelemetry.io/collector/internal/data/protogen/collector/metrics/v1"
+	otlpcollectortrace "go.opentelemetry.io/collector/internal/data/protogen/collector/trace/v1"
+	otlpcommon "go.opentelemetry.io/collector/internal/data/protogen/common/v1"
+	otlpmetrics "go.opentelemetry.io/collector/internal/data/protogen/metrics/v1"
+	otlpresource "go.opentelemetry.io/collector/internal/data/protogen/resource/v1"
+	otlptrace "go.opentelemetry.io/collector/internal/data/protogen/trace/v1"
+	"go.opentelemetry.io/collector/internal/testdata"
+)
+
+var (
+	testTraceID = data.NewTraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})
+	testSpanID  = data.NewSpanID([8]byte{1, 2, 3, 4, 5, 6, 7, 8})
+	testParent  = data.NewSpanID([8]byte{8, 7, 6, 5, 4, 3, 2, 1})
+)
+
+func testAttributes() []otlpcommon.KeyValue {
+	return []otlpcommon.KeyValue{
+		{Key: "empty"},
+		{Key: "string", Value: otlpcommon.AnyValue{Value: &otlpcommon.AnyValue_StringValue{StringValue: "value"}}},
+		{Key: "bool", Value: otlpcommon.AnyValue{Value: &otlpcommon.AnyValue_BoolValue{BoolValue: true}}},
+		{Key: "int", Value: otlpcommon.AnyValue{Value: &otlpcommon.AnyValue_IntValue{IntValue: -42}}},
+		{Key: "double", Value: otlpcommon.AnyValue{Value: &otlpcommon.AnyValue_DoubleValue{DoubleValue: 4.2}}},
+		{Key: "array", Value: otlpcommon.AnyValue{Value: &otlpcommon.AnyValue_ArrayValue{ArrayValue: &otlpcommon.ArrayValue{
+			Values: []otlpcommon.AnyValue{{Value: &otlpcommon.AnyValue_IntValue{IntValue: 1}}},
+		}}}},
+		{Key: "kvlist", Value: otlpcommon.AnyValue{Value: &otlpcommon.AnyValue_KvlistValue{KvlistValue: &otlpcommon.KeyValueList{
+			Values: []otlpcommon.KeyValue{{Key: "nested", Value: otlpcommon.AnyValue{Value: &otlpcommon.AnyValue_StringValue{StringValue: "v"}}}},
+		}}}},
+	}
+}
+
+func testResource() otlpresource.Resource {
+	return otlpresource.Resource{Attributes: testAttributes(), DroppedAttributesCount: 3}
+}
+
+func testResourceSpans() []*otlptrace.ResourceSpans {
+	return []*otlptrace.ResourceSpans{
+		{
+			Resource: testResource(),
+			InstrumentationLibrarySpans: []*otlptrace.InstrumentationLibrarySpans{
+				{
+					InstrumentationLibrary: otlpcommon.InstrumentationLibrary{Name: "library", Version: "v1"},
+					Spans: []*otlptrace.Span{
+						{
+							TraceId:                testTraceID,
+							SpanId:                 testSpanID,
+							TraceState:             "state",
+							ParentSpanId:           testParent,
+							Name:                   "span",
+							Kind:                   otlptrace.Span_SPAN_KIND_SERVER,
+							StartTimeUnixNano:      1,
+							EndTimeUnixNano:        2,
+							Attributes:             testAttributes(),
+							DroppedAttributesCount: 4,
+							Events: []*otlptrace.Span_Event{
+								{TimeUnixNano: 3, Name: "event", Attributes: testAttributes(), DroppedAttributesCount: 5},
+								{Name: "no attributes"},
+							},
+							DroppedEventsCount: 6,
+							Links: []*otlptrace.Span_Link{
+								{TraceId: testTraceID, SpanId: testParent, TraceState: "link", Attributes: testAttributes(), DroppedAttributesCount: 7},
+							},
+							DroppedLinksCount: 8,
+							Status: otlptrace.Status{
+								Code:           otlptrace.Status_STATUS_CODE_ERROR,
+								DeprecatedCode: otlptrace.Status_DEPRECATED_STATUS_CODE_UNKNOWN_ERROR,
+								Message:        "error",
+							},
+						},
+						{TraceId: testTraceID, SpanId: testParent, Name: "root"},
+					},
+				},
+				{},
+			},
+		},
+		{},
+	}
+}
+
+func testResourceMetrics() []*otlpmetrics.ResourceMetrics {
+	labels := []otlpcommon.StringKeyValue{{Key: "k1", Value: "v1"}, {Key: "k2", Value: "v2"}}
+	intExemplars := []otlpmetrics.IntExemplar{{FilteredLabels: labels, TimeUnixNano: 1, Value: 2, SpanId: testSpanID, TraceId: testTraceID}}
+	doubleExemplars := []otlpmetrics.DoubleExemplar{{FilteredLabels: labels, TimeUnixNano: 1, Value: 2.5, SpanId: testSpanID, TraceId: testTraceID}}
+	intPoints := []*otlpmetrics.IntDataPoint{
+		{Labels: labels, StartTimeUnixNano: 1, TimeUnixNano: 2, Value: -3, Exemplars: intExemplars},
+		{TimeUnixNano: 2, Value: 4},
+	}
+	doublePoints := []*otlpmetrics.DoubleDataPoint{
+		{Labels: labels, StartTimeUnixNano: 1, TimeUnixNano: 2, Value: 3.5, Exemplars: doubleExemplars},
+	}
+	return []*otlpmetrics.ResourceMetrics{
+		{
+			Resource: testResource(),
+			InstrumentationLibraryMetrics: []*otlpmetrics.InstrumentationLibraryMetrics{
+				{
+					InstrumentationLibrary: otlpcommon.InstrumentationLibrary{Name: "library", Version: "v1"},
+					Metrics: []*otlpmetrics.Metric{
+						{Name: "int_gauge", Description: "description", Unit: "1", Data: &otlpmetrics.Metric_IntGauge{IntGauge: &otlpmetrics.IntGauge{DataPoints: intPoints}}},
+						{Name: "double_gauge", Data: &otlpmetrics.Metric_DoubleGauge{DoubleGauge: &otlpmetrics.DoubleGauge{DataPoints: doublePoints}}},
+						{Name: "int_sum", Data: &otlpmetrics.Metric_IntSum{IntSum: &otlpmetrics.IntSum{
+							DataPoints:             intPoints,
+							AggregationTemporality: otlpmetrics.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE,
+							IsMonotonic:            true,
+						}}},
+						{Name: "double_sum", Data: &otlpmetrics.Metric_DoubleSum{DoubleSum: &otlpmetrics.DoubleSum{
+							DataPoints:             doublePoints,
+							AggregationTemporality: otlpmetrics.AggregationTemporality_AGGREGATION_TEMPORALITY_DELTA,
+						}}},
+						{Name: "int_histogram", Data: &otlpmetrics.Metric_IntHistogram{IntHistogram: &otlpmetrics.IntHistogram{
+							DataPoints: []*otlpmetrics.IntHistogramDataPoint{
+								{Labels: labels, StartTimeUnixNano: 1, TimeUnixNano: 2, Count: 6, Sum: 10, BucketCounts: []uint64{1, 2, 3}, ExplicitBounds: []float64{1, 2}, Exemplars: intExemplars},
+								{TimeUnixNano: 2},
+							},
+							AggregationTemporality: otlpmetrics.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE,
+						}}},
+						{Name: "double_histogram", Data: &otlpmetrics.Metric_DoubleHistogram{DoubleHistogram: &otlpmetrics.DoubleHistogram{
+							DataPoints: []*otlpmetrics.DoubleHistogramDataPoint{
+								{Labels: labels, StartTimeUnixNano: 1, TimeUnixNano: 2, Count: 3, Sum: 1.5, BucketCounts: []uint64{1, 2}, ExplicitBounds: []float64{0.5}, Exemplars: doubleExemplars},
+							},
+							AggregationTemporality: otlpmetrics.AggregationTemporality_AGGREGATION_TEMPORALITY_DELTA,
+						}}},
+						{Name: "summary", Data: &otlpmetrics.Metric_DoubleSummary{DoubleSummary: &otlpmetrics.DoubleSummary{
+							DataPoints: []*otlpmetrics.DoubleSummaryDataPoint{
+								{Labels: labels, StartTimeUnixNano: 1, TimeUnixNano: 2, Count: 3, Sum: 1.5, QuantileValues: []*otlpmetrics.DoubleSummaryDataPoint_ValueAtQuantile{
+									{Quantile: 0.5, Value: 1}, {Quantile: 0.99, Value: 2},
+								}},
+								{TimeUnixNano: 2},
+							},
+						}}},
+						{Name: "empty_gauge", Data: &otlpmetrics.Metric_IntGauge{IntGauge: &otlpmetrics.IntGauge{}}},
+						{Name: "no_data"},
+					},
+				},
+				{},
+			},
+		},
+		{},
+	}
+}
+
+// marshalTraces returns the OTLP request of the resource spans, the round trips are compared
+// on the serialized requests given that the empty slices are decoded as nil.
+func marshalTraces(t *testing.T, rss []*otlptrace.ResourceSpans) []byte {
+	b, err := (&otlpcollectortrace.ExportTraceServiceRequest{ResourceSpans: rss}).Marshal()
+	require.NoError(t, err)
+	return b
+}
+
+func marshalMetrics(t *testing.T, rms []*otlpmetrics.ResourceMetrics) []byte {
+	b, err := (&otlpcollectormetrics.ExportMetricsServiceRequest{ResourceMetrics: rms}).Marshal()
+	require.NoError(t, err)
+	return b
+}
+
+// roundTrip returns the request after being sent over the wire.
+func roundTrip(t *testing.T, req *Request) *Request {
+	b, err := req.Marshal()
+	require.NoError(t, err)
+	result := &Request{}
+	require.NoError(t, result.Unmarshal(b))
+	return result
+}
+
+func TestTracesRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		rss  []*otlptrace.ResourceSpans
+	}{
+		{name: "all_fields", rss: testResourceSpans()},
+		{name: "empty", rss: pdata.TracesToOtlp(testdata.GenerateTraceDataEmpty())},
+		{name: "no_libraries", rss: pdata.TracesToOtlp(testdata.GenerateTraceDataNoLibraries())},
+		{name: "two_resources", rss: pdata.TracesToOtlp(testdata.GenerateTraceDataTwoSpansSameResourceOneDifferent())},
+		{name: "many_spans", rss: pdata.TracesToOtlp(testdata.GenerateTraceDataManySpansSameResource(100))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := EncodeTraces(tt.rss)
+			require.NoError(t, err)
+			assert.Len(t, req.Tables, len(tracesSchemas))
+
+			rss, err := DecodeTraces(roundTrip(t, req))
+			require.NoError(t, err)
+			assert.Equal(t, marshalTraces(t, tt.rss), marshalTraces(t, rss))
+		})
+	}
+}
+
+func TestMetricsRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		rms  []*otlpmetrics.ResourceMetrics
+	}{
+		{name: "all_fields", rms: testResourceMetrics()},
+		{name: "empty", rms: pdata.MetricsToOtlp(testdata.GenerateMetricsEmpty())},
+		{name: "all_types_no_data_points", rms: pdata.MetricsToOtlp(testdata.GenerateMetricsAllTypesNoDataPoints())},
+		{name: "all_types_empty_data_point", rms: pdata.MetricsToOtlp(testdata.GenerateMetricsAllTypesEmptyDataPoint())},
+		{name: "many_metrics", rms: pdata.MetricsToOtlp(testdata.GenerateMetricsManyMetricsSameResource(100))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := EncodeMetrics(tt.rms)
+			require.NoError(t, err)
+			assert.Len(t, req.Tables, len(metricsSchemas))
+
+			rms, err := DecodeMetrics(roundTrip(t, req))
+			require.NoError(t, err)
+			assert.Equal(t, marshalMetrics(t, tt.rms), marshalMetrics(t, rms))
+		})
+	}
+}
+
+func TestDecodeInvalidRequests(t *testing.T) {
+	traces, err := EncodeTraces(testResourceSpans())
+	require.NoError(t, err)
+	metrics, err := EncodeMetrics(testResourceMetrics())
+	require.NoError(t, err)
+
+	_, err = DecodeTraces(&Request{Tables: traces.Tables[1:]})
+	assert.EqualError(t, err, "9 tables expected, got 8")
+
+	// The tables of the metrics do not have the schemas of the traces.
+	_, err = DecodeTraces(&Request{Tables: metrics.Tables[:len(tracesSchemas)]})
+	assert.Error(t, err)
+
+	garbage := append([][]byte(nil), metrics.Tables...)
+	garbage[metricsMetrics] = []byte("not an arrow stream")
+	_, err = DecodeMetrics(&Request{Tables: garbage})
+	assert.Error(t, err)
+
+	// The spans refer to the libraries of another request.
+	other, err := EncodeTraces([]*otlptrace.ResourceSpans{{}})
+	require.NoError(t, err)
+	invalidParents := append([][]byte(nil), other.Tables...)
+	invalidParents[tracesSpans] = traces.Tables[tracesSpans]
+	_, err = DecodeTraces(&Request{Tables: invalidParents})
+	assert.EqualError(t, err, "invalid parent row 0 of 0 rows")
+
+	assert.Error(t, (&Request{}).Unmarshal([]byte{0x0a, 0x05, 0x01}))
+}
@@ -0,0 +1,109 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package otlparrow encodes the OTLP traces and metrics in a columnar representation with
+// Apache Arrow, for the experimental Arrow mode of the OTLP exporter and receiver.
+//
+// A request is split in tables, e.g. the resources, the spans and the attributes of the
+// spans, each table being an Arrow record batch in which the values of a field are stored
+// contiguously, so that the requests compress much better than the OTLP protos. The rows of a
+// table refer to their parent, e.g. the span of an attribute, by its row in the parent table.
+//
+// The encoding is only built with the arrow build tag: the vendored Arrow library fails the
+// pointer checks of the race detector, so the default build leaves the Arrow mode out.
+package otlparrow
+
+import (
+	"errors"
+	"fmt"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// The Arrow services are served next to the OTLP services by the OTLP receiver, the clients
+// fall back to the OTLP services when they are unimplemented by the server. Their responses
+// are the OTLP export responses of the signal.
+const (
+	TracesServiceName   = "opentelemetry.proto.experimental.arrow.v1.ArrowTracesService"
+	MetricsServiceName  = "opentelemetry.proto.experimental.arrow.v1.ArrowMetricsService"
+	TracesExportMethod  = "/" + TracesServiceName + "/Export"
+	MetricsExportMethod = "/" + MetricsServiceName + "/Export"
+)
+
+// The field numbers of the request:
+//
+//	message ArrowExportRequest {
+//	  repeated bytes tables = 1;
+//	}
+const tablesFieldNumber = 1
+
+var errInvalidRequest = errors.New("invalid Arrow export request")
+
+// ErrDisabled is returned when the Arrow mode is used by a collector built without the arrow
+// build tag.
+var ErrDisabled = errors.New("the Arrow mode requires the collector to be built with the arrow build tag")
+
+// Request is the request of the Export methods of the Arrow services, to be passed to
+// grpc.ClientConn.Invoke and decoded by the gRPC codec.
+type Request struct {
+	// Tables are Arrow IPC streams of a single record batch, in the order defined by the signal.
+	Tables [][]byte
+}
+
+// Reset implements proto.Message.
+func (r *Request) Reset() { *r = Request{} }
+
+// String implements proto.Message.
+func (r *Request) String() string { return fmt.Sprintf("Request{%d tables}", len(r.Tables)) }
+
+// ProtoMessage implements proto.Message.
+func (r *Request) ProtoMessage() {}
+
+// Marshal encodes the request, and is used by the gRPC codec.
+func (r *Request) Marshal() ([]byte, error) {
+	var buf []byte
+	for _, table := range r.Tables {
+		buf = protowire.AppendTag(buf, tablesFieldNumber, protowire.BytesType)
+		buf = protowire.AppendBytes(buf, table)
+	}
+	return buf, nil
+}
+
+// Unmarshal decodes the serialized request, and is used by the gRPC codec.
+func (r *Request) Unmarshal(buf []byte) error {
+	r.Reset()
+	for len(buf) > 0 {
+		num, typ, n := protowire.ConsumeTag(buf)
+		if n < 0 {
+			return errInvalidRequest
+		}
+		buf = buf[n:]
+		if num == tablesFieldNumber && typ == protowire.BytesType {
+			table, n := protowire.ConsumeBytes(buf)
+			if n < 0 {
+				return errInvalidRequest
+			}
+			// The buffer is not owned by the request.
+			r.Tables = append(r.Tables, append([]byte(nil), table...))
+			buf = buf[n:]
+			continue
+		}
+		n = protowire.ConsumeFieldValue(num, typ, buf)
+		if n < 0 {
+			return errInvalidRequest
+		}
+		buf = buf[n:]
+	}
+	return nil
+}
@@ -0,0 +1,281 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build arrow
+
+package otlparrow
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/apache/arrow/go/arrow"
+	"github.com/apache/arrow/go/arrow/array"
+	"github.com/apache/arrow/go/arrow/ipc"
+	"github.com/apache/arrow/go/arrow/memory"
+)
+
+var (
+	uint8Type   = arrow.PrimitiveTypes.Uint8
+	uint32Type  = arrow.PrimitiveTypes.Uint32
+	int32Type   = arrow.PrimitiveTypes.Int32
+	int64Type   = arrow.PrimitiveTypes.Int64
+	uint64Type  = arrow.PrimitiveTypes.Uint64
+	float64Type = arrow.PrimitiveTypes.Float64
+	boolType    = arrow.FixedWidthTypes.Boolean
+	stringType  = arrow.BinaryTypes.String
+	binaryType  = arrow.BinaryTypes.Binary
+	traceIDType = &arrow.FixedSizeBinaryType{ByteWidth: 16}
+	spanIDType  = &arrow.FixedSizeBinaryType{ByteWidth: 8}
+)
+
+func arrowField(name string, typ arrow.DataType) arrow.Field {
+	return arrow.Field{Name: name, Type: typ}
+}
+
+// newSchema returns the schema of the columns, the first column being the parent row of
+// the rows when the table has a parent table.
+func newSchema(columns ...arrow.Field) *arrow.Schema {
+	return arrow.NewSchema(columns, nil)
+}
+
+// tableBuilder builds a table row by row, the values of a row being appended to the
+// builders of all the columns.
+//
+// The values of the list columns are kept aside until the table is encoded: the list
+// builders of this Arrow version lose their offsets when they grow, so they are sized for
+// all the rows before the first value is appended.
+type tableBuilder struct {
+	*array.RecordBuilder
+	listRows map[int][]interface{}
+}
+
+func newTableBuilder(schema *arrow.Schema) tableBuilder {
+	return tableBuilder{
+		RecordBuilder: array.NewRecordBuilder(memory.DefaultAllocator, schema),
+		listRows:      map[int][]interface{}{},
+	}
+}
+
+func (b tableBuilder) uint8s(i int) *array.Uint8Builder   { return b.Field(i).(*array.Uint8Builder) }
+func (b tableBuilder) uint32s(i int) *array.Uint32Builder { return b.Field(i).(*array.Uint32Builder) }
+func (b tableBuilder) int32s(i int) *array.Int32Builder   { return b.Field(i).(*array.Int32Builder) }
+func (b tableBuilder) int64s(i int) *array.Int64Builder   { return b.Field(i).(*array.Int64Builder) }
+func (b tableBuilder) uint64s(i int) *array.Uint64Builder { return b.Field(i).(*array.Uint64Builder) }
+func (b tableBuilder) float64s(i int) *array.Float64Builder {
+	return b.Field(i).(*array.Float64Builder)
+}
+func (b tableBuilder) bools(i int) *array.BooleanBuilder   { return b.Field(i).(*array.BooleanBuilder) }
+func (b tableBuilder) strings(i int) *array.StringBuilder  { return b.Field(i).(*array.StringBuilder) }
+func (b tableBuilder) binaries(i int) *array.BinaryBuilder { return b.Field(i).(*array.BinaryBuilder) }
+func (b tableBuilder) lists(i int) *array.ListBuilder      { return b.Field(i).(*array.ListBuilder) }
+
+func (b tableBuilder) fixedBinaries(i int) *array.FixedSizeBinaryBuilder {
+	return b.Field(i).(*array.FixedSizeBinaryBuilder)
+}
+
+// appendUint64s appends a list of uint64 to the list column i.
+func (b tableBuilder) appendUint64s(i int, values []uint64) {
+	b.listRows[i] = append(b.listRows[i], values)
+}
+
+// appendFloat64s appends a list of float64 to the list column i.
+func (b tableBuilder) appendFloat64s(i int, values []float64) {
+	b.listRows[i] = append(b.listRows[i], values)
+}
+
+// appendBinaries appends a list of binary values to the list column i.
+func (b tableBuilder) appendBinaries(i int, values [][]byte) {
+	b.listRows[i] = append(b.listRows[i], values)
+}
+
+// buildLists appends the values kept aside to the builders of the list columns.
+func (b tableBuilder) buildLists() {
+	for i, rows := range b.listRows {
+		lb := b.lists(i)
+		lb.Reserve(len(rows))
+		for _, values := range rows {
+			lb.Append(true)
+			switch values := values.(type) {
+			case []uint64:
+				lb.ValueBuilder().(*array.Uint64Builder).AppendValues(values, nil)
+			case []float64:
+				lb.ValueBuilder().(*array.Float64Builder).AppendValues(values, nil)
+			case [][]byte:
+				lb.ValueBuilder().(*array.BinaryBuilder).AppendValues(values, nil)
+			}
+		}
+	}
+}
+
+// encode returns the record batch of the appended rows as an Arrow IPC stream.
+func (b tableBuilder) encode() ([]byte, error) {
+	b.buildLists()
+	rec := b.NewRecord()
+	defer rec.Release()
+
+	var buf bytes.Buffer
+	w := ipc.NewWriter(&buf, ipc.WithSchema(rec.Schema()), ipc.WithAllocator(memory.DefaultAllocator))
+	if err := w.Write(rec); err != nil {
+		_ = w.Close()
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// encodeTables encodes the tables built by the builders.
+func encodeTables(builders []tableBuilder) (*Request, error) {
+	req := &Request{Tables: make([][]byte, len(builders))}
+	for i, b := range builders {
+		table, err := b.encode()
+		if err != nil {
+			return nil, err
+		}
+		req.Tables[i] = table
+	}
+	return req, nil
+}
+
+// table is a decoded table, its columns have the types of the schema it was decoded with.
+type table struct {
+	array.Record
+}
+
+// decodeTable decodes the Arrow IPC stream of a table, which must have the schema.
+func decodeTable(data []byte, schema *arrow.Schema) (table, error) {
+	r, err := ipc.NewReader(bytes.NewReader(data), ipc.WithSchema(schema), ipc.WithAllocator(memory.DefaultAllocator))
+	if err != nil {
+		return table{}, err
+	}
+	defer r.Release()
+
+	if !r.Next() {
+		if r.Err() != nil {
+			return table{}, r.Err()
+		}
+		return table{}, errors.New("the table has no record batch")
+	}
+	rec := r.Record()
+	rec.Retain()
+	if r.Next() {
+		rec.Release()
+		return table{}, errors.New("the table has more than one record batch")
+	}
+	return table{Record: rec}, nil
+}
+
+func (t table) rows() int                     { return int(t.NumRows()) }
+func (t table) uint8s(i int) *array.Uint8     { return t.Column(i).(*array.Uint8) }
+func (t table) uint32s(i int) *array.Uint32   { return t.Column(i).(*array.Uint32) }
+func (t table) int32s(i int) *array.Int32     { return t.Column(i).(*array.Int32) }
+func (t table) int64s(i int) *array.Int64     { return t.Column(i).(*array.Int64) }
+func (t table) uint64s(i int) *array.Uint64   { return t.Column(i).(*array.Uint64) }
+func (t table) float64s(i int) *array.Float64 { return t.Column(i).(*array.Float64) }
+func (t table) bools(i int) *array.Boolean    { return t.Column(i).(*array.Boolean) }
+func (t table) strings(i int) *array.String   { return t.Column(i).(*array.String) }
+func (t table) binaries(i int) *array.Binary  { return t.Column(i).(*array.Binary) }
+func (t table) fixedBinaries(i int) *array.FixedSizeBinary {
+	return t.Column(i).(*array.FixedSizeBinary)
+}
+
+// parents returns the parent rows of the rows, which are checked to be less than numParents.
+func (t table) parents(numParents int) ([]uint32, error) {
+	parents := t.uint32s(0).Uint32Values()[:t.rows()]
+	for _, parent := range parents {
+		if int(parent) >= numParents {
+			return nil, fmt.Errorf("invalid parent row %d of %d rows", parent, numParents)
+		}
+	}
+	return parents, nil
+}
+
+// uint64List returns the uint64 list of the list column i at the row.
+func (t table) uint64List(i, row int) []uint64 {
+	list := t.Column(i).(*array.List)
+	offsets := list.Offsets()[list.Data().Offset():]
+	values := list.ListValues().(*array.Uint64).Uint64Values()[offsets[row]:offsets[row+1]]
+	if len(values) == 0 {
+		return nil
+	}
+	return append([]uint64(nil), values...)
+}
+
+// float64List returns the float64 list of the list column i at the row.
+func (t table) float64List(i, row int) []float64 {
+	list := t.Column(i).(*array.List)
+	offsets := list.Offsets()[list.Data().Offset():]
+	values := list.ListValues().(*array.Float64).Float64Values()[offsets[row]:offsets[row+1]]
+	if len(values) == 0 {
+		return nil
+	}
+	return append([]float64(nil), values...)
+}
+
+// binaryList returns the list of binary values of the list column i at the row.
+func (t table) binaryList(i, row int) [][]byte {
+	list := t.Column(i).(*array.List)
+	offsets := list.Offsets()[list.Data().Offset():]
+	values := list.ListValues().(*array.Binary)
+	if offsets[row] == offsets[row+1] {
+		return nil
+	}
+	result := make([][]byte, 0, offsets[row+1]-offsets[row])
+	for j := offsets[row]; j < offsets[row+1]; j++ {
+		result = append(result, values.Value(int(j)))
+	}
+	return result
+}
+
+// decodeTables decodes the tables of a request with their schemas, and releases them if any
+// fails to be decoded. A malformed table can make the Arrow library panic, which is reported
+// as an error.
+func decodeTables(req *Request, schemas []*arrow.Schema) (tables []table, err error) {
+	if len(req.Tables) != len(schemas) {
+		return nil, fmt.Errorf("%d tables expected, got %d", len(schemas), len(req.Tables))
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("malformed table: %v", r)
+		}
+		if err != nil {
+			releaseTables(tables)
+			tables = nil
+		}
+	}()
+	for i, data := range req.Tables {
+		t, err := decodeTable(data, schemas[i])
+		if err != nil {
+			return tables, fmt.Errorf("table %d: %w", i, err)
+		}
+		tables = append(tables, t)
+	}
+	return tables, nil
+}
+
+func releaseTables(tables []table) {
+	for _, t := range tables {
+		t.Release()
+	}
+}
+
+// recoverMalformed reports the panics on malformed tables as an error.
+func recoverMalformed(err *error) {
+	if r := recover(); r != nil {
+		*err = fmt.Errorf("malformed table: %v", r)
+	}
+}
@@ -0,0 +1,349 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build arrow
+
+package otlparrow
+
+import (
+	"github.com/apache/arrow/go/arrow"
+
+	"go.opentelemetry.io/collector/internal/data"
+	otlptrace "go.opentelemetry.io/collector/internal/data/protogen/trace/v1"
+)
+
+// The tables of the traces requests.
+const (
+	tracesResources = iota
+	tracesResourceAttributes
+	tracesLibraries
+	tracesSpans
+	tracesSpanAttributes
+	tracesEvents
+	tracesEventAttributes
+	tracesLinks
+	tracesLinkAttributes
+)
+
+// The parent of the spans is their instrumentation library.
+const (
+	spanParent = iota
+	spanTraceID
+	spanSpanID
+	spanTraceState
+	spanParentSpanID
+	spanName
+	spanKind
+	spanStartTime
+	spanEndTime
+	spanDroppedAttributesCount
+	spanDroppedEventsCount
+	spanDroppedLinksCount
+	spanStatusCode
+	spanStatusDeprecatedCode
+	spanStatusMessage
+)
+
+var spansSchema = newSchema(
+	arrowField("parent", uint32Type),
+	arrowField("trace_id", traceIDType),
+	arrowField("span_id", spanIDType),
+	arrowField("trace_state", stringType),
+	// The parent span ID is empty for the root spans.
+	arrowField("parent_span_id", binaryType),
+	arrowField("name", stringType),
+	arrowField("kind", int32Type),
+	arrowField("start_time_unix_nano", uint64Type),
+	arrowField("end_time_unix_nano", uint64Type),
+	arrowField("dropped_attributes_count", uint32Type),
+	arrowField("dropped_events_count", uint32Type),
+	arrowField("dropped_links_count", uint32Type),
+	arrowField("status_code", int32Type),
+	arrowField("status_deprecated_code", int32Type),
+	arrowField("status_message", stringType),
+)
+
+// The parent of the events is their span.
+const (
+	eventParent = iota
+	eventTime
+	eventName
+	eventDroppedAttributesCount
+)
+
+var eventsSchema = newSchema(
+	arrowField("parent", uint32Type),
+	arrowField("time_unix_nano", uint64Type),
+	arrowField("name", stringType),
+	arrowField("dropped_attributes_count", uint32Type),
+)
+
+// The parent of the links is their span.
+const (
+	linkParent = iota
+	linkTraceID
+	linkSpanID
+	linkTraceState
+	linkDroppedAttributesCount
+)
+
+var linksSchema = newSchema(
+	arrowField("parent", uint32Type),
+	arrowField("trace_id", traceIDType),
+	arrowField("span_id", spanIDType),
+	arrowField("trace_state", stringType),
+	arrowField("dropped_attributes_count", uint32Type),
+)
+
+var tracesSchemas = []*arrow.Schema{
+	tracesResources:          resourcesSchema,
+	tracesResourceAttributes: attributesSchema,
+	tracesLibraries:          librariesSchema,
+	tracesSpans:              spansSchema,
+	tracesSpanAttributes:     attributesSchema,
+	tracesEvents:             eventsSchema,
+	tracesEventAttributes:    attributesSchema,
+	tracesLinks:              linksSchema,
+	tracesLinkAttributes:     attributesSchema,
+}
+
+// EncodeTraces encodes the resource spans of an OTLP traces request in the tables of an
+// Arrow request.
+func EncodeTraces(rss []*otlptrace.ResourceSpans) (*Request, error) {
+	builders := make([]tableBuilder, len(tracesSchemas))
+	for i, schema := range tracesSchemas {
+		builders[i] = newTableBuilder(schema)
+		defer builders[i].Release()
+	}
+
+	resources := builders[tracesResources]
+	libraries := builders[tracesLibraries]
+	spans := builders[tracesSpans]
+	events := builders[tracesEvents]
+	links := builders[tracesLinks]
+	for _, rs := range rss {
+		if rs == nil {
+			rs = &otlptrace.ResourceSpans{}
+		}
+		resource := resources.uint32s(0).Len()
+		if err := appendResource(resources, builders[tracesResourceAttributes], &rs.Resource); err != nil {
+			return nil, err
+		}
+		for _, ils := range rs.InstrumentationLibrarySpans {
+			if ils == nil {
+				ils = &otlptrace.InstrumentationLibrarySpans{}
+			}
+			library := libraries.uint32s(libraryParent).Len()
+			appendLibrary(libraries, resource, ils.InstrumentationLibrary)
+			for _, span := range ils.Spans {
+				if span == nil {
+					span = &otlptrace.Span{}
+				}
+				row := spans.uint32s(spanParent).Len()
+				appendSpan(spans, library, span)
+				if err := appendAttributes(builders[tracesSpanAttributes], row, span.Attributes); err != nil {
+					return nil, err
+				}
+				for _, event := range span.Events {
+					if event == nil {
+						event = &otlptrace.Span_Event{}
+					}
+					eventRow := events.uint32s(eventParent).Len()
+					events.uint32s(eventParent).Append(uint32(row))
+					events.uint64s(eventTime).Append(event.TimeUnixNano)
+					events.strings(eventName).Append(event.Name)
+					events.uint32s(eventDroppedAttributesCount).Append(event.DroppedAttributesCount)
+					if err := appendAttributes(builders[tracesEventAttributes], eventRow, event.Attributes); err != nil {
+						return nil, err
+					}
+				}
+				for _, link := range span.Links {
+					if link == nil {
+						link = &otlptrace.Span_Link{}
+					}
+					linkRow := links.uint32s(linkParent).Len()
+					traceID := link.TraceId.Bytes()
+					spanID := link.SpanId.Bytes()
+					links.uint32s(linkParent).Append(uint32(row))
+					links.fixedBinaries(linkTraceID).Append(traceID[:])
+					links.fixedBinaries(linkSpanID).Append(spanID[:])
+					links.strings(linkTraceState).Append(link.TraceState)
+					links.uint32s(linkDroppedAttributesCount).Append(link.DroppedAttributesCount)
+					if err := appendAttributes(builders[tracesLinkAttributes], linkRow, link.Attributes); err != nil {
+						return nil, err
+					}
+				}
+			}
+		}
+	}
+	return encodeTables(builders)
+}
+
+func appendSpan(b tableBuilder, parent int, span *otlptrace.Span) {
+	traceID := span.TraceId.Bytes()
+	spanID := span.SpanId.Bytes()
+	var parentSpanID []byte
+	if !span.ParentSpanId.IsEmpty() {
+		id := span.ParentSpanId.Bytes()
+		parentSpanID = id[:]
+	}
+	b.uint32s(spanParent).Append(uint32(parent))
+	b.fixedBinaries(spanTraceID).Append(traceID[:])
+	b.fixedBinaries(spanSpanID).Append(spanID[:])
+	b.strings(spanTraceState).Append(span.TraceState)
+	b.binaries(spanParentSpanID).Append(parentSpanID)
+	b.strings(spanName).Append(span.Name)
+	b.int32s(spanKind).Append(int32(span.Kind))
+	b.uint64s(spanStartTime).Append(span.StartTimeUnixNano)
+	b.uint64s(spanEndTime).Append(span.EndTimeUnixNano)
+	b.uint32s(spanDroppedAttributesCount).Append(span.DroppedAttributesCount)
+	b.uint32s(spanDroppedEventsCount).Append(span.DroppedEventsCount)
+	b.uint32s(spanDroppedLinksCount).Append(span.DroppedLinksCount)
+	b.int32s(spanStatusCode).Append(int32(span.Status.Code))
+	b.int32s(spanStatusDeprecatedCode).Append(int32(span.Status.DeprecatedCode))
+	b.strings(spanStatusMessage).Append(span.Status.Message)
+}
+
+// DecodeTraces decodes the resource spans of an OTLP traces request from the tables of an
+// Arrow request.
+func DecodeTraces(req *Request) (rss []*otlptrace.ResourceSpans, err error) {
+	tables, err := decodeTables(req, tracesSchemas)
+	if err != nil {
+		return nil, err
+	}
+	defer releaseTables(tables)
+	defer recoverMalformed(&err)
+
+	resources, err := decodeResources(tables[tracesResources], tables[tracesResourceAttributes])
+	if err != nil {
+		return nil, err
+	}
+	rss = make([]*otlptrace.ResourceSpans, len(resources))
+	for i := range resources {
+		rss[i] = &otlptrace.ResourceSpans{Resource: resources[i]}
+	}
+
+	libraries := tables[tracesLibraries]
+	libraryParents, err := libraries.parents(len(rss))
+	if err != nil {
+		return nil, err
+	}
+	ilss := make([]*otlptrace.InstrumentationLibrarySpans, libraries.rows())
+	for row, parent := range libraryParents {
+		ilss[row] = &otlptrace.InstrumentationLibrarySpans{InstrumentationLibrary: decodeLibrary(libraries, row)}
+		rss[parent].InstrumentationLibrarySpans = append(rss[parent].InstrumentationLibrarySpans, ilss[row])
+	}
+
+	spansTable := tables[tracesSpans]
+	spanParents, err := spansTable.parents(len(ilss))
+	if err != nil {
+		return nil, err
+	}
+	spanAttributes, err := decodeAttributes(tables[tracesSpanAttributes], spansTable.rows())
+	if err != nil {
+		return nil, err
+	}
+	spans := make([]*otlptrace.Span, spansTable.rows())
+	for row, parent := range spanParents {
+		spans[row] = decodeSpan(spansTable, row)
+		spans[row].Attributes = spanAttributes[row]
+		ilss[parent].Spans = append(ilss[parent].Spans, spans[row])
+	}
+
+	if err = decodeEvents(tables[tracesEvents], tables[tracesEventAttributes], spans); err != nil {
+		return nil, err
+	}
+	if err = decodeLinks(tables[tracesLinks], tables[tracesLinkAttributes], spans); err != nil {
+		return nil, err
+	}
+	return rss, nil
+}
+
+func decodeSpan(t table, row int) *otlptrace.Span {
+	var parentSpanID data.SpanID
+	if id := t.binaries(spanParentSpanID).Value(row); len(id) > 0 {
+		parentSpanID = spanIDFromBytes(id)
+	}
+	return &otlptrace.Span{
+		TraceId:                traceIDFromBytes(t.fixedBinaries(spanTraceID).Value(row)),
+		SpanId:                 spanIDFromBytes(t.fixedBinaries(spanSpanID).Value(row)),
+		TraceState:             t.strings(spanTraceState).Value(row),
+		ParentSpanId:           parentSpanID,
+		Name:                   t.strings(spanName).Value(row),
+		Kind:                   otlptrace.Span_SpanKind(t.int32s(spanKind).Value(row)),
+		StartTimeUnixNano:      t.uint64s(spanStartTime).Value(row),
+		EndTimeUnixNano:        t.uint64s(spanEndTime).Value(row),
+		DroppedAttributesCount: t.uint32s(spanDroppedAttributesCount).Value(row),
+		DroppedEventsCount:     t.uint32s(spanDroppedEventsCount).Value(row),
+		DroppedLinksCount:      t.uint32s(spanDroppedLinksCount).Value(row),
+		Status: otlptrace.Status{
+			Code:           otlptrace.Status_StatusCode(t.int32s(spanStatusCode).Value(row)),
+			DeprecatedCode: otlptrace.Status_DeprecatedStatusCode(t.int32s(spanStatusDeprecatedCode).Value(row)),
+			Message:        t.strings(spanStatusMessage).Value(row),
+		},
+	}
+}
+
+func decodeEvents(events, attributes table, spans []*otlptrace.Span) error {
+	parents, err := events.parents(len(spans))
+	if err != nil {
+		return err
+	}
+	attrs, err := decodeAttributes(attributes, events.rows())
+	if err != nil {
+		return err
+	}
+	for row, parent := range parents {
+		spans[parent].Events = append(spans[parent].Events, &otlptrace.Span_Event{
+			TimeUnixNano:           events.uint64s(eventTime).Value(row),
+			Name:                   events.strings(eventName).Value(row),
+			Attributes:             attrs[row],
+			DroppedAttributesCount: events.uint32s(eventDroppedAttributesCount).Value(row),
+		})
+	}
+	return nil
+}
+
+func decodeLinks(links, attributes table, spans []*otlptrace.Span) error {
+	parents, err := links.parents(len(spans))
+	if err != nil {
+		return err
+	}
+	attrs, err := decodeAttributes(attributes, links.rows())
+	if err != nil {
+		return err
+	}
+	for row, parent := range parents {
+		spans[parent].Links = append(spans[parent].Links, &otlptrace.Span_Link{
+			TraceId:                traceIDFromBytes(links.fixedBinaries(linkTraceID).Value(row)),
+			SpanId:                 spanIDFromBytes(links.fixedBinaries(linkSpanID).Value(row)),
+			TraceState:             links.strings(linkTraceState).Value(row),
+			Attributes:             attrs[row],
+			DroppedAttributesCount: links.uint32s(linkDroppedAttributesCount).Value(row),
+		})
+	}
+	return nil
+}
+
+func traceIDFromBytes(b []byte) data.TraceID {
+	var id [16]byte
+	copy(id[:], b)
+	return data.NewTraceID(id)
+}
+
+func spanIDFromBytes(b []byte) data.SpanID {
+	var id [8]byte
+	copy(id[:], b)
+	return data.NewSpanID(id)
+}
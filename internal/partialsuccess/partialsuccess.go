@@ -0,0 +1,137 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package partialsuccess decodes the partial success of the OTLP export responses of all the
+// signals. The field was added to OTLP after the version of the protos generated in
+// internal/data/protogen, so it is decoded from the serialized responses.
+package partialsuccess
+
+import (
+	"errors"
+	"fmt"
+
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"go.opentelemetry.io/collector/consumer/consumererror"
+)
+
+// The field numbers are the same for the responses of all the signals:
+//
+//	message Export<Signal>ServiceResponse {
+//	  Export<Signal>PartialSuccess partial_success = 1;
+//	}
+//	message Export<Signal>PartialSuccess {
+//	  int64 rejected_<items> = 1;
+//	  string error_message = 2;
+//	}
+const (
+	partialSuccessFieldNumber = 1
+	rejectedFieldNumber       = 1
+	errorMessageFieldNumber   = 2
+)
+
+var errInvalidResponse = errors.New("invalid OTLP export response")
+
+// PartialSuccess is the partial success of an OTLP export response.
+type PartialSuccess struct {
+	// Rejected is the number of items, spans, data points or log records, rejected by the server.
+	Rejected int64
+	// ErrorMessage explains why items were rejected, or is a warning when none was.
+	ErrorMessage string
+}
+
+// Error returns the error reporting the rejected items, or nil when none was rejected.
+// The error is permanent unless retry is set.
+func (ps PartialSuccess) Error(retry bool) error {
+	if ps.Rejected <= 0 {
+		return nil
+	}
+	err := consumererror.NewRejectedError(
+		fmt.Errorf("the server rejected %d items: %s", ps.Rejected, ps.ErrorMessage), int(ps.Rejected))
+	if !retry {
+		return consumererror.Permanent(err)
+	}
+	return err
+}
+
+// Unmarshal decodes the partial success of a serialized OTLP export response of any signal.
+func Unmarshal(buf []byte) (PartialSuccess, error) {
+	var ps PartialSuccess
+	err := decodeFields(buf, func(num protowire.Number, typ protowire.Type, value []byte) error {
+		if num != partialSuccessFieldNumber || typ != protowire.BytesType {
+			return nil
+		}
+		return decodeFields(value, func(num protowire.Number, typ protowire.Type, value []byte) error {
+			switch {
+			case num == rejectedFieldNumber && typ == protowire.VarintType:
+				v, n := protowire.ConsumeVarint(value)
+				if n < 0 {
+					return errInvalidResponse
+				}
+				ps.Rejected = int64(v)
+			case num == errorMessageFieldNumber && typ == protowire.BytesType:
+				ps.ErrorMessage = string(value)
+			}
+			return nil
+		})
+	})
+	return ps, err
+}
+
+// decodeFields calls fn with the number, type and value of the fields of a serialized message.
+// The value of the length delimited fields is their content, the other values are undecoded.
+func decodeFields(buf []byte, fn func(protowire.Number, protowire.Type, []byte) error) error {
+	for len(buf) > 0 {
+		num, typ, n := protowire.ConsumeTag(buf)
+		if n < 0 {
+			return errInvalidResponse
+		}
+		buf = buf[n:]
+		n = protowire.ConsumeFieldValue(num, typ, buf)
+		if n < 0 {
+			return errInvalidResponse
+		}
+		value := buf[:n]
+		if typ == protowire.BytesType {
+			value, _ = protowire.ConsumeBytes(value)
+		}
+		if err := fn(num, typ, value); err != nil {
+			return err
+		}
+		buf = buf[n:]
+	}
+	return nil
+}
+
+// Response is an OTLP export response of any signal, to be passed to grpc.ClientConn.Invoke
+// in place of the generated responses that cannot hold the partial success.
+type Response struct {
+	PartialSuccess PartialSuccess
+}
+
+// Reset implements proto.Message.
+func (r *Response) Reset() { *r = Response{} }
+
+// String implements proto.Message.
+func (r *Response) String() string { return fmt.Sprintf("%+v", *r) }
+
+// ProtoMessage implements proto.Message.
+func (r *Response) ProtoMessage() {}
+
+// Unmarshal decodes the partial success of the serialized response, and is used by the gRPC codec.
+func (r *Response) Unmarshal(buf []byte) error {
+	ps, err := Unmarshal(buf)
+	r.PartialSuccess = ps
+	return err
+}
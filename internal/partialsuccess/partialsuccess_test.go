@@ -0,0 +1,82 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package partialsuccess
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"go.opentelemetry.io/collector/consumer/consumererror"
+)
+
+// marshalResponse serializes an OTLP export response with the given partial success.
+func marshalResponse(rejected int64, errorMessage string) []byte {
+	var ps []byte
+	ps = protowire.AppendTag(ps, rejectedFieldNumber, protowire.VarintType)
+	ps = protowire.AppendVarint(ps, uint64(rejected))
+	ps = protowire.AppendTag(ps, errorMessageFieldNumber, protowire.BytesType)
+	ps = protowire.AppendString(ps, errorMessage)
+	// An unknown field, as sent by a newer server.
+	ps = protowire.AppendTag(ps, 15, protowire.Fixed32Type)
+	ps = protowire.AppendFixed32(ps, 42)
+
+	var buf []byte
+	buf = protowire.AppendTag(buf, partialSuccessFieldNumber, protowire.BytesType)
+	return protowire.AppendBytes(buf, ps)
+}
+
+func TestUnmarshal(t *testing.T) {
+	ps, err := Unmarshal(marshalResponse(3, "invalid spans"))
+	require.NoError(t, err)
+	assert.Equal(t, PartialSuccess{Rejected: 3, ErrorMessage: "invalid spans"}, ps)
+
+	ps, err = Unmarshal(nil)
+	require.NoError(t, err)
+	assert.Equal(t, PartialSuccess{}, ps)
+}
+
+func TestUnmarshalInvalid(t *testing.T) {
+	buf := marshalResponse(3, "invalid spans")
+	_, err := Unmarshal(buf[:len(buf)-2])
+	assert.Equal(t, errInvalidResponse, err)
+}
+
+func TestResponseGRPCCodec(t *testing.T) {
+	var resp Response
+	require.NoError(t, encoding.GetCodec("proto").Unmarshal(marshalResponse(2, "too old"), &resp))
+	assert.Equal(t, PartialSuccess{Rejected: 2, ErrorMessage: "too old"}, resp.PartialSuccess)
+}
+
+func TestPartialSuccessError(t *testing.T) {
+	assert.NoError(t, PartialSuccess{}.Error(false))
+	assert.NoError(t, PartialSuccess{ErrorMessage: "a warning"}.Error(false))
+
+	err := PartialSuccess{Rejected: 3, ErrorMessage: "invalid spans"}.Error(false)
+	assert.True(t, consumererror.IsPermanent(err))
+	rejected, ok := consumererror.RejectedItems(err)
+	assert.True(t, ok)
+	assert.Equal(t, 3, rejected)
+	assert.Contains(t, err.Error(), "invalid spans")
+
+	err = PartialSuccess{Rejected: 3}.Error(true)
+	assert.False(t, consumererror.IsPermanent(err))
+	var rejectedErr consumererror.RejectedError
+	assert.True(t, errors.As(err, &rejectedErr))
+}
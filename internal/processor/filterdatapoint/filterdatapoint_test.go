@@ -0,0 +1,153 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filterdatapoint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal/processor/filterconfig"
+	"go.opentelemetry.io/collector/internal/processor/filterset"
+)
+
+func createConfig(matchType filterset.MatchType) *filterset.Config {
+	return &filterset.Config{
+		MatchType: matchType,
+	}
+}
+
+func TestDataPoint_validateMatchesConfiguration_InvalidConfig(t *testing.T) {
+	testcases := []struct {
+		name        string
+		property    filterconfig.MatchProperties
+		errorString string
+	}{
+		{
+			name:        "empty_property",
+			property:    filterconfig.MatchProperties{},
+			errorString: "at least one of \"metric_names\", \"attributes\", \"libraries\" or \"resources\" field must be specified",
+		},
+		{
+			name: "span_properties",
+			property: filterconfig.MatchProperties{
+				SpanNames: []string{"span"},
+			},
+			errorString: "neither services, span_names nor log_names should be specified for metric data points",
+		},
+		{
+			name: "log_properties",
+			property: filterconfig.MatchProperties{
+				LogNames: []string{"log"},
+			},
+			errorString: "neither services, span_names nor log_names should be specified for metric data points",
+		},
+		{
+			name: "invalid_match_type",
+			property: filterconfig.MatchProperties{
+				Config:      *createConfig("wrong_match_type"),
+				MetricNames: []string{"abc"},
+			},
+			errorString: "error creating metric name filters: unrecognized match_type: 'wrong_match_type', valid types are: [regexp strict]",
+		},
+		{
+			name: "invalid_regexp_pattern",
+			property: filterconfig.MatchProperties{
+				Config:      *createConfig(filterset.Regexp),
+				MetricNames: []string{"["},
+			},
+			errorString: "error creating metric name filters: error parsing regexp: missing closing ]: `[`",
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			output, err := NewMatcher(&tc.property)
+			assert.Nil(t, output)
+			require.NotNil(t, err)
+			assert.Equal(t, tc.errorString, err.Error())
+		})
+	}
+}
+
+func TestDataPoint_Matching(t *testing.T) {
+	testcases := []struct {
+		name       string
+		properties *filterconfig.MatchProperties
+		expected   bool
+	}{
+		{
+			name: "metric_name_match",
+			properties: &filterconfig.MatchProperties{
+				Config:      *createConfig(filterset.Regexp),
+				MetricNames: []string{"wrong.*pattern", "metric.*"},
+			},
+			expected: true,
+		},
+		{
+			name: "metric_name_doesnt_match",
+			properties: &filterconfig.MatchProperties{
+				Config:      *createConfig(filterset.Regexp),
+				MetricNames: []string{"metricNo.*Name"},
+			},
+			expected: false,
+		},
+		{
+			name: "attribute_match",
+			properties: &filterconfig.MatchProperties{
+				Config:     *createConfig(filterset.Strict),
+				Attributes: []filterconfig.Attribute{{Key: "label", Value: "value"}},
+			},
+			expected: true,
+		},
+		{
+			name: "attribute_doesnt_match",
+			properties: &filterconfig.MatchProperties{
+				Config:     *createConfig(filterset.Strict),
+				Attributes: []filterconfig.Attribute{{Key: "label", Value: "other"}},
+			},
+			expected: false,
+		},
+		{
+			name: "metric_name_and_attribute_match",
+			properties: &filterconfig.MatchProperties{
+				Config:      *createConfig(filterset.Strict),
+				MetricNames: []string{"metricName"},
+				Attributes:  []filterconfig.Attribute{{Key: "label"}},
+			},
+			expected: true,
+		},
+	}
+
+	attrs := pdata.NewAttributeMap().InitFromMap(map[string]pdata.AttributeValue{
+		"label": pdata.NewAttributeValueString("value"),
+	})
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			matcher, err := NewMatcher(tc.properties)
+			require.NoError(t, err)
+			require.NotNil(t, matcher)
+
+			assert.Equal(t, tc.expected, matcher.MatchDataPoint("metricName", attrs, pdata.Resource{}, pdata.InstrumentationLibrary{}))
+		})
+	}
+}
+
+func TestDataPoint_NilProperties(t *testing.T) {
+	matcher, err := NewMatcher(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, matcher)
+}
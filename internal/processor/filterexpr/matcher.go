@@ -15,10 +15,15 @@
 package filterexpr
 
 import (
+	"fmt"
+	"math"
+	"strconv"
+
 	"github.com/antonmedv/expr"
 	"github.com/antonmedv/expr/vm"
 
 	"go.opentelemetry.io/collector/consumer/pdata"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
 )
 
 type Matcher struct {
@@ -31,6 +36,31 @@ type env struct {
 	// TODO: replace this with GetLabel func(key string) (string,bool)
 	HasLabel func(key string) bool
 	Label    func(key string) string
+	// NumericLabel returns the label value parsed as a number, or NaN.
+	NumericLabel func(key string) float64
+}
+
+type spanEnv struct {
+	SpanName       string
+	SpanKind       string
+	StatusCode     string
+	DurationMillis float64
+	attributesEnv
+}
+
+type logEnv struct {
+	LogName        string
+	SeverityText   string
+	SeverityNumber int64
+	Body           interface{}
+	attributesEnv
+}
+
+type attributesEnv struct {
+	HasAttribute         func(key string) bool
+	Attribute            func(key string) interface{}
+	HasResourceAttribute func(key string) bool
+	ResourceAttribute    func(key string) interface{}
 }
 
 func NewMatcher(expression string) (*Matcher, error) {
@@ -160,13 +190,112 @@ func createEnv(metricName string, labelsMap pdata.StringMap) env {
 			v, _ := labelsMap.Get(key)
 			return v
 		},
+		NumericLabel: func(key string) float64 {
+			v, ok := labelsMap.Get(key)
+			if !ok {
+				return math.NaN()
+			}
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return math.NaN()
+			}
+			return f
+		},
+	}
+}
+
+// MatchSpan evaluates the expression against the given span and its resource.
+func (m *Matcher) MatchSpan(span pdata.Span, resource pdata.Resource) (bool, error) {
+	return m.match(spanEnv{
+		SpanName:       span.Name(),
+		SpanKind:       spanKinds[span.Kind()],
+		StatusCode:     statusCodes[span.Status().Code()],
+		DurationMillis: float64(span.EndTime()-span.StartTime()) / 1e6,
+		attributesEnv:  createAttributesEnv(span.Attributes(), resource),
+	})
+}
+
+// MatchLogRecord evaluates the expression against the given log record and its resource.
+func (m *Matcher) MatchLogRecord(lr pdata.LogRecord, resource pdata.Resource) (bool, error) {
+	return m.match(logEnv{
+		LogName:        lr.Name(),
+		SeverityText:   lr.SeverityText(),
+		SeverityNumber: int64(lr.SeverityNumber()),
+		Body:           attributeValue(lr.Body()),
+		attributesEnv:  createAttributesEnv(lr.Attributes(), resource),
+	})
+}
+
+var spanKinds = map[pdata.SpanKind]string{
+	pdata.SpanKindUNSPECIFIED: "Unspecified",
+	pdata.SpanKindINTERNAL:    "Internal",
+	pdata.SpanKindSERVER:      "Server",
+	pdata.SpanKindCLIENT:      "Client",
+	pdata.SpanKindPRODUCER:    "Producer",
+	pdata.SpanKindCONSUMER:    "Consumer",
+}
+
+var statusCodes = map[pdata.StatusCode]string{
+	pdata.StatusCodeUnset: "Unset",
+	pdata.StatusCodeOk:    "Ok",
+	pdata.StatusCodeError: "Error",
+}
+
+func createAttributesEnv(attrs pdata.AttributeMap, resource pdata.Resource) attributesEnv {
+	resourceAttrs := resource.Attributes()
+	return attributesEnv{
+		HasAttribute: func(key string) bool {
+			_, ok := attrs.Get(key)
+			return ok
+		},
+		Attribute: func(key string) interface{} {
+			v, ok := attrs.Get(key)
+			if !ok {
+				return nil
+			}
+			return attributeValue(v)
+		},
+		HasResourceAttribute: func(key string) bool {
+			_, ok := resourceAttrs.Get(key)
+			return ok
+		},
+		ResourceAttribute: func(key string) interface{} {
+			v, ok := resourceAttrs.Get(key)
+			if !ok {
+				return nil
+			}
+			return attributeValue(v)
+		},
 	}
 }
 
-func (m *Matcher) match(env env) (bool, error) {
+// attributeValue returns the value as a native type so that it can be
+// compared with expr literals, maps and arrays are returned as JSON strings.
+func attributeValue(v pdata.AttributeValue) interface{} {
+	switch v.Type() {
+	case pdata.AttributeValueSTRING:
+		return v.StringVal()
+	case pdata.AttributeValueINT:
+		return v.IntVal()
+	case pdata.AttributeValueDOUBLE:
+		return v.DoubleVal()
+	case pdata.AttributeValueBOOL:
+		return v.BoolVal()
+	case pdata.AttributeValueNULL:
+		return nil
+	default:
+		return tracetranslator.AttributeValueToString(v, true)
+	}
+}
+
+func (m *Matcher) match(env interface{}) (bool, error) {
 	result, err := m.v.Run(m.program, env)
 	if err != nil {
 		return false, err
 	}
-	return result.(bool), nil
+	matched, ok := result.(bool)
+	if !ok {
+		return false, fmt.Errorf("expression returned %T, expected a boolean", result)
+	}
+	return matched, nil
 }
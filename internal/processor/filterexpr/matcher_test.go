@@ -234,6 +234,21 @@ func TestMatchIntGaugeDataPointByMetricAndSecondPointLabelValue(t *testing.T) {
 	assert.True(t, matched)
 }
 
+func TestMatchIntGaugeDataPointByNumericLabel(t *testing.T) {
+	expression := `MetricName == 'my.metric' && NumericLabel("code") >= 500`
+	assert.True(t, testMatchIntGauge(t, "my.metric", expression, map[string]string{"code": "503"}))
+	assert.False(t, testMatchIntGauge(t, "my.metric", expression, map[string]string{"code": "200"}))
+	assert.False(t, testMatchIntGauge(t, "my.metric", expression, map[string]string{"code": "abc"}))
+	assert.False(t, testMatchIntGauge(t, "my.metric", expression, nil))
+}
+
+func TestNonBooleanResult(t *testing.T) {
+	matcher, err := NewMatcher(`MetricName`)
+	require.NoError(t, err)
+	_, err = matcher.match(env{MetricName: "my.metric"})
+	assert.Error(t, err)
+}
+
 func TestMatchDoubleGaugeByMetricName(t *testing.T) {
 	assert.True(t, testMatchDoubleGauge(t, "my.metric"))
 }
@@ -343,3 +358,71 @@ func matchDoubleHistogram(t *testing.T, metricName string) bool {
 	assert.NoError(t, err)
 	return matched
 }
+
+func TestMatchSpan(t *testing.T) {
+	span := pdata.NewSpan()
+	span.SetName("GET /api")
+	span.SetKind(pdata.SpanKindSERVER)
+	span.Status().SetCode(pdata.StatusCodeError)
+	span.SetStartTime(pdata.Timestamp(1000000000))
+	span.SetEndTime(pdata.Timestamp(1250000000))
+	span.Attributes().InitFromMap(map[string]pdata.AttributeValue{
+		"http.status_code": pdata.NewAttributeValueInt(503),
+		"http.method":      pdata.NewAttributeValueString("GET"),
+	})
+	resource := pdata.NewResource()
+	resource.Attributes().InsertString("service.name", "checkout")
+
+	tests := []struct {
+		expression string
+		matched    bool
+	}{
+		{expression: `SpanName == "GET /api" && Attribute("http.status_code") >= 500`, matched: true},
+		{expression: `Attribute("http.status_code") < 500`, matched: false},
+		{expression: `SpanKind == "Server" && StatusCode == "Error"`, matched: true},
+		{expression: `DurationMillis > 200 && DurationMillis < 300`, matched: true},
+		{expression: `HasAttribute("http.method") && !HasAttribute("foo")`, matched: true},
+		{expression: `ResourceAttribute("service.name") == "checkout"`, matched: true},
+		{expression: `HasResourceAttribute("foo")`, matched: false},
+		{expression: `Attribute("foo") == nil`, matched: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			matcher, err := NewMatcher(tt.expression)
+			require.NoError(t, err)
+			matched, err := matcher.MatchSpan(span, resource)
+			require.NoError(t, err)
+			assert.Equal(t, tt.matched, matched)
+		})
+	}
+}
+
+func TestMatchLogRecord(t *testing.T) {
+	lr := pdata.NewLogRecord()
+	lr.SetName("access")
+	lr.SetSeverityNumber(pdata.SeverityNumberERROR)
+	lr.SetSeverityText("ERROR")
+	lr.Body().SetStringVal("connection refused")
+	lr.Attributes().InsertDouble("latency", 1.5)
+	resource := pdata.NewResource()
+
+	tests := []struct {
+		expression string
+		matched    bool
+	}{
+		{expression: `LogName == "access" && SeverityNumber >= 17`, matched: true},
+		{expression: `SeverityText == "INFO"`, matched: false},
+		{expression: `Body contains "refused"`, matched: true},
+		{expression: `Attribute("latency") > 1`, matched: true},
+		{expression: `ResourceAttribute("service.name") == nil`, matched: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			matcher, err := NewMatcher(tt.expression)
+			require.NoError(t, err)
+			matched, err := matcher.MatchLogRecord(lr, resource)
+			require.NoError(t, err)
+			assert.Equal(t, tt.matched, matched)
+		})
+	}
+}
@@ -56,6 +56,13 @@ func TestLogRecord_validateMatchesConfiguration_InvalidConfig(t *testing.T) {
 			},
 			errorString: "neither services nor span_names should be specified for log records",
 		},
+		{
+			name: "metric_properties",
+			property: filterconfig.MatchProperties{
+				MetricNames: []string{"metric"},
+			},
+			errorString: "metric_names should not be specified for log records",
+		},
 		{
 			name: "invalid_match_type",
 			property: filterconfig.MatchProperties{
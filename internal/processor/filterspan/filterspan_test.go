@@ -58,6 +58,13 @@ func TestSpan_validateMatchesConfiguration_InvalidConfig(t *testing.T) {
 			},
 			errorString: "log_names should not be specified for trace spans",
 		},
+		{
+			name: "metric_properties",
+			property: filterconfig.MatchProperties{
+				MetricNames: []string{"metric"},
+			},
+			errorString: "metric_names should not be specified for trace spans",
+		},
 		{
 			name: "invalid_match_type",
 			property: filterconfig.MatchProperties{
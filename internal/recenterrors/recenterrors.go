@@ -0,0 +1,123 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package recenterrors keeps the recent errors and drops of data of the components in an
+// in-memory ring buffer, rendered by the errorz zPage to diagnose intermittent failures.
+package recenterrors
+
+import (
+	"sync"
+	"time"
+)
+
+// capacity is the maximum number of events kept, the oldest events being removed first.
+const capacity = 128
+
+// The types of the events.
+const (
+	// TypeRefused is the data refused by a receiver or a processor, i.e. its next consumer
+	// returned an error.
+	TypeRefused = "refused"
+	// TypeDropped is the data dropped by a component.
+	TypeDropped = "dropped"
+	// TypeSendFailed is the data that an exporter failed to send.
+	TypeSendFailed = "send_failed"
+)
+
+// Event is an error or a drop of data of a component. The occurrences of the events of the
+// same type of a component are merged, the event keeping the last error message.
+type Event struct {
+	// Kind is the kind of the component, e.g. "receiver".
+	Kind string
+	// Name is the full name of the component, e.g. "otlp/2".
+	Name string
+	// Type is the type of the event, e.g. "refused" or "dropped".
+	Type string
+	// Items is the number of items, e.g. spans, affected by all the occurrences of the event.
+	Items int64
+	// Occurrences is the number of occurrences of the event.
+	Occurrences int64
+	// LastError is the error message of the last occurrence of the event, if any.
+	LastError string
+	// FirstTime is the time of the first occurrence of the event.
+	FirstTime time.Time
+	// LastTime is the time of the last occurrence of the event.
+	LastTime time.Time
+}
+
+var (
+	mu sync.Mutex
+	// events are ordered from the least to the most recently updated.
+	events []*Event
+	now    = time.Now
+)
+
+// Record records an occurrence of an event of a component affecting the given number of items,
+// err being the error causing it, if any.
+func Record(kind, name, typ string, items int, err error) {
+	var msg string
+	if err != nil {
+		msg = err.Error()
+	}
+	t := now()
+
+	mu.Lock()
+	defer mu.Unlock()
+	for i, e := range events {
+		if e.Kind != kind || e.Name != name || e.Type != typ {
+			continue
+		}
+		e.Items += int64(items)
+		e.Occurrences++
+		e.LastTime = t
+		if msg != "" {
+			e.LastError = msg
+		}
+		// Move the event to the end, as the most recent.
+		copy(events[i:], events[i+1:])
+		events[len(events)-1] = e
+		return
+	}
+	if len(events) == capacity {
+		events = events[1:]
+	}
+	events = append(events, &Event{
+		Kind:        kind,
+		Name:        name,
+		Type:        typ,
+		Items:       int64(items),
+		Occurrences: 1,
+		LastError:   msg,
+		FirstTime:   t,
+		LastTime:    t,
+	})
+}
+
+// Events returns a copy of the recent events, the most recent first.
+func Events() []Event {
+	mu.Lock()
+	defer mu.Unlock()
+	out := make([]Event, 0, len(events))
+	for i := len(events) - 1; i >= 0; i-- {
+		out = append(out, *events[i])
+	}
+	return out
+}
+
+// Reset removes all the events.
+func Reset() {
+	mu.Lock()
+	defer mu.Unlock()
+	events = nil
+}
@@ -0,0 +1,78 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package recenterrors
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecord(t *testing.T) {
+	defer Reset()
+	start := time.Unix(1000, 0)
+	current := start
+	now = func() time.Time {
+		current = current.Add(time.Second)
+		return current
+	}
+	defer func() { now = time.Now }()
+
+	assert.Empty(t, Events())
+	Record("exporter", "otlp", "send_failed", 10, errors.New("connection refused"))
+	Record("processor", "batch", "dropped", 5, nil)
+	Record("exporter", "otlp", "send_failed", 3, errors.New("deadline exceeded"))
+
+	assert.Equal(t, []Event{
+		{
+			Kind:        "exporter",
+			Name:        "otlp",
+			Type:        "send_failed",
+			Items:       13,
+			Occurrences: 2,
+			LastError:   "deadline exceeded",
+			FirstTime:   start.Add(time.Second),
+			LastTime:    start.Add(3 * time.Second),
+		},
+		{
+			Kind:        "processor",
+			Name:        "batch",
+			Type:        "dropped",
+			Items:       5,
+			Occurrences: 1,
+			FirstTime:   start.Add(2 * time.Second),
+			LastTime:    start.Add(2 * time.Second),
+		},
+	}, Events())
+
+	// The error message is kept when the last occurrence has none.
+	Record("exporter", "otlp", "send_failed", 1, nil)
+	assert.Equal(t, "deadline exceeded", Events()[0].LastError)
+}
+
+func TestRecordCapacity(t *testing.T) {
+	defer Reset()
+	for i := 0; i < capacity+2; i++ {
+		Record("receiver", "otlp/"+strconv.Itoa(i), "refused", 1, nil)
+	}
+	events := Events()
+	require.Len(t, events, capacity)
+	assert.Equal(t, "otlp/"+strconv.Itoa(capacity+1), events[0].Name)
+	assert.Equal(t, "otlp/2", events[capacity-1].Name)
+}
@@ -22,6 +22,8 @@ import (
 	"go.opencensus.io/trace"
 
 	"go.opentelemetry.io/collector/config/configtelemetry"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/internal/recenterrors"
 )
 
 const (
@@ -117,6 +119,7 @@ func (eor *Exporter) StartTracesExportOp(ctx context.Context) context.Context {
 func (eor *Exporter) EndTracesExportOp(ctx context.Context, numSpans int, err error) {
 	numSent, numFailedToSend := toNumItems(numSpans, err)
 	recordMetrics(ctx, numSent, numFailedToSend, mExporterSentSpans, mExporterFailedToSendSpans)
+	eor.recordEvent(numFailedToSend, err)
 	endSpan(ctx, err, numSent, numFailedToSend, SentSpansKey, FailedToSendSpansKey)
 }
 
@@ -132,6 +135,7 @@ func (eor *Exporter) StartMetricsExportOp(ctx context.Context) context.Context {
 func (eor *Exporter) EndMetricsExportOp(ctx context.Context, numMetricPoints int, err error) {
 	numSent, numFailedToSend := toNumItems(numMetricPoints, err)
 	recordMetrics(ctx, numSent, numFailedToSend, mExporterSentMetricPoints, mExporterFailedToSendMetricPoints)
+	eor.recordEvent(numFailedToSend, err)
 	endSpan(ctx, err, numSent, numFailedToSend, SentMetricPointsKey, FailedToSendMetricPointsKey)
 }
 
@@ -146,9 +150,18 @@ func (eor *Exporter) StartLogsExportOp(ctx context.Context) context.Context {
 func (eor *Exporter) EndLogsExportOp(ctx context.Context, numLogRecords int, err error) {
 	numSent, numFailedToSend := toNumItems(numLogRecords, err)
 	recordMetrics(ctx, numSent, numFailedToSend, mExporterSentLogRecords, mExporterFailedToSendLogRecords)
+	eor.recordEvent(numFailedToSend, err)
 	endSpan(ctx, err, numSent, numFailedToSend, SentLogRecordsKey, FailedToSendLogRecordsKey)
 }
 
+// recordEvent records the data that the exporter failed to send in the recent errors, rendered
+// by the errorz zPage.
+func (eor *Exporter) recordEvent(numFailedToSend int64, err error) {
+	if numFailedToSend > 0 {
+		recenterrors.Record(ExporterKey, eor.exporterName, recenterrors.TypeSendFailed, int(numFailedToSend), err)
+	}
+}
+
 // startSpan creates the span used to trace the operation. Returning
 // the updated context and the created span.
 func (eor *Exporter) startSpan(ctx context.Context, operationSuffix string) context.Context {
@@ -184,6 +197,10 @@ func endSpan(ctx context.Context, err error, numSent, numFailedToSend int64, sen
 
 func toNumItems(numExportedItems int, err error) (int64, int64) {
 	if err != nil {
+		// Only the rejected items failed when the destination accepted the others.
+		if rejected, ok := consumererror.RejectedItems(err); ok && rejected <= numExportedItems {
+			return int64(numExportedItems - rejected), int64(rejected)
+		}
 		return 0, int64(numExportedItems)
 	}
 	return int64(numExportedItems), 0
@@ -22,6 +22,7 @@ import (
 	"go.opencensus.io/tag"
 
 	"go.opentelemetry.io/collector/config/configtelemetry"
+	"go.opentelemetry.io/collector/internal/recenterrors"
 )
 
 const (
@@ -118,16 +119,26 @@ var gProcessor = &Processor{level: configtelemetry.LevelNone}
 
 type Processor struct {
 	level    configtelemetry.Level
+	name     string
 	mutators []tag.Mutator
 }
 
 func NewProcessor(level configtelemetry.Level, processorName string) *Processor {
 	return &Processor{
 		level:    level,
+		name:     processorName,
 		mutators: []tag.Mutator{tag.Upsert(tagKeyProcessor, processorName, tag.WithTTL(tag.TTLNoPropagation))},
 	}
 }
 
+// recordEvent records the data refused or dropped by the processor in the recent errors,
+// rendered by the errorz zPage.
+func (por *Processor) recordEvent(typ string, numItems int) {
+	if numItems > 0 && por.name != "" {
+		recenterrors.Record(ProcessorKey, por.name, typ, numItems, nil)
+	}
+}
+
 // TracesAccepted reports that the trace data was accepted.
 func (por *Processor) TracesAccepted(ctx context.Context, numSpans int) {
 	if por.level != configtelemetry.LevelNone {
@@ -143,6 +154,7 @@ func (por *Processor) TracesAccepted(ctx context.Context, numSpans int) {
 
 // TracesRefused reports that the trace data was refused.
 func (por *Processor) TracesRefused(ctx context.Context, numSpans int) {
+	por.recordEvent(recenterrors.TypeRefused, numSpans)
 	if por.level != configtelemetry.LevelNone {
 		stats.RecordWithTags(
 			ctx,
@@ -156,6 +168,7 @@ func (por *Processor) TracesRefused(ctx context.Context, numSpans int) {
 
 // TracesDropped reports that the trace data was dropped.
 func (por *Processor) TracesDropped(ctx context.Context, numSpans int) {
+	por.recordEvent(recenterrors.TypeDropped, numSpans)
 	if por.level != configtelemetry.LevelNone {
 		stats.RecordWithTags(
 			ctx,
@@ -182,6 +195,7 @@ func (por *Processor) MetricsAccepted(ctx context.Context, numPoints int) {
 
 // MetricsRefused reports that the metrics were refused.
 func (por *Processor) MetricsRefused(ctx context.Context, numPoints int) {
+	por.recordEvent(recenterrors.TypeRefused, numPoints)
 	if por.level != configtelemetry.LevelNone {
 		stats.RecordWithTags(
 			ctx,
@@ -195,6 +209,7 @@ func (por *Processor) MetricsRefused(ctx context.Context, numPoints int) {
 
 // MetricsDropped reports that the metrics were dropped.
 func (por *Processor) MetricsDropped(ctx context.Context, numPoints int) {
+	por.recordEvent(recenterrors.TypeDropped, numPoints)
 	if por.level != configtelemetry.LevelNone {
 		stats.RecordWithTags(
 			ctx,
@@ -221,6 +236,7 @@ func (por *Processor) LogsAccepted(ctx context.Context, numRecords int) {
 
 // LogsRefused reports that the logs were refused.
 func (por *Processor) LogsRefused(ctx context.Context, numRecords int) {
+	por.recordEvent(recenterrors.TypeRefused, numRecords)
 	if por.level != configtelemetry.LevelNone {
 		stats.RecordWithTags(
 			ctx,
@@ -234,6 +250,7 @@ func (por *Processor) LogsRefused(ctx context.Context, numRecords int) {
 
 // LogsDropped reports that the logs were dropped.
 func (por *Processor) LogsDropped(ctx context.Context, numRecords int) {
+	por.recordEvent(recenterrors.TypeDropped, numRecords)
 	if por.level != configtelemetry.LevelNone {
 		stats.RecordWithTags(
 			ctx,
@@ -23,6 +23,7 @@ import (
 
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/config/configtelemetry"
+	"go.opentelemetry.io/collector/internal/recenterrors"
 )
 
 const (
@@ -311,6 +312,9 @@ func endReceiveOp(
 	if err != nil {
 		numAccepted = 0
 		numRefused = numReceivedItems
+		if name, ok := tag.FromContext(receiverCtx).Value(tagKeyReceiver); ok && numRefused > 0 {
+			recenterrors.Record(ReceiverKey, name, recenterrors.TypeRefused, numRefused, err)
+		}
 	}
 
 	span := trace.FromContext(receiverCtx)
@@ -29,6 +29,8 @@ import (
 	"go.opencensus.io/trace"
 
 	"go.opentelemetry.io/collector/config/configtelemetry"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/internal/recenterrors"
 	"go.opentelemetry.io/collector/obsreport"
 	"go.opentelemetry.io/collector/obsreport/obsreporttest"
 	"go.opentelemetry.io/collector/receiver/scrapererror"
@@ -373,6 +375,32 @@ func TestExportTraceDataOp(t *testing.T) {
 	obsreporttest.CheckExporterTracesViews(t, exporter, int64(sentSpans), int64(failedToSendSpans))
 }
 
+func TestExportTraceDataOpRejected(t *testing.T) {
+	doneFn, err := obsreporttest.SetupRecordedMetricsTest()
+	require.NoError(t, err)
+	defer doneFn()
+
+	ss := &spanStore{}
+	trace.RegisterExporter(ss)
+	defer trace.UnregisterExporter(ss)
+
+	parentCtx, parentSpan := trace.StartSpan(context.Background(),
+		t.Name(), trace.WithSampler(trace.AlwaysSample()))
+	defer parentSpan.End()
+
+	exporterCtx := obsreport.ExporterContext(parentCtx, exporter)
+	obsrep := obsreport.NewExporter(configtelemetry.LevelNormal, exporter)
+	ctx := obsrep.StartTracesExportOp(exporterCtx)
+	obsrep.EndTracesExportOp(ctx, 14, consumererror.Permanent(consumererror.NewRejectedError(errFake, 4)))
+
+	spans := ss.PullAllSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, int64(10), spans[0].Attributes[obsreport.SentSpansKey])
+	assert.Equal(t, int64(4), spans[0].Attributes[obsreport.FailedToSendSpansKey])
+
+	obsreporttest.CheckExporterTracesViews(t, exporter, 10, 4)
+}
+
 func TestExportMetricsOp(t *testing.T) {
 	doneFn, err := obsreporttest.SetupRecordedMetricsTest()
 	require.NoError(t, err)
@@ -665,3 +693,33 @@ func (ss *spanStore) PullAllSpans() []*trace.SpanData {
 	ss.Unlock()
 	return capturedSpans
 }
+
+func TestRecentErrors(t *testing.T) {
+	recenterrors.Reset()
+	defer recenterrors.Reset()
+
+	receiverCtx := obsreport.ReceiverContext(context.Background(), receiver, transport)
+	ctx := obsreport.StartTraceDataReceiveOp(receiverCtx, receiver, transport)
+	obsreport.EndTraceDataReceiveOp(ctx, format, 3, errFake)
+	ctx = obsreport.StartTraceDataReceiveOp(receiverCtx, receiver, transport)
+	obsreport.EndTraceDataReceiveOp(ctx, format, 4, nil)
+
+	por := obsreport.NewProcessor(configtelemetry.LevelNone, processor)
+	por.MetricsDropped(context.Background(), 5)
+	por.MetricsAccepted(context.Background(), 6)
+
+	eor := obsreport.NewExporter(configtelemetry.LevelNone, exporter)
+	ctx = eor.StartLogsExportOp(obsreport.ExporterContext(context.Background(), exporter))
+	eor.EndLogsExportOp(ctx, 7, errFake)
+
+	events := recenterrors.Events()
+	require.Len(t, events, 3)
+	assert.Equal(t, [3]string{obsreport.ExporterKey, exporter, recenterrors.TypeSendFailed}, [3]string{events[0].Kind, events[0].Name, events[0].Type})
+	assert.Equal(t, int64(7), events[0].Items)
+	assert.Equal(t, errFake.Error(), events[0].LastError)
+	assert.Equal(t, [3]string{obsreport.ProcessorKey, processor, recenterrors.TypeDropped}, [3]string{events[1].Kind, events[1].Name, events[1].Type})
+	assert.Equal(t, int64(5), events[1].Items)
+	assert.Equal(t, [3]string{obsreport.ReceiverKey, receiver, recenterrors.TypeRefused}, [3]string{events[2].Kind, events[2].Name, events[2].Type})
+	assert.Equal(t, int64(3), events[2].Items)
+	assert.Equal(t, errFake.Error(), events[2].LastError)
+}
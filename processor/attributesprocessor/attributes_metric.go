@@ -0,0 +1,150 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package attributesprocessor
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal/processor/filterdatapoint"
+	"go.opentelemetry.io/collector/processor/processorhelper"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+)
+
+type metricAttributesProcessor struct {
+	attrProc *processorhelper.AttrProc
+	include  filterdatapoint.Matcher
+	exclude  filterdatapoint.Matcher
+}
+
+// newMetricAttributesProcessor returns a processor that modifies the labels of
+// metric data points. To construct the attributes processors, the use of the
+// factory methods are required in order to validate the inputs.
+func newMetricAttributesProcessor(attrProc *processorhelper.AttrProc, include, exclude filterdatapoint.Matcher) *metricAttributesProcessor {
+	return &metricAttributesProcessor{
+		attrProc: attrProc,
+		include:  include,
+		exclude:  exclude,
+	}
+}
+
+// ProcessMetrics implements the MetricsProcessor
+func (a *metricAttributesProcessor) ProcessMetrics(_ context.Context, md pdata.Metrics) (pdata.Metrics, error) {
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		ilms := rm.InstrumentationLibraryMetrics()
+		resource := rm.Resource()
+		for j := 0; j < ilms.Len(); j++ {
+			ilm := ilms.At(j)
+			metrics := ilm.Metrics()
+			library := ilm.InstrumentationLibrary()
+			for k := 0; k < metrics.Len(); k++ {
+				metric := metrics.At(k)
+				forEachDataPointLabels(metric, func(labels pdata.StringMap) {
+					a.processLabels(metric.Name(), labels, resource, library)
+				})
+			}
+		}
+	}
+	return md, nil
+}
+
+// processLabels applies the actions to the labels of a data point. Labels are
+// converted to attributes for matching and processing, and the resulting
+// attributes are converted back to labels, non-string values are stringified.
+func (a *metricAttributesProcessor) processLabels(metricName string, labels pdata.StringMap, resource pdata.Resource, library pdata.InstrumentationLibrary) {
+	attrs := pdata.NewAttributeMap()
+	attrs.InitEmptyWithCapacity(labels.Len())
+	labels.ForEach(func(k string, v string) {
+		attrs.InsertString(k, v)
+	})
+
+	if a.skipDataPoint(metricName, attrs, resource, library) {
+		return
+	}
+
+	a.attrProc.Process(attrs)
+
+	labels.InitEmptyWithCapacity(attrs.Len())
+	attrs.ForEach(func(k string, v pdata.AttributeValue) {
+		labels.Insert(k, tracetranslator.AttributeValueToString(v, false))
+	})
+}
+
+// skipDataPoint determines if a data point should be processed.
+// True is returned when a data point should be skipped.
+// False is returned when a data point should not be skipped.
+// The logic determining if a data point should be processed is set
+// in the attribute configuration with the include and exclude settings.
+// Include properties are checked before exclude settings are checked.
+func (a *metricAttributesProcessor) skipDataPoint(metricName string, attrs pdata.AttributeMap, resource pdata.Resource, library pdata.InstrumentationLibrary) bool {
+	if a.include != nil {
+		// A false returned in this case means the data point should not be processed.
+		if include := a.include.MatchDataPoint(metricName, attrs, resource, library); !include {
+			return true
+		}
+	}
+
+	if a.exclude != nil {
+		// A true returned in this case means the data point should not be processed.
+		if exclude := a.exclude.MatchDataPoint(metricName, attrs, resource, library); exclude {
+			return true
+		}
+	}
+
+	return false
+}
+
+// forEachDataPointLabels calls f with the labels of every data point of the metric.
+func forEachDataPointLabels(metric pdata.Metric, f func(labels pdata.StringMap)) {
+	switch metric.DataType() {
+	case pdata.MetricDataTypeIntGauge:
+		dps := metric.IntGauge().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			f(dps.At(i).LabelsMap())
+		}
+	case pdata.MetricDataTypeDoubleGauge:
+		dps := metric.DoubleGauge().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			f(dps.At(i).LabelsMap())
+		}
+	case pdata.MetricDataTypeIntSum:
+		dps := metric.IntSum().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			f(dps.At(i).LabelsMap())
+		}
+	case pdata.MetricDataTypeDoubleSum:
+		dps := metric.DoubleSum().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			f(dps.At(i).LabelsMap())
+		}
+	case pdata.MetricDataTypeIntHistogram:
+		dps := metric.IntHistogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			f(dps.At(i).LabelsMap())
+		}
+	case pdata.MetricDataTypeDoubleHistogram:
+		dps := metric.DoubleHistogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			f(dps.At(i).LabelsMap())
+		}
+	case pdata.MetricDataTypeDoubleSummary:
+		dps := metric.DoubleSummary().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			f(dps.At(i).LabelsMap())
+		}
+	}
+}
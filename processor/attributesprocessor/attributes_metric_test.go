@@ -0,0 +1,317 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package attributesprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal/processor/filterconfig"
+	"go.opentelemetry.io/collector/internal/processor/filterset"
+	"go.opentelemetry.io/collector/internal/testdata"
+	"go.opentelemetry.io/collector/processor/processorhelper"
+)
+
+// Common structure for all the Tests
+type metricTestCase struct {
+	name           string
+	inputLabels    map[string]string
+	expectedLabels map[string]string
+}
+
+// runIndividualMetricTestCase is the common logic of passing metric data through a configured attributes processor.
+func runIndividualMetricTestCase(t *testing.T, tt metricTestCase, mp component.MetricsProcessor) {
+	t.Run(tt.name, func(t *testing.T) {
+		md := generateMetricData(tt.name, tt.inputLabels)
+		assert.NoError(t, mp.ConsumeMetrics(context.Background(), md))
+		// Ensure that the modified `md` has the labels sorted:
+		sortMetricLabels(md)
+		require.Equal(t, generateMetricData(tt.name, tt.expectedLabels), md)
+	})
+}
+
+// generateMetricData generates a metric of each data type with one data point
+// with the given labels.
+func generateMetricData(metricName string, labels map[string]string) pdata.Metrics {
+	md := pdata.NewMetrics()
+	md.ResourceMetrics().Resize(1)
+	rm := md.ResourceMetrics().At(0)
+	rm.InstrumentationLibraryMetrics().Resize(1)
+	ms := rm.InstrumentationLibraryMetrics().At(0).Metrics()
+	dataTypes := []pdata.MetricDataType{
+		pdata.MetricDataTypeIntGauge,
+		pdata.MetricDataTypeDoubleGauge,
+		pdata.MetricDataTypeIntSum,
+		pdata.MetricDataTypeDoubleSum,
+		pdata.MetricDataTypeIntHistogram,
+		pdata.MetricDataTypeDoubleHistogram,
+		pdata.MetricDataTypeDoubleSummary,
+	}
+	ms.Resize(len(dataTypes))
+	for i, dataType := range dataTypes {
+		m := ms.At(i)
+		m.SetName(metricName)
+		m.SetDataType(dataType)
+		switch dataType {
+		case pdata.MetricDataTypeIntGauge:
+			m.IntGauge().DataPoints().Resize(1)
+			m.IntGauge().DataPoints().At(0).LabelsMap().InitFromMap(labels).Sort()
+		case pdata.MetricDataTypeDoubleGauge:
+			m.DoubleGauge().DataPoints().Resize(1)
+			m.DoubleGauge().DataPoints().At(0).LabelsMap().InitFromMap(labels).Sort()
+		case pdata.MetricDataTypeIntSum:
+			m.IntSum().DataPoints().Resize(1)
+			m.IntSum().DataPoints().At(0).LabelsMap().InitFromMap(labels).Sort()
+		case pdata.MetricDataTypeDoubleSum:
+			m.DoubleSum().DataPoints().Resize(1)
+			m.DoubleSum().DataPoints().At(0).LabelsMap().InitFromMap(labels).Sort()
+		case pdata.MetricDataTypeIntHistogram:
+			m.IntHistogram().DataPoints().Resize(1)
+			m.IntHistogram().DataPoints().At(0).LabelsMap().InitFromMap(labels).Sort()
+		case pdata.MetricDataTypeDoubleHistogram:
+			m.DoubleHistogram().DataPoints().Resize(1)
+			m.DoubleHistogram().DataPoints().At(0).LabelsMap().InitFromMap(labels).Sort()
+		case pdata.MetricDataTypeDoubleSummary:
+			m.DoubleSummary().DataPoints().Resize(1)
+			m.DoubleSummary().DataPoints().At(0).LabelsMap().InitFromMap(labels).Sort()
+		}
+	}
+	return md
+}
+
+func sortMetricLabels(md pdata.Metrics) {
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		ilms := rms.At(i).InstrumentationLibraryMetrics()
+		for j := 0; j < ilms.Len(); j++ {
+			ms := ilms.At(j).Metrics()
+			for k := 0; k < ms.Len(); k++ {
+				forEachDataPointLabels(ms.At(k), func(labels pdata.StringMap) {
+					labels.Sort()
+				})
+			}
+		}
+	}
+}
+
+func TestMetricProcessor_NilEmptyData(t *testing.T) {
+	type nilEmptyTestCase struct {
+		name   string
+		input  pdata.Metrics
+		output pdata.Metrics
+	}
+	testCases := []nilEmptyTestCase{
+		{
+			name:   "empty",
+			input:  testdata.GenerateMetricsEmpty(),
+			output: testdata.GenerateMetricsEmpty(),
+		},
+		{
+			name:   "one-empty-resource-metrics",
+			input:  testdata.GenerateMetricsOneEmptyResourceMetrics(),
+			output: testdata.GenerateMetricsOneEmptyResourceMetrics(),
+		},
+		{
+			name:   "no-libraries",
+			input:  testdata.GenerateMetricsNoLibraries(),
+			output: testdata.GenerateMetricsNoLibraries(),
+		},
+		{
+			name:   "one-empty-instrumentation-library",
+			input:  testdata.GenerateMetricsOneEmptyInstrumentationLibrary(),
+			output: testdata.GenerateMetricsOneEmptyInstrumentationLibrary(),
+		},
+	}
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+	oCfg := cfg.(*Config)
+	oCfg.Settings.Actions = []processorhelper.ActionKeyValue{
+		{Key: "attribute1", Action: processorhelper.INSERT, Value: 123},
+		{Key: "attribute1", Action: processorhelper.DELETE},
+	}
+
+	mp, err := factory.CreateMetricsProcessor(
+		context.Background(), component.ProcessorCreateParams{Logger: zap.NewNop()}, oCfg, consumertest.NewMetricsNop())
+	require.Nil(t, err)
+	require.NotNil(t, mp)
+	for i := range testCases {
+		tt := testCases[i]
+		t.Run(tt.name, func(t *testing.T) {
+			assert.NoError(t, mp.ConsumeMetrics(context.Background(), tt.input))
+			assert.EqualValues(t, tt.output, tt.input)
+		})
+	}
+}
+
+func TestMetricAttributes_Actions(t *testing.T) {
+	testCases := []metricTestCase{
+		{
+			name:        "insert non-string value",
+			inputLabels: map[string]string{},
+			expectedLabels: map[string]string{
+				"attribute1": "123",
+			},
+		},
+		{
+			name: "update, delete and extract",
+			inputLabels: map[string]string{
+				"db.secret": "password",
+				"drop":      "me",
+				"http.url":  "http://example.com/path",
+			},
+			expectedLabels: map[string]string{
+				"attribute1":    "123",
+				"db.secret":     "redacted",
+				"http.url":      "http://example.com/path",
+				"http_protocol": "http",
+			},
+		},
+	}
+
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+	oCfg := cfg.(*Config)
+	oCfg.Actions = []processorhelper.ActionKeyValue{
+		{Key: "attribute1", Action: processorhelper.INSERT, Value: 123},
+		{Key: "db.secret", Action: processorhelper.UPDATE, Value: "redacted"},
+		{Key: "drop", Action: processorhelper.DELETE},
+		{Key: "http.url", Action: processorhelper.EXTRACT, RegexPattern: "^(?P<http_protocol>.*)://"},
+	}
+
+	mp, err := factory.CreateMetricsProcessor(context.Background(), component.ProcessorCreateParams{}, cfg, consumertest.NewMetricsNop())
+	require.Nil(t, err)
+	require.NotNil(t, mp)
+
+	for _, tt := range testCases {
+		runIndividualMetricTestCase(t, tt, mp)
+	}
+}
+
+func TestMetricAttributes_FilterByMetricNameAndLabels(t *testing.T) {
+	testCases := []metricTestCase{
+		{
+			name:        "apply",
+			inputLabels: map[string]string{},
+			expectedLabels: map[string]string{
+				"attribute1": "123",
+			},
+		},
+		{
+			name: "apply",
+			inputLabels: map[string]string{
+				"NoModification": "false",
+			},
+			expectedLabels: map[string]string{
+				"NoModification": "false",
+				"attribute1":     "123",
+			},
+		},
+		{
+			name: "apply",
+			inputLabels: map[string]string{
+				"NoModification": "true",
+			},
+			expectedLabels: map[string]string{
+				"NoModification": "true",
+			},
+		},
+		{
+			name:           "dont_apply",
+			inputLabels:    map[string]string{},
+			expectedLabels: map[string]string{},
+		},
+		{
+			name:           "incorrect_metric_name",
+			inputLabels:    map[string]string{},
+			expectedLabels: map[string]string{},
+		},
+		{
+			name:           "apply_dont_apply",
+			inputLabels:    map[string]string{},
+			expectedLabels: map[string]string{},
+		},
+	}
+
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+	oCfg := cfg.(*Config)
+	oCfg.Actions = []processorhelper.ActionKeyValue{
+		{Key: "attribute1", Action: processorhelper.INSERT, Value: 123},
+	}
+	oCfg.Include = &filterconfig.MatchProperties{
+		Config:      *createConfig(filterset.Regexp),
+		MetricNames: []string{"^apply.*"},
+	}
+	oCfg.Exclude = &filterconfig.MatchProperties{
+		Config:      *createConfig(filterset.Strict),
+		MetricNames: []string{"apply_dont_apply"},
+	}
+	mp, err := factory.CreateMetricsProcessor(context.Background(), component.ProcessorCreateParams{}, cfg, consumertest.NewMetricsNop())
+	require.Nil(t, err)
+	require.NotNil(t, mp)
+
+	for _, tt := range testCases[3:] {
+		runIndividualMetricTestCase(t, tt, mp)
+	}
+
+	oCfg.Include = nil
+	oCfg.Exclude = &filterconfig.MatchProperties{
+		Config:     *createConfig(filterset.Strict),
+		Attributes: []filterconfig.Attribute{{Key: "NoModification", Value: "true"}},
+	}
+	mp, err = factory.CreateMetricsProcessor(context.Background(), component.ProcessorCreateParams{}, cfg, consumertest.NewMetricsNop())
+	require.Nil(t, err)
+	require.NotNil(t, mp)
+
+	for _, tt := range testCases[:3] {
+		runIndividualMetricTestCase(t, tt, mp)
+	}
+}
+
+func TestMetricAttributes_Hash(t *testing.T) {
+	testCases := []metricTestCase{
+		{
+			name: "String",
+			inputLabels: map[string]string{
+				"user.email": "john.doe@example.com",
+			},
+			expectedLabels: map[string]string{
+				"user.email": "73ec53c4ba1747d485ae2a0d7bfafa6cda80a5a9",
+			},
+		},
+	}
+
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+	oCfg := cfg.(*Config)
+	oCfg.Actions = []processorhelper.ActionKeyValue{
+		{Key: "user.email", Action: processorhelper.HASH},
+	}
+
+	mp, err := factory.CreateMetricsProcessor(context.Background(), component.ProcessorCreateParams{}, cfg, consumertest.NewMetricsNop())
+	require.Nil(t, err)
+	require.NotNil(t, mp)
+
+	for _, tt := range testCases {
+		runIndividualMetricTestCase(t, tt, mp)
+	}
+}
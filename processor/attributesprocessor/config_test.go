@@ -17,6 +17,7 @@ package attributesprocessor
 import (
 	"path"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -234,4 +235,49 @@ func TestLoadingConfig(t *testing.T) {
 		},
 	})
 
+	p11 := cfg.Processors["attributes/metrics"]
+	assert.Equal(t, p11, &Config{
+		ProcessorSettings: configmodels.ProcessorSettings{
+			NameVal: "attributes/metrics",
+			TypeVal: typeStr,
+		},
+		MatchConfig: filterconfig.MatchConfig{
+			Include: &filterconfig.MatchProperties{
+				Config:      *createConfig(filterset.Regexp),
+				MetricNames: []string{`system\..*`},
+			},
+		},
+		Settings: processorhelper.Settings{
+			Actions: []processorhelper.ActionKeyValue{
+				{Key: "process.pid", Action: processorhelper.DELETE},
+			},
+		},
+	})
+
+	p12 := cfg.Processors["attributes/convert"]
+	assert.Equal(t, p12, &Config{
+		ProcessorSettings: configmodels.ProcessorSettings{
+			NameVal: "attributes/convert",
+			TypeVal: typeStr,
+		},
+		Settings: processorhelper.Settings{
+			Actions: []processorhelper.ActionKeyValue{
+				{Key: "http.status_code", Action: processorhelper.CONVERT, ConvertedType: "int"},
+			},
+		},
+	})
+
+	p13 := cfg.Processors["attributes/map"]
+	assert.Equal(t, p13, &Config{
+		ProcessorSettings: configmodels.ProcessorSettings{
+			NameVal: "attributes/map",
+			TypeVal: typeStr,
+		},
+		Settings: processorhelper.Settings{
+			Actions: []processorhelper.ActionKeyValue{
+				{Key: "account.id", Action: processorhelper.MAP, MappingFile: "/etc/otel/teams.csv", ReloadInterval: time.Minute},
+			},
+		},
+	})
+
 }
@@ -19,13 +19,13 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
-	"github.com/stretchr/testify/require"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configcheck"
-	"go.opentelemetry.io/collector/config/configerror"
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/internal/processor/filterconfig"
+	"go.opentelemetry.io/collector/internal/processor/filterset"
 	"go.opentelemetry.io/collector/processor/processorhelper"
 )
 
@@ -91,13 +91,49 @@ func TestFactoryCreateTraceProcessor(t *testing.T) {
 	assert.Error(t, err)
 }
 
-func TestFactory_CreateMetricsProcessor(t *testing.T) {
+func TestFactoryCreateMetricsProcessor_EmptyActions(t *testing.T) {
 	factory := NewFactory()
 	cfg := factory.CreateDefaultConfig()
+	mp, err := factory.CreateMetricsProcessor(context.Background(), component.ProcessorCreateParams{}, cfg, consumertest.NewMetricsNop())
+	assert.Error(t, err)
+	assert.Nil(t, mp)
+}
+
+func TestFactoryCreateMetricsProcessor(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+	oCfg := cfg.(*Config)
+	oCfg.Actions = []processorhelper.ActionKeyValue{
+		{Key: "a key", Action: processorhelper.DELETE},
+	}
+
+	mp, err := factory.CreateMetricsProcessor(
+		context.Background(), component.ProcessorCreateParams{}, cfg, consumertest.NewMetricsNop())
+	assert.NotNil(t, mp)
+	assert.NoError(t, err)
 
-	mp, err := factory.CreateMetricsProcessor(context.Background(), component.ProcessorCreateParams{}, cfg, nil)
-	require.Nil(t, mp)
-	assert.Equal(t, err, configerror.ErrDataTypeIsNotSupported)
+	mp, err = factory.CreateMetricsProcessor(
+		context.Background(), component.ProcessorCreateParams{}, cfg, nil)
+	assert.Nil(t, mp)
+	assert.Error(t, err)
+
+	oCfg.Include = &filterconfig.MatchProperties{
+		Config:   filterset.Config{MatchType: filterset.Strict},
+		LogNames: []string{"log"},
+	}
+	mp, err = factory.CreateMetricsProcessor(
+		context.Background(), component.ProcessorCreateParams{}, cfg, consumertest.NewMetricsNop())
+	assert.Nil(t, mp)
+	assert.Error(t, err)
+
+	oCfg.Include = nil
+	oCfg.Actions = []processorhelper.ActionKeyValue{
+		{Action: processorhelper.DELETE},
+	}
+	mp, err = factory.CreateMetricsProcessor(
+		context.Background(), component.ProcessorCreateParams{}, cfg, consumertest.NewMetricsNop())
+	assert.Nil(t, mp)
+	assert.Error(t, err)
 }
 
 func TestFactoryCreateLogsProcessor_EmptyActions(t *testing.T) {
@@ -17,15 +17,18 @@ package batchprocessor
 import (
 	"context"
 	"runtime"
+	"sync"
 	"time"
 
 	"go.opencensus.io/stats"
 	"go.opencensus.io/tag"
+	"go.uber.org/atomic"
 	"go.uber.org/zap"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configtelemetry"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/processor"
 )
@@ -37,23 +40,49 @@ import (
 //
 // Batches are sent out with any of the following conditions:
 // - batch size reaches cfg.SendBatchSize
+// - adding an item would make the batch exceed cfg.SendBatchMaxBytes
 // - cfg.Timeout is elapsed since the timestamp when the previous batch was sent out.
+//
+// When cfg.PartitionKeys is set, a separate batch is built for every
+// combination of the values of these resource attributes.
 type batchProcessor struct {
 	name           string
 	logger         *zap.Logger
 	telemetryLevel configtelemetry.Level
 
-	sendBatchSize    uint32
-	timeout          time.Duration
-	sendBatchMaxSize uint32
+	sendBatchSize     uint32
+	timeout           time.Duration
+	sendBatchMaxSize  uint32
+	sendBatchMaxBytes int
 
 	timer   *time.Timer
 	done    chan struct{}
 	newItem chan interface{}
-	batch   batch
+
+	partitionKeys []string
+	newBatch      func() batch
+	partitions    map[string]*partition
 
 	ctx    context.Context
 	cancel context.CancelFunc
+
+	// shutdownCtx is the context given to Shutdown, it is set before ctx is
+	// cancelled.
+	shutdownCtx context.Context
+	// drainCtx is set while the pending items are sent at shutdown.
+	drainCtx context.Context
+	// drainFlushed and drainDropped count the items sent and dropped while
+	// draining, they are read by DrainStats.
+	drainFlushed atomic.Int64
+	drainDropped atomic.Int64
+
+	// backpressure is set when the pipeline propagates backpressure, the new
+	// items are then refused during a timeout after an export was refused with
+	// consumererror.Backpressure.
+	backpressure      bool
+	backpressureMu    sync.Mutex
+	backpressureErr   error
+	backpressureUntil time.Time
 }
 
 type batch interface {
@@ -73,25 +102,39 @@ type batch interface {
 	add(item interface{})
 }
 
+// partition holds the batch of the data sharing the same values of the
+// partition keys.
+type partition struct {
+	batch batch
+	// bytes is the estimated encoded size of the batch, only tracked when
+	// sendBatchMaxBytes is set.
+	bytes int
+}
+
 var _ consumer.TracesConsumer = (*batchProcessor)(nil)
 var _ consumer.MetricsConsumer = (*batchProcessor)(nil)
 var _ consumer.LogsConsumer = (*batchProcessor)(nil)
+var _ component.Drainer = (*batchProcessor)(nil)
 
-func newBatchProcessor(params component.ProcessorCreateParams, cfg *Config, batch batch, telemetryLevel configtelemetry.Level) *batchProcessor {
+func newBatchProcessor(params component.ProcessorCreateParams, cfg *Config, newBatch func() batch, telemetryLevel configtelemetry.Level) *batchProcessor {
 	ctx, cancel := context.WithCancel(context.Background())
 	return &batchProcessor{
 		name:           cfg.Name(),
 		logger:         params.Logger,
 		telemetryLevel: telemetryLevel,
 
-		sendBatchSize:    cfg.SendBatchSize,
-		sendBatchMaxSize: cfg.SendBatchMaxSize,
-		timeout:          cfg.Timeout,
-		done:             make(chan struct{}, 1),
-		newItem:          make(chan interface{}, runtime.NumCPU()),
-		batch:            batch,
-		ctx:              ctx,
-		cancel:           cancel,
+		sendBatchSize:     cfg.SendBatchSize,
+		sendBatchMaxSize:  cfg.SendBatchMaxSize,
+		sendBatchMaxBytes: int(cfg.SendBatchMaxBytes),
+		timeout:           cfg.Timeout,
+		done:              make(chan struct{}, 1),
+		newItem:           make(chan interface{}, runtime.NumCPU()),
+		partitionKeys:     cfg.PartitionKeys,
+		newBatch:          newBatch,
+		partitions:        map[string]*partition{},
+		ctx:               ctx,
+		cancel:            cancel,
+		backpressure:      params.Backpressure,
 	}
 }
 
@@ -106,12 +149,27 @@ func (bp *batchProcessor) Start(context.Context, component.Host) error {
 }
 
 // Shutdown is invoked during service shutdown.
-func (bp *batchProcessor) Shutdown(context.Context) error {
+func (bp *batchProcessor) Shutdown(ctx context.Context) error {
+	bp.shutdownCtx = ctx
 	bp.cancel()
 
-	// Wait until current batch is drained.
-	<-bp.done
-	return nil
+	// Wait until current batch is drained, the items which cannot be sent
+	// before ctx is done are dropped.
+	select {
+	case <-bp.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// DrainStats implements component.Drainer, the items being sent or still
+// waiting to be batched when the context of Shutdown is done are not counted.
+func (bp *batchProcessor) DrainStats() component.DrainStats {
+	return component.DrainStats{
+		Flushed: bp.drainFlushed.Load(),
+		Dropped: bp.drainDropped.Load(),
+	}
 }
 
 func (bp *batchProcessor) startProcessingCycle() {
@@ -119,21 +177,7 @@ func (bp *batchProcessor) startProcessingCycle() {
 	for {
 		select {
 		case <-bp.ctx.Done():
-		DONE:
-			for {
-				select {
-				case item := <-bp.newItem:
-					bp.processItem(item)
-				default:
-					break DONE
-				}
-			}
-			// This is the close of the channel
-			if bp.batch.itemCount() > 0 {
-				// TODO: Set a timeout on sendTraces or
-				// make it cancellable using the context that Shutdown gets as a parameter
-				bp.sendItems(statTimeoutTriggerSend)
-			}
+			bp.drain(bp.shutdownCtx)
 			// Indicate that we finished draining.
 			close(bp.done)
 			return
@@ -143,43 +187,142 @@ func (bp *batchProcessor) startProcessingCycle() {
 			}
 			bp.processItem(item)
 		case <-bp.timer.C:
-			if bp.batch.itemCount() > 0 {
-				bp.sendItems(statTimeoutTriggerSend)
-			}
+			bp.sendAll()
 			bp.resetTimer()
 		}
 	}
 }
 
-func (bp *batchProcessor) processItem(item interface{}) {
-	if bp.sendBatchMaxSize > 0 {
-		if td, ok := item.(pdata.Traces); ok {
-			itemCount := bp.batch.itemCount()
-			if itemCount+uint32(td.SpanCount()) > bp.sendBatchMaxSize {
-				tdRemainSize := splitTrace(int(bp.sendBatchSize-itemCount), td)
-				item = tdRemainSize
-				go func() {
-					bp.newItem <- td
-				}()
+// drain sends all the pending items using ctx, and records how many of them
+// were flushed or dropped.
+func (bp *batchProcessor) drain(ctx context.Context) {
+	bp.timer.Stop()
+	bp.drainCtx = ctx
+DONE:
+	for {
+		select {
+		case item := <-bp.newItem:
+			if item != nil {
+				bp.processItem(item)
 			}
+		default:
+			break DONE
 		}
-		if td, ok := item.(pdata.Metrics); ok {
-			itemCount := bp.batch.itemCount()
-			if itemCount+uint32(td.MetricCount()) > bp.sendBatchMaxSize {
-				tdRemainSize := splitMetrics(int(bp.sendBatchSize-itemCount), td)
-				item = tdRemainSize
-				go func() {
-					bp.newItem <- td
-				}()
-			}
+	}
+	bp.sendAll()
+
+	flushed, dropped := bp.drainFlushed.Load(), bp.drainDropped.Load()
+	statsTags := []tag.Mutator{tag.Insert(processor.TagProcessorNameKey, bp.name)}
+	_ = stats.RecordWithTags(context.Background(), statsTags,
+		statShutdownFlushedItems.M(flushed),
+		statShutdownDroppedItems.M(dropped))
+	if dropped > 0 {
+		bp.logger.Warn("Dropped items while draining at shutdown",
+			zap.Int64("flushed", flushed),
+			zap.Int64("dropped", dropped))
+	}
+}
+
+func (bp *batchProcessor) processItem(item interface{}) {
+	if len(bp.partitionKeys) == 0 {
+		bp.processPartitionItem(bp.partition(""), item)
+		return
+	}
+	for _, pi := range splitByPartition(bp.partitionKeys, item) {
+		bp.processPartitionItem(bp.partition(pi.key), pi.item)
+	}
+}
+
+// partition returns the partition for the given key, creating it if needed.
+func (bp *batchProcessor) partition(key string) *partition {
+	p, ok := bp.partitions[key]
+	if !ok {
+		p = &partition{batch: bp.newBatch()}
+		bp.partitions[key] = p
+	}
+	return p
+}
+
+func (bp *batchProcessor) processPartitionItem(p *partition, item interface{}) {
+	// Fill and send the batch with parts of the item until the rest of the
+	// item fits.
+	for bp.sendBatchMaxSize > 0 && p.batch.itemCount()+uint32(itemCount(item)) > bp.sendBatchMaxSize {
+		limit := bp.sendBatchSize
+		if bp.sendBatchMaxSize < limit {
+			limit = bp.sendBatchMaxSize
 		}
+		if p.batch.itemCount() >= limit {
+			bp.sendBySize(p)
+			continue
+		}
+		bp.addItem(p, splitItem(int(limit-p.batch.itemCount()), item))
 	}
+	bp.addItem(p, item)
+}
 
-	bp.batch.add(item)
-	if bp.batch.itemCount() >= bp.sendBatchSize {
-		bp.timer.Stop()
-		bp.sendItems(statBatchSizeTriggerSend)
-		bp.resetTimer()
+func (bp *batchProcessor) addItem(p *partition, item interface{}) {
+	if bp.sendBatchMaxBytes > 0 {
+		bp.addWithinMaxBytes(p, item)
+	} else {
+		p.batch.add(item)
+	}
+	if p.batch.itemCount() >= bp.sendBatchSize {
+		bp.sendBySize(p)
+	}
+}
+
+// addWithinMaxBytes adds the item to the current batch, sending the batch
+// first if the item does not fit in it. Items larger than sendBatchMaxBytes
+// are split, unless they contain a single span, metric or log which is then
+// sent on its own.
+func (bp *batchProcessor) addWithinMaxBytes(p *partition, item interface{}) {
+	count := itemCount(item)
+	if count == 0 {
+		return
+	}
+	size := encodedSize(item)
+	if p.bytes+size > bp.sendBatchMaxBytes && p.batch.itemCount() > 0 {
+		bp.sendBySize(p)
+	}
+	if size <= bp.sendBatchMaxBytes || count == 1 {
+		p.batch.add(item)
+		p.bytes += size
+		return
+	}
+
+	// Split proportionally to the average item size, the remaining part is
+	// split again if needed.
+	splitSize := count * bp.sendBatchMaxBytes / size
+	if splitSize < 1 {
+		splitSize = 1
+	}
+	head := splitItem(splitSize, item)
+	bp.addWithinMaxBytes(p, head)
+	bp.addWithinMaxBytes(p, item)
+}
+
+// sendBySize sends the batch of the partition after it reached a size limit.
+func (bp *batchProcessor) sendBySize(p *partition) {
+	if len(bp.partitionKeys) > 0 {
+		// The timer is shared by all the partitions, resetting it here could
+		// indefinitely delay the batches of the less busy partitions.
+		bp.sendItems(p, statBatchSizeTriggerSend)
+		return
+	}
+	bp.timer.Stop()
+	bp.sendItems(p, statBatchSizeTriggerSend)
+	bp.resetTimer()
+}
+
+// sendAll sends the batches of all the partitions and forgets the
+// partitions, so that the ones which stopped receiving data do not stay
+// in memory.
+func (bp *batchProcessor) sendAll() {
+	for key, p := range bp.partitions {
+		if p.batch.itemCount() > 0 {
+			bp.sendItems(p, statTimeoutTriggerSend)
+		}
+		delete(bp.partitions, key)
 	}
 }
 
@@ -187,29 +330,86 @@ func (bp *batchProcessor) resetTimer() {
 	bp.timer.Reset(bp.timeout)
 }
 
-func (bp *batchProcessor) sendItems(measure *stats.Int64Measure) {
+func (bp *batchProcessor) sendItems(p *partition, measure *stats.Int64Measure) {
+	ctx := context.Background()
+	if bp.drainCtx != nil {
+		ctx = bp.drainCtx
+		if ctx.Err() != nil {
+			bp.drainDropped.Add(int64(p.batch.itemCount()))
+			p.batch.reset()
+			p.bytes = 0
+			return
+		}
+	}
+
 	// Add that it came form the trace pipeline?
 	statsTags := []tag.Mutator{tag.Insert(processor.TagProcessorNameKey, bp.name)}
-	_ = stats.RecordWithTags(context.Background(), statsTags, measure.M(1), statBatchSendSize.M(int64(bp.batch.itemCount())))
+	_ = stats.RecordWithTags(context.Background(), statsTags, measure.M(1), statBatchSendSize.M(int64(p.batch.itemCount())))
 
 	if bp.telemetryLevel == configtelemetry.LevelDetailed {
-		_ = stats.RecordWithTags(context.Background(), statsTags, statBatchSendSizeBytes.M(int64(bp.batch.size())))
+		_ = stats.RecordWithTags(context.Background(), statsTags, statBatchSendSizeBytes.M(int64(p.batch.size())))
 	}
 
-	if err := bp.batch.export(context.Background()); err != nil {
+	err := p.batch.export(ctx)
+	if err != nil {
 		bp.logger.Warn("Sender failed", zap.Error(err))
 	}
-	bp.batch.reset()
+	if bp.backpressure {
+		bp.trackBackpressure(err)
+	}
+	if bp.drainCtx != nil {
+		if err != nil {
+			bp.drainDropped.Add(int64(p.batch.itemCount()))
+		} else {
+			bp.drainFlushed.Add(int64(p.batch.itemCount()))
+		}
+	}
+	p.batch.reset()
+	p.bytes = 0
+}
+
+// trackBackpressure refuses the new items during a timeout when the export
+// was refused with consumererror.Backpressure, and accepts them again when an
+// export succeeds.
+func (bp *batchProcessor) trackBackpressure(err error) {
+	bp.backpressureMu.Lock()
+	defer bp.backpressureMu.Unlock()
+	if consumererror.IsBackpressure(err) {
+		bp.backpressureErr = err
+		bp.backpressureUntil = time.Now().Add(bp.timeout)
+	} else if err == nil {
+		bp.backpressureErr = nil
+	}
+}
+
+// checkBackpressure returns the error to refuse the new items with, or nil
+// when they are accepted.
+func (bp *batchProcessor) checkBackpressure() error {
+	if !bp.backpressure {
+		return nil
+	}
+	bp.backpressureMu.Lock()
+	defer bp.backpressureMu.Unlock()
+	if bp.backpressureErr == nil || time.Now().After(bp.backpressureUntil) {
+		return nil
+	}
+	return bp.backpressureErr
 }
 
 // ConsumeTraces implements TracesProcessor
 func (bp *batchProcessor) ConsumeTraces(_ context.Context, td pdata.Traces) error {
+	if err := bp.checkBackpressure(); err != nil {
+		return err
+	}
 	bp.newItem <- td
 	return nil
 }
 
 // ConsumeTraces implements MetricsProcessor
 func (bp *batchProcessor) ConsumeMetrics(_ context.Context, md pdata.Metrics) error {
+	if err := bp.checkBackpressure(); err != nil {
+		return err
+	}
 	// First thing is convert into a different internal format
 	bp.newItem <- md
 	return nil
@@ -217,23 +417,26 @@ func (bp *batchProcessor) ConsumeMetrics(_ context.Context, md pdata.Metrics) er
 
 // ConsumeLogs implements LogsProcessor
 func (bp *batchProcessor) ConsumeLogs(_ context.Context, ld pdata.Logs) error {
+	if err := bp.checkBackpressure(); err != nil {
+		return err
+	}
 	bp.newItem <- ld
 	return nil
 }
 
 // newBatchTracesProcessor creates a new batch processor that batches traces by size or with timeout
 func newBatchTracesProcessor(params component.ProcessorCreateParams, trace consumer.TracesConsumer, cfg *Config, telemetryLevel configtelemetry.Level) *batchProcessor {
-	return newBatchProcessor(params, cfg, newBatchTraces(trace), telemetryLevel)
+	return newBatchProcessor(params, cfg, func() batch { return newBatchTraces(trace) }, telemetryLevel)
 }
 
 // newBatchMetricsProcessor creates a new batch processor that batches metrics by size or with timeout
 func newBatchMetricsProcessor(params component.ProcessorCreateParams, metrics consumer.MetricsConsumer, cfg *Config, telemetryLevel configtelemetry.Level) *batchProcessor {
-	return newBatchProcessor(params, cfg, newBatchMetrics(metrics), telemetryLevel)
+	return newBatchProcessor(params, cfg, func() batch { return newBatchMetrics(metrics) }, telemetryLevel)
 }
 
 // newBatchLogsProcessor creates a new batch processor that batches logs by size or with timeout
 func newBatchLogsProcessor(params component.ProcessorCreateParams, logs consumer.LogsConsumer, cfg *Config, telemetryLevel configtelemetry.Level) *batchProcessor {
-	return newBatchProcessor(params, cfg, newBatchLogs(logs), telemetryLevel)
+	return newBatchProcessor(params, cfg, func() batch { return newBatchLogs(logs) }, telemetryLevel)
 }
 
 type batchTraces struct {
@@ -16,7 +16,9 @@ package batchprocessor
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"testing"
 	"time"
 
@@ -28,6 +30,7 @@ import (
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config/configtelemetry"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/internal/testdata"
@@ -115,6 +118,144 @@ func TestBatchProcessorSpansDeliveredEnforceBatchSize(t *testing.T) {
 	assert.Equal(t, (requestCount*spansPerRequest)%int(cfg.SendBatchSize), sink.AllTraces()[len(sink.AllTraces())-1].SpanCount())
 }
 
+func TestBatchProcessorSpansDeliveredEnforceBatchMaxBytes(t *testing.T) {
+	sink := new(consumertest.TracesSink)
+	cfg := createDefaultConfig().(*Config)
+	cfg.SendBatchMaxBytes = 16 * 1024
+	creationParams := component.ProcessorCreateParams{Logger: zap.NewNop()}
+	batcher := newBatchTracesProcessor(creationParams, sink, cfg, configtelemetry.LevelBasic)
+	require.NoError(t, batcher.Start(context.Background(), componenttest.NewNopHost()))
+
+	requestCount := 100
+	spansPerRequest := 150
+	for requestNum := 0; requestNum < requestCount; requestNum++ {
+		td := testdata.GenerateTraceDataManySpansSameResource(spansPerRequest)
+		spans := td.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans()
+		for spanIndex := 0; spanIndex < spansPerRequest; spanIndex++ {
+			spans.At(spanIndex).SetName(getTestSpanName(requestNum, spanIndex))
+		}
+		// Every request is larger than the limit and has to be split.
+		require.Greater(t, encodedSize(td), int(cfg.SendBatchMaxBytes))
+		assert.NoError(t, batcher.ConsumeTraces(context.Background(), td))
+	}
+
+	require.NoError(t, batcher.Shutdown(context.Background()))
+
+	require.Equal(t, requestCount*spansPerRequest, sink.SpansCount())
+	spansReceivedByName := spansReceivedByName(sink.AllTraces())
+	require.Equal(t, requestCount*spansPerRequest, len(spansReceivedByName))
+	receivedTraces := sink.AllTraces()
+	for i, td := range receivedTraces {
+		buf, err := td.ToOtlpProtoBytes()
+		require.NoError(t, err)
+		assert.LessOrEqual(t, len(buf), int(cfg.SendBatchMaxBytes))
+		if i < len(receivedTraces)-1 {
+			// Batches are cut close to the limit, the last batch has the remaining spans.
+			assert.Greater(t, len(buf), int(cfg.SendBatchMaxBytes)/2)
+		}
+	}
+}
+
+func TestBatchProcessorSpansBatchMaxBytesSingleSpan(t *testing.T) {
+	sink := new(consumertest.TracesSink)
+	cfg := createDefaultConfig().(*Config)
+	cfg.SendBatchMaxBytes = 16
+	creationParams := component.ProcessorCreateParams{Logger: zap.NewNop()}
+	batcher := newBatchTracesProcessor(creationParams, sink, cfg, configtelemetry.LevelBasic)
+	require.NoError(t, batcher.Start(context.Background(), componenttest.NewNopHost()))
+
+	// Spans larger than the limit are sent one per batch.
+	assert.NoError(t, batcher.ConsumeTraces(context.Background(), testdata.GenerateTraceDataManySpansSameResource(10)))
+	require.NoError(t, batcher.Shutdown(context.Background()))
+
+	require.Equal(t, 10, sink.SpansCount())
+	require.Equal(t, 10, len(sink.AllTraces()))
+}
+
+func TestBatchProcessorSpansDeliveredPartitioned(t *testing.T) {
+	sink := new(consumertest.TracesSink)
+	cfg := createDefaultConfig().(*Config)
+	cfg.SendBatchSize = 128
+	cfg.PartitionKeys = []string{"tenant"}
+	creationParams := component.ProcessorCreateParams{Logger: zap.NewNop()}
+	batcher := newBatchTracesProcessor(creationParams, sink, cfg, configtelemetry.LevelBasic)
+	require.NoError(t, batcher.Start(context.Background(), componenttest.NewNopHost()))
+
+	tenants := []string{"a", "b", "c"}
+	requestCount := 100
+	spansPerRequest := 10
+	for requestNum := 0; requestNum < requestCount; requestNum++ {
+		// Every request has one resource per tenant.
+		td := pdata.NewTraces()
+		for _, tenant := range tenants {
+			rss := testdata.GenerateTraceDataManySpansSameResource(spansPerRequest).ResourceSpans()
+			rss.At(0).Resource().Attributes().UpsertString("tenant", tenant)
+			rss.MoveAndAppendTo(td.ResourceSpans())
+		}
+		assert.NoError(t, batcher.ConsumeTraces(context.Background(), td))
+	}
+
+	require.NoError(t, batcher.Shutdown(context.Background()))
+
+	require.Equal(t, requestCount*spansPerRequest*len(tenants), sink.SpansCount())
+	spansByTenant := map[string]int{}
+	for _, td := range sink.AllTraces() {
+		rss := td.ResourceSpans()
+		require.Greater(t, rss.Len(), 0)
+		tenant, ok := rss.At(0).Resource().Attributes().Get("tenant")
+		require.True(t, ok)
+		for i := 1; i < rss.Len(); i++ {
+			other, ok := rss.At(i).Resource().Attributes().Get("tenant")
+			require.True(t, ok)
+			require.Equal(t, tenant.StringVal(), other.StringVal())
+		}
+		spansByTenant[tenant.StringVal()] += td.SpanCount()
+	}
+	for _, tenant := range tenants {
+		assert.Equal(t, requestCount*spansPerRequest, spansByTenant[tenant])
+	}
+}
+
+func TestBatchProcessorPartitionSentByTimeout(t *testing.T) {
+	sink := new(consumertest.TracesSink)
+	cfg := createDefaultConfig().(*Config)
+	cfg.SendBatchSize = 10
+	cfg.Timeout = 100 * time.Millisecond
+	cfg.PartitionKeys = []string{"tenant"}
+	creationParams := component.ProcessorCreateParams{Logger: zap.NewNop()}
+	batcher := newBatchTracesProcessor(creationParams, sink, cfg, configtelemetry.LevelBasic)
+	require.NoError(t, batcher.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() {
+		assert.NoError(t, batcher.Shutdown(context.Background()))
+	}()
+
+	quiet := testdata.GenerateTraceDataManySpansSameResource(1)
+	quiet.ResourceSpans().At(0).Resource().Attributes().UpsertString("tenant", "quiet")
+	assert.NoError(t, batcher.ConsumeTraces(context.Background(), quiet))
+
+	// A busy partition sent by size does not delay the others.
+	deadline := time.Now().Add(10 * cfg.Timeout)
+	for sink.SpansCount() == 0 || !receivedTenant(sink.AllTraces(), "quiet") {
+		require.True(t, time.Now().Before(deadline), "quiet partition was not sent")
+		busy := testdata.GenerateTraceDataManySpansSameResource(int(cfg.SendBatchSize))
+		busy.ResourceSpans().At(0).Resource().Attributes().UpsertString("tenant", "busy")
+		assert.NoError(t, batcher.ConsumeTraces(context.Background(), busy))
+		<-time.After(cfg.Timeout / 10)
+	}
+}
+
+func receivedTenant(tds []pdata.Traces, tenant string) bool {
+	for _, td := range tds {
+		rss := td.ResourceSpans()
+		for i := 0; i < rss.Len(); i++ {
+			if v, ok := rss.At(i).Resource().Attributes().Get("tenant"); ok && v.StringVal() == tenant {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func TestBatchProcessorSentBySize(t *testing.T) {
 	views := MetricViews()
 	require.NoError(t, view.Register(views...))
@@ -249,6 +390,103 @@ func TestBatchProcessorTraceSendWhenClosing(t *testing.T) {
 	require.Equal(t, 1, len(sink.AllTraces()))
 }
 
+func TestBatchProcessorTraceSendWhenClosingMaxSize(t *testing.T) {
+	cfg := Config{
+		Timeout:          3 * time.Second,
+		SendBatchSize:    100,
+		SendBatchMaxSize: 100,
+	}
+	sink := new(consumertest.TracesSink)
+
+	creationParams := component.ProcessorCreateParams{Logger: zap.NewNop()}
+	batcher := newBatchTracesProcessor(creationParams, sink, &cfg, configtelemetry.LevelDetailed)
+	require.NoError(t, batcher.Start(context.Background(), componenttest.NewNopHost()))
+
+	// Items larger than the max size are split while closing too.
+	assert.NoError(t, batcher.ConsumeTraces(context.Background(), testdata.GenerateTraceDataManySpansSameResource(250)))
+	require.NoError(t, batcher.Shutdown(context.Background()))
+
+	require.Equal(t, 250, sink.SpansCount())
+	require.Equal(t, 3, len(sink.AllTraces()))
+}
+
+func TestBatchProcessorShutdownDrainMetrics(t *testing.T) {
+	views := MetricViews()
+	require.NoError(t, view.Register(views...))
+	defer view.Unregister(views...)
+
+	cfg := Config{
+		Timeout:       3 * time.Second,
+		SendBatchSize: 1000,
+		PartitionKeys: []string{"tenant"},
+	}
+	sink := new(consumertest.TracesSink)
+
+	creationParams := component.ProcessorCreateParams{Logger: zap.NewNop()}
+	batcher := newBatchTracesProcessor(creationParams, sink, &cfg, configtelemetry.LevelDetailed)
+	require.NoError(t, batcher.Start(context.Background(), componenttest.NewNopHost()))
+
+	requestCount := 10
+	spansPerRequest := 10
+	for requestNum := 0; requestNum < requestCount; requestNum++ {
+		td := testdata.GenerateTraceDataManySpansSameResource(spansPerRequest)
+		td.ResourceSpans().At(0).Resource().Attributes().UpsertString("tenant", fmt.Sprint(requestNum%2))
+		assert.NoError(t, batcher.ConsumeTraces(context.Background(), td))
+	}
+
+	require.NoError(t, batcher.Shutdown(context.Background()))
+
+	require.Equal(t, requestCount*spansPerRequest, sink.SpansCount())
+	require.Equal(t, 2, len(sink.AllTraces()))
+	assertViewSum(t, statShutdownFlushedItems.Name(), requestCount*spansPerRequest)
+	assertViewSum(t, statShutdownDroppedItems.Name(), 0)
+	assert.Equal(t, component.DrainStats{Flushed: int64(requestCount * spansPerRequest)}, batcher.DrainStats())
+}
+
+func TestBatchProcessorShutdownDeadline(t *testing.T) {
+	views := MetricViews()
+	require.NoError(t, view.Register(views...))
+	defer view.Unregister(views...)
+
+	cfg := Config{
+		Timeout:       3 * time.Second,
+		SendBatchSize: 1000,
+	}
+	next := &blockingTracesConsumer{}
+
+	creationParams := component.ProcessorCreateParams{Logger: zap.NewNop()}
+	batcher := newBatchTracesProcessor(creationParams, next, &cfg, configtelemetry.LevelDetailed)
+	require.NoError(t, batcher.Start(context.Background(), componenttest.NewNopHost()))
+
+	spanCount := 10
+	assert.NoError(t, batcher.ConsumeTraces(context.Background(), testdata.GenerateTraceDataManySpansSameResource(spanCount)))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, batcher.Shutdown(ctx))
+
+	// The pending items are dropped once the deadline expires.
+	<-batcher.done
+	assertViewSum(t, statShutdownFlushedItems.Name(), 0)
+	assertViewSum(t, statShutdownDroppedItems.Name(), spanCount)
+	assert.Equal(t, component.DrainStats{Dropped: int64(spanCount)}, batcher.DrainStats())
+}
+
+// blockingTracesConsumer blocks until the context is done.
+type blockingTracesConsumer struct{}
+
+func (blockingTracesConsumer) ConsumeTraces(ctx context.Context, _ pdata.Traces) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func assertViewSum(t *testing.T, name string, expected int) {
+	viewData, err := view.RetrieveData("processor/batch/" + name)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(viewData))
+	assert.Equal(t, float64(expected), viewData[0].Data.(*view.SumData).Value)
+}
+
 func TestBatchMetricProcessor_ReceivingData(t *testing.T) {
 	// Instantiate the batch processor with low config values to test data
 	// gets sent through the processor.
@@ -596,6 +834,41 @@ func TestBatchLogProcessor_BatchSize(t *testing.T) {
 	assert.Equal(t, size, int(distData.Sum()))
 }
 
+func TestBatchLogProcessor_BatchMaxBytes(t *testing.T) {
+	cfg := Config{
+		Timeout:           100 * time.Millisecond,
+		SendBatchSize:     8192,
+		SendBatchMaxBytes: 1024,
+	}
+
+	requestCount := 100
+	logsPerRequest := 5
+	sink := new(consumertest.LogsSink)
+
+	createParams := component.ProcessorCreateParams{Logger: zap.NewNop()}
+	batcher := newBatchLogsProcessor(createParams, sink, &cfg, configtelemetry.LevelDetailed)
+	require.NoError(t, batcher.Start(context.Background(), componenttest.NewNopHost()))
+
+	for requestNum := 0; requestNum < requestCount; requestNum++ {
+		ld := testdata.GenerateLogDataManyLogsSameResource(logsPerRequest)
+		logs := ld.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs()
+		for i := 0; i < logsPerRequest; i++ {
+			logs.At(i).SetName(getTestLogName(requestNum, i))
+		}
+		assert.NoError(t, batcher.ConsumeLogs(context.Background(), ld))
+	}
+	require.NoError(t, batcher.Shutdown(context.Background()))
+
+	require.Equal(t, requestCount*logsPerRequest, sink.LogRecordsCount())
+	require.Equal(t, requestCount*logsPerRequest, len(logsReceivedByName(sink.AllLogs())))
+	require.Greater(t, len(sink.AllLogs()), 1)
+	for _, ld := range sink.AllLogs() {
+		buf, err := ld.ToOtlpProtoBytes()
+		require.NoError(t, err)
+		assert.LessOrEqual(t, len(buf), int(cfg.SendBatchMaxBytes))
+	}
+}
+
 func TestBatchLogsProcessor_Timeout(t *testing.T) {
 	cfg := Config{
 		Timeout:       100 * time.Millisecond,
@@ -694,3 +967,72 @@ func TestShutdown(t *testing.T) {
 	factory := NewFactory()
 	componenttest.VerifyProcessorShutdown(t, factory, factory.CreateDefaultConfig())
 }
+
+// backpressureConsumer refuses the traces with a backpressure error while full is set.
+type backpressureConsumer struct {
+	consumertest.TracesSink
+	mu   sync.Mutex
+	full bool
+}
+
+func (bc *backpressureConsumer) setFull(full bool) {
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+	bc.full = full
+}
+
+func (bc *backpressureConsumer) ConsumeTraces(ctx context.Context, td pdata.Traces) error {
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+	if bc.full {
+		return consumererror.Backpressure(errors.New("sending_queue is full"))
+	}
+	return bc.TracesSink.ConsumeTraces(ctx, td)
+}
+
+func TestBatchProcessorBackpressure(t *testing.T) {
+	next := &backpressureConsumer{full: true}
+	cfg := createDefaultConfig().(*Config)
+	cfg.SendBatchSize = 1
+	cfg.Timeout = time.Hour
+	creationParams := component.ProcessorCreateParams{Logger: zap.NewNop(), Backpressure: true}
+	batcher := newBatchTracesProcessor(creationParams, next, cfg, configtelemetry.LevelDetailed)
+	require.NoError(t, batcher.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() {
+		require.NoError(t, batcher.Shutdown(context.Background()))
+	}()
+
+	// The first batch is refused by the next consumer, then the new traces are refused.
+	require.NoError(t, batcher.ConsumeTraces(context.Background(), testdata.GenerateTraceDataOneSpan()))
+	assert.Eventually(t, func() bool {
+		return consumererror.IsBackpressure(batcher.ConsumeTraces(context.Background(), testdata.GenerateTraceDataOneSpan()))
+	}, time.Second, time.Millisecond)
+
+	// The traces are accepted again after the timeout.
+	next.setFull(false)
+	batcher.backpressureMu.Lock()
+	batcher.backpressureUntil = time.Now()
+	batcher.backpressureMu.Unlock()
+	require.NoError(t, batcher.ConsumeTraces(context.Background(), testdata.GenerateTraceDataOneSpan()))
+	assert.Eventually(t, func() bool {
+		return next.SpansCount() > 0
+	}, time.Second, time.Millisecond)
+	// The successful export resets the backpressure.
+	batcher.backpressureMu.Lock()
+	assert.NoError(t, batcher.backpressureErr)
+	batcher.backpressureMu.Unlock()
+}
+
+func TestBatchProcessorNoBackpressure(t *testing.T) {
+	next := &backpressureConsumer{full: true}
+	cfg := createDefaultConfig().(*Config)
+	cfg.SendBatchSize = 1
+	creationParams := component.ProcessorCreateParams{Logger: zap.NewNop()}
+	batcher := newBatchTracesProcessor(creationParams, next, cfg, configtelemetry.LevelDetailed)
+	require.NoError(t, batcher.Start(context.Background(), componenttest.NewNopHost()))
+	for i := 0; i < 10; i++ {
+		require.NoError(t, batcher.ConsumeTraces(context.Background(), testdata.GenerateTraceDataOneSpan()))
+	}
+	require.NoError(t, batcher.Shutdown(context.Background()))
+	assert.Zero(t, next.SpansCount())
+}
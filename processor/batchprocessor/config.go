@@ -33,4 +33,15 @@ type Config struct {
 	// SendBatchMaxSize is the maximum size of a batch. Larger batches are split into smaller units.
 	// Default value is 0, that means no maximum size.
 	SendBatchMaxSize uint32 `mapstructure:"send_batch_max_size,omitempty"`
+
+	// SendBatchMaxBytes is the maximum estimated size in bytes of the OTLP encoding of a batch.
+	// A batch is sent before it would exceed this size, and larger batches are split into smaller units.
+	// Default value is 0, that means no maximum size.
+	SendBatchMaxBytes uint32 `mapstructure:"send_batch_max_bytes,omitempty"`
+
+	// PartitionKeys is the list of resource attributes used to partition the data.
+	// A separate batch is built for every combination of their values, so that all
+	// the resources of a batch have the same values for these attributes.
+	// Default value is empty, that means all the data goes in the same batch.
+	PartitionKeys []string `mapstructure:"partition_keys,omitempty"`
 }
@@ -46,6 +46,7 @@ func TestLoadConfig(t *testing.T) {
 	timeout := time.Second * 10
 	sendBatchSize := uint32(10000)
 	sendBatchMaxSize := uint32(11000)
+	sendBatchMaxBytes := uint32(4000000)
 
 	assert.Equal(t, p1,
 		&Config{
@@ -53,8 +54,10 @@ func TestLoadConfig(t *testing.T) {
 				TypeVal: "batch",
 				NameVal: "batch/2",
 			},
-			SendBatchSize:    sendBatchSize,
-			SendBatchMaxSize: sendBatchMaxSize,
-			Timeout:          timeout,
+			SendBatchSize:     sendBatchSize,
+			SendBatchMaxSize:  sendBatchMaxSize,
+			SendBatchMaxBytes: sendBatchMaxBytes,
+			Timeout:           timeout,
+			PartitionKeys:     []string{"service.name", "tenant"},
 		})
 }
@@ -28,6 +28,8 @@ var (
 	statTimeoutTriggerSend   = stats.Int64("timeout_trigger_send", "Number of times the batch was sent due to a timeout trigger", stats.UnitDimensionless)
 	statBatchSendSize        = stats.Int64("batch_send_size", "Number of units in the batch", stats.UnitDimensionless)
 	statBatchSendSizeBytes   = stats.Int64("batch_send_size_bytes", "Number of bytes in batch that was sent", stats.UnitBytes)
+	statShutdownFlushedItems = stats.Int64("shutdown_flushed_items", "Number of units sent while draining the batches at shutdown", stats.UnitDimensionless)
+	statShutdownDroppedItems = stats.Int64("shutdown_dropped_items", "Number of units dropped while draining the batches at shutdown", stats.UnitDimensionless)
 )
 
 // MetricViews returns the metrics views related to batching
@@ -68,11 +70,29 @@ func MetricViews() []*view.View {
 			1000_000, 2000_000, 3000_000, 4000_000, 5000_000, 6000_000, 7000_000, 8000_000, 9000_000),
 	}
 
+	countShutdownFlushedItemsView := &view.View{
+		Name:        statShutdownFlushedItems.Name(),
+		Measure:     statShutdownFlushedItems,
+		Description: statShutdownFlushedItems.Description(),
+		TagKeys:     processorTagKeys,
+		Aggregation: view.Sum(),
+	}
+
+	countShutdownDroppedItemsView := &view.View{
+		Name:        statShutdownDroppedItems.Name(),
+		Measure:     statShutdownDroppedItems,
+		Description: statShutdownDroppedItems.Description(),
+		TagKeys:     processorTagKeys,
+		Aggregation: view.Sum(),
+	}
+
 	legacyViews := []*view.View{
 		countBatchSizeTriggerSendView,
 		countTimeoutTriggerSendView,
 		distributionBatchSendSizeView,
 		distributionBatchSendSizeBytesView,
+		countShutdownFlushedItemsView,
+		countShutdownDroppedItemsView,
 	}
 
 	return obsreport.ProcessorMetricViews(typeStr, legacyViews)
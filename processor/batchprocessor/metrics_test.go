@@ -26,6 +26,8 @@ func TestBatchProcessorMetrics(t *testing.T) {
 		"timeout_trigger_send",
 		"batch_send_size",
 		"batch_send_size_bytes",
+		"shutdown_flushed_items",
+		"shutdown_dropped_items",
 	}
 	views := MetricViews()
 	for i, viewName := range viewNames {
@@ -0,0 +1,119 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package batchprocessor
+
+import (
+	"strings"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+)
+
+// partitionItem is the part of an item whose resources share the same
+// partition key.
+type partitionItem struct {
+	key  string
+	item interface{}
+}
+
+// partitionKey builds the key identifying the values of the given resource
+// attributes. Values are JSON-like encoded, so that a missing attribute, an
+// empty string and values of different types get different keys.
+func partitionKey(keys []string, resource pdata.Resource) string {
+	var sb strings.Builder
+	attrs := resource.Attributes()
+	for i, key := range keys {
+		if i > 0 {
+			sb.WriteByte(0)
+		}
+		if value, ok := attrs.Get(key); ok {
+			sb.WriteString(tracetranslator.AttributeValueToString(value, true))
+		}
+	}
+	return sb.String()
+}
+
+// splitByPartition groups the resources of the given data by their partition key.
+// The order of the resources is preserved within every group.
+func splitByPartition(keys []string, item interface{}) []partitionItem {
+	var result []partitionItem
+	switch data := item.(type) {
+	case pdata.Traces:
+		result = splitTracesByPartition(keys, data)
+	case pdata.Metrics:
+		result = splitMetricsByPartition(keys, data)
+	case pdata.Logs:
+		result = splitLogsByPartition(keys, data)
+	}
+	if len(result) == 1 {
+		// Common case, all the resources belong to the same partition.
+		result[0].item = item
+	}
+	return result
+}
+
+func splitTracesByPartition(keys []string, td pdata.Traces) []partitionItem {
+	rss := td.ResourceSpans()
+	var result []partitionItem
+	byKey := map[string]pdata.Traces{}
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		key := partitionKey(keys, rs.Resource())
+		dest, ok := byKey[key]
+		if !ok {
+			dest = pdata.NewTraces()
+			byKey[key] = dest
+			result = append(result, partitionItem{key: key, item: dest})
+		}
+		dest.ResourceSpans().Append(rs)
+	}
+	return result
+}
+
+func splitMetricsByPartition(keys []string, md pdata.Metrics) []partitionItem {
+	rms := md.ResourceMetrics()
+	var result []partitionItem
+	byKey := map[string]pdata.Metrics{}
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		key := partitionKey(keys, rm.Resource())
+		dest, ok := byKey[key]
+		if !ok {
+			dest = pdata.NewMetrics()
+			byKey[key] = dest
+			result = append(result, partitionItem{key: key, item: dest})
+		}
+		dest.ResourceMetrics().Append(rm)
+	}
+	return result
+}
+
+func splitLogsByPartition(keys []string, ld pdata.Logs) []partitionItem {
+	rls := ld.ResourceLogs()
+	var result []partitionItem
+	byKey := map[string]pdata.Logs{}
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		key := partitionKey(keys, rl.Resource())
+		dest, ok := byKey[key]
+		if !ok {
+			dest = pdata.NewLogs()
+			byKey[key] = dest
+			result = append(result, partitionItem{key: key, item: dest})
+		}
+		dest.ResourceLogs().Append(rl)
+	}
+	return result
+}
@@ -0,0 +1,96 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package batchprocessor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal/testdata"
+)
+
+func TestPartitionKey(t *testing.T) {
+	keys := []string{"service.name", "tenant"}
+	newResource := func(attrs map[string]pdata.AttributeValue) pdata.Resource {
+		r := pdata.NewResource()
+		r.Attributes().InitFromMap(attrs)
+		return r
+	}
+
+	missing := partitionKey(keys, newResource(map[string]pdata.AttributeValue{}))
+	empty := partitionKey(keys, newResource(map[string]pdata.AttributeValue{
+		"service.name": pdata.NewAttributeValueString(""),
+	}))
+	str := partitionKey(keys, newResource(map[string]pdata.AttributeValue{
+		"tenant": pdata.NewAttributeValueString("1"),
+	}))
+	integer := partitionKey(keys, newResource(map[string]pdata.AttributeValue{
+		"tenant": pdata.NewAttributeValueInt(1),
+	}))
+	other := partitionKey(keys, newResource(map[string]pdata.AttributeValue{
+		"tenant": pdata.NewAttributeValueString("1"),
+		"other":  pdata.NewAttributeValueString("value"),
+	}))
+
+	assert.NotEqual(t, missing, empty)
+	assert.NotEqual(t, missing, str)
+	assert.NotEqual(t, empty, str)
+	assert.NotEqual(t, str, integer)
+	assert.Equal(t, str, other)
+}
+
+func TestSplitByPartition(t *testing.T) {
+	keys := []string{"tenant"}
+	td := testdata.GenerateTraceDataManySpansSameResource(3)
+	td.ResourceSpans().Resize(3)
+	for i, tenant := range []string{"a", "b", "a"} {
+		testdata.GenerateTraceDataManySpansSameResource(i + 1).ResourceSpans().At(0).CopyTo(td.ResourceSpans().At(i))
+		td.ResourceSpans().At(i).Resource().Attributes().UpsertString("tenant", tenant)
+	}
+
+	split := splitByPartition(keys, td)
+	require.Len(t, split, 2)
+	a := split[0].item.(pdata.Traces)
+	b := split[1].item.(pdata.Traces)
+	assert.Equal(t, 2, a.ResourceSpans().Len())
+	assert.Equal(t, 4, a.SpanCount())
+	assert.Equal(t, 1, b.ResourceSpans().Len())
+	assert.Equal(t, 2, b.SpanCount())
+	assert.NotEqual(t, split[0].key, split[1].key)
+}
+
+func TestSplitByPartitionSinglePartition(t *testing.T) {
+	keys := []string{"tenant"}
+
+	td := testdata.GenerateTraceDataTwoSpansSameResourceOneDifferent()
+	split := splitByPartition(keys, td)
+	require.Len(t, split, 1)
+	assert.Equal(t, td, split[0].item)
+
+	md := testdata.GenerateMetricsTwoMetrics()
+	split = splitByPartition(keys, md)
+	require.Len(t, split, 1)
+	assert.Equal(t, md, split[0].item)
+
+	ld := testdata.GenerateLogDataTwoLogsSameResourceOneDifferent()
+	split = splitByPartition(keys, ld)
+	require.Len(t, split, 1)
+	assert.Equal(t, ld, split[0].item)
+
+	assert.Len(t, splitByPartition(keys, pdata.NewTraces()), 0)
+}
@@ -0,0 +1,79 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package batchprocessor
+
+import (
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// encodedSize estimates the size in bytes of the OTLP export request that
+// carries the given data. The estimate never underestimates the real size.
+// Merging requests concatenates their resource entries, so the estimates of
+// the items added to a batch can be summed to bound the size of the batch.
+func encodedSize(item interface{}) int {
+	switch data := item.(type) {
+	case pdata.Traces:
+		return requestSize(data.Size(), data.ResourceSpans().Len())
+	case pdata.Metrics:
+		return requestSize(data.Size(), data.ResourceMetrics().Len())
+	case pdata.Logs:
+		return requestSize(data.SizeBytes(), data.ResourceLogs().Len())
+	}
+	return 0
+}
+
+// requestSize returns the size of a request with the given number of
+// resource entries whose combined size is size. Each entry is prefixed by a
+// one byte field tag and a length varint, which is never longer than the
+// varint of the combined size.
+func requestSize(size int, entries int) int {
+	return size + entries*(1+varintSize(uint64(size)))
+}
+
+func varintSize(x uint64) int {
+	n := 1
+	for x >= 0x80 {
+		x >>= 7
+		n++
+	}
+	return n
+}
+
+// itemCount returns the number of spans, metrics or logs in the given data.
+func itemCount(item interface{}) int {
+	switch data := item.(type) {
+	case pdata.Traces:
+		return data.SpanCount()
+	case pdata.Metrics:
+		return data.MetricCount()
+	case pdata.Logs:
+		return data.LogRecordCount()
+	}
+	return 0
+}
+
+// splitItem removes size spans, metrics or logs from the given data and
+// returns them as a new data.
+func splitItem(size int, item interface{}) interface{} {
+	switch data := item.(type) {
+	case pdata.Traces:
+		return splitTrace(size, data)
+	case pdata.Metrics:
+		return splitMetrics(size, data)
+	case pdata.Logs:
+		return splitLogs(size, data)
+	}
+	return item
+}
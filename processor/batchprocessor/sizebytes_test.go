@@ -0,0 +1,66 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package batchprocessor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal/testdata"
+)
+
+func TestEncodedSize(t *testing.T) {
+	td := testdata.GenerateTraceDataManySpansSameResource(20)
+	testdata.GenerateTraceDataTwoSpansSameResourceOneDifferent().ResourceSpans().MoveAndAppendTo(td.ResourceSpans())
+	buf, err := td.ToOtlpProtoBytes()
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, encodedSize(td), len(buf))
+
+	md := testdata.GenerateMetricsManyMetricsSameResource(20)
+	testdata.GenerateMetricsTwoMetrics().ResourceMetrics().MoveAndAppendTo(md.ResourceMetrics())
+	buf, err = md.ToOtlpProtoBytes()
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, encodedSize(md), len(buf))
+
+	ld := testdata.GenerateLogDataManyLogsSameResource(20)
+	testdata.GenerateLogDataTwoLogsSameResourceOneDifferent().ResourceLogs().MoveAndAppendTo(ld.ResourceLogs())
+	buf, err = ld.ToOtlpProtoBytes()
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, encodedSize(ld), len(buf))
+
+	assert.Equal(t, 0, encodedSize(pdata.NewTraces()))
+}
+
+func TestEncodedSizeSum(t *testing.T) {
+	td1 := testdata.GenerateTraceDataManySpansSameResource(20)
+	td2 := testdata.GenerateTraceDataTwoSpansSameResourceOneDifferent()
+	sum := encodedSize(td1) + encodedSize(td2)
+
+	td2.ResourceSpans().MoveAndAppendTo(td1.ResourceSpans())
+	buf, err := td1.ToOtlpProtoBytes()
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, sum, len(buf))
+}
+
+func TestVarintSize(t *testing.T) {
+	assert.Equal(t, 1, varintSize(0))
+	assert.Equal(t, 1, varintSize(127))
+	assert.Equal(t, 2, varintSize(128))
+	assert.Equal(t, 3, varintSize(1<<14))
+	assert.Equal(t, 4, varintSize(4*1024*1024))
+}
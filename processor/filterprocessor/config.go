@@ -15,7 +15,10 @@
 package filterprocessor
 
 import (
+	"time"
+
 	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/internal/processor/filterconfig"
 	"go.opentelemetry.io/collector/internal/processor/filtermetric"
 )
 
@@ -23,6 +26,8 @@ import (
 type Config struct {
 	configmodels.ProcessorSettings `mapstructure:",squash"`
 	Metrics                        MetricFilters `mapstructure:"metrics"`
+	Spans                          SpanFilters   `mapstructure:"spans"`
+	Logs                           LogFilters    `mapstructure:"logs"`
 }
 
 // MetricFilter filters by Metric properties.
@@ -37,3 +42,69 @@ type MetricFilters struct {
 	// If both Include and Exclude are specified, Include filtering occurs first.
 	Exclude *filtermetric.MatchProperties `mapstructure:"exclude"`
 }
+
+// SpanFilters filters by Span properties.
+type SpanFilters struct {
+	// Include match properties describe spans that should be included in the Collector Service pipeline,
+	// all other spans should be dropped from further processing.
+	// If both Include and Exclude are specified, Include filtering occurs first.
+	Include *SpanMatchProperties `mapstructure:"include"`
+
+	// Exclude match properties describe spans that should be excluded from the Collector Service pipeline,
+	// all other spans should be included.
+	// If both Include and Exclude are specified, Include filtering occurs first.
+	Exclude *SpanMatchProperties `mapstructure:"exclude"`
+}
+
+// SpanMatchProperties specifies the set of properties in a span to match against.
+// All of the specified properties must match for a span to match.
+type SpanMatchProperties struct {
+	// MatchProperties are the services, span names, attributes, resources and libraries to match against.
+	filterconfig.MatchProperties `mapstructure:",squash"`
+
+	// StatusCodes specifies the list of status codes {Unset, Ok, Error} to match the span status against.
+	// A match occurs if the span status code is one of the items in this list.
+	StatusCodes []string `mapstructure:"status_codes"`
+
+	// MinDuration specifies the minimum duration of the span for a match to occur.
+	MinDuration time.Duration `mapstructure:"min_duration"`
+
+	// MaxDuration specifies the maximum duration of the span for a match to occur.
+	MaxDuration time.Duration `mapstructure:"max_duration"`
+
+	// Expressions specifies the list of expr expressions to match spans against.
+	// A match occurs if the span matches at least one expression in this list.
+	Expressions []string `mapstructure:"expressions"`
+}
+
+// LogFilters filters by LogRecord properties.
+type LogFilters struct {
+	// Include match properties describe log records that should be included in the Collector Service pipeline,
+	// all other log records should be dropped from further processing.
+	// If both Include and Exclude are specified, Include filtering occurs first.
+	Include *LogMatchProperties `mapstructure:"include"`
+
+	// Exclude match properties describe log records that should be excluded from the Collector Service pipeline,
+	// all other log records should be included.
+	// If both Include and Exclude are specified, Include filtering occurs first.
+	Exclude *LogMatchProperties `mapstructure:"exclude"`
+}
+
+// LogMatchProperties specifies the set of properties in a log record to match against.
+// All of the specified properties must match for a log record to match.
+type LogMatchProperties struct {
+	// MatchProperties are the log names, attributes, resources and libraries to match against.
+	filterconfig.MatchProperties `mapstructure:",squash"`
+
+	// MinSeverity specifies the minimum severity {trace, debug, info, warn, error, fatal} of the log
+	// record for a match to occur. Log records without a severity number don't match.
+	MinSeverity string `mapstructure:"min_severity"`
+
+	// Bodies specifies the list of items to match the string representation of the log body against,
+	// interpreted according to match_type. A match occurs if the body matches at least one item in this list.
+	Bodies []string `mapstructure:"bodies"`
+
+	// Expressions specifies the list of expr expressions to match log records against.
+	// A match occurs if the log record matches at least one expression in this list.
+	Expressions []string `mapstructure:"expressions"`
+}
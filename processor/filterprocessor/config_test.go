@@ -17,6 +17,7 @@ package filterprocessor
 import (
 	"path"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -24,7 +25,9 @@ import (
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/config/configtest"
+	"go.opentelemetry.io/collector/internal/processor/filterconfig"
 	"go.opentelemetry.io/collector/internal/processor/filtermetric"
+	"go.opentelemetry.io/collector/internal/processor/filterset"
 	fsregexp "go.opentelemetry.io/collector/internal/processor/filterset/regexp"
 )
 
@@ -311,3 +314,71 @@ func TestLoadingConfigExpr(t *testing.T) {
 		})
 	}
 }
+
+// TestLoadingConfigSpansLogs tests loading testdata/config_spans_logs.yaml
+func TestLoadingConfigSpansLogs(t *testing.T) {
+	factories, err := componenttest.NopFactories()
+	assert.Nil(t, err)
+
+	factory := NewFactory()
+	factories.Processors[configmodels.Type(typeStr)] = factory
+	config, err := configtest.LoadConfigFile(t, path.Join(".", "testdata", "config_spans_logs.yaml"), factories)
+
+	assert.Nil(t, err)
+	require.NotNil(t, config)
+
+	assert.Equal(t, &Config{
+		ProcessorSettings: configmodels.ProcessorSettings{
+			NameVal: "filter/spans",
+			TypeVal: typeStr,
+		},
+		Spans: SpanFilters{
+			Include: &SpanMatchProperties{
+				MatchProperties: filterconfig.MatchProperties{
+					Config:     filterset.Config{MatchType: filterset.Regexp},
+					Services:   []string{"checkout.*"},
+					Attributes: []filterconfig.Attribute{{Key: "http.method", Value: "GET|POST"}},
+				},
+			},
+			Exclude: &SpanMatchProperties{
+				StatusCodes: []string{"Unset", "Ok"},
+				MaxDuration: 10 * time.Millisecond,
+			},
+		},
+	}, config.Processors["filter/spans"])
+
+	assert.Equal(t, &Config{
+		ProcessorSettings: configmodels.ProcessorSettings{
+			NameVal: "filter/logs",
+			TypeVal: typeStr,
+		},
+		Logs: LogFilters{
+			Include: &LogMatchProperties{
+				MinSeverity: "warn",
+			},
+			Exclude: &LogMatchProperties{
+				MatchProperties: filterconfig.MatchProperties{
+					Config: filterset.Config{MatchType: filterset.Regexp},
+				},
+				Bodies: []string{"^health check.*"},
+			},
+		},
+	}, config.Processors["filter/logs"])
+
+	assert.Equal(t, &Config{
+		ProcessorSettings: configmodels.ProcessorSettings{
+			NameVal: "filter/expr",
+			TypeVal: typeStr,
+		},
+		Spans: SpanFilters{
+			Exclude: &SpanMatchProperties{
+				Expressions: []string{`SpanName == "GET /healthz" && Attribute("http.status_code") < 500`},
+			},
+		},
+		Logs: LogFilters{
+			Include: &LogMatchProperties{
+				Expressions: []string{`SeverityNumber >= 13 || HasAttribute("error")`},
+			},
+		},
+	}, config.Processors["filter/expr"])
+}
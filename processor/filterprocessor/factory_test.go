@@ -81,9 +81,9 @@ func TestCreateProcessors(t *testing.T) {
 				factory := NewFactory()
 
 				tp, tErr := factory.CreateTracesProcessor(context.Background(), component.ProcessorCreateParams{Logger: zap.NewNop()}, cfg, consumertest.NewTracesNop())
-				// Not implemented error
-				assert.NotNil(t, tErr)
-				assert.Nil(t, tp)
+				// The metric filters don't apply to spans.
+				assert.NoError(t, tErr)
+				assert.NotNil(t, tp)
 
 				mp, mErr := factory.CreateMetricsProcessor(context.Background(), component.ProcessorCreateParams{Logger: zap.NewNop()}, cfg, consumertest.NewMetricsNop())
 				assert.Equal(t, test.succeed, mp != nil)
@@ -92,3 +92,37 @@ func TestCreateProcessors(t *testing.T) {
 		}
 	}
 }
+
+func TestCreateSpanLogProcessors(t *testing.T) {
+	factories, err := componenttest.NopFactories()
+	assert.Nil(t, err)
+
+	factory := NewFactory()
+	factories.Processors[typeStr] = factory
+	cfg, err := configtest.LoadConfigFile(t, path.Join(".", "testdata", "config_spans_logs.yaml"), factories)
+	assert.Nil(t, err)
+
+	params := component.ProcessorCreateParams{Logger: zap.NewNop()}
+	for name, cfg := range cfg.Processors {
+		t.Run(name, func(t *testing.T) {
+			tp, err := factory.CreateTracesProcessor(context.Background(), params, cfg, consumertest.NewTracesNop())
+			assert.NoError(t, err)
+			assert.NotNil(t, tp)
+
+			lp, err := factory.CreateLogsProcessor(context.Background(), params, cfg, consumertest.NewLogsNop())
+			assert.NoError(t, err)
+			assert.NotNil(t, lp)
+		})
+	}
+
+	invalid := factory.CreateDefaultConfig().(*Config)
+	invalid.Spans.Include = &SpanMatchProperties{StatusCodes: []string{"failed"}}
+	tp, err := factory.CreateTracesProcessor(context.Background(), params, invalid, consumertest.NewTracesNop())
+	assert.EqualError(t, err, `invalid spans include: unsupported status code "failed", valid status codes are {Unset, Ok, Error}`)
+	assert.Nil(t, tp)
+
+	invalid.Logs.Exclude = &LogMatchProperties{MinSeverity: "critical"}
+	lp, err := factory.CreateLogsProcessor(context.Background(), params, invalid, consumertest.NewLogsNop())
+	assert.EqualError(t, err, `invalid logs exclude: unsupported min_severity "critical", valid severities are {trace, debug, info, warn, error, fatal}`)
+	assert.Nil(t, lp)
+}
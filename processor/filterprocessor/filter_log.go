@@ -0,0 +1,204 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filterprocessor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal/processor/filterexpr"
+	"go.opentelemetry.io/collector/internal/processor/filterlog"
+	"go.opentelemetry.io/collector/internal/processor/filterset"
+	"go.opentelemetry.io/collector/processor/processorhelper"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+)
+
+var severityNumbers = map[string]pdata.SeverityNumber{
+	"trace": pdata.SeverityNumberTRACE,
+	"debug": pdata.SeverityNumberDEBUG,
+	"info":  pdata.SeverityNumberINFO,
+	"warn":  pdata.SeverityNumberWARN,
+	"error": pdata.SeverityNumberERROR,
+	"fatal": pdata.SeverityNumberFATAL,
+}
+
+type filterLogProcessor struct {
+	include *logMatcher
+	exclude *logMatcher
+	logger  *zap.Logger
+}
+
+// logMatcher matches log records against the filterlog properties, the
+// minimum severity, the bodies and the expressions, all of the specified
+// ones must match.
+type logMatcher struct {
+	properties  filterlog.Matcher
+	minSeverity pdata.SeverityNumber
+	bodies      filterset.FilterSet
+	expressions []*filterexpr.Matcher
+}
+
+func newFilterLogProcessor(logger *zap.Logger, cfg *Config) (*filterLogProcessor, error) {
+	inc, err := newLogMatcher(cfg.Logs.Include)
+	if err != nil {
+		return nil, fmt.Errorf("invalid logs include: %w", err)
+	}
+	exc, err := newLogMatcher(cfg.Logs.Exclude)
+	if err != nil {
+		return nil, fmt.Errorf("invalid logs exclude: %w", err)
+	}
+
+	logger.Info(
+		"Log filter configured",
+		zap.Any("include", cfg.Logs.Include),
+		zap.Any("exclude", cfg.Logs.Exclude),
+	)
+
+	return &filterLogProcessor{
+		include: inc,
+		exclude: exc,
+		logger:  logger,
+	}, nil
+}
+
+func newLogMatcher(mp *LogMatchProperties) (*logMatcher, error) {
+	if mp == nil {
+		return nil, nil
+	}
+
+	lm := &logMatcher{}
+	if mp.MinSeverity != "" {
+		severity, ok := severityNumbers[strings.ToLower(mp.MinSeverity)]
+		if !ok {
+			return nil, fmt.Errorf("unsupported min_severity %q, valid severities are {trace, debug, info, warn, error, fatal}", mp.MinSeverity)
+		}
+		lm.minSeverity = severity
+	}
+	if len(mp.Bodies) > 0 {
+		bodies, err := filterset.CreateFilterSet(mp.Bodies, &mp.Config)
+		if err != nil {
+			return nil, fmt.Errorf("error creating log body filters: %v", err)
+		}
+		lm.bodies = bodies
+	}
+	expressions, err := newExprMatchers(mp.Expressions)
+	if err != nil {
+		return nil, err
+	}
+	lm.expressions = expressions
+
+	hasOther := lm.minSeverity != pdata.SeverityNumberUNDEFINED || lm.bodies != nil || lm.expressions != nil
+	if !hasOther || hasMatchProperties(&mp.MatchProperties) {
+		properties, err := filterlog.NewMatcher(&mp.MatchProperties)
+		if err != nil {
+			return nil, err
+		}
+		lm.properties = properties
+	}
+	return lm, nil
+}
+
+func (lm *logMatcher) match(lr pdata.LogRecord, resource pdata.Resource, library pdata.InstrumentationLibrary) (bool, error) {
+	if lm.minSeverity != pdata.SeverityNumberUNDEFINED && lr.SeverityNumber() < lm.minSeverity {
+		return false, nil
+	}
+	if lm.bodies != nil && !lm.bodies.Matches(tracetranslator.AttributeValueToString(lr.Body(), false)) {
+		return false, nil
+	}
+	if lm.properties != nil && !lm.properties.MatchLogRecord(lr, resource, library) {
+		return false, nil
+	}
+	if lm.expressions == nil {
+		return true, nil
+	}
+	for _, matcher := range lm.expressions {
+		matched, err := matcher.MatchLogRecord(lr, resource)
+		if err != nil {
+			return false, err
+		}
+		if matched {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ProcessLogs filters the given log records based off the filterLogProcessor's filters.
+func (flp *filterLogProcessor) ProcessLogs(_ context.Context, ld pdata.Logs) (pdata.Logs, error) {
+	out := pdata.NewLogs()
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		resource := rl.Resource()
+		rlOut := pdata.NewResourceLogs()
+		ills := rl.InstrumentationLibraryLogs()
+		for j := 0; j < ills.Len(); j++ {
+			ill := ills.At(j)
+			library := ill.InstrumentationLibrary()
+			illOut := pdata.NewInstrumentationLibraryLogs()
+			logs := ill.Logs()
+			for k := 0; k < logs.Len(); k++ {
+				lr := logs.At(k)
+				keep, err := flp.shouldKeepLog(lr, resource, library)
+				if err != nil {
+					flp.logger.Error("shouldKeepLog failed", zap.Error(err))
+					// don't `continue`, keep the log record if there's an error
+				}
+				if keep {
+					illOut.Logs().Append(lr)
+				}
+			}
+			if illOut.Logs().Len() > 0 {
+				library.CopyTo(illOut.InstrumentationLibrary())
+				rlOut.InstrumentationLibraryLogs().Append(illOut)
+			}
+		}
+		if rlOut.InstrumentationLibraryLogs().Len() > 0 {
+			resource.CopyTo(rlOut.Resource())
+			out.ResourceLogs().Append(rlOut)
+		}
+	}
+	if out.ResourceLogs().Len() == 0 {
+		return ld, processorhelper.ErrSkipProcessingData
+	}
+	return out, nil
+}
+
+func (flp *filterLogProcessor) shouldKeepLog(lr pdata.LogRecord, resource pdata.Resource, library pdata.InstrumentationLibrary) (bool, error) {
+	if flp.include != nil {
+		matches, err := flp.include.match(lr, resource, library)
+		if err != nil {
+			// default to keep if there's an error
+			return true, err
+		}
+		if !matches {
+			return false, nil
+		}
+	}
+	if flp.exclude != nil {
+		matches, err := flp.exclude.match(lr, resource, library)
+		if err != nil {
+			return true, err
+		}
+		if matches {
+			return false, nil
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,193 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filterprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal/processor/filterconfig"
+	"go.opentelemetry.io/collector/internal/processor/filterset"
+)
+
+type testLog struct {
+	name     string
+	severity pdata.SeverityNumber
+	body     string
+}
+
+var testLogs = []testLog{
+	{name: "access", severity: pdata.SeverityNumberINFO, body: "GET / 200"},
+	{name: "access", severity: pdata.SeverityNumberWARN, body: "health check failed"},
+	{name: "app", severity: pdata.SeverityNumberERROR, body: "connection refused"},
+	{name: "app", severity: pdata.SeverityNumberUNDEFINED, body: "starting"},
+}
+
+func generateLogs(logs []testLog) pdata.Logs {
+	ld := pdata.NewLogs()
+	rl := pdata.NewResourceLogs()
+	ld.ResourceLogs().Append(rl)
+	ill := pdata.NewInstrumentationLibraryLogs()
+	rl.InstrumentationLibraryLogs().Append(ill)
+	for _, l := range logs {
+		lr := pdata.NewLogRecord()
+		lr.SetName(l.name)
+		lr.SetSeverityNumber(l.severity)
+		lr.Body().SetStringVal(l.body)
+		ill.Logs().Append(lr)
+	}
+	return ld
+}
+
+func logBodies(ld pdata.Logs) []string {
+	var bodies []string
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		ills := rls.At(i).InstrumentationLibraryLogs()
+		for j := 0; j < ills.Len(); j++ {
+			logs := ills.At(j).Logs()
+			for k := 0; k < logs.Len(); k++ {
+				bodies = append(bodies, logs.At(k).Body().StringVal())
+			}
+		}
+	}
+	return bodies
+}
+
+func TestFilterLogProcessor(t *testing.T) {
+	tests := []struct {
+		name     string
+		include  *LogMatchProperties
+		exclude  *LogMatchProperties
+		expected []string
+	}{
+		{
+			name:     "no filters",
+			expected: []string{"GET / 200", "health check failed", "connection refused", "starting"},
+		},
+		{
+			name:     "include min severity",
+			include:  &LogMatchProperties{MinSeverity: "WARN"},
+			expected: []string{"health check failed", "connection refused"},
+		},
+		{
+			name: "exclude bodies",
+			exclude: &LogMatchProperties{
+				MatchProperties: filterconfig.MatchProperties{Config: filterset.Config{MatchType: filterset.Regexp}},
+				Bodies:          []string{"^health check", "^GET "},
+			},
+			expected: []string{"connection refused", "starting"},
+		},
+		{
+			name: "include log names and body",
+			include: &LogMatchProperties{
+				MatchProperties: filterconfig.MatchProperties{
+					Config:   filterset.Config{MatchType: filterset.Strict},
+					LogNames: []string{"app"},
+				},
+				Bodies: []string{"starting"},
+			},
+			expected: []string{"starting"},
+		},
+		{
+			name:     "include and exclude",
+			include:  &LogMatchProperties{MinSeverity: "info"},
+			exclude:  &LogMatchProperties{MinSeverity: "error"},
+			expected: []string{"GET / 200", "health check failed"},
+		},
+		{
+			name:     "include expression",
+			include:  &LogMatchProperties{Expressions: []string{`LogName == "app" && Body contains "refused"`}},
+			expected: []string{"connection refused"},
+		},
+		{
+			name:     "exclude expression",
+			exclude:  &LogMatchProperties{Expressions: []string{`SeverityNumber < 13`}},
+			expected: []string{"health check failed", "connection refused"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			next := new(consumertest.LogsSink)
+			cfg := &Config{Logs: LogFilters{Include: test.include, Exclude: test.exclude}}
+			factory := NewFactory()
+			lp, err := factory.CreateLogsProcessor(context.Background(), component.ProcessorCreateParams{Logger: zap.NewNop()}, cfg, next)
+			require.NoError(t, err)
+
+			require.NoError(t, lp.ConsumeLogs(context.Background(), generateLogs(testLogs)))
+			require.Len(t, next.AllLogs(), 1)
+			assert.Equal(t, test.expected, logBodies(next.AllLogs()[0]))
+		})
+	}
+}
+
+func TestFilterLogProcessor_DropAll(t *testing.T) {
+	next := new(consumertest.LogsSink)
+	cfg := &Config{Logs: LogFilters{Include: &LogMatchProperties{MinSeverity: "fatal"}}}
+	factory := NewFactory()
+	lp, err := factory.CreateLogsProcessor(context.Background(), component.ProcessorCreateParams{Logger: zap.NewNop()}, cfg, next)
+	require.NoError(t, err)
+
+	require.NoError(t, lp.ConsumeLogs(context.Background(), generateLogs(testLogs)))
+	assert.Len(t, next.AllLogs(), 0)
+}
+
+func TestNewLogMatcher_Invalid(t *testing.T) {
+	tests := []struct {
+		name       string
+		properties *LogMatchProperties
+		errorText  string
+	}{
+		{
+			name:       "empty",
+			properties: &LogMatchProperties{},
+			errorText:  `at least one of "log_names", "attributes", "libraries" or "resources" field must be specified`,
+		},
+		{
+			name:       "bodies without match type",
+			properties: &LogMatchProperties{Bodies: []string{"abc"}},
+			errorText:  "error creating log body filters: unrecognized match_type: '', valid types are: [regexp strict]",
+		},
+		{
+			name: "span names",
+			properties: &LogMatchProperties{
+				MatchProperties: filterconfig.MatchProperties{SpanNames: []string{"span"}},
+				MinSeverity:     "info",
+			},
+			errorText: "neither services nor span_names should be specified for log records",
+		},
+		{
+			name:       "invalid expression",
+			properties: &LogMatchProperties{Expressions: []string{""}},
+			errorText:  `invalid expression "": unexpected token EOF (1:1)`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			lm, err := newLogMatcher(test.properties)
+			assert.Nil(t, lm)
+			assert.EqualError(t, err, test.errorText)
+		})
+	}
+}
@@ -0,0 +1,229 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filterprocessor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal/processor/filterconfig"
+	"go.opentelemetry.io/collector/internal/processor/filterexpr"
+	"go.opentelemetry.io/collector/internal/processor/filterspan"
+	"go.opentelemetry.io/collector/processor/processorhelper"
+)
+
+var statusCodes = map[string]pdata.StatusCode{
+	"unset": pdata.StatusCodeUnset,
+	"ok":    pdata.StatusCodeOk,
+	"error": pdata.StatusCodeError,
+}
+
+type filterSpanProcessor struct {
+	include *spanMatcher
+	exclude *spanMatcher
+	logger  *zap.Logger
+}
+
+// spanMatcher matches spans against the filterspan properties, the status
+// codes, the duration range and the expressions, all of the specified ones
+// must match.
+type spanMatcher struct {
+	properties  filterspan.Matcher
+	statusCodes map[pdata.StatusCode]bool
+	minDuration time.Duration
+	maxDuration time.Duration
+	expressions []*filterexpr.Matcher
+}
+
+func newFilterSpanProcessor(logger *zap.Logger, cfg *Config) (*filterSpanProcessor, error) {
+	inc, err := newSpanMatcher(cfg.Spans.Include)
+	if err != nil {
+		return nil, fmt.Errorf("invalid spans include: %w", err)
+	}
+	exc, err := newSpanMatcher(cfg.Spans.Exclude)
+	if err != nil {
+		return nil, fmt.Errorf("invalid spans exclude: %w", err)
+	}
+
+	logger.Info(
+		"Span filter configured",
+		zap.Any("include", cfg.Spans.Include),
+		zap.Any("exclude", cfg.Spans.Exclude),
+	)
+
+	return &filterSpanProcessor{
+		include: inc,
+		exclude: exc,
+		logger:  logger,
+	}, nil
+}
+
+func newSpanMatcher(mp *SpanMatchProperties) (*spanMatcher, error) {
+	if mp == nil {
+		return nil, nil
+	}
+	if mp.MinDuration < 0 || mp.MaxDuration < 0 {
+		return nil, errors.New("min_duration and max_duration must not be negative")
+	}
+	if mp.MaxDuration != 0 && mp.MinDuration > mp.MaxDuration {
+		return nil, errors.New("min_duration must not be greater than max_duration")
+	}
+
+	sm := &spanMatcher{
+		minDuration: mp.MinDuration,
+		maxDuration: mp.MaxDuration,
+	}
+	for _, name := range mp.StatusCodes {
+		code, ok := statusCodes[strings.ToLower(name)]
+		if !ok {
+			return nil, fmt.Errorf("unsupported status code %q, valid status codes are {Unset, Ok, Error}", name)
+		}
+		if sm.statusCodes == nil {
+			sm.statusCodes = map[pdata.StatusCode]bool{}
+		}
+		sm.statusCodes[code] = true
+	}
+	expressions, err := newExprMatchers(mp.Expressions)
+	if err != nil {
+		return nil, err
+	}
+	sm.expressions = expressions
+
+	hasOther := sm.statusCodes != nil || sm.minDuration != 0 || sm.maxDuration != 0 || sm.expressions != nil
+	if !hasOther || hasMatchProperties(&mp.MatchProperties) {
+		properties, err := filterspan.NewMatcher(&mp.MatchProperties)
+		if err != nil {
+			return nil, err
+		}
+		sm.properties = properties
+	}
+	return sm, nil
+}
+
+func newExprMatchers(expressions []string) ([]*filterexpr.Matcher, error) {
+	var matchers []*filterexpr.Matcher
+	for _, expression := range expressions {
+		matcher, err := filterexpr.NewMatcher(expression)
+		if err != nil {
+			return nil, fmt.Errorf("invalid expression %q: %w", expression, err)
+		}
+		matchers = append(matchers, matcher)
+	}
+	return matchers, nil
+}
+
+// hasMatchProperties returns whether any of the filterconfig properties is specified.
+func hasMatchProperties(mp *filterconfig.MatchProperties) bool {
+	return len(mp.Services) > 0 || len(mp.SpanNames) > 0 || len(mp.LogNames) > 0 || len(mp.MetricNames) > 0 ||
+		len(mp.Attributes) > 0 || len(mp.Resources) > 0 || len(mp.Libraries) > 0
+}
+
+func (sm *spanMatcher) match(span pdata.Span, resource pdata.Resource, library pdata.InstrumentationLibrary) (bool, error) {
+	if sm.statusCodes != nil && !sm.statusCodes[span.Status().Code()] {
+		return false, nil
+	}
+	if sm.minDuration != 0 || sm.maxDuration != 0 {
+		duration := time.Duration(span.EndTime() - span.StartTime())
+		if duration < sm.minDuration || (sm.maxDuration != 0 && duration > sm.maxDuration) {
+			return false, nil
+		}
+	}
+	if sm.properties != nil && !sm.properties.MatchSpan(span, resource, library) {
+		return false, nil
+	}
+	if sm.expressions == nil {
+		return true, nil
+	}
+	for _, matcher := range sm.expressions {
+		matched, err := matcher.MatchSpan(span, resource)
+		if err != nil {
+			return false, err
+		}
+		if matched {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ProcessTraces filters the given spans based off the filterSpanProcessor's filters.
+func (fsp *filterSpanProcessor) ProcessTraces(_ context.Context, td pdata.Traces) (pdata.Traces, error) {
+	out := pdata.NewTraces()
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		resource := rs.Resource()
+		rsOut := pdata.NewResourceSpans()
+		ilss := rs.InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			ils := ilss.At(j)
+			library := ils.InstrumentationLibrary()
+			ilsOut := pdata.NewInstrumentationLibrarySpans()
+			spans := ils.Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				keep, err := fsp.shouldKeepSpan(span, resource, library)
+				if err != nil {
+					fsp.logger.Error("shouldKeepSpan failed", zap.Error(err))
+					// don't `continue`, keep the span if there's an error
+				}
+				if keep {
+					ilsOut.Spans().Append(span)
+				}
+			}
+			if ilsOut.Spans().Len() > 0 {
+				library.CopyTo(ilsOut.InstrumentationLibrary())
+				rsOut.InstrumentationLibrarySpans().Append(ilsOut)
+			}
+		}
+		if rsOut.InstrumentationLibrarySpans().Len() > 0 {
+			resource.CopyTo(rsOut.Resource())
+			out.ResourceSpans().Append(rsOut)
+		}
+	}
+	if out.ResourceSpans().Len() == 0 {
+		return td, processorhelper.ErrSkipProcessingData
+	}
+	return out, nil
+}
+
+func (fsp *filterSpanProcessor) shouldKeepSpan(span pdata.Span, resource pdata.Resource, library pdata.InstrumentationLibrary) (bool, error) {
+	if fsp.include != nil {
+		matches, err := fsp.include.match(span, resource, library)
+		if err != nil {
+			// default to keep if there's an error
+			return true, err
+		}
+		if !matches {
+			return false, nil
+		}
+	}
+	if fsp.exclude != nil {
+		matches, err := fsp.exclude.match(span, resource, library)
+		if err != nil {
+			return true, err
+		}
+		if matches {
+			return false, nil
+		}
+	}
+	return true, nil
+}
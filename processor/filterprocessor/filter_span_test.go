@@ -0,0 +1,264 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filterprocessor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal/processor/filterconfig"
+	"go.opentelemetry.io/collector/internal/processor/filterset"
+)
+
+type testSpan struct {
+	name     string
+	service  string
+	status   pdata.StatusCode
+	duration time.Duration
+}
+
+var testSpans = []testSpan{
+	{name: "GET /", service: "frontend", status: pdata.StatusCodeOk, duration: 5 * time.Millisecond},
+	{name: "GET /checkout", service: "frontend", status: pdata.StatusCodeError, duration: time.Second},
+	{name: "charge", service: "payments", status: pdata.StatusCodeUnset, duration: 100 * time.Millisecond},
+	{name: "healthz", service: "payments", status: pdata.StatusCodeOk, duration: time.Millisecond},
+}
+
+// generateTraces generates one resource per service with the spans of the service.
+func generateTraces(spans []testSpan) pdata.Traces {
+	td := pdata.NewTraces()
+	resources := map[string]pdata.InstrumentationLibrarySpans{}
+	for _, s := range spans {
+		ils, ok := resources[s.service]
+		if !ok {
+			rs := pdata.NewResourceSpans()
+			rs.Resource().Attributes().InsertString("service.name", s.service)
+			ils = pdata.NewInstrumentationLibrarySpans()
+			rs.InstrumentationLibrarySpans().Append(ils)
+			td.ResourceSpans().Append(rs)
+			resources[s.service] = ils
+		}
+		span := pdata.NewSpan()
+		span.SetName(s.name)
+		span.Status().SetCode(s.status)
+		span.SetStartTime(pdata.Timestamp(1000))
+		span.SetEndTime(pdata.Timestamp(1000 + uint64(s.duration)))
+		ils.Spans().Append(span)
+	}
+	return td
+}
+
+func spanNames(td pdata.Traces) []string {
+	var names []string
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		ilss := rss.At(i).InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			spans := ilss.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				names = append(names, spans.At(k).Name())
+			}
+		}
+	}
+	return names
+}
+
+func TestFilterSpanProcessor(t *testing.T) {
+	tests := []struct {
+		name     string
+		include  *SpanMatchProperties
+		exclude  *SpanMatchProperties
+		expected []string
+	}{
+		{
+			name:     "no filters",
+			expected: []string{"GET /", "GET /checkout", "charge", "healthz"},
+		},
+		{
+			name: "include span names",
+			include: &SpanMatchProperties{MatchProperties: filterconfig.MatchProperties{
+				Config:    filterset.Config{MatchType: filterset.Regexp},
+				SpanNames: []string{"^GET .*"},
+			}},
+			expected: []string{"GET /", "GET /checkout"},
+		},
+		{
+			name: "exclude service",
+			exclude: &SpanMatchProperties{MatchProperties: filterconfig.MatchProperties{
+				Config:   filterset.Config{MatchType: filterset.Strict},
+				Services: []string{"frontend"},
+			}},
+			expected: []string{"charge", "healthz"},
+		},
+		{
+			name:     "include status codes",
+			include:  &SpanMatchProperties{StatusCodes: []string{"error", "Unset"}},
+			expected: []string{"GET /checkout", "charge"},
+		},
+		{
+			name:     "include min duration",
+			include:  &SpanMatchProperties{MinDuration: 100 * time.Millisecond},
+			expected: []string{"GET /checkout", "charge"},
+		},
+		{
+			name:     "exclude fast successful spans",
+			exclude:  &SpanMatchProperties{StatusCodes: []string{"Ok"}, MaxDuration: 10 * time.Millisecond},
+			expected: []string{"GET /checkout", "charge"},
+		},
+		{
+			name: "include service and duration range",
+			include: &SpanMatchProperties{
+				MatchProperties: filterconfig.MatchProperties{
+					Config:   filterset.Config{MatchType: filterset.Strict},
+					Services: []string{"payments"},
+				},
+				MinDuration: time.Millisecond,
+				MaxDuration: 10 * time.Millisecond,
+			},
+			expected: []string{"healthz"},
+		},
+		{
+			name:    "include and exclude",
+			include: &SpanMatchProperties{StatusCodes: []string{"Ok"}},
+			exclude: &SpanMatchProperties{MatchProperties: filterconfig.MatchProperties{
+				Config:    filterset.Config{MatchType: filterset.Strict},
+				SpanNames: []string{"healthz"},
+			}},
+			expected: []string{"GET /"},
+		},
+		{
+			name:     "include expressions",
+			include:  &SpanMatchProperties{Expressions: []string{`StatusCode == "Error"`, `DurationMillis >= 100`}},
+			expected: []string{"GET /checkout", "charge"},
+		},
+		{
+			name: "exclude expression and service",
+			exclude: &SpanMatchProperties{
+				MatchProperties: filterconfig.MatchProperties{
+					Config:   filterset.Config{MatchType: filterset.Strict},
+					Services: []string{"payments"},
+				},
+				Expressions: []string{`SpanName != "charge"`},
+			},
+			expected: []string{"GET /", "GET /checkout", "charge"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			next := new(consumertest.TracesSink)
+			cfg := &Config{Spans: SpanFilters{Include: test.include, Exclude: test.exclude}}
+			factory := NewFactory()
+			tp, err := factory.CreateTracesProcessor(context.Background(), component.ProcessorCreateParams{Logger: zap.NewNop()}, cfg, next)
+			require.NoError(t, err)
+
+			require.NoError(t, tp.ConsumeTraces(context.Background(), generateTraces(testSpans)))
+			require.Len(t, next.AllTraces(), 1)
+			assert.Equal(t, test.expected, spanNames(next.AllTraces()[0]))
+		})
+	}
+}
+
+func TestFilterSpanProcessor_DropEmpty(t *testing.T) {
+	next := new(consumertest.TracesSink)
+	cfg := &Config{Spans: SpanFilters{Include: &SpanMatchProperties{StatusCodes: []string{"Error"}}}}
+	factory := NewFactory()
+	tp, err := factory.CreateTracesProcessor(context.Background(), component.ProcessorCreateParams{Logger: zap.NewNop()}, cfg, next)
+	require.NoError(t, err)
+
+	// The resource of the "payments" service has no matching span and is dropped.
+	require.NoError(t, tp.ConsumeTraces(context.Background(), generateTraces(testSpans)))
+	require.Len(t, next.AllTraces(), 1)
+	td := next.AllTraces()[0]
+	require.Equal(t, 1, td.ResourceSpans().Len())
+	service, _ := td.ResourceSpans().At(0).Resource().Attributes().Get("service.name")
+	assert.Equal(t, "frontend", service.StringVal())
+
+	// Nothing is sent if no span matches.
+	next.Reset()
+	require.NoError(t, tp.ConsumeTraces(context.Background(), generateTraces(testSpans[2:])))
+	assert.Len(t, next.AllTraces(), 0)
+}
+
+func TestNewSpanMatcher_Invalid(t *testing.T) {
+	tests := []struct {
+		name       string
+		properties *SpanMatchProperties
+		errorText  string
+	}{
+		{
+			name:       "empty",
+			properties: &SpanMatchProperties{},
+			errorText:  `at least one of "services", "span_names", "attributes", "libraries" or "resources" field must be specified`,
+		},
+		{
+			name:       "negative duration",
+			properties: &SpanMatchProperties{MinDuration: -time.Second},
+			errorText:  "min_duration and max_duration must not be negative",
+		},
+		{
+			name:       "min greater than max",
+			properties: &SpanMatchProperties{MinDuration: time.Second, MaxDuration: time.Millisecond},
+			errorText:  "min_duration must not be greater than max_duration",
+		},
+		{
+			name: "log names",
+			properties: &SpanMatchProperties{
+				MatchProperties: filterconfig.MatchProperties{LogNames: []string{"log"}},
+				StatusCodes:     []string{"Ok"},
+			},
+			errorText: "log_names should not be specified for trace spans",
+		},
+		{
+			name:       "invalid expression",
+			properties: &SpanMatchProperties{Expressions: []string{""}},
+			errorText:  `invalid expression "": unexpected token EOF (1:1)`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sm, err := newSpanMatcher(test.properties)
+			assert.Nil(t, sm)
+			assert.EqualError(t, err, test.errorText)
+		})
+	}
+}
+
+func TestFilterSpanProcessor_ExprError(t *testing.T) {
+	core, logs := observer.New(zapcore.ErrorLevel)
+	next := new(consumertest.TracesSink)
+	cfg := &Config{Spans: SpanFilters{Exclude: &SpanMatchProperties{Expressions: []string{"foo"}}}}
+	factory := NewFactory()
+	tp, err := factory.CreateTracesProcessor(context.Background(), component.ProcessorCreateParams{Logger: zap.New(core)}, cfg, next)
+	require.NoError(t, err)
+
+	// Spans are kept if the expression fails to run.
+	require.NoError(t, tp.ConsumeTraces(context.Background(), generateTraces(testSpans)))
+	require.Len(t, next.AllTraces(), 1)
+	assert.Equal(t, []string{"GET /", "GET /checkout", "charge", "healthz"}, spanNames(next.AllTraces()[0]))
+	assert.Equal(t, len(testSpans), logs.Len())
+	assert.Equal(t, "shouldKeepSpan failed", logs.All()[0].Message)
+}
@@ -0,0 +1,59 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package groupbytraceprocessor
+
+import (
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+
+	"go.opentelemetry.io/collector/obsreport"
+	"go.opentelemetry.io/collector/processor"
+)
+
+var (
+	tagReasonKey, _ = tag.NewKey("reason")
+
+	statTracesReleased = stats.Int64("traces_released", "Number of traces released", stats.UnitDimensionless)
+	statTracesInMemory = stats.Int64("traces_in_memory", "Number of traces kept in memory", stats.UnitDimensionless)
+)
+
+// MetricViews returns the metrics views related to grouping the spans by trace.
+func MetricViews() []*view.View {
+	processorTagKeys := []tag.Key{processor.TagProcessorNameKey}
+
+	countTracesReleasedView := &view.View{
+		Name:        statTracesReleased.Name(),
+		Measure:     statTracesReleased,
+		Description: statTracesReleased.Description(),
+		TagKeys:     []tag.Key{processor.TagProcessorNameKey, tagReasonKey},
+		Aggregation: view.Sum(),
+	}
+
+	lastTracesInMemoryView := &view.View{
+		Name:        statTracesInMemory.Name(),
+		Measure:     statTracesInMemory,
+		Description: statTracesInMemory.Description(),
+		TagKeys:     processorTagKeys,
+		Aggregation: view.LastValue(),
+	}
+
+	legacyViews := []*view.View{
+		countTracesReleasedView,
+		lastTracesInMemoryView,
+	}
+
+	return obsreport.ProcessorMetricViews(typeStr, legacyViews)
+}
@@ -0,0 +1,227 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package groupbytraceprocessor
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/tag"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal/batchpertrace"
+	"go.opentelemetry.io/collector/processor"
+)
+
+const (
+	releaseReasonExpired  = "expired"
+	releaseReasonEvicted  = "evicted"
+	releaseReasonShutdown = "shutdown"
+
+	// maxTickInterval is the maximum interval at which the traces that waited
+	// for the wait duration are released.
+	maxTickInterval = 100 * time.Millisecond
+)
+
+var errShutdown = errors.New("group by trace processor is shut down")
+
+// groupByTraceProcessor keeps the spans of the traces in memory for the wait
+// duration after the first span of the trace is received, then releases all
+// the spans of the trace at once. The spans received after the release of
+// their trace are grouped as a new trace.
+type groupByTraceProcessor struct {
+	name         string
+	logger       *zap.Logger
+	nextConsumer consumer.TracesConsumer
+	waitDuration time.Duration
+	numTraces    int
+	tickInterval time.Duration
+
+	mu     sync.Mutex
+	traces map[pdata.TraceID]*traceEntry
+	// queue holds the IDs of the traces in memory in their arrival order,
+	// which is also the order of their release.
+	queue []pdata.TraceID
+	// stopped is set once Shutdown is called, the spans received afterwards are refused.
+	stopped bool
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// traceEntry holds the spans received for a trace.
+type traceEntry struct {
+	batches   []pdata.Traces
+	releaseAt time.Time
+}
+
+// releasedTrace is a trace removed from memory and waiting to be sent.
+type releasedTrace struct {
+	id      pdata.TraceID
+	batches []pdata.Traces
+}
+
+var _ component.TracesProcessor = (*groupByTraceProcessor)(nil)
+
+func newGroupByTraceProcessor(logger *zap.Logger, nextConsumer consumer.TracesConsumer, cfg *Config) *groupByTraceProcessor {
+	tickInterval := cfg.WaitDuration / 10
+	if tickInterval > maxTickInterval || tickInterval <= 0 {
+		tickInterval = maxTickInterval
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &groupByTraceProcessor{
+		name:         cfg.Name(),
+		logger:       logger,
+		nextConsumer: nextConsumer,
+		waitDuration: cfg.WaitDuration,
+		numTraces:    cfg.NumTraces,
+		tickInterval: tickInterval,
+		traces:       make(map[pdata.TraceID]*traceEntry),
+		ctx:          ctx,
+		cancel:       cancel,
+		done:         make(chan struct{}),
+	}
+}
+
+func (p *groupByTraceProcessor) GetCapabilities() component.ProcessorCapabilities {
+	return component.ProcessorCapabilities{MutatesConsumedData: false}
+}
+
+// Start is invoked during service startup.
+func (p *groupByTraceProcessor) Start(context.Context, component.Host) error {
+	go p.startReleaseCycle()
+	return nil
+}
+
+// Shutdown is invoked during service shutdown, the traces in memory are
+// released before their wait duration.
+func (p *groupByTraceProcessor) Shutdown(ctx context.Context) error {
+	p.mu.Lock()
+	p.stopped = true
+	p.mu.Unlock()
+	p.cancel()
+	select {
+	case <-p.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	p.mu.Lock()
+	released := make([]releasedTrace, 0, len(p.queue))
+	for len(p.queue) > 0 {
+		released = append(released, p.popOldest())
+	}
+	p.mu.Unlock()
+	p.release(ctx, released, releaseReasonShutdown)
+	return nil
+}
+
+func (p *groupByTraceProcessor) startReleaseCycle() {
+	defer close(p.done)
+	ticker := time.NewTicker(p.tickInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.ctx.Done():
+			return
+		case now := <-ticker.C:
+			p.releaseExpired(now)
+		}
+	}
+}
+
+// ConsumeTraces keeps the spans in memory until their trace is released.
+func (p *groupByTraceProcessor) ConsumeTraces(ctx context.Context, td pdata.Traces) error {
+	var evicted []releasedTrace
+	now := time.Now()
+
+	p.mu.Lock()
+	if p.stopped {
+		p.mu.Unlock()
+		return errShutdown
+	}
+	for id, batch := range batchpertrace.Split(td) {
+		entry, ok := p.traces[id]
+		if !ok {
+			if len(p.traces) >= p.numTraces {
+				evicted = append(evicted, p.popOldest())
+			}
+			entry = &traceEntry{releaseAt: now.Add(p.waitDuration)}
+			p.traces[id] = entry
+			p.queue = append(p.queue, id)
+		}
+		entry.batches = append(entry.batches, batch)
+	}
+	numTraces := len(p.traces)
+	p.mu.Unlock()
+
+	p.record(nil, statTracesInMemory.M(int64(numTraces)))
+	p.release(ctx, evicted, releaseReasonEvicted)
+	return nil
+}
+
+// releaseExpired releases the traces that waited for the wait duration.
+func (p *groupByTraceProcessor) releaseExpired(now time.Time) {
+	var released []releasedTrace
+	p.mu.Lock()
+	for len(p.queue) > 0 && !p.traces[p.queue[0]].releaseAt.After(now) {
+		released = append(released, p.popOldest())
+	}
+	numTraces := len(p.traces)
+	p.mu.Unlock()
+
+	p.record(nil, statTracesInMemory.M(int64(numTraces)))
+	p.release(context.Background(), released, releaseReasonExpired)
+}
+
+// popOldest removes the oldest trace from memory. It is called with p.mu held
+// and a non empty queue.
+func (p *groupByTraceProcessor) popOldest() releasedTrace {
+	id := p.queue[0]
+	p.queue[0] = pdata.InvalidTraceID()
+	p.queue = p.queue[1:]
+	entry := p.traces[id]
+	delete(p.traces, id)
+	return releasedTrace{id: id, batches: entry.batches}
+}
+
+// release sends the spans of each trace to the next consumer at once.
+func (p *groupByTraceProcessor) release(ctx context.Context, traces []releasedTrace, reason string) {
+	if len(traces) == 0 {
+		return
+	}
+	p.record([]tag.Mutator{tag.Insert(tagReasonKey, reason)}, statTracesReleased.M(int64(len(traces))))
+	for _, trace := range traces {
+		merged := trace.batches[0]
+		for _, batch := range trace.batches[1:] {
+			batch.ResourceSpans().MoveAndAppendTo(merged.ResourceSpans())
+		}
+		if err := p.nextConsumer.ConsumeTraces(ctx, merged); err != nil {
+			p.logger.Warn("Failed to release trace", zap.String("trace_id", trace.id.HexString()), zap.Error(err))
+		}
+	}
+}
+
+func (p *groupByTraceProcessor) record(mutators []tag.Mutator, ms ...stats.Measurement) {
+	mutators = append(mutators, tag.Insert(processor.TagProcessorNameKey, p.name))
+	_ = stats.RecordWithTags(context.Background(), mutators, ms...)
+}
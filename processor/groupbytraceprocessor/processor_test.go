@@ -0,0 +1,161 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package groupbytraceprocessor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func newTestProcessor(cfg *Config) (*groupByTraceProcessor, *consumertest.TracesSink) {
+	sink := new(consumertest.TracesSink)
+	cfg.ProcessorSettings = NewFactory().CreateDefaultConfig().(*Config).ProcessorSettings
+	return newGroupByTraceProcessor(zap.NewNop(), sink, cfg), sink
+}
+
+func traceID(b byte) pdata.TraceID {
+	return pdata.NewTraceID([16]byte{b})
+}
+
+// newTraces returns a resource with a span per trace ID.
+func newTraces(service string, ids ...byte) pdata.Traces {
+	td := pdata.NewTraces()
+	td.ResourceSpans().Resize(1)
+	rs := td.ResourceSpans().At(0)
+	rs.Resource().Attributes().InsertString("service.name", service)
+	rs.InstrumentationLibrarySpans().Resize(1)
+	spans := rs.InstrumentationLibrarySpans().At(0).Spans()
+	spans.Resize(len(ids))
+	for i, id := range ids {
+		spans.At(i).SetTraceID(traceID(id))
+	}
+	return td
+}
+
+// releasedTraceIDs returns the trace ID of each pdata.Traces received by the
+// sink, checking that they hold a single trace.
+func releasedTraceIDs(t *testing.T, sink *consumertest.TracesSink) []pdata.TraceID {
+	var ids []pdata.TraceID
+	for _, td := range sink.AllTraces() {
+		var id pdata.TraceID
+		rss := td.ResourceSpans()
+		for i := 0; i < rss.Len(); i++ {
+			spans := rss.At(i).InstrumentationLibrarySpans().At(0).Spans()
+			for j := 0; j < spans.Len(); j++ {
+				if id.IsEmpty() {
+					id = spans.At(j).TraceID()
+				}
+				assert.Equal(t, id, spans.At(j).TraceID())
+			}
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func TestGroupByTraceProcessor_ReleaseAfterWait(t *testing.T) {
+	p, sink := newTestProcessor(&Config{WaitDuration: time.Second, NumTraces: 10})
+
+	start := time.Now()
+	require.NoError(t, p.ConsumeTraces(context.Background(), newTraces("checkout", 1, 2, 1)))
+	require.NoError(t, p.ConsumeTraces(context.Background(), newTraces("cart", 2, 1)))
+
+	p.releaseExpired(start)
+	assert.Equal(t, 0, sink.SpansCount())
+
+	p.releaseExpired(start.Add(2 * time.Second))
+	assert.Equal(t, 5, sink.SpansCount())
+	ids := releasedTraceIDs(t, sink)
+	assert.ElementsMatch(t, []pdata.TraceID{traceID(1), traceID(2)}, ids)
+	for _, td := range sink.AllTraces() {
+		assert.Equal(t, 2, td.ResourceSpans().Len())
+	}
+	assert.Empty(t, p.traces)
+	assert.Empty(t, p.queue)
+
+	// The late spans are grouped as a new trace.
+	sink.Reset()
+	require.NoError(t, p.ConsumeTraces(context.Background(), newTraces("checkout", 1)))
+	assert.Len(t, p.traces, 1)
+	p.releaseExpired(time.Now().Add(2 * time.Second))
+	assert.Equal(t, []pdata.TraceID{traceID(1)}, releasedTraceIDs(t, sink))
+}
+
+func TestGroupByTraceProcessor_ReleaseInArrivalOrder(t *testing.T) {
+	p, sink := newTestProcessor(&Config{WaitDuration: time.Second, NumTraces: 10})
+
+	require.NoError(t, p.ConsumeTraces(context.Background(), newTraces("checkout", 1)))
+	firstReleaseAt := p.traces[traceID(1)].releaseAt
+	time.Sleep(10 * time.Millisecond)
+	require.NoError(t, p.ConsumeTraces(context.Background(), newTraces("checkout", 2)))
+
+	p.releaseExpired(firstReleaseAt)
+	assert.Equal(t, []pdata.TraceID{traceID(1)}, releasedTraceIDs(t, sink))
+	assert.Len(t, p.traces, 1)
+}
+
+func TestGroupByTraceProcessor_OldestTracesEvicted(t *testing.T) {
+	p, sink := newTestProcessor(&Config{WaitDuration: time.Minute, NumTraces: 2})
+
+	for _, id := range []byte{1, 2, 3} {
+		require.NoError(t, p.ConsumeTraces(context.Background(), newTraces("checkout", id)))
+	}
+	assert.Equal(t, []pdata.TraceID{traceID(1)}, releasedTraceIDs(t, sink))
+	assert.Len(t, p.traces, 2)
+}
+
+func TestGroupByTraceProcessor_ReleaseError(t *testing.T) {
+	p := newGroupByTraceProcessor(zap.NewNop(), consumertest.NewTracesErr(errors.New("failed")), &Config{WaitDuration: time.Second, NumTraces: 10})
+
+	require.NoError(t, p.ConsumeTraces(context.Background(), newTraces("checkout", 1)))
+	p.releaseExpired(time.Now().Add(2 * time.Second))
+	assert.Empty(t, p.traces)
+}
+
+func TestGroupByTraceProcessor_StartShutdown(t *testing.T) {
+	p, sink := newTestProcessor(&Config{WaitDuration: 10 * time.Millisecond, NumTraces: 10})
+	require.NoError(t, p.Start(context.Background(), componenttest.NewNopHost()))
+
+	require.NoError(t, p.ConsumeTraces(context.Background(), newTraces("checkout", 1)))
+	assert.Eventually(t, func() bool {
+		return sink.SpansCount() == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// The traces in memory are released at shutdown.
+	p.waitDuration = time.Hour
+	require.NoError(t, p.ConsumeTraces(context.Background(), newTraces("checkout", 2)))
+	require.NoError(t, p.Shutdown(context.Background()))
+	assert.Equal(t, 2, sink.SpansCount())
+}
+
+func TestGroupByTraceProcessor_RefuseAfterShutdown(t *testing.T) {
+	p, sink := newTestProcessor(&Config{WaitDuration: time.Hour, NumTraces: 10})
+	require.NoError(t, p.Start(context.Background(), componenttest.NewNopHost()))
+	require.NoError(t, p.Shutdown(context.Background()))
+
+	assert.Equal(t, errShutdown, p.ConsumeTraces(context.Background(), newTraces("checkout", 1)))
+	assert.Empty(t, p.traces)
+	assert.Equal(t, 0, sink.SpansCount())
+}
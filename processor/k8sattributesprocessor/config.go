@@ -0,0 +1,67 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8sattributesprocessor
+
+import (
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/processor/k8sattributesprocessor/internal/kube"
+)
+
+// Config has the configuration of the Kubernetes attributes processor.
+type Config struct {
+	configmodels.ProcessorSettings `mapstructure:",squash"`
+	// AuthType is the way of authenticating to the API server,
+	// serviceAccount (the default) or kubeConfig.
+	AuthType kube.AuthType `mapstructure:"auth_type"`
+	// Extract are the metadata of the pods added to the resources.
+	Extract ExtractConfig `mapstructure:"extract"`
+	// Filter restricts the pods watched.
+	Filter FilterConfig `mapstructure:"filter"`
+}
+
+// ExtractConfig are the metadata of the pods added to the resources.
+type ExtractConfig struct {
+	// Metadata are the attributes extracted from the pods, among
+	// k8s.pod.name, k8s.pod.uid, k8s.namespace.name, k8s.deployment.name,
+	// k8s.node.name and k8s.pod.start_time. All of them are extracted if it
+	// is empty.
+	Metadata []string `mapstructure:"metadata"`
+	// Labels are the labels of the pods extracted as attributes.
+	Labels []FieldExtractConfig `mapstructure:"labels"`
+	// Annotations are the annotations of the pods extracted as attributes.
+	Annotations []FieldExtractConfig `mapstructure:"annotations"`
+}
+
+// FieldExtractConfig extracts a label or an annotation of the pods as an
+// attribute.
+type FieldExtractConfig struct {
+	// TagName is the name of the attribute, k8s.pod.labels.<key> or
+	// k8s.pod.annotations.<key> if empty.
+	TagName string `mapstructure:"tag_name"`
+	// Key is the key of the label or of the annotation.
+	Key string `mapstructure:"key"`
+}
+
+// FilterConfig restricts the pods watched, e.g. to the node of the collector
+// when it runs as an agent.
+type FilterConfig struct {
+	// Node is the name of the node of the pods.
+	Node string `mapstructure:"node"`
+	// NodeFromEnvVar is the environment variable holding the name of the
+	// node of the pods, it is ignored if Node is set.
+	NodeFromEnvVar string `mapstructure:"node_from_env_var"`
+	// Namespace is the namespace of the pods.
+	Namespace string `mapstructure:"namespace"`
+}
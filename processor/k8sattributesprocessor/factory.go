@@ -0,0 +1,183 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8sattributesprocessor
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/processor/k8sattributesprocessor/internal/kube"
+	"go.opentelemetry.io/collector/processor/processorhelper"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+const (
+	// The value of "type" key in configuration.
+	typeStr = "k8sattributes"
+)
+
+var processorCapabilities = component.ProcessorCapabilities{MutatesConsumedData: true}
+
+// newClientset is replaced in the tests.
+var newClientset = kube.NewClientset
+
+// NewFactory returns a new factory for the Kubernetes attributes processor.
+func NewFactory() component.ProcessorFactory {
+	return processorhelper.NewFactory(
+		typeStr,
+		createDefaultConfig,
+		processorhelper.WithTraces(createTraceProcessor),
+		processorhelper.WithMetrics(createMetricsProcessor),
+		processorhelper.WithLogs(createLogProcessor))
+}
+
+func createDefaultConfig() configmodels.Processor {
+	return &Config{
+		ProcessorSettings: configmodels.ProcessorSettings{
+			TypeVal: typeStr,
+			NameVal: typeStr,
+		},
+		AuthType: kube.AuthTypeServiceAccount,
+	}
+}
+
+func createTraceProcessor(
+	_ context.Context,
+	_ component.ProcessorCreateParams,
+	cfg configmodels.Processor,
+	nextConsumer consumer.TracesConsumer,
+) (component.TracesProcessor, error) {
+	kp, err := createKubernetesProcessor(cfg.(*Config))
+	if err != nil {
+		return nil, err
+	}
+	return processorhelper.NewTraceProcessor(
+		cfg,
+		nextConsumer,
+		kp,
+		processorhelper.WithCapabilities(processorCapabilities),
+		processorhelper.WithStart(kp.Start),
+		processorhelper.WithShutdown(kp.Shutdown))
+}
+
+func createMetricsProcessor(
+	_ context.Context,
+	_ component.ProcessorCreateParams,
+	cfg configmodels.Processor,
+	nextConsumer consumer.MetricsConsumer,
+) (component.MetricsProcessor, error) {
+	kp, err := createKubernetesProcessor(cfg.(*Config))
+	if err != nil {
+		return nil, err
+	}
+	return processorhelper.NewMetricsProcessor(
+		cfg,
+		nextConsumer,
+		kp,
+		processorhelper.WithCapabilities(processorCapabilities),
+		processorhelper.WithStart(kp.Start),
+		processorhelper.WithShutdown(kp.Shutdown))
+}
+
+func createLogProcessor(
+	_ context.Context,
+	_ component.ProcessorCreateParams,
+	cfg configmodels.Processor,
+	nextConsumer consumer.LogsConsumer,
+) (component.LogsProcessor, error) {
+	kp, err := createKubernetesProcessor(cfg.(*Config))
+	if err != nil {
+		return nil, err
+	}
+	return processorhelper.NewLogsProcessor(
+		cfg,
+		nextConsumer,
+		kp,
+		processorhelper.WithCapabilities(processorCapabilities),
+		processorhelper.WithStart(kp.Start),
+		processorhelper.WithShutdown(kp.Shutdown))
+}
+
+func createKubernetesProcessor(cfg *Config) (*kubernetesProcessor, error) {
+	rules, err := extractionRules(cfg.Extract)
+	if err != nil {
+		return nil, err
+	}
+	filters := kube.Filters{Node: cfg.Filter.Node, Namespace: cfg.Filter.Namespace}
+	if filters.Node == "" && cfg.Filter.NodeFromEnvVar != "" {
+		filters.Node = os.Getenv(cfg.Filter.NodeFromEnvVar)
+		if filters.Node == "" {
+			return nil, fmt.Errorf("node_from_env_var %q is not set", cfg.Filter.NodeFromEnvVar)
+		}
+	}
+	clientset, err := newClientset(cfg.AuthType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the Kubernetes client: %w", err)
+	}
+	return newKubernetesProcessor(kube.New(clientset, rules, filters)), nil
+}
+
+func extractionRules(cfg ExtractConfig) (kube.ExtractionRules, error) {
+	var rules kube.ExtractionRules
+	if len(cfg.Metadata) == 0 {
+		rules = kube.ExtractionRules{PodName: true, PodUID: true, Namespace: true, Deployment: true, NodeName: true, StartTime: true}
+	}
+	for _, field := range cfg.Metadata {
+		switch field {
+		case conventions.AttributeK8sPod:
+			rules.PodName = true
+		case conventions.AttributeK8sPodUID:
+			rules.PodUID = true
+		case conventions.AttributeK8sNamespace:
+			rules.Namespace = true
+		case conventions.AttributeK8sDeployment:
+			rules.Deployment = true
+		case conventions.AttributeK8sNodeName:
+			rules.NodeName = true
+		case kube.AttributeK8sPodStartTime:
+			rules.StartTime = true
+		default:
+			return rules, fmt.Errorf("unsupported metadata %q", field)
+		}
+	}
+
+	var err error
+	if rules.Labels, err = fieldExtractionRules(cfg.Labels, "k8s.pod.labels.%s"); err != nil {
+		return rules, fmt.Errorf("invalid labels: %w", err)
+	}
+	if rules.Annotations, err = fieldExtractionRules(cfg.Annotations, "k8s.pod.annotations.%s"); err != nil {
+		return rules, fmt.Errorf("invalid annotations: %w", err)
+	}
+	return rules, nil
+}
+
+func fieldExtractionRules(cfgs []FieldExtractConfig, defaultName string) ([]kube.FieldExtractionRule, error) {
+	rules := make([]kube.FieldExtractionRule, 0, len(cfgs))
+	for i, cfg := range cfgs {
+		if cfg.Key == "" {
+			return nil, fmt.Errorf("key must be configured at index %d", i)
+		}
+		name := cfg.TagName
+		if name == "" {
+			name = fmt.Sprintf(defaultName, cfg.Key)
+		}
+		rules = append(rules, kube.FieldExtractionRule{Name: name, Key: cfg.Key})
+	}
+	return rules, nil
+}
@@ -0,0 +1,120 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8sattributesprocessor
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configcheck"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/processor/k8sattributesprocessor/internal/kube"
+)
+
+func fakeClientset(t *testing.T) {
+	newClientset = func(kube.AuthType) (kubernetes.Interface, error) {
+		return fake.NewSimpleClientset(), nil
+	}
+	t.Cleanup(func() { newClientset = kube.NewClientset })
+}
+
+func TestCreateDefaultConfig(t *testing.T) {
+	factory := NewFactory()
+
+	cfg := factory.CreateDefaultConfig()
+	assert.NotNil(t, cfg, "failed to create default config")
+	assert.NoError(t, configcheck.ValidateConfig(cfg))
+}
+
+func TestCreateProcessors(t *testing.T) {
+	fakeClientset(t)
+	factory := NewFactory()
+
+	cfg := factory.CreateDefaultConfig()
+	creationParams := component.ProcessorCreateParams{Logger: zap.NewNop()}
+
+	tp, err := factory.CreateTracesProcessor(context.Background(), creationParams, cfg, consumertest.NewTracesNop())
+	assert.NotNil(t, tp)
+	assert.NoError(t, err, "cannot create trace processor")
+
+	mp, err := factory.CreateMetricsProcessor(context.Background(), creationParams, cfg, consumertest.NewMetricsNop())
+	assert.NotNil(t, mp)
+	assert.NoError(t, err, "cannot create metrics processor")
+
+	lp, err := factory.CreateLogsProcessor(context.Background(), creationParams, cfg, consumertest.NewLogsNop())
+	assert.NotNil(t, lp)
+	assert.NoError(t, err, "cannot create logs processor")
+}
+
+func TestCreateProcessorErrors(t *testing.T) {
+	newClientset = func(kube.AuthType) (kubernetes.Interface, error) {
+		return nil, errors.New("no cluster")
+	}
+	defer func() { newClientset = kube.NewClientset }()
+
+	_, err := createKubernetesProcessor(createDefaultConfig().(*Config))
+	assert.EqualError(t, err, "failed to create the Kubernetes client: no cluster")
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Filter.NodeFromEnvVar = "K8S_ATTRIBUTES_TEST_UNSET_NODE"
+	_, err = createKubernetesProcessor(cfg)
+	assert.EqualError(t, err, `node_from_env_var "K8S_ATTRIBUTES_TEST_UNSET_NODE" is not set`)
+}
+
+func TestExtractionRules(t *testing.T) {
+	rules, err := extractionRules(ExtractConfig{})
+	require.NoError(t, err)
+	assert.Equal(t, kube.ExtractionRules{PodName: true, PodUID: true, Namespace: true, Deployment: true, NodeName: true, StartTime: true, Labels: []kube.FieldExtractionRule{}, Annotations: []kube.FieldExtractionRule{}}, rules)
+
+	rules, err = extractionRules(ExtractConfig{
+		Metadata:    []string{"k8s.pod.uid", "k8s.node.name"},
+		Labels:      []FieldExtractConfig{{Key: "app", TagName: "app.label"}, {Key: "version"}},
+		Annotations: []FieldExtractConfig{{Key: "owner"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, kube.ExtractionRules{
+		PodUID:      true,
+		NodeName:    true,
+		Labels:      []kube.FieldExtractionRule{{Name: "app.label", Key: "app"}, {Name: "k8s.pod.labels.version", Key: "version"}},
+		Annotations: []kube.FieldExtractionRule{{Name: "k8s.pod.annotations.owner", Key: "owner"}},
+	}, rules)
+
+	_, err = extractionRules(ExtractConfig{Metadata: []string{"k8s.cluster.name"}})
+	assert.EqualError(t, err, `unsupported metadata "k8s.cluster.name"`)
+
+	_, err = extractionRules(ExtractConfig{Annotations: []FieldExtractConfig{{TagName: "owner"}}})
+	assert.EqualError(t, err, "invalid annotations: key must be configured at index 0")
+}
+
+func TestNodeFromEnvVar(t *testing.T) {
+	fakeClientset(t)
+	require.NoError(t, os.Setenv("K8S_ATTRIBUTES_TEST_NODE", "node"))
+	defer os.Unsetenv("K8S_ATTRIBUTES_TEST_NODE")
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Filter.NodeFromEnvVar = "K8S_ATTRIBUTES_TEST_NODE"
+	kp, err := createKubernetesProcessor(cfg)
+	require.NoError(t, err)
+	assert.NotNil(t, kp)
+}
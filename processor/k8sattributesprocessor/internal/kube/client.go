@@ -0,0 +1,314 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package kube keeps the metadata of the Kubernetes pods in memory, updated
+// by an informer watching the API server.
+package kube
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	api_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+// AuthType is the way of authenticating to the API server.
+type AuthType string
+
+const (
+	// AuthTypeServiceAccount uses the service account of the pod of the
+	// collector.
+	AuthTypeServiceAccount AuthType = "serviceAccount"
+	// AuthTypeKubeConfig uses the kubeconfig file of the user, located with
+	// the KUBECONFIG environment variable or at ~/.kube/config.
+	AuthTypeKubeConfig AuthType = "kubeConfig"
+
+	// AttributeK8sPodStartTime is the start time of the pod, in RFC 3339.
+	AttributeK8sPodStartTime = "k8s.pod.start_time"
+	// AttributeK8sPodIP is the IP address of the pod.
+	AttributeK8sPodIP = "k8s.pod.ip"
+)
+
+// NewClientset returns a clientset of the API server authenticated with
+// authType.
+func NewClientset(authType AuthType) (kubernetes.Interface, error) {
+	var cfg *rest.Config
+	var err error
+	switch authType {
+	case AuthTypeServiceAccount:
+		cfg, err = rest.InClusterConfig()
+	case AuthTypeKubeConfig:
+		cfg, err = clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+			clientcmd.NewDefaultClientConfigLoadingRules(), &clientcmd.ConfigOverrides{}).ClientConfig()
+	default:
+		return nil, fmt.Errorf("unsupported auth_type %q, valid types are {%s, %s}", authType, AuthTypeServiceAccount, AuthTypeKubeConfig)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return kubernetes.NewForConfig(cfg)
+}
+
+// FieldExtractionRule extracts the value of a label or an annotation of the
+// pods as an attribute.
+type FieldExtractionRule struct {
+	// Name is the name of the attribute.
+	Name string
+	// Key is the key of the label or of the annotation.
+	Key string
+}
+
+// ExtractionRules are the metadata of the pods extracted as attributes.
+type ExtractionRules struct {
+	PodName     bool
+	PodUID      bool
+	Namespace   bool
+	Deployment  bool
+	NodeName    bool
+	StartTime   bool
+	Labels      []FieldExtractionRule
+	Annotations []FieldExtractionRule
+}
+
+// Filters restrict the pods watched.
+type Filters struct {
+	// Node is the name of the node of the pods, all the nodes if empty.
+	Node string
+	// Namespace is the namespace of the pods, all the namespaces if empty.
+	Namespace string
+}
+
+// Pod is a pod and its extracted attributes.
+type Pod struct {
+	Name       string
+	Namespace  string
+	UID        string
+	IP         string
+	Attributes map[string]string
+}
+
+// Client returns the pods by IP address or UID.
+type Client interface {
+	// GetPodByIP returns the pod with the given IP address.
+	GetPodByIP(ip string) (*Pod, bool)
+	// GetPodByUID returns the pod with the given UID.
+	GetPodByUID(uid string) (*Pod, bool)
+	// Start starts watching the pods.
+	Start()
+	// Stop stops watching the pods.
+	Stop()
+}
+
+// WatchClient is the Client keeping the pods in memory, updated by an
+// informer.
+type WatchClient struct {
+	informer cache.SharedInformer
+	rules    ExtractionRules
+	stopCh   chan struct{}
+	stopOnce sync.Once
+
+	mu    sync.RWMutex
+	byIP  map[string]*Pod
+	byUID map[string]*Pod
+}
+
+var _ Client = (*WatchClient)(nil)
+
+// New returns a WatchClient of the pods matching filters.
+func New(clientset kubernetes.Interface, rules ExtractionRules, filters Filters) *WatchClient {
+	var fieldSelector string
+	if filters.Node != "" {
+		fieldSelector = fields.OneTermEqualSelector("spec.nodeName", filters.Node).String()
+	}
+	pods := clientset.CoreV1().Pods(filters.Namespace)
+	lw := &cache.ListWatch{
+		ListFunc: func(opts meta_v1.ListOptions) (runtime.Object, error) {
+			opts.FieldSelector = fieldSelector
+			return pods.List(context.Background(), opts)
+		},
+		WatchFunc: func(opts meta_v1.ListOptions) (watch.Interface, error) {
+			opts.FieldSelector = fieldSelector
+			return pods.Watch(context.Background(), opts)
+		},
+	}
+
+	c := &WatchClient{
+		informer: cache.NewSharedInformer(lw, &api_v1.Pod{}, 0),
+		rules:    rules,
+		stopCh:   make(chan struct{}),
+		byIP:     make(map[string]*Pod),
+		byUID:    make(map[string]*Pod),
+	}
+	c.informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    c.handlePodAdd,
+		UpdateFunc: c.handlePodUpdate,
+		DeleteFunc: c.handlePodDelete,
+	})
+	return c
+}
+
+// Start starts watching the pods, it does not wait for the initial list of
+// the pods.
+func (c *WatchClient) Start() {
+	go c.informer.Run(c.stopCh)
+}
+
+// Stop stops watching the pods.
+func (c *WatchClient) Stop() {
+	c.stopOnce.Do(func() {
+		close(c.stopCh)
+	})
+}
+
+// GetPodByIP returns the pod with the given IP address.
+func (c *WatchClient) GetPodByIP(ip string) (*Pod, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	pod, ok := c.byIP[ip]
+	return pod, ok
+}
+
+// GetPodByUID returns the pod with the given UID.
+func (c *WatchClient) GetPodByUID(uid string) (*Pod, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	pod, ok := c.byUID[uid]
+	return pod, ok
+}
+
+func (c *WatchClient) handlePodAdd(obj interface{}) {
+	if pod, ok := obj.(*api_v1.Pod); ok {
+		c.addOrUpdatePod(pod)
+	}
+}
+
+func (c *WatchClient) handlePodUpdate(_, newObj interface{}) {
+	if pod, ok := newObj.(*api_v1.Pod); ok {
+		c.addOrUpdatePod(pod)
+	}
+}
+
+func (c *WatchClient) handlePodDelete(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	if pod, ok := obj.(*api_v1.Pod); ok {
+		c.deletePod(pod)
+	}
+}
+
+func (c *WatchClient) addOrUpdatePod(pod *api_v1.Pod) {
+	newPod := &Pod{
+		Name:       pod.Name,
+		Namespace:  pod.Namespace,
+		UID:        string(pod.UID),
+		Attributes: c.extractAttributes(pod),
+	}
+	// The pods of the host network share the IP address of their node.
+	if !pod.Spec.HostNetwork {
+		newPod.IP = pod.Status.PodIP
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if old, ok := c.byUID[newPod.UID]; ok && old.IP != newPod.IP {
+		c.deleteIP(old)
+	}
+	c.byUID[newPod.UID] = newPod
+	if newPod.IP != "" {
+		c.byIP[newPod.IP] = newPod
+	}
+}
+
+func (c *WatchClient) deletePod(pod *api_v1.Pod) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	old, ok := c.byUID[string(pod.UID)]
+	if !ok {
+		return
+	}
+	delete(c.byUID, old.UID)
+	c.deleteIP(old)
+}
+
+// deleteIP removes the IP address of the pod, unless it has been reused by
+// another pod.
+func (c *WatchClient) deleteIP(pod *Pod) {
+	if current, ok := c.byIP[pod.IP]; ok && current.UID == pod.UID {
+		delete(c.byIP, pod.IP)
+	}
+}
+
+func (c *WatchClient) extractAttributes(pod *api_v1.Pod) map[string]string {
+	attrs := make(map[string]string)
+	if c.rules.PodName {
+		attrs[conventions.AttributeK8sPod] = pod.Name
+	}
+	if c.rules.PodUID {
+		attrs[conventions.AttributeK8sPodUID] = string(pod.UID)
+	}
+	if c.rules.Namespace {
+		attrs[conventions.AttributeK8sNamespace] = pod.Namespace
+	}
+	if c.rules.Deployment {
+		if deployment := deploymentName(pod); deployment != "" {
+			attrs[conventions.AttributeK8sDeployment] = deployment
+		}
+	}
+	if c.rules.NodeName && pod.Spec.NodeName != "" {
+		attrs[conventions.AttributeK8sNodeName] = pod.Spec.NodeName
+	}
+	if c.rules.StartTime && pod.Status.StartTime != nil {
+		attrs[AttributeK8sPodStartTime] = pod.Status.StartTime.Format(time.RFC3339)
+	}
+	for _, r := range c.rules.Labels {
+		if v, ok := pod.Labels[r.Key]; ok {
+			attrs[r.Name] = v
+		}
+	}
+	for _, r := range c.rules.Annotations {
+		if v, ok := pod.Annotations[r.Key]; ok {
+			attrs[r.Name] = v
+		}
+	}
+	return attrs
+}
+
+// deploymentName returns the name of the deployment of the pod, the name of
+// its replica set without the hash of the pod template.
+func deploymentName(pod *api_v1.Pod) string {
+	hash, ok := pod.Labels["pod-template-hash"]
+	if !ok {
+		return ""
+	}
+	for _, owner := range pod.OwnerReferences {
+		if owner.Kind == "ReplicaSet" && strings.HasSuffix(owner.Name, "-"+hash) {
+			return strings.TrimSuffix(owner.Name, "-"+hash)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,150 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kube
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	api_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+)
+
+func newPod(name string, uid string, ip string) *api_v1.Pod {
+	return &api_v1.Pod{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      name,
+			Namespace: "ns",
+			UID:       types.UID(uid),
+			Labels:    map[string]string{"app": "web", "pod-template-hash": "5d8f"},
+			Annotations: map[string]string{
+				"owner": "team",
+			},
+			OwnerReferences: []meta_v1.OwnerReference{{Kind: "ReplicaSet", Name: "web-5d8f"}},
+		},
+		Spec:   api_v1.PodSpec{NodeName: "node"},
+		Status: api_v1.PodStatus{PodIP: ip, StartTime: &meta_v1.Time{Time: time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)}},
+	}
+}
+
+var allRules = ExtractionRules{
+	PodName:     true,
+	PodUID:      true,
+	Namespace:   true,
+	Deployment:  true,
+	NodeName:    true,
+	StartTime:   true,
+	Labels:      []FieldExtractionRule{{Name: "app", Key: "app"}, {Name: "absent", Key: "absent"}},
+	Annotations: []FieldExtractionRule{{Name: "k8s.pod.annotations.owner", Key: "owner"}},
+}
+
+func TestNewClientsetErrors(t *testing.T) {
+	_, err := NewClientset("token")
+	assert.EqualError(t, err, `unsupported auth_type "token", valid types are {serviceAccount, kubeConfig}`)
+}
+
+func TestExtractAttributes(t *testing.T) {
+	c := New(fake.NewSimpleClientset(), allRules, Filters{})
+	c.handlePodAdd(newPod("web-5d8f-x1", "uid1", "1.1.1.1"))
+
+	pod, ok := c.GetPodByIP("1.1.1.1")
+	require.True(t, ok)
+	assert.Equal(t, "web-5d8f-x1", pod.Name)
+	assert.Equal(t, map[string]string{
+		"k8s.pod.name":              "web-5d8f-x1",
+		"k8s.pod.uid":               "uid1",
+		"k8s.namespace.name":        "ns",
+		"k8s.deployment.name":       "web",
+		"k8s.node.name":             "node",
+		"k8s.pod.start_time":        "2021-01-02T03:04:05Z",
+		"app":                       "web",
+		"k8s.pod.annotations.owner": "team",
+	}, pod.Attributes)
+
+	byUID, ok := c.GetPodByUID("uid1")
+	require.True(t, ok)
+	assert.Equal(t, pod, byUID)
+
+	c = New(fake.NewSimpleClientset(), ExtractionRules{PodName: true}, Filters{})
+	c.handlePodAdd(newPod("web-5d8f-x1", "uid1", "1.1.1.1"))
+	pod, ok = c.GetPodByUID("uid1")
+	require.True(t, ok)
+	assert.Equal(t, map[string]string{"k8s.pod.name": "web-5d8f-x1"}, pod.Attributes)
+}
+
+func TestPodLifecycle(t *testing.T) {
+	c := New(fake.NewSimpleClientset(), allRules, Filters{})
+
+	old := newPod("a", "uid1", "1.1.1.1")
+	c.handlePodAdd(old)
+
+	// The IP address of a pod changes.
+	updated := newPod("a", "uid1", "2.2.2.2")
+	c.handlePodUpdate(old, updated)
+	_, ok := c.GetPodByIP("1.1.1.1")
+	assert.False(t, ok)
+	_, ok = c.GetPodByIP("2.2.2.2")
+	assert.True(t, ok)
+
+	// The IP address is reused by another pod before the deletion of the
+	// first one.
+	c.handlePodAdd(newPod("b", "uid2", "2.2.2.2"))
+	c.handlePodDelete(cache.DeletedFinalStateUnknown{Obj: updated})
+	_, ok = c.GetPodByUID("uid1")
+	assert.False(t, ok)
+	pod, ok := c.GetPodByIP("2.2.2.2")
+	require.True(t, ok)
+	assert.Equal(t, "b", pod.Name)
+
+	// The pods of the host network are not indexed by IP address.
+	hostPod := newPod("c", "uid3", "3.3.3.3")
+	hostPod.Spec.HostNetwork = true
+	c.handlePodAdd(hostPod)
+	_, ok = c.GetPodByIP("3.3.3.3")
+	assert.False(t, ok)
+	_, ok = c.GetPodByUID("uid3")
+	assert.True(t, ok)
+}
+
+func TestInformer(t *testing.T) {
+	clientset := fake.NewSimpleClientset(newPod("a", "uid1", "1.1.1.1"))
+	c := New(clientset, allRules, Filters{Namespace: "ns"})
+	c.Start()
+	defer c.Stop()
+
+	assert.Eventually(t, func() bool {
+		_, ok := c.GetPodByIP("1.1.1.1")
+		return ok
+	}, 5*time.Second, 10*time.Millisecond)
+
+	_, err := clientset.CoreV1().Pods("ns").Create(context.Background(), newPod("b", "uid2", "2.2.2.2"), meta_v1.CreateOptions{})
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		_, ok := c.GetPodByIP("2.2.2.2")
+		return ok
+	}, 5*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, clientset.CoreV1().Pods("ns").Delete(context.Background(), "a", meta_v1.DeleteOptions{}))
+	assert.Eventually(t, func() bool {
+		_, ok := c.GetPodByUID("uid1")
+		return !ok
+	}, 5*time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,106 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8sattributesprocessor
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/client"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/processor/k8sattributesprocessor/internal/kube"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+// kubernetesProcessor adds the attributes of the pods to the resources they
+// emitted. The pod of a resource is identified by its k8s.pod.uid attribute,
+// else by its k8s.pod.ip attribute, else by the IP address of the client
+// which sent the data.
+type kubernetesProcessor struct {
+	kc kube.Client
+}
+
+func newKubernetesProcessor(kc kube.Client) *kubernetesProcessor {
+	return &kubernetesProcessor{kc: kc}
+}
+
+// Start starts watching the pods.
+func (kp *kubernetesProcessor) Start(context.Context, component.Host) error {
+	kp.kc.Start()
+	return nil
+}
+
+// Shutdown stops watching the pods.
+func (kp *kubernetesProcessor) Shutdown(context.Context) error {
+	kp.kc.Stop()
+	return nil
+}
+
+// ProcessTraces adds the attributes of the pods to the resources of the spans.
+func (kp *kubernetesProcessor) ProcessTraces(ctx context.Context, td pdata.Traces) (pdata.Traces, error) {
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		kp.processResource(ctx, rss.At(i).Resource())
+	}
+	return td, nil
+}
+
+// ProcessMetrics adds the attributes of the pods to the resources of the
+// metrics.
+func (kp *kubernetesProcessor) ProcessMetrics(ctx context.Context, md pdata.Metrics) (pdata.Metrics, error) {
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		kp.processResource(ctx, rms.At(i).Resource())
+	}
+	return md, nil
+}
+
+// ProcessLogs adds the attributes of the pods to the resources of the logs.
+func (kp *kubernetesProcessor) ProcessLogs(ctx context.Context, ld pdata.Logs) (pdata.Logs, error) {
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		kp.processResource(ctx, rls.At(i).Resource())
+	}
+	return ld, nil
+}
+
+// processResource adds the attributes of the pod of the resource, the
+// attributes of the resource are not overwritten.
+func (kp *kubernetesProcessor) processResource(ctx context.Context, resource pdata.Resource) {
+	attrs := resource.Attributes()
+	pod, ok := kp.podOf(ctx, attrs)
+	if !ok {
+		return
+	}
+	if pod.IP != "" {
+		attrs.InsertString(kube.AttributeK8sPodIP, pod.IP)
+	}
+	for k, v := range pod.Attributes {
+		attrs.InsertString(k, v)
+	}
+}
+
+func (kp *kubernetesProcessor) podOf(ctx context.Context, attrs pdata.AttributeMap) (*kube.Pod, bool) {
+	if uid, ok := attrs.Get(conventions.AttributeK8sPodUID); ok && uid.Type() == pdata.AttributeValueSTRING {
+		return kp.kc.GetPodByUID(uid.StringVal())
+	}
+	if ip, ok := attrs.Get(kube.AttributeK8sPodIP); ok && ip.Type() == pdata.AttributeValueSTRING {
+		return kp.kc.GetPodByIP(ip.StringVal())
+	}
+	if c, ok := client.FromContext(ctx); ok {
+		return kp.kc.GetPodByIP(c.IP)
+	}
+	return nil, false
+}
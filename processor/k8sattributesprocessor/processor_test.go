@@ -0,0 +1,153 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8sattributesprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/client"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/processor/k8sattributesprocessor/internal/kube"
+)
+
+type fakeClient struct {
+	pods    []*kube.Pod
+	started bool
+	stopped bool
+}
+
+func (c *fakeClient) GetPodByIP(ip string) (*kube.Pod, bool) {
+	for _, pod := range c.pods {
+		if pod.IP == ip {
+			return pod, true
+		}
+	}
+	return nil, false
+}
+
+func (c *fakeClient) GetPodByUID(uid string) (*kube.Pod, bool) {
+	for _, pod := range c.pods {
+		if pod.UID == uid {
+			return pod, true
+		}
+	}
+	return nil, false
+}
+
+func (c *fakeClient) Start() { c.started = true }
+func (c *fakeClient) Stop()  { c.stopped = true }
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{pods: []*kube.Pod{
+		{Name: "a", UID: "uid-a", IP: "1.1.1.1", Attributes: map[string]string{"k8s.pod.name": "a", "k8s.namespace.name": "ns"}},
+		{Name: "b", UID: "uid-b", IP: "2.2.2.2", Attributes: map[string]string{"k8s.pod.name": "b", "k8s.namespace.name": "ns"}},
+	}}
+}
+
+func TestStartShutdown(t *testing.T) {
+	kc := newFakeClient()
+	kp := newKubernetesProcessor(kc)
+	require.NoError(t, kp.Start(context.Background(), componenttest.NewNopHost()))
+	assert.True(t, kc.started)
+	require.NoError(t, kp.Shutdown(context.Background()))
+	assert.True(t, kc.stopped)
+}
+
+func TestProcessResource(t *testing.T) {
+	tests := []struct {
+		name     string
+		clientIP string
+		attrs    map[string]pdata.AttributeValue
+		expected map[string]pdata.AttributeValue
+	}{
+		{
+			name:  "by uid",
+			attrs: map[string]pdata.AttributeValue{"k8s.pod.uid": pdata.NewAttributeValueString("uid-b"), "k8s.pod.ip": pdata.NewAttributeValueString("1.1.1.1")},
+			expected: map[string]pdata.AttributeValue{
+				"k8s.pod.uid":        pdata.NewAttributeValueString("uid-b"),
+				"k8s.pod.ip":         pdata.NewAttributeValueString("1.1.1.1"),
+				"k8s.pod.name":       pdata.NewAttributeValueString("b"),
+				"k8s.namespace.name": pdata.NewAttributeValueString("ns"),
+			},
+		},
+		{
+			name:     "by ip",
+			clientIP: "2.2.2.2",
+			attrs:    map[string]pdata.AttributeValue{"k8s.pod.ip": pdata.NewAttributeValueString("1.1.1.1")},
+			expected: map[string]pdata.AttributeValue{
+				"k8s.pod.ip":         pdata.NewAttributeValueString("1.1.1.1"),
+				"k8s.pod.name":       pdata.NewAttributeValueString("a"),
+				"k8s.namespace.name": pdata.NewAttributeValueString("ns"),
+			},
+		},
+		{
+			name:     "by client ip",
+			clientIP: "2.2.2.2",
+			attrs:    map[string]pdata.AttributeValue{"k8s.pod.name": pdata.NewAttributeValueString("custom")},
+			expected: map[string]pdata.AttributeValue{
+				"k8s.pod.ip":         pdata.NewAttributeValueString("2.2.2.2"),
+				"k8s.pod.name":       pdata.NewAttributeValueString("custom"),
+				"k8s.namespace.name": pdata.NewAttributeValueString("ns"),
+			},
+		},
+		{
+			name:     "unknown pod",
+			clientIP: "3.3.3.3",
+			attrs:    map[string]pdata.AttributeValue{"host.name": pdata.NewAttributeValueString("h")},
+			expected: map[string]pdata.AttributeValue{"host.name": pdata.NewAttributeValueString("h")},
+		},
+		{
+			name:     "no client",
+			attrs:    map[string]pdata.AttributeValue{},
+			expected: map[string]pdata.AttributeValue{},
+		},
+	}
+	kp := newKubernetesProcessor(newFakeClient())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.clientIP != "" {
+				ctx = client.NewContext(ctx, &client.Client{IP: tt.clientIP})
+			}
+			expected := pdata.NewAttributeMap().InitFromMap(tt.expected).Sort()
+
+			td := pdata.NewTraces()
+			td.ResourceSpans().Resize(1)
+			td.ResourceSpans().At(0).Resource().Attributes().InitFromMap(tt.attrs)
+			td, err := kp.ProcessTraces(ctx, td)
+			require.NoError(t, err)
+			assert.Equal(t, expected, td.ResourceSpans().At(0).Resource().Attributes().Sort())
+
+			md := pdata.NewMetrics()
+			md.ResourceMetrics().Resize(1)
+			md.ResourceMetrics().At(0).Resource().Attributes().InitFromMap(tt.attrs)
+			md, err = kp.ProcessMetrics(ctx, md)
+			require.NoError(t, err)
+			assert.Equal(t, expected, md.ResourceMetrics().At(0).Resource().Attributes().Sort())
+
+			ld := pdata.NewLogs()
+			ld.ResourceLogs().Resize(1)
+			ld.ResourceLogs().At(0).Resource().Attributes().InitFromMap(tt.attrs)
+			ld, err = kp.ProcessLogs(ctx, ld)
+			require.NoError(t, err)
+			assert.Equal(t, expected, ld.ResourceLogs().At(0).Resource().Attributes().Sort())
+		})
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logstometricsprocessor
+
+import (
+	"go.opentelemetry.io/collector/config/configmodels"
+)
+
+// MetricType is the type of the metric extracted from the log records.
+type MetricType string
+
+const (
+	// Count counts the matching log records, as a cumulative sum.
+	Count MetricType = "count"
+	// Sum sums the values extracted from the matching log records, as a
+	// cumulative sum.
+	Sum MetricType = "sum"
+	// Gauge reports the last value extracted from the matching log records.
+	Gauge MetricType = "gauge"
+)
+
+// MetricCfg is the configuration of a metric extracted from the log records.
+type MetricCfg struct {
+	// Name is the name of the metric.
+	Name string `mapstructure:"name"`
+	// Description is the description of the metric.
+	Description string `mapstructure:"description"`
+	// Unit is the unit of the metric.
+	Unit string `mapstructure:"unit"`
+	// Type is the type of the metric, count (the default), sum or gauge.
+	Type MetricType `mapstructure:"type"`
+	// MinSeverity is the minimum severity of the matching log records, one
+	// of TRACE, DEBUG, INFO, WARN, ERROR and FATAL. All the severities match
+	// if it is empty.
+	MinSeverity string `mapstructure:"min_severity"`
+	// BodyRegex is the regular expression the body of the matching log
+	// records must match. All the bodies match if it is empty.
+	BodyRegex string `mapstructure:"body_regex"`
+	// ValueFrom is the named capture group of BodyRegex the value of the sum
+	// and gauge metrics is parsed from.
+	ValueFrom string `mapstructure:"value_from"`
+	// Labels are the labels of the metric. The value of a label is the
+	// named capture group of BodyRegex of the same name or, if absent, the
+	// attribute of the same name of the log record or of its resource.
+	Labels []string `mapstructure:"labels"`
+}
+
+// Config has the configuration of the logs to metrics processor.
+type Config struct {
+	configmodels.ProcessorSettings `mapstructure:",squash"`
+	// MetricsExporter is the name of the metrics exporter the metrics are
+	// sent to.
+	MetricsExporter string `mapstructure:"metrics_exporter"`
+	// Metrics are the metrics extracted from the log records.
+	Metrics []MetricCfg `mapstructure:"metrics"`
+}
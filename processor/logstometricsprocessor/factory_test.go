@@ -0,0 +1,64 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logstometricsprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configcheck"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+)
+
+func TestCreateDefaultConfig(t *testing.T) {
+	factory := NewFactory()
+
+	cfg := factory.CreateDefaultConfig()
+	assert.NotNil(t, cfg, "failed to create default config")
+	assert.NoError(t, configcheck.ValidateConfig(cfg))
+}
+
+func TestCreateProcessor(t *testing.T) {
+	factory := NewFactory()
+
+	cfg := factory.CreateDefaultConfig().(*Config)
+	creationParams := component.ProcessorCreateParams{Logger: zap.NewNop()}
+	lp, err := factory.CreateLogsProcessor(context.Background(), creationParams, cfg, consumertest.NewLogsNop())
+	assert.Nil(t, lp)
+	assert.Equal(t, errMissingMetricsExporter, err)
+
+	cfg.MetricsExporter = "otlp"
+	lp, err = factory.CreateLogsProcessor(context.Background(), creationParams, cfg, consumertest.NewLogsNop())
+	assert.Nil(t, lp)
+	assert.Equal(t, errNoMetrics, err)
+
+	cfg.Metrics = []MetricCfg{{Name: "log.count"}}
+	lp, err = factory.CreateLogsProcessor(context.Background(), creationParams, cfg, consumertest.NewLogsNop())
+	assert.NotNil(t, lp)
+	assert.NoError(t, err, "cannot create logs processor")
+
+	tp, err := factory.CreateTracesProcessor(context.Background(), creationParams, cfg, consumertest.NewTracesNop())
+	assert.Nil(t, tp)
+	assert.Error(t, err)
+
+	cfg.Metrics = []MetricCfg{{Name: "log.count", Type: "histogram"}}
+	lp, err = factory.CreateLogsProcessor(context.Background(), creationParams, cfg, consumertest.NewLogsNop())
+	assert.Nil(t, lp)
+	assert.EqualError(t, err, `invalid metric "log.count": unsupported type "histogram", valid types are {count, sum, gauge}`)
+}
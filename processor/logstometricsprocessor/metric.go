@@ -0,0 +1,171 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logstometricsprocessor
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+)
+
+var (
+	errMissingName = errors.New("name must be configured")
+
+	// severities are the minimum severity numbers of the severity names, in
+	// increasing order.
+	severities = []struct {
+		name   string
+		number pdata.SeverityNumber
+	}{
+		{"TRACE", pdata.SeverityNumberTRACE},
+		{"DEBUG", pdata.SeverityNumberDEBUG},
+		{"INFO", pdata.SeverityNumberINFO},
+		{"WARN", pdata.SeverityNumberWARN},
+		{"ERROR", pdata.SeverityNumberERROR},
+		{"FATAL", pdata.SeverityNumberFATAL},
+	}
+)
+
+// metric is a compiled MetricCfg.
+type metric struct {
+	MetricCfg
+	minSeverity pdata.SeverityNumber
+	bodyRegex   *regexp.Regexp
+	// valueIndex is the index of the ValueFrom capture group.
+	valueIndex int
+	// labelIndexes are the indexes of the capture groups of the labels, -1
+	// for the labels taken from the attributes.
+	labelIndexes []int
+}
+
+func newMetric(cfg MetricCfg) (*metric, error) {
+	if cfg.Name == "" {
+		return nil, errMissingName
+	}
+	m := &metric{MetricCfg: cfg, valueIndex: -1}
+	if m.Type == "" {
+		m.Type = Count
+	}
+	if m.Type != Count && m.Type != Sum && m.Type != Gauge {
+		return nil, fmt.Errorf("unsupported type %q, valid types are {%s, %s, %s}", m.Type, Count, Sum, Gauge)
+	}
+
+	if m.MinSeverity != "" {
+		var ok bool
+		if m.minSeverity, ok = severityNumber(m.MinSeverity); !ok {
+			return nil, fmt.Errorf("unsupported min_severity %q, valid severities are {TRACE, DEBUG, INFO, WARN, ERROR, FATAL}", m.MinSeverity)
+		}
+	}
+
+	if m.BodyRegex != "" {
+		var err error
+		if m.bodyRegex, err = regexp.Compile(m.BodyRegex); err != nil {
+			return nil, fmt.Errorf("invalid body_regex: %w", err)
+		}
+	}
+
+	switch {
+	case m.Type == Count && m.ValueFrom != "":
+		return nil, fmt.Errorf("value_from cannot be configured with the %s type", Count)
+	case m.Type != Count && m.ValueFrom == "":
+		return nil, fmt.Errorf("value_from must be configured with the %s type", m.Type)
+	case m.ValueFrom != "":
+		if m.valueIndex = m.subexpIndex(m.ValueFrom); m.valueIndex < 0 {
+			return nil, fmt.Errorf("value_from %q is not a capture group of body_regex", m.ValueFrom)
+		}
+	}
+
+	m.labelIndexes = make([]int, len(m.Labels))
+	for i, label := range m.Labels {
+		m.labelIndexes[i] = m.subexpIndex(label)
+	}
+	return m, nil
+}
+
+func (m *metric) subexpIndex(name string) int {
+	if m.bodyRegex == nil {
+		return -1
+	}
+	for i, subexp := range m.bodyRegex.SubexpNames() {
+		if i > 0 && subexp == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// match returns the labels and the value of the metric for the log record,
+// and whether the log record matches the metric. The value is the number of
+// matching log records, one, for the count metrics.
+func (m *metric) match(record pdata.LogRecord, resourceAttrs pdata.AttributeMap) (map[string]string, float64, bool) {
+	if m.minSeverity != pdata.SeverityNumberUNDEFINED && recordSeverity(record) < m.minSeverity {
+		return nil, 0, false
+	}
+
+	var submatches []string
+	if m.bodyRegex != nil {
+		submatches = m.bodyRegex.FindStringSubmatch(tracetranslator.AttributeValueToString(record.Body(), false))
+		if submatches == nil {
+			return nil, 0, false
+		}
+	}
+
+	value := 1.0
+	if m.valueIndex >= 0 {
+		var err error
+		if value, err = strconv.ParseFloat(submatches[m.valueIndex], 64); err != nil {
+			return nil, 0, false
+		}
+	}
+
+	labels := make(map[string]string, len(m.Labels))
+	for i, label := range m.Labels {
+		if index := m.labelIndexes[i]; index >= 0 {
+			labels[label] = submatches[index]
+		} else if attr, ok := record.Attributes().Get(label); ok {
+			labels[label] = tracetranslator.AttributeValueToString(attr, false)
+		} else if attr, ok := resourceAttrs.Get(label); ok {
+			labels[label] = tracetranslator.AttributeValueToString(attr, false)
+		}
+	}
+	return labels, value, true
+}
+
+// recordSeverity returns the severity number of the log record, parsed from
+// its severity text if the number is undefined.
+func recordSeverity(record pdata.LogRecord) pdata.SeverityNumber {
+	if record.SeverityNumber() != pdata.SeverityNumberUNDEFINED {
+		return record.SeverityNumber()
+	}
+	number, _ := severityNumber(record.SeverityText())
+	return number
+}
+
+// severityNumber returns the minimum severity number of the severity name,
+// e.g. ERROR or error2.
+func severityNumber(name string) (pdata.SeverityNumber, bool) {
+	name = strings.ToUpper(name)
+	for _, s := range severities {
+		if strings.HasPrefix(name, s.name) {
+			return s.number, true
+		}
+	}
+	return pdata.SeverityNumberUNDEFINED, false
+}
@@ -0,0 +1,155 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logstometricsprocessor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func TestNewMetricErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  MetricCfg
+		err  string
+	}{
+		{
+			name: "missing name",
+			cfg:  MetricCfg{},
+			err:  "name must be configured",
+		},
+		{
+			name: "invalid severity",
+			cfg:  MetricCfg{Name: "m", MinSeverity: "CRITICAL"},
+			err:  `unsupported min_severity "CRITICAL", valid severities are {TRACE, DEBUG, INFO, WARN, ERROR, FATAL}`,
+		},
+		{
+			name: "invalid regex",
+			cfg:  MetricCfg{Name: "m", BodyRegex: "("},
+			err:  "invalid body_regex: error parsing regexp: missing closing ): `(`",
+		},
+		{
+			name: "count with value",
+			cfg:  MetricCfg{Name: "m", BodyRegex: `(?P<v>\d+)`, ValueFrom: "v"},
+			err:  "value_from cannot be configured with the count type",
+		},
+		{
+			name: "sum without value",
+			cfg:  MetricCfg{Name: "m", Type: Sum},
+			err:  "value_from must be configured with the sum type",
+		},
+		{
+			name: "unknown capture group",
+			cfg:  MetricCfg{Name: "m", Type: Gauge, BodyRegex: `(?P<v>\d+)`, ValueFrom: "w"},
+			err:  `value_from "w" is not a capture group of body_regex`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := newMetric(tt.cfg)
+			assert.Nil(t, m)
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
+
+func newLogRecord(body string, severity pdata.SeverityNumber, severityText string, attrs map[string]string) pdata.LogRecord {
+	record := pdata.NewLogRecord()
+	record.Body().SetStringVal(body)
+	record.SetSeverityNumber(severity)
+	record.SetSeverityText(severityText)
+	for k, v := range attrs {
+		record.Attributes().InsertString(k, v)
+	}
+	return record
+}
+
+func TestMatch(t *testing.T) {
+	m, err := newMetric(MetricCfg{
+		Name:        "m",
+		Type:        Sum,
+		MinSeverity: "warn",
+		BodyRegex:   `^(?P<method>\w+) (?P<size>\S+)$`,
+		ValueFrom:   "size",
+		Labels:      []string{"method", "user", "host.name", "absent"},
+	})
+	require.NoError(t, err)
+
+	resourceAttrs := pdata.NewAttributeMap().InitFromMap(map[string]pdata.AttributeValue{
+		"host.name": pdata.NewAttributeValueString("host"),
+		"user":      pdata.NewAttributeValueString("resource"),
+	})
+	tests := []struct {
+		name   string
+		record pdata.LogRecord
+		labels map[string]string
+		value  float64
+		ok     bool
+	}{
+		{
+			name:   "match",
+			record: newLogRecord("GET 12.5", pdata.SeverityNumberERROR, "", map[string]string{"user": "alice"}),
+			labels: map[string]string{"method": "GET", "user": "alice", "host.name": "host"},
+			value:  12.5,
+			ok:     true,
+		},
+		{
+			name:   "severity text",
+			record: newLogRecord("PUT 3", pdata.SeverityNumberUNDEFINED, "Warning", nil),
+			labels: map[string]string{"method": "PUT", "user": "resource", "host.name": "host"},
+			value:  3,
+			ok:     true,
+		},
+		{
+			name:   "low severity",
+			record: newLogRecord("GET 1", pdata.SeverityNumberINFO4, "", nil),
+		},
+		{
+			name:   "unknown severity",
+			record: newLogRecord("GET 1", pdata.SeverityNumberUNDEFINED, "", nil),
+		},
+		{
+			name:   "body mismatch",
+			record: newLogRecord("GET", pdata.SeverityNumberERROR, "", nil),
+		},
+		{
+			name:   "invalid value",
+			record: newLogRecord("GET many", pdata.SeverityNumberERROR, "", nil),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			labels, value, ok := m.match(tt.record, resourceAttrs)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.labels, labels)
+			assert.Equal(t, tt.value, value)
+		})
+	}
+}
+
+func TestMatchCount(t *testing.T) {
+	m, err := newMetric(MetricCfg{Name: "m"})
+	require.NoError(t, err)
+	assert.Equal(t, Count, m.Type)
+
+	labels, value, ok := m.match(newLogRecord("anything", pdata.SeverityNumberUNDEFINED, "", nil), pdata.NewAttributeMap())
+	assert.True(t, ok)
+	assert.Equal(t, map[string]string{}, labels)
+	assert.Equal(t, 1.0, value)
+}
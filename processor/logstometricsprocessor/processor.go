@@ -0,0 +1,248 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logstometricsprocessor
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// logsToMetricsProcessor passes the log records through unmodified and
+// sends, after each batch, the metrics extracted from the log records to the
+// metrics exporter.
+type logsToMetricsProcessor struct {
+	logger          *zap.Logger
+	nextConsumer    consumer.LogsConsumer
+	exporterName    string
+	metricsExporter component.MetricsExporter
+	metrics         []*metric
+	startTime       pdata.Timestamp
+
+	mu sync.Mutex
+	// series are the series of each metric, by labels.
+	series []map[string]*series
+}
+
+// series is the state of the data points of a metric with the same labels.
+type series struct {
+	labels    map[string]string
+	value     float64
+	timestamp pdata.Timestamp
+	// updated is whether the series matched a log record of the current
+	// batch, only the updated gauges are reported.
+	updated bool
+}
+
+var _ component.LogsProcessor = (*logsToMetricsProcessor)(nil)
+
+func newLogsToMetricsProcessor(logger *zap.Logger, nextConsumer consumer.LogsConsumer, cfg *Config) (*logsToMetricsProcessor, error) {
+	p := &logsToMetricsProcessor{
+		logger:       logger,
+		nextConsumer: nextConsumer,
+		exporterName: cfg.MetricsExporter,
+		metrics:      make([]*metric, len(cfg.Metrics)),
+		series:       make([]map[string]*series, len(cfg.Metrics)),
+	}
+	for i, mCfg := range cfg.Metrics {
+		m, err := newMetric(mCfg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid metric %q: %w", mCfg.Name, err)
+		}
+		p.metrics[i] = m
+		p.series[i] = make(map[string]*series)
+	}
+	return p, nil
+}
+
+func (p *logsToMetricsProcessor) GetCapabilities() component.ProcessorCapabilities {
+	return component.ProcessorCapabilities{MutatesConsumedData: false}
+}
+
+// Start looks up the metrics exporter the metrics are sent to.
+func (p *logsToMetricsProcessor) Start(_ context.Context, host component.Host) error {
+	var available []string
+	for entity, exporter := range host.GetExporters()[configmodels.MetricsDataType] {
+		if entity.Name() != p.exporterName {
+			available = append(available, entity.Name())
+			continue
+		}
+		metricsExporter, ok := exporter.(component.MetricsExporter)
+		if !ok {
+			return fmt.Errorf("exporter %q is not a metrics exporter", p.exporterName)
+		}
+		p.metricsExporter = metricsExporter
+		p.startTime = pdata.TimestampFromTime(time.Now())
+		return nil
+	}
+	sort.Strings(available)
+	return fmt.Errorf("failed to find metrics exporter %q, the exporter must be in a metrics pipeline, available exporters are %v", p.exporterName, available)
+}
+
+// Shutdown is invoked during service shutdown.
+func (p *logsToMetricsProcessor) Shutdown(context.Context) error {
+	return nil
+}
+
+// ConsumeLogs extracts the metrics from the log records and sends them to
+// the metrics exporter before passing the log records to the next consumer.
+// A failure to export the metrics does not prevent the log records from
+// being passed on.
+func (p *logsToMetricsProcessor) ConsumeLogs(ctx context.Context, ld pdata.Logs) error {
+	md := p.extract(ld, pdata.TimestampFromTime(time.Now()))
+	if md.MetricCount() > 0 {
+		if err := p.metricsExporter.ConsumeMetrics(ctx, md); err != nil {
+			p.logger.Warn("Failed to export the log metrics", zap.Error(err))
+		}
+	}
+	return p.nextConsumer.ConsumeLogs(ctx, ld)
+}
+
+// extract records the metrics of the log records and returns the metrics of
+// all the series seen so far and of the gauges updated by the log records.
+func (p *logsToMetricsProcessor) extract(ld pdata.Logs, now pdata.Timestamp) pdata.Metrics {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		resourceAttrs := rl.Resource().Attributes()
+		ills := rl.InstrumentationLibraryLogs()
+		for j := 0; j < ills.Len(); j++ {
+			logs := ills.At(j).Logs()
+			for k := 0; k < logs.Len(); k++ {
+				p.record(logs.At(k), resourceAttrs, now)
+			}
+		}
+	}
+	return p.buildMetrics(now)
+}
+
+func (p *logsToMetricsProcessor) record(record pdata.LogRecord, resourceAttrs pdata.AttributeMap, now pdata.Timestamp) {
+	for i, m := range p.metrics {
+		labels, value, ok := m.match(record, resourceAttrs)
+		if !ok {
+			continue
+		}
+		key := labelsKey(labels)
+		s, ok := p.series[i][key]
+		if !ok {
+			s = &series{labels: labels}
+			p.series[i][key] = s
+		}
+		s.updated = true
+		if m.Type == Gauge {
+			s.value = value
+			s.timestamp = record.Timestamp()
+			if s.timestamp == 0 {
+				s.timestamp = now
+			}
+		} else {
+			s.value += value
+		}
+	}
+}
+
+func (p *logsToMetricsProcessor) buildMetrics(now pdata.Timestamp) pdata.Metrics {
+	md := pdata.NewMetrics()
+	md.ResourceMetrics().Resize(1)
+	ilms := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics()
+	ilms.Resize(1)
+	metrics := ilms.At(0).Metrics()
+	metrics.Resize(len(p.metrics))
+
+	n := 0
+	for i, m := range p.metrics {
+		keys := make([]string, 0, len(p.series[i]))
+		for key, s := range p.series[i] {
+			if m.Type != Gauge || s.updated {
+				keys = append(keys, key)
+			}
+			s.updated = false
+		}
+		if len(keys) == 0 {
+			continue
+		}
+		sort.Strings(keys)
+
+		metric := metrics.At(n)
+		n++
+		metric.SetName(m.Name)
+		metric.SetDescription(m.Description)
+		metric.SetUnit(m.Unit)
+		switch m.Type {
+		case Count:
+			metric.SetDataType(pdata.MetricDataTypeIntSum)
+			metric.IntSum().SetIsMonotonic(true)
+			metric.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+			dps := metric.IntSum().DataPoints()
+			dps.Resize(len(keys))
+			for j, key := range keys {
+				s := p.series[i][key]
+				dp := dps.At(j)
+				dp.LabelsMap().InitFromMap(s.labels)
+				dp.SetStartTime(p.startTime)
+				dp.SetTimestamp(now)
+				dp.SetValue(int64(s.value))
+			}
+		case Sum:
+			metric.SetDataType(pdata.MetricDataTypeDoubleSum)
+			metric.DoubleSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+			dps := metric.DoubleSum().DataPoints()
+			dps.Resize(len(keys))
+			for j, key := range keys {
+				s := p.series[i][key]
+				dp := dps.At(j)
+				dp.LabelsMap().InitFromMap(s.labels)
+				dp.SetStartTime(p.startTime)
+				dp.SetTimestamp(now)
+				dp.SetValue(s.value)
+			}
+		case Gauge:
+			metric.SetDataType(pdata.MetricDataTypeDoubleGauge)
+			dps := metric.DoubleGauge().DataPoints()
+			dps.Resize(len(keys))
+			for j, key := range keys {
+				s := p.series[i][key]
+				dp := dps.At(j)
+				dp.LabelsMap().InitFromMap(s.labels)
+				dp.SetTimestamp(s.timestamp)
+				dp.SetValue(s.value)
+			}
+		}
+	}
+	metrics.Resize(n)
+	return md
+}
+
+func labelsKey(labels map[string]string) string {
+	kvs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		kvs = append(kvs, k+"="+v)
+	}
+	sort.Strings(kvs)
+	return strings.Join(kvs, "\x00")
+}
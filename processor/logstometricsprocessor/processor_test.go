@@ -0,0 +1,176 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logstometricsprocessor
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+type metricsExporter struct {
+	component.Component
+	consumertest.MetricsSink
+	err error
+}
+
+func (e *metricsExporter) ConsumeMetrics(ctx context.Context, md pdata.Metrics) error {
+	if e.err != nil {
+		return e.err
+	}
+	return e.MetricsSink.ConsumeMetrics(ctx, md)
+}
+
+type logsExporter struct {
+	component.Component
+	consumertest.LogsSink
+}
+
+type exportersHost struct {
+	component.Host
+	exporters map[configmodels.DataType]map[configmodels.NamedEntity]component.Exporter
+}
+
+func (h *exportersHost) GetExporters() map[configmodels.DataType]map[configmodels.NamedEntity]component.Exporter {
+	return h.exporters
+}
+
+func newHost(exporters map[string]component.Exporter) component.Host {
+	metricsExporters := map[configmodels.NamedEntity]component.Exporter{}
+	for name, exporter := range exporters {
+		metricsExporters[&configmodels.ExporterSettings{TypeVal: "test", NameVal: name}] = exporter
+	}
+	return &exportersHost{
+		Host:      componenttest.NewNopHost(),
+		exporters: map[configmodels.DataType]map[configmodels.NamedEntity]component.Exporter{configmodels.MetricsDataType: metricsExporters},
+	}
+}
+
+func newLogs(records ...pdata.LogRecord) pdata.Logs {
+	ld := pdata.NewLogs()
+	ld.ResourceLogs().Resize(1)
+	rl := ld.ResourceLogs().At(0)
+	rl.Resource().Attributes().InsertString("service.name", "svc")
+	rl.InstrumentationLibraryLogs().Resize(1)
+	logs := rl.InstrumentationLibraryLogs().At(0).Logs()
+	logs.Resize(len(records))
+	for i, record := range records {
+		record.CopyTo(logs.At(i))
+	}
+	return ld
+}
+
+func newStartedProcessor(t *testing.T, metrics []MetricCfg, exporter component.MetricsExporter) (*logsToMetricsProcessor, *consumertest.LogsSink) {
+	next := new(consumertest.LogsSink)
+	p, err := newLogsToMetricsProcessor(zap.NewNop(), next, &Config{MetricsExporter: "logs", Metrics: metrics})
+	require.NoError(t, err)
+	require.NoError(t, p.Start(context.Background(), newHost(map[string]component.Exporter{"logs": exporter})))
+	return p, next
+}
+
+func TestStartErrors(t *testing.T) {
+	p, err := newLogsToMetricsProcessor(zap.NewNop(), consumertest.NewLogsNop(), &Config{MetricsExporter: "logs", Metrics: []MetricCfg{{Name: "m"}}})
+	require.NoError(t, err)
+	err = p.Start(context.Background(), newHost(map[string]component.Exporter{"b": &metricsExporter{}, "a": &metricsExporter{}}))
+	assert.EqualError(t, err, `failed to find metrics exporter "logs", the exporter must be in a metrics pipeline, available exporters are [a b]`)
+
+	err = p.Start(context.Background(), newHost(map[string]component.Exporter{"logs": &logsExporter{}}))
+	assert.EqualError(t, err, `exporter "logs" is not a metrics exporter`)
+}
+
+func TestConsumeLogs(t *testing.T) {
+	exporter := &metricsExporter{}
+	p, next := newStartedProcessor(t, []MetricCfg{
+		{Name: "log.errors", MinSeverity: "ERROR", Labels: []string{"service.name"}},
+		{Name: "response.size", Type: Sum, BodyRegex: `^(?P<method>\w+) (?P<size>\d+)$`, ValueFrom: "size", Labels: []string{"method"}},
+		{Name: "last.size", Type: Gauge, BodyRegex: `^\w+ (?P<size>\d+)$`, ValueFrom: "size"},
+	}, exporter)
+
+	ld := newLogs(
+		newLogRecord("GET 10", pdata.SeverityNumberERROR, "", nil),
+		newLogRecord("GET 5", pdata.SeverityNumberINFO, "", nil),
+		newLogRecord("PUT 1", pdata.SeverityNumberINFO, "", nil),
+	)
+	ld.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(2).SetTimestamp(42)
+	require.NoError(t, p.ConsumeLogs(context.Background(), ld))
+	require.NoError(t, p.ConsumeLogs(context.Background(), newLogs(
+		newLogRecord("failure", pdata.SeverityNumberFATAL, "", nil),
+	)))
+
+	assert.Equal(t, 2, len(next.AllLogs()))
+	assert.Equal(t, ld, next.AllLogs()[0])
+
+	require.Len(t, exporter.AllMetrics(), 2)
+
+	// All the metrics are reported after the first batch.
+	metrics := exporter.AllMetrics()[0].ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	require.Equal(t, 3, metrics.Len())
+	gauge := metrics.At(2)
+	assert.Equal(t, "last.size", gauge.Name())
+	require.Equal(t, 1, gauge.DoubleGauge().DataPoints().Len())
+	assert.Equal(t, 1.0, gauge.DoubleGauge().DataPoints().At(0).Value())
+	assert.Equal(t, pdata.Timestamp(42), gauge.DoubleGauge().DataPoints().At(0).Timestamp())
+
+	// The gauge is not updated by the second batch.
+	metrics = exporter.AllMetrics()[1].ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	require.Equal(t, 2, metrics.Len())
+
+	errorsMetric := metrics.At(0)
+	assert.Equal(t, "log.errors", errorsMetric.Name())
+	assert.True(t, errorsMetric.IntSum().IsMonotonic())
+	assert.Equal(t, pdata.AggregationTemporalityCumulative, errorsMetric.IntSum().AggregationTemporality())
+	require.Equal(t, 1, errorsMetric.IntSum().DataPoints().Len())
+	dp := errorsMetric.IntSum().DataPoints().At(0)
+	assert.Equal(t, int64(2), dp.Value())
+	assert.Equal(t, p.startTime, dp.StartTime())
+	assert.Equal(t, pdata.NewStringMap().InitFromMap(map[string]string{"service.name": "svc"}), dp.LabelsMap())
+
+	sizeMetric := metrics.At(1)
+	assert.Equal(t, "response.size", sizeMetric.Name())
+	assert.Equal(t, pdata.AggregationTemporalityCumulative, sizeMetric.DoubleSum().AggregationTemporality())
+	dps := sizeMetric.DoubleSum().DataPoints()
+	require.Equal(t, 2, dps.Len())
+	assert.Equal(t, pdata.NewStringMap().InitFromMap(map[string]string{"method": "GET"}), dps.At(0).LabelsMap())
+	assert.Equal(t, 15.0, dps.At(0).Value())
+	assert.Equal(t, pdata.NewStringMap().InitFromMap(map[string]string{"method": "PUT"}), dps.At(1).LabelsMap())
+	assert.Equal(t, 1.0, dps.At(1).Value())
+}
+
+func TestNoMatch(t *testing.T) {
+	exporter := &metricsExporter{}
+	p, next := newStartedProcessor(t, []MetricCfg{{Name: "log.errors", MinSeverity: "ERROR"}}, exporter)
+
+	require.NoError(t, p.ConsumeLogs(context.Background(), newLogs(newLogRecord("ok", pdata.SeverityNumberINFO, "", nil))))
+	assert.Equal(t, 1, next.LogRecordsCount())
+	assert.Empty(t, exporter.AllMetrics())
+}
+
+func TestExportFailure(t *testing.T) {
+	exporter := &metricsExporter{err: errors.New("export failed")}
+	p, next := newStartedProcessor(t, []MetricCfg{{Name: "log.count"}}, exporter)
+
+	require.NoError(t, p.ConsumeLogs(context.Background(), newLogs(newLogRecord("ok", pdata.SeverityNumberINFO, "", nil))))
+	assert.Equal(t, 1, next.LogRecordsCount())
+}
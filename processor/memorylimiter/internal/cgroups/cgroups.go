@@ -38,10 +38,18 @@
 
 package cgroups
 
+import (
+	"os"
+	"strconv"
+)
+
 const (
 	// _cgroupFSType is the Linux CGroup file system type used in
 	// `/proc/$PID/mountinfo`.
 	_cgroupFSType = "cgroup"
+	// _cgroupV2FSType is the Linux CGroup v2 (unified hierarchy) file system
+	// type used in `/proc/$PID/mountinfo`.
+	_cgroupV2FSType = "cgroup2"
 	// _cgroupSubsysCPU is the CPU CGroup subsystem.
 	_cgroupSubsysCPU = "cpu"
 	// _cgroupSubsysCPUAcct is the CPU accounting CGroup subsystem.
@@ -51,7 +59,13 @@ const (
 	// _cgroupSubsysMemory is the Memory CGroup subsystem.
 	_cgroupSubsysMemory = "memory"
 
+	// _cgroupSubsysUnified is the key of the CGroup v2 unified hierarchy, it
+	// is listed in `/proc/$PID/cgroup` with an empty subsystem list.
+	_cgroupSubsysUnified = ""
+
 	_cgroupMemoryLimitBytes = "memory.limit_in_bytes"
+	_cgroupV2MemoryMax      = "memory.max"
+	_cgroupV2Unlimited      = "max"
 )
 
 const (
@@ -73,6 +87,9 @@ func NewCGroups(procPathMountInfo, procPathCGroup string) (CGroups, error) {
 
 	cgroups := make(CGroups)
 	newMountPoint := func(mp *MountPoint) error {
+		if mp.FSType == _cgroupV2FSType {
+			return addUnifiedCGroup(cgroups, cgroupSubsystems, mp)
+		}
 		if mp.FSType != _cgroupFSType {
 			return nil
 		}
@@ -99,24 +116,61 @@ func NewCGroups(procPathMountInfo, procPathCGroup string) (CGroups, error) {
 	return cgroups, nil
 }
 
+// addUnifiedCGroup adds the CGroup v2 of the process, if any, mounted at mp.
+func addUnifiedCGroup(cgroups CGroups, cgroupSubsystems map[string]*CGroupSubsys, mp *MountPoint) error {
+	subsys, exists := cgroupSubsystems[_cgroupSubsysUnified]
+	if !exists {
+		return nil
+	}
+	if _, exists := cgroups[_cgroupSubsysUnified]; exists {
+		return nil
+	}
+
+	cgroupPath, err := mp.Translate(subsys.Name)
+	if err != nil {
+		return err
+	}
+	cgroups[_cgroupSubsysUnified] = NewCGroup(cgroupPath)
+	return nil
+}
+
 // NewCGroupsForCurrentProcess returns a new *CGroups instance for the current
 // process.
 func NewCGroupsForCurrentProcess() (CGroups, error) {
 	return NewCGroups(_procPathMountInfo, _procPathCGroup)
 }
 
-// MemoryQuota returns the total memory a
-// It is a result of `memory.limit_in_bytes`. If the value of
-// `memory.limit_in_bytes` was not set (-1), the method returns `(-1, false, nil)`.
+// MemoryQuota returns the total memory limit of the process.
+// It is a result of `memory.limit_in_bytes` for CGroup v1, or `memory.max`
+// for CGroup v2 if the memory subsystem is not found in CGroup v1. If the limit
+// was not set (-1 or "max"), the method returns `(-1, false, nil)`.
 func (cg CGroups) MemoryQuota() (int64, bool, error) {
-	cpuCGroup, exists := cg[_cgroupSubsysMemory]
+	if memCGroup, exists := cg[_cgroupSubsysMemory]; exists {
+		memLimitBytes, err := memCGroup.readInt(_cgroupMemoryLimitBytes)
+		if defined := memLimitBytes > 0; err != nil || !defined {
+			return -1, defined, err
+		}
+		return int64(memLimitBytes), true, nil
+	}
+
+	unifiedCGroup, exists := cg[_cgroupSubsysUnified]
 	if !exists {
 		return -1, false, nil
 	}
 
-	memLimitBytes, err := cpuCGroup.readInt(_cgroupMemoryLimitBytes)
+	text, err := unifiedCGroup.readFirstLine(_cgroupV2MemoryMax)
+	if os.IsNotExist(err) {
+		// The root CGroup and CGroups without the memory controller enabled
+		// have no memory limit.
+		return -1, false, nil
+	}
+	if err != nil || text == _cgroupV2Unlimited {
+		return -1, false, err
+	}
+
+	memLimitBytes, err := strconv.ParseInt(text, 10, 64)
 	if defined := memLimitBytes > 0; err != nil || !defined {
 		return -1, defined, err
 	}
-	return int64(memLimitBytes), true, nil
+	return memLimitBytes, true, nil
 }
@@ -43,6 +43,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewCGroups(t *testing.T) {
@@ -70,6 +71,41 @@ func TestNewCGroups(t *testing.T) {
 	}
 }
 
+func TestNewCGroupsV2(t *testing.T) {
+	testTable := []struct {
+		name  string
+		paths map[string]string
+	}{
+		{
+			name:  "cgroups-v2",
+			paths: map[string]string{_cgroupSubsysUnified: "/sys/fs/cgroup/pod1"},
+		},
+		{
+			name: "cgroups-hybrid",
+			paths: map[string]string{
+				_cgroupSubsysCPUSet:  "/sys/fs/cgroup/cpuset",
+				_cgroupSubsysMemory:  "/sys/fs/cgroup/memory/large",
+				_cgroupSubsysUnified: "/sys/fs/cgroup/unified/docker/large",
+			},
+		},
+	}
+
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			cgroups, err := NewCGroups(
+				filepath.Join(testDataProcPath, tt.name, "mountinfo"),
+				filepath.Join(testDataProcPath, tt.name, "cgroup"))
+			require.NoError(t, err)
+			assert.Equal(t, len(tt.paths), len(cgroups))
+			for subsys, path := range tt.paths {
+				cgroup, exists := cgroups[subsys]
+				require.True(t, exists, "%q expected to present in `cgroups`", subsys)
+				assert.Equal(t, path, cgroup.path)
+			}
+		})
+	}
+}
+
 func TestNewCGroupsWithErrors(t *testing.T) {
 	testTable := []struct {
 		mountInfoPath string
@@ -135,3 +171,78 @@ func TestCGroupsCPUQuota(t *testing.T) {
 		}
 	}
 }
+
+func TestCGroupsMemoryQuota(t *testing.T) {
+	testTable := []struct {
+		name            string
+		subsys          string
+		expectedQuota   int64
+		expectedDefined bool
+		shouldHaveError bool
+	}{
+		{
+			name:            "memory",
+			subsys:          _cgroupSubsysMemory,
+			expectedQuota:   1073741824,
+			expectedDefined: true,
+		},
+		{
+			name:            "undefined",
+			subsys:          _cgroupSubsysMemory,
+			expectedQuota:   -1,
+			expectedDefined: false,
+		},
+		{
+			name:            "memory-v2",
+			subsys:          _cgroupSubsysUnified,
+			expectedQuota:   536870912,
+			expectedDefined: true,
+		},
+		{
+			name:            "memory-v2-unlimited",
+			subsys:          _cgroupSubsysUnified,
+			expectedQuota:   -1,
+			expectedDefined: false,
+		},
+		{
+			name:            "absent",
+			subsys:          _cgroupSubsysUnified,
+			expectedQuota:   -1,
+			expectedDefined: false,
+		},
+		{
+			name:            "memory-v2-invalid",
+			subsys:          _cgroupSubsysUnified,
+			expectedQuota:   -1,
+			expectedDefined: false,
+			shouldHaveError: true,
+		},
+	}
+
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			cgroups := CGroups{tt.subsys: NewCGroup(filepath.Join(testDataCGroupsPath, tt.name))}
+
+			quota, defined, err := cgroups.MemoryQuota()
+			assert.Equal(t, tt.expectedQuota, quota)
+			assert.Equal(t, tt.expectedDefined, defined)
+			if tt.shouldHaveError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCGroupsMemoryQuotaPrefersV1(t *testing.T) {
+	cgroups := CGroups{
+		_cgroupSubsysMemory:  NewCGroup(filepath.Join(testDataCGroupsPath, "memory")),
+		_cgroupSubsysUnified: NewCGroup(filepath.Join(testDataCGroupsPath, "memory-v2")),
+	}
+
+	quota, defined, err := cgroups.MemoryQuota()
+	require.NoError(t, err)
+	assert.True(t, defined)
+	assert.Equal(t, int64(1073741824), quota)
+}
@@ -16,18 +16,42 @@
 
 package iruntime
 
-import "go.opentelemetry.io/collector/processor/memorylimiter/internal/cgroups"
+import (
+	"github.com/shirou/gopsutil/mem"
+
+	"go.opentelemetry.io/collector/processor/memorylimiter/internal/cgroups"
+)
+
+var (
+	newCGroups      = cgroups.NewCGroupsForCurrentProcess
+	hostTotalMemory = func() (uint64, error) {
+		vm, err := mem.VirtualMemory()
+		if err != nil {
+			return 0, err
+		}
+		return vm.Total, nil
+	}
+)
 
 // TotalMemory returns total available memory.
-// This implementation is meant for linux and uses cgroups to determine available memory.
+// This implementation is meant for linux and uses cgroups (v1 or v2) to determine available memory.
+// If no memory limit is set in cgroups, or the limit exceeds the host memory, the total host
+// memory is returned.
 func TotalMemory() (int64, error) {
-	cgroups, err := cgroups.NewCGroupsForCurrentProcess()
+	hostMemory, err := hostTotalMemory()
+	if err != nil {
+		return 0, err
+	}
+	cgroups, err := newCGroups()
 	if err != nil {
 		return 0, err
 	}
 	memoryQuota, defined, err := cgroups.MemoryQuota()
-	if err != nil || !defined {
+	if err != nil {
 		return 0, err
 	}
+	if !defined || uint64(memoryQuota) > hostMemory {
+		return int64(hostMemory), nil
+	}
 	return memoryQuota, nil
 }
@@ -17,10 +17,15 @@
 package iruntime
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/processor/memorylimiter/internal/cgroups"
 )
 
 func TestTotalMemory(t *testing.T) {
@@ -28,3 +33,45 @@ func TestTotalMemory(t *testing.T) {
 	require.NoError(t, err)
 	assert.True(t, totalMemory > 0)
 }
+
+func TestTotalMemoryCGroups(t *testing.T) {
+	const hostMemory = 4 << 30
+	testTable := []struct {
+		name     string
+		param    string
+		value    string
+		expected int64
+	}{
+		{name: "v1", param: "memory.limit_in_bytes", value: "1073741824", expected: 1 << 30},
+		{name: "v1 unlimited", param: "memory.limit_in_bytes", value: "9223372036854771712", expected: hostMemory},
+		{name: "v2", param: "memory.max", value: "536870912", expected: 512 << 20},
+		{name: "v2 unlimited", param: "memory.max", value: "max", expected: hostMemory},
+	}
+
+	defer func(fn func() (cgroups.CGroups, error), hostFn func() (uint64, error)) {
+		newCGroups = fn
+		hostTotalMemory = hostFn
+	}(newCGroups, hostTotalMemory)
+	hostTotalMemory = func() (uint64, error) { return hostMemory, nil }
+
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "cgroup")
+			require.NoError(t, err)
+			defer os.RemoveAll(dir)
+			require.NoError(t, ioutil.WriteFile(filepath.Join(dir, tt.param), []byte(tt.value+"\n"), 0600))
+
+			subsys := "memory"
+			if tt.param == "memory.max" {
+				subsys = ""
+			}
+			newCGroups = func() (cgroups.CGroups, error) {
+				return cgroups.CGroups{subsys: cgroups.NewCGroup(dir)}, nil
+			}
+
+			totalMemory, err := TotalMemory()
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, totalMemory)
+		})
+	}
+}
@@ -26,10 +26,12 @@ import (
 	"go.uber.org/zap"
 
 	"go.opentelemetry.io/collector/config/configtelemetry"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/obsreport"
 	"go.opentelemetry.io/collector/processor"
 	"go.opentelemetry.io/collector/processor/memorylimiter/internal/iruntime"
+	"go.opentelemetry.io/collector/processor/memorylimiter/memorystate"
 )
 
 const (
@@ -37,8 +39,13 @@ const (
 )
 
 var (
-	// errForcedDrop will be returned to callers of ConsumeTraceData to indicate
-	// that data is being dropped due to high memory usage.
+	// errDataRefused will be returned to callers of ConsumeTraceData to indicate
+	// that data is being refused due to high memory usage, and can be retried later.
+	errDataRefused = errors.New("data refused due to high memory usage")
+
+	// errForcedDrop will be returned, as a permanent error, to callers of
+	// ConsumeTraceData to indicate that data is being dropped due to memory usage
+	// above the hard limit.
 	errForcedDrop = errors.New("data dropped due to high memory usage")
 
 	// Construction errors
@@ -66,8 +73,10 @@ type memoryLimiter struct {
 	memCheckWait time.Duration
 	ballastSize  uint64
 
-	// forceDrop is used atomically to indicate when data should be dropped.
-	forceDrop int64
+	// level is the memorystate.Level measured last, it is used atomically.
+	level int32
+	// unregister removes the memory limiter from the memorystate providers.
+	unregister func()
 
 	ticker *time.Ticker
 
@@ -122,6 +131,7 @@ func newMemoryLimiter(logger *zap.Logger, cfg *Config) (*memoryLimiter, error) {
 	}
 
 	ml.startMonitoring()
+	ml.unregister = memorystate.Register(ml)
 
 	return ml, nil
 }
@@ -145,18 +155,17 @@ func getMemUsageChecker(cfg *Config, logger *zap.Logger) (*memUsageChecker, erro
 
 func (ml *memoryLimiter) shutdown(context.Context) error {
 	ml.ticker.Stop()
+	if ml.unregister != nil {
+		ml.unregister()
+	}
 	return nil
 }
 
 // ProcessTraces implements the TProcessor interface
 func (ml *memoryLimiter) ProcessTraces(ctx context.Context, td pdata.Traces) (pdata.Traces, error) {
 	numSpans := td.SpanCount()
-	if ml.forcingDrop() {
-		stats.Record(
-			ctx,
-			processor.StatDroppedSpanCount.M(int64(numSpans)),
-			processor.StatTraceBatchesDroppedCount.M(1))
-
+	switch ml.MemoryLevel() {
+	case memorystate.SoftLimited:
 		// TODO: actually to be 100% sure that this is "refused" and not "dropped"
 		// 	it is necessary to check the pipeline to see if this is directly connected
 		// 	to a receiver (ie.: a receiver is on the call stack). For now it
@@ -164,7 +173,15 @@ func (ml *memoryLimiter) ProcessTraces(ctx context.Context, td pdata.Traces) (pd
 		// 	callstack.
 		ml.obsrep.TracesRefused(ctx, numSpans)
 
-		return td, errForcedDrop
+		return td, errDataRefused
+	case memorystate.HardLimited:
+		stats.Record(
+			ctx,
+			processor.StatDroppedSpanCount.M(int64(numSpans)),
+			processor.StatTraceBatchesDroppedCount.M(1))
+		ml.obsrep.TracesDropped(ctx, numSpans)
+
+		return td, consumererror.Permanent(errForcedDrop)
 	}
 
 	// Even if the next consumer returns error record the data as accepted by
@@ -176,7 +193,8 @@ func (ml *memoryLimiter) ProcessTraces(ctx context.Context, td pdata.Traces) (pd
 // ProcessMetrics implements the MProcessor interface
 func (ml *memoryLimiter) ProcessMetrics(ctx context.Context, md pdata.Metrics) (pdata.Metrics, error) {
 	_, numDataPoints := md.MetricAndDataPointCount()
-	if ml.forcingDrop() {
+	switch ml.MemoryLevel() {
+	case memorystate.SoftLimited:
 		// TODO: actually to be 100% sure that this is "refused" and not "dropped"
 		// 	it is necessary to check the pipeline to see if this is directly connected
 		// 	to a receiver (ie.: a receiver is on the call stack). For now it
@@ -184,7 +202,11 @@ func (ml *memoryLimiter) ProcessMetrics(ctx context.Context, md pdata.Metrics) (
 		// 	callstack.
 		ml.obsrep.MetricsRefused(ctx, numDataPoints)
 
-		return md, errForcedDrop
+		return md, errDataRefused
+	case memorystate.HardLimited:
+		ml.obsrep.MetricsDropped(ctx, numDataPoints)
+
+		return md, consumererror.Permanent(errForcedDrop)
 	}
 
 	// Even if the next consumer returns error record the data as accepted by
@@ -196,7 +218,8 @@ func (ml *memoryLimiter) ProcessMetrics(ctx context.Context, md pdata.Metrics) (
 // ProcessLogs implements the LProcessor interface
 func (ml *memoryLimiter) ProcessLogs(ctx context.Context, ld pdata.Logs) (pdata.Logs, error) {
 	numRecords := ld.LogRecordCount()
-	if ml.forcingDrop() {
+	switch ml.MemoryLevel() {
+	case memorystate.SoftLimited:
 		// TODO: actually to be 100% sure that this is "refused" and not "dropped"
 		// 	it is necessary to check the pipeline to see if this is directly connected
 		// 	to a receiver (ie.: a receiver is on the call stack). For now it
@@ -204,7 +227,11 @@ func (ml *memoryLimiter) ProcessLogs(ctx context.Context, ld pdata.Logs) (pdata.
 		// 	callstack.
 		ml.obsrep.LogsRefused(ctx, numRecords)
 
-		return ld, errForcedDrop
+		return ld, errDataRefused
+	case memorystate.HardLimited:
+		ml.obsrep.LogsDropped(ctx, numRecords)
+
+		return ld, consumererror.Permanent(errForcedDrop)
 	}
 
 	// Even if the next consumer returns error record the data as accepted by
@@ -240,17 +267,14 @@ func (ml *memoryLimiter) startMonitoring() {
 	}()
 }
 
-// forcingDrop indicates when memory resources need to be released.
-func (ml *memoryLimiter) forcingDrop() bool {
-	return atomic.LoadInt64(&ml.forceDrop) != 0
+// MemoryLevel implements memorystate.Provider, it indicates when memory
+// resources need to be released.
+func (ml *memoryLimiter) MemoryLevel() memorystate.Level {
+	return memorystate.Level(atomic.LoadInt32(&ml.level))
 }
 
-func (ml *memoryLimiter) setForcingDrop(b bool) {
-	var i int64
-	if b {
-		i = 1
-	}
-	atomic.StoreInt64(&ml.forceDrop, i)
+func (ml *memoryLimiter) setMemoryLevel(level memorystate.Level) {
+	atomic.StoreInt32(&ml.level, int32(level))
 }
 
 func memstatToZapField(ms *runtime.MemStats) zap.Field {
@@ -275,33 +299,36 @@ func (ml *memoryLimiter) checkMemLimits() {
 		ms = ml.doGCandReadMemStats()
 	}
 
-	// Remember current dropping state.
-	wasForcingDrop := ml.forcingDrop()
+	// Remember current level.
+	prevLevel := ml.MemoryLevel()
 
 	// Check if the memory usage is above the soft limit.
-	mustForceDrop := ml.usageChecker.aboveSoftLimit(ms)
-
-	if wasForcingDrop && !mustForceDrop {
-		// Was previously dropping but enough memory is available now, no need to limit.
-		ml.logger.Info("Memory usage back within limits. Resuming normal operation.", memstatToZapField(ms))
-	}
+	level := ml.usageChecker.level(ms)
 
-	if !wasForcingDrop && mustForceDrop {
+	if prevLevel == memorystate.Normal && level != memorystate.Normal {
 		// We are above soft limit, do a GC if it wasn't done recently and see if
 		// it brings memory usage below the soft limit.
 		if time.Since(ml.lastGCDone) > minGCIntervalWhenSoftLimited {
 			ml.logger.Info("Memory usage is above soft limit. Forcing a GC.", memstatToZapField(ms))
 			ms = ml.doGCandReadMemStats()
 			// Check the limit again to see if GC helped.
-			mustForceDrop = ml.usageChecker.aboveSoftLimit(ms)
+			level = ml.usageChecker.level(ms)
 		}
+	}
 
-		if mustForceDrop {
-			ml.logger.Warn("Memory usage is above soft limit. Dropping data.", memstatToZapField(ms))
+	if level != prevLevel {
+		switch level {
+		case memorystate.Normal:
+			// Was previously limiting but enough memory is available now, no need to limit.
+			ml.logger.Info("Memory usage back within limits. Resuming normal operation.", memstatToZapField(ms))
+		case memorystate.SoftLimited:
+			ml.logger.Warn("Memory usage is above soft limit. Refusing data.", memstatToZapField(ms))
+		case memorystate.HardLimited:
+			ml.logger.Warn("Memory usage is above hard limit. Dropping data.", memstatToZapField(ms))
 		}
 	}
 
-	ml.setForcingDrop(mustForceDrop)
+	ml.setMemoryLevel(level)
 }
 
 type memUsageChecker struct {
@@ -317,6 +344,17 @@ func (d memUsageChecker) aboveHardLimit(ms *runtime.MemStats) bool {
 	return ms.Alloc >= d.memAllocLimit
 }
 
+// level returns the memory usage level for the given memory stats.
+func (d memUsageChecker) level(ms *runtime.MemStats) memorystate.Level {
+	switch {
+	case d.aboveHardLimit(ms):
+		return memorystate.HardLimited
+	case d.aboveSoftLimit(ms):
+		return memorystate.SoftLimited
+	}
+	return memorystate.Normal
+}
+
 func newFixedMemUsageChecker(memAllocLimit, memSpikeLimit uint64) (*memUsageChecker, error) {
 	if memSpikeLimit >= memAllocLimit {
 		return nil, errMemSpikeLimitOutOfRange
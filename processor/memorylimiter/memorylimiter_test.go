@@ -27,10 +27,12 @@ import (
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/config/configtelemetry"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/obsreport"
 	"go.opentelemetry.io/collector/processor/memorylimiter/internal/iruntime"
+	"go.opentelemetry.io/collector/processor/memorylimiter/memorystate"
 	"go.opentelemetry.io/collector/processor/processorhelper"
 )
 
@@ -93,7 +95,11 @@ func TestNew(t *testing.T) {
 				return
 			}
 			if got != nil {
+				assert.Equal(t, memorystate.Normal, memorystate.Current())
+				got.setMemoryLevel(memorystate.SoftLimited)
+				assert.Equal(t, memorystate.SoftLimited, memorystate.Current())
 				assert.NoError(t, got.shutdown(context.Background()))
+				assert.Equal(t, memorystate.Normal, memorystate.Current())
 			}
 		})
 	}
@@ -137,7 +143,8 @@ func TestMetricsMemoryPressureResponse(t *testing.T) {
 	// Above memAllocLimit.
 	currentMemAlloc = 1800
 	ml.checkMemLimits()
-	assert.Equal(t, errForcedDrop, mp.ConsumeMetrics(ctx, md))
+	assert.Equal(t, memorystate.HardLimited, ml.MemoryLevel())
+	assert.Equal(t, consumererror.Permanent(errForcedDrop), mp.ConsumeMetrics(ctx, md))
 
 	// Check ballast effect
 	ml.ballastSize = 1000
@@ -150,7 +157,7 @@ func TestMetricsMemoryPressureResponse(t *testing.T) {
 	// Above memAllocLimit even accountiing for ballast.
 	currentMemAlloc = 1800 + ml.ballastSize
 	ml.checkMemLimits()
-	assert.Equal(t, errForcedDrop, mp.ConsumeMetrics(ctx, md))
+	assert.Equal(t, consumererror.Permanent(errForcedDrop), mp.ConsumeMetrics(ctx, md))
 
 	// Restore ballast to default.
 	ml.ballastSize = 0
@@ -166,7 +173,18 @@ func TestMetricsMemoryPressureResponse(t *testing.T) {
 	// Above memSpikeLimit.
 	currentMemAlloc = 550
 	ml.checkMemLimits()
-	assert.Equal(t, errForcedDrop, mp.ConsumeMetrics(ctx, md))
+	assert.Equal(t, memorystate.SoftLimited, ml.MemoryLevel())
+	assert.Equal(t, errDataRefused, mp.ConsumeMetrics(ctx, md))
+
+	// Above memAllocLimit, memory is freed by the GC.
+	currentMemAlloc = 1800
+	ml.readMemStatsFn = func(ms *runtime.MemStats) {
+		ms.Alloc = currentMemAlloc
+		currentMemAlloc = 550
+	}
+	ml.checkMemLimits()
+	assert.Equal(t, memorystate.SoftLimited, ml.MemoryLevel())
+	assert.Equal(t, errDataRefused, mp.ConsumeMetrics(ctx, md))
 
 }
 
@@ -208,7 +226,8 @@ func TestTraceMemoryPressureResponse(t *testing.T) {
 	// Above memAllocLimit.
 	currentMemAlloc = 1800
 	ml.checkMemLimits()
-	assert.Equal(t, errForcedDrop, tp.ConsumeTraces(ctx, td))
+	assert.Equal(t, memorystate.HardLimited, ml.MemoryLevel())
+	assert.Equal(t, consumererror.Permanent(errForcedDrop), tp.ConsumeTraces(ctx, td))
 
 	// Check ballast effect
 	ml.ballastSize = 1000
@@ -221,7 +240,7 @@ func TestTraceMemoryPressureResponse(t *testing.T) {
 	// Above memAllocLimit even accountiing for ballast.
 	currentMemAlloc = 1800 + ml.ballastSize
 	ml.checkMemLimits()
-	assert.Equal(t, errForcedDrop, tp.ConsumeTraces(ctx, td))
+	assert.Equal(t, consumererror.Permanent(errForcedDrop), tp.ConsumeTraces(ctx, td))
 
 	// Restore ballast to default.
 	ml.ballastSize = 0
@@ -237,7 +256,18 @@ func TestTraceMemoryPressureResponse(t *testing.T) {
 	// Above memSpikeLimit.
 	currentMemAlloc = 550
 	ml.checkMemLimits()
-	assert.Equal(t, errForcedDrop, tp.ConsumeTraces(ctx, td))
+	assert.Equal(t, memorystate.SoftLimited, ml.MemoryLevel())
+	assert.Equal(t, errDataRefused, tp.ConsumeTraces(ctx, td))
+
+	// Above memAllocLimit, memory is freed by the GC.
+	currentMemAlloc = 1800
+	ml.readMemStatsFn = func(ms *runtime.MemStats) {
+		ms.Alloc = currentMemAlloc
+		currentMemAlloc = 550
+	}
+	ml.checkMemLimits()
+	assert.Equal(t, memorystate.SoftLimited, ml.MemoryLevel())
+	assert.Equal(t, errDataRefused, tp.ConsumeTraces(ctx, td))
 
 }
 
@@ -279,7 +309,8 @@ func TestLogMemoryPressureResponse(t *testing.T) {
 	// Above memAllocLimit.
 	currentMemAlloc = 1800
 	ml.checkMemLimits()
-	assert.Equal(t, errForcedDrop, lp.ConsumeLogs(ctx, ld))
+	assert.Equal(t, memorystate.HardLimited, ml.MemoryLevel())
+	assert.Equal(t, consumererror.Permanent(errForcedDrop), lp.ConsumeLogs(ctx, ld))
 
 	// Check ballast effect
 	ml.ballastSize = 1000
@@ -292,7 +323,7 @@ func TestLogMemoryPressureResponse(t *testing.T) {
 	// Above memAllocLimit even accountiing for ballast.
 	currentMemAlloc = 1800 + ml.ballastSize
 	ml.checkMemLimits()
-	assert.Equal(t, errForcedDrop, lp.ConsumeLogs(ctx, ld))
+	assert.Equal(t, consumererror.Permanent(errForcedDrop), lp.ConsumeLogs(ctx, ld))
 
 	// Restore ballast to default.
 	ml.ballastSize = 0
@@ -308,7 +339,18 @@ func TestLogMemoryPressureResponse(t *testing.T) {
 	// Above memSpikeLimit.
 	currentMemAlloc = 550
 	ml.checkMemLimits()
-	assert.Equal(t, errForcedDrop, lp.ConsumeLogs(ctx, ld))
+	assert.Equal(t, memorystate.SoftLimited, ml.MemoryLevel())
+	assert.Equal(t, errDataRefused, lp.ConsumeLogs(ctx, ld))
+
+	// Above memAllocLimit, memory is freed by the GC.
+	currentMemAlloc = 1800
+	ml.readMemStatsFn = func(ms *runtime.MemStats) {
+		ms.Alloc = currentMemAlloc
+		currentMemAlloc = 550
+	}
+	ml.checkMemLimits()
+	assert.Equal(t, memorystate.SoftLimited, ml.MemoryLevel())
+	assert.Equal(t, errDataRefused, lp.ConsumeLogs(ctx, ld))
 }
 
 func TestGetDecision(t *testing.T) {
@@ -408,3 +450,14 @@ func TestDropDecision(t *testing.T) {
 		})
 	}
 }
+
+func TestUsageLevel(t *testing.T) {
+	usageChecker := memUsageChecker{
+		memAllocLimit: 1000,
+		memSpikeLimit: 200,
+	}
+	assert.Equal(t, memorystate.Normal, usageChecker.level(&runtime.MemStats{Alloc: 799}))
+	assert.Equal(t, memorystate.SoftLimited, usageChecker.level(&runtime.MemStats{Alloc: 800}))
+	assert.Equal(t, memorystate.SoftLimited, usageChecker.level(&runtime.MemStats{Alloc: 999}))
+	assert.Equal(t, memorystate.HardLimited, usageChecker.level(&runtime.MemStats{Alloc: 1000}))
+}
@@ -0,0 +1,86 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package memorystate exposes the memory usage level measured by the memory
+// limiter processors, so that receivers can refuse data before decoding it
+// while the collector is short of memory.
+package memorystate
+
+import (
+	"sync"
+)
+
+// Level is a memory usage level.
+type Level int32
+
+const (
+	// Normal means that the memory usage is below the soft limit, data is accepted.
+	Normal Level = iota
+	// SoftLimited means that the memory usage is above the soft limit, data is
+	// refused with retryable errors so that the clients apply backpressure.
+	SoftLimited
+	// HardLimited means that the memory usage is still above the hard limit after
+	// a garbage collection, data is dropped.
+	HardLimited
+)
+
+// String returns the name of the level.
+func (l Level) String() string {
+	switch l {
+	case Normal:
+		return "normal"
+	case SoftLimited:
+		return "soft_limited"
+	case HardLimited:
+		return "hard_limited"
+	}
+	return "unknown"
+}
+
+// Provider reports the memory usage level it measured last.
+type Provider interface {
+	MemoryLevel() Level
+}
+
+var (
+	mu        sync.RWMutex
+	providers = map[Provider]struct{}{}
+)
+
+// Register adds the given provider to the ones queried by Current, and returns
+// the function removing it.
+func Register(p Provider) func() {
+	mu.Lock()
+	providers[p] = struct{}{}
+	mu.Unlock()
+	return func() {
+		mu.Lock()
+		delete(providers, p)
+		mu.Unlock()
+	}
+}
+
+// Current returns the highest level reported by the registered providers, or
+// Normal when none is registered.
+func Current() Level {
+	mu.RLock()
+	defer mu.RUnlock()
+	level := Normal
+	for p := range providers {
+		if l := p.MemoryLevel(); l > level {
+			level = l
+		}
+	}
+	return level
+}
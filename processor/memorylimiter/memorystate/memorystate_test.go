@@ -0,0 +1,54 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memorystate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fixedProvider Level
+
+func (p *fixedProvider) MemoryLevel() Level {
+	return Level(*p)
+}
+
+func TestCurrent(t *testing.T) {
+	assert.Equal(t, Normal, Current())
+
+	soft := fixedProvider(SoftLimited)
+	unregisterSoft := Register(&soft)
+	assert.Equal(t, SoftLimited, Current())
+
+	normal := fixedProvider(Normal)
+	unregisterNormal := Register(&normal)
+	assert.Equal(t, SoftLimited, Current())
+
+	normal = fixedProvider(HardLimited)
+	assert.Equal(t, HardLimited, Current())
+
+	unregisterNormal()
+	assert.Equal(t, SoftLimited, Current())
+	unregisterSoft()
+	assert.Equal(t, Normal, Current())
+}
+
+func TestLevelString(t *testing.T) {
+	assert.Equal(t, "normal", Normal.String())
+	assert.Equal(t, "soft_limited", SoftLimited.String())
+	assert.Equal(t, "hard_limited", HardLimited.String())
+	assert.Equal(t, "unknown", Level(-1).String())
+}
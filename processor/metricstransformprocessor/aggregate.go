@@ -0,0 +1,295 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metricstransformprocessor
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// dataPointSlice gives a uniform access to the data points of the metrics of
+// the different types.
+type dataPointSlice interface {
+	Len() int
+	labels(i int) pdata.StringMap
+	// key returns what, besides the labels, must be identical for the data
+	// points to be aggregated.
+	key(i int) string
+	moveTo(from, to int)
+	resize(newLen int)
+	// aggregate aggregates the data points at indexes into the first one.
+	aggregate(indexes []int, aggregationType AggregationType)
+}
+
+// newDataPointSlice returns the data points of the metric, or nil if the
+// metric has no data.
+func newDataPointSlice(metric pdata.Metric) dataPointSlice {
+	switch metric.DataType() {
+	case pdata.MetricDataTypeIntGauge:
+		return intDataPoints{metric.IntGauge().DataPoints()}
+	case pdata.MetricDataTypeDoubleGauge:
+		return doubleDataPoints{metric.DoubleGauge().DataPoints()}
+	case pdata.MetricDataTypeIntSum:
+		return intDataPoints{metric.IntSum().DataPoints()}
+	case pdata.MetricDataTypeDoubleSum:
+		return doubleDataPoints{metric.DoubleSum().DataPoints()}
+	case pdata.MetricDataTypeIntHistogram:
+		return intHistogramDataPoints{metric.IntHistogram().DataPoints()}
+	case pdata.MetricDataTypeDoubleHistogram:
+		return doubleHistogramDataPoints{metric.DoubleHistogram().DataPoints()}
+	case pdata.MetricDataTypeDoubleSummary:
+		return summaryDataPoints{metric.DoubleSummary().DataPoints()}
+	default:
+		return nil
+	}
+}
+
+// filterDataPoints removes the data points whose labels are not kept.
+func filterDataPoints(metric pdata.Metric, keep func(labels pdata.StringMap) bool) {
+	dps := newDataPointSlice(metric)
+	if dps == nil {
+		return
+	}
+	kept := 0
+	for i := 0; i < dps.Len(); i++ {
+		if !keep(dps.labels(i)) {
+			continue
+		}
+		if kept != i {
+			dps.moveTo(i, kept)
+		}
+		kept++
+	}
+	dps.resize(kept)
+}
+
+// aggregate returns an operation keeping only the labels for which keep
+// returns true and aggregating the data points whose labels become
+// identical. The summaries are not aggregated and the histograms are always
+// aggregated with their sum.
+func aggregate(keep func(key string) bool, aggregationType AggregationType) operation {
+	return func(metric pdata.Metric) {
+		if metric.DataType() == pdata.MetricDataTypeDoubleSummary {
+			return
+		}
+		dps := newDataPointSlice(metric)
+		if dps == nil {
+			return
+		}
+		groups := groupDataPoints(dps, keep)
+		// The groups are sorted by their first data point, the data point of
+		// a group is moved to a data point of a previous group.
+		for j, group := range groups {
+			dps.aggregate(group, aggregationType)
+			if group[0] != j {
+				dps.moveTo(group[0], j)
+			}
+			removeLabels(dps.labels(j), keep)
+		}
+		dps.resize(len(groups))
+	}
+}
+
+// groupDataPoints returns the indexes of the data points to aggregate
+// together, in the order of their first data point.
+func groupDataPoints(dps dataPointSlice, keep func(key string) bool) [][]int {
+	var groups [][]int
+	groupByKey := make(map[string]int)
+	for i := 0; i < dps.Len(); i++ {
+		key := aggregationKey(dps.labels(i), keep) + dps.key(i)
+		g, ok := groupByKey[key]
+		if !ok {
+			g = len(groups)
+			groupByKey[key] = g
+			groups = append(groups, nil)
+		}
+		groups[g] = append(groups[g], i)
+	}
+	return groups
+}
+
+func aggregationKey(labels pdata.StringMap, keep func(key string) bool) string {
+	var kept []string
+	labels.ForEach(func(k string, v string) {
+		if keep(k) {
+			kept = append(kept, k+"="+v)
+		}
+	})
+	sort.Strings(kept)
+	return strings.Join(kept, "\x00") + "\x01"
+}
+
+func removeLabels(labels pdata.StringMap, keep func(key string) bool) {
+	var removed []string
+	labels.ForEach(func(k string, _ string) {
+		if !keep(k) {
+			removed = append(removed, k)
+		}
+	})
+	for _, k := range removed {
+		labels.Delete(k)
+	}
+}
+
+func timestampKey(ts pdata.Timestamp) string {
+	return fmt.Sprint(uint64(ts))
+}
+
+func boundsKey(ts pdata.Timestamp, bounds []float64) string {
+	return fmt.Sprint(uint64(ts), bounds)
+}
+
+func minStartTime(first pdata.Timestamp, other pdata.Timestamp) pdata.Timestamp {
+	if other < first {
+		return other
+	}
+	return first
+}
+
+type intDataPoints struct {
+	pdata.IntDataPointSlice
+}
+
+func (dps intDataPoints) labels(i int) pdata.StringMap { return dps.At(i).LabelsMap() }
+func (dps intDataPoints) key(i int) string             { return timestampKey(dps.At(i).Timestamp()) }
+func (dps intDataPoints) moveTo(from, to int)          { dps.At(from).CopyTo(dps.At(to)) }
+func (dps intDataPoints) resize(newLen int)            { dps.Resize(newLen) }
+
+func (dps intDataPoints) aggregate(indexes []int, aggregationType AggregationType) {
+	dest := dps.At(indexes[0])
+	value := dest.Value()
+	for _, i := range indexes[1:] {
+		dp := dps.At(i)
+		dest.SetStartTime(minStartTime(dest.StartTime(), dp.StartTime()))
+		switch aggregationType {
+		case Sum, Mean:
+			value += dp.Value()
+		case Min:
+			if dp.Value() < value {
+				value = dp.Value()
+			}
+		case Max:
+			if dp.Value() > value {
+				value = dp.Value()
+			}
+		}
+	}
+	if aggregationType == Mean {
+		value /= int64(len(indexes))
+	}
+	dest.SetValue(value)
+}
+
+type doubleDataPoints struct {
+	pdata.DoubleDataPointSlice
+}
+
+func (dps doubleDataPoints) labels(i int) pdata.StringMap { return dps.At(i).LabelsMap() }
+func (dps doubleDataPoints) key(i int) string             { return timestampKey(dps.At(i).Timestamp()) }
+func (dps doubleDataPoints) moveTo(from, to int)          { dps.At(from).CopyTo(dps.At(to)) }
+func (dps doubleDataPoints) resize(newLen int)            { dps.Resize(newLen) }
+
+func (dps doubleDataPoints) aggregate(indexes []int, aggregationType AggregationType) {
+	dest := dps.At(indexes[0])
+	value := dest.Value()
+	for _, i := range indexes[1:] {
+		dp := dps.At(i)
+		dest.SetStartTime(minStartTime(dest.StartTime(), dp.StartTime()))
+		switch aggregationType {
+		case Sum, Mean:
+			value += dp.Value()
+		case Min:
+			value = math.Min(value, dp.Value())
+		case Max:
+			value = math.Max(value, dp.Value())
+		}
+	}
+	if aggregationType == Mean {
+		value /= float64(len(indexes))
+	}
+	dest.SetValue(value)
+}
+
+type intHistogramDataPoints struct {
+	pdata.IntHistogramDataPointSlice
+}
+
+func (dps intHistogramDataPoints) labels(i int) pdata.StringMap { return dps.At(i).LabelsMap() }
+func (dps intHistogramDataPoints) moveTo(from, to int)          { dps.At(from).CopyTo(dps.At(to)) }
+func (dps intHistogramDataPoints) resize(newLen int)            { dps.Resize(newLen) }
+
+func (dps intHistogramDataPoints) key(i int) string {
+	return boundsKey(dps.At(i).Timestamp(), dps.At(i).ExplicitBounds())
+}
+
+func (dps intHistogramDataPoints) aggregate(indexes []int, _ AggregationType) {
+	dest := dps.At(indexes[0])
+	bucketCounts := append([]uint64(nil), dest.BucketCounts()...)
+	for _, i := range indexes[1:] {
+		dp := dps.At(i)
+		dest.SetStartTime(minStartTime(dest.StartTime(), dp.StartTime()))
+		dest.SetCount(dest.Count() + dp.Count())
+		dest.SetSum(dest.Sum() + dp.Sum())
+		addBucketCounts(bucketCounts, dp.BucketCounts())
+	}
+	dest.SetBucketCounts(bucketCounts)
+}
+
+type doubleHistogramDataPoints struct {
+	pdata.DoubleHistogramDataPointSlice
+}
+
+func (dps doubleHistogramDataPoints) labels(i int) pdata.StringMap { return dps.At(i).LabelsMap() }
+func (dps doubleHistogramDataPoints) moveTo(from, to int)          { dps.At(from).CopyTo(dps.At(to)) }
+func (dps doubleHistogramDataPoints) resize(newLen int)            { dps.Resize(newLen) }
+
+func (dps doubleHistogramDataPoints) key(i int) string {
+	return boundsKey(dps.At(i).Timestamp(), dps.At(i).ExplicitBounds())
+}
+
+func (dps doubleHistogramDataPoints) aggregate(indexes []int, _ AggregationType) {
+	dest := dps.At(indexes[0])
+	bucketCounts := append([]uint64(nil), dest.BucketCounts()...)
+	for _, i := range indexes[1:] {
+		dp := dps.At(i)
+		dest.SetStartTime(minStartTime(dest.StartTime(), dp.StartTime()))
+		dest.SetCount(dest.Count() + dp.Count())
+		dest.SetSum(dest.Sum() + dp.Sum())
+		addBucketCounts(bucketCounts, dp.BucketCounts())
+	}
+	dest.SetBucketCounts(bucketCounts)
+}
+
+func addBucketCounts(dest []uint64, counts []uint64) {
+	for i := 0; i < len(dest) && i < len(counts); i++ {
+		dest[i] += counts[i]
+	}
+}
+
+// summaryDataPoints only supports filtering, the summaries cannot be
+// aggregated.
+type summaryDataPoints struct {
+	pdata.DoubleSummaryDataPointSlice
+}
+
+func (dps summaryDataPoints) labels(i int) pdata.StringMap     { return dps.At(i).LabelsMap() }
+func (dps summaryDataPoints) key(i int) string                 { return timestampKey(dps.At(i).Timestamp()) }
+func (dps summaryDataPoints) moveTo(from, to int)              { dps.At(from).CopyTo(dps.At(to)) }
+func (dps summaryDataPoints) resize(newLen int)                { dps.Resize(newLen) }
+func (dps summaryDataPoints) aggregate([]int, AggregationType) {}
@@ -0,0 +1,177 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metricstransformprocessor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func cpuPoints() []testPoint {
+	return []testPoint{
+		{labels: map[string]string{"cpu": "0", "state": "user"}, value: 1},
+		{labels: map[string]string{"cpu": "0", "state": "system"}, value: 2},
+		{labels: map[string]string{"cpu": "1", "state": "user"}, value: 3},
+		{labels: map[string]string{"cpu": "1", "state": "system"}, value: 6},
+		{labels: map[string]string{"cpu": "1", "state": "user"}, value: 5, timestamp: 10},
+	}
+}
+
+func TestAggregateLabels(t *testing.T) {
+	tests := []struct {
+		aggregationType AggregationType
+		expected        []float64
+	}{
+		{aggregationType: Sum, expected: []float64{4, 8, 5}},
+		{aggregationType: Mean, expected: []float64{2, 4, 5}},
+		{aggregationType: Min, expected: []float64{1, 2, 5}},
+		{aggregationType: Max, expected: []float64{3, 6, 5}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.aggregationType), func(t *testing.T) {
+			for _, dataType := range []pdata.MetricDataType{pdata.MetricDataTypeIntGauge, pdata.MetricDataTypeDoubleGauge} {
+				metric := newTestMetric("cpu", dataType, cpuPoints()...)
+				applyOperation(t, Operation{Action: AggregateLabels, LabelSet: []string{"state"}, AggregationType: tt.aggregationType}, metric)
+
+				// The data points with different timestamps are not aggregated.
+				assert.Equal(t, []testPoint{
+					{labels: map[string]string{"state": "user"}, value: tt.expected[0]},
+					{labels: map[string]string{"state": "system"}, value: tt.expected[1]},
+					{labels: map[string]string{"state": "user"}, value: tt.expected[2], timestamp: 10},
+				}, testPoints(metric), dataType.String())
+			}
+		})
+	}
+}
+
+func TestDeleteLabel(t *testing.T) {
+	metric := newTestMetric("cpu", pdata.MetricDataTypeIntGauge, cpuPoints()...)
+	applyOperation(t, Operation{Action: DeleteLabel, Label: "state"}, metric)
+
+	assert.Equal(t, []testPoint{
+		{labels: map[string]string{"cpu": "0"}, value: 3},
+		{labels: map[string]string{"cpu": "1"}, value: 9},
+		{labels: map[string]string{"cpu": "1"}, value: 5, timestamp: 10},
+	}, testPoints(metric))
+}
+
+func TestAggregateLabelValues(t *testing.T) {
+	metric := newTestMetric("cpu", pdata.MetricDataTypeDoubleGauge,
+		testPoint{labels: map[string]string{"state": "user"}, value: 1},
+		testPoint{labels: map[string]string{"state": "idle"}, value: 2},
+		testPoint{labels: map[string]string{"state": "system"}, value: 3},
+	)
+	applyOperation(t, Operation{
+		Action:           AggregateLabelValues,
+		Label:            "state",
+		AggregatedValues: []string{"user", "system"},
+		NewValue:         "used",
+		AggregationType:  Max,
+	}, metric)
+
+	assert.Equal(t, []testPoint{
+		{labels: map[string]string{"state": "used"}, value: 3},
+		{labels: map[string]string{"state": "idle"}, value: 2},
+	}, testPoints(metric))
+}
+
+func TestAggregateLabels_StartTime(t *testing.T) {
+	metric := pdata.NewMetric()
+	metric.SetDataType(pdata.MetricDataTypeIntSum)
+	dps := metric.IntSum().DataPoints()
+	dps.Resize(2)
+	dps.At(0).SetStartTime(20)
+	dps.At(0).SetValue(1)
+	dps.At(1).SetStartTime(10)
+	dps.At(1).SetValue(2)
+	dps.At(1).LabelsMap().Insert("cpu", "1")
+	applyOperation(t, Operation{Action: AggregateLabels, AggregationType: Sum}, metric)
+
+	assert.Equal(t, 1, dps.Len())
+	assert.EqualValues(t, 10, dps.At(0).StartTime())
+	assert.EqualValues(t, 3, dps.At(0).Value())
+	assert.Equal(t, 0, dps.At(0).LabelsMap().Len())
+}
+
+func TestAggregateLabels_Histogram(t *testing.T) {
+	metric := pdata.NewMetric()
+	metric.SetDataType(pdata.MetricDataTypeIntHistogram)
+	dps := metric.IntHistogram().DataPoints()
+	dps.Resize(3)
+	for i, bounds := range [][]float64{{1, 2}, {1, 2}, {5}} {
+		dp := dps.At(i)
+		dp.LabelsMap().Insert("cpu", string(rune('0'+i)))
+		dp.SetCount(uint64(len(bounds) + 1))
+		dp.SetSum(int64(i + 1))
+		dp.SetExplicitBounds(bounds)
+		counts := make([]uint64, len(bounds)+1)
+		for j := range counts {
+			counts[j] = 1
+		}
+		dp.SetBucketCounts(counts)
+	}
+	applyOperation(t, Operation{Action: DeleteLabel, Label: "cpu", AggregationType: Max}, metric)
+
+	// The histograms are summed, only when their bounds are identical.
+	assert.Equal(t, 2, dps.Len())
+	assert.EqualValues(t, 6, dps.At(0).Count())
+	assert.EqualValues(t, 3, dps.At(0).Sum())
+	assert.Equal(t, []float64{1, 2}, dps.At(0).ExplicitBounds())
+	assert.Equal(t, []uint64{2, 2, 2}, dps.At(0).BucketCounts())
+	assert.Equal(t, 0, dps.At(0).LabelsMap().Len())
+	assert.EqualValues(t, 2, dps.At(1).Count())
+	assert.Equal(t, []float64{5}, dps.At(1).ExplicitBounds())
+}
+
+func TestAggregateLabels_DoubleHistogram(t *testing.T) {
+	metric := pdata.NewMetric()
+	metric.SetDataType(pdata.MetricDataTypeDoubleHistogram)
+	dps := metric.DoubleHistogram().DataPoints()
+	dps.Resize(2)
+	for i := 0; i < 2; i++ {
+		dps.At(i).SetCount(2)
+		dps.At(i).SetSum(1.5)
+		dps.At(i).SetExplicitBounds([]float64{1})
+		dps.At(i).SetBucketCounts([]uint64{1, 1})
+	}
+	applyOperation(t, Operation{Action: AggregateLabels, AggregationType: Sum}, metric)
+
+	assert.Equal(t, 1, dps.Len())
+	assert.EqualValues(t, 4, dps.At(0).Count())
+	assert.Equal(t, 3.0, dps.At(0).Sum())
+	assert.Equal(t, []uint64{2, 2}, dps.At(0).BucketCounts())
+}
+
+func TestAggregateLabels_SummaryUnchanged(t *testing.T) {
+	metric := pdata.NewMetric()
+	metric.SetDataType(pdata.MetricDataTypeDoubleSummary)
+	dps := metric.DoubleSummary().DataPoints()
+	dps.Resize(2)
+	dps.At(0).LabelsMap().Insert("cpu", "0")
+	dps.At(1).LabelsMap().Insert("cpu", "1")
+	applyOperation(t, Operation{Action: DeleteLabel, Label: "cpu"}, metric)
+
+	assert.Equal(t, 2, dps.Len())
+	assert.Equal(t, 1, dps.At(0).LabelsMap().Len())
+
+	// The summaries can be filtered.
+	applyOperation(t, Operation{Action: DeleteLabelValue, Label: "cpu", LabelValue: "0"}, metric)
+	assert.Equal(t, 1, dps.Len())
+	cpu, _ := dps.At(0).LabelsMap().Get("cpu")
+	assert.Equal(t, "1", cpu)
+}
@@ -0,0 +1,129 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metricstransformprocessor
+
+import (
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/internal/processor/filterset"
+)
+
+// TransformAction is the action applied to the metrics matching a transform.
+type TransformAction string
+
+const (
+	// Update applies the transform to the matching metrics.
+	Update TransformAction = "update"
+	// Insert applies the transform to a copy of the matching metrics, added
+	// after the original metrics.
+	Insert TransformAction = "insert"
+)
+
+// OperationAction is the action of an operation of a transform.
+type OperationAction string
+
+const (
+	// UpdateLabel renames a label and its values.
+	UpdateLabel OperationAction = "update_label"
+	// AddLabel adds a label with a fixed value to all the data points.
+	AddLabel OperationAction = "add_label"
+	// DeleteLabel removes a label, aggregating the data points whose other
+	// labels are identical.
+	DeleteLabel OperationAction = "delete_label"
+	// DeleteLabelValue removes the data points having a label value.
+	DeleteLabelValue OperationAction = "delete_label_value"
+	// AggregateLabels keeps only a set of labels, aggregating the data points
+	// whose remaining labels are identical.
+	AggregateLabels OperationAction = "aggregate_labels"
+	// AggregateLabelValues merges a set of values of a label into a new
+	// value, aggregating the data points whose labels become identical.
+	AggregateLabelValues OperationAction = "aggregate_label_values"
+	// ScaleValue multiplies the values of the data points.
+	ScaleValue OperationAction = "scale_value"
+)
+
+// AggregationType is the function aggregating the values of data points.
+type AggregationType string
+
+const (
+	// Sum aggregates the values with their sum.
+	Sum AggregationType = "sum"
+	// Mean aggregates the values with their mean.
+	Mean AggregationType = "mean"
+	// Min aggregates the values with their minimum.
+	Min AggregationType = "min"
+	// Max aggregates the values with their maximum.
+	Max AggregationType = "max"
+)
+
+// Config has the configuration of the metrics transform processor.
+type Config struct {
+	configmodels.ProcessorSettings `mapstructure:",squash"`
+	// Transforms are the transforms applied, in order, to the metrics.
+	Transforms []Transform `mapstructure:"transforms"`
+}
+
+// Transform describes the transformation of the metrics matching a name.
+type Transform struct {
+	// MetricName is the name, or the regular expression, matching the metrics
+	// to transform.
+	MetricName string `mapstructure:"metric_name"`
+	// MatchType is the way MetricName is matched, strict or regexp. Defaults
+	// to strict.
+	MatchType filterset.MatchType `mapstructure:"match_type"`
+	// Action is the action applied to the matching metrics, update or insert.
+	Action TransformAction `mapstructure:"action"`
+	// NewName is the new name of the metrics. With the regexp match type, it
+	// can refer to the submatches of MetricName, e.g. $1.
+	NewName string `mapstructure:"new_name"`
+	// Operations are the operations applied, in order, to the metrics.
+	Operations []Operation `mapstructure:"operations"`
+}
+
+// Operation describes an operation applied to the data points of a metric,
+// only the fields relevant to its action are used.
+type Operation struct {
+	// Action is the action of the operation.
+	Action OperationAction `mapstructure:"action"`
+	// Label is the label updated, deleted or aggregated.
+	Label string `mapstructure:"label"`
+	// NewLabel is the new name of the label of update_label, or the label
+	// added by add_label.
+	NewLabel string `mapstructure:"new_label"`
+	// NewValue is the value of the label added by add_label, or the value
+	// replacing the aggregated values of aggregate_label_values.
+	NewValue string `mapstructure:"new_value"`
+	// ValueActions are the label values renamed by update_label.
+	ValueActions []ValueAction `mapstructure:"value_actions"`
+	// LabelValue is the label value of the data points removed by
+	// delete_label_value.
+	LabelValue string `mapstructure:"label_value"`
+	// LabelSet is the set of labels kept by aggregate_labels.
+	LabelSet []string `mapstructure:"label_set"`
+	// AggregatedValues are the label values merged by aggregate_label_values.
+	AggregatedValues []string `mapstructure:"aggregated_values"`
+	// AggregationType is the function aggregating the data points. Defaults to
+	// sum for delete_label.
+	AggregationType AggregationType `mapstructure:"aggregation_type"`
+	// Scale is the factor applied to the values by scale_value.
+	Scale float64 `mapstructure:"scale"`
+}
+
+// ValueAction describes the renaming of a label value.
+type ValueAction struct {
+	// Value is the value renamed.
+	Value string `mapstructure:"value"`
+	// NewValue is the new value.
+	NewValue string `mapstructure:"new_value"`
+}
@@ -0,0 +1,108 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metricstransformprocessor
+
+import (
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/config/configtest"
+	"go.opentelemetry.io/collector/internal/processor/filterset"
+)
+
+func TestLoadConfig(t *testing.T) {
+	factories, err := componenttest.NopFactories()
+	assert.NoError(t, err)
+
+	factory := NewFactory()
+	factories.Processors[typeStr] = factory
+	cfg, err := configtest.LoadConfigFile(t, path.Join(".", "testdata", "config.yaml"), factories)
+
+	require.Nil(t, err)
+	require.NotNil(t, cfg)
+
+	p0 := cfg.Processors["metricstransform"]
+	assert.Equal(t, p0, factory.CreateDefaultConfig())
+
+	p1 := cfg.Processors["metricstransform/custom"]
+	assert.Equal(t, p1,
+		&Config{
+			ProcessorSettings: configmodels.ProcessorSettings{
+				TypeVal: "metricstransform",
+				NameVal: "metricstransform/custom",
+			},
+			Transforms: []Transform{
+				{
+					MetricName: "system.cpu.time",
+					Action:     Update,
+					NewName:    "cpu.time",
+					Operations: []Operation{
+						{
+							Action:       UpdateLabel,
+							Label:        "state",
+							NewLabel:     "cpu.state",
+							ValueActions: []ValueAction{{Value: "idle", NewValue: "free"}},
+						},
+						{
+							Action:   AddLabel,
+							NewLabel: "host.kind",
+							NewValue: "vm",
+						},
+						{
+							Action: DeleteLabel,
+							Label:  "cpu",
+						},
+					},
+				},
+				{
+					MetricName: `^system\.(.*)$`,
+					MatchType:  filterset.Regexp,
+					Action:     Insert,
+					NewName:    "host.$1",
+					Operations: []Operation{
+						{
+							Action:          AggregateLabels,
+							LabelSet:        []string{"state"},
+							AggregationType: Max,
+						},
+						{
+							Action:           AggregateLabelValues,
+							Label:            "state",
+							AggregatedValues: []string{"user", "system"},
+							NewValue:         "used",
+							AggregationType:  Sum,
+						},
+						{
+							Action:     DeleteLabelValue,
+							Label:      "state",
+							LabelValue: "steal",
+						},
+						{
+							Action: ScaleValue,
+							Scale:  1000,
+						},
+					},
+				},
+			},
+		})
+
+	_, err = newMetricsTransformProcessor(p1.(*Config))
+	assert.NoError(t, err)
+}
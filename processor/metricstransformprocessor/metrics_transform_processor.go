@@ -0,0 +1,151 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metricstransformprocessor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal/processor/filterset"
+)
+
+type metricsTransformProcessor struct {
+	transforms []*transform
+}
+
+// transform is the compiled version of a Transform.
+type transform struct {
+	metricName string
+	// re is set with the regexp match type.
+	re         *regexp.Regexp
+	action     TransformAction
+	newName    string
+	operations []operation
+}
+
+// operation applies an operation to a metric.
+type operation func(metric pdata.Metric)
+
+func newMetricsTransformProcessor(cfg *Config) (*metricsTransformProcessor, error) {
+	transforms := make([]*transform, 0, len(cfg.Transforms))
+	for _, t := range cfg.Transforms {
+		compiled, err := newTransform(t)
+		if err != nil {
+			return nil, fmt.Errorf("invalid transform of %q: %w", t.MetricName, err)
+		}
+		transforms = append(transforms, compiled)
+	}
+	return &metricsTransformProcessor{transforms: transforms}, nil
+}
+
+func newTransform(cfg Transform) (*transform, error) {
+	if cfg.MetricName == "" {
+		return nil, errors.New("metric_name must be specified")
+	}
+	t := &transform{
+		metricName: cfg.MetricName,
+		action:     cfg.Action,
+		newName:    cfg.NewName,
+	}
+
+	switch cfg.MatchType {
+	case "", filterset.Strict:
+	case filterset.Regexp:
+		re, err := regexp.Compile(cfg.MetricName)
+		if err != nil {
+			return nil, err
+		}
+		t.re = re
+	default:
+		return nil, fmt.Errorf("unsupported match_type %q, valid types are {%s, %s}", cfg.MatchType, filterset.Strict, filterset.Regexp)
+	}
+
+	switch cfg.Action {
+	case Update:
+	case Insert:
+		if cfg.NewName == "" {
+			return nil, errors.New("new_name must be specified for the insert action")
+		}
+	default:
+		return nil, fmt.Errorf("unsupported action %q, valid actions are {%s, %s}", cfg.Action, Update, Insert)
+	}
+
+	for i, op := range cfg.Operations {
+		compiled, err := newOperation(op)
+		if err != nil {
+			return nil, fmt.Errorf("invalid operation at index %d: %w", i, err)
+		}
+		t.operations = append(t.operations, compiled)
+	}
+	return t, nil
+}
+
+// match returns whether the transform applies to the metric name, and the
+// new name of the metric, empty if it is not renamed.
+func (t *transform) match(name string) (string, bool) {
+	if t.re == nil {
+		return t.newName, name == t.metricName
+	}
+	submatches := t.re.FindStringSubmatchIndex(name)
+	if submatches == nil {
+		return "", false
+	}
+	if t.newName == "" {
+		return "", true
+	}
+	return string(t.re.ExpandString(nil, t.newName, name, submatches)), true
+}
+
+// ProcessMetrics applies the transforms, in order, to the metrics.
+func (mtp *metricsTransformProcessor) ProcessMetrics(_ context.Context, md pdata.Metrics) (pdata.Metrics, error) {
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		ilms := rms.At(i).InstrumentationLibraryMetrics()
+		for j := 0; j < ilms.Len(); j++ {
+			metrics := ilms.At(j).Metrics()
+			for _, t := range mtp.transforms {
+				t.apply(metrics)
+			}
+		}
+	}
+	return md, nil
+}
+
+// apply applies the transform to the matching metrics of the slice.
+func (t *transform) apply(metrics pdata.MetricSlice) {
+	numMetrics := metrics.Len()
+	for k := 0; k < numMetrics; k++ {
+		metric := metrics.At(k)
+		newName, ok := t.match(metric.Name())
+		if !ok {
+			continue
+		}
+		if t.action == Insert {
+			metrics.Resize(metrics.Len() + 1)
+			inserted := metrics.At(metrics.Len() - 1)
+			metric.CopyTo(inserted)
+			metric = inserted
+		}
+		if newName != "" {
+			metric.SetName(newName)
+		}
+		for _, op := range t.operations {
+			op(metric)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metricstransformprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal/processor/filterset"
+)
+
+// testPoint describes a data point of an int or double gauge.
+type testPoint struct {
+	labels    map[string]string
+	value     float64
+	timestamp pdata.Timestamp
+}
+
+func newTestMetric(name string, dataType pdata.MetricDataType, points ...testPoint) pdata.Metric {
+	metric := pdata.NewMetric()
+	metric.SetName(name)
+	metric.SetDataType(dataType)
+	switch dataType {
+	case pdata.MetricDataTypeIntGauge:
+		dps := metric.IntGauge().DataPoints()
+		dps.Resize(len(points))
+		for i, p := range points {
+			dps.At(i).LabelsMap().InitFromMap(p.labels)
+			dps.At(i).SetTimestamp(p.timestamp)
+			dps.At(i).SetValue(int64(p.value))
+		}
+	case pdata.MetricDataTypeDoubleGauge:
+		dps := metric.DoubleGauge().DataPoints()
+		dps.Resize(len(points))
+		for i, p := range points {
+			dps.At(i).LabelsMap().InitFromMap(p.labels)
+			dps.At(i).SetTimestamp(p.timestamp)
+			dps.At(i).SetValue(p.value)
+		}
+	}
+	return metric
+}
+
+// testPoints returns the data points of an int or double gauge.
+func testPoints(metric pdata.Metric) []testPoint {
+	var points []testPoint
+	labels := func(sm pdata.StringMap) map[string]string {
+		m := map[string]string{}
+		sm.ForEach(func(k string, v string) { m[k] = v })
+		return m
+	}
+	switch metric.DataType() {
+	case pdata.MetricDataTypeIntGauge:
+		dps := metric.IntGauge().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			points = append(points, testPoint{labels: labels(dps.At(i).LabelsMap()), value: float64(dps.At(i).Value()), timestamp: dps.At(i).Timestamp()})
+		}
+	case pdata.MetricDataTypeDoubleGauge:
+		dps := metric.DoubleGauge().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			points = append(points, testPoint{labels: labels(dps.At(i).LabelsMap()), value: dps.At(i).Value(), timestamp: dps.At(i).Timestamp()})
+		}
+	}
+	return points
+}
+
+func newTestMetrics(metrics ...pdata.Metric) pdata.Metrics {
+	md := pdata.NewMetrics()
+	md.ResourceMetrics().Resize(1)
+	ilms := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics()
+	ilms.Resize(1)
+	for _, m := range metrics {
+		ilms.At(0).Metrics().Append(m)
+	}
+	return md
+}
+
+func processedMetrics(t *testing.T, cfg *Config, metrics ...pdata.Metric) pdata.MetricSlice {
+	mtp, err := newMetricsTransformProcessor(cfg)
+	require.NoError(t, err)
+	md, err := mtp.ProcessMetrics(context.Background(), newTestMetrics(metrics...))
+	require.NoError(t, err)
+	return md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+}
+
+func TestMetricsTransformProcessor_Update(t *testing.T) {
+	metrics := processedMetrics(t,
+		&Config{Transforms: []Transform{{
+			MetricName: "cpu",
+			Action:     Update,
+			NewName:    "host.cpu",
+			Operations: []Operation{{Action: AddLabel, NewLabel: "host", NewValue: "h1"}},
+		}}},
+		newTestMetric("cpu", pdata.MetricDataTypeIntGauge, testPoint{value: 1}),
+		newTestMetric("memory", pdata.MetricDataTypeIntGauge, testPoint{value: 2}),
+	)
+
+	require.Equal(t, 2, metrics.Len())
+	assert.Equal(t, "host.cpu", metrics.At(0).Name())
+	assert.Equal(t, []testPoint{{labels: map[string]string{"host": "h1"}, value: 1}}, testPoints(metrics.At(0)))
+	assert.Equal(t, "memory", metrics.At(1).Name())
+	assert.Equal(t, []testPoint{{labels: map[string]string{}, value: 2}}, testPoints(metrics.At(1)))
+}
+
+func TestMetricsTransformProcessor_Insert(t *testing.T) {
+	metrics := processedMetrics(t,
+		&Config{Transforms: []Transform{{
+			MetricName: "cpu",
+			Action:     Insert,
+			NewName:    "cpu.ms",
+			Operations: []Operation{{Action: ScaleValue, Scale: 1000}},
+		}}},
+		newTestMetric("cpu", pdata.MetricDataTypeDoubleGauge, testPoint{value: 1.5}),
+	)
+
+	require.Equal(t, 2, metrics.Len())
+	assert.Equal(t, "cpu", metrics.At(0).Name())
+	assert.Equal(t, []testPoint{{labels: map[string]string{}, value: 1.5}}, testPoints(metrics.At(0)))
+	assert.Equal(t, "cpu.ms", metrics.At(1).Name())
+	assert.Equal(t, []testPoint{{labels: map[string]string{}, value: 1500}}, testPoints(metrics.At(1)))
+}
+
+func TestMetricsTransformProcessor_Regexp(t *testing.T) {
+	metrics := processedMetrics(t,
+		&Config{Transforms: []Transform{
+			{
+				MetricName: `^system\.(.*)$`,
+				MatchType:  filterset.Regexp,
+				Action:     Update,
+				NewName:    "host.$1",
+			},
+			{
+				// The transforms are applied in order.
+				MetricName: "host.cpu",
+				Action:     Update,
+				NewName:    "host.processor",
+			},
+		}},
+		newTestMetric("system.cpu", pdata.MetricDataTypeIntGauge),
+		newTestMetric("system.memory", pdata.MetricDataTypeIntGauge),
+		newTestMetric("process.cpu", pdata.MetricDataTypeIntGauge),
+	)
+
+	require.Equal(t, 3, metrics.Len())
+	assert.Equal(t, "host.processor", metrics.At(0).Name())
+	assert.Equal(t, "host.memory", metrics.At(1).Name())
+	assert.Equal(t, "process.cpu", metrics.At(2).Name())
+}
+
+func TestMetricsTransformProcessor_RegexpWithoutNewName(t *testing.T) {
+	metrics := processedMetrics(t,
+		&Config{Transforms: []Transform{{
+			MetricName: `^system\.`,
+			MatchType:  filterset.Regexp,
+			Action:     Update,
+			Operations: []Operation{{Action: AddLabel, NewLabel: "kind", NewValue: "system"}},
+		}}},
+		newTestMetric("system.cpu", pdata.MetricDataTypeIntGauge, testPoint{value: 1}),
+	)
+
+	assert.Equal(t, "system.cpu", metrics.At(0).Name())
+	assert.Equal(t, []testPoint{{labels: map[string]string{"kind": "system"}, value: 1}}, testPoints(metrics.At(0)))
+}
+
+func TestNewMetricsTransformProcessor_Invalid(t *testing.T) {
+	tests := []struct {
+		name      string
+		transform Transform
+		errMsg    string
+	}{
+		{
+			name:      "no metric name",
+			transform: Transform{Action: Update},
+			errMsg:    `invalid transform of "": metric_name must be specified`,
+		},
+		{
+			name:      "invalid match type",
+			transform: Transform{MetricName: "m", MatchType: "glob", Action: Update},
+			errMsg:    `invalid transform of "m": unsupported match_type "glob", valid types are {strict, regexp}`,
+		},
+		{
+			name:      "invalid regexp",
+			transform: Transform{MetricName: "(", MatchType: filterset.Regexp, Action: Update},
+			errMsg:    "invalid transform of \"(\": error parsing regexp: missing closing ): `(`",
+		},
+		{
+			name:      "insert without new name",
+			transform: Transform{MetricName: "m", Action: Insert},
+			errMsg:    `invalid transform of "m": new_name must be specified for the insert action`,
+		},
+		{
+			name:      "invalid operation",
+			transform: Transform{MetricName: "m", Action: Update, Operations: []Operation{{Action: ScaleValue, Scale: 2}, {Action: "rename"}}},
+			errMsg:    `invalid transform of "m": invalid operation at index 1: unsupported operation action "rename"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newMetricsTransformProcessor(&Config{Transforms: []Transform{tt.transform}})
+			assert.EqualError(t, err, tt.errMsg)
+		})
+	}
+}
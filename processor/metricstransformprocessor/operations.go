@@ -0,0 +1,232 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metricstransformprocessor
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func newOperation(op Operation) (operation, error) {
+	switch op.Action {
+	case UpdateLabel:
+		if op.Label == "" {
+			return nil, errors.New("label must be specified for update_label")
+		}
+		if op.NewLabel == "" && len(op.ValueActions) == 0 {
+			return nil, errors.New("new_label or value_actions must be specified for update_label")
+		}
+		return updateLabel(op.Label, op.NewLabel, op.ValueActions), nil
+	case AddLabel:
+		if op.NewLabel == "" || op.NewValue == "" {
+			return nil, errors.New("new_label and new_value must be specified for add_label")
+		}
+		return addLabel(op.NewLabel, op.NewValue), nil
+	case DeleteLabel:
+		if op.Label == "" {
+			return nil, errors.New("label must be specified for delete_label")
+		}
+		aggregationType := op.AggregationType
+		if aggregationType == "" {
+			aggregationType = Sum
+		}
+		if err := validateAggregationType(aggregationType); err != nil {
+			return nil, err
+		}
+		label := op.Label
+		return aggregate(func(key string) bool { return key != label }, aggregationType), nil
+	case DeleteLabelValue:
+		if op.Label == "" || op.LabelValue == "" {
+			return nil, errors.New("label and label_value must be specified for delete_label_value")
+		}
+		return deleteLabelValue(op.Label, op.LabelValue), nil
+	case AggregateLabels:
+		if err := validateAggregationType(op.AggregationType); err != nil {
+			return nil, err
+		}
+		labelSet := make(map[string]bool, len(op.LabelSet))
+		for _, label := range op.LabelSet {
+			labelSet[label] = true
+		}
+		return aggregate(func(key string) bool { return labelSet[key] }, op.AggregationType), nil
+	case AggregateLabelValues:
+		if op.Label == "" || op.NewValue == "" || len(op.AggregatedValues) == 0 {
+			return nil, errors.New("label, new_value and aggregated_values must be specified for aggregate_label_values")
+		}
+		if err := validateAggregationType(op.AggregationType); err != nil {
+			return nil, err
+		}
+		return aggregateLabelValues(op.Label, op.AggregatedValues, op.NewValue, op.AggregationType), nil
+	case ScaleValue:
+		if op.Scale == 0 {
+			return nil, errors.New("scale must be specified for scale_value")
+		}
+		return scaleValue(op.Scale), nil
+	default:
+		return nil, fmt.Errorf("unsupported operation action %q", op.Action)
+	}
+}
+
+func validateAggregationType(aggregationType AggregationType) error {
+	switch aggregationType {
+	case Sum, Mean, Min, Max:
+		return nil
+	default:
+		return fmt.Errorf("unsupported aggregation_type %q, valid types are {%s, %s, %s, %s}", aggregationType, Sum, Mean, Min, Max)
+	}
+}
+
+func updateLabel(label string, newLabel string, valueActions []ValueAction) operation {
+	newValues := make(map[string]string, len(valueActions))
+	for _, va := range valueActions {
+		newValues[va.Value] = va.NewValue
+	}
+	return func(metric pdata.Metric) {
+		forEachLabelsMap(metric, func(labels pdata.StringMap) {
+			value, ok := labels.Get(label)
+			if !ok {
+				return
+			}
+			if newValue, ok := newValues[value]; ok {
+				value = newValue
+			}
+			if newLabel != "" {
+				labels.Delete(label)
+				labels.Upsert(newLabel, value)
+				return
+			}
+			labels.Update(label, value)
+		})
+	}
+}
+
+func addLabel(label string, value string) operation {
+	return func(metric pdata.Metric) {
+		forEachLabelsMap(metric, func(labels pdata.StringMap) {
+			labels.Insert(label, value)
+		})
+	}
+}
+
+func deleteLabelValue(label string, value string) operation {
+	return func(metric pdata.Metric) {
+		filterDataPoints(metric, func(labels pdata.StringMap) bool {
+			v, ok := labels.Get(label)
+			return !ok || v != value
+		})
+	}
+}
+
+func aggregateLabelValues(label string, aggregatedValues []string, newValue string, aggregationType AggregationType) operation {
+	values := make(map[string]bool, len(aggregatedValues))
+	for _, v := range aggregatedValues {
+		values[v] = true
+	}
+	aggregateAll := aggregate(func(string) bool { return true }, aggregationType)
+	return func(metric pdata.Metric) {
+		forEachLabelsMap(metric, func(labels pdata.StringMap) {
+			if v, ok := labels.Get(label); ok && values[v] {
+				labels.Update(label, newValue)
+			}
+		})
+		aggregateAll(metric)
+	}
+}
+
+// scaleValue multiplies the values of the gauges and sums, and the sums,
+// bounds and quantile values of the histograms and summaries.
+func scaleValue(scale float64) operation {
+	return func(metric pdata.Metric) {
+		switch metric.DataType() {
+		case pdata.MetricDataTypeIntGauge:
+			scaleIntDataPoints(metric.IntGauge().DataPoints(), scale)
+		case pdata.MetricDataTypeDoubleGauge:
+			scaleDoubleDataPoints(metric.DoubleGauge().DataPoints(), scale)
+		case pdata.MetricDataTypeIntSum:
+			scaleIntDataPoints(metric.IntSum().DataPoints(), scale)
+		case pdata.MetricDataTypeDoubleSum:
+			scaleDoubleDataPoints(metric.DoubleSum().DataPoints(), scale)
+		case pdata.MetricDataTypeIntHistogram:
+			dps := metric.IntHistogram().DataPoints()
+			for i := 0; i < dps.Len(); i++ {
+				dp := dps.At(i)
+				dp.SetSum(scaleInt(dp.Sum(), scale))
+				dp.SetExplicitBounds(scaleBounds(dp.ExplicitBounds(), scale))
+			}
+		case pdata.MetricDataTypeDoubleHistogram:
+			dps := metric.DoubleHistogram().DataPoints()
+			for i := 0; i < dps.Len(); i++ {
+				dp := dps.At(i)
+				dp.SetSum(dp.Sum() * scale)
+				dp.SetExplicitBounds(scaleBounds(dp.ExplicitBounds(), scale))
+			}
+		case pdata.MetricDataTypeDoubleSummary:
+			dps := metric.DoubleSummary().DataPoints()
+			for i := 0; i < dps.Len(); i++ {
+				dp := dps.At(i)
+				dp.SetSum(dp.Sum() * scale)
+				quantiles := dp.QuantileValues()
+				for j := 0; j < quantiles.Len(); j++ {
+					quantiles.At(j).SetValue(quantiles.At(j).Value() * scale)
+				}
+			}
+		}
+	}
+}
+
+func scaleIntDataPoints(dps pdata.IntDataPointSlice, scale float64) {
+	for i := 0; i < dps.Len(); i++ {
+		dps.At(i).SetValue(scaleInt(dps.At(i).Value(), scale))
+	}
+}
+
+func scaleDoubleDataPoints(dps pdata.DoubleDataPointSlice, scale float64) {
+	for i := 0; i < dps.Len(); i++ {
+		dps.At(i).SetValue(dps.At(i).Value() * scale)
+	}
+}
+
+func scaleInt(v int64, scale float64) int64 {
+	return int64(math.Round(float64(v) * scale))
+}
+
+// scaleBounds returns the scaled bounds, reversed if the scale is negative to
+// keep them sorted.
+func scaleBounds(bounds []float64, scale float64) []float64 {
+	scaled := make([]float64, len(bounds))
+	for i, b := range bounds {
+		scaled[i] = b * scale
+	}
+	if scale < 0 {
+		for i, j := 0, len(scaled)-1; i < j; i, j = i+1, j-1 {
+			scaled[i], scaled[j] = scaled[j], scaled[i]
+		}
+	}
+	return scaled
+}
+
+// forEachLabelsMap calls f with the labels of each data point of the metric.
+func forEachLabelsMap(metric pdata.Metric, f func(labels pdata.StringMap)) {
+	dps := newDataPointSlice(metric)
+	if dps == nil {
+		return
+	}
+	for i := 0; i < dps.Len(); i++ {
+		f(dps.labels(i))
+	}
+}
@@ -0,0 +1,204 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metricstransformprocessor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func applyOperation(t *testing.T, op Operation, metric pdata.Metric) pdata.Metric {
+	compiled, err := newOperation(op)
+	require.NoError(t, err)
+	compiled(metric)
+	return metric
+}
+
+func TestUpdateLabel(t *testing.T) {
+	metric := newTestMetric("cpu", pdata.MetricDataTypeIntGauge,
+		testPoint{labels: map[string]string{"state": "idle"}, value: 1},
+		testPoint{labels: map[string]string{"state": "user"}, value: 2},
+		testPoint{labels: map[string]string{"cpu": "0"}, value: 3},
+	)
+	applyOperation(t, Operation{
+		Action:       UpdateLabel,
+		Label:        "state",
+		NewLabel:     "cpu.state",
+		ValueActions: []ValueAction{{Value: "idle", NewValue: "free"}},
+	}, metric)
+
+	assert.Equal(t, []testPoint{
+		{labels: map[string]string{"cpu.state": "free"}, value: 1},
+		{labels: map[string]string{"cpu.state": "user"}, value: 2},
+		{labels: map[string]string{"cpu": "0"}, value: 3},
+	}, testPoints(metric))
+}
+
+func TestUpdateLabel_ValuesOnly(t *testing.T) {
+	metric := newTestMetric("cpu", pdata.MetricDataTypeDoubleGauge,
+		testPoint{labels: map[string]string{"state": "idle"}, value: 1},
+		testPoint{labels: map[string]string{"state": "user"}, value: 2},
+	)
+	applyOperation(t, Operation{
+		Action:       UpdateLabel,
+		Label:        "state",
+		ValueActions: []ValueAction{{Value: "idle", NewValue: "free"}},
+	}, metric)
+
+	assert.Equal(t, []testPoint{
+		{labels: map[string]string{"state": "free"}, value: 1},
+		{labels: map[string]string{"state": "user"}, value: 2},
+	}, testPoints(metric))
+}
+
+func TestAddLabel(t *testing.T) {
+	metric := newTestMetric("cpu", pdata.MetricDataTypeIntGauge,
+		testPoint{labels: map[string]string{"host": "h1"}, value: 1},
+		testPoint{labels: map[string]string{"state": "idle"}, value: 2},
+	)
+	applyOperation(t, Operation{Action: AddLabel, NewLabel: "host", NewValue: "h2"}, metric)
+
+	assert.Equal(t, []testPoint{
+		{labels: map[string]string{"host": "h1"}, value: 1},
+		{labels: map[string]string{"host": "h2", "state": "idle"}, value: 2},
+	}, testPoints(metric))
+}
+
+func TestDeleteLabelValue(t *testing.T) {
+	metric := newTestMetric("cpu", pdata.MetricDataTypeIntGauge,
+		testPoint{labels: map[string]string{"state": "steal"}, value: 1},
+		testPoint{labels: map[string]string{"state": "user"}, value: 2},
+		testPoint{labels: map[string]string{"state": "steal"}, value: 3},
+		testPoint{labels: map[string]string{}, value: 4},
+	)
+	applyOperation(t, Operation{Action: DeleteLabelValue, Label: "state", LabelValue: "steal"}, metric)
+
+	assert.Equal(t, []testPoint{
+		{labels: map[string]string{"state": "user"}, value: 2},
+		{labels: map[string]string{}, value: 4},
+	}, testPoints(metric))
+}
+
+func TestScaleValue(t *testing.T) {
+	intGauge := newTestMetric("cpu", pdata.MetricDataTypeIntGauge, testPoint{value: 3})
+	applyOperation(t, Operation{Action: ScaleValue, Scale: 0.5}, intGauge)
+	assert.EqualValues(t, 2, intGauge.IntGauge().DataPoints().At(0).Value())
+
+	doubleSum := pdata.NewMetric()
+	doubleSum.SetDataType(pdata.MetricDataTypeDoubleSum)
+	doubleSum.DoubleSum().DataPoints().Resize(1)
+	doubleSum.DoubleSum().DataPoints().At(0).SetValue(1.5)
+	applyOperation(t, Operation{Action: ScaleValue, Scale: 1000}, doubleSum)
+	assert.Equal(t, 1500.0, doubleSum.DoubleSum().DataPoints().At(0).Value())
+
+	histogram := pdata.NewMetric()
+	histogram.SetDataType(pdata.MetricDataTypeDoubleHistogram)
+	histogram.DoubleHistogram().DataPoints().Resize(1)
+	hdp := histogram.DoubleHistogram().DataPoints().At(0)
+	hdp.SetSum(2.5)
+	hdp.SetExplicitBounds([]float64{0.5, 1})
+	hdp.SetBucketCounts([]uint64{1, 2, 3})
+	applyOperation(t, Operation{Action: ScaleValue, Scale: 1000}, histogram)
+	assert.Equal(t, 2500.0, hdp.Sum())
+	assert.Equal(t, []float64{500, 1000}, hdp.ExplicitBounds())
+	assert.Equal(t, []uint64{1, 2, 3}, hdp.BucketCounts())
+
+	summary := pdata.NewMetric()
+	summary.SetDataType(pdata.MetricDataTypeDoubleSummary)
+	summary.DoubleSummary().DataPoints().Resize(1)
+	sdp := summary.DoubleSummary().DataPoints().At(0)
+	sdp.SetSum(2)
+	sdp.QuantileValues().Resize(1)
+	sdp.QuantileValues().At(0).SetQuantile(0.5)
+	sdp.QuantileValues().At(0).SetValue(1)
+	applyOperation(t, Operation{Action: ScaleValue, Scale: -2}, summary)
+	assert.Equal(t, -4.0, sdp.Sum())
+	assert.Equal(t, 0.5, sdp.QuantileValues().At(0).Quantile())
+	assert.Equal(t, -2.0, sdp.QuantileValues().At(0).Value())
+}
+
+func TestScaleBounds(t *testing.T) {
+	assert.Equal(t, []float64{2, 4}, scaleBounds([]float64{1, 2}, 2))
+	assert.Equal(t, []float64{-4, -2}, scaleBounds([]float64{1, 2}, -2))
+}
+
+func TestNewOperation_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		op     Operation
+		errMsg string
+	}{
+		{
+			name:   "update_label without label",
+			op:     Operation{Action: UpdateLabel, NewLabel: "l"},
+			errMsg: "label must be specified for update_label",
+		},
+		{
+			name:   "update_label without update",
+			op:     Operation{Action: UpdateLabel, Label: "l"},
+			errMsg: "new_label or value_actions must be specified for update_label",
+		},
+		{
+			name:   "add_label without value",
+			op:     Operation{Action: AddLabel, NewLabel: "l"},
+			errMsg: "new_label and new_value must be specified for add_label",
+		},
+		{
+			name:   "delete_label without label",
+			op:     Operation{Action: DeleteLabel},
+			errMsg: "label must be specified for delete_label",
+		},
+		{
+			name:   "delete_label with invalid aggregation type",
+			op:     Operation{Action: DeleteLabel, Label: "l", AggregationType: "median"},
+			errMsg: `unsupported aggregation_type "median", valid types are {sum, mean, min, max}`,
+		},
+		{
+			name:   "delete_label_value without value",
+			op:     Operation{Action: DeleteLabelValue, Label: "l"},
+			errMsg: "label and label_value must be specified for delete_label_value",
+		},
+		{
+			name:   "aggregate_labels without aggregation type",
+			op:     Operation{Action: AggregateLabels, LabelSet: []string{"l"}},
+			errMsg: `unsupported aggregation_type "", valid types are {sum, mean, min, max}`,
+		},
+		{
+			name:   "aggregate_label_values without values",
+			op:     Operation{Action: AggregateLabelValues, Label: "l", NewValue: "v", AggregationType: Sum},
+			errMsg: "label, new_value and aggregated_values must be specified for aggregate_label_values",
+		},
+		{
+			name:   "aggregate_label_values without aggregation type",
+			op:     Operation{Action: AggregateLabelValues, Label: "l", NewValue: "v", AggregatedValues: []string{"a"}},
+			errMsg: `unsupported aggregation_type "", valid types are {sum, mean, min, max}`,
+		},
+		{
+			name:   "scale_value without scale",
+			op:     Operation{Action: ScaleValue},
+			errMsg: "scale must be specified for scale_value",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newOperation(tt.op)
+			assert.EqualError(t, err, tt.errMsg)
+		})
+	}
+}
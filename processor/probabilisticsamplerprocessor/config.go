@@ -19,11 +19,14 @@ import "go.opentelemetry.io/collector/config/configmodels"
 // Config has the configuration guiding the trace sampler processor.
 type Config struct {
 	configmodels.ProcessorSettings `mapstructure:",squash"`
-	// SamplingPercentage is the percentage rate at which traces are going to be sampled. Defaults to zero, i.e.: no sample.
+	// SamplingPercentage is the percentage rate at which traces and logs are going to be sampled. Defaults to zero, i.e.: no sample.
 	// Values greater or equal 100 are treated as "sample all traces".
 	SamplingPercentage float32 `mapstructure:"sampling_percentage"`
 	// HashSeed allows one to configure the hashing seed. This is important in scenarios where multiple layers of collectors
 	// have different sampling rates: if they use the same seed all passing one layer may pass the other even if they have
 	// different sampling rates, configuring different seeds avoids that.
 	HashSeed uint32 `mapstructure:"hash_seed"`
+	// HashAttribute is the attribute of the log records hashed instead of the trace ID when they have none, e.g. a
+	// request ID. The log records with neither trace ID nor HashAttribute are always sampled.
+	HashAttribute string `mapstructure:"hash_attribute"`
 }
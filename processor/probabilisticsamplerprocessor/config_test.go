@@ -47,6 +47,7 @@ func TestLoadConfig(t *testing.T) {
 			},
 			SamplingPercentage: 15.3,
 			HashSeed:           22,
+			HashAttribute:      "request.id",
 		})
 
 }
@@ -38,4 +38,8 @@ func TestCreateProcessor(t *testing.T) {
 	tp, err := createTraceProcessor(context.Background(), params, cfg, consumertest.NewTracesNop())
 	assert.NotNil(t, tp)
 	assert.NoError(t, err, "cannot create trace processor")
+
+	lp, err := createLogsProcessor(context.Background(), params, cfg, consumertest.NewLogsNop())
+	assert.NotNil(t, lp)
+	assert.NoError(t, err, "cannot create logs processor")
 }
@@ -0,0 +1,142 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package probabilisticsamplerprocessor
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+)
+
+type logsamplerprocessor struct {
+	nextConsumer       consumer.LogsConsumer
+	scaledSamplingRate uint32
+	hashSeed           uint32
+	hashAttribute      string
+}
+
+// newLogsProcessor returns a processor.LogsProcessor that will sample the log records according to the given
+// configuration. The log records are sampled by hashing their trace ID, as the spans, so that the log records of a
+// trace are sampled with its spans.
+func newLogsProcessor(nextConsumer consumer.LogsConsumer, cfg Config) (component.LogsProcessor, error) {
+	if nextConsumer == nil {
+		return nil, componenterror.ErrNilNextConsumer
+	}
+
+	return &logsamplerprocessor{
+		nextConsumer: nextConsumer,
+		// Adjust sampling percentage on private so recalculations are avoided.
+		scaledSamplingRate: uint32(cfg.SamplingPercentage * percentageScaleFactor),
+		hashSeed:           cfg.HashSeed,
+		hashAttribute:      cfg.HashAttribute,
+	}, nil
+}
+
+func (lsp *logsamplerprocessor) ConsumeLogs(ctx context.Context, ld pdata.Logs) error {
+	rls := ld.ResourceLogs()
+	sampledLogData := pdata.NewLogs()
+	srls := sampledLogData.ResourceLogs()
+	srls.Resize(rls.Len())
+	rlsCount := 0
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		srl := srls.At(rlsCount)
+		if lsp.processLogs(rl, srl) {
+			rl.Resource().CopyTo(srl.Resource())
+			rlsCount++
+		}
+	}
+	srls.Resize(rlsCount)
+	return lsp.nextConsumer.ConsumeLogs(ctx, sampledLogData)
+}
+
+// processLogs copies the sampled log records of resourceLogs to sampledResourceLogs, it returns false if no log
+// record was sampled.
+func (lsp *logsamplerprocessor) processLogs(resourceLogs pdata.ResourceLogs, sampledResourceLogs pdata.ResourceLogs) bool {
+	ills := resourceLogs.InstrumentationLibraryLogs()
+	sills := sampledResourceLogs.InstrumentationLibraryLogs()
+	sills.Resize(ills.Len())
+	illsCount := 0
+	for j := 0; j < ills.Len(); j++ {
+		ill := ills.At(j)
+		sill := sills.At(illsCount)
+		logs := ill.Logs()
+		slogs := sill.Logs()
+		slogs.Resize(logs.Len())
+		logsCount := 0
+		for k := 0; k < logs.Len(); k++ {
+			lr := logs.At(k)
+			if lsp.sampled(lr) {
+				lr.CopyTo(slogs.At(logsCount))
+				logsCount++
+			}
+		}
+		slogs.Resize(logsCount)
+		if logsCount > 0 {
+			ill.InstrumentationLibrary().CopyTo(sill.InstrumentationLibrary())
+			illsCount++
+		}
+	}
+	sills.Resize(illsCount)
+	return illsCount > 0
+}
+
+// sampled returns whether the log record is sampled. The "sampling.priority" attribute takes priority over hashing,
+// as for the spans, then the trace ID is hashed or, if the log record has none, the configured hash attribute. The
+// log records with neither are always sampled.
+func (lsp *logsamplerprocessor) sampled(lr pdata.LogRecord) bool {
+	switch parseSamplingPriority(lr.Attributes()) {
+	case doNotSampleSpan:
+		return false
+	case mustSampleSpan:
+		return true
+	}
+
+	var key []byte
+	if tid := lr.TraceID(); !tid.IsEmpty() {
+		tidBytes := tid.Bytes()
+		key = tidBytes[:]
+	} else if attr, ok := lsp.hashAttributeOf(lr); ok {
+		key = []byte(tracetranslator.AttributeValueToString(attr, false))
+	} else {
+		return true
+	}
+	return hash(key, lsp.hashSeed)&bitMaskHashBuckets < lsp.scaledSamplingRate
+}
+
+func (lsp *logsamplerprocessor) hashAttributeOf(lr pdata.LogRecord) (pdata.AttributeValue, bool) {
+	if lsp.hashAttribute == "" {
+		return pdata.AttributeValue{}, false
+	}
+	return lr.Attributes().Get(lsp.hashAttribute)
+}
+
+func (lsp *logsamplerprocessor) GetCapabilities() component.ProcessorCapabilities {
+	return component.ProcessorCapabilities{MutatesConsumedData: false}
+}
+
+// Start is invoked during service startup.
+func (lsp *logsamplerprocessor) Start(context.Context, component.Host) error {
+	return nil
+}
+
+// Shutdown is invoked during service shutdown.
+func (lsp *logsamplerprocessor) Shutdown(context.Context) error {
+	return nil
+}
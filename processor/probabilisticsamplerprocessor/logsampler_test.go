@@ -0,0 +1,178 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package probabilisticsamplerprocessor
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal/testdata"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+)
+
+func TestNewLogsProcessor(t *testing.T) {
+	lp, err := newLogsProcessor(nil, Config{})
+	assert.Nil(t, lp)
+	assert.Error(t, err)
+
+	cfg := Config{SamplingPercentage: 15.5, HashSeed: 4321, HashAttribute: "request.id"}
+	lp, err = newLogsProcessor(consumertest.NewLogsNop(), cfg)
+	require.NoError(t, err)
+	assert.Equal(t, &logsamplerprocessor{
+		nextConsumer:       consumertest.NewLogsNop(),
+		scaledSamplingRate: uint32(cfg.SamplingPercentage * percentageScaleFactor),
+		hashSeed:           4321,
+		hashAttribute:      "request.id",
+	}, lp)
+}
+
+// Test_logsamplerprocessor_ConsistentWithTraces checks that the log records are sampled with the spans of their
+// trace.
+func Test_logsamplerprocessor_ConsistentWithTraces(t *testing.T) {
+	cfg := Config{SamplingPercentage: 30, HashSeed: 22}
+	tracesSink := new(consumertest.TracesSink)
+	tsp, err := newTraceProcessor(tracesSink, cfg)
+	require.NoError(t, err)
+	logsSink := new(consumertest.LogsSink)
+	lsp, err := newLogsProcessor(logsSink, cfg)
+	require.NoError(t, err)
+
+	td := genRandomTestData(1, 1000, "svc", 1)[0]
+	ld := pdata.NewLogs()
+	ld.ResourceLogs().Resize(1)
+	ld.ResourceLogs().At(0).InstrumentationLibraryLogs().Resize(1)
+	logs := ld.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs()
+	spans := td.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans()
+	logs.Resize(spans.Len())
+	for i := 0; i < spans.Len(); i++ {
+		logs.At(i).SetTraceID(spans.At(i).TraceID())
+	}
+
+	require.NoError(t, tsp.ConsumeTraces(context.Background(), td))
+	require.NoError(t, lsp.ConsumeLogs(context.Background(), ld))
+
+	sampledTraceIDs, spanCount := assertSampledData(t, tracesSink.AllTraces(), "svc")
+	assert.InDelta(t, 300, spanCount, 50)
+	require.Len(t, logsSink.AllLogs(), 1)
+	sampledLogs := logsSink.AllLogs()[0].ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs()
+	require.Equal(t, spanCount, sampledLogs.Len())
+	for i := 0; i < sampledLogs.Len(); i++ {
+		assert.True(t, sampledTraceIDs[sampledLogs.At(i).TraceID().Bytes()])
+	}
+}
+
+func Test_logsamplerprocessor_HashAttribute(t *testing.T) {
+	newLogs := func(n int, attribute string) pdata.Logs {
+		ld := pdata.NewLogs()
+		ld.ResourceLogs().Resize(1)
+		ld.ResourceLogs().At(0).InstrumentationLibraryLogs().Resize(1)
+		logs := ld.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs()
+		logs.Resize(2 * n)
+		for i := 0; i < logs.Len(); i++ {
+			// Two log records per value of the attribute.
+			logs.At(i).Attributes().InsertString(attribute, strconv.Itoa(i/2))
+		}
+		return ld
+	}
+
+	sink := new(consumertest.LogsSink)
+	lsp, err := newLogsProcessor(sink, Config{SamplingPercentage: 50, HashAttribute: "request.id"})
+	require.NoError(t, err)
+	require.NoError(t, lsp.ConsumeLogs(context.Background(), newLogs(1000, "request.id")))
+
+	sampled := sink.AllLogs()[0].ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs()
+	assert.InDelta(t, 1000, sampled.Len(), 100)
+	counts := map[string]int{}
+	for i := 0; i < sampled.Len(); i++ {
+		attr, _ := sampled.At(i).Attributes().Get("request.id")
+		counts[attr.StringVal()]++
+	}
+	for value, count := range counts {
+		assert.Equal(t, 2, count, "log records of %q sampled separately", value)
+	}
+
+	// The log records with neither trace ID nor hash attribute are sampled.
+	sink.Reset()
+	require.NoError(t, lsp.ConsumeLogs(context.Background(), newLogs(1000, "other")))
+	assert.Equal(t, 2000, sink.LogRecordsCount())
+}
+
+func Test_logsamplerprocessor_SamplingPriority(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   pdata.AttributeValue
+		sampled bool
+	}{
+		{name: "must_sample", value: pdata.NewAttributeValueInt(2), sampled: true},
+		{name: "must_sample_string", value: pdata.NewAttributeValueString("1"), sampled: true},
+		{name: "must_not_sample", value: pdata.NewAttributeValueInt(0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The rate is the opposite of the priority which takes priority over the trace ID hashing.
+			cfg := Config{SamplingPercentage: 100}
+			if tt.sampled {
+				cfg.SamplingPercentage = 0
+			}
+			sink := new(consumertest.LogsSink)
+			lsp, err := newLogsProcessor(sink, cfg)
+			require.NoError(t, err)
+
+			ld := testdata.GenerateLogDataOneLogNoResource()
+			lr := ld.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(0)
+			lr.SetTraceID(tracetranslator.UInt64ToTraceID(0, 1))
+			lr.Attributes().Upsert("sampling.priority", tt.value)
+			require.NoError(t, lsp.ConsumeLogs(context.Background(), ld))
+
+			if tt.sampled {
+				assert.Equal(t, 1, sink.LogRecordsCount())
+			} else {
+				assert.Equal(t, 0, sink.LogRecordsCount())
+			}
+		})
+	}
+}
+
+func Test_logsamplerprocessor_DropsEmpty(t *testing.T) {
+	sink := new(consumertest.LogsSink)
+	lsp, err := newLogsProcessor(sink, Config{SamplingPercentage: 0})
+	require.NoError(t, err)
+
+	ld := testdata.GenerateLogDataTwoLogsSameResource()
+	ld.ResourceLogs().Resize(2)
+	ld.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(0).SetTraceID(tracetranslator.UInt64ToTraceID(0, 1))
+	lr := ld.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(1)
+	lr.SetTraceID(pdata.NewTraceID([16]byte{}))
+	lr.Attributes().UpsertInt("sampling.priority", 1)
+	ld.ResourceLogs().At(1).Resource().Attributes().InsertString("service.name", "svc")
+	ld.ResourceLogs().At(1).InstrumentationLibraryLogs().Resize(1)
+	ld.ResourceLogs().At(1).InstrumentationLibraryLogs().At(0).Logs().Resize(1)
+	ld.ResourceLogs().At(1).InstrumentationLibraryLogs().At(0).Logs().At(0).SetTraceID(tracetranslator.UInt64ToTraceID(0, 2))
+	require.NoError(t, lsp.ConsumeLogs(context.Background(), ld))
+
+	require.Len(t, sink.AllLogs(), 1)
+	sampled := sink.AllLogs()[0]
+	require.Equal(t, 1, sampled.ResourceLogs().Len())
+	assert.Equal(t, ld.ResourceLogs().At(0).Resource(), sampled.ResourceLogs().At(0).Resource())
+	require.Equal(t, 1, sampled.ResourceLogs().At(0).InstrumentationLibraryLogs().Len())
+	require.Equal(t, 1, sampled.LogRecordCount())
+	assert.Equal(t, lr, sampled.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(0))
+}
@@ -133,7 +133,12 @@ func (tsp *tracesamplerprocessor) Shutdown(context.Context) error {
 // OpenTracing semantic tags:
 // https://github.com/opentracing/specification/blob/main/semantic_conventions.md#span-tags-table
 func parseSpanSamplingPriority(span pdata.Span) samplingPriority {
-	attribMap := span.Attributes()
+	return parseSamplingPriority(span.Attributes())
+}
+
+// parseSamplingPriority checks the "sampling.priority" attribute of a span or
+// a log record.
+func parseSamplingPriority(attribMap pdata.AttributeMap) samplingPriority {
 	if attribMap.Len() <= 0 {
 		return deferDecision
 	}
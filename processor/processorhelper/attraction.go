@@ -16,8 +16,10 @@ package processorhelper
 
 import (
 	"fmt"
+	"os"
 	"regexp"
 	"strings"
+	"time"
 
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/internal/processor/filterhelper"
@@ -26,7 +28,7 @@ import (
 // Settings
 type Settings struct {
 	// Actions specifies the list of attributes to act on.
-	// The set of actions are {INSERT, UPDATE, UPSERT, DELETE, HASH, EXTRACT}.
+	// The set of actions are {INSERT, UPDATE, UPSERT, DELETE, HASH, EXTRACT, CONVERT, MAP}.
 	// This is a required field.
 	Actions []ActionKeyValue `mapstructure:"actions"`
 }
@@ -55,20 +57,45 @@ type ActionKeyValue struct {
 	// the value. If the attribute doesn't exist, no action is performed.
 	FromAttribute string `mapstructure:"from_attribute"`
 
+	// FromEnv specifies the environment variable to use to populate the
+	// value. The variable is read when the processor is created, if it
+	// isn't set, no action is performed.
+	FromEnv string `mapstructure:"from_env"`
+
+	// FromCommand specifies the command whose output, without the trailing
+	// white spaces, is used to populate the value. The command is split into
+	// arguments on white spaces, it isn't run in a shell, and is run once when
+	// the processor is created.
+	FromCommand string `mapstructure:"from_command"`
+
+	// ConvertedType specifies the type the attribute is converted to for the
+	// action CONVERT. The set of values are {int, double, string, bool}.
+	ConvertedType string `mapstructure:"converted_type"`
+
+	// MappingFile specifies the YAML (.yaml, .yml) or CSV (.csv) file with the
+	// mapping used by the action MAP. The YAML file is a map of keys to values,
+	// the CSV file has records with two fields, the key and the value.
+	MappingFile string `mapstructure:"mapping_file"`
+
+	// ReloadInterval specifies how often the mapping file of the action MAP is
+	// checked for modifications and reloaded. If it is zero, the mapping file
+	// is loaded only once.
+	ReloadInterval time.Duration `mapstructure:"reload_interval"`
+
 	// Action specifies the type of action to perform.
-	// The set of values are {INSERT, UPDATE, UPSERT, DELETE, HASH}.
+	// The set of values are {INSERT, UPDATE, UPSERT, DELETE, HASH, EXTRACT, CONVERT, MAP}.
 	// Both lower case and upper case are supported.
 	// INSERT -  Inserts the key/value to attributes when the key does not exist.
 	//           No action is applied to attributes where the key already exists.
-	//           Either Value or FromAttribute must be set.
+	//           One of Value, FromAttribute, FromEnv or FromCommand must be set.
 	// UPDATE -  Updates an existing key with a value. No action is applied
 	//           to attributes where the key does not exist.
-	//           Either Value or FromAttribute must be set.
+	//           One of Value, FromAttribute, FromEnv or FromCommand must be set.
 	// UPSERT -  Performs insert or update action depending on the attributes
 	//           containing the key. The key/value is insert to attributes
 	//           that did not originally have the key. The key/value is updated
 	//           for attributes where the key already existed.
-	//           Either Value or FromAttribute must be set.
+	//           One of Value, FromAttribute, FromEnv or FromCommand must be set.
 	// DELETE  - Deletes the attribute. If the key doesn't exist,
 	//           no action is performed.
 	// HASH    - Calculates the SHA-1 hash of an existing value and overwrites the
@@ -76,6 +103,12 @@ type ActionKeyValue struct {
 	// EXTRACT - Extracts values using a regular expression rule from the input
 	//           'key' to target keys specified in the 'rule'. If a target key
 	//           already exists, it will be overridden.
+	// CONVERT - Converts the value of an existing attribute to 'converted_type'.
+	//           If the value cannot be converted, the attribute is not changed.
+	// MAP     - Replaces the value of an existing attribute with the value it is
+	//           mapped to in 'mapping_file'. Keys of the mapping may be CIDR
+	//           blocks matching IP addresses. If the value is not mapped, the
+	//           attribute is not changed.
 	// This is a required field.
 	Action Action `mapstructure:"action"`
 }
@@ -109,6 +142,15 @@ const (
 	// 'key' to target keys specified in the 'rule'. If a target key already
 	// exists, it will be overridden.
 	EXTRACT Action = "extract"
+
+	// CONVERT converts the value of an existing attribute to the configured
+	// type. If the value cannot be converted, the attribute is not changed.
+	CONVERT Action = "convert"
+
+	// MAP replaces the value of an existing attribute with the value it is
+	// mapped to in a mapping file. If the value is not mapped, the attribute
+	// is not changed.
+	MAP Action = "map"
 )
 
 type attributeAction struct {
@@ -126,6 +168,10 @@ type attributeAction struct {
 	// and could impact performance.
 	Action         Action
 	AttributeValue *pdata.AttributeValue
+	// Type the attribute is converted to for the action CONVERT.
+	ConvertedType pdata.AttributeValueType
+	// Mapping used for the action MAP.
+	Mapping *attributeMapping
 }
 
 type AttrProc struct {
@@ -150,27 +196,57 @@ func NewAttrProc(settings *Settings) (*AttrProc, error) {
 			Action: a.Action,
 		}
 
+		if a.ConvertedType != "" && a.Action != CONVERT {
+			return nil, fmt.Errorf("error creating AttrProc. Action \"%s\" does not use the \"converted_type\" field. This must not be specified for %d-th action", a.Action, i)
+		}
+		if (a.MappingFile != "" || a.ReloadInterval != 0) && a.Action != MAP {
+			return nil, fmt.Errorf("error creating AttrProc. Action \"%s\" does not use the \"mapping_file\" or \"reload_interval\" field. These must not be specified for %d-th action", a.Action, i)
+		}
+
+		if (a.FromEnv != "" || a.FromCommand != "") && a.Action != INSERT && a.Action != UPDATE && a.Action != UPSERT {
+			return nil, fmt.Errorf("error creating AttrProc. Action \"%s\" does not use the \"from_env\" or \"from_command\" field. These must not be specified for %d-th action", a.Action, i)
+		}
+
 		switch a.Action {
 		case INSERT, UPDATE, UPSERT:
-			if a.Value == nil && a.FromAttribute == "" {
-				return nil, fmt.Errorf("error creating AttrProc. Either field \"value\" or \"from_attribute\" setting must be specified for %d-th action", i)
+			if a.Value == nil && a.FromAttribute == "" && a.FromEnv == "" && a.FromCommand == "" {
+				return nil, fmt.Errorf("error creating AttrProc. Either field \"value\", \"from_attribute\", \"from_env\" or \"from_command\" setting must be specified for %d-th action", i)
 			}
 
 			if a.Value != nil && a.FromAttribute != "" {
 				return nil, fmt.Errorf("error creating AttrProc due to both fields \"value\" and \"from_attribute\" being set at the %d-th actions", i)
 			}
+			if countSources(a) > 1 {
+				return nil, fmt.Errorf("error creating AttrProc due to more than one of the fields \"value\", \"from_attribute\", \"from_env\" and \"from_command\" being set at the %d-th actions", i)
+			}
 			if a.RegexPattern != "" {
 				return nil, fmt.Errorf("error creating AttrProc. Action \"%s\" does not use the \"pattern\" field. This must not be specified for %d-th action", a.Action, i)
 
 			}
 			// Convert the raw value from the configuration to the internal trace representation of the value.
-			if a.Value != nil {
+			switch {
+			case a.Value != nil:
 				val, err := filterhelper.NewAttributeValueRaw(a.Value)
 				if err != nil {
 					return nil, err
 				}
 				action.AttributeValue = &val
-			} else {
+			case a.FromEnv != "":
+				env, ok := os.LookupEnv(a.FromEnv)
+				if !ok {
+					// No action is performed if the environment variable isn't set.
+					continue
+				}
+				val := pdata.NewAttributeValueString(env)
+				action.AttributeValue = &val
+			case a.FromCommand != "":
+				out, err := commandOutput(a.FromCommand)
+				if err != nil {
+					return nil, fmt.Errorf("error creating AttrProc. Failed to run \"from_command\" at the %d-th action: %w", i, err)
+				}
+				val := pdata.NewAttributeValueString(out)
+				action.AttributeValue = &val
+			default:
 				action.FromAttribute = a.FromAttribute
 			}
 		case HASH, DELETE:
@@ -201,6 +277,30 @@ func NewAttrProc(settings *Settings) (*AttrProc, error) {
 			}
 			action.Regex = re
 			action.AttrNames = attrNames
+		case CONVERT:
+			if a.Value != nil || a.FromAttribute != "" || a.RegexPattern != "" {
+				return nil, fmt.Errorf("error creating AttrProc. Action \"%s\" does not use \"value\", \"pattern\" or \"from_attribute\" field. These must not be specified for %d-th action", a.Action, i)
+			}
+			convertedType, ok := convertedTypes[strings.ToLower(a.ConvertedType)]
+			if !ok {
+				return nil, fmt.Errorf("error creating AttrProc. Field \"converted_type\" has unsupported type %q, valid types are {int, double, string, bool}, at the %d-th action", a.ConvertedType, i)
+			}
+			action.ConvertedType = convertedType
+		case MAP:
+			if a.Value != nil || a.FromAttribute != "" || a.RegexPattern != "" {
+				return nil, fmt.Errorf("error creating AttrProc. Action \"%s\" does not use \"value\", \"pattern\" or \"from_attribute\" field. These must not be specified for %d-th action", a.Action, i)
+			}
+			if a.MappingFile == "" {
+				return nil, fmt.Errorf("error creating AttrProc due to missing required field \"mapping_file\" for action \"%s\" at the %d-th action", a.Action, i)
+			}
+			if a.ReloadInterval < 0 {
+				return nil, fmt.Errorf("error creating AttrProc. Field \"reload_interval\" must not be negative at the %d-th action", i)
+			}
+			mapping, err := newAttributeMapping(a.MappingFile, a.ReloadInterval)
+			if err != nil {
+				return nil, fmt.Errorf("error creating AttrProc. Failed to load \"mapping_file\" at the %d-th action: %w", i, err)
+			}
+			action.Mapping = mapping
 		default:
 			return nil, fmt.Errorf("error creating AttrProc due to unsupported action %q at the %d-th actions", a.Action, i)
 		}
@@ -241,6 +341,10 @@ func (ap *AttrProc) Process(attrs pdata.AttributeMap) {
 			hashAttribute(action, attrs)
 		case EXTRACT:
 			extractAttributes(action, attrs)
+		case CONVERT:
+			convertAttribute(action, attrs)
+		case MAP:
+			mapAttribute(action, attrs)
 		}
 	}
 }
@@ -260,6 +364,26 @@ func hashAttribute(action attributeAction, attrs pdata.AttributeMap) {
 	}
 }
 
+func convertAttribute(action attributeAction, attrs pdata.AttributeMap) {
+	if value, exists := attrs.Get(action.Key); exists {
+		convertValue(value, action.ConvertedType)
+	}
+}
+
+func mapAttribute(action attributeAction, attrs pdata.AttributeMap) {
+	value, exists := attrs.Get(action.Key)
+	if !exists {
+		return
+	}
+	key, ok := stringValue(value)
+	if !ok {
+		return
+	}
+	if mapped, ok := action.Mapping.lookup(key); ok {
+		value.SetStringVal(mapped)
+	}
+}
+
 func extractAttributes(action attributeAction, attrs pdata.AttributeMap) {
 	value, found := attrs.Get(action.Key)
 
@@ -20,8 +20,12 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"os"
 	"regexp"
+	"runtime"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -152,6 +156,83 @@ func TestAttributes_InsertFromAttribute(t *testing.T) {
 	}
 }
 
+func TestAttributes_InsertFromEnv(t *testing.T) {
+	const envName = "OTEL_TEST_ATTRACTION_FROM_ENV"
+	require.NoError(t, os.Setenv(envName, "production"))
+	defer os.Unsetenv(envName)
+
+	cfg := &Settings{
+		Actions: []ActionKeyValue{
+			{Key: "deployment.environment", Action: INSERT, FromEnv: envName},
+			{Key: "unset", Action: INSERT, FromEnv: envName + "_UNSET"},
+		},
+	}
+
+	ap, err := NewAttrProc(cfg)
+	require.Nil(t, err)
+	require.NotNil(t, ap)
+
+	testCases := []testCase{
+		// Ensure the value of the environment variable is inserted and nothing is inserted for the unset one.
+		{
+			name:            "InsertFromEnv",
+			inputAttributes: map[string]pdata.AttributeValue{},
+			expectedAttributes: map[string]pdata.AttributeValue{
+				"deployment.environment": pdata.NewAttributeValueString("production"),
+			},
+		},
+		// Ensures no insert is performed because the key already exists.
+		{
+			name: "InsertKeyExists",
+			inputAttributes: map[string]pdata.AttributeValue{
+				"deployment.environment": pdata.NewAttributeValueString("staging"),
+			},
+			expectedAttributes: map[string]pdata.AttributeValue{
+				"deployment.environment": pdata.NewAttributeValueString("staging"),
+			},
+		},
+	}
+	for _, tt := range testCases {
+		runIndividualTestCase(t, tt, ap)
+	}
+}
+
+func TestAttributes_UpsertFromCommand(t *testing.T) {
+	cfg := &Settings{
+		Actions: []ActionKeyValue{
+			{Key: "os.type", Action: UPSERT, FromCommand: "go env GOOS"},
+		},
+	}
+
+	ap, err := NewAttrProc(cfg)
+	require.Nil(t, err)
+	require.NotNil(t, ap)
+
+	runIndividualTestCase(t, testCase{
+		name: "UpsertFromCommand",
+		inputAttributes: map[string]pdata.AttributeValue{
+			"os.type": pdata.NewAttributeValueString("unknown"),
+		},
+		expectedAttributes: map[string]pdata.AttributeValue{
+			"os.type": pdata.NewAttributeValueString(runtime.GOOS),
+		},
+	}, ap)
+}
+
+func TestAttributes_FromCommandFailure(t *testing.T) {
+	ap, err := NewAttrProc(&Settings{Actions: []ActionKeyValue{
+		{Key: "key", Action: INSERT, FromCommand: "go not-a-go-command"},
+	}})
+	assert.Nil(t, ap)
+	assert.Error(t, err)
+
+	ap, err = NewAttrProc(&Settings{Actions: []ActionKeyValue{
+		{Key: "key", Action: INSERT, FromCommand: " "},
+	}})
+	assert.Nil(t, ap)
+	assert.EqualError(t, err, "error creating AttrProc. Failed to run \"from_command\" at the 0-th action: empty command")
+}
+
 func TestAttributes_UpdateValue(t *testing.T) {
 
 	testCases := []testCase{
@@ -629,6 +710,145 @@ func TestAttributes_HashValue(t *testing.T) {
 	}
 }
 
+func TestAttributes_Convert(t *testing.T) {
+	testCases := []testCase{
+		{
+			name: "ToInt",
+			inputAttributes: map[string]pdata.AttributeValue{
+				"to.int.string":  pdata.NewAttributeValueString("404"),
+				"to.int.double":  pdata.NewAttributeValueDouble(3.99),
+				"to.int.bool":    pdata.NewAttributeValueBool(true),
+				"to.int.int":     pdata.NewAttributeValueInt(7),
+				"to.int.invalid": pdata.NewAttributeValueString("not a number"),
+			},
+			expectedAttributes: map[string]pdata.AttributeValue{
+				"to.int.string":  pdata.NewAttributeValueInt(404),
+				"to.int.double":  pdata.NewAttributeValueInt(3),
+				"to.int.bool":    pdata.NewAttributeValueInt(1),
+				"to.int.int":     pdata.NewAttributeValueInt(7),
+				"to.int.invalid": pdata.NewAttributeValueString("not a number"),
+			},
+		},
+		{
+			name: "ToDouble",
+			inputAttributes: map[string]pdata.AttributeValue{
+				"to.double.string":  pdata.NewAttributeValueString("1.5"),
+				"to.double.int":     pdata.NewAttributeValueInt(2),
+				"to.double.bool":    pdata.NewAttributeValueBool(false),
+				"to.double.invalid": pdata.NewAttributeValueString("1.5s"),
+			},
+			expectedAttributes: map[string]pdata.AttributeValue{
+				"to.double.string":  pdata.NewAttributeValueDouble(1.5),
+				"to.double.int":     pdata.NewAttributeValueDouble(2),
+				"to.double.bool":    pdata.NewAttributeValueDouble(0),
+				"to.double.invalid": pdata.NewAttributeValueString("1.5s"),
+			},
+		},
+		{
+			name: "ToString",
+			inputAttributes: map[string]pdata.AttributeValue{
+				"to.string.int":    pdata.NewAttributeValueInt(404),
+				"to.string.double": pdata.NewAttributeValueDouble(0.25),
+				"to.string.bool":   pdata.NewAttributeValueBool(true),
+			},
+			expectedAttributes: map[string]pdata.AttributeValue{
+				"to.string.int":    pdata.NewAttributeValueString("404"),
+				"to.string.double": pdata.NewAttributeValueString("0.25"),
+				"to.string.bool":   pdata.NewAttributeValueString("true"),
+			},
+		},
+		{
+			name: "ToBool",
+			inputAttributes: map[string]pdata.AttributeValue{
+				"to.bool.string":  pdata.NewAttributeValueString("true"),
+				"to.bool.int":     pdata.NewAttributeValueInt(0),
+				"to.bool.double":  pdata.NewAttributeValueDouble(0.5),
+				"to.bool.invalid": pdata.NewAttributeValueString("yes"),
+			},
+			expectedAttributes: map[string]pdata.AttributeValue{
+				"to.bool.string":  pdata.NewAttributeValueBool(true),
+				"to.bool.int":     pdata.NewAttributeValueBool(false),
+				"to.bool.double":  pdata.NewAttributeValueBool(true),
+				"to.bool.invalid": pdata.NewAttributeValueString("yes"),
+			},
+		},
+		{
+			name:               "Missing",
+			inputAttributes:    map[string]pdata.AttributeValue{},
+			expectedAttributes: map[string]pdata.AttributeValue{},
+		},
+	}
+
+	var actions []ActionKeyValue
+	for _, tc := range testCases {
+		for k := range tc.inputAttributes {
+			// The target type is the second element of the key.
+			actions = append(actions, ActionKeyValue{Key: k, Action: CONVERT, ConvertedType: strings.Split(k, ".")[1]})
+		}
+	}
+	actions = append(actions, ActionKeyValue{Key: "to.int.missing", Action: CONVERT, ConvertedType: "INT"})
+
+	ap, err := NewAttrProc(&Settings{Actions: actions})
+	require.Nil(t, err)
+	require.NotNil(t, ap)
+
+	for _, tt := range testCases {
+		runIndividualTestCase(t, tt, ap)
+	}
+}
+
+func TestAttributes_Map(t *testing.T) {
+	testCases := []testCase{
+		{
+			name: "MapString",
+			inputAttributes: map[string]pdata.AttributeValue{
+				"net.peer.ip": pdata.NewAttributeValueString("10.1.0.1"),
+			},
+			expectedAttributes: map[string]pdata.AttributeValue{
+				"net.peer.ip": pdata.NewAttributeValueString("payments"),
+			},
+		},
+		{
+			name: "MapInt",
+			inputAttributes: map[string]pdata.AttributeValue{
+				"account.id": pdata.NewAttributeValueInt(210987654321),
+			},
+			expectedAttributes: map[string]pdata.AttributeValue{
+				"account.id": pdata.NewAttributeValueString("search"),
+			},
+		},
+		{
+			name: "NotMapped",
+			inputAttributes: map[string]pdata.AttributeValue{
+				"account.id": pdata.NewAttributeValueInt(1),
+			},
+			expectedAttributes: map[string]pdata.AttributeValue{
+				"account.id": pdata.NewAttributeValueInt(1),
+			},
+		},
+		{
+			name:               "Missing",
+			inputAttributes:    map[string]pdata.AttributeValue{},
+			expectedAttributes: map[string]pdata.AttributeValue{},
+		},
+	}
+
+	cfg := &Settings{
+		Actions: []ActionKeyValue{
+			{Key: "net.peer.ip", Action: MAP, MappingFile: "testdata/mapping.yaml"},
+			{Key: "account.id", Action: MAP, MappingFile: "testdata/mapping.csv", ReloadInterval: time.Minute},
+		},
+	}
+
+	ap, err := NewAttrProc(cfg)
+	require.Nil(t, err)
+	require.NotNil(t, ap)
+
+	for _, tt := range testCases {
+		runIndividualTestCase(t, tt, ap)
+	}
+}
+
 func TestAttributes_FromAttributeNoChange(t *testing.T) {
 	tc := testCase{
 		name: "FromAttributeNoChange",
@@ -771,7 +991,7 @@ func TestInvalidConfig(t *testing.T) {
 			actionLists: []ActionKeyValue{
 				{Key: "MissingValueFromAttributes", Action: INSERT},
 			},
-			errorString: "error creating AttrProc. Either field \"value\" or \"from_attribute\" setting must be specified for 0-th action",
+			errorString: "error creating AttrProc. Either field \"value\", \"from_attribute\", \"from_env\" or \"from_command\" setting must be specified for 0-th action",
 		},
 		{
 			name: "both set value and from attribute",
@@ -780,6 +1000,20 @@ func TestInvalidConfig(t *testing.T) {
 			},
 			errorString: "error creating AttrProc due to both fields \"value\" and \"from_attribute\" being set at the 0-th actions",
 		},
+		{
+			name: "both set from env and from command",
+			actionLists: []ActionKeyValue{
+				{Key: "BothSet", FromEnv: "HOME", FromCommand: "hostname", Action: UPSERT},
+			},
+			errorString: "error creating AttrProc due to more than one of the fields \"value\", \"from_attribute\", \"from_env\" and \"from_command\" being set at the 0-th actions",
+		},
+		{
+			name: "from env for delete",
+			actionLists: []ActionKeyValue{
+				{Key: "key", FromEnv: "HOME", Action: DELETE},
+			},
+			errorString: "error creating AttrProc. Action \"delete\" does not use the \"from_env\" or \"from_command\" field. These must not be specified for 0-th action",
+		},
 		{
 			name: "pattern shouldn't be specified",
 			actionLists: []ActionKeyValue{
@@ -835,6 +1069,62 @@ func TestInvalidConfig(t *testing.T) {
 			},
 			errorString: "error creating AttrProc. Field \"pattern\" contains at least one unnamed matcher group at the 0-th actions",
 		},
+		{
+			name: "missing converted type",
+			actionLists: []ActionKeyValue{
+				{Key: "aa", Action: CONVERT},
+			},
+			errorString: "error creating AttrProc. Field \"converted_type\" has unsupported type \"\", valid types are {int, double, string, bool}, at the 0-th action",
+		},
+		{
+			name: "unsupported converted type",
+			actionLists: []ActionKeyValue{
+				{Key: "aa", Action: CONVERT, ConvertedType: "bytes"},
+			},
+			errorString: "error creating AttrProc. Field \"converted_type\" has unsupported type \"bytes\", valid types are {int, double, string, bool}, at the 0-th action",
+		},
+		{
+			name: "convert with value",
+			actionLists: []ActionKeyValue{
+				{Key: "aa", Value: 123, Action: CONVERT, ConvertedType: "int"},
+			},
+			errorString: "error creating AttrProc. Action \"convert\" does not use \"value\", \"pattern\" or \"from_attribute\" field. These must not be specified for 0-th action",
+		},
+		{
+			name: "converted type with other action",
+			actionLists: []ActionKeyValue{
+				{Key: "aa", Action: DELETE, ConvertedType: "int"},
+			},
+			errorString: "error creating AttrProc. Action \"delete\" does not use the \"converted_type\" field. This must not be specified for 0-th action",
+		},
+		{
+			name: "missing mapping file",
+			actionLists: []ActionKeyValue{
+				{Key: "aa", Action: MAP},
+			},
+			errorString: "error creating AttrProc due to missing required field \"mapping_file\" for action \"map\" at the 0-th action",
+		},
+		{
+			name: "map with from attribute",
+			actionLists: []ActionKeyValue{
+				{Key: "aa", FromAttribute: "bb", Action: MAP, MappingFile: "testdata/mapping.yaml"},
+			},
+			errorString: "error creating AttrProc. Action \"map\" does not use \"value\", \"pattern\" or \"from_attribute\" field. These must not be specified for 0-th action",
+		},
+		{
+			name: "negative reload interval",
+			actionLists: []ActionKeyValue{
+				{Key: "aa", Action: MAP, MappingFile: "testdata/mapping.yaml", ReloadInterval: -time.Second},
+			},
+			errorString: "error creating AttrProc. Field \"reload_interval\" must not be negative at the 0-th action",
+		},
+		{
+			name: "mapping file with other action",
+			actionLists: []ActionKeyValue{
+				{Key: "aa", Action: HASH, MappingFile: "testdata/mapping.yaml"},
+			},
+			errorString: "error creating AttrProc. Action \"hash\" does not use the \"mapping_file\" or \"reload_interval\" field. These must not be specified for 0-th action",
+		},
 	}
 
 	for _, tc := range testcase {
@@ -846,6 +1136,14 @@ func TestInvalidConfig(t *testing.T) {
 	}
 }
 
+func TestInvalidMappingFile(t *testing.T) {
+	ap, err := NewAttrProc(&Settings{Actions: []ActionKeyValue{
+		{Key: "aa", Action: MAP, MappingFile: "testdata/mapping.txt"},
+	}})
+	assert.Nil(t, ap)
+	assert.EqualError(t, err, "error creating AttrProc. Failed to load \"mapping_file\" at the 0-th action: unsupported mapping file extension \".txt\", supported extensions are {.yaml, .yml, .csv}")
+}
+
 func TestValidConfiguration(t *testing.T) {
 	cfg := &Settings{
 		Actions: []ActionKeyValue{
@@ -854,6 +1152,7 @@ func TestValidConfiguration(t *testing.T) {
 			{Key: "three", FromAttribute: "two", Action: "upDaTE"},
 			{Key: "five", FromAttribute: "two", Action: "upsert"},
 			{Key: "two", RegexPattern: "^\\/api\\/v1\\/document\\/(?P<documentId>.*)\\/update$", Action: "EXTRact"},
+			{Key: "six", ConvertedType: "Double", Action: "CONVERT"},
 		},
 	}
 	ap, err := NewAttrProc(cfg)
@@ -869,6 +1168,7 @@ func TestValidConfiguration(t *testing.T) {
 		{Key: "three", FromAttribute: "two", Action: UPDATE},
 		{Key: "five", FromAttribute: "two", Action: UPSERT},
 		{Key: "two", Regex: compiledRegex, AttrNames: []string{"", "documentId"}, Action: EXTRACT},
+		{Key: "six", ConvertedType: pdata.AttributeValueDOUBLE, Action: CONVERT},
 	}, ap.actions)
 
 }
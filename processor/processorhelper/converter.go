@@ -0,0 +1,119 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package processorhelper
+
+import (
+	"strconv"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// convertedTypes maps the supported values of "converted_type" to the types.
+var convertedTypes = map[string]pdata.AttributeValueType{
+	"int":    pdata.AttributeValueINT,
+	"double": pdata.AttributeValueDOUBLE,
+	"string": pdata.AttributeValueSTRING,
+	"bool":   pdata.AttributeValueBOOL,
+}
+
+// convertValue converts an AttributeValue in place to the given type. Strings
+// are parsed, numbers and bools are converted to each other with 0 as false
+// and 1 as true, doubles are truncated when converted to int. Values that
+// cannot be converted, e.g. a string that is not a number when converting to
+// int, maps or arrays, are not changed.
+func convertValue(attr pdata.AttributeValue, to pdata.AttributeValueType) {
+	if attr.Type() == to {
+		return
+	}
+	switch to {
+	case pdata.AttributeValueINT:
+		convertToInt(attr)
+	case pdata.AttributeValueDOUBLE:
+		convertToDouble(attr)
+	case pdata.AttributeValueSTRING:
+		convertToString(attr)
+	case pdata.AttributeValueBOOL:
+		convertToBool(attr)
+	}
+}
+
+func convertToInt(attr pdata.AttributeValue) {
+	switch attr.Type() {
+	case pdata.AttributeValueSTRING:
+		if v, err := strconv.ParseInt(attr.StringVal(), 10, 64); err == nil {
+			attr.SetIntVal(v)
+		}
+	case pdata.AttributeValueDOUBLE:
+		attr.SetIntVal(int64(attr.DoubleVal()))
+	case pdata.AttributeValueBOOL:
+		if attr.BoolVal() {
+			attr.SetIntVal(1)
+		} else {
+			attr.SetIntVal(0)
+		}
+	}
+}
+
+func convertToDouble(attr pdata.AttributeValue) {
+	switch attr.Type() {
+	case pdata.AttributeValueSTRING:
+		if v, err := strconv.ParseFloat(attr.StringVal(), 64); err == nil {
+			attr.SetDoubleVal(v)
+		}
+	case pdata.AttributeValueINT:
+		attr.SetDoubleVal(float64(attr.IntVal()))
+	case pdata.AttributeValueBOOL:
+		if attr.BoolVal() {
+			attr.SetDoubleVal(1)
+		} else {
+			attr.SetDoubleVal(0)
+		}
+	}
+}
+
+func convertToString(attr pdata.AttributeValue) {
+	if v, ok := stringValue(attr); ok {
+		attr.SetStringVal(v)
+	}
+}
+
+// stringValue returns the string representation of a string, int, double or
+// bool AttributeValue, false is returned for other types.
+func stringValue(attr pdata.AttributeValue) (string, bool) {
+	switch attr.Type() {
+	case pdata.AttributeValueSTRING:
+		return attr.StringVal(), true
+	case pdata.AttributeValueINT:
+		return strconv.FormatInt(attr.IntVal(), 10), true
+	case pdata.AttributeValueDOUBLE:
+		return strconv.FormatFloat(attr.DoubleVal(), 'f', -1, 64), true
+	case pdata.AttributeValueBOOL:
+		return strconv.FormatBool(attr.BoolVal()), true
+	}
+	return "", false
+}
+
+func convertToBool(attr pdata.AttributeValue) {
+	switch attr.Type() {
+	case pdata.AttributeValueSTRING:
+		if v, err := strconv.ParseBool(attr.StringVal()); err == nil {
+			attr.SetBoolVal(v)
+		}
+	case pdata.AttributeValueINT:
+		attr.SetBoolVal(attr.IntVal() != 0)
+	case pdata.AttributeValueDOUBLE:
+		attr.SetBoolVal(attr.DoubleVal() != 0)
+	}
+}
@@ -103,6 +103,19 @@ func (f *factory) Type() configmodels.Type {
 	return f.cfgType
 }
 
+// SupportsDataType returns true if the factory creates the processors of the data type.
+func (f *factory) SupportsDataType(dataType configmodels.DataType) bool {
+	switch dataType {
+	case configmodels.TracesDataType:
+		return f.createTraceProcessor != nil
+	case configmodels.MetricsDataType:
+		return f.createMetricsProcessor != nil
+	case configmodels.LogsDataType:
+		return f.createLogsProcessor != nil
+	}
+	return false
+}
+
 // CreateDefaultConfig creates the default configuration for processor.
 func (f *factory) CreateDefaultConfig() configmodels.Processor {
 	return f.createDefaultConfig()
@@ -42,6 +42,10 @@ func TestNewTrace(t *testing.T) {
 	assert.EqualValues(t, defaultCfg, factory.CreateDefaultConfig())
 	_, ok := factory.(component.ConfigUnmarshaler)
 	assert.False(t, ok)
+	ds := factory.(component.DataTypeSupporter)
+	assert.False(t, ds.SupportsDataType(configmodels.TracesDataType))
+	assert.False(t, ds.SupportsDataType(configmodels.MetricsDataType))
+	assert.False(t, ds.SupportsDataType(configmodels.LogsDataType))
 	_, err := factory.CreateTracesProcessor(context.Background(), component.ProcessorCreateParams{}, defaultCfg, nil)
 	assert.Error(t, err)
 	_, err = factory.CreateMetricsProcessor(context.Background(), component.ProcessorCreateParams{}, defaultCfg, nil)
@@ -65,6 +69,11 @@ func TestNewMetrics_WithConstructors(t *testing.T) {
 	assert.True(t, ok)
 	assert.Equal(t, errors.New("my error"), fu.Unmarshal(nil, nil))
 
+	ds := factory.(component.DataTypeSupporter)
+	assert.True(t, ds.SupportsDataType(configmodels.TracesDataType))
+	assert.True(t, ds.SupportsDataType(configmodels.MetricsDataType))
+	assert.True(t, ds.SupportsDataType(configmodels.LogsDataType))
+
 	_, err := factory.CreateTracesProcessor(context.Background(), component.ProcessorCreateParams{}, defaultCfg, nil)
 	assert.NoError(t, err)
 
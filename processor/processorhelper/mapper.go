@@ -0,0 +1,193 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package processorhelper
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// attributeMapping maps attribute values to new values using a table loaded
+// from a YAML or CSV file. If a reload interval is set, the file is checked
+// for modifications at most once per interval and reloaded in the background.
+// If reloading fails, the previously loaded table continues to be used.
+type attributeMapping struct {
+	path           string
+	reloadInterval time.Duration
+
+	// table holds the current *mappingTable.
+	table atomic.Value
+	// nextCheck is the time in unix nanoseconds after which the file is
+	// checked for modifications, it is used atomically.
+	nextCheck int64
+
+	// reloadMu serializes reloads and guards modTime and size.
+	reloadMu sync.Mutex
+	modTime  time.Time
+	size     int64
+}
+
+// mappingTable is a loaded mapping. Keys that are CIDR blocks (e.g. "10.0.0.0/8")
+// match any IP address in the block, the most specific block wins.
+type mappingTable struct {
+	values   map[string]string
+	networks []mappingNetwork
+}
+
+type mappingNetwork struct {
+	network *net.IPNet
+	ones    int
+	value   string
+}
+
+func newAttributeMapping(path string, reloadInterval time.Duration) (*attributeMapping, error) {
+	m := &attributeMapping{
+		path:           path,
+		reloadInterval: reloadInterval,
+	}
+	if err := m.load(); err != nil {
+		return nil, err
+	}
+	atomic.StoreInt64(&m.nextCheck, time.Now().Add(reloadInterval).UnixNano())
+	return m, nil
+}
+
+// lookup returns the value mapped to the key.
+func (m *attributeMapping) lookup(key string) (string, bool) {
+	m.maybeReload()
+	return m.table.Load().(*mappingTable).lookup(key)
+}
+
+// maybeReload starts a reload in the background if the reload interval elapsed.
+func (m *attributeMapping) maybeReload() {
+	if m.reloadInterval <= 0 {
+		return
+	}
+	now := time.Now().UnixNano()
+	next := atomic.LoadInt64(&m.nextCheck)
+	if now < next || !atomic.CompareAndSwapInt64(&m.nextCheck, next, now+int64(m.reloadInterval)) {
+		return
+	}
+	go func() {
+		_ = m.load()
+	}()
+}
+
+// load loads the mapping file, unless it was not modified since last loaded.
+func (m *attributeMapping) load() error {
+	m.reloadMu.Lock()
+	defer m.reloadMu.Unlock()
+
+	info, err := os.Stat(m.path)
+	if err != nil {
+		return err
+	}
+	if info.ModTime().Equal(m.modTime) && info.Size() == m.size {
+		return nil
+	}
+
+	table, err := loadMappingTable(m.path)
+	if err != nil {
+		return err
+	}
+	m.table.Store(table)
+	m.modTime = info.ModTime()
+	m.size = info.Size()
+	return nil
+}
+
+func loadMappingTable(path string) (*mappingTable, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var values map[string]string
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		if err = yaml.Unmarshal(content, &values); err != nil {
+			return nil, fmt.Errorf("failed to parse mapping file %q: %w", path, err)
+		}
+	case ".csv":
+		if values, err = parseCSVMapping(content); err != nil {
+			return nil, fmt.Errorf("failed to parse mapping file %q: %w", path, err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported mapping file extension %q, supported extensions are {.yaml, .yml, .csv}", ext)
+	}
+
+	table := &mappingTable{values: values}
+	for k, v := range values {
+		if !strings.Contains(k, "/") {
+			continue
+		}
+		if _, network, err := net.ParseCIDR(k); err == nil {
+			ones, _ := network.Mask.Size()
+			table.networks = append(table.networks, mappingNetwork{network: network, ones: ones, value: v})
+		}
+	}
+	sort.Slice(table.networks, func(i, j int) bool {
+		return table.networks[i].ones > table.networks[j].ones
+	})
+	return table, nil
+}
+
+// parseCSVMapping parses records of two fields, the key and the value. Lines
+// starting with '#' are ignored.
+func parseCSVMapping(content []byte) (map[string]string, error) {
+	r := csv.NewReader(strings.NewReader(string(content)))
+	r.Comment = '#'
+	r.FieldsPerRecord = 2
+	r.TrimLeadingSpace = true
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]string, len(records))
+	for _, record := range records {
+		values[record[0]] = record[1]
+	}
+	return values, nil
+}
+
+func (t *mappingTable) lookup(key string) (string, bool) {
+	if v, ok := t.values[key]; ok {
+		return v, true
+	}
+	if len(t.networks) == 0 {
+		return "", false
+	}
+	ip := net.ParseIP(key)
+	if ip == nil {
+		return "", false
+	}
+	for _, n := range t.networks {
+		if n.network.Contains(ip) {
+			return n.value, true
+		}
+	}
+	return "", false
+}
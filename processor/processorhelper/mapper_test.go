@@ -0,0 +1,101 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package processorhelper
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttributeMapping_Lookup(t *testing.T) {
+	testCases := []struct {
+		key      string
+		expected string
+		found    bool
+	}{
+		{key: "123456789012", expected: "payments", found: true},
+		{key: "210987654321", expected: "search", found: true},
+		{key: "10.2.3.4", expected: "infrastructure", found: true},
+		{key: "10.1.2.3", expected: "payments", found: true},
+		{key: "10.0.0.0/8", expected: "infrastructure", found: true},
+		{key: "192.168.0.1", found: false},
+		{key: "unknown", found: false},
+	}
+
+	for _, file := range []string{"mapping.yaml", "mapping.csv"} {
+		t.Run(file, func(t *testing.T) {
+			m, err := newAttributeMapping(filepath.Join("testdata", file), 0)
+			require.NoError(t, err)
+			for _, tc := range testCases {
+				value, found := m.lookup(tc.key)
+				assert.Equal(t, tc.found, found, tc.key)
+				assert.Equal(t, tc.expected, value, tc.key)
+			}
+		})
+	}
+}
+
+func TestAttributeMapping_InvalidFile(t *testing.T) {
+	for _, file := range []string{"mapping_invalid.csv", "mapping.txt", "nonexistent.yaml"} {
+		t.Run(file, func(t *testing.T) {
+			m, err := newAttributeMapping(filepath.Join("testdata", file), 0)
+			assert.Error(t, err)
+			assert.Nil(t, m)
+		})
+	}
+}
+
+func TestAttributeMapping_Reload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mapping")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "mapping.yaml")
+	require.NoError(t, ioutil.WriteFile(path, []byte("key: before\n"), 0600))
+
+	m, err := newAttributeMapping(path, time.Millisecond)
+	require.NoError(t, err)
+	value, _ := m.lookup("key")
+	assert.Equal(t, "before", value)
+
+	writeMappingFile(t, path, "key: after the reload\n")
+	assert.Eventually(t, func() bool {
+		value, _ := m.lookup("key")
+		return value == "after the reload"
+	}, 5*time.Second, 5*time.Millisecond)
+
+	// An invalid file keeps the previous mapping.
+	writeMappingFile(t, path, "key: [invalid\n")
+	time.Sleep(10 * time.Millisecond)
+	for i := 0; i < 10; i++ {
+		value, found := m.lookup("key")
+		assert.True(t, found)
+		assert.Equal(t, "after the reload", value)
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// writeMappingFile replaces the file atomically so that a reload never sees it
+// truncated.
+func writeMappingFile(t *testing.T, path, content string) {
+	tmp := path + ".tmp"
+	require.NoError(t, ioutil.WriteFile(tmp, []byte(content), 0600))
+	require.NoError(t, os.Rename(tmp, path))
+}
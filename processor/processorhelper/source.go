@@ -0,0 +1,58 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package processorhelper
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// commandTimeout is the maximum duration of the commands run for the
+// "from_command" field.
+const commandTimeout = 10 * time.Second
+
+// countSources returns the number of fields set to populate the value of the action.
+func countSources(a ActionKeyValue) int {
+	count := 0
+	if a.Value != nil {
+		count++
+	}
+	for _, source := range []string{a.FromAttribute, a.FromEnv, a.FromCommand} {
+		if source != "" {
+			count++
+		}
+	}
+	return count
+}
+
+// commandOutput runs the command and returns its standard output without the
+// trailing white spaces.
+func commandOutput(command string) (string, error) {
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return "", errors.New("empty command")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, args[0], args[1:]...).Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(out), " \t\r\n"), nil
+}
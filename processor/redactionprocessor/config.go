@@ -0,0 +1,55 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redactionprocessor
+
+import (
+	"go.opentelemetry.io/collector/config/configmodels"
+)
+
+// Action is the action applied to the attribute values matching a rule.
+type Action string
+
+const (
+	// Mask replaces the values of the matching keys, or the parts of the
+	// values matching the pattern, by the mask value.
+	Mask Action = "mask"
+	// Remove removes the matching attributes.
+	Remove Action = "remove"
+)
+
+// Rule is a redaction rule.
+type Rule struct {
+	// Name is the name of the rule, it is the rule tag of the redactions
+	// counter.
+	Name string `mapstructure:"name"`
+	// Keys are the attribute keys whose values are redacted.
+	Keys []string `mapstructure:"keys"`
+	// ValuePattern is the regular expression the string values redacted
+	// must match. If both Keys and ValuePattern are set, only the values of
+	// the keys matching the pattern are redacted.
+	ValuePattern string `mapstructure:"value_pattern"`
+	// Action is the action applied to the matching attributes, mask (the
+	// default) or remove.
+	Action Action `mapstructure:"action"`
+}
+
+// Config has the configuration of the redaction processor.
+type Config struct {
+	configmodels.ProcessorSettings `mapstructure:",squash"`
+	// Rules are the redaction rules, applied in order.
+	Rules []Rule `mapstructure:"rules"`
+	// MaskValue is the value replacing the masked values.
+	MaskValue string `mapstructure:"mask_value"`
+}
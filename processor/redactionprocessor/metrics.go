@@ -0,0 +1,47 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redactionprocessor
+
+import (
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+
+	"go.opentelemetry.io/collector/obsreport"
+	"go.opentelemetry.io/collector/processor"
+)
+
+var (
+	tagRuleKey, _ = tag.NewKey("rule")
+
+	statRedactions = stats.Int64("redactions", "Number of attribute values redacted", stats.UnitDimensionless)
+)
+
+// MetricViews returns the metrics views related to the redactions.
+func MetricViews() []*view.View {
+	countRedactionsView := &view.View{
+		Name:        statRedactions.Name(),
+		Measure:     statRedactions,
+		Description: statRedactions.Description(),
+		TagKeys:     []tag.Key{processor.TagProcessorNameKey, tagRuleKey},
+		Aggregation: view.Sum(),
+	}
+
+	legacyViews := []*view.View{
+		countRedactionsView,
+	}
+
+	return obsreport.ProcessorMetricViews(typeStr, legacyViews)
+}
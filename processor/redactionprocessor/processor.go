@@ -0,0 +1,155 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redactionprocessor
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+type tracesRedactionProcessor struct {
+	redactor *redactor
+}
+
+func newTracesRedactionProcessor(r *redactor) *tracesRedactionProcessor {
+	return &tracesRedactionProcessor{redactor: r}
+}
+
+// ProcessTraces redacts the attributes of the resources, spans, span events
+// and span links.
+func (p *tracesRedactionProcessor) ProcessTraces(ctx context.Context, td pdata.Traces) (pdata.Traces, error) {
+	counts := p.redactor.newRedactions()
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		p.redactor.redactAttributes(rs.Resource().Attributes(), counts)
+		ilss := rs.InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			spans := ilss.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				p.redactor.redactAttributes(span.Attributes(), counts)
+				events := span.Events()
+				for l := 0; l < events.Len(); l++ {
+					p.redactor.redactAttributes(events.At(l).Attributes(), counts)
+				}
+				links := span.Links()
+				for l := 0; l < links.Len(); l++ {
+					p.redactor.redactAttributes(links.At(l).Attributes(), counts)
+				}
+			}
+		}
+	}
+	p.redactor.record(ctx, counts)
+	return td, nil
+}
+
+type metricsRedactionProcessor struct {
+	redactor *redactor
+}
+
+func newMetricsRedactionProcessor(r *redactor) *metricsRedactionProcessor {
+	return &metricsRedactionProcessor{redactor: r}
+}
+
+// ProcessMetrics redacts the attributes of the resources and the labels of
+// the data points.
+func (p *metricsRedactionProcessor) ProcessMetrics(ctx context.Context, md pdata.Metrics) (pdata.Metrics, error) {
+	counts := p.redactor.newRedactions()
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		p.redactor.redactAttributes(rm.Resource().Attributes(), counts)
+		ilms := rm.InstrumentationLibraryMetrics()
+		for j := 0; j < ilms.Len(); j++ {
+			metrics := ilms.At(j).Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				forEachDataPointLabels(metrics.At(k), func(labels pdata.StringMap) {
+					p.redactor.redactLabels(labels, counts)
+				})
+			}
+		}
+	}
+	p.redactor.record(ctx, counts)
+	return md, nil
+}
+
+type logsRedactionProcessor struct {
+	redactor *redactor
+}
+
+func newLogsRedactionProcessor(r *redactor) *logsRedactionProcessor {
+	return &logsRedactionProcessor{redactor: r}
+}
+
+// ProcessLogs redacts the attributes of the resources and the log records.
+func (p *logsRedactionProcessor) ProcessLogs(ctx context.Context, ld pdata.Logs) (pdata.Logs, error) {
+	counts := p.redactor.newRedactions()
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		p.redactor.redactAttributes(rl.Resource().Attributes(), counts)
+		ills := rl.InstrumentationLibraryLogs()
+		for j := 0; j < ills.Len(); j++ {
+			logs := ills.At(j).Logs()
+			for k := 0; k < logs.Len(); k++ {
+				p.redactor.redactAttributes(logs.At(k).Attributes(), counts)
+			}
+		}
+	}
+	p.redactor.record(ctx, counts)
+	return ld, nil
+}
+
+func forEachDataPointLabels(metric pdata.Metric, f func(labels pdata.StringMap)) {
+	switch metric.DataType() {
+	case pdata.MetricDataTypeIntGauge:
+		dps := metric.IntGauge().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			f(dps.At(i).LabelsMap())
+		}
+	case pdata.MetricDataTypeDoubleGauge:
+		dps := metric.DoubleGauge().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			f(dps.At(i).LabelsMap())
+		}
+	case pdata.MetricDataTypeIntSum:
+		dps := metric.IntSum().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			f(dps.At(i).LabelsMap())
+		}
+	case pdata.MetricDataTypeDoubleSum:
+		dps := metric.DoubleSum().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			f(dps.At(i).LabelsMap())
+		}
+	case pdata.MetricDataTypeIntHistogram:
+		dps := metric.IntHistogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			f(dps.At(i).LabelsMap())
+		}
+	case pdata.MetricDataTypeDoubleHistogram:
+		dps := metric.DoubleHistogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			f(dps.At(i).LabelsMap())
+		}
+	case pdata.MetricDataTypeDoubleSummary:
+		dps := metric.DoubleSummary().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			f(dps.At(i).LabelsMap())
+		}
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redactionprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func newConfig() *Config {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Rules = []Rule{
+		{Name: "credentials", Keys: []string{"password"}, Action: Remove},
+		{Name: "email", ValuePattern: `[\w.+-]+@[\w-]+\.[\w.]+`},
+	}
+	return cfg
+}
+
+func newSensitiveAttributes() map[string]pdata.AttributeValue {
+	return map[string]pdata.AttributeValue{
+		"password": pdata.NewAttributeValueString("secret"),
+		"user":     pdata.NewAttributeValueString("a@b.com"),
+	}
+}
+
+func newRedactedAttributes() pdata.AttributeMap {
+	return pdata.NewAttributeMap().InitFromMap(map[string]pdata.AttributeValue{
+		"user": pdata.NewAttributeValueString(defaultMaskValue),
+	})
+}
+
+func TestProcessTraces(t *testing.T) {
+	r, err := newRedactor(newConfig())
+	require.NoError(t, err)
+
+	td := pdata.NewTraces()
+	td.ResourceSpans().Resize(1)
+	rs := td.ResourceSpans().At(0)
+	rs.Resource().Attributes().InitFromMap(newSensitiveAttributes())
+	rs.InstrumentationLibrarySpans().Resize(1)
+	rs.InstrumentationLibrarySpans().At(0).Spans().Resize(1)
+	span := rs.InstrumentationLibrarySpans().At(0).Spans().At(0)
+	span.Attributes().InitFromMap(newSensitiveAttributes())
+	span.Events().Resize(1)
+	span.Events().At(0).Attributes().InitFromMap(newSensitiveAttributes())
+	span.Links().Resize(1)
+	span.Links().At(0).Attributes().InitFromMap(newSensitiveAttributes())
+
+	td, err = newTracesRedactionProcessor(r).ProcessTraces(context.Background(), td)
+	require.NoError(t, err)
+
+	assert.Equal(t, newRedactedAttributes(), rs.Resource().Attributes())
+	assert.Equal(t, newRedactedAttributes(), span.Attributes())
+	assert.Equal(t, newRedactedAttributes(), span.Events().At(0).Attributes())
+	assert.Equal(t, newRedactedAttributes(), span.Links().At(0).Attributes())
+}
+
+func TestProcessMetrics(t *testing.T) {
+	r, err := newRedactor(newConfig())
+	require.NoError(t, err)
+
+	md := pdata.NewMetrics()
+	md.ResourceMetrics().Resize(1)
+	rm := md.ResourceMetrics().At(0)
+	rm.Resource().Attributes().InitFromMap(newSensitiveAttributes())
+	rm.InstrumentationLibraryMetrics().Resize(1)
+	metrics := rm.InstrumentationLibraryMetrics().At(0).Metrics()
+	metrics.Resize(2)
+	metrics.At(0).SetDataType(pdata.MetricDataTypeIntSum)
+	metrics.At(0).IntSum().DataPoints().Resize(1)
+	metrics.At(0).IntSum().DataPoints().At(0).LabelsMap().InitFromMap(map[string]string{"password": "secret", "user": "a@b.com"})
+	metrics.At(1).SetDataType(pdata.MetricDataTypeDoubleHistogram)
+	metrics.At(1).DoubleHistogram().DataPoints().Resize(1)
+	metrics.At(1).DoubleHistogram().DataPoints().At(0).LabelsMap().InitFromMap(map[string]string{"host": "h"})
+
+	md, err = newMetricsRedactionProcessor(r).ProcessMetrics(context.Background(), md)
+	require.NoError(t, err)
+
+	assert.Equal(t, newRedactedAttributes(), rm.Resource().Attributes())
+	assert.Equal(t, pdata.NewStringMap().InitFromMap(map[string]string{"user": defaultMaskValue}), metrics.At(0).IntSum().DataPoints().At(0).LabelsMap())
+	assert.Equal(t, pdata.NewStringMap().InitFromMap(map[string]string{"host": "h"}), metrics.At(1).DoubleHistogram().DataPoints().At(0).LabelsMap())
+}
+
+func TestProcessLogs(t *testing.T) {
+	views := MetricViews()
+	require.NoError(t, view.Register(views...))
+	defer view.Unregister(views...)
+
+	r, err := newRedactor(newConfig())
+	require.NoError(t, err)
+
+	ld := pdata.NewLogs()
+	ld.ResourceLogs().Resize(1)
+	rl := ld.ResourceLogs().At(0)
+	rl.Resource().Attributes().InitFromMap(newSensitiveAttributes())
+	rl.InstrumentationLibraryLogs().Resize(1)
+	rl.InstrumentationLibraryLogs().At(0).Logs().Resize(1)
+	record := rl.InstrumentationLibraryLogs().At(0).Logs().At(0)
+	record.Attributes().InitFromMap(newSensitiveAttributes())
+
+	ld, err = newLogsRedactionProcessor(r).ProcessLogs(context.Background(), ld)
+	require.NoError(t, err)
+
+	assert.Equal(t, newRedactedAttributes(), rl.Resource().Attributes())
+	assert.Equal(t, newRedactedAttributes(), record.Attributes())
+
+	rows, err := view.RetrieveData("processor/redaction/" + statRedactions.Name())
+	require.NoError(t, err)
+	counts := map[string]int64{}
+	for _, row := range rows {
+		for _, tg := range row.Tags {
+			if tg.Key == tagRuleKey {
+				counts[tg.Value] = int64(row.Data.(*view.SumData).Value)
+			}
+		}
+	}
+	assert.Equal(t, map[string]int64{"credentials": 2, "email": 2}, counts)
+}
@@ -0,0 +1,210 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redactionprocessor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/tag"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/processor"
+)
+
+var (
+	errNoRules          = errors.New("at least one rule must be configured")
+	errMissingRuleName  = errors.New("name must be configured")
+	errMissingCondition = errors.New("keys or value_pattern must be configured")
+)
+
+// rule is a compiled Rule.
+type rule struct {
+	name         string
+	keys         map[string]bool
+	valuePattern *regexp.Regexp
+	action       Action
+}
+
+// redactor applies the redaction rules to the attribute values.
+type redactor struct {
+	name      string
+	rules     []rule
+	maskValue string
+}
+
+func newRedactor(cfg *Config) (*redactor, error) {
+	if len(cfg.Rules) == 0 {
+		return nil, errNoRules
+	}
+	r := &redactor{
+		name:      cfg.Name(),
+		rules:     make([]rule, len(cfg.Rules)),
+		maskValue: cfg.MaskValue,
+	}
+	for i, ruleCfg := range cfg.Rules {
+		compiled, err := newRule(ruleCfg)
+		if err != nil {
+			if ruleCfg.Name == "" {
+				return nil, fmt.Errorf("invalid rule at index %d: %w", i, err)
+			}
+			return nil, fmt.Errorf("invalid rule %q: %w", ruleCfg.Name, err)
+		}
+		r.rules[i] = compiled
+	}
+	return r, nil
+}
+
+func newRule(cfg Rule) (rule, error) {
+	if cfg.Name == "" {
+		return rule{}, errMissingRuleName
+	}
+	if len(cfg.Keys) == 0 && cfg.ValuePattern == "" {
+		return rule{}, errMissingCondition
+	}
+	r := rule{name: cfg.Name, action: cfg.Action}
+	if r.action == "" {
+		r.action = Mask
+	}
+	if r.action != Mask && r.action != Remove {
+		return rule{}, fmt.Errorf("unsupported action %q, valid actions are {%s, %s}", r.action, Mask, Remove)
+	}
+	if len(cfg.Keys) > 0 {
+		r.keys = make(map[string]bool, len(cfg.Keys))
+		for _, key := range cfg.Keys {
+			r.keys[key] = true
+		}
+	}
+	if cfg.ValuePattern != "" {
+		var err error
+		if r.valuePattern, err = regexp.Compile(cfg.ValuePattern); err != nil {
+			return rule{}, fmt.Errorf("invalid value_pattern: %w", err)
+		}
+	}
+	return r, nil
+}
+
+// redactions counts the redactions of each rule.
+type redactions []int64
+
+func (r *redactor) newRedactions() redactions {
+	return make(redactions, len(r.rules))
+}
+
+// record records the redactions of each rule which redacted at least one
+// value.
+func (r *redactor) record(ctx context.Context, counts redactions) {
+	for i, count := range counts {
+		if count == 0 {
+			continue
+		}
+		_ = stats.RecordWithTags(
+			ctx,
+			[]tag.Mutator{tag.Insert(processor.TagProcessorNameKey, r.name), tag.Insert(tagRuleKey, r.rules[i].name)},
+			statRedactions.M(count))
+	}
+}
+
+// outcome is the outcome of the redaction of an attribute value.
+type outcome int
+
+const (
+	kept outcome = iota
+	masked
+	removed
+)
+
+// redact applies the rules in order to the value of key and returns the
+// redacted value. The values which are not strings are only redacted by the
+// rules matching their key, the masked values are strings.
+func (r *redactor) redact(key string, value string, isString bool, counts redactions) (string, outcome) {
+	result := kept
+	for i, rl := range r.rules {
+		if rl.keys != nil && !rl.keys[key] {
+			continue
+		}
+		if rl.valuePattern != nil {
+			if !isString || !rl.valuePattern.MatchString(value) {
+				continue
+			}
+		}
+		counts[i]++
+		if rl.action == Remove {
+			return "", removed
+		}
+		if rl.valuePattern == nil {
+			value = r.maskValue
+		} else {
+			value = rl.valuePattern.ReplaceAllLiteralString(value, r.maskValue)
+		}
+		isString = true
+		result = masked
+	}
+	return value, result
+}
+
+// redactAttributes applies the rules to the attributes.
+func (r *redactor) redactAttributes(attrs pdata.AttributeMap, counts redactions) {
+	var removedKeys []string
+	var maskedValues map[string]string
+	attrs.ForEach(func(k string, v pdata.AttributeValue) {
+		isString := v.Type() == pdata.AttributeValueSTRING
+		value := ""
+		if isString {
+			value = v.StringVal()
+		}
+		switch redacted, result := r.redact(k, value, isString, counts); result {
+		case removed:
+			removedKeys = append(removedKeys, k)
+		case masked:
+			if maskedValues == nil {
+				maskedValues = make(map[string]string)
+			}
+			maskedValues[k] = redacted
+		}
+	})
+	for _, k := range removedKeys {
+		attrs.Delete(k)
+	}
+	for k, v := range maskedValues {
+		attrs.UpdateString(k, v)
+	}
+}
+
+// redactLabels applies the rules to the labels of a data point.
+func (r *redactor) redactLabels(labels pdata.StringMap, counts redactions) {
+	var removedKeys []string
+	var maskedValues map[string]string
+	labels.ForEach(func(k string, v string) {
+		switch redacted, result := r.redact(k, v, true, counts); result {
+		case removed:
+			removedKeys = append(removedKeys, k)
+		case masked:
+			if maskedValues == nil {
+				maskedValues = make(map[string]string)
+			}
+			maskedValues[k] = redacted
+		}
+	})
+	for _, k := range removedKeys {
+		labels.Delete(k)
+	}
+	for k, v := range maskedValues {
+		labels.Update(k, v)
+	}
+}
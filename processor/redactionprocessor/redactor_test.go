@@ -0,0 +1,127 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redactionprocessor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func TestNewRedactorErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules []Rule
+		err   string
+	}{
+		{
+			name: "no rules",
+			err:  "at least one rule must be configured",
+		},
+		{
+			name:  "missing name",
+			rules: []Rule{{Name: "a", Keys: []string{"k"}}, {Keys: []string{"k"}}},
+			err:   "invalid rule at index 1: name must be configured",
+		},
+		{
+			name:  "missing condition",
+			rules: []Rule{{Name: "a"}},
+			err:   `invalid rule "a": keys or value_pattern must be configured`,
+		},
+		{
+			name:  "invalid action",
+			rules: []Rule{{Name: "a", Keys: []string{"k"}, Action: "hash"}},
+			err:   `invalid rule "a": unsupported action "hash", valid actions are {mask, remove}`,
+		},
+		{
+			name:  "invalid pattern",
+			rules: []Rule{{Name: "a", ValuePattern: "("}},
+			err:   "invalid rule \"a\": invalid value_pattern: error parsing regexp: missing closing ): `(`",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := newRedactor(&Config{Rules: tt.rules})
+			assert.Nil(t, r)
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
+
+func newTestRedactor(t *testing.T) *redactor {
+	r, err := newRedactor(&Config{
+		Rules: []Rule{
+			{Name: "credentials", Keys: []string{"password", "token"}, Action: Remove},
+			{Name: "user", Keys: []string{"user.id"}},
+			{Name: "email", ValuePattern: `[\w.+-]+@[\w-]+\.[\w.]+`},
+			{Name: "bearer", Keys: []string{"authorization"}, ValuePattern: `^Bearer `},
+			{Name: "digits", Keys: []string{"card", "user.id"}, ValuePattern: `\d{4}`},
+		},
+		MaskValue: "***",
+	})
+	require.NoError(t, err)
+	return r
+}
+
+func TestRedactAttributes(t *testing.T) {
+	r := newTestRedactor(t)
+	attrs := pdata.NewAttributeMap().InitFromMap(map[string]pdata.AttributeValue{
+		"password":      pdata.NewAttributeValueString("secret"),
+		"token":         pdata.NewAttributeValueInt(1234),
+		"user.id":       pdata.NewAttributeValueInt(42),
+		"message":       pdata.NewAttributeValueString("sent to a@b.com and c@d.org"),
+		"authorization": pdata.NewAttributeValueString("Bearer abc"),
+		"basic":         pdata.NewAttributeValueString("Bearer abc"),
+		"card":          pdata.NewAttributeValueString("1234 5678"),
+		"other":         pdata.NewAttributeValueInt(1234),
+	})
+	counts := r.newRedactions()
+	r.redactAttributes(attrs, counts)
+
+	expected := pdata.NewAttributeMap().InitFromMap(map[string]pdata.AttributeValue{
+		"user.id":       pdata.NewAttributeValueString("***"),
+		"message":       pdata.NewAttributeValueString("sent to *** and ***"),
+		"authorization": pdata.NewAttributeValueString("***abc"),
+		"basic":         pdata.NewAttributeValueString("Bearer abc"),
+		"card":          pdata.NewAttributeValueString("*** ***"),
+		"other":         pdata.NewAttributeValueInt(1234),
+	})
+	assert.Equal(t, expected.Sort(), attrs.Sort())
+	// The user.id masked by the user rule is not matched by the digits rule.
+	assert.Equal(t, redactions{2, 1, 1, 1, 1}, counts)
+}
+
+func TestRedactLabels(t *testing.T) {
+	r := newTestRedactor(t)
+	labels := pdata.NewStringMap().InitFromMap(map[string]string{
+		"password": "secret",
+		"user.id":  "1234",
+		"email":    "a@b.com",
+		"host":     "host",
+	})
+	counts := r.newRedactions()
+	r.redactLabels(labels, counts)
+
+	expected := pdata.NewStringMap().InitFromMap(map[string]string{
+		"user.id": "***",
+		"email":   "***",
+		"host":    "host",
+	})
+	assert.Equal(t, expected.Sort(), labels.Sort())
+	assert.Equal(t, redactions{1, 1, 1, 0, 0}, counts)
+}
@@ -0,0 +1,38 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcedetectionprocessor
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/config/configmodels"
+)
+
+// Config defines configuration for Resource detection processor.
+type Config struct {
+	configmodels.ProcessorSettings `mapstructure:",squash"`
+
+	// Detectors is the ordered list of detectors to run, the attributes
+	// detected by the first detectors take precedence. It defaults to [env].
+	// The set of values are {env, system, ec2, gce, azure, k8s}.
+	Detectors []string `mapstructure:"detectors"`
+
+	// Timeout is the maximum duration of the detection, it defaults to 5 seconds.
+	Timeout time.Duration `mapstructure:"timeout"`
+
+	// Override indicates whether the detected attributes replace the existing
+	// attributes of the resources, it defaults to true.
+	Override bool `mapstructure:"override"`
+}
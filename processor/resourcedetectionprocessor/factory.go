@@ -0,0 +1,176 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcedetectionprocessor
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/processor/processorhelper"
+	"go.opentelemetry.io/collector/processor/resourcedetectionprocessor/internal"
+	"go.opentelemetry.io/collector/processor/resourcedetectionprocessor/internal/aws/ec2"
+	"go.opentelemetry.io/collector/processor/resourcedetectionprocessor/internal/azure"
+	"go.opentelemetry.io/collector/processor/resourcedetectionprocessor/internal/env"
+	"go.opentelemetry.io/collector/processor/resourcedetectionprocessor/internal/gcp/gce"
+	"go.opentelemetry.io/collector/processor/resourcedetectionprocessor/internal/k8s"
+	"go.opentelemetry.io/collector/processor/resourcedetectionprocessor/internal/system"
+)
+
+const (
+	// The value of "type" key in configuration.
+	typeStr = "resourcedetection"
+
+	defaultTimeout = 5 * time.Second
+)
+
+var processorCapabilities = component.ProcessorCapabilities{MutatesConsumedData: true}
+
+type factory struct {
+	resourceProviderFactory *internal.ResourceProviderFactory
+
+	// providers stores a provider for each named processor that may be
+	// used in several pipelines, so that the detection runs only once.
+	providers map[string]*internal.ResourceProvider
+	lock      sync.Mutex
+}
+
+// NewFactory returns a new factory for the Resource detection processor.
+func NewFactory() component.ProcessorFactory {
+	resourceProviderFactory := internal.NewProviderFactory(map[internal.DetectorType]internal.DetectorFactory{
+		env.TypeStr:    env.NewDetector,
+		system.TypeStr: system.NewDetector,
+		ec2.TypeStr:    ec2.NewDetector,
+		gce.TypeStr:    gce.NewDetector,
+		azure.TypeStr:  azure.NewDetector,
+		k8s.TypeStr:    k8s.NewDetector,
+	})
+
+	f := &factory{
+		resourceProviderFactory: resourceProviderFactory,
+		providers:               map[string]*internal.ResourceProvider{},
+	}
+
+	return processorhelper.NewFactory(
+		typeStr,
+		createDefaultConfig,
+		processorhelper.WithTraces(f.createTraceProcessor),
+		processorhelper.WithMetrics(f.createMetricsProcessor),
+		processorhelper.WithLogs(f.createLogsProcessor))
+}
+
+func createDefaultConfig() configmodels.Processor {
+	return &Config{
+		ProcessorSettings: configmodels.ProcessorSettings{
+			TypeVal: typeStr,
+			NameVal: typeStr,
+		},
+		Timeout:  defaultTimeout,
+		Override: true,
+	}
+}
+
+func (f *factory) createTraceProcessor(
+	_ context.Context,
+	params component.ProcessorCreateParams,
+	cfg configmodels.Processor,
+	nextConsumer consumer.TracesConsumer) (component.TracesProcessor, error) {
+	rdp, err := f.createProcessor(params, cfg.(*Config))
+	if err != nil {
+		return nil, err
+	}
+	return processorhelper.NewTraceProcessor(
+		cfg,
+		nextConsumer,
+		rdp,
+		processorhelper.WithCapabilities(processorCapabilities),
+		processorhelper.WithStart(rdp.Start))
+}
+
+func (f *factory) createMetricsProcessor(
+	_ context.Context,
+	params component.ProcessorCreateParams,
+	cfg configmodels.Processor,
+	nextConsumer consumer.MetricsConsumer) (component.MetricsProcessor, error) {
+	rdp, err := f.createProcessor(params, cfg.(*Config))
+	if err != nil {
+		return nil, err
+	}
+	return processorhelper.NewMetricsProcessor(
+		cfg,
+		nextConsumer,
+		rdp,
+		processorhelper.WithCapabilities(processorCapabilities),
+		processorhelper.WithStart(rdp.Start))
+}
+
+func (f *factory) createLogsProcessor(
+	_ context.Context,
+	params component.ProcessorCreateParams,
+	cfg configmodels.Processor,
+	nextConsumer consumer.LogsConsumer) (component.LogsProcessor, error) {
+	rdp, err := f.createProcessor(params, cfg.(*Config))
+	if err != nil {
+		return nil, err
+	}
+	return processorhelper.NewLogsProcessor(
+		cfg,
+		nextConsumer,
+		rdp,
+		processorhelper.WithCapabilities(processorCapabilities),
+		processorhelper.WithStart(rdp.Start))
+}
+
+func (f *factory) createProcessor(params component.ProcessorCreateParams, cfg *Config) (*resourceDetectionProcessor, error) {
+	provider, err := f.getResourceProvider(params.Logger, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &resourceDetectionProcessor{provider: provider, override: cfg.Override}, nil
+}
+
+func (f *factory) getResourceProvider(logger *zap.Logger, cfg *Config) (*internal.ResourceProvider, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if provider, ok := f.providers[cfg.Name()]; ok {
+		return provider, nil
+	}
+
+	detectors := cfg.Detectors
+	if len(detectors) == 0 {
+		detectors = []string{env.TypeStr}
+	}
+	detectorTypes := make([]internal.DetectorType, 0, len(detectors))
+	for _, detector := range detectors {
+		detectorTypes = append(detectorTypes, internal.DetectorType(detector))
+	}
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	provider, err := f.resourceProviderFactory.CreateResourceProvider(logger, timeout, detectorTypes...)
+	if err != nil {
+		return nil, err
+	}
+	f.providers[cfg.Name()] = provider
+	return provider, nil
+}
@@ -0,0 +1,107 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ec2 provides a detector that loads the resource attributes of an
+// AWS EC2 instance from its instance metadata service.
+package ec2
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/processor/resourcedetectionprocessor/internal"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+// TypeStr is the type of the detector.
+const TypeStr = "ec2"
+
+const (
+	defaultEndpoint = "http://169.254.169.254"
+	tokenPath       = "/latest/api/token"
+	identityPath    = "/latest/dynamic/instance-identity/document"
+	hostnamePath    = "/latest/meta-data/hostname"
+	tokenHeader     = "X-aws-ec2-metadata-token"
+	tokenTTLHeader  = "X-aws-ec2-metadata-token-ttl-seconds"
+	tokenTTLSeconds = "60"
+)
+
+var _ internal.Detector = (*Detector)(nil)
+
+// Detector detects the resource attributes of an EC2 instance.
+type Detector struct {
+	endpoint string
+	client   *http.Client
+	logger   *zap.Logger
+}
+
+// NewDetector creates a new EC2 detector.
+func NewDetector(logger *zap.Logger) (internal.Detector, error) {
+	return &Detector{endpoint: defaultEndpoint, client: &http.Client{}, logger: logger}, nil
+}
+
+// identityDocument contains the fields used of the instance identity document.
+type identityDocument struct {
+	AccountID        string `json:"accountId"`
+	Region           string `json:"region"`
+	AvailabilityZone string `json:"availabilityZone"`
+	InstanceID       string `json:"instanceId"`
+	ImageID          string `json:"imageId"`
+	InstanceType     string `json:"instanceType"`
+}
+
+// Detect returns the resource attributes of the EC2 instance, or an empty
+// resource if the instance metadata service is not available.
+func (d *Detector) Detect(ctx context.Context) (pdata.Resource, error) {
+	res := pdata.NewResource()
+
+	// Use a session token (IMDSv2) if the instance metadata service supports
+	// them, otherwise fall back to IMDSv1.
+	headers := map[string]string{}
+	token, err := internal.FetchMetadata(ctx, d.client, http.MethodPut, d.endpoint+tokenPath, map[string]string{tokenTTLHeader: tokenTTLSeconds})
+	if err == nil {
+		headers[tokenHeader] = string(token)
+	}
+
+	body, err := internal.FetchMetadata(ctx, d.client, http.MethodGet, d.endpoint+identityPath, headers)
+	if err != nil {
+		d.logger.Debug("EC2 instance metadata service is not available", zap.Error(err))
+		return res, nil
+	}
+	var doc identityDocument
+	if err = json.Unmarshal(body, &doc); err != nil {
+		return res, fmt.Errorf("failed parsing EC2 instance identity document: %w", err)
+	}
+	hostname, err := internal.FetchMetadata(ctx, d.client, http.MethodGet, d.endpoint+hostnamePath, headers)
+	if err != nil {
+		return res, fmt.Errorf("failed getting EC2 hostname: %w", err)
+	}
+
+	attrs := res.Attributes()
+	attrs.InsertString(conventions.AttributeCloudProvider, conventions.AttributeCloudProviderAWS)
+	attrs.InsertString(conventions.AttributeCloudInfrastructureService, conventions.AttributeCloudProviderAWSEC2)
+	attrs.InsertString(conventions.AttributeCloudAccount, doc.AccountID)
+	attrs.InsertString(conventions.AttributeCloudRegion, doc.Region)
+	attrs.InsertString(conventions.AttributeCloudZone, doc.AvailabilityZone)
+	attrs.InsertString(conventions.AttributeHostID, doc.InstanceID)
+	attrs.InsertString(conventions.AttributeHostImageID, doc.ImageID)
+	attrs.InsertString(conventions.AttributeHostType, doc.InstanceType)
+	attrs.InsertString(conventions.AttributeHostName, string(hostname))
+	return res, nil
+}
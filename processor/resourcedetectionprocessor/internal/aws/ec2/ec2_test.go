@@ -0,0 +1,106 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ec2
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+const identity = `{
+  "accountId": "123456789012",
+  "availabilityZone": "us-west-2b",
+  "imageId": "ami-5fb8c835",
+  "instanceId": "i-1234567890abcdef0",
+  "instanceType": "t2.micro",
+  "region": "us-west-2"
+}`
+
+// newServer returns an instance metadata service requiring a session token if tokens is true.
+func newServer(tokens bool, identity string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == tokenPath {
+			if !tokens || r.Method != http.MethodPut || r.Header.Get(tokenTTLHeader) == "" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write([]byte("token"))
+			return
+		}
+		if tokens && r.Header.Get(tokenHeader) != "token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case identityPath:
+			_, _ = w.Write([]byte(identity))
+		case hostnamePath:
+			_, _ = w.Write([]byte("ip-172-31-0-1.us-west-2.compute.internal"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestDetect(t *testing.T) {
+	expected := pdata.NewAttributeMap().InitFromMap(map[string]pdata.AttributeValue{
+		conventions.AttributeCloudProvider:              pdata.NewAttributeValueString(conventions.AttributeCloudProviderAWS),
+		conventions.AttributeCloudInfrastructureService: pdata.NewAttributeValueString(conventions.AttributeCloudProviderAWSEC2),
+		conventions.AttributeCloudAccount:               pdata.NewAttributeValueString("123456789012"),
+		conventions.AttributeCloudRegion:                pdata.NewAttributeValueString("us-west-2"),
+		conventions.AttributeCloudZone:                  pdata.NewAttributeValueString("us-west-2b"),
+		conventions.AttributeHostID:                     pdata.NewAttributeValueString("i-1234567890abcdef0"),
+		conventions.AttributeHostImageID:                pdata.NewAttributeValueString("ami-5fb8c835"),
+		conventions.AttributeHostType:                   pdata.NewAttributeValueString("t2.micro"),
+		conventions.AttributeHostName:                   pdata.NewAttributeValueString("ip-172-31-0-1.us-west-2.compute.internal"),
+	}).Sort()
+
+	for _, tokens := range []bool{true, false} {
+		server := newServer(tokens, identity)
+		d := &Detector{endpoint: server.URL, client: server.Client(), logger: zap.NewNop()}
+		res, err := d.Detect(context.Background())
+		server.Close()
+		require.NoError(t, err)
+		assert.Equal(t, expected, res.Attributes().Sort())
+	}
+}
+
+func TestDetect_NotAvailable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	d := &Detector{endpoint: server.URL, client: server.Client(), logger: zap.NewNop()}
+	res, err := d.Detect(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, res.Attributes().Len())
+}
+
+func TestDetect_InvalidIdentity(t *testing.T) {
+	server := newServer(true, "{")
+	defer server.Close()
+
+	d := &Detector{endpoint: server.URL, client: server.Client(), logger: zap.NewNop()}
+	_, err := d.Detect(context.Background())
+	assert.Error(t, err)
+}
@@ -0,0 +1,93 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package azure provides a detector that loads the resource attributes of an
+// Azure virtual machine from its instance metadata service.
+package azure
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/processor/resourcedetectionprocessor/internal"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+// TypeStr is the type of the detector.
+const TypeStr = "azure"
+
+const (
+	defaultEndpoint = "http://169.254.169.254"
+	computePath     = "/metadata/instance/compute?api-version=2020-09-01&format=json"
+
+	attributeResourceGroup = "azure.resourcegroup.name"
+)
+
+var metadataHeaders = map[string]string{"Metadata": "true"}
+
+var _ internal.Detector = (*Detector)(nil)
+
+// Detector detects the resource attributes of an Azure virtual machine.
+type Detector struct {
+	endpoint string
+	client   *http.Client
+	logger   *zap.Logger
+}
+
+// NewDetector creates a new Azure detector.
+func NewDetector(logger *zap.Logger) (internal.Detector, error) {
+	return &Detector{endpoint: defaultEndpoint, client: &http.Client{}, logger: logger}, nil
+}
+
+// computeMetadata contains the fields used of the compute instance metadata.
+type computeMetadata struct {
+	Location          string `json:"location"`
+	Name              string `json:"name"`
+	VMID              string `json:"vmId"`
+	VMSize            string `json:"vmSize"`
+	SubscriptionID    string `json:"subscriptionId"`
+	ResourceGroupName string `json:"resourceGroupName"`
+}
+
+// Detect returns the resource attributes of the Azure virtual machine, or an
+// empty resource if the instance metadata service is not available.
+func (d *Detector) Detect(ctx context.Context) (pdata.Resource, error) {
+	res := pdata.NewResource()
+
+	body, err := internal.FetchMetadata(ctx, d.client, http.MethodGet, d.endpoint+computePath, metadataHeaders)
+	if err != nil {
+		d.logger.Debug("Azure instance metadata service is not available", zap.Error(err))
+		return res, nil
+	}
+	var compute computeMetadata
+	if err = json.Unmarshal(body, &compute); err != nil {
+		return res, fmt.Errorf("failed parsing Azure compute metadata: %w", err)
+	}
+
+	attrs := res.Attributes()
+	attrs.InsertString(conventions.AttributeCloudProvider, conventions.AttributeCloudProviderAzure)
+	attrs.InsertString(conventions.AttributeCloudInfrastructureService, conventions.AttributeCloudProviderAzureVM)
+	attrs.InsertString(conventions.AttributeCloudAccount, compute.SubscriptionID)
+	attrs.InsertString(conventions.AttributeCloudRegion, compute.Location)
+	attrs.InsertString(conventions.AttributeHostID, compute.VMID)
+	attrs.InsertString(conventions.AttributeHostName, compute.Name)
+	attrs.InsertString(conventions.AttributeHostType, compute.VMSize)
+	attrs.InsertString(attributeResourceGroup, compute.ResourceGroupName)
+	return res, nil
+}
@@ -0,0 +1,84 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azure
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+func newServer(compute string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/metadata/instance/compute" || r.Header.Get("Metadata") != "true" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(compute))
+	}))
+}
+
+func TestDetect(t *testing.T) {
+	server := newServer(`{
+  "location": "westeurope",
+  "name": "my-vm",
+  "resourceGroupName": "my-group",
+  "subscriptionId": "8d10da13-8125-4ba9-a717-bf7490507b3d",
+  "vmId": "02aab8a4-74ef-476e-8182-f6d2ba4166a6",
+  "vmSize": "Standard_A3"
+}`)
+	defer server.Close()
+
+	d := &Detector{endpoint: server.URL, client: server.Client(), logger: zap.NewNop()}
+	res, err := d.Detect(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, pdata.NewAttributeMap().InitFromMap(map[string]pdata.AttributeValue{
+		conventions.AttributeCloudProvider:              pdata.NewAttributeValueString(conventions.AttributeCloudProviderAzure),
+		conventions.AttributeCloudInfrastructureService: pdata.NewAttributeValueString(conventions.AttributeCloudProviderAzureVM),
+		conventions.AttributeCloudAccount:               pdata.NewAttributeValueString("8d10da13-8125-4ba9-a717-bf7490507b3d"),
+		conventions.AttributeCloudRegion:                pdata.NewAttributeValueString("westeurope"),
+		conventions.AttributeHostID:                     pdata.NewAttributeValueString("02aab8a4-74ef-476e-8182-f6d2ba4166a6"),
+		conventions.AttributeHostName:                   pdata.NewAttributeValueString("my-vm"),
+		conventions.AttributeHostType:                   pdata.NewAttributeValueString("Standard_A3"),
+		attributeResourceGroup:                          pdata.NewAttributeValueString("my-group"),
+	}).Sort(), res.Attributes().Sort())
+}
+
+func TestDetect_NotAvailable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	d := &Detector{endpoint: server.URL, client: server.Client(), logger: zap.NewNop()}
+	res, err := d.Detect(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, res.Attributes().Len())
+}
+
+func TestDetect_InvalidMetadata(t *testing.T) {
+	server := newServer("{")
+	defer server.Close()
+
+	d := &Detector{endpoint: server.URL, client: server.Client(), logger: zap.NewNop()}
+	_, err := d.Detect(context.Background())
+	assert.Error(t, err)
+}
@@ -0,0 +1,72 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package env provides a detector that loads the resource attributes from
+// the OTEL_RESOURCE_ATTRIBUTES environment variable.
+package env
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/processor/resourcedetectionprocessor/internal"
+)
+
+// TypeStr is the type of the detector.
+const TypeStr = "env"
+
+// envVar is the environment variable holding the comma separated list of
+// key=value attributes.
+const envVar = "OTEL_RESOURCE_ATTRIBUTES"
+
+var _ internal.Detector = (*Detector)(nil)
+
+// Detector detects the resource attributes from the OTEL_RESOURCE_ATTRIBUTES
+// environment variable.
+type Detector struct{}
+
+// NewDetector creates a new environment variable detector.
+func NewDetector(*zap.Logger) (internal.Detector, error) {
+	return &Detector{}, nil
+}
+
+// Detect returns the attributes of the OTEL_RESOURCE_ATTRIBUTES environment
+// variable, the values may be percent-encoded.
+func (d *Detector) Detect(context.Context) (pdata.Resource, error) {
+	res := pdata.NewResource()
+	labels := strings.TrimSpace(os.Getenv(envVar))
+	if labels == "" {
+		return res, nil
+	}
+
+	attrs := res.Attributes()
+	for _, pair := range strings.Split(labels, ",") {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 || strings.TrimSpace(kv[0]) == "" {
+			return pdata.NewResource(), fmt.Errorf("invalid %s format: %q", envVar, labels)
+		}
+		value, err := url.PathUnescape(strings.TrimSpace(kv[1]))
+		if err != nil {
+			return pdata.NewResource(), fmt.Errorf("invalid %s value of %q: %w", envVar, kv[0], err)
+		}
+		attrs.UpsertString(strings.TrimSpace(kv[0]), value)
+	}
+	return res, nil
+}
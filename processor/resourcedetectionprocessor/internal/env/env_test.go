@@ -0,0 +1,78 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package env
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		expected  map[string]pdata.AttributeValue
+		errorText string
+	}{
+		{
+			name:     "unset",
+			expected: map[string]pdata.AttributeValue{},
+		},
+		{
+			name:  "attributes",
+			value: " service.name = checkout, deployment.environment=production,team=a%2Cb ",
+			expected: map[string]pdata.AttributeValue{
+				"service.name":           pdata.NewAttributeValueString("checkout"),
+				"deployment.environment": pdata.NewAttributeValueString("production"),
+				"team":                   pdata.NewAttributeValueString("a,b"),
+			},
+		},
+		{
+			name:      "missing value",
+			value:     "service.name=checkout,team",
+			errorText: `invalid OTEL_RESOURCE_ATTRIBUTES format: "service.name=checkout,team"`,
+		},
+		{
+			name:      "invalid encoding",
+			value:     "team=%zz",
+			errorText: `invalid OTEL_RESOURCE_ATTRIBUTES value of "team": invalid URL escape "%zz"`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.NoError(t, os.Setenv(envVar, test.value))
+			defer os.Unsetenv(envVar)
+
+			d, err := NewDetector(zap.NewNop())
+			require.NoError(t, err)
+			res, err := d.Detect(context.Background())
+			if test.errorText != "" {
+				assert.EqualError(t, err, test.errorText)
+				assert.Equal(t, 0, res.Attributes().Len())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, pdata.NewAttributeMap().InitFromMap(test.expected).Sort(), res.Attributes().Sort())
+		})
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package gce provides a detector that loads the resource attributes of a
+// Google Compute Engine instance from its metadata server.
+package gce
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/processor/resourcedetectionprocessor/internal"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+// TypeStr is the type of the detector.
+const TypeStr = "gce"
+
+const (
+	defaultEndpoint = "http://metadata.google.internal"
+	metadataPath    = "/computeMetadata/v1/"
+)
+
+var metadataHeaders = map[string]string{"Metadata-Flavor": "Google"}
+
+var _ internal.Detector = (*Detector)(nil)
+
+// Detector detects the resource attributes of a GCE instance.
+type Detector struct {
+	endpoint string
+	client   *http.Client
+	logger   *zap.Logger
+}
+
+// NewDetector creates a new GCE detector.
+func NewDetector(logger *zap.Logger) (internal.Detector, error) {
+	return &Detector{endpoint: defaultEndpoint, client: &http.Client{}, logger: logger}, nil
+}
+
+// Detect returns the resource attributes of the GCE instance, or an empty
+// resource if the metadata server is not available.
+func (d *Detector) Detect(ctx context.Context) (pdata.Resource, error) {
+	res := pdata.NewResource()
+
+	projectID, err := d.get(ctx, "project/project-id")
+	if err != nil {
+		d.logger.Debug("GCE metadata server is not available", zap.Error(err))
+		return res, nil
+	}
+
+	values := map[string]string{}
+	for _, path := range []string{"instance/id", "instance/zone", "instance/hostname", "instance/machine-type"} {
+		value, err := d.get(ctx, path)
+		if err != nil {
+			return res, fmt.Errorf("failed getting GCE %s: %w", path, err)
+		}
+		values[path] = value
+	}
+	// The zone and machine type are returned as "projects/<project number>/zones/<zone>"
+	// and "projects/<project number>/machineTypes/<machine type>".
+	zone := lastSegment(values["instance/zone"])
+
+	attrs := res.Attributes()
+	attrs.InsertString(conventions.AttributeCloudProvider, conventions.AttributeCloudProviderGCP)
+	attrs.InsertString(conventions.AttributeCloudInfrastructureService, conventions.AttributeCloudProviderGCPComputeEngine)
+	attrs.InsertString(conventions.AttributeCloudAccount, projectID)
+	attrs.InsertString(conventions.AttributeCloudZone, zone)
+	if i := strings.LastIndex(zone, "-"); i > 0 {
+		attrs.InsertString(conventions.AttributeCloudRegion, zone[:i])
+	}
+	attrs.InsertString(conventions.AttributeHostID, values["instance/id"])
+	attrs.InsertString(conventions.AttributeHostName, values["instance/hostname"])
+	attrs.InsertString(conventions.AttributeHostType, lastSegment(values["instance/machine-type"]))
+	return res, nil
+}
+
+func (d *Detector) get(ctx context.Context, path string) (string, error) {
+	body, err := internal.FetchMetadata(ctx, d.client, http.MethodGet, d.endpoint+metadataPath+path, metadataHeaders)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(body)), nil
+}
+
+func lastSegment(s string) string {
+	return s[strings.LastIndex(s, "/")+1:]
+}
@@ -0,0 +1,86 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gce
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+func newServer(metadata map[string]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		value, ok := metadata[r.URL.Path]
+		if !ok || r.Header.Get("Metadata-Flavor") != "Google" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(value))
+	}))
+}
+
+func TestDetect(t *testing.T) {
+	server := newServer(map[string]string{
+		metadataPath + "project/project-id":    "my-project",
+		metadataPath + "instance/id":           "4520031799277581759",
+		metadataPath + "instance/zone":         "projects/123456789/zones/us-central1-a",
+		metadataPath + "instance/hostname":     "my-instance.c.my-project.internal",
+		metadataPath + "instance/machine-type": "projects/123456789/machineTypes/n1-standard-1",
+	})
+	defer server.Close()
+
+	d := &Detector{endpoint: server.URL, client: server.Client(), logger: zap.NewNop()}
+	res, err := d.Detect(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, pdata.NewAttributeMap().InitFromMap(map[string]pdata.AttributeValue{
+		conventions.AttributeCloudProvider:              pdata.NewAttributeValueString(conventions.AttributeCloudProviderGCP),
+		conventions.AttributeCloudInfrastructureService: pdata.NewAttributeValueString(conventions.AttributeCloudProviderGCPComputeEngine),
+		conventions.AttributeCloudAccount:               pdata.NewAttributeValueString("my-project"),
+		conventions.AttributeCloudRegion:                pdata.NewAttributeValueString("us-central1"),
+		conventions.AttributeCloudZone:                  pdata.NewAttributeValueString("us-central1-a"),
+		conventions.AttributeHostID:                     pdata.NewAttributeValueString("4520031799277581759"),
+		conventions.AttributeHostName:                   pdata.NewAttributeValueString("my-instance.c.my-project.internal"),
+		conventions.AttributeHostType:                   pdata.NewAttributeValueString("n1-standard-1"),
+	}).Sort(), res.Attributes().Sort())
+}
+
+func TestDetect_NotAvailable(t *testing.T) {
+	server := newServer(nil)
+	defer server.Close()
+
+	d := &Detector{endpoint: server.URL, client: server.Client(), logger: zap.NewNop()}
+	res, err := d.Detect(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, res.Attributes().Len())
+}
+
+func TestDetect_MissingMetadata(t *testing.T) {
+	server := newServer(map[string]string{
+		metadataPath + "project/project-id": "my-project",
+	})
+	defer server.Close()
+
+	d := &Detector{endpoint: server.URL, client: server.Client(), logger: zap.NewNop()}
+	_, err := d.Detect(context.Background())
+	assert.EqualError(t, err, `failed getting GCE instance/id: metadata request to "`+server.URL+metadataPath+`instance/id" failed with status 404`)
+}
@@ -0,0 +1,102 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package k8s provides a detector that detects the pod the collector runs in
+// on Kubernetes.
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/processor/resourcedetectionprocessor/internal"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+// TypeStr is the type of the detector.
+const TypeStr = "k8s"
+
+const (
+	// serviceHostEnvVar is set by Kubernetes in all the containers.
+	serviceHostEnvVar = "KUBERNETES_SERVICE_HOST"
+
+	// The following environment variables are expected to be set with the
+	// downward API, the pod name and namespace default to the host name and
+	// the namespace of the service account.
+	podNameEnvVar   = "K8S_POD_NAME"
+	podUIDEnvVar    = "K8S_POD_UID"
+	namespaceEnvVar = "K8S_NAMESPACE"
+	nodeNameEnvVar  = "K8S_NODE_NAME"
+
+	defaultNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+)
+
+var _ internal.Detector = (*Detector)(nil)
+
+// Detector detects the pod the collector runs in.
+type Detector struct {
+	namespaceFile string
+	hostname      func() (string, error)
+}
+
+// NewDetector creates a new Kubernetes detector.
+func NewDetector(*zap.Logger) (internal.Detector, error) {
+	return &Detector{namespaceFile: defaultNamespaceFile, hostname: os.Hostname}, nil
+}
+
+// Detect returns the k8s.pod.name, k8s.pod.uid, k8s.namespace.name and
+// k8s.node.name attributes which are known, or an empty resource if the
+// collector doesn't run on Kubernetes.
+func (d *Detector) Detect(context.Context) (pdata.Resource, error) {
+	res := pdata.NewResource()
+	if os.Getenv(serviceHostEnvVar) == "" {
+		return res, nil
+	}
+
+	podName := os.Getenv(podNameEnvVar)
+	if podName == "" {
+		hostname, err := d.hostname()
+		if err != nil {
+			return res, fmt.Errorf("failed getting host name: %w", err)
+		}
+		podName = hostname
+	}
+	namespace := os.Getenv(namespaceEnvVar)
+	if namespace == "" {
+		// The namespace file is missing if the service account token isn't
+		// mounted, the namespace is unknown then.
+		if content, err := ioutil.ReadFile(d.namespaceFile); err == nil {
+			namespace = strings.TrimSpace(string(content))
+		}
+	}
+
+	attrs := res.Attributes()
+	attrs.InsertString(conventions.AttributeK8sPod, podName)
+	insertIfNotEmpty(attrs, conventions.AttributeK8sPodUID, os.Getenv(podUIDEnvVar))
+	insertIfNotEmpty(attrs, conventions.AttributeK8sNamespace, namespace)
+	insertIfNotEmpty(attrs, conventions.AttributeK8sNodeName, os.Getenv(nodeNameEnvVar))
+	return res, nil
+}
+
+func insertIfNotEmpty(attrs pdata.AttributeMap, key, value string) {
+	if value != "" {
+		attrs.InsertString(key, value)
+	}
+}
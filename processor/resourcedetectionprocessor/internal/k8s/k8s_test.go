@@ -0,0 +1,98 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8s
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+func setEnv(t *testing.T, env map[string]string) func() {
+	for k, v := range env {
+		require.NoError(t, os.Setenv(k, v))
+	}
+	return func() {
+		for k := range env {
+			os.Unsetenv(k)
+		}
+	}
+}
+
+func TestDetect_NotOnKubernetes(t *testing.T) {
+	defer setEnv(t, map[string]string{serviceHostEnvVar: ""})()
+
+	d, err := NewDetector(zap.NewNop())
+	require.NoError(t, err)
+	res, err := d.Detect(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, res.Attributes().Len())
+}
+
+func TestDetect_DownwardAPI(t *testing.T) {
+	defer setEnv(t, map[string]string{
+		serviceHostEnvVar: "10.0.0.1",
+		podNameEnvVar:     "collector-abc",
+		podUIDEnvVar:      "uid-1",
+		namespaceEnvVar:   "monitoring",
+		nodeNameEnvVar:    "node-1",
+	})()
+
+	d, err := NewDetector(zap.NewNop())
+	require.NoError(t, err)
+	res, err := d.Detect(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, pdata.NewAttributeMap().InitFromMap(map[string]pdata.AttributeValue{
+		conventions.AttributeK8sPod:       pdata.NewAttributeValueString("collector-abc"),
+		conventions.AttributeK8sPodUID:    pdata.NewAttributeValueString("uid-1"),
+		conventions.AttributeK8sNamespace: pdata.NewAttributeValueString("monitoring"),
+		conventions.AttributeK8sNodeName:  pdata.NewAttributeValueString("node-1"),
+	}).Sort(), res.Attributes().Sort())
+}
+
+func TestDetect_Defaults(t *testing.T) {
+	defer setEnv(t, map[string]string{serviceHostEnvVar: "10.0.0.1"})()
+
+	dir, err := ioutil.TempDir("", "k8s")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	namespaceFile := filepath.Join(dir, "namespace")
+	require.NoError(t, ioutil.WriteFile(namespaceFile, []byte("default\n"), 0600))
+
+	d := &Detector{namespaceFile: namespaceFile, hostname: func() (string, error) { return "collector-xyz", nil }}
+	res, err := d.Detect(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, pdata.NewAttributeMap().InitFromMap(map[string]pdata.AttributeValue{
+		conventions.AttributeK8sPod:       pdata.NewAttributeValueString("collector-xyz"),
+		conventions.AttributeK8sNamespace: pdata.NewAttributeValueString("default"),
+	}).Sort(), res.Attributes().Sort())
+
+	// The namespace is unknown without the service account.
+	d.namespaceFile = filepath.Join(dir, "nonexistent")
+	res, err = d.Detect(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, pdata.NewAttributeMap().InitFromMap(map[string]pdata.AttributeValue{
+		conventions.AttributeK8sPod: pdata.NewAttributeValueString("collector-xyz"),
+	}).Sort(), res.Attributes().Sort())
+}
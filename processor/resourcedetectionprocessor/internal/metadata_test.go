@@ -0,0 +1,48 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetchMetadata(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Metadata") != "true" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(r.Method + " " + r.URL.Path))
+	}))
+	defer server.Close()
+
+	body, err := FetchMetadata(context.Background(), server.Client(), http.MethodPut, server.URL+"/metadata", map[string]string{"Metadata": "true"})
+	require.NoError(t, err)
+	assert.Equal(t, "PUT /metadata", string(body))
+
+	_, err = FetchMetadata(context.Background(), server.Client(), http.MethodGet, server.URL+"/metadata", nil)
+	assert.EqualError(t, err, `metadata request to "`+server.URL+`/metadata" failed with status 400`)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = FetchMetadata(ctx, server.Client(), http.MethodGet, server.URL+"/metadata", nil)
+	assert.Error(t, err)
+}
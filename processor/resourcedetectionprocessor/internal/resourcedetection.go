@@ -0,0 +1,139 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package internal contains the resource provider shared by the detectors of
+// the resource detection processor.
+package internal
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+)
+
+// DetectorType is the name of a detector in the configuration.
+type DetectorType string
+
+// Detector detects the resource of the platform the collector runs on. It
+// returns an empty resource if the collector doesn't run on the platform.
+type Detector interface {
+	Detect(ctx context.Context) (pdata.Resource, error)
+}
+
+// DetectorFactory creates a Detector.
+type DetectorFactory func(logger *zap.Logger) (Detector, error)
+
+// ResourceProviderFactory creates the ResourceProviders from the configured
+// detector types.
+type ResourceProviderFactory struct {
+	detectorFactories map[DetectorType]DetectorFactory
+}
+
+// NewProviderFactory returns a ResourceProviderFactory supporting the given detectors.
+func NewProviderFactory(detectorFactories map[DetectorType]DetectorFactory) *ResourceProviderFactory {
+	return &ResourceProviderFactory{detectorFactories: detectorFactories}
+}
+
+// CreateResourceProvider creates a ResourceProvider running the detectors of
+// the given types, the attributes detected by the first ones take precedence.
+func (f *ResourceProviderFactory) CreateResourceProvider(
+	logger *zap.Logger,
+	timeout time.Duration,
+	detectorTypes ...DetectorType) (*ResourceProvider, error) {
+	detectors := make([]Detector, 0, len(detectorTypes))
+	for _, detectorType := range detectorTypes {
+		detectorFactory, ok := f.detectorFactories[detectorType]
+		if !ok {
+			return nil, fmt.Errorf("invalid detector key: %q", detectorType)
+		}
+		detector, err := detectorFactory(logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed creating detector type %q: %w", detectorType, err)
+		}
+		detectors = append(detectors, detector)
+	}
+	return &ResourceProvider{logger: logger, timeout: timeout, detectors: detectors}, nil
+}
+
+// ResourceProvider runs the detectors once and caches the detected resource.
+type ResourceProvider struct {
+	logger    *zap.Logger
+	timeout   time.Duration
+	detectors []Detector
+
+	once     sync.Once
+	resource pdata.Resource
+	err      error
+}
+
+// Get returns the resource detected by all the detectors, they are run the
+// first time Get is called and their results are reused afterwards.
+func (p *ResourceProvider) Get(ctx context.Context) (pdata.Resource, error) {
+	p.once.Do(func() {
+		ctx, cancel := context.WithTimeout(ctx, p.timeout)
+		defer cancel()
+		p.resource, p.err = p.detectResource(ctx)
+	})
+	return p.resource, p.err
+}
+
+// detectResource runs the detectors concurrently, so that a detector waiting
+// for an unreachable metadata endpoint doesn't delay the other ones, and
+// merges their results in order.
+func (p *ResourceProvider) detectResource(ctx context.Context) (pdata.Resource, error) {
+	resources := make([]pdata.Resource, len(p.detectors))
+	errs := make([]error, len(p.detectors))
+	var wg sync.WaitGroup
+	for i, detector := range p.detectors {
+		wg.Add(1)
+		go func(i int, detector Detector) {
+			defer wg.Done()
+			resources[i], errs[i] = detector.Detect(ctx)
+		}(i, detector)
+	}
+	wg.Wait()
+
+	res := pdata.NewResource()
+	for i := range p.detectors {
+		if errs[i] != nil {
+			return res, errs[i]
+		}
+		MergeResource(res, resources[i], false)
+	}
+	attrs := map[string]string{}
+	res.Attributes().ForEach(func(k string, v pdata.AttributeValue) {
+		attrs[k] = tracetranslator.AttributeValueToString(v, false)
+	})
+	p.logger.Info("detected resource information", zap.Any("resource", attrs))
+	return res, nil
+}
+
+// MergeResource copies the attributes of from into to. The existing
+// attributes of to are replaced only if overrideTo is true.
+func MergeResource(to, from pdata.Resource, overrideTo bool) {
+	toAttrs := to.Attributes()
+	from.Attributes().ForEach(func(k string, v pdata.AttributeValue) {
+		if overrideTo {
+			toAttrs.Upsert(k, v)
+		} else {
+			toAttrs.Insert(k, v)
+		}
+	})
+}
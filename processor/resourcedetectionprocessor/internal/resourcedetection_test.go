@@ -0,0 +1,124 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+type mockDetector struct {
+	attributes map[string]string
+	err        error
+	calls      int
+}
+
+func (d *mockDetector) Detect(context.Context) (pdata.Resource, error) {
+	d.calls++
+	res := pdata.NewResource()
+	for k, v := range d.attributes {
+		res.Attributes().InsertString(k, v)
+	}
+	return res, d.err
+}
+
+func mockDetectorFactory(d *mockDetector) DetectorFactory {
+	return func(*zap.Logger) (Detector, error) {
+		return d, nil
+	}
+}
+
+func TestResourceProvider(t *testing.T) {
+	first := &mockDetector{attributes: map[string]string{"a": "1", "b": "1"}}
+	second := &mockDetector{attributes: map[string]string{"b": "2", "c": "2"}}
+	factory := NewProviderFactory(map[DetectorType]DetectorFactory{
+		"first":  mockDetectorFactory(first),
+		"second": mockDetectorFactory(second),
+	})
+
+	provider, err := factory.CreateResourceProvider(zap.NewNop(), time.Second, "first", "second")
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		res, err := provider.Get(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, pdata.NewAttributeMap().InitFromMap(map[string]pdata.AttributeValue{
+			"a": pdata.NewAttributeValueString("1"),
+			"b": pdata.NewAttributeValueString("1"),
+			"c": pdata.NewAttributeValueString("2"),
+		}).Sort(), res.Attributes().Sort())
+	}
+
+	// The detectors run only once.
+	assert.Equal(t, 1, first.calls)
+	assert.Equal(t, 1, second.calls)
+}
+
+func TestResourceProvider_DetectorError(t *testing.T) {
+	factory := NewProviderFactory(map[DetectorType]DetectorFactory{
+		"ok":     mockDetectorFactory(&mockDetector{attributes: map[string]string{"a": "1"}}),
+		"failed": mockDetectorFactory(&mockDetector{err: errors.New("detection failed")}),
+	})
+
+	provider, err := factory.CreateResourceProvider(zap.NewNop(), time.Second, "ok", "failed")
+	require.NoError(t, err)
+	_, err = provider.Get(context.Background())
+	assert.EqualError(t, err, "detection failed")
+}
+
+func TestCreateResourceProvider_Invalid(t *testing.T) {
+	factory := NewProviderFactory(map[DetectorType]DetectorFactory{
+		"failed": func(*zap.Logger) (Detector, error) {
+			return nil, errors.New("creation failed")
+		},
+	})
+
+	_, err := factory.CreateResourceProvider(zap.NewNop(), time.Second, "unknown")
+	assert.EqualError(t, err, `invalid detector key: "unknown"`)
+
+	_, err = factory.CreateResourceProvider(zap.NewNop(), time.Second, "failed")
+	assert.EqualError(t, err, `failed creating detector type "failed": creation failed`)
+}
+
+func TestMergeResource(t *testing.T) {
+	newResource := func(attrs map[string]string) pdata.Resource {
+		res := pdata.NewResource()
+		for k, v := range attrs {
+			res.Attributes().InsertString(k, v)
+		}
+		res.Attributes().Sort()
+		return res
+	}
+
+	from := newResource(map[string]string{"a": "new", "b": "new"})
+
+	to := newResource(map[string]string{"a": "old", "c": "old"})
+	MergeResource(to, from, false)
+	to.Attributes().Sort()
+	assert.Equal(t, newResource(map[string]string{"a": "old", "b": "new", "c": "old"}), to)
+
+	to = newResource(map[string]string{"a": "old", "c": "old"})
+	MergeResource(to, from, true)
+	to.Attributes().Sort()
+	assert.Equal(t, newResource(map[string]string{"a": "new", "b": "new", "c": "old"}), to)
+}
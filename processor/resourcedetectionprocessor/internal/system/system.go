@@ -0,0 +1,59 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package system provides a detector that detects the host name and the
+// operating system of the local host.
+package system
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/processor/resourcedetectionprocessor/internal"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+// TypeStr is the type of the detector.
+const TypeStr = "system"
+
+var _ internal.Detector = (*Detector)(nil)
+
+// Detector detects the host name and the operating system type.
+type Detector struct {
+	hostname func() (string, error)
+}
+
+// NewDetector creates a new system detector.
+func NewDetector(*zap.Logger) (internal.Detector, error) {
+	return &Detector{hostname: os.Hostname}, nil
+}
+
+// Detect returns the host.name and os.type attributes.
+func (d *Detector) Detect(context.Context) (pdata.Resource, error) {
+	res := pdata.NewResource()
+	hostname, err := d.hostname()
+	if err != nil {
+		return res, fmt.Errorf("failed getting host name: %w", err)
+	}
+
+	attrs := res.Attributes()
+	attrs.InsertString(conventions.AttributeHostName, hostname)
+	attrs.InsertString(conventions.AttributeOSType, runtime.GOOS)
+	return res, nil
+}
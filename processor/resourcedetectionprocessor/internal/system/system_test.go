@@ -0,0 +1,56 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package system
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+func TestNewDetector(t *testing.T) {
+	d, err := NewDetector(zap.NewNop())
+	require.NoError(t, err)
+	res, err := d.Detect(context.Background())
+	require.NoError(t, err)
+	hostname, ok := res.Attributes().Get(conventions.AttributeHostName)
+	require.True(t, ok)
+	assert.NotEmpty(t, hostname.StringVal())
+}
+
+func TestDetect(t *testing.T) {
+	d := &Detector{hostname: func() (string, error) { return "my-host", nil }}
+	res, err := d.Detect(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, pdata.NewAttributeMap().InitFromMap(map[string]pdata.AttributeValue{
+		conventions.AttributeHostName: pdata.NewAttributeValueString("my-host"),
+		conventions.AttributeOSType:   pdata.NewAttributeValueString(runtime.GOOS),
+	}).Sort(), res.Attributes().Sort())
+}
+
+func TestDetectError(t *testing.T) {
+	d := &Detector{hostname: func() (string, error) { return "", errors.New("no hostname") }}
+	res, err := d.Detect(context.Background())
+	assert.EqualError(t, err, "failed getting host name: no hostname")
+	assert.Equal(t, 0, res.Attributes().Len())
+}
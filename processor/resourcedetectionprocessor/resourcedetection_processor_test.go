@@ -0,0 +1,114 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcedetectionprocessor
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal/testdata"
+)
+
+func TestResourceDetectionProcessor(t *testing.T) {
+	require.NoError(t, os.Setenv("OTEL_RESOURCE_ATTRIBUTES", "cloud.zone=zone-1,host.name=detected-host"))
+	defer os.Unsetenv("OTEL_RESOURCE_ATTRIBUTES")
+
+	tests := []struct {
+		name     string
+		override bool
+		expected map[string]pdata.AttributeValue
+	}{
+		{
+			name:     "override",
+			override: true,
+			expected: map[string]pdata.AttributeValue{
+				"resource-attr": pdata.NewAttributeValueString("resource-attr-val-1"),
+				"cloud.zone":    pdata.NewAttributeValueString("zone-1"),
+				"host.name":     pdata.NewAttributeValueString("detected-host"),
+			},
+		},
+		{
+			name:     "no override",
+			override: false,
+			expected: map[string]pdata.AttributeValue{
+				"resource-attr": pdata.NewAttributeValueString("resource-attr-val-1"),
+				"cloud.zone":    pdata.NewAttributeValueString("zone-1"),
+				"host.name":     pdata.NewAttributeValueString("existing-host"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			factory := NewFactory()
+			cfg := factory.CreateDefaultConfig().(*Config)
+			cfg.Override = tt.override
+			params := component.ProcessorCreateParams{Logger: zap.NewNop()}
+			expected := pdata.NewAttributeMap().InitFromMap(tt.expected).Sort()
+
+			// Test trace consumer
+			tn := new(consumertest.TracesSink)
+			tp, err := factory.CreateTracesProcessor(context.Background(), params, cfg, tn)
+			require.NoError(t, err)
+			require.NoError(t, tp.Start(context.Background(), componenttest.NewNopHost()))
+			td := testdata.GenerateTraceDataOneSpan()
+			td.ResourceSpans().At(0).Resource().Attributes().InsertString("host.name", "existing-host")
+			require.NoError(t, tp.ConsumeTraces(context.Background(), td))
+			require.Len(t, tn.AllTraces(), 1)
+			assert.Equal(t, expected, tn.AllTraces()[0].ResourceSpans().At(0).Resource().Attributes().Sort())
+
+			// Test metrics consumer
+			mn := new(consumertest.MetricsSink)
+			mp, err := factory.CreateMetricsProcessor(context.Background(), params, cfg, mn)
+			require.NoError(t, err)
+			require.NoError(t, mp.Start(context.Background(), componenttest.NewNopHost()))
+			md := testdata.GenerateMetricsOneMetric()
+			md.ResourceMetrics().At(0).Resource().Attributes().InsertString("host.name", "existing-host")
+			require.NoError(t, mp.ConsumeMetrics(context.Background(), md))
+			require.Len(t, mn.AllMetrics(), 1)
+			assert.Equal(t, expected, mn.AllMetrics()[0].ResourceMetrics().At(0).Resource().Attributes().Sort())
+
+			// Test logs consumer
+			ln := new(consumertest.LogsSink)
+			lp, err := factory.CreateLogsProcessor(context.Background(), params, cfg, ln)
+			require.NoError(t, err)
+			require.NoError(t, lp.Start(context.Background(), componenttest.NewNopHost()))
+			ld := testdata.GenerateLogDataOneLog()
+			ld.ResourceLogs().At(0).Resource().Attributes().InsertString("host.name", "existing-host")
+			require.NoError(t, lp.ConsumeLogs(context.Background(), ld))
+			require.Len(t, ln.AllLogs(), 1)
+			assert.Equal(t, expected, ln.AllLogs()[0].ResourceLogs().At(0).Resource().Attributes().Sort())
+		})
+	}
+}
+
+func TestResourceDetectionProcessor_StartError(t *testing.T) {
+	require.NoError(t, os.Setenv("OTEL_RESOURCE_ATTRIBUTES", "invalid"))
+	defer os.Unsetenv("OTEL_RESOURCE_ATTRIBUTES")
+
+	factory := NewFactory()
+	tp, err := factory.CreateTracesProcessor(context.Background(), component.ProcessorCreateParams{Logger: zap.NewNop()}, factory.CreateDefaultConfig(), consumertest.NewTracesNop())
+	require.NoError(t, err)
+	assert.EqualError(t, tp.Start(context.Background(), componenttest.NewNopHost()), `invalid OTEL_RESOURCE_ATTRIBUTES format: "invalid"`)
+}
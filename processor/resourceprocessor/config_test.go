@@ -45,6 +45,7 @@ func TestLoadConfig(t *testing.T) {
 			{Key: "cloud.zone", Value: "zone-1", Action: processorhelper.UPSERT},
 			{Key: "k8s.cluster.name", FromAttribute: "k8s-cluster", Action: processorhelper.INSERT},
 			{Key: "redundant-attribute", Action: processorhelper.DELETE},
+			{Key: "deployment.environment", FromEnv: "DEPLOYMENT_ENVIRONMENT", Action: processorhelper.INSERT},
 		},
 	})
 
@@ -16,6 +16,7 @@ package resourceprocessor
 
 import (
 	"context"
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -142,6 +143,27 @@ func TestResourceProcessorAttributesUpsert(t *testing.T) {
 	}
 }
 
+func TestResourceProcessorAttributesFromEnv(t *testing.T) {
+	require.NoError(t, os.Setenv("OTEL_TEST_DEPLOYMENT_ENVIRONMENT", "production"))
+	defer os.Unsetenv("OTEL_TEST_DEPLOYMENT_ENVIRONMENT")
+
+	ttn := &testTraceConsumer{}
+	rtp, err := NewFactory().CreateTracesProcessor(context.Background(), component.ProcessorCreateParams{}, &Config{
+		ProcessorSettings: processorSettings,
+		AttributesActions: []processorhelper.ActionKeyValue{
+			{Key: "deployment.environment", FromEnv: "OTEL_TEST_DEPLOYMENT_ENVIRONMENT", Action: processorhelper.INSERT},
+		},
+	}, ttn)
+	require.NoError(t, err)
+
+	err = rtp.ConsumeTraces(context.Background(), generateTraceData(map[string]string{"cloud.zone": "zone-1"}))
+	require.NoError(t, err)
+	assert.EqualValues(t, generateTraceData(map[string]string{
+		"cloud.zone":             "zone-1",
+		"deployment.environment": "production",
+	}), ttn.td)
+}
+
 func TestResourceProcessorError(t *testing.T) {
 	ttn := &testTraceConsumer{}
 
@@ -0,0 +1,61 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routingprocessor
+
+import (
+	"go.opentelemetry.io/collector/config/configmodels"
+)
+
+// AttributeSource is where the attribute the routes are selected on is read.
+type AttributeSource string
+
+const (
+	// ContextAttributeSource reads the attribute from the metadata of the
+	// incoming gRPC request, e.g. an HTTP/2 header.
+	ContextAttributeSource AttributeSource = "context"
+	// ResourceAttributeSource reads the attribute from the resources, the
+	// data of each resource is routed separately.
+	ResourceAttributeSource AttributeSource = "resource"
+)
+
+// Config has the configuration of the routing processor.
+type Config struct {
+	configmodels.ProcessorSettings `mapstructure:",squash"`
+	// AttributeSource is where FromAttribute is read, context (the default)
+	// or resource.
+	AttributeSource AttributeSource `mapstructure:"attribute_source"`
+	// FromAttribute is the name of the attribute whose value selects the
+	// route.
+	FromAttribute string `mapstructure:"from_attribute"`
+	// DefaultExporters are the exporters the data is sent to when the value
+	// of the attribute matches no route.
+	DefaultExporters []string `mapstructure:"default_exporters"`
+	// Table are the routes.
+	Table []RoutingTableItem `mapstructure:"table"`
+}
+
+// RoutingTableItem is a route, the data whose attribute has the value Value
+// is sent to Exporters.
+type RoutingTableItem struct {
+	Value     string   `mapstructure:"value"`
+	Exporters []string `mapstructure:"exporters"`
+}
+
+var _ configmodels.ConfigValidator = (*Config)(nil)
+
+// Validate checks the processor configuration is valid.
+func (cfg *Config) Validate() error {
+	return validateConfig(cfg)
+}
@@ -0,0 +1,113 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routingprocessor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/processor/processorhelper"
+)
+
+const (
+	// The value of "type" key in configuration.
+	typeStr = "routing"
+)
+
+var (
+	errMissingFromAttribute = errors.New("from_attribute must be configured")
+	errEmptyTable           = errors.New("table must have at least one route")
+)
+
+// NewFactory returns a new factory for the Routing processor.
+func NewFactory() component.ProcessorFactory {
+	return processorhelper.NewFactory(
+		typeStr,
+		createDefaultConfig,
+		processorhelper.WithTraces(createTraceProcessor),
+		processorhelper.WithMetrics(createMetricsProcessor),
+		processorhelper.WithLogs(createLogProcessor))
+}
+
+func createDefaultConfig() configmodels.Processor {
+	return &Config{
+		ProcessorSettings: configmodels.ProcessorSettings{
+			TypeVal: typeStr,
+			NameVal: typeStr,
+		},
+		AttributeSource: ContextAttributeSource,
+	}
+}
+
+func createTraceProcessor(
+	_ context.Context,
+	_ component.ProcessorCreateParams,
+	cfg configmodels.Processor,
+	_ consumer.TracesConsumer,
+) (component.TracesProcessor, error) {
+	r, err := newRouter(cfg.(*Config), configmodels.TracesDataType)
+	if err != nil {
+		return nil, err
+	}
+	return &tracesProcessor{router: r}, nil
+}
+
+func createMetricsProcessor(
+	_ context.Context,
+	_ component.ProcessorCreateParams,
+	cfg configmodels.Processor,
+	_ consumer.MetricsConsumer,
+) (component.MetricsProcessor, error) {
+	r, err := newRouter(cfg.(*Config), configmodels.MetricsDataType)
+	if err != nil {
+		return nil, err
+	}
+	return &metricsProcessor{router: r}, nil
+}
+
+func createLogProcessor(
+	_ context.Context,
+	_ component.ProcessorCreateParams,
+	cfg configmodels.Processor,
+	_ consumer.LogsConsumer,
+) (component.LogsProcessor, error) {
+	r, err := newRouter(cfg.(*Config), configmodels.LogsDataType)
+	if err != nil {
+		return nil, err
+	}
+	return &logsProcessor{router: r}, nil
+}
+
+func validateConfig(cfg *Config) error {
+	if cfg.AttributeSource != ContextAttributeSource && cfg.AttributeSource != ResourceAttributeSource {
+		return fmt.Errorf("unsupported attribute_source %q, valid sources are {%s, %s}", cfg.AttributeSource, ContextAttributeSource, ResourceAttributeSource)
+	}
+	if cfg.FromAttribute == "" {
+		return errMissingFromAttribute
+	}
+	if len(cfg.Table) == 0 {
+		return errEmptyTable
+	}
+	for i, item := range cfg.Table {
+		if len(item.Exporters) == 0 {
+			return fmt.Errorf("route %q at index %d must have at least one exporter", item.Value, i)
+		}
+	}
+	return nil
+}
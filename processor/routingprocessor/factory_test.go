@@ -0,0 +1,101 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routingprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configcheck"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+)
+
+func TestCreateDefaultConfig(t *testing.T) {
+	factory := NewFactory()
+
+	cfg := factory.CreateDefaultConfig()
+	assert.NotNil(t, cfg, "failed to create default config")
+	assert.NoError(t, configcheck.ValidateConfig(cfg))
+}
+
+func TestCreateProcessor(t *testing.T) {
+	factory := NewFactory()
+
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.FromAttribute = "X-Tenant"
+	cfg.Table = []RoutingTableItem{{Value: "acme", Exporters: []string{"otlp/acme"}}}
+	creationParams := component.ProcessorCreateParams{Logger: zap.NewNop()}
+
+	tp, err := factory.CreateTracesProcessor(context.Background(), creationParams, cfg, consumertest.NewTracesNop())
+	assert.NotNil(t, tp)
+	assert.NoError(t, err, "cannot create trace processor")
+
+	mp, err := factory.CreateMetricsProcessor(context.Background(), creationParams, cfg, consumertest.NewMetricsNop())
+	assert.NotNil(t, mp)
+	assert.NoError(t, err, "cannot create metrics processor")
+
+	lp, err := factory.CreateLogsProcessor(context.Background(), creationParams, cfg, consumertest.NewLogsNop())
+	assert.NotNil(t, lp)
+	assert.NoError(t, err, "cannot create logs processor")
+}
+
+func TestCreateProcessorInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(cfg *Config)
+		wantErr string
+	}{
+		{
+			name:    "attribute_source",
+			modify:  func(cfg *Config) { cfg.AttributeSource = "span" },
+			wantErr: `unsupported attribute_source "span", valid sources are {context, resource}`,
+		},
+		{
+			name:    "from_attribute",
+			modify:  func(cfg *Config) { cfg.FromAttribute = "" },
+			wantErr: errMissingFromAttribute.Error(),
+		},
+		{
+			name:    "table",
+			modify:  func(cfg *Config) { cfg.Table = nil },
+			wantErr: errEmptyTable.Error(),
+		},
+		{
+			name: "exporters",
+			modify: func(cfg *Config) {
+				cfg.Table = append(cfg.Table, RoutingTableItem{Value: "globex"})
+			},
+			wantErr: `route "globex" at index 1 must have at least one exporter`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			factory := NewFactory()
+			cfg := factory.CreateDefaultConfig().(*Config)
+			cfg.FromAttribute = "tenant"
+			cfg.Table = []RoutingTableItem{{Value: "acme", Exporters: []string{"otlp/acme"}}}
+			tt.modify(cfg)
+
+			creationParams := component.ProcessorCreateParams{Logger: zap.NewNop()}
+			tp, err := factory.CreateTracesProcessor(context.Background(), creationParams, cfg, consumertest.NewTracesNop())
+			assert.Nil(t, tp)
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
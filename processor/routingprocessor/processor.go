@@ -0,0 +1,161 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routingprocessor
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+type tracesProcessor struct {
+	router *router
+}
+
+var _ component.TracesProcessor = (*tracesProcessor)(nil)
+
+func (p *tracesProcessor) GetCapabilities() component.ProcessorCapabilities {
+	return component.ProcessorCapabilities{MutatesConsumedData: false}
+}
+
+func (p *tracesProcessor) Start(_ context.Context, host component.Host) error {
+	return p.router.start(host)
+}
+
+func (p *tracesProcessor) Shutdown(context.Context) error {
+	return nil
+}
+
+// ConsumeTraces sends the traces to the exporters of their routes, the next
+// consumer is never called.
+func (p *tracesProcessor) ConsumeTraces(ctx context.Context, td pdata.Traces) error {
+	rss := td.ResourceSpans()
+	order, groups := p.router.groupByRoute(ctx, rss.Len(), func(i int) pdata.Resource {
+		return rss.At(i).Resource()
+	})
+	var errs []error
+	for _, rt := range order {
+		batch := td
+		if groups != nil {
+			batch = pdata.NewTraces()
+			indexes := groups[rt]
+			batchRss := batch.ResourceSpans()
+			batchRss.Resize(len(indexes))
+			for j, i := range indexes {
+				rss.At(i).CopyTo(batchRss.At(j))
+			}
+		}
+		for _, exporter := range rt.exporters {
+			if err := exporter.(component.TracesExporter).ConsumeTraces(ctx, batch); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return consumererror.CombineErrors(errs)
+}
+
+type metricsProcessor struct {
+	router *router
+}
+
+var _ component.MetricsProcessor = (*metricsProcessor)(nil)
+
+func (p *metricsProcessor) GetCapabilities() component.ProcessorCapabilities {
+	return component.ProcessorCapabilities{MutatesConsumedData: false}
+}
+
+func (p *metricsProcessor) Start(_ context.Context, host component.Host) error {
+	return p.router.start(host)
+}
+
+func (p *metricsProcessor) Shutdown(context.Context) error {
+	return nil
+}
+
+// ConsumeMetrics sends the metrics to the exporters of their routes, the
+// next consumer is never called.
+func (p *metricsProcessor) ConsumeMetrics(ctx context.Context, md pdata.Metrics) error {
+	rms := md.ResourceMetrics()
+	order, groups := p.router.groupByRoute(ctx, rms.Len(), func(i int) pdata.Resource {
+		return rms.At(i).Resource()
+	})
+	var errs []error
+	for _, rt := range order {
+		batch := md
+		if groups != nil {
+			batch = pdata.NewMetrics()
+			indexes := groups[rt]
+			batchRms := batch.ResourceMetrics()
+			batchRms.Resize(len(indexes))
+			for j, i := range indexes {
+				rms.At(i).CopyTo(batchRms.At(j))
+			}
+		}
+		for _, exporter := range rt.exporters {
+			if err := exporter.(component.MetricsExporter).ConsumeMetrics(ctx, batch); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return consumererror.CombineErrors(errs)
+}
+
+type logsProcessor struct {
+	router *router
+}
+
+var _ component.LogsProcessor = (*logsProcessor)(nil)
+
+func (p *logsProcessor) GetCapabilities() component.ProcessorCapabilities {
+	return component.ProcessorCapabilities{MutatesConsumedData: false}
+}
+
+func (p *logsProcessor) Start(_ context.Context, host component.Host) error {
+	return p.router.start(host)
+}
+
+func (p *logsProcessor) Shutdown(context.Context) error {
+	return nil
+}
+
+// ConsumeLogs sends the logs to the exporters of their routes, the next
+// consumer is never called.
+func (p *logsProcessor) ConsumeLogs(ctx context.Context, ld pdata.Logs) error {
+	rls := ld.ResourceLogs()
+	order, groups := p.router.groupByRoute(ctx, rls.Len(), func(i int) pdata.Resource {
+		return rls.At(i).Resource()
+	})
+	var errs []error
+	for _, rt := range order {
+		batch := ld
+		if groups != nil {
+			batch = pdata.NewLogs()
+			indexes := groups[rt]
+			batchRls := batch.ResourceLogs()
+			batchRls.Resize(len(indexes))
+			for j, i := range indexes {
+				rls.At(i).CopyTo(batchRls.At(j))
+			}
+		}
+		for _, exporter := range rt.exporters {
+			if err := exporter.(component.LogsExporter).ConsumeLogs(ctx, batch); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return consumererror.CombineErrors(errs)
+}
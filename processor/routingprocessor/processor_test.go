@@ -0,0 +1,280 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routingprocessor
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/metadata"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+type tracesExporter struct {
+	component.Component
+	consumertest.TracesSink
+	err error
+}
+
+func (e *tracesExporter) ConsumeTraces(ctx context.Context, td pdata.Traces) error {
+	if e.err != nil {
+		return e.err
+	}
+	return e.TracesSink.ConsumeTraces(ctx, td)
+}
+
+type metricsExporter struct {
+	component.Component
+	consumertest.MetricsSink
+}
+
+type logsExporter struct {
+	component.Component
+	consumertest.LogsSink
+}
+
+type exportersHost struct {
+	component.Host
+	exporters map[configmodels.DataType]map[configmodels.NamedEntity]component.Exporter
+}
+
+func (h *exportersHost) GetExporters() map[configmodels.DataType]map[configmodels.NamedEntity]component.Exporter {
+	return h.exporters
+}
+
+func newHost(dataType configmodels.DataType, exporters map[string]component.Exporter) component.Host {
+	dataTypeExporters := map[configmodels.NamedEntity]component.Exporter{}
+	for name, exporter := range exporters {
+		dataTypeExporters[&configmodels.ExporterSettings{TypeVal: "test", NameVal: name}] = exporter
+	}
+	return &exportersHost{
+		Host:      componenttest.NewNopHost(),
+		exporters: map[configmodels.DataType]map[configmodels.NamedEntity]component.Exporter{dataType: dataTypeExporters},
+	}
+}
+
+func newConfig(source AttributeSource) *Config {
+	cfg := createDefaultConfig().(*Config)
+	cfg.AttributeSource = source
+	cfg.FromAttribute = "X-Tenant"
+	cfg.DefaultExporters = []string{"default"}
+	cfg.Table = []RoutingTableItem{
+		{Value: "acme", Exporters: []string{"acme"}},
+		{Value: "globex", Exporters: []string{"globex", "default"}},
+	}
+	return cfg
+}
+
+func newTraces(tenants ...string) pdata.Traces {
+	td := pdata.NewTraces()
+	rss := td.ResourceSpans()
+	rss.Resize(len(tenants))
+	for i, tenant := range tenants {
+		rs := rss.At(i)
+		if tenant != "" {
+			rs.Resource().Attributes().InsertString("X-Tenant", tenant)
+		}
+		rs.InstrumentationLibrarySpans().Resize(1)
+		rs.InstrumentationLibrarySpans().At(0).Spans().Resize(1)
+	}
+	return td
+}
+
+func tenantsOf(tds []pdata.Traces) [][]string {
+	var tenants [][]string
+	for _, td := range tds {
+		var batch []string
+		for i := 0; i < td.ResourceSpans().Len(); i++ {
+			attr, _ := td.ResourceSpans().At(i).Resource().Attributes().Get("X-Tenant")
+			batch = append(batch, attr.StringVal())
+		}
+		tenants = append(tenants, batch)
+	}
+	return tenants
+}
+
+func newTracesFixture(t *testing.T, cfg *Config) (component.TracesProcessor, map[string]*tracesExporter) {
+	exporters := map[string]*tracesExporter{
+		"default": {},
+		"acme":    {},
+		"globex":  {},
+	}
+	hostExporters := map[string]component.Exporter{}
+	for name, exporter := range exporters {
+		hostExporters[name] = exporter
+	}
+	tp, err := NewFactory().CreateTracesProcessor(context.Background(), component.ProcessorCreateParams{Logger: zap.NewNop()}, cfg, consumertest.NewTracesNop())
+	require.NoError(t, err)
+	require.NoError(t, tp.Start(context.Background(), newHost(configmodels.TracesDataType, hostExporters)))
+	return tp, exporters
+}
+
+func TestRouteTracesFromContext(t *testing.T) {
+	tests := []struct {
+		name     string
+		md       metadata.MD
+		defaults int
+		acme     int
+		globex   int
+	}{
+		{name: "acme", md: metadata.Pairs("x-tenant", "acme"), acme: 1},
+		{name: "first value", md: metadata.Pairs("x-tenant", "globex", "x-tenant", "acme"), globex: 1, defaults: 1},
+		{name: "unknown", md: metadata.Pairs("x-tenant", "initech"), defaults: 1},
+		{name: "missing", md: metadata.Pairs("x-other", "acme"), defaults: 1},
+		{name: "no metadata", defaults: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tp, exporters := newTracesFixture(t, newConfig(ContextAttributeSource))
+			ctx := context.Background()
+			if tt.md != nil {
+				ctx = metadata.NewIncomingContext(ctx, tt.md)
+			}
+			// The whole batch follows the context, whatever its resources.
+			require.NoError(t, tp.ConsumeTraces(ctx, newTraces("globex", "")))
+
+			assert.Len(t, exporters["default"].AllTraces(), tt.defaults)
+			assert.Equal(t, 2*tt.defaults, exporters["default"].SpansCount())
+			assert.Len(t, exporters["acme"].AllTraces(), tt.acme)
+			assert.Equal(t, 2*tt.acme, exporters["acme"].SpansCount())
+			assert.Len(t, exporters["globex"].AllTraces(), tt.globex)
+			assert.Equal(t, 2*tt.globex, exporters["globex"].SpansCount())
+		})
+	}
+}
+
+func TestRouteTracesFromResource(t *testing.T) {
+	tp, exporters := newTracesFixture(t, newConfig(ResourceAttributeSource))
+
+	td := newTraces("acme", "globex", "", "acme", "initech")
+	require.NoError(t, tp.ConsumeTraces(metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-tenant", "acme")), td))
+
+	assert.Equal(t, [][]string{{"acme", "acme"}}, tenantsOf(exporters["acme"].AllTraces()))
+	assert.Equal(t, [][]string{{"globex"}}, tenantsOf(exporters["globex"].AllTraces()))
+	assert.Equal(t, [][]string{{"globex"}, {"", "initech"}}, tenantsOf(exporters["default"].AllTraces()))
+	// The received data is not modified.
+	assert.Equal(t, 5, td.ResourceSpans().Len())
+}
+
+func TestRouteTracesFromResourceSingleRoute(t *testing.T) {
+	tp, exporters := newTracesFixture(t, newConfig(ResourceAttributeSource))
+
+	td := newTraces("acme", "acme")
+	require.NoError(t, tp.ConsumeTraces(context.Background(), td))
+
+	require.Len(t, exporters["acme"].AllTraces(), 1)
+	assert.Equal(t, td, exporters["acme"].AllTraces()[0])
+}
+
+func TestRouteTracesWithoutDefaultExporters(t *testing.T) {
+	cfg := newConfig(ResourceAttributeSource)
+	cfg.DefaultExporters = nil
+	tp, exporters := newTracesFixture(t, cfg)
+
+	require.NoError(t, tp.ConsumeTraces(context.Background(), newTraces("initech", "globex")))
+
+	assert.Equal(t, [][]string{{"globex"}}, tenantsOf(exporters["globex"].AllTraces()))
+	assert.Equal(t, [][]string{{"globex"}}, tenantsOf(exporters["default"].AllTraces()))
+	assert.Empty(t, exporters["acme"].AllTraces())
+}
+
+func TestRouteTracesExportError(t *testing.T) {
+	tp, exporters := newTracesFixture(t, newConfig(ResourceAttributeSource))
+	exporters["acme"].err = errors.New("acme failed")
+	exporters["globex"].err = errors.New("globex failed")
+
+	err := tp.ConsumeTraces(context.Background(), newTraces("acme", "globex"))
+	assert.EqualError(t, err, "[acme failed; globex failed]")
+	// The other exporters of a route still get the data.
+	assert.Equal(t, [][]string{{"globex"}}, tenantsOf(exporters["default"].AllTraces()))
+}
+
+func TestRouteMetrics(t *testing.T) {
+	defaultExporter := &metricsExporter{}
+	acmeExporter := &metricsExporter{}
+	cfg := newConfig(ResourceAttributeSource)
+	cfg.Table = cfg.Table[:1]
+	mp, err := NewFactory().CreateMetricsProcessor(context.Background(), component.ProcessorCreateParams{Logger: zap.NewNop()}, cfg, consumertest.NewMetricsNop())
+	require.NoError(t, err)
+	require.NoError(t, mp.Start(context.Background(), newHost(configmodels.MetricsDataType, map[string]component.Exporter{
+		"default": defaultExporter,
+		"acme":    acmeExporter,
+	})))
+
+	md := pdata.NewMetrics()
+	md.ResourceMetrics().Resize(2)
+	md.ResourceMetrics().At(0).Resource().Attributes().InsertString("X-Tenant", "acme")
+	md.ResourceMetrics().At(1).Resource().Attributes().InsertString("X-Tenant", "globex")
+	require.NoError(t, mp.ConsumeMetrics(context.Background(), md))
+
+	require.Len(t, acmeExporter.AllMetrics(), 1)
+	assert.Equal(t, 1, acmeExporter.AllMetrics()[0].ResourceMetrics().Len())
+	tenant, _ := acmeExporter.AllMetrics()[0].ResourceMetrics().At(0).Resource().Attributes().Get("X-Tenant")
+	assert.Equal(t, "acme", tenant.StringVal())
+	require.Len(t, defaultExporter.AllMetrics(), 1)
+	assert.Equal(t, 1, defaultExporter.AllMetrics()[0].ResourceMetrics().Len())
+	tenant, _ = defaultExporter.AllMetrics()[0].ResourceMetrics().At(0).Resource().Attributes().Get("X-Tenant")
+	assert.Equal(t, "globex", tenant.StringVal())
+}
+
+func TestRouteLogs(t *testing.T) {
+	defaultExporter := &logsExporter{}
+	acmeExporter := &logsExporter{}
+	cfg := newConfig(ContextAttributeSource)
+	cfg.Table = cfg.Table[:1]
+	lp, err := NewFactory().CreateLogsProcessor(context.Background(), component.ProcessorCreateParams{Logger: zap.NewNop()}, cfg, consumertest.NewLogsNop())
+	require.NoError(t, err)
+	require.NoError(t, lp.Start(context.Background(), newHost(configmodels.LogsDataType, map[string]component.Exporter{
+		"default": defaultExporter,
+		"acme":    acmeExporter,
+	})))
+
+	ld := pdata.NewLogs()
+	ld.ResourceLogs().Resize(1)
+	ld.ResourceLogs().At(0).InstrumentationLibraryLogs().Resize(1)
+	ld.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().Resize(3)
+	require.NoError(t, lp.ConsumeLogs(metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-tenant", "acme")), ld))
+	require.NoError(t, lp.ConsumeLogs(context.Background(), ld))
+
+	assert.Equal(t, 3, acmeExporter.LogRecordsCount())
+	assert.Equal(t, 3, defaultExporter.LogRecordsCount())
+}
+
+func TestStartErrors(t *testing.T) {
+	tp, err := NewFactory().CreateTracesProcessor(context.Background(), component.ProcessorCreateParams{Logger: zap.NewNop()}, newConfig(ContextAttributeSource), consumertest.NewTracesNop())
+	require.NoError(t, err)
+
+	err = tp.Start(context.Background(), newHost(configmodels.TracesDataType, map[string]component.Exporter{
+		"default": &tracesExporter{},
+		"acme":    &tracesExporter{},
+	}))
+	assert.EqualError(t, err, `failed to find traces exporter "globex", the exporter must be in a traces pipeline, available exporters are [acme default]`)
+
+	err = tp.Start(context.Background(), newHost(configmodels.TracesDataType, map[string]component.Exporter{
+		"default": &tracesExporter{},
+		"acme":    &metricsExporter{},
+		"globex":  &tracesExporter{},
+	}))
+	assert.EqualError(t, err, `exporter "acme" is not a traces exporter`)
+}
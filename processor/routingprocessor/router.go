@@ -0,0 +1,180 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routingprocessor
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// route is the exporters of a routing table entry or the default exporters.
+type route struct {
+	exporters []component.Exporter
+}
+
+// router selects the route of the data.
+type router struct {
+	config   *Config
+	dataType configmodels.DataType
+	// attribute is the name of the attribute, lower-cased for the gRPC
+	// metadata whose keys are always lower case.
+	attribute string
+
+	defaultRoute *route
+	routes       map[string]*route
+}
+
+func newRouter(cfg *Config, dataType configmodels.DataType) (*router, error) {
+	if err := validateConfig(cfg); err != nil {
+		return nil, err
+	}
+	attribute := cfg.FromAttribute
+	if cfg.AttributeSource == ContextAttributeSource {
+		attribute = strings.ToLower(attribute)
+	}
+	return &router{
+		config:    cfg,
+		dataType:  dataType,
+		attribute: attribute,
+		routes:    make(map[string]*route, len(cfg.Table)),
+	}, nil
+}
+
+// start looks up the exporters of the routes, they must be in a pipeline of
+// the data type of the router.
+func (r *router) start(host component.Host) error {
+	available := host.GetExporters()[r.dataType]
+	var err error
+	if r.defaultRoute, err = r.newRoute(available, r.config.DefaultExporters); err != nil {
+		return err
+	}
+	for _, item := range r.config.Table {
+		if r.routes[item.Value], err = r.newRoute(available, item.Exporters); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *router) newRoute(available map[configmodels.NamedEntity]component.Exporter, names []string) (*route, error) {
+	rt := &route{exporters: make([]component.Exporter, 0, len(names))}
+	for _, name := range names {
+		exporter := findExporter(available, name)
+		if exporter == nil {
+			return nil, fmt.Errorf("failed to find %s exporter %q, the exporter must be in a %s pipeline, available exporters are %v",
+				r.dataType, name, r.dataType, exporterNames(available))
+		}
+		if !isExporterOf(exporter, r.dataType) {
+			return nil, fmt.Errorf("exporter %q is not a %s exporter", name, r.dataType)
+		}
+		rt.exporters = append(rt.exporters, exporter)
+	}
+	return rt, nil
+}
+
+func findExporter(exporters map[configmodels.NamedEntity]component.Exporter, name string) component.Exporter {
+	for entity, exporter := range exporters {
+		if entity.Name() == name {
+			return exporter
+		}
+	}
+	return nil
+}
+
+func isExporterOf(exporter component.Exporter, dataType configmodels.DataType) bool {
+	switch dataType {
+	case configmodels.TracesDataType:
+		_, ok := exporter.(component.TracesExporter)
+		return ok
+	case configmodels.MetricsDataType:
+		_, ok := exporter.(component.MetricsExporter)
+		return ok
+	case configmodels.LogsDataType:
+		_, ok := exporter.(component.LogsExporter)
+		return ok
+	}
+	return false
+}
+
+func exporterNames(exporters map[configmodels.NamedEntity]component.Exporter) []string {
+	names := make([]string, 0, len(exporters))
+	for entity := range exporters {
+		names = append(names, entity.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+// routeOf returns the route of the value of the attribute, the default
+// route if the value matches no route.
+func (r *router) routeOf(value string, ok bool) *route {
+	if !ok {
+		return r.defaultRoute
+	}
+	if rt, ok := r.routes[value]; ok {
+		return rt
+	}
+	return r.defaultRoute
+}
+
+func (r *router) contextValue(ctx context.Context) (string, bool) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", false
+	}
+	values := md.Get(r.attribute)
+	if len(values) == 0 {
+		return "", false
+	}
+	return values[0], true
+}
+
+func (r *router) resourceValue(resource pdata.Resource) (string, bool) {
+	attr, ok := resource.Attributes().Get(r.attribute)
+	if !ok || attr.Type() != pdata.AttributeValueSTRING {
+		return "", false
+	}
+	return attr.StringVal(), true
+}
+
+// groupByRoute returns the indexes of the n resources grouped by route, in
+// the order the routes are first seen. The groups are nil when all the data
+// has a single route, e.g. always with the context attribute source.
+func (r *router) groupByRoute(ctx context.Context, n int, resource func(i int) pdata.Resource) ([]*route, map[*route][]int) {
+	if r.config.AttributeSource == ContextAttributeSource {
+		return []*route{r.routeOf(r.contextValue(ctx))}, nil
+	}
+	var order []*route
+	groups := make(map[*route][]int)
+	for i := 0; i < n; i++ {
+		rt := r.routeOf(r.resourceValue(resource(i)))
+		if _, ok := groups[rt]; !ok {
+			order = append(order, rt)
+		}
+		groups[rt] = append(groups[rt], i)
+	}
+	if len(order) <= 1 {
+		return order, nil
+	}
+	return order, groups
+}
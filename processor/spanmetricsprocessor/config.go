@@ -0,0 +1,45 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanmetricsprocessor
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/config/configmodels"
+)
+
+// Dimension is a label added to the metrics, whose value is the attribute of
+// the same name of the span or, if absent, of its resource.
+type Dimension struct {
+	Name string `mapstructure:"name"`
+	// Default is the value of the label when the attribute is absent. The
+	// label is omitted if both the attribute and Default are absent.
+	Default *string `mapstructure:"default"`
+}
+
+// Config has the configuration of the span metrics processor.
+type Config struct {
+	configmodels.ProcessorSettings `mapstructure:",squash"`
+	// MetricsExporter is the name of the metrics exporter the metrics are
+	// sent to.
+	MetricsExporter string `mapstructure:"metrics_exporter"`
+	// LatencyHistogramBuckets are the upper bounds of the buckets of the
+	// latency histogram, in increasing order. Default buckets ranging from
+	// 2ms to 15s are used if it is empty.
+	LatencyHistogramBuckets []time.Duration `mapstructure:"latency_histogram_buckets"`
+	// Dimensions are the labels added to the metrics in addition to the
+	// service name, operation, span kind and status code.
+	Dimensions []Dimension `mapstructure:"dimensions"`
+}
@@ -0,0 +1,266 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanmetricsprocessor
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+)
+
+const (
+	serviceNameLabel = "service.name"
+	operationLabel   = "operation"
+	spanKindLabel    = "span.kind"
+	statusCodeLabel  = "status.code"
+
+	callsMetricName   = "calls_total"
+	latencyMetricName = "latency"
+)
+
+// spanMetricsProcessor passes the spans through unmodified and sends, after
+// each batch, the cumulative number of calls and latency histogram of the
+// operations of the services to the metrics exporter.
+type spanMetricsProcessor struct {
+	logger          *zap.Logger
+	nextConsumer    consumer.TracesConsumer
+	exporterName    string
+	metricsExporter component.MetricsExporter
+	// bounds are the bounds of the latency histogram, in milliseconds.
+	bounds     []float64
+	dimensions []Dimension
+	startTime  pdata.Timestamp
+
+	mu     sync.Mutex
+	series map[string]*seriesMetrics
+}
+
+// seriesMetrics are the metrics of the spans with the same labels.
+type seriesMetrics struct {
+	labels       map[string]string
+	calls        int64
+	count        uint64
+	sum          float64
+	bucketCounts []uint64
+}
+
+var _ component.TracesProcessor = (*spanMetricsProcessor)(nil)
+
+func newSpanMetricsProcessor(logger *zap.Logger, nextConsumer consumer.TracesConsumer, cfg *Config) *spanMetricsProcessor {
+	buckets := cfg.LatencyHistogramBuckets
+	if len(buckets) == 0 {
+		buckets = defaultLatencyHistogramBuckets
+	}
+	bounds := make([]float64, len(buckets))
+	for i, bucket := range buckets {
+		bounds[i] = durationToMillis(bucket)
+	}
+	return &spanMetricsProcessor{
+		logger:       logger,
+		nextConsumer: nextConsumer,
+		exporterName: cfg.MetricsExporter,
+		bounds:       bounds,
+		dimensions:   cfg.Dimensions,
+		series:       make(map[string]*seriesMetrics),
+	}
+}
+
+func (p *spanMetricsProcessor) GetCapabilities() component.ProcessorCapabilities {
+	return component.ProcessorCapabilities{MutatesConsumedData: false}
+}
+
+// Start looks up the metrics exporter the metrics are sent to.
+func (p *spanMetricsProcessor) Start(_ context.Context, host component.Host) error {
+	var available []string
+	for entity, exporter := range host.GetExporters()[configmodels.MetricsDataType] {
+		if entity.Name() != p.exporterName {
+			available = append(available, entity.Name())
+			continue
+		}
+		metricsExporter, ok := exporter.(component.MetricsExporter)
+		if !ok {
+			return fmt.Errorf("exporter %q is not a metrics exporter", p.exporterName)
+		}
+		p.metricsExporter = metricsExporter
+		p.startTime = pdata.TimestampFromTime(time.Now())
+		return nil
+	}
+	sort.Strings(available)
+	return fmt.Errorf("failed to find metrics exporter %q, the exporter must be in a metrics pipeline, available exporters are %v", p.exporterName, available)
+}
+
+// Shutdown is invoked during service shutdown.
+func (p *spanMetricsProcessor) Shutdown(context.Context) error {
+	return nil
+}
+
+// ConsumeTraces records the metrics of the spans and sends them to the
+// metrics exporter before passing the spans to the next consumer. A failure
+// to export the metrics does not prevent the spans from being passed on.
+func (p *spanMetricsProcessor) ConsumeTraces(ctx context.Context, td pdata.Traces) error {
+	md := p.aggregate(td, pdata.TimestampFromTime(time.Now()))
+	if err := p.metricsExporter.ConsumeMetrics(ctx, md); err != nil {
+		p.logger.Warn("Failed to export the span metrics", zap.Error(err))
+	}
+	return p.nextConsumer.ConsumeTraces(ctx, td)
+}
+
+// aggregate records the metrics of the spans and returns the metrics of all
+// the series seen so far.
+func (p *spanMetricsProcessor) aggregate(td pdata.Traces, now pdata.Timestamp) pdata.Metrics {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		resourceAttrs := rs.Resource().Attributes()
+		serviceName := ""
+		if attr, ok := resourceAttrs.Get(conventions.AttributeServiceName); ok {
+			serviceName = attr.StringVal()
+		}
+		ilss := rs.InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			spans := ilss.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				p.record(serviceName, resourceAttrs, spans.At(k))
+			}
+		}
+	}
+	return p.buildMetrics(now)
+}
+
+func (p *spanMetricsProcessor) record(serviceName string, resourceAttrs pdata.AttributeMap, span pdata.Span) {
+	labels := map[string]string{
+		serviceNameLabel: serviceName,
+		operationLabel:   span.Name(),
+		spanKindLabel:    span.Kind().String(),
+		statusCodeLabel:  span.Status().Code().String(),
+	}
+	for _, d := range p.dimensions {
+		if value, ok := dimensionValue(d, span.Attributes(), resourceAttrs); ok {
+			labels[d.Name] = value
+		}
+	}
+
+	key := labelsKey(labels)
+	series, ok := p.series[key]
+	if !ok {
+		series = &seriesMetrics{labels: labels, bucketCounts: make([]uint64, len(p.bounds)+1)}
+		p.series[key] = series
+	}
+
+	latency := 0.0
+	if span.EndTime() > span.StartTime() {
+		latency = durationToMillis(time.Duration(span.EndTime() - span.StartTime()))
+	}
+	series.calls++
+	series.count++
+	series.sum += latency
+	// The bucket i holds the latencies in (bounds[i-1], bounds[i]].
+	series.bucketCounts[sort.SearchFloat64s(p.bounds, latency)]++
+}
+
+func (p *spanMetricsProcessor) buildMetrics(now pdata.Timestamp) pdata.Metrics {
+	keys := make([]string, 0, len(p.series))
+	for key := range p.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	md := pdata.NewMetrics()
+	md.ResourceMetrics().Resize(1)
+	ilms := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics()
+	ilms.Resize(1)
+	metrics := ilms.At(0).Metrics()
+	metrics.Resize(2)
+
+	calls := metrics.At(0)
+	calls.SetName(callsMetricName)
+	calls.SetDescription("Number of spans")
+	calls.SetUnit("1")
+	calls.SetDataType(pdata.MetricDataTypeIntSum)
+	calls.IntSum().SetIsMonotonic(true)
+	calls.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+	callsDps := calls.IntSum().DataPoints()
+	callsDps.Resize(len(keys))
+
+	latency := metrics.At(1)
+	latency.SetName(latencyMetricName)
+	latency.SetDescription("Duration of the spans")
+	latency.SetUnit("ms")
+	latency.SetDataType(pdata.MetricDataTypeDoubleHistogram)
+	latency.DoubleHistogram().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+	latencyDps := latency.DoubleHistogram().DataPoints()
+	latencyDps.Resize(len(keys))
+
+	for i, key := range keys {
+		series := p.series[key]
+
+		callsDp := callsDps.At(i)
+		callsDp.LabelsMap().InitFromMap(series.labels)
+		callsDp.SetStartTime(p.startTime)
+		callsDp.SetTimestamp(now)
+		callsDp.SetValue(series.calls)
+
+		latencyDp := latencyDps.At(i)
+		latencyDp.LabelsMap().InitFromMap(series.labels)
+		latencyDp.SetStartTime(p.startTime)
+		latencyDp.SetTimestamp(now)
+		latencyDp.SetCount(series.count)
+		latencyDp.SetSum(series.sum)
+		latencyDp.SetExplicitBounds(p.bounds)
+		latencyDp.SetBucketCounts(append([]uint64(nil), series.bucketCounts...))
+	}
+	return md
+}
+
+func dimensionValue(d Dimension, spanAttrs pdata.AttributeMap, resourceAttrs pdata.AttributeMap) (string, bool) {
+	if attr, ok := spanAttrs.Get(d.Name); ok {
+		return tracetranslator.AttributeValueToString(attr, false), true
+	}
+	if attr, ok := resourceAttrs.Get(d.Name); ok {
+		return tracetranslator.AttributeValueToString(attr, false), true
+	}
+	if d.Default != nil {
+		return *d.Default, true
+	}
+	return "", false
+}
+
+func labelsKey(labels map[string]string) string {
+	kvs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		kvs = append(kvs, k+"="+v)
+	}
+	sort.Strings(kvs)
+	return strings.Join(kvs, "\x00")
+}
+
+func durationToMillis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,221 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanmetricsprocessor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+type metricsExporter struct {
+	component.Component
+	consumertest.MetricsSink
+	err error
+}
+
+func (e *metricsExporter) ConsumeMetrics(ctx context.Context, md pdata.Metrics) error {
+	if e.err != nil {
+		return e.err
+	}
+	return e.MetricsSink.ConsumeMetrics(ctx, md)
+}
+
+type tracesExporter struct {
+	component.Component
+	consumertest.TracesSink
+}
+
+type exportersHost struct {
+	component.Host
+	exporters map[configmodels.DataType]map[configmodels.NamedEntity]component.Exporter
+}
+
+func (h *exportersHost) GetExporters() map[configmodels.DataType]map[configmodels.NamedEntity]component.Exporter {
+	return h.exporters
+}
+
+func newHost(exporters map[string]component.Exporter) component.Host {
+	metricsExporters := map[configmodels.NamedEntity]component.Exporter{}
+	for name, exporter := range exporters {
+		metricsExporters[&configmodels.ExporterSettings{TypeVal: "test", NameVal: name}] = exporter
+	}
+	return &exportersHost{
+		Host:      componenttest.NewNopHost(),
+		exporters: map[configmodels.DataType]map[configmodels.NamedEntity]component.Exporter{configmodels.MetricsDataType: metricsExporters},
+	}
+}
+
+type testSpan struct {
+	service  string
+	name     string
+	kind     pdata.SpanKind
+	status   pdata.StatusCode
+	duration time.Duration
+	attrs    map[string]string
+}
+
+func newTraces(spans ...testSpan) pdata.Traces {
+	td := pdata.NewTraces()
+	rss := td.ResourceSpans()
+	rss.Resize(len(spans))
+	for i, s := range spans {
+		rs := rss.At(i)
+		rs.Resource().Attributes().InsertString("service.name", s.service)
+		rs.Resource().Attributes().InsertString("host.name", "host")
+		rs.InstrumentationLibrarySpans().Resize(1)
+		ss := rs.InstrumentationLibrarySpans().At(0).Spans()
+		ss.Resize(1)
+		span := ss.At(0)
+		span.SetName(s.name)
+		span.SetKind(s.kind)
+		span.Status().SetCode(s.status)
+		span.SetStartTime(1000)
+		span.SetEndTime(1000 + pdata.Timestamp(s.duration))
+		for k, v := range s.attrs {
+			span.Attributes().InsertString(k, v)
+		}
+	}
+	return td
+}
+
+func newStartedProcessor(t *testing.T, cfg *Config, exporter component.MetricsExporter) (*spanMetricsProcessor, *consumertest.TracesSink) {
+	next := new(consumertest.TracesSink)
+	cfg.MetricsExporter = "spans"
+	p := newSpanMetricsProcessor(zap.NewNop(), next, cfg)
+	require.NoError(t, p.Start(context.Background(), newHost(map[string]component.Exporter{"spans": exporter})))
+	return p, next
+}
+
+func TestStartErrors(t *testing.T) {
+	p := newSpanMetricsProcessor(zap.NewNop(), consumertest.NewTracesNop(), &Config{MetricsExporter: "spans"})
+	err := p.Start(context.Background(), newHost(map[string]component.Exporter{"b": &metricsExporter{}, "a": &metricsExporter{}}))
+	assert.EqualError(t, err, `failed to find metrics exporter "spans", the exporter must be in a metrics pipeline, available exporters are [a b]`)
+
+	err = p.Start(context.Background(), newHost(map[string]component.Exporter{"spans": &tracesExporter{}}))
+	assert.EqualError(t, err, `exporter "spans" is not a metrics exporter`)
+}
+
+func TestConsumeTraces(t *testing.T) {
+	exporter := &metricsExporter{}
+	p, next := newStartedProcessor(t, &Config{LatencyHistogramBuckets: []time.Duration{10 * time.Millisecond, 100 * time.Millisecond}}, exporter)
+
+	td := newTraces(
+		testSpan{service: "a", name: "get", kind: pdata.SpanKindSERVER, status: pdata.StatusCodeOk, duration: 5 * time.Millisecond},
+		testSpan{service: "a", name: "get", kind: pdata.SpanKindSERVER, status: pdata.StatusCodeOk, duration: 50 * time.Millisecond},
+		testSpan{service: "a", name: "get", kind: pdata.SpanKindSERVER, status: pdata.StatusCodeError, duration: time.Second},
+	)
+	require.NoError(t, p.ConsumeTraces(context.Background(), td))
+	require.NoError(t, p.ConsumeTraces(context.Background(), newTraces(
+		testSpan{service: "a", name: "get", kind: pdata.SpanKindSERVER, status: pdata.StatusCodeOk, duration: 10 * time.Millisecond},
+	)))
+
+	assert.Equal(t, 2, len(next.AllTraces()))
+	assert.Equal(t, td, next.AllTraces()[0])
+
+	require.Len(t, exporter.AllMetrics(), 2)
+	md := exporter.AllMetrics()[1]
+	metrics := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	require.Equal(t, 2, metrics.Len())
+
+	calls := metrics.At(0)
+	assert.Equal(t, "calls_total", calls.Name())
+	assert.True(t, calls.IntSum().IsMonotonic())
+	assert.Equal(t, pdata.AggregationTemporalityCumulative, calls.IntSum().AggregationTemporality())
+	callsDps := calls.IntSum().DataPoints()
+	require.Equal(t, 2, callsDps.Len())
+
+	latency := metrics.At(1)
+	assert.Equal(t, "latency", latency.Name())
+	assert.Equal(t, "ms", latency.Unit())
+	assert.Equal(t, pdata.AggregationTemporalityCumulative, latency.DoubleHistogram().AggregationTemporality())
+	latencyDps := latency.DoubleHistogram().DataPoints()
+	require.Equal(t, 2, latencyDps.Len())
+
+	// The series are sorted by their labels, STATUS_CODE_ERROR < STATUS_CODE_OK.
+	expectedLabels := []map[string]string{
+		{"service.name": "a", "operation": "get", "span.kind": "SPAN_KIND_SERVER", "status.code": "STATUS_CODE_ERROR"},
+		{"service.name": "a", "operation": "get", "span.kind": "SPAN_KIND_SERVER", "status.code": "STATUS_CODE_OK"},
+	}
+	expectedCalls := []int64{1, 3}
+	expectedSums := []float64{1000, 65}
+	expectedBuckets := [][]uint64{{0, 0, 1}, {2, 1, 0}}
+	for i := range expectedLabels {
+		assert.Equal(t, pdata.NewStringMap().InitFromMap(expectedLabels[i]).Sort(), callsDps.At(i).LabelsMap().Sort())
+		assert.Equal(t, expectedCalls[i], callsDps.At(i).Value())
+		assert.Equal(t, p.startTime, callsDps.At(i).StartTime())
+
+		dp := latencyDps.At(i)
+		assert.Equal(t, pdata.NewStringMap().InitFromMap(expectedLabels[i]).Sort(), dp.LabelsMap().Sort())
+		assert.Equal(t, uint64(expectedCalls[i]), dp.Count())
+		assert.Equal(t, expectedSums[i], dp.Sum())
+		assert.Equal(t, []float64{10, 100}, dp.ExplicitBounds())
+		assert.Equal(t, expectedBuckets[i], dp.BucketCounts())
+	}
+}
+
+func TestDimensions(t *testing.T) {
+	exporter := &metricsExporter{}
+	missing := "missing"
+	p, _ := newStartedProcessor(t, &Config{
+		Dimensions: []Dimension{
+			{Name: "http.method"},
+			{Name: "host.name"},
+			{Name: "http.status_code", Default: &missing},
+			{Name: "absent"},
+		},
+	}, exporter)
+
+	require.NoError(t, p.ConsumeTraces(context.Background(), newTraces(
+		testSpan{service: "a", name: "get", attrs: map[string]string{"http.method": "GET"}},
+	)))
+
+	require.Len(t, exporter.AllMetrics(), 1)
+	dps := exporter.AllMetrics()[0].ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().At(0).IntSum().DataPoints()
+	require.Equal(t, 1, dps.Len())
+	expected := pdata.NewStringMap().InitFromMap(map[string]string{
+		"service.name":     "a",
+		"operation":        "get",
+		"span.kind":        "SPAN_KIND_UNSPECIFIED",
+		"status.code":      "STATUS_CODE_UNSET",
+		"http.method":      "GET",
+		"host.name":        "host",
+		"http.status_code": "missing",
+	})
+	assert.Equal(t, expected.Sort(), dps.At(0).LabelsMap().Sort())
+
+	// The default buckets are used when none is configured.
+	bounds := exporter.AllMetrics()[0].ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().At(1).DoubleHistogram().DataPoints().At(0).ExplicitBounds()
+	assert.Len(t, bounds, len(defaultLatencyHistogramBuckets))
+}
+
+func TestExportFailure(t *testing.T) {
+	exporter := &metricsExporter{err: errors.New("export failed")}
+	p, next := newStartedProcessor(t, &Config{}, exporter)
+
+	require.NoError(t, p.ConsumeTraces(context.Background(), newTraces(testSpan{service: "a", name: "get"})))
+	assert.Equal(t, 1, next.SpansCount())
+}
@@ -34,6 +34,16 @@ type Config struct {
 	// Note: The field name is `Rename` to avoid collision with the Name() method
 	// from configmodels.ProcessorSettings.NamedEntity
 	Rename Name `mapstructure:"name"`
+
+	// SetStatus specifies how to set the status of a span.
+	SetStatus *Status `mapstructure:"status"`
+
+	// DropEvents is a list of regex pattern strings, the events of a span whose
+	// name matches any of them are removed from the span.
+	DropEvents []string `mapstructure:"drop_events"`
+
+	// TruncateAttributes specifies the truncation of long attribute values.
+	TruncateAttributes *TruncateAttributes `mapstructure:"truncate_attributes"`
 }
 
 // Name specifies the attributes to use to re-name a span.
@@ -76,4 +86,36 @@ type ToAttributes struct {
 	// match. If it is false rule processing will continue to be performed over the
 	// modified span name.
 	BreakAfterMatch bool `mapstructure:"break_after_match"`
+
+	// MatchAll specifies if each rule is applied to all the non-overlapping
+	// matches in the span name rather than only the first one, e.g. to replace
+	// all the IDs in "/users/123/orders/456" with the rule "/(?P<id>\d+)" to
+	// get "/users/{id}/orders/{id}". The first value extracted for an attribute
+	// name is added to the attribute of that name, the following ones to the
+	// attributes of that name suffixed with their index, e.g. "id" and "id.1".
+	MatchAll bool `mapstructure:"match_all"`
+}
+
+type Status struct {
+	// FromAttribute is the attribute key whose value sets the status code of
+	// the span. The value must be a string, "Ok", "Error" or "Unset" (case
+	// insensitive), any other value leaves the status unchanged.
+	FromAttribute string `mapstructure:"from_attribute"`
+
+	// FromHTTPStatusCode sets the status code of the span to Error when its
+	// status is unset and its "http.status_code" attribute is a 5xx code, or a
+	// 4xx code for a span that is not a server span, following the
+	// OpenTelemetry semantic conventions. It is applied after FromAttribute.
+	FromHTTPStatusCode bool `mapstructure:"from_http_status_code"`
+}
+
+type TruncateAttributes struct {
+	// Limit is the maximum length, in bytes, of the string attribute values of
+	// the span and of its events. The longer values are truncated without
+	// splitting UTF-8 characters. This field is required and must be positive.
+	Limit int `mapstructure:"limit"`
+
+	// Keys are the attribute keys whose values are truncated. If empty, the
+	// values of all the attributes are truncated.
+	Keys []string `mapstructure:"keys"`
 }
@@ -99,6 +99,37 @@ func TestLoadConfig(t *testing.T) {
 			},
 		},
 	})
+
+	p4 := cfg.Processors["span/match_all"]
+	assert.Equal(t, p4, &Config{
+		ProcessorSettings: configmodels.ProcessorSettings{
+			TypeVal: typeStr,
+			NameVal: "span/match_all",
+		},
+		Rename: Name{
+			ToAttributes: &ToAttributes{
+				Rules:    []string{`\/(?P<id>\d+)`},
+				MatchAll: true,
+			},
+		},
+	})
+
+	p5 := cfg.Processors["span/cleanup"]
+	assert.Equal(t, p5, &Config{
+		ProcessorSettings: configmodels.ProcessorSettings{
+			TypeVal: typeStr,
+			NameVal: "span/cleanup",
+		},
+		SetStatus: &Status{
+			FromAttribute:      "status",
+			FromHTTPStatusCode: true,
+		},
+		DropEvents: []string{`^debug\.`},
+		TruncateAttributes: &TruncateAttributes{
+			Limit: 1024,
+			Keys:  []string{"db.statement"},
+		},
+	})
 }
 
 func createMatchConfig(matchType filterset.MatchType) *filterset.Config {
@@ -35,7 +35,15 @@ var processorCapabilities = component.ProcessorCapabilities{MutatesConsumedData:
 // is not specified.
 // TODO https://github.com/open-telemetry/opentelemetry-collector/issues/215
 //	Move this to the error package that allows for span name and field to be specified.
-var errMissingRequiredField = errors.New("error creating \"span\" processor: either \"from_attributes\" or \"to_attributes\" must be specified in \"name:\"")
+var errMissingRequiredField = errors.New("error creating \"span\" processor: either \"from_attributes\" or \"to_attributes\" must be specified in \"name:\", or one of \"status\", \"drop_events\" or \"truncate_attributes\"")
+
+// errMissingStatusSource is returned when "status" has neither "from_attribute"
+// nor "from_http_status_code".
+var errMissingStatusSource = errors.New("error creating \"span\" processor: either \"from_attribute\" or \"from_http_status_code\" must be specified in \"status:\"")
+
+// errInvalidTruncateLimit is returned when the "truncate_attributes" limit is
+// not positive.
+var errInvalidTruncateLimit = errors.New("error creating \"span\" processor: \"limit\" must be positive in \"truncate_attributes:\"")
 
 // NewFactory returns a new factory for the Span processor.
 func NewFactory() component.ProcessorFactory {
@@ -61,13 +69,21 @@ func createTraceProcessor(
 	nextConsumer consumer.TracesConsumer,
 ) (component.TracesProcessor, error) {
 
-	// 'from_attributes' or 'to_attributes' under 'name' has to be set for the span
-	// processor to be valid. If not set and not enforced, the processor would do no work.
+	// 'from_attributes' or 'to_attributes' under 'name', or one of the other actions,
+	// has to be set for the span processor to be valid. If not set and not enforced,
+	// the processor would do no work.
 	oCfg := cfg.(*Config)
 	if len(oCfg.Rename.FromAttributes) == 0 &&
-		(oCfg.Rename.ToAttributes == nil || len(oCfg.Rename.ToAttributes.Rules) == 0) {
+		(oCfg.Rename.ToAttributes == nil || len(oCfg.Rename.ToAttributes.Rules) == 0) &&
+		oCfg.SetStatus == nil && len(oCfg.DropEvents) == 0 && oCfg.TruncateAttributes == nil {
 		return nil, errMissingRequiredField
 	}
+	if oCfg.SetStatus != nil && oCfg.SetStatus.FromAttribute == "" && !oCfg.SetStatus.FromHTTPStatusCode {
+		return nil, errMissingStatusSource
+	}
+	if oCfg.TruncateAttributes != nil && oCfg.TruncateAttributes.Limit <= 0 {
+		return nil, errInvalidTruncateLimit
+	}
 
 	sp, err := newSpanProcessor(*oCfg)
 	if err != nil {
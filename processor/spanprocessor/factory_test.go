@@ -97,6 +97,43 @@ func TestFactory_CreateTraceProcessor_InvalidConfig(t *testing.T) {
 	}
 }
 
+func TestFactory_CreateTraceProcessor_InvalidActions(t *testing.T) {
+	factory := NewFactory()
+
+	testcases := []struct {
+		name   string
+		modify func(cfg *Config)
+		err    error
+	}{
+		{
+			name:   "missing_status_source",
+			modify: func(cfg *Config) { cfg.SetStatus = &Status{} },
+			err:    errMissingStatusSource,
+		},
+		{
+			name:   "invalid_drop_events_regexp",
+			modify: func(cfg *Config) { cfg.DropEvents = []string{"\\"} },
+			err:    fmt.Errorf("invalid regexp pattern \\"),
+		},
+		{
+			name:   "invalid_truncate_limit",
+			modify: func(cfg *Config) { cfg.TruncateAttributes = &TruncateAttributes{Keys: []string{"key"}} },
+			err:    errInvalidTruncateLimit,
+		},
+	}
+
+	for _, test := range testcases {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := factory.CreateDefaultConfig().(*Config)
+			test.modify(cfg)
+
+			tp, err := factory.CreateTracesProcessor(context.Background(), component.ProcessorCreateParams{Logger: zap.NewNop()}, cfg, consumertest.NewTracesNop())
+			require.Nil(t, tp)
+			assert.EqualValues(t, err, test.err)
+		})
+	}
+}
+
 func TestFactory_CreateMetricProcessor(t *testing.T) {
 	factory := NewFactory()
 	cfg := factory.CreateDefaultConfig()
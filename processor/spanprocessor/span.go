@@ -20,9 +20,11 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/internal/processor/filterspan"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
 )
 
 type spanProcessor struct {
@@ -30,6 +32,10 @@ type spanProcessor struct {
 	toAttributeRules []toAttributeRule
 	include          filterspan.Matcher
 	exclude          filterspan.Matcher
+	// dropEvents are the compiled DropEvents regexps.
+	dropEvents []*regexp.Regexp
+	// truncateKeys are the TruncateAttributes keys, nil to truncate all attributes.
+	truncateKeys map[string]struct{}
 }
 
 // toAttributeRule is the compiled equivalent of config.ToAttributes field.
@@ -76,6 +82,21 @@ func newSpanProcessor(config Config) (*spanProcessor, error) {
 		}
 	}
 
+	for _, pattern := range config.DropEvents {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regexp pattern %s", pattern)
+		}
+		sp.dropEvents = append(sp.dropEvents, re)
+	}
+
+	if config.TruncateAttributes != nil && len(config.TruncateAttributes.Keys) > 0 {
+		sp.truncateKeys = make(map[string]struct{}, len(config.TruncateAttributes.Keys))
+		for _, key := range config.TruncateAttributes.Keys {
+			sp.truncateKeys[key] = struct{}{}
+		}
+	}
+
 	return sp, nil
 }
 
@@ -96,6 +117,9 @@ func (sp *spanProcessor) ProcessTraces(_ context.Context, td pdata.Traces) (pdat
 				}
 				sp.processFromAttributes(s)
 				sp.processToAttributes(s)
+				sp.processStatus(s)
+				sp.processDropEvents(s)
+				sp.processTruncateAttributes(s)
 			}
 		}
 	}
@@ -173,13 +197,17 @@ func (sp *spanProcessor) processToAttributes(span pdata.Span) {
 		re := rule.re
 		oldName := span.Name()
 
-		// Match the regular expression and extract matched subexpressions.
-		submatches := re.FindStringSubmatch(oldName)
-		if submatches == nil {
+		// Match the regular expression and find the positions of the matched
+		// subexpressions, in all the matches if MatchAll is set.
+		var matches [][]int
+		if sp.config.Rename.ToAttributes.MatchAll {
+			matches = re.FindAllStringSubmatchIndex(oldName, -1)
+		} else if submatchIdxPairs := re.FindStringSubmatchIndex(oldName); submatchIdxPairs != nil {
+			matches = [][]int{submatchIdxPairs}
+		}
+		if len(matches) == 0 {
 			continue
 		}
-		// There is a match. We will also need positions of subexpression matches.
-		submatchIdxPairs := re.FindStringSubmatchIndex(oldName)
 
 		// A place to accumulate new span name.
 		var sb strings.Builder
@@ -189,21 +217,39 @@ func (sp *spanProcessor) processToAttributes(span pdata.Span) {
 
 		attrs := span.Attributes()
 
+		// The number of values extracted for each attribute name, the subsequent
+		// values of a name extracted more than once are suffixed with their index.
+		extracted := make(map[string]int, len(rule.attrNames))
+
 		// TODO: Pre-allocate len(submatches) space in the attributes.
 
-		// Start from index 1, which is the first submatch (index 0 is the entire match).
-		// We will go over submatches and will simultaneously build a new span name,
-		// replacing matched subexpressions by attribute names.
-		for i := 1; i < len(submatches); i++ {
-			attrs.UpsertString(rule.attrNames[i], submatches[i])
+		for _, submatchIdxPairs := range matches {
+			// Start from index 1, which is the first submatch (index 0 is the entire match).
+			// We will go over submatches and will simultaneously build a new span name,
+			// replacing matched subexpressions by attribute names.
+			for i := 1; i < len(rule.attrNames); i++ {
+				matchStartIndex := submatchIdxPairs[i*2] // start of i'th submatch.
+				matchEndIndex := submatchIdxPairs[i*2+1] // end of i'th submatch.
+				if matchStartIndex < 0 {
+					// The subexpression did not participate in the match.
+					continue
+				}
+
+				attrName := rule.attrNames[i]
+				attrKey := attrName
+				if n := extracted[attrName]; n > 0 {
+					attrKey = attrName + "." + strconv.Itoa(n)
+				}
+				extracted[attrName]++
+				attrs.UpsertString(attrKey, oldName[matchStartIndex:matchEndIndex])
 
-			// Add part of span name from end of previous match to start of this match
-			// and then add attribute name wrapped in curly brackets.
-			matchStartIndex := submatchIdxPairs[i*2] // start of i'th submatch.
-			sb.WriteString(oldName[oldNameIndex:matchStartIndex] + "{" + rule.attrNames[i] + "}")
+				// Add part of span name from end of previous match to start of this match
+				// and then add attribute name wrapped in curly brackets.
+				sb.WriteString(oldName[oldNameIndex:matchStartIndex] + "{" + attrName + "}")
 
-			// Advance the index to the end of current match.
-			oldNameIndex = submatchIdxPairs[i*2+1] // end of i'th submatch.
+				// Advance the index to the end of current match.
+				oldNameIndex = matchEndIndex
+			}
 		}
 		if oldNameIndex < len(oldName) {
 			// Append the remainder, from the end of last match until end of span name.
@@ -219,3 +265,127 @@ func (sp *spanProcessor) processToAttributes(span pdata.Span) {
 		}
 	}
 }
+
+func (sp *spanProcessor) processStatus(span pdata.Span) {
+	if sp.config.SetStatus == nil {
+		// No status to set.
+		return
+	}
+
+	if sp.config.SetStatus.FromAttribute != "" {
+		if attr, found := span.Attributes().Get(sp.config.SetStatus.FromAttribute); found && attr.Type() == pdata.AttributeValueSTRING {
+			switch strings.ToLower(attr.StringVal()) {
+			case "ok":
+				span.Status().SetCode(pdata.StatusCodeOk)
+			case "error":
+				span.Status().SetCode(pdata.StatusCodeError)
+			case "unset":
+				span.Status().SetCode(pdata.StatusCodeUnset)
+			}
+		}
+	}
+
+	if sp.config.SetStatus.FromHTTPStatusCode && span.Status().Code() == pdata.StatusCodeUnset {
+		code, ok := httpStatusCode(span)
+		if !ok {
+			return
+		}
+		// Per the semantic conventions the 4xx codes are errors of the
+		// client, not of the server.
+		if (code >= 500 && code < 600) || (code >= 400 && code < 500 && span.Kind() != pdata.SpanKindSERVER) {
+			span.Status().SetCode(pdata.StatusCodeError)
+		}
+	}
+}
+
+// httpStatusCode returns the "http.status_code" attribute of the span, it may
+// be an integer or a string depending on the instrumentation.
+func httpStatusCode(span pdata.Span) (int64, bool) {
+	attr, found := span.Attributes().Get(tracetranslator.TagHTTPStatusCode)
+	if !found {
+		return 0, false
+	}
+	switch attr.Type() {
+	case pdata.AttributeValueINT:
+		return attr.IntVal(), true
+	case pdata.AttributeValueSTRING:
+		code, err := strconv.ParseInt(attr.StringVal(), 10, 64)
+		return code, err == nil
+	}
+	return 0, false
+}
+
+func (sp *spanProcessor) processDropEvents(span pdata.Span) {
+	if len(sp.dropEvents) == 0 {
+		// No events to drop.
+		return
+	}
+
+	// Move the kept events to the front of the slice, then truncate it.
+	events := span.Events()
+	kept := 0
+	for i := 0; i < events.Len(); i++ {
+		event := events.At(i)
+		if sp.dropEvent(event.Name()) {
+			continue
+		}
+		if kept != i {
+			event.CopyTo(events.At(kept))
+		}
+		kept++
+	}
+	if dropped := events.Len() - kept; dropped > 0 {
+		events.Resize(kept)
+		span.SetDroppedEventsCount(span.DroppedEventsCount() + uint32(dropped))
+	}
+}
+
+func (sp *spanProcessor) dropEvent(name string) bool {
+	for _, re := range sp.dropEvents {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (sp *spanProcessor) processTruncateAttributes(span pdata.Span) {
+	if sp.config.TruncateAttributes == nil {
+		// No attributes to truncate.
+		return
+	}
+
+	sp.truncateAttributes(span.Attributes())
+	events := span.Events()
+	for i := 0; i < events.Len(); i++ {
+		sp.truncateAttributes(events.At(i).Attributes())
+	}
+}
+
+func (sp *spanProcessor) truncateAttributes(attrs pdata.AttributeMap) {
+	limit := sp.config.TruncateAttributes.Limit
+	attrs.ForEach(func(k string, v pdata.AttributeValue) {
+		if v.Type() != pdata.AttributeValueSTRING || len(v.StringVal()) <= limit {
+			return
+		}
+		if sp.truncateKeys != nil {
+			if _, ok := sp.truncateKeys[k]; !ok {
+				return
+			}
+		}
+		v.SetStringVal(truncateString(v.StringVal(), limit))
+	})
+}
+
+// truncateString returns the longest prefix of s of at most limit bytes which
+// does not split a UTF-8 character.
+func truncateString(s string, limit int) string {
+	if len(s) <= limit {
+		return s
+	}
+	end := limit
+	for end > 0 && !utf8.RuneStart(s[end]) {
+		end--
+	}
+	return s[:end]
+}
@@ -451,6 +451,7 @@ func TestSpanProcessor_ToAttributes(t *testing.T) {
 	testCases := []struct {
 		rules           []string
 		breakAfterMatch bool
+		matchAll        bool
 		testCase
 	}{
 		{
@@ -512,6 +513,57 @@ func TestSpanProcessor_ToAttributes(t *testing.T) {
 				outputAttributes: nil,
 			},
 		},
+
+		{
+			rules: []string{`\/(?P<id>\d+)`},
+			testCase: testCase{
+				inputName:  "/users/123/orders/456/first",
+				outputName: "/users/{id}/orders/456/first",
+				outputAttributes: map[string]pdata.AttributeValue{
+					"id": pdata.NewAttributeValueString("123"),
+				},
+			},
+		},
+
+		{
+			rules: []string{`\/(?P<id>\d+)`},
+			testCase: testCase{
+				inputName:  "/users/123/orders/456/items/789/all",
+				outputName: "/users/{id}/orders/{id}/items/{id}/all",
+				outputAttributes: map[string]pdata.AttributeValue{
+					"id":   pdata.NewAttributeValueString("123"),
+					"id.1": pdata.NewAttributeValueString("456"),
+					"id.2": pdata.NewAttributeValueString("789"),
+				},
+			},
+			matchAll: true,
+		},
+
+		{
+			rules: []string{`^\/users\/(?P<id>\d+)\/orders\/(?P<id>\d+)(?:\/(?P<action>[a-z]+))?$`},
+			testCase: testCase{
+				inputName:  "/users/123/orders/456",
+				outputName: "/users/{id}/orders/{id}",
+				outputAttributes: map[string]pdata.AttributeValue{
+					"id":   pdata.NewAttributeValueString("123"),
+					"id.1": pdata.NewAttributeValueString("456"),
+				},
+			},
+		},
+
+		{
+			rules: []string{`\/(?P<uuid>[0-9a-f]{8}-[0-9a-f-]{27})`, `\/(?P<id>\d+)`},
+			testCase: testCase{
+				inputName:  "/orders/1/items/2/0f8fad5b-d9cb-469f-a165-70867728950e",
+				outputName: "/orders/{id}/items/{id}/{uuid}",
+				outputAttributes: map[string]pdata.AttributeValue{
+					"id":   pdata.NewAttributeValueString("1"),
+					"id.1": pdata.NewAttributeValueString("2"),
+					"uuid": pdata.NewAttributeValueString("0f8fad5b-d9cb-469f-a165-70867728950e"),
+				},
+			},
+			matchAll: true,
+		},
 	}
 
 	factory := NewFactory()
@@ -522,6 +574,7 @@ func TestSpanProcessor_ToAttributes(t *testing.T) {
 	for _, tc := range testCases {
 		oCfg.Rename.ToAttributes.Rules = tc.rules
 		oCfg.Rename.ToAttributes.BreakAfterMatch = tc.breakAfterMatch
+		oCfg.Rename.ToAttributes.MatchAll = tc.matchAll
 		tp, err := factory.CreateTracesProcessor(context.Background(), component.ProcessorCreateParams{Logger: zap.NewNop()}, oCfg, consumertest.NewTracesNop())
 		require.Nil(t, err)
 		require.NotNil(t, tp)
@@ -597,3 +650,189 @@ func TestSpanProcessor_skipSpan(t *testing.T) {
 		runIndividualTestCase(t, tc, tp)
 	}
 }
+
+func newSpanWithAttributes(kind pdata.SpanKind, attrs map[string]pdata.AttributeValue) (pdata.Traces, pdata.Span) {
+	td := generateTraceData("svc", "operation", attrs)
+	span := td.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0)
+	span.SetKind(kind)
+	return td, span
+}
+
+func TestSpanProcessor_Status(t *testing.T) {
+	testCases := []struct {
+		name   string
+		kind   pdata.SpanKind
+		attrs  map[string]pdata.AttributeValue
+		status pdata.StatusCode
+		want   pdata.StatusCode
+	}{
+		{
+			name:  "from_attribute",
+			attrs: map[string]pdata.AttributeValue{"status": pdata.NewAttributeValueString("Error")},
+			want:  pdata.StatusCodeError,
+		},
+		{
+			name:   "from_attribute_overrides",
+			attrs:  map[string]pdata.AttributeValue{"status": pdata.NewAttributeValueString("ok")},
+			status: pdata.StatusCodeError,
+			want:   pdata.StatusCodeOk,
+		},
+		{
+			name:   "from_attribute_invalid",
+			attrs:  map[string]pdata.AttributeValue{"status": pdata.NewAttributeValueString("failed")},
+			status: pdata.StatusCodeOk,
+			want:   pdata.StatusCodeOk,
+		},
+		{
+			name:  "http_5xx",
+			kind:  pdata.SpanKindSERVER,
+			attrs: map[string]pdata.AttributeValue{"http.status_code": pdata.NewAttributeValueInt(503)},
+			want:  pdata.StatusCodeError,
+		},
+		{
+			name:  "http_4xx_client",
+			kind:  pdata.SpanKindCLIENT,
+			attrs: map[string]pdata.AttributeValue{"http.status_code": pdata.NewAttributeValueString("404")},
+			want:  pdata.StatusCodeError,
+		},
+		{
+			name:  "http_4xx_server",
+			kind:  pdata.SpanKindSERVER,
+			attrs: map[string]pdata.AttributeValue{"http.status_code": pdata.NewAttributeValueInt(404)},
+			want:  pdata.StatusCodeUnset,
+		},
+		{
+			name:  "http_2xx",
+			kind:  pdata.SpanKindCLIENT,
+			attrs: map[string]pdata.AttributeValue{"http.status_code": pdata.NewAttributeValueInt(200)},
+			want:  pdata.StatusCodeUnset,
+		},
+		{
+			name:   "http_status_set",
+			kind:   pdata.SpanKindCLIENT,
+			attrs:  map[string]pdata.AttributeValue{"http.status_code": pdata.NewAttributeValueInt(500)},
+			status: pdata.StatusCodeOk,
+			want:   pdata.StatusCodeOk,
+		},
+		{
+			name: "from_attribute_before_http",
+			kind: pdata.SpanKindCLIENT,
+			attrs: map[string]pdata.AttributeValue{
+				"status":           pdata.NewAttributeValueString("unset"),
+				"http.status_code": pdata.NewAttributeValueInt(500),
+			},
+			status: pdata.StatusCodeOk,
+			want:   pdata.StatusCodeError,
+		},
+	}
+
+	factory := NewFactory()
+	oCfg := factory.CreateDefaultConfig().(*Config)
+	oCfg.SetStatus = &Status{FromAttribute: "status", FromHTTPStatusCode: true}
+	tp, err := factory.CreateTracesProcessor(context.Background(), component.ProcessorCreateParams{Logger: zap.NewNop()}, oCfg, consumertest.NewTracesNop())
+	require.Nil(t, err)
+	require.NotNil(t, tp)
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			td, span := newSpanWithAttributes(tc.kind, tc.attrs)
+			span.Status().SetCode(tc.status)
+			assert.NoError(t, tp.ConsumeTraces(context.Background(), td))
+			assert.Equal(t, tc.want, span.Status().Code())
+		})
+	}
+}
+
+func TestSpanProcessor_DropEvents(t *testing.T) {
+	factory := NewFactory()
+	oCfg := factory.CreateDefaultConfig().(*Config)
+	oCfg.DropEvents = []string{"^debug\\.", "^heartbeat$"}
+	tp, err := factory.CreateTracesProcessor(context.Background(), component.ProcessorCreateParams{Logger: zap.NewNop()}, oCfg, consumertest.NewTracesNop())
+	require.Nil(t, err)
+	require.NotNil(t, tp)
+
+	td, span := newSpanWithAttributes(pdata.SpanKindINTERNAL, nil)
+	span.SetDroppedEventsCount(1)
+	names := []string{"debug.start", "exception", "heartbeat", "retry", "debug.end", "heartbeats"}
+	span.Events().Resize(len(names))
+	for i, name := range names {
+		span.Events().At(i).SetName(name)
+		span.Events().At(i).Attributes().InsertInt("index", int64(i))
+	}
+	assert.NoError(t, tp.ConsumeTraces(context.Background(), td))
+
+	var got []string
+	for i := 0; i < span.Events().Len(); i++ {
+		got = append(got, span.Events().At(i).Name())
+	}
+	assert.Equal(t, []string{"exception", "retry", "heartbeats"}, got)
+	index, _ := span.Events().At(1).Attributes().Get("index")
+	assert.Equal(t, int64(3), index.IntVal())
+	assert.Equal(t, uint32(4), span.DroppedEventsCount())
+}
+
+func TestSpanProcessor_TruncateAttributes(t *testing.T) {
+	testCases := []struct {
+		name  string
+		keys  []string
+		attrs map[string]pdata.AttributeValue
+		want  map[string]pdata.AttributeValue
+	}{
+		{
+			name: "all",
+			attrs: map[string]pdata.AttributeValue{
+				"long":  pdata.NewAttributeValueString("0123456789"),
+				"short": pdata.NewAttributeValueString("0123"),
+				"int":   pdata.NewAttributeValueInt(1234567890),
+				"utf8":  pdata.NewAttributeValueString("abcdéf"),
+			},
+			want: map[string]pdata.AttributeValue{
+				"long":  pdata.NewAttributeValueString("01234"),
+				"short": pdata.NewAttributeValueString("0123"),
+				"int":   pdata.NewAttributeValueInt(1234567890),
+				"utf8":  pdata.NewAttributeValueString("abcd"),
+			},
+		},
+		{
+			name: "keys",
+			keys: []string{"db.statement"},
+			attrs: map[string]pdata.AttributeValue{
+				"db.statement": pdata.NewAttributeValueString("SELECT * FROM users"),
+				"url":          pdata.NewAttributeValueString("http://example.com"),
+			},
+			want: map[string]pdata.AttributeValue{
+				"db.statement": pdata.NewAttributeValueString("SELEC"),
+				"url":          pdata.NewAttributeValueString("http://example.com"),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			factory := NewFactory()
+			oCfg := factory.CreateDefaultConfig().(*Config)
+			oCfg.TruncateAttributes = &TruncateAttributes{Limit: 5, Keys: tc.keys}
+			tp, err := factory.CreateTracesProcessor(context.Background(), component.ProcessorCreateParams{Logger: zap.NewNop()}, oCfg, consumertest.NewTracesNop())
+			require.Nil(t, err)
+			require.NotNil(t, tp)
+
+			td, span := newSpanWithAttributes(pdata.SpanKindINTERNAL, tc.attrs)
+			span.Events().Resize(1)
+			span.Events().At(0).Attributes().InitFromMap(tc.attrs)
+			assert.NoError(t, tp.ConsumeTraces(context.Background(), td))
+
+			want := pdata.NewAttributeMap().InitFromMap(tc.want).Sort()
+			assert.Equal(t, want, span.Attributes().Sort())
+			assert.Equal(t, want, span.Events().At(0).Attributes().Sort())
+		})
+	}
+}
+
+func TestTruncateString(t *testing.T) {
+	assert.Equal(t, "abc", truncateString("abc", 5))
+	assert.Equal(t, "abcde", truncateString("abcdef", 5))
+	// "é" is 2 bytes, "世" is 3 bytes.
+	assert.Equal(t, "abcd", truncateString("abcdé", 5))
+	assert.Equal(t, "é", truncateString("é世", 4))
+	assert.Equal(t, "", truncateString("世", 2))
+}
@@ -0,0 +1,146 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tailsamplingprocessor
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/config/configmodels"
+)
+
+// PolicyType indicates the type of a sampling policy.
+type PolicyType string
+
+const (
+	// AlwaysSample samples all the traces.
+	AlwaysSample PolicyType = "always_sample"
+	// Latency samples the traces lasting longer than a threshold.
+	Latency PolicyType = "latency"
+	// StatusCode samples the traces with a span having one of the given status codes.
+	StatusCode PolicyType = "status_code"
+	// StringAttribute samples the traces with a string attribute having one of the given values.
+	StringAttribute PolicyType = "string_attribute"
+	// NumericAttribute samples the traces with a numeric attribute within the given range.
+	NumericAttribute PolicyType = "numeric_attribute"
+	// RateLimiting samples the traces until a number of spans per second is reached.
+	RateLimiting PolicyType = "rate_limiting"
+	// Probabilistic samples a percentage of the traces.
+	Probabilistic PolicyType = "probabilistic"
+	// And samples the traces sampled by all its sub policies.
+	And PolicyType = "and"
+	// Or samples the traces sampled by any of its sub policies.
+	Or PolicyType = "or"
+)
+
+// LatencyCfg holds the configuration of the latency policy.
+type LatencyCfg struct {
+	// ThresholdMs is the duration, in milliseconds, from the earliest span start
+	// to the latest span end above which the trace is sampled.
+	ThresholdMs int64 `mapstructure:"threshold_ms"`
+}
+
+// StatusCodeCfg holds the configuration of the status code policy.
+type StatusCodeCfg struct {
+	// StatusCodes is the list of status codes, among Unset, Ok and Error,
+	// sampling the trace.
+	StatusCodes []string `mapstructure:"status_codes"`
+}
+
+// StringAttributeCfg holds the configuration of the string attribute policy.
+type StringAttributeCfg struct {
+	// Key is the span or resource attribute key to match.
+	Key string `mapstructure:"key"`
+	// Values is the list of values sampling the trace.
+	Values []string `mapstructure:"values"`
+}
+
+// NumericAttributeCfg holds the configuration of the numeric attribute policy.
+type NumericAttributeCfg struct {
+	// Key is the span or resource attribute key to match.
+	Key string `mapstructure:"key"`
+	// MinValue is the minimum value, inclusive, sampling the trace.
+	MinValue int64 `mapstructure:"min_value"`
+	// MaxValue is the maximum value, inclusive, sampling the trace.
+	MaxValue int64 `mapstructure:"max_value"`
+}
+
+// RateLimitingCfg holds the configuration of the rate limiting policy.
+type RateLimitingCfg struct {
+	// SpansPerSecond is the maximum number of spans sampled per second.
+	SpansPerSecond int64 `mapstructure:"spans_per_second"`
+}
+
+// ProbabilisticCfg holds the configuration of the probabilistic policy.
+type ProbabilisticCfg struct {
+	// SamplingPercentage is the percentage of the traces sampled.
+	SamplingPercentage float64 `mapstructure:"sampling_percentage"`
+	// HashSalt is mixed to the trace ID before hashing it. Collectors using
+	// the same salt take the same decisions, configuring different salts in
+	// different layers of collectors avoids that.
+	HashSalt string `mapstructure:"hash_salt"`
+}
+
+// CompositeCfg holds the configuration of the and and or policies.
+type CompositeCfg struct {
+	// SubPolicies are the policies composed, they are evaluated in order.
+	SubPolicies []SubPolicyCfg `mapstructure:"policies"`
+}
+
+// sharedPolicyCfg holds the configuration shared by the policies and the sub
+// policies, only the configuration matching the type is used.
+type sharedPolicyCfg struct {
+	// Name of the policy, used in the metrics and the logs.
+	Name string `mapstructure:"name"`
+	// Type of the policy.
+	Type PolicyType `mapstructure:"type"`
+
+	LatencyCfg          LatencyCfg          `mapstructure:"latency"`
+	StatusCodeCfg       StatusCodeCfg       `mapstructure:"status_code"`
+	StringAttributeCfg  StringAttributeCfg  `mapstructure:"string_attribute"`
+	NumericAttributeCfg NumericAttributeCfg `mapstructure:"numeric_attribute"`
+	RateLimitingCfg     RateLimitingCfg     `mapstructure:"rate_limiting"`
+	ProbabilisticCfg    ProbabilisticCfg    `mapstructure:"probabilistic"`
+}
+
+// SubPolicyCfg holds the configuration of a policy composed by an and or an
+// or policy, it cannot be a composite policy itself.
+type SubPolicyCfg struct {
+	sharedPolicyCfg `mapstructure:",squash"`
+}
+
+// PolicyCfg holds the configuration of a sampling policy.
+type PolicyCfg struct {
+	sharedPolicyCfg `mapstructure:",squash"`
+
+	AndCfg CompositeCfg `mapstructure:"and"`
+	OrCfg  CompositeCfg `mapstructure:"or"`
+}
+
+// Config has the configuration of the tail sampling processor.
+type Config struct {
+	configmodels.ProcessorSettings `mapstructure:",squash"`
+	// DecisionWait is the time waited after the first span of a trace is
+	// received before the sampling decision of the trace is made.
+	DecisionWait time.Duration `mapstructure:"decision_wait"`
+	// NumTraces is the maximum number of traces kept in memory. The oldest
+	// traces are dropped when it is reached, even if their decision is not
+	// made yet.
+	NumTraces uint64 `mapstructure:"num_traces"`
+	// ExpectedNewTracesPerSec sizes the internal buffers to avoid reallocations.
+	ExpectedNewTracesPerSec uint64 `mapstructure:"expected_new_traces_per_sec"`
+	// Policies are the sampling policies, a trace is sampled as soon as one of
+	// them samples it.
+	Policies []PolicyCfg `mapstructure:"policies"`
+}
@@ -0,0 +1,107 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tailsamplingprocessor
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/config/configtest"
+)
+
+func TestLoadConfig(t *testing.T) {
+	factories, err := componenttest.NopFactories()
+	assert.NoError(t, err)
+
+	factory := NewFactory()
+	factories.Processors[typeStr] = factory
+	cfg, err := configtest.LoadConfigFile(t, path.Join(".", "testdata", "config.yaml"), factories)
+
+	require.Nil(t, err)
+	require.NotNil(t, cfg)
+
+	p0 := cfg.Processors["tail_sampling"]
+	assert.Equal(t, p0, factory.CreateDefaultConfig())
+
+	p1 := cfg.Processors["tail_sampling/custom"]
+	assert.Equal(t, p1,
+		&Config{
+			ProcessorSettings: configmodels.ProcessorSettings{
+				TypeVal: "tail_sampling",
+				NameVal: "tail_sampling/custom",
+			},
+			DecisionWait:            10 * time.Second,
+			NumTraces:               100,
+			ExpectedNewTracesPerSec: 10,
+			Policies: []PolicyCfg{
+				{
+					sharedPolicyCfg: sharedPolicyCfg{
+						Name:       "slow",
+						Type:       Latency,
+						LatencyCfg: LatencyCfg{ThresholdMs: 5000},
+					},
+				},
+				{
+					sharedPolicyCfg: sharedPolicyCfg{
+						Name:          "errors",
+						Type:          StatusCode,
+						StatusCodeCfg: StatusCodeCfg{StatusCodes: []string{"Error"}},
+					},
+				},
+				{
+					sharedPolicyCfg: sharedPolicyCfg{Name: "checkout-errors", Type: And},
+					AndCfg: CompositeCfg{SubPolicies: []SubPolicyCfg{
+						{sharedPolicyCfg: sharedPolicyCfg{
+							Name:               "checkout",
+							Type:               StringAttribute,
+							StringAttributeCfg: StringAttributeCfg{Key: "service.name", Values: []string{"checkout"}},
+						}},
+						{sharedPolicyCfg: sharedPolicyCfg{
+							Name:                "server-errors",
+							Type:                NumericAttribute,
+							NumericAttributeCfg: NumericAttributeCfg{Key: "http.status_code", MinValue: 500, MaxValue: 599},
+						}},
+					}},
+				},
+				{
+					sharedPolicyCfg: sharedPolicyCfg{Name: "baseline", Type: Or},
+					OrCfg: CompositeCfg{SubPolicies: []SubPolicyCfg{
+						{sharedPolicyCfg: sharedPolicyCfg{
+							Name:             "ten-percent",
+							Type:             Probabilistic,
+							ProbabilisticCfg: ProbabilisticCfg{SamplingPercentage: 10, HashSalt: "gateway"},
+						}},
+						{sharedPolicyCfg: sharedPolicyCfg{
+							Name: "always",
+							Type: AlwaysSample,
+						}},
+					}},
+				},
+				{
+					sharedPolicyCfg: sharedPolicyCfg{
+						Name:            "limited",
+						Type:            RateLimiting,
+						RateLimitingCfg: RateLimitingCfg{SpansPerSecond: 100},
+					},
+				},
+			},
+		})
+}
@@ -0,0 +1,54 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tailsamplingprocessor
+
+import (
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// idBatcher groups the trace IDs by the tick they were received at so that
+// their decision can be made after a fixed number of ticks. It is not safe
+// for concurrent use.
+type idBatcher struct {
+	batches     [][]pdata.TraceID
+	current     int
+	newBatchCap uint64
+}
+
+// newIDBatcher creates an idBatcher returning the IDs from the numBatches-th
+// call to closeCurrentAndTakeNext after they were added.
+func newIDBatcher(numBatches int, newBatchCap uint64) *idBatcher {
+	return &idBatcher{
+		batches:     make([][]pdata.TraceID, numBatches),
+		newBatchCap: newBatchCap,
+	}
+}
+
+// add adds the ID to the current batch.
+func (b *idBatcher) add(id pdata.TraceID) {
+	if b.batches[b.current] == nil {
+		b.batches[b.current] = make([]pdata.TraceID, 0, b.newBatchCap)
+	}
+	b.batches[b.current] = append(b.batches[b.current], id)
+}
+
+// closeCurrentAndTakeNext closes the current batch and returns the oldest
+// one, whose slot becomes the new current batch.
+func (b *idBatcher) closeCurrentAndTakeNext() []pdata.TraceID {
+	b.current = (b.current + 1) % len(b.batches)
+	batch := b.batches[b.current]
+	b.batches[b.current] = nil
+	return batch
+}
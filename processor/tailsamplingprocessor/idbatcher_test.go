@@ -0,0 +1,42 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tailsamplingprocessor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func TestIDBatcher(t *testing.T) {
+	b := newIDBatcher(3, 10)
+	b.add(traceID(1))
+	b.add(traceID(2))
+	assert.Empty(t, b.closeCurrentAndTakeNext())
+	b.add(traceID(3))
+	assert.Empty(t, b.closeCurrentAndTakeNext())
+	assert.Equal(t, []pdata.TraceID{traceID(1), traceID(2)}, b.closeCurrentAndTakeNext())
+	assert.Equal(t, []pdata.TraceID{traceID(3)}, b.closeCurrentAndTakeNext())
+	assert.Empty(t, b.closeCurrentAndTakeNext())
+}
+
+func TestIDBatcher_SingleBatch(t *testing.T) {
+	b := newIDBatcher(1, 0)
+	b.add(traceID(1))
+	assert.Equal(t, []pdata.TraceID{traceID(1)}, b.closeCurrentAndTakeNext())
+	assert.Empty(t, b.closeCurrentAndTakeNext())
+}
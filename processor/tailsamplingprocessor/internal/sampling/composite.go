@@ -0,0 +1,67 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+type and struct {
+	subPolicies []PolicyEvaluator
+}
+
+// NewAnd creates a policy evaluator sampling the traces sampled by all of the
+// sub-policies.
+func NewAnd(subPolicies []PolicyEvaluator) PolicyEvaluator {
+	return &and{subPolicies: subPolicies}
+}
+
+// Evaluate implements PolicyEvaluator.
+func (a *and) Evaluate(traceID pdata.TraceID, trace *TraceData) (Decision, error) {
+	for _, sub := range a.subPolicies {
+		d, err := sub.Evaluate(traceID, trace)
+		if err != nil {
+			return NotSampled, err
+		}
+		if d != Sampled {
+			return NotSampled, nil
+		}
+	}
+	return Sampled, nil
+}
+
+type or struct {
+	subPolicies []PolicyEvaluator
+}
+
+// NewOr creates a policy evaluator sampling the traces sampled by at least
+// one of the sub-policies.
+func NewOr(subPolicies []PolicyEvaluator) PolicyEvaluator {
+	return &or{subPolicies: subPolicies}
+}
+
+// Evaluate implements PolicyEvaluator.
+func (o *or) Evaluate(traceID pdata.TraceID, trace *TraceData) (Decision, error) {
+	for _, sub := range o.subPolicies {
+		d, err := sub.Evaluate(traceID, trace)
+		if err != nil {
+			return NotSampled, err
+		}
+		if d == Sampled {
+			return Sampled, nil
+		}
+	}
+	return NotSampled, nil
+}
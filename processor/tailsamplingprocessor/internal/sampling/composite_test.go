@@ -0,0 +1,66 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+type fixedPolicy struct {
+	decision Decision
+	err      error
+	calls    int
+}
+
+func (fp *fixedPolicy) Evaluate(pdata.TraceID, *TraceData) (Decision, error) {
+	fp.calls++
+	return fp.decision, fp.err
+}
+
+func TestAnd(t *testing.T) {
+	d, err := NewAnd([]PolicyEvaluator{&fixedPolicy{decision: Sampled}, &fixedPolicy{decision: Sampled}}).Evaluate(testTraceID, nil)
+	require.NoError(t, err)
+	assert.Equal(t, Sampled, d)
+
+	last := &fixedPolicy{decision: Sampled}
+	d, err = NewAnd([]PolicyEvaluator{&fixedPolicy{decision: NotSampled}, last}).Evaluate(testTraceID, nil)
+	require.NoError(t, err)
+	assert.Equal(t, NotSampled, d)
+	assert.Equal(t, 0, last.calls)
+
+	_, err = NewAnd([]PolicyEvaluator{&fixedPolicy{err: errors.New("failed")}}).Evaluate(testTraceID, nil)
+	assert.EqualError(t, err, "failed")
+}
+
+func TestOr(t *testing.T) {
+	d, err := NewOr([]PolicyEvaluator{&fixedPolicy{decision: NotSampled}, &fixedPolicy{decision: NotSampled}}).Evaluate(testTraceID, nil)
+	require.NoError(t, err)
+	assert.Equal(t, NotSampled, d)
+
+	last := &fixedPolicy{decision: NotSampled}
+	d, err = NewOr([]PolicyEvaluator{&fixedPolicy{decision: Sampled}, last}).Evaluate(testTraceID, nil)
+	require.NoError(t, err)
+	assert.Equal(t, Sampled, d)
+	assert.Equal(t, 0, last.calls)
+
+	_, err = NewOr([]PolicyEvaluator{&fixedPolicy{err: errors.New("failed")}}).Evaluate(testTraceID, nil)
+	assert.EqualError(t, err, "failed")
+}
@@ -0,0 +1,47 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+type latency struct {
+	threshold time.Duration
+}
+
+// NewLatency creates a policy evaluator sampling the traces lasting at least
+// the threshold, from the start of their first span to the end of their last
+// span.
+func NewLatency(threshold time.Duration) PolicyEvaluator {
+	return &latency{threshold: threshold}
+}
+
+// Evaluate implements PolicyEvaluator.
+func (l *latency) Evaluate(_ pdata.TraceID, trace *TraceData) (Decision, error) {
+	var minStart, maxEnd pdata.Timestamp
+	matched := forEachSpan(trace, func(span pdata.Span, _ pdata.Resource) bool {
+		if minStart == 0 || span.StartTime() < minStart {
+			minStart = span.StartTime()
+		}
+		if span.EndTime() > maxEnd {
+			maxEnd = span.EndTime()
+		}
+		return maxEnd > minStart && time.Duration(maxEnd-minStart) >= l.threshold
+	})
+	return decision(matched), nil
+}
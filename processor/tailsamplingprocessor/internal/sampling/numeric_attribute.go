@@ -0,0 +1,50 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+type numericAttribute struct {
+	key      string
+	minValue int64
+	maxValue int64
+}
+
+// NewNumericAttribute creates a policy evaluator sampling the traces with at
+// least a span having the numeric attribute key within [minValue, maxValue].
+func NewNumericAttribute(key string, minValue, maxValue int64) PolicyEvaluator {
+	return &numericAttribute{key: key, minValue: minValue, maxValue: maxValue}
+}
+
+// Evaluate implements PolicyEvaluator.
+func (na *numericAttribute) Evaluate(_ pdata.TraceID, trace *TraceData) (Decision, error) {
+	matched := forEachSpan(trace, func(span pdata.Span, _ pdata.Resource) bool {
+		v, ok := span.Attributes().Get(na.key)
+		if !ok {
+			return false
+		}
+		switch v.Type() {
+		case pdata.AttributeValueINT:
+			return v.IntVal() >= na.minValue && v.IntVal() <= na.maxValue
+		case pdata.AttributeValueDOUBLE:
+			return v.DoubleVal() >= float64(na.minValue) && v.DoubleVal() <= float64(na.maxValue)
+		default:
+			return false
+		}
+	})
+	return decision(matched), nil
+}
@@ -0,0 +1,97 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sampling contains the policies evaluating the sampling decision of
+// complete traces.
+package sampling
+
+import (
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// Decision gives the status of a sampling decision.
+type Decision int32
+
+const (
+	// Pending indicates that the decision of the trace is not made yet.
+	Pending Decision = iota
+	// Sampled indicates that the trace is sampled and must be forwarded.
+	Sampled
+	// NotSampled indicates that the trace is not sampled and must be dropped.
+	NotSampled
+)
+
+// String returns the name of the decision.
+func (d Decision) String() string {
+	switch d {
+	case Sampled:
+		return "sampled"
+	case NotSampled:
+		return "not_sampled"
+	default:
+		return "pending"
+	}
+}
+
+// TraceData stores the spans received for a trace and its sampling decision.
+type TraceData struct {
+	sync.Mutex
+	// ArrivalTime is the time the first span of the trace was received.
+	ArrivalTime time.Time
+	// SpanCount is the number of spans received for the trace.
+	SpanCount int64
+	// ReceivedBatches stores the spans received for the trace until the
+	// decision is made.
+	ReceivedBatches []pdata.Traces
+	// FinalDecision is the sampling decision of the trace.
+	FinalDecision Decision
+}
+
+// PolicyEvaluator evaluates the sampling decision of a trace. Evaluate is
+// called with the lock of the trace held.
+type PolicyEvaluator interface {
+	Evaluate(traceID pdata.TraceID, trace *TraceData) (Decision, error)
+}
+
+// forEachSpan calls f for all the spans of the trace until it returns true,
+// and returns whether f returned true.
+func forEachSpan(trace *TraceData, f func(span pdata.Span, resource pdata.Resource) bool) bool {
+	for _, batch := range trace.ReceivedBatches {
+		rss := batch.ResourceSpans()
+		for i := 0; i < rss.Len(); i++ {
+			rs := rss.At(i)
+			ilss := rs.InstrumentationLibrarySpans()
+			for j := 0; j < ilss.Len(); j++ {
+				spans := ilss.At(j).Spans()
+				for k := 0; k < spans.Len(); k++ {
+					if f(spans.At(k), rs.Resource()) {
+						return true
+					}
+				}
+			}
+		}
+	}
+	return false
+}
+
+// decision returns Sampled if matched is true, NotSampled otherwise.
+func decision(matched bool) Decision {
+	if matched {
+		return Sampled
+	}
+	return NotSampled
+}
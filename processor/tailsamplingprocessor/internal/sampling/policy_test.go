@@ -0,0 +1,193 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+var testTraceID = pdata.NewTraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})
+
+type testSpan struct {
+	start      time.Duration
+	end        time.Duration
+	status     pdata.StatusCode
+	attributes map[string]pdata.AttributeValue
+}
+
+// newTraceData returns a trace with one batch per span, the span times are
+// relative to a fixed time.
+func newTraceData(resourceAttrs map[string]pdata.AttributeValue, spans ...testSpan) *TraceData {
+	base := pdata.Timestamp(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC).UnixNano())
+	trace := &TraceData{}
+	for _, s := range spans {
+		td := pdata.NewTraces()
+		rs := pdata.NewResourceSpans()
+		rs.Resource().Attributes().InitFromMap(resourceAttrs)
+		ils := pdata.NewInstrumentationLibrarySpans()
+		span := pdata.NewSpan()
+		span.SetTraceID(testTraceID)
+		span.SetStartTime(base + pdata.Timestamp(s.start))
+		span.SetEndTime(base + pdata.Timestamp(s.end))
+		span.Status().SetCode(s.status)
+		span.Attributes().InitFromMap(s.attributes)
+		ils.Spans().Append(span)
+		rs.InstrumentationLibrarySpans().Append(ils)
+		td.ResourceSpans().Append(rs)
+		trace.ReceivedBatches = append(trace.ReceivedBatches, td)
+		trace.SpanCount++
+	}
+	return trace
+}
+
+func TestDecisionString(t *testing.T) {
+	assert.Equal(t, "pending", Pending.String())
+	assert.Equal(t, "sampled", Sampled.String())
+	assert.Equal(t, "not_sampled", NotSampled.String())
+}
+
+func TestAlwaysSample(t *testing.T) {
+	d, err := NewAlwaysSample().Evaluate(testTraceID, newTraceData(nil))
+	require.NoError(t, err)
+	assert.Equal(t, Sampled, d)
+}
+
+func TestLatency(t *testing.T) {
+	policy := NewLatency(500 * time.Millisecond)
+	tests := []struct {
+		name     string
+		spans    []testSpan
+		expected Decision
+	}{
+		{
+			name:     "single fast span",
+			spans:    []testSpan{{start: 0, end: 100 * time.Millisecond}},
+			expected: NotSampled,
+		},
+		{
+			name:     "single slow span",
+			spans:    []testSpan{{start: 0, end: time.Second}},
+			expected: Sampled,
+		},
+		{
+			name: "fast spans spanning a long time",
+			spans: []testSpan{
+				{start: 200 * time.Millisecond, end: 300 * time.Millisecond},
+				{start: 0, end: 100 * time.Millisecond},
+				{start: 500 * time.Millisecond, end: 600 * time.Millisecond},
+			},
+			expected: Sampled,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := policy.Evaluate(testTraceID, newTraceData(nil, tt.spans...))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, d)
+		})
+	}
+}
+
+func TestStatusCode(t *testing.T) {
+	policy, err := NewStatusCode([]string{"Error", "unset"})
+	require.NoError(t, err)
+
+	d, err := policy.Evaluate(testTraceID, newTraceData(nil, testSpan{status: pdata.StatusCodeOk}, testSpan{status: pdata.StatusCodeError}))
+	require.NoError(t, err)
+	assert.Equal(t, Sampled, d)
+
+	d, err = policy.Evaluate(testTraceID, newTraceData(nil, testSpan{status: pdata.StatusCodeOk}))
+	require.NoError(t, err)
+	assert.Equal(t, NotSampled, d)
+}
+
+func TestStatusCode_Invalid(t *testing.T) {
+	_, err := NewStatusCode(nil)
+	assert.EqualError(t, err, "at least one status code must be specified")
+
+	_, err = NewStatusCode([]string{"failed"})
+	assert.EqualError(t, err, `unsupported status code "failed", valid status codes are {Unset, Ok, Error}`)
+}
+
+func TestStringAttribute(t *testing.T) {
+	policy := NewStringAttribute("http.method", []string{"POST", "PUT"})
+	tests := []struct {
+		name          string
+		resourceAttrs map[string]pdata.AttributeValue
+		spanAttrs     map[string]pdata.AttributeValue
+		expected      Decision
+	}{
+		{
+			name:      "span attribute",
+			spanAttrs: map[string]pdata.AttributeValue{"http.method": pdata.NewAttributeValueString("POST")},
+			expected:  Sampled,
+		},
+		{
+			name:          "resource attribute",
+			resourceAttrs: map[string]pdata.AttributeValue{"http.method": pdata.NewAttributeValueString("PUT")},
+			expected:      Sampled,
+		},
+		{
+			name:      "other value",
+			spanAttrs: map[string]pdata.AttributeValue{"http.method": pdata.NewAttributeValueString("GET")},
+			expected:  NotSampled,
+		},
+		{
+			name:      "not a string",
+			spanAttrs: map[string]pdata.AttributeValue{"http.method": pdata.NewAttributeValueInt(1)},
+			expected:  NotSampled,
+		},
+		{
+			name:     "missing",
+			expected: NotSampled,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := policy.Evaluate(testTraceID, newTraceData(tt.resourceAttrs, testSpan{attributes: tt.spanAttrs}))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, d)
+		})
+	}
+}
+
+func TestNumericAttribute(t *testing.T) {
+	policy := NewNumericAttribute("http.status_code", 500, 599)
+	tests := []struct {
+		name     string
+		value    pdata.AttributeValue
+		expected Decision
+	}{
+		{name: "int in range", value: pdata.NewAttributeValueInt(503), expected: Sampled},
+		{name: "double in range", value: pdata.NewAttributeValueDouble(500), expected: Sampled},
+		{name: "lower", value: pdata.NewAttributeValueInt(200), expected: NotSampled},
+		{name: "greater", value: pdata.NewAttributeValueInt(600), expected: NotSampled},
+		{name: "string", value: pdata.NewAttributeValueString("503"), expected: NotSampled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trace := newTraceData(nil, testSpan{attributes: map[string]pdata.AttributeValue{"http.status_code": tt.value}})
+			d, err := policy.Evaluate(testTraceID, trace)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, d)
+		})
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"hash/fnv"
+	"math"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+type probabilistic struct {
+	threshold uint64
+	hashSalt  string
+}
+
+// NewProbabilistic creates a policy evaluator sampling the given percentage
+// of the traces. The decision is based on the hash of the trace ID, salted
+// with hashSalt, so that the collectors with the same salt make the same
+// decisions.
+func NewProbabilistic(samplingPercentage float64, hashSalt string) PolicyEvaluator {
+	return &probabilistic{
+		threshold: calculateThreshold(samplingPercentage / 100),
+		hashSalt:  hashSalt,
+	}
+}
+
+func calculateThreshold(ratio float64) uint64 {
+	if ratio >= 1 {
+		return math.MaxUint64
+	}
+	if ratio <= 0 {
+		return 0
+	}
+	return uint64(ratio * math.MaxUint64)
+}
+
+// Evaluate implements PolicyEvaluator.
+func (p *probabilistic) Evaluate(traceID pdata.TraceID, _ *TraceData) (Decision, error) {
+	if p.threshold == math.MaxUint64 {
+		return Sampled, nil
+	}
+	return decision(hashTraceID(p.hashSalt, traceID) < p.threshold), nil
+}
+
+func hashTraceID(salt string, traceID pdata.TraceID) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(salt))
+	b := traceID.Bytes()
+	_, _ = h.Write(b[:])
+	// FNV does not spread the last written bytes over the high bits, mix the
+	// result so that the thresholds work on similar trace IDs too.
+	x := h.Sum64()
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+	return x
+}
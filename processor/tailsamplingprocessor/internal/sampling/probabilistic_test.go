@@ -0,0 +1,70 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func TestProbabilistic(t *testing.T) {
+	tests := []struct {
+		name       string
+		percentage float64
+		hashSalt   string
+		min, max   int
+	}{
+		{name: "none", percentage: 0, min: 0, max: 0},
+		{name: "all", percentage: 100, min: 10000, max: 10000},
+		{name: "quarter", percentage: 25, min: 2300, max: 2700},
+		{name: "quarter with salt", percentage: 25, hashSalt: "salt", min: 2300, max: 2700},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := NewProbabilistic(tt.percentage, tt.hashSalt)
+			sampled := 0
+			for i := 0; i < 10000; i++ {
+				d, err := policy.Evaluate(traceIDFromInt(i), nil)
+				require.NoError(t, err)
+				if d == Sampled {
+					sampled++
+				}
+			}
+			assert.GreaterOrEqual(t, sampled, tt.min)
+			assert.LessOrEqual(t, sampled, tt.max)
+		})
+	}
+}
+
+func TestProbabilistic_Consistent(t *testing.T) {
+	first := NewProbabilistic(50, "salt")
+	second := NewProbabilistic(50, "salt")
+	for i := 0; i < 100; i++ {
+		d1, _ := first.Evaluate(traceIDFromInt(i), nil)
+		d2, _ := second.Evaluate(traceIDFromInt(i), nil)
+		assert.Equal(t, d1, d2)
+	}
+}
+
+func traceIDFromInt(i int) pdata.TraceID {
+	var b [16]byte
+	binary.BigEndian.PutUint64(b[8:], uint64(i))
+	return pdata.NewTraceID(b)
+}
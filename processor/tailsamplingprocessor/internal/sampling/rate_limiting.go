@@ -0,0 +1,54 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+type rateLimiting struct {
+	spansPerSecond int64
+
+	mu            sync.Mutex
+	currentSecond int64
+	spansInSecond int64
+	now           func() time.Time
+}
+
+// NewRateLimiting creates a policy evaluator sampling the traces as long as
+// the spans of the traces sampled in the current second don't exceed
+// spansPerSecond.
+func NewRateLimiting(spansPerSecond int64) PolicyEvaluator {
+	return &rateLimiting{spansPerSecond: spansPerSecond, now: time.Now}
+}
+
+// Evaluate implements PolicyEvaluator.
+func (rl *rateLimiting) Evaluate(_ pdata.TraceID, trace *TraceData) (Decision, error) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	if second := rl.now().Unix(); second != rl.currentSecond {
+		rl.currentSecond = second
+		rl.spansInSecond = 0
+	}
+	if rl.spansInSecond+trace.SpanCount > rl.spansPerSecond {
+		return NotSampled, nil
+	}
+	rl.spansInSecond += trace.SpanCount
+	return Sampled, nil
+}
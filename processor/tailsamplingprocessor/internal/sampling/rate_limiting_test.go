@@ -0,0 +1,50 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiting(t *testing.T) {
+	now := time.Unix(1000, 0)
+	policy := NewRateLimiting(3).(*rateLimiting)
+	policy.now = func() time.Time { return now }
+
+	twoSpans := newTraceData(nil, testSpan{}, testSpan{})
+
+	d, err := policy.Evaluate(testTraceID, twoSpans)
+	require.NoError(t, err)
+	assert.Equal(t, Sampled, d)
+
+	// The second trace would exceed the limit of the current second.
+	d, err = policy.Evaluate(testTraceID, twoSpans)
+	require.NoError(t, err)
+	assert.Equal(t, NotSampled, d)
+
+	d, err = policy.Evaluate(testTraceID, newTraceData(nil, testSpan{}))
+	require.NoError(t, err)
+	assert.Equal(t, Sampled, d)
+
+	// The limit is reset the next second.
+	now = now.Add(time.Second)
+	d, err = policy.Evaluate(testTraceID, twoSpans)
+	require.NoError(t, err)
+	assert.Equal(t, Sampled, d)
+}
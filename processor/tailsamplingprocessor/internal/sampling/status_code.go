@@ -0,0 +1,57 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+var statusCodes = map[string]pdata.StatusCode{
+	"unset": pdata.StatusCodeUnset,
+	"ok":    pdata.StatusCodeOk,
+	"error": pdata.StatusCodeError,
+}
+
+type statusCode struct {
+	codes map[pdata.StatusCode]bool
+}
+
+// NewStatusCode creates a policy evaluator sampling the traces with at least
+// a span with one of the status codes {Unset, Ok, Error}.
+func NewStatusCode(codes []string) (PolicyEvaluator, error) {
+	if len(codes) == 0 {
+		return nil, fmt.Errorf("at least one status code must be specified")
+	}
+	sc := &statusCode{codes: map[pdata.StatusCode]bool{}}
+	for _, name := range codes {
+		code, ok := statusCodes[strings.ToLower(name)]
+		if !ok {
+			return nil, fmt.Errorf("unsupported status code %q, valid status codes are {Unset, Ok, Error}", name)
+		}
+		sc.codes[code] = true
+	}
+	return sc, nil
+}
+
+// Evaluate implements PolicyEvaluator.
+func (sc *statusCode) Evaluate(_ pdata.TraceID, trace *TraceData) (Decision, error) {
+	matched := forEachSpan(trace, func(span pdata.Span, _ pdata.Resource) bool {
+		return sc.codes[span.Status().Code()]
+	})
+	return decision(matched), nil
+}
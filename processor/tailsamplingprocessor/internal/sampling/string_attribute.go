@@ -0,0 +1,48 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+type stringAttribute struct {
+	key    string
+	values map[string]bool
+}
+
+// NewStringAttribute creates a policy evaluator sampling the traces with at
+// least a span, or the resource of a span, having the string attribute key
+// set to one of the values.
+func NewStringAttribute(key string, values []string) PolicyEvaluator {
+	sa := &stringAttribute{key: key, values: map[string]bool{}}
+	for _, value := range values {
+		sa.values[value] = true
+	}
+	return sa
+}
+
+// Evaluate implements PolicyEvaluator.
+func (sa *stringAttribute) Evaluate(_ pdata.TraceID, trace *TraceData) (Decision, error) {
+	matched := forEachSpan(trace, func(span pdata.Span, resource pdata.Resource) bool {
+		return sa.matches(span.Attributes()) || sa.matches(resource.Attributes())
+	})
+	return decision(matched), nil
+}
+
+func (sa *stringAttribute) matches(attrs pdata.AttributeMap) bool {
+	v, ok := attrs.Get(sa.key)
+	return ok && v.Type() == pdata.AttributeValueSTRING && sa.values[v.StringVal()]
+}
@@ -0,0 +1,111 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tailsamplingprocessor
+
+import (
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+
+	"go.opentelemetry.io/collector/obsreport"
+	"go.opentelemetry.io/collector/processor"
+)
+
+var (
+	tagPolicyKey, _   = tag.NewKey("policy")
+	tagDecisionKey, _ = tag.NewKey("decision")
+
+	statPolicyDecisions  = stats.Int64("policy_decisions", "Number of sampling decisions made by a policy", stats.UnitDimensionless)
+	statPolicyErrors     = stats.Int64("policy_evaluation_errors", "Number of errors evaluating a policy", stats.UnitDimensionless)
+	statTraceDecisions   = stats.Int64("trace_decisions", "Number of sampling decisions made for the traces", stats.UnitDimensionless)
+	statLateSpans        = stats.Int64("late_spans", "Number of spans received after the decision of their trace", stats.UnitDimensionless)
+	statTracesDropped    = stats.Int64("traces_dropped", "Number of traces dropped from memory before their decision", stats.UnitDimensionless)
+	statTracesOnMemory   = stats.Int64("traces_on_memory", "Number of traces kept in memory", stats.UnitDimensionless)
+	statDecisionBatchLen = stats.Int64("decision_batch_size", "Number of traces evaluated at once", stats.UnitDimensionless)
+)
+
+// MetricViews returns the metrics views related to tail sampling.
+func MetricViews() []*view.View {
+	processorTagKeys := []tag.Key{processor.TagProcessorNameKey}
+	policyTagKeys := []tag.Key{processor.TagProcessorNameKey, tagPolicyKey, tagDecisionKey}
+
+	countPolicyDecisionsView := &view.View{
+		Name:        statPolicyDecisions.Name(),
+		Measure:     statPolicyDecisions,
+		Description: statPolicyDecisions.Description(),
+		TagKeys:     policyTagKeys,
+		Aggregation: view.Sum(),
+	}
+
+	countPolicyErrorsView := &view.View{
+		Name:        statPolicyErrors.Name(),
+		Measure:     statPolicyErrors,
+		Description: statPolicyErrors.Description(),
+		TagKeys:     []tag.Key{processor.TagProcessorNameKey, tagPolicyKey},
+		Aggregation: view.Sum(),
+	}
+
+	countTraceDecisionsView := &view.View{
+		Name:        statTraceDecisions.Name(),
+		Measure:     statTraceDecisions,
+		Description: statTraceDecisions.Description(),
+		TagKeys:     []tag.Key{processor.TagProcessorNameKey, tagDecisionKey},
+		Aggregation: view.Sum(),
+	}
+
+	countLateSpansView := &view.View{
+		Name:        statLateSpans.Name(),
+		Measure:     statLateSpans,
+		Description: statLateSpans.Description(),
+		TagKeys:     []tag.Key{processor.TagProcessorNameKey, tagDecisionKey},
+		Aggregation: view.Sum(),
+	}
+
+	countTracesDroppedView := &view.View{
+		Name:        statTracesDropped.Name(),
+		Measure:     statTracesDropped,
+		Description: statTracesDropped.Description(),
+		TagKeys:     processorTagKeys,
+		Aggregation: view.Sum(),
+	}
+
+	lastTracesOnMemoryView := &view.View{
+		Name:        statTracesOnMemory.Name(),
+		Measure:     statTracesOnMemory,
+		Description: statTracesOnMemory.Description(),
+		TagKeys:     processorTagKeys,
+		Aggregation: view.LastValue(),
+	}
+
+	distributionDecisionBatchLenView := &view.View{
+		Name:        statDecisionBatchLen.Name(),
+		Measure:     statDecisionBatchLen,
+		Description: statDecisionBatchLen.Description(),
+		TagKeys:     processorTagKeys,
+		Aggregation: view.Distribution(10, 25, 50, 75, 100, 250, 500, 750, 1000, 2000, 5000, 10000, 20000, 50000, 100000),
+	}
+
+	legacyViews := []*view.View{
+		countPolicyDecisionsView,
+		countPolicyErrorsView,
+		countTraceDecisionsView,
+		countLateSpansView,
+		countTracesDroppedView,
+		lastTracesOnMemoryView,
+		distributionDecisionBatchLenView,
+	}
+
+	return obsreport.ProcessorMetricViews(typeStr, legacyViews)
+}
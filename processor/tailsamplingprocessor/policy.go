@@ -0,0 +1,119 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tailsamplingprocessor
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/collector/processor/tailsamplingprocessor/internal/sampling"
+)
+
+// policy is a named sampling policy evaluator.
+type policy struct {
+	name      string
+	evaluator sampling.PolicyEvaluator
+}
+
+func newPolicies(cfgs []PolicyCfg) ([]*policy, error) {
+	if len(cfgs) == 0 {
+		return nil, errors.New("at least one policy must be configured")
+	}
+	policies := make([]*policy, 0, len(cfgs))
+	for _, cfg := range cfgs {
+		evaluator, err := newPolicyEvaluator(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create policy %q: %w", cfg.Name, err)
+		}
+		policies = append(policies, &policy{name: cfg.Name, evaluator: evaluator})
+	}
+	return policies, nil
+}
+
+func newPolicyEvaluator(cfg PolicyCfg) (sampling.PolicyEvaluator, error) {
+	switch cfg.Type {
+	case And:
+		subs, err := newSubPolicyEvaluators(cfg.Type, cfg.AndCfg.SubPolicies)
+		if err != nil {
+			return nil, err
+		}
+		return sampling.NewAnd(subs), nil
+	case Or:
+		subs, err := newSubPolicyEvaluators(cfg.Type, cfg.OrCfg.SubPolicies)
+		if err != nil {
+			return nil, err
+		}
+		return sampling.NewOr(subs), nil
+	default:
+		return newLeafPolicyEvaluator(cfg.sharedPolicyCfg)
+	}
+}
+
+func newLeafPolicyEvaluator(cfg sharedPolicyCfg) (sampling.PolicyEvaluator, error) {
+	switch cfg.Type {
+	case AlwaysSample:
+		return sampling.NewAlwaysSample(), nil
+	case Latency:
+		if cfg.LatencyCfg.ThresholdMs <= 0 {
+			return nil, errors.New("latency threshold_ms must be positive")
+		}
+		return sampling.NewLatency(time.Duration(cfg.LatencyCfg.ThresholdMs) * time.Millisecond), nil
+	case StatusCode:
+		return sampling.NewStatusCode(cfg.StatusCodeCfg.StatusCodes)
+	case StringAttribute:
+		sCfg := cfg.StringAttributeCfg
+		if sCfg.Key == "" || len(sCfg.Values) == 0 {
+			return nil, errors.New("string_attribute requires a key and at least one value")
+		}
+		return sampling.NewStringAttribute(sCfg.Key, sCfg.Values), nil
+	case NumericAttribute:
+		nCfg := cfg.NumericAttributeCfg
+		if nCfg.Key == "" {
+			return nil, errors.New("numeric_attribute requires a key")
+		}
+		if nCfg.MinValue > nCfg.MaxValue {
+			return nil, errors.New("numeric_attribute min_value must not be greater than max_value")
+		}
+		return sampling.NewNumericAttribute(nCfg.Key, nCfg.MinValue, nCfg.MaxValue), nil
+	case RateLimiting:
+		if cfg.RateLimitingCfg.SpansPerSecond <= 0 {
+			return nil, errors.New("rate_limiting spans_per_second must be positive")
+		}
+		return sampling.NewRateLimiting(cfg.RateLimitingCfg.SpansPerSecond), nil
+	case Probabilistic:
+		pCfg := cfg.ProbabilisticCfg
+		return sampling.NewProbabilistic(pCfg.SamplingPercentage, pCfg.HashSalt), nil
+	case And, Or:
+		return nil, fmt.Errorf("%s policy cannot be a sub policy", cfg.Type)
+	default:
+		return nil, fmt.Errorf("unknown sampling policy type %q", cfg.Type)
+	}
+}
+
+func newSubPolicyEvaluators(typ PolicyType, cfgs []SubPolicyCfg) ([]sampling.PolicyEvaluator, error) {
+	if len(cfgs) == 0 {
+		return nil, fmt.Errorf("%s policy requires at least one sub policy", typ)
+	}
+	subs := make([]sampling.PolicyEvaluator, 0, len(cfgs))
+	for _, cfg := range cfgs {
+		sub, err := newLeafPolicyEvaluator(cfg.sharedPolicyCfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create sub policy %q: %w", cfg.Name, err)
+		}
+		subs = append(subs, sub)
+	}
+	return subs, nil
+}
@@ -0,0 +1,111 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tailsamplingprocessor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPolicies(t *testing.T) {
+	policies, err := newPolicies([]PolicyCfg{
+		{sharedPolicyCfg: sharedPolicyCfg{Name: "all", Type: AlwaysSample}},
+		{sharedPolicyCfg: sharedPolicyCfg{Name: "slow", Type: Latency, LatencyCfg: LatencyCfg{ThresholdMs: 100}}},
+		{sharedPolicyCfg: sharedPolicyCfg{Name: "either", Type: Or}, OrCfg: CompositeCfg{SubPolicies: []SubPolicyCfg{
+			{sharedPolicyCfg: sharedPolicyCfg{Name: "ten-percent", Type: Probabilistic, ProbabilisticCfg: ProbabilisticCfg{SamplingPercentage: 10}}},
+			{sharedPolicyCfg: sharedPolicyCfg{Name: "limited", Type: RateLimiting, RateLimitingCfg: RateLimitingCfg{SpansPerSecond: 10}}},
+		}}},
+	})
+	require.NoError(t, err)
+	require.Len(t, policies, 3)
+	assert.Equal(t, "all", policies[0].name)
+	assert.Equal(t, "slow", policies[1].name)
+	assert.Equal(t, "either", policies[2].name)
+}
+
+func TestNewPolicies_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		cfgs   []PolicyCfg
+		errMsg string
+	}{
+		{
+			name:   "no policies",
+			errMsg: "at least one policy must be configured",
+		},
+		{
+			name:   "unknown type",
+			cfgs:   []PolicyCfg{{sharedPolicyCfg: sharedPolicyCfg{Name: "p", Type: "unknown"}}},
+			errMsg: `failed to create policy "p": unknown sampling policy type "unknown"`,
+		},
+		{
+			name:   "latency without threshold",
+			cfgs:   []PolicyCfg{{sharedPolicyCfg: sharedPolicyCfg{Name: "p", Type: Latency}}},
+			errMsg: `failed to create policy "p": latency threshold_ms must be positive`,
+		},
+		{
+			name:   "status code without codes",
+			cfgs:   []PolicyCfg{{sharedPolicyCfg: sharedPolicyCfg{Name: "p", Type: StatusCode}}},
+			errMsg: `failed to create policy "p": at least one status code must be specified`,
+		},
+		{
+			name:   "string attribute without values",
+			cfgs:   []PolicyCfg{{sharedPolicyCfg: sharedPolicyCfg{Name: "p", Type: StringAttribute, StringAttributeCfg: StringAttributeCfg{Key: "k"}}}},
+			errMsg: `failed to create policy "p": string_attribute requires a key and at least one value`,
+		},
+		{
+			name:   "numeric attribute without key",
+			cfgs:   []PolicyCfg{{sharedPolicyCfg: sharedPolicyCfg{Name: "p", Type: NumericAttribute}}},
+			errMsg: `failed to create policy "p": numeric_attribute requires a key`,
+		},
+		{
+			name:   "numeric attribute with inverted range",
+			cfgs:   []PolicyCfg{{sharedPolicyCfg: sharedPolicyCfg{Name: "p", Type: NumericAttribute, NumericAttributeCfg: NumericAttributeCfg{Key: "k", MinValue: 2, MaxValue: 1}}}},
+			errMsg: `failed to create policy "p": numeric_attribute min_value must not be greater than max_value`,
+		},
+		{
+			name:   "rate limiting without limit",
+			cfgs:   []PolicyCfg{{sharedPolicyCfg: sharedPolicyCfg{Name: "p", Type: RateLimiting}}},
+			errMsg: `failed to create policy "p": rate_limiting spans_per_second must be positive`,
+		},
+		{
+			name:   "and without sub policies",
+			cfgs:   []PolicyCfg{{sharedPolicyCfg: sharedPolicyCfg{Name: "p", Type: And}}},
+			errMsg: `failed to create policy "p": and policy requires at least one sub policy`,
+		},
+		{
+			name: "invalid sub policy",
+			cfgs: []PolicyCfg{{sharedPolicyCfg: sharedPolicyCfg{Name: "p", Type: Or}, OrCfg: CompositeCfg{SubPolicies: []SubPolicyCfg{
+				{sharedPolicyCfg: sharedPolicyCfg{Name: "sub", Type: Latency}},
+			}}}},
+			errMsg: `failed to create policy "p": failed to create sub policy "sub": latency threshold_ms must be positive`,
+		},
+		{
+			name: "composite sub policy",
+			cfgs: []PolicyCfg{{sharedPolicyCfg: sharedPolicyCfg{Name: "p", Type: And}, AndCfg: CompositeCfg{SubPolicies: []SubPolicyCfg{
+				{sharedPolicyCfg: sharedPolicyCfg{Name: "sub", Type: Or}},
+			}}}},
+			errMsg: `failed to create policy "p": failed to create sub policy "sub": or policy cannot be a sub policy`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newPolicies(tt.cfgs)
+			assert.EqualError(t, err, tt.errMsg)
+		})
+	}
+}
@@ -0,0 +1,256 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tailsamplingprocessor
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/tag"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal/batchpertrace"
+	"go.opentelemetry.io/collector/processor"
+	"go.opentelemetry.io/collector/processor/tailsamplingprocessor/internal/sampling"
+)
+
+var errNoTraces = errors.New("num_traces must be positive")
+
+// defaultTickInterval is the interval at which the decisions of the traces
+// waiting for decisionWait are made.
+const defaultTickInterval = time.Second
+
+// tailSamplingProcessor keeps the spans of the traces in memory until their
+// sampling decision is made, then forwards the spans of the sampled traces.
+// The spans received after the decision follow it.
+type tailSamplingProcessor struct {
+	name         string
+	logger       *zap.Logger
+	nextConsumer consumer.TracesConsumer
+	policies     []*policy
+	tickInterval time.Duration
+
+	mu      sync.Mutex
+	traces  map[pdata.TraceID]*sampling.TraceData
+	batcher *idBatcher
+	// arrivals is a circular buffer of the IDs of the traces in memory in
+	// their arrival order, used to drop the oldest traces.
+	arrivals    []pdata.TraceID
+	nextArrival int
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+var _ component.TracesProcessor = (*tailSamplingProcessor)(nil)
+
+func newTailSamplingProcessor(params component.ProcessorCreateParams, nextConsumer consumer.TracesConsumer, cfg *Config) (*tailSamplingProcessor, error) {
+	policies, err := newPolicies(cfg.Policies)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.NumTraces == 0 {
+		return nil, errNoTraces
+	}
+	numBatches := int(cfg.DecisionWait / defaultTickInterval)
+	if numBatches < 1 {
+		numBatches = 1
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &tailSamplingProcessor{
+		name:         cfg.Name(),
+		logger:       params.Logger,
+		nextConsumer: nextConsumer,
+		policies:     policies,
+		tickInterval: defaultTickInterval,
+		traces:       make(map[pdata.TraceID]*sampling.TraceData),
+		batcher:      newIDBatcher(numBatches, cfg.ExpectedNewTracesPerSec),
+		arrivals:     make([]pdata.TraceID, cfg.NumTraces),
+		ctx:          ctx,
+		cancel:       cancel,
+		done:         make(chan struct{}),
+	}, nil
+}
+
+func (tsp *tailSamplingProcessor) GetCapabilities() component.ProcessorCapabilities {
+	return component.ProcessorCapabilities{MutatesConsumedData: false}
+}
+
+// Start is invoked during service startup.
+func (tsp *tailSamplingProcessor) Start(context.Context, component.Host) error {
+	go tsp.startDecisionCycle()
+	return nil
+}
+
+// Shutdown is invoked during service shutdown. The traces whose decision is
+// not made yet are dropped.
+func (tsp *tailSamplingProcessor) Shutdown(ctx context.Context) error {
+	tsp.cancel()
+	select {
+	case <-tsp.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (tsp *tailSamplingProcessor) startDecisionCycle() {
+	defer close(tsp.done)
+	ticker := time.NewTicker(tsp.tickInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-tsp.ctx.Done():
+			return
+		case <-ticker.C:
+			tsp.makeDecisions()
+		}
+	}
+}
+
+// ConsumeTraces stores the spans of the traces whose decision is not made
+// yet and forwards the spans of the sampled traces.
+func (tsp *tailSamplingProcessor) ConsumeTraces(ctx context.Context, td pdata.Traces) error {
+	var errs []error
+	for id, batch := range batchpertrace.Split(td) {
+		if err := tsp.processTrace(ctx, id, batch); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return consumererror.CombineErrors(errs)
+}
+
+func (tsp *tailSamplingProcessor) processTrace(ctx context.Context, id pdata.TraceID, batch pdata.Traces) error {
+	spanCount := int64(batch.SpanCount())
+	tsp.mu.Lock()
+	trace, ok := tsp.traces[id]
+	if !ok {
+		trace = &sampling.TraceData{
+			ArrivalTime: time.Now(),
+		}
+		tsp.addTrace(id, trace)
+	}
+	tsp.mu.Unlock()
+
+	trace.Lock()
+	decision := trace.FinalDecision
+	if decision == sampling.Pending {
+		trace.ReceivedBatches = append(trace.ReceivedBatches, batch)
+		trace.SpanCount += spanCount
+	}
+	trace.Unlock()
+
+	if decision == sampling.Pending {
+		return nil
+	}
+	tsp.record(tagsDecision(decision), statLateSpans.M(spanCount))
+	if decision == sampling.Sampled {
+		return tsp.nextConsumer.ConsumeTraces(ctx, batch)
+	}
+	return nil
+}
+
+// addTrace adds a new trace, dropping the oldest one if the maximum number of
+// traces is reached. It is called with tsp.mu held.
+func (tsp *tailSamplingProcessor) addTrace(id pdata.TraceID, trace *sampling.TraceData) {
+	if len(tsp.traces) == len(tsp.arrivals) {
+		oldestID := tsp.arrivals[tsp.nextArrival]
+		oldest := tsp.traces[oldestID]
+		delete(tsp.traces, oldestID)
+		oldest.Lock()
+		if oldest.FinalDecision == sampling.Pending {
+			tsp.record(nil, statTracesDropped.M(1))
+		}
+		oldest.ReceivedBatches = nil
+		oldest.Unlock()
+	}
+	tsp.traces[id] = trace
+	tsp.arrivals[tsp.nextArrival] = id
+	tsp.nextArrival = (tsp.nextArrival + 1) % len(tsp.arrivals)
+	tsp.batcher.add(id)
+}
+
+// makeDecisions makes the decisions of the traces that waited for the
+// decision wait and forwards the sampled ones.
+func (tsp *tailSamplingProcessor) makeDecisions() {
+	tsp.mu.Lock()
+	ids := tsp.batcher.closeCurrentAndTakeNext()
+	numTraces := len(tsp.traces)
+	tsp.mu.Unlock()
+	tsp.record(nil, statTracesOnMemory.M(int64(numTraces)), statDecisionBatchLen.M(int64(len(ids))))
+
+	for _, id := range ids {
+		tsp.mu.Lock()
+		trace, ok := tsp.traces[id]
+		tsp.mu.Unlock()
+		if !ok {
+			// The trace was dropped before its decision.
+			continue
+		}
+
+		trace.Lock()
+		decision := tsp.evaluate(id, trace)
+		trace.FinalDecision = decision
+		batches := trace.ReceivedBatches
+		trace.ReceivedBatches = nil
+		trace.Unlock()
+
+		tsp.record(tagsDecision(decision), statTraceDecisions.M(1))
+		if decision != sampling.Sampled {
+			continue
+		}
+		for _, batch := range batches {
+			if err := tsp.nextConsumer.ConsumeTraces(tsp.ctx, batch); err != nil {
+				tsp.logger.Warn("Failed to forward sampled trace", zap.String("trace_id", id.HexString()), zap.Error(err))
+			}
+		}
+	}
+}
+
+// evaluate evaluates the policies in order until one of them samples the
+// trace. The policies failing are considered as not sampling the trace.
+func (tsp *tailSamplingProcessor) evaluate(id pdata.TraceID, trace *sampling.TraceData) sampling.Decision {
+	for _, p := range tsp.policies {
+		decision, err := p.evaluator.Evaluate(id, trace)
+		if err != nil {
+			tsp.record([]tag.Mutator{tag.Insert(tagPolicyKey, p.name)}, statPolicyErrors.M(1))
+			tsp.logger.Debug("Sampling policy evaluation failed", zap.String("policy", p.name), zap.Error(err))
+			continue
+		}
+		tsp.record(append(tagsDecision(decision), tag.Insert(tagPolicyKey, p.name)), statPolicyDecisions.M(1))
+		if decision == sampling.Sampled {
+			return sampling.Sampled
+		}
+	}
+	return sampling.NotSampled
+}
+
+func (tsp *tailSamplingProcessor) record(mutators []tag.Mutator, ms ...stats.Measurement) {
+	mutators = append(mutators, tag.Insert(processor.TagProcessorNameKey, tsp.name))
+	_ = stats.RecordWithTags(context.Background(), mutators, ms...)
+}
+
+func tagsDecision(decision sampling.Decision) []tag.Mutator {
+	return []tag.Mutator{tag.Insert(tagDecisionKey, decision.String())}
+}
@@ -0,0 +1,181 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tailsamplingprocessor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/processor/tailsamplingprocessor/internal/sampling"
+)
+
+func newTestProcessor(t *testing.T, cfg *Config) (*tailSamplingProcessor, *consumertest.TracesSink) {
+	sink := new(consumertest.TracesSink)
+	cfg.ProcessorSettings = NewFactory().CreateDefaultConfig().(*Config).ProcessorSettings
+	if cfg.NumTraces == 0 {
+		cfg.NumTraces = defaultNumTraces
+	}
+	tsp, err := newTailSamplingProcessor(component.ProcessorCreateParams{Logger: zap.NewNop()}, sink, cfg)
+	require.NoError(t, err)
+	return tsp, sink
+}
+
+func traceID(b byte) pdata.TraceID {
+	return pdata.NewTraceID([16]byte{b})
+}
+
+// newTraces returns a resource and instrumentation library with a span per
+// trace ID, the spans whose trace ID is in errorIDs have the error status.
+func newTraces(ids []byte, errorIDs ...byte) pdata.Traces {
+	td := pdata.NewTraces()
+	td.ResourceSpans().Resize(1)
+	rs := td.ResourceSpans().At(0)
+	rs.Resource().Attributes().InsertString("service.name", "checkout")
+	rs.InstrumentationLibrarySpans().Resize(1)
+	ils := rs.InstrumentationLibrarySpans().At(0)
+	ils.InstrumentationLibrary().SetName("lib")
+	spans := ils.Spans()
+	spans.Resize(len(ids))
+	for i, id := range ids {
+		spans.At(i).SetTraceID(traceID(id))
+		spans.At(i).SetName("span")
+		for _, errorID := range errorIDs {
+			if id == errorID {
+				spans.At(i).Status().SetCode(pdata.StatusCodeError)
+			}
+		}
+	}
+	return td
+}
+
+func errorsPolicyCfg() []PolicyCfg {
+	return []PolicyCfg{{sharedPolicyCfg: sharedPolicyCfg{
+		Name:          "errors",
+		Type:          StatusCode,
+		StatusCodeCfg: StatusCodeCfg{StatusCodes: []string{"Error"}},
+	}}}
+}
+
+func TestTailSamplingProcessor_DecisionAfterDecisionWait(t *testing.T) {
+	tsp, sink := newTestProcessor(t, &Config{
+		DecisionWait: 2 * time.Second,
+		Policies:     errorsPolicyCfg(),
+	})
+
+	require.NoError(t, tsp.ConsumeTraces(context.Background(), newTraces([]byte{1, 2, 1})))
+	require.NoError(t, tsp.ConsumeTraces(context.Background(), newTraces([]byte{2}, 2)))
+
+	tsp.makeDecisions()
+	assert.Equal(t, 0, sink.SpansCount())
+
+	tsp.makeDecisions()
+	require.Equal(t, 2, sink.SpansCount())
+	for _, td := range sink.AllTraces() {
+		span := td.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0)
+		assert.Equal(t, traceID(2), span.TraceID())
+	}
+
+	// The late spans follow the decision of their trace.
+	sink.Reset()
+	require.NoError(t, tsp.ConsumeTraces(context.Background(), newTraces([]byte{1, 2, 2})))
+	assert.Equal(t, 2, sink.SpansCount())
+
+	tsp.makeDecisions()
+	tsp.makeDecisions()
+	assert.Equal(t, 2, sink.SpansCount())
+}
+
+func TestTailSamplingProcessor_OldestTracesDropped(t *testing.T) {
+	tsp, sink := newTestProcessor(t, &Config{
+		NumTraces: 2,
+		Policies:  []PolicyCfg{{sharedPolicyCfg: sharedPolicyCfg{Name: "all", Type: AlwaysSample}}},
+	})
+
+	for _, id := range []byte{1, 2, 3} {
+		require.NoError(t, tsp.ConsumeTraces(context.Background(), newTraces([]byte{id})))
+	}
+	assert.Len(t, tsp.traces, 2)
+	assert.NotContains(t, tsp.traces, traceID(1))
+
+	tsp.makeDecisions()
+	assert.Equal(t, 2, sink.SpansCount())
+}
+
+func TestTailSamplingProcessor_PolicyErrors(t *testing.T) {
+	tsp, sink := newTestProcessor(t, &Config{Policies: errorsPolicyCfg()})
+	tsp.policies = []*policy{
+		{name: "failing", evaluator: &failingPolicy{}},
+		{name: "all", evaluator: sampling.NewAlwaysSample()},
+	}
+
+	require.NoError(t, tsp.ConsumeTraces(context.Background(), newTraces([]byte{1})))
+	for i := 0; i < 30; i++ {
+		tsp.makeDecisions()
+	}
+	assert.Equal(t, 1, sink.SpansCount())
+}
+
+func TestTailSamplingProcessor_ForwardError(t *testing.T) {
+	tsp, err := newTailSamplingProcessor(
+		component.ProcessorCreateParams{Logger: zap.NewNop()},
+		consumertest.NewTracesErr(errors.New("forward failed")),
+		&Config{
+			NumTraces: 10,
+			Policies:  []PolicyCfg{{sharedPolicyCfg: sharedPolicyCfg{Name: "all", Type: AlwaysSample}}},
+		})
+	require.NoError(t, err)
+
+	require.NoError(t, tsp.ConsumeTraces(context.Background(), newTraces([]byte{1})))
+	tsp.makeDecisions()
+	assert.EqualError(t, tsp.ConsumeTraces(context.Background(), newTraces([]byte{1})), "forward failed")
+}
+
+func TestTailSamplingProcessor_StartShutdown(t *testing.T) {
+	tsp, sink := newTestProcessor(t, &Config{
+		Policies: []PolicyCfg{{sharedPolicyCfg: sharedPolicyCfg{Name: "all", Type: AlwaysSample}}},
+	})
+	tsp.tickInterval = 10 * time.Millisecond
+
+	require.NoError(t, tsp.Start(context.Background(), componenttest.NewNopHost()))
+	require.NoError(t, tsp.ConsumeTraces(context.Background(), newTraces([]byte{1, 2})))
+	assert.Eventually(t, func() bool {
+		return sink.SpansCount() == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.NoError(t, tsp.Shutdown(context.Background()))
+}
+
+func TestNewTailSamplingProcessor_NoTraces(t *testing.T) {
+	_, err := newTailSamplingProcessor(
+		component.ProcessorCreateParams{Logger: zap.NewNop()},
+		consumertest.NewTracesNop(),
+		&Config{Policies: []PolicyCfg{{sharedPolicyCfg: sharedPolicyCfg{Name: "all", Type: AlwaysSample}}}})
+	assert.Equal(t, errNoTraces, err)
+}
+
+type failingPolicy struct{}
+
+func (*failingPolicy) Evaluate(pdata.TraceID, *sampling.TraceData) (sampling.Decision, error) {
+	return sampling.Pending, errors.New("failed")
+}
@@ -0,0 +1,46 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package temporalityprocessor
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/config/configmodels"
+)
+
+// Temporality is the aggregation temporality the sums and histograms are
+// converted to.
+type Temporality string
+
+const (
+	// Delta converts the cumulative sums and histograms to delta ones.
+	Delta Temporality = "delta"
+	// Cumulative converts the delta sums and histograms to cumulative ones.
+	Cumulative Temporality = "cumulative"
+)
+
+// Config has the configuration of the temporality processor.
+type Config struct {
+	configmodels.ProcessorSettings `mapstructure:",squash"`
+	// Target is the aggregation temporality the sums and histograms are
+	// converted to, delta or cumulative.
+	Target Temporality `mapstructure:"target"`
+	// Metrics is the list of the names of the metrics converted. All the
+	// metrics are converted if it is empty.
+	Metrics []string `mapstructure:"metrics"`
+	// MaxStaleness is the duration after which the state of a series that
+	// received no data point is dropped.
+	MaxStaleness time.Duration `mapstructure:"max_staleness"`
+}
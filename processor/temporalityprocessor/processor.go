@@ -0,0 +1,408 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package temporalityprocessor
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+)
+
+// seriesState is the last cumulative value seen, or produced, for a series.
+type seriesState struct {
+	// lastSeen is the wall time of the last data point of the series, used
+	// to drop the state of the series that stopped reporting.
+	lastSeen time.Time
+
+	startTime pdata.Timestamp
+	timestamp pdata.Timestamp
+
+	intValue     int64
+	doubleValue  float64
+	count        uint64
+	bucketCounts []uint64
+	bounds       []float64
+}
+
+type temporalityProcessor struct {
+	target       pdata.AggregationTemporality
+	metrics      map[string]bool
+	maxStaleness time.Duration
+
+	// now is the wall clock, it is replaced in the tests.
+	now func() time.Time
+
+	mu        sync.Mutex
+	series    map[string]*seriesState
+	lastSweep time.Time
+}
+
+func newTemporalityProcessor(cfg *Config) *temporalityProcessor {
+	tp := &temporalityProcessor{
+		target:       pdata.AggregationTemporalityCumulative,
+		maxStaleness: cfg.MaxStaleness,
+		now:          time.Now,
+		series:       make(map[string]*seriesState),
+	}
+	if cfg.Target == Delta {
+		tp.target = pdata.AggregationTemporalityDelta
+	}
+	if len(cfg.Metrics) > 0 {
+		tp.metrics = make(map[string]bool, len(cfg.Metrics))
+		for _, name := range cfg.Metrics {
+			tp.metrics[name] = true
+		}
+	}
+	return tp
+}
+
+// ProcessMetrics converts the sums and histograms of the configured metrics
+// to the target temporality. The cumulative data points that are not newer
+// than the previous data point of their series are dropped.
+func (tp *temporalityProcessor) ProcessMetrics(_ context.Context, md pdata.Metrics) (pdata.Metrics, error) {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+
+	now := tp.now()
+	tp.sweep(now)
+
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		resourceKey := attributesKey(rm.Resource().Attributes())
+		ilms := rm.InstrumentationLibraryMetrics()
+		for j := 0; j < ilms.Len(); j++ {
+			ilm := ilms.At(j)
+			il := ilm.InstrumentationLibrary()
+			libraryKey := resourceKey + il.Name() + "\x00" + il.Version() + "\x00"
+			metrics := ilm.Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				metric := metrics.At(k)
+				if tp.metrics != nil && !tp.metrics[metric.Name()] {
+					continue
+				}
+				tp.convert(libraryKey+metric.Name()+"\x00", metric, now)
+			}
+		}
+	}
+	return md, nil
+}
+
+// sweep drops the state of the series that received no data point for
+// longer than maxStaleness. The series are only scanned once per
+// maxStaleness.
+func (tp *temporalityProcessor) sweep(now time.Time) {
+	if now.Sub(tp.lastSweep) < tp.maxStaleness {
+		return
+	}
+	tp.lastSweep = now
+	for key, st := range tp.series {
+		if now.Sub(st.lastSeen) > tp.maxStaleness {
+			delete(tp.series, key)
+		}
+	}
+}
+
+func (tp *temporalityProcessor) convert(prefix string, metric pdata.Metric, now time.Time) {
+	switch metric.DataType() {
+	case pdata.MetricDataTypeIntSum:
+		sum := metric.IntSum()
+		if !tp.needsConversion(sum.AggregationTemporality()) {
+			return
+		}
+		tp.convertIntPoints(prefix+"int\x00", sum.DataPoints(), sum.IsMonotonic(), now)
+		sum.SetAggregationTemporality(tp.target)
+	case pdata.MetricDataTypeDoubleSum:
+		sum := metric.DoubleSum()
+		if !tp.needsConversion(sum.AggregationTemporality()) {
+			return
+		}
+		tp.convertDoublePoints(prefix+"double\x00", sum.DataPoints(), sum.IsMonotonic(), now)
+		sum.SetAggregationTemporality(tp.target)
+	case pdata.MetricDataTypeIntHistogram:
+		histogram := metric.IntHistogram()
+		if !tp.needsConversion(histogram.AggregationTemporality()) {
+			return
+		}
+		tp.convertIntHistogramPoints(prefix+"inthistogram\x00", histogram.DataPoints(), now)
+		histogram.SetAggregationTemporality(tp.target)
+	case pdata.MetricDataTypeDoubleHistogram:
+		histogram := metric.DoubleHistogram()
+		if !tp.needsConversion(histogram.AggregationTemporality()) {
+			return
+		}
+		tp.convertDoubleHistogramPoints(prefix+"doublehistogram\x00", histogram.DataPoints(), now)
+		histogram.SetAggregationTemporality(tp.target)
+	}
+}
+
+// needsConversion returns whether the data of the given temporality must be
+// converted. The data of unspecified temporality is left as is since its
+// meaning is unknown.
+func (tp *temporalityProcessor) needsConversion(temporality pdata.AggregationTemporality) bool {
+	return temporality != pdata.AggregationTemporalityUnspecified && temporality != tp.target
+}
+
+// lookup returns the state of the series identified by key, and whether the
+// data point at ts must be dropped because it is not newer than the last data
+// point of the series. A nil state means that the series is new.
+func (tp *temporalityProcessor) lookup(key string, ts pdata.Timestamp, now time.Time) (*seriesState, bool) {
+	st, ok := tp.series[key]
+	if !ok {
+		return nil, false
+	}
+	if ts <= st.timestamp {
+		return st, true
+	}
+	st.lastSeen = now
+	return st, false
+}
+
+func (tp *temporalityProcessor) store(key string, st *seriesState, now time.Time) {
+	st.lastSeen = now
+	tp.series[key] = st
+}
+
+func (tp *temporalityProcessor) convertIntPoints(prefix string, dps pdata.IntDataPointSlice, monotonic bool, now time.Time) {
+	kept := 0
+	for i := 0; i < dps.Len(); i++ {
+		dp := dps.At(i)
+		key := prefix + labelsKey(dp.LabelsMap())
+		st, drop := tp.lookup(key, dp.Timestamp(), now)
+		if drop {
+			continue
+		}
+		start, value := dp.StartTime(), dp.Value()
+		switch {
+		case st == nil:
+			// The first data point is both the cumulative and the delta
+			// value since its start time.
+			tp.store(key, &seriesState{startTime: start, timestamp: dp.Timestamp(), intValue: value}, now)
+		case tp.target == pdata.AggregationTemporalityDelta:
+			reset := start != st.startTime || (monotonic && value < st.intValue)
+			if !reset {
+				dp.SetValue(value - st.intValue)
+			}
+			if !reset || start == st.startTime {
+				dp.SetStartTime(st.timestamp)
+			}
+			st.startTime, st.timestamp, st.intValue = start, dp.Timestamp(), value
+		default:
+			st.timestamp = dp.Timestamp()
+			st.intValue += value
+			dp.SetStartTime(st.startTime)
+			dp.SetValue(st.intValue)
+		}
+		if kept != i {
+			dp.CopyTo(dps.At(kept))
+		}
+		kept++
+	}
+	dps.Resize(kept)
+}
+
+func (tp *temporalityProcessor) convertDoublePoints(prefix string, dps pdata.DoubleDataPointSlice, monotonic bool, now time.Time) {
+	kept := 0
+	for i := 0; i < dps.Len(); i++ {
+		dp := dps.At(i)
+		key := prefix + labelsKey(dp.LabelsMap())
+		st, drop := tp.lookup(key, dp.Timestamp(), now)
+		if drop {
+			continue
+		}
+		start, value := dp.StartTime(), dp.Value()
+		switch {
+		case st == nil:
+			tp.store(key, &seriesState{startTime: start, timestamp: dp.Timestamp(), doubleValue: value}, now)
+		case tp.target == pdata.AggregationTemporalityDelta:
+			reset := start != st.startTime || (monotonic && value < st.doubleValue)
+			if !reset {
+				dp.SetValue(value - st.doubleValue)
+			}
+			if !reset || start == st.startTime {
+				dp.SetStartTime(st.timestamp)
+			}
+			st.startTime, st.timestamp, st.doubleValue = start, dp.Timestamp(), value
+		default:
+			st.timestamp = dp.Timestamp()
+			st.doubleValue += value
+			dp.SetStartTime(st.startTime)
+			dp.SetValue(st.doubleValue)
+		}
+		if kept != i {
+			dp.CopyTo(dps.At(kept))
+		}
+		kept++
+	}
+	dps.Resize(kept)
+}
+
+func (tp *temporalityProcessor) convertIntHistogramPoints(prefix string, dps pdata.IntHistogramDataPointSlice, now time.Time) {
+	kept := 0
+	for i := 0; i < dps.Len(); i++ {
+		dp := dps.At(i)
+		key := prefix + labelsKey(dp.LabelsMap())
+		st, drop := tp.lookup(key, dp.Timestamp(), now)
+		if drop {
+			continue
+		}
+		start, sum, count, buckets := dp.StartTime(), dp.Sum(), dp.Count(), dp.BucketCounts()
+		switch {
+		case st == nil || !sameBuckets(st, dp.ExplicitBounds(), buckets):
+			// A change of the buckets restarts the series.
+			tp.store(key, &seriesState{
+				startTime:    start,
+				timestamp:    dp.Timestamp(),
+				intValue:     sum,
+				count:        count,
+				bucketCounts: copyCounts(buckets),
+				bounds:       dp.ExplicitBounds(),
+			}, now)
+		case tp.target == pdata.AggregationTemporalityDelta:
+			reset := start != st.startTime || count < st.count
+			if !reset {
+				dp.SetSum(sum - st.intValue)
+				dp.SetCount(count - st.count)
+				dp.SetBucketCounts(subtractCounts(buckets, st.bucketCounts))
+			}
+			if !reset || start == st.startTime {
+				dp.SetStartTime(st.timestamp)
+			}
+			st.startTime, st.timestamp, st.intValue, st.count = start, dp.Timestamp(), sum, count
+			st.bucketCounts = copyCounts(buckets)
+		default:
+			st.timestamp = dp.Timestamp()
+			st.intValue += sum
+			st.count += count
+			addCounts(st.bucketCounts, buckets)
+			dp.SetStartTime(st.startTime)
+			dp.SetSum(st.intValue)
+			dp.SetCount(st.count)
+			dp.SetBucketCounts(copyCounts(st.bucketCounts))
+		}
+		if kept != i {
+			dp.CopyTo(dps.At(kept))
+		}
+		kept++
+	}
+	dps.Resize(kept)
+}
+
+func (tp *temporalityProcessor) convertDoubleHistogramPoints(prefix string, dps pdata.DoubleHistogramDataPointSlice, now time.Time) {
+	kept := 0
+	for i := 0; i < dps.Len(); i++ {
+		dp := dps.At(i)
+		key := prefix + labelsKey(dp.LabelsMap())
+		st, drop := tp.lookup(key, dp.Timestamp(), now)
+		if drop {
+			continue
+		}
+		start, sum, count, buckets := dp.StartTime(), dp.Sum(), dp.Count(), dp.BucketCounts()
+		switch {
+		case st == nil || !sameBuckets(st, dp.ExplicitBounds(), buckets):
+			tp.store(key, &seriesState{
+				startTime:    start,
+				timestamp:    dp.Timestamp(),
+				doubleValue:  sum,
+				count:        count,
+				bucketCounts: copyCounts(buckets),
+				bounds:       dp.ExplicitBounds(),
+			}, now)
+		case tp.target == pdata.AggregationTemporalityDelta:
+			reset := start != st.startTime || count < st.count
+			if !reset {
+				dp.SetSum(sum - st.doubleValue)
+				dp.SetCount(count - st.count)
+				dp.SetBucketCounts(subtractCounts(buckets, st.bucketCounts))
+			}
+			if !reset || start == st.startTime {
+				dp.SetStartTime(st.timestamp)
+			}
+			st.startTime, st.timestamp, st.doubleValue, st.count = start, dp.Timestamp(), sum, count
+			st.bucketCounts = copyCounts(buckets)
+		default:
+			st.timestamp = dp.Timestamp()
+			st.doubleValue += sum
+			st.count += count
+			addCounts(st.bucketCounts, buckets)
+			dp.SetStartTime(st.startTime)
+			dp.SetSum(st.doubleValue)
+			dp.SetCount(st.count)
+			dp.SetBucketCounts(copyCounts(st.bucketCounts))
+		}
+		if kept != i {
+			dp.CopyTo(dps.At(kept))
+		}
+		kept++
+	}
+	dps.Resize(kept)
+}
+
+func sameBuckets(st *seriesState, bounds []float64, bucketCounts []uint64) bool {
+	if len(st.bounds) != len(bounds) || len(st.bucketCounts) != len(bucketCounts) {
+		return false
+	}
+	for i := range bounds {
+		if st.bounds[i] != bounds[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func copyCounts(counts []uint64) []uint64 {
+	return append([]uint64(nil), counts...)
+}
+
+func addCounts(dest []uint64, counts []uint64) {
+	for i := range counts {
+		dest[i] += counts[i]
+	}
+}
+
+// subtractCounts returns the difference of the bucket counts. A bucket that
+// decreased without a reset of the series is reported as empty.
+func subtractCounts(counts []uint64, prev []uint64) []uint64 {
+	delta := make([]uint64, len(counts))
+	for i := range counts {
+		if counts[i] > prev[i] {
+			delta[i] = counts[i] - prev[i]
+		}
+	}
+	return delta
+}
+
+func labelsKey(labels pdata.StringMap) string {
+	kvs := make([]string, 0, labels.Len())
+	labels.ForEach(func(k string, v string) {
+		kvs = append(kvs, k+"="+v)
+	})
+	sort.Strings(kvs)
+	return strings.Join(kvs, "\x00")
+}
+
+func attributesKey(attrs pdata.AttributeMap) string {
+	kvs := make([]string, 0, attrs.Len())
+	attrs.ForEach(func(k string, v pdata.AttributeValue) {
+		kvs = append(kvs, k+"="+tracetranslator.AttributeValueToString(v, false))
+	})
+	sort.Strings(kvs)
+	return strings.Join(kvs, "\x00") + "\x01"
+}
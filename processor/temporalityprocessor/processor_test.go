@@ -0,0 +1,324 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package temporalityprocessor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+type point struct {
+	labels    map[string]string
+	start     pdata.Timestamp
+	ts        pdata.Timestamp
+	value     float64
+	count     uint64
+	bounds    []float64
+	buckets   []uint64
+	resourceA string
+}
+
+// newMetrics returns a metric of the given type and temporality, named name,
+// with one resource per distinct point.resourceA.
+func newMetrics(name string, dataType pdata.MetricDataType, temporality pdata.AggregationTemporality, points ...point) pdata.Metrics {
+	md := pdata.NewMetrics()
+	resources := map[string]pdata.Metric{}
+	for _, p := range points {
+		metric, ok := resources[p.resourceA]
+		if !ok {
+			rms := md.ResourceMetrics()
+			rms.Resize(rms.Len() + 1)
+			rm := rms.At(rms.Len() - 1)
+			rm.Resource().Attributes().InsertString("a", p.resourceA)
+			rm.InstrumentationLibraryMetrics().Resize(1)
+			metrics := rm.InstrumentationLibraryMetrics().At(0).Metrics()
+			metrics.Resize(1)
+			metric = metrics.At(0)
+			metric.SetName(name)
+			metric.SetDataType(dataType)
+			resources[p.resourceA] = metric
+		}
+		switch dataType {
+		case pdata.MetricDataTypeIntSum:
+			metric.IntSum().SetAggregationTemporality(temporality)
+			metric.IntSum().SetIsMonotonic(true)
+			dps := metric.IntSum().DataPoints()
+			dps.Resize(dps.Len() + 1)
+			dp := dps.At(dps.Len() - 1)
+			dp.LabelsMap().InitFromMap(p.labels)
+			dp.SetStartTime(p.start)
+			dp.SetTimestamp(p.ts)
+			dp.SetValue(int64(p.value))
+		case pdata.MetricDataTypeDoubleSum:
+			metric.DoubleSum().SetAggregationTemporality(temporality)
+			dps := metric.DoubleSum().DataPoints()
+			dps.Resize(dps.Len() + 1)
+			dp := dps.At(dps.Len() - 1)
+			dp.LabelsMap().InitFromMap(p.labels)
+			dp.SetStartTime(p.start)
+			dp.SetTimestamp(p.ts)
+			dp.SetValue(p.value)
+		case pdata.MetricDataTypeIntHistogram:
+			metric.IntHistogram().SetAggregationTemporality(temporality)
+			dps := metric.IntHistogram().DataPoints()
+			dps.Resize(dps.Len() + 1)
+			dp := dps.At(dps.Len() - 1)
+			dp.LabelsMap().InitFromMap(p.labels)
+			dp.SetStartTime(p.start)
+			dp.SetTimestamp(p.ts)
+			dp.SetSum(int64(p.value))
+			dp.SetCount(p.count)
+			dp.SetExplicitBounds(p.bounds)
+			dp.SetBucketCounts(p.buckets)
+		case pdata.MetricDataTypeDoubleHistogram:
+			metric.DoubleHistogram().SetAggregationTemporality(temporality)
+			dps := metric.DoubleHistogram().DataPoints()
+			dps.Resize(dps.Len() + 1)
+			dp := dps.At(dps.Len() - 1)
+			dp.LabelsMap().InitFromMap(p.labels)
+			dp.SetStartTime(p.start)
+			dp.SetTimestamp(p.ts)
+			dp.SetSum(p.value)
+			dp.SetCount(p.count)
+			dp.SetExplicitBounds(p.bounds)
+			dp.SetBucketCounts(p.buckets)
+		case pdata.MetricDataTypeIntGauge:
+			dps := metric.IntGauge().DataPoints()
+			dps.Resize(dps.Len() + 1)
+			dp := dps.At(dps.Len() - 1)
+			dp.LabelsMap().InitFromMap(p.labels)
+			dp.SetStartTime(p.start)
+			dp.SetTimestamp(p.ts)
+			dp.SetValue(int64(p.value))
+		}
+	}
+	return md
+}
+
+func newProcessor(t *testing.T, cfg *Config) *temporalityProcessor {
+	if cfg.MaxStaleness == 0 {
+		cfg.MaxStaleness = defaultMaxStaleness
+	}
+	tp := newTemporalityProcessor(cfg)
+	require.NotNil(t, tp)
+	return tp
+}
+
+func process(t *testing.T, tp *temporalityProcessor, md pdata.Metrics) pdata.Metrics {
+	out, err := tp.ProcessMetrics(context.Background(), md)
+	require.NoError(t, err)
+	return out
+}
+
+func TestCumulativeToDeltaSum(t *testing.T) {
+	tp := newProcessor(t, &Config{Target: Delta})
+	batches := []struct {
+		name     string
+		in       point
+		expected []point
+	}{
+		{
+			name:     "first",
+			in:       point{start: 1, ts: 2, value: 10},
+			expected: []point{{start: 1, ts: 2, value: 10}},
+		},
+		{
+			name:     "increase",
+			in:       point{start: 1, ts: 3, value: 15},
+			expected: []point{{start: 2, ts: 3, value: 5}},
+		},
+		{
+			name:     "decrease",
+			in:       point{start: 1, ts: 4, value: 12},
+			expected: []point{{start: 3, ts: 4, value: 12}},
+		},
+		{
+			name:     "restart",
+			in:       point{start: 5, ts: 6, value: 20},
+			expected: []point{{start: 5, ts: 6, value: 20}},
+		},
+		{
+			name: "out of order",
+			in:   point{start: 5, ts: 6, value: 25},
+		},
+		{
+			name:     "after restart",
+			in:       point{start: 5, ts: 7, value: 26},
+			expected: []point{{start: 6, ts: 7, value: 6}},
+		},
+	}
+	for _, b := range batches {
+		t.Run(b.name, func(t *testing.T) {
+			out := process(t, tp, newMetrics("m", pdata.MetricDataTypeIntSum, pdata.AggregationTemporalityCumulative, b.in))
+			metric := out.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().At(0)
+			assert.Equal(t, pdata.AggregationTemporalityDelta, metric.IntSum().AggregationTemporality())
+			expected := newMetrics("m", pdata.MetricDataTypeIntSum, pdata.AggregationTemporalityDelta, b.expected...)
+			if len(b.expected) > 0 {
+				assert.Equal(t, expected.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().At(0), metric)
+			} else {
+				assert.Equal(t, 0, metric.IntSum().DataPoints().Len())
+			}
+		})
+	}
+}
+
+func TestDeltaToCumulativeSum(t *testing.T) {
+	tp := newProcessor(t, &Config{Target: Cumulative})
+	in := newMetrics("m", pdata.MetricDataTypeDoubleSum, pdata.AggregationTemporalityDelta,
+		point{start: 1, ts: 2, value: 1.5},
+		point{start: 2, ts: 3, value: 2},
+		point{start: 1, ts: 2, value: 3},
+		point{start: 3, ts: 4, value: 0.5},
+	)
+	expected := newMetrics("m", pdata.MetricDataTypeDoubleSum, pdata.AggregationTemporalityCumulative,
+		point{start: 1, ts: 2, value: 1.5},
+		point{start: 1, ts: 3, value: 3.5},
+		point{start: 1, ts: 4, value: 4},
+	)
+	assert.Equal(t, expected, process(t, tp, in))
+}
+
+func TestSeriesIdentity(t *testing.T) {
+	tp := newProcessor(t, &Config{Target: Cumulative})
+	in := newMetrics("m", pdata.MetricDataTypeDoubleSum, pdata.AggregationTemporalityDelta,
+		point{labels: map[string]string{"l": "1"}, start: 1, ts: 2, value: 1},
+		point{labels: map[string]string{"l": "2"}, start: 1, ts: 2, value: 2},
+		point{resourceA: "other", labels: map[string]string{"l": "1"}, start: 1, ts: 2, value: 3},
+	)
+	process(t, tp, in)
+
+	in = newMetrics("m", pdata.MetricDataTypeDoubleSum, pdata.AggregationTemporalityDelta,
+		point{labels: map[string]string{"l": "1"}, start: 2, ts: 3, value: 1},
+		point{labels: map[string]string{"l": "2"}, start: 2, ts: 3, value: 1},
+		point{resourceA: "other", labels: map[string]string{"l": "1"}, start: 2, ts: 3, value: 1},
+	)
+	expected := newMetrics("m", pdata.MetricDataTypeDoubleSum, pdata.AggregationTemporalityCumulative,
+		point{labels: map[string]string{"l": "1"}, start: 1, ts: 3, value: 2},
+		point{labels: map[string]string{"l": "2"}, start: 1, ts: 3, value: 3},
+		point{resourceA: "other", labels: map[string]string{"l": "1"}, start: 1, ts: 3, value: 4},
+	)
+	assert.Equal(t, expected, process(t, tp, in))
+
+	// The same series of another metric is distinct.
+	in = newMetrics("n", pdata.MetricDataTypeDoubleSum, pdata.AggregationTemporalityDelta,
+		point{labels: map[string]string{"l": "1"}, start: 2, ts: 3, value: 1},
+	)
+	expected = newMetrics("n", pdata.MetricDataTypeDoubleSum, pdata.AggregationTemporalityCumulative,
+		point{labels: map[string]string{"l": "1"}, start: 2, ts: 3, value: 1},
+	)
+	assert.Equal(t, expected, process(t, tp, in))
+}
+
+func TestCumulativeToDeltaHistogram(t *testing.T) {
+	tp := newProcessor(t, &Config{Target: Delta})
+	bounds := []float64{1, 2}
+	in := newMetrics("m", pdata.MetricDataTypeIntHistogram, pdata.AggregationTemporalityCumulative,
+		point{start: 1, ts: 2, value: 10, count: 4, bounds: bounds, buckets: []uint64{1, 2, 1}},
+		point{start: 1, ts: 3, value: 16, count: 7, bounds: bounds, buckets: []uint64{2, 3, 2}},
+		point{start: 1, ts: 4, value: 3, count: 2, bounds: bounds, buckets: []uint64{1, 1, 0}},
+		point{start: 1, ts: 5, value: 5, count: 3, bounds: []float64{1}, buckets: []uint64{1, 2}},
+	)
+	expected := newMetrics("m", pdata.MetricDataTypeIntHistogram, pdata.AggregationTemporalityDelta,
+		point{start: 1, ts: 2, value: 10, count: 4, bounds: bounds, buckets: []uint64{1, 2, 1}},
+		point{start: 2, ts: 3, value: 6, count: 3, bounds: bounds, buckets: []uint64{1, 1, 1}},
+		point{start: 3, ts: 4, value: 3, count: 2, bounds: bounds, buckets: []uint64{1, 1, 0}},
+		point{start: 1, ts: 5, value: 5, count: 3, bounds: []float64{1}, buckets: []uint64{1, 2}},
+	)
+	assert.Equal(t, expected, process(t, tp, in))
+}
+
+func TestDeltaToCumulativeHistogram(t *testing.T) {
+	tp := newProcessor(t, &Config{Target: Cumulative})
+	bounds := []float64{1, 2}
+	in := newMetrics("m", pdata.MetricDataTypeDoubleHistogram, pdata.AggregationTemporalityDelta,
+		point{start: 1, ts: 2, value: 1.5, count: 2, bounds: bounds, buckets: []uint64{1, 1, 0}},
+		point{start: 2, ts: 3, value: 2.5, count: 2, bounds: bounds, buckets: []uint64{0, 1, 1}},
+		point{start: 2, ts: 3, value: 2.5, count: 2, bounds: bounds, buckets: []uint64{0, 1, 1}},
+		point{start: 3, ts: 4, value: 1, count: 1, bounds: []float64{1}, buckets: []uint64{1, 0}},
+	)
+	expected := newMetrics("m", pdata.MetricDataTypeDoubleHistogram, pdata.AggregationTemporalityCumulative,
+		point{start: 1, ts: 2, value: 1.5, count: 2, bounds: bounds, buckets: []uint64{1, 1, 0}},
+		point{start: 1, ts: 3, value: 4, count: 4, bounds: bounds, buckets: []uint64{1, 2, 1}},
+		point{start: 3, ts: 4, value: 1, count: 1, bounds: []float64{1}, buckets: []uint64{1, 0}},
+	)
+	assert.Equal(t, expected, process(t, tp, in))
+}
+
+func TestUnconvertedMetrics(t *testing.T) {
+	tp := newProcessor(t, &Config{Target: Cumulative, Metrics: []string{"m"}})
+	tests := []struct {
+		name string
+		md   pdata.Metrics
+	}{
+		{
+			name: "not configured",
+			md:   newMetrics("n", pdata.MetricDataTypeIntSum, pdata.AggregationTemporalityDelta, point{start: 1, ts: 2, value: 1}, point{start: 2, ts: 3, value: 1}),
+		},
+		{
+			name: "target temporality",
+			md:   newMetrics("m", pdata.MetricDataTypeIntSum, pdata.AggregationTemporalityCumulative, point{start: 1, ts: 2, value: 1}, point{start: 1, ts: 2, value: 1}),
+		},
+		{
+			name: "unspecified temporality",
+			md:   newMetrics("m", pdata.MetricDataTypeIntSum, pdata.AggregationTemporalityUnspecified, point{start: 1, ts: 2, value: 1}, point{start: 2, ts: 3, value: 1}),
+		},
+		{
+			name: "gauge",
+			md:   newMetrics("m", pdata.MetricDataTypeIntGauge, pdata.AggregationTemporalityUnspecified, point{start: 1, ts: 2, value: 1}, point{start: 1, ts: 2, value: 1}),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expected := tt.md.Clone()
+			assert.Equal(t, expected, process(t, tp, tt.md))
+		})
+	}
+	assert.Empty(t, tp.series)
+}
+
+func TestStaleSeries(t *testing.T) {
+	now := time.Unix(0, 0)
+	tp := newProcessor(t, &Config{Target: Cumulative, MaxStaleness: time.Minute})
+	tp.now = func() time.Time { return now }
+
+	process(t, tp, newMetrics("m", pdata.MetricDataTypeIntSum, pdata.AggregationTemporalityDelta,
+		point{labels: map[string]string{"l": "stale"}, start: 1, ts: 2, value: 1},
+		point{labels: map[string]string{"l": "active"}, start: 1, ts: 2, value: 1},
+	))
+	assert.Len(t, tp.series, 2)
+
+	now = now.Add(40 * time.Second)
+	process(t, tp, newMetrics("m", pdata.MetricDataTypeIntSum, pdata.AggregationTemporalityDelta,
+		point{labels: map[string]string{"l": "active"}, start: 2, ts: 3, value: 1},
+	))
+	assert.Len(t, tp.series, 2)
+
+	now = now.Add(40 * time.Second)
+	out := process(t, tp, newMetrics("m", pdata.MetricDataTypeIntSum, pdata.AggregationTemporalityDelta,
+		point{labels: map[string]string{"l": "stale"}, start: 3, ts: 4, value: 1},
+		point{labels: map[string]string{"l": "active"}, start: 3, ts: 4, value: 1},
+	))
+	expected := newMetrics("m", pdata.MetricDataTypeIntSum, pdata.AggregationTemporalityCumulative,
+		point{labels: map[string]string{"l": "stale"}, start: 3, ts: 4, value: 1},
+		point{labels: map[string]string{"l": "active"}, start: 1, ts: 4, value: 3},
+	)
+	assert.Equal(t, expected, out)
+}
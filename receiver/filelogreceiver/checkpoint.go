@@ -0,0 +1,102 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filelogreceiver
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"go.opentelemetry.io/collector/extension/storage"
+)
+
+// checkpoint is the persisted offset of a file.
+type checkpoint struct {
+	Fingerprint []byte `json:"fingerprint"`
+	Offset      int64  `json:"offset"`
+}
+
+// checkpointsKey is the key of the checkpoints in the storage client.
+const checkpointsKey = "checkpoints"
+
+// loadCheckpoints returns the readers, without file, of the checkpoints
+// persisted to path, none if it does not exist.
+func loadCheckpoints(path string) ([]*reader, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return decodeCheckpoints(data)
+}
+
+// loadStorageCheckpoints returns the readers, without file, of the
+// checkpoints persisted in the storage client, none if they are not set.
+func loadStorageCheckpoints(ctx context.Context, client storage.Client) ([]*reader, error) {
+	data, err := client.Get(ctx, checkpointsKey)
+	if err != nil || data == nil {
+		return nil, err
+	}
+	return decodeCheckpoints(data)
+}
+
+func decodeCheckpoints(data []byte) ([]*reader, error) {
+	var checkpoints []checkpoint
+	if err := json.Unmarshal(data, &checkpoints); err != nil {
+		return nil, err
+	}
+	readers := make([]*reader, 0, len(checkpoints))
+	for _, c := range checkpoints {
+		readers = append(readers, &reader{fingerprint: c.Fingerprint, offset: c.Offset})
+	}
+	return readers, nil
+}
+
+// saveCheckpoints persists the offsets of the readers to path. The file is
+// replaced atomically so that it is not left truncated if the collector is
+// stopped while writing it.
+func saveCheckpoints(path string, readers []*reader) error {
+	data, err := encodeCheckpoints(readers)
+	if err != nil {
+		return err
+	}
+	tmpFile := filepath.Join(filepath.Dir(path), "."+filepath.Base(path)+".tmp")
+	if err = ioutil.WriteFile(tmpFile, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmpFile, path)
+}
+
+// saveStorageCheckpoints persists the offsets of the readers in the storage
+// client.
+func saveStorageCheckpoints(ctx context.Context, client storage.Client, readers []*reader) error {
+	data, err := encodeCheckpoints(readers)
+	if err != nil {
+		return err
+	}
+	return client.Set(ctx, checkpointsKey, data)
+}
+
+func encodeCheckpoints(readers []*reader) ([]byte, error) {
+	checkpoints := make([]checkpoint, 0, len(readers))
+	for _, r := range readers {
+		checkpoints = append(checkpoints, checkpoint{Fingerprint: r.fingerprint, Offset: r.offset})
+	}
+	return json.Marshal(checkpoints)
+}
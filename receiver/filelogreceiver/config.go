@@ -0,0 +1,86 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filelogreceiver
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/config/configmodels"
+)
+
+// Config defines configuration for the filelog receiver.
+type Config struct {
+	configmodels.ReceiverSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct
+
+	// Include are the glob patterns, as supported by filepath.Match, of the
+	// files to read. Required.
+	Include []string `mapstructure:"include"`
+
+	// Exclude are the glob patterns of the files matched by Include which
+	// are not read.
+	Exclude []string `mapstructure:"exclude"`
+
+	// StartAt is where the files found at start are read from when they are
+	// not in the checkpoint, "end" (default) or "beginning". The files
+	// created later are read from the beginning.
+	StartAt string `mapstructure:"start_at"`
+
+	// PollInterval is the interval at which the files are matched and read.
+	PollInterval time.Duration `mapstructure:"poll_interval"`
+
+	// MaxLogSize is the maximum size in bytes of a log entry, the longer
+	// entries being split.
+	MaxLogSize int `mapstructure:"max_log_size"`
+
+	// CheckpointFile is the file where the offsets of the files are
+	// persisted, the files being read from them when the receiver is
+	// restarted. The offsets are not persisted if empty.
+	CheckpointFile string `mapstructure:"checkpoint_file"`
+
+	// Storage is the full name of the storage extension where the offsets of
+	// the files are persisted, instead of CheckpointFile.
+	Storage string `mapstructure:"storage"`
+
+	// Multiline stitches the lines into multiline log entries. Every line is
+	// a log entry if nil.
+	Multiline *MultilineConfig `mapstructure:"multiline"`
+
+	// IncludeFileName adds the "file.name" attribute, the base name of the
+	// file, to the log records.
+	IncludeFileName bool `mapstructure:"include_file_name"`
+
+	// IncludeFilePath adds the "file.path" attribute, the path of the file,
+	// to the log records.
+	IncludeFilePath bool `mapstructure:"include_file_path"`
+}
+
+// MultilineConfig configures how the lines are stitched into log entries,
+// exactly one of the patterns being set.
+type MultilineConfig struct {
+	// LineStartPattern is the regular expression matching the first line of
+	// the log entries.
+	LineStartPattern string `mapstructure:"line_start_pattern"`
+
+	// LineEndPattern is the regular expression matching the last line of
+	// the log entries.
+	LineEndPattern string `mapstructure:"line_end_pattern"`
+}
+
+var _ configmodels.ConfigValidator = (*Config)(nil)
+
+// Validate checks the receiver configuration is valid.
+func (cfg *Config) Validate() error {
+	return validateConfig(cfg)
+}
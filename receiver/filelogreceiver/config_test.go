@@ -0,0 +1,60 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filelogreceiver
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/config/configtest"
+)
+
+func TestLoadConfig(t *testing.T) {
+	factories, err := componenttest.NopFactories()
+	assert.NoError(t, err)
+
+	factory := NewFactory()
+	factories.Receivers[typeStr] = factory
+	cfg, err := configtest.LoadConfigFile(t, path.Join(".", "testdata", "config.yaml"), factories)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(cfg.Receivers))
+
+	assert.Equal(t, factory.CreateDefaultConfig(), cfg.Receivers[typeStr])
+
+	r := cfg.Receivers[typeStr+"/custom"].(*Config)
+	assert.Equal(t, &Config{
+		ReceiverSettings: configmodels.ReceiverSettings{
+			NameVal: typeStr + "/custom",
+			TypeVal: typeStr,
+		},
+		Include:        []string{"/var/log/app/*.log"},
+		Exclude:        []string{"/var/log/app/debug*.log"},
+		StartAt:        startAtBeginning,
+		PollInterval:   time.Second,
+		MaxLogSize:     65536,
+		CheckpointFile: "/var/lib/otelcol/filelog.json",
+		Multiline: &MultilineConfig{
+			LineStartPattern: `^\d{4}-\d{2}-\d{2}`,
+		},
+		IncludeFileName: false,
+		IncludeFilePath: true,
+	}, r)
+}
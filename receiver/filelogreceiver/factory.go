@@ -0,0 +1,113 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filelogreceiver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/receiver/receiverhelper"
+)
+
+const (
+	// The value of "type" key in configuration.
+	typeStr = "filelog"
+
+	defaultPollInterval = 200 * time.Millisecond
+	defaultMaxLogSize   = 1024 * 1024
+
+	startAtEnd       = "end"
+	startAtBeginning = "beginning"
+)
+
+var (
+	errNoInclude           = errors.New("\"include\" must contain at least one pattern")
+	errInvalidStartAt      = errors.New("\"start_at\" must be either \"end\" or \"beginning\"")
+	errInvalidPollInterval = errors.New("\"poll_interval\" must be positive")
+	errInvalidMaxLogSize   = errors.New("\"max_log_size\" must be positive")
+	errInvalidMultiline    = errors.New("\"multiline\" must set exactly one of \"line_start_pattern\" and \"line_end_pattern\"")
+	errCheckpointStorage   = errors.New("\"checkpoint_file\" and \"storage\" cannot be both set")
+)
+
+// NewFactory creates a factory for the filelog receiver.
+func NewFactory() component.ReceiverFactory {
+	return receiverhelper.NewFactory(
+		typeStr,
+		createDefaultConfig,
+		receiverhelper.WithLogs(createLogsReceiver))
+}
+
+func createDefaultConfig() configmodels.Receiver {
+	return &Config{
+		ReceiverSettings: configmodels.ReceiverSettings{
+			TypeVal: typeStr,
+			NameVal: typeStr,
+		},
+		StartAt:         startAtEnd,
+		PollInterval:    defaultPollInterval,
+		MaxLogSize:      defaultMaxLogSize,
+		IncludeFileName: true,
+	}
+}
+
+func createLogsReceiver(
+	_ context.Context,
+	params component.ReceiverCreateParams,
+	cfg configmodels.Receiver,
+	nextConsumer consumer.LogsConsumer,
+) (component.LogsReceiver, error) {
+	c := cfg.(*Config)
+	if err := validateConfig(c); err != nil {
+		return nil, err
+	}
+	splitter, err := newSplitter(c.Multiline, c.MaxLogSize)
+	if err != nil {
+		return nil, err
+	}
+	return newFileLogReceiver(params.Logger, c, splitter, nextConsumer), nil
+}
+
+func validateConfig(cfg *Config) error {
+	if len(cfg.Include) == 0 {
+		return errNoInclude
+	}
+	for _, pattern := range append(append([]string{}, cfg.Include...), cfg.Exclude...) {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+	}
+	if cfg.StartAt != startAtEnd && cfg.StartAt != startAtBeginning {
+		return errInvalidStartAt
+	}
+	if cfg.PollInterval <= 0 {
+		return errInvalidPollInterval
+	}
+	if cfg.MaxLogSize <= 0 {
+		return errInvalidMaxLogSize
+	}
+	if cfg.Multiline != nil && (cfg.Multiline.LineStartPattern == "") == (cfg.Multiline.LineEndPattern == "") {
+		return errInvalidMultiline
+	}
+	if cfg.CheckpointFile != "" && cfg.Storage != "" {
+		return errCheckpointStorage
+	}
+	return nil
+}
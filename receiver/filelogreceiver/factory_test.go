@@ -0,0 +1,118 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filelogreceiver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configcheck"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+)
+
+func TestCreateDefaultConfig(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	assert.NotNil(t, cfg, "failed to create default config")
+	assert.NoError(t, configcheck.ValidateConfig(cfg))
+	assert.Equal(t, startAtEnd, cfg.StartAt)
+	assert.Equal(t, defaultPollInterval, cfg.PollInterval)
+	assert.Equal(t, defaultMaxLogSize, cfg.MaxLogSize)
+	assert.True(t, cfg.IncludeFileName)
+	assert.False(t, cfg.IncludeFilePath)
+}
+
+func TestCreateLogsReceiver(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Include = []string{"/var/log/*.log"}
+	params := component.ReceiverCreateParams{Logger: zap.NewNop()}
+	r, err := NewFactory().CreateLogsReceiver(context.Background(), params, cfg, consumertest.NewLogsNop())
+	require.NoError(t, err)
+	assert.NotNil(t, r)
+}
+
+func TestCreateLogsReceiverInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		modify      func(cfg *Config)
+		expectedErr string
+	}{
+		{
+			name:        "no include",
+			modify:      func(cfg *Config) { cfg.Include = nil },
+			expectedErr: errNoInclude.Error(),
+		},
+		{
+			name:        "invalid exclude",
+			modify:      func(cfg *Config) { cfg.Exclude = []string{"[a-"} },
+			expectedErr: "invalid pattern \"[a-\": syntax error in pattern",
+		},
+		{
+			name:        "invalid start_at",
+			modify:      func(cfg *Config) { cfg.StartAt = "now" },
+			expectedErr: errInvalidStartAt.Error(),
+		},
+		{
+			name:        "invalid poll_interval",
+			modify:      func(cfg *Config) { cfg.PollInterval = 0 },
+			expectedErr: errInvalidPollInterval.Error(),
+		},
+		{
+			name:        "invalid max_log_size",
+			modify:      func(cfg *Config) { cfg.MaxLogSize = -1 },
+			expectedErr: errInvalidMaxLogSize.Error(),
+		},
+		{
+			name:        "no multiline pattern",
+			modify:      func(cfg *Config) { cfg.Multiline = &MultilineConfig{} },
+			expectedErr: errInvalidMultiline.Error(),
+		},
+		{
+			name: "both multiline patterns",
+			modify: func(cfg *Config) {
+				cfg.Multiline = &MultilineConfig{LineStartPattern: "^a", LineEndPattern: "b$"}
+			},
+			expectedErr: errInvalidMultiline.Error(),
+		},
+		{
+			name: "checkpoint file and storage",
+			modify: func(cfg *Config) {
+				cfg.CheckpointFile = "/var/lib/otelcol/filelog.json"
+				cfg.Storage = "file_storage"
+			},
+			expectedErr: errCheckpointStorage.Error(),
+		},
+		{
+			name:        "invalid multiline pattern",
+			modify:      func(cfg *Config) { cfg.Multiline = &MultilineConfig{LineEndPattern: "("} },
+			expectedErr: "invalid \"line_end_pattern\": error parsing regexp: missing closing ): `(`",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.Include = []string{"/var/log/*.log"}
+			test.modify(cfg)
+			params := component.ReceiverCreateParams{Logger: zap.NewNop()}
+			r, err := NewFactory().CreateLogsReceiver(context.Background(), params, cfg, consumertest.NewLogsNop())
+			assert.EqualError(t, err, test.expectedErr)
+			assert.Nil(t, r)
+		})
+	}
+}
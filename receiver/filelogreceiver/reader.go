@@ -0,0 +1,106 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filelogreceiver
+
+import (
+	"bytes"
+	"io"
+	"os"
+)
+
+// fingerprintSize is the size of the fingerprints identifying the files,
+// their first bytes.
+const fingerprintSize = 1000
+
+// reader reads the entries of a file from the offset where the last entry
+// read ended. The file is identified by its fingerprint, so that it is still
+// read from its offset when renamed by a rotation.
+type reader struct {
+	path        string
+	file        *os.File
+	fingerprint []byte
+	offset      int64
+	// pendingSize is the size of the incomplete entry at the end of the file
+	// at the last read, the entry being complete when the file does not grow.
+	pendingSize int64
+}
+
+// readFingerprint reads the fingerprint of file.
+func readFingerprint(file *os.File) ([]byte, error) {
+	buf := make([]byte, fingerprintSize)
+	n, err := file.ReadAt(buf, 0)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	return buf[:n], nil
+}
+
+// matches returns whether the file of the fingerprint is the file of the
+// reader, the fingerprint of a file growing with it until fingerprintSize.
+func (r *reader) matches(fingerprint []byte) bool {
+	return len(fingerprint) >= len(r.fingerprint) && bytes.HasPrefix(fingerprint, r.fingerprint)
+}
+
+// read reads the entries from the offset of the reader to the end of the
+// file, buf being used for the reads. The incomplete entry at the end of the
+// file is also read if the file did not grow since the last read or if
+// flush is set, when the file is not read anymore.
+func (r *reader) read(s *splitter, buf []byte, flush bool, emit func(entry string)) error {
+	info, err := r.file.Stat()
+	if err != nil {
+		return err
+	}
+	size := info.Size()
+	if size < r.offset {
+		// The file was truncated.
+		r.offset = 0
+		r.pendingSize = 0
+	}
+
+	if err = r.readUntil(s, buf, size, false, emit); err != nil {
+		return err
+	}
+	pendingSize := size - r.offset
+	if pendingSize > 0 && (flush || pendingSize == r.pendingSize) {
+		if err = r.readUntil(s, buf, size, true, emit); err != nil {
+			return err
+		}
+		pendingSize = 0
+	}
+	r.pendingSize = pendingSize
+	return nil
+}
+
+func (r *reader) readUntil(s *splitter, buf []byte, size int64, atEOF bool, emit func(entry string)) error {
+	for r.offset < size {
+		n, err := r.file.ReadAt(buf, r.offset)
+		if err != nil && err != io.EOF {
+			return err
+		}
+		if r.offset+int64(n) > size {
+			n = int(size - r.offset)
+		}
+		end := r.offset+int64(n) == size
+		entries, consumed := s.split(buf[:n], atEOF && end)
+		for _, entry := range entries {
+			emit(entry)
+		}
+		r.offset += int64(consumed)
+		if end || consumed == 0 {
+			return nil
+		}
+	}
+	return nil
+}
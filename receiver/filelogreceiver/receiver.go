@@ -0,0 +1,318 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filelogreceiver
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/extension/storage"
+	"go.opentelemetry.io/collector/obsreport"
+)
+
+// The maximum number of log records of the batches.
+const maxBatchSize = 100
+
+// The attributes of the log records.
+const (
+	fileNameAttributeKey = "file.name"
+	filePathAttributeKey = "file.path"
+)
+
+const (
+	dataFormat = "filelog"
+	transport  = "file"
+)
+
+type fileLogReceiver struct {
+	config   *Config
+	logger   *zap.Logger
+	next     consumer.LogsConsumer
+	splitter *splitter
+	buf      []byte
+
+	// readers are the readers of the files matched by the last poll, or
+	// those of the checkpoints before the first poll.
+	readers   []*reader
+	firstPoll bool
+
+	// storageClient persists the checkpoints when the storage is configured.
+	storageClient storage.Client
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func newFileLogReceiver(logger *zap.Logger, config *Config, splitter *splitter, next consumer.LogsConsumer) *fileLogReceiver {
+	return &fileLogReceiver{
+		config:   config,
+		logger:   logger,
+		next:     next,
+		splitter: splitter,
+		// The buffer is large enough for an entry to be split from it.
+		buf:       make([]byte, 2*config.MaxLogSize+1),
+		firstPoll: true,
+	}
+}
+
+func (r *fileLogReceiver) Start(ctx context.Context, host component.Host) error {
+	switch {
+	case r.config.CheckpointFile != "":
+		readers, err := loadCheckpoints(r.config.CheckpointFile)
+		if err != nil {
+			return fmt.Errorf("failed to load the checkpoints: %w", err)
+		}
+		r.readers = readers
+	case r.config.Storage != "":
+		client, err := storage.GetClient(ctx, host, r.config.Storage, component.KindReceiver, r.config.Name())
+		if err != nil {
+			return err
+		}
+		readers, err := loadStorageCheckpoints(ctx, client)
+		if err != nil {
+			_ = client.Close(ctx)
+			return fmt.Errorf("failed to load the checkpoints: %w", err)
+		}
+		r.storageClient = client
+		r.readers = readers
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		r.pollPeriodically(ctx)
+	}()
+	return nil
+}
+
+// Shutdown stops the receiver, closing the files.
+func (r *fileLogReceiver) Shutdown(context.Context) error {
+	if r.cancel == nil {
+		return nil
+	}
+	r.cancel()
+	r.wg.Wait()
+	for _, rd := range r.readers {
+		if rd.file != nil {
+			rd.file.Close()
+		}
+	}
+	if r.storageClient != nil {
+		return r.storageClient.Close(context.Background())
+	}
+	return nil
+}
+
+func (r *fileLogReceiver) pollPeriodically(ctx context.Context) {
+	r.poll()
+	ticker := time.NewTicker(r.config.PollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			r.poll()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// matchFiles returns the paths of the files matching the include patterns
+// and none of the exclude patterns.
+func (r *fileLogReceiver) matchFiles() []string {
+	var paths []string
+	seen := make(map[string]bool)
+	for _, include := range r.config.Include {
+		matches, _ := filepath.Glob(include)
+	match:
+		for _, path := range matches {
+			if seen[path] {
+				continue
+			}
+			for _, exclude := range r.config.Exclude {
+				if excluded, _ := filepath.Match(exclude, path); excluded {
+					continue match
+				}
+			}
+			seen[path] = true
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// poll reads the matched files. The files which are not matched anymore,
+// such as the files renamed by a rotation, are read until their end from
+// the file opened at the previous poll before being closed.
+func (r *fileLogReceiver) poll() {
+	batch := newBatch()
+	var readers []*reader
+	matched := make(map[*reader]bool)
+	for _, path := range r.matchFiles() {
+		file, err := os.Open(path)
+		if err != nil {
+			r.logger.Debug("Failed to open file", zap.String("path", path), zap.Error(err))
+			continue
+		}
+		fingerprint, err := readFingerprint(file)
+		if err != nil || len(fingerprint) == 0 {
+			// The empty files are identified once written.
+			file.Close()
+			continue
+		}
+
+		rd := r.findReader(fingerprint, matched)
+		if rd == nil {
+			rd = &reader{}
+			if r.firstPoll && r.config.StartAt == startAtEnd {
+				if info, err := file.Stat(); err == nil {
+					rd.offset = info.Size()
+				}
+			}
+		} else if rd.file != nil {
+			rd.file.Close()
+		}
+		rd.path = path
+		rd.file = file
+		rd.fingerprint = fingerprint
+		matched[rd] = true
+		readers = append(readers, rd)
+	}
+
+	for _, rd := range r.readers {
+		if matched[rd] || rd.file == nil {
+			continue
+		}
+		// A truncated file is read again by a new reader.
+		if !isOpen(rd.file, readers) {
+			r.read(rd, true, batch)
+		}
+		rd.file.Close()
+	}
+	for _, rd := range readers {
+		r.read(rd, false, batch)
+	}
+	r.consume(batch)
+
+	r.readers = readers
+	r.firstPoll = false
+	var err error
+	switch {
+	case r.config.CheckpointFile != "":
+		err = saveCheckpoints(r.config.CheckpointFile, readers)
+	case r.storageClient != nil:
+		err = saveStorageCheckpoints(context.Background(), r.storageClient, readers)
+	}
+	if err != nil {
+		r.logger.Error("Failed to save the checkpoints", zap.Error(err))
+	}
+}
+
+// findReader returns the reader of the file of the fingerprint, nil if the
+// file is new.
+func (r *fileLogReceiver) findReader(fingerprint []byte, matched map[*reader]bool) *reader {
+	for _, rd := range r.readers {
+		if !matched[rd] && rd.matches(fingerprint) {
+			return rd
+		}
+	}
+	return nil
+}
+
+// isOpen returns whether file is also opened by one of the readers.
+func isOpen(file *os.File, readers []*reader) bool {
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	for _, rd := range readers {
+		if other, err := rd.file.Stat(); err == nil && os.SameFile(info, other) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *fileLogReceiver) read(rd *reader, flush bool, batch *batch) {
+	name := filepath.Base(rd.path)
+	now := pdata.TimestampFromTime(time.Now())
+	err := rd.read(r.splitter, r.buf, flush, func(entry string) {
+		lr := pdata.NewLogRecord()
+		lr.SetTimestamp(now)
+		lr.Body().SetStringVal(entry)
+		if r.config.IncludeFileName {
+			lr.Attributes().InsertString(fileNameAttributeKey, name)
+		}
+		if r.config.IncludeFilePath {
+			lr.Attributes().InsertString(filePathAttributeKey, rd.path)
+		}
+		batch.records.Append(lr)
+		if batch.records.Len() >= maxBatchSize {
+			r.consume(batch)
+		}
+	})
+	if err != nil {
+		r.logger.Error("Failed to read file", zap.String("path", rd.path), zap.Error(err))
+	}
+}
+
+// batch is the batch of log records sent to the next consumer.
+type batch struct {
+	logs    pdata.Logs
+	records pdata.LogSlice
+}
+
+func newBatch() *batch {
+	b := &batch{}
+	b.reset()
+	return b
+}
+
+func (b *batch) reset() {
+	b.logs = pdata.NewLogs()
+	b.logs.ResourceLogs().Resize(1)
+	rl := b.logs.ResourceLogs().At(0)
+	rl.InstrumentationLibraryLogs().Resize(1)
+	b.records = rl.InstrumentationLibraryLogs().At(0).Logs()
+}
+
+// consume sends the batch, if not empty, to the next consumer and resets it.
+func (r *fileLogReceiver) consume(batch *batch) {
+	numRecords := batch.records.Len()
+	if numRecords == 0 {
+		return
+	}
+	ctx := obsreport.ReceiverContext(context.Background(), r.config.Name(), transport)
+	ctx = obsreport.StartLogsReceiveOp(ctx, r.config.Name(), transport)
+	err := r.next.ConsumeLogs(ctx, batch.logs)
+	obsreport.EndLogsReceiveOp(ctx, dataFormat, numRecords, err)
+	if err != nil {
+		r.logger.Error("Failed to send log entries", zap.Error(err))
+	}
+	batch.reset()
+}
@@ -0,0 +1,306 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filelogreceiver
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/extension/storage"
+)
+
+func newTestReceiver(t *testing.T, dir string, modify func(cfg *Config)) (*fileLogReceiver, *consumertest.LogsSink) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Include = []string{filepath.Join(dir, "*.log")}
+	modify(cfg)
+	require.NoError(t, validateConfig(cfg))
+	s, err := newSplitter(cfg.Multiline, cfg.MaxLogSize)
+	require.NoError(t, err)
+	sink := new(consumertest.LogsSink)
+	return newFileLogReceiver(zap.NewNop(), cfg, s, sink), sink
+}
+
+func writeFile(t *testing.T, path, content string) {
+	require.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+}
+
+func appendFile(t *testing.T, path, content string) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	require.NoError(t, err)
+	_, err = file.WriteString(content)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+}
+
+// entries returns the bodies of the received log records, and resets the sink.
+func entries(sink *consumertest.LogsSink) []string {
+	var bodies []string
+	for _, logs := range sink.AllLogs() {
+		records := logs.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs()
+		for i := 0; i < records.Len(); i++ {
+			bodies = append(bodies, records.At(i).Body().StringVal())
+		}
+	}
+	sink.Reset()
+	return bodies
+}
+
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "filelog")
+	require.NoError(t, err)
+	return dir
+}
+
+func TestPollStartAtEnd(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "app.log")
+	writeFile(t, path, "existing\n")
+
+	r, sink := newTestReceiver(t, dir, func(cfg *Config) { cfg.IncludeFilePath = true })
+	defer r.Shutdown(context.Background())
+	r.poll()
+	assert.Empty(t, entries(sink))
+
+	appendFile(t, path, "first\nsecond\n")
+	r.poll()
+	require.Equal(t, 1, len(sink.AllLogs()))
+	attrs := sink.AllLogs()[0].ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(0).Attributes()
+	name, ok := attrs.Get(fileNameAttributeKey)
+	require.True(t, ok)
+	assert.Equal(t, "app.log", name.StringVal())
+	filePath, ok := attrs.Get(filePathAttributeKey)
+	require.True(t, ok)
+	assert.Equal(t, path, filePath.StringVal())
+	assert.Equal(t, []string{"first", "second"}, entries(sink))
+
+	// A file created later is read from the beginning.
+	writeFile(t, filepath.Join(dir, "other.log"), "other\n")
+	r.poll()
+	assert.Equal(t, []string{"other"}, entries(sink))
+}
+
+func TestPollStartAtBeginningExclude(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	writeFile(t, filepath.Join(dir, "app.log"), "app\n")
+	writeFile(t, filepath.Join(dir, "debug.log"), "debug\n")
+	writeFile(t, filepath.Join(dir, "empty.log"), "")
+
+	r, sink := newTestReceiver(t, dir, func(cfg *Config) {
+		cfg.StartAt = startAtBeginning
+		cfg.Exclude = []string{filepath.Join(dir, "debug*")}
+		cfg.IncludeFileName = false
+	})
+	defer r.Shutdown(context.Background())
+	r.poll()
+	require.Equal(t, 1, sink.LogRecordsCount())
+	assert.Equal(t, 0, sink.AllLogs()[0].ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(0).Attributes().Len())
+	assert.Equal(t, []string{"app"}, entries(sink))
+}
+
+func TestPollRotation(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "app.log")
+	writeFile(t, path, "first\n")
+
+	r, sink := newTestReceiver(t, dir, func(cfg *Config) { cfg.StartAt = startAtBeginning })
+	defer r.Shutdown(context.Background())
+	r.poll()
+	assert.Equal(t, []string{"first"}, entries(sink))
+
+	// The lines written before the rotation are read from the renamed file,
+	// which is not matched anymore, and the new file from the beginning.
+	appendFile(t, path, "second\n")
+	require.NoError(t, os.Rename(path, path+".1"))
+	writeFile(t, path, "third\n")
+	r.poll()
+	assert.Equal(t, []string{"second", "third"}, entries(sink))
+
+	// The renamed file matched again is read from its offset.
+	require.NoError(t, os.Rename(path, filepath.Join(dir, "app.1.log")))
+	appendFile(t, filepath.Join(dir, "app.1.log"), "fourth\n")
+	r.poll()
+	assert.Equal(t, []string{"fourth"}, entries(sink))
+}
+
+func TestPollTruncation(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "app.log")
+	writeFile(t, path, "first\nsecond\n")
+
+	r, sink := newTestReceiver(t, dir, func(cfg *Config) { cfg.StartAt = startAtBeginning })
+	defer r.Shutdown(context.Background())
+	r.poll()
+	assert.Equal(t, []string{"first", "second"}, entries(sink))
+
+	require.NoError(t, os.Truncate(path, 0))
+	appendFile(t, path, "third\n")
+	r.poll()
+	assert.Equal(t, []string{"third"}, entries(sink))
+}
+
+func TestPollMultilinePending(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "app.log")
+	writeFile(t, path, "")
+
+	r, sink := newTestReceiver(t, dir, func(cfg *Config) {
+		cfg.Multiline = &MultilineConfig{LineStartPattern: `^\S`}
+	})
+	defer r.Shutdown(context.Background())
+	r.poll()
+
+	appendFile(t, path, "panic: boom\n  main.go:10\n")
+	r.poll()
+	assert.Empty(t, entries(sink))
+	appendFile(t, path, "  main.go:5\n")
+	r.poll()
+	assert.Empty(t, entries(sink))
+
+	// The entry is complete when the file does not grow.
+	r.poll()
+	assert.Equal(t, []string{"panic: boom\n  main.go:10\n  main.go:5"}, entries(sink))
+	r.poll()
+	assert.Empty(t, entries(sink))
+}
+
+func TestCheckpoints(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "app.log")
+	writeFile(t, path, "first\npartial")
+	checkpointFile := filepath.Join(dir, "checkpoints.json")
+	modify := func(cfg *Config) {
+		cfg.StartAt = startAtBeginning
+		cfg.CheckpointFile = checkpointFile
+		// The files are only read at start.
+		cfg.PollInterval = time.Hour
+	}
+
+	r, sink := newTestReceiver(t, dir, modify)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	require.Eventually(t, func() bool {
+		return sink.LogRecordsCount() == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, r.Shutdown(context.Background()))
+	assert.Equal(t, []string{"first"}, entries(sink))
+
+	// The restarted receiver reads the file from the checkpoint.
+	appendFile(t, path, " line\nsecond\n")
+	r, sink = newTestReceiver(t, dir, modify)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	defer r.Shutdown(context.Background())
+	require.Eventually(t, func() bool {
+		return sink.LogRecordsCount() == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"partial line", "second"}, entries(sink))
+}
+
+type fakeStorage struct {
+	component.Extension
+	client storage.Client
+}
+
+func (s *fakeStorage) GetClient(context.Context, component.Kind, string) (storage.Client, error) {
+	return s.client, nil
+}
+
+type storageHost struct {
+	component.Host
+	extensions map[configmodels.NamedEntity]component.Extension
+}
+
+func (h *storageHost) GetExtensions() map[configmodels.NamedEntity]component.Extension {
+	return h.extensions
+}
+
+func TestStorageCheckpoints(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "app.log")
+	writeFile(t, path, "first\npartial")
+	client, err := storage.NewFileClient(filepath.Join(dir, "storage"))
+	require.NoError(t, err)
+	host := &storageHost{
+		Host: componenttest.NewNopHost(),
+		extensions: map[configmodels.NamedEntity]component.Extension{
+			&configmodels.ExtensionSettings{TypeVal: "file_storage", NameVal: "file_storage"}: &fakeStorage{client: client},
+		},
+	}
+	modify := func(cfg *Config) {
+		cfg.StartAt = startAtBeginning
+		cfg.Storage = "file_storage"
+		// The files are only read at start.
+		cfg.PollInterval = time.Hour
+	}
+
+	r, sink := newTestReceiver(t, dir, modify)
+	require.NoError(t, r.Start(context.Background(), host))
+	require.Eventually(t, func() bool {
+		return sink.LogRecordsCount() == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, r.Shutdown(context.Background()))
+	assert.Equal(t, []string{"first"}, entries(sink))
+	data, err := client.Get(context.Background(), checkpointsKey)
+	require.NoError(t, err)
+	assert.NotEmpty(t, data)
+
+	// The restarted receiver reads the file from the checkpoint.
+	appendFile(t, path, " line\nsecond\n")
+	r, sink = newTestReceiver(t, dir, modify)
+	require.NoError(t, r.Start(context.Background(), host))
+	defer r.Shutdown(context.Background())
+	require.Eventually(t, func() bool {
+		return sink.LogRecordsCount() == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"partial line", "second"}, entries(sink))
+}
+
+func TestStartMissingStorage(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	r, _ := newTestReceiver(t, dir, func(cfg *Config) { cfg.Storage = "file_storage" })
+	assert.EqualError(t, r.Start(context.Background(), componenttest.NewNopHost()),
+		`storage extension "file_storage" not found, it must be enabled in the service`)
+	assert.NoError(t, r.Shutdown(context.Background()))
+}
+
+func TestStartInvalidCheckpointFile(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	checkpointFile := filepath.Join(dir, "checkpoints.json")
+	writeFile(t, checkpointFile, "{")
+
+	r, _ := newTestReceiver(t, dir, func(cfg *Config) { cfg.CheckpointFile = checkpointFile })
+	assert.Error(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	assert.NoError(t, r.Shutdown(context.Background()))
+}
@@ -0,0 +1,98 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filelogreceiver
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+)
+
+// splitter splits the content of the files into log entries, every line
+// being an entry unless the lines are stitched into multiline entries.
+type splitter struct {
+	lineStart  *regexp.Regexp
+	lineEnd    *regexp.Regexp
+	maxLogSize int
+}
+
+func newSplitter(cfg *MultilineConfig, maxLogSize int) (*splitter, error) {
+	s := &splitter{maxLogSize: maxLogSize}
+	if cfg == nil {
+		return s, nil
+	}
+	var err error
+	if cfg.LineStartPattern != "" {
+		if s.lineStart, err = regexp.Compile(cfg.LineStartPattern); err != nil {
+			return nil, fmt.Errorf("invalid \"line_start_pattern\": %w", err)
+		}
+	}
+	if cfg.LineEndPattern != "" {
+		if s.lineEnd, err = regexp.Compile(cfg.LineEndPattern); err != nil {
+			return nil, fmt.Errorf("invalid \"line_end_pattern\": %w", err)
+		}
+	}
+	return s, nil
+}
+
+// split splits data, which starts at the beginning of an entry, into the
+// complete entries and returns them with the number of bytes of data they
+// span. The last entry is only complete at the end of the file, atEOF, the
+// last line being complete when terminated by LF. The lines and entries
+// longer than maxLogSize are split, so that an entry is returned when data is
+// longer than 2*maxLogSize.
+func (s *splitter) split(data []byte, atEOF bool) ([]string, int) {
+	var entries []string
+	emit := func(entry []byte) {
+		if entry = bytes.TrimRight(entry, "\r\n"); len(entry) > 0 {
+			entries = append(entries, string(entry))
+		}
+	}
+
+	entryStart := 0
+	for lineStart := 0; lineStart < len(data); {
+		lineEnd := len(data)
+		if i := bytes.IndexByte(data[lineStart:], '\n'); i >= 0 {
+			lineEnd = lineStart + i + 1
+		}
+		if lineEnd-lineStart > s.maxLogSize {
+			lineEnd = lineStart + s.maxLogSize
+		} else if lineEnd == len(data) && data[lineEnd-1] != '\n' {
+			// The last line is not complete yet.
+			break
+		}
+		line := bytes.TrimRight(data[lineStart:lineEnd], "\r\n")
+
+		switch {
+		case lineStart > entryStart && (lineEnd-entryStart > s.maxLogSize || (s.lineStart != nil && s.lineStart.Match(line))):
+			emit(data[entryStart:lineStart])
+			entryStart = lineStart
+		case s.lineStart == nil && s.lineEnd == nil:
+			emit(data[entryStart:lineEnd])
+			entryStart = lineEnd
+		}
+		if s.lineEnd != nil && s.lineEnd.Match(line) {
+			emit(data[entryStart:lineEnd])
+			entryStart = lineEnd
+		}
+		lineStart = lineEnd
+	}
+
+	if atEOF && entryStart < len(data) {
+		emit(data[entryStart:])
+		entryStart = len(data)
+	}
+	return entries, entryStart
+}
@@ -0,0 +1,105 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filelogreceiver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		name             string
+		multiline        *MultilineConfig
+		maxLogSize       int
+		data             string
+		atEOF            bool
+		expectedEntries  []string
+		expectedConsumed int
+	}{
+		{
+			name:             "lines",
+			data:             "first\r\nsecond\n\nthird",
+			expectedEntries:  []string{"first", "second"},
+			expectedConsumed: 15,
+		},
+		{
+			name:             "lines at end of file",
+			data:             "first\nsecond",
+			atEOF:            true,
+			expectedEntries:  []string{"first", "second"},
+			expectedConsumed: 12,
+		},
+		{
+			name:             "long line",
+			maxLogSize:       4,
+			data:             "abcdefghij\n",
+			expectedEntries:  []string{"abcd", "efgh", "ij"},
+			expectedConsumed: 11,
+		},
+		{
+			name:             "line start pattern",
+			multiline:        &MultilineConfig{LineStartPattern: `^\d{4}-`},
+			data:             "2021-03-15 error\n  at main.go:10\n  at main.go:5\n2021-03-15 info\n2021-03-15 warn\n  details\n",
+			expectedEntries:  []string{"2021-03-15 error\n  at main.go:10\n  at main.go:5", "2021-03-15 info"},
+			expectedConsumed: 64,
+		},
+		{
+			name:             "line start pattern at end of file",
+			multiline:        &MultilineConfig{LineStartPattern: `^\d{4}-`},
+			data:             "2021-03-15 info\n2021-03-15 warn\n  details",
+			atEOF:            true,
+			expectedEntries:  []string{"2021-03-15 info", "2021-03-15 warn\n  details"},
+			expectedConsumed: 41,
+		},
+		{
+			name:             "line end pattern",
+			multiline:        &MultilineConfig{LineEndPattern: `;$`},
+			data:             "SELECT *\nFROM t;\nSELECT 1;\nSELECT\n",
+			expectedEntries:  []string{"SELECT *\nFROM t;", "SELECT 1;"},
+			expectedConsumed: 27,
+		},
+		{
+			name:             "long multiline entry",
+			multiline:        &MultilineConfig{LineStartPattern: `^start`},
+			maxLogSize:       12,
+			data:             "start\nabcd\nefgh\nstart\n",
+			expectedEntries:  []string{"start\nabcd", "efgh"},
+			expectedConsumed: 16,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			maxLogSize := test.maxLogSize
+			if maxLogSize == 0 {
+				maxLogSize = defaultMaxLogSize
+			}
+			s, err := newSplitter(test.multiline, maxLogSize)
+			require.NoError(t, err)
+			entries, consumed := s.split([]byte(test.data), test.atEOF)
+			assert.Equal(t, test.expectedEntries, entries)
+			assert.Equal(t, test.expectedConsumed, consumed)
+		})
+	}
+}
+
+func TestNewSplitterInvalidPattern(t *testing.T) {
+	_, err := newSplitter(&MultilineConfig{LineStartPattern: "("}, defaultMaxLogSize)
+	assert.EqualError(t, err, "invalid \"line_start_pattern\": error parsing regexp: missing closing ): `(`")
+	_, err = newSplitter(&MultilineConfig{LineEndPattern: "["}, defaultMaxLogSize)
+	assert.EqualError(t, err, "invalid \"line_end_pattern\": error parsing regexp: missing closing ]: `[`")
+}
@@ -16,6 +16,7 @@ package fluentforwardreceiver
 
 import (
 	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/config/configtls"
 )
 
 // Config defines configuration for the SignalFx receiver.
@@ -26,4 +27,23 @@ type Config struct {
 	// of the form `<ip addr>:<port>` (TCP) or `unix://<socket_path>` (Unix
 	// domain socket).
 	ListenAddress string `mapstructure:"endpoint"`
+
+	// Configures the TLS settings of the listener, the UDP heartbeats are not
+	// encrypted. TLS is disabled if nil.
+	TLSSetting *configtls.TLSServerSetting `mapstructure:"tls"`
+
+	// Security enables the handshake of the forward protocol, the clients
+	// having to authenticate with the shared key before sending events. The
+	// handshake is disabled if nil.
+	Security *SecurityConfig `mapstructure:"security"`
+}
+
+// SecurityConfig configures the handshake of the forward protocol.
+type SecurityConfig struct {
+	// SelfHostname is the hostname of the receiver sent to the clients, the
+	// hostname of the host by default.
+	SelfHostname string `mapstructure:"self_hostname"`
+
+	// SharedKey is the key shared with the clients, required.
+	SharedKey string `mapstructure:"shared_key"`
 }
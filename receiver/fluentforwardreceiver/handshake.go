@@ -0,0 +1,180 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fluentforwardreceiver
+
+import (
+	"crypto/rand"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/tinylib/msgp/msgp"
+)
+
+const nonceSize = 16
+
+var (
+	errEmptySharedKey    = errors.New("\"shared_key\" of \"security\" cannot be empty")
+	errSharedKeyMismatch = errors.New("shared key mismatch")
+)
+
+// handshaker authenticates the clients with the handshake of the forward
+// protocol, see
+// https://github.com/fluent/fluentd/wiki/Forward-Protocol-Specification-v1#handshake-messages.
+// The user authentication is not supported, the HELO message not requesting
+// it.
+type handshaker struct {
+	hostname  string
+	sharedKey string
+}
+
+// newHandshaker returns the handshaker of the given configuration, or nil
+// when the handshake is disabled.
+func newHandshaker(security *SecurityConfig) (*handshaker, error) {
+	if security == nil {
+		return nil, nil
+	}
+	if security.SharedKey == "" {
+		return nil, errEmptySharedKey
+	}
+
+	hostname := security.SelfHostname
+	if hostname == "" {
+		var err error
+		hostname, err = os.Hostname()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the hostname: %v", err)
+		}
+	}
+	return &handshaker{hostname: hostname, sharedKey: security.SharedKey}, nil
+}
+
+// ping is the PING message sent by the client in response to the HELO
+// message.
+type ping struct {
+	hostname string
+	salt     string
+	digest   string
+}
+
+// handshake sends a HELO message with a random nonce to the client, which
+// must respond with a PING message holding the digest of the nonce and of
+// the shared key. The PONG response holds the digest of the receiver so that
+// the client can authenticate it in turn. An error is returned if the client
+// does not know the shared key, after sending the PONG message.
+func (h *handshaker) handshake(conn io.Writer, reader *msgp.Reader) error {
+	nonce := make([]byte, nonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("failed to generate nonce: %v", err)
+	}
+
+	// ["HELO", {"nonce": nonce, "auth": "", "keepalive": true}]
+	var helo []byte
+	helo = msgp.AppendArrayHeader(helo, 2)
+	helo = msgp.AppendString(helo, "HELO")
+	helo = msgp.AppendMapHeader(helo, 3)
+	helo = msgp.AppendString(helo, "nonce")
+	helo = msgp.AppendBytes(helo, nonce)
+	helo = msgp.AppendString(helo, "auth")
+	helo = msgp.AppendBytes(helo, []byte{})
+	helo = msgp.AppendString(helo, "keepalive")
+	helo = msgp.AppendBool(helo, true)
+	if _, err := conn.Write(helo); err != nil {
+		return fmt.Errorf("failed to send HELO message: %v", err)
+	}
+
+	p, err := readPing(reader)
+	if err != nil {
+		return fmt.Errorf("failed to parse PING message: %v", err)
+	}
+
+	// ["PONG", auth_result, reason, self_hostname, shared_key_hexdigest]
+	var pong []byte
+	pong = msgp.AppendArrayHeader(pong, 5)
+	pong = msgp.AppendString(pong, "PONG")
+	expected := h.digest(p.salt, p.hostname, nonce)
+	authenticated := subtle.ConstantTimeCompare([]byte(expected), []byte(p.digest)) == 1
+	if authenticated {
+		pong = msgp.AppendBool(pong, true)
+		pong = msgp.AppendString(pong, "")
+		pong = msgp.AppendString(pong, h.hostname)
+		pong = msgp.AppendString(pong, h.digest(p.salt, h.hostname, nonce))
+	} else {
+		pong = msgp.AppendBool(pong, false)
+		pong = msgp.AppendString(pong, errSharedKeyMismatch.Error())
+		pong = msgp.AppendString(pong, "")
+		pong = msgp.AppendString(pong, "")
+	}
+	if _, err := conn.Write(pong); err != nil {
+		return fmt.Errorf("failed to send PONG message: %v", err)
+	}
+
+	if !authenticated {
+		return fmt.Errorf("handshake with %s failed: %w", p.hostname, errSharedKeyMismatch)
+	}
+	return nil
+}
+
+// digest returns the hex encoded SHA-512 digest of the salt, the hostname,
+// the nonce and the shared key.
+func (h *handshaker) digest(salt, hostname string, nonce []byte) string {
+	hash := sha512.New()
+	hash.Write([]byte(salt))
+	hash.Write([]byte(hostname))
+	hash.Write(nonce)
+	hash.Write([]byte(h.sharedKey))
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// readPing reads the ["PING", hostname, shared_key_salt, shared_key_hexdigest,
+// username, password_digest] message, the salt being sent as a string or as
+// binary depending on the client.
+func readPing(reader *msgp.Reader) (*ping, error) {
+	arrLen, err := reader.ReadArrayHeader()
+	if err != nil {
+		return nil, msgp.WrapError(err)
+	}
+	if arrLen != 6 {
+		return nil, msgp.ArrayError{Wanted: 6, Got: arrLen}
+	}
+
+	fields := make([]string, arrLen)
+	for i := range fields {
+		fields[i], err = readStringOrBytes(reader)
+		if err != nil {
+			return nil, msgp.WrapError(err, i)
+		}
+	}
+	if fields[0] != "PING" {
+		return nil, fmt.Errorf("unexpected message type %q", fields[0])
+	}
+	return &ping{hostname: fields[1], salt: fields[2], digest: fields[3]}, nil
+}
+
+func readStringOrBytes(reader *msgp.Reader) (string, error) {
+	typ, err := reader.NextType()
+	if err != nil {
+		return "", err
+	}
+	if typ == msgp.BinType {
+		b, err := reader.ReadBytes(nil)
+		return string(b), err
+	}
+	return reader.ReadString()
+}
@@ -0,0 +1,187 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fluentforwardreceiver
+
+import (
+	"crypto/sha512"
+	"encoding/hex"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tinylib/msgp/msgp"
+)
+
+func TestNewHandshaker(t *testing.T) {
+	h, err := newHandshaker(nil)
+	require.NoError(t, err)
+	assert.Nil(t, h)
+
+	_, err = newHandshaker(&SecurityConfig{SelfHostname: "collector"})
+	assert.Equal(t, errEmptySharedKey, err)
+
+	h, err = newHandshaker(&SecurityConfig{SharedKey: "secret"})
+	require.NoError(t, err)
+	hostname, err := os.Hostname()
+	require.NoError(t, err)
+	assert.Equal(t, &handshaker{hostname: hostname, sharedKey: "secret"}, h)
+
+	h, err = newHandshaker(&SecurityConfig{SelfHostname: "collector", SharedKey: "secret"})
+	require.NoError(t, err)
+	assert.Equal(t, &handshaker{hostname: "collector", sharedKey: "secret"}, h)
+}
+
+func sharedKeyDigest(salt, hostname string, nonce []byte, sharedKey string) string {
+	hash := sha512.New()
+	hash.Write([]byte(salt))
+	hash.Write([]byte(hostname))
+	hash.Write(nonce)
+	hash.Write([]byte(sharedKey))
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// clientHandshake performs the client side of the handshake with the given
+// shared key, returning the fields of the PONG message.
+func clientHandshake(t *testing.T, conn net.Conn, sharedKey string) (bool, string, string, string, []byte) {
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	reader := msgp.NewReader(conn)
+
+	arrLen, err := reader.ReadArrayHeader()
+	require.NoError(t, err)
+	require.EqualValues(t, 2, arrLen)
+	msgType, err := reader.ReadString()
+	require.NoError(t, err)
+	require.Equal(t, "HELO", msgType)
+	mapLen, err := reader.ReadMapHeader()
+	require.NoError(t, err)
+	var nonce []byte
+	for i := uint32(0); i < mapLen; i++ {
+		key, err := reader.ReadString()
+		require.NoError(t, err)
+		switch key {
+		case "nonce":
+			nonce, err = reader.ReadBytes(nil)
+		case "auth":
+			var auth []byte
+			auth, err = reader.ReadBytes(nil)
+			assert.Len(t, auth, 0)
+		case "keepalive":
+			var keepalive bool
+			keepalive, err = reader.ReadBool()
+			assert.True(t, keepalive)
+		}
+		require.NoError(t, err)
+	}
+	require.Len(t, nonce, nonceSize)
+
+	var ping []byte
+	ping = msgp.AppendArrayHeader(ping, 6)
+	ping = msgp.AppendString(ping, "PING")
+	ping = msgp.AppendString(ping, "fluent-bit")
+	ping = msgp.AppendBytes(ping, []byte("salt"))
+	ping = msgp.AppendString(ping, sharedKeyDigest("salt", "fluent-bit", nonce, sharedKey))
+	ping = msgp.AppendString(ping, "")
+	ping = msgp.AppendString(ping, "")
+	_, err = conn.Write(ping)
+	require.NoError(t, err)
+
+	arrLen, err = reader.ReadArrayHeader()
+	require.NoError(t, err)
+	require.EqualValues(t, 5, arrLen)
+	msgType, err = reader.ReadString()
+	require.NoError(t, err)
+	require.Equal(t, "PONG", msgType)
+	authenticated, err := reader.ReadBool()
+	require.NoError(t, err)
+	reason, err := reader.ReadString()
+	require.NoError(t, err)
+	hostname, err := reader.ReadString()
+	require.NoError(t, err)
+	digest, err := reader.ReadString()
+	require.NoError(t, err)
+	return authenticated, reason, hostname, digest, nonce
+}
+
+func TestHandshake(t *testing.T) {
+	connect, next, _, cancel := setupServerWithConfig(t, &Config{
+		ListenAddress: "127.0.0.1:0",
+		Security:      &SecurityConfig{SelfHostname: "collector", SharedKey: "secret"},
+	})
+	defer cancel()
+
+	conn := connect()
+	authenticated, reason, hostname, digest, nonce := clientHandshake(t, conn, "secret")
+	assert.True(t, authenticated)
+	assert.Equal(t, "", reason)
+	assert.Equal(t, "collector", hostname)
+	assert.Equal(t, sharedKeyDigest("salt", "collector", nonce, "secret"), digest)
+
+	_, err := conn.Write(parseHexDump("testdata/message-event"))
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+
+	require.Eventually(t, func() bool {
+		return len(next.AllLogs()) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestHandshakeSharedKeyMismatch(t *testing.T) {
+	connect, next, observedLogs, cancel := setupServerWithConfig(t, &Config{
+		ListenAddress: "127.0.0.1:0",
+		Security:      &SecurityConfig{SelfHostname: "collector", SharedKey: "secret"},
+	})
+	defer cancel()
+
+	conn := connect()
+	authenticated, reason, hostname, digest, _ := clientHandshake(t, conn, "wrong")
+	assert.False(t, authenticated)
+	assert.Equal(t, "shared key mismatch", reason)
+	assert.Equal(t, "", hostname)
+	assert.Equal(t, "", digest)
+
+	waitForConnectionClose(t, conn)
+	require.Eventually(t, func() bool {
+		return len(observedLogs.FilterMessageSnippet("Unexpected").All()) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Len(t, next.AllLogs(), 0)
+}
+
+func TestHandshakeWithoutPing(t *testing.T) {
+	connect, next, _, cancel := setupServerWithConfig(t, &Config{
+		ListenAddress: "127.0.0.1:0",
+		Security:      &SecurityConfig{SharedKey: "secret"},
+	})
+	defer cancel()
+
+	// A client not performing the handshake sends its events right away.
+	conn := connect()
+	_, err := conn.Write(parseHexDump("testdata/message-event"))
+	require.NoError(t, err)
+
+	waitForConnectionCloseAfterHelo(t, conn)
+	assert.Len(t, next.AllLogs(), 0)
+}
+
+// waitForConnectionCloseAfterHelo discards the HELO message and waits for
+// the connection to be closed.
+func waitForConnectionCloseAfterHelo(t *testing.T, conn net.Conn) {
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	reader := msgp.NewReader(conn)
+	require.NoError(t, reader.Skip())
+	waitForConnectionClose(t, conn)
+}
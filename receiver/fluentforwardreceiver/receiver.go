@@ -16,6 +16,7 @@ package fluentforwardreceiver
 
 import (
 	"context"
+	"crypto/tls"
 	"net"
 	"strings"
 
@@ -39,11 +40,16 @@ type fluentReceiver struct {
 }
 
 func newFluentReceiver(logger *zap.Logger, conf *Config, next consumer.LogsConsumer) (component.LogsReceiver, error) {
+	handshaker, err := newHandshaker(conf.Security)
+	if err != nil {
+		return nil, err
+	}
+
 	eventCh := make(chan Event, eventChannelLength)
 
 	collector := newCollector(eventCh, next, logger)
 
-	server := newServer(eventCh, logger)
+	server := newServer(eventCh, logger, handshaker)
 
 	return &fluentReceiver{
 		collector: collector,
@@ -54,6 +60,15 @@ func newFluentReceiver(logger *zap.Logger, conf *Config, next consumer.LogsConsu
 }
 
 func (r *fluentReceiver) Start(ctx context.Context, _ component.Host) error {
+	var tlsCfg *tls.Config
+	if r.conf.TLSSetting != nil {
+		var err error
+		tlsCfg, err = r.conf.TLSSetting.LoadTLSConfig()
+		if err != nil {
+			return err
+		}
+	}
+
 	receiverCtx, cancel := context.WithCancel(ctx)
 	r.cancel = cancel
 
@@ -77,6 +92,10 @@ func (r *fluentReceiver) Start(ctx context.Context, _ component.Host) error {
 		return err
 	}
 
+	if tlsCfg != nil {
+		listener = tls.NewListener(listener, tlsCfg)
+	}
+
 	r.listener = listener
 
 	r.server.Start(receiverCtx, listener)
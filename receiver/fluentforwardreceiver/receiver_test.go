@@ -16,9 +16,17 @@ package fluentforwardreceiver
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"math/big"
 	"net"
 	"os"
 	"path/filepath"
@@ -31,22 +39,25 @@ import (
 	"go.uber.org/zap"
 	"go.uber.org/zap/zaptest/observer"
 
+	"go.opentelemetry.io/collector/config/configtls"
 	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/testutil/logstest"
 )
 
 func setupServer(t *testing.T) (func() net.Conn, *consumertest.LogsSink, *observer.ObservedLogs, context.CancelFunc) {
+	return setupServerWithConfig(t, &Config{
+		ListenAddress: "127.0.0.1:0",
+	})
+}
+
+func setupServerWithConfig(t *testing.T, conf *Config) (func() net.Conn, *consumertest.LogsSink, *observer.ObservedLogs, context.CancelFunc) {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	next := new(consumertest.LogsSink)
 	logCore, logObserver := observer.New(zap.DebugLevel)
 	logger := zap.New(logCore)
 
-	conf := &Config{
-		ListenAddress: "127.0.0.1:0",
-	}
-
 	receiver, err := newFluentReceiver(logger, conf, next)
 	require.NoError(t, err)
 	require.NoError(t, receiver.Start(ctx, nil))
@@ -377,6 +388,89 @@ func TestUnixEndpoint(t *testing.T) {
 	}, 5*time.Second, 10*time.Millisecond)
 }
 
+// writeSelfSignedCert writes a certificate for 127.0.0.1 and its key to dir.
+func writeSelfSignedCert(t *testing.T, dir string) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	cert, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyBytes, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	require.NoError(t, ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert}), 0600))
+	require.NoError(t, ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyBytes}), 0600))
+	return certFile, keyFile
+}
+
+func TestTLSEndpoint(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	next := new(consumertest.LogsSink)
+
+	tmpdir, err := ioutil.TempDir("", "fluent-tls")
+	require.NoError(t, err)
+
+	defer os.RemoveAll(tmpdir)
+
+	certFile, keyFile := writeSelfSignedCert(t, tmpdir)
+	conf := &Config{
+		ListenAddress: "127.0.0.1:0",
+		TLSSetting: &configtls.TLSServerSetting{
+			TLSSetting: configtls.TLSSetting{CertFile: certFile, KeyFile: keyFile},
+		},
+	}
+
+	receiver, err := newFluentReceiver(zap.NewNop(), conf, next)
+	require.NoError(t, err)
+	require.NoError(t, receiver.Start(ctx, nil))
+	defer receiver.Shutdown(ctx)
+
+	conn, err := tls.Dial("tcp", receiver.(*fluentReceiver).listener.Addr().String(), &tls.Config{InsecureSkipVerify: true})
+	require.NoError(t, err)
+
+	n, err := conn.Write(parseHexDump("testdata/message-event"))
+	require.NoError(t, err)
+	require.Greater(t, n, 0)
+	require.NoError(t, conn.Close())
+
+	require.Eventually(t, func() bool {
+		return len(next.AllLogs()) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestTLSInvalidCert(t *testing.T) {
+	conf := &Config{
+		ListenAddress: "127.0.0.1:0",
+		TLSSetting: &configtls.TLSServerSetting{
+			TLSSetting: configtls.TLSSetting{CertFile: "testdata/missing-cert.pem", KeyFile: "testdata/missing-key.pem"},
+		},
+	}
+
+	receiver, err := newFluentReceiver(zap.NewNop(), conf, new(consumertest.LogsSink))
+	require.NoError(t, err)
+	require.Error(t, receiver.Start(context.Background(), nil))
+}
+
+func TestInvalidSecurity(t *testing.T) {
+	conf := &Config{
+		ListenAddress: "127.0.0.1:0",
+		Security:      &SecurityConfig{},
+	}
+
+	_, err := newFluentReceiver(zap.NewNop(), conf, new(consumertest.LogsSink))
+	require.Equal(t, errEmptySharedKey, err)
+}
+
 func makeSampleEvent(tag string) []byte {
 	var b []byte
 
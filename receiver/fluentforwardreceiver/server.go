@@ -35,14 +35,16 @@ import (
 const readBufferSize = 10 * 1024
 
 type server struct {
-	outCh  chan<- Event
-	logger *zap.Logger
+	outCh      chan<- Event
+	logger     *zap.Logger
+	handshaker *handshaker
 }
 
-func newServer(outCh chan<- Event, logger *zap.Logger) *server {
+func newServer(outCh chan<- Event, logger *zap.Logger, handshaker *handshaker) *server {
 	return &server{
-		outCh:  outCh,
-		logger: logger,
+		outCh:      outCh,
+		logger:     logger,
+		handshaker: handshaker,
 	}
 }
 
@@ -91,6 +93,12 @@ func (s *server) handleConnections(ctx context.Context, listener net.Listener) {
 func (s *server) handleConn(ctx context.Context, conn net.Conn) error {
 	reader := msgp.NewReaderSize(conn, readBufferSize)
 
+	if s.handshaker != nil {
+		if err := s.handshaker.handshake(conn, reader); err != nil {
+			return err
+		}
+	}
+
 	for {
 		mode, err := DetermineNextEventMode(reader.R)
 		if err != nil {
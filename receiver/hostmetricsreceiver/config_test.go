@@ -36,6 +36,7 @@ import (
 	"go.opentelemetry.io/collector/receiver/hostmetricsreceiver/internal/scraper/pagingscraper"
 	"go.opentelemetry.io/collector/receiver/hostmetricsreceiver/internal/scraper/processesscraper"
 	"go.opentelemetry.io/collector/receiver/hostmetricsreceiver/internal/scraper/processscraper"
+	"go.opentelemetry.io/collector/receiver/hostmetricsreceiver/internal/scraper/sensorsscraper"
 	"go.opentelemetry.io/collector/receiver/scraperhelper"
 )
 
@@ -80,14 +81,18 @@ func TestLoadConfig(t *testing.T) {
 					Interfaces: []string{"test1"},
 					Config:     filterset.Config{MatchType: "strict"},
 				},
+				Scope: "container",
 			},
 			processesscraper.TypeStr: &processesscraper.Config{},
 			pagingscraper.TypeStr:    &pagingscraper.Config{},
+			sensorsscraper.TypeStr:   &sensorsscraper.Config{},
 			processscraper.TypeStr: &processscraper.Config{
 				Include: processscraper.MatchConfig{
 					Names:  []string{"test2", "test3"},
 					Config: filterset.Config{MatchType: "regexp"},
 				},
+				TopN:  10,
+				TopBy: "memory",
 			},
 		},
 	}
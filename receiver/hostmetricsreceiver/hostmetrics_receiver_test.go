@@ -69,6 +69,11 @@ var resourceMetrics = []string{
 	"process.memory.physical_usage",
 	"process.memory.virtual_usage",
 	"process.disk.io",
+	"process.threads",
+}
+
+var systemSpecificResourceMetrics = map[string][]string{
+	"linux": {"process.open_file_descriptors"},
 }
 
 var systemSpecificMetrics = map[string][]string{
@@ -173,8 +178,9 @@ func assertIncludesExpectedMetrics(t *testing.T, got pdata.Metrics) {
 		return
 	}
 
-	assert.Equal(t, len(resourceMetrics), len(returnedResourceMetrics))
-	for _, expected := range resourceMetrics {
+	expectedResourceMetrics := append(resourceMetrics, systemSpecificResourceMetrics[runtime.GOOS]...)
+	assert.Equal(t, len(expectedResourceMetrics), len(returnedResourceMetrics))
+	for _, expected := range expectedResourceMetrics {
 		assert.Contains(t, returnedResourceMetrics, expected)
 	}
 }
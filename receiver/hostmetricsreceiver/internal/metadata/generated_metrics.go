@@ -59,6 +59,10 @@ type metricStruct struct {
 	ProcessDiskIo               MetricIntf
 	ProcessMemoryPhysicalUsage  MetricIntf
 	ProcessMemoryVirtualUsage   MetricIntf
+	ProcessOpenFileDescriptors  MetricIntf
+	ProcessThreads              MetricIntf
+	SystemBatteryCharge         MetricIntf
+	SystemBatteryPower          MetricIntf
 	SystemCPULoadAverage15m     MetricIntf
 	SystemCPULoadAverage1m      MetricIntf
 	SystemCPULoadAverage5m      MetricIntf
@@ -83,6 +87,9 @@ type metricStruct struct {
 	SystemPagingUsage           MetricIntf
 	SystemProcessesCount        MetricIntf
 	SystemProcessesCreated      MetricIntf
+	SystemSensorsFanSpeed       MetricIntf
+	SystemSensorsPower          MetricIntf
+	SystemSensorsTemperature    MetricIntf
 }
 
 // Names returns a list of all the metric name strings.
@@ -92,6 +99,10 @@ func (m *metricStruct) Names() []string {
 		"process.disk.io",
 		"process.memory.physical_usage",
 		"process.memory.virtual_usage",
+		"process.open_file_descriptors",
+		"process.threads",
+		"system.battery.charge",
+		"system.battery.power",
 		"system.cpu.load_average.15m",
 		"system.cpu.load_average.1m",
 		"system.cpu.load_average.5m",
@@ -116,6 +127,9 @@ func (m *metricStruct) Names() []string {
 		"system.paging.usage",
 		"system.processes.count",
 		"system.processes.created",
+		"system.sensors.fan_speed",
+		"system.sensors.power",
+		"system.sensors.temperature",
 	}
 }
 
@@ -124,6 +138,10 @@ var metricsByName = map[string]MetricIntf{
 	"process.disk.io":                Metrics.ProcessDiskIo,
 	"process.memory.physical_usage":  Metrics.ProcessMemoryPhysicalUsage,
 	"process.memory.virtual_usage":   Metrics.ProcessMemoryVirtualUsage,
+	"process.open_file_descriptors":  Metrics.ProcessOpenFileDescriptors,
+	"process.threads":                Metrics.ProcessThreads,
+	"system.battery.charge":          Metrics.SystemBatteryCharge,
+	"system.battery.power":           Metrics.SystemBatteryPower,
 	"system.cpu.load_average.15m":    Metrics.SystemCPULoadAverage15m,
 	"system.cpu.load_average.1m":     Metrics.SystemCPULoadAverage1m,
 	"system.cpu.load_average.5m":     Metrics.SystemCPULoadAverage5m,
@@ -148,6 +166,9 @@ var metricsByName = map[string]MetricIntf{
 	"system.paging.usage":            Metrics.SystemPagingUsage,
 	"system.processes.count":         Metrics.SystemProcessesCount,
 	"system.processes.created":       Metrics.SystemProcessesCreated,
+	"system.sensors.fan_speed":       Metrics.SystemSensorsFanSpeed,
+	"system.sensors.power":           Metrics.SystemSensorsPower,
+	"system.sensors.temperature":     Metrics.SystemSensorsTemperature,
 }
 
 func (m *metricStruct) ByName(n string) MetricIntf {
@@ -160,6 +181,10 @@ func (m *metricStruct) FactoriesByName() map[string]func() pdata.Metric {
 		Metrics.ProcessDiskIo.Name():               Metrics.ProcessDiskIo.New,
 		Metrics.ProcessMemoryPhysicalUsage.Name():  Metrics.ProcessMemoryPhysicalUsage.New,
 		Metrics.ProcessMemoryVirtualUsage.Name():   Metrics.ProcessMemoryVirtualUsage.New,
+		Metrics.ProcessOpenFileDescriptors.Name():  Metrics.ProcessOpenFileDescriptors.New,
+		Metrics.ProcessThreads.Name():              Metrics.ProcessThreads.New,
+		Metrics.SystemBatteryCharge.Name():         Metrics.SystemBatteryCharge.New,
+		Metrics.SystemBatteryPower.Name():          Metrics.SystemBatteryPower.New,
 		Metrics.SystemCPULoadAverage15m.Name():     Metrics.SystemCPULoadAverage15m.New,
 		Metrics.SystemCPULoadAverage1m.Name():      Metrics.SystemCPULoadAverage1m.New,
 		Metrics.SystemCPULoadAverage5m.Name():      Metrics.SystemCPULoadAverage5m.New,
@@ -184,6 +209,9 @@ func (m *metricStruct) FactoriesByName() map[string]func() pdata.Metric {
 		Metrics.SystemPagingUsage.Name():           Metrics.SystemPagingUsage.New,
 		Metrics.SystemProcessesCount.Name():        Metrics.SystemProcessesCount.New,
 		Metrics.SystemProcessesCreated.Name():      Metrics.SystemProcessesCreated.New,
+		Metrics.SystemSensorsFanSpeed.Name():       Metrics.SystemSensorsFanSpeed.New,
+		Metrics.SystemSensorsPower.Name():          Metrics.SystemSensorsPower.New,
+		Metrics.SystemSensorsTemperature.Name():    Metrics.SystemSensorsTemperature.New,
 	}
 }
 
@@ -234,6 +262,46 @@ var Metrics = &metricStruct{
 			metric.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
 		},
 	},
+	&metricImpl{
+		"process.open_file_descriptors",
+		func(metric pdata.Metric) {
+			metric.SetName("process.open_file_descriptors")
+			metric.SetDescription("Number of file descriptors opened by the process.")
+			metric.SetUnit("{descriptors}")
+			metric.SetDataType(pdata.MetricDataTypeIntSum)
+			metric.IntSum().SetIsMonotonic(false)
+			metric.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+		},
+	},
+	&metricImpl{
+		"process.threads",
+		func(metric pdata.Metric) {
+			metric.SetName("process.threads")
+			metric.SetDescription("Number of threads of the process.")
+			metric.SetUnit("{threads}")
+			metric.SetDataType(pdata.MetricDataTypeIntSum)
+			metric.IntSum().SetIsMonotonic(false)
+			metric.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+		},
+	},
+	&metricImpl{
+		"system.battery.charge",
+		func(metric pdata.Metric) {
+			metric.SetName("system.battery.charge")
+			metric.SetDescription("Remaining charge of the battery, in percent of its full capacity.")
+			metric.SetUnit("%")
+			metric.SetDataType(pdata.MetricDataTypeDoubleGauge)
+		},
+	},
+	&metricImpl{
+		"system.battery.power",
+		func(metric pdata.Metric) {
+			metric.SetName("system.battery.power")
+			metric.SetDescription("Power drawn from or supplied to the battery.")
+			metric.SetUnit("W")
+			metric.SetDataType(pdata.MetricDataTypeDoubleGauge)
+		},
+	},
 	&metricImpl{
 		"system.cpu.load_average.15m",
 		func(metric pdata.Metric) {
@@ -492,6 +560,33 @@ var Metrics = &metricStruct{
 			metric.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
 		},
 	},
+	&metricImpl{
+		"system.sensors.fan_speed",
+		func(metric pdata.Metric) {
+			metric.SetName("system.sensors.fan_speed")
+			metric.SetDescription("Speed of the fan.")
+			metric.SetUnit("{rpm}")
+			metric.SetDataType(pdata.MetricDataTypeIntGauge)
+		},
+	},
+	&metricImpl{
+		"system.sensors.power",
+		func(metric pdata.Metric) {
+			metric.SetName("system.sensors.power")
+			metric.SetDescription("Power reported by the sensor.")
+			metric.SetUnit("W")
+			metric.SetDataType(pdata.MetricDataTypeDoubleGauge)
+		},
+	},
+	&metricImpl{
+		"system.sensors.temperature",
+		func(metric pdata.Metric) {
+			metric.SetName("system.sensors.temperature")
+			metric.SetDescription("Temperature reported by the sensor.")
+			metric.SetUnit("Cel")
+			metric.SetDataType(pdata.MetricDataTypeDoubleGauge)
+		},
+	},
 }
 
 // M contains a set of methods for each metric that help with
@@ -500,6 +595,8 @@ var M = Metrics
 
 // Labels contains the possible metric labels that can be used.
 var Labels = struct {
+	// BatteryName (Name of the battery, such as "BAT0".)
+	BatteryName string
 	// Cpu (CPU number starting at 0.)
 	Cpu string
 	// CPUState (Breakdown of CPU usage by type.)
@@ -542,7 +639,12 @@ var Labels = struct {
 	ProcessState string
 	// ProcessesStatus (Breakdown status of the processes.)
 	ProcessesStatus string
+	// SensorsChip (Name of the hardware monitoring chip, such as "coretemp" or "acpitz".)
+	SensorsChip string
+	// SensorsSensor (Name of the sensor of the chip, such as "Core 0" or "temp1".)
+	SensorsSensor string
 }{
+	"battery",
 	"cpu",
 	"state",
 	"device",
@@ -564,6 +666,8 @@ var Labels = struct {
 	"direction",
 	"state",
 	"status",
+	"chip",
+	"sensor",
 }
 
 // L contains the possible metric labels that can be used. L is an alias for
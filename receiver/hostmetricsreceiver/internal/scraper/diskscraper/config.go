@@ -28,6 +28,10 @@ type Config struct {
 	// If neither `include` or `exclude` are set, metrics will be generated for all devices.
 	Include MatchConfig `mapstructure:"include"`
 	Exclude MatchConfig `mapstructure:"exclude"`
+
+	// Scope is either "host" (default), the statistics of the host, or "container", the block
+	// I/O statistics of the cgroup (v1 or v2) of the collector, only supported on Linux.
+	Scope string `mapstructure:"scope"`
 }
 
 type MatchConfig struct {
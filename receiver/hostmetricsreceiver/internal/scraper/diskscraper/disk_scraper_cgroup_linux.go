@@ -0,0 +1,213 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build linux
+
+package diskscraper
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/shirou/gopsutil/disk"
+)
+
+// cgroupIOReader reads the block I/O statistics of the cgroup of the collector, from the
+// io controller with cgroup v2 or the blkio controller with cgroup v1. The files are read
+// from the mount namespace of the collector, regardless of HOST_PROC and HOST_SYS.
+type cgroupIOReader struct {
+	procSelfCgroup string
+	cgroupRoot     string
+	sysDevBlock    string
+}
+
+func newCgroupIOReader() *cgroupIOReader {
+	return &cgroupIOReader{
+		procSelfCgroup: "/proc/self/cgroup",
+		cgroupRoot:     "/sys/fs/cgroup",
+		sysDevBlock:    "/sys/dev/block",
+	}
+}
+
+// ioCounters returns the counters of the devices, by name, of the cgroup of the collector.
+func (r *cgroupIOReader) ioCounters(...string) (map[string]disk.IOCountersStat, error) {
+	v1Path, v2Path, err := r.cgroupPaths()
+	if err != nil {
+		return nil, err
+	}
+
+	// With the hybrid hierarchy, the io controller may not be enabled in the v2 hierarchy.
+	if v2Path != "" {
+		ioStat := filepath.Join(r.cgroupDir("", v2Path), "io.stat")
+		if _, err = os.Stat(ioStat); err == nil {
+			return r.readIOStat(ioStat)
+		}
+	}
+	if v1Path != "" {
+		return r.readBlkio(r.cgroupDir("blkio", v1Path))
+	}
+	return nil, errors.New("no io or blkio cgroup found for the collector")
+}
+
+// cgroupPaths returns the path of the cgroup of the collector in the blkio cgroup v1
+// hierarchy and in the cgroup v2 hierarchy, empty if the collector is not in the hierarchy.
+func (r *cgroupIOReader) cgroupPaths() (v1Path string, v2Path string, err error) {
+	f, err := os.Open(r.procSelfCgroup)
+	if err != nil {
+		return "", "", err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// hierarchy-ID:controller-list:cgroup-path
+		parts := strings.SplitN(scanner.Text(), ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		if parts[0] == "0" && parts[1] == "" {
+			v2Path = parts[2]
+			continue
+		}
+		for _, controller := range strings.Split(parts[1], ",") {
+			if controller == "blkio" {
+				v1Path = parts[2]
+			}
+		}
+	}
+	return v1Path, v2Path, scanner.Err()
+}
+
+// cgroupDir returns the directory of the cgroup, or the root of the hierarchy when the
+// cgroup of the collector is mounted as the root, as in containers.
+func (r *cgroupIOReader) cgroupDir(controller, path string) string {
+	dir := filepath.Join(r.cgroupRoot, controller, path)
+	if _, err := os.Stat(dir); err != nil {
+		return filepath.Join(r.cgroupRoot, controller)
+	}
+	return dir
+}
+
+// readIOStat reads the cgroup v2 io.stat file, made of lines like
+// "8:0 rbytes=1024 wbytes=2048 rios=1 wios=2 dbytes=0 dios=0".
+func (r *cgroupIOReader) readIOStat(path string) (map[string]disk.IOCountersStat, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	ioCounters := make(map[string]disk.IOCountersStat)
+	for _, line := range strings.Split(string(content), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		device := r.deviceName(fields[0])
+		ioCounter := disk.IOCountersStat{Name: device}
+		for _, field := range fields[1:] {
+			kv := strings.SplitN(field, "=", 2)
+			if len(kv) != 2 {
+				continue
+			}
+			value, err := strconv.ParseUint(kv[1], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value %q in %s: %w", field, path, err)
+			}
+			switch kv[0] {
+			case "rbytes":
+				ioCounter.ReadBytes = value
+			case "wbytes":
+				ioCounter.WriteBytes = value
+			case "rios":
+				ioCounter.ReadCount = value
+			case "wios":
+				ioCounter.WriteCount = value
+			}
+		}
+		ioCounters[device] = ioCounter
+	}
+	return ioCounters, nil
+}
+
+// readBlkio reads the cgroup v1 blkio.throttle.io_service_bytes and blkio.throttle.io_serviced
+// files, made of lines like "8:0 Read 1024".
+func (r *cgroupIOReader) readBlkio(dir string) (map[string]disk.IOCountersStat, error) {
+	ioCounters := make(map[string]disk.IOCountersStat)
+	bytesFile := filepath.Join(dir, "blkio.throttle.io_service_bytes")
+	err := r.readBlkioFile(bytesFile, ioCounters, func(ioCounter *disk.IOCountersStat, op string, value uint64) {
+		switch op {
+		case "Read":
+			ioCounter.ReadBytes = value
+		case "Write":
+			ioCounter.WriteBytes = value
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	opsFile := filepath.Join(dir, "blkio.throttle.io_serviced")
+	err = r.readBlkioFile(opsFile, ioCounters, func(ioCounter *disk.IOCountersStat, op string, value uint64) {
+		switch op {
+		case "Read":
+			ioCounter.ReadCount = value
+		case "Write":
+			ioCounter.WriteCount = value
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ioCounters, nil
+}
+
+func (r *cgroupIOReader) readBlkioFile(path string, ioCounters map[string]disk.IOCountersStat, set func(*disk.IOCountersStat, string, uint64)) error {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	for _, line := range strings.Split(string(content), "\n") {
+		// The last line is the total of all the devices: "Total 3072".
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			continue
+		}
+		value, err := strconv.ParseUint(fields[2], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid value %q in %s: %w", line, path, err)
+		}
+		device := r.deviceName(fields[0])
+		ioCounter := ioCounters[device]
+		ioCounter.Name = device
+		set(&ioCounter, fields[1], value)
+		ioCounters[device] = ioCounter
+	}
+	return nil
+}
+
+// deviceName returns the name of the block device with the given "major:minor" numbers,
+// or the numbers if the device cannot be found.
+func (r *cgroupIOReader) deviceName(majorMinor string) string {
+	target, err := os.Readlink(filepath.Join(r.sysDevBlock, majorMinor))
+	if err != nil {
+		return majorMinor
+	}
+	return filepath.Base(target)
+}
@@ -0,0 +1,171 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build linux
+
+package diskscraper
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shirou/gopsutil/disk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/receiver/hostmetricsreceiver/internal/metadata"
+)
+
+// newTestCgroupIOReader returns a cgroupIOReader reading the given files, relative to a
+// temporary directory, with the block device 8:0 named sda.
+func newTestCgroupIOReader(t *testing.T, files map[string]string) *cgroupIOReader {
+	dir, err := ioutil.TempDir("", "cgroup")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+		require.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+	}
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "sys/dev/block"), 0700))
+	require.NoError(t, os.Symlink("../../devices/pci0000:00/block/sda", filepath.Join(dir, "sys/dev/block/8:0")))
+
+	return &cgroupIOReader{
+		procSelfCgroup: filepath.Join(dir, "proc/self/cgroup"),
+		cgroupRoot:     filepath.Join(dir, "sys/fs/cgroup"),
+		sysDevBlock:    filepath.Join(dir, "sys/dev/block"),
+	}
+}
+
+func TestCgroupIOCounters(t *testing.T) {
+	expected := map[string]disk.IOCountersStat{
+		"sda":  {Name: "sda", ReadBytes: 1024, WriteBytes: 2048, ReadCount: 1, WriteCount: 2},
+		"8:16": {Name: "8:16", ReadBytes: 4096, WriteBytes: 0, ReadCount: 4, WriteCount: 0},
+	}
+	ioStat := "8:0 rbytes=1024 wbytes=2048 rios=1 wios=2 dbytes=0 dios=0\n8:16 rbytes=4096 wbytes=0 rios=4 wios=0 dbytes=0 dios=0\n"
+	ioServiceBytes := "8:0 Read 1024\n8:0 Write 2048\n8:0 Sync 3072\n8:0 Async 0\n8:0 Total 3072\n8:16 Read 4096\n8:16 Write 0\nTotal 7168\n"
+	ioServiced := "8:0 Read 1\n8:0 Write 2\n8:0 Total 3\n8:16 Read 4\n8:16 Write 0\nTotal 7\n"
+
+	tests := []struct {
+		name  string
+		files map[string]string
+	}{
+		{
+			name: "v2",
+			files: map[string]string{
+				"proc/self/cgroup": "0::/system.slice/otelcol.service\n",
+				"sys/fs/cgroup/system.slice/otelcol.service/io.stat": ioStat,
+			},
+		},
+		{
+			name: "v2 namespace",
+			files: map[string]string{
+				"proc/self/cgroup":      "0::/kubepods/pod1/container1\n",
+				"sys/fs/cgroup/io.stat": ioStat,
+			},
+		},
+		{
+			name: "v1",
+			files: map[string]string{
+				"proc/self/cgroup": "12:cpu,cpuacct:/docker/container1\n4:blkio:/docker/container1\n0::/\n",
+				"sys/fs/cgroup/blkio/docker/container1/blkio.throttle.io_service_bytes": ioServiceBytes,
+				"sys/fs/cgroup/blkio/docker/container1/blkio.throttle.io_serviced":      ioServiced,
+			},
+		},
+		{
+			name: "v1 namespace",
+			files: map[string]string{
+				"proc/self/cgroup": "4:blkio:/docker/container1\n",
+				"sys/fs/cgroup/blkio/blkio.throttle.io_service_bytes": ioServiceBytes,
+				"sys/fs/cgroup/blkio/blkio.throttle.io_serviced":      ioServiced,
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := newTestCgroupIOReader(t, test.files)
+			ioCounters, err := r.ioCounters()
+			require.NoError(t, err)
+			assert.Equal(t, expected, ioCounters)
+		})
+	}
+}
+
+func TestCgroupIOCounters_Error(t *testing.T) {
+	tests := []struct {
+		name        string
+		files       map[string]string
+		expectedErr string
+	}{
+		{
+			name:        "No Cgroup File",
+			expectedErr: "no such file or directory",
+		},
+		{
+			name: "No IO Cgroup",
+			files: map[string]string{
+				"proc/self/cgroup": "12:cpu,cpuacct:/docker/container1\n",
+			},
+			expectedErr: "no io or blkio cgroup found for the collector",
+		},
+		{
+			name: "Invalid Value",
+			files: map[string]string{
+				"proc/self/cgroup":      "0::/\n",
+				"sys/fs/cgroup/io.stat": "8:0 rbytes=foo\n",
+			},
+			expectedErr: `invalid value "rbytes=foo"`,
+		},
+		{
+			name: "No Blkio File",
+			files: map[string]string{
+				"proc/self/cgroup": "4:blkio:/\n",
+			},
+			expectedErr: "blkio.throttle.io_service_bytes: no such file or directory",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := newTestCgroupIOReader(t, test.files)
+			_, err := r.ioCounters()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), test.expectedErr)
+		})
+	}
+}
+
+func TestScrape_Container(t *testing.T) {
+	scraper, err := newDiskScraper(context.Background(), &Config{Scope: scopeContainer})
+	require.NoError(t, err, "Failed to create disk scraper: %v", err)
+	scraper.ioCounters = newTestCgroupIOReader(t, map[string]string{
+		"proc/self/cgroup":      "0::/\n",
+		"sys/fs/cgroup/io.stat": "8:0 rbytes=1024 wbytes=2048 rios=1 wios=2 dbytes=0 dios=0\n",
+	}).ioCounters
+
+	err = scraper.start(context.Background(), componenttest.NewNopHost())
+	require.NoError(t, err, "Failed to initialize disk scraper: %v", err)
+
+	metrics, err := scraper.scrape(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, containerMetricsLen, metrics.Len())
+	assert.Equal(t, metadata.Metrics.SystemDiskIo.Name(), metrics.At(0).Name())
+	assert.Equal(t, metadata.Metrics.SystemDiskOperations.Name(), metrics.At(1).Name())
+	assert.Equal(t, int64(1024), metrics.At(0).IntSum().DataPoints().At(0).Value())
+	assert.Equal(t, int64(2), metrics.At(1).IntSum().DataPoints().At(1).Value())
+}
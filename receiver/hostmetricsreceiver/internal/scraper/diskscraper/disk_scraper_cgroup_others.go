@@ -0,0 +1,35 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !linux,!windows
+
+package diskscraper
+
+import (
+	"errors"
+
+	"github.com/shirou/gopsutil/disk"
+)
+
+// cgroupIOReader is only supported on Linux, the factory rejecting the container scope
+// on the other operating systems.
+type cgroupIOReader struct{}
+
+func newCgroupIOReader() *cgroupIOReader {
+	return &cgroupIOReader{}
+}
+
+func (r *cgroupIOReader) ioCounters(...string) (map[string]disk.IOCountersStat, error) {
+	return nil, errors.New("cgroups are only available on Linux")
+}
@@ -34,6 +34,9 @@ import (
 const (
 	standardMetricsLen = 5
 	metricsLen         = standardMetricsLen + systemSpecificMetricsLen
+
+	// cgroups only provide the bytes and operations counters.
+	containerMetricsLen = 2
 )
 
 // scraper for Disk Metrics
@@ -51,6 +54,9 @@ type scraper struct {
 // newDiskScraper creates a Disk Scraper
 func newDiskScraper(_ context.Context, cfg *Config) (*scraper, error) {
 	scraper := &scraper{config: cfg, bootTime: host.BootTime, ioCounters: disk.IOCounters}
+	if cfg.Scope == scopeContainer {
+		scraper.ioCounters = newCgroupIOReader().ioCounters
+	}
 
 	var err error
 
@@ -87,13 +93,20 @@ func (s *scraper) scrape(_ context.Context) (pdata.MetricSlice, error) {
 	now := pdata.TimestampFromTime(time.Now())
 	ioCounters, err := s.ioCounters()
 	if err != nil {
+		if s.config.Scope == scopeContainer {
+			return metrics, scrapererror.NewPartialScrapeError(err, containerMetricsLen)
+		}
 		return metrics, scrapererror.NewPartialScrapeError(err, metricsLen)
 	}
 
 	// filter devices by name
 	ioCounters = s.filterByDevice(ioCounters)
 
-	if len(ioCounters) > 0 {
+	if len(ioCounters) > 0 && s.config.Scope == scopeContainer {
+		metrics.Resize(containerMetricsLen)
+		initializeDiskIOMetric(metrics.At(0), s.startTime, now, ioCounters)
+		initializeDiskOperationsMetric(metrics.At(1), s.startTime, now, ioCounters)
+	} else if len(ioCounters) > 0 {
 		metrics.Resize(metricsLen)
 		initializeDiskIOMetric(metrics.At(0), s.startTime, now, ioCounters)
 		initializeDiskOperationsMetric(metrics.At(1), s.startTime, now, ioCounters)
@@ -16,6 +16,8 @@ package diskscraper
 
 import (
 	"context"
+	"errors"
+	"runtime"
 
 	"go.uber.org/zap"
 
@@ -28,6 +30,9 @@ import (
 const (
 	// The value of "type" key in configuration.
 	TypeStr = "disk"
+
+	scopeHost      = "host"
+	scopeContainer = "container"
 )
 
 // Factory is the Factory for scraper.
@@ -46,6 +51,16 @@ func (f *Factory) CreateMetricsScraper(
 	config internal.Config,
 ) (scraperhelper.MetricsScraper, error) {
 	cfg := config.(*Config)
+	switch cfg.Scope {
+	case "", scopeHost:
+	case scopeContainer:
+		if runtime.GOOS != "linux" {
+			return nil, errors.New("disk scraper container scope only available on Linux")
+		}
+	default:
+		return nil, errors.New("disk scraper scope must be either \"host\" or \"container\"")
+	}
+
 	s, err := newDiskScraper(ctx, cfg)
 	if err != nil {
 		return nil, err
@@ -38,6 +38,15 @@ func TestCreateMetricsScraper(t *testing.T) {
 	assert.NotNil(t, scraper)
 }
 
+func TestCreateMetricsScraper_ScopeError(t *testing.T) {
+	factory := &Factory{}
+	cfg := &Config{Scope: "pod"}
+
+	_, err := factory.CreateMetricsScraper(context.Background(), zap.NewNop(), cfg)
+
+	assert.EqualError(t, err, `disk scraper scope must be either "host" or "container"`)
+}
+
 func TestCreateMetricsScraper_Error(t *testing.T) {
 	factory := &Factory{}
 	cfg := &Config{Include: MatchConfig{Devices: []string{""}}}
@@ -27,6 +27,10 @@ type Config struct {
 	Include MatchConfig `mapstructure:"include"`
 	// Exclude specifies a filter on the network interfaces that should be excluded from the generated metrics.
 	Exclude MatchConfig `mapstructure:"exclude"`
+
+	// Scope is either "host" (default), the statistics of the host, or "container", the
+	// statistics of the network namespace of the collector, only supported on Linux.
+	Scope string `mapstructure:"scope"`
 }
 
 type MatchConfig struct {
@@ -16,6 +16,8 @@ package networkscraper
 
 import (
 	"context"
+	"errors"
+	"runtime"
 
 	"go.uber.org/zap"
 
@@ -28,6 +30,9 @@ import (
 const (
 	// The value of "type" key in configuration.
 	TypeStr = "network"
+
+	scopeHost      = "host"
+	scopeContainer = "container"
 )
 
 // Factory is the Factory for scraper.
@@ -46,6 +51,16 @@ func (f *Factory) CreateMetricsScraper(
 	config internal.Config,
 ) (scraperhelper.MetricsScraper, error) {
 	cfg := config.(*Config)
+	switch cfg.Scope {
+	case "", scopeHost:
+	case scopeContainer:
+		if runtime.GOOS != "linux" {
+			return nil, errors.New("network scraper container scope only available on Linux")
+		}
+	default:
+		return nil, errors.New("network scraper scope must be either \"host\" or \"container\"")
+	}
+
 	s, err := newNetworkScraper(ctx, cfg)
 	if err != nil {
 		return nil, err
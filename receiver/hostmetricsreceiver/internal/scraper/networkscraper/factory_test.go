@@ -46,3 +46,12 @@ func TestCreateMetricsScraper_Error(t *testing.T) {
 
 	assert.Error(t, err)
 }
+
+func TestCreateMetricsScraper_ScopeError(t *testing.T) {
+	factory := &Factory{}
+	cfg := &Config{Scope: "pod"}
+
+	_, err := factory.CreateMetricsScraper(context.Background(), zap.NewNop(), cfg)
+
+	assert.EqualError(t, err, `network scraper scope must be either "host" or "container"`)
+}
@@ -16,6 +16,14 @@
 
 package networkscraper
 
+import (
+	"bufio"
+	"os"
+	"strings"
+
+	"github.com/shirou/gopsutil/net"
+)
+
 var allTCPStates = []string{
 	"CLOSE_WAIT",
 	"CLOSE",
@@ -30,3 +38,55 @@ var allTCPStates = []string{
 	"SYN_RECV",
 	"TIME_WAIT",
 }
+
+// The files of the network namespace of the collector, read regardless of HOST_PROC.
+const (
+	procSelfNetDev  = "/proc/self/net/dev"
+	procSelfNetTCP  = "/proc/self/net/tcp"
+	procSelfNetTCP6 = "/proc/self/net/tcp6"
+)
+
+// containerIOCounters returns the counters of the network interfaces of the network
+// namespace of the collector.
+func containerIOCounters(pernic bool) ([]net.IOCountersStat, error) {
+	return net.IOCountersByFile(pernic, procSelfNetDev)
+}
+
+// containerConnections returns the TCP connections, with their status only, of the network
+// namespace of the collector.
+func containerConnections(string) ([]net.ConnectionStat, error) {
+	return readTCPConnections(procSelfNetTCP, procSelfNetTCP6)
+}
+
+// readTCPConnections reads the /proc/net/tcp formatted files, the status of a connection
+// being the hexadecimal "st" column.
+func readTCPConnections(paths ...string) ([]net.ConnectionStat, error) {
+	var connections []net.ConnectionStat
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if os.IsNotExist(err) {
+			// tcp6 is missing when IPv6 is disabled.
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		scanner := bufio.NewScanner(f)
+		// Skip the header line.
+		scanner.Scan()
+		for scanner.Scan() {
+			fields := strings.Fields(scanner.Text())
+			if len(fields) < 4 {
+				continue
+			}
+			connections = append(connections, net.ConnectionStat{Status: net.TCPStatuses[fields[3]]})
+		}
+		err = scanner.Err()
+		f.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return connections, nil
+}
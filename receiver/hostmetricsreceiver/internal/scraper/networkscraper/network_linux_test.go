@@ -0,0 +1,51 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build linux
+
+package networkscraper
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadTCPConnections(t *testing.T) {
+	dir, err := ioutil.TempDir("", "net")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	tcp := filepath.Join(dir, "tcp")
+	require.NoError(t, ioutil.WriteFile(tcp, []byte(
+		"  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode\n"+
+			"   0: 00000000:0016 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 16152 1 0000000000000000 100 0 0 10 0\n"+
+			"   1: 0100007F:A2C4 0100007F:0016 01 00000000:00000000 02:0000040A 00000000  1000        0 96262 2 0000000000000000 20 4 30 10 -1\n"), 0600))
+	tcp6 := filepath.Join(dir, "tcp6")
+	require.NoError(t, ioutil.WriteFile(tcp6, []byte(
+		"  sl  local_address                         remote_address                        st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode\n"+
+			"   0: 00000000000000000000000000000000:0016 00000000000000000000000000000000:0000 06 00000000:00000000 00:00000000 00000000     0        0 16154 1 0000000000000000 100 0 0 10 0\n"), 0600))
+
+	connections, err := readTCPConnections(tcp, tcp6, filepath.Join(dir, "missing"))
+	require.NoError(t, err)
+	statuses := make([]string, 0, len(connections))
+	for _, connection := range connections {
+		statuses = append(statuses, connection.Status)
+	}
+	assert.Equal(t, []string{"LISTEN", "ESTABLISHED", "TIME_WAIT"}, statuses)
+}
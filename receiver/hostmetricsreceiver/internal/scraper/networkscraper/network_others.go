@@ -16,6 +16,12 @@
 
 package networkscraper
 
+import (
+	"errors"
+
+	"github.com/shirou/gopsutil/net"
+)
+
 var allTCPStates = []string{
 	"CLOSE_WAIT",
 	"CLOSED",
@@ -30,3 +36,15 @@ var allTCPStates = []string{
 	"SYN_RECEIVED",
 	"TIME_WAIT",
 }
+
+// The container scope is only supported on Linux, the factory rejecting it on the other
+// operating systems.
+var errContainerScope = errors.New("network namespaces are only available on Linux")
+
+func containerIOCounters(bool) ([]net.IOCountersStat, error) {
+	return nil, errContainerScope
+}
+
+func containerConnections(string) ([]net.ConnectionStat, error) {
+	return nil, errContainerScope
+}
@@ -50,6 +50,10 @@ type scraper struct {
 // newNetworkScraper creates a set of Network related metrics
 func newNetworkScraper(_ context.Context, cfg *Config) (*scraper, error) {
 	scraper := &scraper{config: cfg, bootTime: host.BootTime, ioCounters: net.IOCounters, connections: net.Connections}
+	if cfg.Scope == scopeContainer {
+		scraper.ioCounters = containerIOCounters
+		scraper.connections = containerConnections
+	}
 
 	var err error
 
@@ -28,6 +28,13 @@ type Config struct {
 	// If neither `include` or `exclude` are set, process metrics will be generated for all processes.
 	Include MatchConfig `mapstructure:"include"`
 	Exclude MatchConfig `mapstructure:"exclude"`
+
+	// TopN limits the metrics to the N processes using the most resources, ranked by TopBy.
+	// If not set or 0, process metrics will be generated for all the processes.
+	TopN int `mapstructure:"top_n"`
+	// TopBy is the resource used to rank the processes when TopN is set: "cpu", the CPU
+	// time used since the previous scrape (default), or "memory", the physical memory usage.
+	TopBy string `mapstructure:"top_by"`
 }
 
 type MatchConfig struct {
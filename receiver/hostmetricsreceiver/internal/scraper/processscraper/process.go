@@ -106,6 +106,8 @@ type processHandle interface {
 	Times() (*cpu.TimesStat, error)
 	MemoryInfo() (*process.MemoryInfoStat, error)
 	IOCounters() (*process.IOCountersStat, error)
+	NumThreads() (int32, error)
+	NumFDs() (int32, error)
 }
 
 type gopsProcessHandles struct {
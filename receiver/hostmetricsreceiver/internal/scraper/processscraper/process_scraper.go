@@ -16,7 +16,9 @@ package processscraper
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/shirou/gopsutil/cpu"
@@ -31,11 +33,22 @@ import (
 )
 
 const (
-	cpuMetricsLen    = 1
-	memoryMetricsLen = 2
-	diskMetricsLen   = 1
+	cpuMetricsLen     = 1
+	memoryMetricsLen  = 2
+	diskMetricsLen    = 1
+	threadsMetricsLen = 1
 
-	metricsLen = cpuMetricsLen + memoryMetricsLen + diskMetricsLen
+	metricsLen = cpuMetricsLen + memoryMetricsLen + diskMetricsLen + threadsMetricsLen + fdMetricsLen
+)
+
+const (
+	topByCPU    = "cpu"
+	topByMemory = "memory"
+)
+
+var (
+	errNegativeTopN = errors.New("\"top_n\" cannot be negative")
+	errInvalidTopBy = errors.New("\"top_by\" must be either \"cpu\" or \"memory\"")
 )
 
 // scraper for Process Metrics
@@ -45,6 +58,10 @@ type scraper struct {
 	includeFS filterset.FilterSet
 	excludeFS filterset.FilterSet
 
+	// cpuTimes are the total CPU times of the processes at the previous scrape, by pid,
+	// used to rank the processes when top_n is set.
+	cpuTimes map[int32]float64
+
 	// for mocking
 	bootTime          func() (uint64, error)
 	getProcessHandles func() (processHandles, error)
@@ -56,6 +73,13 @@ func newProcessScraper(cfg *Config) (*scraper, error) {
 
 	var err error
 
+	if cfg.TopN < 0 {
+		return nil, errNegativeTopN
+	}
+	if cfg.TopBy != "" && cfg.TopBy != topByCPU && cfg.TopBy != topByMemory {
+		return nil, errInvalidTopBy
+	}
+
 	if len(cfg.Include.Names) > 0 {
 		scraper.includeFS, err = filterset.CreateFilterSet(cfg.Include.Names, &cfg.Include.Config)
 		if err != nil {
@@ -98,6 +122,10 @@ func (s *scraper) scrape(_ context.Context) (pdata.ResourceMetricsSlice, error)
 		errs.AddPartial(partialErr.Failed, partialErr)
 	}
 
+	if s.config.TopN > 0 {
+		metadata = s.topProcesses(metadata)
+	}
+
 	rms.Resize(len(metadata))
 	for i, md := range metadata {
 		rm := rms.At(i)
@@ -120,6 +148,14 @@ func (s *scraper) scrape(_ context.Context) (pdata.ResourceMetricsSlice, error)
 		if err = scrapeAndAppendDiskIOMetric(metrics, s.startTime, now, md.handle); err != nil {
 			errs.AddPartial(diskMetricsLen, fmt.Errorf("error reading disk usage for process %q (pid %v): %w", md.executable.name, md.pid, err))
 		}
+
+		if err = scrapeAndAppendThreadsMetric(metrics, now, md.handle); err != nil {
+			errs.AddPartial(threadsMetricsLen, fmt.Errorf("error reading thread count for process %q (pid %v): %w", md.executable.name, md.pid, err))
+		}
+
+		if err = scrapeAndAppendOpenFileDescriptorsMetric(metrics, now, md.handle); err != nil {
+			errs.AddPartial(fdMetricsLen, fmt.Errorf("error reading open file descriptors for process %q (pid %v): %w", md.executable.name, md.pid, err))
+		}
 	}
 
 	return rms, errs.Combine()
@@ -178,6 +214,45 @@ func (s *scraper) getProcessMetadata() ([]*processMetadata, error) {
 	return metadata, errs.Combine()
 }
 
+// topProcesses returns the top_n processes using the most CPU time since the previous
+// scrape, or the most physical memory, the processes whose usage cannot be read being
+// ranked last.
+func (s *scraper) topProcesses(metadata []*processMetadata) []*processMetadata {
+	usages := make(map[*processMetadata]float64, len(metadata))
+	if s.config.TopBy == topByMemory {
+		for _, md := range metadata {
+			if mem, err := md.handle.MemoryInfo(); err == nil {
+				usages[md] = float64(mem.RSS)
+			}
+		}
+	} else {
+		cpuTimes := make(map[int32]float64, len(metadata))
+		for _, md := range metadata {
+			times, err := md.handle.Times()
+			if err != nil {
+				continue
+			}
+			total := times.User + times.System
+			cpuTimes[md.pid] = total
+			// A lower total than at the previous scrape means that the pid has been reused.
+			if prev, ok := s.cpuTimes[md.pid]; ok && prev <= total {
+				usages[md] = total - prev
+			} else {
+				usages[md] = total
+			}
+		}
+		s.cpuTimes = cpuTimes
+	}
+
+	sort.SliceStable(metadata, func(i, j int) bool {
+		return usages[metadata[i]] > usages[metadata[j]]
+	})
+	if len(metadata) > s.config.TopN {
+		metadata = metadata[:s.config.TopN]
+	}
+	return metadata
+}
+
 func scrapeAndAppendCPUTimeMetric(metrics pdata.MetricSlice, startTime, now pdata.Timestamp, handle processHandle) error {
 	times, err := handle.Times()
 	if err != nil {
@@ -224,6 +299,27 @@ func initializeMemoryUsageDataPoint(dataPoint pdata.IntDataPoint, now pdata.Time
 	dataPoint.SetValue(usage)
 }
 
+func scrapeAndAppendThreadsMetric(metrics pdata.MetricSlice, now pdata.Timestamp, handle processHandle) error {
+	threads, err := handle.NumThreads()
+	if err != nil {
+		return err
+	}
+
+	startIdx := metrics.Len()
+	metrics.Resize(startIdx + threadsMetricsLen)
+	initializeCountMetric(metrics.At(startIdx), metadata.Metrics.ProcessThreads, now, int64(threads))
+	return nil
+}
+
+func initializeCountMetric(metric pdata.Metric, metricIntf metadata.MetricIntf, now pdata.Timestamp, count int64) {
+	metricIntf.Init(metric)
+
+	idps := metric.IntSum().DataPoints()
+	idps.Resize(1)
+	idps.At(0).SetTimestamp(now)
+	idps.At(0).SetValue(count)
+}
+
 func scrapeAndAppendDiskIOMetric(metrics pdata.MetricSlice, startTime, now pdata.Timestamp, handle processHandle) error {
 	io, err := handle.IOCounters()
 	if err != nil {
@@ -23,7 +23,10 @@ import (
 	"go.opentelemetry.io/collector/receiver/hostmetricsreceiver/internal/metadata"
 )
 
-const cpuStatesLen = 3
+const (
+	cpuStatesLen = 3
+	fdMetricsLen = 1
+)
 
 func appendCPUTimeStateDataPoints(ddps pdata.DoubleDataPointSlice, startTime, now pdata.Timestamp, cpuTime *cpu.TimesStat) {
 	initializeCPUTimeDataPoint(ddps.At(0), startTime, now, cpuTime.User, metadata.LabelProcessState.User)
@@ -68,3 +71,15 @@ func getProcessCommand(proc processHandle) (*commandMetadata, error) {
 	command := &commandMetadata{command: cmd, commandLineSlice: cmdline}
 	return command, nil
 }
+
+func scrapeAndAppendOpenFileDescriptorsMetric(metrics pdata.MetricSlice, now pdata.Timestamp, handle processHandle) error {
+	fds, err := handle.NumFDs()
+	if err != nil {
+		return err
+	}
+
+	startIdx := metrics.Len()
+	metrics.Resize(startIdx + fdMetricsLen)
+	initializeCountMetric(metrics.At(startIdx), metadata.Metrics.ProcessOpenFileDescriptors, now, int64(fds))
+	return nil
+}
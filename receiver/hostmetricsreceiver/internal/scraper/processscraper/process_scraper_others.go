@@ -22,7 +22,11 @@ import (
 	"go.opentelemetry.io/collector/consumer/pdata"
 )
 
-const cpuStatesLen = 0
+const (
+	cpuStatesLen = 0
+	// The number of open file descriptors is only available on Linux.
+	fdMetricsLen = 0
+)
 
 func appendCPUTimeStateDataPoints(ddps pdata.DoubleDataPointSlice, startTime, now pdata.Timestamp, cpuTime *cpu.TimesStat) {
 }
@@ -34,3 +38,7 @@ func getProcessExecutable(processHandle) (*executableMetadata, error) {
 func getProcessCommand(processHandle) (*commandMetadata, error) {
 	return nil, nil
 }
+
+func scrapeAndAppendOpenFileDescriptorsMetric(pdata.MetricSlice, pdata.Timestamp, processHandle) error {
+	return nil
+}
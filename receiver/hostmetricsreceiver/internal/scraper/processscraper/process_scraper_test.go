@@ -75,6 +75,10 @@ func TestScrape(t *testing.T) {
 	assertMemoryUsageMetricValid(t, metadata.Metrics.ProcessMemoryPhysicalUsage.New(), resourceMetrics)
 	assertMemoryUsageMetricValid(t, metadata.Metrics.ProcessMemoryVirtualUsage.New(), resourceMetrics)
 	assertDiskIOMetricValid(t, resourceMetrics, expectedStartTime)
+	assertCountMetricValid(t, metadata.Metrics.ProcessThreads.New(), resourceMetrics)
+	if runtime.GOOS == "linux" {
+		assertCountMetricValid(t, metadata.Metrics.ProcessOpenFileDescriptors.New(), resourceMetrics)
+	}
 	assertSameTimeStampForAllMetricsWithinResource(t, resourceMetrics)
 }
 
@@ -118,6 +122,11 @@ func assertDiskIOMetricValid(t *testing.T, resourceMetrics pdata.ResourceMetrics
 	internal.AssertIntSumMetricLabelHasValue(t, diskIOMetric, 1, "direction", "write")
 }
 
+func assertCountMetricValid(t *testing.T, descriptor pdata.Metric, resourceMetrics pdata.ResourceMetricsSlice) {
+	countMetric := getMetric(t, descriptor, resourceMetrics)
+	internal.AssertDescriptorEqual(t, descriptor, countMetric)
+}
+
 func assertSameTimeStampForAllMetricsWithinResource(t *testing.T, resourceMetrics pdata.ResourceMetricsSlice) {
 	for i := 0; i < resourceMetrics.Len(); i++ {
 		ilms := resourceMetrics.At(i).InstrumentationLibraryMetrics()
@@ -158,6 +167,12 @@ func TestScrapeMetrics_NewError(t *testing.T) {
 	_, err = newProcessScraper(&Config{Exclude: MatchConfig{Names: []string{"test"}}})
 	require.Error(t, err)
 	require.Regexp(t, "^error creating process exclude filters:", err.Error())
+
+	_, err = newProcessScraper(&Config{TopN: -1})
+	assert.Equal(t, errNegativeTopN, err)
+
+	_, err = newProcessScraper(&Config{TopN: 1, TopBy: "disk"})
+	assert.Equal(t, errInvalidTopBy, err)
 }
 
 func TestScrapeMetrics_GetProcessesError(t *testing.T) {
@@ -179,9 +194,14 @@ func TestScrapeMetrics_GetProcessesError(t *testing.T) {
 
 type processHandlesMock struct {
 	handles []*processHandleMock
+	// pids are the pids of the handles, 1 if not set.
+	pids []int32
 }
 
 func (p *processHandlesMock) Pid(index int) int32 {
+	if p.pids != nil {
+		return p.pids[index]
+	}
 	return 1
 }
 
@@ -237,6 +257,16 @@ func (p *processHandleMock) IOCounters() (*process.IOCountersStat, error) {
 	return args.Get(0).(*process.IOCountersStat), args.Error(1)
 }
 
+func (p *processHandleMock) NumThreads() (int32, error) {
+	args := p.MethodCalled("NumThreads")
+	return args.Get(0).(int32), args.Error(1)
+}
+
+func (p *processHandleMock) NumFDs() (int32, error) {
+	args := p.MethodCalled("NumFDs")
+	return args.Get(0).(int32), args.Error(1)
+}
+
 func newDefaultHandleMock() *processHandleMock {
 	handleMock := &processHandleMock{}
 	handleMock.On("Username").Return("username", nil)
@@ -245,6 +275,8 @@ func newDefaultHandleMock() *processHandleMock {
 	handleMock.On("Times").Return(&cpu.TimesStat{}, nil)
 	handleMock.On("MemoryInfo").Return(&process.MemoryInfoStat{}, nil)
 	handleMock.On("IOCounters").Return(&process.IOCountersStat{}, nil)
+	handleMock.On("NumThreads").Return(int32(0), nil)
+	handleMock.On("NumFDs").Return(int32(0), nil)
 	return handleMock
 }
 
@@ -340,6 +372,72 @@ func TestScrapeMetrics_Filtered(t *testing.T) {
 	}
 }
 
+func TestScrapeMetrics_TopN(t *testing.T) {
+	skipTestOnUnsupportedOS(t)
+
+	newHandleMock := func(name string, cpuTime float64, rss uint64) *processHandleMock {
+		handleMock := &processHandleMock{}
+		handleMock.On("Name").Return(name, nil)
+		handleMock.On("Exe").Return(name, nil)
+		handleMock.On("Username").Return("username", nil)
+		handleMock.On("Cmdline").Return("cmdline", nil)
+		handleMock.On("CmdlineSlice").Return([]string{"cmdline"}, nil)
+		handleMock.On("Times").Return(&cpu.TimesStat{User: cpuTime}, nil)
+		handleMock.On("MemoryInfo").Return(&process.MemoryInfoStat{RSS: rss}, nil)
+		handleMock.On("IOCounters").Return(&process.IOCountersStat{}, nil)
+		handleMock.On("NumThreads").Return(int32(0), nil)
+		handleMock.On("NumFDs").Return(int32(0), nil)
+		return handleMock
+	}
+	scrapedNames := func(t *testing.T, s *scraper) []string {
+		resourceMetrics, err := s.scrape(context.Background())
+		require.NoError(t, err)
+		names := make([]string, 0, resourceMetrics.Len())
+		for i := 0; i < resourceMetrics.Len(); i++ {
+			name, _ := resourceMetrics.At(i).Resource().Attributes().Get(conventions.AttributeProcessExecutableName)
+			names = append(names, name.StringVal())
+		}
+		return names
+	}
+
+	t.Run("cpu", func(t *testing.T) {
+		scraper, err := newProcessScraper(&Config{TopN: 2})
+		require.NoError(t, err)
+		require.NoError(t, scraper.start(context.Background(), componenttest.NewNopHost()))
+
+		scraper.getProcessHandles = func() (processHandles, error) {
+			return &processHandlesMock{
+				handles: []*processHandleMock{newHandleMock("test1", 10, 0), newHandleMock("test2", 30, 0), newHandleMock("test3", 20, 0)},
+				pids:    []int32{1, 2, 3},
+			}, nil
+		}
+		assert.Equal(t, []string{"test2", "test3"}, scrapedNames(t, scraper))
+
+		// Ranked by the CPU time used since the previous scrape.
+		scraper.getProcessHandles = func() (processHandles, error) {
+			return &processHandlesMock{
+				handles: []*processHandleMock{newHandleMock("test1", 25, 0), newHandleMock("test2", 31, 0), newHandleMock("test3", 22, 0)},
+				pids:    []int32{1, 2, 3},
+			}, nil
+		}
+		assert.Equal(t, []string{"test1", "test3"}, scrapedNames(t, scraper))
+	})
+
+	t.Run("memory", func(t *testing.T) {
+		scraper, err := newProcessScraper(&Config{TopN: 1, TopBy: "memory"})
+		require.NoError(t, err)
+		require.NoError(t, scraper.start(context.Background(), componenttest.NewNopHost()))
+
+		scraper.getProcessHandles = func() (processHandles, error) {
+			return &processHandlesMock{
+				handles: []*processHandleMock{newHandleMock("test1", 30, 100), newHandleMock("test2", 10, 200)},
+				pids:    []int32{1, 2},
+			}, nil
+		}
+		assert.Equal(t, []string{"test2"}, scrapedNames(t, scraper))
+	})
+}
+
 func TestScrapeMetrics_ProcessErrors(t *testing.T) {
 	skipTestOnUnsupportedOS(t)
 
@@ -353,6 +451,8 @@ func TestScrapeMetrics_ProcessErrors(t *testing.T) {
 		timesError      error
 		memoryInfoError error
 		ioCountersError error
+		numThreadsError error
+		numFDsError     error
 		expectedError   string
 	}
 
@@ -393,6 +493,17 @@ func TestScrapeMetrics_ProcessErrors(t *testing.T) {
 			ioCountersError: errors.New("err6"),
 			expectedError:   `error reading disk usage for process "test" (pid 1): err6`,
 		},
+		{
+			name:            "Num Threads Error",
+			numThreadsError: errors.New("err7"),
+			expectedError:   `error reading thread count for process "test" (pid 1): err7`,
+		},
+		{
+			name:          "Num FDs Error",
+			osFilter:      "windows",
+			numFDsError:   errors.New("err8"),
+			expectedError: `error reading open file descriptors for process "test" (pid 1): err8`,
+		},
 		{
 			name:            "Multiple Errors",
 			cmdlineError:    errors.New("err2"),
@@ -400,11 +511,13 @@ func TestScrapeMetrics_ProcessErrors(t *testing.T) {
 			timesError:      errors.New("err4"),
 			memoryInfoError: errors.New("err5"),
 			ioCountersError: errors.New("err6"),
+			numThreadsError: errors.New("err7"),
 			expectedError: `[[error reading command for process "test" (pid 1): err2; ` +
 				`error reading username for process "test" (pid 1): err3]; ` +
 				`error reading cpu times for process "test" (pid 1): err4; ` +
 				`error reading memory info for process "test" (pid 1): err5; ` +
-				`error reading disk usage for process "test" (pid 1): err6]`,
+				`error reading disk usage for process "test" (pid 1): err6; ` +
+				`error reading thread count for process "test" (pid 1): err7]`,
 		},
 	}
 
@@ -433,6 +546,8 @@ func TestScrapeMetrics_ProcessErrors(t *testing.T) {
 			handleMock.On("Times").Return(&cpu.TimesStat{}, test.timesError)
 			handleMock.On("MemoryInfo").Return(&process.MemoryInfoStat{}, test.memoryInfoError)
 			handleMock.On("IOCounters").Return(&process.IOCountersStat{}, test.ioCountersError)
+			handleMock.On("NumThreads").Return(int32(0), test.numThreadsError)
+			handleMock.On("NumFDs").Return(int32(0), test.numFDsError)
 
 			scraper.getProcessHandles = func() (processHandles, error) {
 				return &processHandlesMock{handles: []*processHandleMock{handleMock}}, nil
@@ -442,7 +557,7 @@ func TestScrapeMetrics_ProcessErrors(t *testing.T) {
 
 			md := pdata.NewMetrics()
 			resourceMetrics.MoveAndAppendTo(md.ResourceMetrics())
-			expectedResourceMetricsLen, expectedMetricsLen := getExpectedLengthOfReturnedMetrics(test.nameError, test.exeError, test.timesError, test.memoryInfoError, test.ioCountersError)
+			expectedResourceMetricsLen, expectedMetricsLen := getExpectedLengthOfReturnedMetrics(test.nameError, test.exeError, test.timesError, test.memoryInfoError, test.ioCountersError, test.numThreadsError, test.numFDsError)
 			assert.Equal(t, expectedResourceMetricsLen, md.ResourceMetrics().Len())
 			assert.Equal(t, expectedMetricsLen, md.MetricCount())
 
@@ -450,14 +565,14 @@ func TestScrapeMetrics_ProcessErrors(t *testing.T) {
 			isPartial := scrapererror.IsPartialScrapeError(err)
 			assert.True(t, isPartial)
 			if isPartial {
-				expectedFailures := getExpectedScrapeFailures(test.nameError, test.exeError, test.timesError, test.memoryInfoError, test.ioCountersError)
+				expectedFailures := getExpectedScrapeFailures(test.nameError, test.exeError, test.timesError, test.memoryInfoError, test.ioCountersError, test.numThreadsError, test.numFDsError)
 				assert.Equal(t, expectedFailures, err.(scrapererror.PartialScrapeError).Failed)
 			}
 		})
 	}
 }
 
-func getExpectedLengthOfReturnedMetrics(nameError, exeError, timeError, memError, diskError error) (int, int) {
+func getExpectedLengthOfReturnedMetrics(nameError, exeError, timeError, memError, diskError, threadsError, fdError error) (int, int) {
 	if nameError != nil || exeError != nil {
 		return 0, 0
 	}
@@ -472,11 +587,17 @@ func getExpectedLengthOfReturnedMetrics(nameError, exeError, timeError, memError
 	if diskError == nil {
 		expectedLen += diskMetricsLen
 	}
+	if threadsError == nil {
+		expectedLen += threadsMetricsLen
+	}
+	if fdError == nil {
+		expectedLen += fdMetricsLen
+	}
 	return 1, expectedLen
 }
 
-func getExpectedScrapeFailures(nameError, exeError, timeError, memError, diskError error) int {
-	expectedResourceMetricsLen, expectedMetricsLen := getExpectedLengthOfReturnedMetrics(nameError, exeError, timeError, memError, diskError)
+func getExpectedScrapeFailures(nameError, exeError, timeError, memError, diskError, threadsError, fdError error) int {
+	expectedResourceMetricsLen, expectedMetricsLen := getExpectedLengthOfReturnedMetrics(nameError, exeError, timeError, memError, diskError, threadsError, fdError)
 	if expectedResourceMetricsLen == 0 {
 		return 1
 	}
@@ -26,7 +26,11 @@ import (
 	"go.opentelemetry.io/collector/receiver/hostmetricsreceiver/internal/metadata"
 )
 
-const cpuStatesLen = 2
+const (
+	cpuStatesLen = 2
+	// The number of open file descriptors is only available on Linux.
+	fdMetricsLen = 0
+)
 
 func appendCPUTimeStateDataPoints(ddps pdata.DoubleDataPointSlice, startTime, now pdata.Timestamp, cpuTime *cpu.TimesStat) {
 	initializeCPUTimeDataPoint(ddps.At(0), startTime, now, cpuTime.User, metadata.LabelProcessState.User)
@@ -70,3 +74,7 @@ func getProcessCommand(proc processHandle) (*commandMetadata, error) {
 	command := &commandMetadata{command: cmd, commandLine: cmdline}
 	return command, nil
 }
+
+func scrapeAndAppendOpenFileDescriptorsMetric(pdata.MetricSlice, pdata.Timestamp, processHandle) error {
+	return nil
+}
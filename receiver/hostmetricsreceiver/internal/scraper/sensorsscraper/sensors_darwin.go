@@ -0,0 +1,36 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build darwin
+
+package sensorsscraper
+
+import (
+	"github.com/shirou/gopsutil/host"
+)
+
+// readSensors reads the temperatures of the System Management Controller, the only
+// sensors available on macOS, which require the collector to be compiled with cgo.
+func readSensors() (*sensorsStat, error) {
+	temperatures, err := host.SensorsTemperatures()
+	if err != nil {
+		return nil, err
+	}
+
+	stat := &sensorsStat{temperatures: make([]sensorReading, 0, len(temperatures))}
+	for _, temperature := range temperatures {
+		stat.temperatures = append(stat.temperatures, sensorReading{chip: "smc", sensor: temperature.SensorKey, value: temperature.Temperature})
+	}
+	return stat, nil
+}
@@ -0,0 +1,225 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build linux
+
+package sensorsscraper
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// hwmonInput matches the files of the hwmon sysfs interface read by the scraper, such as
+// "temp1_input", "fan2_input" or "power1_average".
+var hwmonInput = regexp.MustCompile(`^(temp|fan|power)([0-9]+)_(input|average)$`)
+
+// sysfsReader reads the hwmon sensors and the batteries from sysfs, rooted at HOST_SYS
+// when set, as for the other scrapers.
+type sysfsReader struct {
+	root string
+}
+
+func readSensors() (*sensorsStat, error) {
+	root := os.Getenv("HOST_SYS")
+	if root == "" {
+		root = "/sys"
+	}
+	return (&sysfsReader{root: root}).read()
+}
+
+func (r *sysfsReader) read() (*sensorsStat, error) {
+	stat := &sensorsStat{}
+	if err := r.readHwmon(stat); err != nil {
+		return nil, err
+	}
+	if err := r.readBatteries(stat); err != nil {
+		return nil, err
+	}
+	return stat, nil
+}
+
+// hwmonSensor identifies an input of a hwmon device, such as temp1.
+type hwmonSensor struct {
+	kind  string
+	index int
+}
+
+// readHwmon reads the temperature, fan and power inputs of the hwmon devices, the thermal
+// zones being exposed as hwmon devices as well. The devices with the same name, such as
+// several NVMe drives, are suffixed with their rank: "nvme", "nvme_1", ...
+func (r *sysfsReader) readHwmon(stat *sensorsStat) error {
+	dirs, err := readDir(filepath.Join(r.root, "class", "hwmon"))
+	if err != nil {
+		return err
+	}
+
+	chips := make(map[string]int)
+	for _, dir := range dirs {
+		// Older kernels expose the attributes in the device directory.
+		if _, err := os.Stat(filepath.Join(dir, "name")); err != nil {
+			dir = filepath.Join(dir, "device")
+		}
+		name, err := readString(filepath.Join(dir, "name"))
+		if err != nil {
+			continue
+		}
+		chip := name
+		if n := chips[name]; n > 0 {
+			chip = fmt.Sprintf("%s_%d", name, n)
+		}
+		chips[name]++
+
+		files, err := ioutil.ReadDir(dir)
+		if err != nil {
+			return err
+		}
+		values := make(map[hwmonSensor]float64)
+		for _, file := range files {
+			match := hwmonInput.FindStringSubmatch(file.Name())
+			if match == nil {
+				continue
+			}
+			sensor := hwmonSensor{kind: match[1]}
+			sensor.index, _ = strconv.Atoi(match[2])
+			// The average power is only used when the instantaneous power is not available.
+			if _, ok := values[sensor]; ok && match[3] == "average" {
+				continue
+			}
+			// Some drivers report an error for the disconnected sensors.
+			value, err := readFloat(filepath.Join(dir, file.Name()))
+			if err != nil {
+				continue
+			}
+			values[sensor] = value
+		}
+
+		sensors := make([]hwmonSensor, 0, len(values))
+		for sensor := range values {
+			sensors = append(sensors, sensor)
+		}
+		sort.Slice(sensors, func(i, j int) bool {
+			if sensors[i].kind != sensors[j].kind {
+				return sensors[i].kind < sensors[j].kind
+			}
+			return sensors[i].index < sensors[j].index
+		})
+		for _, sensor := range sensors {
+			reading := sensorReading{chip: chip, sensor: sensorLabel(dir, sensor), value: values[sensor]}
+			switch sensor.kind {
+			case "temp":
+				// millidegree Celsius
+				reading.value /= 1e3
+				stat.temperatures = append(stat.temperatures, reading)
+			case "fan":
+				stat.fanSpeeds = append(stat.fanSpeeds, reading)
+			case "power":
+				// microwatt
+				reading.value /= 1e6
+				stat.power = append(stat.power, reading)
+			}
+		}
+	}
+	return nil
+}
+
+// sensorLabel returns the label of the sensor set by the driver, such as "Core 0", or the
+// name of the input, such as "temp1".
+func sensorLabel(dir string, sensor hwmonSensor) string {
+	name := fmt.Sprintf("%s%d", sensor.kind, sensor.index)
+	if label, err := readString(filepath.Join(dir, name+"_label")); err == nil && label != "" {
+		return label
+	}
+	return name
+}
+
+// readBatteries reads the charge and the power of the batteries of the power supply class.
+func (r *sysfsReader) readBatteries(stat *sensorsStat) error {
+	dirs, err := readDir(filepath.Join(r.root, "class", "power_supply"))
+	if err != nil {
+		return err
+	}
+
+	for _, dir := range dirs {
+		if supplyType, err := readString(filepath.Join(dir, "type")); err != nil || supplyType != "Battery" {
+			continue
+		}
+		battery := filepath.Base(dir)
+		if capacity, err := readFloat(filepath.Join(dir, "capacity")); err == nil {
+			stat.batteryCharges = append(stat.batteryCharges, batteryReading{battery: battery, value: capacity})
+		}
+		if power, ok := batteryPower(dir); ok {
+			stat.batteryPower = append(stat.batteryPower, batteryReading{battery: battery, value: power})
+		}
+	}
+	return nil
+}
+
+// batteryPower returns the power of the battery in watts, computed from its current and
+// voltage for the drivers not reporting the power.
+func batteryPower(dir string) (float64, bool) {
+	if power, err := readFloat(filepath.Join(dir, "power_now")); err == nil {
+		// microwatt
+		return power / 1e6, true
+	}
+	current, err := readFloat(filepath.Join(dir, "current_now"))
+	if err != nil {
+		return 0, false
+	}
+	voltage, err := readFloat(filepath.Join(dir, "voltage_now"))
+	if err != nil {
+		return 0, false
+	}
+	// microampere and microvolt
+	return current * voltage / 1e12, true
+}
+
+// readDir returns the paths of the entries of the directory, none if it does not exist.
+func readDir(dir string) ([]string, error) {
+	files, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0, len(files))
+	for _, file := range files {
+		paths = append(paths, filepath.Join(dir, file.Name()))
+	}
+	return paths, nil
+}
+
+func readString(path string) (string, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(content)), nil
+}
+
+func readFloat(path string) (float64, error) {
+	content, err := readString(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseFloat(content, 64)
+}
@@ -0,0 +1,109 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build linux
+
+package sensorsscraper
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeSysfsFiles(t *testing.T, root string, files map[string]string) {
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+		require.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+	}
+}
+
+func TestSysfsReader(t *testing.T) {
+	root, err := ioutil.TempDir("", "sys")
+	require.NoError(t, err)
+	defer os.RemoveAll(root)
+
+	writeSysfsFiles(t, root, map[string]string{
+		"class/hwmon/hwmon0/name":              "acpitz\n",
+		"class/hwmon/hwmon0/temp1_input":       "27800\n",
+		"class/hwmon/hwmon1/name":              "coretemp\n",
+		"class/hwmon/hwmon1/temp1_input":       "45000\n",
+		"class/hwmon/hwmon1/temp1_label":       "Package id 0\n",
+		"class/hwmon/hwmon1/temp2_input":       "42500\n",
+		"class/hwmon/hwmon1/temp2_label":       "Core 0\n",
+		"class/hwmon/hwmon1/temp10_input":      "43000\n",
+		"class/hwmon/hwmon1/temp2_max":         "100000\n",
+		"class/hwmon/hwmon2/device/name":       "thinkpad\n",
+		"class/hwmon/hwmon2/device/fan1_input": "2843\n",
+		"class/hwmon/hwmon2/device/fan2_input": "invalid\n",
+		"class/hwmon/hwmon3/name":              "amdgpu\n",
+		"class/hwmon/hwmon3/power1_average":    "11000000\n",
+		"class/hwmon/hwmon3/power1_input":      "12250000\n",
+		"class/hwmon/hwmon3/power1_label":      "PPT\n",
+		"class/hwmon/hwmon3/power2_average":    "3500000\n",
+		"class/hwmon/hwmon4/name":              "acpitz\n",
+		"class/hwmon/hwmon4/temp1_input":       "30000\n",
+		"class/power_supply/AC/type":           "Mains\n",
+		"class/power_supply/AC/online":         "1\n",
+		"class/power_supply/BAT0/type":         "Battery\n",
+		"class/power_supply/BAT0/capacity":     "87\n",
+		"class/power_supply/BAT0/power_now":    "6200000\n",
+		"class/power_supply/BAT1/type":         "Battery\n",
+		"class/power_supply/BAT1/capacity":     "50\n",
+		"class/power_supply/BAT1/current_now":  "500000\n",
+		"class/power_supply/BAT1/voltage_now":  "12000000\n",
+	})
+
+	stat, err := (&sysfsReader{root: root}).read()
+	require.NoError(t, err)
+	assert.Equal(t, &sensorsStat{
+		temperatures: []sensorReading{
+			{chip: "acpitz", sensor: "temp1", value: 27.8},
+			{chip: "coretemp", sensor: "Package id 0", value: 45},
+			{chip: "coretemp", sensor: "Core 0", value: 42.5},
+			{chip: "coretemp", sensor: "temp10", value: 43},
+			{chip: "acpitz_1", sensor: "temp1", value: 30},
+		},
+		fanSpeeds: []sensorReading{
+			{chip: "thinkpad", sensor: "fan1", value: 2843},
+		},
+		power: []sensorReading{
+			{chip: "amdgpu", sensor: "PPT", value: 12.25},
+			{chip: "amdgpu", sensor: "power2", value: 3.5},
+		},
+		batteryCharges: []batteryReading{
+			{battery: "BAT0", value: 87},
+			{battery: "BAT1", value: 50},
+		},
+		batteryPower: []batteryReading{
+			{battery: "BAT0", value: 6.2},
+			{battery: "BAT1", value: 6},
+		},
+	}, stat)
+}
+
+func TestSysfsReader_NoSensors(t *testing.T) {
+	root, err := ioutil.TempDir("", "sys")
+	require.NoError(t, err)
+	defer os.RemoveAll(root)
+
+	stat, err := (&sysfsReader{root: root}).read()
+	require.NoError(t, err)
+	assert.Equal(t, &sensorsStat{}, stat)
+}
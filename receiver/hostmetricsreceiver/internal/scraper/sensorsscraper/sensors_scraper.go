@@ -0,0 +1,144 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sensorsscraper
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/receiver/hostmetricsreceiver/internal/metadata"
+	"go.opentelemetry.io/collector/receiver/scrapererror"
+)
+
+const metricsLen = 5
+
+// sensorReading is the value of a sensor of a hardware monitoring chip.
+type sensorReading struct {
+	chip   string
+	sensor string
+	value  float64
+}
+
+// batteryReading is a value reported for a battery.
+type batteryReading struct {
+	battery string
+	value   float64
+}
+
+// sensorsStat holds the readings of all the sensors available on the host.
+type sensorsStat struct {
+	// temperatures in degrees Celsius.
+	temperatures []sensorReading
+	// fanSpeeds in revolutions per minute.
+	fanSpeeds []sensorReading
+	// power in watts.
+	power []sensorReading
+	// batteryCharges in percent of the full capacity.
+	batteryCharges []batteryReading
+	// batteryPower in watts.
+	batteryPower []batteryReading
+}
+
+// scraper for Sensors Metrics
+type scraper struct {
+	config *Config
+
+	// for mocking
+	sensors func() (*sensorsStat, error)
+}
+
+// newSensorsScraper creates a set of Sensors related metrics
+func newSensorsScraper(_ context.Context, cfg *Config) *scraper {
+	return &scraper{config: cfg, sensors: readSensors}
+}
+
+// scrape appends a metric for each kind of sensor available on the host, a host without
+// fans or batteries not reporting the corresponding metrics.
+func (s *scraper) scrape(_ context.Context) (pdata.MetricSlice, error) {
+	metrics := pdata.NewMetricSlice()
+
+	now := pdata.TimestampFromTime(time.Now())
+	stat, err := s.sensors()
+	if err != nil {
+		return metrics, scrapererror.NewPartialScrapeError(err, metricsLen)
+	}
+
+	if len(stat.temperatures) > 0 {
+		metrics.Resize(metrics.Len() + 1)
+		initializeSensorsDoubleMetric(metrics.At(metrics.Len()-1), metadata.Metrics.SystemSensorsTemperature, now, stat.temperatures)
+	}
+	if len(stat.fanSpeeds) > 0 {
+		metrics.Resize(metrics.Len() + 1)
+		initializeSensorsIntMetric(metrics.At(metrics.Len()-1), metadata.Metrics.SystemSensorsFanSpeed, now, stat.fanSpeeds)
+	}
+	if len(stat.power) > 0 {
+		metrics.Resize(metrics.Len() + 1)
+		initializeSensorsDoubleMetric(metrics.At(metrics.Len()-1), metadata.Metrics.SystemSensorsPower, now, stat.power)
+	}
+	if len(stat.batteryCharges) > 0 {
+		metrics.Resize(metrics.Len() + 1)
+		initializeBatteryMetric(metrics.At(metrics.Len()-1), metadata.Metrics.SystemBatteryCharge, now, stat.batteryCharges)
+	}
+	if len(stat.batteryPower) > 0 {
+		metrics.Resize(metrics.Len() + 1)
+		initializeBatteryMetric(metrics.At(metrics.Len()-1), metadata.Metrics.SystemBatteryPower, now, stat.batteryPower)
+	}
+	return metrics, nil
+}
+
+func initializeSensorsDoubleMetric(metric pdata.Metric, metricIntf metadata.MetricIntf, now pdata.Timestamp, readings []sensorReading) {
+	metricIntf.Init(metric)
+
+	ddps := metric.DoubleGauge().DataPoints()
+	ddps.Resize(len(readings))
+	for i, reading := range readings {
+		dp := ddps.At(i)
+		initializeSensorsLabels(dp.LabelsMap(), reading)
+		dp.SetTimestamp(now)
+		dp.SetValue(reading.value)
+	}
+}
+
+func initializeSensorsIntMetric(metric pdata.Metric, metricIntf metadata.MetricIntf, now pdata.Timestamp, readings []sensorReading) {
+	metricIntf.Init(metric)
+
+	idps := metric.IntGauge().DataPoints()
+	idps.Resize(len(readings))
+	for i, reading := range readings {
+		dp := idps.At(i)
+		initializeSensorsLabels(dp.LabelsMap(), reading)
+		dp.SetTimestamp(now)
+		dp.SetValue(int64(reading.value))
+	}
+}
+
+func initializeSensorsLabels(labelsMap pdata.StringMap, reading sensorReading) {
+	labelsMap.Insert(metadata.Labels.SensorsChip, reading.chip)
+	labelsMap.Insert(metadata.Labels.SensorsSensor, reading.sensor)
+}
+
+func initializeBatteryMetric(metric pdata.Metric, metricIntf metadata.MetricIntf, now pdata.Timestamp, readings []batteryReading) {
+	metricIntf.Init(metric)
+
+	ddps := metric.DoubleGauge().DataPoints()
+	ddps.Resize(len(readings))
+	for i, reading := range readings {
+		dp := ddps.At(i)
+		dp.LabelsMap().Insert(metadata.Labels.BatteryName, reading.battery)
+		dp.SetTimestamp(now)
+		dp.SetValue(reading.value)
+	}
+}
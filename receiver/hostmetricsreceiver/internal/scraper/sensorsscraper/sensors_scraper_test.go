@@ -0,0 +1,140 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sensorsscraper
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/receiver/hostmetricsreceiver/internal"
+	"go.opentelemetry.io/collector/receiver/hostmetricsreceiver/internal/metadata"
+	"go.opentelemetry.io/collector/receiver/scrapererror"
+)
+
+func TestScrape(t *testing.T) {
+	type testCase struct {
+		name            string
+		sensorsFunc     func() (*sensorsStat, error)
+		expectedMetrics []pdata.Metric
+		expectedErr     string
+	}
+
+	testCases := []testCase{
+		{
+			name: "All Sensors",
+			sensorsFunc: func() (*sensorsStat, error) {
+				return &sensorsStat{
+					temperatures:   []sensorReading{{chip: "coretemp", sensor: "Core 0", value: 42.5}, {chip: "acpitz", sensor: "temp1", value: 27.8}},
+					fanSpeeds:      []sensorReading{{chip: "thinkpad", sensor: "fan1", value: 2843}},
+					power:          []sensorReading{{chip: "amdgpu", sensor: "PPT", value: 12.25}},
+					batteryCharges: []batteryReading{{battery: "BAT0", value: 87}},
+					batteryPower:   []batteryReading{{battery: "BAT0", value: 6.2}},
+				}, nil
+			},
+			expectedMetrics: []pdata.Metric{
+				metadata.Metrics.SystemSensorsTemperature.New(),
+				metadata.Metrics.SystemSensorsFanSpeed.New(),
+				metadata.Metrics.SystemSensorsPower.New(),
+				metadata.Metrics.SystemBatteryCharge.New(),
+				metadata.Metrics.SystemBatteryPower.New(),
+			},
+		},
+		{
+			name: "Temperatures Only",
+			sensorsFunc: func() (*sensorsStat, error) {
+				return &sensorsStat{temperatures: []sensorReading{{chip: "smc", sensor: "TC0D", value: 51}}}, nil
+			},
+			expectedMetrics: []pdata.Metric{metadata.Metrics.SystemSensorsTemperature.New()},
+		},
+		{
+			name:        "No Sensors",
+			sensorsFunc: func() (*sensorsStat, error) { return &sensorsStat{}, nil },
+		},
+		{
+			name:        "Sensors Error",
+			sensorsFunc: func() (*sensorsStat, error) { return nil, errors.New("err1") },
+			expectedErr: "err1",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			scraper := newSensorsScraper(context.Background(), &Config{})
+			scraper.sensors = test.sensorsFunc
+
+			metrics, err := scraper.scrape(context.Background())
+			if test.expectedErr != "" {
+				assert.EqualError(t, err, test.expectedErr)
+
+				isPartial := scrapererror.IsPartialScrapeError(err)
+				assert.True(t, isPartial)
+				if isPartial {
+					assert.Equal(t, metricsLen, err.(scrapererror.PartialScrapeError).Failed)
+				}
+
+				return
+			}
+			require.NoError(t, err, "Failed to scrape metrics: %v", err)
+
+			require.Equal(t, len(test.expectedMetrics), metrics.Len())
+			for i, expected := range test.expectedMetrics {
+				internal.AssertDescriptorEqual(t, expected, metrics.At(i))
+			}
+			internal.AssertSameTimeStampForAllMetrics(t, metrics)
+		})
+	}
+}
+
+func TestScrape_Values(t *testing.T) {
+	scraper := newSensorsScraper(context.Background(), &Config{})
+	scraper.sensors = func() (*sensorsStat, error) {
+		return &sensorsStat{
+			temperatures:   []sensorReading{{chip: "coretemp", sensor: "Core 0", value: 42.5}},
+			fanSpeeds:      []sensorReading{{chip: "thinkpad", sensor: "fan1", value: 2843}},
+			batteryCharges: []batteryReading{{battery: "BAT0", value: 87}},
+		}, nil
+	}
+
+	metrics, err := scraper.scrape(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 3, metrics.Len())
+
+	temperature := metrics.At(0).DoubleGauge().DataPoints()
+	require.Equal(t, 1, temperature.Len())
+	assert.Equal(t, 42.5, temperature.At(0).Value())
+	assert.Equal(t, map[string]string{"chip": "coretemp", "sensor": "Core 0"}, labels(temperature.At(0).LabelsMap()))
+
+	internal.AssertIntGaugeMetricLabelHasValue(t, metrics.At(1), 0, "chip", "thinkpad")
+	internal.AssertIntGaugeMetricLabelHasValue(t, metrics.At(1), 0, "sensor", "fan1")
+	assert.Equal(t, int64(2843), metrics.At(1).IntGauge().DataPoints().At(0).Value())
+
+	charge := metrics.At(2).DoubleGauge().DataPoints()
+	require.Equal(t, 1, charge.Len())
+	assert.Equal(t, 87.0, charge.At(0).Value())
+	assert.Equal(t, map[string]string{"battery": "BAT0"}, labels(charge.At(0).LabelsMap()))
+}
+
+func labels(labelsMap pdata.StringMap) map[string]string {
+	m := make(map[string]string, labelsMap.Len())
+	labelsMap.ForEach(func(k, v string) {
+		m[k] = v
+	})
+	return m
+}
@@ -15,6 +15,8 @@
 package jaegerreceiver
 
 import (
+	"time"
+
 	"go.opentelemetry.io/collector/config/configgrpc"
 	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/config/configmodels"
@@ -33,8 +35,11 @@ const (
 
 // RemoteSamplingConfig defines config key for remote sampling fetch endpoint
 type RemoteSamplingConfig struct {
-	HostEndpoint                  string `mapstructure:"host_endpoint"`
-	StrategyFile                  string `mapstructure:"strategy_file"`
+	HostEndpoint string `mapstructure:"host_endpoint"`
+	StrategyFile string `mapstructure:"strategy_file"`
+	// StrategyFileReloadInterval is the interval at which the strategy file is checked for
+	// changes and reloaded. Zero, the default, disables reloading.
+	StrategyFileReloadInterval    time.Duration `mapstructure:"strategy_file_reload_interval"`
 	configgrpc.GRPCClientSettings `mapstructure:",squash"`
 }
 
@@ -46,7 +51,10 @@ type Protocols struct {
 }
 
 type ProtocolUDP struct {
-	Endpoint        string `mapstructure:"endpoint"`
+	Endpoint string `mapstructure:"endpoint"`
+	// Transport is either "udp", the default, or "unixgram" in which case Endpoint is
+	// the path of the Unix domain socket.
+	Transport       string `mapstructure:"transport"`
 	ServerConfigUDP `mapstructure:",squash"`
 }
 
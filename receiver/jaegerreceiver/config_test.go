@@ -17,6 +17,7 @@ package jaegerreceiver
 import (
 	"path"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -41,7 +42,7 @@ func TestLoadConfig(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, cfg)
 
-	assert.Equal(t, len(cfg.Receivers), 4)
+	assert.Equal(t, len(cfg.Receivers), 5)
 
 	r1 := cfg.Receivers["jaeger/customname"].(*Config)
 	assert.Equal(t, r1,
@@ -84,7 +85,8 @@ func TestLoadConfig(t *testing.T) {
 				GRPCClientSettings: configgrpc.GRPCClientSettings{
 					Endpoint: "jaeger-collector:1234",
 				},
-				StrategyFile: "/etc/strategies.json",
+				StrategyFile:               "/etc/strategies.json",
+				StrategyFileReloadInterval: 10 * time.Second,
 			},
 		})
 
@@ -163,6 +165,27 @@ func TestLoadConfig(t *testing.T) {
 				},
 			},
 		})
+
+	rUnixgram := cfg.Receivers["jaeger/unixgram"].(*Config)
+	assert.Equal(t, rUnixgram,
+		&Config{
+			ReceiverSettings: configmodels.ReceiverSettings{
+				TypeVal: typeStr,
+				NameVal: "jaeger/unixgram",
+			},
+			Protocols: Protocols{
+				ThriftCompact: &ProtocolUDP{
+					Endpoint:        "/var/run/jaeger/compact.sock",
+					Transport:       "unixgram",
+					ServerConfigUDP: DefaultServerConfigUDP(),
+				},
+				ThriftBinary: &ProtocolUDP{
+					Endpoint:        "/var/run/jaeger/binary.sock",
+					Transport:       "unixgram",
+					ServerConfigUDP: DefaultServerConfigUDP(),
+				},
+			},
+		})
 }
 
 func TestFailedLoadConfig(t *testing.T) {
@@ -43,6 +43,10 @@ const (
 	protoThriftBinary  = "thrift_binary"
 	protoThriftCompact = "thrift_compact"
 
+	// Transports of the agent protocols.
+	transportUDP      = "udp"
+	transportUnixgram = "unixgram"
+
 	// Default endpoints to bind to.
 	defaultGRPCBindEndpoint            = "0.0.0.0:14250"
 	defaultHTTPBindEndpoint            = "0.0.0.0:14268"
@@ -175,23 +179,24 @@ func createTraceReceiver(
 		if err != nil {
 			return nil, fmt.Errorf("unable to extract port for ThriftHTTP: %w", err)
 		}
+		config.CollectorHTTPSettings = rCfg.Protocols.ThriftHTTP
 	}
 
 	if rCfg.Protocols.ThriftBinary != nil {
 		config.AgentBinaryThriftConfig = rCfg.ThriftBinary.ServerConfigUDP
 		var err error
-		config.AgentBinaryThriftPort, err = extractPortFromEndpoint(rCfg.Protocols.ThriftBinary.Endpoint)
+		config.AgentBinaryThriftPort, config.AgentBinaryThriftSocket, err = extractAgentAddress(rCfg.Protocols.ThriftBinary)
 		if err != nil {
-			return nil, fmt.Errorf("unable to extract port for ThriftBinary: %w", err)
+			return nil, fmt.Errorf("unable to extract address for ThriftBinary: %w", err)
 		}
 	}
 
 	if rCfg.Protocols.ThriftCompact != nil {
 		config.AgentCompactThriftConfig = rCfg.ThriftCompact.ServerConfigUDP
 		var err error
-		config.AgentCompactThriftPort, err = extractPortFromEndpoint(rCfg.Protocols.ThriftCompact.Endpoint)
+		config.AgentCompactThriftPort, config.AgentCompactThriftSocket, err = extractAgentAddress(rCfg.Protocols.ThriftCompact)
 		if err != nil {
-			return nil, fmt.Errorf("unable to extract port for ThriftCompact: %w", err)
+			return nil, fmt.Errorf("unable to extract address for ThriftCompact: %w", err)
 		}
 	}
 
@@ -211,6 +216,10 @@ func createTraceReceiver(
 			}
 		}
 
+		if remoteSamplingConfig.StrategyFileReloadInterval < 0 {
+			return nil, fmt.Errorf("strategy file reload interval cannot be negative")
+		}
+
 		// strategies are served over grpc so if grpc is not enabled and strategies are present return an error
 		if len(remoteSamplingConfig.StrategyFile) != 0 {
 			if config.CollectorGRPCPort == 0 {
@@ -218,11 +227,13 @@ func createTraceReceiver(
 			}
 
 			config.RemoteSamplingStrategyFile = remoteSamplingConfig.StrategyFile
+			config.RemoteSamplingStrategyFileReloadInterval = remoteSamplingConfig.StrategyFileReloadInterval
 		}
 	}
 
 	if (rCfg.Protocols.GRPC == nil && rCfg.Protocols.ThriftHTTP == nil && rCfg.Protocols.ThriftBinary == nil && rCfg.Protocols.ThriftCompact == nil) ||
-		(config.CollectorGRPCPort == 0 && config.CollectorHTTPPort == 0 && config.CollectorThriftPort == 0 && config.AgentBinaryThriftPort == 0 && config.AgentCompactThriftPort == 0) {
+		(config.CollectorGRPCPort == 0 && config.CollectorHTTPPort == 0 && config.CollectorThriftPort == 0 && config.AgentBinaryThriftPort == 0 && config.AgentCompactThriftPort == 0 &&
+			config.AgentBinaryThriftSocket == "" && config.AgentCompactThriftSocket == "") {
 		err := fmt.Errorf("either GRPC(%v), ThriftHTTP(%v), ThriftCompact(%v), or ThriftBinary(%v) protocol endpoint with non-zero port must be enabled for %s receiver",
 			rCfg.Protocols.GRPC,
 			rCfg.Protocols.ThriftHTTP,
@@ -237,6 +248,23 @@ func createTraceReceiver(
 	return newJaegerReceiver(rCfg.Name(), &config, nextConsumer, params), nil
 }
 
+// extractAgentAddress returns the port of the UDP endpoint, or the socket path of the
+// unixgram endpoint, of the agent protocol.
+func extractAgentAddress(protocol *ProtocolUDP) (int, string, error) {
+	switch protocol.Transport {
+	case "", transportUDP:
+		port, err := extractPortFromEndpoint(protocol.Endpoint)
+		return port, "", err
+	case transportUnixgram:
+		if protocol.Endpoint == "" {
+			return 0, "", fmt.Errorf("endpoint must be the socket path for the %q transport", transportUnixgram)
+		}
+		return 0, protocol.Endpoint, nil
+	default:
+		return 0, "", fmt.Errorf("unsupported transport %q, expecting %q or %q", protocol.Transport, transportUDP, transportUnixgram)
+	}
+}
+
 // extract the port number from string in "address:port" format. If the
 // port number cannot be extracted returns an error.
 func extractPortFromEndpoint(endpoint string) (int, error) {
@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"path"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -161,6 +162,65 @@ func TestCreateInvalidThriftBinaryEndpoint(t *testing.T) {
 	assert.Equal(t, 6832, r.(*jReceiver).config.AgentBinaryThriftPort, "thrift port should be default")
 }
 
+func TestCreateUnixgramThriftEndpoints(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+
+	cfg.(*Config).Protocols.ThriftBinary = &ProtocolUDP{
+		Endpoint:  "/var/run/jaeger/binary.sock",
+		Transport: transportUnixgram,
+	}
+	cfg.(*Config).Protocols.ThriftCompact = &ProtocolUDP{
+		Endpoint:  "/var/run/jaeger/compact.sock",
+		Transport: transportUnixgram,
+	}
+	params := component.ReceiverCreateParams{Logger: zap.NewNop()}
+	r, err := factory.CreateTracesReceiver(context.Background(), params, cfg, nil)
+
+	assert.NoError(t, err, "unexpected error creating receiver")
+	assert.Equal(t, "/var/run/jaeger/binary.sock", r.(*jReceiver).config.AgentBinaryThriftSocket)
+	assert.Equal(t, 0, r.(*jReceiver).config.AgentBinaryThriftPort)
+	assert.Equal(t, "/var/run/jaeger/compact.sock", r.(*jReceiver).config.AgentCompactThriftSocket)
+	assert.Equal(t, 0, r.(*jReceiver).config.AgentCompactThriftPort)
+}
+
+func TestCreateUnixgramThriftEndpointOnly(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+
+	cfg.(*Config).Protocols = Protocols{
+		ThriftCompact: &ProtocolUDP{
+			Endpoint:  "/var/run/jaeger/compact.sock",
+			Transport: transportUnixgram,
+		},
+	}
+	params := component.ReceiverCreateParams{Logger: zap.NewNop()}
+	_, err := factory.CreateTracesReceiver(context.Background(), params, cfg, nil)
+
+	assert.NoError(t, err, "a unixgram endpoint alone should be enough to create the receiver")
+}
+
+func TestCreateInvalidThriftTransport(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+
+	cfg.(*Config).Protocols.ThriftCompact = &ProtocolUDP{
+		Endpoint:  "/var/run/jaeger/compact.sock",
+		Transport: "unix",
+	}
+	params := component.ReceiverCreateParams{Logger: zap.NewNop()}
+	_, err := factory.CreateTracesReceiver(context.Background(), params, cfg, nil)
+
+	assert.Error(t, err, "stream transports are not supported by the agent protocols")
+
+	cfg.(*Config).Protocols.ThriftCompact = &ProtocolUDP{
+		Transport: transportUnixgram,
+	}
+	_, err = factory.CreateTracesReceiver(context.Background(), params, cfg, nil)
+
+	assert.Error(t, err, "unixgram transport requires a socket path")
+}
+
 func TestCreateInvalidThriftCompactEndpoint(t *testing.T) {
 	factory := NewFactory()
 	cfg := factory.CreateDefaultConfig()
@@ -307,8 +367,9 @@ func TestRemoteSamplingConfigPropagation(t *testing.T) {
 		GRPCClientSettings: configgrpc.GRPCClientSettings{
 			Endpoint: endpoint,
 		},
-		HostEndpoint: fmt.Sprintf("localhost:%d", hostPort),
-		StrategyFile: strategyFile,
+		HostEndpoint:               fmt.Sprintf("localhost:%d", hostPort),
+		StrategyFile:               strategyFile,
+		StrategyFileReloadInterval: time.Minute,
 	}
 	params := component.ReceiverCreateParams{Logger: zap.NewNop()}
 	r, err := factory.CreateTracesReceiver(context.Background(), params, cfg, nil)
@@ -317,6 +378,22 @@ func TestRemoteSamplingConfigPropagation(t *testing.T) {
 	assert.Equal(t, endpoint, r.(*jReceiver).config.RemoteSamplingClientSettings.Endpoint)
 	assert.Equal(t, hostPort, r.(*jReceiver).config.AgentHTTPPort, "agent http port should be configured value")
 	assert.Equal(t, strategyFile, r.(*jReceiver).config.RemoteSamplingStrategyFile)
+	assert.Equal(t, time.Minute, r.(*jReceiver).config.RemoteSamplingStrategyFileReloadInterval)
+}
+
+func TestRemoteSamplingNegativeReloadInterval(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+	rCfg := cfg.(*Config)
+
+	rCfg.RemoteSampling = &RemoteSamplingConfig{
+		StrategyFile:               "strategies.json",
+		StrategyFileReloadInterval: -time.Second,
+	}
+	params := component.ReceiverCreateParams{Logger: zap.NewNop()}
+	_, err := factory.CreateTracesReceiver(context.Background(), params, cfg, nil)
+
+	assert.Error(t, err, "create trace receiver should error")
 }
 
 func TestRemoteSamplingFileRequiresGRPC(t *testing.T) {
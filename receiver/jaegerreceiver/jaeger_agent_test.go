@@ -15,10 +15,14 @@
 package jaegerreceiver
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io/ioutil"
 	"net"
 	"net/http"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/apache/thrift/lib/go/thrift"
@@ -78,6 +82,43 @@ func TestJaegerAgentUDP_ThriftBinary(t *testing.T) {
 	})
 }
 
+func TestJaegerAgentUnixgram_ThriftCompact(t *testing.T) {
+	dir, err := ioutil.TempDir("", "jaeger")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	socket := filepath.Join(dir, "compact.sock")
+	testJaegerAgent(t, socket, &configuration{
+		AgentCompactThriftSocket: socket,
+		AgentCompactThriftConfig: DefaultServerConfigUDP(),
+	})
+	_, err = os.Stat(socket)
+	assert.True(t, os.IsNotExist(err), "socket file should have been removed on shutdown")
+}
+
+func TestJaegerAgentUnixgram_ThriftBinary(t *testing.T) {
+	dir, err := ioutil.TempDir("", "jaeger")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	socket := filepath.Join(dir, "binary.sock")
+	testJaegerAgent(t, socket, &configuration{
+		AgentBinaryThriftSocket: socket,
+		AgentBinaryThriftConfig: DefaultServerConfigUDP(),
+	})
+}
+
+func TestJaegerAgentUnixgram_InvalidSocket(t *testing.T) {
+	config := &configuration{
+		AgentCompactThriftSocket: filepath.Join("does-not-exist", "compact.sock"),
+		AgentCompactThriftConfig: DefaultServerConfigUDP(),
+	}
+	params := component.ReceiverCreateParams{Logger: zap.NewNop()}
+	jr := newJaegerReceiver(jaegerAgent, config, nil, params)
+
+	assert.Error(t, jr.Start(context.Background(), componenttest.NewNopHost()), "should not have been able to startTraceReception")
+
+	jr.Shutdown(context.Background())
+}
+
 func TestJaegerAgentUDP_ThriftBinary_PortInUse(t *testing.T) {
 	// This test confirms that the thrift binary port is opened correctly.  This is all we can test at the moment.  See above.
 	port := testutil.GetAvailablePort(t)
@@ -187,7 +228,13 @@ func testJaegerAgent(t *testing.T, agentEndpoint string, receiverConfig *configu
 	assert.NoError(t, jr.Start(context.Background(), componenttest.NewNopHost()), "Start failed")
 
 	// 2. Then send spans to the Jaeger receiver.
-	jexp, err := newClientUDP(agentEndpoint, jr.agentBinaryThriftEnabled())
+	var jexp *agent.AgentClient
+	var err error
+	if receiverConfig.AgentBinaryThriftSocket != "" || receiverConfig.AgentCompactThriftSocket != "" {
+		jexp, err = newClientUnixgram(agentEndpoint, jr.agentBinaryThriftEnabled())
+	} else {
+		jexp, err = newClientUDP(agentEndpoint, jr.agentBinaryThriftEnabled())
+	}
 	assert.NoError(t, err, "Failed to create the Jaeger OpenCensus exporter for the live application")
 
 	// 3. Now finally send some spans
@@ -221,6 +268,56 @@ func newClientUDP(hostPort string, binary bool) (*agent.AgentClient, error) {
 	return agent.NewAgentClientFactory(clientTransport, protocolFactory), nil
 }
 
+func newClientUnixgram(path string, binary bool) (*agent.AgentClient, error) {
+	conn, err := net.Dial("unixgram", path)
+	if err != nil {
+		return nil, err
+	}
+	var protocolFactory thrift.TProtocolFactory
+	if binary {
+		protocolFactory = thrift.NewTBinaryProtocolFactoryDefault()
+	} else {
+		protocolFactory = thrift.NewTCompactProtocolFactory()
+	}
+	return agent.NewAgentClientFactory(&unixgramClientTransport{conn: conn}, protocolFactory), nil
+}
+
+// unixgramClientTransport sends each flushed batch as one Unix datagram.
+type unixgramClientTransport struct {
+	conn     net.Conn
+	writeBuf bytes.Buffer
+}
+
+func (t *unixgramClientTransport) Open() error {
+	return nil
+}
+
+func (t *unixgramClientTransport) IsOpen() bool {
+	return true
+}
+
+func (t *unixgramClientTransport) Close() error {
+	return t.conn.Close()
+}
+
+func (t *unixgramClientTransport) Read(buf []byte) (int, error) {
+	return t.conn.Read(buf)
+}
+
+func (t *unixgramClientTransport) Write(buf []byte) (int, error) {
+	return t.writeBuf.Write(buf)
+}
+
+func (t *unixgramClientTransport) Flush(context.Context) error {
+	_, err := t.conn.Write(t.writeBuf.Bytes())
+	t.writeBuf.Reset()
+	return err
+}
+
+func (t *unixgramClientTransport) RemainingBytes() uint64 {
+	return ^uint64(0)
+}
+
 // Cannot use the testdata because timestamps are nanoseconds.
 func generateTraceData() pdata.Traces {
 	td := pdata.NewTraces()
@@ -22,6 +22,7 @@ import (
 	"net"
 	"net/http"
 	"sync"
+	"time"
 
 	apacheThrift "github.com/apache/thrift/lib/go/thrift"
 	"github.com/gorilla/mux"
@@ -33,6 +34,7 @@ import (
 	"github.com/jaegertracing/jaeger/cmd/agent/app/servers/thriftudp"
 	"github.com/jaegertracing/jaeger/cmd/collector/app/handler"
 	collectorSampling "github.com/jaegertracing/jaeger/cmd/collector/app/sampling"
+	"github.com/jaegertracing/jaeger/cmd/collector/app/sampling/strategystore"
 	staticStrategyStore "github.com/jaegertracing/jaeger/plugin/sampling/strategystore/static"
 	"github.com/jaegertracing/jaeger/proto-gen/api_v2"
 	"github.com/jaegertracing/jaeger/thrift-gen/agent"
@@ -48,6 +50,7 @@ import (
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenterror"
 	"go.opentelemetry.io/collector/config/configgrpc"
+	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/obsreport"
@@ -57,18 +60,22 @@ import (
 // configuration defines the behavior and the ports that
 // the Jaeger receiver will use.
 type configuration struct {
-	CollectorThriftPort  int
-	CollectorHTTPPort    int
-	CollectorGRPCPort    int
-	CollectorGRPCOptions []grpc.ServerOption
-
-	AgentCompactThriftPort       int
-	AgentCompactThriftConfig     ServerConfigUDP
-	AgentBinaryThriftPort        int
-	AgentBinaryThriftConfig      ServerConfigUDP
-	AgentHTTPPort                int
-	RemoteSamplingClientSettings configgrpc.GRPCClientSettings
-	RemoteSamplingStrategyFile   string
+	CollectorThriftPort   int
+	CollectorHTTPPort     int
+	CollectorHTTPSettings *confighttp.HTTPServerSettings
+	CollectorGRPCPort     int
+	CollectorGRPCOptions  []grpc.ServerOption
+
+	AgentCompactThriftPort                   int
+	AgentCompactThriftSocket                 string
+	AgentCompactThriftConfig                 ServerConfigUDP
+	AgentBinaryThriftPort                    int
+	AgentBinaryThriftSocket                  string
+	AgentBinaryThriftConfig                  ServerConfigUDP
+	AgentHTTPPort                            int
+	RemoteSamplingClientSettings             configgrpc.GRPCClientSettings
+	RemoteSamplingStrategyFile               string
+	RemoteSamplingStrategyFileReloadInterval time.Duration
 }
 
 // Receiver type is used to receive spans that were originally intended to be sent to Jaeger.
@@ -87,6 +94,7 @@ type jReceiver struct {
 
 	grpc            *grpc.Server
 	collectorServer *http.Server
+	strategyStore   strategystore.StrategyStore
 
 	agentSamplingManager *jSamplingConfig.SamplingManager
 	agentProcessors      []processors.Processor
@@ -131,25 +139,31 @@ func newJaegerReceiver(
 func (jr *jReceiver) agentCompactThriftAddr() string {
 	var port int
 	if jr.config != nil {
+		if jr.config.AgentCompactThriftSocket != "" {
+			return jr.config.AgentCompactThriftSocket
+		}
 		port = jr.config.AgentCompactThriftPort
 	}
 	return fmt.Sprintf(":%d", port)
 }
 
 func (jr *jReceiver) agentCompactThriftEnabled() bool {
-	return jr.config != nil && jr.config.AgentCompactThriftPort > 0
+	return jr.config != nil && (jr.config.AgentCompactThriftPort > 0 || jr.config.AgentCompactThriftSocket != "")
 }
 
 func (jr *jReceiver) agentBinaryThriftAddr() string {
 	var port int
 	if jr.config != nil {
+		if jr.config.AgentBinaryThriftSocket != "" {
+			return jr.config.AgentBinaryThriftSocket
+		}
 		port = jr.config.AgentBinaryThriftPort
 	}
 	return fmt.Sprintf(":%d", port)
 }
 
 func (jr *jReceiver) agentBinaryThriftEnabled() bool {
-	return jr.config != nil && jr.config.AgentBinaryThriftPort > 0
+	return jr.config != nil && (jr.config.AgentBinaryThriftPort > 0 || jr.config.AgentBinaryThriftSocket != "")
 }
 
 func (jr *jReceiver) agentHTTPAddr() string {
@@ -234,6 +248,11 @@ func (jr *jReceiver) Shutdown(context.Context) error {
 			jr.grpc.Stop()
 			jr.grpc = nil
 		}
+		// the static strategy store stops reloading the strategy file once closed
+		if closer, ok := jr.strategyStore.(interface{ Close() }); ok {
+			closer.Close()
+		}
+		jr.strategyStore = nil
 		err = consumererror.CombineErrors(errs)
 	})
 
@@ -319,7 +338,7 @@ func (jr *jReceiver) startAgent(_ component.Host) error {
 			transport:    agentTransportBinary,
 			nextConsumer: jr.nextConsumer,
 		}
-		processor, err := jr.buildProcessor(jr.agentBinaryThriftAddr(), jr.config.AgentBinaryThriftConfig, apacheThrift.NewTBinaryProtocolFactoryDefault(), h)
+		processor, err := jr.buildProcessor(jr.config.AgentBinaryThriftSocket != "", jr.agentBinaryThriftAddr(), jr.config.AgentBinaryThriftConfig, apacheThrift.NewTBinaryProtocolFactoryDefault(), h)
 		if err != nil {
 			return err
 		}
@@ -332,7 +351,7 @@ func (jr *jReceiver) startAgent(_ component.Host) error {
 			transport:    agentTransportCompact,
 			nextConsumer: jr.nextConsumer,
 		}
-		processor, err := jr.buildProcessor(jr.agentCompactThriftAddr(), jr.config.AgentCompactThriftConfig, apacheThrift.NewTCompactProtocolFactory(), h)
+		processor, err := jr.buildProcessor(jr.config.AgentCompactThriftSocket != "", jr.agentCompactThriftAddr(), jr.config.AgentCompactThriftConfig, apacheThrift.NewTCompactProtocolFactory(), h)
 		if err != nil {
 			return err
 		}
@@ -372,14 +391,23 @@ func (jr *jReceiver) startAgent(_ component.Host) error {
 	return nil
 }
 
-func (jr *jReceiver) buildProcessor(address string, cfg ServerConfigUDP, factory apacheThrift.TProtocolFactory, a agent.Agent) (processors.Processor, error) {
+// buildProcessor builds the processor of an agent protocol, address being a socket path
+// when unixgram is set and a UDP address otherwise.
+func (jr *jReceiver) buildProcessor(unixgram bool, address string, cfg ServerConfigUDP, factory apacheThrift.TProtocolFactory, a agent.Agent) (processors.Processor, error) {
 	handler := agent.NewAgentProcessor(a)
-	transport, err := thriftudp.NewTUDPServerTransport(address)
+	var transport agentServerTransport
+	var err error
+	if unixgram {
+		transport, err = newUnixgramServerTransport(address)
+	} else {
+		transport, err = thriftudp.NewTUDPServerTransport(address)
+	}
 	if err != nil {
 		return nil, err
 	}
 	if cfg.SocketBufferSize > 0 {
 		if err = transport.SetSocketBufferSize(cfg.SocketBufferSize); err != nil {
+			transport.Close()
 			return nil, err
 		}
 	}
@@ -462,15 +490,23 @@ func (jr *jReceiver) startCollector(host component.Host) error {
 
 	if jr.collectorHTTPEnabled() {
 		// Now the collector that runs over HTTP
+		nr := mux.NewRouter()
+		nr.HandleFunc("/api/traces", jr.HandleThriftHTTPBatch).Methods(http.MethodPost)
+		jr.collectorServer = &http.Server{Handler: nr}
+		if jr.config.CollectorHTTPSettings != nil {
+			// the settings shared by the HTTP receivers, e.g. the authentication and the limits
+			var err error
+			if jr.collectorServer, err = jr.config.CollectorHTTPSettings.ToServer(nr); err != nil {
+				return fmt.Errorf("failed to set up the Collector HTTP server: %w", err)
+			}
+		}
+
 		caddr := jr.collectorHTTPAddr()
 		cln, cerr := net.Listen("tcp", caddr)
 		if cerr != nil {
 			return fmt.Errorf("failed to bind to Collector address %q: %v", caddr, cerr)
 		}
 
-		nr := mux.NewRouter()
-		nr.HandleFunc("/api/traces", jr.HandleThriftHTTPBatch).Methods(http.MethodPost)
-		jr.collectorServer = &http.Server{Handler: nr}
 		go func() {
 			_ = jr.collectorServer.Serve(cln)
 		}()
@@ -489,10 +525,12 @@ func (jr *jReceiver) startCollector(host component.Host) error {
 		// init and register sampling strategy store
 		ss, gerr := staticStrategyStore.NewStrategyStore(staticStrategyStore.Options{
 			StrategiesFile: jr.config.RemoteSamplingStrategyFile,
+			ReloadInterval: jr.config.RemoteSamplingStrategyFileReloadInterval,
 		}, jr.logger)
 		if gerr != nil {
 			return fmt.Errorf("failed to create collector strategy store: %v", gerr)
 		}
+		jr.strategyStore = ss
 		api_v2.RegisterSamplingManagerServer(jr.grpc, collectorSampling.NewGRPCHandler(ss))
 
 		go func() {
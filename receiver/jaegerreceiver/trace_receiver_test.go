@@ -24,7 +24,9 @@ import (
 	"net"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"path"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -43,6 +45,7 @@ import (
 	"go.opentelemetry.io/collector/client"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configauth"
 	"go.opentelemetry.io/collector/config/configgrpc"
 	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/config/configtls"
@@ -161,6 +164,49 @@ func TestReception(t *testing.T) {
 	assert.EqualValues(t, td, gotTraces[0])
 }
 
+func TestReceptionWithAuth(t *testing.T) {
+	port := testutil.GetAvailablePort(t)
+	config := &configuration{
+		CollectorHTTPPort: int(port),
+		CollectorHTTPSettings: &confighttp.HTTPServerSettings{
+			Auth: &configauth.Authentication{
+				Basic: &configauth.BasicAuth{Users: map[string]string{"collector": "secret"}},
+			},
+		},
+	}
+	sink := new(consumertest.TracesSink)
+
+	params := component.ReceiverCreateParams{Logger: zap.NewNop()}
+	jr := newJaegerReceiver(jaegerReceiver, config, sink, params)
+	defer jr.Shutdown(context.Background())
+	require.NoError(t, jr.Start(context.Background(), componenttest.NewNopHost()))
+
+	collectorAddr := fmt.Sprintf("http://localhost:%d/api/traces", port)
+	batches, err := jaeger.InternalTracesToJaegerProto(generateTraceData())
+	require.NoError(t, err)
+	buf, err := thrift.NewTSerializer().Write(context.Background(), modelToThrift(batches[0]))
+	require.NoError(t, err)
+
+	for _, password := range []string{"", "secret"} {
+		req, err := http.NewRequest(http.MethodPost, collectorAddr, bytes.NewBuffer(buf))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/x-thrift")
+		if password != "" {
+			req.SetBasicAuth("collector", password)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		if password == "" {
+			assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		} else {
+			assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+		}
+	}
+
+	assert.Equal(t, 1, len(sink.AllTraces()))
+}
+
 func TestPortsNotOpen(t *testing.T) {
 	// an empty config should result in no open ports
 	config := &configuration{}
@@ -474,6 +520,62 @@ func TestSamplingFailsOnBadFile(t *testing.T) {
 	assert.Error(t, jr.Start(context.Background(), componenttest.NewNopHost()))
 }
 
+func TestSamplingReloadsStrategyFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "strategies")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	strategyFile := filepath.Join(dir, "strategies.json")
+	writeStrategies := func(rate float64) {
+		strategies := fmt.Sprintf(`{
+			"service_strategies": [
+				{
+					"service": "foo",
+					"type": "probabilistic",
+					"param": 0.8,
+					"operation_strategies": [{"operation": "op1", "type": "probabilistic", "param": %v}]
+				}
+			],
+			"default_strategy": {"type": "probabilistic", "param": 0.5}
+		}`, rate)
+		require.NoError(t, ioutil.WriteFile(strategyFile, []byte(strategies), 0600))
+	}
+	writeStrategies(0.2)
+
+	port := testutil.GetAvailablePort(t)
+	config := &configuration{
+		CollectorGRPCPort:                        int(port),
+		RemoteSamplingStrategyFile:               strategyFile,
+		RemoteSamplingStrategyFileReloadInterval: 10 * time.Millisecond,
+	}
+	sink := new(consumertest.TracesSink)
+
+	params := component.ReceiverCreateParams{Logger: zap.NewNop()}
+	jr := newJaegerReceiver(jaegerReceiver, config, sink, params)
+	defer jr.Shutdown(context.Background())
+
+	require.NoError(t, jr.Start(context.Background(), componenttest.NewNopHost()))
+
+	conn, err := grpc.Dial(fmt.Sprintf("localhost:%d", config.CollectorGRPCPort), grpc.WithInsecure())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	cl := api_v2.NewSamplingManagerClient(conn)
+	operationRate := func() float64 {
+		resp, err := cl.GetSamplingStrategy(context.Background(), &api_v2.SamplingStrategyParameters{
+			ServiceName: "foo",
+		})
+		require.NoError(t, err)
+		require.Len(t, resp.GetOperationSampling().GetPerOperationStrategies(), 1)
+		return resp.GetOperationSampling().GetPerOperationStrategies()[0].GetProbabilisticSampling().GetSamplingRate()
+	}
+	assert.Equal(t, 0.2, operationRate())
+
+	writeStrategies(0.6)
+	assert.Eventually(t, func() bool {
+		return operationRate() == 0.6
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
 func TestSamplingStrategiesMutualTLS(t *testing.T) {
 	caPath := path.Join(".", "testdata", "ca.crt")
 	serverCertPath := path.Join(".", "testdata", "server.crt")
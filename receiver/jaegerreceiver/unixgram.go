@@ -0,0 +1,71 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jaegerreceiver
+
+import (
+	"net"
+	"os"
+	"sync"
+
+	"github.com/jaegertracing/jaeger/cmd/agent/app/servers"
+)
+
+// agentServerTransport is the transport of the agent protocols.
+type agentServerTransport interface {
+	servers.ThriftTransport
+	SetSocketBufferSize(bufferSize int) error
+}
+
+// unixgramServerTransport reads the Thrift batches sent by the Jaeger clients as Unix
+// datagrams, each datagram holding one batch as the UDP packets do.
+type unixgramServerTransport struct {
+	conn      *net.UnixConn
+	path      string
+	closeOnce sync.Once
+}
+
+var _ agentServerTransport = (*unixgramServerTransport)(nil)
+
+// newUnixgramServerTransport listens for Unix datagrams on the socket at path.
+func newUnixgramServerTransport(path string) (*unixgramServerTransport, error) {
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		return nil, err
+	}
+	return &unixgramServerTransport{conn: conn, path: path}, nil
+}
+
+// Read reads one datagram into buf.
+func (t *unixgramServerTransport) Read(buf []byte) (int, error) {
+	return t.conn.Read(buf)
+}
+
+// Close closes the socket and removes its file, which unlike the stream sockets
+// is not removed when a datagram socket is closed.
+func (t *unixgramServerTransport) Close() error {
+	var err error
+	t.closeOnce.Do(func() {
+		err = t.conn.Close()
+		if rerr := os.Remove(t.path); rerr != nil && !os.IsNotExist(rerr) && err == nil {
+			err = rerr
+		}
+	})
+	return err
+}
+
+// SetSocketBufferSize sets the size of the socket receive buffer.
+func (t *unixgramServerTransport) SetSocketBufferSize(bufferSize int) error {
+	return t.conn.SetReadBuffer(bufferSize)
+}
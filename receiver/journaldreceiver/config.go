@@ -0,0 +1,61 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package journaldreceiver
+
+import (
+	"go.opentelemetry.io/collector/config/configmodels"
+)
+
+// Config defines configuration for the journald receiver.
+type Config struct {
+	configmodels.ReceiverSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct
+
+	// JournalctlPath is the path of the journalctl executable, "journalctl"
+	// being looked up in the PATH by default.
+	JournalctlPath string `mapstructure:"journalctl_path"`
+
+	// Directory is the directory of the journal files, the journal of the
+	// host being read by default.
+	Directory string `mapstructure:"directory"`
+
+	// Units are the systemd units whose entries are read, all the entries
+	// being read if empty.
+	Units []string `mapstructure:"units"`
+
+	// Priority is the maximum priority, "emerg" (0) to "debug" (7), or the
+	// range of priorities, for example "err..warning", of the entries read.
+	// All the entries are read if empty.
+	Priority string `mapstructure:"priority"`
+
+	// StartAt is where the entries are read from when there is no persisted
+	// cursor, "end" (default) or "beginning".
+	StartAt string `mapstructure:"start_at"`
+
+	// CursorFile is the file where the cursor of the last entry sent is
+	// persisted, the entries being read after it when the receiver is
+	// restarted. The cursor is not persisted if empty.
+	CursorFile string `mapstructure:"cursor_file"`
+
+	// Fields maps the journal fields, case insensitive, to the attributes of
+	// the log records. The default mapping is used if empty.
+	Fields map[string]string `mapstructure:"fields"`
+}
+
+var _ configmodels.ConfigValidator = (*Config)(nil)
+
+// Validate checks the receiver configuration is valid.
+func (cfg *Config) Validate() error {
+	return validateConfig(cfg)
+}
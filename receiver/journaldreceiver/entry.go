@@ -0,0 +1,116 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package journaldreceiver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// The fields of the journal entries.
+const (
+	cursorField            = "__CURSOR"
+	realtimeTimestampField = "__REALTIME_TIMESTAMP"
+	messageField           = "MESSAGE"
+	priorityField          = "PRIORITY"
+)
+
+var errMissingCursor = errors.New("missing " + cursorField)
+
+// severities maps the priorities of the entries, those of syslog, to the
+// OpenTelemetry severities.
+var severities = [8]struct {
+	number pdata.SeverityNumber
+	text   string
+}{
+	{pdata.SeverityNumberFATAL4, "emerg"},
+	{pdata.SeverityNumberFATAL3, "alert"},
+	{pdata.SeverityNumberFATAL2, "crit"},
+	{pdata.SeverityNumberERROR, "err"},
+	{pdata.SeverityNumberWARN, "warning"},
+	{pdata.SeverityNumberINFO2, "notice"},
+	{pdata.SeverityNumberINFO, "info"},
+	{pdata.SeverityNumberDEBUG, "debug"},
+}
+
+// parseEntry parses a journal entry printed by "journalctl --output=json"
+// into the log record lr, the fields being mapped to the attributes, and
+// returns its cursor.
+func parseEntry(line []byte, fields map[string]string, lr pdata.LogRecord) (string, error) {
+	var entry map[string]interface{}
+	if err := json.Unmarshal(line, &entry); err != nil {
+		return "", fmt.Errorf("invalid journal entry: %w", err)
+	}
+	cursor, ok := fieldValue(entry[cursorField])
+	if !ok {
+		return "", errMissingCursor
+	}
+
+	if value, ok := fieldValue(entry[realtimeTimestampField]); ok {
+		us, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return "", fmt.Errorf("invalid %s %q", realtimeTimestampField, value)
+		}
+		lr.SetTimestamp(pdata.Timestamp(uint64(us) * 1000))
+	}
+	if value, ok := fieldValue(entry[priorityField]); ok {
+		if priority, err := strconv.Atoi(value); err == nil && priority >= 0 && priority < len(severities) {
+			lr.SetSeverityNumber(severities[priority].number)
+			lr.SetSeverityText(severities[priority].text)
+		}
+	}
+	if value, ok := fieldValue(entry[messageField]); ok {
+		lr.Body().SetStringVal(value)
+	}
+
+	for field, attribute := range fields {
+		if value, ok := fieldValue(entry[field]); ok {
+			lr.Attributes().UpsertString(attribute, value)
+		}
+	}
+	lr.Attributes().Sort()
+	return cursor, nil
+}
+
+// fieldValue returns the value of a field, journalctl printing the values
+// which are not valid UTF-8 as arrays of bytes, and the fields with several
+// values as arrays of values, the first one being returned.
+func fieldValue(v interface{}) (string, bool) {
+	switch v := v.(type) {
+	case string:
+		return v, true
+	case []interface{}:
+		if len(v) == 0 {
+			return "", false
+		}
+		if _, ok := v[0].(float64); !ok {
+			return fieldValue(v[0])
+		}
+		b := make([]byte, 0, len(v))
+		for _, c := range v {
+			n, ok := c.(float64)
+			if !ok {
+				return "", false
+			}
+			b = append(b, byte(n))
+		}
+		return string(b), true
+	}
+	return "", false
+}
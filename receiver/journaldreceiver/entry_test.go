@@ -0,0 +1,80 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package journaldreceiver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func TestParseEntry(t *testing.T) {
+	line := `{"__CURSOR":"s=739ad463348b4ceca5a9e69c95a3c93f;i=4ece7","__REALTIME_TIMESTAMP":"1615809600123456","PRIORITY":"3",` +
+		`"_SYSTEMD_UNIT":"ssh.service","SYSLOG_IDENTIFIER":"sshd","_PID":"1234","_HOSTNAME":"node-1","_BOOT_ID":"b1",` +
+		`"MESSAGE":"Connection closed"}`
+	lr := pdata.NewLogRecord()
+	cursor, err := parseEntry([]byte(line), defaultFields, lr)
+	require.NoError(t, err)
+	assert.Equal(t, "s=739ad463348b4ceca5a9e69c95a3c93f;i=4ece7", cursor)
+	assert.Equal(t, pdata.TimestampFromTime(time.Date(2021, time.March, 15, 12, 0, 0, 123456000, time.UTC)), lr.Timestamp())
+	assert.Equal(t, pdata.SeverityNumberERROR, lr.SeverityNumber())
+	assert.Equal(t, "err", lr.SeverityText())
+	assert.Equal(t, "Connection closed", lr.Body().StringVal())
+
+	expected := pdata.NewAttributeMap()
+	expected.InsertString("host.name", "node-1")
+	expected.InsertString("journald.pid", "1234")
+	expected.InsertString("syslog.identifier", "sshd")
+	expected.InsertString("systemd.unit", "ssh.service")
+	assert.Equal(t, expected.Sort(), lr.Attributes())
+}
+
+func TestParseEntryValues(t *testing.T) {
+	// The message is not valid UTF-8 and the field has several values.
+	line := `{"__CURSOR":"c","MESSAGE":[104,105,255],"UNIT":["a.service","b.service"],"PRIORITY":"9"}`
+	lr := pdata.NewLogRecord()
+	cursor, err := parseEntry([]byte(line), map[string]string{"UNIT": "unit", "MISSING": "missing"}, lr)
+	require.NoError(t, err)
+	assert.Equal(t, "c", cursor)
+	assert.Equal(t, pdata.Timestamp(0), lr.Timestamp())
+	assert.Equal(t, pdata.SeverityNumberUNDEFINED, lr.SeverityNumber())
+	assert.Equal(t, "hi\xff", lr.Body().StringVal())
+	unit, ok := lr.Attributes().Get("unit")
+	require.True(t, ok)
+	assert.Equal(t, "a.service", unit.StringVal())
+	assert.Equal(t, 1, lr.Attributes().Len())
+}
+
+func TestParseEntryErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		line        string
+		expectedErr string
+	}{
+		{name: "invalid json", line: `{"__CURSOR":`, expectedErr: "invalid journal entry: unexpected end of JSON input"},
+		{name: "missing cursor", line: `{"MESSAGE":"message"}`, expectedErr: errMissingCursor.Error()},
+		{name: "invalid timestamp", line: `{"__CURSOR":"c","__REALTIME_TIMESTAMP":"now"}`, expectedErr: "invalid __REALTIME_TIMESTAMP \"now\""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := parseEntry([]byte(test.line), defaultFields, pdata.NewLogRecord())
+			assert.EqualError(t, err, test.expectedErr)
+		})
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package journaldreceiver
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"strings"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/receiver/receiverhelper"
+)
+
+const (
+	// The value of "type" key in configuration.
+	typeStr = "journald"
+
+	defaultJournalctlPath = "journalctl"
+
+	startAtEnd       = "end"
+	startAtBeginning = "beginning"
+)
+
+// defaultFields are the attributes of the log records when "fields" is not
+// set. They are not set in the default configuration since the configured
+// fields would be merged with them.
+var defaultFields = map[string]string{
+	"_HOSTNAME":         "host.name",
+	"_SYSTEMD_UNIT":     "systemd.unit",
+	"SYSLOG_IDENTIFIER": "syslog.identifier",
+	"_PID":              "journald.pid",
+}
+
+var (
+	errNotLinux        = errors.New("journald receiver only available on Linux")
+	errInvalidStartAt  = errors.New("\"start_at\" must be either \"end\" or \"beginning\"")
+	errInvalidPriority = errors.New("\"priority\" must be a priority, \"emerg\" (0) to \"debug\" (7), or a range of priorities such as \"err..warning\"")
+	errEmptyUnit       = errors.New("\"units\" cannot contain empty units")
+	errEmptyAttribute  = errors.New("\"fields\" cannot map a field to an empty attribute")
+)
+
+// NewFactory creates a factory for the journald receiver.
+func NewFactory() component.ReceiverFactory {
+	return receiverhelper.NewFactory(
+		typeStr,
+		createDefaultConfig,
+		receiverhelper.WithLogs(createLogsReceiver))
+}
+
+func createDefaultConfig() configmodels.Receiver {
+	return &Config{
+		ReceiverSettings: configmodels.ReceiverSettings{
+			TypeVal: typeStr,
+			NameVal: typeStr,
+		},
+		JournalctlPath: defaultJournalctlPath,
+		StartAt:        startAtEnd,
+	}
+}
+
+func createLogsReceiver(
+	_ context.Context,
+	params component.ReceiverCreateParams,
+	cfg configmodels.Receiver,
+	nextConsumer consumer.LogsConsumer,
+) (component.LogsReceiver, error) {
+	if runtime.GOOS != "linux" {
+		return nil, errNotLinux
+	}
+	c := cfg.(*Config)
+	if err := validateConfig(c); err != nil {
+		return nil, err
+	}
+	return newJournaldReceiver(params.Logger, c, nextConsumer), nil
+}
+
+func validateConfig(cfg *Config) error {
+	if cfg.StartAt != startAtEnd && cfg.StartAt != startAtBeginning {
+		return errInvalidStartAt
+	}
+	if cfg.Priority != "" {
+		for _, priority := range strings.SplitN(cfg.Priority, "..", 2) {
+			if !isPriority(priority) {
+				return errInvalidPriority
+			}
+		}
+	}
+	for _, unit := range cfg.Units {
+		if unit == "" {
+			return errEmptyUnit
+		}
+	}
+	for _, attribute := range cfg.Fields {
+		if attribute == "" {
+			return errEmptyAttribute
+		}
+	}
+	return nil
+}
+
+// isPriority returns whether priority is the name or the number of a priority.
+func isPriority(priority string) bool {
+	for i, severity := range severities {
+		if priority == severity.text || (len(priority) == 1 && int(priority[0]-'0') == i) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package journaldreceiver
+
+import (
+	"context"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configcheck"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+)
+
+func TestCreateDefaultConfig(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	assert.NotNil(t, cfg, "failed to create default config")
+	assert.NoError(t, configcheck.ValidateConfig(cfg))
+	assert.Equal(t, defaultJournalctlPath, cfg.JournalctlPath)
+	assert.Equal(t, startAtEnd, cfg.StartAt)
+	assert.Nil(t, cfg.Fields)
+}
+
+func TestCreateLogsReceiver(t *testing.T) {
+	params := component.ReceiverCreateParams{Logger: zap.NewNop()}
+	r, err := NewFactory().CreateLogsReceiver(context.Background(), params, createDefaultConfig(), consumertest.NewLogsNop())
+	if runtime.GOOS != "linux" {
+		assert.Equal(t, errNotLinux, err)
+		assert.Nil(t, r)
+		return
+	}
+	require.NoError(t, err)
+	assert.NotNil(t, r)
+}
+
+func TestValidateConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		modify      func(cfg *Config)
+		expectedErr error
+	}{
+		{
+			name:   "priority name",
+			modify: func(cfg *Config) { cfg.Priority = "warning" },
+		},
+		{
+			name:   "priority range",
+			modify: func(cfg *Config) { cfg.Priority = "0..err" },
+		},
+		{
+			name:        "invalid priority",
+			modify:      func(cfg *Config) { cfg.Priority = "8" },
+			expectedErr: errInvalidPriority,
+		},
+		{
+			name:        "invalid priority range",
+			modify:      func(cfg *Config) { cfg.Priority = "err.." },
+			expectedErr: errInvalidPriority,
+		},
+		{
+			name:        "invalid start_at",
+			modify:      func(cfg *Config) { cfg.StartAt = "now" },
+			expectedErr: errInvalidStartAt,
+		},
+		{
+			name:        "empty unit",
+			modify:      func(cfg *Config) { cfg.Units = []string{"ssh.service", ""} },
+			expectedErr: errEmptyUnit,
+		},
+		{
+			name:        "empty attribute",
+			modify:      func(cfg *Config) { cfg.Fields = map[string]string{"_PID": ""} },
+			expectedErr: errEmptyAttribute,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			test.modify(cfg)
+			assert.Equal(t, test.expectedErr, validateConfig(cfg))
+		})
+	}
+}
@@ -0,0 +1,272 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package journaldreceiver
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/obsreport"
+)
+
+const (
+	// The maximum number of log records of the batches, the entries read
+	// while more output is buffered being sent in the same batch.
+	maxBatchSize = 100
+
+	// The maximum size of the stderr output reported when journalctl exits.
+	maxStderrSize = 4 * 1024
+)
+
+const (
+	dataFormat = "journald"
+	transport  = "journalctl"
+)
+
+// A global var that is available only for testing
+var restartDelay = 10 * time.Second
+
+type journaldReceiver struct {
+	config *Config
+	logger *zap.Logger
+	next   consumer.LogsConsumer
+	fields map[string]string
+
+	// cursor is the cursor of the last entry sent, only accessed by the run
+	// goroutine once started.
+	cursor string
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func newJournaldReceiver(logger *zap.Logger, config *Config, next consumer.LogsConsumer) *journaldReceiver {
+	fields := defaultFields
+	if len(config.Fields) > 0 {
+		// The journal fields are uppercase, the keys of the configuration
+		// being lowercased when loaded.
+		fields = make(map[string]string, len(config.Fields))
+		for field, attribute := range config.Fields {
+			fields[strings.ToUpper(field)] = attribute
+		}
+	}
+	return &journaldReceiver{
+		config: config,
+		logger: logger,
+		next:   next,
+		fields: fields,
+	}
+}
+
+func (r *journaldReceiver) Start(_ context.Context, _ component.Host) error {
+	if r.config.CursorFile != "" {
+		cursor, err := ioutil.ReadFile(r.config.CursorFile)
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to read the cursor file: %w", err)
+		}
+		r.cursor = strings.TrimSpace(string(cursor))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+	r.done = make(chan struct{})
+	go func() {
+		defer close(r.done)
+		r.run(ctx)
+	}()
+	return nil
+}
+
+// Shutdown stops journalctl, waiting for it to exit until ctx is done.
+func (r *journaldReceiver) Shutdown(ctx context.Context) error {
+	if r.cancel == nil {
+		return nil
+	}
+	r.cancel()
+	select {
+	case <-r.done:
+	case <-ctx.Done():
+	}
+	return nil
+}
+
+// run runs journalctl until ctx is done, restarting it after restartDelay
+// when it exits.
+func (r *journaldReceiver) run(ctx context.Context) {
+	for {
+		err := r.runJournalctl(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		r.logger.Error("journalctl exited, restarting it", zap.Error(err), zap.Duration("delay", restartDelay))
+		select {
+		case <-time.After(restartDelay):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// args returns the arguments of journalctl, the entries being read after the
+// cursor of the last entry sent if any.
+func (r *journaldReceiver) args() []string {
+	args := []string{"--output=json", "--follow", "--no-pager"}
+	switch {
+	case r.cursor != "":
+		args = append(args, "--after-cursor="+r.cursor, "--no-tail")
+	case r.config.StartAt == startAtBeginning:
+		args = append(args, "--no-tail")
+	default:
+		args = append(args, "--lines=0")
+	}
+	if r.config.Directory != "" {
+		args = append(args, "--directory="+r.config.Directory)
+	}
+	for _, unit := range r.config.Units {
+		args = append(args, "--unit="+unit)
+	}
+	if r.config.Priority != "" {
+		args = append(args, "--priority="+r.config.Priority)
+	}
+	return args
+}
+
+// runJournalctl runs journalctl until it exits or ctx is done, sending the
+// entries it prints to the next consumer.
+func (r *journaldReceiver) runJournalctl(ctx context.Context) error {
+	cmd := exec.Command(r.config.JournalctlPath, r.args()...)
+	applyOSSpecificCmdModifications(cmd)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	stderr := &limitedBuffer{limit: maxStderrSize}
+	cmd.Stderr = stderr
+
+	r.logger.Debug("Starting journalctl", zap.String("command", cmd.String()))
+	if err = cmd.Start(); err != nil {
+		return err
+	}
+
+	stopped := make(chan struct{})
+	defer close(stopped)
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = cmd.Process.Signal(syscall.SIGTERM)
+		case <-stopped:
+		}
+	}()
+
+	r.readEntries(stdout)
+	if err = cmd.Wait(); err != nil && stderr.Len() > 0 {
+		err = fmt.Errorf("%w: %s", err, bytes.TrimSpace(stderr.Bytes()))
+	}
+	return err
+}
+
+// readEntries reads the entries until the output of journalctl is closed.
+func (r *journaldReceiver) readEntries(stdout io.Reader) {
+	reader := bufio.NewReader(stdout)
+	logs, records := newLogs()
+	cursor := ""
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			lr := pdata.NewLogRecord()
+			if c, perr := parseEntry(line, r.fields, lr); perr != nil {
+				r.logger.Debug("Failed to parse journal entry", zap.Error(perr))
+			} else {
+				records.Append(lr)
+				cursor = c
+			}
+		}
+		if records.Len() > 0 && (err != nil || reader.Buffered() == 0 || records.Len() >= maxBatchSize) {
+			r.consume(logs)
+			r.saveCursor(cursor)
+			logs, records = newLogs()
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+func newLogs() (pdata.Logs, pdata.LogSlice) {
+	logs := pdata.NewLogs()
+	logs.ResourceLogs().Resize(1)
+	rl := logs.ResourceLogs().At(0)
+	rl.InstrumentationLibraryLogs().Resize(1)
+	return logs, rl.InstrumentationLibraryLogs().At(0).Logs()
+}
+
+func (r *journaldReceiver) consume(logs pdata.Logs) {
+	numRecords := logs.LogRecordCount()
+	ctx := obsreport.ReceiverContext(context.Background(), r.config.Name(), transport)
+	ctx = obsreport.StartLogsReceiveOp(ctx, r.config.Name(), transport)
+	err := r.next.ConsumeLogs(ctx, logs)
+	obsreport.EndLogsReceiveOp(ctx, dataFormat, numRecords, err)
+	if err != nil {
+		r.logger.Error("Failed to send journal entries", zap.Error(err))
+	}
+}
+
+// saveCursor sets the cursor of the last entry sent, persisting it to the
+// cursor file if configured. The file is replaced atomically so that it is
+// not left truncated if the collector is stopped while writing it.
+func (r *journaldReceiver) saveCursor(cursor string) {
+	r.cursor = cursor
+	if r.config.CursorFile == "" {
+		return
+	}
+	tmpFile := filepath.Join(filepath.Dir(r.config.CursorFile), "."+filepath.Base(r.config.CursorFile)+".tmp")
+	err := ioutil.WriteFile(tmpFile, []byte(cursor+"\n"), 0600)
+	if err == nil {
+		err = os.Rename(tmpFile, r.config.CursorFile)
+	}
+	if err != nil {
+		r.logger.Error("Failed to persist the journal cursor", zap.Error(err))
+	}
+}
+
+// limitedBuffer is a buffer keeping the first limit bytes written to it.
+type limitedBuffer struct {
+	bytes.Buffer
+	limit int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if n := b.limit - b.Len(); n < len(p) {
+		b.Buffer.Write(p[:n])
+	} else {
+		b.Buffer.Write(p)
+	}
+	return len(p), nil
+}
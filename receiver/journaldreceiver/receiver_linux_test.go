@@ -0,0 +1,149 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build linux
+
+package journaldreceiver
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+)
+
+const testEntries = `{"__CURSOR":"c1","__REALTIME_TIMESTAMP":"1615809600000000","PRIORITY":"6","_SYSTEMD_UNIT":"ssh.service","MESSAGE":"first"}
+invalid
+{"__CURSOR":"c2","__REALTIME_TIMESTAMP":"1615809601000000","PRIORITY":"4","_SYSTEMD_UNIT":"ssh.service","MESSAGE":"second"}
+`
+
+// writeJournalctl writes a fake journalctl to dir, appending its arguments to
+// the "args" file and printing the test entries. It then waits to be
+// stopped, or exits with an error if exit is set.
+func writeJournalctl(t *testing.T, dir string, exit bool) string {
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "entries"), []byte(testEntries), 0600))
+	end := "exec sleep 60"
+	if exit {
+		end = "echo failed >&2; exit 1"
+	}
+	script := fmt.Sprintf("#!/bin/sh\necho \"$@\" >> %[1]s/args\ncat %[1]s/entries\n%[2]s\n", dir, end)
+	path := filepath.Join(dir, "journalctl")
+	require.NoError(t, ioutil.WriteFile(path, []byte(script), 0700))
+	return path
+}
+
+func readArgs(t *testing.T, dir string) []string {
+	args, err := ioutil.ReadFile(filepath.Join(dir, "args"))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	require.NoError(t, err)
+	return strings.Split(strings.TrimSpace(string(args)), "\n")
+}
+
+func newTestReceiver(t *testing.T, dir string, exit bool) (*journaldReceiver, *consumertest.LogsSink) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.JournalctlPath = writeJournalctl(t, dir, exit)
+	cfg.CursorFile = filepath.Join(dir, "cursor")
+	cfg.Units = []string{"ssh.service"}
+	sink := new(consumertest.LogsSink)
+	r := newJournaldReceiver(zap.NewNop(), cfg, sink)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	return r, sink
+}
+
+func TestReceiver(t *testing.T) {
+	dir, err := ioutil.TempDir("", "journald")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	r, sink := newTestReceiver(t, dir, false)
+	require.Eventually(t, func() bool {
+		return sink.LogRecordsCount() == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	var bodies []string
+	for _, logs := range sink.AllLogs() {
+		records := logs.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs()
+		for i := 0; i < records.Len(); i++ {
+			bodies = append(bodies, records.At(i).Body().StringVal())
+		}
+	}
+	assert.Equal(t, []string{"first", "second"}, bodies)
+	assert.Equal(t, []string{"--output=json --follow --no-pager --lines=0 --unit=ssh.service"}, readArgs(t, dir))
+	cursor, err := ioutil.ReadFile(filepath.Join(dir, "cursor"))
+	require.NoError(t, err)
+	assert.Equal(t, "c2\n", string(cursor))
+
+	// journalctl is stopped.
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, r.Shutdown(shutdownCtx))
+	assert.NoError(t, shutdownCtx.Err())
+}
+
+func TestReceiverPersistedCursor(t *testing.T) {
+	dir, err := ioutil.TempDir("", "journald")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "cursor"), []byte("c0\n"), 0600))
+
+	r, sink := newTestReceiver(t, dir, false)
+	defer r.Shutdown(context.Background())
+	require.Eventually(t, func() bool {
+		return sink.LogRecordsCount() == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"--output=json --follow --no-pager --after-cursor=c0 --no-tail --unit=ssh.service"}, readArgs(t, dir))
+}
+
+func TestReceiverRestart(t *testing.T) {
+	defer func(delay time.Duration) { restartDelay = delay }(restartDelay)
+	restartDelay = 10 * time.Millisecond
+
+	dir, err := ioutil.TempDir("", "journald")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	r, _ := newTestReceiver(t, dir, true)
+	defer r.Shutdown(context.Background())
+	require.Eventually(t, func() bool {
+		return len(readArgs(t, dir)) >= 2
+	}, 5*time.Second, 10*time.Millisecond)
+	// journalctl is restarted after the last entry sent.
+	assert.Equal(t, "--output=json --follow --no-pager --after-cursor=c2 --no-tail --unit=ssh.service", readArgs(t, dir)[1])
+}
+
+func TestStartInvalidCursorFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "journald")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	cfg := createDefaultConfig().(*Config)
+	// The cursor file is a directory.
+	cfg.CursorFile = dir
+	r := newJournaldReceiver(zap.NewNop(), cfg, consumertest.NewLogsNop())
+	assert.Error(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	assert.NoError(t, r.Shutdown(context.Background()))
+}
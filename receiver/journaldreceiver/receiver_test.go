@@ -0,0 +1,87 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package journaldreceiver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/consumer/consumertest"
+)
+
+func TestArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		modify   func(cfg *Config)
+		cursor   string
+		expected []string
+	}{
+		{
+			name:     "default",
+			modify:   func(*Config) {},
+			expected: []string{"--output=json", "--follow", "--no-pager", "--lines=0"},
+		},
+		{
+			name:     "beginning",
+			modify:   func(cfg *Config) { cfg.StartAt = startAtBeginning },
+			expected: []string{"--output=json", "--follow", "--no-pager", "--no-tail"},
+		},
+		{
+			name: "cursor and filters",
+			modify: func(cfg *Config) {
+				cfg.Directory = "/var/log/journal"
+				cfg.Units = []string{"ssh.service", "kubelet.service"}
+				cfg.Priority = "warning"
+			},
+			cursor: "s=1;i=2",
+			expected: []string{
+				"--output=json", "--follow", "--no-pager", "--after-cursor=s=1;i=2", "--no-tail",
+				"--directory=/var/log/journal", "--unit=ssh.service", "--unit=kubelet.service", "--priority=warning",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			test.modify(cfg)
+			r := newJournaldReceiver(zap.NewNop(), cfg, consumertest.NewLogsNop())
+			r.cursor = test.cursor
+			assert.Equal(t, test.expected, r.args())
+		})
+	}
+}
+
+func TestFieldsCase(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	r := newJournaldReceiver(zap.NewNop(), cfg, consumertest.NewLogsNop())
+	assert.Equal(t, defaultFields, r.fields)
+
+	cfg.Fields = map[string]string{"_boot_id": "journald.boot_id"}
+	r = newJournaldReceiver(zap.NewNop(), cfg, consumertest.NewLogsNop())
+	assert.Equal(t, map[string]string{"_BOOT_ID": "journald.boot_id"}, r.fields)
+}
+
+func TestLimitedBuffer(t *testing.T) {
+	b := &limitedBuffer{limit: 4}
+	n, err := b.Write([]byte("abc"))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, n)
+	n, err = b.Write([]byte("def"))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, n)
+	assert.Equal(t, "abcd", b.String())
+}
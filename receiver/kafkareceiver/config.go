@@ -30,10 +30,17 @@ type Config struct {
 	Topic string `mapstructure:"topic"`
 	// Encoding of the messages (default "otlp_proto")
 	Encoding string `mapstructure:"encoding"`
+	// The kafka topics to consume from, with the encoding of their messages. Topic is
+	// ignored when set.
+	Topics []TopicConfig `mapstructure:"topics"`
 	// The consumer group that receiver will be consuming messages from (default "otel-collector")
 	GroupID string `mapstructure:"group_id"`
 	// The consumer client ID that receiver will use (default "otel-collector")
 	ClientID string `mapstructure:"client_id"`
+	// When the offsets of the messages are committed, either "after_consume" when the
+	// messages are consumed from kafka, or "after_export" when the next consumer succeeds
+	// (default "after_consume")
+	OffsetCommitMode string `mapstructure:"offset_commit_mode"`
 
 	// Metadata is the namespace for metadata management properties used by the
 	// Client, and shared by the Producer/Consumer.
@@ -41,3 +48,13 @@ type Config struct {
 
 	Authentication kafkaexporter.Authentication `mapstructure:"auth"`
 }
+
+// TopicConfig defines a kafka topic, or the kafka topics matching a pattern, to consume from.
+type TopicConfig struct {
+	// The name of the kafka topic
+	Name string `mapstructure:"name"`
+	// The regular expression matching the names of the kafka topics
+	Pattern string `mapstructure:"pattern"`
+	// Encoding of the messages (default the encoding of the receiver)
+	Encoding string `mapstructure:"encoding"`
+}
@@ -37,7 +37,7 @@ func TestLoadConfig(t *testing.T) {
 	factories.Receivers[typeStr] = factory
 	cfg, err := configtest.LoadConfigFile(t, path.Join(".", "testdata", "config.yaml"), factories)
 	require.NoError(t, err)
-	require.Equal(t, 1, len(cfg.Receivers))
+	require.Equal(t, 2, len(cfg.Receivers))
 
 	r := cfg.Receivers[typeStr].(*Config)
 	assert.Equal(t, &Config{
@@ -45,11 +45,12 @@ func TestLoadConfig(t *testing.T) {
 			NameVal: typeStr,
 			TypeVal: typeStr,
 		},
-		Topic:    "spans",
-		Encoding: "otlp_proto",
-		Brokers:  []string{"foo:123", "bar:456"},
-		ClientID: "otel-collector",
-		GroupID:  "otel-collector",
+		Topic:            "spans",
+		Encoding:         "otlp_proto",
+		Brokers:          []string{"foo:123", "bar:456"},
+		ClientID:         "otel-collector",
+		GroupID:          "otel-collector",
+		OffsetCommitMode: "after_export",
 		Authentication: kafkaexporter.Authentication{
 			TLS: &configtls.TLSClientSetting{
 				TLSSetting: configtls.TLSSetting{
@@ -67,4 +68,29 @@ func TestLoadConfig(t *testing.T) {
 			},
 		},
 	}, r)
+
+	r = cfg.Receivers[typeStr+"/topics"].(*Config)
+	assert.Equal(t, &Config{
+		ReceiverSettings: configmodels.ReceiverSettings{
+			NameVal: typeStr + "/topics",
+			TypeVal: typeStr,
+		},
+		Topic:    defaultTopic,
+		Encoding: "jaeger_proto",
+		Topics: []TopicConfig{
+			{Name: "otlp_spans", Encoding: "otlp_proto"},
+			{Pattern: "^jaeger-.*"},
+		},
+		Brokers:          []string{"foo:123"},
+		ClientID:         defaultClientID,
+		GroupID:          defaultGroupID,
+		OffsetCommitMode: defaultOffsetCommitMode,
+		Metadata: kafkaexporter.Metadata{
+			Full: defaultMetadataFull,
+			Retry: kafkaexporter.MetadataRetry{
+				Max:     defaultMetadataRetryMax,
+				Backoff: defaultMetadataRetryBackoff,
+			},
+		},
+	}, r)
 }
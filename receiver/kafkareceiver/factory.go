@@ -33,6 +33,8 @@ const (
 	defaultClientID = "otel-collector"
 	defaultGroupID  = defaultClientID
 
+	defaultOffsetCommitMode = offsetCommitAfterConsume
+
 	// default from sarama.NewConfig()
 	defaultMetadataRetryMax = 3
 	// default from sarama.NewConfig()
@@ -73,11 +75,12 @@ func createDefaultConfig() configmodels.Receiver {
 			TypeVal: typeStr,
 			NameVal: typeStr,
 		},
-		Topic:    defaultTopic,
-		Encoding: defaultEncoding,
-		Brokers:  []string{defaultBroker},
-		ClientID: defaultClientID,
-		GroupID:  defaultGroupID,
+		Topic:            defaultTopic,
+		Encoding:         defaultEncoding,
+		Brokers:          []string{defaultBroker},
+		ClientID:         defaultClientID,
+		GroupID:          defaultGroupID,
+		OffsetCommitMode: defaultOffsetCommitMode,
 		Metadata: kafkaexporter.Metadata{
 			Full: defaultMetadataFull,
 			Retry: kafkaexporter.MetadataRetry{
@@ -16,8 +16,11 @@ package kafkareceiver
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"regexp"
 	"sync"
+	"time"
 
 	"github.com/Shopify/sarama"
 	"go.opencensus.io/stats"
@@ -26,28 +29,51 @@ import (
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/exporter/kafkaexporter"
 	"go.opentelemetry.io/collector/obsreport"
 )
 
 const (
 	transport = "kafka"
+
+	offsetCommitAfterConsume = "after_consume"
+	offsetCommitAfterExport  = "after_export"
 )
 
-var errUnrecognizedEncoding = fmt.Errorf("unrecognized encoding")
+var (
+	errUnrecognizedEncoding = fmt.Errorf("unrecognized encoding")
+	errInvalidTopic         = errors.New("exactly one of \"name\" and \"pattern\" must be set for each of the \"topics\"")
+	errNoTopics             = errors.New("no kafka topic to consume from")
+	errUnrecognizedCommit   = errors.New("unrecognized offset_commit_mode, expecting \"after_consume\" or \"after_export\"")
+)
 
 // kafkaConsumer uses sarama to consume and handle messages from kafka.
 type kafkaConsumer struct {
 	name              string
+	client            sarama.Client
 	consumerGroup     sarama.ConsumerGroup
 	nextConsumer      consumer.TracesConsumer
-	topics            []string
+	topics            []topicUnmarshaller
 	cancelConsumeLoop context.CancelFunc
 	unmarshaller      Unmarshaller
+	commitAfterExport bool
+	// listTopics lists the topics of the cluster, to resolve the topic patterns.
+	listTopics func() ([]string, error)
+	// resolveBackoff is how long to wait before resolving the topics again when none is found.
+	resolveBackoff time.Duration
 
 	logger *zap.Logger
 }
 
+// topicUnmarshaller is the unmarshaller of the messages of a topic, or of the topics
+// matching a pattern.
+type topicUnmarshaller struct {
+	name         string
+	pattern      *regexp.Regexp
+	unmarshaller Unmarshaller
+}
+
 var _ component.Receiver = (*kafkaConsumer)(nil)
 
 func newReceiver(config Config, params component.ReceiverCreateParams, unmarshalers map[string]Unmarshaller, nextConsumer consumer.TracesConsumer) (*kafkaConsumer, error) {
@@ -55,6 +81,18 @@ func newReceiver(config Config, params component.ReceiverCreateParams, unmarshal
 	if unmarshaller == nil {
 		return nil, errUnrecognizedEncoding
 	}
+	topics, err := newTopicUnmarshallers(config, unmarshaller, unmarshalers)
+	if err != nil {
+		return nil, err
+	}
+	var commitAfterExport bool
+	switch config.OffsetCommitMode {
+	case "", offsetCommitAfterConsume:
+	case offsetCommitAfterExport:
+		commitAfterExport = true
+	default:
+		return nil, errUnrecognizedCommit
+	}
 
 	c := sarama.NewConfig()
 	c.ClientID = config.ClientID
@@ -71,41 +109,145 @@ func newReceiver(config Config, params component.ReceiverCreateParams, unmarshal
 	if err := kafkaexporter.ConfigureAuthentication(config.Authentication, c); err != nil {
 		return nil, err
 	}
-	client, err := sarama.NewConsumerGroup(config.Brokers, config.GroupID, c)
+	client, err := sarama.NewClient(config.Brokers, c)
+	if err != nil {
+		return nil, err
+	}
+	consumerGroup, err := sarama.NewConsumerGroupFromClient(config.GroupID, client)
 	if err != nil {
+		client.Close()
 		return nil, err
 	}
 	return &kafkaConsumer{
-		name:          config.Name(),
-		consumerGroup: client,
-		topics:        []string{config.Topic},
-		nextConsumer:  nextConsumer,
-		unmarshaller:  unmarshaller,
-		logger:        params.Logger,
+		name:              config.Name(),
+		client:            client,
+		consumerGroup:     consumerGroup,
+		topics:            topics,
+		nextConsumer:      nextConsumer,
+		unmarshaller:      unmarshaller,
+		commitAfterExport: commitAfterExport,
+		listTopics: func() ([]string, error) {
+			if err := client.RefreshMetadata(); err != nil {
+				return nil, err
+			}
+			return client.Topics()
+		},
+		resolveBackoff: config.Metadata.Retry.Backoff,
+		logger:         params.Logger,
 	}, nil
 }
 
+// newTopicUnmarshallers returns the topics to consume from, config.Topic when no
+// topics are configured, with the unmarshaller of their encoding.
+func newTopicUnmarshallers(config Config, unmarshaller Unmarshaller, unmarshalers map[string]Unmarshaller) ([]topicUnmarshaller, error) {
+	if len(config.Topics) == 0 {
+		return []topicUnmarshaller{{name: config.Topic, unmarshaller: unmarshaller}}, nil
+	}
+	topics := make([]topicUnmarshaller, 0, len(config.Topics))
+	for _, topic := range config.Topics {
+		if (topic.Name == "") == (topic.Pattern == "") {
+			return nil, errInvalidTopic
+		}
+		t := topicUnmarshaller{name: topic.Name, unmarshaller: unmarshaller}
+		if topic.Pattern != "" {
+			pattern, err := regexp.Compile(topic.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid topic pattern %q: %w", topic.Pattern, err)
+			}
+			t.pattern = pattern
+		}
+		if topic.Encoding != "" {
+			if t.unmarshaller = unmarshalers[topic.Encoding]; t.unmarshaller == nil {
+				return nil, errUnrecognizedEncoding
+			}
+		}
+		topics = append(topics, t)
+	}
+	return topics, nil
+}
+
+// resolveTopics returns the names of the topics to consume from with their unmarshaller,
+// the first configured topic matching a name taking precedence.
+func (c *kafkaConsumer) resolveTopics() ([]string, map[string]Unmarshaller, error) {
+	var names []string
+	unmarshallers := make(map[string]Unmarshaller)
+	add := func(name string, unmarshaller Unmarshaller) {
+		if _, ok := unmarshallers[name]; !ok {
+			names = append(names, name)
+			unmarshallers[name] = unmarshaller
+		}
+	}
+
+	var clusterTopics []string
+	for _, topic := range c.topics {
+		if topic.pattern == nil {
+			add(topic.name, topic.unmarshaller)
+			continue
+		}
+		if clusterTopics == nil {
+			var err error
+			if clusterTopics, err = c.listTopics(); err != nil {
+				return nil, nil, err
+			}
+		}
+		for _, name := range clusterTopics {
+			if topic.pattern.MatchString(name) {
+				add(name, topic.unmarshaller)
+			}
+		}
+	}
+	return names, unmarshallers, nil
+}
+
 func (c *kafkaConsumer) Start(context.Context, component.Host) error {
+	// Fail fast when no topic matches rather than waiting for a session.
+	topics, _, err := c.resolveTopics()
+	if err != nil {
+		return err
+	}
+	if len(topics) == 0 {
+		return errNoTopics
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 	c.cancelConsumeLoop = cancel
 	consumerGroup := &consumerGroupHandler{
-		name:         c.name,
-		logger:       c.logger,
-		unmarshaller: c.unmarshaller,
-		nextConsumer: c.nextConsumer,
-		ready:        make(chan bool),
+		name:              c.name,
+		logger:            c.logger,
+		unmarshaller:      c.unmarshaller,
+		nextConsumer:      c.nextConsumer,
+		commitAfterExport: c.commitAfterExport,
+		ready:             make(chan bool),
 	}
 	go c.consumeLoop(ctx, consumerGroup)
 	<-consumerGroup.ready
 	return nil
 }
 
-func (c *kafkaConsumer) consumeLoop(ctx context.Context, handler sarama.ConsumerGroupHandler) error {
+func (c *kafkaConsumer) consumeLoop(ctx context.Context, handler *consumerGroupHandler) error {
 	for {
+		// The topics are resolved again for each session, the topics matching the patterns
+		// may have changed.
+		topics, unmarshallers, err := c.resolveTopics()
+		if err == nil && len(topics) == 0 {
+			err = errNoTopics
+		}
+		if err != nil {
+			c.logger.Error("Error resolving topics", zap.Error(err))
+			select {
+			case <-ctx.Done():
+				c.logger.Info("Consumer stopped", zap.Error(ctx.Err()))
+				return ctx.Err()
+			case <-time.After(c.resolveBackoff):
+				continue
+			}
+		}
+		handler.unmarshallers = unmarshallers
+
 		// `Consume` should be called inside an infinite loop, when a
 		// server-side rebalance happens, the consumer session will need to be
 		// recreated to get the new claims
-		if err := c.consumerGroup.Consume(ctx, c.topics, handler); err != nil {
+		if err := c.consumerGroup.Consume(ctx, topics, handler); err != nil {
 			c.logger.Error("Error from consumer", zap.Error(err))
 		}
 		// check if context was cancelled, signaling that the consumer should stop
@@ -118,15 +260,26 @@ func (c *kafkaConsumer) consumeLoop(ctx context.Context, handler sarama.Consumer
 
 func (c *kafkaConsumer) Shutdown(context.Context) error {
 	c.cancelConsumeLoop()
-	return c.consumerGroup.Close()
+	err := c.consumerGroup.Close()
+	if c.client != nil {
+		if cerr := c.client.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+	return err
 }
 
 type consumerGroupHandler struct {
-	name         string
-	unmarshaller Unmarshaller
-	nextConsumer consumer.TracesConsumer
-	ready        chan bool
-	readyCloser  sync.Once
+	name string
+	// unmarshaller is used for the topics without an unmarshaller in unmarshallers.
+	unmarshaller  Unmarshaller
+	unmarshallers map[string]Unmarshaller
+	nextConsumer  consumer.TracesConsumer
+	// commitAfterExport marks the messages once the next consumer succeeds instead of
+	// when they are claimed, so that they are consumed again after a failure.
+	commitAfterExport bool
+	ready             chan bool
+	readyCloser       sync.Once
 
 	logger *zap.Logger
 }
@@ -150,12 +303,18 @@ func (c *consumerGroupHandler) Cleanup(session sarama.ConsumerGroupSession) erro
 
 func (c *consumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
 	c.logger.Info("Starting consumer group", zap.Int32("partition", claim.Partition()))
+	unmarshaller := c.unmarshaller
+	if topicUnmarshaller, ok := c.unmarshallers[claim.Topic()]; ok {
+		unmarshaller = topicUnmarshaller
+	}
 	for message := range claim.Messages() {
 		c.logger.Debug("Kafka message claimed",
 			zap.String("value", string(message.Value)),
 			zap.Time("timestamp", message.Timestamp),
 			zap.String("topic", message.Topic))
-		session.MarkMessage(message, "")
+		if !c.commitAfterExport {
+			session.MarkMessage(message, "")
+		}
 
 		ctx := obsreport.ReceiverContext(session.Context(), c.name, transport)
 		ctx = obsreport.StartTraceDataReceiveOp(ctx, c.name, transport)
@@ -165,14 +324,21 @@ func (c *consumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSession,
 			statMessageOffset.M(message.Offset),
 			statMessageOffsetLag.M(claim.HighWaterMarkOffset()-message.Offset-1))
 
-		traces, err := c.unmarshaller.Unmarshal(message.Value)
+		traces, err := unmarshaller.Unmarshal(message.Value)
 		if err != nil {
 			c.logger.Error("failed to unmarshall message", zap.Error(err))
+			// The message cannot be unmarshalled, consuming it again would fail again.
+			if c.commitAfterExport {
+				session.MarkMessage(message, "")
+			}
 			return err
 		}
 
 		err = c.nextConsumer.ConsumeTraces(session.Context(), traces)
-		obsreport.EndTraceDataReceiveOp(ctx, c.unmarshaller.Encoding(), traces.SpanCount(), err)
+		obsreport.EndTraceDataReceiveOp(ctx, unmarshaller.Encoding(), traces.SpanCount(), err)
+		if c.commitAfterExport && (err == nil || consumererror.IsPermanent(err)) {
+			session.MarkMessage(message, "")
+		}
 		if err != nil {
 			return err
 		}
@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"regexp"
 	"sync"
 	"testing"
 	"time"
@@ -32,6 +33,8 @@ import (
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/exporter/kafkaexporter"
@@ -85,6 +88,7 @@ func TestReceiverStart(t *testing.T) {
 		nextConsumer:  consumertest.NewTracesNop(),
 		logger:        zap.NewNop(),
 		consumerGroup: testClient,
+		topics:        []topicUnmarshaller{{name: defaultTopic}},
 	}
 
 	err := c.Start(context.Background(), nil)
@@ -98,6 +102,7 @@ func TestReceiverStartConsume(t *testing.T) {
 		nextConsumer:  consumertest.NewTracesNop(),
 		logger:        zap.NewNop(),
 		consumerGroup: testClient,
+		topics:        []topicUnmarshaller{{name: defaultTopic}},
 	}
 	ctx, cancelFunc := context.WithCancel(context.Background())
 	c.cancelConsumeLoop = cancelFunc
@@ -118,6 +123,7 @@ func TestReceiver_error(t *testing.T) {
 		nextConsumer:  consumertest.NewTracesNop(),
 		logger:        logger,
 		consumerGroup: testClient,
+		topics:        []topicUnmarshaller{{name: defaultTopic}},
 	}
 
 	err := c.Start(context.Background(), nil)
@@ -232,6 +238,238 @@ func TestConsumerGroupHandler_error_nextConsumer(t *testing.T) {
 	wg.Wait()
 }
 
+func TestConsumerGroupHandler_topicUnmarshaller(t *testing.T) {
+	sink := new(consumertest.TracesSink)
+	c := consumerGroupHandler{
+		unmarshaller:  &otlpProtoUnmarshaller{},
+		unmarshallers: map[string]Unmarshaller{testTopic: zipkinJSONSpanUnmarshaller{}},
+		logger:        zap.NewNop(),
+		ready:         make(chan bool),
+		nextConsumer:  sink,
+	}
+
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	groupClaim := &testConsumerGroupClaim{
+		messageChan: make(chan *sarama.ConsumerMessage),
+	}
+	go func() {
+		err := c.ConsumeClaim(testConsumerGroupSession{}, groupClaim)
+		assert.NoError(t, err)
+		wg.Done()
+	}()
+	groupClaim.messageChan <- &sarama.ConsumerMessage{Value: []byte(`[{"traceId":"0000000000000001","id":"0000000000000002","name":"test"}]`)}
+	close(groupClaim.messageChan)
+	wg.Wait()
+	assert.Equal(t, 1, sink.SpansCount())
+}
+
+func TestNewTopicUnmarshallers(t *testing.T) {
+	unmarshallers := defaultUnmarshallers()
+	otlp := unmarshallers[defaultEncoding]
+	tests := []struct {
+		name    string
+		topics  []TopicConfig
+		want    []topicUnmarshaller
+		wantErr string
+	}{
+		{
+			name: "default topic",
+			want: []topicUnmarshaller{{name: "spans", unmarshaller: otlp}},
+		},
+		{
+			name: "topics",
+			topics: []TopicConfig{
+				{Name: "otlp"},
+				{Pattern: "^zipkin-.*", Encoding: "zipkin_json"},
+			},
+			want: []topicUnmarshaller{
+				{name: "otlp", unmarshaller: otlp},
+				{pattern: regexp.MustCompile("^zipkin-.*"), unmarshaller: unmarshallers["zipkin_json"]},
+			},
+		},
+		{
+			name:    "name and pattern",
+			topics:  []TopicConfig{{Name: "otlp", Pattern: "otlp"}},
+			wantErr: errInvalidTopic.Error(),
+		},
+		{
+			name:    "no name nor pattern",
+			topics:  []TopicConfig{{Encoding: "zipkin_json"}},
+			wantErr: errInvalidTopic.Error(),
+		},
+		{
+			name:    "invalid pattern",
+			topics:  []TopicConfig{{Pattern: "("}},
+			wantErr: "invalid topic pattern \"(\": error parsing regexp: missing closing ): `(`",
+		},
+		{
+			name:    "unrecognized encoding",
+			topics:  []TopicConfig{{Name: "otlp", Encoding: "foo"}},
+			wantErr: errUnrecognizedEncoding.Error(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Config{Topic: "spans", Encoding: defaultEncoding, Topics: tt.topics}
+			got, err := newTopicUnmarshallers(c, otlp, unmarshallers)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNewReceiver_topics_err(t *testing.T) {
+	c := Config{
+		Encoding: defaultEncoding,
+		Topics:   []TopicConfig{{}},
+	}
+	r, err := newReceiver(c, component.ReceiverCreateParams{}, defaultUnmarshallers(), consumertest.NewTracesNop())
+	assert.EqualError(t, err, errInvalidTopic.Error())
+	assert.Nil(t, r)
+}
+
+func TestResolveTopics(t *testing.T) {
+	otlp := &otlpProtoUnmarshaller{}
+	zipkin := zipkinJSONSpanUnmarshaller{}
+	c := kafkaConsumer{
+		topics: []topicUnmarshaller{
+			{name: "zipkin-legacy", unmarshaller: otlp},
+			{pattern: regexp.MustCompile("^zipkin-"), unmarshaller: zipkin},
+			{name: "otlp", unmarshaller: otlp},
+		},
+		listTopics: func() ([]string, error) {
+			return []string{"otlp", "zipkin-legacy", "zipkin-frontend", "jaeger"}, nil
+		},
+	}
+	topics, unmarshallers, err := c.resolveTopics()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"zipkin-legacy", "zipkin-frontend", "otlp"}, topics)
+	assert.Equal(t, map[string]Unmarshaller{
+		"zipkin-legacy":   otlp,
+		"zipkin-frontend": zipkin,
+		"otlp":            otlp,
+	}, unmarshallers)
+
+	listErr := errors.New("list error")
+	c.listTopics = func() ([]string, error) {
+		return nil, listErr
+	}
+	_, _, err = c.resolveTopics()
+	assert.Equal(t, listErr, err)
+}
+
+func TestReceiverStart_no_topics(t *testing.T) {
+	c := kafkaConsumer{
+		nextConsumer:  consumertest.NewTracesNop(),
+		logger:        zap.NewNop(),
+		consumerGroup: testConsumerGroup{once: &sync.Once{}},
+		topics:        []topicUnmarshaller{{pattern: regexp.MustCompile("^zipkin-")}},
+		listTopics: func() ([]string, error) {
+			return []string{"otlp"}, nil
+		},
+	}
+	assert.Equal(t, errNoTopics, c.Start(context.Background(), nil))
+}
+
+func TestConsumerGroupHandler_commitAfterExport(t *testing.T) {
+	td := pdata.NewTraces()
+	td.ResourceSpans().Resize(1)
+	request := &otlptrace.ExportTraceServiceRequest{
+		ResourceSpans: pdata.TracesToOtlp(td),
+	}
+	bts, err := request.Marshal()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		value      []byte
+		consumer   consumer.TracesConsumer
+		wantErr    bool
+		wantMarked bool
+	}{
+		{
+			name:       "success",
+			value:      bts,
+			consumer:   consumertest.NewTracesNop(),
+			wantMarked: true,
+		},
+		{
+			name:     "next consumer error",
+			value:    bts,
+			consumer: consumertest.NewTracesErr(errors.New("failed to consume")),
+			wantErr:  true,
+		},
+		{
+			name:       "next consumer permanent error",
+			value:      bts,
+			consumer:   consumertest.NewTracesErr(consumererror.Permanent(errors.New("failed to consume"))),
+			wantErr:    true,
+			wantMarked: true,
+		},
+		{
+			name:       "unmarshal error",
+			value:      []byte("!@#"),
+			consumer:   consumertest.NewTracesNop(),
+			wantErr:    true,
+			wantMarked: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := consumerGroupHandler{
+				unmarshaller:      &otlpProtoUnmarshaller{},
+				logger:            zap.NewNop(),
+				ready:             make(chan bool),
+				nextConsumer:      tt.consumer,
+				commitAfterExport: true,
+			}
+
+			session := &markingConsumerGroupSession{}
+			groupClaim := &testConsumerGroupClaim{
+				messageChan: make(chan *sarama.ConsumerMessage, 1),
+			}
+			groupClaim.messageChan <- &sarama.ConsumerMessage{Value: tt.value, Offset: 7}
+			close(groupClaim.messageChan)
+
+			err := c.ConsumeClaim(session, groupClaim)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			if tt.wantMarked {
+				assert.Equal(t, []int64{7}, session.marked)
+			} else {
+				assert.Empty(t, session.marked)
+			}
+		})
+	}
+}
+
+func TestNewReceiver_offset_commit_mode_err(t *testing.T) {
+	c := Config{
+		Encoding:         defaultEncoding,
+		OffsetCommitMode: "never",
+	}
+	r, err := newReceiver(c, component.ReceiverCreateParams{}, defaultUnmarshallers(), consumertest.NewTracesNop())
+	assert.EqualError(t, err, errUnrecognizedCommit.Error())
+	assert.Nil(t, r)
+}
+
+type markingConsumerGroupSession struct {
+	testConsumerGroupSession
+	marked []int64
+}
+
+func (t *markingConsumerGroupSession) MarkMessage(msg *sarama.ConsumerMessage, metadata string) {
+	t.marked = append(t.marked, msg.Offset)
+}
+
 type testConsumerGroupClaim struct {
 	messageChan chan *sarama.ConsumerMessage
 }
@@ -15,10 +15,21 @@
 package opencensusreceiver
 
 import (
+	"errors"
+	"time"
+
 	"go.opentelemetry.io/collector/config/configgrpc"
 	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/receiver/opencensusreceiver/internal/flowcontrol"
+)
+
+const (
+	defaultFlowControlInitialInterval = 100 * time.Millisecond
+	defaultFlowControlMaxInterval     = 5 * time.Second
 )
 
+var errInvalidFlowControl = errors.New("\"max_interval\" of \"flow_control\" must not be lower than \"initial_interval\"")
+
 // Config defines configuration for OpenCensus receiver.
 type Config struct {
 	configmodels.ReceiverSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct
@@ -31,6 +42,27 @@ type Config struct {
 	// An empty list means that CORS is not enabled at all. A wildcard (*) can be
 	// used to match any origin or one or more characters of an origin.
 	CorsOrigins []string `mapstructure:"cors_allowed_origins"`
+
+	// FlowControl pauses the reading of the streams while the next consumer
+	// refuses the data, for example when the memory limiter is tripped, the
+	// data being retried with exponential backoff. The streams are closed with
+	// the error of the next consumer if nil.
+	FlowControl *FlowControlSettings `mapstructure:"flow_control"`
+}
+
+// FlowControlSettings defines the backoff of the retries of the data refused
+// by the next consumer.
+type FlowControlSettings struct {
+	// InitialInterval is the time to wait after the first refusal, 100ms by
+	// default.
+	InitialInterval time.Duration `mapstructure:"initial_interval"`
+	// MaxInterval is the upper bound of the time waited between the retries,
+	// 5s by default.
+	MaxInterval time.Duration `mapstructure:"max_interval"`
+	// MaxElapsedTime is the maximum time spent retrying the data, the stream
+	// being then closed with the error. The data is retried until the stream
+	// is closed by the client if 0.
+	MaxElapsedTime time.Duration `mapstructure:"max_elapsed_time"`
 }
 
 func (rOpts *Config) buildOptions() ([]ocOption, error) {
@@ -47,5 +79,23 @@ func (rOpts *Config) buildOptions() ([]ocOption, error) {
 		opts = append(opts, withGRPCServerOptions(grpcServerOptions...))
 	}
 
+	if rOpts.FlowControl != nil {
+		settings := &flowcontrol.Settings{
+			InitialInterval: rOpts.FlowControl.InitialInterval,
+			MaxInterval:     rOpts.FlowControl.MaxInterval,
+			MaxElapsedTime:  rOpts.FlowControl.MaxElapsedTime,
+		}
+		if settings.InitialInterval <= 0 {
+			settings.InitialInterval = defaultFlowControlInitialInterval
+		}
+		if settings.MaxInterval <= 0 {
+			settings.MaxInterval = defaultFlowControlMaxInterval
+		}
+		if settings.MaxInterval < settings.InitialInterval {
+			return nil, errInvalidFlowControl
+		}
+		opts = append(opts, withFlowControl(settings))
+	}
+
 	return opts, nil
 }
@@ -28,6 +28,7 @@ import (
 	"go.opentelemetry.io/collector/config/confignet"
 	"go.opentelemetry.io/collector/config/configtest"
 	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/receiver/opencensusreceiver/internal/flowcontrol"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -41,7 +42,7 @@ func TestLoadConfig(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, cfg)
 
-	assert.Equal(t, len(cfg.Receivers), 7)
+	assert.Equal(t, len(cfg.Receivers), 8)
 
 	r0 := cfg.Receivers["opencensus"]
 	assert.Equal(t, r0, factory.CreateDefaultConfig())
@@ -171,6 +172,27 @@ func TestLoadConfig(t *testing.T) {
 				ReadBufferSize: 512 * 1024,
 			},
 		})
+
+	r7 := cfg.Receivers["opencensus/flowcontrol"].(*Config)
+	assert.Equal(t, r7,
+		&Config{
+			ReceiverSettings: configmodels.ReceiverSettings{
+				TypeVal: typeStr,
+				NameVal: "opencensus/flowcontrol",
+			},
+			GRPCServerSettings: configgrpc.GRPCServerSettings{
+				NetAddr: confignet.NetAddr{
+					Endpoint:  "0.0.0.0:55678",
+					Transport: "tcp",
+				},
+				ReadBufferSize: 512 * 1024,
+			},
+			FlowControl: &FlowControlSettings{
+				InitialInterval: 50 * time.Millisecond,
+				MaxInterval:     2 * time.Second,
+				MaxElapsedTime:  time.Minute,
+			},
+		})
 }
 
 func TestBuildOptions_TLSCredentials(t *testing.T) {
@@ -194,3 +216,18 @@ func TestBuildOptions_TLSCredentials(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, opt)
 }
+
+func TestBuildOptions_FlowControl(t *testing.T) {
+	cfg := Config{FlowControl: &FlowControlSettings{}}
+	opts, err := cfg.buildOptions()
+	require.NoError(t, err)
+	require.Len(t, opts, 1)
+	assert.Equal(t, &flowcontrol.Settings{
+		InitialInterval: defaultFlowControlInitialInterval,
+		MaxInterval:     defaultFlowControlMaxInterval,
+	}, opts[0].(*flowControl).settings)
+
+	cfg.FlowControl = &FlowControlSettings{InitialInterval: 10 * time.Second}
+	_, err = cfg.buildOptions()
+	assert.Equal(t, errInvalidFlowControl, err)
+}
@@ -0,0 +1,79 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package flowcontrol pauses the reading of the gRPC streams while the next
+// consumer refuses the data, gRPC flow control then preventing the clients
+// from sending more data than the stream window.
+package flowcontrol
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+
+	"go.opentelemetry.io/collector/consumer/consumererror"
+)
+
+// Settings are the exponential backoff settings of the retries of the data
+// refused by the next consumer.
+type Settings struct {
+	// InitialInterval is the time to wait after the first refusal.
+	InitialInterval time.Duration
+	// MaxInterval is the upper bound of the time waited between the retries.
+	MaxInterval time.Duration
+	// MaxElapsedTime is the maximum time spent retrying, the data being
+	// retried until the stream is closed if 0.
+	MaxElapsedTime time.Duration
+}
+
+// Retry calls consume until it succeeds, the message of the stream not being
+// received meanwhile. It returns the error of consume if it is permanent,
+// when MaxElapsedTime is reached or when ctx, the context of the stream, is
+// done. consume is called once if s is nil.
+func Retry(ctx context.Context, s *Settings, consume func() error) error {
+	err := consume()
+	if err == nil || s == nil || consumererror.IsPermanent(err) {
+		return err
+	}
+
+	expBackoff := backoff.ExponentialBackOff{
+		InitialInterval:     s.InitialInterval,
+		RandomizationFactor: backoff.DefaultRandomizationFactor,
+		Multiplier:          backoff.DefaultMultiplier,
+		MaxInterval:         s.MaxInterval,
+		MaxElapsedTime:      s.MaxElapsedTime,
+		Stop:                backoff.Stop,
+		Clock:               backoff.SystemClock,
+	}
+	// Reset sets the first interval to InitialInterval and starts measuring the
+	// time elapsed since the first refusal.
+	expBackoff.Reset()
+	for {
+		delay := expBackoff.NextBackOff()
+		if delay == backoff.Stop {
+			return fmt.Errorf("max elapsed time expired %w", err)
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("stream is closed %w", err)
+		case <-time.After(delay):
+		}
+
+		if err = consume(); err == nil || consumererror.IsPermanent(err) {
+			return err
+		}
+	}
+}
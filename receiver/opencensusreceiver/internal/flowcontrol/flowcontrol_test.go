@@ -0,0 +1,88 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flowcontrol
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.opentelemetry.io/collector/consumer/consumererror"
+)
+
+var errRefused = errors.New("refused")
+
+var testSettings = &Settings{
+	InitialInterval: time.Millisecond,
+	MaxInterval:     5 * time.Millisecond,
+}
+
+// refuse returns a consume function refusing the data n times, and the
+// number of calls.
+func refuse(n int, err error) (func() error, *int) {
+	calls := new(int)
+	return func() error {
+		*calls++
+		if *calls <= n {
+			return err
+		}
+		return nil
+	}, calls
+}
+
+func TestRetry(t *testing.T) {
+	consume, calls := refuse(3, errRefused)
+	assert.NoError(t, Retry(context.Background(), testSettings, consume))
+	assert.Equal(t, 4, *calls)
+}
+
+func TestRetryDisabled(t *testing.T) {
+	consume, calls := refuse(1, errRefused)
+	assert.Equal(t, errRefused, Retry(context.Background(), nil, consume))
+	assert.Equal(t, 1, *calls)
+}
+
+func TestRetryPermanentError(t *testing.T) {
+	permanent := consumererror.Permanent(errRefused)
+	consume, calls := refuse(1, permanent)
+	assert.Equal(t, permanent, Retry(context.Background(), testSettings, consume))
+	assert.Equal(t, 1, *calls)
+}
+
+func TestRetryMaxElapsedTime(t *testing.T) {
+	consume, calls := refuse(1000000, errRefused)
+	settings := *testSettings
+	settings.MaxElapsedTime = 20 * time.Millisecond
+	err := Retry(context.Background(), &settings, consume)
+	assert.True(t, errors.Is(err, errRefused))
+	assert.Contains(t, err.Error(), "max elapsed time expired")
+	assert.Greater(t, *calls, 1)
+}
+
+func TestRetryStreamClosed(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	consume, calls := refuse(1000000, errRefused)
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+	err := Retry(ctx, testSettings, consume)
+	assert.True(t, errors.Is(err, errRefused))
+	assert.Contains(t, err.Error(), "stream is closed")
+	assert.Greater(t, *calls, 1)
+}
@@ -26,6 +26,7 @@ import (
 	"go.opentelemetry.io/collector/component/componenterror"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/obsreport"
+	"go.opentelemetry.io/collector/receiver/opencensusreceiver/internal/flowcontrol"
 	"go.opentelemetry.io/collector/translator/internaldata"
 )
 
@@ -34,10 +35,11 @@ type Receiver struct {
 	agentmetricspb.UnimplementedMetricsServiceServer
 	instanceName string
 	nextConsumer consumer.MetricsConsumer
+	flowControl  *flowcontrol.Settings
 }
 
 // New creates a new ocmetrics.Receiver reference.
-func New(instanceName string, nextConsumer consumer.MetricsConsumer) (*Receiver, error) {
+func New(instanceName string, nextConsumer consumer.MetricsConsumer, opts ...Option) (*Receiver, error) {
 	if nextConsumer == nil {
 		return nil, componenterror.ErrNilNextConsumer
 	}
@@ -45,6 +47,9 @@ func New(instanceName string, nextConsumer consumer.MetricsConsumer) (*Receiver,
 		instanceName: instanceName,
 		nextConsumer: nextConsumer,
 	}
+	for _, opt := range opts {
+		opt(ocr)
+	}
 	return ocr, nil
 }
 
@@ -145,7 +150,10 @@ func (ocr *Receiver) sendToNextConsumer(longLivedRPCCtx context.Context, md inte
 
 	var consumerErr error
 	if len(md.Metrics) > 0 {
-		consumerErr = ocr.nextConsumer.ConsumeMetrics(ctx, internaldata.OCToMetrics(md))
+		metrics := internaldata.OCToMetrics(md)
+		consumerErr = flowcontrol.Retry(longLivedRPCCtx, ocr.flowControl, func() error {
+			return ocr.nextConsumer.ConsumeMetrics(ctx, metrics)
+		})
 	}
 
 	obsreport.EndMetricsReceiveOp(
@@ -18,6 +18,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net"
@@ -40,9 +41,11 @@ import (
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/exporter/opencensusexporter"
 	"go.opentelemetry.io/collector/internal/testdata"
 	"go.opentelemetry.io/collector/obsreport"
+	"go.opentelemetry.io/collector/receiver/opencensusreceiver/internal/flowcontrol"
 	"go.opentelemetry.io/collector/testutil"
 	"go.opentelemetry.io/collector/translator/internaldata"
 )
@@ -350,7 +353,7 @@ func nodeToKey(n *commonpb.Node) string {
 	return string(blob)
 }
 
-func ocReceiverOnGRPCServer(t *testing.T, sr consumer.MetricsConsumer) (int, func()) {
+func ocReceiverOnGRPCServer(t *testing.T, sr consumer.MetricsConsumer, opts ...Option) (int, func()) {
 	ln, err := net.Listen("tcp", "localhost:")
 	require.NoError(t, err, "Failed to find an available address to run the gRPC server: %v", err)
 
@@ -367,7 +370,7 @@ func ocReceiverOnGRPCServer(t *testing.T, sr consumer.MetricsConsumer) (int, fun
 		t.Fatalf("Failed to parse host:port from listener address: %s error: %v", ln.Addr(), err)
 	}
 
-	oci, err := New(receiverTagValue, sr)
+	oci, err := New(receiverTagValue, sr, opts...)
 	require.NoError(t, err, "Failed to create the Receiver: %v", err)
 
 	// Now run it as a gRPC server
@@ -416,3 +419,68 @@ func makeMetric(val int) *metricspb.Metric {
 		Timeseries:       []*metricspb.TimeSeries{ts},
 	}
 }
+
+var errRefused = errors.New("data refused")
+
+// refusingMetricsSink refuses the first metrics it receives.
+type refusingMetricsSink struct {
+	consumertest.MetricsSink
+	mu       sync.Mutex
+	refusals int
+}
+
+func (s *refusingMetricsSink) ConsumeMetrics(ctx context.Context, md pdata.Metrics) error {
+	s.mu.Lock()
+	if s.refusals > 0 {
+		s.refusals--
+		s.mu.Unlock()
+		return errRefused
+	}
+	s.mu.Unlock()
+	return s.MetricsSink.ConsumeMetrics(ctx, md)
+}
+
+func TestExportFlowControl(t *testing.T) {
+	sink := &refusingMetricsSink{refusals: 3}
+	port, doneFn := ocReceiverOnGRPCServer(t, sink, WithFlowControl(&flowcontrol.Settings{
+		InitialInterval: time.Millisecond,
+		MaxInterval:     5 * time.Millisecond,
+	}))
+	defer doneFn()
+
+	metricsClient, metricsClientDoneFn, err := makeMetricsServiceClient(port)
+	require.NoError(t, err)
+	defer metricsClientDoneFn()
+
+	node := &commonpb.Node{Identifier: &commonpb.ProcessIdentifier{Pid: 1}}
+	require.NoError(t, metricsClient.Send(&agentmetricspb.ExportMetricsServiceRequest{Node: node, Metrics: []*metricspb.Metric{makeMetric(1)}}))
+	require.NoError(t, metricsClient.Send(&agentmetricspb.ExportMetricsServiceRequest{Metrics: []*metricspb.Metric{makeMetric(2)}}))
+
+	// The refused metrics are retried, the stream staying open.
+	require.Eventually(t, func() bool {
+		return len(sink.AllMetrics()) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, metricsClient.Send(&agentmetricspb.ExportMetricsServiceRequest{Metrics: []*metricspb.Metric{makeMetric(3)}}))
+	require.Eventually(t, func() bool {
+		return len(sink.AllMetrics()) == 3
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestExportWithoutFlowControl(t *testing.T) {
+	sink := &refusingMetricsSink{refusals: 1}
+	port, doneFn := ocReceiverOnGRPCServer(t, sink)
+	defer doneFn()
+
+	metricsClient, metricsClientDoneFn, err := makeMetricsServiceClient(port)
+	require.NoError(t, err)
+	defer metricsClientDoneFn()
+
+	node := &commonpb.Node{Identifier: &commonpb.ProcessIdentifier{Pid: 1}}
+	require.NoError(t, metricsClient.Send(&agentmetricspb.ExportMetricsServiceRequest{Node: node, Metrics: []*metricspb.Metric{makeMetric(1)}}))
+
+	// The stream is closed with the error of the next consumer.
+	_, err = metricsClient.Recv()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), errRefused.Error())
+	assert.Empty(t, sink.AllMetrics())
+}
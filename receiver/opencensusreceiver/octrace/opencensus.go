@@ -28,6 +28,7 @@ import (
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/obsreport"
+	"go.opentelemetry.io/collector/receiver/opencensusreceiver/internal/flowcontrol"
 	"go.opentelemetry.io/collector/translator/internaldata"
 )
 
@@ -42,6 +43,7 @@ type Receiver struct {
 	agenttracepb.UnimplementedTraceServiceServer
 	nextConsumer consumer.TracesConsumer
 	instanceName string
+	flowControl  *flowcontrol.Settings
 }
 
 // New creates a new opencensus.Receiver reference.
@@ -148,7 +150,9 @@ func (ocr *Receiver) sendToNextConsumer(longLivedRPCCtx context.Context, td pdat
 		receiverTransport,
 		obsreport.WithLongLivedCtx())
 
-	err := ocr.nextConsumer.ConsumeTraces(ctx, td)
+	err := flowcontrol.Retry(longLivedRPCCtx, ocr.flowControl, func() error {
+		return ocr.nextConsumer.ConsumeTraces(ctx, td)
+	})
 	obsreport.EndTraceDataReceiveOp(ctx, receiverDataFormat, td.SpanCount(), err)
 
 	return err
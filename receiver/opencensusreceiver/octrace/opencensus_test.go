@@ -18,10 +18,12 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -38,9 +40,11 @@ import (
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/exporter/opencensusexporter"
 	"go.opentelemetry.io/collector/internal/testdata"
 	"go.opentelemetry.io/collector/obsreport"
+	"go.opentelemetry.io/collector/receiver/opencensusreceiver/internal/flowcontrol"
 	"go.opentelemetry.io/collector/testutil"
 	"go.opentelemetry.io/collector/translator/internaldata"
 )
@@ -366,7 +370,7 @@ func nodeToKey(n *commonpb.Node) string {
 	return string(blob)
 }
 
-func ocReceiverOnGRPCServer(t *testing.T, sr consumer.TracesConsumer) (int, func()) {
+func ocReceiverOnGRPCServer(t *testing.T, sr consumer.TracesConsumer, opts ...Option) (int, func()) {
 	ln, err := net.Listen("tcp", "localhost:0")
 	require.NoError(t, err, "Failed to find an available address to run the gRPC server: %v", err)
 
@@ -383,7 +387,7 @@ func ocReceiverOnGRPCServer(t *testing.T, sr consumer.TracesConsumer) (int, func
 		t.Fatalf("Failed to parse host:port from listener address: %s error: %v", ln.Addr(), err)
 	}
 
-	oci, err := New(receiverTagValue, sr)
+	oci, err := New(receiverTagValue, sr, opts...)
 	require.NoError(t, err, "Failed to create the Receiver: %v", err)
 
 	// Now run it as a gRPC server
@@ -395,3 +399,70 @@ func ocReceiverOnGRPCServer(t *testing.T, sr consumer.TracesConsumer) (int, func
 
 	return port, done
 }
+
+var errRefused = errors.New("data refused")
+
+// refusingTracesSink refuses the first traces it receives.
+type refusingTracesSink struct {
+	consumertest.TracesSink
+	mu       sync.Mutex
+	refusals int
+}
+
+func (s *refusingTracesSink) ConsumeTraces(ctx context.Context, td pdata.Traces) error {
+	s.mu.Lock()
+	if s.refusals > 0 {
+		s.refusals--
+		s.mu.Unlock()
+		return errRefused
+	}
+	s.mu.Unlock()
+	return s.TracesSink.ConsumeTraces(ctx, td)
+}
+
+func TestExportFlowControl(t *testing.T) {
+	sink := &refusingTracesSink{refusals: 3}
+	port, doneFn := ocReceiverOnGRPCServer(t, sink, WithFlowControl(&flowcontrol.Settings{
+		InitialInterval: time.Millisecond,
+		MaxInterval:     5 * time.Millisecond,
+	}))
+	defer doneFn()
+
+	traceClient, traceClientDoneFn, err := makeTraceServiceClient(port)
+	require.NoError(t, err)
+	defer traceClientDoneFn()
+
+	node := &commonpb.Node{Identifier: &commonpb.ProcessIdentifier{Pid: 1}}
+	spans := []*tracepb.Span{{TraceId: []byte("1234567890abcdef"), Status: &tracepb.Status{}}}
+	require.NoError(t, traceClient.Send(&agenttracepb.ExportTraceServiceRequest{Node: node, Spans: spans}))
+	require.NoError(t, traceClient.Send(&agenttracepb.ExportTraceServiceRequest{Spans: spans}))
+
+	// The refused spans are retried, the stream staying open.
+	require.Eventually(t, func() bool {
+		return sink.SpansCount() == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, traceClient.Send(&agenttracepb.ExportTraceServiceRequest{Spans: spans}))
+	require.Eventually(t, func() bool {
+		return sink.SpansCount() == 3
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestExportWithoutFlowControl(t *testing.T) {
+	sink := &refusingTracesSink{refusals: 1}
+	port, doneFn := ocReceiverOnGRPCServer(t, sink)
+	defer doneFn()
+
+	traceClient, traceClientDoneFn, err := makeTraceServiceClient(port)
+	require.NoError(t, err)
+	defer traceClientDoneFn()
+
+	node := &commonpb.Node{Identifier: &commonpb.ProcessIdentifier{Pid: 1}}
+	spans := []*tracepb.Span{{TraceId: []byte("1234567890abcdef"), Status: &tracepb.Status{}}}
+	require.NoError(t, traceClient.Send(&agenttracepb.ExportTraceServiceRequest{Node: node, Spans: spans}))
+
+	// The stream is closed with the error of the next consumer.
+	_, err = traceClient.Recv()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), errRefused.Error())
+	assert.Equal(t, 0, sink.SpansCount())
+}
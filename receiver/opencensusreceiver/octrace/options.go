@@ -14,7 +14,19 @@
 
 package octrace
 
+import (
+	"go.opentelemetry.io/collector/receiver/opencensusreceiver/internal/flowcontrol"
+)
+
 // Option interface defines for configuration settings to be applied to receivers.
 //
 // WithReceiver applies the configuration to the given receiver.
 type Option func(*Receiver)
+
+// WithFlowControl retries the spans refused by the next consumer with the
+// given backoff settings, the stream not being read meanwhile.
+func WithFlowControl(settings *flowcontrol.Settings) Option {
+	return func(ocr *Receiver) {
+		ocr.flowControl = settings
+	}
+}
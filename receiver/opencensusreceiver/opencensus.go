@@ -47,7 +47,8 @@ type ocReceiver struct {
 	corsOrigins       []string
 	grpcServerOptions []grpc.ServerOption
 
-	traceReceiverOpts []octrace.Option
+	traceReceiverOpts   []octrace.Option
+	metricsReceiverOpts []ocmetrics.Option
 
 	traceReceiver   *octrace.Receiver
 	metricsReceiver *ocmetrics.Receiver
@@ -123,7 +124,7 @@ func (ocr *ocReceiver) registerMetricsConsumer() error {
 
 	ocr.startMetricsReceiverOnce.Do(func() {
 		ocr.metricsReceiver, err = ocmetrics.New(
-			ocr.instanceName, ocr.metricsConsumer)
+			ocr.instanceName, ocr.metricsConsumer, ocr.metricsReceiverOpts...)
 		if err == nil {
 			srv := ocr.grpcServer()
 			agentmetricspb.RegisterMetricsServiceServer(srv, ocr.metricsReceiver)
@@ -16,6 +16,10 @@ package opencensusreceiver
 
 import (
 	"google.golang.org/grpc"
+
+	"go.opentelemetry.io/collector/receiver/opencensusreceiver/internal/flowcontrol"
+	"go.opentelemetry.io/collector/receiver/opencensusreceiver/ocmetrics"
+	"go.opentelemetry.io/collector/receiver/opencensusreceiver/octrace"
 )
 
 // ocOption interface defines for configuration settings to be applied to receivers.
@@ -54,3 +58,20 @@ func withGRPCServerOptions(gsOpts ...grpc.ServerOption) ocOption {
 	gsvOpts := grpcServerOptions(gsOpts)
 	return gsvOpts
 }
+
+var _ ocOption = (*flowControl)(nil)
+
+type flowControl struct {
+	settings *flowcontrol.Settings
+}
+
+func (fc *flowControl) withReceiver(ocr *ocReceiver) {
+	ocr.traceReceiverOpts = append(ocr.traceReceiverOpts, octrace.WithFlowControl(fc.settings))
+	ocr.metricsReceiverOpts = append(ocr.metricsReceiverOpts, ocmetrics.WithFlowControl(fc.settings))
+}
+
+// withFlowControl is an option to retry the data refused by the next consumers
+// instead of closing the streams.
+func withFlowControl(settings *flowcontrol.Settings) ocOption {
+	return &flowControl{settings: settings}
+}
@@ -0,0 +1,52 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !arrow
+
+package otlpreceiver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	collectortrace "go.opentelemetry.io/collector/internal/data/protogen/collector/trace/v1"
+	"go.opentelemetry.io/collector/internal/otlparrow"
+	"go.opentelemetry.io/collector/testutil"
+)
+
+func TestGRPCArrowDisabled(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+	traceSink := new(consumertest.TracesSink)
+	ocr := newGRPCReceiver(t, "otlp_arrow", addr, traceSink, new(consumertest.MetricsSink))
+	require.NoError(t, ocr.Start(context.Background(), componenttest.NewNopHost()))
+	defer ocr.Shutdown(context.Background())
+
+	cc, err := grpc.Dial(addr, grpc.WithInsecure(), grpc.WithBlock())
+	require.NoError(t, err)
+	defer cc.Close()
+
+	// The Arrow services are not served, the clients fall back to OTLP.
+	var traceResp collectortrace.ExportTraceServiceResponse
+	err = cc.Invoke(context.Background(), otlparrow.TracesExportMethod, &otlparrow.Request{}, &traceResp)
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+	assert.Len(t, traceSink.AllTraces(), 0)
+}
@@ -0,0 +1,72 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build arrow
+
+package otlpreceiver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	collectormetrics "go.opentelemetry.io/collector/internal/data/protogen/collector/metrics/v1"
+	collectortrace "go.opentelemetry.io/collector/internal/data/protogen/collector/trace/v1"
+	"go.opentelemetry.io/collector/internal/otlparrow"
+	"go.opentelemetry.io/collector/internal/testdata"
+	"go.opentelemetry.io/collector/testutil"
+)
+
+func TestGRPCArrow(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+	traceSink := new(consumertest.TracesSink)
+	metricsSink := new(consumertest.MetricsSink)
+	ocr := newGRPCReceiver(t, "otlp_arrow", addr, traceSink, metricsSink)
+	require.NoError(t, ocr.Start(context.Background(), componenttest.NewNopHost()))
+	defer ocr.Shutdown(context.Background())
+
+	cc, err := grpc.Dial(addr, grpc.WithInsecure(), grpc.WithBlock())
+	require.NoError(t, err)
+	defer cc.Close()
+
+	td := testdata.GenerateTraceDataTwoSpansSameResource()
+	req, err := otlparrow.EncodeTraces(pdata.TracesToOtlp(td))
+	require.NoError(t, err)
+	var traceResp collectortrace.ExportTraceServiceResponse
+	require.NoError(t, cc.Invoke(context.Background(), otlparrow.TracesExportMethod, req, &traceResp))
+	require.Len(t, traceSink.AllTraces(), 1)
+	assert.Equal(t, td, traceSink.AllTraces()[0])
+
+	md := testdata.GenerateMetricsOneCounterOneSummaryMetrics()
+	req, err = otlparrow.EncodeMetrics(pdata.MetricsToOtlp(md))
+	require.NoError(t, err)
+	var metricsResp collectormetrics.ExportMetricsServiceResponse
+	require.NoError(t, cc.Invoke(context.Background(), otlparrow.MetricsExportMethod, req, &metricsResp))
+	require.Len(t, metricsSink.AllMetrics(), 1)
+	assert.Equal(t, md, metricsSink.AllMetrics()[0])
+
+	// The requests of which the tables cannot be decoded are invalid.
+	req.Tables = req.Tables[1:]
+	err = cc.Invoke(context.Background(), otlparrow.MetricsExportMethod, req, &metricsResp)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Len(t, metricsSink.AllMetrics(), 1)
+}
@@ -0,0 +1,67 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlpreceiver
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	collectorlog "go.opentelemetry.io/collector/internal/data/protogen/collector/logs/v1"
+	collectormetrics "go.opentelemetry.io/collector/internal/data/protogen/collector/metrics/v1"
+	collectortrace "go.opentelemetry.io/collector/internal/data/protogen/collector/trace/v1"
+)
+
+// backpressureStatus returns an UNAVAILABLE status, retryable by the OTLP clients, for the
+// errors of the pipelines refusing the data with consumererror.Backpressure. Over HTTP the
+// status is returned with 503 Service Unavailable.
+func backpressureStatus(err error) error {
+	if consumererror.IsBackpressure(err) {
+		return status.Error(codes.Unavailable, err.Error())
+	}
+	return err
+}
+
+// backpressureTraceServer returns a retryable status when the pipeline refuses the traces.
+type backpressureTraceServer struct {
+	next collectortrace.TraceServiceServer
+}
+
+func (s *backpressureTraceServer) Export(ctx context.Context, req *collectortrace.ExportTraceServiceRequest) (*collectortrace.ExportTraceServiceResponse, error) {
+	resp, err := s.next.Export(ctx, req)
+	return resp, backpressureStatus(err)
+}
+
+// backpressureMetricsServer returns a retryable status when the pipeline refuses the metrics.
+type backpressureMetricsServer struct {
+	next collectormetrics.MetricsServiceServer
+}
+
+func (s *backpressureMetricsServer) Export(ctx context.Context, req *collectormetrics.ExportMetricsServiceRequest) (*collectormetrics.ExportMetricsServiceResponse, error) {
+	resp, err := s.next.Export(ctx, req)
+	return resp, backpressureStatus(err)
+}
+
+// backpressureLogsServer returns a retryable status when the pipeline refuses the logs.
+type backpressureLogsServer struct {
+	next collectorlog.LogsServiceServer
+}
+
+func (s *backpressureLogsServer) Export(ctx context.Context, req *collectorlog.ExportLogsServiceRequest) (*collectorlog.ExportLogsServiceResponse, error) {
+	resp, err := s.next.Export(ctx, req)
+	return resp, backpressureStatus(err)
+}
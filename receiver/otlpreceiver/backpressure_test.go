@@ -0,0 +1,141 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlpreceiver
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	collectortrace "go.opentelemetry.io/collector/internal/data/protogen/collector/trace/v1"
+	"go.opentelemetry.io/collector/internal/internalconsumertest"
+	"go.opentelemetry.io/collector/internal/testdata"
+	"go.opentelemetry.io/collector/testutil"
+)
+
+func TestBackpressureStatus(t *testing.T) {
+	err := errors.New("consumer error")
+	assert.Equal(t, err, backpressureStatus(err))
+	assert.NoError(t, backpressureStatus(nil))
+
+	st, ok := status.FromError(backpressureStatus(consumererror.Backpressure(errors.New("sending_queue is full"))))
+	require.True(t, ok)
+	assert.Equal(t, codes.Unavailable, st.Code())
+	assert.Equal(t, "sending_queue is full", st.Message())
+}
+
+func TestGRPCBackpressure(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+	sink := &internalconsumertest.ErrOrSinkConsumer{TracesSink: new(consumertest.TracesSink)}
+	sink.SetConsumeError(consumererror.Backpressure(errors.New("sending_queue is full")))
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.SetName(otlpReceiverName)
+	cfg.GRPC.NetAddr.Endpoint = addr
+	cfg.HTTP = nil
+	ocr := newBackpressureReceiver(t, factory, cfg, sink)
+	require.NoError(t, ocr.Start(context.Background(), componenttest.NewNopHost()))
+	defer ocr.Shutdown(context.Background())
+
+	cc, err := grpc.Dial(addr, grpc.WithInsecure(), grpc.WithBlock())
+	require.NoError(t, err)
+	defer cc.Close()
+
+	req := &collectortrace.ExportTraceServiceRequest{ResourceSpans: pdata.TracesToOtlp(testdata.GenerateTraceDataOneSpan())}
+	_, err = collectortrace.NewTraceServiceClient(cc).Export(context.Background(), req)
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
+
+func TestHTTPBackpressure(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+	sink := &internalconsumertest.ErrOrSinkConsumer{TracesSink: new(consumertest.TracesSink)}
+	sink.SetConsumeError(consumererror.Backpressure(errors.New("sending_queue is full")))
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.SetName(otlpReceiverName)
+	cfg.HTTP.Endpoint = addr
+	cfg.GRPC = nil
+	ocr := newBackpressureReceiver(t, factory, cfg, sink)
+	require.NoError(t, ocr.Start(context.Background(), componenttest.NewNopHost()))
+	defer ocr.Shutdown(context.Background())
+
+	req := &collectortrace.ExportTraceServiceRequest{ResourceSpans: pdata.TracesToOtlp(testdata.GenerateTraceDataOneSpan())}
+	body, err := req.Marshal()
+	require.NoError(t, err)
+	resp, err := http.Post("http://"+addr+"/v1/traces", "application/x-protobuf", bytes.NewReader(body))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+}
+
+func TestGRPCWithoutBackpressure(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+	sink := &internalconsumertest.ErrOrSinkConsumer{TracesSink: new(consumertest.TracesSink)}
+	sink.SetConsumeError(consumererror.Backpressure(errors.New("sending_queue is full")))
+	// the pipelines without backpressure keep returning the errors as is.
+	ocr := newGRPCReceiver(t, otlpReceiverName, addr, sink, nil)
+	require.NoError(t, ocr.Start(context.Background(), componenttest.NewNopHost()))
+	defer ocr.Shutdown(context.Background())
+
+	cc, err := grpc.Dial(addr, grpc.WithInsecure(), grpc.WithBlock())
+	require.NoError(t, err)
+	defer cc.Close()
+
+	req := &collectortrace.ExportTraceServiceRequest{ResourceSpans: pdata.TracesToOtlp(testdata.GenerateTraceDataOneSpan())}
+	_, err = collectortrace.NewTraceServiceClient(cc).Export(context.Background(), req)
+	assert.Equal(t, codes.Unknown, status.Code(err))
+}
+
+func TestHTTPWithoutBackpressure(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+	sink := &internalconsumertest.ErrOrSinkConsumer{TracesSink: new(consumertest.TracesSink)}
+	sink.SetConsumeError(consumererror.Backpressure(errors.New("sending_queue is full")))
+	ocr := newHTTPReceiver(t, addr, sink, nil)
+	require.NoError(t, ocr.Start(context.Background(), componenttest.NewNopHost()))
+	defer ocr.Shutdown(context.Background())
+
+	req := &collectortrace.ExportTraceServiceRequest{ResourceSpans: pdata.TracesToOtlp(testdata.GenerateTraceDataOneSpan())}
+	body, err := req.Marshal()
+	require.NoError(t, err)
+	resp, err := http.Post("http://"+addr+"/v1/traces", "application/x-protobuf", bytes.NewReader(body))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+}
+
+// newBackpressureReceiver creates a receiver attached to pipelines propagating backpressure.
+func newBackpressureReceiver(t *testing.T, factory component.ReceiverFactory, cfg *Config, tc consumer.TracesConsumer) *otlpReceiver {
+	r, err := createReceiver(cfg, zap.NewNop())
+	require.NoError(t, err)
+	params := component.ReceiverCreateParams{Logger: zap.NewNop(), Backpressure: true}
+	_, err = factory.CreateTracesReceiver(context.Background(), params, cfg, tc)
+	require.NoError(t, err)
+	return r
+}
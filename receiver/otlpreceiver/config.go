@@ -31,4 +31,11 @@ type Config struct {
 
 	// Protocols is the configuration for the supported protocols, currently gRPC and HTTP (Proto and JSON).
 	Protocols `mapstructure:"protocols"`
+
+	// Limits bounds the load accepted by the receiver, for all the protocols.
+	Limits LimitsConfig `mapstructure:"limits"`
+
+	// HeadersToResourceAttributes sets resource attributes of every received batch from the
+	// gRPC metadata or HTTP headers of the request.
+	HeadersToResourceAttributes []HeaderToResourceAttributeConfig `mapstructure:"headers_to_resource_attributes"`
 }
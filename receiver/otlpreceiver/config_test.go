@@ -42,7 +42,7 @@ func TestLoadConfig(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, cfg)
 
-	assert.Equal(t, len(cfg.Receivers), 10)
+	assert.Equal(t, len(cfg.Receivers), 12)
 
 	assert.Equal(t, cfg.Receivers["otlp"], factory.CreateDefaultConfig())
 
@@ -128,6 +128,58 @@ func TestLoadConfig(t *testing.T) {
 			},
 		})
 
+	assert.Equal(t, cfg.Receivers["otlp/limits"],
+		&Config{
+			ReceiverSettings: configmodels.ReceiverSettings{
+				TypeVal: typeStr,
+				NameVal: "otlp/limits",
+			},
+			Protocols: Protocols{
+				GRPC: &configgrpc.GRPCServerSettings{
+					NetAddr: confignet.NetAddr{
+						Endpoint:  "0.0.0.0:4317",
+						Transport: "tcp",
+					},
+					ReadBufferSize: 512 * 1024,
+				},
+				HTTP: &confighttp.HTTPServerSettings{
+					Endpoint: "0.0.0.0:55681",
+				},
+			},
+			Limits: LimitsConfig{
+				MaxInflightBytes: 64 * 1024 * 1024,
+				RequestsPerSecond: RequestsPerSecondConfig{
+					Traces:  100,
+					Metrics: 50,
+					Logs:    10,
+				},
+				RetryAfter: 5 * time.Second,
+			},
+		})
+
+	assert.Equal(t, cfg.Receivers["otlp/headers"],
+		&Config{
+			ReceiverSettings: configmodels.ReceiverSettings{
+				TypeVal: typeStr,
+				NameVal: "otlp/headers",
+			},
+			Protocols: Protocols{
+				GRPC: &configgrpc.GRPCServerSettings{
+					NetAddr: confignet.NetAddr{
+						Endpoint:  "0.0.0.0:4317",
+						Transport: "tcp",
+					},
+					ReadBufferSize: 512 * 1024,
+				},
+				HTTP: &confighttp.HTTPServerSettings{
+					Endpoint: "0.0.0.0:55681",
+				},
+			},
+			HeadersToResourceAttributes: []HeaderToResourceAttributeConfig{
+				{Header: "X-Tenant-Id", Attribute: "tenant.id"},
+			},
+		})
+
 	// NOTE: Once the config loader checks for the files existence, this test may fail and require
 	// 	use of fake cert/key for test purposes.
 	assert.Equal(t, cfg.Receivers["otlp/tlscredentials"],
@@ -203,11 +255,13 @@ func TestLoadConfig(t *testing.T) {
 						Endpoint:  "/tmp/grpc_otlp.sock",
 						Transport: "unix",
 					},
-					ReadBufferSize: 512 * 1024,
+					SocketPermissions: "0660",
+					ReadBufferSize:    512 * 1024,
 				},
 				HTTP: &confighttp.HTTPServerSettings{
-					Endpoint: "/tmp/http_otlp.sock",
-					// Transport: "unix",
+					Endpoint:          "/tmp/http_otlp.sock",
+					Transport:         "unix",
+					SocketPermissions: "0660",
 				},
 			},
 		})
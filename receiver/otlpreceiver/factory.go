@@ -130,7 +130,7 @@ func createTraceReceiver(
 	if err != nil {
 		return nil, err
 	}
-	if err = r.registerTraceConsumer(ctx, nextConsumer); err != nil {
+	if err = r.registerTraceConsumer(ctx, nextConsumer, params.Backpressure); err != nil {
 		return nil, err
 	}
 	return r, nil
@@ -147,7 +147,7 @@ func createMetricsReceiver(
 	if err != nil {
 		return nil, err
 	}
-	if err = r.registerMetricsConsumer(ctx, consumer); err != nil {
+	if err = r.registerMetricsConsumer(ctx, consumer, params.Backpressure); err != nil {
 		return nil, err
 	}
 	return r, nil
@@ -164,7 +164,7 @@ func createLogReceiver(
 	if err != nil {
 		return nil, err
 	}
-	if err = r.registerLogsConsumer(ctx, consumer); err != nil {
+	if err = r.registerLogsConsumer(ctx, consumer, params.Backpressure); err != nil {
 		return nil, err
 	}
 	return r, nil
@@ -179,6 +179,12 @@ func createReceiver(cfg configmodels.Receiver, logger *zap.Logger) (*otlpReceive
 	// Check to see if there is already a receiver for this config.
 	receiver, ok := receivers[rCfg]
 	if !ok {
+		if err := rCfg.Limits.validate(); err != nil {
+			return nil, err
+		}
+		if err := validateHeadersToResourceAttributes(rCfg.HeadersToResourceAttributes); err != nil {
+			return nil, err
+		}
 		var err error
 		// We don't have a receiver, so create one.
 		receiver, err = newOtlpReceiver(rCfg, logger)
@@ -0,0 +1,157 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlpreceiver
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	gatewayruntime "github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"google.golang.org/grpc/metadata"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal"
+	collectorlog "go.opentelemetry.io/collector/internal/data/protogen/collector/logs/v1"
+	collectormetrics "go.opentelemetry.io/collector/internal/data/protogen/collector/metrics/v1"
+	collectortrace "go.opentelemetry.io/collector/internal/data/protogen/collector/trace/v1"
+)
+
+var (
+	errEmptyHeader    = errors.New("\"header\" of \"headers_to_resource_attributes\" cannot be empty")
+	errEmptyAttribute = errors.New("\"attribute\" of \"headers_to_resource_attributes\" cannot be empty")
+)
+
+// HeaderToResourceAttributeConfig maps an incoming gRPC metadata key or HTTP header to a
+// resource attribute.
+type HeaderToResourceAttributeConfig struct {
+	// Header is the name, case insensitive, of the gRPC metadata key or HTTP header.
+	Header string `mapstructure:"header"`
+	// Attribute is the key of the resource attribute set to the value of the header.
+	Attribute string `mapstructure:"attribute"`
+}
+
+func validateHeadersToResourceAttributes(cfgs []HeaderToResourceAttributeConfig) error {
+	for _, cfg := range cfgs {
+		if cfg.Header == "" {
+			return errEmptyHeader
+		}
+		if cfg.Attribute == "" {
+			return errEmptyAttribute
+		}
+	}
+	return nil
+}
+
+// headerAttributes sets the resource attributes of the received batches from the
+// incoming gRPC metadata, the HTTP headers being forwarded as metadata by the gateway.
+type headerAttributes struct {
+	// keys are the lowercase metadata keys, in the order of the configuration.
+	keys       []string
+	attributes map[string]string
+}
+
+// newHeaderAttributes returns the headerAttributes for the given configuration, or nil
+// when no header is mapped.
+func newHeaderAttributes(cfgs []HeaderToResourceAttributeConfig) *headerAttributes {
+	if len(cfgs) == 0 {
+		return nil
+	}
+	h := &headerAttributes{attributes: make(map[string]string, len(cfgs))}
+	for _, cfg := range cfgs {
+		key := strings.ToLower(cfg.Header)
+		if _, ok := h.attributes[key]; !ok {
+			h.keys = append(h.keys, key)
+		}
+		h.attributes[key] = cfg.Attribute
+	}
+	return h
+}
+
+// headerMatcher forwards the mapped HTTP headers as gRPC metadata, the other headers
+// are forwarded as by default.
+func (h *headerAttributes) headerMatcher(key string) (string, bool) {
+	lower := strings.ToLower(key)
+	if _, ok := h.attributes[lower]; ok {
+		return lower, true
+	}
+	return gatewayruntime.DefaultHeaderMatcher(key)
+}
+
+// apply upserts the attributes of the headers present in the incoming metadata of ctx
+// into the resources. The first value is used when a header has several values.
+func (h *headerAttributes) apply(ctx context.Context, resources []pdata.Resource) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return
+	}
+	for _, key := range h.keys {
+		values := md.Get(key)
+		if len(values) == 0 {
+			continue
+		}
+		for _, resource := range resources {
+			resource.Attributes().UpsertString(h.attributes[key], values[0])
+		}
+	}
+}
+
+// headersTraceServer sets the resource attributes of the trace requests from their headers.
+type headersTraceServer struct {
+	headers *headerAttributes
+	next    collectortrace.TraceServiceServer
+}
+
+func (s *headersTraceServer) Export(ctx context.Context, req *collectortrace.ExportTraceServiceRequest) (*collectortrace.ExportTraceServiceResponse, error) {
+	rss := pdata.TracesFromOtlp(req.ResourceSpans).ResourceSpans()
+	resources := make([]pdata.Resource, 0, rss.Len())
+	for i := 0; i < rss.Len(); i++ {
+		resources = append(resources, rss.At(i).Resource())
+	}
+	s.headers.apply(ctx, resources)
+	return s.next.Export(ctx, req)
+}
+
+// headersMetricsServer sets the resource attributes of the metrics requests from their headers.
+type headersMetricsServer struct {
+	headers *headerAttributes
+	next    collectormetrics.MetricsServiceServer
+}
+
+func (s *headersMetricsServer) Export(ctx context.Context, req *collectormetrics.ExportMetricsServiceRequest) (*collectormetrics.ExportMetricsServiceResponse, error) {
+	rms := pdata.MetricsFromOtlp(req.ResourceMetrics).ResourceMetrics()
+	resources := make([]pdata.Resource, 0, rms.Len())
+	for i := 0; i < rms.Len(); i++ {
+		resources = append(resources, rms.At(i).Resource())
+	}
+	s.headers.apply(ctx, resources)
+	return s.next.Export(ctx, req)
+}
+
+// headersLogsServer sets the resource attributes of the logs requests from their headers.
+type headersLogsServer struct {
+	headers *headerAttributes
+	next    collectorlog.LogsServiceServer
+}
+
+func (s *headersLogsServer) Export(ctx context.Context, req *collectorlog.ExportLogsServiceRequest) (*collectorlog.ExportLogsServiceResponse, error) {
+	rls := pdata.LogsFromInternalRep(internal.LogsFromOtlp(req.ResourceLogs)).ResourceLogs()
+	resources := make([]pdata.Resource, 0, rls.Len())
+	for i := 0; i < rls.Len(); i++ {
+		resources = append(resources, rls.At(i).Resource())
+	}
+	s.headers.apply(ctx, resources)
+	return s.next.Export(ctx, req)
+}
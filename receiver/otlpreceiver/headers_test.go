@@ -0,0 +1,174 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlpreceiver
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	collectortrace "go.opentelemetry.io/collector/internal/data/protogen/collector/trace/v1"
+	"go.opentelemetry.io/collector/testutil"
+)
+
+var tenantHeaders = []HeaderToResourceAttributeConfig{
+	{Header: "X-Tenant-Id", Attribute: "tenant.id"},
+	{Header: "x-region", Attribute: "cloud.region"},
+}
+
+func TestValidateHeadersToResourceAttributes(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers []HeaderToResourceAttributeConfig
+		wantErr error
+	}{
+		{name: "no headers"},
+		{name: "headers", headers: tenantHeaders},
+		{name: "empty header", headers: []HeaderToResourceAttributeConfig{{Attribute: "tenant.id"}}, wantErr: errEmptyHeader},
+		{name: "empty attribute", headers: []HeaderToResourceAttributeConfig{{Header: "X-Tenant-Id"}}, wantErr: errEmptyAttribute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantErr, validateHeadersToResourceAttributes(tt.headers))
+		})
+	}
+}
+
+func TestCreateReceiverInvalidHeadersToResourceAttributes(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.HeadersToResourceAttributes = []HeaderToResourceAttributeConfig{{Header: "X-Tenant-Id"}}
+	_, err := createReceiver(cfg, nil)
+	assert.Equal(t, errEmptyAttribute, err)
+}
+
+func TestNewHeaderAttributesWithoutHeaders(t *testing.T) {
+	assert.Nil(t, newHeaderAttributes(nil))
+}
+
+func TestHeaderAttributesHeaderMatcher(t *testing.T) {
+	h := newHeaderAttributes(tenantHeaders)
+	key, ok := h.headerMatcher("X-Tenant-Id")
+	assert.True(t, ok)
+	assert.Equal(t, "x-tenant-id", key)
+	key, ok = h.headerMatcher("X-Region")
+	assert.True(t, ok)
+	assert.Equal(t, "x-region", key)
+	key, ok = h.headerMatcher("Grpc-Metadata-Foo")
+	assert.True(t, ok)
+	assert.Equal(t, "Foo", key)
+	_, ok = h.headerMatcher("X-Other")
+	assert.False(t, ok)
+}
+
+func TestHeaderAttributesApply(t *testing.T) {
+	h := newHeaderAttributes(tenantHeaders)
+	resources := []pdata.Resource{pdata.NewResource(), pdata.NewResource()}
+	resources[0].Attributes().InsertString("tenant.id", "spoofed")
+	resources[0].Attributes().InsertString("service.name", "checkout")
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-tenant-id", "acme", "x-tenant-id", "other"))
+	h.apply(ctx, resources)
+
+	for _, resource := range resources {
+		tenant, ok := resource.Attributes().Get("tenant.id")
+		require.True(t, ok)
+		assert.Equal(t, "acme", tenant.StringVal())
+		_, ok = resource.Attributes().Get("cloud.region")
+		assert.False(t, ok)
+	}
+	service, ok := resources[0].Attributes().Get("service.name")
+	require.True(t, ok)
+	assert.Equal(t, "checkout", service.StringVal())
+
+	// Without incoming metadata the resources are unchanged.
+	resource := pdata.NewResource()
+	h.apply(context.Background(), []pdata.Resource{resource})
+	assert.Equal(t, 0, resource.Attributes().Len())
+}
+
+func TestGRPCHeadersToResourceAttributes(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.SetName("otlp/grpc_headers")
+	cfg.GRPC.NetAddr.Endpoint = addr
+	cfg.HTTP = nil
+	cfg.HeadersToResourceAttributes = tenantHeaders
+	sink := new(consumertest.TracesSink)
+	r := newReceiver(t, factory, cfg, sink, nil)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	defer r.Shutdown(context.Background())
+
+	cc, err := grpc.Dial(addr, grpc.WithInsecure(), grpc.WithBlock())
+	require.NoError(t, err)
+	defer cc.Close()
+	client := collectortrace.NewTraceServiceClient(cc)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "X-Tenant-Id", "acme", "x-region", "eu-west-1")
+	_, err = client.Export(ctx, createSingleSpanTrace())
+	require.NoError(t, err)
+
+	require.Len(t, sink.AllTraces(), 1)
+	attrs := sink.AllTraces()[0].ResourceSpans().At(0).Resource().Attributes()
+	tenant, ok := attrs.Get("tenant.id")
+	require.True(t, ok)
+	assert.Equal(t, "acme", tenant.StringVal())
+	region, ok := attrs.Get("cloud.region")
+	require.True(t, ok)
+	assert.Equal(t, "eu-west-1", region.StringVal())
+}
+
+func TestHTTPHeadersToResourceAttributes(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.SetName("otlp/http_headers")
+	cfg.HTTP.Endpoint = addr
+	cfg.GRPC = nil
+	cfg.HeadersToResourceAttributes = tenantHeaders
+	sink := new(consumertest.TracesSink)
+	r := newReceiver(t, factory, cfg, sink, nil)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	defer r.Shutdown(context.Background())
+
+	req, err := http.NewRequest(http.MethodPost, "http://"+addr+"/v1/traces", strings.NewReader(otlpJSONTraces))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", jsonContentType)
+	req.Header.Set("X-Tenant-Id", "acme")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	require.Len(t, sink.AllTraces(), 1)
+	attrs := sink.AllTraces()[0].ResourceSpans().At(0).Resource().Attributes()
+	tenant, ok := attrs.Get("tenant.id")
+	require.True(t, ok)
+	assert.Equal(t, "acme", tenant.StringVal())
+	service, ok := attrs.Get("service.name")
+	require.True(t, ok)
+	assert.Equal(t, "checkout", service.StringVal())
+	_, ok = attrs.Get("cloud.region")
+	assert.False(t, ok)
+}
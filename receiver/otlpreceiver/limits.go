@@ -0,0 +1,296 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlpreceiver
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	gatewayruntime "github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal"
+	collectorlog "go.opentelemetry.io/collector/internal/data/protogen/collector/logs/v1"
+	collectormetrics "go.opentelemetry.io/collector/internal/data/protogen/collector/metrics/v1"
+	collectortrace "go.opentelemetry.io/collector/internal/data/protogen/collector/trace/v1"
+	"go.opentelemetry.io/collector/obsreport"
+)
+
+const (
+	defaultRetryAfter = time.Second
+
+	limitsGRPCTransport = "grpc"
+	limitsHTTPTransport = "http"
+	limitsDataFormat    = "protobuf"
+)
+
+var (
+	errNegativeMaxInflightBytes  = errors.New("\"max_inflight_bytes\" cannot be negative")
+	errNegativeRequestsPerSecond = errors.New("\"requests_per_second\" cannot be negative")
+	errNegativeRetryAfter        = errors.New("\"retry_after\" cannot be negative")
+)
+
+// LimitsConfig bounds the load accepted by the receiver, over both gRPC and HTTP. The requests
+// exceeding a limit are refused with RESOURCE_EXHAUSTED over gRPC and 429 Too Many Requests
+// over HTTP, along with the delay after which the client should retry them.
+type LimitsConfig struct {
+	// MaxInflightBytes is the maximum total size, in bytes of serialized protobuf, of the
+	// requests being processed at the same time. Zero means no limit.
+	MaxInflightBytes int `mapstructure:"max_inflight_bytes"`
+	// RequestsPerSecond limits the rate of the requests of each signal.
+	RequestsPerSecond RequestsPerSecondConfig `mapstructure:"requests_per_second"`
+	// RetryAfter is the delay after which the clients are asked to retry the refused
+	// requests. Defaults to 1s.
+	RetryAfter time.Duration `mapstructure:"retry_after"`
+}
+
+// RequestsPerSecondConfig defines the maximum rate of the requests of each signal, bursts
+// of up to one second of requests being accepted. Zero means no limit.
+type RequestsPerSecondConfig struct {
+	Traces  float64 `mapstructure:"traces"`
+	Metrics float64 `mapstructure:"metrics"`
+	Logs    float64 `mapstructure:"logs"`
+}
+
+func (lc *LimitsConfig) validate() error {
+	if lc.MaxInflightBytes < 0 {
+		return errNegativeMaxInflightBytes
+	}
+	rps := lc.RequestsPerSecond
+	if rps.Traces < 0 || rps.Metrics < 0 || rps.Logs < 0 {
+		return errNegativeRequestsPerSecond
+	}
+	if lc.RetryAfter < 0 {
+		return errNegativeRetryAfter
+	}
+	return nil
+}
+
+// limiter enforces the LimitsConfig of a receiver.
+type limiter struct {
+	receiverName string
+	retryAfter   time.Duration
+	inflight     *inflightLimiter
+	traces       *rateLimiter
+	metrics      *rateLimiter
+	logs         *rateLimiter
+}
+
+// newLimiter returns the limiter enforcing the given limits, or nil when none is set.
+func newLimiter(receiverName string, lc LimitsConfig) *limiter {
+	rps := lc.RequestsPerSecond
+	if lc.MaxInflightBytes == 0 && rps.Traces == 0 && rps.Metrics == 0 && rps.Logs == 0 {
+		return nil
+	}
+	l := &limiter{
+		receiverName: receiverName,
+		retryAfter:   lc.RetryAfter,
+		inflight:     newInflightLimiter(lc.MaxInflightBytes),
+		traces:       newRateLimiter(rps.Traces, time.Now),
+		metrics:      newRateLimiter(rps.Metrics, time.Now),
+		logs:         newRateLimiter(rps.Logs, time.Now),
+	}
+	if l.retryAfter == 0 {
+		l.retryAfter = defaultRetryAfter
+	}
+	return l
+}
+
+// acquire checks that a request of the given size is within the limits, and returns the
+// function to call once it has been processed.
+func (l *limiter) acquire(rate *rateLimiter, size int) (func(), error) {
+	if !rate.allow() {
+		return nil, l.resourceExhausted("too many requests")
+	}
+	if !l.inflight.acquire(size) {
+		return nil, l.resourceExhausted("too many bytes in flight")
+	}
+	return func() { l.inflight.release(size) }, nil
+}
+
+func (l *limiter) resourceExhausted(msg string) error {
+	return resourceExhausted(msg, l.retryAfter)
+}
+
+// resourceExhausted returns a RESOURCE_EXHAUSTED status error asking the client to retry
+// after the given delay.
+func resourceExhausted(msg string, retryAfter time.Duration) error {
+	st, err := status.New(codes.ResourceExhausted, msg).WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(retryAfter)})
+	if err != nil {
+		return status.Error(codes.ResourceExhausted, msg)
+	}
+	return st.Err()
+}
+
+// inflightLimiter bounds the total size of the requests being processed. A request is
+// always accepted when no other request is in flight, so that a request larger than the
+// limit is not refused forever.
+type inflightLimiter struct {
+	mu      sync.Mutex
+	max     int
+	current int
+}
+
+func newInflightLimiter(max int) *inflightLimiter {
+	if max == 0 {
+		return nil
+	}
+	return &inflightLimiter{max: max}
+}
+
+func (l *inflightLimiter) acquire(size int) bool {
+	if l == nil {
+		return true
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.current > 0 && l.current+size > l.max {
+		return false
+	}
+	l.current += size
+	return true
+}
+
+func (l *inflightLimiter) release(size int) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	l.current -= size
+	l.mu.Unlock()
+}
+
+// rateLimiter is a token bucket holding up to one second of requests.
+type rateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	now    func() time.Time
+}
+
+func newRateLimiter(rate float64, now func() time.Time) *rateLimiter {
+	if rate == 0 {
+		return nil
+	}
+	burst := math.Max(rate, 1)
+	return &rateLimiter{rate: rate, burst: burst, tokens: burst, last: now(), now: now}
+}
+
+func (l *rateLimiter) allow() bool {
+	if l == nil {
+		return true
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+	return true
+}
+
+// limitsTransport returns the transport the request was received over: the limited servers are
+// registered both on the gRPC server, which sets the peer of the requests, and on the HTTP gateway.
+func limitsTransport(ctx context.Context) string {
+	if _, ok := peer.FromContext(ctx); ok {
+		return limitsGRPCTransport
+	}
+	return limitsHTTPTransport
+}
+
+// limitedTraceServer refuses the trace requests exceeding the limits of the receiver.
+type limitedTraceServer struct {
+	limiter *limiter
+	next    collectortrace.TraceServiceServer
+}
+
+func (s *limitedTraceServer) Export(ctx context.Context, req *collectortrace.ExportTraceServiceRequest) (*collectortrace.ExportTraceServiceResponse, error) {
+	release, err := s.limiter.acquire(s.limiter.traces, req.Size())
+	if err != nil {
+		name, transport := s.limiter.receiverName, limitsTransport(ctx)
+		ctx = obsreport.StartTraceDataReceiveOp(obsreport.ReceiverContext(ctx, name, transport), name, transport)
+		obsreport.EndTraceDataReceiveOp(ctx, limitsDataFormat, pdata.TracesFromOtlp(req.ResourceSpans).SpanCount(), err)
+		return nil, err
+	}
+	defer release()
+	return s.next.Export(ctx, req)
+}
+
+// limitedMetricsServer refuses the metrics requests exceeding the limits of the receiver.
+type limitedMetricsServer struct {
+	limiter *limiter
+	next    collectormetrics.MetricsServiceServer
+}
+
+func (s *limitedMetricsServer) Export(ctx context.Context, req *collectormetrics.ExportMetricsServiceRequest) (*collectormetrics.ExportMetricsServiceResponse, error) {
+	release, err := s.limiter.acquire(s.limiter.metrics, req.Size())
+	if err != nil {
+		name, transport := s.limiter.receiverName, limitsTransport(ctx)
+		_, numPoints := pdata.MetricsFromOtlp(req.ResourceMetrics).MetricAndDataPointCount()
+		ctx = obsreport.StartMetricsReceiveOp(obsreport.ReceiverContext(ctx, name, transport), name, transport)
+		obsreport.EndMetricsReceiveOp(ctx, limitsDataFormat, numPoints, err)
+		return nil, err
+	}
+	defer release()
+	return s.next.Export(ctx, req)
+}
+
+// limitedLogsServer refuses the logs requests exceeding the limits of the receiver.
+type limitedLogsServer struct {
+	limiter *limiter
+	next    collectorlog.LogsServiceServer
+}
+
+func (s *limitedLogsServer) Export(ctx context.Context, req *collectorlog.ExportLogsServiceRequest) (*collectorlog.ExportLogsServiceResponse, error) {
+	release, err := s.limiter.acquire(s.limiter.logs, req.Size())
+	if err != nil {
+		name, transport := s.limiter.receiverName, limitsTransport(ctx)
+		ctx = obsreport.StartLogsReceiveOp(obsreport.ReceiverContext(ctx, name, transport), name, transport)
+		obsreport.EndLogsReceiveOp(ctx, limitsDataFormat, pdata.LogsFromInternalRep(internal.LogsFromOtlp(req.ResourceLogs)).LogRecordCount(), err)
+		return nil, err
+	}
+	defer release()
+	return s.next.Export(ctx, req)
+}
+
+// protoErrorHandler sets the Retry-After header of the HTTP responses to the requests that
+// exceeded the limits of the receiver, the other errors are handled as by default.
+func protoErrorHandler(ctx context.Context, mux *gatewayruntime.ServeMux, marshaler gatewayruntime.Marshaler, w http.ResponseWriter, r *http.Request, err error) {
+	if s, ok := status.FromError(err); ok && s.Code() == codes.ResourceExhausted {
+		for _, detail := range s.Details() {
+			if info, ok := detail.(*errdetails.RetryInfo); ok {
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(info.RetryDelay.AsDuration().Seconds()))))
+			}
+		}
+		// the details are in the header, the JSON marshaler of the receiver cannot encode them.
+		err = status.Error(s.Code(), s.Message())
+	}
+	gatewayruntime.DefaultHTTPProtoErrorHandler(ctx, mux, marshaler, w, r, err)
+}
@@ -0,0 +1,191 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlpreceiver
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	collectortrace "go.opentelemetry.io/collector/internal/data/protogen/collector/trace/v1"
+	"go.opentelemetry.io/collector/obsreport/obsreporttest"
+	"go.opentelemetry.io/collector/testutil"
+)
+
+func TestLimitsConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		limits  LimitsConfig
+		wantErr error
+	}{
+		{name: "no limits"},
+		{name: "all limits", limits: LimitsConfig{MaxInflightBytes: 1024, RequestsPerSecond: RequestsPerSecondConfig{Traces: 1, Metrics: 1, Logs: 1}, RetryAfter: time.Second}},
+		{name: "negative inflight bytes", limits: LimitsConfig{MaxInflightBytes: -1}, wantErr: errNegativeMaxInflightBytes},
+		{name: "negative rate", limits: LimitsConfig{RequestsPerSecond: RequestsPerSecondConfig{Logs: -1}}, wantErr: errNegativeRequestsPerSecond},
+		{name: "negative retry after", limits: LimitsConfig{RetryAfter: -time.Second}, wantErr: errNegativeRetryAfter},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantErr, tt.limits.validate())
+		})
+	}
+}
+
+func TestCreateReceiverInvalidLimits(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Limits.MaxInflightBytes = -1
+	_, err := createReceiver(cfg, nil)
+	assert.Equal(t, errNegativeMaxInflightBytes, err)
+}
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Unix(0, 0)
+	l := newRateLimiter(2, func() time.Time { return now })
+
+	// a full second of requests is accepted at once.
+	assert.True(t, l.allow())
+	assert.True(t, l.allow())
+	assert.False(t, l.allow())
+
+	now = now.Add(500 * time.Millisecond)
+	assert.True(t, l.allow())
+	assert.False(t, l.allow())
+
+	// the tokens do not accumulate beyond a second of requests.
+	now = now.Add(time.Hour)
+	assert.True(t, l.allow())
+	assert.True(t, l.allow())
+	assert.False(t, l.allow())
+
+	assert.Nil(t, newRateLimiter(0, time.Now))
+	assert.True(t, (*rateLimiter)(nil).allow())
+}
+
+func TestRateLimiterBelowOneRequestPerSecond(t *testing.T) {
+	now := time.Unix(0, 0)
+	l := newRateLimiter(0.5, func() time.Time { return now })
+
+	assert.True(t, l.allow())
+	now = now.Add(time.Second)
+	assert.False(t, l.allow())
+	now = now.Add(time.Second)
+	assert.True(t, l.allow())
+}
+
+func TestInflightLimiter(t *testing.T) {
+	l := newInflightLimiter(100)
+
+	// a request larger than the limit is accepted when it is the only one.
+	assert.True(t, l.acquire(150))
+	assert.False(t, l.acquire(1))
+	l.release(150)
+
+	assert.True(t, l.acquire(60))
+	assert.True(t, l.acquire(40))
+	assert.False(t, l.acquire(1))
+	l.release(40)
+	assert.True(t, l.acquire(30))
+	assert.False(t, l.acquire(20))
+
+	assert.Nil(t, newInflightLimiter(0))
+	assert.True(t, (*inflightLimiter)(nil).acquire(1))
+}
+
+func TestNewLimiterWithoutLimits(t *testing.T) {
+	assert.Nil(t, newLimiter("otlp", LimitsConfig{RetryAfter: time.Second}))
+}
+
+func TestGRPCRequestsPerSecondLimit(t *testing.T) {
+	doneFn, err := obsreporttest.SetupRecordedMetricsTest()
+	require.NoError(t, err)
+	defer doneFn()
+
+	addr := testutil.GetAvailableLocalAddress(t)
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.SetName("otlp/grpc_limits")
+	cfg.GRPC.NetAddr.Endpoint = addr
+	cfg.HTTP = nil
+	cfg.Limits = LimitsConfig{RequestsPerSecond: RequestsPerSecondConfig{Traces: 1}, RetryAfter: 3 * time.Second}
+	sink := new(consumertest.TracesSink)
+	r := newReceiver(t, factory, cfg, sink, nil)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	defer r.Shutdown(context.Background())
+
+	cc, err := grpc.Dial(addr, grpc.WithInsecure(), grpc.WithBlock())
+	require.NoError(t, err)
+	defer cc.Close()
+	client := collectortrace.NewTraceServiceClient(cc)
+
+	_, err = client.Export(context.Background(), createSingleSpanTrace())
+	require.NoError(t, err)
+	_, err = client.Export(context.Background(), createSingleSpanTrace())
+	require.Error(t, err)
+
+	st := status.Convert(err)
+	assert.Equal(t, codes.ResourceExhausted, st.Code())
+	require.Len(t, st.Details(), 1)
+	info, ok := st.Details()[0].(*errdetails.RetryInfo)
+	require.True(t, ok)
+	assert.Equal(t, 3*time.Second, info.RetryDelay.AsDuration())
+	assert.Equal(t, 1, sink.SpansCount())
+	obsreporttest.CheckReceiverTracesViews(t, "otlp/grpc_limits", limitsGRPCTransport, 1, 1)
+}
+
+func TestHTTPRequestsPerSecondLimit(t *testing.T) {
+	doneFn, err := obsreporttest.SetupRecordedMetricsTest()
+	require.NoError(t, err)
+	defer doneFn()
+
+	addr := testutil.GetAvailableLocalAddress(t)
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.SetName("otlp/http_limits")
+	cfg.HTTP.Endpoint = addr
+	cfg.GRPC = nil
+	cfg.Limits = LimitsConfig{RequestsPerSecond: RequestsPerSecondConfig{Traces: 1}, RetryAfter: 1500 * time.Millisecond}
+	sink := new(consumertest.TracesSink)
+	r := newReceiver(t, factory, cfg, sink, nil)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	defer r.Shutdown(context.Background())
+
+	traceBytes, err := createSingleSpanTrace().Marshal()
+	require.NoError(t, err)
+	url := "http://" + addr + "/v1/traces"
+
+	resp, err := http.DefaultClient.Do(createHTTPProtobufRequest(t, url, "", traceBytes))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp, err = http.DefaultClient.Do(createHTTPProtobufRequest(t, url, "", traceBytes))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, "2", resp.Header.Get("Retry-After"))
+	assert.Equal(t, 1, sink.SpansCount())
+	// the refused request is recorded as received over HTTP.
+	obsreporttest.CheckReceiverTracesViews(t, "otlp/http_limits", limitsHTTPTransport, 0, 1)
+}
@@ -0,0 +1,213 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlpreceiver
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	collectorlog "go.opentelemetry.io/collector/internal/data/protogen/collector/logs/v1"
+	collectormetrics "go.opentelemetry.io/collector/internal/data/protogen/collector/metrics/v1"
+	collectortrace "go.opentelemetry.io/collector/internal/data/protogen/collector/trace/v1"
+	"go.opentelemetry.io/collector/internal/otlparrow"
+	"go.opentelemetry.io/collector/processor/memorylimiter/memorystate"
+)
+
+const memoryLimitedMsg = "memory usage is above the limit"
+
+// memoryGuard refuses the requests while a memory limiter reports that the
+// memory usage is above its soft limit. The requests are refused before being
+// decoded, with RESOURCE_EXHAUSTED over gRPC and 429 Too Many Requests over
+// HTTP, so that the clients retry them later instead of the collector using
+// memory to decode data that would be refused anyway.
+type memoryGuard struct {
+	retryAfter time.Duration
+}
+
+func newMemoryGuard(lc LimitsConfig) *memoryGuard {
+	g := &memoryGuard{retryAfter: lc.RetryAfter}
+	if g.retryAfter == 0 {
+		g.retryAfter = defaultRetryAfter
+	}
+	return g
+}
+
+// check returns the error to refuse the requests with, or nil when the memory
+// usage is within the limits.
+func (g *memoryGuard) check() error {
+	if memorystate.Current() == memorystate.Normal {
+		return nil
+	}
+	return resourceExhausted(memoryLimitedMsg, g.retryAfter)
+}
+
+// httpHandler refuses the export requests before their body is read.
+func (g *memoryGuard) httpHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && memorystate.Current() != memorystate.Normal {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(g.retryAfter.Seconds()))))
+			errorHandler(w, r, memoryLimitedMsg, http.StatusTooManyRequests)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// exportHandler returns the gRPC handler of an Export method, this is the
+// handler generated for the service except that the memory usage is checked
+// before decoding the request.
+func (g *memoryGuard) exportHandler(
+	fullMethod string,
+	newRequest func() interface{},
+	export func(srv interface{}, ctx context.Context, req interface{}) (interface{}, error),
+) func(interface{}, context.Context, func(interface{}) error, grpc.UnaryServerInterceptor) (interface{}, error) {
+	return func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+		if err := g.check(); err != nil {
+			return nil, err
+		}
+		in := newRequest()
+		if err := dec(in); err != nil {
+			return nil, err
+		}
+		if interceptor == nil {
+			return export(srv, ctx, in)
+		}
+		info := &grpc.UnaryServerInfo{
+			Server:     srv,
+			FullMethod: fullMethod,
+		}
+		handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+			return export(srv, ctx, req)
+		}
+		return interceptor(ctx, in, info, handler)
+	}
+}
+
+func (g *memoryGuard) traceServiceDesc() *grpc.ServiceDesc {
+	return &grpc.ServiceDesc{
+		ServiceName: "opentelemetry.proto.collector.trace.v1.TraceService",
+		HandlerType: (*collectortrace.TraceServiceServer)(nil),
+		Methods: []grpc.MethodDesc{
+			{
+				MethodName: "Export",
+				Handler: g.exportHandler(
+					"/opentelemetry.proto.collector.trace.v1.TraceService/Export",
+					func() interface{} { return new(collectortrace.ExportTraceServiceRequest) },
+					func(srv interface{}, ctx context.Context, req interface{}) (interface{}, error) {
+						return srv.(collectortrace.TraceServiceServer).Export(ctx, req.(*collectortrace.ExportTraceServiceRequest))
+					}),
+			},
+		},
+		Streams:  []grpc.StreamDesc{},
+		Metadata: "opentelemetry/proto/collector/trace/v1/trace_service.proto",
+	}
+}
+
+func (g *memoryGuard) metricsServiceDesc() *grpc.ServiceDesc {
+	return &grpc.ServiceDesc{
+		ServiceName: "opentelemetry.proto.collector.metrics.v1.MetricsService",
+		HandlerType: (*collectormetrics.MetricsServiceServer)(nil),
+		Methods: []grpc.MethodDesc{
+			{
+				MethodName: "Export",
+				Handler: g.exportHandler(
+					"/opentelemetry.proto.collector.metrics.v1.MetricsService/Export",
+					func() interface{} { return new(collectormetrics.ExportMetricsServiceRequest) },
+					func(srv interface{}, ctx context.Context, req interface{}) (interface{}, error) {
+						return srv.(collectormetrics.MetricsServiceServer).Export(ctx, req.(*collectormetrics.ExportMetricsServiceRequest))
+					}),
+			},
+		},
+		Streams:  []grpc.StreamDesc{},
+		Metadata: "opentelemetry/proto/collector/metrics/v1/metrics_service.proto",
+	}
+}
+
+func (g *memoryGuard) logsServiceDesc() *grpc.ServiceDesc {
+	return &grpc.ServiceDesc{
+		ServiceName: "opentelemetry.proto.collector.logs.v1.LogsService",
+		HandlerType: (*collectorlog.LogsServiceServer)(nil),
+		Methods: []grpc.MethodDesc{
+			{
+				MethodName: "Export",
+				Handler: g.exportHandler(
+					"/opentelemetry.proto.collector.logs.v1.LogsService/Export",
+					func() interface{} { return new(collectorlog.ExportLogsServiceRequest) },
+					func(srv interface{}, ctx context.Context, req interface{}) (interface{}, error) {
+						return srv.(collectorlog.LogsServiceServer).Export(ctx, req.(*collectorlog.ExportLogsServiceRequest))
+					}),
+			},
+		},
+		Streams:  []grpc.StreamDesc{},
+		Metadata: "opentelemetry/proto/collector/logs/v1/logs_service.proto",
+	}
+}
+
+// arrowTracesServiceDesc returns the experimental Arrow traces service, the Arrow tables of
+// the requests are decoded and exported to the trace service.
+func (g *memoryGuard) arrowTracesServiceDesc() *grpc.ServiceDesc {
+	return &grpc.ServiceDesc{
+		ServiceName: otlparrow.TracesServiceName,
+		HandlerType: (*collectortrace.TraceServiceServer)(nil),
+		Methods: []grpc.MethodDesc{
+			{
+				MethodName: "Export",
+				Handler: g.exportHandler(
+					otlparrow.TracesExportMethod,
+					func() interface{} { return new(otlparrow.Request) },
+					func(srv interface{}, ctx context.Context, req interface{}) (interface{}, error) {
+						rss, err := otlparrow.DecodeTraces(req.(*otlparrow.Request))
+						if err != nil {
+							return nil, status.Error(codes.InvalidArgument, err.Error())
+						}
+						return srv.(collectortrace.TraceServiceServer).Export(ctx, &collectortrace.ExportTraceServiceRequest{ResourceSpans: rss})
+					}),
+			},
+		},
+		Streams: []grpc.StreamDesc{},
+	}
+}
+
+// arrowMetricsServiceDesc returns the experimental Arrow metrics service, the Arrow tables of
+// the requests are decoded and exported to the metrics service.
+func (g *memoryGuard) arrowMetricsServiceDesc() *grpc.ServiceDesc {
+	return &grpc.ServiceDesc{
+		ServiceName: otlparrow.MetricsServiceName,
+		HandlerType: (*collectormetrics.MetricsServiceServer)(nil),
+		Methods: []grpc.MethodDesc{
+			{
+				MethodName: "Export",
+				Handler: g.exportHandler(
+					otlparrow.MetricsExportMethod,
+					func() interface{} { return new(otlparrow.Request) },
+					func(srv interface{}, ctx context.Context, req interface{}) (interface{}, error) {
+						rms, err := otlparrow.DecodeMetrics(req.(*otlparrow.Request))
+						if err != nil {
+							return nil, status.Error(codes.InvalidArgument, err.Error())
+						}
+						return srv.(collectormetrics.MetricsServiceServer).Export(ctx, &collectormetrics.ExportMetricsServiceRequest{ResourceMetrics: rms})
+					}),
+			},
+		},
+		Streams: []grpc.StreamDesc{},
+	}
+}
@@ -0,0 +1,182 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlpreceiver
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal"
+	collectorlog "go.opentelemetry.io/collector/internal/data/protogen/collector/logs/v1"
+	collectormetrics "go.opentelemetry.io/collector/internal/data/protogen/collector/metrics/v1"
+	collectortrace "go.opentelemetry.io/collector/internal/data/protogen/collector/trace/v1"
+	"go.opentelemetry.io/collector/internal/testdata"
+	"go.opentelemetry.io/collector/processor/memorylimiter/memorystate"
+	"go.opentelemetry.io/collector/testutil"
+)
+
+type fixedMemoryLevel struct {
+	level int32
+}
+
+func (f *fixedMemoryLevel) MemoryLevel() memorystate.Level {
+	return memorystate.Level(atomic.LoadInt32(&f.level))
+}
+
+func (f *fixedMemoryLevel) set(level memorystate.Level) {
+	atomic.StoreInt32(&f.level, int32(level))
+}
+
+// rawCodec sends the bytes of the requests as they are.
+type rawCodec struct{}
+
+func (rawCodec) Marshal(v interface{}) ([]byte, error) {
+	return v.([]byte), nil
+}
+
+func (rawCodec) Unmarshal(data []byte, v interface{}) error {
+	return nil
+}
+
+func (rawCodec) Name() string {
+	return "proto"
+}
+
+func TestGRPCMemoryLimited(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.SetName("otlp/grpc_memory")
+	cfg.GRPC.NetAddr.Endpoint = addr
+	cfg.HTTP = nil
+	cfg.Limits.RetryAfter = 2 * time.Second
+	traceSink := new(consumertest.TracesSink)
+	metricsSink := new(consumertest.MetricsSink)
+	logsSink := new(consumertest.LogsSink)
+	r := newReceiver(t, factory, cfg, traceSink, metricsSink)
+	_, err := factory.CreateLogsReceiver(context.Background(), component.ReceiverCreateParams{}, cfg, logsSink)
+	require.NoError(t, err)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	defer r.Shutdown(context.Background())
+
+	cc, err := grpc.Dial(addr, grpc.WithInsecure(), grpc.WithBlock())
+	require.NoError(t, err)
+	defer cc.Close()
+	traceClient := collectortrace.NewTraceServiceClient(cc)
+	metricsClient := collectormetrics.NewMetricsServiceClient(cc)
+	logsClient := collectorlog.NewLogsServiceClient(cc)
+	metricsReq := &collectormetrics.ExportMetricsServiceRequest{
+		ResourceMetrics: pdata.MetricsToOtlp(testdata.GenerateMetricsOneMetric()),
+	}
+	logsReq := &collectorlog.ExportLogsServiceRequest{
+		ResourceLogs: internal.LogsToOtlp(testdata.GenerateLogDataOneLog().InternalRep()),
+	}
+
+	memory := &fixedMemoryLevel{level: int32(memorystate.SoftLimited)}
+	unregister := memorystate.Register(memory)
+	defer unregister()
+
+	assertRefused := func(err error) {
+		st := status.Convert(err)
+		assert.Equal(t, codes.ResourceExhausted, st.Code())
+		require.Len(t, st.Details(), 1)
+		info, ok := st.Details()[0].(*errdetails.RetryInfo)
+		require.True(t, ok)
+		assert.Equal(t, 2*time.Second, info.RetryDelay.AsDuration())
+	}
+	_, err = traceClient.Export(context.Background(), createSingleSpanTrace())
+	assertRefused(err)
+	_, err = metricsClient.Export(context.Background(), metricsReq)
+	assertRefused(err)
+	_, err = logsClient.Export(context.Background(), logsReq)
+	assertRefused(err)
+
+	// The requests are refused before being decoded.
+	var resp collectortrace.ExportTraceServiceResponse
+	invalid := []byte{0xff, 0xff, 0xff}
+	err = cc.Invoke(context.Background(), "/opentelemetry.proto.collector.trace.v1.TraceService/Export", invalid, &resp, grpc.ForceCodec(rawCodec{}))
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	memory.set(memorystate.HardLimited)
+	_, err = traceClient.Export(context.Background(), createSingleSpanTrace())
+	assertRefused(err)
+
+	assert.Equal(t, 0, traceSink.SpansCount())
+	assert.Equal(t, 0, metricsSink.MetricsCount())
+	assert.Equal(t, 0, logsSink.LogRecordsCount())
+
+	memory.set(memorystate.Normal)
+	_, err = traceClient.Export(context.Background(), createSingleSpanTrace())
+	require.NoError(t, err)
+	_, err = metricsClient.Export(context.Background(), metricsReq)
+	require.NoError(t, err)
+	_, err = logsClient.Export(context.Background(), logsReq)
+	require.NoError(t, err)
+	assert.Equal(t, 1, traceSink.SpansCount())
+	assert.Equal(t, 1, metricsSink.MetricsCount())
+	assert.Equal(t, 1, logsSink.LogRecordsCount())
+
+	err = cc.Invoke(context.Background(), "/opentelemetry.proto.collector.trace.v1.TraceService/Export", invalid, &resp, grpc.ForceCodec(rawCodec{}))
+	assert.Equal(t, codes.Internal, status.Code(err))
+}
+
+func TestHTTPMemoryLimited(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.SetName("otlp/http_memory")
+	cfg.HTTP.Endpoint = addr
+	cfg.GRPC = nil
+	sink := new(consumertest.TracesSink)
+	r := newReceiver(t, factory, cfg, sink, nil)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	defer r.Shutdown(context.Background())
+
+	traceBytes, err := createSingleSpanTrace().Marshal()
+	require.NoError(t, err)
+	url := "http://" + addr + "/v1/traces"
+
+	memory := &fixedMemoryLevel{level: int32(memorystate.SoftLimited)}
+	unregister := memorystate.Register(memory)
+	defer unregister()
+
+	resp, err := http.DefaultClient.Do(createHTTPProtobufRequest(t, url, "", traceBytes))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, "1", resp.Header.Get("Retry-After"))
+	assert.Equal(t, 0, sink.SpansCount())
+
+	memory.set(memorystate.Normal)
+	resp, err = http.DefaultClient.Do(createHTTPProtobufRequest(t, url, "", traceBytes))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, 1, sink.SpansCount())
+}
@@ -33,6 +33,7 @@ import (
 	collectorlog "go.opentelemetry.io/collector/internal/data/protogen/collector/logs/v1"
 	collectormetrics "go.opentelemetry.io/collector/internal/data/protogen/collector/metrics/v1"
 	collectortrace "go.opentelemetry.io/collector/internal/data/protogen/collector/trace/v1"
+	"go.opentelemetry.io/collector/internal/otlparrow"
 	"go.opentelemetry.io/collector/receiver/otlpreceiver/logs"
 	"go.opentelemetry.io/collector/receiver/otlpreceiver/metrics"
 	"go.opentelemetry.io/collector/receiver/otlpreceiver/trace"
@@ -49,6 +50,10 @@ type otlpReceiver struct {
 	metricsReceiver *metrics.Receiver
 	logReceiver     *logs.Receiver
 
+	limiter *limiter
+	memory  *memoryGuard
+	headers *headerAttributes
+
 	stopOnce        sync.Once
 	startServerOnce sync.Once
 
@@ -60,8 +65,11 @@ type otlpReceiver struct {
 // as the various Stop*Reception methods to end it.
 func newOtlpReceiver(cfg *Config, logger *zap.Logger) (*otlpReceiver, error) {
 	r := &otlpReceiver{
-		cfg:    cfg,
-		logger: logger,
+		cfg:     cfg,
+		limiter: newLimiter(cfg.Name(), cfg.Limits),
+		memory:  newMemoryGuard(cfg.Limits),
+		headers: newHeaderAttributes(cfg.HeadersToResourceAttributes),
+		logger:  logger,
 	}
 	if cfg.GRPC != nil {
 		opts, err := cfg.GRPC.ToServerOption()
@@ -79,11 +87,15 @@ func newOtlpReceiver(cfg *Config, logger *zap.Logger) (*otlpReceiver, error) {
 			Indent:       "  ",
 			OrigName:     true,
 		}
-		r.gatewayMux = gatewayruntime.NewServeMux(
-			gatewayruntime.WithProtoErrorHandler(gatewayruntime.DefaultHTTPProtoErrorHandler),
-			gatewayruntime.WithMarshalerOption("application/x-protobuf", &xProtobufMarshaler{}),
+		opts := []gatewayruntime.ServeMuxOption{
+			gatewayruntime.WithProtoErrorHandler(protoErrorHandler),
+			gatewayruntime.WithMarshalerOption(protobufContentType, &xProtobufMarshaler{}),
 			gatewayruntime.WithMarshalerOption(gatewayruntime.MIMEWildcard, jsonpb),
-		)
+		}
+		if r.headers != nil {
+			opts = append(opts, gatewayruntime.WithIncomingHeaderMatcher(r.headers.headerMatcher))
+		}
+		r.gatewayMux = gatewayruntime.NewServeMux(opts...)
 	}
 
 	return r, nil
@@ -140,10 +152,13 @@ func (r *otlpReceiver) startProtocolServers(host component.Host) error {
 		}
 	}
 	if r.cfg.HTTP != nil {
-		r.serverHTTP = r.cfg.HTTP.ToServer(
-			r.gatewayMux,
+		r.serverHTTP, err = r.cfg.HTTP.ToServer(
+			r.memory.httpHandler(contentTypeHandler(r.gatewayMux)),
 			confighttp.WithErrorHandler(errorHandler),
 		)
+		if err != nil {
+			return err
+		}
 		err = r.startHTTPServer(r.cfg.HTTP, host)
 		if err != nil {
 			return err
@@ -184,49 +199,85 @@ func (r *otlpReceiver) Shutdown(ctx context.Context) error {
 	return err
 }
 
-func (r *otlpReceiver) registerTraceConsumer(ctx context.Context, tc consumer.TracesConsumer) error {
+func (r *otlpReceiver) registerTraceConsumer(ctx context.Context, tc consumer.TracesConsumer, backpressure bool) error {
 	if tc == nil {
 		return componenterror.ErrNilNextConsumer
 	}
 	r.traceReceiver = trace.New(r.cfg.Name(), tc)
+	var server collectortrace.TraceServiceServer = r.traceReceiver
+	if backpressure {
+		server = &backpressureTraceServer{next: server}
+	}
+	if r.headers != nil {
+		server = &headersTraceServer{headers: r.headers, next: server}
+	}
+	if r.limiter != nil {
+		server = &limitedTraceServer{limiter: r.limiter, next: server}
+	}
 	if r.serverGRPC != nil {
-		collectortrace.RegisterTraceServiceServer(r.serverGRPC, r.traceReceiver)
+		r.serverGRPC.RegisterService(r.memory.traceServiceDesc(), server)
+		if otlparrow.Enabled {
+			r.serverGRPC.RegisterService(r.memory.arrowTracesServiceDesc(), server)
+		}
 	}
 	if r.gatewayMux != nil {
-		err := collectortrace.RegisterTraceServiceHandlerServer(ctx, r.gatewayMux, r.traceReceiver)
+		err := collectortrace.RegisterTraceServiceHandlerServer(ctx, r.gatewayMux, server)
 		if err != nil {
 			return err
 		}
 		// Also register an alias handler. This fixes bug https://github.com/open-telemetry/opentelemetry-collector/issues/1968
-		return collectortrace.RegisterTraceServiceHandlerServerAlias(ctx, r.gatewayMux, r.traceReceiver)
+		return collectortrace.RegisterTraceServiceHandlerServerAlias(ctx, r.gatewayMux, server)
 	}
 	return nil
 }
 
-func (r *otlpReceiver) registerMetricsConsumer(ctx context.Context, mc consumer.MetricsConsumer) error {
+func (r *otlpReceiver) registerMetricsConsumer(ctx context.Context, mc consumer.MetricsConsumer, backpressure bool) error {
 	if mc == nil {
 		return componenterror.ErrNilNextConsumer
 	}
 	r.metricsReceiver = metrics.New(r.cfg.Name(), mc)
+	var server collectormetrics.MetricsServiceServer = r.metricsReceiver
+	if backpressure {
+		server = &backpressureMetricsServer{next: server}
+	}
+	if r.headers != nil {
+		server = &headersMetricsServer{headers: r.headers, next: server}
+	}
+	if r.limiter != nil {
+		server = &limitedMetricsServer{limiter: r.limiter, next: server}
+	}
 	if r.serverGRPC != nil {
-		collectormetrics.RegisterMetricsServiceServer(r.serverGRPC, r.metricsReceiver)
+		r.serverGRPC.RegisterService(r.memory.metricsServiceDesc(), server)
+		if otlparrow.Enabled {
+			r.serverGRPC.RegisterService(r.memory.arrowMetricsServiceDesc(), server)
+		}
 	}
 	if r.gatewayMux != nil {
-		return collectormetrics.RegisterMetricsServiceHandlerServer(ctx, r.gatewayMux, r.metricsReceiver)
+		return collectormetrics.RegisterMetricsServiceHandlerServer(ctx, r.gatewayMux, server)
 	}
 	return nil
 }
 
-func (r *otlpReceiver) registerLogsConsumer(ctx context.Context, tc consumer.LogsConsumer) error {
+func (r *otlpReceiver) registerLogsConsumer(ctx context.Context, tc consumer.LogsConsumer, backpressure bool) error {
 	if tc == nil {
 		return componenterror.ErrNilNextConsumer
 	}
 	r.logReceiver = logs.New(r.cfg.Name(), tc)
+	var server collectorlog.LogsServiceServer = r.logReceiver
+	if backpressure {
+		server = &backpressureLogsServer{next: server}
+	}
+	if r.headers != nil {
+		server = &headersLogsServer{headers: r.headers, next: server}
+	}
+	if r.limiter != nil {
+		server = &limitedLogsServer{limiter: r.limiter, next: server}
+	}
 	if r.serverGRPC != nil {
-		collectorlog.RegisterLogsServiceServer(r.serverGRPC, r.logReceiver)
+		r.serverGRPC.RegisterService(r.memory.logsServiceDesc(), server)
 	}
 	if r.gatewayMux != nil {
-		return collectorlog.RegisterLogsServiceHandlerServer(ctx, r.gatewayMux, r.logReceiver)
+		return collectorlog.RegisterLogsServiceHandlerServer(ctx, r.gatewayMux, server)
 	}
 	return nil
 }
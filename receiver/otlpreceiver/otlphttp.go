@@ -16,6 +16,7 @@ package otlpreceiver
 
 import (
 	"bytes"
+	"mime"
 	"net/http"
 
 	"github.com/gogo/protobuf/jsonpb"
@@ -38,6 +39,34 @@ func (*xProtobufMarshaler) ContentType() string {
 
 var jsonMarshaller = &jsonpb.Marshaler{}
 
+const (
+	jsonContentType     = "application/json"
+	protobufContentType = "application/x-protobuf"
+)
+
+// contentTypeHandler negotiates the encoding of the export requests from their Content-Type,
+// OTLP/JSON or binary protobuf. The requests without a Content-Type are decoded as JSON,
+// the requests of any other type are refused with 415 Unsupported Media Type.
+func contentTypeHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			next.ServeHTTP(w, r)
+			return
+		}
+		contentType := r.Header.Get("Content-Type")
+		if contentType != "" {
+			mediaType, _, err := mime.ParseMediaType(contentType)
+			if err != nil || (mediaType != jsonContentType && mediaType != protobufContentType) {
+				errorHandler(w, r, "unsupported content type "+contentType, http.StatusUnsupportedMediaType)
+				return
+			}
+			// the marshalers are selected by the exact media type, without its parameters.
+			r.Header.Set("Content-Type", mediaType)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
 // errorHandler encodes the HTTP error message inside a rpc.Status message as required
 // by the OTLP protocol.
 func errorHandler(w http.ResponseWriter, r *http.Request, errMsg string, statusCode int) {
@@ -50,19 +79,23 @@ func errorHandler(w http.ResponseWriter, r *http.Request, errMsg string, statusC
 	fallbackMsg := []byte(`{"code": 13, "message": "failed to marshal error message"}`)
 	fallbackContentType := "application/json"
 
-	if statusCode == http.StatusBadRequest {
+	switch statusCode {
+	case http.StatusBadRequest, http.StatusUnsupportedMediaType:
 		s = status.New(codes.InvalidArgument, errMsg)
-	} else {
+	case http.StatusTooManyRequests:
+		s = status.New(codes.ResourceExhausted, errMsg)
+	default:
 		s = status.New(codes.Internal, errMsg)
 	}
 
 	contentType := r.Header.Get("Content-Type")
-	if contentType == "application/json" {
+	if contentType == jsonContentType {
 		buf := new(bytes.Buffer)
 		err = jsonMarshaller.Marshal(buf, s.Proto())
 		msg = buf.Bytes()
 	} else {
 		msg, err = proto.Marshal(s.Proto())
+		contentType = protobufContentType
 	}
 	if err != nil {
 		msg = fallbackMsg
@@ -0,0 +1,136 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlpreceiver
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/testutil"
+)
+
+// The requests below use the Protobuf JSON mapping, as sent by OTLP/JSON clients: lowerCamelCase
+// field names, enum values as strings, 64 bits integers as strings and bytes as base64.
+const (
+	otlpJSONTraces = `{"resourceSpans": [{
+		"resource": {"attributes": [{"key": "service.name", "value": {"stringValue": "checkout"}}]},
+		"instrumentationLibrarySpans": [{"spans": [{
+			"traceId": "W47/95gDgQPSabYzgT/GDA==",
+			"spanId": "7uGbfsPBsXM=",
+			"name": "GET /cart",
+			"kind": "SPAN_KIND_SERVER",
+			"startTimeUnixNano": "1544712660000000000",
+			"endTimeUnixNano": "1544712661000000000",
+			"status": {"code": "STATUS_CODE_ERROR"},
+			"unknownField": true
+		}]}]
+	}]}`
+	otlpJSONMetrics = `{"resourceMetrics": [{
+		"instrumentationLibraryMetrics": [{"metrics": [{
+			"name": "requests",
+			"intSum": {
+				"aggregationTemporality": "AGGREGATION_TEMPORALITY_CUMULATIVE",
+				"isMonotonic": true,
+				"dataPoints": [{"startTimeUnixNano": "1544712660000000000", "timeUnixNano": "1544712661000000000", "value": "42"}]
+			}
+		}]}]
+	}]}`
+	otlpJSONLogs = `{"resourceLogs": [{
+		"instrumentationLibraryLogs": [{"logs": [{
+			"timeUnixNano": "1544712660000000000",
+			"severityNumber": "SEVERITY_NUMBER_WARN",
+			"body": {"stringValue": "cart is empty"},
+			"traceId": "W47/95gDgQPSabYzgT/GDA==",
+			"spanId": "7uGbfsPBsXM="
+		}]}]
+	}]}`
+)
+
+func TestOTLPJSONHTTP(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.SetName("otlp/json")
+	cfg.HTTP.Endpoint = addr
+	cfg.GRPC = nil
+
+	tracesSink := new(consumertest.TracesSink)
+	metricsSink := new(consumertest.MetricsSink)
+	logsSink := new(consumertest.LogsSink)
+	params := component.ReceiverCreateParams{Logger: zap.NewNop()}
+	r, err := factory.CreateTracesReceiver(context.Background(), params, cfg, tracesSink)
+	require.NoError(t, err)
+	_, err = factory.CreateMetricsReceiver(context.Background(), params, cfg, metricsSink)
+	require.NoError(t, err)
+	_, err = factory.CreateLogsReceiver(context.Background(), params, cfg, logsSink)
+	require.NoError(t, err)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	defer r.Shutdown(context.Background())
+
+	for path, body := range map[string]string{"/v1/traces": otlpJSONTraces, "/v1/metrics": otlpJSONMetrics, "/v1/logs": otlpJSONLogs} {
+		resp, err := http.Post("http://"+addr+path, "application/json; charset=utf-8", strings.NewReader(body))
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode, path)
+		assert.Equal(t, jsonContentType, resp.Header.Get("Content-Type"), path)
+	}
+
+	require.Len(t, tracesSink.AllTraces(), 1)
+	span := tracesSink.AllTraces()[0].ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0)
+	assert.Equal(t, "5b8efff798038103d269b633813fc60c", span.TraceID().HexString())
+	assert.Equal(t, "eee19b7ec3c1b173", span.SpanID().HexString())
+	assert.Equal(t, pdata.SpanKindSERVER, span.Kind())
+	assert.Equal(t, pdata.StatusCodeError, span.Status().Code())
+	assert.Equal(t, pdata.Timestamp(1544712660000000000), span.StartTime())
+
+	require.Len(t, metricsSink.AllMetrics(), 1)
+	metric := metricsSink.AllMetrics()[0].ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().At(0)
+	assert.Equal(t, pdata.MetricDataTypeIntSum, metric.DataType())
+	assert.Equal(t, pdata.AggregationTemporalityCumulative, metric.IntSum().AggregationTemporality())
+	assert.Equal(t, int64(42), metric.IntSum().DataPoints().At(0).Value())
+
+	require.Len(t, logsSink.AllLogs(), 1)
+	log := logsSink.AllLogs()[0].ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(0)
+	assert.Equal(t, pdata.SeverityNumberWARN, log.SeverityNumber())
+	assert.Equal(t, "cart is empty", log.Body().StringVal())
+	assert.Equal(t, "5b8efff798038103d269b633813fc60c", log.TraceID().HexString())
+}
+
+func TestOTLPHTTPUnsupportedContentType(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+	sink := new(consumertest.TracesSink)
+	r := newHTTPReceiver(t, addr, sink, nil)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	defer r.Shutdown(context.Background())
+
+	for _, contentType := range []string{"text/plain", "application/json;;"} {
+		resp, err := http.Post("http://"+addr+"/v1/traces", contentType, strings.NewReader(otlpJSONTraces))
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusUnsupportedMediaType, resp.StatusCode, contentType)
+		assert.Equal(t, protobufContentType, resp.Header.Get("Content-Type"), contentType)
+	}
+	assert.Equal(t, 0, sink.SpansCount())
+}
@@ -15,11 +15,15 @@
 package prometheusreceiver
 
 import (
+	"errors"
+	"fmt"
 	"time"
 
+	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/config"
 
 	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/receiver/prometheusreceiver/internal"
 )
 
 // Config defines configuration for Prometheus receiver.
@@ -31,8 +35,208 @@ type Config struct {
 	UseStartTimeMetric            bool           `mapstructure:"use_start_time_metric"`
 	StartTimeMetricRegex          string         `mapstructure:"start_time_metric_regex"`
 
+	// ConfigFile is the path to a Prometheus configuration file that is used instead of
+	// the inline "config" section. The file is re-read and applied, without restarting
+	// the receiver, every time the collector process receives a SIGHUP.
+	ConfigFile string `mapstructure:"config_file"`
+
+	// ReportStaleness defines how the staleness markers reported when a series or a target
+	// disappears are handled: "drop" (the default) drops them, "flag" forwards them as data
+	// points holding the Prometheus staleness NaN value and "gap" closes the cumulative series
+	// so that its next sample starts a new series.
+	ReportStaleness internal.StalenessMode `mapstructure:"report_staleness"`
+
+	// MaxTimeseriesPerBatch, when positive, makes the receiver forward the metrics of a scrape
+	// in batches of about this many timeseries while the scrape is processed, instead of
+	// forwarding all of them once the whole scrape has been processed.
+	MaxTimeseriesPerBatch int `mapstructure:"max_timeseries_per_batch"`
+
+	// Federation, when set, makes the receiver read the series of an existing Prometheus
+	// server from its federation endpoint, in addition to any configured scrape jobs.
+	Federation *FederationConfig `mapstructure:"federation"`
+
+	// Push, when set, starts an HTTP endpoint accepting metrics pushed with the Pushgateway
+	// API. The receiver can then be used without any scrape job.
+	Push *PushConfig `mapstructure:"push"`
+
+	// ResourceAttributesFromLabels lists, per scrape job, the metric labels that are
+	// promoted to resource attributes and removed from the data point labels.
+	ResourceAttributesFromLabels []ResourceAttributesFromLabels `mapstructure:"resource_attributes_from_labels"`
+
+	// SeriesLimits bounds, per scrape job, the labels and the number of the series exposed
+	// by a target. Series exceeding the limits are dropped, or truncated.
+	SeriesLimits []SeriesLimitsConfig `mapstructure:"series_limits"`
+
+	// TargetAllowlist, when not empty, restricts the targets found by service discovery
+	// to the ones matching any of its filters. It applies to all scrape jobs, before
+	// their relabel_configs.
+	TargetAllowlist []TargetFilter `mapstructure:"target_allowlist"`
+	// TargetDenylist excludes the targets found by service discovery matching any of its
+	// filters. It applies to all scrape jobs, before their relabel_configs, and takes
+	// precedence over TargetAllowlist.
+	TargetDenylist []TargetFilter `mapstructure:"target_denylist"`
+
+	// Sharding, when set, splits the targets found by service discovery between several
+	// receivers so that each target is only scraped by one of them.
+	Sharding *ShardingConfig `mapstructure:"sharding"`
+
 	// ConfigPlaceholder is just an entry to make the configuration pass a check
 	// that requires that all keys present in the config actually exist on the
 	// structure, ie.: it will error if an unknown key is present.
 	ConfigPlaceholder interface{} `mapstructure:"config"`
 }
+
+// ResourceAttributesFromLabels defines the metric labels of a scrape job promoted to
+// resource attributes. The data points of a scrape are grouped under one resource per
+// distinct set of values of these labels.
+type ResourceAttributesFromLabels struct {
+	// JobName is the name of the scrape job the labels are promoted for.
+	JobName string `mapstructure:"job_name"`
+	// Labels are the names of the promoted labels, used as is as attribute keys.
+	Labels []string `mapstructure:"labels"`
+}
+
+// SeriesLimitsConfig defines the series limits of a scrape job, a zero value disables a limit.
+type SeriesLimitsConfig struct {
+	// JobName is the name of the scrape job the limits apply to.
+	JobName string `mapstructure:"job_name"`
+	// MaxLabelsPerMetric is the maximum number of labels of a series, not counting the
+	// __name__, job, instance, le and quantile labels. Series with more labels are dropped.
+	MaxLabelsPerMetric int `mapstructure:"max_labels_per_metric"`
+	// MaxLabelValueLength is the maximum length in bytes of a label value, longer values
+	// are truncated.
+	MaxLabelValueLength int `mapstructure:"max_label_value_length"`
+	// MaxSeriesPerTarget is the maximum number of series of a scrape of a target, the
+	// series exceeding it are dropped.
+	MaxSeriesPerTarget int `mapstructure:"max_series_per_target"`
+}
+
+// seriesLimitsByJob validates SeriesLimits and indexes it by job name.
+func (cfg *Config) seriesLimitsByJob() (map[string]*internal.SeriesLimits, error) {
+	if len(cfg.SeriesLimits) == 0 {
+		return nil, nil
+	}
+	byJob := make(map[string]*internal.SeriesLimits, len(cfg.SeriesLimits))
+	for _, sl := range cfg.SeriesLimits {
+		if sl.JobName == "" {
+			return nil, errors.New("series_limits requires a \"job_name\"")
+		}
+		if sl.MaxLabelsPerMetric < 0 || sl.MaxLabelValueLength < 0 || sl.MaxSeriesPerTarget < 0 {
+			return nil, fmt.Errorf("series_limits for job %q cannot be negative", sl.JobName)
+		}
+		if _, ok := byJob[sl.JobName]; ok {
+			return nil, fmt.Errorf("series_limits for job %q is defined more than once", sl.JobName)
+		}
+		byJob[sl.JobName] = &internal.SeriesLimits{
+			MaxLabelsPerMetric:  sl.MaxLabelsPerMetric,
+			MaxLabelValueLength: sl.MaxLabelValueLength,
+			MaxSeriesPerTarget:  sl.MaxSeriesPerTarget,
+		}
+	}
+	return byJob, nil
+}
+
+// TargetFilter matches the targets found by service discovery on the value of one of their labels.
+type TargetFilter struct {
+	// Label is the name of the discovered label that is matched, e.g. "__meta_kubernetes_namespace".
+	// Defaults to "__address__".
+	Label string `mapstructure:"label"`
+	// Regex is the regular expression the value of the label must fully match.
+	Regex string `mapstructure:"regex"`
+}
+
+// ShardingConfig defines the share of the discovered targets scraped by the receiver. Every
+// receiver of a fleet uses the same TotalShards and HashLabels and a distinct ShardIndex.
+type ShardingConfig struct {
+	// ShardIndex is the shard of this receiver, from 0 to TotalShards - 1.
+	ShardIndex uint64 `mapstructure:"shard_index"`
+	// TotalShards is the number of shards the targets are split into.
+	TotalShards uint64 `mapstructure:"total_shards"`
+	// HashLabels are the discovered labels whose values make the hash key of a target.
+	// Defaults to "__address__".
+	HashLabels []string `mapstructure:"hash_labels"`
+}
+
+func (sc *ShardingConfig) targetShard() (*internal.TargetShard, error) {
+	if sc.TotalShards == 0 {
+		return nil, errors.New("sharding requires a non-zero \"total_shards\"")
+	}
+	if sc.ShardIndex >= sc.TotalShards {
+		return nil, fmt.Errorf("sharding \"shard_index\" %d must be less than \"total_shards\" %d", sc.ShardIndex, sc.TotalShards)
+	}
+	hashLabels := sc.HashLabels
+	if len(hashLabels) == 0 {
+		hashLabels = []string{model.AddressLabel}
+	}
+	labelNames := make([]model.LabelName, 0, len(hashLabels))
+	for _, label := range hashLabels {
+		if !model.LabelName(label).IsValid() {
+			return nil, fmt.Errorf("sharding has an invalid hash label %q", label)
+		}
+		labelNames = append(labelNames, model.LabelName(label))
+	}
+	return &internal.TargetShard{Index: sc.ShardIndex, Total: sc.TotalShards, Labels: labelNames}, nil
+}
+
+// targetFilter compiles TargetAllowlist, TargetDenylist and Sharding, it returns nil when
+// none of them is set.
+func (cfg *Config) targetFilter() (*internal.TargetFilter, error) {
+	if len(cfg.TargetAllowlist) == 0 && len(cfg.TargetDenylist) == 0 && cfg.Sharding == nil {
+		return nil, nil
+	}
+	allow, err := compileTargetFilters("target_allowlist", cfg.TargetAllowlist)
+	if err != nil {
+		return nil, err
+	}
+	deny, err := compileTargetFilters("target_denylist", cfg.TargetDenylist)
+	if err != nil {
+		return nil, err
+	}
+	filter := &internal.TargetFilter{Allow: allow, Deny: deny}
+	if cfg.Sharding != nil {
+		if filter.Shard, err = cfg.Sharding.targetShard(); err != nil {
+			return nil, err
+		}
+	}
+	return filter, nil
+}
+
+func compileTargetFilters(key string, filters []TargetFilter) ([]internal.TargetMatcher, error) {
+	matchers := make([]internal.TargetMatcher, 0, len(filters))
+	for _, f := range filters {
+		label := f.Label
+		if label == "" {
+			label = model.AddressLabel
+		}
+		if !model.LabelName(label).IsValid() {
+			return nil, fmt.Errorf("%s has an invalid label %q", key, label)
+		}
+		m, err := internal.NewTargetMatcher(label, f.Regex)
+		if err != nil {
+			return nil, fmt.Errorf("%s has an invalid regex %q: %w", key, f.Regex, err)
+		}
+		matchers = append(matchers, m)
+	}
+	return matchers, nil
+}
+
+// resourceLabelsByJob validates ResourceAttributesFromLabels and indexes it by job name.
+func (cfg *Config) resourceLabelsByJob() (map[string][]string, error) {
+	if len(cfg.ResourceAttributesFromLabels) == 0 {
+		return nil, nil
+	}
+	byJob := make(map[string][]string, len(cfg.ResourceAttributesFromLabels))
+	for _, ra := range cfg.ResourceAttributesFromLabels {
+		if ra.JobName == "" {
+			return nil, errors.New("resource_attributes_from_labels requires a \"job_name\"")
+		}
+		if len(ra.Labels) == 0 {
+			return nil, fmt.Errorf("resource_attributes_from_labels for job %q requires at least one label", ra.JobName)
+		}
+		if _, ok := byJob[ra.JobName]; ok {
+			return nil, fmt.Errorf("resource_attributes_from_labels for job %q is defined more than once", ra.JobName)
+		}
+		byJob[ra.JobName] = ra.Labels
+	}
+	return byJob, nil
+}
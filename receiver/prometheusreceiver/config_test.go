@@ -26,8 +26,10 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/config/configtest"
+	"go.opentelemetry.io/collector/receiver/prometheusreceiver/internal"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -56,6 +58,22 @@ func TestLoadConfig(t *testing.T) {
 	assert.Equal(t, time.Duration(r1.PrometheusConfig.ScrapeConfigs[0].ScrapeInterval), 5*time.Second)
 	assert.Equal(t, r1.UseStartTimeMetric, true)
 	assert.Equal(t, r1.StartTimeMetricRegex, "^(.+_)*process_start_time_seconds$")
+	assert.Equal(t, r1.ReportStaleness, internal.StalenessGap)
+	assert.Equal(t, r1.MaxTimeseriesPerBatch, 10000)
+	assert.Equal(t, r1.ResourceAttributesFromLabels, []ResourceAttributesFromLabels{
+		{JobName: "demo", Labels: []string{"namespace", "pod"}},
+	})
+	assert.Equal(t, r1.Push, &PushConfig{HTTPServerSettings: confighttp.HTTPServerSettings{Endpoint: "0.0.0.0:9091"}})
+	assert.Equal(t, r1.SeriesLimits, []SeriesLimitsConfig{
+		{JobName: "demo", MaxLabelsPerMetric: 30, MaxLabelValueLength: 200, MaxSeriesPerTarget: 10000},
+	})
+	assert.Equal(t, r1.TargetAllowlist, []TargetFilter{{Regex: ".*:8080"}})
+	assert.Equal(t, r1.TargetDenylist, []TargetFilter{{Label: "__meta_kubernetes_namespace", Regex: "kube-system"}})
+	assert.Equal(t, r1.Sharding, &ShardingConfig{
+		ShardIndex:  1,
+		TotalShards: 3,
+		HashLabels:  []string{"__address__", "__meta_kubernetes_namespace"},
+	})
 }
 
 func TestLoadConfigWithEnvVar(t *testing.T) {
@@ -114,6 +132,27 @@ func TestLoadConfigK8s(t *testing.T) {
 		"$1:$2")
 }
 
+func TestLoadConfigFederation(t *testing.T) {
+	factories, err := componenttest.NopFactories()
+	assert.NoError(t, err)
+
+	factory := NewFactory()
+	factories.Receivers[typeStr] = factory
+	cfg, err := configtest.LoadConfigFile(t, path.Join(".", "testdata", "config_federation.yaml"), factories)
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+
+	r := cfg.Receivers["prometheus"].(*Config)
+	assert.Nil(t, r.PrometheusConfig)
+	require.NotNil(t, r.Federation)
+	assert.Equal(t, "https://prometheus:9090", r.Federation.Endpoint)
+	assert.Equal(t, 30*time.Second, r.Federation.Interval)
+	assert.Equal(t, []string{`{job="node"}`, `{__name__=~"job:.*"}`}, r.Federation.Match)
+	require.NotNil(t, r.Federation.TLSSetting)
+	assert.Equal(t, "/etc/prometheus/ca.crt", r.Federation.TLSSetting.CAFile)
+	assert.Equal(t, "prometheus.local", r.Federation.TLSSetting.ServerName)
+}
+
 func TestLoadConfigFailsOnUnknownSection(t *testing.T) {
 	factories, err := componenttest.NopFactories()
 	assert.NoError(t, err)
@@ -19,6 +19,7 @@ import (
 	"errors"
 	"fmt"
 
+	promconfig "github.com/prometheus/prometheus/config"
 	_ "github.com/prometheus/prometheus/discovery/install" // init() of this package registers service discovery impl.
 	"github.com/spf13/viper"
 	"gopkg.in/yaml.v2"
@@ -26,6 +27,7 @@ import (
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/receiver/prometheusreceiver/internal"
 	"go.opentelemetry.io/collector/receiver/receiverhelper"
 )
 
@@ -40,7 +42,10 @@ const (
 )
 
 var (
-	errNilScrapeConfig = errors.New("expecting a non-nil ScrapeConfig")
+	errNilScrapeConfig          = errors.New("expecting a non-nil ScrapeConfig")
+	errConfigAndConfigFileIsSet = errors.New("only one of \"config\" and \"config_file\" can be set")
+
+	errNegativeMaxTimeseriesPerBatch = errors.New("\"max_timeseries_per_batch\" cannot be negative")
 )
 
 func NewFactory() component.ReceiverFactory {
@@ -67,6 +72,9 @@ func customUnmarshaler(componentViperSection *viper.Viper, intoCfg interface{})
 	if !componentViperSection.IsSet(prometheusConfigKey) {
 		return nil
 	}
+	if intoCfg.(*Config).ConfigFile != "" {
+		return errConfigAndConfigFileIsSet
+	}
 	promCfgMap := componentViperSection.Sub(prometheusConfigKey).AllSettings()
 	out, err := yaml.Marshal(promCfgMap)
 	if err != nil {
@@ -90,6 +98,7 @@ func createDefaultConfig() configmodels.Receiver {
 			TypeVal: typeStr,
 			NameVal: typeStr,
 		},
+		ReportStaleness: internal.StalenessDrop,
 	}
 }
 
@@ -100,7 +109,41 @@ func createMetricsReceiver(
 	nextConsumer consumer.MetricsConsumer,
 ) (component.MetricsReceiver, error) {
 	config := cfg.(*Config)
-	if config.PrometheusConfig == nil || len(config.PrometheusConfig.ScrapeConfigs) == 0 {
+	if err := internal.ValidateStalenessMode(config.ReportStaleness); err != nil {
+		return nil, err
+	}
+	if config.MaxTimeseriesPerBatch < 0 {
+		return nil, errNegativeMaxTimeseriesPerBatch
+	}
+	if config.Push != nil {
+		if err := config.Push.validate(); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := config.resourceLabelsByJob(); err != nil {
+		return nil, err
+	}
+	if _, err := config.seriesLimitsByJob(); err != nil {
+		return nil, err
+	}
+	if _, err := config.targetFilter(); err != nil {
+		return nil, err
+	}
+	if config.ConfigFile != "" {
+		promCfg, err := promconfig.LoadFile(config.ConfigFile)
+		if err != nil {
+			return nil, fmt.Errorf("prometheus receiver failed to load config file %q: %w", config.ConfigFile, err)
+		}
+		// copy so that the configuration of the caller is not modified.
+		withFile := *config
+		withFile.PrometheusConfig = promCfg
+		config = &withFile
+	}
+	promCfg, err := config.withFederation(config.PrometheusConfig)
+	if err != nil {
+		return nil, err
+	}
+	if (promCfg == nil || len(promCfg.ScrapeConfigs) == 0) && config.Push == nil {
 		return nil, errNilScrapeConfig
 	}
 	return newPrometheusReceiver(params.Logger, config, nextConsumer), nil
@@ -20,11 +20,13 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config/configcheck"
+	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/config/configtest"
 )
 
@@ -55,3 +57,158 @@ func TestFactoryCanParseServiceDiscoveryConfigs(t *testing.T) {
 
 	assert.NoError(t, err)
 }
+
+func TestCreateReceiverFromConfigFile(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.ConfigFile = path.Join(".", "testdata", "prometheus.yaml")
+
+	creationParams := component.ReceiverCreateParams{Logger: zap.NewNop()}
+	mReceiver, err := createMetricsReceiver(context.Background(), creationParams, cfg, nil)
+	assert.NoError(t, err)
+	require.NotNil(t, mReceiver)
+	assert.Equal(t, "demo", mReceiver.(*pReceiver).cfg.PrometheusConfig.ScrapeConfigs[0].JobName)
+	assert.Nil(t, cfg.PrometheusConfig)
+
+	cfg.ConfigFile = path.Join(".", "testdata", "does-not-exist.yaml")
+	mReceiver, err = createMetricsReceiver(context.Background(), creationParams, cfg, nil)
+	assert.Error(t, err)
+	assert.Nil(t, mReceiver)
+}
+
+func TestLoadConfigFailsWithConfigAndConfigFile(t *testing.T) {
+	factories, err := componenttest.NopFactories()
+	assert.NoError(t, err)
+
+	factory := NewFactory()
+	factories.Receivers[typeStr] = factory
+	_, err = configtest.LoadConfigFile(t, path.Join(".", "testdata", "invalid-config-and-config-file.yaml"), factories)
+
+	assert.Error(t, err)
+}
+
+func TestCreateReceiverInvalidStaleness(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.ReportStaleness = "unknown"
+
+	creationParams := component.ReceiverCreateParams{Logger: zap.NewNop()}
+	mReceiver, err := createMetricsReceiver(context.Background(), creationParams, cfg, nil)
+	assert.Error(t, err)
+	assert.Nil(t, mReceiver)
+}
+
+func TestCreateReceiverInvalidResourceAttributesFromLabels(t *testing.T) {
+	tests := []struct {
+		name string
+		ra   []ResourceAttributesFromLabels
+	}{
+		{name: "no job name", ra: []ResourceAttributesFromLabels{{Labels: []string{"namespace"}}}},
+		{name: "no labels", ra: []ResourceAttributesFromLabels{{JobName: "demo"}}},
+		{name: "duplicate job", ra: []ResourceAttributesFromLabels{
+			{JobName: "demo", Labels: []string{"namespace"}},
+			{JobName: "demo", Labels: []string{"pod"}},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.ResourceAttributesFromLabels = tt.ra
+
+			creationParams := component.ReceiverCreateParams{Logger: zap.NewNop()}
+			mReceiver, err := createMetricsReceiver(context.Background(), creationParams, cfg, nil)
+			assert.Error(t, err)
+			assert.Nil(t, mReceiver)
+		})
+	}
+}
+
+func TestCreateReceiverInvalidTargetFilter(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.TargetDenylist = []TargetFilter{{Label: "__meta_kubernetes_namespace", Regex: "("}}
+
+	creationParams := component.ReceiverCreateParams{Logger: zap.NewNop()}
+	mReceiver, err := createMetricsReceiver(context.Background(), creationParams, cfg, nil)
+	assert.Error(t, err)
+	assert.Nil(t, mReceiver)
+
+	cfg.TargetDenylist = []TargetFilter{{Label: "not-a-label", Regex: ".*"}}
+	mReceiver, err = createMetricsReceiver(context.Background(), creationParams, cfg, nil)
+	assert.Error(t, err)
+	assert.Nil(t, mReceiver)
+}
+
+func TestCreateReceiverInvalidSharding(t *testing.T) {
+	tests := []struct {
+		name     string
+		sharding ShardingConfig
+	}{
+		{name: "no total shards", sharding: ShardingConfig{}},
+		{name: "index out of range", sharding: ShardingConfig{ShardIndex: 2, TotalShards: 2}},
+		{name: "invalid hash label", sharding: ShardingConfig{TotalShards: 2, HashLabels: []string{"not-a-label"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.Sharding = &tt.sharding
+
+			creationParams := component.ReceiverCreateParams{Logger: zap.NewNop()}
+			mReceiver, err := createMetricsReceiver(context.Background(), creationParams, cfg, nil)
+			assert.Error(t, err)
+			assert.Nil(t, mReceiver)
+		})
+	}
+}
+
+func TestCreateReceiverNegativeMaxTimeseriesPerBatch(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.MaxTimeseriesPerBatch = -1
+
+	creationParams := component.ReceiverCreateParams{Logger: zap.NewNop()}
+	mReceiver, err := createMetricsReceiver(context.Background(), creationParams, cfg, nil)
+	assert.Equal(t, errNegativeMaxTimeseriesPerBatch, err)
+	assert.Nil(t, mReceiver)
+}
+
+func TestCreateReceiverInvalidSeriesLimits(t *testing.T) {
+	tests := []struct {
+		name   string
+		limits []SeriesLimitsConfig
+	}{
+		{name: "no job name", limits: []SeriesLimitsConfig{{MaxSeriesPerTarget: 10}}},
+		{name: "negative limit", limits: []SeriesLimitsConfig{{JobName: "demo", MaxLabelValueLength: -1}}},
+		{name: "duplicate job", limits: []SeriesLimitsConfig{
+			{JobName: "demo", MaxSeriesPerTarget: 10},
+			{JobName: "demo", MaxLabelsPerMetric: 10},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.SeriesLimits = tt.limits
+
+			creationParams := component.ReceiverCreateParams{Logger: zap.NewNop()}
+			mReceiver, err := createMetricsReceiver(context.Background(), creationParams, cfg, nil)
+			assert.Error(t, err)
+			assert.Nil(t, mReceiver)
+		})
+	}
+}
+
+func TestCreateReceiverPushOnly(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Push = &PushConfig{HTTPServerSettings: confighttp.HTTPServerSettings{Endpoint: "localhost:9091"}}
+
+	creationParams := component.ReceiverCreateParams{Logger: zap.NewNop()}
+	mReceiver, err := createMetricsReceiver(context.Background(), creationParams, cfg, nil)
+	assert.NoError(t, err)
+	assert.NotNil(t, mReceiver)
+}
+
+func TestCreateReceiverInvalidPush(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Push = &PushConfig{}
+
+	creationParams := component.ReceiverCreateParams{Logger: zap.NewNop()}
+	mReceiver, err := createMetricsReceiver(context.Background(), creationParams, cfg, nil)
+	assert.Equal(t, errNoPushEndpoint, err)
+	assert.Nil(t, mReceiver)
+}
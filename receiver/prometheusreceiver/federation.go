@@ -0,0 +1,148 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheusreceiver
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	config_util "github.com/prometheus/common/config"
+	"github.com/prometheus/common/model"
+	promconfig "github.com/prometheus/prometheus/config"
+	"github.com/prometheus/prometheus/discovery"
+	"github.com/prometheus/prometheus/discovery/targetgroup"
+
+	"go.opentelemetry.io/collector/config/configtls"
+)
+
+const (
+	// federationJobName is the job name given to the scrape config generated for the federation endpoint.
+	federationJobName = "federate"
+	federationPath    = "/federate"
+)
+
+var (
+	errNoFederationEndpoint = errors.New("federation requires an \"endpoint\"")
+	errNoFederationMatch    = errors.New("federation requires at least one \"match\" selector")
+)
+
+// FederationConfig defines how the receiver reads series from the federation endpoint of
+// an existing Prometheus server instead of scraping the application targets directly.
+type FederationConfig struct {
+	// Endpoint is the base URL of the Prometheus server, e.g. "http://prometheus:9090".
+	Endpoint string `mapstructure:"endpoint"`
+	// Interval is how often the federation endpoint is read. Defaults to the
+	// Prometheus global scrape interval.
+	Interval time.Duration `mapstructure:"interval"`
+	// Timeout is the timeout of a single read. Defaults to the Prometheus global
+	// scrape timeout, capped at Interval.
+	Timeout time.Duration `mapstructure:"timeout"`
+	// Match holds the series selectors, e.g. `{job="node"}`, sent as "match[]" parameters.
+	Match []string `mapstructure:"match"`
+	// TLSSetting configures the connection to a Prometheus server served over https.
+	TLSSetting *configtls.TLSClientSetting `mapstructure:"tls"`
+}
+
+// scrapeConfig translates the federation settings to a Prometheus scrape config reading
+// the "/federate" endpoint of the configured server. The labels of the federated series
+// are not honored, so their original "job" and "instance" labels are kept as
+// "exported_job" and "exported_instance".
+func (fc *FederationConfig) scrapeConfig(global promconfig.GlobalConfig) (*promconfig.ScrapeConfig, error) {
+	if fc.Endpoint == "" {
+		return nil, errNoFederationEndpoint
+	}
+	if len(fc.Match) == 0 {
+		return nil, errNoFederationMatch
+	}
+	endpoint, err := url.Parse(fc.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid federation endpoint %q: %w", fc.Endpoint, err)
+	}
+	if endpoint.Scheme != "http" && endpoint.Scheme != "https" {
+		return nil, fmt.Errorf("invalid federation endpoint %q: scheme must be http or https", fc.Endpoint)
+	}
+	if endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid federation endpoint %q: missing host", fc.Endpoint)
+	}
+
+	sc := promconfig.DefaultScrapeConfig
+	sc.JobName = federationJobName
+	sc.HonorLabels = false
+	sc.Scheme = endpoint.Scheme
+	sc.MetricsPath = endpoint.Path + federationPath
+	sc.Params = url.Values{"match[]": fc.Match}
+
+	sc.ScrapeInterval = global.ScrapeInterval
+	if fc.Interval > 0 {
+		sc.ScrapeInterval = model.Duration(fc.Interval)
+	}
+	sc.ScrapeTimeout = global.ScrapeTimeout
+	if fc.Timeout > 0 {
+		sc.ScrapeTimeout = model.Duration(fc.Timeout)
+	}
+	if sc.ScrapeTimeout > sc.ScrapeInterval {
+		sc.ScrapeTimeout = sc.ScrapeInterval
+	}
+
+	if fc.TLSSetting != nil {
+		sc.HTTPClientConfig.TLSConfig = config_util.TLSConfig{
+			CAFile:             fc.TLSSetting.CAFile,
+			CertFile:           fc.TLSSetting.CertFile,
+			KeyFile:            fc.TLSSetting.KeyFile,
+			ServerName:         fc.TLSSetting.ServerName,
+			InsecureSkipVerify: fc.TLSSetting.InsecureSkipVerify,
+		}
+	}
+	if err := sc.HTTPClientConfig.Validate(); err != nil {
+		return nil, err
+	}
+
+	sc.ServiceDiscoveryConfigs = discovery.Configs{
+		discovery.StaticConfig{
+			&targetgroup.Group{
+				Targets: []model.LabelSet{{model.AddressLabel: model.LabelValue(endpoint.Host)}},
+				Source:  federationJobName,
+			},
+		},
+	}
+	return &sc, nil
+}
+
+// withFederation returns the Prometheus configuration to run for the receiver: the given
+// configuration, which may be nil, extended with the federation scrape config when
+// federation is enabled.
+func (cfg *Config) withFederation(promCfg *promconfig.Config) (*promconfig.Config, error) {
+	if cfg.Federation == nil {
+		return promCfg, nil
+	}
+	if promCfg == nil {
+		promCfg = &promconfig.Config{GlobalConfig: promconfig.DefaultGlobalConfig}
+	}
+	for _, sc := range promCfg.ScrapeConfigs {
+		if sc.JobName == federationJobName {
+			return nil, fmt.Errorf("scrape job name %q is reserved when federation is enabled", federationJobName)
+		}
+	}
+	sc, err := cfg.Federation.scrapeConfig(promCfg.GlobalConfig)
+	if err != nil {
+		return nil, err
+	}
+	// copy so that the configuration the receiver was created with is never modified.
+	extended := *promCfg
+	extended.ScrapeConfigs = append(append([]*promconfig.ScrapeConfig{}, promCfg.ScrapeConfigs...), sc)
+	return &extended, nil
+}
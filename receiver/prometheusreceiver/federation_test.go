@@ -0,0 +1,173 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheusreceiver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	promconfig "github.com/prometheus/prometheus/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/translator/internaldata"
+)
+
+func TestFederationScrapeConfig(t *testing.T) {
+	fc := &FederationConfig{
+		Endpoint: "https://prometheus:9090/prom",
+		Interval: 30 * time.Second,
+		Timeout:  time.Minute,
+		Match:    []string{`{job="node"}`, `up`},
+		TLSSetting: &configtls.TLSClientSetting{
+			TLSSetting: configtls.TLSSetting{CAFile: "ca.crt"},
+			Insecure:   true,
+			ServerName: "prometheus.local",
+		},
+	}
+	sc, err := fc.scrapeConfig(promconfig.DefaultGlobalConfig)
+	require.NoError(t, err)
+
+	assert.Equal(t, federationJobName, sc.JobName)
+	assert.False(t, sc.HonorLabels)
+	assert.Equal(t, "https", sc.Scheme)
+	assert.Equal(t, "/prom/federate", sc.MetricsPath)
+	assert.Equal(t, url.Values{"match[]": fc.Match}, sc.Params)
+	assert.Equal(t, model.Duration(30*time.Second), sc.ScrapeInterval)
+	// the timeout is capped at the interval.
+	assert.Equal(t, model.Duration(30*time.Second), sc.ScrapeTimeout)
+	assert.Equal(t, "ca.crt", sc.HTTPClientConfig.TLSConfig.CAFile)
+	assert.Equal(t, "prometheus.local", sc.HTTPClientConfig.TLSConfig.ServerName)
+	// insecure only applies to gRPC connections, the certificate is still verified.
+	assert.False(t, sc.HTTPClientConfig.TLSConfig.InsecureSkipVerify)
+	require.Len(t, sc.ServiceDiscoveryConfigs, 1)
+}
+
+func TestFederationScrapeConfigDefaults(t *testing.T) {
+	fc := &FederationConfig{Endpoint: "http://prometheus:9090", Match: []string{"up"}}
+	sc, err := fc.scrapeConfig(promconfig.DefaultGlobalConfig)
+	require.NoError(t, err)
+	assert.Equal(t, promconfig.DefaultGlobalConfig.ScrapeInterval, sc.ScrapeInterval)
+	assert.Equal(t, promconfig.DefaultGlobalConfig.ScrapeTimeout, sc.ScrapeTimeout)
+	assert.Equal(t, federationPath, sc.MetricsPath)
+}
+
+func TestFederationScrapeConfigInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		fc   FederationConfig
+	}{
+		{name: "no endpoint", fc: FederationConfig{Match: []string{"up"}}},
+		{name: "no match", fc: FederationConfig{Endpoint: "http://prometheus:9090"}},
+		{name: "bad scheme", fc: FederationConfig{Endpoint: "ftp://prometheus:9090", Match: []string{"up"}}},
+		{name: "no host", fc: FederationConfig{Endpoint: "http://", Match: []string{"up"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.fc.scrapeConfig(promconfig.DefaultGlobalConfig)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestWithFederation(t *testing.T) {
+	cfg := &Config{}
+	promCfg, err := cfg.withFederation(nil)
+	require.NoError(t, err)
+	assert.Nil(t, promCfg)
+
+	cfg.Federation = &FederationConfig{Endpoint: "http://prometheus:9090", Match: []string{"up"}}
+	promCfg, err = cfg.withFederation(nil)
+	require.NoError(t, err)
+	require.Len(t, promCfg.ScrapeConfigs, 1)
+
+	base := &promconfig.Config{
+		GlobalConfig:  promconfig.DefaultGlobalConfig,
+		ScrapeConfigs: []*promconfig.ScrapeConfig{{JobName: "app"}},
+	}
+	promCfg, err = cfg.withFederation(base)
+	require.NoError(t, err)
+	require.Len(t, promCfg.ScrapeConfigs, 2)
+	assert.Len(t, base.ScrapeConfigs, 1, "the base configuration must not be modified")
+
+	base.ScrapeConfigs[0].JobName = federationJobName
+	_, err = cfg.withFederation(base)
+	assert.Error(t, err)
+}
+
+const federatePage = `
+# TYPE http_requests_total counter
+http_requests_total{job="app",instance="app:8080",code="200"} 100 1614064712000
+`
+
+func TestFederationEndToEnd(t *testing.T) {
+	var once sync.Once
+	scraped := make(chan url.Values)
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != federationPath {
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = rw.Write([]byte(federatePage))
+		once.Do(func() { scraped <- req.URL.Query() })
+	}))
+	defer srv.Close()
+
+	cfg := &Config{
+		Federation: &FederationConfig{
+			Endpoint: srv.URL,
+			Interval: 100 * time.Millisecond,
+			Match:    []string{`{job="app"}`},
+		},
+	}
+	cms := new(consumertest.MetricsSink)
+	rcvr := newPrometheusReceiver(logger, cfg, cms)
+	require.NoError(t, rcvr.Start(context.Background(), componenttest.NewNopHost()))
+	defer rcvr.Shutdown(context.Background())
+
+	select {
+	case query := <-scraped:
+		assert.Equal(t, []string{`{job="app"}`}, query["match[]"])
+	case <-time.After(10 * time.Second):
+		t.Fatal("the federation endpoint was never scraped")
+	}
+
+	require.Eventually(t, func() bool { return cms.MetricsCount() > 0 }, 10*time.Second, 10*time.Millisecond)
+	ocmd := internaldata.MetricsToOC(cms.AllMetrics()[0])[0]
+	assert.Equal(t, federationJobName, ocmd.Node.ServiceInfo.Name)
+
+	var found bool
+	for _, m := range ocmd.Metrics {
+		if m.MetricDescriptor.Name != "http_requests_total" {
+			continue
+		}
+		found = true
+		var keys []string
+		for _, k := range m.MetricDescriptor.LabelKeys {
+			keys = append(keys, k.Key)
+		}
+		assert.ElementsMatch(t, []string{"code", "exported_instance", "exported_job"}, keys)
+	}
+	assert.True(t, found)
+}
@@ -0,0 +1,96 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheusreceiver
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	promconfig "github.com/prometheus/prometheus/config"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/translator/internaldata"
+)
+
+// writeTargetsFile atomically replaces the targets file, as recommended for file_sd_configs.
+func writeTargetsFile(t *testing.T, path string, target string) {
+	tmp := path + ".tmp"
+	require.NoError(t, ioutil.WriteFile(tmp, []byte(fmt.Sprintf("- targets: [%q]\n", target)), 0600))
+	require.NoError(t, os.Rename(tmp, path))
+}
+
+func newFileSDTarget(t *testing.T) string {
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		_, _ = rw.Write([]byte("# TYPE file_sd_gauge gauge\nfile_sd_gauge 1\n"))
+	}))
+	t.Cleanup(srv.Close)
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+	return u.Host
+}
+
+func scrapedInstance(cms *consumertest.MetricsSink, instance string) func() bool {
+	return func() bool {
+		for _, md := range cms.AllMetrics() {
+			for _, ocmd := range internaldata.MetricsToOC(md) {
+				if ocmd.Node.Identifier.HostName+":"+ocmd.Resource.Labels["port"] == instance {
+					return true
+				}
+			}
+		}
+		return false
+	}
+}
+
+func TestFileSDTargetsRefreshOnChange(t *testing.T) {
+	first, second := newFileSDTarget(t), newFileSDTarget(t)
+	dir, err := ioutil.TempDir("", "file_sd")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	targetsFile := filepath.Join(dir, "targets.yaml")
+	writeTargetsFile(t, targetsFile, first)
+
+	// the refresh interval is long enough that only watching the file can explain the
+	// targets being updated while the test runs.
+	promCfg, err := promconfig.Load(fmt.Sprintf(`
+scrape_configs:
+  - job_name: file_sd
+    scrape_interval: 100ms
+    file_sd_configs:
+      - files: [%q]
+        refresh_interval: 1h
+`, targetsFile))
+	require.NoError(t, err)
+
+	cms := new(consumertest.MetricsSink)
+	rcvr := newPrometheusReceiver(logger, &Config{PrometheusConfig: promCfg}, cms)
+	require.NoError(t, rcvr.Start(context.Background(), componenttest.NewNopHost()))
+	defer rcvr.Shutdown(context.Background())
+
+	require.Eventually(t, scrapedInstance(cms, first), 20*time.Second, 50*time.Millisecond)
+
+	writeTargetsFile(t, targetsFile, second)
+	require.Eventually(t, scrapedInstance(cms, second), 20*time.Second, 50*time.Millisecond)
+}
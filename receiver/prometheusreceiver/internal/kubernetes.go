@@ -0,0 +1,77 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+	"github.com/prometheus/prometheus/pkg/labels"
+
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+// Meta labels attached to targets by the kubernetes service discovery.
+const (
+	kubernetesMetaNamespace         = "__meta_kubernetes_namespace"
+	kubernetesMetaPodName           = "__meta_kubernetes_pod_name"
+	kubernetesMetaPodUID            = "__meta_kubernetes_pod_uid"
+	kubernetesMetaPodNodeName       = "__meta_kubernetes_pod_node_name"
+	kubernetesMetaPodContainerName  = "__meta_kubernetes_pod_container_name"
+	kubernetesMetaPodControllerKind = "__meta_kubernetes_pod_controller_kind"
+	kubernetesMetaPodControllerName = "__meta_kubernetes_pod_controller_name"
+	kubernetesMetaNodeName          = "__meta_kubernetes_node_name"
+	kubernetesMetaEndpointNodeName  = "__meta_kubernetes_endpoint_node_name"
+)
+
+// kubernetesMetaToResource maps the meta labels set by the kubernetes service discovery
+// to the corresponding resource semantic conventions.
+var kubernetesMetaToResource = []struct {
+	meta      string
+	attribute string
+}{
+	{meta: kubernetesMetaNamespace, attribute: conventions.AttributeK8sNamespace},
+	{meta: kubernetesMetaPodName, attribute: conventions.AttributeK8sPod},
+	{meta: kubernetesMetaPodUID, attribute: conventions.AttributeK8sPodUID},
+	{meta: kubernetesMetaPodContainerName, attribute: conventions.AttributeK8sContainer},
+	{meta: kubernetesMetaNodeName, attribute: conventions.AttributeK8sNodeName},
+	{meta: kubernetesMetaEndpointNodeName, attribute: conventions.AttributeK8sNodeName},
+	{meta: kubernetesMetaPodNodeName, attribute: conventions.AttributeK8sNodeName},
+}
+
+// kubernetesControllerKindToResource maps the kind of the controller owning a pod to
+// the resource attribute holding the controller name.
+var kubernetesControllerKindToResource = map[string]string{
+	"ReplicaSet":  conventions.AttributeK8sReplicaSet,
+	"DaemonSet":   conventions.AttributeK8sDaemonSet,
+	"StatefulSet": conventions.AttributeK8sStatefulSet,
+	"Job":         conventions.AttributeK8sJob,
+	"CronJob":     conventions.AttributeK8sCronJob,
+}
+
+// addKubernetesResourceLabels adds the kubernetes metadata discovered for a target, if
+// any, to the resource labels. Targets that were not discovered through
+// kubernetes_sd_configs have no such metadata and their resource is left untouched.
+func addKubernetesResourceLabels(resource *resourcepb.Resource, discoveredLabels labels.Labels) {
+	for _, m := range kubernetesMetaToResource {
+		if v := discoveredLabels.Get(m.meta); v != "" {
+			resource.Labels[m.attribute] = v
+		}
+	}
+
+	kind := discoveredLabels.Get(kubernetesMetaPodControllerKind)
+	name := discoveredLabels.Get(kubernetesMetaPodControllerName)
+	if attribute, ok := kubernetesControllerKindToResource[kind]; ok && name != "" {
+		resource.Labels[attribute] = name
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"testing"
+
+	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddKubernetesResourceLabels(t *testing.T) {
+	tests := []struct {
+		name             string
+		discoveredLabels labels.Labels
+		want             map[string]string
+	}{
+		{
+			name:             "static target",
+			discoveredLabels: labels.FromStrings("__address__", "localhost:8080", "__scheme__", "http"),
+			want:             map[string]string{"port": "8080"},
+		},
+		{
+			name: "pod role",
+			discoveredLabels: labels.FromStrings(
+				"__address__", "10.0.0.1:8080",
+				kubernetesMetaNamespace, "default",
+				kubernetesMetaPodName, "my-app-5d8f9c-abcde",
+				kubernetesMetaPodUID, "c5a1b1f2-0000-0000-0000-000000000000",
+				kubernetesMetaPodContainerName, "app",
+				kubernetesMetaPodNodeName, "node-1",
+				kubernetesMetaPodControllerKind, "ReplicaSet",
+				kubernetesMetaPodControllerName, "my-app-5d8f9c",
+			),
+			want: map[string]string{
+				"port":                "8080",
+				"k8s.namespace.name":  "default",
+				"k8s.pod.name":        "my-app-5d8f9c-abcde",
+				"k8s.pod.uid":         "c5a1b1f2-0000-0000-0000-000000000000",
+				"k8s.container.name":  "app",
+				"k8s.node.name":       "node-1",
+				"k8s.replicaset.name": "my-app-5d8f9c",
+			},
+		},
+		{
+			name: "node role",
+			discoveredLabels: labels.FromStrings(
+				"__address__", "10.0.0.2:10250",
+				kubernetesMetaNodeName, "node-2",
+			),
+			want: map[string]string{
+				"port":          "8080",
+				"k8s.node.name": "node-2",
+			},
+		},
+		{
+			name: "unknown controller kind",
+			discoveredLabels: labels.FromStrings(
+				kubernetesMetaPodControllerKind, "Rollout",
+				kubernetesMetaPodControllerName, "my-rollout",
+			),
+			want: map[string]string{"port": "8080"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resource := &resourcepb.Resource{Labels: map[string]string{"port": "8080"}}
+			addKubernetesResourceLabels(resource, tt.discoveredLabels)
+			assert.Equal(t, tt.want, resource.Labels)
+		})
+	}
+}
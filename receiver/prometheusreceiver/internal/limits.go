@@ -0,0 +1,141 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"errors"
+	"unicode/utf8"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/pkg/labels"
+)
+
+var errSeriesLimitExceeded = errors.New("series dropped by the series limits of the job")
+
+// SeriesLimits bounds the labels and the number of the series a target of a job exposes.
+// A zero value disables the matching limit.
+type SeriesLimits struct {
+	// MaxLabelsPerMetric is the maximum number of labels of a series, not counting the
+	// __name__, job, instance, le and quantile labels. Series with more labels are dropped.
+	MaxLabelsPerMetric int
+	// MaxLabelValueLength is the maximum length, in bytes, of a label value. Longer values
+	// are truncated.
+	MaxLabelValueLength int
+	// MaxSeriesPerTarget is the maximum number of series of a scrape. The series exceeding
+	// it, in the order of the scraped page, are dropped. The buckets, count and sum of a
+	// histogram or a summary are a single series.
+	MaxSeriesPerTarget int
+}
+
+// seriesLimiter enforces SeriesLimits on the samples of a single scrape.
+type seriesLimiter struct {
+	limits    *SeriesLimits
+	series    map[string]bool
+	dropped   map[string]bool
+	truncated int
+}
+
+func newSeriesLimiter(limits *SeriesLimits) *seriesLimiter {
+	if limits == nil {
+		return nil
+	}
+	return &seriesLimiter{
+		limits:  limits,
+		series:  make(map[string]bool),
+		dropped: make(map[string]bool),
+	}
+}
+
+// apply returns the labels of the sample once its values are truncated, and false if the
+// sample must be dropped.
+func (l *seriesLimiter) apply(ls labels.Labels) (labels.Labels, bool) {
+	if max := l.limits.MaxLabelValueLength; max > 0 {
+		ls = truncateLabelValues(ls, max, &l.truncated)
+	}
+
+	key := seriesKey(ls)
+	if l.series[key] {
+		return ls, true
+	}
+	if l.dropped[key] {
+		return ls, false
+	}
+	if max := l.limits.MaxLabelsPerMetric; max > 0 && countSeriesLabels(ls) > max {
+		l.dropped[key] = true
+		return ls, false
+	}
+	if max := l.limits.MaxSeriesPerTarget; max > 0 && len(l.series) >= max {
+		l.dropped[key] = true
+		return ls, false
+	}
+	l.series[key] = true
+	return ls, true
+}
+
+// numDropped returns the number of distinct series dropped so far.
+func (l *seriesLimiter) numDropped() int {
+	return len(l.dropped)
+}
+
+// seriesKey identifies the series a sample belongs to, the samples of the buckets, count
+// and sum of a histogram or a summary share the same key.
+func seriesKey(ls labels.Labels) string {
+	b := labels.NewBuilder(ls)
+	b.Set(model.MetricNameLabel, normalizeMetricName(ls.Get(model.MetricNameLabel)))
+	b.Del(model.BucketLabel, model.QuantileLabel)
+	return b.Labels().String()
+}
+
+func countSeriesLabels(ls labels.Labels) int {
+	n := 0
+	for _, l := range ls {
+		switch l.Name {
+		case model.MetricNameLabel, model.JobLabel, model.InstanceLabel, model.BucketLabel, model.QuantileLabel:
+		default:
+			n++
+		}
+	}
+	return n
+}
+
+// truncateLabelValues truncates the values longer than max bytes, without splitting a
+// UTF-8 encoded character, and increments truncated for every truncated value. The
+// metric name and the job and instance labels identifying the target are never truncated.
+func truncateLabelValues(ls labels.Labels, max int, truncated *int) labels.Labels {
+	var out labels.Labels
+	for i, l := range ls {
+		if len(l.Value) <= max {
+			continue
+		}
+		switch l.Name {
+		case model.MetricNameLabel, model.JobLabel, model.InstanceLabel:
+			continue
+		}
+		if out == nil {
+			out = make(labels.Labels, len(ls))
+			copy(out, ls)
+		}
+		n := max
+		for n > 0 && !utf8.RuneStart(l.Value[n]) {
+			n--
+		}
+		out[i].Value = l.Value[:n]
+		*truncated++
+	}
+	if out == nil {
+		return ls
+	}
+	return out
+}
@@ -0,0 +1,141 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/obsreport"
+	"go.opentelemetry.io/collector/obsreport/obsreporttest"
+	"go.opentelemetry.io/collector/translator/internaldata"
+)
+
+func TestSeriesLimiterMaxSeries(t *testing.T) {
+	l := newSeriesLimiter(&SeriesLimits{MaxSeriesPerTarget: 2})
+
+	samples := []labels.Labels{
+		labels.FromStrings(model.MetricNameLabel, "hist_test_bucket", "le", "10"),
+		labels.FromStrings(model.MetricNameLabel, "hist_test_bucket", "le", "+Inf"),
+		labels.FromStrings(model.MetricNameLabel, "hist_test_sum"),
+		labels.FromStrings(model.MetricNameLabel, "hist_test_count"),
+		labels.FromStrings(model.MetricNameLabel, "counter_test", "foo", "a"),
+		labels.FromStrings(model.MetricNameLabel, "counter_test", "foo", "b"),
+		labels.FromStrings(model.MetricNameLabel, "counter_test", "foo", "c"),
+	}
+	var kept []bool
+	for _, ls := range samples {
+		_, ok := l.apply(ls)
+		kept = append(kept, ok)
+	}
+	// all the samples of the histogram belong to a single series.
+	assert.Equal(t, []bool{true, true, true, true, true, false, false}, kept)
+	assert.Equal(t, 2, l.numDropped())
+
+	// a dropped series stays dropped, a kept one stays kept.
+	_, ok := l.apply(labels.FromStrings(model.MetricNameLabel, "counter_test", "foo", "b"))
+	assert.False(t, ok)
+	_, ok = l.apply(labels.FromStrings(model.MetricNameLabel, "counter_test", "foo", "a"))
+	assert.True(t, ok)
+	assert.Equal(t, 2, l.numDropped())
+}
+
+func TestSeriesLimiterMaxLabels(t *testing.T) {
+	l := newSeriesLimiter(&SeriesLimits{MaxLabelsPerMetric: 2})
+
+	ls := labels.FromStrings(model.MetricNameLabel, "hist_test_bucket", model.JobLabel, "job", model.InstanceLabel, "instance", "a", "1", "b", "2", "le", "10")
+	_, ok := l.apply(ls)
+	assert.True(t, ok)
+
+	ls = labels.FromStrings(model.MetricNameLabel, "counter_test", "a", "1", "b", "2", "c", "3")
+	_, ok = l.apply(ls)
+	assert.False(t, ok)
+	assert.Equal(t, 1, l.numDropped())
+}
+
+func TestSeriesLimiterMaxLabelValueLength(t *testing.T) {
+	l := newSeriesLimiter(&SeriesLimits{MaxLabelValueLength: 4})
+
+	ls := labels.FromStrings(model.MetricNameLabel, "a_long_metric_name", model.JobLabel, "a_long_job", model.InstanceLabel, "localhost:8080", "ascii", "abcdefgh", "short", "abc", "utf8", "aéééé")
+	got, ok := l.apply(ls)
+	require.True(t, ok)
+	assert.Equal(t, labels.FromStrings(model.MetricNameLabel, "a_long_metric_name", model.JobLabel, "a_long_job", model.InstanceLabel, "localhost:8080", "ascii", "abcd", "short", "abc", "utf8", "aé"), got)
+	assert.Equal(t, 2, l.truncated)
+	// the labels of the sample are not modified.
+	assert.Equal(t, "abcdefgh", ls.Get("ascii"))
+}
+
+func TestNewSeriesLimiterWithoutLimits(t *testing.T) {
+	assert.Nil(t, newSeriesLimiter(nil))
+}
+
+func TestTransactionSeriesLimits(t *testing.T) {
+	doneFn, err := obsreporttest.SetupRecordedMetricsTest()
+	require.NoError(t, err)
+	defer doneFn()
+	views := MetricViews()
+	require.NoError(t, view.Register(views...))
+	defer view.Unregister(views...)
+
+	limits := map[string]*SeriesLimits{
+		"test": {MaxLabelsPerMetric: 2, MaxLabelValueLength: 3, MaxSeriesPerTarget: 2},
+	}
+	sink := new(consumertest.MetricsSink)
+	ctx := obsreport.ReceiverContext(context.Background(), "prometheus", transport)
+	tr := newTransaction(ctx, NewJobsMap(time.Minute), false, "", "prometheus", StalenessDrop, nil, 0, limits, newStalenessTestMetadataService(), sink, testLogger)
+
+	target := []string{model.InstanceLabel, "localhost:8080", model.JobLabel, "test"}
+	samples := []labels.Labels{
+		labels.FromStrings(append(target, model.MetricNameLabel, "gauge_test", "foo", "a")...),
+		labels.FromStrings(append(target, model.MetricNameLabel, "gauge_test", "foo", "abcdef")...),
+		// exceeds max_series_per_target
+		labels.FromStrings(append(target, model.MetricNameLabel, "gauge_test", "foo", "b")...),
+		// exceeds max_labels_per_metric
+		labels.FromStrings(append(target, model.MetricNameLabel, "gauge_test2", "a", "1", "b", "2", "c", "3")...),
+		// internal metrics are never limited
+		labels.FromStrings(append(target, model.MetricNameLabel, "up")...),
+	}
+	for _, ls := range samples {
+		_, err = tr.Add(ls, startTs, 1)
+		require.NoError(t, err)
+	}
+	require.NoError(t, tr.Commit())
+
+	require.Len(t, sink.AllMetrics(), 1)
+	ocmds := internaldata.MetricsToOC(sink.AllMetrics()[0])
+	require.Len(t, ocmds, 1)
+	require.Len(t, ocmds[0].Metrics, 1)
+	var values []string
+	for _, ts := range ocmds[0].Metrics[0].Timeseries {
+		values = append(values, ts.LabelValues[0].Value)
+	}
+	assert.Equal(t, []string{"a", "abc"}, values)
+
+	obsreporttest.CheckReceiverMetricsViews(t, "prometheus", transport, 2, 2)
+	rows, err := view.RetrieveData(statTruncatedLabelValues.Name())
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.ElementsMatch(t, []tag.Tag{{Key: tagReceiverName, Value: "prometheus"}, {Key: tagJob, Value: "test"}}, rows[0].Tags)
+	assert.Equal(t, 1.0, rows[0].Data.(*view.SumData).Value)
+}
@@ -21,6 +21,7 @@ import (
 	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
 	"github.com/prometheus/prometheus/pkg/labels"
 	"github.com/prometheus/prometheus/pkg/textparse"
+	"github.com/prometheus/prometheus/pkg/value"
 	"github.com/prometheus/prometheus/scrape"
 	"google.golang.org/protobuf/types/known/timestamppb"
 	"google.golang.org/protobuf/types/known/wrapperspb"
@@ -31,6 +32,9 @@ import (
 type MetricFamily interface {
 	Add(metricName string, ls labels.Labels, t int64, v float64) error
 	IsSameFamily(metricName string) bool
+	// CanSplit returns true if the family holds at least n timeseries and its next samples can
+	// be built as a separate metric, which is only the case when every sample is a timeseries.
+	CanSplit(n int) bool
 	ToMetric() (*metricspb.Metric, int, int)
 }
 
@@ -83,6 +87,16 @@ func (mf *metricFamily) IsSameFamily(metricName string) bool {
 	return mf.name == familyName || familyName != metricName && mf.name == metricName
 }
 
+func (mf *metricFamily) CanSplit(n int) bool {
+	switch mf.mtype {
+	case metricspb.MetricDescriptor_CUMULATIVE_DISTRIBUTION, metricspb.MetricDescriptor_SUMMARY:
+		// the samples of a timeseries are only complete once the whole family has been added.
+		return false
+	default:
+		return len(mf.groups) >= n
+	}
+}
+
 // updateLabelKeys is used to store all the label keys of a same metric family in observed order. since prometheus
 // receiver removes any label with empty value before feeding it to an appender, in order to figure out all the labels
 // from the same metric family we will need to keep track of what labels have ever been observed.
@@ -149,6 +163,10 @@ func (mf *metricFamily) getLabelKeys() []*metricspb.LabelKey {
 }
 
 func (mf *metricFamily) Add(metricName string, ls labels.Labels, t int64, v float64) error {
+	if value.IsStaleNaN(v) && mf.mtype != metricspb.MetricDescriptor_CUMULATIVE_DOUBLE && mf.mtype != metricspb.MetricDescriptor_GAUGE_DOUBLE {
+		// the staleness marker cannot be represented by the counts of histograms and summaries.
+		return nil
+	}
 	groupKey := mf.getGroupKey(ls)
 	mg := mf.loadMetricGroupOrCreate(groupKey, ls, t)
 	switch mf.mtype {
@@ -362,6 +380,14 @@ func (mg *metricGroup) toDoubleValueTimeSeries(orderedLabelKeys []string) *metri
 	}
 }
 
+// timeseriesSignature returns the signature used by the metrics adjuster for the timeseries the sample with the given
+// name and labels belongs to.
+func timeseriesSignature(metricName string, ls labels.Labels, mc MetadataCache) string {
+	mf := newMetricFamily(metricName, mc).(*metricFamily)
+	mf.updateLabelKeys(ls)
+	return getTimeseriesSignature(mf.name, populateLabelValues(mf.labelKeysOrdered, ls))
+}
+
 func populateLabelValues(orderedKeys []string, ls labels.Labels) []*metricspb.LabelValue {
 	lvs := make([]*metricspb.LabelValue, len(orderedKeys))
 	lmap := ls.Map()
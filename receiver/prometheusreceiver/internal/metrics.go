@@ -0,0 +1,231 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"context"
+	"fmt"
+
+	gokitLog "github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+)
+
+const (
+	scrapeDurationMetricName       = "scrape_duration_seconds"
+	scrapeSamplesScrapedMetricName = "scrape_samples_scraped"
+
+	scrapePoolKey = "scrape_pool"
+)
+
+// targetSyncErrorMessages are the messages logged by the scrape manager when it fails to
+// (re)create the targets of a scrape pool.
+var targetSyncErrorMessages = map[string]bool{
+	"creating targets failed":        true,
+	"error creating new scrape pool": true,
+	"error reloading scrape pool":    true,
+}
+
+// fileSDReadErrorMessage is the message logged by file based service discovery when it
+// fails to read or parse a target file.
+const fileSDReadErrorMessage = "Error reading file"
+
+var (
+	tagReceiverName, _ = tag.NewKey("receiver")
+	tagJob, _          = tag.NewKey("job")
+	tagStatus, _       = tag.NewKey("status")
+
+	statTargetScrapes        = stats.Int64("prometheus_receiver_target_scrapes", "Number of scrapes of the targets, by status", stats.UnitDimensionless)
+	statTargetScrapeDuration = stats.Float64("prometheus_receiver_target_scrape_duration_seconds", "Duration of the scrapes of the targets", stats.UnitSeconds)
+	statTargetSamples        = stats.Int64("prometheus_receiver_target_scrape_samples_scraped", "Number of samples exposed by the targets", stats.UnitDimensionless)
+	statTargetSyncErrors     = stats.Int64("prometheus_receiver_target_sync_errors", "Number of errors encountered while synchronizing the targets of a scrape pool", stats.UnitDimensionless)
+	statFileSDReadErrors     = stats.Int64("prometheus_receiver_file_sd_read_errors", "Number of times a target file of file_sd_configs failed to be read or parsed", stats.UnitDimensionless)
+	statTruncatedLabelValues = stats.Int64("prometheus_receiver_truncated_label_values", "Number of label values truncated by the series limits of the jobs", stats.UnitDimensionless)
+)
+
+const (
+	scrapeStatusSuccess = "success"
+	scrapeStatusFailure = "failure"
+)
+
+// MetricViews return metric views for the Prometheus receiver.
+func MetricViews() []*view.View {
+	// The views are aggregated by job and not by target, the targets come and go with the
+	// service discovery and the views cannot forget the rows of the removed targets.
+	jobTagKeys := []tag.Key{tagReceiverName, tagJob}
+
+	countTargetScrapes := &view.View{
+		Name:        statTargetScrapes.Name(),
+		Measure:     statTargetScrapes,
+		Description: statTargetScrapes.Description(),
+		TagKeys:     []tag.Key{tagReceiverName, tagJob, tagStatus},
+		Aggregation: view.Count(),
+	}
+
+	distributionTargetScrapeDuration := &view.View{
+		Name:        statTargetScrapeDuration.Name(),
+		Measure:     statTargetScrapeDuration,
+		Description: statTargetScrapeDuration.Description(),
+		TagKeys:     jobTagKeys,
+		Aggregation: view.Distribution(0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60),
+	}
+
+	sumTargetSamples := &view.View{
+		Name:        statTargetSamples.Name(),
+		Measure:     statTargetSamples,
+		Description: statTargetSamples.Description(),
+		TagKeys:     jobTagKeys,
+		Aggregation: view.Sum(),
+	}
+
+	countTargetSyncErrors := &view.View{
+		Name:        statTargetSyncErrors.Name(),
+		Measure:     statTargetSyncErrors,
+		Description: statTargetSyncErrors.Description(),
+		TagKeys:     jobTagKeys,
+		Aggregation: view.Sum(),
+	}
+
+	countFileSDReadErrors := &view.View{
+		Name:        statFileSDReadErrors.Name(),
+		Measure:     statFileSDReadErrors,
+		Description: statFileSDReadErrors.Description(),
+		TagKeys:     []tag.Key{tagReceiverName},
+		Aggregation: view.Sum(),
+	}
+
+	sumTruncatedLabelValues := &view.View{
+		Name:        statTruncatedLabelValues.Name(),
+		Measure:     statTruncatedLabelValues,
+		Description: statTruncatedLabelValues.Description(),
+		TagKeys:     jobTagKeys,
+		Aggregation: view.Sum(),
+	}
+
+	return []*view.View{
+		countTargetScrapes,
+		distributionTargetScrapeDuration,
+		sumTargetSamples,
+		countTargetSyncErrors,
+		countFileSDReadErrors,
+		sumTruncatedLabelValues,
+	}
+}
+
+// recordTruncatedLabelValues records the label values of a scrape of the job truncated by its
+// series limits.
+func recordTruncatedLabelValues(ctx context.Context, receiverName, job string, truncated int) {
+	_ = stats.RecordWithTags(
+		ctx,
+		[]tag.Mutator{tag.Insert(tagReceiverName, receiverName), tag.Insert(tagJob, job)},
+		statTruncatedLabelValues.M(int64(truncated)))
+}
+
+// recordScrapeReport records the report samples that the scrape loop appends after
+// every scrape of a target, successful or not.
+func recordScrapeReport(ctx context.Context, receiverName, job, metricName string, v float64) {
+	mutators := []tag.Mutator{tag.Insert(tagReceiverName, receiverName), tag.Insert(tagJob, job)}
+	var m stats.Measurement
+	switch metricName {
+	case scrapeUpMetricName:
+		status := scrapeStatusSuccess
+		if v == 0 {
+			status = scrapeStatusFailure
+		}
+		mutators = append(mutators, tag.Insert(tagStatus, status))
+		m = statTargetScrapes.M(1)
+	case scrapeDurationMetricName:
+		m = statTargetScrapeDuration.M(v)
+	case scrapeSamplesScrapedMetricName:
+		m = statTargetSamples.M(int64(v))
+	default:
+		return
+	}
+	_ = stats.RecordWithTags(ctx, mutators, m)
+}
+
+// NewTargetSyncErrorsLogger wraps the logger given to the scrape manager so that the
+// errors it reports while synchronizing the targets of a scrape pool are counted.
+// The scrape manager has no other way to surface these errors.
+func NewTargetSyncErrorsLogger(ctx context.Context, next gokitLog.Logger, receiverName string) gokitLog.Logger {
+	return &targetSyncErrorsLogger{ctx: ctx, next: next, receiverName: receiverName}
+}
+
+type targetSyncErrorsLogger struct {
+	ctx          context.Context
+	next         gokitLog.Logger
+	receiverName string
+}
+
+func (l *targetSyncErrorsLogger) Log(keyvals ...interface{}) error {
+	isError, isSyncError := false, false
+	scrapePool := ""
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		if lvl, ok := matchLogLevel(keyvals[i], keyvals[i+1]); ok {
+			isError = lvl == level.ErrorValue()
+		}
+		if msg, ok := matchLogMessage(keyvals[i], keyvals[i+1]); ok {
+			isSyncError = targetSyncErrorMessages[msg]
+		}
+		if key, ok := keyvals[i].(string); ok && key == scrapePoolKey {
+			scrapePool = fmt.Sprint(keyvals[i+1])
+		}
+	}
+	if isError && isSyncError && scrapePool != "" {
+		_ = stats.RecordWithTags(
+			l.ctx,
+			[]tag.Mutator{tag.Insert(tagReceiverName, l.receiverName), tag.Insert(tagJob, scrapePool)},
+			statTargetSyncErrors.M(1))
+	}
+	return l.next.Log(keyvals...)
+}
+
+var _ gokitLog.Logger = (*targetSyncErrorsLogger)(nil)
+
+// NewFileSDErrorsLogger wraps the logger given to the discovery manager so that the target
+// files of file_sd_configs that fail to be read or parsed are counted. The previously read
+// targets of such a file are kept until it is fixed.
+func NewFileSDErrorsLogger(ctx context.Context, next gokitLog.Logger, receiverName string) gokitLog.Logger {
+	return &fileSDErrorsLogger{ctx: ctx, next: next, receiverName: receiverName}
+}
+
+type fileSDErrorsLogger struct {
+	ctx          context.Context
+	next         gokitLog.Logger
+	receiverName string
+}
+
+func (l *fileSDErrorsLogger) Log(keyvals ...interface{}) error {
+	isError, isReadError := false, false
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		if lvl, ok := matchLogLevel(keyvals[i], keyvals[i+1]); ok {
+			isError = lvl == level.ErrorValue()
+		}
+		if msg, ok := matchLogMessage(keyvals[i], keyvals[i+1]); ok {
+			isReadError = msg == fileSDReadErrorMessage
+		}
+	}
+	if isError && isReadError {
+		_ = stats.RecordWithTags(
+			l.ctx,
+			[]tag.Mutator{tag.Insert(tagReceiverName, l.receiverName)},
+			statFileSDReadErrors.M(1))
+	}
+	return l.next.Log(keyvals...)
+}
+
+var _ gokitLog.Logger = (*fileSDErrorsLogger)(nil)
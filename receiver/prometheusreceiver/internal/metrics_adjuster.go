@@ -90,6 +90,13 @@ func (tsm *timeseriesMap) get(
 	return tsi
 }
 
+// Remove the timeseriesinfo of the timeseries with the given signature.
+func (tsm *timeseriesMap) remove(sig string) {
+	tsm.Lock()
+	defer tsm.Unlock()
+	delete(tsm.tsiMap, sig)
+}
+
 // Remove timeseries that have aged out.
 func (tsm *timeseriesMap) gc() {
 	tsm.Lock()
@@ -129,6 +136,8 @@ type JobsMap struct {
 	gcInterval time.Duration
 	lastGC     time.Time
 	jobsMap    map[string]*timeseriesMap
+	// scrapeTimestampJobs holds the jobs configured with honor_timestamps false.
+	scrapeTimestampJobs map[string]bool
 }
 
 // NewJobsMap creates a new (empty) JobsMap.
@@ -165,6 +174,28 @@ func (jm *JobsMap) maybeGC() {
 	}
 }
 
+// Remove the timeseriesMap of a job instance.
+func (jm *JobsMap) remove(job, instance string) {
+	jm.Lock()
+	defer jm.Unlock()
+	delete(jm.jobsMap, job+":"+instance)
+}
+
+// SetScrapeTimestampJobs sets the jobs configured with honor_timestamps false, the timestamps of
+// their samples are the scrape times instead of the ones exposed by the targets.
+func (jm *JobsMap) SetScrapeTimestampJobs(jobs map[string]bool) {
+	jm.Lock()
+	defer jm.Unlock()
+	jm.scrapeTimestampJobs = jobs
+}
+
+// usesScrapeTimestamps returns true if the timestamps of the samples of the job are the scrape times.
+func (jm *JobsMap) usesScrapeTimestamps(job string) bool {
+	jm.RLock()
+	defer jm.RUnlock()
+	return jm.scrapeTimestampJobs[job]
+}
+
 func (jm *JobsMap) get(job, instance string) *timeseriesMap {
 	sig := job + ":" + instance
 	jm.RLock()
@@ -189,15 +220,19 @@ func (jm *JobsMap) get(job, instance string) *timeseriesMap {
 // and provides AdjustMetrics, which takes a sequence of metrics and adjust their values based on
 // the initial points.
 type MetricsAdjuster struct {
-	tsm    *timeseriesMap
-	logger *zap.Logger
+	tsm              *timeseriesMap
+	scrapeTimestamps bool
+	logger           *zap.Logger
 }
 
-// NewMetricsAdjuster is a constructor for MetricsAdjuster.
-func NewMetricsAdjuster(tsm *timeseriesMap, logger *zap.Logger) *MetricsAdjuster {
+// NewMetricsAdjuster is a constructor for MetricsAdjuster. scrapeTimestamps is true when the
+// timestamps of the points are the scrape times, i.e. the job does not honor the timestamps
+// exposed by its targets.
+func NewMetricsAdjuster(tsm *timeseriesMap, scrapeTimestamps bool, logger *zap.Logger) *MetricsAdjuster {
 	return &MetricsAdjuster{
-		tsm:    tsm,
-		logger: logger,
+		tsm:              tsm,
+		scrapeTimestamps: scrapeTimestamps,
+		logger:           logger,
 	}
 }
 
@@ -248,6 +283,17 @@ func (ma *MetricsAdjuster) adjustMetricTimeseries(metric *metricspb.Metric) (boo
 	filtered := make([]*metricspb.TimeSeries, 0, len(metric.GetTimeseries()))
 	for _, current := range metric.GetTimeseries() {
 		tsi := ma.tsm.get(metric, current.GetLabelValues())
+		if isStalePoint(current.GetPoints()[0]) {
+			// staleness markers are forwarded as is, they must neither be compared to nor
+			// become the previous point.
+			if tsi.initial == nil {
+				dropped++
+			} else {
+				current.StartTimestamp = tsi.initial.StartTimestamp
+				filtered = append(filtered, current)
+			}
+			continue
+		}
 		if tsi.initial == nil {
 			// initial timeseries
 			tsi.initial = current
@@ -284,14 +330,29 @@ func (ma *MetricsAdjuster) adjustTimeseries(metricType metricspb.MetricDescripto
 
 func (ma *MetricsAdjuster) adjustPoints(metricType metricspb.MetricDescriptor_Type,
 	current, initial, previous []*metricspb.Point) bool {
-	if len(current) != 1 || len(initial) != 1 || len(current) != 1 {
+	if len(current) != 1 || len(initial) != 1 || len(previous) != 1 {
 		ma.logger.Info("Adjusting Points, all lengths should be 1",
 			zap.Int("len(current)", len(current)), zap.Int("len(initial)", len(initial)), zap.Int("len(previous)", len(previous)))
 		return true
 	}
+	if !ma.scrapeTimestamps && isOlder(current[0], previous[0]) {
+		// the timestamps exposed by the target went backwards, which happens when the target
+		// restarted: handle it as a reset. Scrape times only go backwards when the clock of the
+		// collector is adjusted, which does not reset the series.
+		return false
+	}
 	return ma.adjustPoint(metricType, current[0], initial[0], previous[0])
 }
 
+// isOlder returns true if the timestamp of current is strictly before the one of previous.
+// Points without a timestamp are never considered older.
+func isOlder(current, previous *metricspb.Point) bool {
+	if current.GetTimestamp() == nil || previous.GetTimestamp() == nil {
+		return false
+	}
+	return current.GetTimestamp().AsTime().Before(previous.GetTimestamp().AsTime())
+}
+
 // Note: There is an important, subtle point here. When a new timeseries or a reset is detected,
 // current and initial are the same object. When initial == previous, the previous value/count/sum
 // are all the initial value. When initial != previous, the previous value/count/sum has been
@@ -325,7 +386,8 @@ func (ma *MetricsAdjuster) adjustPoint(metricType metricspb.MetricDescriptor_Typ
 			previousCount += previous.GetDistributionValue().Count
 			previousSum += previous.GetDistributionValue().Sum
 		}
-		if currentDist.Count < previousCount || currentDist.Sum < previousSum {
+		if currentDist.Count < previousCount || currentDist.Sum < previousSum ||
+			bucketsDecreased(currentDist.Buckets, initialDist.Buckets, previous.GetDistributionValue().Buckets, initial != previous) {
 			// reset detected
 			return false
 		}
@@ -359,6 +421,26 @@ func (ma *MetricsAdjuster) adjustPoint(metricType metricspb.MetricDescriptor_Typ
 	return true
 }
 
+// bucketsDecreased returns true if the count of any of the current buckets is less than the
+// count of the matching previous bucket. Like for the count and sum, the previous buckets hold
+// counts adjusted wrt the initial ones unless previousAdjusted is false.
+func bucketsDecreased(current, initial, previous []*metricspb.DistributionValue_Bucket, previousAdjusted bool) bool {
+	if len(current) != len(initial) || len(current) != len(previous) {
+		// mismatched buckets are logged when adjusting them.
+		return false
+	}
+	for i := range current {
+		previousCount := initial[i].Count
+		if previousAdjusted {
+			previousCount += previous[i].Count
+		}
+		if current[i].Count < previousCount {
+			return true
+		}
+	}
+	return false
+}
+
 func (ma *MetricsAdjuster) adjustBuckets(current, initial []*metricspb.DistributionValue_Bucket) {
 	if len(current) != len(initial) {
 		// this shouldn't happen
@@ -101,6 +101,73 @@ func Test_cumulativeDistribution(t *testing.T) {
 	runScript(t, NewJobsMap(time.Minute).get("job", "0"), script)
 }
 
+func Test_cumulativeDistributionBucketReset(t *testing.T) {
+	script := []*metricsAdjusterTest{{
+		"CumulativeDistBucketReset: round 1 - initial instance, adjusted should be empty",
+		[]*metricspb.Metric{mtu.CumulativeDist(cd1, k1k2, mtu.Timeseries(t1Ms, v1v2, mtu.DistPt(t1Ms, bounds0, []int64{4, 2, 3, 7})))},
+		[]*metricspb.Metric{},
+	}, {
+		"CumulativeDistBucketReset: round 2 - instance adjusted based on round 1",
+		[]*metricspb.Metric{mtu.CumulativeDist(cd1, k1k2, mtu.Timeseries(t2Ms, v1v2, mtu.DistPt(t2Ms, bounds0, []int64{6, 3, 4, 8})))},
+		[]*metricspb.Metric{mtu.CumulativeDist(cd1, k1k2, mtu.Timeseries(t1Ms, v1v2, mtu.DistPt(t2Ms, bounds0, []int64{2, 1, 1, 1})))},
+	}, {
+		"CumulativeDistBucketReset: round 3 - instance reset (bucket less than previous bucket), adjusted should be empty",
+		[]*metricspb.Metric{mtu.CumulativeDist(cd1, k1k2, mtu.Timeseries(t3Ms, v1v2, mtu.DistPt(t3Ms, bounds0, []int64{10, 1, 4, 9})))},
+		[]*metricspb.Metric{},
+	}, {
+		"CumulativeDistBucketReset: round 4 - instance adjusted based on round 3",
+		[]*metricspb.Metric{mtu.CumulativeDist(cd1, k1k2, mtu.Timeseries(t4Ms, v1v2, mtu.DistPt(t4Ms, bounds0, []int64{11, 2, 5, 10})))},
+		[]*metricspb.Metric{mtu.CumulativeDist(cd1, k1k2, mtu.Timeseries(t3Ms, v1v2, mtu.DistPt(t4Ms, bounds0, []int64{1, 1, 1, 1})))},
+	}}
+	runScript(t, NewJobsMap(time.Minute).get("job", "0"), script)
+}
+
+func Test_cumulativeTimestampReset(t *testing.T) {
+	script := []*metricsAdjusterTest{{
+		"CumulativeTimestampReset: round 1 - initial instance, adjusted should be empty",
+		[]*metricspb.Metric{mtu.Cumulative(c1, k1k2, mtu.Timeseries(t2Ms, v1v2, mtu.Double(t2Ms, 44)))},
+		[]*metricspb.Metric{},
+	}, {
+		"CumulativeTimestampReset: round 2 - instance adjusted based on round 1",
+		[]*metricspb.Metric{mtu.Cumulative(c1, k1k2, mtu.Timeseries(t3Ms, v1v2, mtu.Double(t3Ms, 66)))},
+		[]*metricspb.Metric{mtu.Cumulative(c1, k1k2, mtu.Timeseries(t2Ms, v1v2, mtu.Double(t3Ms, 22)))},
+	}, {
+		"CumulativeTimestampReset: round 3 - instance reset (timestamp before previous timestamp), adjusted should be empty",
+		[]*metricspb.Metric{mtu.Cumulative(c1, k1k2, mtu.Timeseries(t1Ms, v1v2, mtu.Double(t1Ms, 70)))},
+		[]*metricspb.Metric{},
+	}, {
+		"CumulativeTimestampReset: round 4 - instance adjusted based on round 3",
+		[]*metricspb.Metric{mtu.Cumulative(c1, k1k2, mtu.Timeseries(t4Ms, v1v2, mtu.Double(t4Ms, 80)))},
+		[]*metricspb.Metric{mtu.Cumulative(c1, k1k2, mtu.Timeseries(t1Ms, v1v2, mtu.Double(t4Ms, 10)))},
+	}}
+	runScript(t, NewJobsMap(time.Minute).get("job", "0"), script)
+}
+
+func Test_cumulativeScrapeTimestamps(t *testing.T) {
+	script := []*metricsAdjusterTest{{
+		"CumulativeScrapeTimestamps: round 1 - initial instance, adjusted should be empty",
+		[]*metricspb.Metric{mtu.Cumulative(c1, k1k2, mtu.Timeseries(t2Ms, v1v2, mtu.Double(t2Ms, 44)))},
+		[]*metricspb.Metric{},
+	}, {
+		"CumulativeScrapeTimestamps: round 2 - instance adjusted based on round 1",
+		[]*metricspb.Metric{mtu.Cumulative(c1, k1k2, mtu.Timeseries(t3Ms, v1v2, mtu.Double(t3Ms, 66)))},
+		[]*metricspb.Metric{mtu.Cumulative(c1, k1k2, mtu.Timeseries(t2Ms, v1v2, mtu.Double(t3Ms, 22)))},
+	}, {
+		"CumulativeScrapeTimestamps: round 3 - scrape time before previous scrape time, instance adjusted based on round 1",
+		[]*metricspb.Metric{mtu.Cumulative(c1, k1k2, mtu.Timeseries(t1Ms, v1v2, mtu.Double(t1Ms, 70)))},
+		[]*metricspb.Metric{mtu.Cumulative(c1, k1k2, mtu.Timeseries(t2Ms, v1v2, mtu.Double(t1Ms, 26)))},
+	}, {
+		"CumulativeScrapeTimestamps: round 4 - instance reset (value less than previous value), adjusted should be empty",
+		[]*metricspb.Metric{mtu.Cumulative(c1, k1k2, mtu.Timeseries(t4Ms, v1v2, mtu.Double(t4Ms, 10)))},
+		[]*metricspb.Metric{},
+	}}
+	jm := NewJobsMap(time.Minute)
+	jm.SetScrapeTimestampJobs(map[string]bool{"job": true})
+	assert.True(t, jm.usesScrapeTimestamps("job"))
+	assert.False(t, jm.usesScrapeTimestamps("other"))
+	runAdjusterScript(t, NewMetricsAdjuster(jm.get("job", "0"), true, zap.NewNop()), script)
+}
+
 func Test_summary(t *testing.T) {
 	script := []*metricsAdjusterTest{{
 		"Summary: round 1 - initial instance, adjusted should be empty",
@@ -231,6 +298,15 @@ func Test_emptyLabels(t *testing.T) {
 	runScript(t, NewJobsMap(time.Minute).get("job", "0"), script)
 }
 
+func Test_adjustPointsLengths(t *testing.T) {
+	ma := &MetricsAdjuster{logger: zap.NewNop()}
+	points := []*metricspb.Point{mtu.Double(t1Ms, 44)}
+	// the points are reset, instead of being adjusted, unless there is exactly one of each.
+	assert.True(t, ma.adjustPoints(metricspb.MetricDescriptor_CUMULATIVE_DOUBLE, points, points, nil))
+	assert.True(t, ma.adjustPoints(metricspb.MetricDescriptor_CUMULATIVE_DOUBLE, points, nil, points))
+	assert.True(t, ma.adjustPoints(metricspb.MetricDescriptor_CUMULATIVE_DOUBLE, nil, points, points))
+}
+
 func Test_tsGC(t *testing.T) {
 	script1 := []*metricsAdjusterTest{{
 		"TsGC: round 1 - initial instances, adjusted should be empty",
@@ -366,8 +442,10 @@ func (mat *metricsAdjusterTest) dropped() int {
 func runScript(t *testing.T, tsm *timeseriesMap, script []*metricsAdjusterTest) {
 	l := zap.NewNop()
 	defer l.Sync() // flushes buffer, if any
-	ma := NewMetricsAdjuster(tsm, l)
+	runAdjusterScript(t, NewMetricsAdjuster(tsm, false, l), script)
+}
 
+func runAdjusterScript(t *testing.T, ma *MetricsAdjuster, script []*metricsAdjusterTest) {
 	for _, test := range script {
 		expectedDropped := test.dropped()
 		adjusted, dropped := ma.AdjustMetrics(test.metrics)
@@ -0,0 +1,120 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"context"
+	"testing"
+
+	gokitLog "github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+)
+
+func TestRecordScrapeReport(t *testing.T) {
+	views := MetricViews()
+	require.NoError(t, view.Register(views...))
+	defer view.Unregister(views...)
+
+	ctx := context.Background()
+	// Two targets of the same job are aggregated in the same rows.
+	recordScrapeReport(ctx, "prometheus", "job1", scrapeUpMetricName, 0)
+	recordScrapeReport(ctx, "prometheus", "job1", scrapeUpMetricName, 1)
+	recordScrapeReport(ctx, "prometheus", "job1", scrapeUpMetricName, 1)
+	recordScrapeReport(ctx, "prometheus", "job1", scrapeDurationMetricName, 0.25)
+	recordScrapeReport(ctx, "prometheus", "job1", scrapeDurationMetricName, 0.75)
+	recordScrapeReport(ctx, "prometheus", "job1", scrapeSamplesScrapedMetricName, 42)
+	recordScrapeReport(ctx, "prometheus", "job1", scrapeSamplesScrapedMetricName, 8)
+	recordScrapeReport(ctx, "prometheus", "job1", "scrape_series_added", 42)
+
+	jobTags := []tag.Tag{
+		{Key: tagJob, Value: "job1"},
+		{Key: tagReceiverName, Value: "prometheus"},
+	}
+
+	rows, err := view.RetrieveData(statTargetScrapes.Name())
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	counts := map[string]int64{}
+	for _, row := range rows {
+		require.Len(t, row.Tags, 3)
+		for _, tg := range row.Tags {
+			if tg.Key == tagStatus {
+				counts[tg.Value] = row.Data.(*view.CountData).Value
+			}
+		}
+	}
+	assert.Equal(t, map[string]int64{scrapeStatusSuccess: 2, scrapeStatusFailure: 1}, counts)
+
+	rows, err = view.RetrieveData(statTargetScrapeDuration.Name())
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, jobTags, rows[0].Tags)
+	assert.Equal(t, int64(2), rows[0].Data.(*view.DistributionData).Count)
+	assert.Equal(t, 0.5, rows[0].Data.(*view.DistributionData).Mean)
+
+	rows, err = view.RetrieveData(statTargetSamples.Name())
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, jobTags, rows[0].Tags)
+	assert.Equal(t, float64(50), rows[0].Data.(*view.SumData).Value)
+}
+
+func TestTargetSyncErrorsLogger(t *testing.T) {
+	views := MetricViews()
+	require.NoError(t, view.Register(views...))
+	defer view.Unregister(views...)
+
+	logger := NewTargetSyncErrorsLogger(context.Background(), gokitLog.NewNopLogger(), "prometheus")
+	poolLogger := gokitLog.With(logger, "scrape_pool", "job1")
+	require.NoError(t, level.Error(poolLogger).Log("msg", "creating targets failed", "err", "boom"))
+	require.NoError(t, level.Error(poolLogger).Log("msg", "Scrape commit failed", "err", "boom"))
+	require.NoError(t, level.Warn(poolLogger).Log("msg", "creating targets failed", "err", "boom"))
+	require.NoError(t, level.Error(logger).Log("msg", "error creating new scrape pool", "scrape_pool", "job2"))
+
+	rows, err := view.RetrieveData(statTargetSyncErrors.Name())
+	require.NoError(t, err)
+	got := make(map[string]int64)
+	for _, row := range rows {
+		for _, tg := range row.Tags {
+			if tg.Key == tagJob {
+				got[tg.Value] = int64(row.Data.(*view.SumData).Value)
+			}
+		}
+	}
+	assert.Equal(t, map[string]int64{"job1": 1, "job2": 1}, got)
+}
+
+func TestFileSDErrorsLogger(t *testing.T) {
+	views := MetricViews()
+	require.NoError(t, view.Register(views...))
+	defer view.Unregister(views...)
+
+	logger := NewFileSDErrorsLogger(context.Background(), gokitLog.NewNopLogger(), "prometheus")
+	fileLogger := gokitLog.With(logger, "discovery", "file")
+	require.NoError(t, level.Error(fileLogger).Log("msg", "Error reading file", "path", "targets.yaml", "err", "boom"))
+	require.NoError(t, level.Error(fileLogger).Log("msg", "Error adding file watch", "path", "targets.yaml", "err", "boom"))
+	require.NoError(t, level.Debug(fileLogger).Log("msg", "Error reading file", "path", "targets.yaml"))
+	require.NoError(t, level.Error(fileLogger).Log("msg", "Error reading file", "path", "other.json", "err", "boom"))
+
+	rows, err := view.RetrieveData(statFileSDReadErrors.Name())
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, []tag.Tag{{Key: tagReceiverName, Value: "prometheus"}}, rows[0].Tags)
+	assert.Equal(t, 2.0, rows[0].Data.(*view.SumData).Value)
+}
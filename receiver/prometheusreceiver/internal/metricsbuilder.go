@@ -25,6 +25,7 @@ import (
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/pkg/labels"
 	"github.com/prometheus/prometheus/pkg/textparse"
+	"github.com/prometheus/prometheus/pkg/value"
 	"go.uber.org/zap"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
@@ -56,12 +57,17 @@ type metricBuilder struct {
 	startTime            float64
 	logger               *zap.Logger
 	currentMf            MetricFamily
+	// maxTimeseriesPerBatch bounds the number of timeseries of the metrics that are built
+	// before they are flushed, 0 means that the metrics are only built once the whole page
+	// has been added.
+	maxTimeseriesPerBatch int
+	pendingTimeseries     int
 }
 
 // newMetricBuilder creates a MetricBuilder which is allowed to feed all the datapoints from a single prometheus
 // scraped page by calling its AddDataPoint function, and turn them into an opencensus data.MetricsData object
 // by calling its Build function
-func newMetricBuilder(mc MetadataCache, useStartTimeMetric bool, startTimeMetricRegex string, logger *zap.Logger) *metricBuilder {
+func newMetricBuilder(mc MetadataCache, useStartTimeMetric bool, startTimeMetricRegex string, maxTimeseriesPerBatch int, logger *zap.Logger) *metricBuilder {
 	var regex *regexp.Regexp
 	if startTimeMetricRegex != "" {
 		regex, _ = regexp.Compile(startTimeMetricRegex)
@@ -74,6 +80,8 @@ func newMetricBuilder(mc MetadataCache, useStartTimeMetric bool, startTimeMetric
 		droppedTimeseries:    0,
 		useStartTimeMetric:   useStartTimeMetric,
 		startTimeMetricRegex: regex,
+
+		maxTimeseriesPerBatch: maxTimeseriesPerBatch,
 	}
 }
 
@@ -112,27 +120,51 @@ func (b *metricBuilder) AddDataPoint(ls labels.Labels, t int64, v float64) error
 			}
 		}
 		return nil
-	case b.useStartTimeMetric && b.matchStartTimeMetric(metricName):
+	case b.useStartTimeMetric && b.matchStartTimeMetric(metricName) && !value.IsStaleNaN(v):
 		b.startTime = v
 	}
 
 	b.hasData = true
 
 	if b.currentMf != nil && !b.currentMf.IsSameFamily(metricName) {
-		m, ts, dts := b.currentMf.ToMetric()
-		b.numTimeseries += ts
-		b.droppedTimeseries += dts
-		if m != nil {
-			b.metrics = append(b.metrics, m)
-		}
-		b.currentMf = newMetricFamily(metricName, b.mc)
-	} else if b.currentMf == nil {
+		b.completeFamily()
+	} else if b.currentMf != nil && b.maxTimeseriesPerBatch > 0 && b.currentMf.CanSplit(b.maxTimeseriesPerBatch) {
+		// the rest of the family is built as another metric so that it can be flushed separately.
+		b.completeFamily()
+	}
+	if b.currentMf == nil {
 		b.currentMf = newMetricFamily(metricName, b.mc)
 	}
 
 	return b.currentMf.Add(metricName, ls, t, v)
 }
 
+// completeFamily builds the current metric family and adds it to the metrics built so far.
+func (b *metricBuilder) completeFamily() {
+	m, ts, dts := b.currentMf.ToMetric()
+	b.numTimeseries += ts
+	b.droppedTimeseries += dts
+	if m != nil {
+		b.metrics = append(b.metrics, m)
+		b.pendingTimeseries += len(m.Timeseries)
+	}
+	b.currentMf = nil
+}
+
+// IsBatchFull returns true if the metrics built so far hold enough timeseries to be flushed.
+func (b *metricBuilder) IsBatchFull() bool {
+	return b.maxTimeseriesPerBatch > 0 && b.pendingTimeseries >= b.maxTimeseriesPerBatch
+}
+
+// Flush returns the metrics built so far, from the metric families that are complete. These
+// metrics are not returned again by Flush or Build.
+func (b *metricBuilder) Flush() []*metricspb.Metric {
+	metrics := b.metrics
+	b.metrics = make([]*metricspb.Metric, 0)
+	b.pendingTimeseries = 0
+	return metrics
+}
+
 // Build an opencensus data.MetricsData based on all added data complexValue.
 // The only error returned by this function is errNoDataToBuild.
 func (b *metricBuilder) Build() ([]*metricspb.Metric, int, int, error) {
@@ -144,13 +176,7 @@ func (b *metricBuilder) Build() ([]*metricspb.Metric, int, int, error) {
 	}
 
 	if b.currentMf != nil {
-		m, ts, dts := b.currentMf.ToMetric()
-		b.numTimeseries += ts
-		b.droppedTimeseries += dts
-		if m != nil {
-			b.metrics = append(b.metrics, m)
-		}
-		b.currentMf = nil
+		b.completeFamily()
 	}
 
 	return b.metrics, b.numTimeseries, b.droppedTimeseries, nil
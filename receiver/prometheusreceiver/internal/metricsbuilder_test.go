@@ -16,6 +16,7 @@ package internal
 
 import (
 	"reflect"
+	"strconv"
 	"testing"
 
 	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
@@ -24,6 +25,7 @@ import (
 	"github.com/prometheus/prometheus/pkg/textparse"
 	"github.com/prometheus/prometheus/scrape"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
@@ -97,7 +99,7 @@ func runBuilderTests(t *testing.T, tests []buildTestData) {
 			mc := newMockMetadataCache(testMetadata)
 			st := startTs
 			for i, page := range tt.inputs {
-				b := newMetricBuilder(mc, true, "", testLogger)
+				b := newMetricBuilder(mc, true, "", 0, testLogger)
 				b.startTime = defaultBuilderStartTime // set to a non-zero value
 				for _, pt := range page.pts {
 					// set ts for testing
@@ -120,7 +122,7 @@ func runBuilderStartTimeTests(t *testing.T, tests []buildTestData,
 			mc := newMockMetadataCache(testMetadata)
 			st := startTs
 			for _, page := range tt.inputs {
-				b := newMetricBuilder(mc, true, startTimeMetricRegex,
+				b := newMetricBuilder(mc, true, startTimeMetricRegex, 0,
 					testLogger)
 				b.startTime = defaultBuilderStartTime // set to a non-zero value
 				for _, pt := range page.pts {
@@ -1141,7 +1143,7 @@ func Test_metricBuilder_skipped(t *testing.T) {
 func Test_metricBuilder_baddata(t *testing.T) {
 	t.Run("empty-metric-name", func(t *testing.T) {
 		mc := newMockMetadataCache(testMetadata)
-		b := newMetricBuilder(mc, true, "", testLogger)
+		b := newMetricBuilder(mc, true, "", 0, testLogger)
 		b.startTime = 1.0 // set to a non-zero value
 		if err := b.AddDataPoint(labels.FromStrings("a", "b"), startTs, 123); err != errMetricNameNotFound {
 			t.Error("expecting errMetricNameNotFound error, but get nil")
@@ -1155,7 +1157,7 @@ func Test_metricBuilder_baddata(t *testing.T) {
 
 	t.Run("histogram-datapoint-no-bucket-label", func(t *testing.T) {
 		mc := newMockMetadataCache(testMetadata)
-		b := newMetricBuilder(mc, true, "", testLogger)
+		b := newMetricBuilder(mc, true, "", 0, testLogger)
 		b.startTime = 1.0 // set to a non-zero value
 		if err := b.AddDataPoint(createLabels("hist_test", "k", "v"), startTs, 123); err != errEmptyBoundaryLabel {
 			t.Error("expecting errEmptyBoundaryLabel error, but get nil")
@@ -1164,7 +1166,7 @@ func Test_metricBuilder_baddata(t *testing.T) {
 
 	t.Run("summary-datapoint-no-quantile-label", func(t *testing.T) {
 		mc := newMockMetadataCache(testMetadata)
-		b := newMetricBuilder(mc, true, "", testLogger)
+		b := newMetricBuilder(mc, true, "", 0, testLogger)
 		b.startTime = 1.0 // set to a non-zero value
 		if err := b.AddDataPoint(createLabels("summary_test", "k", "v"), startTs, 123); err != errEmptyBoundaryLabel {
 			t.Error("expecting errEmptyBoundaryLabel error, but get nil")
@@ -1378,3 +1380,63 @@ func Test_heuristicalMetricAndKnownUnits(t *testing.T) {
 		})
 	}
 }
+
+func Test_metricBuilder_flush(t *testing.T) {
+	mc := newMockMetadataCache(testMetadata)
+	b := newMetricBuilder(mc, true, "", 2, testLogger)
+
+	pts := []*testDataPoint{
+		createDataPoint("counter_test", 1, "foo", "a"),
+		createDataPoint("counter_test", 2, "foo", "b"),
+		createDataPoint("counter_test", 3, "foo", "c"),
+		createDataPoint("hist_test_bucket", 1, "le", "10"),
+		createDataPoint("hist_test_bucket", 2, "le", "+Inf"),
+		createDataPoint("hist_test_sum", 5),
+		createDataPoint("hist_test_count", 2),
+		createDataPoint("hist_test_bucket", 3, "foo", "b", "le", "10"),
+		createDataPoint("hist_test_bucket", 4, "foo", "b", "le", "+Inf"),
+		createDataPoint("hist_test_sum", 8, "foo", "b"),
+		createDataPoint("hist_test_count", 4, "foo", "b"),
+		createDataPoint("gauge_test", 1),
+	}
+	var batches [][]*metricspb.Metric
+	for _, pt := range pts {
+		require.NoError(t, b.AddDataPoint(pt.lb, startTs, pt.v))
+		if b.IsBatchFull() {
+			batches = append(batches, b.Flush())
+		}
+	}
+	metrics, numTimeseries, _, err := b.Build()
+	require.NoError(t, err)
+	batches = append(batches, metrics)
+
+	// the counter family is split once it holds 2 timeseries, the histogram family is never split.
+	require.Len(t, batches, 3)
+	require.Len(t, batches[0], 1)
+	assert.Equal(t, "counter_test", batches[0][0].MetricDescriptor.Name)
+	assert.Len(t, batches[0][0].Timeseries, 2)
+
+	require.Len(t, batches[1], 2)
+	assert.Equal(t, "counter_test", batches[1][0].MetricDescriptor.Name)
+	assert.Len(t, batches[1][0].Timeseries, 1)
+	assert.Equal(t, "hist_test", batches[1][1].MetricDescriptor.Name)
+	assert.Len(t, batches[1][1].Timeseries, 2)
+
+	require.Len(t, batches[2], 1)
+	assert.Equal(t, "gauge_test", batches[2][0].MetricDescriptor.Name)
+
+	assert.Equal(t, 6, numTimeseries)
+}
+
+func Test_metricBuilder_noFlush(t *testing.T) {
+	mc := newMockMetadataCache(testMetadata)
+	b := newMetricBuilder(mc, true, "", 0, testLogger)
+	for i := 0; i < 10; i++ {
+		require.NoError(t, b.AddDataPoint(createLabels("counter_test", "foo", strconv.Itoa(i)), startTs, float64(i)))
+		require.False(t, b.IsBatchFull())
+	}
+	metrics, _, _, err := b.Build()
+	require.NoError(t, err)
+	require.Len(t, metrics, 1)
+	assert.Len(t, metrics[0].Timeseries, 10)
+}
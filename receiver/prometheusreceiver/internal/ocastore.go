@@ -40,28 +40,36 @@ var noop = &noopAppender{}
 type OcaStore struct {
 	ctx context.Context
 
-	running              int32 // access atomically
-	sink                 consumer.MetricsConsumer
-	mc                   *metadataService
-	jobsMap              *JobsMap
-	useStartTimeMetric   bool
-	startTimeMetricRegex string
-	receiverName         string
+	running               int32 // access atomically
+	sink                  consumer.MetricsConsumer
+	mc                    *metadataService
+	jobsMap               *JobsMap
+	useStartTimeMetric    bool
+	startTimeMetricRegex  string
+	receiverName          string
+	reportStaleness       StalenessMode
+	resourceLabels        map[string][]string
+	maxTimeseriesPerBatch int
+	seriesLimits          map[string]*SeriesLimits
 
 	logger *zap.Logger
 }
 
 // NewOcaStore returns an ocaStore instance, which can be acted as prometheus' scrape.Appendable
-func NewOcaStore(ctx context.Context, sink consumer.MetricsConsumer, logger *zap.Logger, jobsMap *JobsMap, useStartTimeMetric bool, startTimeMetricRegex string, receiverName string) *OcaStore {
+func NewOcaStore(ctx context.Context, sink consumer.MetricsConsumer, logger *zap.Logger, jobsMap *JobsMap, useStartTimeMetric bool, startTimeMetricRegex string, receiverName string, reportStaleness StalenessMode, resourceLabels map[string][]string, maxTimeseriesPerBatch int, seriesLimits map[string]*SeriesLimits) *OcaStore {
 	return &OcaStore{
-		running:              runningStateInit,
-		ctx:                  ctx,
-		sink:                 sink,
-		logger:               logger,
-		jobsMap:              jobsMap,
-		useStartTimeMetric:   useStartTimeMetric,
-		startTimeMetricRegex: startTimeMetricRegex,
-		receiverName:         receiverName,
+		running:               runningStateInit,
+		ctx:                   ctx,
+		sink:                  sink,
+		logger:                logger,
+		jobsMap:               jobsMap,
+		useStartTimeMetric:    useStartTimeMetric,
+		startTimeMetricRegex:  startTimeMetricRegex,
+		receiverName:          receiverName,
+		reportStaleness:       reportStaleness,
+		resourceLabels:        resourceLabels,
+		maxTimeseriesPerBatch: maxTimeseriesPerBatch,
+		seriesLimits:          seriesLimits,
 	}
 }
 
@@ -76,7 +84,7 @@ func (o *OcaStore) SetScrapeManager(scrapeManager *scrape.Manager) {
 func (o *OcaStore) Appender(context.Context) storage.Appender {
 	state := atomic.LoadInt32(&o.running)
 	if state == runningStateReady {
-		return newTransaction(o.ctx, o.jobsMap, o.useStartTimeMetric, o.startTimeMetricRegex, o.receiverName, o.mc, o.sink, o.logger)
+		return newTransaction(o.ctx, o.jobsMap, o.useStartTimeMetric, o.startTimeMetricRegex, o.receiverName, o.reportStaleness, o.resourceLabels, o.maxTimeseriesPerBatch, o.seriesLimits, o.mc, o.sink, o.logger)
 	} else if state == runningStateInit {
 		panic("ScrapeManager is not set")
 	}
@@ -26,7 +26,7 @@ import (
 
 func TestOcaStore(t *testing.T) {
 
-	o := NewOcaStore(context.Background(), nil, nil, nil, false, "", "prometheus")
+	o := NewOcaStore(context.Background(), nil, nil, nil, false, "", "prometheus", StalenessDrop, nil, 0, nil)
 	o.SetScrapeManager(&scrape.Manager{})
 
 	app := o.Appender(context.Background())
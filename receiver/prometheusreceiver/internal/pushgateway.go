@@ -0,0 +1,300 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/pkg/textparse"
+	"github.com/prometheus/prometheus/scrape"
+	"go.uber.org/zap"
+)
+
+const (
+	pushPathPrefix = "/metrics/"
+	// base64Suffix marks the grouping key labels whose value is base64 encoded, as done by
+	// the Pushgateway for values holding a "/".
+	base64Suffix = "@base64"
+)
+
+var (
+	errNoPushJob   = errors.New("the grouping key of a push requires a non-empty \"job\" label")
+	errInvalidPush = errors.New("invalid push")
+)
+
+// PushHandler returns an http.Handler accepting metrics pushed with the Pushgateway API,
+// "PUT" or "POST" on /metrics/job/<job>{/<label>/<value>}, in the Prometheus text or
+// protobuf format. The pushed metrics are forwarded as the metrics of a scrape are, the
+// labels of the grouping key being set on all of them.
+//
+// Unlike a Pushgateway, the handler does not store the pushed metrics, they are forwarded
+// once, so "PUT" and "POST" behave the same. "DELETE" only discards the state kept to
+// adjust the cumulative series of the grouping key.
+func (o *OcaStore) PushHandler() http.Handler {
+	return &pushHandler{store: o}
+}
+
+type pushHandler struct {
+	store *OcaStore
+}
+
+func (h *pushHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if atomic.LoadInt32(&h.store.running) != runningStateReady {
+		http.Error(w, "the receiver is not running", http.StatusServiceUnavailable)
+		return
+	}
+	switch r.Method {
+	case http.MethodPut, http.MethodPost, http.MethodDelete:
+	default:
+		w.Header().Set("Allow", "PUT, POST, DELETE")
+		http.Error(w, fmt.Sprintf("method %s is not allowed", r.Method), http.StatusMethodNotAllowed)
+		return
+	}
+
+	groupingKey, err := parseGroupingKey(r.URL.EscapedPath())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if groupingKey[model.InstanceLabel] == "" {
+		// the transactions identify their target by job and instance.
+		groupingKey[model.InstanceLabel] = remoteHost(r.RemoteAddr)
+	}
+
+	if r.Method == http.MethodDelete {
+		if h.store.jobsMap != nil {
+			h.store.jobsMap.remove(groupingKey[model.JobLabel], groupingKey[model.InstanceLabel])
+		}
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	families, err := decodeMetricFamilies(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.push(groupingKey, r.TLS != nil, families); err != nil {
+		if errors.Is(err, errInvalidPush) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		h.store.logger.Debug("Failed to forward pushed metrics", zap.Any("grouping_key", groupingKey), zap.Error(err))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// push forwards the given metric families, pushed for groupingKey, through a transaction
+// of their own.
+func (h *pushHandler) push(groupingKey map[string]string, isTLS bool, families []*dto.MetricFamily) error {
+	samples, err := expfmt.ExtractSamples(&expfmt.DecodeOptions{Timestamp: model.Now()}, families...)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errInvalidPush, err)
+	}
+	if len(samples) == 0 {
+		return nil
+	}
+
+	targetLabels := labels.FromMap(groupingKey)
+	scheme := "http"
+	if isTLS {
+		scheme = "https"
+	}
+	target := scrape.NewTarget(targetLabels, labels.FromStrings(model.SchemeLabel, scheme), nil)
+	target.SetMetadataStore(newPushMetadata(families))
+	ms := &metadataService{sm: &pushScrapeManager{job: groupingKey[model.JobLabel], target: target}}
+
+	o := h.store
+	tr := newTransaction(o.ctx, o.jobsMap, o.useStartTimeMetric, o.startTimeMetricRegex, o.receiverName, o.reportStaleness, o.resourceLabels, o.maxTimeseriesPerBatch, o.seriesLimits, ms, o.sink, o.logger)
+	for _, s := range samples {
+		b := labels.NewBuilder(nil)
+		for name, value := range s.Metric {
+			b.Set(string(name), string(value))
+		}
+		for _, l := range targetLabels {
+			b.Set(l.Name, l.Value)
+		}
+		if _, err := tr.Add(b.Labels(), int64(s.Timestamp), float64(s.Value)); err != nil {
+			_ = tr.Rollback()
+			return fmt.Errorf("%w: %v", errInvalidPush, err)
+		}
+	}
+	if err := tr.Commit(); err != nil && err != errNoDataToBuild {
+		return err
+	}
+	return nil
+}
+
+// parseGroupingKey returns the labels of the grouping key encoded in the given escaped
+// path, /metrics/job/<job>{/<label>/<value>}.
+func parseGroupingKey(path string) (map[string]string, error) {
+	if !strings.HasPrefix(path, pushPathPrefix) {
+		return nil, fmt.Errorf("invalid push path %q, expecting /metrics/job/<job>{/<label>/<value>}", path)
+	}
+	parts := strings.Split(strings.TrimPrefix(path, pushPathPrefix), "/")
+	if len(parts)%2 != 0 {
+		return nil, fmt.Errorf("invalid push path %q, the labels of the grouping key must be name and value pairs", path)
+	}
+	groupingKey := make(map[string]string, len(parts)/2+1)
+	for i := 0; i < len(parts); i += 2 {
+		name, value, err := groupingKeyLabel(parts[i], parts[i+1])
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 && name != model.JobLabel {
+			return nil, fmt.Errorf("invalid push path %q, expecting /metrics/job/<job>{/<label>/<value>}", path)
+		}
+		if _, ok := groupingKey[name]; ok {
+			return nil, fmt.Errorf("duplicate label %q in the grouping key", name)
+		}
+		groupingKey[name] = value
+	}
+	if groupingKey[model.JobLabel] == "" {
+		return nil, errNoPushJob
+	}
+	return groupingKey, nil
+}
+
+func groupingKeyLabel(escapedName, escapedValue string) (string, string, error) {
+	name, err := url.PathUnescape(escapedName)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid label name %q in the grouping key: %w", escapedName, err)
+	}
+	value, err := url.PathUnescape(escapedValue)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid value %q of label %q in the grouping key: %w", escapedValue, name, err)
+	}
+	if strings.HasSuffix(name, base64Suffix) {
+		name = strings.TrimSuffix(name, base64Suffix)
+		decoded, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+		if err != nil {
+			return "", "", fmt.Errorf("invalid base64 value %q of label %q in the grouping key: %w", value, name, err)
+		}
+		value = string(decoded)
+	}
+	if !model.LabelName(name).IsValid() || strings.HasPrefix(name, model.ReservedLabelPrefix) {
+		return "", "", fmt.Errorf("invalid label name %q in the grouping key", name)
+	}
+	return name, value, nil
+}
+
+// decodeMetricFamilies decodes the body of a push in the format given by its Content-Type,
+// the text format being assumed when it is not set.
+func decodeMetricFamilies(r *http.Request) ([]*dto.MetricFamily, error) {
+	format := expfmt.ResponseFormat(r.Header)
+	if format == expfmt.FmtUnknown {
+		format = expfmt.FmtText
+	}
+	dec := expfmt.NewDecoder(r.Body, format)
+	var families []*dto.MetricFamily
+	for {
+		mf := &dto.MetricFamily{}
+		if err := dec.Decode(mf); err != nil {
+			if err == io.EOF {
+				// the text decoder returns the families in no particular order.
+				sort.Slice(families, func(i, j int) bool { return families[i].GetName() < families[j].GetName() })
+				return families, nil
+			}
+			return nil, fmt.Errorf("failed to decode the pushed metrics: %w", err)
+		}
+		families = append(families, mf)
+	}
+}
+
+func remoteHost(remoteAddr string) string {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		return remoteAddr
+	}
+	return host
+}
+
+// pushScrapeManager exposes the single target of a push to the metadataService.
+type pushScrapeManager struct {
+	job    string
+	target *scrape.Target
+}
+
+func (m *pushScrapeManager) TargetsAll() map[string][]*scrape.Target {
+	return map[string][]*scrape.Target{m.job: {m.target}}
+}
+
+// pushMetadata is the metadata of the pushed metric families.
+type pushMetadata map[string]scrape.MetricMetadata
+
+var _ scrape.MetricMetadataStore = (pushMetadata)(nil)
+
+func newPushMetadata(families []*dto.MetricFamily) pushMetadata {
+	md := make(pushMetadata, len(families))
+	for _, mf := range families {
+		md[mf.GetName()] = scrape.MetricMetadata{
+			Metric: mf.GetName(),
+			Type:   metricType(mf.GetType()),
+			Help:   mf.GetHelp(),
+		}
+	}
+	return md
+}
+
+func metricType(t dto.MetricType) textparse.MetricType {
+	switch t {
+	case dto.MetricType_COUNTER:
+		return textparse.MetricTypeCounter
+	case dto.MetricType_GAUGE:
+		return textparse.MetricTypeGauge
+	case dto.MetricType_HISTOGRAM:
+		return textparse.MetricTypeHistogram
+	case dto.MetricType_SUMMARY:
+		return textparse.MetricTypeSummary
+	default:
+		return textparse.MetricTypeUnknown
+	}
+}
+
+func (md pushMetadata) ListMetadata() []scrape.MetricMetadata {
+	list := make([]scrape.MetricMetadata, 0, len(md))
+	for _, m := range md {
+		list = append(list, m)
+	}
+	return list
+}
+
+func (md pushMetadata) GetMetadata(metric string) (scrape.MetricMetadata, bool) {
+	m, ok := md[metric]
+	return m, ok
+}
+
+func (md pushMetadata) SizeMetadata() int {
+	return 0
+}
+
+func (md pushMetadata) LengthMetadata() int {
+	return len(md)
+}
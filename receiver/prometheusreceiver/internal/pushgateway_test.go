@@ -0,0 +1,153 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/translator/internaldata"
+)
+
+func newTestPushServer(t *testing.T, jobsMap *JobsMap) (*httptest.Server, *consumertest.MetricsSink) {
+	sink := new(consumertest.MetricsSink)
+	o := NewOcaStore(context.Background(), sink, testLogger, jobsMap, false, "", "prometheus", StalenessDrop, nil, 0, nil)
+	o.running = runningStateReady
+	srv := httptest.NewServer(o.PushHandler())
+	t.Cleanup(srv.Close)
+	return srv, sink
+}
+
+func push(t *testing.T, method, url, body string) int {
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+const pushedMetrics = `# HELP batch_last_success_seconds Last time the batch job succeeded.
+# TYPE batch_last_success_seconds gauge
+batch_last_success_seconds{stage="load"} 1.6e+09
+# TYPE batch_records_total counter
+batch_records_total 1024
+`
+
+func TestPushHandler(t *testing.T) {
+	srv, sink := newTestPushServer(t, NewJobsMap(time.Minute))
+
+	url := srv.URL + "/metrics/job/batch/team@base64/b3BzL2RhdGE"
+	assert.Equal(t, http.StatusOK, push(t, http.MethodPut, url, pushedMetrics))
+	assert.Equal(t, http.StatusOK, push(t, http.MethodPost, url, strings.Replace(pushedMetrics, "1024", "3072", 1)))
+
+	// the first point of the counter is the reference of its start time, so it is only
+	// forwarded by the second push, relative to the first one.
+	require.Len(t, sink.AllMetrics(), 2)
+	first := internaldata.MetricsToOC(sink.AllMetrics()[0])
+	require.Len(t, first, 1)
+	assert.Equal(t, "batch", first[0].Node.ServiceInfo.Name)
+	assert.Equal(t, "127.0.0.1", first[0].Node.Identifier.HostName)
+	require.Len(t, first[0].Metrics, 1)
+
+	gauge := first[0].Metrics[0]
+	assert.Equal(t, "batch_last_success_seconds", gauge.MetricDescriptor.Name)
+	assert.Equal(t, metricspb.MetricDescriptor_GAUGE_DOUBLE, gauge.MetricDescriptor.Type)
+	require.Len(t, gauge.MetricDescriptor.LabelKeys, 2)
+	assert.Equal(t, "stage", gauge.MetricDescriptor.LabelKeys[0].Key)
+	assert.Equal(t, "team", gauge.MetricDescriptor.LabelKeys[1].Key)
+	assert.Equal(t, "load", gauge.Timeseries[0].LabelValues[0].Value)
+	assert.Equal(t, "ops/data", gauge.Timeseries[0].LabelValues[1].Value)
+
+	second := internaldata.MetricsToOC(sink.AllMetrics()[1])
+	require.Len(t, second, 1)
+	require.Len(t, second[0].Metrics, 2)
+	counter := second[0].Metrics[1]
+	assert.Equal(t, "batch_records_total", counter.MetricDescriptor.Name)
+	assert.Equal(t, metricspb.MetricDescriptor_CUMULATIVE_DOUBLE, counter.MetricDescriptor.Type)
+	assert.Equal(t, 2048.0, counter.Timeseries[0].Points[0].GetDoubleValue())
+}
+
+func TestPushHandlerDelete(t *testing.T) {
+	jobsMap := NewJobsMap(time.Minute)
+	srv, sink := newTestPushServer(t, jobsMap)
+
+	url := srv.URL + "/metrics/job/batch/instance/worker-1"
+	assert.Equal(t, http.StatusOK, push(t, http.MethodPut, url, pushedMetrics))
+	assert.Equal(t, http.StatusAccepted, push(t, http.MethodDelete, url, ""))
+	// the counter starts over after the delete, so it is not forwarded.
+	assert.Equal(t, http.StatusOK, push(t, http.MethodPut, url, pushedMetrics))
+
+	require.Len(t, sink.AllMetrics(), 2)
+	for _, md := range sink.AllMetrics() {
+		ocmds := internaldata.MetricsToOC(md)
+		require.Len(t, ocmds, 1)
+		assert.Equal(t, "worker-1", ocmds[0].Node.Identifier.HostName)
+		require.Len(t, ocmds[0].Metrics, 1)
+		assert.Equal(t, "batch_last_success_seconds", ocmds[0].Metrics[0].MetricDescriptor.Name)
+	}
+}
+
+func TestPushHandlerErrors(t *testing.T) {
+	srv, sink := newTestPushServer(t, NewJobsMap(time.Minute))
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{"method", http.MethodGet, "/metrics/job/batch", "", http.StatusMethodNotAllowed},
+		{"not a push path", http.MethodPut, "/api/v1/metrics", pushedMetrics, http.StatusBadRequest},
+		{"no job", http.MethodPut, "/metrics/instance/worker-1", pushedMetrics, http.StatusBadRequest},
+		{"empty job", http.MethodPut, "/metrics/job/", pushedMetrics, http.StatusBadRequest},
+		{"odd labels", http.MethodPut, "/metrics/job/batch/team", pushedMetrics, http.StatusBadRequest},
+		{"duplicate label", http.MethodPut, "/metrics/job/batch/team/a/team/b", pushedMetrics, http.StatusBadRequest},
+		{"reserved label", http.MethodPut, "/metrics/job/batch/__address__/a", pushedMetrics, http.StatusBadRequest},
+		{"invalid base64", http.MethodPut, "/metrics/job/batch/team@base64/$$", pushedMetrics, http.StatusBadRequest},
+		{"invalid body", http.MethodPut, "/metrics/job/batch", "batch_records_total{", http.StatusBadRequest},
+		{"empty body", http.MethodPut, "/metrics/job/batch", "", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.status, push(t, tt.method, srv.URL+tt.path, tt.body))
+		})
+	}
+	assert.Empty(t, sink.AllMetrics())
+}
+
+func TestPushHandlerNotRunning(t *testing.T) {
+	o := NewOcaStore(context.Background(), new(consumertest.MetricsSink), testLogger, nil, false, "", "prometheus", StalenessDrop, nil, 0, nil)
+	srv := httptest.NewServer(o.PushHandler())
+	defer srv.Close()
+
+	assert.Equal(t, http.StatusServiceUnavailable, push(t, http.MethodPut, srv.URL+"/metrics/job/batch", pushedMetrics))
+}
+
+func TestParseGroupingKey(t *testing.T) {
+	got, err := parseGroupingKey("/metrics/job@base64/YS9i/path/%2Fvar%2Flog/empty@base64/=")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"job": "a/b", "path": "/var/log", "empty": ""}, got)
+}
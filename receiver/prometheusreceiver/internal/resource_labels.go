@@ -0,0 +1,165 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"strings"
+
+	commonpb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/common/v1"
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+
+	"go.opentelemetry.io/collector/translator/internaldata"
+)
+
+// resourceGroup holds the metrics of a scrape sharing the same promoted label values.
+type resourceGroup struct {
+	md      internaldata.MetricsData
+	metrics map[*metricspb.MetricDescriptor]*metricspb.Metric
+}
+
+// promoteResourceLabels moves the labels named by keys from the timeseries of the given
+// metrics to the attributes of their resource. Since the promoted values can differ from
+// one timeseries to another, the metrics are split into one MetricsData per distinct set
+// of promoted values. A timeseries that does not have one of the labels, or has it empty,
+// is kept under a resource without the matching attribute.
+func promoteResourceLabels(node *commonpb.Node, resource *resourcepb.Resource, metrics []*metricspb.Metric, keys []string) []internaldata.MetricsData {
+	if len(keys) == 0 {
+		return []internaldata.MetricsData{{Node: node, Resource: resource, Metrics: metrics}}
+	}
+
+	var ordered []*resourceGroup
+	groups := make(map[string]*resourceGroup)
+	groupFor := func(attrs map[string]string) *resourceGroup {
+		signature := resourceSignature(keys, attrs)
+		if g, ok := groups[signature]; ok {
+			return g
+		}
+		g := &resourceGroup{
+			md: internaldata.MetricsData{
+				Node:     node,
+				Resource: resourceWithLabels(resource, attrs),
+			},
+			metrics: make(map[*metricspb.MetricDescriptor]*metricspb.Metric),
+		}
+		groups[signature] = g
+		ordered = append(ordered, g)
+		return g
+	}
+
+	for _, metric := range metrics {
+		promoted, descriptor := splitLabelKeys(metric.MetricDescriptor, keys)
+		if len(promoted) == 0 {
+			g := groupFor(nil)
+			g.md.Metrics = append(g.md.Metrics, metric)
+			continue
+		}
+
+		for _, ts := range metric.Timeseries {
+			attrs := make(map[string]string, len(promoted))
+			values := make([]*metricspb.LabelValue, 0, len(descriptor.LabelKeys))
+			for i, v := range ts.LabelValues {
+				if key, ok := promoted[i]; ok {
+					if v.GetHasValue() && v.GetValue() != "" {
+						attrs[key] = v.GetValue()
+					}
+					continue
+				}
+				values = append(values, v)
+			}
+
+			g := groupFor(attrs)
+			m, ok := g.metrics[descriptor]
+			if !ok {
+				m = &metricspb.Metric{MetricDescriptor: descriptor}
+				g.metrics[descriptor] = m
+				g.md.Metrics = append(g.md.Metrics, m)
+			}
+			m.Timeseries = append(m.Timeseries, &metricspb.TimeSeries{
+				StartTimestamp: ts.StartTimestamp,
+				LabelValues:    values,
+				Points:         ts.Points,
+			})
+		}
+	}
+
+	mds := make([]internaldata.MetricsData, 0, len(ordered))
+	for _, g := range ordered {
+		mds = append(mds, g.md)
+	}
+	return mds
+}
+
+// splitLabelKeys returns the positions of the promoted keys among the label keys of the
+// given descriptor, and a descriptor holding the remaining label keys only.
+func splitLabelKeys(descriptor *metricspb.MetricDescriptor, keys []string) (map[int]string, *metricspb.MetricDescriptor) {
+	promoted := make(map[int]string)
+	for i, lk := range descriptor.GetLabelKeys() {
+		for _, key := range keys {
+			if lk.Key == key {
+				promoted[i] = key
+				break
+			}
+		}
+	}
+	if len(promoted) == 0 {
+		return nil, descriptor
+	}
+
+	labelKeys := make([]*metricspb.LabelKey, 0, len(descriptor.LabelKeys)-len(promoted))
+	for i, lk := range descriptor.LabelKeys {
+		if _, ok := promoted[i]; !ok {
+			labelKeys = append(labelKeys, lk)
+		}
+	}
+	return promoted, &metricspb.MetricDescriptor{
+		Name:        descriptor.Name,
+		Description: descriptor.Description,
+		Unit:        descriptor.Unit,
+		Type:        descriptor.Type,
+		LabelKeys:   labelKeys,
+	}
+}
+
+func resourceSignature(keys []string, attrs map[string]string) string {
+	var b strings.Builder
+	for _, key := range keys {
+		if v, ok := attrs[key]; ok {
+			b.WriteString(key)
+			b.WriteByte('=')
+			b.WriteString(v)
+		}
+		b.WriteByte('\xff')
+	}
+	return b.String()
+}
+
+// resourceWithLabels returns a copy of resource with the given labels added.
+func resourceWithLabels(resource *resourcepb.Resource, attrs map[string]string) *resourcepb.Resource {
+	if len(attrs) == 0 {
+		return resource
+	}
+	out := &resourcepb.Resource{
+		Type:   resource.GetType(),
+		Labels: make(map[string]string, len(resource.GetLabels())+len(attrs)),
+	}
+	for k, v := range resource.GetLabels() {
+		out.Labels[k] = v
+	}
+	for k, v := range attrs {
+		out.Labels[k] = v
+	}
+	return out
+}
@@ -0,0 +1,81 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"testing"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPromoteResourceLabels(t *testing.T) {
+	node, resource := createNodeAndResource("job", "localhost:8080", "http")
+	point := []*metricspb.Point{{Value: &metricspb.Point_DoubleValue{DoubleValue: 1}}}
+	metrics := []*metricspb.Metric{
+		{
+			MetricDescriptor: &metricspb.MetricDescriptor{
+				Name:      "kube_pod_info",
+				Type:      metricspb.MetricDescriptor_GAUGE_DOUBLE,
+				LabelKeys: []*metricspb.LabelKey{{Key: "namespace"}, {Key: "phase"}, {Key: "pod"}},
+			},
+			Timeseries: []*metricspb.TimeSeries{
+				{LabelValues: []*metricspb.LabelValue{{Value: "ns1", HasValue: true}, {Value: "Running", HasValue: true}, {Value: "a", HasValue: true}}, Points: point},
+				{LabelValues: []*metricspb.LabelValue{{Value: "ns2", HasValue: true}, {Value: "Pending", HasValue: true}, {Value: "b", HasValue: true}}, Points: point},
+				{LabelValues: []*metricspb.LabelValue{{Value: "ns1", HasValue: true}, {Value: "Failed", HasValue: true}, {Value: "a", HasValue: true}}, Points: point},
+				{LabelValues: []*metricspb.LabelValue{{Value: "ns1", HasValue: true}, {Value: "Failed", HasValue: true}, {HasValue: false}}, Points: point},
+			},
+		},
+		{
+			MetricDescriptor: &metricspb.MetricDescriptor{
+				Name:      "up",
+				Type:      metricspb.MetricDescriptor_GAUGE_DOUBLE,
+				LabelKeys: []*metricspb.LabelKey{},
+			},
+			Timeseries: []*metricspb.TimeSeries{{LabelValues: []*metricspb.LabelValue{}, Points: point}},
+		},
+	}
+
+	mds := promoteResourceLabels(node, resource, metrics, []string{"namespace", "pod"})
+	require.Len(t, mds, 4)
+
+	assert.Equal(t, map[string]string{"port": "8080", "scheme": "http", "namespace": "ns1", "pod": "a"}, mds[0].Resource.Labels)
+	require.Len(t, mds[0].Metrics, 1)
+	assert.Equal(t, []*metricspb.LabelKey{{Key: "phase"}}, mds[0].Metrics[0].MetricDescriptor.LabelKeys)
+	require.Len(t, mds[0].Metrics[0].Timeseries, 2)
+	assert.Equal(t, "Running", mds[0].Metrics[0].Timeseries[0].LabelValues[0].Value)
+	assert.Equal(t, "Failed", mds[0].Metrics[0].Timeseries[1].LabelValues[0].Value)
+
+	assert.Equal(t, map[string]string{"port": "8080", "scheme": "http", "namespace": "ns2", "pod": "b"}, mds[1].Resource.Labels)
+	assert.Equal(t, map[string]string{"port": "8080", "scheme": "http", "namespace": "ns1"}, mds[2].Resource.Labels)
+
+	// metrics without any of the promoted labels stay on the target resource.
+	assert.Equal(t, resource, mds[3].Resource)
+	require.Len(t, mds[3].Metrics, 1)
+	assert.Equal(t, "up", mds[3].Metrics[0].MetricDescriptor.Name)
+
+	// the target resource must not be modified.
+	assert.Equal(t, map[string]string{"port": "8080", "scheme": "http"}, resource.Labels)
+}
+
+func TestPromoteResourceLabelsNoKeys(t *testing.T) {
+	node, resource := createNodeAndResource("job", "localhost:8080", "http")
+	metrics := []*metricspb.Metric{{MetricDescriptor: &metricspb.MetricDescriptor{Name: "up"}}}
+	mds := promoteResourceLabels(node, resource, metrics, nil)
+	require.Len(t, mds, 1)
+	assert.Equal(t, resource, mds[0].Resource)
+	assert.Equal(t, metrics, mds[0].Metrics)
+}
@@ -0,0 +1,58 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"fmt"
+	"math"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/prometheus/prometheus/pkg/value"
+)
+
+// StalenessMode defines how the staleness markers reported by the scrape loop, when a series
+// or a target disappears, are handled.
+type StalenessMode string
+
+const (
+	// StalenessDrop drops the staleness markers.
+	StalenessDrop StalenessMode = "drop"
+	// StalenessFlag forwards the staleness markers of counters, gauges and untyped series as
+	// data points holding the Prometheus staleness NaN value. Staleness markers of histograms
+	// and summaries are dropped since their counts cannot hold a NaN.
+	StalenessFlag StalenessMode = "flag"
+	// StalenessGap drops the staleness markers and forgets the state kept to adjust the
+	// marked cumulative series, the next sample of such a series starts a new series.
+	StalenessGap StalenessMode = "gap"
+)
+
+// ValidateStalenessMode returns an error if the given mode is not supported, the empty
+// mode is a synonym for StalenessDrop.
+func ValidateStalenessMode(mode StalenessMode) error {
+	switch mode {
+	case "", StalenessDrop, StalenessFlag, StalenessGap:
+		return nil
+	}
+	return fmt.Errorf("unsupported staleness mode %q, must be one of %q, %q or %q", mode, StalenessDrop, StalenessFlag, StalenessGap)
+}
+
+// isStalePoint returns true if the point holds the Prometheus staleness marker.
+func isStalePoint(pt *metricspb.Point) bool {
+	v, ok := pt.GetValue().(*metricspb.Point_DoubleValue)
+	return ok && value.IsStaleNaN(v.DoubleValue)
+}
+
+// staleNaN is the Prometheus staleness marker.
+var staleNaN = math.Float64frombits(value.StaleNaN)
@@ -0,0 +1,138 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/pkg/value"
+	"github.com/prometheus/prometheus/scrape"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/translator/internaldata"
+)
+
+type mockMetadataStore struct {
+	data map[string]scrape.MetricMetadata
+}
+
+func (s *mockMetadataStore) ListMetadata() []scrape.MetricMetadata { return nil }
+
+func (s *mockMetadataStore) GetMetadata(metric string) (scrape.MetricMetadata, bool) {
+	mm, ok := s.data[metric]
+	return mm, ok
+}
+
+func (s *mockMetadataStore) SizeMetadata() int { return 0 }
+
+func (s *mockMetadataStore) LengthMetadata() int { return len(s.data) }
+
+func newStalenessTestMetadataService() *metadataService {
+	target := scrape.NewTarget(
+		labels.FromStrings(model.InstanceLabel, "localhost:8080", model.JobLabel, "test"),
+		labels.FromStrings(model.AddressLabel, "localhost:8080", model.SchemeLabel, "http"),
+		nil)
+	target.SetMetadataStore(&mockMetadataStore{data: testMetadata})
+	return &metadataService{
+		sm: &mockScrapeManager{targets: map[string][]*scrape.Target{"test": {target}}},
+	}
+}
+
+// scrapeOnce runs a transaction adding a single sample and returns the double values that were forwarded.
+func scrapeOnce(t *testing.T, mode StalenessMode, jobsMap *JobsMap, metricName string, ts int64, v float64) []float64 {
+	ms := newStalenessTestMetadataService()
+	sink := new(consumertest.MetricsSink)
+	tr := newTransaction(context.Background(), jobsMap, false, "", "prometheus", mode, nil, 0, nil, ms, sink, testLogger)
+	ls := labels.FromStrings(model.InstanceLabel, "localhost:8080", model.JobLabel, "test", model.MetricNameLabel, metricName, "foo", "bar")
+	_, err := tr.Add(ls, ts, v)
+	require.NoError(t, err)
+	require.NoError(t, tr.Commit())
+
+	var values []float64
+	for _, md := range sink.AllMetrics() {
+		for _, ocmd := range internaldata.MetricsToOC(md) {
+			for _, m := range ocmd.Metrics {
+				for _, series := range m.Timeseries {
+					values = append(values, series.Points[0].GetDoubleValue())
+				}
+			}
+		}
+	}
+	return values
+}
+
+func TestStalenessDrop(t *testing.T) {
+	jobsMap := NewJobsMap(time.Minute)
+	assert.Empty(t, scrapeOnce(t, StalenessDrop, jobsMap, "gauge_test", startTs, staleNaN))
+	assert.Empty(t, scrapeOnce(t, "", jobsMap, "gauge_test", startTs, staleNaN))
+}
+
+func TestStalenessFlag(t *testing.T) {
+	jobsMap := NewJobsMap(time.Minute)
+
+	got := scrapeOnce(t, StalenessFlag, jobsMap, "gauge_test", startTs, staleNaN)
+	require.Len(t, got, 1)
+	assert.True(t, value.IsStaleNaN(got[0]))
+
+	// a regular NaN is not a staleness marker and is still dropped.
+	assert.Empty(t, scrapeOnce(t, StalenessFlag, jobsMap, "gauge_test", startTs, math.NaN()))
+
+	// the first point of a cumulative series is only used as the initial point.
+	assert.Empty(t, scrapeOnce(t, StalenessFlag, jobsMap, "counter_test", startTs, 10))
+	got = scrapeOnce(t, StalenessFlag, jobsMap, "counter_test", startTs+interval, staleNaN)
+	require.Len(t, got, 1)
+	assert.True(t, value.IsStaleNaN(got[0]))
+	// the staleness marker did not become the previous point.
+	assert.Equal(t, []float64{5}, scrapeOnce(t, StalenessFlag, jobsMap, "counter_test", startTs+2*interval, 15))
+}
+
+func TestStalenessGap(t *testing.T) {
+	jobsMap := NewJobsMap(time.Minute)
+	assert.Empty(t, scrapeOnce(t, StalenessGap, jobsMap, "counter_test", startTs, 10))
+	assert.Equal(t, []float64{10}, scrapeOnce(t, StalenessGap, jobsMap, "counter_test", startTs+interval, 20))
+
+	// the staleness marker closes the series, the next sample starts a new one.
+	assert.Empty(t, scrapeOnce(t, StalenessGap, jobsMap, "counter_test", startTs+2*interval, staleNaN))
+	assert.Empty(t, scrapeOnce(t, StalenessGap, jobsMap, "counter_test", startTs+3*interval, 25))
+	assert.Equal(t, []float64{5}, scrapeOnce(t, StalenessGap, jobsMap, "counter_test", startTs+4*interval, 30))
+}
+
+func TestStalenessGapTargetGone(t *testing.T) {
+	jobsMap := NewJobsMap(time.Minute)
+	tsm := jobsMap.get("gone", "localhost:8080")
+	tsm.get(&metricspb.Metric{MetricDescriptor: &metricspb.MetricDescriptor{Name: "counter_test"}}, nil)
+
+	tr := newTransaction(context.Background(), jobsMap, false, "", "prometheus", StalenessGap, nil, 0, nil, newStalenessTestMetadataService(), consumertest.NewMetricsNop(), testLogger)
+	ls := labels.FromStrings(model.InstanceLabel, "localhost:8080", model.JobLabel, "gone", model.MetricNameLabel, "counter_test")
+	_, err := tr.Add(ls, startTs, staleNaN)
+	require.NoError(t, err)
+	require.NoError(t, tr.Commit())
+	assert.NotContains(t, jobsMap.jobsMap, "gone:localhost:8080")
+}
+
+func TestValidateStalenessMode(t *testing.T) {
+	for _, mode := range []StalenessMode{"", StalenessDrop, StalenessFlag, StalenessGap} {
+		assert.NoError(t, ValidateStalenessMode(mode))
+	}
+	assert.Error(t, ValidateStalenessMode("close"))
+}
@@ -0,0 +1,151 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"context"
+	"crypto/md5"
+	"strings"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/discovery/targetgroup"
+	"github.com/prometheus/prometheus/pkg/relabel"
+)
+
+// TargetMatcher matches the targets found by service discovery on the value of one of their labels.
+type TargetMatcher struct {
+	label model.LabelName
+	regex relabel.Regexp
+}
+
+// NewTargetMatcher returns a TargetMatcher matching the targets whose label fully matches regex.
+func NewTargetMatcher(label, regex string) (TargetMatcher, error) {
+	re, err := relabel.NewRegexp(regex)
+	if err != nil {
+		return TargetMatcher{}, err
+	}
+	return TargetMatcher{label: model.LabelName(label), regex: re}, nil
+}
+
+// Matches returns true if the labels of the target match.
+func (m TargetMatcher) Matches(ls model.LabelSet) bool {
+	return m.regex.MatchString(string(ls[m.label]))
+}
+
+// TargetShard selects the targets of one out of Total shards, by hashing the values of Labels.
+// The hash is the one of the Prometheus "hashmod" relabel action, with the values joined by ";"
+// like relabel source labels, so that targets are sharded as Prometheus would.
+type TargetShard struct {
+	Index  uint64
+	Total  uint64
+	Labels []model.LabelName
+}
+
+// Keep returns true if the target with the given labels belongs to the shard.
+func (s *TargetShard) Keep(ls model.LabelSet) bool {
+	values := make([]string, 0, len(s.Labels))
+	for _, ln := range s.Labels {
+		values = append(values, string(ls[ln]))
+	}
+	return sum64(md5.Sum([]byte(strings.Join(values, ";"))))%s.Total == s.Index
+}
+
+// sum64 is the big endian uint64 of the last 8 bytes of the hash, as used by hashmod.
+func sum64(hash [md5.Size]byte) uint64 {
+	var s uint64
+	for _, b := range hash[md5.Size-8:] {
+		s = s<<8 | uint64(b)
+	}
+	return s
+}
+
+// TargetFilter keeps the targets matching any of its allow matchers, or all targets when
+// there are none, unless they also match any of its deny matchers or belong to another
+// shard than Shard.
+type TargetFilter struct {
+	Allow []TargetMatcher
+	Deny  []TargetMatcher
+	Shard *TargetShard
+}
+
+// Keep returns true if the target with the given labels must be scraped.
+func (f *TargetFilter) Keep(ls model.LabelSet) bool {
+	if f.Shard != nil && !f.Shard.Keep(ls) {
+		return false
+	}
+	for _, m := range f.Deny {
+		if m.Matches(ls) {
+			return false
+		}
+	}
+	if len(f.Allow) == 0 {
+		return true
+	}
+	for _, m := range f.Allow {
+		if m.Matches(ls) {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterTargets forwards the target groups received from a discovery manager, removing the
+// targets for which keep returns false, until ctx is done. keep is given the labels of the
+// target merged with the labels of its group, before any relabeling. Groups left without
+// any target are still forwarded so that the targets they used to hold are stopped.
+func FilterTargets(ctx context.Context, in <-chan map[string][]*targetgroup.Group, keep func(model.LabelSet) bool) <-chan map[string][]*targetgroup.Group {
+	out := make(chan map[string][]*targetgroup.Group)
+	go func() {
+		for {
+			select {
+			case tsets := <-in:
+				select {
+				case out <- filterTargetSets(tsets, keep):
+				case <-ctx.Done():
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+func filterTargetSets(tsets map[string][]*targetgroup.Group, keep func(model.LabelSet) bool) map[string][]*targetgroup.Group {
+	filtered := make(map[string][]*targetgroup.Group, len(tsets))
+	for setName, groups := range tsets {
+		filteredGroups := make([]*targetgroup.Group, 0, len(groups))
+		for _, group := range groups {
+			if group == nil {
+				continue
+			}
+			// copy the group, it is shared with the discovery manager.
+			fg := &targetgroup.Group{
+				Labels:  group.Labels,
+				Source:  group.Source,
+				Targets: make([]model.LabelSet, 0, len(group.Targets)),
+			}
+			for _, target := range group.Targets {
+				if keep(group.Labels.Merge(target)) {
+					fg.Targets = append(fg.Targets, target)
+				}
+			}
+			filteredGroups = append(filteredGroups, fg)
+		}
+		filtered[setName] = filteredGroups
+	}
+	return filtered
+}
@@ -0,0 +1,187 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/discovery/targetgroup"
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/pkg/relabel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mustNewTargetMatcher(t *testing.T, label, regex string) TargetMatcher {
+	m, err := NewTargetMatcher(label, regex)
+	require.NoError(t, err)
+	return m
+}
+
+func TestTargetFilterKeep(t *testing.T) {
+	kubeSystem := model.LabelSet{model.AddressLabel: "10.0.0.1:8080", "__meta_kubernetes_namespace": "kube-system"}
+	defaultNs := model.LabelSet{model.AddressLabel: "10.0.0.2:8080", "__meta_kubernetes_namespace": "default"}
+	monitoring := model.LabelSet{model.AddressLabel: "10.0.0.3:9100", "__meta_kubernetes_namespace": "monitoring"}
+
+	tests := []struct {
+		name   string
+		filter TargetFilter
+		kept   []model.LabelSet
+	}{
+		{
+			name:   "no matchers",
+			filter: TargetFilter{},
+			kept:   []model.LabelSet{kubeSystem, defaultNs, monitoring},
+		},
+		{
+			name:   "deny",
+			filter: TargetFilter{Deny: []TargetMatcher{mustNewTargetMatcher(t, "__meta_kubernetes_namespace", "kube-.*")}},
+			kept:   []model.LabelSet{defaultNs, monitoring},
+		},
+		{
+			name:   "allow",
+			filter: TargetFilter{Allow: []TargetMatcher{mustNewTargetMatcher(t, model.AddressLabel, ".*:8080")}},
+			kept:   []model.LabelSet{kubeSystem, defaultNs},
+		},
+		{
+			name: "deny takes precedence",
+			filter: TargetFilter{
+				Allow: []TargetMatcher{mustNewTargetMatcher(t, model.AddressLabel, ".*:8080")},
+				Deny:  []TargetMatcher{mustNewTargetMatcher(t, "__meta_kubernetes_namespace", "kube-system")},
+			},
+			kept: []model.LabelSet{defaultNs},
+		},
+		{
+			name:   "regex is anchored",
+			filter: TargetFilter{Allow: []TargetMatcher{mustNewTargetMatcher(t, "__meta_kubernetes_namespace", "default|system")}},
+			kept:   []model.LabelSet{defaultNs},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var kept []model.LabelSet
+			for _, ls := range []model.LabelSet{kubeSystem, defaultNs, monitoring} {
+				if tt.filter.Keep(ls) {
+					kept = append(kept, ls)
+				}
+			}
+			assert.Equal(t, tt.kept, kept)
+		})
+	}
+}
+
+func TestTargetShard(t *testing.T) {
+	const total = 3
+	shards := make([]*TargetShard, total)
+	for i := range shards {
+		shards[i] = &TargetShard{Index: uint64(i), Total: total, Labels: []model.LabelName{model.AddressLabel, "__meta_kubernetes_namespace"}}
+	}
+	// the shard of a target is the one the Prometheus hashmod relabel action computes.
+	hashmod := &relabel.Config{
+		SourceLabels: model.LabelNames{model.AddressLabel, "__meta_kubernetes_namespace"},
+		Separator:    ";",
+		Regex:        relabel.MustNewRegexp(".*"),
+		Modulus:      total,
+		TargetLabel:  "__tmp_hash",
+		Action:       relabel.HashMod,
+	}
+
+	perShard := make([]int, total)
+	for i := 0; i < 100; i++ {
+		ls := model.LabelSet{
+			model.AddressLabel:            model.LabelValue(fmt.Sprintf("10.0.0.%d:8080", i)),
+			"__meta_kubernetes_namespace": "default",
+		}
+		want, err := strconv.Atoi(relabel.Process(labels.FromStrings(
+			model.AddressLabel, string(ls[model.AddressLabel]),
+			"__meta_kubernetes_namespace", "default"), hashmod).Get("__tmp_hash"))
+		require.NoError(t, err)
+
+		var kept []int
+		for j, shard := range shards {
+			if shard.Keep(ls) {
+				kept = append(kept, j)
+			}
+		}
+		require.Equal(t, []int{want}, kept, "target %v", ls)
+		perShard[want]++
+	}
+	for _, n := range perShard {
+		assert.NotZero(t, n)
+	}
+}
+
+func TestTargetFilterShard(t *testing.T) {
+	filter := &TargetFilter{
+		Allow: []TargetMatcher{mustNewTargetMatcher(t, model.AddressLabel, ".*")},
+		Shard: &TargetShard{Index: 0, Total: 2, Labels: []model.LabelName{model.AddressLabel}},
+	}
+	other := &TargetFilter{Shard: &TargetShard{Index: 1, Total: 2, Labels: []model.LabelName{model.AddressLabel}}}
+	for i := 0; i < 10; i++ {
+		ls := model.LabelSet{model.AddressLabel: model.LabelValue(fmt.Sprintf("10.0.0.%d:8080", i))}
+		assert.NotEqual(t, filter.Keep(ls), other.Keep(ls))
+	}
+}
+
+func TestNewTargetMatcherInvalidRegex(t *testing.T) {
+	_, err := NewTargetMatcher(model.AddressLabel, "(")
+	assert.Error(t, err)
+}
+
+func TestFilterTargets(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	in := make(chan map[string][]*targetgroup.Group)
+	filter := &TargetFilter{Deny: []TargetMatcher{mustNewTargetMatcher(t, "__meta_kubernetes_namespace", "kube-system")}}
+	out := FilterTargets(ctx, in, filter.Keep)
+
+	group := &targetgroup.Group{
+		Source: "pods",
+		Labels: model.LabelSet{"__meta_kubernetes_namespace": "kube-system"},
+		Targets: []model.LabelSet{
+			{model.AddressLabel: "10.0.0.1:8080"},
+			// the labels of the target take precedence over the labels of its group.
+			{model.AddressLabel: "10.0.0.2:8080", "__meta_kubernetes_namespace": "default"},
+		},
+	}
+	in <- map[string][]*targetgroup.Group{"job": {group, nil}}
+
+	select {
+	case tsets := <-out:
+		require.Len(t, tsets["job"], 1)
+		assert.Equal(t, "pods", tsets["job"][0].Source)
+		assert.Equal(t, []model.LabelSet{{model.AddressLabel: "10.0.0.2:8080", "__meta_kubernetes_namespace": "default"}}, tsets["job"][0].Targets)
+		// the discovered group is left untouched.
+		assert.Len(t, group.Targets, 2)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the target groups were not forwarded")
+	}
+
+	// a group left without targets is still forwarded.
+	in <- map[string][]*targetgroup.Group{"job": {{Source: "pods", Labels: group.Labels, Targets: group.Targets[:1]}}}
+	select {
+	case tsets := <-out:
+		require.Len(t, tsets["job"], 1)
+		assert.Empty(t, tsets["job"][0].Targets)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the target groups were not forwarded")
+	}
+}
@@ -26,6 +26,7 @@ import (
 	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/pkg/value"
 	"github.com/prometheus/prometheus/storage"
 	"go.uber.org/zap"
 	"google.golang.org/protobuf/types/known/timestamppb"
@@ -55,35 +56,46 @@ var errNoStartTimeMetrics = errors.New("process_start_time_seconds metric is mis
 // will be flush to the downstream consumer, or Rollback, which means discard all the data, is called and all data
 // points are discarded.
 type transaction struct {
-	id                   int64
-	ctx                  context.Context
-	isNew                bool
-	sink                 consumer.MetricsConsumer
-	job                  string
-	instance             string
-	jobsMap              *JobsMap
-	useStartTimeMetric   bool
-	startTimeMetricRegex string
-	receiverName         string
-	ms                   *metadataService
-	node                 *commonpb.Node
-	resource             *resourcepb.Resource
-	metricBuilder        *metricBuilder
-	logger               *zap.Logger
+	id                    int64
+	ctx                   context.Context
+	isNew                 bool
+	sink                  consumer.MetricsConsumer
+	job                   string
+	instance              string
+	jobsMap               *JobsMap
+	useStartTimeMetric    bool
+	startTimeMetricRegex  string
+	receiverName          string
+	reportStaleness       StalenessMode
+	staleSeries           []labels.Labels
+	resourceLabels        map[string][]string
+	maxTimeseriesPerBatch int
+	flushed               bool
+	seriesLimits          map[string]*SeriesLimits
+	limiter               *seriesLimiter
+	ms                    *metadataService
+	node                  *commonpb.Node
+	resource              *resourcepb.Resource
+	metricBuilder         *metricBuilder
+	logger                *zap.Logger
 }
 
-func newTransaction(ctx context.Context, jobsMap *JobsMap, useStartTimeMetric bool, startTimeMetricRegex string, receiverName string, ms *metadataService, sink consumer.MetricsConsumer, logger *zap.Logger) *transaction {
+func newTransaction(ctx context.Context, jobsMap *JobsMap, useStartTimeMetric bool, startTimeMetricRegex string, receiverName string, reportStaleness StalenessMode, resourceLabels map[string][]string, maxTimeseriesPerBatch int, seriesLimits map[string]*SeriesLimits, ms *metadataService, sink consumer.MetricsConsumer, logger *zap.Logger) *transaction {
 	return &transaction{
-		id:                   atomic.AddInt64(&idSeq, 1),
-		ctx:                  ctx,
-		isNew:                true,
-		sink:                 sink,
-		jobsMap:              jobsMap,
-		useStartTimeMetric:   useStartTimeMetric,
-		startTimeMetricRegex: startTimeMetricRegex,
-		receiverName:         receiverName,
-		ms:                   ms,
-		logger:               logger,
+		id:                    atomic.AddInt64(&idSeq, 1),
+		ctx:                   ctx,
+		isNew:                 true,
+		sink:                  sink,
+		jobsMap:               jobsMap,
+		useStartTimeMetric:    useStartTimeMetric,
+		startTimeMetricRegex:  startTimeMetricRegex,
+		receiverName:          receiverName,
+		reportStaleness:       reportStaleness,
+		resourceLabels:        resourceLabels,
+		maxTimeseriesPerBatch: maxTimeseriesPerBatch,
+		seriesLimits:          seriesLimits,
+		ms:                    ms,
+		logger:                logger,
 	}
 }
 
@@ -96,7 +108,9 @@ func (tr *transaction) Add(ls labels.Labels, t int64, v float64) (uint64, error)
 	// scrape the remote target,  if the previous scrape was success and some data were cached internally
 	// in our case, we don't need these data, simply drop them shall be good enough. more details:
 	// https://github.com/prometheus/prometheus/blob/851131b0740be7291b98f295567a97f32fffc655/scrape/scrape.go#L933-L935
-	if math.IsNaN(v) {
+	// These data are staleness markers, which are kept when asked to.
+	isStale := value.IsStaleNaN(v)
+	if math.IsNaN(v) && (!isStale || tr.reportStaleness == StalenessDrop || tr.reportStaleness == "") {
 		return 0, nil
 	}
 
@@ -108,10 +122,49 @@ func (tr *transaction) Add(ls labels.Labels, t int64, v float64) (uint64, error)
 
 	if tr.isNew {
 		if err := tr.initTransaction(ls); err != nil {
+			if isStale && tr.reportStaleness == StalenessGap && tr.jobsMap != nil {
+				// the target is gone, so are all of its series.
+				tr.jobsMap.remove(ls.Get(model.JobLabel), ls.Get(model.InstanceLabel))
+				return 0, nil
+			}
 			return 0, err
 		}
 	}
-	return 0, tr.metricBuilder.AddDataPoint(ls, t, v)
+	metricName := ls.Get(model.MetricNameLabel)
+	if tr.limiter != nil && !isInternalMetric(metricName) {
+		var ok bool
+		if ls, ok = tr.limiter.apply(ls); !ok {
+			return 0, nil
+		}
+	}
+	switch {
+	case isInternalMetric(metricName) && isStale:
+		// internal metrics are never forwarded, neither are their staleness markers.
+		return 0, nil
+	case isInternalMetric(metricName):
+		recordScrapeReport(tr.ctx, tr.receiverName, ls.Get(model.JobLabel), metricName, v)
+	case isStale && tr.reportStaleness == StalenessGap:
+		tr.staleSeries = append(tr.staleSeries, ls)
+		return 0, nil
+	}
+	if err := tr.metricBuilder.AddDataPoint(ls, t, v); err != nil {
+		return 0, err
+	}
+	return 0, tr.maybeFlush()
+}
+
+// maybeFlush forwards the metrics built so far, without waiting for Commit, once they hold
+// enough timeseries so that the memory used by a transaction stays bounded on large pages.
+func (tr *transaction) maybeFlush() error {
+	if !tr.metricBuilder.IsBatchFull() {
+		return nil
+	}
+	if tr.useStartTimeMetric && tr.metricBuilder.startTime == 0.0 {
+		// the start time metric has not been added yet, keep the metrics until it is.
+		return nil
+	}
+	tr.flushed = true
+	return tr.consume(obsreport.StartMetricsReceiveOp(tr.ctx, tr.receiverName, transport), tr.metricBuilder.Flush())
 }
 
 // always returns error since caching is not supported by Add() function
@@ -129,12 +182,12 @@ func (tr *transaction) initTransaction(ls labels.Labels) error {
 	if err != nil {
 		return err
 	}
-	if tr.jobsMap != nil {
-		tr.job = job
-		tr.instance = instance
-	}
+	tr.job = job
+	tr.instance = instance
 	tr.node, tr.resource = createNodeAndResource(job, instance, mc.SharedLabels().Get(model.SchemeLabel))
-	tr.metricBuilder = newMetricBuilder(mc, tr.useStartTimeMetric, tr.startTimeMetricRegex, tr.logger)
+	addKubernetesResourceLabels(tr.resource, mc.SharedLabels())
+	tr.limiter = newSeriesLimiter(tr.seriesLimits[job])
+	tr.metricBuilder = newMetricBuilder(mc, tr.useStartTimeMetric, tr.startTimeMetricRegex, tr.maxTimeseriesPerBatch, tr.logger)
 	tr.isNew = false
 	return nil
 }
@@ -147,21 +200,47 @@ func (tr *transaction) Commit() error {
 		return nil
 	}
 
+	if tr.limiter != nil {
+		tr.reportLimitedSeries()
+	}
+
+	if len(tr.staleSeries) > 0 && tr.jobsMap != nil {
+		tsm := tr.jobsMap.get(tr.job, tr.instance)
+		for _, ls := range tr.staleSeries {
+			tsm.remove(timeseriesSignature(ls.Get(model.MetricNameLabel), ls, tr.metricBuilder.mc))
+		}
+	}
+
 	ctx := obsreport.StartMetricsReceiveOp(tr.ctx, tr.receiverName, transport)
 	metrics, _, _, err := tr.metricBuilder.Build()
+	if err == errNoDataToBuild && len(tr.staleSeries) > 0 {
+		// the page only held staleness markers, which have already been handled.
+		obsreport.EndMetricsReceiveOp(ctx, dataformat, 0, nil)
+		return nil
+	}
 	if err != nil {
 		// Only error by Build() is errNoDataToBuild, with numReceivedPoints set to zero.
 		obsreport.EndMetricsReceiveOp(ctx, dataformat, 0, err)
 		return err
 	}
+	if len(metrics) == 0 && tr.flushed {
+		// all the metrics of the page have already been forwarded.
+		obsreport.EndMetricsReceiveOp(ctx, dataformat, 0, nil)
+		return nil
+	}
+	return tr.consume(ctx, metrics)
+}
 
+// consume adjusts the given metrics and forwards them to the sink, ending the receive
+// operation started with ctx.
+func (tr *transaction) consume(ctx context.Context, metrics []*metricspb.Metric) error {
 	if tr.useStartTimeMetric {
 		// startTime is mandatory in this case, but may be zero when the
 		// process_start_time_seconds metric is missing from the target endpoint.
 		if tr.metricBuilder.startTime == 0.0 {
 			// Since we are unable to adjust metrics properly, we will drop them
 			// and return an error.
-			err = errNoStartTimeMetrics
+			err := errNoStartTimeMetrics
 			obsreport.EndMetricsReceiveOp(ctx, dataformat, 0, err)
 			return err
 		}
@@ -170,16 +249,13 @@ func (tr *transaction) Commit() error {
 	} else {
 		// AdjustMetrics - jobsMap has to be non-nil in this case.
 		// Note: metrics could be empty after adjustment, which needs to be checked before passing it on to ConsumeMetrics()
-		metrics, _ = NewMetricsAdjuster(tr.jobsMap.get(tr.job, tr.instance), tr.logger).AdjustMetrics(metrics)
+		metrics, _ = NewMetricsAdjuster(tr.jobsMap.get(tr.job, tr.instance), tr.jobsMap.usesScrapeTimestamps(tr.job), tr.logger).AdjustMetrics(metrics)
 	}
 
+	var err error
 	numPoints := 0
 	if len(metrics) > 0 {
-		md := internaldata.OCToMetrics(internaldata.MetricsData{
-			Node:     tr.node,
-			Resource: tr.resource,
-			Metrics:  metrics,
-		})
+		md := internaldata.OCSliceToMetrics(promoteResourceLabels(tr.node, tr.resource, metrics, tr.resourceLabels[tr.job]))
 		_, numPoints = md.MetricAndDataPointCount()
 		err = tr.sink.ConsumeMetrics(ctx, md)
 	}
@@ -187,6 +263,23 @@ func (tr *transaction) Commit() error {
 	return err
 }
 
+// reportLimitedSeries reports the series dropped because of the series limits of the job as
+// refused metric points, in a receive operation of their own, and counts the truncated label
+// values.
+func (tr *transaction) reportLimitedSeries() {
+	if dropped := tr.limiter.numDropped(); dropped > 0 {
+		ctx := obsreport.StartMetricsReceiveOp(tr.ctx, tr.receiverName, transport)
+		obsreport.EndMetricsReceiveOp(ctx, dataformat, dropped, errSeriesLimitExceeded)
+		tr.logger.Warn("Dropped series exceeding the series limits",
+			zap.String("job", tr.job), zap.String("instance", tr.instance), zap.Int("dropped_series", dropped))
+	}
+	if tr.limiter.truncated > 0 {
+		recordTruncatedLabelValues(tr.ctx, tr.receiverName, tr.job, tr.limiter.truncated)
+		tr.logger.Debug("Truncated label values exceeding the series limits",
+			zap.String("job", tr.job), zap.String("instance", tr.instance), zap.Int("truncated_values", tr.limiter.truncated))
+	}
+}
+
 func (tr *transaction) Rollback() error {
 	return nil
 }
@@ -17,6 +17,7 @@ package internal
 import (
 	"context"
 	"math"
+	"strconv"
 	"testing"
 	"time"
 
@@ -63,7 +64,7 @@ func Test_transaction(t *testing.T) {
 
 	t.Run("Commit Without Adding", func(t *testing.T) {
 		nomc := consumertest.NewMetricsNop()
-		tr := newTransaction(context.Background(), nil, true, "", rn, ms, nomc, testLogger)
+		tr := newTransaction(context.Background(), nil, true, "", rn, StalenessDrop, nil, 0, nil, ms, nomc, testLogger)
 		if got := tr.Commit(); got != nil {
 			t.Errorf("expecting nil from Commit() but got err %v", got)
 		}
@@ -71,7 +72,7 @@ func Test_transaction(t *testing.T) {
 
 	t.Run("Rollback dose nothing", func(t *testing.T) {
 		nomc := consumertest.NewMetricsNop()
-		tr := newTransaction(context.Background(), nil, true, "", rn, ms, nomc, testLogger)
+		tr := newTransaction(context.Background(), nil, true, "", rn, StalenessDrop, nil, 0, nil, ms, nomc, testLogger)
 		if got := tr.Rollback(); got != nil {
 			t.Errorf("expecting nil from Rollback() but got err %v", got)
 		}
@@ -80,7 +81,7 @@ func Test_transaction(t *testing.T) {
 	badLabels := labels.Labels([]labels.Label{{Name: "foo", Value: "bar"}})
 	t.Run("Add One No Target", func(t *testing.T) {
 		nomc := consumertest.NewMetricsNop()
-		tr := newTransaction(context.Background(), nil, true, "", rn, ms, nomc, testLogger)
+		tr := newTransaction(context.Background(), nil, true, "", rn, StalenessDrop, nil, 0, nil, ms, nomc, testLogger)
 		if _, got := tr.Add(badLabels, time.Now().Unix()*1000, 1.0); got == nil {
 			t.Errorf("expecting error from Add() but got nil")
 		}
@@ -92,7 +93,7 @@ func Test_transaction(t *testing.T) {
 		{Name: "foo", Value: "bar"}})
 	t.Run("Add One Job not found", func(t *testing.T) {
 		nomc := consumertest.NewMetricsNop()
-		tr := newTransaction(context.Background(), nil, true, "", rn, ms, nomc, testLogger)
+		tr := newTransaction(context.Background(), nil, true, "", rn, StalenessDrop, nil, 0, nil, ms, nomc, testLogger)
 		if _, got := tr.Add(jobNotFoundLb, time.Now().Unix()*1000, 1.0); got == nil {
 			t.Errorf("expecting error from Add() but got nil")
 		}
@@ -103,7 +104,7 @@ func Test_transaction(t *testing.T) {
 		{Name: "__name__", Value: "foo"}})
 	t.Run("Add One Good", func(t *testing.T) {
 		sink := new(consumertest.MetricsSink)
-		tr := newTransaction(context.Background(), nil, true, "", rn, ms, sink, testLogger)
+		tr := newTransaction(context.Background(), nil, true, "", rn, StalenessDrop, nil, 0, nil, ms, sink, testLogger)
 		if _, got := tr.Add(goodLabels, time.Now().Unix()*1000, 1.0); got != nil {
 			t.Errorf("expecting error == nil from Add() but got: %v\n", got)
 		}
@@ -133,7 +134,7 @@ func Test_transaction(t *testing.T) {
 
 	t.Run("Error when start time is zero", func(t *testing.T) {
 		sink := new(consumertest.MetricsSink)
-		tr := newTransaction(context.Background(), nil, true, "", rn, ms, sink, testLogger)
+		tr := newTransaction(context.Background(), nil, true, "", rn, StalenessDrop, nil, 0, nil, ms, sink, testLogger)
 		if _, got := tr.Add(goodLabels, time.Now().Unix()*1000, 1.0); got != nil {
 			t.Errorf("expecting error == nil from Add() but got: %v\n", got)
 		}
@@ -148,7 +149,7 @@ func Test_transaction(t *testing.T) {
 
 	t.Run("Drop NaN value", func(t *testing.T) {
 		sink := new(consumertest.MetricsSink)
-		tr := newTransaction(context.Background(), nil, true, "", rn, ms, sink, testLogger)
+		tr := newTransaction(context.Background(), nil, true, "", rn, StalenessDrop, nil, 0, nil, ms, sink, testLogger)
 		if _, got := tr.Add(goodLabels, time.Now().Unix()*1000, math.NaN()); got != nil {
 			t.Errorf("expecting error == nil from Add() but got: %v\n", got)
 		}
@@ -161,3 +162,49 @@ func Test_transaction(t *testing.T) {
 	})
 
 }
+
+func TestTransactionFlushesBatches(t *testing.T) {
+	sink := new(consumertest.MetricsSink)
+	tr := newTransaction(context.Background(), NewJobsMap(time.Minute), false, "", "prometheus", StalenessDrop, nil, 2, nil, newStalenessTestMetadataService(), sink, testLogger)
+	for i := 0; i < 5; i++ {
+		ls := labels.FromStrings(model.InstanceLabel, "localhost:8080", model.JobLabel, "test", model.MetricNameLabel, "gauge_test", "foo", strconv.Itoa(i))
+		if _, err := tr.Add(ls, startTs, float64(i)); err != nil {
+			t.Fatalf("expecting error == nil from Add() but got: %v\n", err)
+		}
+	}
+	// the first batches are forwarded while the page is added.
+	if got := sink.MetricsCount(); got != 2 {
+		t.Errorf("expecting 2 batches before Commit() but got %d", got)
+	}
+	if err := tr.Commit(); err != nil {
+		t.Fatalf("expecting nil from Commit() but got err %v", err)
+	}
+	if got := sink.MetricsCount(); got != 3 {
+		t.Errorf("expecting 3 batches after Commit() but got %d", got)
+	}
+	numPoints := 0
+	for _, md := range sink.AllMetrics() {
+		_, n := md.MetricAndDataPointCount()
+		numPoints += n
+	}
+	if numPoints != 5 {
+		t.Errorf("expecting 5 data points but got %d", numPoints)
+	}
+}
+
+func TestTransactionFlushesCompleteFamilies(t *testing.T) {
+	sink := new(consumertest.MetricsSink)
+	tr := newTransaction(context.Background(), NewJobsMap(time.Minute), false, "", "prometheus", StalenessDrop, nil, 1, nil, newStalenessTestMetadataService(), sink, testLogger)
+	for _, name := range []string{"gauge_test", "gauge_test2"} {
+		ls := labels.FromStrings(model.InstanceLabel, "localhost:8080", model.JobLabel, "test", model.MetricNameLabel, name)
+		if _, err := tr.Add(ls, startTs, 1); err != nil {
+			t.Fatalf("expecting error == nil from Add() but got: %v\n", err)
+		}
+	}
+	if err := tr.Commit(); err != nil {
+		t.Fatalf("expecting nil from Commit() but got err %v", err)
+	}
+	if got := sink.MetricsCount(); got != 2 {
+		t.Errorf("expecting 2 batches but got %d", got)
+	}
+}
@@ -16,8 +16,15 @@ package prometheusreceiver
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
 	"time"
 
+	"github.com/prometheus/prometheus/config"
 	"github.com/prometheus/prometheus/discovery"
 	"github.com/prometheus/prometheus/scrape"
 	"go.uber.org/zap"
@@ -33,6 +40,13 @@ type pReceiver struct {
 	consumer   consumer.MetricsConsumer
 	cancelFunc context.CancelFunc
 
+	// mu serializes configuration changes applied to the managers below.
+	mu               sync.Mutex
+	discoveryManager *discovery.Manager
+	scrapeManager    *scrape.Manager
+	jobsMap          *internal.JobsMap
+	pushServer       *http.Server
+
 	logger *zap.Logger
 }
 
@@ -49,48 +63,163 @@ func newPrometheusReceiver(logger *zap.Logger, cfg *Config, next consumer.Metric
 // Start is the method that starts Prometheus scraping and it
 // is controlled by having previously defined a Configuration using perhaps New.
 func (r *pReceiver) Start(ctx context.Context, host component.Host) error {
+	resourceLabels, err := r.cfg.resourceLabelsByJob()
+	if err != nil {
+		return err
+	}
+	seriesLimits, err := r.cfg.seriesLimitsByJob()
+	if err != nil {
+		return err
+	}
+	targetFilter, err := r.cfg.targetFilter()
+	if err != nil {
+		return err
+	}
+
 	discoveryCtx, cancel := context.WithCancel(context.Background())
 	r.cancelFunc = cancel
 
 	logger := internal.NewZapToGokitLogAdapter(r.logger)
 
-	discoveryManager := discovery.NewManager(discoveryCtx, logger)
-	discoveryCfg := make(map[string]discovery.Configs)
-	for _, scrapeConfig := range r.cfg.PrometheusConfig.ScrapeConfigs {
-		discoveryCfg[scrapeConfig.JobName] = scrapeConfig.ServiceDiscoveryConfigs
+	r.discoveryManager = discovery.NewManager(discoveryCtx, internal.NewFileSDErrorsLogger(ctx, logger, r.cfg.Name()))
+
+	if !r.cfg.UseStartTimeMetric {
+		r.jobsMap = internal.NewJobsMap(2 * time.Minute)
 	}
-	if err := discoveryManager.ApplyConfig(discoveryCfg); err != nil {
+	ocaStore := internal.NewOcaStore(ctx, r.consumer, r.logger, r.jobsMap, r.cfg.UseStartTimeMetric, r.cfg.StartTimeMetricRegex, r.cfg.Name(), r.cfg.ReportStaleness, resourceLabels, r.cfg.MaxTimeseriesPerBatch, seriesLimits)
+
+	r.scrapeManager = scrape.NewManager(internal.NewTargetSyncErrorsLogger(ctx, logger, r.cfg.Name()), ocaStore)
+	ocaStore.SetScrapeManager(r.scrapeManager)
+
+	if err := r.applyConfig(r.cfg.PrometheusConfig); err != nil {
 		return err
 	}
+
+	if r.cfg.Push != nil {
+		pushServer, err := r.cfg.Push.ToServer(ocaStore.PushHandler())
+		if err != nil {
+			return fmt.Errorf("prometheus receiver failed to set up the push server: %w", err)
+		}
+		listener, err := r.cfg.Push.ToListener()
+		if err != nil {
+			return fmt.Errorf("prometheus receiver failed to listen for pushes on %q: %w", r.cfg.Push.Endpoint, err)
+		}
+		r.pushServer = pushServer
+		go func() {
+			if err := r.pushServer.Serve(listener); err != http.ErrServerClosed {
+				r.logger.Error("Push server failed", zap.Error(err))
+				host.ReportFatalError(err)
+			}
+		}()
+	}
+
 	go func() {
-		if err := discoveryManager.Run(); err != nil {
+		if err := r.discoveryManager.Run(); err != nil {
 			r.logger.Error("Discovery manager failed", zap.Error(err))
 			host.ReportFatalError(err)
 		}
 	}()
-
-	var jobsMap *internal.JobsMap
-	if !r.cfg.UseStartTimeMetric {
-		jobsMap = internal.NewJobsMap(2 * time.Minute)
-	}
-	ocaStore := internal.NewOcaStore(ctx, r.consumer, r.logger, jobsMap, r.cfg.UseStartTimeMetric, r.cfg.StartTimeMetricRegex, r.cfg.Name())
-
-	scrapeManager := scrape.NewManager(logger, ocaStore)
-	ocaStore.SetScrapeManager(scrapeManager)
-	if err := scrapeManager.ApplyConfig(r.cfg.PrometheusConfig); err != nil {
-		return err
+	syncCh := r.discoveryManager.SyncCh()
+	if targetFilter != nil {
+		syncCh = internal.FilterTargets(discoveryCtx, syncCh, targetFilter.Keep)
 	}
 	go func() {
-		if err := scrapeManager.Run(discoveryManager.SyncCh()); err != nil {
+		if err := r.scrapeManager.Run(syncCh); err != nil {
 			r.logger.Error("Scrape manager failed", zap.Error(err))
 			host.ReportFatalError(err)
 		}
 	}()
+
+	if r.cfg.ConfigFile != "" {
+		go r.reloadOnSignal(discoveryCtx)
+	}
 	return nil
 }
 
-// Shutdown stops and cancels the underlying Prometheus scrapers.
+// applyConfig applies the given Prometheus configuration to the running discovery and
+// scrape managers. Targets and jobs that are unchanged by the new configuration keep
+// their state, only the differences are started or stopped.
+func (r *pReceiver) applyConfig(promCfg *config.Config) error {
+	promCfg, err := r.cfg.withFederation(promCfg)
+	if err != nil {
+		return err
+	}
+	if promCfg == nil || len(promCfg.ScrapeConfigs) == 0 {
+		if r.cfg.Push == nil {
+			return errNilScrapeConfig
+		}
+		// only pushes are received, the managers are run without any scrape job.
+		promCfg = &config.Config{GlobalConfig: config.DefaultGlobalConfig}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.scrapeManager.ApplyConfig(promCfg); err != nil {
+		return err
+	}
+	if r.jobsMap != nil {
+		r.jobsMap.SetScrapeTimestampJobs(scrapeTimestampJobs(promCfg))
+	}
+
+	discoveryCfg := make(map[string]discovery.Configs)
+	for _, scrapeConfig := range promCfg.ScrapeConfigs {
+		discoveryCfg[scrapeConfig.JobName] = scrapeConfig.ServiceDiscoveryConfigs
+	}
+	return r.discoveryManager.ApplyConfig(discoveryCfg)
+}
+
+// scrapeTimestampJobs returns the scrape jobs configured with honor_timestamps false.
+func scrapeTimestampJobs(promCfg *config.Config) map[string]bool {
+	var jobs map[string]bool
+	for _, scrapeConfig := range promCfg.ScrapeConfigs {
+		if scrapeConfig.HonorTimestamps {
+			continue
+		}
+		if jobs == nil {
+			jobs = make(map[string]bool)
+		}
+		jobs[scrapeConfig.JobName] = true
+	}
+	return jobs
+}
+
+// reloadConfigFile reads the Prometheus configuration file again and applies it.
+func (r *pReceiver) reloadConfigFile() error {
+	promCfg, err := config.LoadFile(r.cfg.ConfigFile)
+	if err != nil {
+		return fmt.Errorf("prometheus receiver failed to load config file %q: %w", r.cfg.ConfigFile, err)
+	}
+	return r.applyConfig(promCfg)
+}
+
+// reloadOnSignal reloads the Prometheus configuration file every time a SIGHUP is
+// received, until the given context is done. A failed reload keeps the previously
+// applied configuration running.
+func (r *pReceiver) reloadOnSignal(ctx context.Context) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	for {
+		select {
+		case <-hup:
+			if err := r.reloadConfigFile(); err != nil {
+				r.logger.Error("Failed to reload Prometheus configuration", zap.Error(err))
+				continue
+			}
+			r.logger.Info("Reloaded Prometheus configuration", zap.String("config_file", r.cfg.ConfigFile))
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Shutdown stops and cancels the underlying Prometheus scrapers, and the push server.
 func (r *pReceiver) Shutdown(context.Context) error {
 	r.cancelFunc()
+	if r.pushServer != nil {
+		return r.pushServer.Close()
+	}
 	return nil
 }
@@ -17,11 +17,14 @@ package prometheusreceiver
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
 	"log"
 	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -1180,3 +1183,82 @@ func testEndToEndRegex(t *testing.T, targets []*testData, useStartTimeMetric boo
 		target.validateFunc(t, target, results[target.name])
 	}
 }
+
+// TestConfigFileReload validates that scrape configs added to the config file are applied
+// on reload without restarting the receiver.
+func TestConfigFileReload(t *testing.T) {
+	targets := []*testData{
+		{
+			name: "target1",
+			pages: []mockPrometheusResponse{
+				{code: 200, data: startTimeMetricPage},
+			},
+			validateFunc: verifyStartTimeMetricPage,
+		},
+		{
+			name: "target2",
+			pages: []mockPrometheusResponse{
+				{code: 200, data: startTimeMetricPage},
+			},
+			validateFunc: verifyStartTimeMetricPage,
+		},
+	}
+	mp, cfg, err := setupMockPrometheus(targets...)
+	require.Nilf(t, err, "Failed to create Promtheus config: %v", err)
+	defer mp.Close()
+
+	dir, err := ioutil.TempDir("", "prometheusreceiver")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	configFile := filepath.Join(dir, "prometheus.yaml")
+
+	// Start scraping only the first target.
+	allScrapeConfigs := cfg.ScrapeConfigs
+	cfg.ScrapeConfigs = allScrapeConfigs[:1]
+	require.NoError(t, ioutil.WriteFile(configFile, []byte(cfg.String()), 0600))
+	initialCfg, err := promcfg.LoadFile(configFile)
+	require.NoError(t, err)
+
+	cms := new(consumertest.MetricsSink)
+	rcvr := newPrometheusReceiver(logger, &Config{PrometheusConfig: initialCfg, ConfigFile: configFile, UseStartTimeMetric: true}, cms)
+	require.NoError(t, rcvr.Start(context.Background(), componenttest.NewNopHost()))
+	defer rcvr.Shutdown(context.Background())
+
+	// A broken config file must not affect the running configuration.
+	require.NoError(t, ioutil.WriteFile(configFile, []byte("scrape_configs: ["), 0600))
+	require.Error(t, rcvr.reloadConfigFile())
+
+	// Add the second target and reload.
+	cfg.ScrapeConfigs = allScrapeConfigs
+	require.NoError(t, ioutil.WriteFile(configFile, []byte(cfg.String()), 0600))
+	require.NoError(t, rcvr.reloadConfigFile())
+
+	// wait for both targets to be scraped
+	mp.wg.Wait()
+
+	results := make(map[string][]internaldata.MetricsData)
+	for _, m := range cms.AllMetrics() {
+		for _, ocmd := range internaldata.MetricsToOC(m) {
+			results[ocmd.Node.ServiceInfo.Name] = append(results[ocmd.Node.ServiceInfo.Name], ocmd)
+		}
+	}
+	assert.Len(t, results, len(targets))
+	for _, target := range targets {
+		target.validateFunc(t, target, results[target.name])
+	}
+}
+
+func TestApplyConfigRequiresScrapeConfigs(t *testing.T) {
+	rcvr := newPrometheusReceiver(logger, &Config{}, new(consumertest.MetricsSink))
+	assert.Equal(t, errNilScrapeConfig, rcvr.applyConfig(nil))
+	assert.Equal(t, errNilScrapeConfig, rcvr.applyConfig(&promcfg.Config{}))
+}
+
+func TestScrapeTimestampJobs(t *testing.T) {
+	promCfg := &promcfg.Config{ScrapeConfigs: []*promcfg.ScrapeConfig{
+		{JobName: "honored", HonorTimestamps: true},
+		{JobName: "scraped", HonorTimestamps: false},
+	}}
+	assert.Equal(t, map[string]bool{"scraped": true}, scrapeTimestampJobs(promCfg))
+	assert.Nil(t, scrapeTimestampJobs(&promcfg.Config{ScrapeConfigs: promCfg.ScrapeConfigs[:1]}))
+}
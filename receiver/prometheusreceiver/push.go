@@ -0,0 +1,37 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheusreceiver
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/collector/config/confighttp"
+)
+
+var errNoPushEndpoint = errors.New("push requires an \"endpoint\"")
+
+// PushConfig defines the HTTP endpoint accepting the metrics pushed by batch jobs with the
+// Pushgateway API, so that they can push to the collector instead of a Pushgateway.
+type PushConfig struct {
+	// Configures the HTTP server the metrics are pushed to, e.g. "0.0.0.0:9091".
+	confighttp.HTTPServerSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct
+}
+
+func (pc *PushConfig) validate() error {
+	if pc.Endpoint == "" {
+		return errNoPushEndpoint
+	}
+	return nil
+}
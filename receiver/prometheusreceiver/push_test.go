@@ -0,0 +1,59 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheusreceiver
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/testutil"
+	"go.opentelemetry.io/collector/translator/internaldata"
+)
+
+func TestPushEndToEnd(t *testing.T) {
+	endpoint := testutil.GetAvailableLocalAddress(t)
+	cfg := &Config{
+		Push: &PushConfig{HTTPServerSettings: confighttp.HTTPServerSettings{Endpoint: endpoint}},
+	}
+	cms := new(consumertest.MetricsSink)
+	rcvr := newPrometheusReceiver(logger, cfg, cms)
+	require.NoError(t, rcvr.Start(context.Background(), componenttest.NewNopHost()))
+	defer rcvr.Shutdown(context.Background())
+
+	body := "# TYPE batch_duration_seconds gauge\nbatch_duration_seconds 12.5\n"
+	req, err := http.NewRequest(http.MethodPut, "http://"+endpoint+"/metrics/job/batch/instance/worker-1", strings.NewReader(body))
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	require.Len(t, cms.AllMetrics(), 1)
+	ocmds := internaldata.MetricsToOC(cms.AllMetrics()[0])
+	require.Len(t, ocmds, 1)
+	assert.Equal(t, "batch", ocmds[0].Node.ServiceInfo.Name)
+	assert.Equal(t, "worker-1", ocmds[0].Node.Identifier.HostName)
+	require.Len(t, ocmds[0].Metrics, 1)
+	assert.Equal(t, "batch_duration_seconds", ocmds[0].Metrics[0].MetricDescriptor.Name)
+	assert.Equal(t, 12.5, ocmds[0].Metrics[0].Timeseries[0].Points[0].GetDoubleValue())
+}
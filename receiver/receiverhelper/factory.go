@@ -103,6 +103,19 @@ func (f *factory) Type() configmodels.Type {
 	return f.cfgType
 }
 
+// SupportsDataType returns true if the factory creates the receivers of the data type.
+func (f *factory) SupportsDataType(dataType configmodels.DataType) bool {
+	switch dataType {
+	case configmodels.TracesDataType:
+		return f.createTraceReceiver != nil
+	case configmodels.MetricsDataType:
+		return f.createMetricsReceiver != nil
+	case configmodels.LogsDataType:
+		return f.createLogsReceiver != nil
+	}
+	return false
+}
+
 // CreateDefaultConfig creates the default configuration for receiver.
 func (f *factory) CreateDefaultConfig() configmodels.Receiver {
 	return f.createDefaultConfig()
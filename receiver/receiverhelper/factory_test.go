@@ -42,6 +42,10 @@ func TestNewFactory(t *testing.T) {
 	assert.EqualValues(t, defaultCfg, factory.CreateDefaultConfig())
 	_, ok := factory.(component.ConfigUnmarshaler)
 	assert.False(t, ok)
+	ds := factory.(component.DataTypeSupporter)
+	assert.False(t, ds.SupportsDataType(configmodels.TracesDataType))
+	assert.False(t, ds.SupportsDataType(configmodels.MetricsDataType))
+	assert.False(t, ds.SupportsDataType(configmodels.LogsDataType))
 	_, err := factory.CreateTracesReceiver(context.Background(), component.ReceiverCreateParams{}, defaultCfg, nil)
 	assert.Error(t, err)
 	_, err = factory.CreateMetricsReceiver(context.Background(), component.ReceiverCreateParams{}, defaultCfg, nil)
@@ -65,6 +69,11 @@ func TestNewFactory_WithConstructors(t *testing.T) {
 	assert.True(t, ok)
 	assert.Equal(t, errors.New("my error"), fu.Unmarshal(nil, nil))
 
+	ds := factory.(component.DataTypeSupporter)
+	assert.True(t, ds.SupportsDataType(configmodels.TracesDataType))
+	assert.True(t, ds.SupportsDataType(configmodels.MetricsDataType))
+	assert.True(t, ds.SupportsDataType(configmodels.LogsDataType))
+
 	_, err := factory.CreateTracesReceiver(context.Background(), component.ReceiverCreateParams{}, defaultCfg, nil)
 	assert.NoError(t, err)
 
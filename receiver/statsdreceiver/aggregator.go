@@ -0,0 +1,262 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statsdreceiver
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// metricKey identifies the metric of a statsd line.
+type metricKey struct {
+	name       string
+	metricType string
+}
+
+// series holds the aggregated values of a metric and of a set of labels
+// during a flush interval.
+type series struct {
+	labels []label
+
+	// value is the sum of the counters and the value of the gauges.
+	value float64
+	// updated is set when the gauge was set during the flush interval, the
+	// gauges being kept between the flush intervals for the relative values.
+	updated bool
+
+	// count, sum and bucketCounts of the histograms, the values being
+	// weighted by the inverse of the sample rate.
+	count        float64
+	sum          float64
+	bucketCounts []float64
+
+	// members of the sets.
+	members map[string]struct{}
+}
+
+// aggregator aggregates the statsd metrics between the flushes: the counters
+// are summed, the last value of the gauges is kept, the timers, histograms
+// and distributions are aggregated into explicit bucket histograms and the
+// sets count their distinct members.
+type aggregator struct {
+	mu        sync.Mutex
+	bounds    []float64
+	startTime time.Time
+	metrics   map[metricKey]map[string]*series
+}
+
+func newAggregator(bounds []float64, startTime time.Time) *aggregator {
+	return &aggregator{
+		bounds:    bounds,
+		startTime: startTime,
+		metrics:   make(map[metricKey]map[string]*series),
+	}
+}
+
+func labelsKey(labels []label) string {
+	var b strings.Builder
+	for _, l := range labels {
+		b.WriteString(l.key)
+		b.WriteByte(0)
+		b.WriteString(l.value)
+		b.WriteByte(0)
+	}
+	return b.String()
+}
+
+// add aggregates the values of the statsd metric.
+func (a *aggregator) add(m *statsdMetric) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	key := metricKey{name: m.name, metricType: m.metricType}
+	allSeries, ok := a.metrics[key]
+	if !ok {
+		allSeries = make(map[string]*series)
+		a.metrics[key] = allSeries
+	}
+	lk := labelsKey(m.labels)
+	s, ok := allSeries[lk]
+	if !ok {
+		s = &series{labels: m.labels}
+		allSeries[lk] = s
+	}
+
+	for _, v := range m.values {
+		switch m.metricType {
+		case counterType:
+			s.value += v.number / m.sampleRate
+		case gaugeType:
+			if v.relative {
+				s.value += v.number
+			} else {
+				s.value = v.number
+			}
+			s.updated = true
+		case timerType, histogramType, distributionType:
+			if s.bucketCounts == nil {
+				s.bucketCounts = make([]float64, len(a.bounds)+1)
+			}
+			weight := 1 / m.sampleRate
+			s.count += weight
+			s.sum += v.number * weight
+			s.bucketCounts[sort.SearchFloat64s(a.bounds, v.number)] += weight
+		case setType:
+			if s.members == nil {
+				s.members = make(map[string]struct{})
+			}
+			s.members[v.member] = struct{}{}
+		}
+	}
+}
+
+// flush returns the metrics aggregated since the previous flush, and their
+// number of data points, and resets the aggregations. The gauges not set
+// since the previous flush are not reported.
+func (a *aggregator) flush(now time.Time) (pdata.Metrics, int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	md := pdata.NewMetrics()
+	rms := md.ResourceMetrics()
+	rms.Resize(1)
+	ilms := rms.At(0).InstrumentationLibraryMetrics()
+	ilms.Resize(1)
+	metrics := ilms.At(0).Metrics()
+
+	startTime := pdata.TimestampFromTime(a.startTime)
+	timestamp := pdata.TimestampFromTime(now)
+	numPoints := 0
+	for _, key := range a.sortedKeys() {
+		allSeries := a.metrics[key]
+		lks := make([]string, 0, len(allSeries))
+		for lk, s := range allSeries {
+			if key.metricType != gaugeType || s.updated {
+				lks = append(lks, lk)
+			}
+		}
+		if len(lks) == 0 {
+			continue
+		}
+		sort.Strings(lks)
+
+		metric := pdata.NewMetric()
+		metric.SetName(key.name)
+		switch key.metricType {
+		case counterType:
+			metric.SetDataType(pdata.MetricDataTypeDoubleSum)
+			sum := metric.DoubleSum()
+			sum.SetIsMonotonic(true)
+			sum.SetAggregationTemporality(pdata.AggregationTemporalityDelta)
+			dps := sum.DataPoints()
+			dps.Resize(len(lks))
+			for i, lk := range lks {
+				s := allSeries[lk]
+				fillLabels(dps.At(i).LabelsMap(), s.labels)
+				dps.At(i).SetStartTime(startTime)
+				dps.At(i).SetTimestamp(timestamp)
+				dps.At(i).SetValue(s.value)
+			}
+		case gaugeType:
+			metric.SetDataType(pdata.MetricDataTypeDoubleGauge)
+			dps := metric.DoubleGauge().DataPoints()
+			dps.Resize(len(lks))
+			for i, lk := range lks {
+				s := allSeries[lk]
+				fillLabels(dps.At(i).LabelsMap(), s.labels)
+				dps.At(i).SetTimestamp(timestamp)
+				dps.At(i).SetValue(s.value)
+			}
+		case timerType, histogramType, distributionType:
+			if key.metricType == timerType {
+				metric.SetUnit("ms")
+			}
+			metric.SetDataType(pdata.MetricDataTypeDoubleHistogram)
+			histogram := metric.DoubleHistogram()
+			histogram.SetAggregationTemporality(pdata.AggregationTemporalityDelta)
+			dps := histogram.DataPoints()
+			dps.Resize(len(lks))
+			for i, lk := range lks {
+				s := allSeries[lk]
+				fillLabels(dps.At(i).LabelsMap(), s.labels)
+				dps.At(i).SetStartTime(startTime)
+				dps.At(i).SetTimestamp(timestamp)
+				dps.At(i).SetCount(uint64(math.Round(s.count)))
+				dps.At(i).SetSum(s.sum)
+				bucketCounts := make([]uint64, len(s.bucketCounts))
+				for j, c := range s.bucketCounts {
+					bucketCounts[j] = uint64(math.Round(c))
+				}
+				dps.At(i).SetBucketCounts(bucketCounts)
+				dps.At(i).SetExplicitBounds(a.bounds)
+			}
+		case setType:
+			metric.SetDataType(pdata.MetricDataTypeIntGauge)
+			dps := metric.IntGauge().DataPoints()
+			dps.Resize(len(lks))
+			for i, lk := range lks {
+				s := allSeries[lk]
+				fillLabels(dps.At(i).LabelsMap(), s.labels)
+				dps.At(i).SetTimestamp(timestamp)
+				dps.At(i).SetValue(int64(len(s.members)))
+			}
+		}
+		metrics.Append(metric)
+		numPoints += len(lks)
+	}
+
+	a.reset(now)
+	return md, numPoints
+}
+
+func (a *aggregator) sortedKeys() []metricKey {
+	keys := make([]metricKey, 0, len(a.metrics))
+	for key := range a.metrics {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].name != keys[j].name {
+			return keys[i].name < keys[j].name
+		}
+		return keys[i].metricType < keys[j].metricType
+	})
+	return keys
+}
+
+// reset starts a new flush interval, only keeping the gauges.
+func (a *aggregator) reset(now time.Time) {
+	a.startTime = now
+	for key, allSeries := range a.metrics {
+		if key.metricType != gaugeType {
+			delete(a.metrics, key)
+			continue
+		}
+		for _, s := range allSeries {
+			s.updated = false
+		}
+	}
+}
+
+func fillLabels(labelsMap pdata.StringMap, labels []label) {
+	labelsMap.InitEmptyWithCapacity(len(labels))
+	for _, l := range labels {
+		labelsMap.Insert(l.key, l.value)
+	}
+}
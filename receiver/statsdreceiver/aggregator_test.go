@@ -0,0 +1,125 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statsdreceiver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func addLines(t *testing.T, a *aggregator, lines ...string) {
+	for _, line := range lines {
+		m, err := parseLine(line)
+		require.NoError(t, err)
+		a.add(m)
+	}
+}
+
+func labelsOf(labelsMap pdata.StringMap) map[string]string {
+	labels := make(map[string]string, labelsMap.Len())
+	labelsMap.ForEach(func(k, v string) {
+		labels[k] = v
+	})
+	return labels
+}
+
+func TestAggregatorFlush(t *testing.T) {
+	start := time.Unix(1000, 0)
+	now := start.Add(time.Minute)
+	a := newAggregator([]float64{10, 100}, start)
+	addLines(t, a,
+		"page.views:1|c|#env:prod",
+		"page.views:2|c|@0.5|#env:prod",
+		"page.views:1|c|#env:dev",
+		"queue.size:10|g",
+		"queue.size:-3|g",
+		"request.latency:5:50|ms",
+		"request.latency:100:500|ms|@0.5",
+		"users.unique:alice|s",
+		"users.unique:bob|s",
+		"users.unique:alice|s",
+	)
+
+	md, numPoints := a.flush(now)
+	assert.Equal(t, 5, numPoints)
+	metrics := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	require.Equal(t, 4, metrics.Len())
+
+	views := metrics.At(0)
+	assert.Equal(t, "page.views", views.Name())
+	require.Equal(t, pdata.MetricDataTypeDoubleSum, views.DataType())
+	assert.True(t, views.DoubleSum().IsMonotonic())
+	assert.Equal(t, pdata.AggregationTemporalityDelta, views.DoubleSum().AggregationTemporality())
+	viewsDps := views.DoubleSum().DataPoints()
+	require.Equal(t, 2, viewsDps.Len())
+	assert.Equal(t, map[string]string{"env": "dev"}, labelsOf(viewsDps.At(0).LabelsMap()))
+	assert.Equal(t, 1.0, viewsDps.At(0).Value())
+	assert.Equal(t, map[string]string{"env": "prod"}, labelsOf(viewsDps.At(1).LabelsMap()))
+	assert.Equal(t, 5.0, viewsDps.At(1).Value())
+	assert.Equal(t, pdata.TimestampFromTime(start), viewsDps.At(1).StartTime())
+	assert.Equal(t, pdata.TimestampFromTime(now), viewsDps.At(1).Timestamp())
+
+	size := metrics.At(1)
+	assert.Equal(t, "queue.size", size.Name())
+	require.Equal(t, pdata.MetricDataTypeDoubleGauge, size.DataType())
+	require.Equal(t, 1, size.DoubleGauge().DataPoints().Len())
+	assert.Equal(t, 7.0, size.DoubleGauge().DataPoints().At(0).Value())
+
+	latency := metrics.At(2)
+	assert.Equal(t, "request.latency", latency.Name())
+	assert.Equal(t, "ms", latency.Unit())
+	require.Equal(t, pdata.MetricDataTypeDoubleHistogram, latency.DataType())
+	assert.Equal(t, pdata.AggregationTemporalityDelta, latency.DoubleHistogram().AggregationTemporality())
+	require.Equal(t, 1, latency.DoubleHistogram().DataPoints().Len())
+	latencyDp := latency.DoubleHistogram().DataPoints().At(0)
+	assert.Equal(t, uint64(6), latencyDp.Count())
+	assert.Equal(t, 1255.0, latencyDp.Sum())
+	assert.Equal(t, []float64{10, 100}, latencyDp.ExplicitBounds())
+	assert.Equal(t, []uint64{1, 3, 2}, latencyDp.BucketCounts())
+
+	users := metrics.At(3)
+	assert.Equal(t, "users.unique", users.Name())
+	require.Equal(t, pdata.MetricDataTypeIntGauge, users.DataType())
+	require.Equal(t, 1, users.IntGauge().DataPoints().Len())
+	assert.Equal(t, int64(2), users.IntGauge().DataPoints().At(0).Value())
+}
+
+func TestAggregatorFlushReset(t *testing.T) {
+	start := time.Unix(1000, 0)
+	a := newAggregator(defaultHistogramBuckets, start)
+	addLines(t, a, "page.views:1|c", "queue.size:10|g", "request.latency:5|ms", "users.unique:alice|s")
+	_, numPoints := a.flush(start.Add(time.Minute))
+	assert.Equal(t, 4, numPoints)
+
+	// Nothing was received since the previous flush.
+	_, numPoints = a.flush(start.Add(2 * time.Minute))
+	assert.Equal(t, 0, numPoints)
+
+	// The gauges are kept for the relative values.
+	addLines(t, a, "queue.size:+2|g", "page.views:3|c")
+	md, numPoints := a.flush(start.Add(3 * time.Minute))
+	assert.Equal(t, 2, numPoints)
+	metrics := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	require.Equal(t, 2, metrics.Len())
+	views := metrics.At(0).DoubleSum().DataPoints().At(0)
+	assert.Equal(t, 3.0, views.Value())
+	assert.Equal(t, pdata.TimestampFromTime(start.Add(2*time.Minute)), views.StartTime())
+	assert.Equal(t, 12.0, metrics.At(1).DoubleGauge().DataPoints().At(0).Value())
+}
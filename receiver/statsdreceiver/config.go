@@ -0,0 +1,47 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statsdreceiver
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/config/confignet"
+)
+
+// Config defines configuration for the statsd receiver.
+type Config struct {
+	configmodels.ReceiverSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct
+
+	// NetAddr is the address to listen on, the transport being "udp" (default),
+	// "udp4", "udp6" or "unixgram" for a Unix domain socket.
+	confignet.NetAddr `mapstructure:",squash"`
+
+	// FlushInterval is the interval at which the aggregated metrics are sent
+	// to the next consumer.
+	FlushInterval time.Duration `mapstructure:"flush_interval"`
+
+	// HistogramBuckets are the explicit bounds of the histograms of the
+	// timers, in milliseconds, of the DogStatsD histograms and of the
+	// distributions, those of the Prometheus client libraries by default.
+	HistogramBuckets []float64 `mapstructure:"histogram_buckets"`
+}
+
+var _ configmodels.ConfigValidator = (*Config)(nil)
+
+// Validate checks the receiver configuration is valid.
+func (cfg *Config) Validate() error {
+	return validateConfig(cfg)
+}
@@ -0,0 +1,104 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statsdreceiver
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/config/confignet"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/receiver/receiverhelper"
+)
+
+const (
+	// The value of "type" key in configuration.
+	typeStr = "statsd"
+
+	defaultEndpoint      = "localhost:8125"
+	defaultTransport     = "udp"
+	defaultFlushInterval = 60 * time.Second
+)
+
+// defaultHistogramBuckets are the bounds, in milliseconds, of the histograms
+// when "histogram_buckets" is not set, those of the Prometheus client
+// libraries. They are not set in the default configuration since the
+// configured buckets would be merged with them.
+var defaultHistogramBuckets = []float64{5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000}
+
+var (
+	errUnsupportedTransport     = errors.New("unsupported transport, the supported transports are \"udp\", \"udp4\", \"udp6\" and \"unixgram\"")
+	errInvalidFlushInterval     = errors.New("\"flush_interval\" must be positive")
+	errUnsortedHistogramBuckets = errors.New("\"histogram_buckets\" must be sorted in increasing order")
+)
+
+// NewFactory creates a factory for the statsd receiver.
+func NewFactory() component.ReceiverFactory {
+	return receiverhelper.NewFactory(
+		typeStr,
+		createDefaultConfig,
+		receiverhelper.WithMetrics(createMetricsReceiver))
+}
+
+func createDefaultConfig() configmodels.Receiver {
+	return &Config{
+		ReceiverSettings: configmodels.ReceiverSettings{
+			TypeVal: typeStr,
+			NameVal: typeStr,
+		},
+		NetAddr: confignet.NetAddr{
+			Endpoint:  defaultEndpoint,
+			Transport: defaultTransport,
+		},
+		FlushInterval: defaultFlushInterval,
+	}
+}
+
+func createMetricsReceiver(
+	_ context.Context,
+	params component.ReceiverCreateParams,
+	cfg configmodels.Receiver,
+	nextConsumer consumer.MetricsConsumer,
+) (component.MetricsReceiver, error) {
+	c := cfg.(*Config)
+	if err := validateConfig(c); err != nil {
+		return nil, err
+	}
+	return newStatsdReceiver(params.Logger, c, nextConsumer), nil
+}
+
+func validateConfig(cfg *Config) error {
+	switch cfg.Transport {
+	case "udp", "udp4", "udp6", "unixgram":
+	default:
+		return errUnsupportedTransport
+	}
+	if cfg.FlushInterval <= 0 {
+		return errInvalidFlushInterval
+	}
+	if !sort.Float64sAreSorted(cfg.HistogramBuckets) {
+		return errUnsortedHistogramBuckets
+	}
+	for i := 1; i < len(cfg.HistogramBuckets); i++ {
+		if cfg.HistogramBuckets[i] == cfg.HistogramBuckets[i-1] {
+			return errUnsortedHistogramBuckets
+		}
+	}
+	return nil
+}
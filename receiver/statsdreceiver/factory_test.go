@@ -0,0 +1,84 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statsdreceiver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configcheck"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+)
+
+func TestCreateDefaultConfig(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	assert.NotNil(t, cfg, "failed to create default config")
+	assert.NoError(t, configcheck.ValidateConfig(cfg))
+	assert.Equal(t, defaultEndpoint, cfg.Endpoint)
+	assert.Equal(t, defaultTransport, cfg.Transport)
+	assert.Equal(t, defaultFlushInterval, cfg.FlushInterval)
+	assert.Nil(t, cfg.HistogramBuckets)
+}
+
+func TestCreateMetricsReceiver(t *testing.T) {
+	params := component.ReceiverCreateParams{Logger: zap.NewNop()}
+	r, err := NewFactory().CreateMetricsReceiver(context.Background(), params, createDefaultConfig(), consumertest.NewMetricsNop())
+	require.NoError(t, err)
+	assert.NotNil(t, r)
+}
+
+func TestCreateMetricsReceiverInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		modify      func(cfg *Config)
+		expectedErr error
+	}{
+		{
+			name:        "tcp transport",
+			modify:      func(cfg *Config) { cfg.Transport = "tcp" },
+			expectedErr: errUnsupportedTransport,
+		},
+		{
+			name:        "zero flush interval",
+			modify:      func(cfg *Config) { cfg.FlushInterval = 0 },
+			expectedErr: errInvalidFlushInterval,
+		},
+		{
+			name:        "unsorted buckets",
+			modify:      func(cfg *Config) { cfg.HistogramBuckets = []float64{10, 1} },
+			expectedErr: errUnsortedHistogramBuckets,
+		},
+		{
+			name:        "duplicate buckets",
+			modify:      func(cfg *Config) { cfg.HistogramBuckets = []float64{1, 1} },
+			expectedErr: errUnsortedHistogramBuckets,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			test.modify(cfg)
+			params := component.ReceiverCreateParams{Logger: zap.NewNop()}
+			r, err := NewFactory().CreateMetricsReceiver(context.Background(), params, cfg, consumertest.NewMetricsNop())
+			assert.Equal(t, test.expectedErr, err)
+			assert.Nil(t, r)
+		})
+	}
+}
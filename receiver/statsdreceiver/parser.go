@@ -0,0 +1,159 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statsdreceiver
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// The statsd metric types, "h" and "d" being the DogStatsD histograms and
+// distributions.
+const (
+	counterType      = "c"
+	gaugeType        = "g"
+	timerType        = "ms"
+	histogramType    = "h"
+	distributionType = "d"
+	setType          = "s"
+)
+
+// statsdValue is a value of a statsd line.
+type statsdValue struct {
+	// number is the value of the counters, gauges, timers, histograms and
+	// distributions.
+	number float64
+	// relative is set for the gauge values with a sign, which increase or
+	// decrease the gauge instead of setting it.
+	relative bool
+	// member is the value of the sets.
+	member string
+}
+
+// label is a DogStatsD tag, the value being empty for the tags without value.
+type label struct {
+	key   string
+	value string
+}
+
+// statsdMetric is a parsed statsd line, such as
+// "page.views:1|c|@0.5|#host:web1,env:prod".
+type statsdMetric struct {
+	name       string
+	metricType string
+	// values holds the values of the line, several with the DogStatsD packed
+	// format "name:1:2:3|ms".
+	values     []statsdValue
+	sampleRate float64
+	// labels are sorted by key.
+	labels []label
+}
+
+// parseLine parses a statsd line, returning nil for the empty lines and the
+// DogStatsD events and service checks, which are not metrics.
+func parseLine(line string) (*statsdMetric, error) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "_e{") || strings.HasPrefix(line, "_sc|") {
+		return nil, nil
+	}
+
+	sections := strings.Split(line, "|")
+	if len(sections) < 2 {
+		return nil, fmt.Errorf("invalid statsd line %q: missing metric type", line)
+	}
+	sep := strings.IndexByte(sections[0], ':')
+	if sep <= 0 {
+		return nil, fmt.Errorf("invalid statsd line %q: missing metric name or value", line)
+	}
+
+	m := &statsdMetric{
+		name:       sections[0][:sep],
+		metricType: sections[1],
+		sampleRate: 1,
+	}
+	switch m.metricType {
+	case counterType, gaugeType, timerType, histogramType, distributionType, setType:
+	default:
+		return nil, fmt.Errorf("invalid statsd line %q: unsupported metric type %q", line, m.metricType)
+	}
+
+	for _, raw := range strings.Split(sections[0][sep+1:], ":") {
+		value, err := parseValue(m.metricType, raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid statsd line %q: %v", line, err)
+		}
+		m.values = append(m.values, value)
+	}
+
+	// The other DogStatsD sections, such as the container ID "c:<id>", are
+	// ignored.
+	for _, section := range sections[2:] {
+		switch {
+		case strings.HasPrefix(section, "@"):
+			rate, err := strconv.ParseFloat(section[1:], 64)
+			if err != nil || rate <= 0 || rate > 1 {
+				return nil, fmt.Errorf("invalid statsd line %q: invalid sample rate %q", line, section[1:])
+			}
+			m.sampleRate = rate
+		case strings.HasPrefix(section, "#"):
+			m.labels = parseTags(section[1:])
+		}
+	}
+	return m, nil
+}
+
+func parseValue(metricType, raw string) (statsdValue, error) {
+	if metricType == setType {
+		if raw == "" {
+			return statsdValue{}, fmt.Errorf("empty set value")
+		}
+		return statsdValue{member: raw}, nil
+	}
+
+	number, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return statsdValue{}, fmt.Errorf("invalid value %q", raw)
+	}
+	relative := metricType == gaugeType && (strings.HasPrefix(raw, "+") || strings.HasPrefix(raw, "-"))
+	return statsdValue{number: number, relative: relative}, nil
+}
+
+// parseTags parses the DogStatsD tags "key1:value1,key2", the last value
+// being kept for duplicate keys.
+func parseTags(tags string) []label {
+	values := make(map[string]string)
+	for _, tag := range strings.Split(tags, ",") {
+		if tag == "" {
+			continue
+		}
+		kv := strings.SplitN(tag, ":", 2)
+		if len(kv) == 2 {
+			values[kv[0]] = kv[1]
+		} else {
+			values[kv[0]] = ""
+		}
+	}
+
+	labels := make([]label, 0, len(values))
+	for key, value := range values {
+		labels = append(labels, label{key: key, value: value})
+	}
+	sort.Slice(labels, func(i, j int) bool {
+		return labels[i].key < labels[j].key
+	})
+	return labels
+}
@@ -0,0 +1,139 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statsdreceiver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLine(t *testing.T) {
+	tests := []struct {
+		line     string
+		expected *statsdMetric
+	}{
+		{
+			line:     "",
+			expected: nil,
+		},
+		{
+			line: "page.views:1|c",
+			expected: &statsdMetric{
+				name:       "page.views",
+				metricType: counterType,
+				values:     []statsdValue{{number: 1}},
+				sampleRate: 1,
+			},
+		},
+		{
+			line: "page.views:2|c|@0.1|#host:web1,env:prod,canary",
+			expected: &statsdMetric{
+				name:       "page.views",
+				metricType: counterType,
+				values:     []statsdValue{{number: 2}},
+				sampleRate: 0.1,
+				labels:     []label{{key: "canary"}, {key: "env", value: "prod"}, {key: "host", value: "web1"}},
+			},
+		},
+		{
+			line: "queue.size:-3|g",
+			expected: &statsdMetric{
+				name:       "queue.size",
+				metricType: gaugeType,
+				values:     []statsdValue{{number: -3, relative: true}},
+				sampleRate: 1,
+			},
+		},
+		{
+			line: "queue.size:42.5|g|#env:prod,env:dev",
+			expected: &statsdMetric{
+				name:       "queue.size",
+				metricType: gaugeType,
+				values:     []statsdValue{{number: 42.5}},
+				sampleRate: 1,
+				labels:     []label{{key: "env", value: "dev"}},
+			},
+		},
+		{
+			line: "request.latency:320:+12:4|ms|c:83c0a99c0a54",
+			expected: &statsdMetric{
+				name:       "request.latency",
+				metricType: timerType,
+				values:     []statsdValue{{number: 320}, {number: 12}, {number: 4}},
+				sampleRate: 1,
+			},
+		},
+		{
+			line: "users.unique:alice|s",
+			expected: &statsdMetric{
+				name:       "users.unique",
+				metricType: setType,
+				values:     []statsdValue{{member: "alice"}},
+				sampleRate: 1,
+			},
+		},
+		{
+			line: "payload.size:512|d|@0.5",
+			expected: &statsdMetric{
+				name:       "payload.size",
+				metricType: distributionType,
+				values:     []statsdValue{{number: 512}},
+				sampleRate: 0.5,
+			},
+		},
+		{
+			line:     "_e{5,4}:title|text|#env:prod",
+			expected: nil,
+		},
+		{
+			line:     "_sc|redis.can_connect|0",
+			expected: nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.line, func(t *testing.T) {
+			m, err := parseLine(test.line)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, m)
+		})
+	}
+}
+
+func TestParseLineErrors(t *testing.T) {
+	tests := []struct {
+		line        string
+		expectedErr string
+	}{
+		{line: "page.views:1", expectedErr: `invalid statsd line "page.views:1": missing metric type`},
+		{line: "page.views|c", expectedErr: `invalid statsd line "page.views|c": missing metric name or value`},
+		{line: ":1|c", expectedErr: `invalid statsd line ":1|c": missing metric name or value`},
+		{line: "page.views:1|x", expectedErr: `invalid statsd line "page.views:1|x": unsupported metric type "x"`},
+		{line: "page.views:one|c", expectedErr: `invalid statsd line "page.views:one|c": invalid value "one"`},
+		{line: "users.unique:|s", expectedErr: `invalid statsd line "users.unique:|s": empty set value`},
+		{line: "page.views:1|c|@2", expectedErr: `invalid statsd line "page.views:1|c|@2": invalid sample rate "2"`},
+		{line: "page.views:1|c|@0", expectedErr: `invalid statsd line "page.views:1|c|@0": invalid sample rate "0"`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.line, func(t *testing.T) {
+			m, err := parseLine(test.line)
+			assert.EqualError(t, err, test.expectedErr)
+			assert.Nil(t, m)
+		})
+	}
+}
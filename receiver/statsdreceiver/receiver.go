@@ -0,0 +1,156 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statsdreceiver
+
+import (
+	"context"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/obsreport"
+)
+
+// The maximum size of the UDP packets.
+const readBufferSize = 64 * 1024
+
+const dataFormat = "statsd"
+
+type statsdReceiver struct {
+	config     *Config
+	logger     *zap.Logger
+	next       consumer.MetricsConsumer
+	aggregator *aggregator
+
+	conn   net.PacketConn
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func newStatsdReceiver(logger *zap.Logger, config *Config, next consumer.MetricsConsumer) *statsdReceiver {
+	return &statsdReceiver{
+		config: config,
+		logger: logger,
+		next:   next,
+	}
+}
+
+func (r *statsdReceiver) Start(_ context.Context, _ component.Host) error {
+	conn, err := net.ListenPacket(r.config.Transport, r.config.Endpoint)
+	if err != nil {
+		return err
+	}
+	r.conn = conn
+	bounds := r.config.HistogramBuckets
+	if len(bounds) == 0 {
+		bounds = defaultHistogramBuckets
+	}
+	r.aggregator = newAggregator(bounds, time.Now())
+
+	receiverCtx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+
+	r.wg.Add(2)
+	go func() {
+		defer r.wg.Done()
+		r.read()
+	}()
+	go func() {
+		defer r.wg.Done()
+		r.flushPeriodically(receiverCtx)
+	}()
+	return nil
+}
+
+// Shutdown stops the receiver, the metrics aggregated since the last flush
+// being sent to the next consumer.
+func (r *statsdReceiver) Shutdown(ctx context.Context) error {
+	if r.conn == nil {
+		return nil
+	}
+	r.cancel()
+	err := r.conn.Close()
+	r.wg.Wait()
+	if r.config.Transport == "unixgram" {
+		os.Remove(r.config.Endpoint)
+	}
+	r.flush(ctx)
+	return err
+}
+
+// read reads the packets until the connection is closed, a packet holding
+// one or more statsd lines.
+func (r *statsdReceiver) read() {
+	buf := make([]byte, readBufferSize)
+	for {
+		n, _, err := r.conn.ReadFrom(buf)
+		if n > 0 {
+			r.handlePacket(string(buf[:n]))
+		}
+		if err != nil {
+			if ne, ok := err.(net.Error); ok && ne.Temporary() {
+				continue
+			}
+			return
+		}
+	}
+}
+
+func (r *statsdReceiver) handlePacket(packet string) {
+	for _, line := range strings.Split(packet, "\n") {
+		m, err := parseLine(line)
+		if err != nil {
+			r.logger.Debug("Failed to parse statsd line", zap.Error(err))
+			continue
+		}
+		if m != nil {
+			r.aggregator.add(m)
+		}
+	}
+}
+
+func (r *statsdReceiver) flushPeriodically(ctx context.Context) {
+	ticker := time.NewTicker(r.config.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			r.flush(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (r *statsdReceiver) flush(ctx context.Context) {
+	md, numPoints := r.aggregator.flush(time.Now())
+	if numPoints == 0 {
+		return
+	}
+
+	ctx = obsreport.ReceiverContext(ctx, r.config.Name(), r.config.Transport)
+	ctx = obsreport.StartMetricsReceiveOp(ctx, r.config.Name(), r.config.Transport)
+	err := r.next.ConsumeMetrics(ctx, md)
+	obsreport.EndMetricsReceiveOp(ctx, dataFormat, numPoints, err)
+	if err != nil {
+		r.logger.Error("Failed to send statsd metrics", zap.Error(err))
+	}
+}
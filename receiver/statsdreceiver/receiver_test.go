@@ -0,0 +1,127 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statsdreceiver
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/confignet"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func newTestReceiver(t *testing.T, netAddr confignet.NetAddr, flushInterval time.Duration) (*statsdReceiver, *consumertest.MetricsSink) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.NetAddr = netAddr
+	cfg.FlushInterval = flushInterval
+	sink := new(consumertest.MetricsSink)
+	r := newStatsdReceiver(zap.NewNop(), cfg, sink)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	return r, sink
+}
+
+func metricNames(mds []pdata.Metrics) []string {
+	var names []string
+	for _, md := range mds {
+		metrics := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+		for i := 0; i < metrics.Len(); i++ {
+			names = append(names, metrics.At(i).Name())
+		}
+	}
+	return names
+}
+
+func TestUDPReceiver(t *testing.T) {
+	r, sink := newTestReceiver(t, confignet.NetAddr{Endpoint: "127.0.0.1:0", Transport: "udp"}, 50*time.Millisecond)
+	defer r.Shutdown(context.Background())
+
+	conn, err := net.Dial("udp", r.conn.LocalAddr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = conn.Write([]byte("page.views:1|c|#env:prod\ninvalid\nrequest.latency:320|ms\n"))
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		return sink.MetricsCount() == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"page.views", "request.latency"}, metricNames(sink.AllMetrics()))
+}
+
+func TestShutdownFlush(t *testing.T) {
+	r, sink := newTestReceiver(t, confignet.NetAddr{Endpoint: "127.0.0.1:0", Transport: "udp"}, time.Hour)
+
+	conn, err := net.Dial("udp", r.conn.LocalAddr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = conn.Write([]byte("queue.size:42|g"))
+	require.NoError(t, err)
+
+	// Wait for the packet to be read before shutting down.
+	require.Eventually(t, func() bool {
+		r.aggregator.mu.Lock()
+		defer r.aggregator.mu.Unlock()
+		return len(r.aggregator.metrics) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, r.Shutdown(context.Background()))
+	assert.Equal(t, []string{"queue.size"}, metricNames(sink.AllMetrics()))
+}
+
+func TestUnixgramReceiver(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping test on windows")
+	}
+
+	tmpdir, err := ioutil.TempDir("", "statsd")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	socket := filepath.Join(tmpdir, "statsd.sock")
+	r, sink := newTestReceiver(t, confignet.NetAddr{Endpoint: socket, Transport: "unixgram"}, 50*time.Millisecond)
+
+	conn, err := net.Dial("unixgram", socket)
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = conn.Write([]byte("users.unique:alice|s"))
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		return sink.MetricsCount() == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"users.unique"}, metricNames(sink.AllMetrics()))
+
+	require.NoError(t, r.Shutdown(context.Background()))
+	_, err = os.Stat(socket)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestStartError(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = "invalid:address:8125"
+	r := newStatsdReceiver(zap.NewNop(), cfg, consumertest.NewMetricsNop())
+	assert.Error(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	assert.NoError(t, r.Shutdown(context.Background()))
+}
@@ -0,0 +1,37 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syslogreceiver
+
+import (
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/config/confignet"
+	"go.opentelemetry.io/collector/config/configtls"
+)
+
+// Config defines configuration for the syslog receiver.
+type Config struct {
+	configmodels.ReceiverSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct
+
+	// NetAddr is the address to listen on, the transport being "udp"
+	// (default), "udp4", "udp6", "tcp", "tcp4" or "tcp6".
+	confignet.NetAddr `mapstructure:",squash"`
+
+	// Configures the TLS settings of the TCP listener. TLS is disabled if nil.
+	TLSSetting *configtls.TLSServerSetting `mapstructure:"tls"`
+
+	// Location is the IANA time zone of the RFC3164 timestamps, which do not
+	// include the time zone, "UTC" by default.
+	Location string `mapstructure:"location"`
+}
@@ -0,0 +1,87 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syslogreceiver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/config/confignet"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/receiver/receiverhelper"
+)
+
+const (
+	// The value of "type" key in configuration.
+	typeStr = "syslog"
+
+	defaultEndpoint  = "0.0.0.0:514"
+	defaultTransport = "udp"
+	defaultLocation  = "UTC"
+)
+
+var (
+	errUnsupportedTransport = errors.New("unsupported transport, the supported transports are \"udp\", \"udp4\", \"udp6\", \"tcp\", \"tcp4\" and \"tcp6\"")
+	errTLSWithUDP           = errors.New("\"tls\" is only supported with the tcp transports")
+)
+
+// NewFactory creates a factory for the syslog receiver.
+func NewFactory() component.ReceiverFactory {
+	return receiverhelper.NewFactory(
+		typeStr,
+		createDefaultConfig,
+		receiverhelper.WithLogs(createLogsReceiver))
+}
+
+func createDefaultConfig() configmodels.Receiver {
+	return &Config{
+		ReceiverSettings: configmodels.ReceiverSettings{
+			TypeVal: typeStr,
+			NameVal: typeStr,
+		},
+		NetAddr: confignet.NetAddr{
+			Endpoint:  defaultEndpoint,
+			Transport: defaultTransport,
+		},
+		Location: defaultLocation,
+	}
+}
+
+func createLogsReceiver(
+	_ context.Context,
+	params component.ReceiverCreateParams,
+	cfg configmodels.Receiver,
+	nextConsumer consumer.LogsConsumer,
+) (component.LogsReceiver, error) {
+	c := cfg.(*Config)
+	switch c.Transport {
+	case "udp", "udp4", "udp6":
+		if c.TLSSetting != nil {
+			return nil, errTLSWithUDP
+		}
+	case "tcp", "tcp4", "tcp6":
+	default:
+		return nil, errUnsupportedTransport
+	}
+	location, err := time.LoadLocation(c.Location)
+	if err != nil {
+		return nil, fmt.Errorf("invalid \"location\": %w", err)
+	}
+	return newSyslogReceiver(params.Logger, c, location, nextConsumer), nil
+}
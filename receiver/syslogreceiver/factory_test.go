@@ -0,0 +1,80 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syslogreceiver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configcheck"
+	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+)
+
+func TestCreateDefaultConfig(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	assert.NotNil(t, cfg, "failed to create default config")
+	assert.NoError(t, configcheck.ValidateConfig(cfg))
+	assert.Equal(t, defaultEndpoint, cfg.Endpoint)
+	assert.Equal(t, defaultTransport, cfg.Transport)
+	assert.Equal(t, defaultLocation, cfg.Location)
+	assert.Nil(t, cfg.TLSSetting)
+}
+
+func TestCreateLogsReceiver(t *testing.T) {
+	params := component.ReceiverCreateParams{Logger: zap.NewNop()}
+	r, err := NewFactory().CreateLogsReceiver(context.Background(), params, createDefaultConfig(), consumertest.NewLogsNop())
+	require.NoError(t, err)
+	assert.NotNil(t, r)
+}
+
+func TestCreateLogsReceiverInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		modify      func(cfg *Config)
+		expectedErr string
+	}{
+		{
+			name:        "unix transport",
+			modify:      func(cfg *Config) { cfg.Transport = "unix" },
+			expectedErr: errUnsupportedTransport.Error(),
+		},
+		{
+			name:        "tls with udp",
+			modify:      func(cfg *Config) { cfg.TLSSetting = &configtls.TLSServerSetting{} },
+			expectedErr: errTLSWithUDP.Error(),
+		},
+		{
+			name:        "invalid location",
+			modify:      func(cfg *Config) { cfg.Location = "Mars/Olympus_Mons" },
+			expectedErr: "invalid \"location\": unknown time zone Mars/Olympus_Mons",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			test.modify(cfg)
+			params := component.ReceiverCreateParams{Logger: zap.NewNop()}
+			r, err := NewFactory().CreateLogsReceiver(context.Background(), params, cfg, consumertest.NewLogsNop())
+			assert.EqualError(t, err, test.expectedErr)
+			assert.Nil(t, r)
+		})
+	}
+}
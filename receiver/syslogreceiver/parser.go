@@ -0,0 +1,272 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syslogreceiver
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// The attributes of the log records.
+const (
+	facilityAttributeKey       = "syslog.facility"
+	versionAttributeKey        = "syslog.version"
+	hostnameAttributeKey       = "syslog.hostname"
+	appNameAttributeKey        = "syslog.appname"
+	procIDAttributeKey         = "syslog.procid"
+	msgIDAttributeKey          = "syslog.msgid"
+	structuredDataAttributeKey = "syslog.structured_data"
+)
+
+// nilValue is the RFC5424 NILVALUE of the fields that are not set.
+const nilValue = "-"
+
+// rfc3164TimestampLayout is the timestamp of the RFC3164 messages, which has
+// no year nor time zone.
+const rfc3164TimestampLayout = "Jan _2 15:04:05"
+
+var errMissingPriority = errors.New("missing priority")
+
+// severities maps the syslog severities to the OpenTelemetry severities.
+var severities = [8]struct {
+	number pdata.SeverityNumber
+	text   string
+}{
+	{pdata.SeverityNumberFATAL4, "emerg"},
+	{pdata.SeverityNumberFATAL3, "alert"},
+	{pdata.SeverityNumberFATAL2, "crit"},
+	{pdata.SeverityNumberERROR, "err"},
+	{pdata.SeverityNumberWARN, "warning"},
+	{pdata.SeverityNumberINFO2, "notice"},
+	{pdata.SeverityNumberINFO, "info"},
+	{pdata.SeverityNumberDEBUG, "debug"},
+}
+
+// parser parses the RFC5424 and RFC3164 messages, the format being detected
+// from the version which follows the priority of the RFC5424 messages.
+type parser struct {
+	// location is the time zone of the RFC3164 timestamps.
+	location *time.Location
+	// now returns the time used for the messages without timestamp and for
+	// the year of the RFC3164 timestamps.
+	now func() time.Time
+}
+
+// parse parses the message into the log record lr.
+func (p *parser) parse(message string, lr pdata.LogRecord) error {
+	message = strings.TrimRight(message, "\r\n")
+	priority, rest, err := parsePriority(message)
+	if err != nil {
+		return err
+	}
+
+	severity := severities[priority%8]
+	lr.SetSeverityNumber(severity.number)
+	lr.SetSeverityText(severity.text)
+	lr.Attributes().InsertInt(facilityAttributeKey, int64(priority/8))
+
+	if len(rest) >= 2 && rest[0] >= '1' && rest[0] <= '9' && (rest[1] == ' ' || (rest[1] >= '0' && rest[1] <= '9')) {
+		return p.parseRFC5424(rest, lr)
+	}
+	p.parseRFC3164(rest, lr)
+	return nil
+}
+
+// parsePriority parses the "<PRI>" prefix of the message.
+func parsePriority(message string) (int, string, error) {
+	end := strings.IndexByte(message, '>')
+	if !strings.HasPrefix(message, "<") || end < 2 || end > 4 {
+		return 0, "", errMissingPriority
+	}
+	priority, err := strconv.Atoi(message[1:end])
+	if err != nil || priority > 191 {
+		return 0, "", fmt.Errorf("invalid priority %q", message[1:end])
+	}
+	return priority, message[end+1:], nil
+}
+
+// parseRFC5424 parses "VERSION TIMESTAMP HOSTNAME APP-NAME PROCID MSGID
+// STRUCTURED-DATA [MSG]".
+func (p *parser) parseRFC5424(message string, lr pdata.LogRecord) error {
+	fields := strings.SplitN(message, " ", 7)
+	if len(fields) < 7 {
+		return errors.New("invalid RFC5424 message: missing header fields")
+	}
+
+	version, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return fmt.Errorf("invalid RFC5424 version %q", fields[0])
+	}
+	lr.Attributes().InsertInt(versionAttributeKey, int64(version))
+
+	if fields[1] == nilValue {
+		lr.SetTimestamp(pdata.TimestampFromTime(p.now()))
+	} else {
+		timestamp, err := time.Parse(time.RFC3339Nano, fields[1])
+		if err != nil {
+			return fmt.Errorf("invalid RFC5424 timestamp %q", fields[1])
+		}
+		lr.SetTimestamp(pdata.TimestampFromTime(timestamp))
+	}
+
+	insertField(lr.Attributes(), hostnameAttributeKey, fields[2])
+	insertField(lr.Attributes(), appNameAttributeKey, fields[3])
+	insertField(lr.Attributes(), procIDAttributeKey, fields[4])
+	insertField(lr.Attributes(), msgIDAttributeKey, fields[5])
+
+	structuredData, msg, err := parseStructuredData(fields[6])
+	if err != nil {
+		return err
+	}
+	if structuredData.Type() == pdata.AttributeValueMAP {
+		lr.Attributes().Insert(structuredDataAttributeKey, structuredData)
+	}
+
+	// The message may start with the UTF-8 byte order mark.
+	lr.Body().SetStringVal(strings.TrimPrefix(msg, "\ufeff"))
+	return nil
+}
+
+func insertField(attrs pdata.AttributeMap, key, value string) {
+	if value != nilValue {
+		attrs.InsertString(key, value)
+	}
+}
+
+// parseStructuredData parses the structured data, "-" or one or more
+// "[SD-ID PARAM-NAME="PARAM-VALUE" ...]" elements, at the start of s into a
+// map of the SD-IDs to the maps of their parameters, returning the rest of
+// s. A null value is returned when there is no structured data.
+func parseStructuredData(s string) (pdata.AttributeValue, string, error) {
+	if s == nilValue || strings.HasPrefix(s, nilValue+" ") {
+		return pdata.NewAttributeValueNull(), strings.TrimPrefix(s[1:], " "), nil
+	}
+	if !strings.HasPrefix(s, "[") {
+		return pdata.NewAttributeValueNull(), "", errors.New("invalid RFC5424 structured data: missing \"[\"")
+	}
+
+	sd := pdata.NewAttributeValueMap()
+	for strings.HasPrefix(s, "[") {
+		// SD-ID
+		end := strings.IndexAny(s, " ]")
+		if end < 0 {
+			return sd, "", errors.New("invalid RFC5424 structured data: unterminated element")
+		}
+		params := pdata.NewAttributeValueMap()
+		id := s[1:end]
+		s = s[end:]
+
+		for strings.HasPrefix(s, " ") {
+			// PARAM-NAME="PARAM-VALUE"
+			s = s[1:]
+			eq := strings.Index(s, "=\"")
+			if eq <= 0 {
+				return sd, "", fmt.Errorf("invalid RFC5424 structured data: invalid parameter of %q", id)
+			}
+			name := s[:eq]
+			value, n, err := parseParamValue(s[eq+2:])
+			if err != nil {
+				return sd, "", fmt.Errorf("invalid RFC5424 structured data: parameter %q of %q: %v", name, id, err)
+			}
+			params.MapVal().Upsert(name, pdata.NewAttributeValueString(value))
+			s = s[eq+2+n:]
+		}
+
+		if !strings.HasPrefix(s, "]") {
+			return sd, "", fmt.Errorf("invalid RFC5424 structured data: unterminated element %q", id)
+		}
+		s = s[1:]
+		sd.MapVal().Upsert(id, params)
+	}
+	return sd, strings.TrimPrefix(s, " "), nil
+}
+
+// parseParamValue parses a parameter value up to its closing quote, where
+// '"', '\' and ']' are escaped with '\', returning the value and the length
+// consumed, closing quote included.
+func parseParamValue(s string) (string, int, error) {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			return b.String(), i + 1, nil
+		case '\\':
+			if i+1 < len(s) && (s[i+1] == '"' || s[i+1] == '\\' || s[i+1] == ']') {
+				i++
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return "", 0, errors.New("missing closing quote")
+}
+
+// parseRFC3164 parses "TIMESTAMP HOSTNAME TAG[PID]: MSG", the messages which
+// do not follow the format being kept as the body of the log record.
+func (p *parser) parseRFC3164(message string, lr pdata.LogRecord) {
+	now := p.now().In(p.location)
+	lr.SetTimestamp(pdata.TimestampFromTime(now))
+	if len(message) < len(rfc3164TimestampLayout) {
+		lr.Body().SetStringVal(message)
+		return
+	}
+	timestamp, err := time.ParseInLocation(rfc3164TimestampLayout, message[:len(rfc3164TimestampLayout)], p.location)
+	if err != nil {
+		lr.Body().SetStringVal(message)
+		return
+	}
+	// The timestamps more than a day in the future, such as those of December
+	// received in January, are from the previous year.
+	timestamp = timestamp.AddDate(now.Year(), 0, 0)
+	if timestamp.After(now.Add(24 * time.Hour)) {
+		timestamp = timestamp.AddDate(-1, 0, 0)
+	}
+	lr.SetTimestamp(pdata.TimestampFromTime(timestamp))
+
+	rest := strings.TrimPrefix(message[len(rfc3164TimestampLayout):], " ")
+	fields := strings.SplitN(rest, " ", 2)
+	if len(fields) < 2 {
+		lr.Body().SetStringVal(rest)
+		return
+	}
+	lr.Attributes().InsertString(hostnameAttributeKey, fields[0])
+	rest = fields[1]
+
+	// The TAG is the name of the program, alphanumeric, optionally followed
+	// by the process ID.
+	tagEnd := strings.IndexAny(rest, "[: ")
+	if tagEnd <= 0 {
+		lr.Body().SetStringVal(rest)
+		return
+	}
+	tag := rest[:tagEnd]
+	rest = rest[tagEnd:]
+	if strings.HasPrefix(rest, "[") {
+		pidEnd := strings.IndexByte(rest, ']')
+		if pidEnd < 0 {
+			lr.Body().SetStringVal(tag + rest)
+			return
+		}
+		lr.Attributes().InsertString(procIDAttributeKey, rest[1:pidEnd])
+		rest = rest[pidEnd+1:]
+	}
+	lr.Attributes().InsertString(appNameAttributeKey, tag)
+	rest = strings.TrimPrefix(rest, ":")
+	lr.Body().SetStringVal(strings.TrimPrefix(rest, " "))
+}
@@ -0,0 +1,240 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syslogreceiver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+var testNow = time.Date(2021, time.March, 15, 12, 0, 0, 0, time.UTC)
+
+func newTestParser(location *time.Location, now time.Time) *parser {
+	return &parser{location: location, now: func() time.Time { return now }}
+}
+
+// attributesToMap returns the attributes as a map, the map attributes being
+// converted recursively.
+func attributesToMap(attrs pdata.AttributeMap) map[string]interface{} {
+	m := make(map[string]interface{}, attrs.Len())
+	attrs.ForEach(func(k string, v pdata.AttributeValue) {
+		switch v.Type() {
+		case pdata.AttributeValueSTRING:
+			m[k] = v.StringVal()
+		case pdata.AttributeValueINT:
+			m[k] = v.IntVal()
+		case pdata.AttributeValueMAP:
+			m[k] = attributesToMap(v.MapVal())
+		}
+	})
+	return m
+}
+
+func TestParseRFC5424(t *testing.T) {
+	tests := []struct {
+		name         string
+		message      string
+		timestamp    time.Time
+		severity     pdata.SeverityNumber
+		severityText string
+		attributes   map[string]interface{}
+		body         string
+	}{
+		{
+			name:         "full",
+			message:      `<165>1 2003-10-11T22:14:15.003Z mymachine.example.com evntslog 1234 ID47 [exampleSDID@32473 iut="3" eventSource="Application" eventID="1011"][examplePriority@32473 class="high"] An application event`,
+			timestamp:    time.Date(2003, time.October, 11, 22, 14, 15, 3000000, time.UTC),
+			severity:     pdata.SeverityNumberINFO2,
+			severityText: "notice",
+			attributes: map[string]interface{}{
+				facilityAttributeKey: int64(20),
+				versionAttributeKey:  int64(1),
+				hostnameAttributeKey: "mymachine.example.com",
+				appNameAttributeKey:  "evntslog",
+				procIDAttributeKey:   "1234",
+				msgIDAttributeKey:    "ID47",
+				structuredDataAttributeKey: map[string]interface{}{
+					"exampleSDID@32473":     map[string]interface{}{"iut": "3", "eventSource": "Application", "eventID": "1011"},
+					"examplePriority@32473": map[string]interface{}{"class": "high"},
+				},
+			},
+			body: "An application event",
+		},
+		{
+			name:         "nil values",
+			message:      "<34>1 - - - - - -",
+			timestamp:    testNow,
+			severity:     pdata.SeverityNumberFATAL2,
+			severityText: "crit",
+			attributes: map[string]interface{}{
+				facilityAttributeKey: int64(4),
+				versionAttributeKey:  int64(1),
+			},
+		},
+		{
+			name:         "bom and offset",
+			message:      "<11>1 2003-08-24T05:14:15.000003-07:00 192.0.2.1 myproc 8710 - - \ufeff%% It's time to make the do-nuts.\n",
+			timestamp:    time.Date(2003, time.August, 24, 12, 14, 15, 3000, time.UTC),
+			severity:     pdata.SeverityNumberERROR,
+			severityText: "err",
+			attributes: map[string]interface{}{
+				facilityAttributeKey: int64(1),
+				versionAttributeKey:  int64(1),
+				hostnameAttributeKey: "192.0.2.1",
+				appNameAttributeKey:  "myproc",
+				procIDAttributeKey:   "8710",
+			},
+			body: "%% It's time to make the do-nuts.",
+		},
+		{
+			name:         "escaped structured data without message",
+			message:      `<191>1 2003-10-11T22:14:15Z host app - - [meta path="C:\\logs\]" quote="say \"hi\"" empty=""][origin]`,
+			timestamp:    time.Date(2003, time.October, 11, 22, 14, 15, 0, time.UTC),
+			severity:     pdata.SeverityNumberDEBUG,
+			severityText: "debug",
+			attributes: map[string]interface{}{
+				facilityAttributeKey: int64(23),
+				versionAttributeKey:  int64(1),
+				hostnameAttributeKey: "host",
+				appNameAttributeKey:  "app",
+				structuredDataAttributeKey: map[string]interface{}{
+					"meta":   map[string]interface{}{"path": `C:\logs]`, "quote": `say "hi"`, "empty": ""},
+					"origin": map[string]interface{}{},
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			lr := pdata.NewLogRecord()
+			require.NoError(t, newTestParser(time.UTC, testNow).parse(test.message, lr))
+			assert.Equal(t, pdata.TimestampFromTime(test.timestamp), lr.Timestamp())
+			assert.Equal(t, test.severity, lr.SeverityNumber())
+			assert.Equal(t, test.severityText, lr.SeverityText())
+			assert.Equal(t, test.attributes, attributesToMap(lr.Attributes()))
+			assert.Equal(t, test.body, lr.Body().StringVal())
+		})
+	}
+}
+
+func TestParseRFC3164(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		location   *time.Location
+		now        time.Time
+		message    string
+		timestamp  time.Time
+		severity   pdata.SeverityNumber
+		attributes map[string]interface{}
+		body       string
+	}{
+		{
+			name:      "tag with pid",
+			location:  time.UTC,
+			now:       testNow,
+			message:   "<34>Oct 11 22:14:15 mymachine su[123]: 'su root' failed for lonvick on /dev/pts/8",
+			timestamp: time.Date(2020, time.October, 11, 22, 14, 15, 0, time.UTC),
+			severity:  pdata.SeverityNumberFATAL2,
+			attributes: map[string]interface{}{
+				facilityAttributeKey: int64(4),
+				hostnameAttributeKey: "mymachine",
+				appNameAttributeKey:  "su",
+				procIDAttributeKey:   "123",
+			},
+			body: "'su root' failed for lonvick on /dev/pts/8",
+		},
+		{
+			name:      "location",
+			location:  paris,
+			now:       testNow,
+			message:   "<13>Mar  5 08:00:00 router sshd: Accepted publickey",
+			timestamp: time.Date(2021, time.March, 5, 7, 0, 0, 0, time.UTC),
+			severity:  pdata.SeverityNumberINFO2,
+			attributes: map[string]interface{}{
+				facilityAttributeKey: int64(1),
+				hostnameAttributeKey: "router",
+				appNameAttributeKey:  "sshd",
+			},
+			body: "Accepted publickey",
+		},
+		{
+			name:      "previous year",
+			location:  time.UTC,
+			now:       time.Date(2021, time.January, 1, 0, 0, 10, 0, time.UTC),
+			message:   "<13>Dec 31 23:59:59 host cron: job done",
+			timestamp: time.Date(2020, time.December, 31, 23, 59, 59, 0, time.UTC),
+			severity:  pdata.SeverityNumberINFO2,
+			attributes: map[string]interface{}{
+				facilityAttributeKey: int64(1),
+				hostnameAttributeKey: "host",
+				appNameAttributeKey:  "cron",
+			},
+			body: "job done",
+		},
+		{
+			name:      "no timestamp",
+			location:  time.UTC,
+			now:       testNow,
+			message:   "<13>link down on port 3",
+			timestamp: testNow,
+			severity:  pdata.SeverityNumberINFO2,
+			attributes: map[string]interface{}{
+				facilityAttributeKey: int64(1),
+			},
+			body: "link down on port 3",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			lr := pdata.NewLogRecord()
+			require.NoError(t, newTestParser(test.location, test.now).parse(test.message, lr))
+			assert.Equal(t, pdata.TimestampFromTime(test.timestamp), lr.Timestamp())
+			assert.Equal(t, test.severity, lr.SeverityNumber())
+			assert.Equal(t, test.attributes, attributesToMap(lr.Attributes()))
+			assert.Equal(t, test.body, lr.Body().StringVal())
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		message     string
+		expectedErr string
+	}{
+		{name: "missing priority", message: "Oct 11 22:14:15 host app: message", expectedErr: errMissingPriority.Error()},
+		{name: "unterminated priority", message: "<34", expectedErr: errMissingPriority.Error()},
+		{name: "invalid priority", message: "<192>Oct 11 22:14:15 host app: message", expectedErr: "invalid priority \"192\""},
+		{name: "missing header fields", message: "<34>1 2003-10-11T22:14:15Z host app", expectedErr: "invalid RFC5424 message: missing header fields"},
+		{name: "invalid timestamp", message: "<34>1 yesterday host app - - -", expectedErr: "invalid RFC5424 timestamp \"yesterday\""},
+		{name: "invalid structured data", message: "<34>1 - host app - - message", expectedErr: "invalid RFC5424 structured data: missing \"[\""},
+		{name: "unterminated element", message: `<34>1 - host app - - [id k="v"`, expectedErr: "invalid RFC5424 structured data: unterminated element \"id\""},
+		{name: "unterminated value", message: `<34>1 - host app - - [id k="v]`, expectedErr: "invalid RFC5424 structured data: parameter \"k\" of \"id\": missing closing quote"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := newTestParser(time.UTC, testNow).parse(test.message, pdata.NewLogRecord())
+			assert.EqualError(t, err, test.expectedErr)
+		})
+	}
+}
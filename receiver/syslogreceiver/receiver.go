@@ -0,0 +1,277 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syslogreceiver
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/obsreport"
+)
+
+const (
+	// The maximum size of the messages, that of the UDP packets.
+	maxMessageSize = 64 * 1024
+	// The maximum number of log records of the batches of the TCP
+	// connections.
+	maxBatchSize = 100
+)
+
+const dataFormat = "syslog"
+
+var errMessageTooLarge = errors.New("message too large")
+
+type syslogReceiver struct {
+	config *Config
+	logger *zap.Logger
+	next   consumer.LogsConsumer
+	parser *parser
+
+	packetConn net.PacketConn
+	listener   net.Listener
+
+	mu sync.Mutex
+	// closed is set once Shutdown closed the open connections, the connections
+	// accepted afterwards are closed right away.
+	closed bool
+	conns  map[net.Conn]struct{}
+	wg     sync.WaitGroup
+}
+
+func newSyslogReceiver(logger *zap.Logger, config *Config, location *time.Location, next consumer.LogsConsumer) *syslogReceiver {
+	return &syslogReceiver{
+		config: config,
+		logger: logger,
+		next:   next,
+		parser: &parser{location: location, now: time.Now},
+		conns:  make(map[net.Conn]struct{}),
+	}
+}
+
+func (r *syslogReceiver) Start(_ context.Context, _ component.Host) error {
+	if strings.HasPrefix(r.config.Transport, "udp") {
+		conn, err := net.ListenPacket(r.config.Transport, r.config.Endpoint)
+		if err != nil {
+			return err
+		}
+		r.packetConn = conn
+		r.wg.Add(1)
+		go func() {
+			defer r.wg.Done()
+			r.readPackets()
+		}()
+		return nil
+	}
+
+	var tlsCfg *tls.Config
+	if r.config.TLSSetting != nil {
+		var err error
+		tlsCfg, err = r.config.TLSSetting.LoadTLSConfig()
+		if err != nil {
+			return err
+		}
+	}
+	listener, err := net.Listen(r.config.Transport, r.config.Endpoint)
+	if err != nil {
+		return err
+	}
+	if tlsCfg != nil {
+		listener = tls.NewListener(listener, tlsCfg)
+	}
+	r.listener = listener
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		r.accept()
+	}()
+	return nil
+}
+
+// Shutdown stops the receiver, closing the listener and the open connections.
+func (r *syslogReceiver) Shutdown(context.Context) error {
+	var err error
+	switch {
+	case r.packetConn != nil:
+		err = r.packetConn.Close()
+	case r.listener != nil:
+		err = r.listener.Close()
+		r.mu.Lock()
+		r.closed = true
+		for conn := range r.conns {
+			conn.Close()
+		}
+		r.mu.Unlock()
+	}
+	r.wg.Wait()
+	return err
+}
+
+// readPackets reads the packets until the connection is closed, a packet
+// holding one message.
+func (r *syslogReceiver) readPackets() {
+	buf := make([]byte, maxMessageSize)
+	for {
+		n, _, err := r.packetConn.ReadFrom(buf)
+		if n > 0 {
+			logs, records := newLogs()
+			r.appendRecord(records, string(buf[:n]))
+			r.consume(logs)
+		}
+		if err != nil {
+			if ne, ok := err.(net.Error); ok && ne.Temporary() {
+				continue
+			}
+			return
+		}
+	}
+}
+
+func (r *syslogReceiver) accept() {
+	for {
+		conn, err := r.listener.Accept()
+		if err != nil {
+			if ne, ok := err.(net.Error); ok && ne.Temporary() {
+				continue
+			}
+			return
+		}
+		r.mu.Lock()
+		if r.closed {
+			r.mu.Unlock()
+			conn.Close()
+			continue
+		}
+		r.conns[conn] = struct{}{}
+		r.mu.Unlock()
+		r.wg.Add(1)
+		go func() {
+			defer r.wg.Done()
+			r.handleConn(conn)
+			r.mu.Lock()
+			delete(r.conns, conn)
+			r.mu.Unlock()
+			conn.Close()
+		}()
+	}
+}
+
+// handleConn reads the messages of a TCP connection until it is closed. The
+// messages read while more data is buffered are sent in the same batch.
+func (r *syslogReceiver) handleConn(conn net.Conn) {
+	reader := bufio.NewReaderSize(conn, maxMessageSize)
+	logs, records := newLogs()
+	for {
+		message, err := readFrame(reader)
+		if message != "" {
+			r.appendRecord(records, message)
+		}
+		if records.Len() > 0 && (err != nil || reader.Buffered() == 0 || records.Len() >= maxBatchSize) {
+			r.consume(logs)
+			logs, records = newLogs()
+		}
+		if err != nil {
+			if err != io.EOF {
+				r.logger.Debug("Failed to read syslog message", zap.Error(err))
+			}
+			return
+		}
+	}
+}
+
+// readFrame reads a message framed with octet counting, "MSG-LEN SP MSG", if
+// it starts with a digit, or delimited by a trailing LF otherwise, as
+// described by RFC6587.
+func readFrame(reader *bufio.Reader) (string, error) {
+	first, err := reader.Peek(1)
+	if err != nil {
+		return "", err
+	}
+
+	if first[0] >= '1' && first[0] <= '9' {
+		prefix, err := reader.ReadSlice(' ')
+		if err != nil {
+			return "", fmt.Errorf("invalid octet counting frame: %w", err)
+		}
+		length, err := strconv.Atoi(string(prefix[:len(prefix)-1]))
+		if err != nil {
+			return "", fmt.Errorf("invalid octet counting frame length %q", prefix[:len(prefix)-1])
+		}
+		if length > maxMessageSize {
+			return "", errMessageTooLarge
+		}
+		message := make([]byte, length)
+		if _, err := io.ReadFull(reader, message); err != nil {
+			return "", err
+		}
+		return string(message), nil
+	}
+
+	line, err := reader.ReadSlice('\n')
+	if err == bufio.ErrBufferFull {
+		return "", errMessageTooLarge
+	}
+	// The last message may not be terminated when the connection is closed.
+	return string(line), err
+}
+
+func newLogs() (pdata.Logs, pdata.LogSlice) {
+	logs := pdata.NewLogs()
+	logs.ResourceLogs().Resize(1)
+	rl := logs.ResourceLogs().At(0)
+	rl.InstrumentationLibraryLogs().Resize(1)
+	return logs, rl.InstrumentationLibraryLogs().At(0).Logs()
+}
+
+// appendRecord appends the log record of the message to records, the
+// messages which cannot be parsed being dropped.
+func (r *syslogReceiver) appendRecord(records pdata.LogSlice, message string) {
+	if strings.TrimSpace(message) == "" {
+		return
+	}
+	lr := pdata.NewLogRecord()
+	if err := r.parser.parse(message, lr); err != nil {
+		r.logger.Debug("Failed to parse syslog message", zap.Error(err))
+		return
+	}
+	records.Append(lr)
+}
+
+func (r *syslogReceiver) consume(logs pdata.Logs) {
+	numRecords := logs.LogRecordCount()
+	if numRecords == 0 {
+		return
+	}
+	ctx := obsreport.ReceiverContext(context.Background(), r.config.Name(), r.config.Transport)
+	ctx = obsreport.StartLogsReceiveOp(ctx, r.config.Name(), r.config.Transport)
+	err := r.next.ConsumeLogs(ctx, logs)
+	obsreport.EndLogsReceiveOp(ctx, dataFormat, numRecords, err)
+	if err != nil {
+		r.logger.Error("Failed to send syslog logs", zap.Error(err))
+	}
+}
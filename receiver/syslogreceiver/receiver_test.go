@@ -0,0 +1,251 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syslogreceiver
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/confignet"
+	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+)
+
+func newTestReceiver(t *testing.T, modify func(cfg *Config)) (*syslogReceiver, *consumertest.LogsSink) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.NetAddr = confignet.NetAddr{Endpoint: "127.0.0.1:0", Transport: "udp"}
+	modify(cfg)
+	sink := new(consumertest.LogsSink)
+	r := newSyslogReceiver(zap.NewNop(), cfg, time.UTC, sink)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	return r, sink
+}
+
+// bodies returns the bodies of the received log records.
+func bodies(sink *consumertest.LogsSink) []string {
+	var bodies []string
+	for _, logs := range sink.AllLogs() {
+		records := logs.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs()
+		for i := 0; i < records.Len(); i++ {
+			bodies = append(bodies, records.At(i).Body().StringVal())
+		}
+	}
+	return bodies
+}
+
+func TestUDPReceiver(t *testing.T) {
+	r, sink := newTestReceiver(t, func(*Config) {})
+	defer r.Shutdown(context.Background())
+
+	conn, err := net.Dial("udp", r.packetConn.LocalAddr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+	for _, message := range []string{
+		"<34>Oct 11 22:14:15 mymachine su: 'su root' failed",
+		"invalid",
+		"<165>1 2003-10-11T22:14:15.003Z mymachine.example.com evntslog - ID47 - An application event\n",
+	} {
+		_, err = conn.Write([]byte(message))
+		require.NoError(t, err)
+	}
+
+	require.Eventually(t, func() bool {
+		return sink.LogRecordsCount() == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"'su root' failed", "An application event"}, bodies(sink))
+}
+
+func TestTCPReceiver(t *testing.T) {
+	r, sink := newTestReceiver(t, func(cfg *Config) { cfg.Transport = "tcp" })
+	defer r.Shutdown(context.Background())
+
+	conn, err := net.Dial("tcp", r.listener.Addr().String())
+	require.NoError(t, err)
+	// Octet counting and LF delimited frames, the last one not being
+	// terminated.
+	_, err = conn.Write([]byte("33 <34>1 - host app - - - first\nline\n<13>second\n<13>third"))
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+
+	require.Eventually(t, func() bool {
+		return sink.LogRecordsCount() == 3
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"first\nline", "second", "third"}, bodies(sink))
+}
+
+func TestTCPReceiverFramingError(t *testing.T) {
+	r, sink := newTestReceiver(t, func(cfg *Config) { cfg.Transport = "tcp" })
+	defer r.Shutdown(context.Background())
+
+	conn, err := net.Dial("tcp", r.listener.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = conn.Write([]byte("<13>first\n999999 <13>too large"))
+	require.NoError(t, err)
+
+	// The connection is closed by the receiver.
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	_, err = conn.Read(make([]byte, 1))
+	assert.Error(t, err)
+	assert.NotContains(t, err.Error(), "timeout")
+	assert.Equal(t, []string{"first"}, bodies(sink))
+}
+
+func TestShutdownOpenConnection(t *testing.T) {
+	r, _ := newTestReceiver(t, func(cfg *Config) { cfg.Transport = "tcp" })
+
+	conn, err := net.Dial("tcp", r.listener.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+	require.Eventually(t, func() bool {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		return len(r.conns) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	assert.NoError(t, r.Shutdown(context.Background()))
+}
+
+// lateListener accepts its connection once it is released, then fails.
+type lateListener struct {
+	net.Listener
+	conn    net.Conn
+	release chan struct{}
+}
+
+func (l *lateListener) Accept() (net.Conn, error) {
+	if l.conn == nil {
+		return nil, errors.New("listener closed")
+	}
+	<-l.release
+	conn := l.conn
+	l.conn = nil
+	return conn, nil
+}
+
+func (l *lateListener) Close() error {
+	return nil
+}
+
+func TestShutdownLateConnection(t *testing.T) {
+	r := newSyslogReceiver(zap.NewNop(), createDefaultConfig().(*Config), time.UTC, new(consumertest.LogsSink))
+	server, client := net.Pipe()
+	defer client.Close()
+	l := &lateListener{conn: server, release: make(chan struct{})}
+	r.listener = l
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		r.accept()
+	}()
+
+	done := make(chan error, 1)
+	go func() { done <- r.Shutdown(context.Background()) }()
+	require.Eventually(t, func() bool {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		return r.closed
+	}, 5*time.Second, 10*time.Millisecond)
+	// the connection is accepted after the open connections were closed.
+	close(l.release)
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Shutdown did not return")
+	}
+}
+
+func writeSelfSignedCert(t *testing.T, dir string) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	cert, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyBytes, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	require.NoError(t, ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert}), 0600))
+	require.NoError(t, ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyBytes}), 0600))
+	return certFile, keyFile
+}
+
+func TestTLSReceiver(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "syslog-tls")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	certFile, keyFile := writeSelfSignedCert(t, tmpdir)
+	r, sink := newTestReceiver(t, func(cfg *Config) {
+		cfg.Transport = "tcp"
+		cfg.TLSSetting = &configtls.TLSServerSetting{
+			TLSSetting: configtls.TLSSetting{CertFile: certFile, KeyFile: keyFile},
+		}
+	})
+	defer r.Shutdown(context.Background())
+
+	conn, err := tls.Dial("tcp", r.listener.Addr().String(), &tls.Config{InsecureSkipVerify: true})
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = conn.Write([]byte("29 <34>1 - host app - - - secure"))
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		return sink.LogRecordsCount() == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"secure"}, bodies(sink))
+}
+
+func TestStartError(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Transport = "tcp"
+	cfg.Endpoint = "localhost:invalid"
+	r := newSyslogReceiver(zap.NewNop(), cfg, time.UTC, new(consumertest.LogsSink))
+	assert.Error(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	assert.NoError(t, r.Shutdown(context.Background()))
+
+	cfg.TLSSetting = &configtls.TLSServerSetting{
+		TLSSetting: configtls.TLSSetting{CertFile: "/nonexistent/cert.pem", KeyFile: "/nonexistent/key.pem"},
+	}
+	assert.Error(t, r.Start(context.Background(), componenttest.NewNopHost()))
+}
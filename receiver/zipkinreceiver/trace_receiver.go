@@ -22,12 +22,15 @@ import (
 	"errors"
 	"io"
 	"io/ioutil"
+	"mime"
 	"net"
 	"net/http"
 	"strings"
 	"sync"
 
+	"github.com/apache/thrift/lib/go/thrift"
 	jaegerzipkin "github.com/jaegertracing/jaeger/model/converter/thrift/zipkin"
+	"github.com/jaegertracing/jaeger/thrift-gen/zipkincore"
 	zipkinmodel "github.com/openzipkin/zipkin-go/model"
 	"github.com/openzipkin/zipkin-go/proto/zipkin_proto3"
 
@@ -95,7 +98,10 @@ func (zr *ZipkinReceiver) Start(ctx context.Context, host component.Host) error
 	zr.startOnce.Do(func() {
 		err = nil
 		zr.host = host
-		zr.server = zr.config.HTTPServerSettings.ToServer(zr)
+		zr.server, err = zr.config.HTTPServerSettings.ToServer(zr)
+		if err != nil {
+			return
+		}
 		var listener net.Listener
 		listener, err = zr.config.HTTPServerSettings.ToListener()
 		if err != nil {
@@ -115,8 +121,8 @@ func (zr *ZipkinReceiver) Start(ctx context.Context, host component.Host) error
 
 // v1ToTraceSpans parses Zipkin v1 JSON traces and converts them to OpenCensus Proto spans.
 func (zr *ZipkinReceiver) v1ToTraceSpans(blob []byte, hdr http.Header) (reqs pdata.Traces, err error) {
-	if hdr.Get("Content-Type") == "application/x-thrift" {
-		zSpans, err := jaegerzipkin.DeserializeThrift(blob)
+	if contentType(hdr) == "application/x-thrift" {
+		zSpans, err := deserializeThrift(blob)
 		if err != nil {
 			return pdata.NewTraces(), err
 		}
@@ -126,6 +132,37 @@ func (zr *ZipkinReceiver) v1ToTraceSpans(blob []byte, hdr http.Header) (reqs pda
 	return zipkin.V1JSONBatchToInternalTraces(blob, zr.config.ParseStringTags)
 }
 
+// deserializeThrift decodes the Zipkin v1 Thrift spans encoded as a list, or as the
+// concatenation of spans bulk posted by the legacy Kafka transports.
+func deserializeThrift(blob []byte) ([]*zipkincore.Span, error) {
+	// A list starts with the type of its elements, a span with the type of its first field.
+	if len(blob) == 0 || blob[0] == byte(thrift.STRUCT) {
+		return jaegerzipkin.DeserializeThrift(blob)
+	}
+
+	buffer := thrift.NewTMemoryBuffer()
+	buffer.Write(blob)
+	protocol := thrift.NewTBinaryProtocolTransport(buffer)
+	var zSpans []*zipkincore.Span
+	for buffer.Len() > 0 {
+		zSpan := &zipkincore.Span{}
+		if err := zSpan.Read(protocol); err != nil {
+			return nil, err
+		}
+		zSpans = append(zSpans, zSpan)
+	}
+	return zSpans, nil
+}
+
+// contentType returns the media type of the Content-Type header, without its parameters.
+func contentType(hdr http.Header) string {
+	mediaType, _, err := mime.ParseMediaType(hdr.Get("Content-Type"))
+	if err != nil {
+		return hdr.Get("Content-Type")
+	}
+	return mediaType
+}
+
 // v2ToTraceSpans parses Zipkin v2 JSON or Protobuf traces and converts them to OpenCensus Proto spans.
 func (zr *ZipkinReceiver) v2ToTraceSpans(blob []byte, hdr http.Header) (reqs pdata.Traces, err error) {
 	// This flag's reference is from:
@@ -135,7 +172,7 @@ func (zr *ZipkinReceiver) v2ToTraceSpans(blob []byte, hdr http.Header) (reqs pda
 	var zipkinSpans []*zipkinmodel.SpanModel
 
 	// Zipkin can send protobuf via http
-	switch hdr.Get("Content-Type") {
+	switch contentType(hdr) {
 	// TODO: (@odeke-em) record the unique types of Content-Type uploads
 	case "application/x-protobuf":
 		zipkinSpans, err = zipkin_proto3.ParseSpans(blob, debugWasSet)
@@ -268,12 +305,12 @@ func (zr *ZipkinReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 func transportType(r *http.Request) string {
 	v1 := r.URL != nil && strings.Contains(r.URL.Path, "api/v1/spans")
 	if v1 {
-		if r.Header.Get("Content-Type") == "application/x-thrift" {
+		if contentType(r.Header) == "application/x-thrift" {
 			return receiverTransportV1Thrift
 		}
 		return receiverTransportV1JSON
 	}
-	if r.Header.Get("Content-Type") == "application/x-protobuf" {
+	if contentType(r.Header) == "application/x-protobuf" {
 		return receiverTransportV2PROTO
 	}
 	return receiverTransportV2JSON
@@ -30,6 +30,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/apache/thrift/lib/go/thrift"
 	zipkin2 "github.com/jaegertracing/jaeger/model/converter/thrift/zipkin"
 	"github.com/jaegertracing/jaeger/thrift-gen/zipkincore"
 	"github.com/stretchr/testify/assert"
@@ -333,6 +334,24 @@ func TestReceiverContentTypes(t *testing.T) {
 			},
 		},
 
+		{
+			endpoint: "/api/v1/spans",
+			content:  "application/x-thrift",
+			encoding: "",
+			bodyFn: func() ([]byte, error) {
+				return thriftConcatenatedExample(), nil
+			},
+		},
+
+		{
+			endpoint: "/api/v1/spans",
+			content:  "application/json; charset=utf-8",
+			encoding: "",
+			bodyFn: func() ([]byte, error) {
+				return ioutil.ReadFile("../../translator/trace/zipkin/testdata/zipkin_v1_address_annotations.json")
+			},
+		},
+
 		{
 			endpoint: "/api/v2/spans",
 			content:  "application/json",
@@ -489,6 +508,61 @@ func thriftExample() []byte {
 	return zipkin2.SerializeThrift(zSpans)
 }
 
+// thriftConcatenatedExample encodes spans one after the other, without a list, as
+// the legacy Kafka transports do.
+func thriftConcatenatedExample() []byte {
+	now := time.Now().Unix()
+	buffer := thrift.NewTMemoryBuffer()
+	protocol := thrift.NewTBinaryProtocolTransport(buffer)
+	for _, id := range []int64{2, 3} {
+		zSpan := &zipkincore.Span{
+			TraceID:   1,
+			Name:      "test",
+			ID:        id,
+			Timestamp: &now,
+		}
+		if err := zSpan.Write(protocol); err != nil {
+			panic(err)
+		}
+	}
+	return buffer.Bytes()
+}
+
+func TestDeserializeThrift(t *testing.T) {
+	zSpans, err := deserializeThrift(thriftExample())
+	require.NoError(t, err)
+	assert.Len(t, zSpans, 1)
+
+	zSpans, err = deserializeThrift(thriftConcatenatedExample())
+	require.NoError(t, err)
+	require.Len(t, zSpans, 2)
+	assert.Equal(t, int64(2), zSpans[0].ID)
+	assert.Equal(t, int64(3), zSpans[1].ID)
+
+	concatenated := thriftConcatenatedExample()
+	_, err = deserializeThrift(concatenated[:len(concatenated)-1])
+	assert.Error(t, err)
+}
+
+func TestContentType(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{header: "application/x-thrift", want: "application/x-thrift"},
+		{header: "application/json; charset=utf-8", want: "application/json"},
+		{header: "Application/X-Protobuf", want: "application/x-protobuf"},
+		{header: "", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			hdr := http.Header{}
+			hdr.Set("Content-Type", tt.header)
+			assert.Equal(t, tt.want, contentType(hdr))
+		})
+	}
+}
+
 func compressGzip(body []byte) (*bytes.Buffer, error) {
 	var buf bytes.Buffer
 	zw := gzip.NewWriter(&buf)
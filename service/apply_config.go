@@ -0,0 +1,175 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v2"
+
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/configcheck"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/service/internal/builder"
+)
+
+// GetEffectiveConfig returns the configuration the collector runs with, as a YAML document.
+func (app *Application) GetEffectiveConfig() ([]byte, error) {
+	app.configMu.RLock()
+	defer app.configMu.RUnlock()
+	if app.v == nil {
+		return nil, errors.New("the configuration is not loaded")
+	}
+	return yaml.Marshal(app.v.AllSettings())
+}
+
+// ApplyConfig replaces the receivers, processors, exporters and pipelines of the running
+// collector by the ones of the given YAML configuration. The configuration is validated and the
+// new pipelines are built before the running pipelines are stopped, the running pipelines are
+// then drained and the new ones started in their place. The previous pipelines are restored when
+// the new ones fail to start. The extensions cannot be changed, they would have to be restarted
+// and the configuration is usually applied by one of them.
+func (app *Application) ApplyConfig(ctx context.Context, rawCfg []byte) error {
+	v := config.NewViper()
+	v.SetConfigType("yaml")
+	if err := v.ReadConfig(bytes.NewReader(rawCfg)); err != nil {
+		return fmt.Errorf("cannot read configuration: %w", err)
+	}
+	return app.applyViper(ctx, v)
+}
+
+// applyViper applies the configuration loaded in v, see ApplyConfig.
+func (app *Application) applyViper(ctx context.Context, v *viper.Viper) error {
+	if err := configcheck.ValidateConfigFromFactories(app.factories); err != nil {
+		return err
+	}
+	cfg, err := config.Load(v, app.factories)
+	if err != nil {
+		return fmt.Errorf("cannot load configuration: %w", err)
+	}
+	if err = cfg.Validate(); err != nil {
+		return fmt.Errorf("cannot load configuration: %w", err)
+	}
+
+	app.configMu.Lock()
+	if !app.configurable {
+		app.configMu.Unlock()
+		return errors.New("the collector is not running")
+	}
+	if app.paused {
+		app.configMu.Unlock()
+		return errors.New("the pipelines are paused")
+	}
+	if !reflect.DeepEqual(cfg.Extensions, app.config.Extensions) ||
+		!reflect.DeepEqual(cfg.Service.Extensions, app.config.Service.Extensions) {
+		app.configMu.Unlock()
+		return errors.New("the extensions cannot be changed without restarting the collector")
+	}
+
+	// The new pipelines are built alongside the running ones, a configuration that cannot be
+	// built is refused without interrupting them.
+	built, err := app.buildPipelines(cfg)
+	if err != nil {
+		app.configMu.Unlock()
+		return fmt.Errorf("cannot build the pipelines: %w", err)
+	}
+
+	previous := app.config
+	app.notifySystemd(sdNotifyReloading)
+	defer app.notifySystemd(sdNotifyReady)
+	err = app.swapPipelines(ctx, cfg, built)
+	if err == nil {
+		app.v = v
+		app.configMu.Unlock()
+		return nil
+	}
+	app.logger.Error("Failed to apply the configuration, restoring the previous one", zap.Error(err))
+	rerr := app.replacePipelines(ctx, previous)
+	app.configMu.Unlock()
+	if rerr != nil {
+		rerr = fmt.Errorf("failed to apply the configuration: %v, failed to restore the previous configuration: %w", err, rerr)
+		go app.ReportFatalError(rerr)
+		return rerr
+	}
+	return fmt.Errorf("failed to apply the configuration, the previous configuration is restored: %w", err)
+}
+
+// builtPipelines are the components of a configuration, built but not started.
+type builtPipelines struct {
+	exporters builder.Exporters
+	pipelines builder.BuiltPipelines
+	receivers builder.Receivers
+}
+
+// buildPipelines builds the exporters, processors and receivers of cfg without starting them.
+func (app *Application) buildPipelines(cfg *configmodels.Config) (*builtPipelines, error) {
+	// Pipeline is built backwards, starting from exporters, so that we create objects
+	// which are referenced before objects which reference them.
+	exporters, err := builder.BuildExporters(app.logger, app.info, cfg, app.factories.Exporters)
+	if err != nil {
+		return nil, fmt.Errorf("cannot build builtExporters: %w", err)
+	}
+	pipelines, err := builder.BuildPipelines(app.logger, app.info, cfg, exporters, app.factories.Processors)
+	if err != nil {
+		return nil, fmt.Errorf("cannot build pipelines: %w", err)
+	}
+	receivers, err := builder.BuildReceivers(app.logger, app.info, cfg, pipelines, app.factories.Receivers)
+	if err != nil {
+		return nil, fmt.Errorf("cannot build receivers: %w", err)
+	}
+	return &builtPipelines{exporters: exporters, pipelines: pipelines, receivers: receivers}, nil
+}
+
+// replacePipelines stops the running pipelines and starts the ones of the given configuration.
+// It must be called with configMu held.
+func (app *Application) replacePipelines(ctx context.Context, cfg *configmodels.Config) error {
+	built, err := app.buildPipelines(cfg)
+	if err != nil {
+		return err
+	}
+	return app.swapPipelines(ctx, cfg, built)
+}
+
+// swapPipelines stops the running pipelines and starts the built ones in their place. The
+// running receivers are stopped first and the processors and exporters shut down after them, so
+// that the data they hold is sent before the new pipelines start. It must be called with configMu
+// held.
+func (app *Application) swapPipelines(ctx context.Context, cfg *configmodels.Config, built *builtPipelines) error {
+	if err := app.builtExtensions.NotifyPipelineNotReady(); err != nil {
+		app.logger.Warn("Failed to notify that pipeline is not ready", zap.Error(err))
+	}
+	if err := app.shutdownPipelines(ctx); err != nil {
+		app.logger.Warn("Failed to shutdown pipelines", zap.Error(err))
+	}
+
+	app.config = cfg
+	app.builtExporters, app.builtPipelines, app.builtReceivers = built.exporters, built.pipelines, built.receivers
+	app.logger.Info("Applying configuration...")
+	if err := app.startPipelines(ctx); err != nil {
+		// Stop the components started before the failure.
+		if serr := app.shutdownPipelines(ctx); serr != nil {
+			app.logger.Warn("Failed to shutdown pipelines", zap.Error(serr))
+		}
+		app.builtReceivers, app.builtPipelines, app.builtExporters = nil, nil, nil
+		return err
+	}
+	return app.builtExtensions.NotifyPipelineReady()
+}
@@ -0,0 +1,247 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+)
+
+const applyConfigBase = `
+receivers:
+  nop:
+processors:
+  nop:
+exporters:
+  nop:
+extensions:
+  nop:
+service:
+  extensions: [nop]
+  pipelines:
+    traces:
+      receivers: [nop]
+      processors: [nop]
+      exporters: [nop]
+`
+
+const applyConfigNew = `
+receivers:
+  nop:
+exporters:
+  nop:
+  nop/2:
+extensions:
+  nop:
+service:
+  extensions: [nop]
+  pipelines:
+    metrics:
+      receivers: [nop]
+      exporters: [nop, nop/2]
+`
+
+const applyConfigFailing = `
+receivers:
+  nop:
+exporters:
+  failing:
+extensions:
+  nop:
+service:
+  extensions: [nop]
+  pipelines:
+    traces:
+      receivers: [nop]
+      exporters: [failing]
+`
+
+const applyConfigUnbuildable = `
+receivers:
+  nop:
+exporters:
+  unbuildable:
+extensions:
+  nop:
+service:
+  extensions: [nop]
+  pipelines:
+    traces:
+      receivers: [nop]
+      exporters: [unbuildable]
+`
+
+const applyConfigOtherExtensions = `
+receivers:
+  nop:
+exporters:
+  nop:
+extensions:
+  nop/2:
+service:
+  extensions: [nop/2]
+  pipelines:
+    traces:
+      receivers: [nop]
+      exporters: [nop]
+`
+
+func newFailingExporterFactory() component.ExporterFactory {
+	return exporterhelper.NewFactory(
+		"failing",
+		func() configmodels.Exporter {
+			return &configmodels.ExporterSettings{TypeVal: "failing", NameVal: "failing"}
+		},
+		exporterhelper.WithTraces(func(_ context.Context, params component.ExporterCreateParams, cfg configmodels.Exporter) (component.TracesExporter, error) {
+			return exporterhelper.NewTraceExporter(cfg, params.Logger,
+				func(context.Context, pdata.Traces) (int, error) { return 0, nil },
+				exporterhelper.WithStart(func(context.Context, component.Host) error { return errors.New("cannot start") }))
+		}))
+}
+
+func newUnbuildableExporterFactory() component.ExporterFactory {
+	return exporterhelper.NewFactory(
+		"unbuildable",
+		func() configmodels.Exporter {
+			return &configmodels.ExporterSettings{TypeVal: "unbuildable", NameVal: "unbuildable"}
+		},
+		exporterhelper.WithTraces(func(context.Context, component.ExporterCreateParams, configmodels.Exporter) (component.TracesExporter, error) {
+			return nil, errors.New("cannot create")
+		}))
+}
+
+func startApplyConfigApplication(t *testing.T) (*Application, func()) {
+	factories, err := componenttest.NopFactories()
+	require.NoError(t, err)
+	failing := newFailingExporterFactory()
+	factories.Exporters[failing.Type()] = failing
+	unbuildable := newUnbuildableExporterFactory()
+	factories.Exporters[unbuildable.Type()] = unbuildable
+
+	app, err := New(Parameters{
+		ApplicationStartInfo: component.DefaultApplicationStartInfo(),
+		ConfigFactory: func(v *viper.Viper, _ *cobra.Command, factories component.Factories) (*configmodels.Config, error) {
+			v.SetConfigType("yaml")
+			if err := v.ReadConfig(bytes.NewReader([]byte(applyConfigBase))); err != nil {
+				return nil, err
+			}
+			return config.Load(v, factories)
+		},
+		Factories: factories,
+	})
+	require.NoError(t, err)
+	// The telemetry flags are global, the metrics address is set by the tests serving the metrics.
+	app.Command().SetArgs([]string{"--metrics-addr="})
+
+	appDone := make(chan struct{})
+	go func() {
+		defer close(appDone)
+		assert.NoError(t, app.Run())
+	}()
+	assert.Equal(t, Starting, <-app.GetStateChannel())
+	assert.Equal(t, Running, <-app.GetStateChannel())
+
+	return app, func() {
+		app.Shutdown()
+		<-appDone
+		assert.Equal(t, Closing, <-app.GetStateChannel())
+		assert.Equal(t, Closed, <-app.GetStateChannel())
+	}
+}
+
+func TestApplication_ApplyConfig(t *testing.T) {
+	app, stop := startApplyConfigApplication(t)
+	defer stop()
+
+	effective, err := app.GetEffectiveConfig()
+	require.NoError(t, err)
+	assert.Contains(t, string(effective), "processors:")
+
+	require.NoError(t, app.ApplyConfig(context.Background(), []byte(applyConfigNew)))
+	require.Contains(t, app.builtPipelines, app.config.Service.Pipelines["metrics"])
+	assert.Len(t, app.builtPipelines, 1)
+	assert.Len(t, app.GetExporters()[configmodels.MetricsDataType], 2)
+
+	effective, err = app.GetEffectiveConfig()
+	require.NoError(t, err)
+	assert.Contains(t, string(effective), "nop/2")
+	assert.NotContains(t, string(effective), "processors:")
+}
+
+func TestApplication_ApplyConfigInvalid(t *testing.T) {
+	app, stop := startApplyConfigApplication(t)
+	defer stop()
+	previous := app.config
+
+	assert.Error(t, app.ApplyConfig(context.Background(), []byte("receivers: [")))
+	assert.Error(t, app.ApplyConfig(context.Background(), []byte("receivers:\n  unknown:\n")))
+	err := app.ApplyConfig(context.Background(), []byte(applyConfigOtherExtensions))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "extensions cannot be changed")
+
+	assert.Same(t, previous, app.config)
+	assert.Len(t, app.builtPipelines, 1)
+}
+
+func TestApplication_ApplyConfigRollback(t *testing.T) {
+	app, stop := startApplyConfigApplication(t)
+	defer stop()
+	previous := app.config
+
+	err := app.ApplyConfig(context.Background(), []byte(applyConfigFailing))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "the previous configuration is restored")
+	assert.Same(t, previous, app.config)
+	require.Contains(t, app.builtPipelines, previous.Service.Pipelines["traces"])
+	assert.Len(t, app.GetExporters()[configmodels.TracesDataType], 1)
+
+	effective, err := app.GetEffectiveConfig()
+	require.NoError(t, err)
+	assert.NotContains(t, string(effective), "failing")
+}
+
+func TestApplication_ApplyConfigUnbuildable(t *testing.T) {
+	app, stop := startApplyConfigApplication(t)
+	defer stop()
+	previous := app.config
+	running := app.builtPipelines[previous.Service.Pipelines["traces"]]
+
+	// The running pipelines are not stopped when the new ones cannot be built.
+	err := app.ApplyConfig(context.Background(), []byte(applyConfigUnbuildable))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot build the pipelines")
+	assert.Same(t, previous, app.config)
+	assert.Same(t, running, app.builtPipelines[previous.Service.Pipelines["traces"]])
+}
+
+func TestApplication_ApplyConfigNotRunning(t *testing.T) {
+	app := &Application{logger: zap.NewNop()}
+	assert.Error(t, app.ApplyConfig(context.Background(), []byte(applyConfigBase)))
+}
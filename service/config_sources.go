@@ -0,0 +1,137 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/configprovider"
+	"go.opentelemetry.io/collector/service/internal/builder"
+)
+
+// readConfigSources reads and merges the configurations passed via the --config flags: the
+// local files and directories, and the remote configurations retrieved by the providers.
+func readConfigSources(ctx context.Context, uris []string) (map[string]interface{}, error) {
+	if len(uris) == 0 {
+		return nil, errors.New("config file not specified")
+	}
+	merged := map[string]interface{}{}
+	for _, uri := range uris {
+		if !configprovider.IsRemote(uri) {
+			if err := config.MergeFile(merged, uri); err != nil {
+				return nil, fmt.Errorf("error loading config file %q: %v", uri, err)
+			}
+			continue
+		}
+		provider, err := configprovider.New(uri, configprovider.GetFlagSettings())
+		if err != nil {
+			return nil, err
+		}
+		content, err := provider.Retrieve(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving config %q: %v", uri, err)
+		}
+		if err := config.MergeYAML(merged, content, uri); err != nil {
+			return nil, err
+		}
+	}
+	return merged, nil
+}
+
+// startConfigRefresh re-reads the configuration every --config-refresh-interval when it was
+// loaded by FileLoaderConfigFactory, and applies it when it changed. It returns the function
+// stopping the refresh.
+func (app *Application) startConfigRefresh(ctx context.Context) func() {
+	interval := configprovider.GetRefreshInterval()
+	if !app.refreshConfig || interval <= 0 {
+		return func() {}
+	}
+	uris := builder.GetConfigFiles()
+	app.loadConfigSources(ctx, uris)
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				app.refreshConfigSources(ctx, uris)
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		wg.Wait()
+	}
+}
+
+// loadConfigSources reads the configuration the next ones are compared with, unless it was
+// already read.
+func (app *Application) loadConfigSources(ctx context.Context, uris []string) {
+	app.configSourcesMu.Lock()
+	defer app.configSourcesMu.Unlock()
+	if app.configSources != nil {
+		return
+	}
+	merged, err := readConfigSources(ctx, uris)
+	if err != nil {
+		app.logger.Warn("Failed to read the configuration", zap.Error(err))
+		return
+	}
+	app.configSources = merged
+}
+
+// refreshConfigSources applies the configuration read from uris if it is different from the
+// previous one.
+func (app *Application) refreshConfigSources(ctx context.Context, uris []string) {
+	app.configSourcesMu.Lock()
+	defer app.configSourcesMu.Unlock()
+	merged, err := readConfigSources(ctx, uris)
+	if err != nil {
+		app.logger.Warn("Failed to read the configuration", zap.Error(err))
+		return
+	}
+	if reflect.DeepEqual(merged, app.configSources) {
+		return
+	}
+	// A configuration failing to apply is not retried until it changes.
+	app.configSources = merged
+
+	app.logger.Info("The configuration changed, applying it")
+	v := config.NewViper()
+	if err = v.MergeConfigMap(merged); err == nil {
+		err = AddSetFlagProperties(v, app.rootCmd)
+	}
+	if err == nil {
+		err = app.applyViper(ctx, v)
+	}
+	if err != nil {
+		app.logger.Error("Failed to apply the configuration", zap.Error(err))
+	}
+}
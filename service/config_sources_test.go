@@ -0,0 +1,118 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/testutil"
+)
+
+func newConfigServer(content *atomic.Value) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(content.Load().(string)))
+	}))
+}
+
+func TestReadConfigSources(t *testing.T) {
+	var content atomic.Value
+	content.Store(`
+processors:
+  batch:
+    timeout: 2s
+`)
+	server := newConfigServer(&content)
+	defer server.Close()
+
+	merged, err := readConfigSources(context.Background(), []string{"testdata/otelcol-config-minimal.yaml", server.URL})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"batch": map[string]interface{}{"timeout": "2s"}}, merged["processors"])
+	assert.Contains(t, merged, "receivers")
+}
+
+func TestReadConfigSourcesErrors(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	_, err := readConfigSources(context.Background(), nil)
+	assert.EqualError(t, err, "config file not specified")
+
+	_, err = readConfigSources(context.Background(), []string{"testdata/missing.yaml"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `error loading config file "testdata/missing.yaml"`)
+
+	_, err = readConfigSources(context.Background(), []string{server.URL})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "404 Not Found")
+}
+
+func TestApplication_ConfigRefresh(t *testing.T) {
+	var content atomic.Value
+	content.Store(applyConfigBase)
+	server := newConfigServer(&content)
+	defer server.Close()
+
+	factories, err := componenttest.NopFactories()
+	require.NoError(t, err)
+	app, err := New(Parameters{
+		ApplicationStartInfo: component.DefaultApplicationStartInfo(),
+		Factories:            factories,
+	})
+	require.NoError(t, err)
+	app.Command().SetArgs([]string{
+		"--metrics-addr=",
+		"--config=" + server.URL,
+		"--config-refresh-interval=10ms",
+	})
+
+	appDone := make(chan struct{})
+	go func() {
+		defer close(appDone)
+		assert.NoError(t, app.Run())
+	}()
+	assert.Equal(t, Starting, <-app.GetStateChannel())
+	assert.Equal(t, Running, <-app.GetStateChannel())
+
+	content.Store(applyConfigNew)
+	testutil.WaitFor(t, func() bool {
+		app.configMu.RLock()
+		defer app.configMu.RUnlock()
+		return app.config.Service.Pipelines["metrics"] != nil
+	}, "apply the refreshed configuration")
+	assert.Len(t, app.GetExporters()[configmodels.MetricsDataType], 2)
+
+	// An invalid configuration is not applied.
+	content.Store("receivers: [")
+	time.Sleep(50 * time.Millisecond)
+	app.configMu.RLock()
+	assert.NotNil(t, app.config.Service.Pipelines["metrics"])
+	app.configMu.RUnlock()
+
+	app.Shutdown()
+	<-appDone
+	assert.Equal(t, Closing, <-app.GetStateChannel())
+	assert.Equal(t, Closed, <-app.GetStateChannel())
+}
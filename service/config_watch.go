@@ -0,0 +1,112 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/config/configprovider"
+	"go.opentelemetry.io/collector/service/internal/builder"
+)
+
+// configWatchDelay is the time waited after a change of the config files before reading them,
+// editors and Kubernetes usually write the files in several steps.
+var configWatchDelay = 500 * time.Millisecond
+
+// startConfigWatch watches the local config files and directories with --config-watch when the
+// configuration was loaded by FileLoaderConfigFactory, and applies the configuration when they
+// change. It returns the function stopping the watch.
+func (app *Application) startConfigWatch(ctx context.Context) func() {
+	if !app.refreshConfig || !builder.GetConfigWatch() {
+		return func() {}
+	}
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		app.logger.Warn("Failed to watch the config files", zap.Error(err))
+		return func() {}
+	}
+	uris := builder.GetConfigFiles()
+	for _, dir := range configWatchDirs(uris) {
+		if err = watcher.Add(dir); err != nil {
+			app.logger.Warn("Failed to watch the config files", zap.String("path", dir), zap.Error(err))
+		}
+	}
+	app.loadConfigSources(ctx, uris)
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		var delay <-chan time.Time
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if event.Op != fsnotify.Chmod {
+					delay = time.After(configWatchDelay)
+				}
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				app.logger.Warn("Failed to watch the config files", zap.Error(err))
+			case <-delay:
+				delay = nil
+				app.refreshConfigSources(ctx, uris)
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		wg.Wait()
+		watcher.Close()
+	}
+}
+
+// configWatchDirs returns the directories to watch for the local config files and directories.
+// The directories of the files are watched rather than the files, the files replaced by a rename
+// or a symbolic link update, as Kubernetes does for the mounted ConfigMaps, would otherwise no
+// longer be watched. The files of the other directories, included by the config files, are not
+// watched.
+func configWatchDirs(uris []string) []string {
+	var dirs []string
+	seen := map[string]bool{}
+	for _, uri := range uris {
+		if configprovider.IsRemote(uri) {
+			continue
+		}
+		dir := uri
+		if info, err := os.Stat(uri); err != nil || !info.IsDir() {
+			dir = filepath.Dir(uri)
+		}
+		if !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
@@ -0,0 +1,95 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/testutil"
+)
+
+func TestConfigWatchDirs(t *testing.T) {
+	assert.Equal(t, []string{"testdata", "conf.d"}, configWatchDirs([]string{
+		"testdata/otelcol-config.yaml",
+		"testdata",
+		"https://example.com/config.yaml",
+		"conf.d/missing.yaml",
+	}))
+}
+
+func TestApplication_ConfigWatch(t *testing.T) {
+	defer func(delay time.Duration) { configWatchDelay = delay }(configWatchDelay)
+	configWatchDelay = 10 * time.Millisecond
+
+	dir, err := ioutil.TempDir("", "config-watch")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "config.yaml")
+	require.NoError(t, ioutil.WriteFile(file, []byte(applyConfigBase), 0600))
+
+	factories, err := componenttest.NopFactories()
+	require.NoError(t, err)
+	app, err := New(Parameters{
+		ApplicationStartInfo: component.DefaultApplicationStartInfo(),
+		Factories:            factories,
+	})
+	require.NoError(t, err)
+	app.Command().SetArgs([]string{
+		"--metrics-addr=",
+		"--config=" + file,
+		"--config-watch",
+	})
+
+	appDone := make(chan struct{})
+	go func() {
+		defer close(appDone)
+		assert.NoError(t, app.Run())
+	}()
+	assert.Equal(t, Starting, <-app.GetStateChannel())
+	assert.Equal(t, Running, <-app.GetStateChannel())
+
+	// The file is replaced by a rename, as most editors do.
+	tmp := filepath.Join(dir, "config.yaml.tmp")
+	require.NoError(t, ioutil.WriteFile(tmp, []byte(applyConfigNew), 0600))
+	require.NoError(t, os.Rename(tmp, file))
+	testutil.WaitFor(t, func() bool {
+		app.configMu.RLock()
+		defer app.configMu.RUnlock()
+		return app.config.Service.Pipelines["metrics"] != nil
+	}, "apply the changed configuration")
+	assert.Len(t, app.GetExporters()[configmodels.MetricsDataType], 2)
+
+	// An invalid configuration is not applied.
+	require.NoError(t, ioutil.WriteFile(file, []byte("receivers: ["), 0600))
+	time.Sleep(50 * time.Millisecond)
+	app.configMu.RLock()
+	assert.NotNil(t, app.config.Service.Pipelines["metrics"])
+	app.configMu.RUnlock()
+
+	app.Shutdown()
+	<-appDone
+	assert.Equal(t, Closing, <-app.GetStateChannel())
+	assert.Equal(t, Closed, <-app.GetStateChannel())
+}
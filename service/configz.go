@@ -0,0 +1,85 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/configcheck"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/service/internal/zpages"
+)
+
+const (
+	configzPath = "configz"
+
+	dumpConfigFlagName = "dump-config"
+)
+
+// marshalRedactedConfig returns the YAML document of the loaded configuration, with the sensitive
+// settings redacted, see config.ToRedactedMap.
+func marshalRedactedConfig(cfg *configmodels.Config) ([]byte, error) {
+	return yaml.Marshal(config.ToRedactedMap(cfg))
+}
+
+// GetRedactedConfig returns the configuration the collector runs with, with the default values
+// of the components and the sensitive settings redacted, as a YAML document.
+func (app *Application) GetRedactedConfig() ([]byte, error) {
+	app.configMu.RLock()
+	defer app.configMu.RUnlock()
+	if app.config == nil {
+		return nil, errors.New("the configuration is not loaded")
+	}
+	return marshalRedactedConfig(app.config)
+}
+
+// handleConfigzRequest writes the configuration the collector runs with, redacted.
+func (app *Application) handleConfigzRequest(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+	cfg, err := app.GetRedactedConfig()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	zpages.WriteHTMLHeader(w, zpages.HeaderData{Title: "Effective Configuration"})
+	fmt.Fprintf(w, "<pre>%s</pre>\n", html.EscapeString(string(cfg)))
+	zpages.WriteHTMLFooter(w)
+}
+
+// dumpConfig loads the configuration with the factory and writes it, redacted, to the output of
+// the command.
+func (app *Application) dumpConfig(cmd *cobra.Command, factory ConfigFactory) error {
+	if err := configcheck.ValidateConfigFromFactories(app.factories); err != nil {
+		return err
+	}
+	cfg, err := factory(app.v, cmd, app.factories)
+	if err != nil {
+		return fmt.Errorf("cannot load configuration: %w", err)
+	}
+	out, err := marshalRedactedConfig(cfg)
+	if err != nil {
+		return err
+	}
+	_, err = cmd.OutOrStdout().Write(out)
+	return err
+}
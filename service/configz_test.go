@@ -0,0 +1,74 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/exporter/otlpexporter"
+	"go.opentelemetry.io/collector/service/defaultcomponents"
+)
+
+func TestDumpConfigFlag(t *testing.T) {
+	factories, err := defaultcomponents.Components()
+	require.NoError(t, err)
+	app, err := New(Parameters{Factories: factories})
+	require.NoError(t, err)
+	out := &bytes.Buffer{}
+	app.rootCmd.SetOut(out)
+	app.rootCmd.SetArgs([]string{"--config=testdata/otelcol-config.yaml", "--set=exporters.opencensus.headers.x-api-key=secret", "--dump-config"})
+
+	require.NoError(t, app.Run())
+	dump := out.String()
+	// The default values of the components are shown.
+	assert.Contains(t, dump, "send_batch_size: 8192")
+	assert.Contains(t, dump, "x-api-key: '[REDACTED]'")
+	assert.NotContains(t, dump, "secret")
+	// The collector is not started.
+	assert.Nil(t, app.builtExporters)
+}
+
+func TestHandleConfigzRequest(t *testing.T) {
+	app := &Application{}
+	rec := httptest.NewRecorder()
+	app.handleConfigzRequest(rec, httptest.NewRequest(http.MethodGet, "/debug/configz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+	exporterCfg := otlpexporter.NewFactory().CreateDefaultConfig().(*otlpexporter.Config)
+	exporterCfg.Headers = map[string]string{"authorization": "Bearer <secret>"}
+	app.config = &configmodels.Config{
+		Exporters: configmodels.Exporters{"otlp": exporterCfg},
+		Service: configmodels.Service{
+			Pipelines: configmodels.Pipelines{
+				"traces": {Name: "traces", InputType: configmodels.TracesDataType, Exporters: []string{"otlp"}},
+			},
+		},
+	}
+
+	rec = httptest.NewRecorder()
+	app.handleConfigzRequest(rec, httptest.NewRequest(http.MethodGet, "/debug/configz", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	body := rec.Body.String()
+	assert.Contains(t, body, "authorization: &#39;[REDACTED]&#39;")
+	assert.Contains(t, body, "timeout: 5s")
+	assert.NotContains(t, body, "secret")
+}
@@ -31,6 +31,9 @@ func TestDefaultComponents(t *testing.T) {
 		"pprof",
 		"zpages",
 		"fluentbit",
+		"remote_config",
+		"oauth2",
+		"file_storage",
 	}
 	expectedReceivers := []configmodels.Type{
 		"jaeger",
@@ -41,6 +44,10 @@ func TestDefaultComponents(t *testing.T) {
 		"hostmetrics",
 		"fluentforward",
 		"kafka",
+		"statsd",
+		"syslog",
+		"journald",
+		"filelog",
 	}
 	expectedProcessors := []configmodels.Type{
 		"attributes",
@@ -50,6 +57,16 @@ func TestDefaultComponents(t *testing.T) {
 		"probabilistic_sampler",
 		"span",
 		"filter",
+		"resourcedetection",
+		"tail_sampling",
+		"groupbytrace",
+		"metricstransform",
+		"temporality",
+		"spanmetrics",
+		"logstometrics",
+		"redaction",
+		"k8sattributes",
+		"routing",
 	}
 	expectedExporters := []configmodels.Type{
 		"opencensus",
@@ -62,6 +79,7 @@ func TestDefaultComponents(t *testing.T) {
 		"otlp",
 		"otlphttp",
 		"kafka",
+		"loadbalancing",
 	}
 
 	factories, err := Components()
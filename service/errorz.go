@@ -0,0 +1,52 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/collector/internal/recenterrors"
+	"go.opentelemetry.io/collector/service/internal/zpages"
+)
+
+const errorzPath = "errorz"
+
+// handleErrorzRequest writes the recent errors and dropped data of the components, the most
+// recent first.
+func (app *Application) handleErrorzRequest(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	zpages.WriteHTMLHeader(w, zpages.HeaderData{Title: "Recent Errors"})
+	zpages.WriteHTMLErrorsTable(w, getErrorsTableData(recenterrors.Events()))
+	zpages.WriteHTMLFooter(w)
+}
+
+func getErrorsTableData(events []recenterrors.Event) zpages.ErrorsTableData {
+	data := zpages.ErrorsTableData{Rows: make([]zpages.ErrorsTableRowData, 0, len(events))}
+	for _, e := range events {
+		data.Rows = append(data.Rows, zpages.ErrorsTableRowData{
+			Kind:      e.Kind,
+			Name:      e.Name,
+			Type:      e.Type,
+			Items:     e.Items,
+			Count:     e.Occurrences,
+			LastError: e.LastError,
+			FirstSeen: e.FirstTime.Format(time.RFC3339),
+			LastSeen:  e.LastTime.Format(time.RFC3339),
+		})
+	}
+	return data
+}
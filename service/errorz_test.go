@@ -0,0 +1,48 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.opentelemetry.io/collector/internal/recenterrors"
+)
+
+func TestHandleErrorzRequest(t *testing.T) {
+	recenterrors.Reset()
+	defer recenterrors.Reset()
+	app := &Application{}
+
+	rec := httptest.NewRecorder()
+	app.handleErrorzRequest(rec, httptest.NewRequest(http.MethodGet, "/debug/errorz", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "No error or dropped data recorded.")
+
+	recenterrors.Record("exporter", "otlp", recenterrors.TypeSendFailed, 10, errors.New("connection refused"))
+	recenterrors.Record("processor", "batch", recenterrors.TypeDropped, 5, nil)
+
+	rec = httptest.NewRecorder()
+	app.handleErrorzRequest(rec, httptest.NewRequest(http.MethodGet, "/debug/errorz", nil))
+	body := rec.Body.String()
+	assert.Contains(t, body, "<td>otlp</td>")
+	assert.Contains(t, body, "<td>connection refused</td>")
+	assert.Contains(t, body, "<td>batch</td>")
+	assert.Contains(t, body, `<td align="center">dropped</td>`)
+}
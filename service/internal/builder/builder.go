@@ -17,13 +17,35 @@ package builder
 import (
 	"flag"
 	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
 )
 
 const (
 	// flags
 	configCfg      = "config"
+	configWatchCfg = "config-watch"
 	memBallastFlag = "mem-ballast-size-mib"
 
+	shutdownReceiversTimeoutFlag  = "shutdown-receivers-timeout"
+	shutdownProcessorsTimeoutFlag = "shutdown-processors-timeout"
+	shutdownExportersTimeoutFlag  = "shutdown-exporters-timeout"
+
+	defaultShutdownReceiversTimeout  = 5 * time.Second
+	defaultShutdownProcessorsTimeout = 5 * time.Second
+	defaultShutdownExportersTimeout  = 15 * time.Second
+
+	restartMaxRetriesFlag      = "receiver-restart-max-retries"
+	restartInitialIntervalFlag = "receiver-restart-initial-interval"
+	restartMaxIntervalFlag     = "receiver-restart-max-interval"
+
+	defaultRestartInitialInterval = time.Second
+	defaultRestartMaxInterval     = 30 * time.Second
+
 	kindLogKey        = "component_kind"
 	kindLogsReceiver  = "receiver"
 	kindLogsProcessor = "processor"
@@ -34,24 +56,161 @@ const (
 )
 
 var (
-	configFile     *string
+	configFiles    *stringsFlag
+	configWatch    *bool
 	memBallastSize *uint
+
+	shutdownReceiversTimeout  *time.Duration
+	shutdownProcessorsTimeout *time.Duration
+	shutdownExportersTimeout  *time.Duration
+
+	restartMaxRetries      *int
+	restartInitialInterval *time.Duration
+	restartMaxInterval     *time.Duration
 )
 
 // Flags adds flags related to basic building of the collector application to the given flagset.
 func Flags(flags *flag.FlagSet) {
-	configFile = flags.String(configCfg, "", "Path to the config file")
+	configFiles = new(stringsFlag)
+	flags.Var(configFiles, configCfg, "Path to the config file or directory, can be repeated, the later files override the earlier ones")
+	configWatch = flags.Bool(configWatchCfg, false, "Watch the local config files and directories, and apply the configuration when they change")
 	memBallastSize = flags.Uint(memBallastFlag, 0,
 		fmt.Sprintf("Flag to specify size of memory (MiB) ballast to set. Ballast is not used when this is not specified. "+
 			"default settings: 0"))
+	shutdownReceiversTimeout = flags.Duration(shutdownReceiversTimeoutFlag, defaultShutdownReceiversTimeout,
+		"Maximum time to wait for the receivers to stop at shutdown")
+	shutdownProcessorsTimeout = flags.Duration(shutdownProcessorsTimeoutFlag, defaultShutdownProcessorsTimeout,
+		"Maximum time to wait for the processors to flush their data at shutdown")
+	shutdownExportersTimeout = flags.Duration(shutdownExportersTimeoutFlag, defaultShutdownExportersTimeout,
+		"Maximum time to wait for the exporters to drain their queues at shutdown")
+	restartMaxRetries = flags.Int(restartMaxRetriesFlag, 0,
+		"Maximum number of consecutive restarts of a receiver reporting a fatal error before the collector shuts down, 0 disables the restarts")
+	restartInitialInterval = flags.Duration(restartInitialIntervalFlag, defaultRestartInitialInterval,
+		"Time to wait before the first restart of a receiver reporting a fatal error")
+	restartMaxInterval = flags.Duration(restartMaxIntervalFlag, defaultRestartMaxInterval,
+		"Upper bound of the exponentially increasing time to wait before restarting a receiver")
 }
 
-// GetConfigFile gets the config file from the config file flag.
-func GetConfigFile() string {
-	return *configFile
+// ShutdownTimeouts are the maximum durations of the stages of the shutdown of the pipelines.
+type ShutdownTimeouts struct {
+	Receivers  time.Duration
+	Processors time.Duration
+	Exporters  time.Duration
+}
+
+// GetShutdownTimeouts returns the shutdown timeouts from the flags, or the default ones when
+// the flags were not added.
+func GetShutdownTimeouts() ShutdownTimeouts {
+	return ShutdownTimeouts{
+		Receivers:  durationOrDefault(shutdownReceiversTimeout, defaultShutdownReceiversTimeout),
+		Processors: durationOrDefault(shutdownProcessorsTimeout, defaultShutdownProcessorsTimeout),
+		Exporters:  durationOrDefault(shutdownExportersTimeout, defaultShutdownExportersTimeout),
+	}
+}
+
+// RestartPolicy is the policy restarting the receivers reporting a fatal error, instead of
+// shutting down the collector.
+type RestartPolicy struct {
+	// MaxRetries is the maximum number of consecutive restarts of a receiver, the fatal error
+	// is reported to the host once it is reached. 0 disables the restarts.
+	MaxRetries int
+	// InitialInterval is the time to wait before the first restart.
+	InitialInterval time.Duration
+	// MaxInterval is the upper bound of the exponentially increasing time to wait between the
+	// restarts. A receiver running for MaxInterval after being restarted is considered
+	// recovered, and its restarts are counted from zero again.
+	MaxInterval time.Duration
+}
+
+// GetRestartPolicy returns the receivers restart policy from the flags, the restarts are
+// disabled when the flags were not added.
+func GetRestartPolicy() RestartPolicy {
+	p := RestartPolicy{
+		InitialInterval: durationOrDefault(restartInitialInterval, defaultRestartInitialInterval),
+		MaxInterval:     durationOrDefault(restartMaxInterval, defaultRestartMaxInterval),
+	}
+	if restartMaxRetries != nil {
+		p.MaxRetries = *restartMaxRetries
+	}
+	return p
+}
+
+func durationOrDefault(d *time.Duration, def time.Duration) time.Duration {
+	if d == nil {
+		return def
+	}
+	return *d
+}
+
+// GetConfigFiles gets the config files and directories from the config file flags.
+func GetConfigFiles() []string {
+	return *configFiles
+}
+
+// GetConfigWatch returns whether the local config files and directories are watched for changes.
+func GetConfigWatch() bool {
+	return *configWatch
+}
+
+// stringsFlag is a flag that can be repeated, its values are the values of each flag.
+type stringsFlag []string
+
+func (s *stringsFlag) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringsFlag) Set(value string) error {
+	*s = append(*s, value)
+	return nil
 }
 
 // MemBallastSize returns the size of memory ballast to use in MBs
 func MemBallastSize() int {
 	return int(*memBallastSize)
 }
+
+// statusReporter is implemented by the hosts notifying the extensions of the status
+// of the components.
+type statusReporter interface {
+	ReportComponentStatus(event component.StatusEvent)
+}
+
+// reportStatus reports the status of the component to the host, if it supports it.
+func reportStatus(host component.Host, event component.StatusEvent) {
+	if r, ok := host.(statusReporter); ok {
+		r.ReportComponentStatus(event)
+	}
+}
+
+// reportStart reports the component as starting, and returns the function reporting
+// the result of its start.
+func reportStart(host component.Host, event component.StatusEvent) func(err error) {
+	event.Status = component.StatusStarting
+	reportStatus(host, event)
+	return func(err error) {
+		event.Status = component.StatusHealthy
+		if err != nil {
+			event.Status = component.StatusFailed
+			event.Err = err
+		}
+		reportStatus(host, event)
+	}
+}
+
+// addDrainStats adds the drain stats of the component to total if it implements
+// component.Drainer, logging them when items were dropped.
+func addDrainStats(total component.DrainStats, logger *zap.Logger, c component.Component) component.DrainStats {
+	d, ok := c.(component.Drainer)
+	if !ok {
+		return total
+	}
+	stats := d.DrainStats()
+	if stats.Dropped > 0 {
+		logger.Warn("Items dropped at shutdown",
+			zap.Int64("flushed", stats.Flushed),
+			zap.Int64("dropped", stats.Dropped))
+	}
+	total.Flushed += stats.Flushed
+	total.Dropped += stats.Dropped
+	return total
+}
@@ -88,10 +88,13 @@ type Exporters map[configmodels.Exporter]*builtExporter
 
 // StartAll starts all exporters.
 func (exps Exporters) StartAll(ctx context.Context, host component.Host) error {
-	for _, exp := range exps {
+	for cfg, exp := range exps {
 		exp.logger.Info("Exporter is starting...")
 
-		if err := exp.Start(ctx, host); err != nil {
+		started := reportStart(host, component.StatusEvent{Kind: component.KindExporter, Name: cfg.Name()})
+		err := exp.Start(ctx, host)
+		started(err)
+		if err != nil {
 			return err
 		}
 		exp.logger.Info("Exporter started.")
@@ -112,6 +115,18 @@ func (exps Exporters) ShutdownAll(ctx context.Context) error {
 	return consumererror.CombineErrors(errs)
 }
 
+// DrainStats returns the items drained at shutdown by the exporters implementing
+// component.Drainer, the items dropped by each exporter are logged.
+func (exps Exporters) DrainStats() component.DrainStats {
+	var total component.DrainStats
+	for _, exp := range exps {
+		for _, e := range exp.expByDataType {
+			total = addDrainStats(total, exp.logger, e)
+		}
+	}
+	return total
+}
+
 func (exps Exporters) ToMapByDataType() map[configmodels.DataType]map[configmodels.NamedEntity]component.Exporter {
 
 	exportersMap := make(map[configmodels.DataType]map[configmodels.NamedEntity]component.Exporter)
@@ -221,6 +221,33 @@ func TestBuildExporters_StopAll(t *testing.T) {
 	assert.True(t, logsExporter.ExporterShutdown)
 }
 
+type drainingExporter struct {
+	testcomponents.ExampleExporterConsumer
+	stats component.DrainStats
+}
+
+func (e *drainingExporter) DrainStats() component.DrainStats {
+	return e.stats
+}
+
+func TestBuildExporters_DrainStats(t *testing.T) {
+	exporters := make(Exporters)
+	exporters[&configmodels.ExporterSettings{NameVal: "exp1"}] = &builtExporter{
+		logger: zap.NewNop(),
+		expByDataType: map[configmodels.DataType]component.Exporter{
+			configmodels.TracesDataType:  &drainingExporter{stats: component.DrainStats{Flushed: 3, Dropped: 1}},
+			configmodels.MetricsDataType: &testcomponents.ExampleExporterConsumer{},
+		},
+	}
+	exporters[&configmodels.ExporterSettings{NameVal: "exp2"}] = &builtExporter{
+		logger: zap.NewNop(),
+		expByDataType: map[configmodels.DataType]component.Exporter{
+			configmodels.LogsDataType: &drainingExporter{stats: component.DrainStats{Flushed: 5}},
+		},
+	}
+	assert.Equal(t, component.DrainStats{Flushed: 8, Dropped: 1}, exporters.DrainStats())
+}
+
 func TestBuildExporters_NotSupportedDataType(t *testing.T) {
 	factories := createTestFactories()
 
@@ -49,10 +49,13 @@ type Extensions map[configmodels.Extension]*builtExtension
 
 // StartAll starts all exporters.
 func (exts Extensions) StartAll(ctx context.Context, host component.Host) error {
-	for _, ext := range exts {
+	for cfg, ext := range exts {
 		ext.logger.Info("Extension is starting...")
 
-		if err := ext.Start(ctx, host); err != nil {
+		started := reportStart(host, component.StatusEvent{Kind: component.KindExtension, Name: cfg.Name()})
+		err := ext.Start(ctx, host)
+		started(err)
+		if err != nil {
 			return err
 		}
 
@@ -102,6 +105,15 @@ func (exts Extensions) NotifyPipelineNotReady() error {
 	return consumererror.CombineErrors(errs)
 }
 
+// NotifyComponentStatus notifies the extensions of the change of status of a component.
+func (exts Extensions) NotifyComponentStatus(event component.StatusEvent) {
+	for _, ext := range exts {
+		if sw, ok := ext.extension.(component.StatusWatcher); ok {
+			sw.ComponentStatusChanged(event)
+		}
+	}
+}
+
 func (exts Extensions) ToMap() map[configmodels.NamedEntity]component.Extension {
 	result := make(map[configmodels.NamedEntity]component.Extension, len(exts))
 	for k, v := range exts {
@@ -40,6 +40,9 @@ type builtPipeline struct {
 	// can mutate the TraceData or MetricsData input argument.
 	MutatesConsumedData bool
 
+	// backpressure is set to true if the pipeline propagates backpressure.
+	backpressure bool
+
 	processors []component.Processor
 }
 
@@ -47,14 +50,21 @@ type builtPipeline struct {
 type BuiltPipelines map[*configmodels.Pipeline]*builtPipeline
 
 func (bps BuiltPipelines) StartProcessors(ctx context.Context, host component.Host) error {
-	for _, bp := range bps {
+	for cfg, bp := range bps {
 		bp.logger.Info("Pipeline is starting...")
 		// Start in reverse order, starting from the back of processors pipeline.
 		// This is important so that processors that are earlier in the pipeline and
 		// reference processors that are later in the pipeline do not start sending
 		// data to later pipelines which are not yet started.
 		for i := len(bp.processors) - 1; i >= 0; i-- {
-			if err := bp.processors[i].Start(ctx, host); err != nil {
+			started := reportStart(host, component.StatusEvent{
+				Kind:     component.KindProcessor,
+				Name:     cfg.Processors[i],
+				Pipeline: cfg.Name,
+			})
+			err := bp.processors[i].Start(ctx, host)
+			started(err)
+			if err != nil {
 				return err
 			}
 		}
@@ -78,6 +88,18 @@ func (bps BuiltPipelines) ShutdownProcessors(ctx context.Context) error {
 	return consumererror.CombineErrors(errs)
 }
 
+// ProcessorsDrainStats returns the items drained at shutdown by the processors implementing
+// component.Drainer, the items dropped by each processor are logged.
+func (bps BuiltPipelines) ProcessorsDrainStats() component.DrainStats {
+	var total component.DrainStats
+	for _, bp := range bps {
+		for _, p := range bp.processors {
+			total = addDrainStats(total, bp.logger, p)
+		}
+	}
+	return total
+}
+
 // pipelinesBuilder builds Pipelines from config.
 type pipelinesBuilder struct {
 	logger    *zap.Logger
@@ -153,6 +175,7 @@ func (pb *pipelinesBuilder) buildPipeline(ctx context.Context, pipelineCfg *conf
 		creationParams := component.ProcessorCreateParams{
 			Logger:               componentLogger,
 			ApplicationStartInfo: pb.appInfo,
+			Backpressure:         pipelineCfg.Backpressure,
 		}
 
 		switch pipelineCfg.InputType {
@@ -208,6 +231,7 @@ func (pb *pipelinesBuilder) buildPipeline(ctx context.Context, pipelineCfg *conf
 		mc,
 		lc,
 		mutatesConsumedData,
+		pipelineCfg.Backpressure,
 		processors,
 	}
 
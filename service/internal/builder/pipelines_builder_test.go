@@ -58,6 +58,24 @@ func TestBuildPipelines(t *testing.T) {
 	}
 }
 
+func TestBuildPipelines_StartReportsStatus(t *testing.T) {
+	factories, err := testcomponents.ExampleComponents()
+	require.NoError(t, err)
+	cfg := createExampleConfig(string(configmodels.TracesDataType))
+	exporters, err := BuildExporters(zap.NewNop(), component.DefaultApplicationStartInfo(), cfg, factories.Exporters)
+	require.NoError(t, err)
+	pipelines, err := BuildPipelines(zap.NewNop(), component.DefaultApplicationStartInfo(), cfg, exporters, factories.Processors)
+	require.NoError(t, err)
+
+	host := &statusHost{Host: componenttest.NewNopHost()}
+	require.NoError(t, pipelines.StartProcessors(context.Background(), host))
+	assert.Equal(t, []component.StatusEvent{
+		{Kind: component.KindProcessor, Name: "exampleprocessor", Pipeline: "traces", Status: component.StatusStarting},
+		{Kind: component.KindProcessor, Name: "exampleprocessor", Pipeline: "traces", Status: component.StatusHealthy},
+	}, host.events)
+	assert.NoError(t, pipelines.ShutdownProcessors(context.Background()))
+}
+
 func createExampleConfig(dataType string) *configmodels.Config {
 	exampleReceiverFactory := &testcomponents.ExampleReceiverFactory{}
 	exampleProcessorFactory := &testcomponents.ExampleProcessorFactory{}
@@ -0,0 +1,169 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package builder
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+)
+
+// errNotRestartable is returned when the factory of a receiver returns the failed instance
+// instead of a new one, e.g. when it shares an instance between the data types.
+var errNotRestartable = errors.New("the receiver factory returned the failed instance, it cannot be restarted")
+
+// supervisedHost is the host given to the receivers started with a restart policy, the fatal
+// errors they report restart them instead of being reported to the host.
+type supervisedHost struct {
+	component.Host
+	rcv      *builtReceiver
+	instance component.Receiver
+}
+
+// ReportFatalError restarts the receiver. The restart runs asynchronously, the receiver being
+// shut down by it.
+func (h *supervisedHost) ReportFatalError(err error) {
+	go h.rcv.handleFatalError(h.Host, h.instance, err)
+}
+
+// startSupervised starts the receiver with a host restarting it when it reports a fatal error.
+// It must be called with mu held.
+func (rcv *builtReceiver) startSupervised(ctx context.Context, host component.Host) error {
+	rcv.startedAt = time.Now()
+	return rcv.receiver.Start(ctx, &supervisedHost{Host: host, rcv: rcv, instance: rcv.receiver})
+}
+
+// handleFatalError restarts the receiver after the fatal error reported by the instance, unless
+// the receiver was restarted or shut down since then. The error is reported to the host when the
+// receiver was restarted MaxRetries times in a row.
+func (rcv *builtReceiver) handleFatalError(host component.Host, instance component.Receiver, err error) {
+	rcv.mu.Lock()
+	defer rcv.mu.Unlock()
+	if rcv.stopped || instance != rcv.receiver {
+		return
+	}
+	reportStatus(host, component.StatusEvent{
+		Kind:   component.KindReceiver,
+		Name:   rcv.name,
+		Status: component.StatusFailed,
+		Err:    err,
+	})
+	if time.Since(rcv.startedAt) >= rcv.restartPolicy.MaxInterval {
+		// The receiver recovered from the previous failures.
+		rcv.restarts = 0
+	}
+	if !rcv.nextRestart(host, err) {
+		return
+	}
+	rcv.restartWG.Add(1)
+	go rcv.restartLoop(host, rcv.stopCh, err)
+}
+
+// nextRestart counts a restart, it reports err to the host and returns false when the restarts
+// are exhausted. It must be called with mu held.
+func (rcv *builtReceiver) nextRestart(host component.Host, err error) bool {
+	if rcv.restarts >= rcv.restartPolicy.MaxRetries {
+		rcv.logger.Error("Receiver failed too many times, reporting the error",
+			zap.Int("restarts", rcv.restarts), zap.Error(err))
+		// The host may not read the error until it shuts down the receivers.
+		go host.ReportFatalError(err)
+		return false
+	}
+	rcv.restarts++
+	return true
+}
+
+// restartLoop restarts the receiver with an exponential backoff until it starts, the restarts
+// are exhausted or the receiver is shut down.
+func (rcv *builtReceiver) restartLoop(host component.Host, stopCh <-chan struct{}, err error) {
+	defer rcv.restartWG.Done()
+	for {
+		rcv.mu.Lock()
+		delay, restarts := rcv.restartDelay(), rcv.restarts
+		rcv.mu.Unlock()
+		rcv.logger.Warn("Receiver failed, restarting it",
+			zap.Int("restarts", restarts), zap.Duration("delay", delay), zap.Error(err))
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-stopCh:
+			timer.Stop()
+			return
+		}
+
+		rcv.mu.Lock()
+		if rcv.stopped {
+			rcv.mu.Unlock()
+			return
+		}
+		rerr := rcv.restart(host)
+		if rerr == nil {
+			rcv.mu.Unlock()
+			rcv.logger.Info("Receiver restarted.")
+			return
+		}
+		if rerr == errNotRestartable {
+			rcv.mu.Unlock()
+			rcv.logger.Error("Receiver cannot be restarted, reporting the error", zap.Error(err))
+			go host.ReportFatalError(err)
+			return
+		}
+		err = rerr
+		more := rcv.nextRestart(host, err)
+		rcv.mu.Unlock()
+		if !more {
+			return
+		}
+	}
+}
+
+// restart replaces the receiver by a new instance, and starts it. It must be called with mu held.
+func (rcv *builtReceiver) restart(host component.Host) error {
+	ctx := context.Background()
+	if err := rcv.receiver.Shutdown(ctx); err != nil {
+		rcv.logger.Warn("Failed to shutdown the failed receiver", zap.Error(err))
+	}
+	created, err := rcv.create(ctx)
+	if err != nil {
+		return err
+	}
+	if created == rcv.receiver {
+		return errNotRestartable
+	}
+	rcv.receiver = created
+
+	started := reportStart(host, component.StatusEvent{Kind: component.KindReceiver, Name: rcv.name})
+	err = rcv.startSupervised(ctx, host)
+	started(err)
+	return err
+}
+
+// restartDelay returns the time to wait before the restart, the InitialInterval doubled for
+// each previous restart, up to MaxInterval. It must be called with mu held.
+func (rcv *builtReceiver) restartDelay() time.Duration {
+	delay := rcv.restartPolicy.InitialInterval
+	for i := 1; i < rcv.restarts && delay < rcv.restartPolicy.MaxInterval; i++ {
+		delay *= 2
+	}
+	if delay > rcv.restartPolicy.MaxInterval {
+		delay = rcv.restartPolicy.MaxInterval
+	}
+	return delay
+}
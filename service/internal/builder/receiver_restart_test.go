@@ -0,0 +1,188 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package builder
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+)
+
+// crashingReceiver reports a fatal error when started if fail is set.
+type crashingReceiver struct {
+	fail bool
+
+	mu       sync.Mutex
+	started  bool
+	shutdown bool
+}
+
+func (r *crashingReceiver) Start(_ context.Context, host component.Host) error {
+	r.mu.Lock()
+	r.started = true
+	r.mu.Unlock()
+	if r.fail {
+		host.ReportFatalError(errors.New("scrape manager crashed"))
+	}
+	return nil
+}
+
+func (r *crashingReceiver) Shutdown(context.Context) error {
+	r.mu.Lock()
+	r.shutdown = true
+	r.mu.Unlock()
+	return nil
+}
+
+func (r *crashingReceiver) isStarted() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.started && !r.shutdown
+}
+
+// newRestartedReceiver returns a receiver built with the policy, of which the created
+// instances fail while fail returns true.
+func newRestartedReceiver(policy RestartPolicy, fail func(created int) bool) (*builtReceiver, *[]*crashingReceiver, *sync.Mutex) {
+	var mu sync.Mutex
+	created := []*crashingReceiver{{fail: fail(0)}}
+	rcv := &builtReceiver{
+		logger:        zap.NewNop(),
+		name:          "prometheus",
+		receiver:      created[0],
+		restartPolicy: policy,
+		create: func(context.Context) (component.Receiver, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			r := &crashingReceiver{fail: fail(len(created))}
+			created = append(created, r)
+			return r, nil
+		},
+	}
+	return rcv, &created, &mu
+}
+
+func TestBuiltReceiver_Restart(t *testing.T) {
+	policy := RestartPolicy{MaxRetries: 3, InitialInterval: 10 * time.Millisecond, MaxInterval: time.Second}
+	rcv, created, mu := newRestartedReceiver(policy, func(created int) bool { return created < 2 })
+
+	host := componenttest.NewErrorWaitingHost()
+	require.NoError(t, rcv.Start(context.Background(), host))
+
+	// The first two instances fail, the third one keeps running.
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(*created) == 3 && (*created)[2].isStarted()
+	}, 5*time.Second, 10*time.Millisecond)
+	mu.Lock()
+	assert.False(t, (*created)[0].isStarted())
+	assert.False(t, (*created)[1].isStarted())
+	mu.Unlock()
+
+	received, _ := host.WaitForFatalError(100 * time.Millisecond)
+	assert.False(t, received)
+
+	require.NoError(t, rcv.Shutdown(context.Background()))
+	mu.Lock()
+	assert.False(t, (*created)[2].isStarted())
+	mu.Unlock()
+}
+
+func TestBuiltReceiver_RestartExhausted(t *testing.T) {
+	policy := RestartPolicy{MaxRetries: 2, InitialInterval: 10 * time.Millisecond, MaxInterval: time.Second}
+	rcv, created, mu := newRestartedReceiver(policy, func(int) bool { return true })
+
+	host := componenttest.NewErrorWaitingHost()
+	require.NoError(t, rcv.Start(context.Background(), host))
+
+	received, err := host.WaitForFatalError(5 * time.Second)
+	require.True(t, received)
+	assert.EqualError(t, err, "scrape manager crashed")
+	mu.Lock()
+	assert.Len(t, *created, 3)
+	mu.Unlock()
+
+	require.NoError(t, rcv.Shutdown(context.Background()))
+}
+
+func TestBuiltReceiver_RestartNotRestartable(t *testing.T) {
+	policy := RestartPolicy{MaxRetries: 2, InitialInterval: 10 * time.Millisecond, MaxInterval: time.Second}
+	failed := &crashingReceiver{fail: true}
+	rcv := &builtReceiver{
+		logger:        zap.NewNop(),
+		receiver:      failed,
+		restartPolicy: policy,
+		create: func(context.Context) (component.Receiver, error) {
+			return failed, nil
+		},
+	}
+
+	host := componenttest.NewErrorWaitingHost()
+	require.NoError(t, rcv.Start(context.Background(), host))
+
+	received, err := host.WaitForFatalError(5 * time.Second)
+	require.True(t, received)
+	assert.EqualError(t, err, "scrape manager crashed")
+	require.NoError(t, rcv.Shutdown(context.Background()))
+}
+
+func TestBuiltReceiver_ShutdownStopsRestart(t *testing.T) {
+	policy := RestartPolicy{MaxRetries: 2, InitialInterval: time.Hour, MaxInterval: time.Hour}
+	rcv, created, mu := newRestartedReceiver(policy, func(int) bool { return true })
+
+	host := componenttest.NewErrorWaitingHost()
+	require.NoError(t, rcv.Start(context.Background(), host))
+
+	// The restart waits for an hour, the shutdown does not wait for it.
+	time.Sleep(50 * time.Millisecond)
+	require.NoError(t, rcv.Shutdown(context.Background()))
+	mu.Lock()
+	assert.Len(t, *created, 1)
+	mu.Unlock()
+
+	received, _ := host.WaitForFatalError(100 * time.Millisecond)
+	assert.False(t, received)
+}
+
+func TestBuiltReceiver_RestartDisabled(t *testing.T) {
+	rcv, _, _ := newRestartedReceiver(RestartPolicy{}, func(int) bool { return true })
+
+	host := componenttest.NewErrorWaitingHost()
+	require.NoError(t, rcv.Start(context.Background(), host))
+
+	received, err := host.WaitForFatalError(time.Second)
+	require.True(t, received)
+	assert.EqualError(t, err, "scrape manager crashed")
+}
+
+func TestRestartPolicy_Delay(t *testing.T) {
+	rcv := &builtReceiver{
+		restartPolicy: RestartPolicy{MaxRetries: 10, InitialInterval: time.Second, MaxInterval: 5 * time.Second},
+	}
+	var delays []time.Duration
+	for rcv.restarts = 1; rcv.restarts <= 5; rcv.restarts++ {
+		delays = append(delays, rcv.restartDelay())
+	}
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}, delays)
+}
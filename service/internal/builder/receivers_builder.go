@@ -18,6 +18,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
+	"time"
 
 	"go.uber.org/zap"
 
@@ -35,16 +37,47 @@ var errUnusedReceiver = errors.New("receiver defined but not used by any pipelin
 // a trace and/or a metrics component.
 type builtReceiver struct {
 	logger   *zap.Logger
+	name     string
 	receiver component.Receiver
+
+	// create creates a new instance of the receiver, replacing the failed one when it is
+	// restarted according to restartPolicy.
+	create        func(ctx context.Context) (component.Receiver, error)
+	restartPolicy RestartPolicy
+
+	mu        sync.Mutex
+	stopped   bool
+	stopCh    chan struct{}
+	startedAt time.Time
+	restarts  int
+	restartWG sync.WaitGroup
 }
 
 // Start the receiver.
 func (rcv *builtReceiver) Start(ctx context.Context, host component.Host) error {
-	return rcv.receiver.Start(ctx, host)
+	if rcv.restartPolicy.MaxRetries <= 0 || rcv.create == nil {
+		return rcv.receiver.Start(ctx, host)
+	}
+	rcv.mu.Lock()
+	defer rcv.mu.Unlock()
+	rcv.stopCh = make(chan struct{})
+	return rcv.startSupervised(ctx, host)
 }
 
 // Stop the receiver.
 func (rcv *builtReceiver) Shutdown(ctx context.Context) error {
+	rcv.mu.Lock()
+	rcv.stopped = true
+	if rcv.stopCh != nil {
+		close(rcv.stopCh)
+		rcv.stopCh = nil
+	}
+	rcv.mu.Unlock()
+	// Wait for the restart in progress, if any.
+	rcv.restartWG.Wait()
+
+	rcv.mu.Lock()
+	defer rcv.mu.Unlock()
 	return rcv.receiver.Shutdown(ctx)
 }
 
@@ -66,10 +99,13 @@ func (rcvs Receivers) ShutdownAll(ctx context.Context) error {
 
 // StartAll starts all receivers.
 func (rcvs Receivers) StartAll(ctx context.Context, host component.Host) error {
-	for _, rcv := range rcvs {
+	for cfg, rcv := range rcvs {
 		rcv.logger.Info("Receiver is starting...")
 
-		if err := rcv.Start(ctx, host); err != nil {
+		started := reportStart(host, component.StatusEvent{Kind: component.KindReceiver, Name: cfg.Name()})
+		err := rcv.Start(ctx, host)
+		started(err)
+		if err != nil {
 			return err
 		}
 		rcv.logger.Info("Receiver started.")
@@ -95,6 +131,7 @@ func BuildReceivers(
 	factories map[configmodels.Type]component.ReceiverFactory,
 ) (Receivers, error) {
 	rb := &receiversBuilder{logger.With(zap.String(kindLogKey, kindLogsReceiver)), appInfo, config, builtPipelines, factories}
+	restartPolicy := GetRestartPolicy()
 
 	receivers := make(Receivers)
 	for _, cfg := range rb.config.Receivers {
@@ -107,6 +144,7 @@ func BuildReceivers(
 			}
 			return nil, err
 		}
+		rcv.restartPolicy = restartPolicy
 		receivers[cfg] = rcv
 	}
 
@@ -176,6 +214,7 @@ func (rb *receiversBuilder) attachReceiverToPipelines(
 	creationParams := component.ReceiverCreateParams{
 		Logger:               logger,
 		ApplicationStartInfo: appInfo,
+		Backpressure:         propagatesBackpressure(builtPipelines),
 	}
 
 	switch dataType {
@@ -247,6 +286,14 @@ func (rb *receiversBuilder) buildReceiver(ctx context.Context, logger *zap.Logge
 	}
 	rcv := &builtReceiver{
 		logger: logger,
+		name:   config.Name(),
+		create: func(ctx context.Context) (component.Receiver, error) {
+			created, err := rb.buildReceiver(ctx, logger, appInfo, config)
+			if err != nil {
+				return nil, err
+			}
+			return created.receiver, nil
+		},
 	}
 
 	// Now we have list of pipelines broken down by data type. Iterate for each data type.
@@ -271,6 +318,16 @@ func (rb *receiversBuilder) buildReceiver(ctx context.Context, logger *zap.Logge
 	return rcv, nil
 }
 
+// propagatesBackpressure returns true when all the given pipelines propagate backpressure.
+func propagatesBackpressure(pipelines []*builtPipeline) bool {
+	for _, pipeline := range pipelines {
+		if !pipeline.backpressure {
+			return false
+		}
+	}
+	return true
+}
+
 func buildFanoutTraceConsumer(pipelines []*builtPipeline) consumer.TracesConsumer {
 	// Optimize for the case when there is only one processor, no need to create junction point.
 	if len(pipelines) == 1 {
@@ -16,6 +16,7 @@ package builder
 
 import (
 	"context"
+	"errors"
 	"path"
 	"testing"
 
@@ -278,6 +279,54 @@ func TestBuildReceivers_StartAll(t *testing.T) {
 	assert.True(t, receiver.Started)
 }
 
+// statusHost is a host recording the status of the components.
+type statusHost struct {
+	component.Host
+	events []component.StatusEvent
+}
+
+func (h *statusHost) ReportComponentStatus(event component.StatusEvent) {
+	h.events = append(h.events, event)
+}
+
+type failingReceiver struct {
+	err error
+}
+
+func (r *failingReceiver) Start(context.Context, component.Host) error {
+	return r.err
+}
+
+func (r *failingReceiver) Shutdown(context.Context) error {
+	return nil
+}
+
+func TestBuildReceivers_StartAllReportsStatus(t *testing.T) {
+	rcvCfg := &configmodels.ReceiverSettings{NameVal: "example"}
+	receivers := Receivers{rcvCfg: {
+		logger:   zap.NewNop(),
+		receiver: &testcomponents.ExampleReceiverProducer{},
+	}}
+	host := &statusHost{Host: componenttest.NewNopHost()}
+	require.NoError(t, receivers.StartAll(context.Background(), host))
+	assert.Equal(t, []component.StatusEvent{
+		{Kind: component.KindReceiver, Name: "example", Status: component.StatusStarting},
+		{Kind: component.KindReceiver, Name: "example", Status: component.StatusHealthy},
+	}, host.events)
+
+	startErr := errors.New("start failed")
+	receivers = Receivers{rcvCfg: {
+		logger:   zap.NewNop(),
+		receiver: &failingReceiver{err: startErr},
+	}}
+	host = &statusHost{Host: componenttest.NewNopHost()}
+	assert.Equal(t, startErr, receivers.StartAll(context.Background(), host))
+	assert.Equal(t, []component.StatusEvent{
+		{Kind: component.KindReceiver, Name: "example", Status: component.StatusStarting},
+		{Kind: component.KindReceiver, Name: "example", Status: component.StatusFailed, Err: startErr},
+	}, host.events)
+}
+
 func TestBuildReceivers_StopAll(t *testing.T) {
 	receivers := make(Receivers)
 	rcvCfg := &configmodels.ReceiverSettings{}
@@ -316,6 +365,12 @@ func TestBuildReceivers_Unused(t *testing.T) {
 	assert.NoError(t, receivers.ShutdownAll(context.Background()))
 }
 
+func TestPropagatesBackpressure(t *testing.T) {
+	assert.True(t, propagatesBackpressure([]*builtPipeline{{backpressure: true}, {backpressure: true}}))
+	assert.False(t, propagatesBackpressure([]*builtPipeline{{backpressure: true}, {backpressure: false}}))
+	assert.False(t, propagatesBackpressure([]*builtPipeline{{}}))
+}
+
 func TestBuildReceivers_NotSupportedDataType(t *testing.T) {
 	factories := createTestFactories()
 
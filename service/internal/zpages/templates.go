@@ -29,11 +29,15 @@ var (
 		"even":     even,
 		"getKey":   getKey,
 		"getValue": getValue,
+		"add":      add,
+		"midpoint": midpoint,
 	}
 	componentHeaderTemplate = parseTemplate("component_header")
+	errorsTableTemplate     = parseTemplate("errors_table")
 	extensionsTableTemplate = parseTemplate("extensions_table")
 	headerTemplate          = parseTemplate("header")
 	footerTemplate          = parseTemplate("footer")
+	pipelineGraphTemplate   = parseTemplate("pipeline_graph")
 	pipelinesTableTemplate  = parseTemplate("pipelines_table")
 	propertiesTableTemplate = parseTemplate("properties_table")
 )
@@ -54,6 +58,8 @@ func parseTemplate(name string) *template.Template {
 // HeaderData contains data for the header template.
 type HeaderData struct {
 	Title string
+	// RefreshSeconds makes the browser reload the page periodically when not zero.
+	RefreshSeconds int
 }
 
 // WriteHTMLFooter writes the header.
@@ -107,6 +113,75 @@ func WriteHTMLPipelinesSummaryTable(w io.Writer, spd SummaryPipelinesTableData)
 	}
 }
 
+// ErrorsTableData contains data for the recent errors table template.
+type ErrorsTableData struct {
+	Rows []ErrorsTableRowData
+}
+
+// ErrorsTableRowData contains data for one row in the recent errors table template.
+type ErrorsTableRowData struct {
+	Kind string
+	Name string
+	// Type is the kind of event, e.g. a refused request or dropped data.
+	Type      string
+	Items     int64
+	Count     int64
+	LastError string
+	FirstSeen string
+	LastSeen  string
+}
+
+// WriteHTMLErrorsTable writes the table of the recent errors and dropped data of the components.
+func WriteHTMLErrorsTable(w io.Writer, etd ErrorsTableData) {
+	if err := errorsTableTemplate.Execute(w, etd); err != nil {
+		log.Printf("zpages: executing template: %v", err)
+	}
+}
+
+// PipelineGraphData contains data for the pipeline graph template.
+type PipelineGraphData struct {
+	Name      string
+	InputType string
+	Width     int
+	Height    int
+	Nodes     []PipelineGraphNodeData
+	Edges     []PipelineGraphEdgeData
+}
+
+// PipelineGraphNodeData contains data for one component in the pipeline graph template.
+type PipelineGraphNodeData struct {
+	Kind   string
+	Name   string
+	Link   string
+	X      int
+	Y      int
+	Width  int
+	Height int
+	// Counters is the summary of the items handled by the component.
+	Counters string
+	// Errors is the number of items refused, dropped or failed to be sent by the component.
+	Errors int64
+}
+
+// PipelineGraphEdgeData contains data for one edge between two components in the pipeline graph template.
+type PipelineGraphEdgeData struct {
+	X1 int
+	Y1 int
+	X2 int
+	Y2 int
+	// Throughput is the rate of the items sent along the edge.
+	Throughput string
+	// Errors is the number of items refused by the destination of the edge.
+	Errors int64
+}
+
+// WriteHTMLPipelineGraph writes the graph of the components of a pipeline.
+func WriteHTMLPipelineGraph(w io.Writer, pgd PipelineGraphData) {
+	if err := pipelineGraphTemplate.Execute(w, pgd); err != nil {
+		log.Printf("zpages: executing template: %v", err)
+	}
+}
+
 // ComponentHeaderData contains data for component header template.
 type ComponentHeaderData struct {
 	Name              string
@@ -152,3 +227,11 @@ func getKey(row [2]string) string {
 func getValue(row [2]string) string {
 	return row[1]
 }
+
+func add(x, y int) int {
+	return x + y
+}
+
+func midpoint(x, y int) int {
+	return (x + y) / 2
+}
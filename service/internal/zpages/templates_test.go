@@ -82,6 +82,64 @@ func TestNoCrash(t *testing.T) {
 	assert.NotPanics(t, func() {
 		WriteHTMLPropertiesTable(buf, PropertiesTableData{Name: "Bar", Properties: [][2]string{{"key", "value"}}})
 	})
+	assert.NotPanics(t, func() {
+		WriteHTMLPipelineGraph(buf, PipelineGraphData{
+			Name:      "traces",
+			InputType: "traces",
+			Nodes:     []PipelineGraphNodeData{{Kind: "receiver", Name: "oc", Link: "pagez"}},
+			Edges:     []PipelineGraphEdgeData{{Throughput: "1/s"}},
+		})
+	})
 	assert.NotPanics(t, func() { WriteHTMLFooter(buf) })
 	assert.NotPanics(t, func() { WriteHTMLFooter(buf) })
 }
+
+func TestWriteHTMLPipelineGraph(t *testing.T) {
+	buf := new(bytes.Buffer)
+	WriteHTMLPipelineGraph(buf, PipelineGraphData{
+		Name:      "traces",
+		InputType: "traces",
+		Width:     400,
+		Height:    50,
+		Nodes: []PipelineGraphNodeData{
+			{Kind: "receiver", Name: "otlp", Link: "pipelinez?zcomponentname=otlp", X: 0, Y: 0, Width: 150, Height: 40, Counters: "10 accepted"},
+			{Kind: "exporter", Name: "otlp", Link: "pipelinez", X: 250, Y: 0, Width: 150, Height: 40, Counters: "8 sent, 2 failed", Errors: 2},
+		},
+		Edges: []PipelineGraphEdgeData{{X1: 150, Y1: 20, X2: 250, Y2: 20, Throughput: "2.5/s", Errors: 3}},
+	})
+	out := buf.String()
+	assert.Contains(t, out, `<svg width="400" height="50"`)
+	assert.Contains(t, out, `<line x1="150" y1="20" x2="250" y2="20" stroke="#c62828"`)
+	assert.Contains(t, out, `<text x="200" y="14" text-anchor="middle">2.5/s</text>`)
+	assert.Contains(t, out, `3 refused`)
+	assert.Contains(t, out, `<text x="75" y="16" text-anchor="middle" font-weight="bold">receiver: otlp</text>`)
+	assert.Contains(t, out, `8 sent, 2 failed`)
+}
+
+func TestWriteHTMLHeaderRefresh(t *testing.T) {
+	buf := new(bytes.Buffer)
+	WriteHTMLHeader(buf, HeaderData{Title: "Foo"})
+	assert.NotContains(t, buf.String(), "refresh")
+
+	buf.Reset()
+	WriteHTMLHeader(buf, HeaderData{Title: "Foo", RefreshSeconds: 5})
+	assert.Contains(t, buf.String(), `<meta http-equiv="refresh" content="5">`)
+}
+
+func TestWriteHTMLErrorsTable(t *testing.T) {
+	buf := new(bytes.Buffer)
+	WriteHTMLErrorsTable(buf, ErrorsTableData{})
+	assert.Contains(t, buf.String(), "No error or dropped data recorded.")
+
+	buf.Reset()
+	WriteHTMLErrorsTable(buf, ErrorsTableData{
+		Rows: []ErrorsTableRowData{
+			{Kind: "exporter", Name: "otlp", Type: "send_failed", Items: 10, Count: 2, LastError: "connection refused"},
+		},
+	})
+	out := buf.String()
+	assert.NotContains(t, out, "No error or dropped data recorded.")
+	assert.Contains(t, out, "<td>otlp</td>")
+	assert.Contains(t, out, `<td align="center">send_failed</td>`)
+	assert.Contains(t, out, "<td>connection refused</td>")
+}
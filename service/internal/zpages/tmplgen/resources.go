@@ -231,7 +231,23 @@ var _escData = map[string]*_escFile{
 		compressed: `
 H4sIAAAAAAAC/1SMsQqDMBRFd7/iIq7q5lBiltKt9B8CPklQX6R1e9x/L6ZQ2vXcc65ZE3AZ0V3ztmcV
 PW467TnpQVZmzZp0Kfs96VJQizTjw1uyAgAXB+8C4lPmsT4fydqbdY+wCen64F0fB19iWV/yF/54X0en
-U3kHAAD//zT+SdCcAAAA
+U3kPADT+SdCcAAAA
+`,
+	},
+
+	"/templates/errors_table.html": {
+		name:    "errors_table.html",
+		local:   "../templates/errors_table.html",
+		size:    1620,
+		modtime: 0,
+		compressed: `
+H4sIAAAAAAAC/7SVsU7DMBCG9zzFKSAm2sBaHC8IJETVAXgBJ75GFqlt2S6lMn535KQJKRUDkCyRrdx9
+v3L5f533Yg3zJ7WzISTEsaJGsG5fY54WynA0M6tZKWS1gKuUJgAAxJn20F44lKq2msn8GlgtKpnXuHaU
+FPRRSE6ygpLM8aMOeiELq2/a58fwclL6I3zFNjg6vETp0ET8y15PiX9wuLET8m/VVroJ+ffCWPeMKCfU
+WLKJJDoLRYE7Y5Q5ViBZZ3DvDZMVwrlROyE5vl82R1jkfWQ6He9nINaAbyi/ygfvD8Hps8XK18qoreQL
+OEPElA5IWFs8baXeo+QwC+FoDt5HuXnMWgjNR/xuOh0g5ulPgMNQ0/bHpR0vBmhMXpOYMYFNRMYE9pkY
+E7pk/2AOIY3RQ/hWM7A6RgMlJGt2AE16GxJNVwowtoMywI3SGjlw5hgYLOOS4HOSaZocGMnnAKRszItU
+BgAA
 `,
 	},
 
@@ -244,7 +260,7 @@ U3kHAAD//zT+SdCcAAAA
 H4sIAAAAAAAC/2SQwU7DMBBE7/2KlemRNJwjxxwQHDnwB248DRbOOnK2tGD531HTQIvqk1fzZjU7Wuw2
 gCb5CmjVNiaHVE2j7Tz3DT0osyIiynltqWlp8xSHMTJYntmN0bOUsgDJcg9ap3jw7HC8n7+z5y0epgU7
 oxX5HeETfMGv9NPTkv4i2e6jT3HPrqE7AEui8yaECbdWkzPYUXWlaHFkg++5VR1YkJTRlt4Tdq06HVfK
-4zeOAp58ZLYD2pw3L/sQXu2AUpT5N+raGl2Lu0TRtaTfqsCulJWu52bNTwAAAP//sz5qjmEBAAA=
+4zeOAp58ZLYD2pw3L/sQXu2AUpT5N+raGl2Lu0TRtaTfqsCulJWu52bNzwCzPmqOYQEAAA==
 `,
 	},
 
@@ -254,21 +270,40 @@ oxX5HeETfMGv9NPTkv4i2e6jT3HPrqE7AEui8yaECbdWkzPYUXWlaHFkg++5VR1YkJTRlt4Tdq06HVfK
 		size:    15,
 		modtime: 0,
 		compressed: `
-H4sIAAAAAAAC/7LRT8pPqbTjstHPKMnNsQMEAAD//wEFevAPAAAA
+H4sIAAAAAAAC/7LRT8pPqbTjstHPKMnNsQMMAAEFevAPAAAA
 `,
 	},
 
 	"/templates/header.html": {
 		name:    "header.html",
 		local:   "../templates/header.html",
-		size:    467,
+		size:    572,
+		modtime: 0,
+		compressed: `
+H4sIAAAAAAAC/5TSvU4jMRAH8D5P4XN7Wvsu15yQdymAggKBIA2lscdrE38s9iTRarXvjqwlKCgNVLY8
+8//JX+LX9f3V5vnhhlgMvluJOhAvY99SiLQTFqTuVoQQIgKgJMrKXABbukPT/KdLaZoa4gxhj2AyFPsE
+KkVd5vkkZxGHBt52bt/SvLRRolJEiNjSaTrLntAQ9dFChx66aWKbOplnwZeVpepd3JIMvqXFpoxqh8Sp
+FCmxGUxLOT8cDiwNEBE8BMA8Mpe4kfvaxZxKlJ9LOHooFgCPTD1KueDcpIiF9Sn1HuTgClMp8CpdGhmc
+H9s7iZCd9L9vVYqF/oBWSQPrAYP2dYd/2T/2h4cPjrmoXZ+awcUtCy4yVT7xorIbkGgwkEnJ6rtkZV4L
+7QRfhG4l+PL04iXpsX6M9Zdrt+vufQDHN44DPAIAAA==
+`,
+	},
+
+	"/templates/pipeline_graph.html": {
+		name:    "pipeline_graph.html",
+		local:   "../templates/pipeline_graph.html",
+		size:    1134,
 		modtime: 0,
 		compressed: `
-H4sIAAAAAAAC/5TRMU8sIRAH8P4+BY/25eC9szGGxUItLIwW11giO7uMB8wG5rxsLvfdDdnTxNhoBeFP
-fpnM3/y5fbzZPj/dicAp2pVph4guj52ELK0J4Hq7EkIIk4Cd8MGVCtzJPQ/rS3mOGDmCPR7Vtl1OJ6OX
-lyWNmHeiQOxkDVTY71mgpyxFKDB0UuvD4aBogswQIQGXWSHpwb21Xwo9Sf1d4jlCDQD8wQTmqV5pPVDm
-qkaiMYKbsCpPSTfpenAJ49w9OIaCLv6995Sr/AXtqQc1Aqc+tgn/qwv1T6czpzD3ONJ6wrxTCbPy9ROv
-vuDEoocBiqjF/5RszGuV1uhFsCujl0bMC/Vz62vzZe1hY98DAAD//7qRGmLTAQAA
+H4sIAAAAAAAC/6STv27bMBDGdz/FgVniQZQlN6oRSF6KAC1aZApQa1TMk0REJg2SjuQSfPeCtPwnAdwG
+rb3w7kjdx9/Hy9tsaS19rDboHNxaS7+J7c487bfo3DSP22w5yfVrAz1npi2ItfSnXzlHoEXetCbkvoal
+T9ZSmKiuNrzbF0RXQkcaFa/Hgua/sCBJQmDYdEIXpDVmex/Hfd/Tfk6lauJ0NpvF+rUhywkAgLURqEo0
+CPSBNaidC+m84wJhSEL3VeI77w9BGYIhPVTSUDkEZQi0UfIFfYLXQB+Ukko7d7PO0kW6sBY7jc7dfL7z
+f2tRsPOhaISQkvggLjc4GBj8xzacbSUXBugqgWNfX6gYg9tztUyAlukUoszv8OejSqxbqQqy4Yx1SLwf
+T62Su6bd7oxzeex3nWlcyv5fFUl6TQXUvOsKMnIJoo5NQWG908jeCwusrkSjhY+SnS2soFVYB2t+cPHi
+3Gh5KCpcj3eiq9MtaOmXH32KaijIp9NFcIFVnX3U/3l9lzyf/I8vhJ1he6Z0dYF1BgdF0zfYaQlJdh2z
+H4t+lP8sOxZQf+e+7z2cRvOS9T/LmP/N7T8zmYXfkYmX+UXuhEGl3+rL4+r9o8j9RC8nvwcAe4+iVG4E
+AAA=
 `,
 	},
 
@@ -278,14 +313,14 @@ vuDEoocBiqjF/5RszGuV1uhFsCujl0bMC/Vz62vzZe1hY98DAAD//7qRGmLTAQAA
 		size:    1946,
 		modtime: 0,
 		compressed: `
-H4sIAAAAAAAC/7SVwXLTMBCG7zyFxnRyIjVcU1scSpnhAMN0eAFZ2gRNlZVmJbdujd+dsWyrTp0LtL5k
-rOjX/tlv/8hFEJUB5sOjgTKrLCmgrXdCajzs2MeMv2OMsSLQ8DAsFJPWeCew/MSE0QcsDewDLyr+tTbm
-hzhCkVe8yIM6OcU3WHl3NXz+mS8W0oWBBAxAvcU3dHX49ejW9PheBxHAX1v09RHUFxHEim63IEHfA/kV
-PX6SleC9XdXkpnGWwqKRIp/i07YXgu1Kdnltj84iYLhB5azG0HWjgAQegF2QfdCooPkQH+OZW/vgR9kg
-3TK9Z3AP+Cyf7Y+5TdEW8u5Atka1Y+8BIOOzSmA8LI/ytgVUbDvb6VG1bW93OUW962Kn/wZxKpLC/Koq
-Z+L6X/XGgWbDRGeETkcDMo0GZD+a+CNSil+AjLUF+02wL7M+AF33+clpB0YjoDhCuQC6eZJTPpIA5Mn3
-dxpVSaNlxidFkQu+dK/oZSuAaj7VN0a1IUF0dZ6eIzvRc2QTvef/5yr4HNklPjd5Rn5Rcpbf2XbWJZhw
-QeMmXNC4hCvdNKvQgsYtacFoGWFFxSvCNl+lu3HQFXl8JfO/AQAA//9We3KLmgcAAA==
+H4sIAAAAAAAC/7SVTW7bMBCF9z0FoQZe1VG7dSR2kaZAFy2KoBegyLFLhB4SQypRouruhX7IyJE3bSIv
+DNJ+nOf55pkqgqgMMB8eDZRZZUkBbb0TUuNhxz5m/B1jjBWBxsW4UUxa453A8hMTRh+wNLAPvKj419qY
+H+IIRV7xIg/q5BTfYOXd1fj+Z75ZSBcGEjAA9Rbf0NXh16Nb0+N7HUQAf23R10dQX0QQK7rdggR9D+RX
+9PhJVoL3dlWTm8ZZCotGijzGp20vBNuV7PLaHp1FwHCDylmNoesmAQk8ALsg+6BRQfNhWA5nbu2Dn2Sj
+dMv0nsE94LN89v2U2xRtIe8OZGtUO/YeADI+qwTGw/Iob1tAxbZdd4KqbXu7yxj1rhs6/TeIsUgK86uq
+nInrf9WbBpqNE50ROh0NyDQakP1ohh+RUvwCZP8qBPtNsC+zPgBd9/nJaQdGI6A4QrkAunmSMR9JAPLk
+8zuNqqTJMuNRUeSCL90retkKoJpP9Y1RbUgQXZ2n58hGeo5sovf8/1wFnyO7xOeiZ8aj5Cy/s+2sSzDh
+gsZFXNC4hCvdNKvQgsYtacFkmfGoeEXY5rt0N466Ih8eyfzvAFZ7couaBwAA
 `,
 	},
 
@@ -295,11 +330,10 @@ QeMmXNC4hCvdNKvQgsYtacFoGWFFxSvCNl+lu3HQFXl8JfO/AQAA//9We3KLmgcAAA==
 		size:    420,
 		modtime: 0,
 		compressed: `
-H4sIAAAAAAAC/2SRwW7DIBBE7/6KVRr1VMc5u5gfqFT11Ds2U8sqWVuwqRoR/r1yTCpb4YAEO48ZDarV
-MR7ezQkp1apqdaHEtA4U5OLQ7NrRW/gyTKYbuK/puNMFEVGMtB/Y4pfqho6UUr71hnvk0Qvt4XACyyw6
-fPhxgpcBIasXoqThi/ADztRqOC8l/j+L6b57P57Z1vQEIEdZnoELeER1jGBL5WqixJJxQ89NBxZ4favg
-nvTaQ95wSWnuQlVi9RrUz9yG6XXZr+vDg3TrsTX4NO6M2WLDVOLv3YJtSoWqbl+h/wIAAP//aLmk3KQB
-AAA=
+H4sIAAAAAAAC/2SRwW6DMBBE73zFKo16KiFnavwDlaqeejd4ilCdBZlN1cjZf68IpCKJD5bsmecdjU1t
+U9q9uwNUS1PUNjPi6gAa5RRQbeo+esR8HFzTcVvSfmMzIqKUaNuxxy+VFe1JdbmNjlss0gttEXAAy2Ta
+fcR+QJQO4+KeiZy6L8IPeKFW4rSMxP8srvluY39kX9ITgCXK/AzCiEfUpgT2lK8UI55c6FquGrAg2ksF
+16TnFvKGk+rUhSnE2zVon7keh9d5P68PD9bbGbcDPl04QvWOKSReuwV71cwUl6+wfwMAaLmk3KQBAAA=
 `,
 	},
 
@@ -314,9 +348,11 @@ var _escDirs = map[string][]os.FileInfo{
 
 	"../templates/": {
 		_escData["/templates/component_header.html"],
+		_escData["/templates/errors_table.html"],
 		_escData["/templates/extensions_table.html"],
 		_escData["/templates/footer.html"],
 		_escData["/templates/header.html"],
+		_escData["/templates/pipeline_graph.html"],
 		_escData["/templates/pipelines_table.html"],
 		_escData["/templates/properties_table.html"],
 	},
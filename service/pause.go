@@ -0,0 +1,81 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// pausePipelines stops the receivers, processors and exporters, draining the data they hold,
+// while the extensions keep running. The configuration cannot be applied until the pipelines
+// are resumed.
+func (app *Application) pausePipelines(ctx context.Context) error {
+	app.configMu.Lock()
+	defer app.configMu.Unlock()
+	if !app.configurable {
+		return errors.New("the collector is not running")
+	}
+	if app.paused {
+		return nil
+	}
+
+	app.logger.Info("Pausing pipelines...")
+	if err := app.builtExtensions.NotifyPipelineNotReady(); err != nil {
+		app.logger.Warn("Failed to notify that pipeline is not ready", zap.Error(err))
+	}
+	err := app.shutdownPipelines(ctx)
+	app.builtReceivers, app.builtPipelines, app.builtExporters = nil, nil, nil
+	app.paused = true
+	if err != nil {
+		return fmt.Errorf("failed to shutdown pipelines: %w", err)
+	}
+	app.logger.Info("Pipelines paused.")
+	return nil
+}
+
+// resumePipelines builds and starts the pipelines of the configuration again, after they were
+// paused.
+func (app *Application) resumePipelines(ctx context.Context) error {
+	app.configMu.Lock()
+	defer app.configMu.Unlock()
+	if !app.configurable {
+		return errors.New("the collector is not running")
+	}
+	if !app.paused {
+		return nil
+	}
+
+	app.logger.Info("Resuming pipelines...")
+	built, err := app.buildPipelines(app.config)
+	if err != nil {
+		return fmt.Errorf("cannot build the pipelines: %w", err)
+	}
+	app.builtExporters, app.builtPipelines, app.builtReceivers = built.exporters, built.pipelines, built.receivers
+	if err = app.startPipelines(ctx); err != nil {
+		// Stop the components started before the failure, the pipelines stay paused.
+		if serr := app.shutdownPipelines(ctx); serr != nil {
+			app.logger.Warn("Failed to shutdown pipelines", zap.Error(serr))
+		}
+		app.builtReceivers, app.builtPipelines, app.builtExporters = nil, nil, nil
+		return err
+	}
+	app.paused = false
+	app.logger.Info("Pipelines resumed.")
+	return app.builtExtensions.NotifyPipelineReady()
+}
@@ -0,0 +1,50 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplication_PauseResumePipelines(t *testing.T) {
+	app, stop := startApplyConfigApplication(t)
+	defer stop()
+
+	require.NoError(t, app.pausePipelines(context.Background()))
+	assert.Empty(t, app.builtReceivers)
+	assert.Empty(t, app.builtExporters)
+	// Pausing twice does nothing.
+	require.NoError(t, app.pausePipelines(context.Background()))
+
+	err := app.ApplyConfig(context.Background(), []byte(applyConfigBase))
+	assert.EqualError(t, err, "the pipelines are paused")
+
+	require.NoError(t, app.resumePipelines(context.Background()))
+	assert.NotEmpty(t, app.builtReceivers)
+	assert.NotEmpty(t, app.builtExporters)
+	require.NoError(t, app.resumePipelines(context.Background()))
+
+	assert.NoError(t, app.ApplyConfig(context.Background(), []byte(applyConfigBase)))
+}
+
+func TestApplication_PausePipelinesNotRunning(t *testing.T) {
+	app := &Application{}
+	assert.Error(t, app.pausePipelines(context.Background()))
+	assert.Error(t, app.resumePipelines(context.Background()))
+}
@@ -18,7 +18,6 @@ package service
 
 import (
 	"context"
-	"errors"
 	"flag"
 	"fmt"
 	"net/http"
@@ -27,7 +26,9 @@ import (
 	"path"
 	"runtime"
 	"sort"
+	"sync"
 	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -37,6 +38,7 @@ import (
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/config/configcheck"
 	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/config/configprovider"
 	"go.opentelemetry.io/collector/config/configtelemetry"
 	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/internal/collector/telemetry"
@@ -49,6 +51,7 @@ const (
 	servicezPath   = "servicez"
 	pipelinezPath  = "pipelinez"
 	extensionzPath = "extensionz"
+	topologyzPath  = "topologyz"
 )
 
 // State defines Application's state.
@@ -76,6 +79,23 @@ type Application struct {
 	factories component.Factories
 	config    *configmodels.Config
 
+	// configMu protects the configuration and the pipelines, which are replaced when a
+	// configuration is applied while the collector runs.
+	configMu sync.RWMutex
+	// configurable is true while the pipelines run and a configuration can be applied.
+	configurable bool
+	// paused is true while the pipelines are stopped by a pause of the Windows service, the
+	// configuration cannot be applied until they are resumed.
+	paused bool
+	// refreshConfig is true when the configuration is loaded by FileLoaderConfigFactory, it is
+	// then re-read and applied every --config-refresh-interval or when the files change with
+	// --config-watch.
+	refreshConfig bool
+	// configSourcesMu serializes the refreshes of the configuration, configSources is the
+	// last configuration read from the --config flags.
+	configSourcesMu sync.Mutex
+	configSources   map[string]interface{}
+
 	// stopTestChan is used to terminate the application in end to end tests.
 	stopTestChan chan struct{}
 
@@ -84,6 +104,13 @@ type Application struct {
 
 	// asyncErrorChannel is used to signal a fatal error from any component.
 	asyncErrorChannel chan error
+
+	// notifier notifies systemd of the state of the collector, it is nil when the collector
+	// does not run as a systemd service.
+	notifier *systemdNotifier
+
+	// topology holds the counters of the components at the previous render of the topology page.
+	topology topologySnapshot
 }
 
 // Parameters holds configuration for creating a new Application.
@@ -107,21 +134,21 @@ type Parameters struct {
 // The factories also belong to the Application and are equal to the factories passed via Parameters.
 type ConfigFactory func(v *viper.Viper, cmd *cobra.Command, factories component.Factories) (*configmodels.Config, error)
 
-// FileLoaderConfigFactory implements ConfigFactory and it creates configuration from file
-// and from --set command line flag (if the flag is present).
+// FileLoaderConfigFactory implements ConfigFactory and it creates configuration from files
+// and from --set command line flag (if the flag is present). The configurations passed via
+// the --config flags, local files and directories or remote configurations retrieved by the
+// config providers, are merged, see config.ReadFiles.
 func FileLoaderConfigFactory(v *viper.Viper, cmd *cobra.Command, factories component.Factories) (*configmodels.Config, error) {
-	file := builder.GetConfigFile()
-	if file == "" {
-		return nil, errors.New("config file not specified")
-	}
-	// first load the config file
-	v.SetConfigFile(file)
-	err := v.ReadInConfig()
+	// first load the config files
+	merged, err := readConfigSources(context.Background(), builder.GetConfigFiles())
 	if err != nil {
-		return nil, fmt.Errorf("error loading config file %q: %v", file, err)
+		return nil, err
+	}
+	if err = v.MergeConfigMap(merged); err != nil {
+		return nil, err
 	}
 
-	// next overlay the config file with --set flags
+	// next overlay the config files with --set flags
 	if err := AddSetFlagProperties(v, cmd); err != nil {
 		return nil, fmt.Errorf("failed to process set flag: %v", err)
 	}
@@ -141,12 +168,17 @@ func New(params Parameters) (*Application, error) {
 	if factory == nil {
 		// use default factory that loads the configuration file
 		factory = FileLoaderConfigFactory
+		app.refreshConfig = true
 	}
 
 	rootCmd := &cobra.Command{
 		Use:  params.ApplicationStartInfo.ExeName,
 		Long: params.ApplicationStartInfo.LongName,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if dump, _ := cmd.Flags().GetBool(dumpConfigFlagName); dump {
+				return app.dumpConfig(cmd, factory)
+			}
+
 			err := app.init(params.LoggingOptions)
 			if err != nil {
 				return err
@@ -165,6 +197,7 @@ func New(params Parameters) (*Application, error) {
 	flagSet := new(flag.FlagSet)
 	addFlagsFns := []func(*flag.FlagSet){
 		configtelemetry.Flags,
+		configprovider.Flags,
 		telemetry.Flags,
 		builder.Flags,
 		loggerFlags,
@@ -172,8 +205,11 @@ func New(params Parameters) (*Application, error) {
 	for _, addFlags := range addFlagsFns {
 		addFlags(flagSet)
 	}
-	rootCmd.Flags().AddGoFlagSet(flagSet)
-	addSetFlag(rootCmd.Flags())
+	// The flags are persistent to be available to the validate command.
+	rootCmd.PersistentFlags().AddGoFlagSet(flagSet)
+	addSetFlag(rootCmd.PersistentFlags())
+	rootCmd.Flags().Bool(dumpConfigFlagName, false, "Print the effective configuration, with the default values of the components and the sensitive settings redacted, and exit")
+	rootCmd.AddCommand(app.newValidateCommand(factory))
 
 	app.rootCmd = rootCmd
 
@@ -203,6 +239,12 @@ func (app *Application) ReportFatalError(err error) {
 	app.asyncErrorChannel <- err
 }
 
+// ReportComponentStatus notifies the extensions watching the status of the components
+// that the status of a component changed.
+func (app *Application) ReportComponentStatus(event component.StatusEvent) {
+	app.builtExtensions.NotifyComponentStatus(event)
+}
+
 func (app *Application) GetFactory(kind component.Kind, componentType configmodels.Type) component.Factory {
 	switch kind {
 	case component.KindReceiver:
@@ -229,6 +271,9 @@ func (app *Application) RegisterZPages(mux *http.ServeMux, pathPrefix string) {
 	mux.HandleFunc(path.Join(pathPrefix, servicezPath), app.handleServicezRequest)
 	mux.HandleFunc(path.Join(pathPrefix, pipelinezPath), app.handlePipelinezRequest)
 	mux.HandleFunc(path.Join(pathPrefix, extensionzPath), app.handleExtensionzRequest)
+	mux.HandleFunc(path.Join(pathPrefix, topologyzPath), app.handleTopologyzRequest)
+	mux.HandleFunc(path.Join(pathPrefix, errorzPath), app.handleErrorzRequest)
+	mux.HandleFunc(path.Join(pathPrefix, configzPath), app.handleConfigzRequest)
 }
 
 func (app *Application) Shutdown() {
@@ -326,41 +371,29 @@ func (app *Application) setupExtensions(ctx context.Context) error {
 }
 
 func (app *Application) setupPipelines(ctx context.Context) error {
-	// Pipeline is built backwards, starting from exporters, so that we create objects
-	// which are referenced before objects which reference them.
-
-	// First create exporters.
-	var err error
-	app.builtExporters, err = builder.BuildExporters(app.logger, app.info, app.config, app.factories.Exporters)
+	built, err := app.buildPipelines(app.config)
 	if err != nil {
-		return fmt.Errorf("cannot build builtExporters: %w", err)
+		return err
 	}
+	app.builtExporters, app.builtPipelines, app.builtReceivers = built.exporters, built.pipelines, built.receivers
+	return app.startPipelines(ctx)
+}
 
+// startPipelines starts the built exporters, processors and receivers, in this order so that
+// the components are started before the components sending data to them.
+func (app *Application) startPipelines(ctx context.Context) error {
 	app.logger.Info("Starting exporters...")
-	err = app.builtExporters.StartAll(ctx, app)
+	err := app.builtExporters.StartAll(ctx, app)
 	if err != nil {
 		return fmt.Errorf("cannot start builtExporters: %w", err)
 	}
 
-	// Create pipelines and their processors and plug exporters to the
-	// end of the pipelines.
-	app.builtPipelines, err = builder.BuildPipelines(app.logger, app.info, app.config, app.builtExporters, app.factories.Processors)
-	if err != nil {
-		return fmt.Errorf("cannot build pipelines: %w", err)
-	}
-
 	app.logger.Info("Starting processors...")
 	err = app.builtPipelines.StartProcessors(ctx, app)
 	if err != nil {
 		return fmt.Errorf("cannot start processors: %w", err)
 	}
 
-	// Create receivers and plug them into the start of the pipelines.
-	app.builtReceivers, err = builder.BuildReceivers(app.logger, app.info, app.config, app.builtPipelines, app.factories.Receivers)
-	if err != nil {
-		return fmt.Errorf("cannot build receivers: %w", err)
-	}
-
 	app.logger.Info("Starting receivers...")
 	err = app.builtReceivers.StartAll(ctx, app)
 	if err != nil {
@@ -371,33 +404,88 @@ func (app *Application) setupPipelines(ctx context.Context) error {
 }
 
 func (app *Application) shutdownPipelines(ctx context.Context) error {
-	// Shutdown order is the reverse of building: first receivers, then flushing pipelines
-	// giving senders a chance to send all their data. This may take time, the allowed
-	// time should be part of configuration.
+	// Shutdown order is the reverse of building: first receivers so that no new data comes in,
+	// then the processors flush their data to the exporters, and last the exporters drain their
+	// queues. Each stage is bounded by its own timeout, the next stage starts when it expires.
+	timeouts := builder.GetShutdownTimeouts()
 
 	var errs []error
 
 	app.logger.Info("Stopping receivers...")
-	err := app.builtReceivers.ShutdownAll(ctx)
+	err := app.shutdownStage(ctx, "receivers", timeouts.Receivers, app.builtReceivers.ShutdownAll)
 	if err != nil {
 		errs = append(errs, fmt.Errorf("failed to stop receivers: %w", err))
 	}
 
 	app.logger.Info("Stopping processors...")
-	err = app.builtPipelines.ShutdownProcessors(ctx)
+	err = app.shutdownStage(ctx, "processors", timeouts.Processors, app.builtPipelines.ShutdownProcessors)
 	if err != nil {
 		errs = append(errs, fmt.Errorf("failed to shutdown processors: %w", err))
 	}
+	app.logDrainStats("Processors drained", app.builtPipelines.ProcessorsDrainStats())
 
 	app.logger.Info("Stopping exporters...")
-	err = app.builtExporters.ShutdownAll(ctx)
+	err = app.shutdownStage(ctx, "exporters", timeouts.Exporters, app.builtExporters.ShutdownAll)
 	if err != nil {
 		errs = append(errs, fmt.Errorf("failed to shutdown exporters: %w", err))
 	}
+	app.logDrainStats("Exporters drained", app.builtExporters.DrainStats())
 
 	return consumererror.CombineErrors(errs)
 }
 
+// shutdownStage runs shutdown with a context expiring after timeout, and returns when it returns
+// or when the timeout expires, whichever comes first.
+func (app *Application) shutdownStage(ctx context.Context, stage string, timeout time.Duration, shutdown func(context.Context) error) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- shutdown(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+	}
+	// The shutdown may have returned at the same time as the timeout expired.
+	select {
+	case err := <-done:
+		return err
+	default:
+	}
+	app.logger.Warn("Shutdown timeout expired, moving on to the next stage",
+		zap.String("stage", stage), zap.Duration("timeout", timeout))
+	return fmt.Errorf("%s did not stop within %v: %w", stage, timeout, ctx.Err())
+}
+
+// logDrainStats logs the items drained at shutdown, as a warning when items were dropped.
+func (app *Application) logDrainStats(msg string, stats component.DrainStats) {
+	log := app.logger.Info
+	if stats.Dropped > 0 {
+		log = app.logger.Warn
+	}
+	log(msg, zap.Int64("flushed", stats.Flushed), zap.Int64("dropped", stats.Dropped))
+}
+
+// notifySystemd notifies systemd of the state of the collector, when it runs as a systemd
+// service.
+func (app *Application) notifySystemd(state string) {
+	if err := app.notifier.notify(state); err != nil {
+		app.logger.Warn("Failed to notify systemd", zap.String("state", state), zap.Error(err))
+	}
+}
+
+// setConfigurable sets whether a configuration can be applied, it waits for the configuration
+// being applied if any.
+func (app *Application) setConfigurable(configurable bool) {
+	app.configMu.Lock()
+	app.configurable = configurable
+	app.configMu.Unlock()
+}
+
 func (app *Application) shutdownExtensions(ctx context.Context) error {
 	app.logger.Info("Stopping extensions...")
 	err := app.builtExtensions.ShutdownAll(ctx)
@@ -415,6 +503,12 @@ func (app *Application) execute(ctx context.Context, factory ConfigFactory) erro
 	)
 	app.stateChannel <- Starting
 
+	app.notifier = newSystemdNotifier()
+	stopWatchdog := app.notifier.startWatchdog(func(err error) {
+		app.logger.Warn("Failed to notify the systemd watchdog", zap.Error(err))
+	})
+	defer stopWatchdog()
+
 	// Set memory ballast
 	ballast, ballastSizeBytes := app.createMemoryBallast()
 
@@ -435,9 +529,16 @@ func (app *Application) execute(ctx context.Context, factory ConfigFactory) erro
 	if err != nil {
 		return err
 	}
+	app.setConfigurable(true)
+	app.notifySystemd(sdNotifyReady)
+	stopConfigRefresh := app.startConfigRefresh(ctx)
+	stopConfigWatch := app.startConfigWatch(ctx)
 
 	// Everything is ready, now run until an event requiring shutdown happens.
 	app.runAndWaitForShutdownEvent()
+	stopConfigWatch()
+	stopConfigRefresh()
+	app.setConfigurable(false)
 
 	// Accumulate errors and proceed with shutting down remaining components.
 	var errs []error
@@ -445,6 +546,7 @@ func (app *Application) execute(ctx context.Context, factory ConfigFactory) erro
 	// Begin shutdown sequence.
 	runtime.KeepAlive(ballast)
 	app.logger.Info("Starting shutdown...")
+	app.notifySystemd(sdNotifyStopping)
 
 	err = app.builtExtensions.NotifyPipelineNotReady()
 	if err != nil {
@@ -503,11 +605,28 @@ func (app *Application) handleServicezRequest(w http.ResponseWriter, r *http.Req
 		ComponentEndpoint: extensionzPath,
 		Link:              true,
 	})
+	zpages.WriteHTMLComponentHeader(w, zpages.ComponentHeaderData{
+		Name:              "Topology",
+		ComponentEndpoint: topologyzPath,
+		Link:              true,
+	})
+	zpages.WriteHTMLComponentHeader(w, zpages.ComponentHeaderData{
+		Name:              "Recent Errors",
+		ComponentEndpoint: errorzPath,
+		Link:              true,
+	})
+	zpages.WriteHTMLComponentHeader(w, zpages.ComponentHeaderData{
+		Name:              "Effective Configuration",
+		ComponentEndpoint: configzPath,
+		Link:              true,
+	})
 	zpages.WriteHTMLPropertiesTable(w, zpages.PropertiesTableData{Name: "Build And Runtime", Properties: version.RuntimeVar()})
 	zpages.WriteHTMLFooter(w)
 }
 
 func (app *Application) handlePipelinezRequest(w http.ResponseWriter, r *http.Request) {
+	app.configMu.RLock()
+	defer app.configMu.RUnlock()
 	r.ParseForm()
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	pipelineName := r.Form.Get(zPipelineName)
@@ -138,6 +138,29 @@ func TestApplication_ReportError(t *testing.T) {
 	assert.Equal(t, Closed, <-app.GetStateChannel())
 }
 
+func TestApplication_ShutdownStage(t *testing.T) {
+	app := &Application{logger: zap.NewNop()}
+
+	errShutdown := errors.New("shutdown failed")
+	err := app.shutdownStage(context.Background(), "exporters", time.Second, func(context.Context) error {
+		return errShutdown
+	})
+	assert.Equal(t, errShutdown, err)
+
+	// A stuck component does not prevent the shutdown from continuing after the timeout.
+	stuck := make(chan struct{})
+	defer close(stuck)
+	start := time.Now()
+	err = app.shutdownStage(context.Background(), "receivers", 50*time.Millisecond, func(context.Context) error {
+		<-stuck
+		return nil
+	})
+	assert.Less(t, int64(time.Since(start)), int64(time.Second))
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Contains(t, err.Error(), "receivers did not stop within 50ms")
+}
+
 func TestApplication_StartAsGoRoutine(t *testing.T) {
 	factories, err := defaultcomponents.Components()
 	require.NoError(t, err)
@@ -617,6 +640,41 @@ func TestSetFlag_component_does_not_exist(t *testing.T) {
 	require.NotNil(t, cfg)
 }
 
+func TestFileLoaderConfigFactory_MultipleFiles(t *testing.T) {
+	factories, err := defaultcomponents.Components()
+	require.NoError(t, err)
+
+	app, err := New(Parameters{Factories: factories})
+	require.NoError(t, err)
+	err = app.rootCmd.ParseFlags([]string{
+		"--config=testdata/otelcol-config.yaml",
+		"--config=testdata/otelcol-config-override.yaml",
+		"--set=receivers.jaeger.protocols.grpc.endpoint=localhost:12345",
+	})
+	require.NoError(t, err)
+	cfg, err := FileLoaderConfigFactory(app.v, app.rootCmd, factories)
+	require.NoError(t, err)
+	require.NoError(t, cfg.Validate())
+
+	batch := cfg.Processors["batch"].(*batchprocessor.Config)
+	assert.Equal(t, time.Second*2, batch.Timeout)
+	jaeger := cfg.Receivers["jaeger"].(*jaegerreceiver.Config)
+	assert.Equal(t, "localhost:12345", jaeger.GRPC.NetAddr.Endpoint)
+	assert.Equal(t, []string{"jaeger"}, cfg.Service.Pipelines["traces"].Receivers)
+	assert.Equal(t, []string{"batch"}, cfg.Service.Pipelines["traces"].Processors)
+}
+
+func TestFileLoaderConfigFactory_NoFile(t *testing.T) {
+	factories, err := defaultcomponents.Components()
+	require.NoError(t, err)
+
+	app, err := New(Parameters{Factories: factories})
+	require.NoError(t, err)
+	require.NoError(t, app.rootCmd.ParseFlags([]string{}))
+	_, err = FileLoaderConfigFactory(app.v, app.rootCmd, factories)
+	assert.EqualError(t, err, "config file not specified")
+}
+
 func constructMimumalOpConfig(t *testing.T, factories component.Factories) *configmodels.Config {
 	configStr := `
 receivers:
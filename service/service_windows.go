@@ -17,6 +17,7 @@
 package service
 
 import (
+	"context"
 	"fmt"
 	"syscall"
 
@@ -55,13 +56,31 @@ func (s *WindowsService) Execute(args []string, requests <-chan svc.ChangeReques
 		elog.Error(3, fmt.Sprintf("failed to start service: %v", err))
 		return false, 1064 // 1064: ERROR_EXCEPTION_IN_SERVICE
 	}
-	changes <- svc.Status{State: svc.Running, Accepts: svc.AcceptStop | svc.AcceptShutdown}
+	const accepts = svc.AcceptStop | svc.AcceptShutdown | svc.AcceptPauseAndContinue
+	changes <- svc.Status{State: svc.Running, Accepts: accepts}
 
 	for req := range requests {
 		switch req.Cmd {
 		case svc.Interrogate:
 			changes <- req.CurrentStatus
 
+		case svc.Pause:
+			// The pipelines are stopped, the extensions keep running.
+			changes <- svc.Status{State: svc.PausePending, Accepts: accepts}
+			if err := s.app.pausePipelines(context.Background()); err != nil {
+				elog.Error(3, fmt.Sprintf("errors occurred while pausing the service: %v", err))
+			}
+			changes <- svc.Status{State: svc.Paused, Accepts: accepts}
+
+		case svc.Continue:
+			changes <- svc.Status{State: svc.ContinuePending, Accepts: accepts}
+			if err := s.app.resumePipelines(context.Background()); err != nil {
+				elog.Error(3, fmt.Sprintf("failed to resume the service: %v", err))
+				changes <- svc.Status{State: svc.Paused, Accepts: accepts}
+				continue
+			}
+			changes <- svc.Status{State: svc.Running, Accepts: accepts}
+
 		case svc.Stop, svc.Shutdown:
 			changes <- svc.Status{State: svc.StopPending}
 			if err := s.stop(appErrorChannel); err != nil {
@@ -50,6 +50,12 @@ func TestWindowsService_Execute(t *testing.T) {
 	assert.Equal(t, svc.Running, (<-changes).State)
 	requests <- svc.ChangeRequest{Cmd: svc.Interrogate, CurrentStatus: svc.Status{State: svc.Running}}
 	assert.Equal(t, svc.Running, (<-changes).State)
+	requests <- svc.ChangeRequest{Cmd: svc.Pause}
+	assert.Equal(t, svc.PausePending, (<-changes).State)
+	assert.Equal(t, svc.Paused, (<-changes).State)
+	requests <- svc.ChangeRequest{Cmd: svc.Continue}
+	assert.Equal(t, svc.ContinuePending, (<-changes).State)
+	assert.Equal(t, svc.Running, (<-changes).State)
 	requests <- svc.ChangeRequest{Cmd: svc.Stop}
 	assert.Equal(t, svc.StopPending, (<-changes).State)
 	assert.Equal(t, svc.Stopped, (<-changes).State)
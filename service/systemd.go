@@ -0,0 +1,102 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"net"
+	"os"
+	"strconv"
+	"time"
+)
+
+// The states notified to systemd, see sd_notify(3).
+const (
+	sdNotifyReady     = "READY=1"
+	sdNotifyReloading = "RELOADING=1"
+	sdNotifyStopping  = "STOPPING=1"
+	sdNotifyWatchdog  = "WATCHDOG=1"
+)
+
+// systemdNotifier notifies systemd of the state of the collector when it runs as a service of
+// Type=notify, on the socket of the NOTIFY_SOCKET environment variable. It keeps the watchdog
+// of the service alive when WatchdogSec is set.
+type systemdNotifier struct {
+	addr *net.UnixAddr
+	// watchdog is the interval between the notifications keeping the watchdog alive, 0 when
+	// the watchdog is disabled.
+	watchdog time.Duration
+}
+
+// newSystemdNotifier returns the notifier of the service, or nil when the collector does not run
+// as a systemd service.
+func newSystemdNotifier() *systemdNotifier {
+	socket := os.Getenv("NOTIFY_SOCKET")
+	if socket == "" {
+		return nil
+	}
+	n := &systemdNotifier{addr: &net.UnixAddr{Name: socket, Net: "unixgram"}}
+
+	// The watchdog is for this process when WATCHDOG_PID is not set.
+	if pid := os.Getenv("WATCHDOG_PID"); pid == "" || pid == strconv.Itoa(os.Getpid()) {
+		if usec, err := strconv.ParseInt(os.Getenv("WATCHDOG_USEC"), 10, 64); err == nil && usec > 0 {
+			// Notify at half the watchdog timeout, as recommended by sd_watchdog_enabled(3).
+			n.watchdog = time.Duration(usec) * time.Microsecond / 2
+		}
+	}
+	return n
+}
+
+// notify sends the state to systemd, it does nothing when the notifier is nil.
+func (n *systemdNotifier) notify(state string) error {
+	if n == nil {
+		return nil
+	}
+	conn, err := net.DialUnix(n.addr.Net, nil, n.addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	_, err = conn.Write([]byte(state))
+	return err
+}
+
+// startWatchdog keeps the watchdog alive until the returned function is called. It does nothing
+// when the notifier is nil or the watchdog is disabled.
+func (n *systemdNotifier) startWatchdog(onError func(err error)) func() {
+	if n == nil || n.watchdog == 0 {
+		return func() {}
+	}
+	ticker := time.NewTicker(n.watchdog)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		for {
+			select {
+			case <-ticker.C:
+				if err := n.notify(sdNotifyWatchdog); err != nil {
+					onError(err)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		ticker.Stop()
+		close(done)
+		<-stopped
+	}
+}
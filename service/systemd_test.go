@@ -0,0 +1,127 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !windows
+
+package service
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// listenNotifySocket listens on a NOTIFY_SOCKET, and returns the states it receives.
+func listenNotifySocket(t *testing.T) (<-chan string, func()) {
+	dir, err := ioutil.TempDir("", "sdnotify")
+	require.NoError(t, err)
+	socket := filepath.Join(dir, "notify.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socket, Net: "unixgram"})
+	require.NoError(t, err)
+	require.NoError(t, os.Setenv("NOTIFY_SOCKET", socket))
+
+	states := make(chan string, 100)
+	go func() {
+		defer close(states)
+		buf := make([]byte, 1024)
+		for {
+			n, err := conn.Read(buf)
+			if err != nil {
+				return
+			}
+			states <- string(buf[:n])
+		}
+	}()
+	return states, func() {
+		os.Unsetenv("NOTIFY_SOCKET")
+		conn.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func waitForState(t *testing.T, states <-chan string, want string) {
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case state := <-states:
+			if state == want {
+				return
+			}
+		case <-timeout:
+			t.Fatalf("%q was not notified", want)
+		}
+	}
+}
+
+func TestSystemdNotifier_NotRunningAsService(t *testing.T) {
+	os.Unsetenv("NOTIFY_SOCKET")
+	n := newSystemdNotifier()
+	assert.Nil(t, n)
+	assert.NoError(t, n.notify(sdNotifyReady))
+	n.startWatchdog(func(error) {})()
+}
+
+func TestSystemdNotifier_Notify(t *testing.T) {
+	states, stop := listenNotifySocket(t)
+	defer stop()
+
+	n := newSystemdNotifier()
+	require.NotNil(t, n)
+	assert.Zero(t, n.watchdog)
+	require.NoError(t, n.notify(sdNotifyReady))
+	assert.Equal(t, sdNotifyReady, <-states)
+}
+
+func TestSystemdNotifier_Watchdog(t *testing.T) {
+	states, stop := listenNotifySocket(t)
+	defer stop()
+	require.NoError(t, os.Setenv("WATCHDOG_USEC", "20000"))
+	defer os.Unsetenv("WATCHDOG_USEC")
+
+	// The watchdog of another process is ignored.
+	require.NoError(t, os.Setenv("WATCHDOG_PID", strconv.Itoa(os.Getpid()+1)))
+	assert.Zero(t, newSystemdNotifier().watchdog)
+	require.NoError(t, os.Setenv("WATCHDOG_PID", strconv.Itoa(os.Getpid())))
+	defer os.Unsetenv("WATCHDOG_PID")
+
+	n := newSystemdNotifier()
+	assert.Equal(t, 10*time.Millisecond, n.watchdog)
+	stopWatchdog := n.startWatchdog(func(err error) { assert.NoError(t, err) })
+	waitForState(t, states, sdNotifyWatchdog)
+	waitForState(t, states, sdNotifyWatchdog)
+	stopWatchdog()
+}
+
+func TestApplication_NotifySystemd(t *testing.T) {
+	states, stop := listenNotifySocket(t)
+	defer stop()
+
+	app, stopApp := startApplyConfigApplication(t)
+	waitForState(t, states, sdNotifyReady)
+
+	require.NoError(t, app.ApplyConfig(context.Background(), []byte(applyConfigBase)))
+	waitForState(t, states, sdNotifyReloading)
+	waitForState(t, states, sdNotifyReady)
+
+	stopApp()
+	waitForState(t, states, sdNotifyStopping)
+}
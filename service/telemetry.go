@@ -24,14 +24,20 @@ import (
 	"go.opencensus.io/stats/view"
 	"go.uber.org/zap"
 
+	"go.opentelemetry.io/collector/config/configauth"
 	"go.opentelemetry.io/collector/config/configtelemetry"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
 	"go.opentelemetry.io/collector/exporter/jaegerexporter"
 	"go.opentelemetry.io/collector/internal/collector/telemetry"
 	"go.opentelemetry.io/collector/obsreport"
 	"go.opentelemetry.io/collector/processor"
 	"go.opentelemetry.io/collector/processor/batchprocessor"
+	"go.opentelemetry.io/collector/processor/groupbytraceprocessor"
+	"go.opentelemetry.io/collector/processor/redactionprocessor"
+	"go.opentelemetry.io/collector/processor/tailsamplingprocessor"
 	fluentobserv "go.opentelemetry.io/collector/receiver/fluentforwardreceiver/observ"
 	"go.opentelemetry.io/collector/receiver/kafkareceiver"
+	"go.opentelemetry.io/collector/receiver/prometheusreceiver"
 	telemetry2 "go.opentelemetry.io/collector/service/internal/telemetry"
 	"go.opentelemetry.io/collector/translator/conventions"
 )
@@ -64,12 +70,18 @@ func (tel *appTelemetry) init(asyncErrorChannel chan<- error, ballastSizeBytes u
 
 	var views []*view.View
 	views = append(views, batchprocessor.MetricViews()...)
+	views = append(views, configauth.MetricViews()...)
+	views = append(views, exporterhelper.MetricViews()...)
 	views = append(views, fluentobserv.MetricViews()...)
+	views = append(views, groupbytraceprocessor.MetricViews()...)
 	views = append(views, jaegerexporter.MetricViews()...)
 	views = append(views, kafkareceiver.MetricViews()...)
 	views = append(views, obsreport.Configure(level)...)
 	views = append(views, processMetricsViews.Views()...)
 	views = append(views, processor.MetricViews()...)
+	views = append(views, prometheusreceiver.MetricViews()...)
+	views = append(views, redactionprocessor.MetricViews()...)
+	views = append(views, tailsamplingprocessor.MetricViews()...)
 
 	tel.views = views
 	if err = view.Register(views...); err != nil {
@@ -0,0 +1,302 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opencensus.io/stats/view"
+
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/obsreport"
+	"go.opentelemetry.io/collector/service/internal/zpages"
+)
+
+const (
+	zRefresh = "zrefresh"
+
+	defaultTopologyRefreshSeconds = 5
+
+	topologyNodeWidth    = 170
+	topologyNodeHeight   = 40
+	topologyColumnWidth  = 260
+	topologyRowHeight    = 60
+	topologyGraphPadding = 10
+)
+
+// topologyCounterKey identifies an obsreport counter of a component, e.g. the spans accepted
+// by a receiver.
+type topologyCounterKey struct {
+	kind    string
+	name    string
+	measure string
+}
+
+// topologySnapshot is the value of the obsreport counters of the components at a given time,
+// the throughputs being computed from the difference with the previous snapshot.
+type topologySnapshot struct {
+	mu       sync.Mutex
+	time     time.Time
+	counters map[topologyCounterKey]int64
+}
+
+// topologyMeasures are the keys of the obsreport counters of the components handling a signal.
+type topologyMeasures struct {
+	receiverAccepted  string
+	receiverRefused   string
+	processorAccepted string
+	processorRefused  string
+	processorDropped  string
+	exporterSent      string
+	exporterFailed    string
+}
+
+var topologyMeasuresByDataType = map[configmodels.DataType]topologyMeasures{
+	configmodels.TracesDataType: {
+		receiverAccepted:  obsreport.AcceptedSpansKey,
+		receiverRefused:   obsreport.RefusedSpansKey,
+		processorAccepted: obsreport.AcceptedSpansKey,
+		processorRefused:  obsreport.RefusedSpansKey,
+		processorDropped:  obsreport.DroppedSpansKey,
+		exporterSent:      obsreport.SentSpansKey,
+		exporterFailed:    obsreport.FailedToSendSpansKey,
+	},
+	configmodels.MetricsDataType: {
+		receiverAccepted:  obsreport.AcceptedMetricPointsKey,
+		receiverRefused:   obsreport.RefusedMetricPointsKey,
+		processorAccepted: obsreport.AcceptedMetricPointsKey,
+		processorRefused:  obsreport.RefusedMetricPointsKey,
+		processorDropped:  obsreport.DroppedMetricPointsKey,
+		exporterSent:      obsreport.SentMetricPointsKey,
+		exporterFailed:    obsreport.FailedToSendMetricPointsKey,
+	},
+	configmodels.LogsDataType: {
+		receiverAccepted:  obsreport.AcceptedLogRecordsKey,
+		receiverRefused:   obsreport.RefusedLogRecordsKey,
+		processorAccepted: obsreport.AcceptedLogRecordsKey,
+		processorRefused:  obsreport.RefusedLogRecordsKey,
+		processorDropped:  obsreport.DroppedLogRecordsKey,
+		exporterSent:      obsreport.SentLogRecordsKey,
+		exporterFailed:    obsreport.FailedToSendLogRecordsKey,
+	},
+}
+
+// readTopologyCounters reads the obsreport counters of all the components, it returns false
+// when the obsreport views are not registered, i.e. when the metrics of the collector are disabled.
+func readTopologyCounters() (map[topologyCounterKey]int64, bool) {
+	counters := map[topologyCounterKey]int64{}
+	registered := false
+	for _, measures := range topologyMeasuresByDataType {
+		for _, c := range []struct{ kind, measure string }{
+			{obsreport.ReceiverKey, measures.receiverAccepted},
+			{obsreport.ReceiverKey, measures.receiverRefused},
+			{obsreport.ProcessorKey, measures.processorAccepted},
+			{obsreport.ProcessorKey, measures.processorRefused},
+			{obsreport.ProcessorKey, measures.processorDropped},
+			{obsreport.ExporterKey, measures.exporterSent},
+			{obsreport.ExporterKey, measures.exporterFailed},
+		} {
+			rows, err := view.RetrieveData(c.kind + "/" + c.measure)
+			if err != nil {
+				continue
+			}
+			registered = true
+			for _, row := range rows {
+				sum, ok := row.Data.(*view.SumData)
+				if !ok {
+					continue
+				}
+				for _, t := range row.Tags {
+					if t.Key.Name() == c.kind {
+						// The receivers have a row per transport.
+						counters[topologyCounterKey{kind: c.kind, name: t.Value, measure: c.measure}] += int64(sum.Value)
+					}
+				}
+			}
+		}
+	}
+	return counters, registered
+}
+
+// update replaces the snapshot with the given counters, and returns the previous counters and
+// the time elapsed since they were read.
+func (s *topologySnapshot) update(counters map[topologyCounterKey]int64, now time.Time) (map[topologyCounterKey]int64, time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	previous, elapsed := s.counters, now.Sub(s.time)
+	s.counters, s.time = counters, now
+	return previous, elapsed
+}
+
+// topologyGraphBuilder builds the graphs of the pipelines from the counters of their components.
+type topologyGraphBuilder struct {
+	counters map[topologyCounterKey]int64
+	previous map[topologyCounterKey]int64
+	elapsed  time.Duration
+}
+
+func (b *topologyGraphBuilder) count(kind, name, measure string) int64 {
+	return b.counters[topologyCounterKey{kind: kind, name: name, measure: measure}]
+}
+
+// throughput returns the rate of the counter since the previous snapshot.
+func (b *topologyGraphBuilder) throughput(kind, name, measure string) string {
+	if b.previous == nil || b.elapsed <= 0 {
+		return "-/s"
+	}
+	key := topologyCounterKey{kind: kind, name: name, measure: measure}
+	rate := float64(b.counters[key]-b.previous[key]) / b.elapsed.Seconds()
+	return strconv.FormatFloat(rate, 'f', 1, 64) + "/s"
+}
+
+func topologyLink(pipeline, kind, name string) string {
+	return pipelinezPath + "?" + url.Values{
+		zPipelineName:  {pipeline},
+		zComponentName: {name},
+		zComponentKind: {kind},
+	}.Encode()
+}
+
+func topologyCounters(parts ...string) string {
+	return strings.Join(parts, ", ")
+}
+
+func (b *topologyGraphBuilder) build(pipeline *configmodels.Pipeline) zpages.PipelineGraphData {
+	measures := topologyMeasuresByDataType[pipeline.InputType]
+	rows := len(pipeline.Receivers)
+	if len(pipeline.Exporters) > rows {
+		rows = len(pipeline.Exporters)
+	}
+	if rows == 0 {
+		rows = 1
+	}
+	columns := len(pipeline.Processors) + 2
+	graph := zpages.PipelineGraphData{
+		Name:      pipeline.Name,
+		InputType: string(pipeline.InputType),
+		Width:     (columns-1)*topologyColumnWidth + topologyNodeWidth + 2*topologyGraphPadding,
+		Height:    (rows-1)*topologyRowHeight + topologyNodeHeight + 2*topologyGraphPadding,
+	}
+	node := func(column int, row float64, kind, name string, counters string, errors int64) zpages.PipelineGraphNodeData {
+		return zpages.PipelineGraphNodeData{
+			Kind:     kind,
+			Name:     name,
+			Link:     topologyLink(pipeline.Name, kind, name),
+			X:        topologyGraphPadding + column*topologyColumnWidth,
+			Y:        topologyGraphPadding + int(row*topologyRowHeight),
+			Width:    topologyNodeWidth,
+			Height:   topologyNodeHeight,
+			Counters: counters,
+			Errors:   errors,
+		}
+	}
+	// The processors are on the middle row, between the receivers and the exporters.
+	middle := float64(rows-1) / 2
+
+	var sources []zpages.PipelineGraphNodeData
+	var sourceThroughputs []string
+	var sourceRefused []int64
+	for i, name := range pipeline.Receivers {
+		accepted := b.count(obsreport.ReceiverKey, name, measures.receiverAccepted)
+		refused := b.count(obsreport.ReceiverKey, name, measures.receiverRefused)
+		counters := topologyCounters(fmt.Sprintf("%d accepted", accepted), fmt.Sprintf("%d refused", refused))
+		n := node(0, float64(i), "receiver", name, counters, refused)
+		graph.Nodes = append(graph.Nodes, n)
+		sources = append(sources, n)
+		sourceThroughputs = append(sourceThroughputs, b.throughput(obsreport.ReceiverKey, name, measures.receiverAccepted))
+		sourceRefused = append(sourceRefused, refused)
+	}
+	connect := func(targets []zpages.PipelineGraphNodeData) {
+		for i, source := range sources {
+			for _, target := range targets {
+				graph.Edges = append(graph.Edges, zpages.PipelineGraphEdgeData{
+					X1:         source.X + source.Width,
+					Y1:         source.Y + source.Height/2,
+					X2:         target.X,
+					Y2:         target.Y + target.Height/2,
+					Throughput: sourceThroughputs[i],
+					Errors:     sourceRefused[i],
+				})
+			}
+		}
+	}
+	for i, name := range pipeline.Processors {
+		accepted := b.count(obsreport.ProcessorKey, name, measures.processorAccepted)
+		refused := b.count(obsreport.ProcessorKey, name, measures.processorRefused)
+		dropped := b.count(obsreport.ProcessorKey, name, measures.processorDropped)
+		counters := topologyCounters(fmt.Sprintf("%d accepted", accepted), fmt.Sprintf("%d refused", refused), fmt.Sprintf("%d dropped", dropped))
+		n := node(i+1, middle, "processor", name, counters, refused+dropped)
+		graph.Nodes = append(graph.Nodes, n)
+		connect([]zpages.PipelineGraphNodeData{n})
+		sources = []zpages.PipelineGraphNodeData{n}
+		sourceThroughputs = []string{b.throughput(obsreport.ProcessorKey, name, measures.processorAccepted)}
+		sourceRefused = []int64{refused}
+	}
+	var exporters []zpages.PipelineGraphNodeData
+	for i, name := range pipeline.Exporters {
+		sent := b.count(obsreport.ExporterKey, name, measures.exporterSent)
+		failed := b.count(obsreport.ExporterKey, name, measures.exporterFailed)
+		counters := topologyCounters(fmt.Sprintf("%d sent", sent), fmt.Sprintf("%d failed", failed))
+		n := node(columns-1, float64(i), "exporter", name, counters, failed)
+		graph.Nodes = append(graph.Nodes, n)
+		exporters = append(exporters, n)
+	}
+	connect(exporters)
+	return graph
+}
+
+func (app *Application) handleTopologyzRequest(w http.ResponseWriter, r *http.Request) {
+	app.configMu.RLock()
+	defer app.configMu.RUnlock()
+	r.ParseForm()
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	refresh := defaultTopologyRefreshSeconds
+	if v := r.Form.Get(zRefresh); v != "" {
+		if seconds, err := strconv.Atoi(v); err == nil && seconds >= 0 {
+			refresh = seconds
+		}
+	}
+	zpages.WriteHTMLHeader(w, zpages.HeaderData{Title: "Topology", RefreshSeconds: refresh})
+
+	counters, registered := readTopologyCounters()
+	if !registered {
+		zpages.WriteHTMLComponentHeader(w, zpages.ComponentHeaderData{
+			Name: "The counters are not available, the metrics of the collector are disabled.",
+		})
+	}
+	previous, elapsed := app.topology.update(counters, time.Now())
+	b := &topologyGraphBuilder{counters: counters, previous: previous, elapsed: elapsed}
+
+	var pipelines []*configmodels.Pipeline
+	if app.config != nil {
+		for _, p := range app.config.Service.Pipelines {
+			pipelines = append(pipelines, p)
+		}
+	}
+	sort.Slice(pipelines, func(i, j int) bool {
+		return pipelines[i].Name < pipelines[j].Name
+	})
+	for _, p := range pipelines {
+		zpages.WriteHTMLPipelineGraph(w, b.build(p))
+	}
+	zpages.WriteHTMLFooter(w)
+}
@@ -0,0 +1,157 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/config/configtelemetry"
+	"go.opentelemetry.io/collector/obsreport"
+	"go.opentelemetry.io/collector/obsreport/obsreporttest"
+	"go.opentelemetry.io/collector/service/internal/zpages"
+)
+
+func TestReadTopologyCounters(t *testing.T) {
+	_, registered := readTopologyCounters()
+	assert.False(t, registered)
+
+	doneFn, err := obsreporttest.SetupRecordedMetricsTest()
+	require.NoError(t, err)
+	defer doneFn()
+
+	for _, transport := range []string{"grpc", "http"} {
+		ctx := obsreport.StartTraceDataReceiveOp(obsreport.ReceiverContext(context.Background(), "otlp", transport), "otlp", transport)
+		obsreport.EndTraceDataReceiveOp(ctx, "protobuf", 7, nil)
+	}
+	ctx := obsreport.StartTraceDataReceiveOp(obsreport.ReceiverContext(context.Background(), "otlp", "grpc"), "otlp", "grpc")
+	obsreport.EndTraceDataReceiveOp(ctx, "protobuf", 2, errors.New("refused"))
+	processor := obsreport.NewProcessor(configtelemetry.LevelNormal, "batch")
+	processor.TracesAccepted(context.Background(), 12)
+	processor.TracesDropped(context.Background(), 4)
+	exporter := obsreport.NewExporter(configtelemetry.LevelNormal, "otlp")
+	exporter.EndTracesExportOp(exporter.StartTracesExportOp(obsreport.ExporterContext(context.Background(), "otlp")), 3, errors.New("failed"))
+
+	counters, registered := readTopologyCounters()
+	assert.True(t, registered)
+	for key, value := range map[topologyCounterKey]int64{
+		{kind: obsreport.ReceiverKey, name: "otlp", measure: obsreport.AcceptedSpansKey}:     14,
+		{kind: obsreport.ReceiverKey, name: "otlp", measure: obsreport.RefusedSpansKey}:      2,
+		{kind: obsreport.ProcessorKey, name: "batch", measure: obsreport.AcceptedSpansKey}:   12,
+		{kind: obsreport.ProcessorKey, name: "batch", measure: obsreport.DroppedSpansKey}:    4,
+		{kind: obsreport.ExporterKey, name: "otlp", measure: obsreport.SentSpansKey}:         0,
+		{kind: obsreport.ExporterKey, name: "otlp", measure: obsreport.FailedToSendSpansKey}: 3,
+	} {
+		assert.Equal(t, value, counters[key], key)
+	}
+}
+
+func TestTopologyGraph(t *testing.T) {
+	b := &topologyGraphBuilder{
+		counters: map[topologyCounterKey]int64{
+			{kind: obsreport.ReceiverKey, name: "otlp", measure: obsreport.AcceptedMetricPointsKey}:     30,
+			{kind: obsreport.ReceiverKey, name: "otlp", measure: obsreport.RefusedMetricPointsKey}:      2,
+			{kind: obsreport.ProcessorKey, name: "batch", measure: obsreport.AcceptedMetricPointsKey}:   25,
+			{kind: obsreport.ProcessorKey, name: "batch", measure: obsreport.DroppedMetricPointsKey}:    1,
+			{kind: obsreport.ExporterKey, name: "otlp", measure: obsreport.SentMetricPointsKey}:         20,
+			{kind: obsreport.ExporterKey, name: "otlp", measure: obsreport.FailedToSendMetricPointsKey}: 5,
+		},
+		previous: map[topologyCounterKey]int64{
+			{kind: obsreport.ReceiverKey, name: "otlp", measure: obsreport.AcceptedMetricPointsKey}: 10,
+		},
+		elapsed: 4 * time.Second,
+	}
+	graph := b.build(&configmodels.Pipeline{
+		Name:       "metrics",
+		InputType:  configmodels.MetricsDataType,
+		Receivers:  []string{"otlp", "prometheus"},
+		Processors: []string{"batch"},
+		Exporters:  []string{"otlp"},
+	})
+
+	assert.Equal(t, "metrics", graph.Name)
+	assert.Equal(t, "metrics", graph.InputType)
+	assert.Equal(t, 2*topologyColumnWidth+topologyNodeWidth+2*topologyGraphPadding, graph.Width)
+	assert.Equal(t, topologyRowHeight+topologyNodeHeight+2*topologyGraphPadding, graph.Height)
+	require.Len(t, graph.Nodes, 4)
+	assert.Equal(t, "receiver", graph.Nodes[0].Kind)
+	assert.Equal(t, "30 accepted, 2 refused", graph.Nodes[0].Counters)
+	assert.Equal(t, int64(2), graph.Nodes[0].Errors)
+	assert.Equal(t, "pipelinez?zcomponentkind=receiver&zcomponentname=otlp&zpipelinename=metrics", graph.Nodes[0].Link)
+	assert.Equal(t, "0 accepted, 0 refused", graph.Nodes[1].Counters)
+	assert.Equal(t, "processor", graph.Nodes[2].Kind)
+	assert.Equal(t, "25 accepted, 0 refused, 1 dropped", graph.Nodes[2].Counters)
+	assert.Equal(t, int64(1), graph.Nodes[2].Errors)
+	assert.Equal(t, "exporter", graph.Nodes[3].Kind)
+	assert.Equal(t, "20 sent, 5 failed", graph.Nodes[3].Counters)
+	assert.Equal(t, graph.Nodes[0].Y, graph.Nodes[3].Y)
+
+	require.Len(t, graph.Edges, 3)
+	assert.Equal(t, zpages.PipelineGraphEdgeData{
+		X1:         graph.Nodes[0].X + topologyNodeWidth,
+		Y1:         graph.Nodes[0].Y + topologyNodeHeight/2,
+		X2:         graph.Nodes[2].X,
+		Y2:         graph.Nodes[2].Y + topologyNodeHeight/2,
+		Throughput: "5.0/s",
+		Errors:     2,
+	}, graph.Edges[0])
+	assert.Equal(t, "0.0/s", graph.Edges[1].Throughput)
+	assert.Equal(t, "6.2/s", graph.Edges[2].Throughput)
+	assert.Equal(t, int64(0), graph.Edges[2].Errors)
+
+	b.previous = nil
+	graph = b.build(&configmodels.Pipeline{
+		Name:      "metrics",
+		InputType: configmodels.MetricsDataType,
+		Receivers: []string{"otlp"},
+		Exporters: []string{"otlp", "logging"},
+	})
+	require.Len(t, graph.Edges, 2)
+	assert.Equal(t, "-/s", graph.Edges[0].Throughput)
+	assert.Equal(t, graph.Nodes[2].X, graph.Edges[1].X2)
+}
+
+func TestHandleTopologyzRequest(t *testing.T) {
+	app := &Application{config: &configmodels.Config{
+		Service: configmodels.Service{
+			Pipelines: map[string]*configmodels.Pipeline{
+				"traces": {
+					Name:      "traces",
+					InputType: configmodels.TracesDataType,
+					Receivers: []string{"otlp"},
+					Exporters: []string{"logging"},
+				},
+			},
+		},
+	}}
+
+	rec := httptest.NewRecorder()
+	app.handleTopologyzRequest(rec, httptest.NewRequest(http.MethodGet, "/debug/topologyz?zrefresh=10", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	body := rec.Body.String()
+	assert.Contains(t, body, `<meta http-equiv="refresh" content="10">`)
+	assert.Contains(t, body, "the metrics of the collector are disabled")
+	assert.Contains(t, body, "traces (traces)")
+	assert.Contains(t, body, "receiver: otlp")
+	assert.Contains(t, body, "exporter: logging")
+}
@@ -0,0 +1,87 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configcheck"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+)
+
+// newValidateCommand returns the command validating the configuration without running the collector.
+func (app *Application) newValidateCommand(factory ConfigFactory) *cobra.Command {
+	return &cobra.Command{
+		Use:   "validate",
+		Short: "Validates the configuration and exits",
+		Long: "Loads the configuration passed via the --config and --set flags, validates the settings of the " +
+			"components and the data types of the pipelines they are used in, and exits with an error if the " +
+			"configuration is invalid. The components are not started.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if err := app.validateConfig(cmd, factory); err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), "The configuration is valid.")
+			return nil
+		},
+	}
+}
+
+// validateConfig loads the configuration with the factory and validates it.
+func (app *Application) validateConfig(cmd *cobra.Command, factory ConfigFactory) error {
+	if err := configcheck.ValidateConfigFromFactories(app.factories); err != nil {
+		return err
+	}
+	cfg, err := factory(app.v, cmd, app.factories)
+	if err != nil {
+		return fmt.Errorf("cannot load configuration: %w", err)
+	}
+	if err = cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err = validatePipelineDataTypes(cfg, app.factories); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	return nil
+}
+
+// validatePipelineDataTypes checks that the components of the pipelines support the data type of
+// the pipelines, for the factories implementing component.DataTypeSupporter. All the unsupported
+// components are reported.
+func validatePipelineDataTypes(cfg *configmodels.Config, factories component.Factories) error {
+	var errs []error
+	check := func(pipeline *configmodels.Pipeline, kind string, ref string, factory component.Factory) {
+		if ds, ok := factory.(component.DataTypeSupporter); ok && !ds.SupportsDataType(pipeline.InputType) {
+			errs = append(errs, fmt.Errorf("pipeline %q references %s %q which does not support %s", pipeline.Name, kind, ref, pipeline.InputType))
+		}
+	}
+	for _, pipeline := range cfg.Service.Pipelines {
+		for _, ref := range pipeline.Receivers {
+			check(pipeline, "receiver", ref, factories.Receivers[cfg.Receivers[ref].Type()])
+		}
+		for _, ref := range pipeline.Processors {
+			check(pipeline, "processor", ref, factories.Processors[cfg.Processors[ref].Type()])
+		}
+		for _, ref := range pipeline.Exporters {
+			check(pipeline, "exporter", ref, factories.Exporters[cfg.Exporters[ref].Type()])
+		}
+	}
+	return consumererror.CombineErrors(errs)
+}
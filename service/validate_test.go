@@ -0,0 +1,80 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/service/defaultcomponents"
+)
+
+func TestValidateCommand(t *testing.T) {
+	factories, err := defaultcomponents.Components()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		config string
+		errMsg []string
+	}{
+		{
+			name:   "valid",
+			config: "testdata/otelcol-config.yaml",
+		},
+		{
+			name:   "invalid data types",
+			config: "testdata/otelcol-config-invalid-data-types.yaml",
+			errMsg: []string{
+				`pipeline "traces" references exporter "prometheus" which does not support traces`,
+				`pipeline "metrics" references receiver "jaeger" which does not support metrics`,
+			},
+		},
+		{
+			name:   "invalid component",
+			config: "testdata/otelcol-config-invalid-component.yaml",
+			errMsg: []string{`receiver "filelog" has invalid configuration: "include" must contain at least one pattern`},
+		},
+		{
+			name:   "missing file",
+			config: "testdata/missing.yaml",
+			errMsg: []string{`cannot load configuration: error loading config file "testdata/missing.yaml"`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app, err := New(Parameters{Factories: factories})
+			require.NoError(t, err)
+			out := &bytes.Buffer{}
+			app.rootCmd.SetOut(out)
+			app.rootCmd.SetErr(out)
+			app.rootCmd.SetArgs([]string{"validate", "--config=" + tt.config})
+
+			err = app.Run()
+			if len(tt.errMsg) == 0 {
+				require.NoError(t, err)
+				assert.Contains(t, out.String(), "The configuration is valid.")
+				return
+			}
+			require.Error(t, err)
+			for _, msg := range tt.errMsg {
+				assert.Contains(t, err.Error(), msg)
+			}
+		})
+	}
+}
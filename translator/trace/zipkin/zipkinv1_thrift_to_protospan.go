@@ -147,6 +147,11 @@ func zipkinV1ThriftBinAnnotationsToOCAttributes(ztBinAnnotations []*zipkincore.B
 	var localComponent string
 	attributeMap := make(map[string]*tracepb.AttributeValue)
 	for _, binaryAnnotation := range ztBinAnnotations {
+		if isAddressAnnotation(binaryAnnotation.Key) && binaryAnnotation.Host != nil {
+			addressAnnotationToOCAttributes(toTranslatorEndpoint(binaryAnnotation.Host), attributeMap)
+			continue
+		}
+
 		pbAttrib := &tracepb.AttributeValue{}
 		binAnnotationType := binaryAnnotation.AnnotationType
 		if binaryAnnotation.Host != nil {
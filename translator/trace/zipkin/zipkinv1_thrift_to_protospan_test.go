@@ -27,6 +27,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"go.opentelemetry.io/collector/translator/conventions"
 	tracetranslator "go.opentelemetry.io/collector/translator/trace"
 )
 
@@ -56,6 +57,29 @@ func TestZipkinThriftFallbackToLocalComponent(t *testing.T) {
 	require.Equal(t, "myServiceName", got)
 }
 
+func TestZipkinThriftAddressAnnotations(t *testing.T) {
+	blob, err := ioutil.ReadFile("./testdata/zipkin_v1_thrift_address_annotations.json")
+	require.NoError(t, err, "Failed to load test data")
+
+	var ztSpans []*zipkincore.Span
+	err = json.Unmarshal(blob, &ztSpans)
+	require.NoError(t, err, "Failed to unmarshal json into zipkin v1 thrift")
+
+	reqs, err := v1ThriftBatchToOCProto(ztSpans)
+	require.NoError(t, err, "Failed to translate zipkinv1 thrift to OC proto")
+	require.Equal(t, 1, len(reqs), "Invalid trace service requests count")
+
+	// The address annotation host is the remote endpoint, not the local one.
+	require.Equal(t, "backend", reqs[0].Node.ServiceInfo.Name)
+	require.Equal(t, 1, len(reqs[0].Spans))
+	attributes := reqs[0].Spans[0].Attributes.AttributeMap
+	assert.Equal(t, "frontend", attributes[conventions.AttributePeerService].GetStringValue().GetValue())
+	assert.Equal(t, "10.0.0.1", attributes[conventions.AttributeNetPeerIP].GetStringValue().GetValue())
+	assert.NotContains(t, attributes, conventions.AttributeNetPeerPort)
+	assert.Equal(t, "/api", attributes["http.path"].GetStringValue().GetValue())
+	assert.NotContains(t, attributes, "ca")
+}
+
 func TestV1ThriftToOCProto(t *testing.T) {
 	blob, err := ioutil.ReadFile("./testdata/zipkin_v1_thrift_single_batch.json")
 	require.NoError(t, err, "Failed to load test data")
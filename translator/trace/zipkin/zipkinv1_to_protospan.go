@@ -28,6 +28,7 @@ import (
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
 	tracetranslator "go.opentelemetry.io/collector/translator/trace"
 )
 
@@ -84,6 +85,29 @@ type binaryAnnotation struct {
 	Endpoint *endpoint `json:"endpoint"`
 }
 
+// UnmarshalJSON decodes a binaryAnnotation whose value is a string or, as in the legacy
+// address annotations sent with the value true, a JSON literal kept as its text.
+func (b *binaryAnnotation) UnmarshalJSON(data []byte) error {
+	type rawBinaryAnnotation binaryAnnotation
+	raw := struct {
+		*rawBinaryAnnotation
+		Value json.RawMessage `json:"value"`
+	}{rawBinaryAnnotation: (*rawBinaryAnnotation)(b)}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	switch {
+	case len(raw.Value) == 0 || string(raw.Value) == "null":
+		b.Value = ""
+	case raw.Value[0] == '"':
+		return json.Unmarshal(raw.Value, &b.Value)
+	default:
+		b.Value = string(raw.Value)
+	}
+	return nil
+}
+
 // v1JSONBatchToOCProto converts a JSON blob with a list of Zipkin v1 spans to OC Proto.
 func v1JSONBatchToOCProto(blob []byte, parseStringTags bool) ([]traceData, error) {
 	var zSpans []*zipkinV1Span
@@ -211,6 +235,11 @@ func zipkinV1BinAnnotationsToOCAttributes(binAnnotations []*binaryAnnotation, pa
 	attributeMap := make(map[string]*tracepb.AttributeValue)
 	for _, binAnnotation := range binAnnotations {
 
+		if isAddressAnnotation(binAnnotation.Key) && binAnnotation.Endpoint != nil {
+			addressAnnotationToOCAttributes(binAnnotation.Endpoint, attributeMap)
+			continue
+		}
+
 		if binAnnotation.Endpoint != nil && binAnnotation.Endpoint.ServiceName != "" {
 			fallbackServiceName = binAnnotation.Endpoint.ServiceName
 		}
@@ -249,6 +278,30 @@ func zipkinV1BinAnnotationsToOCAttributes(binAnnotations []*binaryAnnotation, pa
 	return attributes, status, fallbackServiceName
 }
 
+// isAddressAnnotation tells whether key is the key of a legacy address annotation, whose
+// endpoint is the remote endpoint of the span instead of the local one.
+func isAddressAnnotation(key string) bool {
+	return key == zipkincore.CLIENT_ADDR || key == zipkincore.SERVER_ADDR || key == zipkincore.MESSAGE_ADDR
+}
+
+// addressAnnotationToOCAttributes sets the peer attributes from the remote endpoint of an
+// address annotation, the first address annotation of a span taking precedence.
+func addressAnnotationToOCAttributes(remote *endpoint, attributeMap map[string]*tracepb.AttributeValue) {
+	insertString := func(key, value string) {
+		if _, ok := attributeMap[key]; !ok && value != "" {
+			attributeMap[key] = &tracepb.AttributeValue{
+				Value: &tracepb.AttributeValue_StringValue{StringValue: &tracepb.TruncatableString{Value: value}}}
+		}
+	}
+	insertString(conventions.AttributePeerService, remote.ServiceName)
+	insertString(conventions.AttributeNetPeerIP, remote.IPv4)
+	insertString(conventions.AttributeNetPeerIP, remote.IPv6)
+	if _, ok := attributeMap[conventions.AttributeNetPeerPort]; !ok && remote.Port != 0 {
+		attributeMap[conventions.AttributeNetPeerPort] = &tracepb.AttributeValue{
+			Value: &tracepb.AttributeValue_IntValue{IntValue: int64(remote.Port)}}
+	}
+}
+
 func parseAnnotationValue(value string, parseStringTags bool) *tracepb.AttributeValue {
 	pbAttrib := &tracepb.AttributeValue{}
 
@@ -31,6 +31,7 @@ import (
 	"google.golang.org/protobuf/testing/protocmp"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
+	"go.opentelemetry.io/collector/translator/conventions"
 	tracetranslator "go.opentelemetry.io/collector/translator/trace"
 )
 
@@ -150,6 +151,42 @@ func TestZipkinJSONFallbackToLocalComponent(t *testing.T) {
 	require.Equal(t, "myServiceName", got)
 }
 
+func TestZipkinJSONAddressAnnotations(t *testing.T) {
+	blob, err := ioutil.ReadFile("./testdata/zipkin_v1_address_annotations.json")
+	require.NoError(t, err, "Failed to load test data")
+
+	reqs, err := v1JSONBatchToOCProto(blob, false)
+	require.NoError(t, err, "Failed to translate zipkinv1 to OC proto")
+	require.Equal(t, 1, len(reqs), "Invalid trace service requests count")
+
+	// The address annotation endpoint is the remote endpoint, not the local one.
+	require.Equal(t, "frontend", reqs[0].Node.ServiceInfo.Name)
+	require.Equal(t, 1, len(reqs[0].Spans))
+	attributes := reqs[0].Spans[0].Attributes.AttributeMap
+	assert.Equal(t, "backend", attributes[conventions.AttributePeerService].GetStringValue().GetValue())
+	assert.Equal(t, "10.0.0.2", attributes[conventions.AttributeNetPeerIP].GetStringValue().GetValue())
+	assert.Equal(t, int64(8080), attributes[conventions.AttributeNetPeerPort].GetIntValue())
+	assert.Equal(t, "/api", attributes["http.path"].GetStringValue().GetValue())
+	assert.NotContains(t, attributes, "sa")
+}
+
+func TestZipkinJSONBinaryAnnotationLiteralValues(t *testing.T) {
+	var got []*binaryAnnotation
+	err := json.Unmarshal([]byte(`[{"key":"a","value":"text"},{"key":"b","value":true},{"key":"c","value":12},{"key":"d","value":null},{"key":"e"}]`), &got)
+	require.NoError(t, err)
+	want := []*binaryAnnotation{
+		{Key: "a", Value: "text"},
+		{Key: "b", Value: "true"},
+		{Key: "c", Value: "12"},
+		{Key: "d"},
+		{Key: "e"},
+	}
+	assert.Equal(t, want, got)
+
+	err = json.Unmarshal([]byte(`[{"key":"a","value":"text}]`), &got)
+	assert.Error(t, err)
+}
+
 func TestSingleJSONV1BatchToOCProto(t *testing.T) {
 	blob, err := ioutil.ReadFile("./testdata/zipkin_v1_single_batch.json")
 	require.NoError(t, err, "Failed to load test data")